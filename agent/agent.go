@@ -24,11 +24,13 @@ import (
 	"github.com/neuvector/neuvector/share/container"
 	"github.com/neuvector/neuvector/share/fsmon"
 	"github.com/neuvector/neuvector/share/global"
+	"github.com/neuvector/neuvector/share/otel"
 	scanUtils "github.com/neuvector/neuvector/share/scan"
 	"github.com/neuvector/neuvector/share/utils"
 )
 
 const goroutineStackSize = 1024 * 1024
+const fileBaselineDir = "/var/neuvector/fbaseline/"
 
 var containerTaskExitChan chan interface{} = make(chan interface{}, 1)
 var errRestartChan chan interface{} = make(chan interface{}, 1)
@@ -99,6 +101,28 @@ func taskReexamHostIntf() {
 	}
 }
 
+// The privileged daemonsets are disallowed in some environments, such as GKE Autopilot. Without the
+// privileged access or the host PID namespace, the enforcer cannot intercept the traffic or monitor the
+// other containers by the kernel interfaces, so it falls back to the restricted mode.
+func checkRestrictedMode(force bool) bool {
+	if force {
+		log.Info("Restricted mode is enabled")
+		return true
+	}
+
+	if Agent.PidMode != "host" {
+		log.WithFields(log.Fields{"pidMode": Agent.PidMode}).Info("Not in host PID namespace, enter restricted mode")
+		return true
+	}
+	if missing, err := global.SYS.GetMissingCapabilities(); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to read capabilities")
+	} else if len(missing) > 0 {
+		log.WithFields(log.Fields{"missing": missing}).Info("Not privileged, enter restricted mode")
+		return true
+	}
+	return false
+}
+
 func getLocalInfo(selfID string, pid2ID map[int]string) error {
 	host, err := global.RT.GetHost()
 	if err != nil {
@@ -106,6 +130,7 @@ func getLocalInfo(selfID string, pid2ID map[int]string) error {
 	}
 	Host = *host
 	Host.CgroupVersion = global.SYS.GetCgroupVersion()
+	Host.Labels = utils.NewEnvironParser(os.Environ()).GetHostLabels()
 
 	getHostIPs()
 
@@ -268,6 +293,7 @@ func main() {
 	grpcPort := flag.Uint("grpc_port", 0, "Cluster GRPC port")
 	pipeType := flag.String("p", "", "Pipe driver")
 	cnet_type := flag.String("n", "", "Container Network type")
+	fwBackend := flag.String("fw", "", "Firewall backend, iptables or nftables; auto-detected if not set")
 	skip_nvProtect := flag.Bool("s", false, "Skip NV Protect")
 	show_monitor_trace := flag.Bool("m", false, "Show process/file monitor traces")
 	disable_kv_congest_ctl := flag.Bool("no_kvc", false, "disable kv congestion control")
@@ -276,6 +302,14 @@ func main() {
 	disable_system_protection := flag.Bool("no_sys_protect", false, "disable system protections")
 	policy_puller := flag.Int("policy_puller", 0, "set policy pulling period")
 	autoProfile := flag.Int("apc", 1, "Enable auto profile collection")
+	etcdEndpoints := flag.String("etcd_endpoints", "", "Use external etcd instead of embedded consul as cluster kv store, comma separated endpoint urls")
+	etcdCA := flag.String("etcd_ca", "", "CA cert file of etcd")
+	etcdCert := flag.String("etcd_cert", "", "Client cert file for etcd")
+	etcdKey := flag.String("etcd_key", "", "Client key file for etcd")
+	otelEndpoint := flag.String("otel_endpoint", "", "OpenTelemetry collector OTLP/HTTP endpoint, such as http://otel-collector:4318")
+	otelSampleRatio := flag.Float64("otel_sample_ratio", 1, "Ratio of the traces to sample")
+	restricted := flag.Bool("restricted", false, "Run without privileged access or host PID namespace; auto-detected if not set")
+	hostSkipPorts := flag.String("host_skip_ports", defaultHostNodeSkipPorts, "Comma separated ports or port ranges of the host that are not enforced in host policy mode")
 	flag.Parse()
 
 	if *debug {
@@ -331,6 +365,14 @@ func main() {
 		log.WithFields(log.Fields{"auto-profile": agentEnv.autoProfieCapture}).Info()
 	}
 
+	agentEnv.hostSkipPorts = defaultHostNodeSkipPorts
+	if ports, err := parseHostNodeSkipPorts(*hostSkipPorts); err != nil {
+		log.WithFields(log.Fields{"ports": *hostSkipPorts, "error": err}).Error("Invalid value, use the default host skip ports")
+	} else if ports != defaultHostNodeSkipPorts {
+		agentEnv.hostSkipPorts = ports
+		log.WithFields(log.Fields{"ports": ports}).Info("Host skip ports")
+	}
+
 	if *join != "" {
 		// Join addresses might not be all ready. Accept whatever input is, resolve them
 		// when starting the cluster.
@@ -356,6 +398,18 @@ func main() {
 		bindAddr = *bind
 		log.WithFields(log.Fields{"bind": bindAddr}).Info()
 	}
+	if *etcdEndpoints != "" {
+		etcdCfg := &cluster.EtcdConfig{
+			Endpoints: strings.Split(*etcdEndpoints, ","),
+			CACert:    *etcdCA,
+			Cert:      *etcdCert,
+			Key:       *etcdKey,
+		}
+		if err := cluster.UseEtcd(etcdCfg); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Invalid etcd setting. Exit!")
+			os.Exit(-2)
+		}
+	}
 
 	// Set global objects at the very first
 	platform, flavor, network, containers, err := global.SetGlobalObjects(*rtSock, resource.Register)
@@ -384,6 +438,13 @@ func main() {
 				log.WithFields(log.Fields{"error": err}).Info("register image failed")
 			}
 		}
+		if flavor == "" {
+			if embedded, kine, err := global.SYS.GetEmbeddedKube(); err == nil && embedded != "" {
+				log.WithFields(log.Fields{"flavor": embedded, "kine": kine}).Info("Embedded kubernetes")
+				flavor = embedded
+				global.ORCH.SetFlavor(flavor)
+			}
+		}
 		log.WithFields(log.Fields{"k8s": k8sVer, "oc": ocVer, "flavor": flavor}).Info()
 	}
 
@@ -432,6 +493,14 @@ func main() {
 		time.Sleep(time.Second * 4)
 	}
 
+	if agentEnv.runInContainer {
+		if agentEnv.restrictedMode = checkRestrictedMode(*restricted); agentEnv.restrictedMode && Agent.PidMode != "host" {
+			// the pid reported by the runtime is not visible in the container's PID namespace
+			Agent.Pid = os.Getpid()
+		}
+	}
+	Agent.Restricted = agentEnv.restrictedMode
+
 	// Check anti-affinity
 	var retry int
 	retryDuration := time.Duration(time.Second * 2)
@@ -463,8 +532,8 @@ func main() {
 	Host.Platform = platform
 	Host.Flavor = flavor
 	Host.Network = network
-	Host.CapDockerBench = (global.RT.String() == container.RuntimeDocker)
-	Host.CapKubeBench = global.ORCH.SupportKubeCISBench()
+	Host.CapDockerBench = (global.RT.String() == container.RuntimeDocker) && !agentEnv.restrictedMode
+	Host.CapKubeBench = global.ORCH.SupportKubeCISBench() && !agentEnv.restrictedMode
 
 	Agent.Domain = global.ORCH.GetDomain(Agent.Labels)
 	parentAgent.Domain = global.ORCH.GetDomain(parentAgent.Labels)
@@ -487,13 +556,24 @@ func main() {
 	log.WithFields(log.Fields{"host": Host}).Info("")
 	log.WithFields(log.Fields{"agent": Agent}).Info("")
 
+	otelCfg := otel.Config{
+		Endpoint: *otelEndpoint, ServiceName: "neuvector-enforcer", InstanceID: Agent.ID, HostName: Host.Name,
+		SampleRatio: *otelSampleRatio,
+	}
+	if err := otel.Init(otelCfg); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Invalid OpenTelemetry setting")
+	}
+
 	// Other objects
 	eventLogKey := share.CLUSAgentEventLogKey(Host.ID, Agent.ID)
 	evqueue = cluster.NewObjectQueue(eventLogKey, cluster.DefaultMaxQLen)
 	messenger = cluster.NewMessenger(Host.ID, Agent.ID)
 
 	//var driver string
-	if *pipeType == "ovs" {
+	if agentEnv.restrictedMode {
+		// no traffic interception, the connections are reported from the socket tables
+		driver = pipe.PIPE_NOTC
+	} else if *pipeType == "ovs" {
 		driver = pipe.PIPE_OVS
 	} else if *pipeType == "no_tc" {
 		driver = pipe.PIPE_CLM
@@ -504,7 +584,7 @@ func main() {
 		}
 	}
 	log.WithFields(log.Fields{"pipeType": driver, "jumboframe": gInfo.jumboFrameMTU, "ciliumCNI": gInfo.ciliumCNI}).Info("")
-	if nvSvcPort, nvSvcBrPort, err = pipe.Open(driver, cnet_type, Agent.Pid, gInfo.jumboFrameMTU); err != nil {
+	if nvSvcPort, nvSvcBrPort, err = pipe.Open(driver, cnet_type, Agent.Pid, gInfo.jumboFrameMTU, *fwBackend); err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("Failed to open pipe driver")
 		os.Exit(-2)
 	}
@@ -572,7 +652,8 @@ func main() {
 		bench.ResetKubeStatus()
 	}
 
-	bPassiveContainerDetect := global.RT.String() == container.RuntimeCriO
+	// the cri runtime generates the container events by polling
+	bPassiveContainerDetect := global.RT.String() == container.RuntimeCriO || global.RT.String() == container.RuntimeCri
 
 	// Probe
 	probeTaskChan := make(chan *probe.ProbeMessage, 256) // increase to avoid underflow
@@ -589,6 +670,7 @@ func main() {
 		PolicyLookupFunc:     hostPolicyLookup,
 		ProcPolicyLookupFunc: processPolicyLookup,
 		IsK8sGroupWithProbe:  pe.IsK8sGroupWithProbe,
+		ZeroDriftLookup:      pe.ZeroDriftLookup,
 		ReportLearnProc:      addLearnedProcess,
 		ContainerInContainer: agentEnv.containerInContainer,
 		GetContainerPid:      cbGetContainerPid,
@@ -602,6 +684,7 @@ func main() {
 		KubePlatform:         Host.Platform == share.PlatformKubernetes,
 		KubeFlavor:           Host.Flavor,
 		WalkHelper:           walkerTask,
+		Restricted:           agentEnv.restrictedMode,
 	}
 
 	if prober, err = probe.New(&probeConfig); err != nil {
@@ -619,6 +702,8 @@ func main() {
 		SendReport:     prober.SendAggregateFsMonReport,
 		SendAccessRule: sendLearnedFileAccessRule,
 		EstRule:        cbEstimateFileAlertByGroup,
+		InotifyOnly:    agentEnv.restrictedMode,
+		BaselineDir:    fileBaselineDir,
 	}
 
 	if fileWatcher, err = fsmon.NewFileWatcher(&fmonConfig); err != nil {
@@ -632,9 +717,13 @@ func main() {
 
 	// grpc need to be put after probe (grpc requests like sessionList, ProbeSummary require probe ready),
 	// and it also should be before clusterLoop, sending grpc port in update agent
-	global.SYS.CallNetNamespaceFunc(Agent.Pid, func(params interface{}) {
+	if agentEnv.restrictedMode {
 		grpcServer, Agent.RPCServerPort = startGRPCServer(uint16(*grpcPort))
-	}, nil)
+	} else {
+		global.SYS.CallNetNamespaceFunc(Agent.Pid, func(params interface{}) {
+			grpcServer, Agent.RPCServerPort = startGRPCServer(uint16(*grpcPort))
+		}, nil)
+	}
 
 	// Start container task thread
 	// Start monitoring container events
@@ -699,6 +788,7 @@ func main() {
 
 	stopMonitorLoop()
 	closeCluster()
+	otel.Close()
 
 	waitContainerTaskExit()
 
@@ -15,7 +15,6 @@ import (
 	"text/template"
 	"time"
 
-	"github.com/hashicorp/go-version"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/neuvector/neuvector/agent/nvbench"
@@ -35,30 +34,6 @@ const (
 	dstHostBenchSh      = dstSh + "host.sh"
 	srcContainerBenchSh = srcSh + "container.tmpl"
 	dstContainerBenchSh = dstSh + "container.sh"
-	kube100MasterTmpl   = srcSh + "kube_master_1_0_0.tmpl"
-	kube100WorkerTmpl   = srcSh + "kube_worker_1_0_0.tmpl"
-	kube100Remediation  = srcSh + "kubecis_1_0_0.rem"
-	kube120MasterTmpl   = srcSh + "kube_master_1_2_0.tmpl"
-	kube120WorkerTmpl   = srcSh + "kube_worker_1_2_0.tmpl"
-	kube120Remediation  = srcSh + "kubecis_1_2_0.rem"
-	kube141MasterTmpl   = srcSh + "kube_master_1_4_1.tmpl"
-	kube141WorkerTmpl   = srcSh + "kube_worker_1_4_1.tmpl"
-	kube141Remediation  = srcSh + "kubecis_1_4_1.rem"
-	kube151MasterTmpl   = srcSh + "kube_master_1_5_1.tmpl"
-	kube151WorkerTmpl   = srcSh + "kube_worker_1_5_1.tmpl"
-	kube151Remediation  = srcSh + "kubecis_1_5_1.rem"
-	kube160MasterTmpl   = srcSh + "kube_master_1_6_0.tmpl"
-	kube160WorkerTmpl   = srcSh + "kube_worker_1_6_0.tmpl"
-	kube160Remediation  = srcSh + "kubecis_1_6_0.rem"
-	kubeGKEMasterTmpl   = srcSh + "kube_master_gke_1_0_0.tmpl"
-	kubeGKEWorkerTmpl   = srcSh + "kube_worker_gke_1_0_0.tmpl"
-	kubeGKERemediation  = srcSh + "kubecis_gke_1_0_0.rem"
-	kubeOC43MasterTmpl  = srcSh + "kube_master_ocp_4_3.tmpl"
-	kubeOC43WorkerTmpl  = srcSh + "kube_worker_ocp_4_3.tmpl"
-	kubeOC43Remediation = srcSh + "kubecis_ocp_4_3.rem"
-	kubeOC45MasterTmpl  = srcSh + "kube_master_ocp_4_5.tmpl"
-	kubeOC45WorkerTmpl  = srcSh + "kube_worker_ocp_4_5.tmpl"
-	kubeOC45Remediation = srcSh + "kubecis_ocp_4_5.rem"
 	masterScriptSh      = dstSh + "kube_master.sh"
 	workerScriptSh      = dstSh + "kube_worker.sh"
 	checkKubeVersion    = srcSh + "check_kube_version.sh"
@@ -92,6 +67,7 @@ type benchItem struct {
 	automated   bool
 	message     []string
 	remediation string
+	stig        *share.CLUSBenchStig
 }
 
 type Bench struct {
@@ -111,12 +87,17 @@ type Bench struct {
 	kubeCisCmds     map[string]string
 	isKubeMaster    bool
 	isKubeWorker    bool
+	kineBackend     bool // etcd is replaced by kine on k3s and microk8s
 	childCmd        *exec.Cmd
 	hostScript      *share.CLUSCustomCheckGroup
 	hostWarnItems   map[string]share.CLUSAuditBenchItem
 	kubeHostDone    bool
 	dockerHostDone  bool
 	kubeCISVer      string
+	kubeCISPrefix   string
+	kubeCISProfile  string // selected profile, empty to select by the platform
+	benchMode       string // CIS or STIG benchmarks
+	kubeStig        *kubeStigMeta
 	dockerCISVer    string
 	taskScanner     *TaskScanner
 }
@@ -192,7 +173,6 @@ func (b *Bench) logBenchFailure(benchPlat benchPlatform, status share.BenchStatu
 }
 
 func (b *Bench) BenchLoop() {
-	var masterScript, workerScript, remediation string
 	b.taskScanner = newTaskScanner(b, scanWorkerMax)
 	//after the host bench, it will schedule a container bench automaticly even if no container
 	for {
@@ -205,75 +185,28 @@ func (b *Bench) BenchLoop() {
 			if !agentEnv.autoBenchmark {
 				continue
 			}
-			// Check version whenever the benchmark is rerun
+			// Check version and reload the bundle whenever the benchmark is rerun
 			k8sVer, ocVer := global.ORCH.GetVersion(false, false)
-			if masterScript == "" {
-				// 1.11- : 1.3.0
-				// 1.13- : 1.4.1
-				// 1.15- : 1.5.1
-				// 1.16- : 1.6.0
-				// GKE: GKE 1.0.0
-				if b.platform == share.PlatformKubernetes && b.flavor == share.FlavorGKE {
-					b.kubeCISVer = "GKE-1.0.0"
-					masterScript = kubeGKEMasterTmpl
-					workerScript = kubeGKEWorkerTmpl
-					remediation = kubeGKERemediation
-				} else if b.platform == share.PlatformKubernetes && b.flavor == share.FlavorOpenShift {
-					ocVer, err := version.NewVersion(ocVer)
-					if err != nil {
-						b.kubeCISVer = "OpenShift-1.1.0"
-						masterScript = kubeOC45MasterTmpl
-						workerScript = kubeOC45WorkerTmpl
-						remediation = kubeOC45Remediation
-					} else if ocVer.Compare(version.Must(version.NewVersion("4.4"))) >= 0 {
-						b.kubeCISVer = "OpenShift-1.1.0"
-						masterScript = kubeOC45MasterTmpl
-						workerScript = kubeOC45WorkerTmpl
-						remediation = kubeOC45Remediation
-					} else {
-						b.kubeCISVer = "OpenShift-1.1.0"
-						masterScript = kubeOC43MasterTmpl
-						workerScript = kubeOC43WorkerTmpl
-						remediation = kubeOC43Remediation
-					}
+			b.mux.Lock()
+			selected := b.kubeCISProfile
+			mode := b.benchMode
+			b.mux.Unlock()
+
+			profile := selectKubeBenchProfile(getKubeBenchProfiles(), selected, mode, b.flavor, k8sVer, ocVer)
+			log.WithFields(log.Fields{"profile": profile.Name, "mode": mode, "k8s": k8sVer, "oc": ocVer}).Info("Kubernetes CIS profile")
+			b.kubeCISVer = profile.Version
+			b.kubeCISPrefix = profile.Prefix
+			b.remediations = b.loadRemediation(profile.Remediation)
+			b.kubeStig = nil
+			if profile.Stig != "" {
+				if meta, err := loadKubeStigMeta(profile.Stig); err != nil {
+					log.WithFields(log.Fields{"file": profile.Stig, "error": err}).Error("Failed to load STIG metadata")
 				} else {
-					kVer, err := version.NewVersion(k8sVer)
-					if err != nil {
-						b.kubeCISVer = "1.6.0"
-						masterScript = kube160MasterTmpl
-						workerScript = kube160WorkerTmpl
-						remediation = kube160Remediation
-					} else if kVer.Compare(version.Must(version.NewVersion("1.16"))) >= 0 {
-						b.kubeCISVer = "1.6.0"
-						masterScript = kube160MasterTmpl
-						workerScript = kube160WorkerTmpl
-						remediation = kube160Remediation
-					} else if kVer.Compare(version.Must(version.NewVersion("1.15"))) >= 0 {
-						b.kubeCISVer = "1.5.1"
-						masterScript = kube151MasterTmpl
-						workerScript = kube151WorkerTmpl
-						remediation = kube151Remediation
-					} else if kVer.Compare(version.Must(version.NewVersion("1.11"))) >= 0 {
-						b.kubeCISVer = "1.4.1"
-						masterScript = kube141MasterTmpl
-						workerScript = kube141WorkerTmpl
-						remediation = kube141Remediation
-					} else if kVer.Compare(version.Must(version.NewVersion("1.8"))) >= 0 {
-						b.kubeCISVer = "1.2.0"
-						masterScript = kube120MasterTmpl
-						workerScript = kube120WorkerTmpl
-						remediation = kube120Remediation
-					} else {
-						b.kubeCISVer = "1.0.0"
-						masterScript = kube100MasterTmpl
-						workerScript = kube100WorkerTmpl
-						remediation = kube100Remediation
-					}
+					b.kubeStig = meta
 				}
-				b.remediations = b.loadRemediation(remediation)
 			}
 
-			b.doKubeBench(masterScript, workerScript, remediation)
+			b.doKubeBench(profile.Master, profile.Worker, profile.Remediation)
 		case <-b.conTimer.C:
 			containers := b.cloneAllNewContainers()
 			if agentEnv.autoBenchmark {
@@ -315,6 +248,9 @@ func (b *Bench) BenchLoop() {
 			}
 			gInfoRUnlock()
 
+			if schedule := customCheckSchedule(); schedule > 0 {
+				b.customConTimer.Reset(schedule)
+			}
 			b.doContainerCustomCheck(wls)
 		case <-b.customHostTimer.C:
 			b.doHostCustomCheck()
@@ -331,8 +267,13 @@ func (b *Bench) doKubeBench(masterScript, workerScript, remediation string) (err
 	var errMaster, errWorker error
 	var out []byte
 
+	// The profile of the managed kubernetes has no master checks
+	if b.isKubeMaster && masterScript == "" {
+		b.putBenchReport(Host.ID, share.BenchKubeMaster, nil, share.BenchStatusIdle)
+	}
+
 	// run master bench
-	if b.isKubeMaster {
+	if b.isKubeMaster && masterScript != "" {
 		b.putBenchReport(Host.ID, share.BenchKubeMaster, nil, share.BenchStatusRunning)
 
 		out, errMaster = b.runKubeBench(share.BenchKubeMaster, masterScriptSh)
@@ -345,6 +286,9 @@ func (b *Bench) doKubeBench(masterScript, workerScript, remediation string) (err
 			b.putBenchReport(Host.ID, share.BenchKubeMaster, nil, share.BenchStatusKubeMasterFail)
 		} else {
 			list := b.getBenchMsg(out)
+			if b.kineBackend {
+				list = filterEtcdBenchItems(out, list)
+			}
 			b.assignKubeBenchMeta(list)
 			b.kubeHostDone = true
 			b.logHostResult(list)
@@ -353,7 +297,7 @@ func (b *Bench) doKubeBench(masterScript, workerScript, remediation string) (err
 	}
 
 	// run worker bench
-	if b.isKubeWorker {
+	if b.isKubeWorker && workerScript != "" {
 		b.putBenchReport(Host.ID, share.BenchKubeWorker, nil, share.BenchStatusRunning)
 
 		out, errWorker = b.runKubeBench(share.BenchKubeWorker, workerScriptSh)
@@ -424,6 +368,28 @@ func (b *Bench) RerunDocker(forced bool) {
 	}
 }
 
+func (b *Bench) SetKubeCISProfile(profile string) {
+	b.mux.Lock()
+	changed := b.kubeCISProfile != profile
+	b.kubeCISProfile = profile
+	b.mux.Unlock()
+
+	if changed && Host.CapKubeBench {
+		b.RerunKube("", "", false)
+	}
+}
+
+func (b *Bench) SetBenchMode(mode string) {
+	b.mux.Lock()
+	changed := b.benchMode != mode
+	b.benchMode = mode
+	b.mux.Unlock()
+
+	if changed && Host.CapKubeBench {
+		b.RerunKube("", "", false)
+	}
+}
+
 func (b *Bench) RerunKube(cmd, cmdRemap string, forced bool) {
 	if agentEnv.autoBenchmark == false && forced == false {
 		log.Info("ignored")
@@ -439,6 +405,9 @@ func (b *Bench) RerunKube(cmd, cmdRemap string, forced bool) {
 	masterErr, workerErr := b.kubeCheckPrerequisites()
 	_, b.isKubeMaster = b.kubeCisCmds[cmdKubeApiServer]
 	_, b.isKubeWorker = b.kubeCisCmds[cmdKubelet]
+	if b.flavor == share.FlavorK3s || b.flavor == share.FlavorMicroK8s {
+		_, b.kineBackend, _ = global.SYS.GetEmbeddedKube()
+	}
 
 	var sched bool
 
@@ -622,7 +591,20 @@ func (b *Bench) assignKubeBenchMeta(list []*benchItem) {
 		if r, ok := b.remediations[l.testNum]; ok {
 			l.remediation = r
 		}
-		l.testNum = fmt.Sprintf("K.%s", l.testNum)
+		// The STIG checks are numbered by the vulnerability ID
+		if b.kubeStig != nil {
+			if c, ok := b.kubeStig.Checks[l.testNum]; ok {
+				stig := *c
+				l.stig = &stig
+				l.testNum = c.VulnID
+				continue
+			}
+		}
+		if b.kubeCISPrefix != "" {
+			l.testNum = fmt.Sprintf("%s%s", b.kubeCISPrefix, l.testNum)
+		} else {
+			l.testNum = fmt.Sprintf("K.%s", l.testNum)
+		}
 	}
 }
 
@@ -664,6 +646,37 @@ func (b *Bench) acceptBenchItem(last, item *benchItem) bool {
 	return true
 }
 
+// The etcd checks are not applicable when the datastore is kine. They are the checks in the etcd section,
+// and the checks of the etcd files and arguments in the other sections.
+func filterEtcdBenchItems(out []byte, list []*benchItem) []*benchItem {
+	sections := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.Contains(line, "[INFO]") || !strings.HasSuffix(strings.TrimSpace(line), " - etcd") {
+			continue
+		}
+		if a, c := strings.Index(line, "0m "), strings.Index(line, " - "); a != -1 && c > a {
+			sections = append(sections, strings.TrimSpace(line[a+3:c])+".")
+		}
+	}
+
+	filtered := make([]*benchItem, 0, len(list))
+LOOP:
+	for _, l := range list {
+		if strings.Contains(strings.ToLower(l.header), "etcd") {
+			continue
+		}
+		for _, s := range sections {
+			if strings.HasPrefix(l.testNum, s) {
+				continue LOOP
+			}
+		}
+		filtered = append(filtered, l)
+	}
+	return filtered
+}
+
 func (b *Bench) getBenchMsg(out []byte) []*benchItem {
 	list := make([]*benchItem, 0)
 	scanner := bufio.NewScanner(strings.NewReader(string(out)))
@@ -896,29 +909,19 @@ func (b *Bench) doHostCustomCheck() {
 
 	b.mux.Lock()
 	scripts := b.hostScript.Scripts
+	schedule := b.hostScript.Schedule
 	b.mux.Unlock()
 
+	if len(scripts) > 0 && schedule > 0 {
+		b.customHostTimer.Reset(time.Duration(schedule) * time.Minute)
+	}
+
 	items := make([]*benchItem, 0)
 	for _, s := range scripts {
 		ret, msg, err := b.runScript(s.Script, 1)
 		log.WithFields(log.Fields{"Script": s.Name, "msg": msg}).Debug("run host script")
 
-		item := &benchItem{
-			testNum: s.Name,
-			group:   "nodes",
-			header:  msg,
-		}
-
-		if err == nil {
-			if ret {
-				item.level = share.BenchLevelPass
-			} else {
-				item.level = share.BenchLevelWarn
-			}
-		} else {
-			item.level = share.BenchLevelError
-		}
-		items = append(items, item)
+		items = append(items, customCheckItem(s, "nodes", ret, msg, err))
 	}
 
 	// Write to the cluster
@@ -995,27 +998,57 @@ func (b *Bench) runCustomScript(wl *share.CLUSWorkload) []*benchItem {
 			msg = strings.TrimRight(msg, "\r\n")
 			log.WithFields(log.Fields{"Script": s.Name, "name": wl.Name, "msg": msg, "err": err}).Debug("run script")
 
-			item := &benchItem{
-				testNum: s.Name,
-				group:   grpName,
-				header:  msg,
-			}
-			if err == nil {
-				if ret {
-					item.level = share.BenchLevelPass
-				} else {
-					item.level = share.BenchLevelWarn
-				}
-			} else {
-				item.level = share.BenchLevelError
-			}
-			items = append(items, item)
+			items = append(items, customCheckItem(s, grpName, ret, msg, err))
 		}
 	}
 
 	return items
 }
 
+func customCheckItem(s *share.CLUSCustomCheck, group string, ret bool, msg string, err error) *benchItem {
+	item := &benchItem{
+		testNum:     s.Name,
+		group:       group,
+		header:      msg,
+		profile:     s.Profile,
+		scored:      s.Scored,
+		remediation: s.Remediation,
+	}
+	// With a description, the script output is reported as the message
+	if s.Description != "" {
+		item.header = s.Description
+		if msg != "" {
+			item.message = []string{msg}
+		}
+	}
+	if err == nil {
+		if ret {
+			item.level = share.BenchLevelPass
+		} else {
+			item.level = share.BenchLevelWarn
+		}
+	} else {
+		item.level = share.BenchLevelError
+	}
+	return item
+}
+
+// The container checks are rerun with the shortest schedule of the groups
+func customCheckSchedule() time.Duration {
+	var schedule uint32
+	groupMux.RLock()
+	for _, grp := range groups {
+		if grp.script != nil && grp.group != nil && grp.group.Kind == share.GroupKindContainer &&
+			len(grp.script.Scripts) > 0 && grp.script.Schedule > 0 {
+			if schedule == 0 || grp.script.Schedule < schedule {
+				schedule = grp.script.Schedule
+			}
+		}
+	}
+	groupMux.RUnlock()
+	return time.Duration(schedule) * time.Minute
+}
+
 func (b *Bench) runScript(script string, pid int) (bool, string, error) {
 	if !b.bEnable {
 		return false, "Session ended", fmt.Errorf("Session ended")
@@ -1118,6 +1151,10 @@ func (b *Bench) putBenchReport(id string, bench share.BenchType, items []*benchI
 	case share.BenchKubeMaster, share.BenchKubeWorker:
 		if Host.CapKubeBench {
 			report.Version = b.kubeCISVer
+			if b.kubeStig != nil {
+				info := b.kubeStig.Benchmark
+				report.Stig = &info
+			}
 		}
 	}
 
@@ -1299,6 +1336,7 @@ func (b *Bench) bench2Report(l *benchItem) *share.CLUSBenchItem {
 		Scored:      l.scored,
 		Automated:   l.automated,
 		Profile:     l.profile,
+		Stig:        l.stig,
 	}
 }
 
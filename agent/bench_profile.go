@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+
+	"github.com/hashicorp/go-version"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share"
+)
+
+const (
+	kubeBenchBundle = "kube_cis_bundle.json"
+	// The bundle in the update folder, such as a mounted config map, takes precedence over the built-in one
+	benchBundleUpdateDir = "/etc/neuvector/bench/"
+)
+
+// The check scripts of a Kubernetes CIS benchmark version. The file names are relative to the bundle folder.
+type kubeBenchProfile struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`                // reported as the CIS version of the bench report
+	Flavor      string `json:"flavor,omitempty"`       // auto-selected on the platform flavor
+	VersionExpr string `json:"version_expr,omitempty"` // auto-selected when the expression matches the orchestrator version
+	MinVersion  string `json:"min_version,omitempty"`  // minimum kubernetes, or openshift version of the openshift flavor
+	Master      string `json:"master,omitempty"`
+	Worker      string `json:"worker,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+	Prefix      string `json:"prefix,omitempty"` // test number prefix, if the check numbers conflict with the default ones
+	Stig        string `json:"stig,omitempty"`   // STIG metadata of the checks, only the STIG profiles have it
+}
+
+// The STIG metadata file maps the check numbers of the scripts to the STIG rules
+type kubeStigMeta struct {
+	Benchmark share.CLUSBenchStigInfo         `json:"benchmark"`
+	Checks    map[string]*share.CLUSBenchStig `json:"checks"`
+}
+
+type kubeBenchBundleFile struct {
+	Version  string              `json:"version"`
+	Profiles []*kubeBenchProfile `json:"profiles"`
+}
+
+// The latest built-in benchmark, used when the bundle cannot be loaded
+var defaultKubeBenchProfile = kubeBenchProfile{
+	Name:        "cis-1.6.0",
+	Version:     "1.6.0",
+	Master:      srcSh + "kube_master_1_6_0.tmpl",
+	Worker:      srcSh + "kube_worker_1_6_0.tmpl",
+	Remediation: srcSh + "kubecis_1_6_0.rem",
+}
+
+// The built-in STIG benchmark, used in the STIG mode when the bundle cannot be loaded
+var defaultKubeStigProfile = kubeBenchProfile{
+	Name:        "stig-v1",
+	Version:     "STIG-V1R8",
+	Master:      srcSh + "kube_master_stig_v1.tmpl",
+	Worker:      srcSh + "kube_worker_stig_v1.tmpl",
+	Remediation: srcSh + "kubestig_v1.rem",
+	Stig:        srcSh + "kube_stig_v1.json",
+}
+
+func loadKubeBenchBundle(dir string) (*kubeBenchBundleFile, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, kubeBenchBundle))
+	if err != nil {
+		return nil, err
+	}
+
+	var bundle kubeBenchBundleFile
+	if err = json.Unmarshal(data, &bundle); err != nil {
+		return nil, err
+	}
+	for _, p := range bundle.Profiles {
+		if p.Name == "" || p.Version == "" || (p.Master == "" && p.Worker == "") {
+			return nil, fmt.Errorf("Invalid profile %s", p.Name)
+		}
+		if p.VersionExpr != "" {
+			if _, err = regexp.Compile(p.VersionExpr); err != nil {
+				return nil, fmt.Errorf("Invalid version expression of profile %s: %s", p.Name, err)
+			}
+		}
+		for _, f := range []*string{&p.Master, &p.Worker, &p.Remediation, &p.Stig} {
+			if *f != "" {
+				*f = filepath.Join(dir, *f)
+			}
+		}
+	}
+	return &bundle, nil
+}
+
+func getKubeBenchProfiles() []*kubeBenchProfile {
+	for _, dir := range []string{benchBundleUpdateDir, srcSh} {
+		if bundle, err := loadKubeBenchBundle(dir); err == nil {
+			log.WithFields(log.Fields{"dir": dir, "version": bundle.Version}).Info("Kubernetes CIS bundle")
+			return bundle.Profiles
+		} else if dir == srcSh {
+			log.WithFields(log.Fields{"dir": dir, "error": err}).Error("Failed to load kubernetes CIS bundle")
+		}
+	}
+	return nil
+}
+
+func loadKubeStigMeta(path string) (*kubeStigMeta, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta kubeStigMeta
+	if err = json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	for num, c := range meta.Checks {
+		if c == nil || c.VulnID == "" || c.StigID == "" {
+			return nil, fmt.Errorf("Invalid STIG check %s", num)
+		}
+	}
+	return &meta, nil
+}
+
+// Only the profiles of the benchmark mode are selectable. Use the selected profile if it is in the bundle. Otherwise,
+// select the profile of the flavor and the orchestrator version. The profiles matched by the version expression go
+// first, then the one with the highest minimum version.
+func selectKubeBenchProfile(profiles []*kubeBenchProfile, selected, mode, flavor, k8sVer, ocVer string) *kubeBenchProfile {
+	stig := mode == share.BenchModeSTIG
+	modeProfiles := make([]*kubeBenchProfile, 0, len(profiles))
+	for _, p := range profiles {
+		if (p.Stig != "") == stig {
+			modeProfiles = append(modeProfiles, p)
+		}
+	}
+	profiles = modeProfiles
+
+	if selected != "" {
+		for _, p := range profiles {
+			if p.Name == selected {
+				return p
+			}
+		}
+		log.WithFields(log.Fields{"profile": selected, "mode": mode}).Error("Selected kubernetes CIS profile not found")
+	}
+
+	// The generic profiles are used for the flavor without its own profiles, such as Rancher
+	var flavored bool
+	for _, p := range profiles {
+		if p.Flavor == flavor {
+			flavored = true
+			break
+		}
+	}
+	if !flavored {
+		flavor = ""
+	}
+
+	orchVer := k8sVer
+	if flavor == share.FlavorOpenShift {
+		orchVer = ocVer
+	}
+	// If the version is unknown, the latest profile is taken
+	ver, verErr := version.NewVersion(orchVer)
+
+	var match *kubeBenchProfile
+	var matchVer *version.Version
+	for _, p := range profiles {
+		if p.Flavor != flavor {
+			continue
+		}
+		if p.VersionExpr != "" {
+			if matched, _ := regexp.MatchString(p.VersionExpr, orchVer); !matched {
+				continue
+			}
+		}
+		minVer := version.Must(version.NewVersion("0"))
+		if p.MinVersion != "" {
+			var err error
+			if minVer, err = version.NewVersion(p.MinVersion); err != nil {
+				continue
+			}
+		}
+		if verErr == nil && ver.LessThan(minVer) {
+			continue
+		}
+		if match == nil || (p.VersionExpr != "" && match.VersionExpr == "") ||
+			((p.VersionExpr != "") == (match.VersionExpr != "") && minVer.GreaterThan(matchVer)) {
+			match, matchVer = p, minVer
+		}
+	}
+	if match == nil {
+		if stig {
+			return &defaultKubeStigProfile
+		}
+		return &defaultKubeBenchProfile
+	}
+	return match
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestKubeBenchBundle(t *testing.T) {
+	bundle, err := loadKubeBenchBundle("nvbench")
+	if err != nil {
+		t.Fatalf("Failed to load bundle: %v", err)
+	}
+
+	for _, p := range bundle.Profiles {
+		for _, f := range []string{p.Master, p.Worker, p.Remediation, p.Stig} {
+			if f == "" {
+				continue
+			}
+			if _, err := os.Stat(f); err != nil {
+				t.Errorf("Missing file of profile %s: %v", p.Name, err)
+			}
+		}
+	}
+
+	tests := []struct {
+		selected, mode, flavor, k8sVer, ocVer string
+		profile                               string
+	}{
+		{"", "", "", "1.7.3", "", "cis-1.0.0"},
+		{"", "", "", "1.10.1", "", "cis-1.2.0"},
+		{"", "", "", "1.14.0", "", "cis-1.4.1"},
+		{"", "", "", "1.15.3", "", "cis-1.5.1"},
+		{"", "", "", "1.21.2", "", "cis-1.6.0"},
+		{"", "", "", "", "", "cis-1.6.0"},
+		{"", "", share.FlavorRancher, "1.15.3", "", "cis-1.5.1"},
+		{"", "", share.FlavorGKE, "1.21.2-gke.1", "", "gke-1.0.0"},
+		{"", "", share.FlavorOpenShift, "1.16.2", "4.3.1", "ocp-4.3"},
+		{"", "", share.FlavorOpenShift, "1.18.3", "4.6.8", "ocp-4.5"},
+		{"", "", share.FlavorOpenShift, "1.18.3", "", "ocp-4.5"},
+		{"", "", "", "1.21.5-eks-bc4871b", "", "eks-1.0.1"},
+		{"aks-1.0.0", "", "", "1.21.2", "", "aks-1.0.0"},
+		{"cis-1.5.1", "", "", "1.21.2", "", "cis-1.5.1"},
+		{"unknown", "", "", "1.21.2", "", "cis-1.6.0"},
+		{"", share.BenchModeCIS, "", "1.21.2", "", "cis-1.6.0"},
+		{"", share.BenchModeSTIG, "", "1.21.2", "", "stig-v1"},
+		{"", share.BenchModeSTIG, share.FlavorGKE, "1.21.2-gke.1", "", "stig-v1"},
+		{"stig-v1", "", "", "1.21.2", "", "cis-1.6.0"},
+		{"cis-1.6.0", share.BenchModeSTIG, "", "1.21.2", "", "stig-v1"},
+	}
+	for _, c := range tests {
+		p := selectKubeBenchProfile(bundle.Profiles, c.selected, c.mode, c.flavor, c.k8sVer, c.ocVer)
+		if p.Name != c.profile {
+			t.Errorf("Unexpected profile: %+v, profile=%s", c, p.Name)
+		}
+	}
+
+	if p := selectKubeBenchProfile(nil, "", "", "", "1.21.2", ""); p != &defaultKubeBenchProfile {
+		t.Errorf("Unexpected profile without bundle: %s", p.Name)
+	}
+	if p := selectKubeBenchProfile(nil, "", share.BenchModeSTIG, "", "1.21.2", ""); p != &defaultKubeStigProfile {
+		t.Errorf("Unexpected STIG profile without bundle: %s", p.Name)
+	}
+}
+
+func TestKubeStigMeta(t *testing.T) {
+	bundle, err := loadKubeBenchBundle("nvbench")
+	if err != nil {
+		t.Fatalf("Failed to load bundle: %v", err)
+	}
+
+	for _, p := range bundle.Profiles {
+		if p.Stig == "" {
+			continue
+		}
+		meta, err := loadKubeStigMeta(p.Stig)
+		if err != nil {
+			t.Fatalf("Failed to load STIG metadata of profile %s: %v", p.Name, err)
+		}
+		if meta.Benchmark.StigID == "" || len(meta.Checks) == 0 {
+			t.Errorf("Unexpected STIG metadata of profile %s: %+v", p.Name, meta.Benchmark)
+		}
+
+		// Every check in the scripts has its STIG rule and remediation
+		b := &Bench{}
+		remediations := b.loadRemediation(p.Remediation)
+		for _, script := range []string{p.Master, p.Worker} {
+			data, _ := ioutil.ReadFile(script)
+			for _, m := range regexp.MustCompile(`(?m)^check_[0-9_]+="([0-9.]+) `).FindAllStringSubmatch(string(data), -1) {
+				if _, ok := meta.Checks[m[1]]; !ok {
+					t.Errorf("No STIG rule of check %s in profile %s", m[1], p.Name)
+				}
+				if _, ok := remediations[m[1]]; !ok {
+					t.Errorf("No remediation of check %s in profile %s", m[1], p.Name)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestFilterEtcdBenchItems(t *testing.T) {
+	out := "\x1b[1;34m[INFO]\x1b[0m 1 - Control Plane Components\n" +
+		"\x1b[1;34m[INFO]\x1b[0m 2 - etcd\n"
+	list := []*benchItem{
+		{testNum: "1.1.1", header: "Ensure that the API server pod specification file permissions are set to 644 or more restrictive"},
+		{testNum: "1.1.7", header: "Ensure that the etcd pod specification file permissions are set to 644 or more restrictive"},
+		{testNum: "1.2.29", header: "Ensure that the --etcd-certfile and --etcd-keyfile arguments are set as appropriate"},
+		{testNum: "2.1", header: "Ensure that the --cert-file and --key-file arguments are set as appropriate"},
+		{testNum: "2.2", header: "Ensure that the --client-cert-auth argument is set to true"},
+		{testNum: "3.1.1", header: "Client certificate authentication should not be used for users"},
+	}
+
+	filtered := filterEtcdBenchItems([]byte(out), list)
+	if len(filtered) != 2 || filtered[0].testNum != "1.1.1" || filtered[1].testNum != "3.1.1" {
+		t.Errorf("Unexpected filtered items: %+v", filtered)
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+// #include "../defs.h"
+import "C"
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/agent/dp"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// Cryptomining is suspected when a workload has mining activity, either connecting
+// to a known mining pool, sending stratum requests detected by dp or running a
+// process with a miner command line, while its cpu usage stays high.
+const miningCPUThreshold float64 = 0.5
+const miningCPUSlots uint = 6 // consecutive stats samples
+const miningEvidenceLife = time.Duration(time.Minute * 10)
+const miningCmdlineInterval = time.Duration(time.Minute)
+const miningMitreTechnique = "T1496" // resource hijacking
+const miningThreatID uint32 = C.THRT_ID_CRYPTO_MINING
+
+type miningEvidence struct {
+	msg        string
+	remoteIP   net.IP
+	remotePort uint16
+	ipproto    uint8
+	firstAt    time.Time
+	lastAt     time.Time
+	count      int
+	reported   bool
+}
+
+// mining pool feed, updated with the threat signatures
+var miningPoolMutex sync.RWMutex
+var miningPoolNets []*net.IPNet
+var miningPoolFqdns []string
+
+var miningMutex sync.Mutex
+var miningEvidenceMap map[string]*miningEvidence = make(map[string]*miningEvidence)
+var miningCmdlineCheckAt map[string]time.Time = make(map[string]time.Time)
+
+// the pool url given to the miner, "stratum+tcp://pool.com:3333", "stratum2+ssl://..."
+var miningPoolURLRegex *regexp.Regexp = regexp.MustCompile(`(?i)\bstratum[0-9]?\+(tcp|ssl|tls)://\S+`)
+
+var minerNames utils.Set = utils.NewSet("xmrig", "xmr-stak", "xmr-stak-rx", "minerd", "cpuminer", "cpuminer-multi",
+	"ethminer", "cgminer", "bfgminer", "nbminer", "t-rex", "lolminer", "nanominer", "teamredminer", "phoenixminer")
+
+// options only used by the miners
+var minerOptions utils.Set = utils.NewSet("--donate-level", "--donate-over-proxy", "--randomx-1gb-pages", "--cpu-max-threads-hint")
+
+// Pool entries are ip, cidr or fqdn, a fqdn can start with a wildcard, "*.pool.com"
+func setMiningPools(pools []string) {
+	nets := make([]*net.IPNet, 0)
+	fqdns := make([]string, 0)
+	for _, pool := range pools {
+		if ip := net.ParseIP(pool); ip != nil {
+			if ip.To4() != nil {
+				nets = append(nets, &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)})
+			} else {
+				nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)})
+			}
+		} else if _, ipnet, err := net.ParseCIDR(pool); err == nil {
+			nets = append(nets, ipnet)
+		} else {
+			fqdns = append(fqdns, strings.ToLower(pool))
+		}
+	}
+
+	miningPoolMutex.Lock()
+	miningPoolNets = nets
+	miningPoolFqdns = fqdns
+	miningPoolMutex.Unlock()
+	log.WithFields(log.Fields{"ips": len(nets), "fqdns": len(fqdns)}).Info("Mining pools")
+}
+
+func isMiningPoolFqdn(name string) bool {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	for _, fqdn := range miningPoolFqdns {
+		if strings.HasPrefix(fqdn, "*.") {
+			if strings.HasSuffix(name, fqdn[1:]) || name == fqdn[2:] {
+				return true
+			}
+		} else if name == fqdn {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the matched pool
+func lookupMiningPool(ip net.IP) (string, bool) {
+	miningPoolMutex.RLock()
+	defer miningPoolMutex.RUnlock()
+
+	for _, ipnet := range miningPoolNets {
+		if ipnet.Contains(ip) {
+			return ip.String(), true
+		}
+	}
+
+	if len(miningPoolFqdns) > 0 {
+		ipFqdnStorageMutex.Lock()
+		name, ok := ipFqdnStorageCache[ip.String()]
+		ipFqdnStorageMutex.Unlock()
+		if ok && isMiningPoolFqdn(name) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func addMiningEvidence(id, msg string, ip net.IP, port uint16, ipproto uint8) {
+	now := time.Now()
+
+	miningMutex.Lock()
+	defer miningMutex.Unlock()
+
+	if e, ok := miningEvidenceMap[id]; ok {
+		e.lastAt = now
+		e.count++
+		return
+	}
+	miningEvidenceMap[id] = &miningEvidence{
+		msg: msg, remoteIP: ip, remotePort: port, ipproto: ipproto, firstAt: now, lastAt: now, count: 1,
+	}
+	log.WithFields(log.Fields{"id": id, "msg": msg}).Debug()
+}
+
+// Egress connections of the workload to a known mining pool
+func checkMiningConnection(conn *dp.Connection, id string) {
+	if conn.Ingress || !conn.ExternalPeer {
+		return
+	}
+	if pool, ok := lookupMiningPool(conn.ServerIP); ok {
+		msg := fmt.Sprintf("Connection to mining pool %s", pool)
+		addMiningEvidence(id, msg, conn.ServerIP, conn.ServerPort, conn.IPProto)
+	}
+}
+
+// Stratum requests detected by dp
+func checkMiningThreatLog(id string, slog *share.CLUSThreatLog) {
+	if slog.ThreatID == miningThreatID {
+		addMiningEvidence(id, slog.Msg, slog.DstIP, slog.DstPort, slog.IPProto)
+	}
+}
+
+// Return the evidence when the command line is a miner's
+func miningCmdlineEvidence(cmds []string) (string, bool) {
+	if len(cmds) == 0 {
+		return "", false
+	}
+	if name := strings.ToLower(filepath.Base(cmds[0])); minerNames.Contains(name) {
+		return fmt.Sprintf("Miner process %s", name), true
+	}
+	for _, cmd := range cmds[1:] {
+		if url := miningPoolURLRegex.FindString(cmd); url != "" {
+			return fmt.Sprintf("Miner command line with pool %s", url), true
+		}
+		if opt := strings.SplitN(cmd, "=", 2)[0]; minerOptions.Contains(opt) {
+			return fmt.Sprintf("Miner command line with option %s", opt), true
+		}
+	}
+	return "", false
+}
+
+// The command lines of the busy workloads without other evidence are checked at most once per interval.
+// It is called without gInfo lock, the process list is read from the probe
+func checkMiningCmdlines(ids []string, getProcs func(id string) []*share.CLUSProcess) {
+	now := time.Now()
+	for _, id := range ids {
+		miningMutex.Lock()
+		at, ok := miningCmdlineCheckAt[id]
+		if ok && now.Sub(at) < miningCmdlineInterval {
+			miningMutex.Unlock()
+			continue
+		}
+		miningCmdlineCheckAt[id] = now
+		miningMutex.Unlock()
+
+		for _, proc := range getProcs(id) {
+			if msg, ok := miningCmdlineEvidence(proc.Cmds); ok {
+				addMiningEvidence(id, msg, nil, 0, 0)
+				break
+			}
+		}
+	}
+}
+
+func isSustainedHighCPU(stats *share.ContainerStats) bool {
+	for n := uint(1); n <= miningCPUSlots; n++ {
+		s := (stats.CurSlot + share.ContainerStatsSlots - n) % share.ContainerStatsSlots
+		if stats.Cpu[s] < miningCPUThreshold {
+			return false
+		}
+	}
+	return true
+}
+
+// Called with gInfo read lock held, after the stats are updated. Return the busy workloads without evidence,
+// their command lines are checked after the lock is released.
+func checkCryptoMining() []string {
+	now := time.Now()
+
+	miningMutex.Lock()
+	defer miningMutex.Unlock()
+
+	for id := range miningCmdlineCheckAt {
+		if _, ok := gInfo.activeContainers[id]; !ok {
+			delete(miningCmdlineCheckAt, id)
+		}
+	}
+
+	var busy []string
+	for id, c := range gInfo.activeContainers {
+		if _, ok := miningEvidenceMap[id]; !ok && isSustainedHighCPU(&c.stats) {
+			busy = append(busy, id)
+		}
+	}
+
+	for id, e := range miningEvidenceMap {
+		c, ok := gInfo.activeContainers[id]
+		if !ok || now.Sub(e.lastAt) > miningEvidenceLife {
+			delete(miningEvidenceMap, id)
+			continue
+		}
+		if e.reported || !isSustainedHighCPU(&c.stats) {
+			continue
+		}
+
+		e.reported = true
+		s := (c.stats.CurSlot + share.ContainerStatsSlots - 1) % share.ContainerStatsSlots
+		eLog := &share.CLUSIncidentLog{
+			ID:           share.CLUSIncidContainerCryptoMining,
+			HostID:       Host.ID,
+			HostName:     Host.Name,
+			AgentID:      Agent.ID,
+			AgentName:    Agent.Name,
+			WorkloadID:   id,
+			WorkloadName: c.name,
+			ReportedAt:   now.UTC(),
+			RemoteIP:     e.remoteIP,
+			RemotePort:   e.remotePort,
+			IPProto:      e.ipproto,
+			Count:        e.count,
+			StartAt:      e.firstAt.UTC(),
+			Action:       share.PolicyActionViolate,
+			Msg:          fmt.Sprintf("Cryptomining suspected: %s, cpu usage %.0f%%", e.msg, c.stats.Cpu[s]*100),
+			Mitre:        []string{miningMitreTechnique},
+		}
+		reportIncident(eLog)
+		log.WithFields(log.Fields{"id": id, "msg": e.msg}).Info("Cryptomining suspected")
+	}
+	return busy
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/agent/dp"
+	"github.com/neuvector/neuvector/share"
+)
+
+func resetMiningState() {
+	setMiningPools(nil)
+	miningEvidenceMap = make(map[string]*miningEvidence)
+	miningCmdlineCheckAt = make(map[string]time.Time)
+	incidentLogCache = nil
+}
+
+func busyStats(cpu float64) share.ContainerStats {
+	var stats share.ContainerStats
+	for i := uint(0); i < share.ContainerStatsSlots; i++ {
+		stats.Cpu[i] = cpu
+	}
+	return stats
+}
+
+func TestMiningPoolLookup(t *testing.T) {
+	defer resetMiningState()
+
+	setMiningPools([]string{"1.2.3.4", "10.10.0.0/16", "2001:db8::1", "*.nanopool.org", "Pool.Minexmr.com"})
+
+	ipFqdnStorageMutex.Lock()
+	ipFqdnStorageCache["5.5.5.1"] = "xmr-eu1.nanopool.org."
+	ipFqdnStorageCache["5.5.5.2"] = "nanopool.org"
+	ipFqdnStorageCache["5.5.5.3"] = "pool.minexmr.com"
+	ipFqdnStorageCache["5.5.5.4"] = "nanopool.org.example.com"
+	ipFqdnStorageCache["5.5.5.5"] = "mypool.minexmr.com"
+	ipFqdnStorageMutex.Unlock()
+	defer func() {
+		ipFqdnStorageMutex.Lock()
+		for i := 1; i <= 5; i++ {
+			delete(ipFqdnStorageCache, net.IPv4(5, 5, 5, byte(i)).String())
+		}
+		ipFqdnStorageMutex.Unlock()
+	}()
+
+	tests := []struct {
+		ip    string
+		pool  string
+		match bool
+	}{
+		{"1.2.3.4", "1.2.3.4", true},
+		{"1.2.3.5", "", false},
+		{"10.10.200.1", "10.10.200.1", true},
+		{"10.11.0.1", "", false},
+		{"2001:db8::1", "2001:db8::1", true},
+		{"5.5.5.1", "xmr-eu1.nanopool.org.", true},
+		{"5.5.5.2", "nanopool.org", true},
+		{"5.5.5.3", "pool.minexmr.com", true},
+		{"5.5.5.4", "", false},
+		{"5.5.5.5", "", false},
+		{"6.6.6.6", "", false},
+	}
+
+	for _, test := range tests {
+		if pool, ok := lookupMiningPool(net.ParseIP(test.ip)); ok != test.match || pool != test.pool {
+			t.Errorf("Unexpected pool lookup: ip=%v pool=%v match=%v, expect=%v %v", test.ip, pool, ok, test.pool, test.match)
+		}
+	}
+}
+
+func TestMiningConnection(t *testing.T) {
+	defer resetMiningState()
+
+	setMiningPools([]string{"1.2.3.4"})
+
+	conns := []*dp.Connection{
+		{ServerIP: net.ParseIP("1.2.3.4"), ServerPort: 3333, IPProto: 6, Ingress: true, ExternalPeer: true},
+		{ServerIP: net.ParseIP("1.2.3.4"), ServerPort: 3333, IPProto: 6, ExternalPeer: false},
+		{ServerIP: net.ParseIP("1.2.3.5"), ServerPort: 3333, IPProto: 6, ExternalPeer: true},
+	}
+	for _, conn := range conns {
+		checkMiningConnection(conn, "c1")
+	}
+	if len(miningEvidenceMap) != 0 {
+		t.Errorf("Ingress, internal or unknown peer should not be evidence: %+v", miningEvidenceMap)
+	}
+
+	conn := &dp.Connection{ServerIP: net.ParseIP("1.2.3.4"), ServerPort: 3333, IPProto: 6, ExternalPeer: true}
+	checkMiningConnection(conn, "c1")
+	checkMiningConnection(conn, "c1")
+	if e, ok := miningEvidenceMap["c1"]; !ok || e.count != 2 || e.remotePort != 3333 || !e.remoteIP.Equal(conn.ServerIP) {
+		t.Errorf("Unexpected evidence: %+v", e)
+	}
+}
+
+func TestMiningThreatLog(t *testing.T) {
+	defer resetMiningState()
+
+	checkMiningThreatLog("c1", &share.CLUSThreatLog{ThreatID: miningThreatID + 1, Msg: "other"})
+	if len(miningEvidenceMap) != 0 {
+		t.Errorf("Other threat should not be evidence: %+v", miningEvidenceMap)
+	}
+
+	slog := &share.CLUSThreatLog{ThreatID: miningThreatID, Msg: "Stratum mining.subscribe", DstIP: net.ParseIP("1.2.3.4"), DstPort: 4444, IPProto: 6}
+	checkMiningThreatLog("c1", slog)
+	if e, ok := miningEvidenceMap["c1"]; !ok || e.msg != slog.Msg || e.remotePort != 4444 {
+		t.Errorf("Unexpected evidence: %+v", e)
+	}
+}
+
+func TestMiningCmdline(t *testing.T) {
+	tests := []struct {
+		cmds  []string
+		match bool
+	}{
+		{nil, false},
+		{[]string{"/usr/local/bin/xmrig"}, true},
+		{[]string{"./XMRig", "-c", "config.json"}, true},
+		{[]string{"/tmp/.x/kworker", "-o", "stratum+tcp://pool.minexmr.com:4444", "-u", "wallet"}, true},
+		{[]string{"/tmp/kdevtmpfs", "--url=stratum+ssl://xmr.pool.com:443"}, true},
+		{[]string{"/tmp/kdevtmpfs", "-o", "STRATUM2+TCP://pool.com:3333"}, true},
+		{[]string{"/tmp/kdevtmpfs", "--donate-level=1"}, true},
+		{[]string{"/tmp/kdevtmpfs", "--donate-level", "1"}, true},
+		{[]string{"nginx", "-g", "daemon off;"}, false},
+		{[]string{"curl", "http://stratum.example.com"}, false},
+		{[]string{"grep", "xmrig"}, false},
+		{[]string{"/usr/bin/python", "stratum_tcp.py"}, false},
+	}
+
+	for _, test := range tests {
+		if msg, ok := miningCmdlineEvidence(test.cmds); ok != test.match || (ok && msg == "") {
+			t.Errorf("Unexpected cmdline evidence: cmds=%+v match=%v msg=%v, expect=%v", test.cmds, ok, msg, test.match)
+		}
+	}
+}
+
+func TestMiningCmdlineCheck(t *testing.T) {
+	defer resetMiningState()
+
+	procs := map[string][]*share.CLUSProcess{
+		"c1": {{Name: "sh", Cmds: []string{"sh"}}, {Name: "kworker", Cmds: []string{"kworker", "-o", "stratum+tcp://pool.com:3333"}}},
+		"c2": {{Name: "nginx", Cmds: []string{"nginx"}}},
+	}
+	var calls int
+	getProcs := func(id string) []*share.CLUSProcess {
+		calls++
+		return procs[id]
+	}
+
+	checkMiningCmdlines([]string{"c1", "c2"}, getProcs)
+	if _, ok := miningEvidenceMap["c1"]; !ok {
+		t.Errorf("Miner command line is not evidence")
+	}
+	if _, ok := miningEvidenceMap["c2"]; ok {
+		t.Errorf("Unexpected evidence: %+v", miningEvidenceMap["c2"])
+	}
+
+	// checked at most once per interval
+	checkMiningCmdlines([]string{"c1", "c2"}, getProcs)
+	if calls != 2 {
+		t.Errorf("Command lines are checked too often: %v", calls)
+	}
+}
+
+func TestSustainedHighCPU(t *testing.T) {
+	stats := busyStats(0.9)
+	stats.CurSlot = 3
+	if !isSustainedHighCPU(&stats) {
+		t.Errorf("Busy workload is not detected")
+	}
+
+	// one idle sample in the window
+	stats.Cpu[(stats.CurSlot+share.ContainerStatsSlots-miningCPUSlots)%share.ContainerStatsSlots] = 0.1
+	if isSustainedHighCPU(&stats) {
+		t.Errorf("Idle sample in the window should not be busy")
+	}
+
+	// idle sample out of the window
+	stats = busyStats(0.9)
+	stats.CurSlot = 3
+	stats.Cpu[stats.CurSlot] = 0.1
+	if !isSustainedHighCPU(&stats) {
+		t.Errorf("Idle sample out of the window should not be checked")
+	}
+}
+
+func TestCheckCryptoMining(t *testing.T) {
+	defer resetMiningState()
+	defer func() { gInfo.activeContainers = make(map[string]*containerData) }()
+
+	gInfo.activeContainers = map[string]*containerData{
+		"busy":  {id: "busy", name: "busy", stats: busyStats(0.9)},
+		"idle":  {id: "idle", name: "idle", stats: busyStats(0.1)},
+		"miner": {id: "miner", name: "miner", stats: busyStats(0.9)},
+	}
+	addMiningEvidence("idle", "idle evidence", nil, 0, 0)
+	addMiningEvidence("miner", "miner evidence", nil, 0, 0)
+	addMiningEvidence("gone", "gone evidence", nil, 0, 0)
+
+	busy := checkCryptoMining()
+	if len(busy) != 1 || busy[0] != "busy" {
+		t.Errorf("Unexpected busy workloads: %+v", busy)
+	}
+	if len(incidentLogCache) != 1 || incidentLogCache[0].WorkloadID != "miner" ||
+		incidentLogCache[0].ID != share.CLUSIncidContainerCryptoMining {
+		t.Errorf("Unexpected incidents: %+v", incidentLogCache)
+	}
+	if _, ok := miningEvidenceMap["gone"]; ok {
+		t.Errorf("Evidence of removed workload is not cleared")
+	}
+
+	// reported once
+	checkCryptoMining()
+	if len(incidentLogCache) != 1 {
+		t.Errorf("Incident is reported again: %+v", incidentLogCache)
+	}
+}
@@ -235,6 +235,19 @@ func DPCtrlSetDetectUnmanagedWl(detectUnmanagedWl *bool) {
 	dpSendMsg(msg)
 }
 
+func DPCtrlConfigMeter(synFlood, portScan uint32) {
+	log.WithFields(log.Fields{"synFlood": synFlood, "portScan": portScan}).Debug("")
+
+	data := DPMeterConfReq{
+		MeterConf: &DPMeterConf{
+			SynFlood: synFlood,
+			PortScan: portScan,
+		},
+	}
+	msg, _ := json.Marshal(data)
+	dpSendMsg(msg)
+}
+
 func DPCtrlAddMAC(iface string, mac, ucmac, bcmac, oldmac, pmac net.HardwareAddr, pips []net.IP) {
 	log.WithFields(log.Fields{"mac": mac, "iface": iface}).Debug("")
 
@@ -607,14 +620,16 @@ func DPCtrlConfigPolicyAddr(subnets map[string]share.CLUSSubnet) {
 
 func DPCtrlConfigInternalSubnet(subnets map[string]share.CLUSSubnet) {
 	data_subnet := make([]DPSubnet, 0, len(subnets))
+	data_subnet6 := make([]DPSubnet, 0)
 	for _, addr := range subnets {
-		if utils.IsIPv4(addr.Subnet.IP) == false {
-			continue
-		}
 		subnet := DPSubnet{
 			IP:   addr.Subnet.IP,
 			Mask: net.IP(addr.Subnet.Mask),
 		}
+		if utils.IsIPv4(addr.Subnet.IP) == false {
+			data_subnet6 = append(data_subnet6, subnet)
+			continue
+		}
 		data_subnet = append(data_subnet, subnet)
 	}
 
@@ -646,6 +661,10 @@ func DPCtrlConfigInternalSubnet(subnets map[string]share.CLUSSubnet) {
 				Subnets: data_subnet[start:end],
 			},
 		}
+		// ipv6 subnets are few, send them all with the first message
+		if start == 0 {
+			data.SubnetCfg.Subnets6 = data_subnet6
+		}
 
 		msg, _ = json.Marshal(data)
 		sz := len(msg)
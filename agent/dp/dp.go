@@ -164,6 +164,7 @@ func dpMsgConnection(msg []byte) {
 			Application:  uint32(conn.Application),
 			PolicyId:     uint32(conn.PolicyId),
 			Violates:     uint32(conn.Violates),
+			MeshID:       C.GoString(&conn.MeshID[0]),
 		}
 		switch uint16(conn.EtherType) {
 		case syscall.ETH_P_IP:
@@ -154,6 +154,15 @@ type DPDetectUnmanagedWlReq struct {
 	DetectUnmanagedWlConf *DPDetectUnmanagedWl `json:"ctrl_detect_unmanaged_wl"`
 }
 
+type DPMeterConf struct {
+	SynFlood uint32 `json:"syn_flood"`
+	PortScan uint32 `json:"port_scan"`
+}
+
+type DPMeterConfReq struct {
+	MeterConf *DPMeterConf `json:"ctrl_cfg_meter"`
+}
+
 type DPStatsMACReq struct {
 	Stats *DPMACArray `json:"ctrl_stats_macs"`
 }
@@ -224,6 +233,9 @@ type DPPolicyIPRule struct {
 	Fqdn    string         `json:"fqdn,omitempty"`
 	Vhost	bool           `json:"vhost,omitempty"`
 	Apps    []*DPPolicyApp `json:"apps,omitempty"`
+	Rate     uint32         `json:"rate,omitempty"`
+	RateID   uint32         `json:"rate_id,omitempty"`
+	RateUnit uint8          `json:"rate_unit,omitempty"`
 }
 
 type DPWorkloadIPPolicy struct {
@@ -278,8 +290,9 @@ type DPSpecSubnet struct {
 }
 
 type DPInternalSubnetCfg struct {
-	Flag    uint       `json:"flag"`
-	Subnets []DPSubnet `json:"subnet_addr"`
+	Flag     uint       `json:"flag"`
+	Subnets  []DPSubnet `json:"subnet_addr"`
+	Subnets6 []DPSubnet `json:"subnet_addr6,omitempty"`
 }
 
 type DPInternalSubnetCfgReq struct {
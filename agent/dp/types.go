@@ -51,6 +51,7 @@ type Connection struct {
 	LinkLocal    bool
 	TmpOpen      bool
 	UwlIp        bool
+	MeshID       string
 }
 
 type ConnectionData struct {
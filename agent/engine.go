@@ -55,6 +55,7 @@ type containerData struct {
 	inline         bool
 	blocking       bool
 	quar           bool
+	quarExcepts    []share.CLUSQuarException // quarantined but the traffic is forwarded to dp for the exceptions
 	capIntcp       bool
 	capBlock       bool
 	hostMode       bool
@@ -115,6 +116,9 @@ type localSystemInfo struct {
 	ciliumCNI       bool
 	disableNetPolicy bool
 	detectUnmanagedWl bool
+	hostNetPolicy    bool
+	synFloodThreshold uint32
+	portScanThreshold uint32
 }
 
 var defaultPolicyMode string = share.PolicyModeLearn
@@ -124,6 +128,7 @@ var defaultTapProxymesh bool = true
 var defaultXffEnabled bool = false
 var defaultDisableNetPolicy bool = false
 var defaultDetectUnmanagedWl bool = false
+var defaultHostNetPolicy bool = false
 var specialSubnets map[string]share.CLUSSpecSubnet = make(map[string]share.CLUSSpecSubnet)
 var rtStorageDriver string
 
@@ -734,6 +739,70 @@ func programDelProxyMeshDP(c *containerData, ns string) {
 	}
 }
 
+// Host processes are enforced as a pseudo endpoint in dp, traffic of host
+// network namespace is queued by NFQUEUE and matched with this MAC.
+// 4e:56:48:4f:53:54 - NVHOST
+const hostNodeIface string = "nv-host"
+const hostNodeMacStr string = "4e:56:48:4f:53:54"
+const hostNodeNfqNo int = 1
+
+var hostNodeMac, _ = net.ParseMAC(hostNodeMacStr)
+
+// Traffic of the skipped ports is not queued, so it's neither enforced nor reported. By default, only
+// the ports used among neuvector components and the ports that the overlay networks can't work
+// without are skipped: bgp, vxlan, geneve, flannel vxlan and wireguard. The ports of the control
+// plane, such as apiserver, etcd and kubelet, can be added with the agent option, so that a policy
+// miss doesn't take the node out of the cluster, but the host policy is not applied to them either.
+const defaultHostNodeSkipPorts string = "18300:18302,18400:18402,179,4789,6081,8472,51820:51821"
+
+// Validate the comma separated ports or port ranges, such as "179,10250:10259"
+func parseHostNodeSkipPorts(ports string) (string, error) {
+	if ports == "" {
+		return "", nil
+	}
+	list := strings.Split(strings.Replace(ports, " ", "", -1), ",")
+	for _, p := range list {
+		r := strings.Split(p, ":")
+		if len(r) > 2 {
+			return "", fmt.Errorf("Invalid port range %s", p)
+		}
+		var low uint64
+		for i, s := range r {
+			port, err := strconv.ParseUint(s, 10, 16)
+			if err != nil || port == 0 || port < low {
+				return "", fmt.Errorf("Invalid port %s", p)
+			}
+			if i == 0 {
+				low = port
+			}
+		}
+	}
+	return strings.Join(list, ","), nil
+}
+
+func programHostNodeDP(enable bool) {
+	log.WithFields(log.Fields{"enable": enable}).Debug("")
+
+	netns := global.SYS.GetNetNamespacePath(1)
+	if enable {
+		if err := pipe.CreateHostNfqRules(hostNodeNfqNo, agentEnv.hostSkipPorts); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Failed to create host nfq iptable rules")
+			return
+		}
+		var ucmac, bcmac, oldmac, pmac net.HardwareAddr
+		dp.DPCtrlAddMAC(hostNodeIface, hostNodeMac, ucmac, bcmac, oldmac, pmac, nil)
+		jumboFrame := gInfo.jumboFrameMTU
+		dp.DPCtrlAddNfqPort(netns, hostNodeIface, hostNodeNfqNo, hostNodeMac, &jumboFrame)
+		tap := false
+		dp.DPCtrlConfigMAC([]string{hostNodeMacStr}, &tap, nil)
+	} else {
+		//delete dp nfq handle first before reset iptable rules
+		dp.DPCtrlDelNfqPort(netns, hostNodeIface)
+		pipe.DeleteHostNfqRules()
+		dp.DPCtrlDelMAC(hostNodeIface, hostNodeMac)
+	}
+}
+
 func enableTapProxymesh(c *containerData) {
 	updateProxyMeshMac(c, false)
 	programProxyMeshDP(c, false, false)
@@ -1188,6 +1257,7 @@ func fillContainerProperties(c *containerData, parent *containerData,
 		c.inline = isContainerInline(c)
 		c.blocking = isContainerBlocking(c)
 		c.quar = isContainerQuarantine(c)
+		c.quarExcepts = containerQuarExceptions(c)
 	} else {
 		c.service = parent.service
 		c.domain = parent.domain
@@ -1199,6 +1269,7 @@ func fillContainerProperties(c *containerData, parent *containerData,
 		c.inline = parent.inline
 		c.blocking = parent.blocking
 		c.quar = parent.quar
+		c.quarExcepts = parent.quarExcepts
 		if parent.pid == 0 {
 			//NVSHAS-7830, multiple children exist, some may not be runnig
 			//when parent pid=0, need to set all child hasDatapath to true
@@ -1344,7 +1415,7 @@ func programBridge(c *containerData) {
 
 	log.WithFields(log.Fields{"container": c.id}).Debug("")
 
-	if c.quar {
+	if isContainerQuarBlocked(c) {
 		for _, pair := range c.intcpPairs {
 			pipe.ResetPortPair(c.pid, pair)
 		}
@@ -1354,7 +1425,7 @@ func programBridge(c *containerData) {
 				log.WithFields(log.Fields{"container": c.id, "error": err}).Error("Failed to create quarantine iptable rules")
 			}
 		}
-	} else if c.inline {
+	} else if c.inline || c.quar {
 		for _, pair := range c.intcpPairs {
 			pipe.FwdPortPair(c.pid, pair)
 		}
@@ -1452,7 +1523,7 @@ func programBridgeNoTc(c *containerData) {
 
 	log.WithFields(log.Fields{"container": c.id}).Debug("")
 
-	quar := c.quar
+	quar := isContainerQuarBlocked(c)
 	for _, pair := range c.intcpPairs {
 		dp.DPCtrlAddPortPair(pair.ExPort(), pair.InPort(), pair.MAC, &quar)
 	}
@@ -1479,7 +1550,7 @@ func programDP(c *containerData, cfgApp bool, macChangePairs map[string]*pipe.In
 
 	var oldMAC, pMAC net.HardwareAddr
 	tap := false
-	quar := c.quar
+	quar := isContainerQuarBlocked(c)
 	if c.quar || c.inline {
 		for _, pair := range c.intcpPairs {
 			if macChangePairs != nil {
@@ -1594,7 +1665,7 @@ func isNeuvectorContainerById(id string) bool {
 
 // oc49 and above: pod process is none on the cri-o
 func isEmptyProcessPod(info *container.ContainerMetaExtra) bool {
-	if global.RT.String() == container.RuntimeCriO {
+	if rt := global.RT.String(); rt == container.RuntimeCriO || rt == container.RuntimeCri {
 		return (info.Pid == 0) && info.ID == info.Sandbox
 	}
 	return false
@@ -1882,6 +1953,11 @@ func taskInterceptContainer(id string, info *container.ContainerMetaExtra) {
 	workloadJoinGroup(c, parent)
 	updateAppPorts(c, parent)
 	notifyContainerChanges(c, parent, changeInit)
+
+	// threat signatures apply to all workloads with datapath
+	if len(threatSigs) > 0 && c.hasDatapath {
+		dlpConfigRule(lastDlpRules)
+	}
 }
 
 func taskAddContainer(id string, info *container.ContainerMetaExtra) {
@@ -2145,6 +2221,7 @@ func taskDelContainer(id string) {
 	}
 
 	removeContainerLayerPath(id)
+	fileWatcher.RemoveFileBaselines(id)
 
 	gInfoLock()
 	gInfo.allContainers.Remove(id)
@@ -2176,6 +2253,9 @@ func taskDPConnect() {
 			updateProxyMeshMac(c, false)
 		}
 	}
+	if gInfo.hostNetPolicy {
+		programHostNodeDP(true)
+	}
 	pe.PushFqdnInfoToDP()
 	if gInfo.disableNetPolicy == false {
 		pe.PushNetworkPolicyToDP()
@@ -2194,9 +2274,38 @@ func taskDPConnect() {
 	//set detectUnmanagedWl
 	duw := gInfo.detectUnmanagedWl
 	dp.DPCtrlSetDetectUnmanagedWl(&duw)
+	//set flood and scan detection thresholds
+	if gInfo.synFloodThreshold != 0 || gInfo.portScanThreshold != 0 {
+		dp.DPCtrlConfigMeter(gInfo.synFloodThreshold, gInfo.portScanThreshold)
+	}
 }
 
+// the versions are guarded by networkPolicyVerMutex, a policy can be re-applied for a workload config change
+var networkPolicyVerMutex sync.Mutex
 var nextNetworkPolicyVer *share.CLUSGroupIPPolicyVer // incoming network ploicy version
+var lastNetworkPolicyVer *share.CLUSGroupIPPolicyVer // applied network ploicy version
+
+func getLastNetworkPolicyVer() *share.CLUSGroupIPPolicyVer {
+	networkPolicyVerMutex.Lock()
+	defer networkPolicyVerMutex.Unlock()
+	return lastNetworkPolicyVer
+}
+
+func setNextNetworkPolicyVer(s *share.CLUSGroupIPPolicyVer) {
+	networkPolicyVerMutex.Lock()
+	nextNetworkPolicyVer = s
+	networkPolicyVerMutex.Unlock()
+}
+
+// take the incoming version, it is applied once
+func takeNextNetworkPolicyVer() *share.CLUSGroupIPPolicyVer {
+	networkPolicyVerMutex.Lock()
+	defer networkPolicyVerMutex.Unlock()
+	s := nextNetworkPolicyVer
+	nextNetworkPolicyVer = nil
+	return s
+}
+
 // gInfo write should only be done in this thread; and gInfo read doesn't need to be locked
 // in this thread.
 func containerTaskWorker(probeChan chan *probe.ProbeMessage, fsmonChan chan *fsmon.MonitorMessage, dpStatusChan chan bool) {
@@ -2227,12 +2336,11 @@ func containerTaskWorker(probeChan chan *probe.ProbeMessage, fsmonChan chan *fsm
 			ticks++
 			if ticks > pnpTargetTick {
 				ticks = 0
-				if nextNetworkPolicyVer != nil {
-					if !systemUpdatePolicy(*nextNetworkPolicyVer) {
+				if s := takeNextNetworkPolicyVer(); s != nil {
+					if !systemUpdatePolicy(*s) {
 						ticks = pnpTargetTick // version changed? trigger a quick cycle
 					}
 				}
-				nextNetworkPolicyVer = nil
 			}
 		case task := <-ContainerTaskChan:
 			taskName := ContainerTaskName[task.task]
@@ -2302,6 +2410,12 @@ func containerTaskWorker(probeChan chan *probe.ProbeMessage, fsmonChan chan *fsm
 				reportIncident(procViolationToIncidentLog(pmsg.Process, pmsg.Count, pmsg.StartAt))
 			case probe.PROBE_REPORT_PROCESS_DENIED:
 				reportIncident(procDeniedToIncidentLog(pmsg.Process, pmsg.Count, pmsg.StartAt))
+			case probe.PROBE_REPORT_KERNEL_LOAD:
+				reportIncident(kernelLoadToIncidentLog(pmsg.Process, pmsg.Count, pmsg.StartAt, false))
+			case probe.PROBE_REPORT_KERNEL_LOAD_DENIED:
+				reportIncident(kernelLoadToIncidentLog(pmsg.Process, pmsg.Count, pmsg.StartAt, true))
+			case probe.PROBE_REPORT_ESCAPE:
+				reportIncident(escapeToIncidentLog(pmsg.Process, pmsg.Count, pmsg.StartAt))
 			}
 
 			log.WithFields(log.Fields{"msg": msgName}).Debug("Probe message done")
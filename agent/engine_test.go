@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseHostNodeSkipPorts(t *testing.T) {
+	if ports, err := parseHostNodeSkipPorts(defaultHostNodeSkipPorts); err != nil || ports != defaultHostNodeSkipPorts {
+		t.Errorf("Unexpected default ports: %s, %v", ports, err)
+	}
+	if ports, err := parseHostNodeSkipPorts("179, 6443,10250:10259"); err != nil || ports != "179,6443,10250:10259" {
+		t.Errorf("Unexpected ports: %s, %v", ports, err)
+	}
+	if ports, err := parseHostNodeSkipPorts(""); err != nil || ports != "" {
+		t.Errorf("Empty ports should be allowed: %s, %v", ports, err)
+	}
+
+	for _, ports := range []string{"0", "65536", "10259:10250", "1:2:3", "179,", "179;reboot", "a:b"} {
+		if _, err := parseHostNodeSkipPorts(ports); err == nil {
+			t.Errorf("Invalid ports should fail: %s", ports)
+		}
+	}
+}
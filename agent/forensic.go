@@ -0,0 +1,205 @@
+package main
+
+import (
+	"container/heap"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/global"
+	"github.com/neuvector/neuvector/share/osutil"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+const forensicMaxChangedFiles = 1000
+
+// the walk of the writable layer is bounded, so the collection in the grpc call doesn't take too long
+const forensicMaxWalkEntries = 100000
+const forensicWalkTimeout = time.Duration(time.Second * 10)
+
+var errForensicWalkStop = errors.New("Forensic walk stopped")
+
+// values of the environment variables with the secret-like names are not collected
+var forensicSecretEnvRegex = regexp.MustCompile(`(?i)(passw|secret|token|key|credential|cert|auth|private)`)
+
+const forensicMaskedValue = "*****"
+
+type forensicSummary struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Pid         int    `json:"pid"`
+	Host        string `json:"host"`
+	Enforcer    string `json:"enforcer"`
+	CollectedAt string `json:"collected_at"`
+}
+
+type forensicProcess struct {
+	*share.CLUSProcess
+	Libraries []string       `json:"libraries,omitempty"`
+	OpenFiles map[string]int `json:"open_files,omitempty"`
+}
+
+type forensicFileChange struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	Mode    string `json:"mode"`
+	ModTime string `json:"mod_time"`
+}
+
+type forensicFileReport struct {
+	Changed  []*forensicFileChange     `json:"changed"`
+	Baseline []*share.CLUSFileBaseline `json:"baseline_drifts"`
+}
+
+type forensicFileEntry struct {
+	path string
+	info os.FileInfo
+}
+
+// min-heap by modification time, the oldest file is dropped when the heap is full
+type forensicFileHeap []*forensicFileEntry
+
+func (h forensicFileHeap) Len() int            { return len(h) }
+func (h forensicFileHeap) Less(i, j int) bool  { return h[i].info.ModTime().Before(h[j].info.ModTime()) }
+func (h forensicFileHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *forensicFileHeap) Push(x interface{}) { *h = append(*h, x.(*forensicFileEntry)) }
+func (h *forensicFileHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// Files changed in the container's writable layer, the most recent first.
+func collectContainerChangedFiles(c *containerData) []*forensicFileChange {
+	if c.upperDir == "" {
+		return nil
+	}
+
+	root := filepath.Join("/proc/1/root", c.upperDir)
+	list, complete := walkChangedFiles(root, forensicMaxChangedFiles, forensicMaxWalkEntries, time.Now().Add(forensicWalkTimeout))
+	if !complete {
+		log.WithFields(log.Fields{"id": c.id}).Info("Changed files are partially collected")
+	}
+	return list
+}
+
+// Only the most recent files are kept during the walk, and the walk stops at the entry limit or the deadline.
+// Return false if the walk is stopped.
+func walkChangedFiles(root string, maxFiles, maxEntries int, deadline time.Time) ([]*forensicFileChange, bool) {
+	h := make(forensicFileHeap, 0, maxFiles)
+	var entries int
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if entries++; entries > maxEntries || time.Now().After(deadline) {
+			return errForensicWalkStop
+		}
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		if h.Len() < maxFiles {
+			heap.Push(&h, &forensicFileEntry{path: path, info: info})
+		} else if info.ModTime().After(h[0].info.ModTime()) {
+			h[0] = &forensicFileEntry{path: path, info: info}
+			heap.Fix(&h, 0)
+		}
+		return nil
+	})
+
+	list := make([]*forensicFileChange, h.Len())
+	for i := len(list) - 1; i >= 0; i-- {
+		e := heap.Pop(&h).(*forensicFileEntry)
+		list[i] = &forensicFileChange{
+			Path:    strings.TrimPrefix(e.path, root),
+			Size:    e.info.Size(),
+			Mode:    e.info.Mode().String(),
+			ModTime: e.info.ModTime().UTC().Format(time.RFC3339),
+		}
+	}
+	return list, err != errForensicWalkStop
+}
+
+func maskForensicEnvs(envs []string) []string {
+	masked := make([]string, len(envs))
+	for i, env := range envs {
+		if kv := strings.SplitN(env, "=", 2); len(kv) == 2 && forensicSecretEnvRegex.MatchString(kv[0]) {
+			masked[i] = kv[0] + "=" + forensicMaskedValue
+		} else {
+			masked[i] = env
+		}
+	}
+	return masked
+}
+
+// Collect a gzipped tarball of the container's runtime state for incident response
+func collectForensicBundle(c *containerData) ([]byte, error) {
+	procs := prober.GetContainerProcs(c.id)
+	history := prober.GetContainerProcHistory(c.id)
+	baselines := fileWatcher.GetFileBaselines(c.pid)
+	return makeForensicBundle(c, procs, history, baselines)
+}
+
+func makeForensicBundle(c *containerData, procs, history []*share.CLUSProcess, baselines []*share.CLUSFileBaseline) ([]byte, error) {
+	var files []utils.TarFileInfo
+	addJSON := func(name string, v interface{}) {
+		if body, err := json.MarshalIndent(v, "", "  "); err == nil {
+			files = append(files, utils.TarFileInfo{Name: name, Body: body})
+		}
+	}
+
+	addJSON("summary.json", &forensicSummary{
+		ID: c.id, Name: c.name, Pid: c.pid, Host: Host.Name, Enforcer: Agent.ID,
+		CollectedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+
+	// process tree with the libraries and files each process holds
+	sort.Slice(procs, func(i, j int) bool { return procs[i].Pid < procs[j].Pid })
+	fprocs := make([]*forensicProcess, len(procs))
+	for i, proc := range procs {
+		fprocs[i] = &forensicProcess{
+			CLUSProcess: proc,
+			Libraries:   osutil.GetProcessMappedFiles(int(proc.Pid)),
+			OpenFiles:   osutil.GetProcessOpenFiles(int(proc.Pid)),
+		}
+	}
+	addJSON("processes.json", fprocs)
+	addJSON("process_history.json", history)
+
+	// socket tables are read in the container's network namespace
+	for _, table := range []string{"tcp", "tcp6", "udp", "udp6", "raw", "unix"} {
+		if dat, err := ioutil.ReadFile(global.SYS.ContainerProcFilePath(c.pid, "/net/"+table)); err == nil {
+			files = append(files, utils.TarFileInfo{Name: "sockets/" + table, Body: dat})
+		}
+	}
+
+	addJSON("file_changes.json", &forensicFileReport{
+		Changed:  collectContainerChangedFiles(c),
+		Baseline: baselines,
+	})
+
+	envs, err := osutil.GetProcessEnviron(c.pid)
+	if err != nil && c.info != nil {
+		envs, err = c.info.Envs, nil
+	}
+	if err == nil {
+		envs = maskForensicEnvs(envs)
+		files = append(files, utils.TarFileInfo{Name: "environ", Body: []byte(strings.Join(envs, "\n") + "\n")})
+	}
+
+	buf, err := utils.MakeTar(files)
+	if err != nil {
+		log.WithFields(log.Fields{"id": c.id, "error": err}).Error("Failed to make forensic tarball")
+		return nil, fmt.Errorf("Failed to make forensic tarball: %v", err)
+	}
+	return utils.GzipBytes(buf.Bytes()), nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/global"
+	"github.com/neuvector/neuvector/share/system"
+)
+
+func TestMaskForensicEnvs(t *testing.T) {
+	envs := []string{
+		"PATH=/usr/bin:/bin",
+		"DB_PASSWORD=abc=123",
+		"AWS_SECRET_ACCESS_KEY=xyz",
+		"GITHUB_TOKEN=ghp_123",
+		"HOSTNAME=web-1",
+		"NOVALUE",
+	}
+	expect := []string{
+		"PATH=/usr/bin:/bin",
+		"DB_PASSWORD=" + forensicMaskedValue,
+		"AWS_SECRET_ACCESS_KEY=" + forensicMaskedValue,
+		"GITHUB_TOKEN=" + forensicMaskedValue,
+		"HOSTNAME=web-1",
+		"NOVALUE",
+	}
+	if masked := maskForensicEnvs(envs); !reflect.DeepEqual(masked, expect) {
+		t.Errorf("Unexpected masked envs: %+v", masked)
+	}
+}
+
+func TestWalkChangedFiles(t *testing.T) {
+	root, err := ioutil.TempDir("", "forensic")
+	if err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	// files are changed one minute apart, file0 is the oldest
+	now := time.Now()
+	os.MkdirAll(filepath.Join(root, "etc"), 0755)
+	for i := 0; i < 5; i++ {
+		path := filepath.Join(root, "etc", fmt.Sprintf("file%d", i))
+		ioutil.WriteFile(path, []byte(strings.Repeat("x", i)), 0644)
+		mtime := now.Add(time.Duration(i-5) * time.Minute)
+		os.Chtimes(path, mtime, mtime)
+	}
+
+	list, complete := walkChangedFiles(root, 3, 100, now.Add(time.Minute))
+	if !complete {
+		t.Errorf("Walk should be completed")
+	}
+	if len(list) != 3 || list[0].Path != "/etc/file4" || list[1].Path != "/etc/file3" || list[2].Path != "/etc/file2" {
+		t.Errorf("Unexpected changed files: %+v", list)
+	} else if list[0].Size != 4 || list[0].Mode != "-rw-r--r--" {
+		t.Errorf("Unexpected changed file: %+v", list[0])
+	}
+
+	// the walk stops at the entry limit, the root and etc directories are counted
+	if list, complete = walkChangedFiles(root, 10, 4, now.Add(time.Minute)); complete || len(list) != 2 {
+		t.Errorf("Unexpected partial walk: complete=%v files=%+v", complete, list)
+	}
+
+	// the walk stops at the deadline
+	if list, complete = walkChangedFiles(root, 10, 100, now.Add(-time.Minute)); complete || len(list) != 0 {
+		t.Errorf("Unexpected walk after deadline: complete=%v files=%+v", complete, list)
+	}
+
+	if list, complete = walkChangedFiles(filepath.Join(root, "none"), 10, 100, now.Add(time.Minute)); !complete || len(list) != 0 {
+		t.Errorf("Unexpected walk of missing root: complete=%v files=%+v", complete, list)
+	}
+}
+
+func TestMakeForensicBundle(t *testing.T) {
+	if global.SYS == nil {
+		global.SYS = system.NewSystemTools()
+	}
+
+	pid := os.Getpid()
+	c := &containerData{id: "c1", name: "web", pid: pid}
+	procs := []*share.CLUSProcess{
+		{Pid: uint32(pid), Name: "web", Cmds: []string{"web", "--port", "80"}},
+	}
+	history := []*share.CLUSProcess{
+		{Pid: 1, Name: "sh"},
+	}
+	baselines := []*share.CLUSFileBaseline{
+		{Path: "/etc/app.conf", Hash: "abc", Current: "def", Drifted: true},
+	}
+
+	data, err := makeForensicBundle(c, procs, history, baselines)
+	if err != nil {
+		t.Fatalf("Failed to make bundle: %v", err)
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Bundle is not gzipped: %v", err)
+	}
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		files[hdr.Name], _ = ioutil.ReadAll(tr)
+	}
+
+	for _, name := range []string{"summary.json", "processes.json", "process_history.json", "sockets/tcp", "file_changes.json", "environ"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("Missing file in bundle: %v", name)
+		}
+	}
+
+	var summary forensicSummary
+	if err := json.Unmarshal(files["summary.json"], &summary); err != nil || summary.ID != "c1" || summary.Name != "web" || summary.Pid != pid {
+		t.Errorf("Unexpected summary: %+v, error=%v", summary, err)
+	}
+
+	var fprocs []*forensicProcess
+	if err := json.Unmarshal(files["processes.json"], &fprocs); err != nil || len(fprocs) != 1 {
+		t.Fatalf("Unexpected processes: %s, error=%v", files["processes.json"], err)
+	}
+	if fprocs[0].CLUSProcess == nil || fprocs[0].Name != "web" || len(fprocs[0].Libraries) == 0 || len(fprocs[0].OpenFiles) == 0 {
+		t.Errorf("Unexpected process: %s", files["processes.json"])
+	}
+
+	var report forensicFileReport
+	if err := json.Unmarshal(files["file_changes.json"], &report); err != nil || len(report.Changed) != 0 ||
+		len(report.Baseline) != 1 || !report.Baseline[0].Drifted {
+		t.Errorf("Unexpected file changes: %s, error=%v", files["file_changes.json"], err)
+	}
+
+	for _, env := range strings.Split(strings.TrimSpace(string(files["environ"])), "\n") {
+		if kv := strings.SplitN(env, "=", 2); len(kv) == 2 && forensicSecretEnvRegex.MatchString(kv[0]) && kv[1] != forensicMaskedValue {
+			t.Errorf("Secret env is not masked: %v", kv[0])
+		}
+	}
+}
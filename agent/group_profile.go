@@ -498,6 +498,7 @@ func mergeProcessProfiles(cur []*share.CLUSProcessProfileEntry) []*share.CLUSPro
 // (1) share.FileAccessBehaviorBlock > share.FileAccessBehaviorMonitor
 // (2) recursive > non-recursive
 // (3) customer-added > default-setting
+// (4) content baseline > no baseline
 func mergeFileMonitorProfile(filters []share.CLUSFileMonitorFilter) []share.CLUSFileMonitorFilter {
 	m := make(map[string]share.CLUSFileMonitorFilter)
 	for _, ff := range filters { // slice enumeration
@@ -517,6 +518,10 @@ func mergeFileMonitorProfile(filters []share.CLUSFileMonitorFilter) []share.CLUS
 			if v.CustomerAdd {
 				ff.CustomerAdd = true // higher priority
 			}
+
+			if v.Baseline {
+				ff.Baseline = true // higher priority
+			}
 		}
 		m[key] = ff
 	}
@@ -586,6 +591,52 @@ func mergeFileAccessProfile(cur, add *share.CLUSFileAccessRule) {
 	}
 }
 
+// Template group rules are inherited only when the workload has no rule of the same process or path,
+// so the rules of its service and other groups take precedence over the org-wide baseline.
+func inheritTemplateProcesses(tmpl []*share.CLUSProcessProfileEntry, curs ...[]*share.CLUSProcessProfileEntry) []*share.CLUSProcessProfileEntry {
+	keys := utils.NewSet()
+	for _, cur := range curs {
+		for _, p := range cur {
+			keys.Add(fmt.Sprintf("%s:%s", p.Name, p.Path))
+		}
+	}
+
+	list := make([]*share.CLUSProcessProfileEntry, 0, len(tmpl))
+	for _, p := range tmpl {
+		if key := fmt.Sprintf("%s:%s", p.Name, p.Path); !keys.Contains(key) {
+			keys.Add(key)
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+func inheritTemplateFileFilters(tmpl []share.CLUSFileMonitorFilter, curs ...[]share.CLUSFileMonitorFilter) []share.CLUSFileMonitorFilter {
+	keys := utils.NewSet()
+	for _, cur := range curs {
+		for _, ff := range cur {
+			keys.Add(GetPathRegexString(ff.Path, ff.Regex, ff.Recursive))
+		}
+	}
+
+	list := make([]share.CLUSFileMonitorFilter, 0, len(tmpl))
+	for _, ff := range tmpl {
+		if key := GetPathRegexString(ff.Path, ff.Regex, ff.Recursive); !keys.Contains(key) {
+			keys.Add(key)
+			list = append(list, ff)
+		}
+	}
+	return list
+}
+
+func inheritTemplateFileAccess(cur, tmpl *share.CLUSFileAccessRule) {
+	for name, profile := range tmpl.Filters {
+		if _, ok := cur.Filters[name]; !ok {
+			cur.Filters[name] = profile
+		}
+	}
+}
+
 ////
 func calculateProcGroupProfile(id, svc string) (*share.CLUSProcessProfile, bool) {
 	log.WithFields(log.Fields{"id": id, "svc": svc}).Debug("GRP: ")
@@ -594,11 +645,14 @@ func calculateProcGroupProfile(id, svc string) (*share.CLUSProcessProfile, bool)
 	fedproc := &share.CLUSProcessProfile{Process: make([]*share.CLUSProcessProfileEntry, 0)}
 	crdproc := &share.CLUSProcessProfile{Process: make([]*share.CLUSProcessProfileEntry, 0)}
 	proc := &share.CLUSProcessProfile{Process: make([]*share.CLUSProcessProfileEntry, 0)}
+	tmplproc := make([]*share.CLUSProcessProfileEntry, 0)
 	grpCacheLock.Lock()
 	for grpName, grpCache := range grpProfileCacheMap {
 		if grpCache.members.Contains(id) {
 			if strings.HasPrefix(grpName, federalGrpPrefix) {
 				fedproc.Process = append(fedproc.Process, grpCache.proc.Process...)
+			} else if grpCache.group.Template {
+				tmplproc = append(tmplproc, grpCache.proc.Process...)
 			} else {
 				for _, p := range grpCache.proc.Process { // separate CRD and other types
 					if p.CfgType == share.GroundCfg {
@@ -627,6 +681,10 @@ func calculateProcGroupProfile(id, svc string) (*share.CLUSProcessProfile, bool)
 		}
 	}
 
+	if len(tmplproc) > 0 {
+		proc.Process = append(proc.Process, inheritTemplateProcesses(tmplproc, crdproc.Process, proc.Process)...)
+	}
+
 	// remove the duplicate entries and prioritize the sequences
 	pp := &share.CLUSProcessProfile{Process: make([]*share.CLUSProcessProfileEntry, 0)}
 	if len(fedproc.Process) > 0 {
@@ -680,9 +738,16 @@ func calculateFileGroupProfile(id, svc string) (*share.CLUSFileMonitorProfile, *
 		FiltersCRD: make(map[string]*share.CLUSFileAccessFilterRule),
 	} // from (map).Filter
 
+	tmplfile := make([]share.CLUSFileMonitorFilter, 0)
+	tmplaccess := &share.CLUSFileAccessRule{Filters: make(map[string]*share.CLUSFileAccessFilterRule)}
 	grpCacheLock.Lock()
 	for _, grpCache := range grpProfileCacheMap {
 		if grpCache.members.Contains(id) {
+			if grpCache.group.Template {
+				tmplfile = append(tmplfile, grpCache.file.Filters...)
+				inheritTemplateFileAccess(tmplaccess, grpCache.access)
+				continue
+			}
 			file.Filters = append(file.Filters, grpCache.file.Filters...)
 			file.FiltersCRD = append(file.FiltersCRD, grpCache.file.FiltersCRD...)
 			mergeFileAccessProfile(access, grpCache.access)
@@ -703,6 +768,9 @@ func calculateFileGroupProfile(id, svc string) (*share.CLUSFileMonitorProfile, *
 
 	// merge regular files
 	file.Filters = append(file.Filters, svc_file.Filters...)
+	if len(tmplfile) > 0 {
+		file.Filters = append(file.Filters, inheritTemplateFileFilters(tmplfile, file.FiltersCRD, svc_file.FiltersCRD, file.Filters)...)
+	}
 	file.Filters = mergeFileMonitorProfile(file.Filters)
 
 	// merge CRD files
@@ -720,6 +788,7 @@ func calculateFileGroupProfile(id, svc string) (*share.CLUSFileMonitorProfile, *
 	//	log.WithFields(log.Fields{"name": name, "profile": profile}).Debug("GRP:")
 	//}
 	mergeFileAccessProfile(access, svc_access)
+	inheritTemplateFileAccess(access, tmplaccess)
 	return file, access, true
 }
 
@@ -740,3 +740,75 @@ func TestFileAccessPolicy(t *testing.T) {
 		}
 	}
 }
+
+func TestTemplateProcessPolicy(t *testing.T) {
+	// template: org-wide baseline
+	tmpl_rule := []*share.CLUSProcessProfileEntry{
+		&share.CLUSProcessProfileEntry{Name: "curl", Path: "/usr/bin/curl", Action: share.PolicyActionDeny},
+		&share.CLUSProcessProfileEntry{Name: "sh", Path: "/bin/sh", Action: share.PolicyActionDeny},
+		&share.CLUSProcessProfileEntry{Name: "sleep", Path: "/bin/sleep", Action: share.PolicyActionAllow},
+	}
+
+	// workload: overrides the curl rule of the template
+	workload_rule := []*share.CLUSProcessProfileEntry{
+		&share.CLUSProcessProfileEntry{Name: "curl", Path: "/usr/bin/curl", Action: share.PolicyActionAllow},
+		&share.CLUSProcessProfileEntry{Name: "nginx", Path: "/usr/sbin/nginx", Action: share.PolicyActionAllow},
+	}
+
+	profile := append(workload_rule, inheritTemplateProcesses(tmpl_rule, workload_rule)...)
+	profile = mergeProcessProfiles(profile)
+
+	apps := []appSample{
+		{name: "curl", path: "/usr/bin/curl", good: true},
+		{name: "nginx", path: "/usr/sbin/nginx", good: true},
+		{name: "sleep", path: "/bin/sleep", good: true},
+		{name: "sh", path: "/bin/sh", good: false},
+	}
+
+	if !tester(profile, apps) {
+		t.Errorf("")
+	}
+}
+
+func TestTemplateFilePolicy(t *testing.T) {
+	// template: org-wide baseline
+	tmpl_rule := []share.CLUSFileMonitorFilter{
+		share.CLUSFileMonitorFilter{Filter: "/etc/passwd", Path: "/etc/passwd", Behavior: share.FileAccessBehaviorBlock, CustomerAdd: true},
+		share.CLUSFileMonitorFilter{Filter: "/etc/shadow", Path: "/etc/shadow", Behavior: share.FileAccessBehaviorBlock, CustomerAdd: true},
+	}
+	tmpl_access := &share.CLUSFileAccessRule{Filters: map[string]*share.CLUSFileAccessFilterRule{
+		"/etc/passwd": &share.CLUSFileAccessFilterRule{Apps: []string{}, Behavior: share.FileAccessBehaviorBlock},
+		"/etc/shadow": &share.CLUSFileAccessFilterRule{Apps: []string{}, Behavior: share.FileAccessBehaviorBlock},
+	}}
+
+	// workload: overrides the passwd rule of the template
+	workload_rule := []share.CLUSFileMonitorFilter{
+		share.CLUSFileMonitorFilter{Filter: "/etc/passwd", Path: "/etc/passwd", Behavior: share.FileAccessBehaviorMonitor, CustomerAdd: true},
+	}
+	workload_access := &share.CLUSFileAccessRule{Filters: map[string]*share.CLUSFileAccessFilterRule{
+		"/etc/passwd": &share.CLUSFileAccessFilterRule{Apps: []string{"useradd"}, Behavior: share.FileAccessBehaviorMonitor},
+	}}
+
+	filters := append(workload_rule, inheritTemplateFileFilters(tmpl_rule, workload_rule)...)
+	filters = mergeFileMonitorProfile(filters)
+
+	files := []fileSample{
+		{path: "/etc/passwd", behavior: share.FileAccessBehaviorMonitor, good: true},
+		{path: "/etc/passwd", behavior: share.FileAccessBehaviorBlock},
+		{path: "/etc/shadow", behavior: share.FileAccessBehaviorBlock, good: true},
+		{path: "/etc/shadow", behavior: share.FileAccessBehaviorMonitor},
+	}
+	if !evalFileMonitor(filters, files) {
+		t.Errorf("")
+	}
+
+	access := &share.CLUSFileAccessRule{Filters: make(map[string]*share.CLUSFileAccessFilterRule)}
+	mergeFileAccessProfile(access, workload_access)
+	inheritTemplateFileAccess(access, tmpl_access)
+	if v, ok := access.Filters["/etc/passwd"]; !ok || v.Behavior != share.FileAccessBehaviorMonitor {
+		t.Errorf("Unexpected access rule of /etc/passwd: %+v", v)
+	}
+	if v, ok := access.Filters["/etc/shadow"]; !ok || v.Behavior != share.FileAccessBehaviorBlock {
+		t.Errorf("Unexpected access rule of /etc/shadow: %+v", v)
+	}
+}
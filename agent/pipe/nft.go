@@ -0,0 +1,185 @@
+package pipe
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/codeskyblue/go-sh"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/global"
+)
+
+const (
+	FW_IPTABLES = "iptables"
+	FW_NFTABLES = "nftables"
+)
+
+// Each group of rules lives in its own table, so that a group can be
+// replaced atomically and removed without touching rules of others.
+const nvNftTable string = "nv_proxymesh"
+const nvNftQuarTable string = "nv_quar_proxymesh"
+const nvNftHostTable string = "nv_host"
+
+// Detect which backend is used by the host. If the host has rules programmed
+// by legacy iptables, keep using iptables; if it only has nftables rules,
+// either by nft or iptables-nft, program nftables natively so the rules don't
+// interleave with the host's in an unexpected order.
+func detectFirewallBackend() string {
+	backend := FW_IPTABLES
+	global.SYS.CallNetNamespaceFunc(1, func(params interface{}) {
+		if out, err := shell("iptables-save"); err == nil && countIptablesRules(out) > 0 {
+			return
+		}
+		if out, err := shell("nft list tables"); err == nil && len(bytes.TrimSpace(out)) > 0 {
+			backend = FW_NFTABLES
+		}
+	}, nil)
+	return backend
+}
+
+func countIptablesRules(out []byte) int {
+	var cnt int
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "-A ") {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+func nftApply(ruleset string) error {
+	out, err := sh.Command("nft", "-f", "-").SetInput(ruleset).CombinedOutput()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "out": string(out)}).Error("Failed to apply nftables rules")
+	}
+	return err
+}
+
+func deleteNftTable(table string) {
+	cmd := fmt.Sprintf("nft delete table inet %v", table)
+	shellCombined(cmd)
+}
+
+// Declaring the table before flushing it makes the ruleset valid whether
+// the table exists or not, and the whole file is committed in one transaction.
+func nftTableHeader(table string) string {
+	return fmt.Sprintf("table inet %v {}\nflush table inet %v\n", table, table)
+}
+
+/*
+ * table inet nv_proxymesh {
+ *     chain input {
+ *         type filter hook input priority 0; policy accept;
+ *         iifname "lo" tcp sport 8080 queue num 0 bypass
+ *     }
+ *     chain output {
+ *         type filter hook output priority 0; policy accept;
+ *         oifname "lo" tcp dport 8080 queue num 0 bypass
+ *     }
+ * }
+ */
+func createNftNvRules(intf string, isloopback bool, qno int, appMap map[share.CLUSProtoPort]*share.CLUSApp) error {
+	return nftApply(nftNvRuleset(intf, isloopback, qno, appMap))
+}
+
+func nftNvRuleset(intf string, isloopback bool, qno int, appMap map[share.CLUSProtoPort]*share.CLUSApp) string {
+	var input, output []string
+	if len(appMap) == 0 {
+		input = append(input, fmt.Sprintf("iifname \"%v\" queue num %d bypass", intf, qno))
+		output = append(output, fmt.Sprintf("oifname \"%v\" queue num %d bypass", intf, qno))
+	} else {
+		// keep the rules in order, so the same apps always generate the same ruleset
+		ports := make([]share.CLUSProtoPort, 0, len(appMap))
+		for p := range appMap {
+			ports = append(ports, p)
+		}
+		sort.Slice(ports, func(i, j int) bool {
+			if ports[i].IPProto != ports[j].IPProto {
+				return ports[i].IPProto < ports[j].IPProto
+			}
+			return ports[i].Port < ports[j].Port
+		})
+
+		for _, p := range ports {
+			var proto string
+			switch p.IPProto {
+			case syscall.IPPROTO_TCP:
+				proto = "tcp"
+			case syscall.IPPROTO_UDP:
+				proto = "udp"
+			default:
+				continue
+			}
+			if isloopback {
+				input = append(input, fmt.Sprintf("iifname \"%v\" %v sport %d queue num %d bypass", intf, proto, p.Port, qno))
+				output = append(output, fmt.Sprintf("oifname \"%v\" %v dport %d queue num %d bypass", intf, proto, p.Port, qno))
+			} else {
+				input = append(input, fmt.Sprintf("iifname \"%v\" %v dport %d queue num %d bypass", intf, proto, p.Port, qno))
+				output = append(output, fmt.Sprintf("oifname \"%v\" %v sport %d queue num %d bypass", intf, proto, p.Port, qno))
+			}
+		}
+	}
+
+	return nftTableHeader(nvNftTable) + nftTableBody(nvNftTable, "0", input, output)
+}
+
+func createNftNvQuarRules() error {
+	return nftApply(nftNvQuarRuleset())
+}
+
+func nftNvQuarRuleset() string {
+	input := []string{"drop"}
+	output := []string{"drop"}
+	return nftTableHeader(nvNftQuarTable) + nftTableBody(nvNftQuarTable, "-1", input, output)
+}
+
+// The nftables equivalent of the NV_INPUT_HOST/NV_OUTPUT_HOST chains
+func createNftHostRules(qno int, skipPorts string) error {
+	return nftApply(nftHostRuleset(qno, skipPorts))
+}
+
+func nftHostRuleset(qno int, skipPorts string) string {
+	var input, output []string
+
+	input = append(input, "iifname \"lo\" return")
+	output = append(output, "oifname \"lo\" return")
+	if skipPorts != "" {
+		ports := strings.Replace(skipPorts, ":", "-", -1)
+		for _, proto := range []string{"tcp", "udp"} {
+			input = append(input, fmt.Sprintf("%v sport { %v } return", proto, ports))
+			input = append(input, fmt.Sprintf("%v dport { %v } return", proto, ports))
+			output = append(output, fmt.Sprintf("%v sport { %v } return", proto, ports))
+			output = append(output, fmt.Sprintf("%v dport { %v } return", proto, ports))
+		}
+	}
+	input = append(input, fmt.Sprintf("queue num %d bypass", qno))
+	output = append(output, fmt.Sprintf("queue num %d bypass", qno))
+
+	return nftTableHeader(nvNftHostTable) + nftTableBody(nvNftHostTable, "-1", input, output)
+}
+
+func nftTableBody(table, priority string, input, output []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "table inet %v {\n", table)
+	for _, c := range []struct {
+		name  string
+		rules []string
+	}{
+		{"input", input},
+		{"output", output},
+	} {
+		fmt.Fprintf(&b, "\tchain %v {\n", c.name)
+		fmt.Fprintf(&b, "\t\ttype filter hook %v priority %v; policy accept;\n", c.name, priority)
+		for _, r := range c.rules {
+			fmt.Fprintf(&b, "\t\t%v\n", r)
+		}
+		b.WriteString("\t}\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
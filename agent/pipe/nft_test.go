@@ -0,0 +1,163 @@
+package pipe
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestNftRulesets(t *testing.T) {
+	apps := map[share.CLUSProtoPort]*share.CLUSApp{
+		{IPProto: syscall.IPPROTO_UDP, Port: 53}:   {},
+		{IPProto: syscall.IPPROTO_TCP, Port: 8080}: {},
+		{IPProto: syscall.IPPROTO_TCP, Port: 443}:  {},
+		{IPProto: syscall.IPPROTO_ICMP, Port: 0}:   {},
+	}
+
+	tests := []struct {
+		name    string
+		ruleset string
+		expect  string
+	}{
+		{
+			name:    "no apps",
+			ruleset: nftNvRuleset("eth0", false, 0, nil),
+			expect: "table inet nv_proxymesh {}\n" +
+				"flush table inet nv_proxymesh\n" +
+				"table inet nv_proxymesh {\n" +
+				"\tchain input {\n" +
+				"\t\ttype filter hook input priority 0; policy accept;\n" +
+				"\t\tiifname \"eth0\" queue num 0 bypass\n" +
+				"\t}\n" +
+				"\tchain output {\n" +
+				"\t\ttype filter hook output priority 0; policy accept;\n" +
+				"\t\toifname \"eth0\" queue num 0 bypass\n" +
+				"\t}\n" +
+				"}\n",
+		},
+		{
+			name:    "apps",
+			ruleset: nftNvRuleset("eth0", false, 1, apps),
+			expect: "table inet nv_proxymesh {}\n" +
+				"flush table inet nv_proxymesh\n" +
+				"table inet nv_proxymesh {\n" +
+				"\tchain input {\n" +
+				"\t\ttype filter hook input priority 0; policy accept;\n" +
+				"\t\tiifname \"eth0\" tcp dport 443 queue num 1 bypass\n" +
+				"\t\tiifname \"eth0\" tcp dport 8080 queue num 1 bypass\n" +
+				"\t\tiifname \"eth0\" udp dport 53 queue num 1 bypass\n" +
+				"\t}\n" +
+				"\tchain output {\n" +
+				"\t\ttype filter hook output priority 0; policy accept;\n" +
+				"\t\toifname \"eth0\" tcp sport 443 queue num 1 bypass\n" +
+				"\t\toifname \"eth0\" tcp sport 8080 queue num 1 bypass\n" +
+				"\t\toifname \"eth0\" udp sport 53 queue num 1 bypass\n" +
+				"\t}\n" +
+				"}\n",
+		},
+		{
+			name:    "loopback apps",
+			ruleset: nftNvRuleset("lo", true, 0, apps),
+			expect: "table inet nv_proxymesh {}\n" +
+				"flush table inet nv_proxymesh\n" +
+				"table inet nv_proxymesh {\n" +
+				"\tchain input {\n" +
+				"\t\ttype filter hook input priority 0; policy accept;\n" +
+				"\t\tiifname \"lo\" tcp sport 443 queue num 0 bypass\n" +
+				"\t\tiifname \"lo\" tcp sport 8080 queue num 0 bypass\n" +
+				"\t\tiifname \"lo\" udp sport 53 queue num 0 bypass\n" +
+				"\t}\n" +
+				"\tchain output {\n" +
+				"\t\ttype filter hook output priority 0; policy accept;\n" +
+				"\t\toifname \"lo\" tcp dport 443 queue num 0 bypass\n" +
+				"\t\toifname \"lo\" tcp dport 8080 queue num 0 bypass\n" +
+				"\t\toifname \"lo\" udp dport 53 queue num 0 bypass\n" +
+				"\t}\n" +
+				"}\n",
+		},
+		{
+			name:    "quarantine",
+			ruleset: nftNvQuarRuleset(),
+			expect: "table inet nv_quar_proxymesh {}\n" +
+				"flush table inet nv_quar_proxymesh\n" +
+				"table inet nv_quar_proxymesh {\n" +
+				"\tchain input {\n" +
+				"\t\ttype filter hook input priority -1; policy accept;\n" +
+				"\t\tdrop\n" +
+				"\t}\n" +
+				"\tchain output {\n" +
+				"\t\ttype filter hook output priority -1; policy accept;\n" +
+				"\t\tdrop\n" +
+				"\t}\n" +
+				"}\n",
+		},
+		{
+			name:    "host",
+			ruleset: nftHostRuleset(2, ""),
+			expect: "table inet nv_host {}\n" +
+				"flush table inet nv_host\n" +
+				"table inet nv_host {\n" +
+				"\tchain input {\n" +
+				"\t\ttype filter hook input priority -1; policy accept;\n" +
+				"\t\tiifname \"lo\" return\n" +
+				"\t\tqueue num 2 bypass\n" +
+				"\t}\n" +
+				"\tchain output {\n" +
+				"\t\ttype filter hook output priority -1; policy accept;\n" +
+				"\t\toifname \"lo\" return\n" +
+				"\t\tqueue num 2 bypass\n" +
+				"\t}\n" +
+				"}\n",
+		},
+		{
+			name:    "host with skipped ports",
+			ruleset: nftHostRuleset(2, "18300:18500,22"),
+			expect: "table inet nv_host {}\n" +
+				"flush table inet nv_host\n" +
+				"table inet nv_host {\n" +
+				"\tchain input {\n" +
+				"\t\ttype filter hook input priority -1; policy accept;\n" +
+				"\t\tiifname \"lo\" return\n" +
+				"\t\ttcp sport { 18300-18500,22 } return\n" +
+				"\t\ttcp dport { 18300-18500,22 } return\n" +
+				"\t\tudp sport { 18300-18500,22 } return\n" +
+				"\t\tudp dport { 18300-18500,22 } return\n" +
+				"\t\tqueue num 2 bypass\n" +
+				"\t}\n" +
+				"\tchain output {\n" +
+				"\t\ttype filter hook output priority -1; policy accept;\n" +
+				"\t\toifname \"lo\" return\n" +
+				"\t\ttcp sport { 18300-18500,22 } return\n" +
+				"\t\ttcp dport { 18300-18500,22 } return\n" +
+				"\t\tudp sport { 18300-18500,22 } return\n" +
+				"\t\tudp dport { 18300-18500,22 } return\n" +
+				"\t\tqueue num 2 bypass\n" +
+				"\t}\n" +
+				"}\n",
+		},
+	}
+
+	for _, test := range tests {
+		if test.ruleset != test.expect {
+			t.Errorf("%v: unexpected ruleset:\n%v\nexpect:\n%v", test.name, test.ruleset, test.expect)
+		}
+	}
+}
+
+func TestCountIptablesRules(t *testing.T) {
+	tests := []struct {
+		out string
+		cnt int
+	}{
+		{"", 0},
+		{"# Generated by iptables-save\n*filter\n:INPUT ACCEPT [0:0]\nCOMMIT\n", 0},
+		{"*filter\n:INPUT ACCEPT [0:0]\n-A INPUT -i lo -j ACCEPT\n-A FORWARD -j DOCKER\nCOMMIT\n", 2},
+	}
+
+	for i, test := range tests {
+		if cnt := countIptablesRules([]byte(test.out)); cnt != test.cnt {
+			t.Errorf("%v: unexpected count: %v, expect=%v", i, cnt, test.cnt)
+		}
+	}
+}
@@ -88,6 +88,7 @@ var piper pipeInterface
 type pipeConfig struct {
 	workingPid int
 	cnet_type  string
+	fwBackend  string
 }
 
 var cfg pipeConfig
@@ -1457,9 +1458,16 @@ func CreateNfqQuarRules(pid int, create bool) error {
 		return err
 	}
 
-	deleteIptablesNvQuarRules()
-	if create {
-		createIptablesNvQuarRules()
+	if cfg.fwBackend == FW_NFTABLES {
+		deleteNftTable(nvNftQuarTable)
+		if create {
+			createNftNvQuarRules()
+		}
+	} else {
+		deleteIptablesNvQuarRules()
+		if create {
+			createIptablesNvQuarRules()
+		}
 	}
 	// Switch back to original NS
 	log.WithFields(log.Fields{"ns": curNs}).Debug("Restore ns")
@@ -1497,7 +1505,10 @@ func CreateNfqRules(pid, qno int, create, isloopback bool, intf string, appMap m
 		return err
 	}
 
-	if create {
+	if cfg.fwBackend == FW_NFTABLES {
+		// the table is replaced in one transaction, so no need to check existing rules
+		createNftNvRules(intf, isloopback, qno, appMap)
+	} else if create {
 		//create iptable rules
 		resetIptablesNvRules()
 		createIptablesNvRules(intf, isloopback, qno, appMap)
@@ -1542,8 +1553,13 @@ func DeleteNfqRules(pid int) error {
 	}
 
 	//delete iptable rules
-	resetIptablesNvRules()
-	deleteIptablesNvQuarRules()
+	if cfg.fwBackend == FW_NFTABLES {
+		deleteNftTable(nvNftTable)
+		deleteNftTable(nvNftQuarTable)
+	} else {
+		resetIptablesNvRules()
+		deleteIptablesNvQuarRules()
+	}
 
 	// Switch back to original NS
 	log.WithFields(log.Fields{"ns": curNs}).Debug("Restore ns")
@@ -1552,6 +1568,137 @@ func DeleteNfqRules(pid int) error {
 	return err
 }
 
+const nvInputHostChain string = "NV_INPUT_HOST"
+const nvOutputHostChain string = "NV_OUTPUT_HOST"
+
+// Host rules are installed for both address families, otherwise IPv6 traffic
+// of host processes would bypass the queue.
+var iptablesHostCmds []string = []string{"iptables", "ip6tables"}
+
+func resetIptablesHostRules() {
+	var cmd string
+	for _, ipt := range iptablesHostCmds {
+		//disassociate OUTPUT/INPUT with NV_OUTPUT_HOST/NV_INPUT_HOST
+		cmd = fmt.Sprintf("%v -D OUTPUT -j %v", ipt, nvOutputHostChain)
+		shellCombined(cmd)
+		cmd = fmt.Sprintf("%v -D INPUT -j %v", ipt, nvInputHostChain)
+		shellCombined(cmd)
+
+		//flush custom chain of its rules
+		cmd = fmt.Sprintf("%v -F %v", ipt, nvOutputHostChain)
+		shellCombined(cmd)
+		cmd = fmt.Sprintf("%v -F %v", ipt, nvInputHostChain)
+		shellCombined(cmd)
+
+		//delete custom chain
+		cmd = fmt.Sprintf("%v -X %v", ipt, nvOutputHostChain)
+		shellCombined(cmd)
+		cmd = fmt.Sprintf("%v -X %v", ipt, nvInputHostChain)
+		shellCombined(cmd)
+	}
+}
+
+// A multiport match takes up to 15 ports, a port range counts as two
+const iptablesMaxMultiports int = 15
+
+func splitMultiports(ports string) []string {
+	var list []string
+	var cur []string
+	var n int
+	for _, p := range strings.Split(ports, ",") {
+		w := 1
+		if strings.Contains(p, ":") {
+			w = 2
+		}
+		if n+w > iptablesMaxMultiports {
+			list = append(list, strings.Join(cur, ","))
+			cur, n = nil, 0
+		}
+		cur = append(cur, p)
+		n += w
+	}
+	if len(cur) > 0 {
+		list = append(list, strings.Join(cur, ","))
+	}
+	return list
+}
+
+/*
+ * Traffic routed to containers goes through FORWARD chain, so only traffic
+ * of host processes is queued here. Loopback and the skipped ports, such as
+ * neuvector's own ports and the cluster control ports, are returned so that
+ * the cluster keeps working whatever the host policy is.
+ * iptables -N NV_INPUT_HOST
+ * iptables -A NV_INPUT_HOST -i lo -j RETURN
+ * iptables -A NV_INPUT_HOST -p tcp -m multiport --ports 18300:18302,18400:18402,... -j RETURN
+ * iptables -A NV_INPUT_HOST -p udp -m multiport --ports 18300:18302,18400:18402,... -j RETURN
+ * iptables -A NV_INPUT_HOST -j NFQUEUE --queue-num 1 --queue-bypass
+ * iptables -I INPUT -j NV_INPUT_HOST
+ * The same chains are created with ip6tables.
+ */
+func createIptablesHostRules(qno int, skipPorts string) {
+	for _, ipt := range iptablesHostCmds {
+		createIptablesHostChains(ipt, qno, skipPorts)
+	}
+}
+
+func createIptablesHostChains(ipt string, qno int, skipPorts string) {
+	var cmd string
+	for _, chain := range []string{nvInputHostChain, nvOutputHostChain} {
+		cmd = fmt.Sprintf("%v -N %v", ipt, chain)
+		shellCombined(cmd)
+
+		dir := "-i"
+		if chain == nvOutputHostChain {
+			dir = "-o"
+		}
+		cmd = fmt.Sprintf("%v -A %v %v lo -j RETURN", ipt, chain, dir)
+		shellCombined(cmd)
+		if skipPorts != "" {
+			for _, ports := range splitMultiports(skipPorts) {
+				cmd = fmt.Sprintf("%v -A %v -p tcp -m multiport --ports %v -j RETURN", ipt, chain, ports)
+				shellCombined(cmd)
+				cmd = fmt.Sprintf("%v -A %v -p udp -m multiport --ports %v -j RETURN", ipt, chain, ports)
+				shellCombined(cmd)
+			}
+		}
+		cmd = fmt.Sprintf("%v -A %v -j NFQUEUE --queue-num %d --queue-bypass", ipt, chain, qno)
+		shellCombined(cmd)
+	}
+
+	//associate NV_OUTPUT_HOST/NV_INPUT_HOST with OUTPUT/INPUT chain
+	cmd = fmt.Sprintf("%v -I INPUT -j %v", ipt, nvInputHostChain)
+	shellCombined(cmd)
+	cmd = fmt.Sprintf("%v -I OUTPUT -j %v", ipt, nvOutputHostChain)
+	shellCombined(cmd)
+}
+
+//setup iptable rules with NFQUEUE target in host network namespace
+func CreateHostNfqRules(qno int, skipPorts string) error {
+	log.WithFields(log.Fields{"qno": qno, "skip": skipPorts}).Debug("")
+
+	return global.SYS.CallNetNamespaceFunc(1, func(params interface{}) {
+		if cfg.fwBackend == FW_NFTABLES {
+			createNftHostRules(qno, skipPorts)
+		} else {
+			resetIptablesHostRules()
+			createIptablesHostRules(qno, skipPorts)
+		}
+	}, nil)
+}
+
+func DeleteHostNfqRules() error {
+	log.Debug("")
+
+	return global.SYS.CallNetNamespaceFunc(1, func(params interface{}) {
+		if cfg.fwBackend == FW_NFTABLES {
+			deleteNftTable(nvNftHostTable)
+		} else {
+			resetIptablesHostRules()
+		}
+	}, nil)
+}
+
 type pipeParam struct {
 	pid  int
 	pair *InterceptPair
@@ -1600,7 +1747,7 @@ func GetPortPairDebug(pair *InterceptPair) *share.CLUSWorkloadInterceptPort {
 	return &p
 }
 
-func Open(driver string, cnet_type *string, pid int, jumboframe bool) (string, string, error) {
+func Open(driver string, cnet_type *string, pid int, jumboframe bool, fw string) (string, string, error) {
 	switch driver {
 	case PIPE_OVS:
 		piper = &ovsPipe
@@ -1619,6 +1766,13 @@ func Open(driver string, cnet_type *string, pid int, jumboframe bool) (string, s
 	} else {
 		cfg.cnet_type = CNET_DEFAULT
 	}
+	switch fw {
+	case FW_IPTABLES, FW_NFTABLES:
+		cfg.fwBackend = fw
+	default:
+		cfg.fwBackend = detectFirewallBackend()
+	}
+	log.WithFields(log.Fields{"backend": cfg.fwBackend}).Info("Firewall backend")
 	piper.Connect(jumboframe)
 	return nvVthPortName, nvVbrPortName, nil
 }
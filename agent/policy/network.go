@@ -25,6 +25,12 @@ type fqdnInfo struct {
 
 var fqdnMap map[string]*fqdnInfo = make(map[string]*fqdnInfo)
 
+// Domain names are only resolved to ipv4 addresses by the enforcer, so domain rules are not
+// applied to ipv6 addresses and such connections fall to the default action. The skipped rules
+// are logged when they change after the policy calculation.
+var fqdnIPv6Rules utils.Set = utils.NewSet()
+var fqdnIPv6RulesLogged utils.Set = utils.NewSet()
+
 func isWorkloadFqdn(wl string) bool {
 	return strings.HasPrefix(wl, share.CLUSWLFqdnPrefix)
 }
@@ -94,6 +100,7 @@ func fqdnInfoPrePolicyCalc() {
 	for _, info := range fqdnMap {
 		info.used = false
 	}
+	fqdnIPv6Rules.Clear()
 }
 
 func fqdnInfoPostPolicyCalc(hid string) {
@@ -115,6 +122,15 @@ func fqdnInfoPostPolicyCalc(hid string) {
 		}
 	}
 
+	if !fqdnIPv6Rules.Equal(fqdnIPv6RulesLogged) {
+		if fqdnIPv6Rules.Cardinality() > 0 {
+			log.WithFields(log.Fields{
+				"rules": fqdnIPv6Rules.ToSlice(),
+			}).Error("Domain rules are not enforced on ipv6 addresses")
+		}
+		fqdnIPv6RulesLogged = fqdnIPv6Rules.Clone()
+	}
+
 	if len(fqdnMap) > C.DP_POLICY_FQDN_MAX_ENTRIES {
 		// Todo: trigger event logging
 		log.WithFields(log.Fields{
@@ -148,6 +164,30 @@ type ruleContext struct {
 	id      uint32
 	fqdn    string
 	vhost	bool
+	rate     uint32
+	rateID   uint32
+	rateUnit uint8
+}
+
+// Rules can only be enforced between addresses of the same family. The external
+// address is an ipv4 placeholder, switch it to ipv6 when the peer is ipv6.
+func alignIPFamily(from, to net.IP) (net.IP, net.IP, bool) {
+	if utils.IsIPv4(from) == utils.IsIPv4(to) {
+		return from, to, true
+	}
+	if from.Equal(net.IPv4zero) {
+		return net.IPv6zero, to, true
+	} else if to.Equal(net.IPv4zero) {
+		return from, net.IPv6zero, true
+	}
+	return from, to, false
+}
+
+func loopbackIP(ip net.IP) net.IP {
+	if utils.IsIPv4(ip) {
+		return utils.IPv4Loopback
+	}
+	return net.IPv6loopback
 }
 
 func createIPRule(from, to, fromR, toR net.IP, portApps []share.CLUSPortApp, action uint8,
@@ -155,6 +195,16 @@ func createIPRule(from, to, fromR, toR net.IP, portApps []share.CLUSPortApp, act
 
 	id := ctx.id
 
+	if ctx.fqdn != "" && (!utils.IsIPv4(from) || !utils.IsIPv4(to)) {
+		fqdnIPv6Rules.Add(id)
+		return
+	}
+
+	var ok bool
+	if from, to, ok = alignIPFamily(from, to); !ok {
+		return
+	}
+
 	/*
 		log.WithFields(log.Fields{
 			"id": id, "from": from, "to": to, "fromR": fromR, "toR": toR,
@@ -234,6 +284,9 @@ func createIPRule(from, to, fromR, toR net.IP, portApps []share.CLUSPortApp, act
 				Ingress: ctx.ingress,
 				Fqdn:    ctx.fqdn,
 				Vhost:	 ctx.vhost,
+				Rate:     ctx.rate,
+				RateID:   ctx.rateID,
+				RateUnit: ctx.rateUnit,
 			}
 
 			// For host mode container, only check ports, not applications.
@@ -320,6 +373,17 @@ func (e *Engine) createWorkloadRule(from, to *share.CLUSWorkloadAddr, policy *sh
 	}
 
 	ctx := &ruleContext{ingress: ingress, id: policy.ID}
+
+	// Traffic is only throttled where a deny would be enforced
+	if policy.RateLimit > 0 && action == C.DP_POLICY_ACTION_ALLOW && pInfo.CapIntcp &&
+		adjustAction(C.DP_POLICY_ACTION_DENY, from, to, policy.ID) == C.DP_POLICY_ACTION_DENY {
+		ctx.rate, ctx.rateID, ctx.rateUnit = policy.RateLimit, policy.RateID, policy.RateUnit
+		if ctx.rateID == 0 {
+			// from the controller without group rate limit
+			ctx.rateID = policy.ID
+		}
+	}
+
 	if ingress == false && isWorkloadFqdn(to.WlID) {
 		ctx.fqdn, ctx.vhost = getFqdnName(to.WlID)
 	} else if ingress == true && isWorkloadFqdn(from.WlID) {
@@ -329,7 +393,10 @@ func (e *Engine) createWorkloadRule(from, to *share.CLUSWorkloadAddr, policy *sh
 	if ingress == false {
 		var fromIPList []net.IP
 
-		if pInfo.HostMode {
+		if pInfo.HostNode {
+			// host processes can use any of the host addresses
+			fromIPList = from.NatIP
+		} else if pInfo.HostMode {
 			// for host mode container, we will not check src ip
 			if len(from.NatIP) == 0 {
 				// Supress the log, a host mode container in the exit state can trigger this forever
@@ -351,7 +418,7 @@ func (e *Engine) createWorkloadRule(from, to *share.CLUSWorkloadAddr, policy *sh
 			// For host mode container, the address will be set to NatIP even though the system
 			// can be a global ip system (such as k8s).  So add the rule from the host
 			// ip to global here as well.
-			if pInfo.HostMode {
+			if pInfo.HostMode || pInfo.HostNode {
 				for _, ipTo := range to.GlobalIP {
 					createIPRule(ipFrom, ipTo, nil, nil, to.LocalPortApp, action, pInfo, ctx)
 				}
@@ -386,8 +453,8 @@ func (e *Engine) createWorkloadRule(from, to *share.CLUSWorkloadAddr, policy *sh
 							createIPRule(ipFrom, net.ParseIP(addr.(string)), nil, nil, to.NatPortApp,
 								action, pInfo, ctx)
 						}
-						if pInfo.HostMode {
-							createIPRule(ipFrom, utils.IPv4Loopback, nil, nil, to.NatPortApp,
+						if pInfo.HostMode || pInfo.HostNode {
+							createIPRule(ipFrom, loopbackIP(ipFrom), nil, nil, to.NatPortApp,
 								action, pInfo, ctx)
 						}
 						//no need to continue the loop as all ip on this local host is already included above
@@ -400,14 +467,18 @@ func (e *Engine) createWorkloadRule(from, to *share.CLUSWorkloadAddr, policy *sh
 		var toIPList []net.IP
 		var toPortApp []share.CLUSPortApp
 
-		if pInfo.HostMode {
+		if pInfo.HostMode || pInfo.HostNode {
 			// for host mode container, we will not check dst ip
 			if len(to.NatIP) == 0 {
 				// Supress the log, a host mode container in the exit state can trigger this forever
 				// log.WithFields(log.Fields{"to": to.WlID}).Debug("Missing ip for host mode container!")
 				return
 			}
-			toIPList = to.NatIP[:1]
+			if pInfo.HostNode {
+				toIPList = to.NatIP
+			} else {
+				toIPList = to.NatIP[:1]
+			}
 			//(NVSHAS-4175) for host mode container we also need
 			//to include LocalPortApp to create ip rule. For host
 			//mode workload we do not check application but only
@@ -434,7 +505,7 @@ func (e *Engine) createWorkloadRule(from, to *share.CLUSWorkloadAddr, policy *sh
 				if from.WlID == share.CLUSWLAddressGroup  || from.WlID == share.CLUSHostAddrGroup {
 					for i := 0; i < len(from.NatIP); i += 2 {
 						createIPRule(from.NatIP[i], ipTo, from.NatIP[i+1], nil, toPortApp, action, pInfo, ctx)
-						if pInfo.HostMode && from.NatIP[i].Equal(utils.IPv4Loopback) {
+						if (pInfo.HostMode || pInfo.HostNode) && from.NatIP[i].Equal(utils.IPv4Loopback) {
 							// address group with loopback ip as member, we know it's the "nodes" group.
 							// Add 127.0.0.1 -> 127.0.0.1 rule
 							createIPRule(from.NatIP[i], utils.IPv4Loopback, nil, nil, toPortApp, action, pInfo, ctx)
@@ -469,7 +540,7 @@ func (e *Engine) createWorkloadRule(from, to *share.CLUSWorkloadAddr, policy *sh
 					// For host mode container, the address will be set to NatIP even though the system
 					// can be a global ip system (such as k8s). So add the rule from the global address to
 					// NatIP here as well.
-					if pInfo.HostMode {
+					if pInfo.HostMode || pInfo.HostNode {
 						for _, ipFrom := range from.GlobalIP {
 							createIPRule(ipFrom, ipTo, nil, nil, toPortApp, action, pInfo, ctx)
 						}
@@ -666,6 +737,9 @@ func getRelevantWorkload(addrs []*share.CLUSWorkloadAddr,
 				(polAppDir&C.DP_POLICY_APPLY_INGRESS > 0 && isto) {
 				for id, pInfo := range pMap {
 					mode := pInfo.Policy.Mode
+					if pInfo.HostNode {
+						continue
+					}
 					if strings.Contains(addr.PolicyMode, mode) {
 						wlList = append(wlList, &share.CLUSWorkloadAddr{WlID: id,
 							LocalPortApp: addr.LocalPortApp, NatPortApp: addr.NatPortApp})
@@ -675,6 +749,9 @@ func getRelevantWorkload(addrs []*share.CLUSWorkloadAddr,
 			}
 		} else if addr.WlID == share.CLUSWLAllContainer {
 			for id, pInfo := range pMap {
+				if pInfo.HostNode {
+					continue
+				}
 				wlAddr := share.CLUSWorkloadAddr{
 					WlID: id,
 					PolicyMode: pInfo.Policy.Mode,
@@ -685,6 +762,13 @@ func getRelevantWorkload(addrs []*share.CLUSWorkloadAddr,
 				wlList = append(wlList, &wlAddr)
 				pInfoList = append(pInfoList, pInfo)
 			}
+		} else if addr.WlID == share.CLUSHostAddrGroup {
+			// rules of "nodes" group are enforced on host processes
+			if pInfo, ok := pMap[share.CLUSHostNodeWL]; ok {
+				wlList = append(wlList, &share.CLUSWorkloadAddr{WlID: share.CLUSHostNodeWL,
+					LocalPortApp: addr.LocalPortApp, NatPortApp: addr.NatPortApp})
+				pInfoList = append(pInfoList, pInfo)
+			}
 		} else {
 			if pInfo, ok := pMap[addr.WlID]; ok {
 				wlList = append(wlList, addr)
@@ -704,6 +788,9 @@ func getWorkload(addrs []*share.CLUSWorkloadAddr,
 			if (polAppDir&C.DP_POLICY_APPLY_EGRESS > 0 && isto) ||
 				(polAppDir&C.DP_POLICY_APPLY_INGRESS > 0 && !isto) {
 				for id, wl := range wlMap {
+					if id == share.CLUSHostNodeWL {
+						continue
+					}
 					if strings.Contains(addr.PolicyMode, wl.PolicyMode) {
 						if addr.NatPortApp == nil  || len(addr.NatPortApp) <= 0 {//PAI
 							wlList = append(wlList, &share.CLUSWorkloadAddr{WlID: id,
@@ -719,6 +806,9 @@ func getWorkload(addrs []*share.CLUSWorkloadAddr,
 			if (polAppDir&C.DP_POLICY_APPLY_EGRESS > 0 && isto) ||
 				(polAppDir&C.DP_POLICY_APPLY_INGRESS > 0 && !isto) {
 				for id, wl := range wlMap {
+					if id == share.CLUSHostNodeWL {
+						continue
+					}
 					wlAddr := share.CLUSWorkloadAddr{
 						WlID: id,
 						PolicyMode: wl.PolicyMode,
@@ -753,7 +843,7 @@ func addPolicyAddrIPNet(subnets map[string]share.CLUSSubnet, ipnet *net.IPNet, s
 
 func addWlLocalAddrToPolicyAddrMap(from *share.CLUSWorkloadAddr, newPolicyAddrMap map[string]share.CLUSSubnet) {
 	for _, lip := range from.LocalIP {
-		lipnet := &net.IPNet{IP: lip, Mask: net.CIDRMask(32, 32)}
+		lipnet := &net.IPNet{IP: lip, Mask: utils.IPHostMask(lip)}
 		//log.WithFields(log.Fields{"ip": lipnet.IP.String(), "mask": lipnet.Mask.String()}).Debug("add local ip")
 		addPolicyAddrIPNet(newPolicyAddrMap, lipnet, share.CLUSIPAddrScopeLocalhost)
 	}
@@ -761,12 +851,27 @@ func addWlLocalAddrToPolicyAddrMap(from *share.CLUSWorkloadAddr, newPolicyAddrMa
 
 func addWlGlobalAddrToPolicyAddrMap(from *share.CLUSWorkloadAddr, newPolicyAddrMap map[string]share.CLUSSubnet) {
 	for _, gip := range from.GlobalIP {
-		gipnet := &net.IPNet{IP: gip, Mask: net.CIDRMask(32, 32)}
+		gipnet := &net.IPNet{IP: gip, Mask: utils.IPHostMask(gip)}
 		//log.WithFields(log.Fields{"ip": gipnet.IP.String(), "mask": gipnet.Mask.String()}).Debug("add global ip")
 		addPolicyAddrIPNet(newPolicyAddrMap, gipnet, share.CLUSIPAddrScopeGlobal)
 	}
 }
 
+// Host processes are addressed by all host IPs, fill them as the NAT addresses
+func (e *Engine) hostNodeAddr(from *share.CLUSWorkloadAddr, newPolicyAddrMap map[string]share.CLUSSubnet) *share.CLUSWorkloadAddr {
+	addr := &share.CLUSWorkloadAddr{WlID: from.WlID, PolicyMode: from.PolicyMode}
+	for _, ip := range e.HostIPs.ToSlice() {
+		if hip := net.ParseIP(ip.(string)); hip != nil {
+			addr.NatIP = append(addr.NatIP, hip)
+			if from.PolicyMode == share.PolicyModeEvaluate || from.PolicyMode == share.PolicyModeEnforce {
+				hipnet := &net.IPNet{IP: hip, Mask: utils.IPHostMask(hip)}
+				addPolicyAddrIPNet(newPolicyAddrMap, hipnet, share.CLUSIPAddrScopeLocalhost)
+			}
+		}
+	}
+	return addr
+}
+
 func (e *Engine) parseGroupIPPolicy(p []share.CLUSGroupIPPolicy, workloadPolicyMap map[string]*WorkloadIPPolicyInfo,
 	newPolicyAddrMap map[string]share.CLUSSubnet) {
 	addrMap := make(map[string]*share.CLUSWorkloadAddr)
@@ -774,6 +879,9 @@ func (e *Engine) parseGroupIPPolicy(p []share.CLUSGroupIPPolicy, workloadPolicyM
 		// The first rule is the default rule that contains all container
 		if i == 0 {
 			for _, from := range pp.From {
+				if from.WlID == share.CLUSHostNodeWL {
+					from = e.hostNodeAddr(from, newPolicyAddrMap)
+				}
 				addrMap[from.WlID] = from
 				//add wl global/nat address to policy address map
 				//these address will be pushed to DP
@@ -893,9 +1001,14 @@ func policyModeToDefaultAction(mode string, capIntcp bool) uint8 {
 }
 
 func ipMatch(ip, ipL, ipR net.IP, external bool) bool {
-	if external && bytes.Compare(ipL, net.IPv4zero) == 0 {
+	if external && ipL.IsUnspecified() {
 		return true
 	}
+	// compare in the same length as ipv4 address can be in either form
+	ip, ipL = ip.To16(), ipL.To16()
+	if ipR != nil {
+		ipR = ipR.To16()
+	}
 	s := bytes.Compare(ip, ipL)
 	if s == 0 {
 		return true
@@ -989,6 +1102,11 @@ func (e *Engine) UpdateNetworkPolicy(ps []share.CLUSGroupIPPolicy,
 
 	newPolicyAddrMap := make(map[string]share.CLUSSubnet)
 	e.parseGroupIPPolicy(ps, newPolicy, newPolicyAddrMap)
+	for _, pInfo := range newPolicy {
+		if pInfo.Configured && len(pInfo.QuarExceptions) > 0 {
+			quarantinePolicy(pInfo)
+		}
+	}
 
 	dpConnected := dp.Connected()
 
@@ -0,0 +1,139 @@
+package policy
+
+import (
+	"net"
+	"testing"
+
+	"github.com/neuvector/neuvector/agent/dp"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// C constants can't be used in tests
+const testApplyDirBoth int = 0x3 // DP_POLICY_APPLY_EGRESS | DP_POLICY_APPLY_INGRESS
+const testActionAllow uint8 = 2  // DP_POLICY_ACTION_ALLOW
+
+func findIPRule(pInfo *WorkloadIPPolicyInfo, id uint32, ingress bool, from, to string) *dp.DPPolicyIPRule {
+	for _, r := range pInfo.Policy.IPRules {
+		if r.ID == id && r.Ingress == ingress &&
+			r.SrcIP.Equal(net.ParseIP(from)) && r.DstIP.Equal(net.ParseIP(to)) {
+			return r
+		}
+	}
+	return nil
+}
+
+func countIPRules(pInfo *WorkloadIPPolicyInfo, id uint32) int {
+	var n int
+	for _, r := range pInfo.Policy.IPRules {
+		if r.ID == id {
+			n++
+		}
+	}
+	return n
+}
+
+func TestHostNodePolicy(t *testing.T) {
+	var e Engine
+	e.Init("h1", utils.NewSet("10.0.0.1", "fd00::1"), nil, nil, testApplyDirBoth)
+
+	hostInfo := &WorkloadIPPolicyInfo{
+		RuleMap:  make(map[string]*dp.DPPolicyIPRule),
+		Policy:   dp.DPWorkloadIPPolicy{WlID: share.CLUSHostNodeWL, ApplyDir: testApplyDirBoth},
+		HostNode: true,
+		CapIntcp: true,
+	}
+	wlInfo := &WorkloadIPPolicyInfo{
+		RuleMap:  make(map[string]*dp.DPPolicyIPRule),
+		Policy:   dp.DPWorkloadIPPolicy{WlID: "wl1", ApplyDir: testApplyDirBoth},
+		CapIntcp: true,
+	}
+	pMap := map[string]*WorkloadIPPolicyInfo{share.CLUSHostNodeWL: hostInfo, "wl1": wlInfo}
+
+	port80 := []share.CLUSPortApp{share.CLUSPortApp{Ports: "tcp/80"}}
+	port22 := []share.CLUSPortApp{share.CLUSPortApp{Ports: "tcp/22"}}
+	policy := []share.CLUSGroupIPPolicy{
+		share.CLUSGroupIPPolicy{
+			From: []*share.CLUSWorkloadAddr{
+				&share.CLUSWorkloadAddr{WlID: share.CLUSHostNodeWL, PolicyMode: share.PolicyModeEnforce},
+				&share.CLUSWorkloadAddr{
+					WlID: "wl1", PolicyMode: share.PolicyModeEnforce,
+					LocalIP:  []net.IP{net.ParseIP("172.17.0.2")},
+					GlobalIP: []net.IP{net.ParseIP("10.1.0.5")},
+				},
+				&share.CLUSWorkloadAddr{
+					WlID: "wl2", PolicyMode: share.PolicyModeEnforce,
+					GlobalIP: []net.IP{net.ParseIP("10.1.0.2"), net.ParseIP("fd01::2")},
+				},
+			},
+		},
+		// nodes -> wl2
+		share.CLUSGroupIPPolicy{
+			ID:     1001,
+			From:   []*share.CLUSWorkloadAddr{&share.CLUSWorkloadAddr{WlID: share.CLUSHostAddrGroup}},
+			To:     []*share.CLUSWorkloadAddr{&share.CLUSWorkloadAddr{WlID: "wl2", LocalPortApp: port80}},
+			Action: testActionAllow,
+		},
+		// containers -> wl2
+		share.CLUSGroupIPPolicy{
+			ID:     1002,
+			From:   []*share.CLUSWorkloadAddr{&share.CLUSWorkloadAddr{WlID: share.CLUSWLAllContainer}},
+			To:     []*share.CLUSWorkloadAddr{&share.CLUSWorkloadAddr{WlID: "wl2", LocalPortApp: port80}},
+			Action: testActionAllow,
+		},
+		// wl2 -> nodes
+		share.CLUSGroupIPPolicy{
+			ID:     1003,
+			From:   []*share.CLUSWorkloadAddr{&share.CLUSWorkloadAddr{WlID: "wl2"}},
+			To:     []*share.CLUSWorkloadAddr{&share.CLUSWorkloadAddr{WlID: share.CLUSHostAddrGroup, NatPortApp: port22}},
+			Action: testActionAllow,
+		},
+	}
+
+	addrMap := make(map[string]share.CLUSSubnet)
+	e.parseGroupIPPolicy(policy, pMap, addrMap)
+
+	if !hostInfo.Configured || hostInfo.Policy.Mode != share.PolicyModeEnforce {
+		t.Errorf("Host node policy is not configured: %+v\n", hostInfo.Policy)
+	}
+
+	// Host processes can use any host address, of both families
+	for _, r := range [][2]string{{"10.0.0.1", "10.1.0.2"}, {"fd00::1", "fd01::2"}} {
+		if rule := findIPRule(hostInfo, 1001, false, r[0], r[1]); rule == nil {
+			t.Errorf("Missing egress rule: %v -> %v\n", r[0], r[1])
+		} else if rule.Port != 80 || rule.Action != testActionAllow {
+			t.Errorf("Unexpected egress rule: %+v\n", rule)
+		}
+	}
+	if n := countIPRules(hostInfo, 1001); n != 2 {
+		t.Errorf("Unexpected egress rule count: %v\n", n)
+	}
+	if n := countIPRules(wlInfo, 1001); n != 0 {
+		t.Errorf("Rule of nodes should not apply to containers: %v\n", n)
+	}
+
+	// The containers group doesn't include host processes
+	if n := countIPRules(hostInfo, 1002); n != 0 {
+		t.Errorf("Rule of containers should not apply to host processes: %v\n", n)
+	}
+	if n := countIPRules(wlInfo, 1002); n == 0 {
+		t.Errorf("Missing container rule\n")
+	}
+
+	for _, r := range [][2]string{{"10.1.0.2", "10.0.0.1"}, {"fd01::2", "fd00::1"}} {
+		if rule := findIPRule(hostInfo, 1003, true, r[0], r[1]); rule == nil {
+			t.Errorf("Missing ingress rule: %v -> %v\n", r[0], r[1])
+		} else if rule.Port != 22 {
+			t.Errorf("Unexpected ingress rule: %+v\n", rule)
+		}
+	}
+
+	// Host addresses are pushed to dp so that host traffic is looked up in the policy
+	for _, ip := range []string{"10.0.0.1", "fd00::1"} {
+		hip := net.ParseIP(ip)
+		snet := utils.IPNet2Subnet(&net.IPNet{IP: hip, Mask: utils.IPHostMask(hip)})
+		if _, ok := addrMap[snet.String()]; !ok {
+			t.Errorf("Missing host address in policy address map: %v\n", ip)
+		}
+	}
+}
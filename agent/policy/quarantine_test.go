@@ -0,0 +1,50 @@
+package policy
+
+import (
+	"net"
+	"testing"
+
+	"github.com/neuvector/neuvector/agent/dp"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestQuarantinePolicy(t *testing.T) {
+	pInfo := &WorkloadIPPolicyInfo{
+		Policy: dp.DPWorkloadIPPolicy{
+			WlID:    "wl",
+			IPRules: []*dp.DPPolicyIPRule{&dp.DPPolicyIPRule{ID: 10001}},
+		},
+		QuarExceptions: []share.CLUSQuarException{
+			share.CLUSQuarException{Address: "10.1.2.0/24", Ports: "tcp/514"},
+			share.CLUSQuarException{Address: "192.168.1.10"},
+			share.CLUSQuarException{Address: "fd00::1"},
+			share.CLUSQuarException{Address: "bad-address"},
+		},
+	}
+	quarantinePolicy(pInfo)
+
+	if pInfo.Policy.DefAction != policyModeToDefaultAction(share.PolicyModeEnforce, true) {
+		t.Errorf("Unexpected default action: %v\n", pInfo.Policy.DefAction)
+	}
+	if len(pInfo.Policy.IPRules) != 6 {
+		t.Fatalf("Unexpected rule count: %v\n", len(pInfo.Policy.IPRules))
+	}
+
+	r := pInfo.Policy.IPRules[0]
+	if r.Ingress || !r.DstIP.Equal(net.ParseIP("10.1.2.0")) || !r.DstIPR.Equal(net.ParseIP("10.1.2.255")) ||
+		r.Port != 514 || r.PortR != 514 || r.IPProto != 6 {
+		t.Errorf("Unexpected egress rule: %+v\n", r)
+	}
+	r = pInfo.Policy.IPRules[1]
+	if !r.Ingress || !r.SrcIP.Equal(net.ParseIP("10.1.2.0")) || r.Port != 0 || r.PortR != 0xffff || r.IPProto != 0 {
+		t.Errorf("Exception ports should not apply to ingress: %+v\n", r)
+	}
+	r = pInfo.Policy.IPRules[3]
+	if !r.Ingress || !r.SrcIP.Equal(net.ParseIP("192.168.1.10")) || r.SrcIPR != nil || r.Port != 0 || r.PortR != 0xffff {
+		t.Errorf("Unexpected ingress rule: %+v\n", r)
+	}
+	r = pInfo.Policy.IPRules[4]
+	if r.SrcIP.To4() != nil || !r.DstIP.Equal(net.ParseIP("fd00::1")) {
+		t.Errorf("Unexpected ipv6 rule: %+v\n", r)
+	}
+}
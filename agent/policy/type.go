@@ -22,6 +22,9 @@ type WorkloadIPPolicyInfo struct {
 	SkipPush   bool
 	HostMode   bool
 	CapIntcp   bool
+	HostNode   bool // host processes, enforced when host network policy is enabled
+
+	QuarExceptions []share.CLUSQuarException // the workload is quarantined but still reachable from/to these addresses
 }
 
 type DlpBuildInfo struct {
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"crypto/md5"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/cluster"
+)
+
+// Profiles pushed by the controller in batches. The same profiles are written to the kv, so the values that
+// are applied are remembered and the kv notifications of the same values are skipped. A batch is applied
+// only if it follows the last one; when a batch is missed, the profiles are reloaded from the kv.
+var policyPushMutex sync.Mutex
+var policyPushEpoch, policyPushSeq uint64
+
+// Only accessed in task thread context
+var appliedProfiles map[string][md5.Size]byte = make(map[string][md5.Size]byte)
+
+type policyPushTask struct {
+	entries []*share.CLUSPolicyPushEntry
+}
+
+// This is run in task thread context
+func (p *policyPushTask) handler() {
+	for _, e := range p.entries {
+		if e.Delete {
+			applyProfileUpdate(cluster.ClusterNotifyDelete, e.Key, nil)
+		} else {
+			applyProfileUpdate(cluster.ClusterNotifyModify, e.Key, e.Value)
+		}
+	}
+}
+
+// Skip the profile if the same value is already applied. Return false if the profile is skipped.
+func applyProfileUpdate(nType cluster.ClusterNotifyType, key string, value []byte) bool {
+	if share.CLUSKey2Target(key)+"/" == share.CLUSNodeStore {
+		if nType == cluster.ClusterNotifyDelete {
+			delete(appliedProfiles, key)
+		} else {
+			sum := md5.Sum(value)
+			if last, ok := appliedProfiles[key]; ok && last == sum {
+				return false
+			}
+			appliedProfiles[key] = sum
+		}
+	}
+	systemUpdateProc(nType, key, value)
+	return true
+}
+
+func isPolicyPushInSequence(epoch, seq uint64) bool {
+	if epoch == policyPushEpoch {
+		return seq == policyPushSeq+1
+	}
+	// a new sequence, started by a new lead or after resync
+	return seq == 1
+}
+
+func reloadNodeProfiles() {
+	store := share.CLUSNodeProfileStoreKey(Host.ID)
+	keys, err := cluster.GetStoreKeys(store)
+	if err != nil {
+		log.WithFields(log.Fields{"store": store, "error": err}).Error("Failed to read profiles")
+		return
+	}
+	for _, key := range keys {
+		if value, err := cluster.Get(key); err == nil {
+			systemUpdateHandler(cluster.ClusterNotifyModify, key, value, 0)
+		}
+	}
+}
+
+func (rs *RPCService) PushPolicyBatch(ctx context.Context, batch *share.CLUSPolicyPushBatch) (*share.CLUSPolicyPushAck, error) {
+	policyPushMutex.Lock()
+	defer policyPushMutex.Unlock()
+
+	if !isPolicyPushInSequence(batch.Epoch, batch.Seq) {
+		log.WithFields(log.Fields{
+			"epoch": batch.Epoch, "seq": batch.Seq, "last": policyPushSeq,
+		}).Info("Policy push out of sequence, resync")
+		ack := &share.CLUSPolicyPushAck{Seq: policyPushSeq, Resync: true}
+		policyPushEpoch, policyPushSeq = 0, 0
+		go reloadNodeProfiles()
+		return ack, nil
+	}
+
+	entries, err := cluster.DecodePolicyPushEntries(batch)
+	if err != nil {
+		log.WithFields(log.Fields{"seq": batch.Seq, "error": err}).Error("Failed to decode policy push")
+		ack := &share.CLUSPolicyPushAck{Seq: policyPushSeq, Resync: true}
+		policyPushEpoch, policyPushSeq = 0, 0
+		go reloadNodeProfiles()
+		return ack, nil
+	}
+
+	log.WithFields(log.Fields{"ctrl": batch.CtrlID, "seq": batch.Seq, "count": len(entries)}).Debug()
+
+	task := ContainerTask{task: TASK_CONFIG_SYSTEM, taskData: &policyPushTask{entries: entries}}
+	ContainerTaskChan <- &task
+	policyPushEpoch, policyPushSeq = batch.Epoch, batch.Seq
+	return &share.CLUSPolicyPushAck{Seq: batch.Seq}, nil
+}
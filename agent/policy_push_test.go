@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestPolicyPushSequence(t *testing.T) {
+	policyPushEpoch, policyPushSeq = 0, 0
+	defer func() { policyPushEpoch, policyPushSeq = 0, 0 }()
+
+	if isPolicyPushInSequence(100, 2) {
+		t.Errorf("New sequence should start from 1")
+	}
+	if !isPolicyPushInSequence(100, 1) {
+		t.Errorf("New sequence should be accepted")
+	}
+	policyPushEpoch, policyPushSeq = 100, 1
+	if !isPolicyPushInSequence(100, 2) {
+		t.Errorf("Next batch should be accepted")
+	}
+	if isPolicyPushInSequence(100, 3) || isPolicyPushInSequence(100, 1) {
+		t.Errorf("Missed or repeated batch should not be accepted")
+	}
+	if !isPolicyPushInSequence(200, 1) {
+		t.Errorf("Sequence of a new lead should be accepted")
+	}
+}
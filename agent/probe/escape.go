@@ -0,0 +1,133 @@
+package probe
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/global"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// MITRE ATT&CK technique IDs
+const (
+	mitreEscapeToHost      = "T1611" // Escape to Host
+	mitreExploitPrivEscape = "T1068" // Exploitation for Privilege Escalation
+)
+
+type escapeTechnique struct {
+	name  string
+	mitre []string
+}
+
+var (
+	escapeCorePattern  = &escapeTechnique{name: "core_pattern overwrite", mitre: []string{mitreEscapeToHost}}
+	escapeReleaseAgent = &escapeTechnique{name: "cgroup release_agent abuse", mitre: []string{mitreEscapeToHost}}
+	escapeRuntimeExe   = &escapeTechnique{name: "runtime binary overwrite", mitre: []string{mitreEscapeToHost, mitreExploitPrivEscape}}
+	escapeDirtyPipe    = &escapeTechnique{name: "dirty pipe file overwrite", mitre: []string{mitreExploitPrivEscape}}
+)
+
+// shell redirections or tee into the sensitive kernel and cgroup files
+var escapeCmdRegex *regexp.Regexp = regexp.MustCompile(`(>|\btee\b[^|;&]*)\s*\S*(/proc/sys/kernel/core_pattern|/release_agent|/notify_on_release|/proc/(self|[0-9]+)/exe)\b`)
+
+// the overwritten targets from a page cache exploit to gain the host or root access
+var dirtyPipeTargets utils.Set = utils.NewSet("/etc/passwd", "/etc/shadow", "/etc/sudoers", "/bin/su", "/usr/bin/su", "/usr/bin/sudo")
+
+func escapeTechniqueByPath(path string) *escapeTechnique {
+	switch {
+	case path == "/proc/sys/kernel/core_pattern":
+		return escapeCorePattern
+	case strings.HasSuffix(path, "/release_agent") || strings.HasSuffix(path, "/notify_on_release"):
+		return escapeReleaseAgent
+	case strings.HasPrefix(path, "/proc/") && strings.HasSuffix(path, "/exe"):
+		return escapeRuntimeExe
+	}
+	return nil
+}
+
+// returns the technique and its evidence from the command line
+func escapeTechniqueByCmds(cmds []string) (*escapeTechnique, string) {
+	cmdline := strings.Join(cmds, " ")
+	if m := escapeCmdRegex.FindStringSubmatch(cmdline); m != nil {
+		return escapeTechniqueByPath(m[2]), m[2]
+	}
+
+	if len(cmds) > 0 && filepath.Base(cmds[0]) == "sysctl" {
+		for _, cmd := range cmds[1:] {
+			if strings.HasPrefix(cmd, "kernel.core_pattern=") {
+				return escapeCorePattern, "kernel.core_pattern"
+			}
+		}
+	}
+	return nil, ""
+}
+
+// returns the technique and its evidence from the opened files. The fds are shared with the kernel load check of
+// the same inspection, and the open flags are only read for the sensitive files.
+func escapeTechniqueByFiles(snap *procSnapshot) (*escapeTechnique, string) {
+	fds := snap.getFds()
+	if len(fds) == 0 {
+		return nil, ""
+	}
+
+	spliced := snap.getSyscall() == "splice"
+	for fd, path := range fds {
+		if !filepath.IsAbs(path) {
+			continue // sockets, pipes and anonymous inodes
+		}
+
+		// the exploit only requires a read-only file descriptor on the target
+		if spliced && dirtyPipeTargets.Contains(path) {
+			return escapeDirtyPipe, path
+		}
+
+		tech := escapeTechniqueByPath(path)
+		if tech == escapeRuntimeExe {
+			tech = nil // an opened "/proc/<pid>/exe" links to the binary, it is matched by the runtime name below
+		}
+		if tech == nil && global.RT.IsRuntimeProcess(filepath.Base(path), nil) {
+			tech = escapeRuntimeExe
+		}
+		if tech == nil {
+			continue
+		}
+
+		if flags, ok := snap.getFdFlags(fd); ok && (flags&syscall.O_ACCMODE) != syscall.O_RDONLY {
+			return tech, path
+		}
+	}
+	return nil, ""
+}
+
+// it is only applied to the containers, the technique is reported regardless of the process profile
+func (p *Probe) checkEscapeAttempt(id string, proc *procInternal, snap *procSnapshot) {
+	if id == "" || (proc.reported&escapeReported) != 0 || p.isAgentProcess(proc.sid, id) {
+		return
+	}
+
+	tech, evidence := escapeTechniqueByCmds(proc.cmds)
+	if tech == nil {
+		if tech, evidence = escapeTechniqueByFiles(snap); tech == nil {
+			return
+		}
+	}
+
+	proc.reported |= escapeReported
+	go func() {
+		p.lockProcMux()
+		proc.user = p.getUpdatedUsername(proc.pid, proc.euid)
+		p.unlockProcMux()
+
+		msg := fmt.Sprintf("Container escape attempt: %s on %s [%s]", tech.name, evidence, strings.Join(tech.mitre, ","))
+		s := p.makeProcessReport(id, proc, msg, nil, false, "", share.CLUSReservedUuidEscape)
+		s.Mitre = tech.mitre
+		rpt := ProbeMessage{Type: PROBE_REPORT_ESCAPE, Process: s, ContainerIDs: utils.NewSet(id)}
+		p.SendAggregateProbeReport(&rpt, false)
+		log.WithFields(log.Fields{"name": proc.name, "pid": proc.pid, "technique": tech.name, "evidence": evidence}).Info("Report escape attempt")
+	}()
+}
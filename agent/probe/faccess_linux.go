@@ -725,6 +725,10 @@ func (fa *FileAccessCtrl) processEvent(ev *fsmon.EventMetadata) bool {
 					if rule_uuid = fa.checkAllowedShieldProcess(id, name, path, svcGroup, ppid, res); rule_uuid == "" {
 						res = rule_allowed_zdrift
 						log.WithFields(log.Fields{"id": id, "rule_uuid": rule_uuid}).Debug("SHD: allowed")
+					} else if bException, bReportOnly := fa.prober.lookupZeroDrift(svcGroup, name, path); bException || bReportOnly {
+						// the drift of the report-only group is reported by the process monitor
+						res = rule_allowed_zdrift
+						log.WithFields(log.Fields{"id": id, "path": path, "exception": bException}).Debug("SHD: allowed")
 					} else {
 						res = rule_not_defined // reject it
 					}
@@ -77,6 +77,8 @@ func generateReportKey(pmsg *ProbeMessage) (int, string, string) {
 		return pmsg.Process.Pid, genProcessReportKey(pmsg.Type, pmsg.Process), genUniqEventKey(pmsg.Type, pmsg.Process.Pid, pmsg.Process.ID)
 	case PROBE_REPORT_PROCESS_DENIED:
 		return pmsg.Process.Pid, genProcessReportKey(pmsg.Type, pmsg.Process), genUniqEventKey(pmsg.Type, pmsg.Process.Pid, pmsg.Process.ID)
+	case PROBE_REPORT_KERNEL_LOAD, PROBE_REPORT_KERNEL_LOAD_DENIED, PROBE_REPORT_ESCAPE:
+		return pmsg.Process.Pid, genProcessReportKey(pmsg.Type, pmsg.Process), genUniqEventKey(pmsg.Type, pmsg.Process.Pid, pmsg.Process.ID)
 	}
 
 	log.WithFields(log.Fields{"msg": pmsg}).Error("PROC: unknown report type")
@@ -0,0 +1,117 @@
+package probe
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+const (
+	kernelLoadModule = "kernel module"
+	kernelLoadBpf    = "eBPF program"
+)
+
+var kernelModuleLoaders utils.Set = utils.NewSet("insmod", "modprobe")
+
+var kernelLoadSyscalls map[string]string = map[string]string{
+	"init_module":  kernelLoadModule,
+	"finit_module": kernelLoadModule,
+	"bpf":          kernelLoadBpf,
+}
+
+// host daemons which load the kernel modules on behalf of the kernel and devices
+var hostKernelLoaders utils.Set = utils.NewSet("systemd-udevd", "systemd-modules-load", "kthreadd")
+
+func isModuleRemoval(cmds []string) bool {
+	for i, cmd := range cmds {
+		if i == 0 {
+			continue
+		}
+		if cmd == "-r" || cmd == "--remove" || cmd == "-n" || cmd == "--dry-run" || cmd == "--show-depends" {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the load type and its evidence
+func kernelLoadType(proc *procInternal, snap *procSnapshot) (string, string) {
+	if kernelModuleLoaders.Contains(proc.name) && !isModuleRemoval(proc.cmds) {
+		return kernelLoadModule, proc.name
+	}
+
+	if name := snap.getSyscall(); name != "" {
+		if kind, ok := kernelLoadSyscalls[name]; ok {
+			return kind, name + "()"
+		}
+	}
+
+	// eBPF programs are held by the loader as "anon_inode:bpf-prog" file descriptors
+	for _, fl := range snap.getFds() {
+		if strings.HasPrefix(fl, "anon_inode:bpf-prog") {
+			return kernelLoadBpf, fl
+		}
+	}
+	return "", ""
+}
+
+// it is unexpected when the loader is not allowed by the process profile of its group.
+// This is a detection, not a prevention: the process is sampled after it starts, so the module or the eBPF program can
+// be loaded already when it is reported. The deny action only kills the loader, it does not unload anything.
+func (p *Probe) checkKernelLoad(id string, proc *procInternal, snap *procSnapshot) {
+	if (proc.reported&kernelLoadReported) != 0 || p.isAgentProcess(proc.sid, id) {
+		return
+	}
+
+	kind, evidence := kernelLoadType(proc, snap)
+	if kind == "" {
+		return
+	}
+
+	if id == "" && (proc.ppid == 2 || hostKernelLoaders.Contains(proc.pname)) { // requested by the kernel
+		proc.reported |= kernelLoadReported
+		return
+	}
+
+	pp := &share.CLUSProcessProfileEntry{
+		Name: proc.name,
+		User: proc.user,
+		Uid:  int32(proc.euid),
+		Path: proc.path,
+	}
+	_, _, derivedGroup, _, _, err := p.procPolicyLookupFunc(id, "", proc.pname, proc.ppath, proc.pid, proc.pgid, 0, pp)
+	if err != nil {
+		return // policy is not ready, try it at next inspection
+	}
+
+	proc.reported |= kernelLoadReported
+	if pp.Action == share.PolicyActionAllow {
+		mLog.WithFields(log.Fields{"name": proc.name, "pid": proc.pid, "type": kind}).Debug("PROC: allowed kernel load")
+		return
+	}
+
+	bDenied := id != "" && pp.Action == share.PolicyActionDeny
+	if bDenied {
+		p.killProcess(proc.pid)
+	}
+
+	go func() {
+		p.lockProcMux()
+		proc.user = p.getUpdatedUsername(proc.pid, proc.euid)
+		p.unlockProcMux()
+
+		msg := fmt.Sprintf("Kernel load detected: %s by %s", kind, evidence)
+		s := p.makeProcessReport(id, proc, msg, nil, false, derivedGroup, share.CLUSReservedUuidKernelLoad)
+		rpt := ProbeMessage{Type: PROBE_REPORT_KERNEL_LOAD, Process: s, ContainerIDs: utils.NewSet(id)}
+		if bDenied {
+			rpt.Type = PROBE_REPORT_KERNEL_LOAD_DENIED
+			s.Msg += ": process killed"
+		}
+		p.SendAggregateProbeReport(&rpt, false)
+		log.WithFields(log.Fields{"name": proc.name, "pid": proc.pid, "type": kind, "denied": bDenied}).Info("Report kernel load")
+	}()
+}
@@ -45,6 +45,7 @@ type Probe struct {
 	policyLookupFunc     func(conn *dp.Connection) (uint32, uint8, bool)
 	procPolicyLookupFunc func(id, riskType, pname, ppath string, pid, pgid, shellCmd int, proc *share.CLUSProcessProfileEntry) (string, string, string, string, bool, error)
 	bK8sGroupWithProbe   func(svcGroup string) bool
+	zeroDriftLookup      func(svcGroup, name, path string) (bool, bool)
 	reportLearnProc      func(svcGroup string, proc *share.CLUSProcessProfileEntry)
 	disableNvProtect     bool
 	bKubePlatform        bool
@@ -102,6 +103,10 @@ type Probe struct {
 	msgAggregatesMux    sync.Mutex
 	fsnCtr              *FileNotificationCtr // anchor profile helper
 	exitProcSlices      []*procDelayExit
+
+	// helper: sampled system calls per service group (discover mode)
+	syscallMux    sync.Mutex
+	syscallGroups map[string]utils.Set
 }
 
 func (p *Probe) cbOpenNetlinkSockets(param interface{}) {
@@ -464,10 +469,12 @@ func New(pc *ProbeConfig) (*Probe, error) {
 		dpTaskCallback:       pc.DpTaskCallback,
 		notifyTaskChan:       pc.NotifyTaskChan,
 		notifyFsTaskChan:     pc.NotifyFsTaskChan,
-		pidNetlink:           pc.PidMode == "host",
+		pidNetlink:           pc.PidMode == "host" && !pc.Restricted,
+		monitorConnection:    pc.Restricted,
 		policyLookupFunc:     pc.PolicyLookupFunc,
 		procPolicyLookupFunc: pc.ProcPolicyLookupFunc,
 		bK8sGroupWithProbe:   pc.IsK8sGroupWithProbe,
+		zeroDriftLookup:      pc.ZeroDriftLookup,
 		reportLearnProc:      pc.ReportLearnProc,
 		containerInContainer: pc.ContainerInContainer,
 		getContainerPid:      pc.GetContainerPid,
@@ -496,6 +503,7 @@ func New(pc *ProbeConfig) (*Probe, error) {
 		chanEvalAppPid: make(chan int, 2048),
 		procHistoryMap: make(map[string]*ringbuffer.RingBuffer),
 		pMsgAggregates: make(map[string]*probeMsgAggregate),
+		syscallGroups:  make(map[string]utils.Set),
 	}
 
 	// for process
@@ -511,7 +519,12 @@ func New(pc *ProbeConfig) (*Probe, error) {
 	}
 
 	// p.pidNetlink = false // for test scan mode
-	if err := global.SYS.CallNetNamespaceFunc(1, p.cbOpenNetlinkSockets, nil); err != nil {
+	if pc.Restricted {
+		// restricted mode: no capability to switch the network namespace, the processes are scanned and
+		// the connections are collected from the sockets of the enforcer's own network namespace.
+		log.Info("PROC: restricted mode")
+		p.cbOpenNetlinkSockets(nil)
+	} else if err := global.SYS.CallNetNamespaceFunc(1, p.cbOpenNetlinkSockets, nil); err != nil {
 		return nil, err
 	}
 
@@ -519,7 +532,7 @@ func New(pc *ProbeConfig) (*Probe, error) {
 	bAufsDriver := global.RT.GetStorageDriver() == "aufs"
 	if bAufsDriver {
 		log.WithFields(log.Fields{"runtime": global.RT.String(), "storage driver": global.RT.GetStorageDriver()}).Info("PROC: ")
-	} else if p.bProfileEnable {
+	} else if p.bProfileEnable && !pc.Restricted {
 		var ok bool
 		if p.fAccessCtl, ok = NewFileAccessCtrl(p); !ok {
 			log.Info("PROC: Process control is not supported")
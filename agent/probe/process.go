@@ -29,6 +29,8 @@ const (
 	dnsTunnelReported
 	profileReported
 	historyReported
+	kernelLoadReported
+	escapeReported
 )
 
 type procEventHdr struct {
@@ -109,6 +111,17 @@ var kubeProcs map[string]int = map[string]int{
 	"kubelet":        1,
 	"kube-apiserver": 1,
 	"hyperkube":      1,
+	"k3s-server":     1,
+	"k3s-agent":      1,
+	"kubelite":       1,
+}
+
+// k3s and microk8s run the kubernetes components in one process. The apiserver and kubelet go last
+// because they decide the master and worker benchmarks.
+var embeddedKubeCmds map[string][]string = map[string][]string{
+	"k3s-server": {"kube-controller-manager", "kube-scheduler", "kube-proxy", "kube-apiserver", "kubelet"},
+	"k3s-agent":  {"kube-proxy", "kubelet"},
+	"kubelite":   {"kube-controller-manager", "kube-scheduler", "kube-proxy", "kube-apiserver", "kubelet"},
 }
 
 //var linuxShells utils.Set = utils.NewSet("sh", "dash", "bash", "rbash")
@@ -1558,6 +1571,11 @@ func (p *Probe) informKubeBench(proc *procInternal) bool {
 				return true
 			}
 		}
+	} else if cmds, ok := embeddedKubeCmds[proc.name]; ok {
+		for _, cmd := range cmds {
+			p.rerunKubeBench(cmd, proc.name)
+		}
+		return true
 	} else {
 		p.rerunKubeBench(proc.name, proc.name)
 		return true
@@ -1979,6 +1997,46 @@ func (p *Probe) checkReversedShellProcess(id string, proc *procInternal) bool {
 	return false
 }
 
+// The /proc states of a process shared by the checks of one inspection. Each of them is read once when it is first used.
+type procSnapshot struct {
+	pid      int
+	syscall  string            // the system call in progress, empty when it is not in a system call
+	fds      map[string]string // fd number => link target
+	flags    map[string]int    // fd number => open flags, only read for the fds in question
+	bSyscall bool
+	bFds     bool
+}
+
+func (s *procSnapshot) getSyscall() string {
+	if !s.bSyscall {
+		s.bSyscall = true
+		s.syscall, _ = osutil.GetProcessSyscall(s.pid)
+	}
+	return s.syscall
+}
+
+func (s *procSnapshot) getFds() map[string]string {
+	if !s.bFds {
+		s.bFds = true
+		s.fds = osutil.GetProcessFds(s.pid)
+	}
+	return s.fds
+}
+
+func (s *procSnapshot) getFdFlags(fd string) (int, bool) {
+	if flags, ok := s.flags[fd]; ok {
+		return flags, true
+	}
+	flags, ok := osutil.GetProcessFdFlags(s.pid, fd)
+	if ok {
+		if s.flags == nil {
+			s.flags = make(map[string]int)
+		}
+		s.flags[fd] = flags
+	}
+	return flags, ok
+}
+
 func (p *Probe) inspectNewProcesses(bInit bool) {
 	p.lockProcMux()
 	defer p.unlockProcMux()
@@ -2018,6 +2076,9 @@ func (p *Probe) inspectNewProcesses(bInit bool) {
 				proc.user = p.getUserName(proc.pid, proc.euid)
 			}
 
+			snap := &procSnapshot{pid: proc.pid}
+			p.checkKernelLoad(c.id, proc, snap)
+			p.checkEscapeAttempt(c.id, proc, snap)
 			if p.checkReversedShellProcess(c.id, proc) {
 				p.inspectProcess.Remove(itr) // either reported or expired
 			}
@@ -2470,6 +2531,12 @@ func (p *Probe) procProfileEval(id string, proc *procInternal, bKeepAlive bool)
 		Path:   proc.path,
 		Action: proc.action, // following the previous decision
 	}
+	if len(proc.cmds) > 1 {
+		pp.Args = strings.Join(proc.cmds[1:], " ")
+	}
+	if id == "" {
+		pp.Unit = osutil.GetProcessSystemdUnit(proc.pid)
+	}
 
 	nShellCmd := p.isShellScript(id, proc)
 	mode, setting, derivedGroup, svcGroup, allowSuspicious, err := p.procPolicyLookupFunc(id, proc.riskType, proc.pname, proc.ppath, proc.pid, proc.pgid, nShellCmd, pp)
@@ -2479,6 +2546,10 @@ func (p *Probe) procProfileEval(id string, proc *procInternal, bKeepAlive bool)
 		return share.PolicyActionAllow, false // assuming it is allowed so far
 	}
 
+	if mode == share.PolicyModeLearn {
+		p.sampleProcessSyscall(svcGroup, proc.pid, pp)
+	}
+
 	if !allowSuspicious { // user does not open the door
 		// suspicious children are suspicious. overwrite the action based on policy mode
 		if proc.riskyChild && pp.Action == share.PolicyActionAllow {
@@ -2501,7 +2572,19 @@ func (p *Probe) procProfileEval(id string, proc *procInternal, bKeepAlive bool)
 	if (proc.reported & profileReported) == 0  || mode == share.PolicyModeEnforce{
 		bZeroDrift := setting == share.ProfileZeroDrift
 		if bZeroDrift {
-			if pass := p.IsAllowedShieldProcess(id, mode, svcGroup, proc, pp, true); pass {
+			action, uuid := pp.Action, pp.Uuid
+			pass := p.IsAllowedShieldProcess(id, mode, svcGroup, proc, pp, true)
+			var bException, bReportOnly bool
+			if !pass {
+				bException, bReportOnly = p.lookupZeroDrift(svcGroup, proc.name, proc.path)
+			}
+			if bException {
+				// an exception of the group, like a package manager, is taken as a process from the image
+				mLog.WithFields(log.Fields{"name": proc.name, "path": proc.path, "svcGroup": svcGroup}).Debug("SHD: exception")
+				pp.Action, pp.Uuid = action, uuid
+				pass = true
+			}
+			if pass {
 				switch pp.Action {
 				case share.PolicyActionLearn, share.PolicyActionCheckApp:	// exclude these two actions
 				default:
@@ -2526,6 +2609,9 @@ func (p *Probe) procProfileEval(id string, proc *procInternal, bKeepAlive bool)
 				// Otherwise, the bug was that we would see
 				//"violation" incidents but the processes would continue to run
 				bKeepAlive = false
+				if bReportOnly && pp.Action == share.PolicyActionDeny && isZeroDriftUuid(pp.Uuid) {
+					pp.Action = share.PolicyActionViolate // report the drift only
+				}
 			}
 		}
 
@@ -2560,12 +2646,38 @@ func (p *Probe) procProfileEval(id string, proc *procInternal, bKeepAlive bool)
 	// multiple learn process event are okay because they are merged at controllers.
 	if pp.Action == share.PolicyActionLearn {
 		p.reportLearnProc(svcGroup, pp)
+	} else if len(pp.Syscalls) > 0 { // not a rule, system calls only
+		p.reportLearnProc(svcGroup, &share.CLUSProcessProfileEntry{Name: pp.Name, Path: pp.Path, Syscalls: pp.Syscalls})
 	}
 
 	mLog.WithFields(log.Fields{"name": proc.name, "pid": proc.pid, "action": pp.Action, "riskType": proc.riskType, "svcGroup": svcGroup}).Debug("PROC:")
 	return pp.Action, true
 }
 
+// sample the system call of a process, a new one for the group is attached to the process report
+func (p *Probe) sampleProcessSyscall(svcGroup string, pid int, pp *share.CLUSProcessProfileEntry) {
+	if name, ok := osutil.GetProcessSyscall(pid); ok && p.addGroupSyscall(svcGroup, name) {
+		pp.Syscalls = []string{name}
+	}
+}
+
+// record a sampled system call of the group, return true if it is not seen before
+func (p *Probe) addGroupSyscall(svcGroup, name string) bool {
+	p.syscallMux.Lock()
+	defer p.syscallMux.Unlock()
+
+	calls, ok := p.syscallGroups[svcGroup]
+	if !ok {
+		calls = utils.NewSet()
+		p.syscallGroups[svcGroup] = calls
+	}
+	if calls.Contains(name) {
+		return false
+	}
+	calls.Add(name)
+	return true
+}
+
 func (p *Probe) sendProcessIncident(bDenied bool, id, uuid, group, derivedGroup string, proc *procInternal) {
 	var s *ProbeProcess
 
@@ -2579,7 +2691,13 @@ func (p *Probe) sendProcessIncident(bDenied bool, id, uuid, group, derivedGroup
 	case share.CLUSReservedUuidShieldMode:	// zero-drift incident
 		s = p.makeProcessReport(id, proc, "Process profile violation, not from its root process", nil, false, group, uuid)
 	default: // rules-based incident
-		s = p.makeProcessReport(id, proc, "Process profile violation", nil, false, derivedGroup, uuid)
+		msg := "Process profile violation"
+		if id == "" { // host service
+			if unit := osutil.GetProcessSystemdUnit(proc.pid); unit != "" {
+				msg = fmt.Sprintf("%s, service %s", msg, unit)
+			}
+		}
+		s = p.makeProcessReport(id, proc, msg, nil, false, derivedGroup, uuid)
 	}
 
 	incidentType := PROBE_REPORT_PROCESS_VIOLATION
@@ -2974,6 +3092,23 @@ func isFamilyProcess(family utils.Set, proc *procInternal) bool {
 	return family.Contains(proc.pid) || family.Contains(proc.ppid) || family.Contains(proc.pgid) || family.Contains(proc.sid)
 }
 
+// the drift causes, not a deny rule of the user
+func isZeroDriftUuid(uuid string) bool {
+	switch uuid {
+	case share.CLUSReservedUuidAnchorMode, share.CLUSReservedUuidShieldMode, share.CLUSReservedUuidNotAlllowed:
+		return true
+	}
+	return false
+}
+
+// Return if the process is a zero-drift exception of the group, and if the drift is reported only
+func (p *Probe) lookupZeroDrift(svcGroup, name, path string) (bool, bool) {
+	if p.zeroDriftLookup == nil {
+		return false, false
+	}
+	return p.zeroDriftLookup(svcGroup, name, path)
+}
+
 func negativeResByMode(mode string) string {
 	if mode == share.PolicyModeEnforce {
 		return share.PolicyActionDeny
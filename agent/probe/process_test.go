@@ -1,12 +1,20 @@
 package probe
 
 import (
+	"errors"
 	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/neuvector/neuvector/share"
 	"github.com/neuvector/neuvector/share/container"
 	"github.com/neuvector/neuvector/share/global"
+	"github.com/neuvector/neuvector/share/osutil"
+	"github.com/neuvector/neuvector/share/system"
 	"github.com/neuvector/neuvector/share/utils"
 )
 
@@ -232,3 +240,319 @@ func TestAzureCniCmd(t *testing.T) {
 		}
 	}
 }
+
+func TestKernelModuleLoader(t *testing.T) {
+	loads := []*procInternal{
+		&procInternal{name: "insmod", cmds: []string{"insmod", "/tmp/rootkit.ko"}},
+		&procInternal{name: "modprobe", cmds: []string{"modprobe", "ip_vs"}},
+		&procInternal{name: "modprobe", cmds: []string{"modprobe", "-v", "br_netfilter"}},
+	}
+	for i, proc := range loads {
+		if kind, _ := kernelLoadType(proc, &procSnapshot{bSyscall: true, bFds: true}); kind != kernelLoadModule {
+			t.Errorf("Error[%v]: positive: %v\n", i, proc.cmds)
+		}
+	}
+
+	removals := [][]string{
+		[]string{"modprobe", "-r", "ip_vs"},
+		[]string{"modprobe", "--remove", "ip_vs"},
+		[]string{"modprobe", "-n", "ip_vs"},
+	}
+	for i, cmds := range removals {
+		if !isModuleRemoval(cmds) {
+			t.Errorf("Error[%v]: negative: %v\n", i, cmds)
+		}
+	}
+}
+
+func TestKernelLoadType(t *testing.T) {
+	tests := []struct {
+		proc     *procInternal
+		syscall  string
+		fds      map[string]string
+		kind     string
+		evidence string
+	}{
+		{&procInternal{name: "loader"}, "finit_module", nil, kernelLoadModule, "finit_module()"},
+		{&procInternal{name: "loader"}, "init_module", nil, kernelLoadModule, "init_module()"},
+		{&procInternal{name: "tc"}, "bpf", nil, kernelLoadBpf, "bpf()"},
+		{&procInternal{name: "agent"}, "epoll_wait", map[string]string{"0": "/dev/null", "5": "anon_inode:bpf-prog"}, kernelLoadBpf, "anon_inode:bpf-prog"},
+		{&procInternal{name: "modprobe", cmds: []string{"modprobe", "-r", "ip_vs"}}, "delete_module", nil, "", ""},
+		{&procInternal{name: "nginx"}, "accept4", map[string]string{"3": "socket:[1234]", "4": "anon_inode:bpf-map"}, "", ""},
+		{&procInternal{name: "nginx"}, "", nil, "", ""},
+	}
+
+	for i, test := range tests {
+		snap := &procSnapshot{syscall: test.syscall, fds: test.fds, bSyscall: true, bFds: true}
+		if kind, evidence := kernelLoadType(test.proc, snap); kind != test.kind || evidence != test.evidence {
+			t.Errorf("Error[%v]: unexpected load: %v, %v\n", i, kind, evidence)
+		}
+	}
+}
+
+func TestCheckKernelLoad(t *testing.T) {
+	var lookups int
+	var action string
+	p := &Probe{
+		selfID:         "agent",
+		agentSessionID: 1,
+		notifyTaskChan: make(chan *ProbeMessage, 8),
+		pMsgAggregates: make(map[string]*probeMsgAggregate),
+		procPolicyLookupFunc: func(id, riskType, pname, ppath string, pid, pgid, shellCmd int, proc *share.CLUSProcessProfileEntry) (string, string, string, string, bool, error) {
+			lookups++
+			if action == "" {
+				return "", "", "", "", false, errors.New("not ready")
+			}
+			proc.Action = action
+			return "", "", "nv.app", "", false, nil
+		},
+	}
+	loadSnap := &procSnapshot{syscall: "finit_module", bSyscall: true, bFds: true}
+
+	// policy is not ready, try it again at next inspection
+	proc := &procInternal{name: "loader", pid: 0x3ffffff0, sid: 100}
+	p.checkKernelLoad("c1", proc, loadSnap)
+	if lookups != 1 || (proc.reported&kernelLoadReported) != 0 {
+		t.Errorf("Unexpected check without policy: lookups=%v reported=%v\n", lookups, proc.reported)
+	}
+
+	// allowed by the process profile
+	action = share.PolicyActionAllow
+	p.checkKernelLoad("c1", proc, loadSnap)
+	if (proc.reported&kernelLoadReported) == 0 || len(p.notifyTaskChan) != 0 {
+		t.Errorf("Allowed load should not be reported\n")
+	}
+
+	// checked only once
+	p.checkKernelLoad("c1", proc, loadSnap)
+	if lookups != 2 {
+		t.Errorf("Reported process should not be checked again: lookups=%v\n", lookups)
+	}
+
+	// the agent's own processes and the loads requested by the kernel are skipped
+	lookups = 0
+	p.checkKernelLoad("agent", &procInternal{name: "loader", sid: 100}, loadSnap)
+	p.checkKernelLoad("", &procInternal{name: "loader", sid: 1}, loadSnap)
+	p.checkKernelLoad("", &procInternal{name: "modprobe", cmds: []string{"modprobe", "ip_vs"}, ppid: 2, sid: 100}, loadSnap)
+	p.checkKernelLoad("", &procInternal{name: "modprobe", cmds: []string{"modprobe", "ip_vs"}, pname: "systemd-udevd", sid: 100}, loadSnap)
+	p.checkKernelLoad("c1", &procInternal{name: "nginx", sid: 100}, &procSnapshot{bSyscall: true, bFds: true})
+	if lookups != 0 {
+		t.Errorf("Unexpected policy lookups: %v\n", lookups)
+	}
+
+	tests := []struct {
+		id     string
+		action string
+		rType  int
+	}{
+		{"c1", share.PolicyActionViolate, PROBE_REPORT_KERNEL_LOAD},
+		{"c1", share.PolicyActionDeny, PROBE_REPORT_KERNEL_LOAD_DENIED},
+		{"", share.PolicyActionDeny, PROBE_REPORT_KERNEL_LOAD}, // host processes are not killed
+	}
+	for i, test := range tests {
+		action = test.action
+		proc := &procInternal{name: "loader", pid: 0x3ffffff0 + i, sid: 100}
+		p.checkKernelLoad(test.id, proc, loadSnap)
+		select {
+		case rpt := <-p.notifyTaskChan:
+			if rpt.Type != test.rType || rpt.Process.Group != "nv.app" || !strings.Contains(rpt.Process.Msg, "finit_module()") {
+				t.Errorf("Error[%v]: unexpected report: %+v %+v\n", i, rpt, rpt.Process)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Error[%v]: kernel load is not reported\n", i)
+		}
+	}
+}
+
+func TestProcSnapshot(t *testing.T) {
+	global.SYS = system.NewSystemTools()
+
+	f, err := ioutil.TempFile("", "snapshot")
+	if err != nil {
+		t.Fatalf("Failed to create file: %v\n", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	snap := &procSnapshot{pid: os.Getpid()}
+	var fd string
+	for n, fl := range snap.getFds() {
+		if fl == f.Name() {
+			fd = n
+		}
+	}
+	if fd == "" {
+		t.Fatalf("Opened file is not found: %+v\n", snap.fds)
+	}
+	if flags, ok := osutil.GetProcessFdFlags(os.Getpid(), fd); !ok || (flags&syscall.O_ACCMODE) != syscall.O_RDWR {
+		t.Errorf("Unexpected open flags: %o\n", flags)
+	}
+
+	// the fds are read once in an inspection
+	f2, _ := ioutil.TempFile("", "snapshot")
+	defer os.Remove(f2.Name())
+	defer f2.Close()
+	for _, fl := range snap.getFds() {
+		if fl == f2.Name() {
+			t.Errorf("Fds should not be read again\n")
+		}
+	}
+}
+
+func TestEscapeCommands(t *testing.T) {
+	positives := map[*escapeTechnique][]string{
+		escapeCorePattern:  []string{"sh", "-c", "echo '|/tmp/x' > /proc/sys/kernel/core_pattern"},
+		escapeReleaseAgent: []string{"sh", "-c", "echo /cmd >/tmp/cgrp/release_agent"},
+		escapeRuntimeExe:   []string{"bash", "-c", "cat payload > /proc/self/exe"},
+	}
+	for tech, cmds := range positives {
+		if t2, _ := escapeTechniqueByCmds(cmds); t2 != tech {
+			t.Errorf("Error: positive: %v\n", cmds)
+		}
+	}
+
+	if tech, _ := escapeTechniqueByCmds([]string{"sysctl", "-w", "kernel.core_pattern=|/tmp/x"}); tech != escapeCorePattern {
+		t.Errorf("Error: positive: sysctl\n")
+	}
+	if tech, _ := escapeTechniqueByCmds([]string{"sh", "-c", "echo 1 | tee /tmp/cgrp/x/notify_on_release"}); tech != escapeReleaseAgent {
+		t.Errorf("Error: positive: tee\n")
+	}
+
+	negatives := [][]string{
+		[]string{"cat", "/proc/sys/kernel/core_pattern"},
+		[]string{"sysctl", "kernel.core_pattern"},
+		[]string{"ls", "-l", "/proc/self/exe"},
+	}
+	for i, cmds := range negatives {
+		if tech, _ := escapeTechniqueByCmds(cmds); tech != nil {
+			t.Errorf("Error[%v]: negative: %v\n", i, cmds)
+		}
+	}
+}
+
+func TestEscapeFiles(t *testing.T) {
+	global.RT = &dummyRTDriver{cmd: "runc"}
+
+	const rdonly, wronly, rdwr = syscall.O_RDONLY, syscall.O_WRONLY, syscall.O_RDWR
+	tests := []struct {
+		syscall string
+		fds     map[string]string
+		flags   map[string]int
+		tech    *escapeTechnique
+	}{
+		{"write", map[string]string{"3": "/proc/sys/kernel/core_pattern"}, map[string]int{"3": wronly}, escapeCorePattern},
+		{"write", map[string]string{"3": "/sys/fs/cgroup/rdma/x/release_agent"}, map[string]int{"3": rdwr}, escapeReleaseAgent},
+		{"write", map[string]string{"3": "/sys/fs/cgroup/rdma/x/notify_on_release"}, map[string]int{"3": wronly | syscall.O_APPEND}, escapeReleaseAgent},
+		{"write", map[string]string{"3": "/usr/bin/runc"}, map[string]int{"3": wronly}, escapeRuntimeExe},
+		{"splice", map[string]string{"0": "pipe:[100]", "3": "/etc/passwd"}, map[string]int{"3": rdonly}, escapeDirtyPipe},
+
+		// negatives
+		{"read", map[string]string{"3": "/proc/sys/kernel/core_pattern"}, map[string]int{"3": rdonly}, nil},
+		{"read", map[string]string{"3": "/usr/bin/runc"}, map[string]int{"3": rdonly}, nil},
+		{"read", map[string]string{"3": "/etc/passwd"}, map[string]int{"3": rdonly}, nil},
+		{"write", map[string]string{"3": "/etc/passwd"}, map[string]int{"3": rdwr}, nil},
+		{"write", map[string]string{"3": "/proc/1/exe"}, map[string]int{"3": wronly}, nil},
+		{"write", map[string]string{"3": "/var/log/app.log", "4": "socket:[200]"}, nil, nil},
+		{"write", nil, nil, nil},
+	}
+
+	for i, test := range tests {
+		snap := &procSnapshot{syscall: test.syscall, fds: test.fds, flags: test.flags, bSyscall: true, bFds: true}
+		if tech, _ := escapeTechniqueByFiles(snap); tech != test.tech {
+			t.Errorf("Error[%v]: unexpected technique: %+v\n", i, tech)
+		}
+	}
+
+	// the open flags are only read for the sensitive files
+	snap := &procSnapshot{
+		fds:      map[string]string{"3": "/var/log/app.log", "4": "/proc/sys/kernel/core_pattern"},
+		flags:    map[string]int{"4": rdonly},
+		bSyscall: true, bFds: true,
+	}
+	escapeTechniqueByFiles(snap)
+	if len(snap.flags) != 1 {
+		t.Errorf("Unexpected open flags read: %+v\n", snap.flags)
+	}
+}
+
+func TestCheckEscapeAttempt(t *testing.T) {
+	global.RT = &dummyRTDriver{cmd: "runc"}
+	p := &Probe{
+		selfID:         "agent",
+		agentSessionID: 1,
+		notifyTaskChan: make(chan *ProbeMessage, 8),
+		pMsgAggregates: make(map[string]*probeMsgAggregate),
+	}
+
+	// host processes and the agent's own processes are not checked
+	cmds := []string{"sh", "-c", "echo '|/tmp/x' > /proc/sys/kernel/core_pattern"}
+	p.checkEscapeAttempt("", &procInternal{name: "sh", cmds: cmds, pid: 100, sid: 100}, &procSnapshot{bSyscall: true, bFds: true})
+	p.checkEscapeAttempt("agent", &procInternal{name: "sh", cmds: cmds, pid: 101, sid: 100}, &procSnapshot{bSyscall: true, bFds: true})
+	p.checkEscapeAttempt("c1", &procInternal{name: "sh", cmds: cmds, pid: 102, sid: 1}, &procSnapshot{bSyscall: true, bFds: true})
+	if len(p.notifyTaskChan) != 0 {
+		t.Errorf("Unexpected escape reports: %v\n", len(p.notifyTaskChan))
+	}
+
+	// the fds are not read when the command line matches
+	proc := &procInternal{name: "sh", cmds: cmds, pid: 103, sid: 100}
+	snap := &procSnapshot{pid: 103}
+	p.checkEscapeAttempt("c1", proc, snap)
+	if snap.bFds || (proc.reported&escapeReported) == 0 {
+		t.Errorf("Unexpected check: fds=%v reported=%v\n", snap.bFds, proc.reported)
+	}
+	select {
+	case rpt := <-p.notifyTaskChan:
+		if rpt.Type != PROBE_REPORT_ESCAPE || len(rpt.Process.Mitre) != 1 || rpt.Process.Mitre[0] != mitreEscapeToHost {
+			t.Errorf("Unexpected report: %+v %+v\n", rpt, rpt.Process)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Escape attempt is not reported\n")
+	}
+
+	// reported once
+	p.checkEscapeAttempt("c1", proc, snap)
+	if len(p.notifyTaskChan) != 0 {
+		t.Errorf("Escape attempt should be reported once\n")
+	}
+
+	// by the opened files, the fds read by the kernel load check are reused
+	proc = &procInternal{name: "exploit", pid: 104, sid: 100}
+	snap = &procSnapshot{
+		syscall: "splice", fds: map[string]string{"3": "/etc/passwd"}, flags: map[string]int{"3": syscall.O_RDONLY},
+		bSyscall: true, bFds: true,
+	}
+	p.checkEscapeAttempt("c1", proc, snap)
+	select {
+	case rpt := <-p.notifyTaskChan:
+		if !strings.Contains(rpt.Process.Msg, escapeDirtyPipe.name) {
+			t.Errorf("Unexpected report: %+v\n", rpt.Process)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Escape attempt is not reported\n")
+	}
+}
+
+func TestGroupSyscalls(t *testing.T) {
+	p := &Probe{syscallGroups: make(map[string]utils.Set)}
+
+	tests := []struct {
+		group string
+		name  string
+		bNew  bool
+	}{
+		{"nv.web", "epoll_wait", true},
+		{"nv.web", "accept4", true},
+		{"nv.web", "epoll_wait", false},
+		{"nv.db", "epoll_wait", true}, // per group
+		{"nv.db", "epoll_wait", false},
+	}
+
+	for _, test := range tests {
+		if bNew := p.addGroupSyscall(test.group, test.name); bNew != test.bNew {
+			t.Errorf("Unexpected result: group=%v, name=%v, new=%v\n", test.group, test.name, bNew)
+		}
+	}
+	if n := p.syscallGroups["nv.web"].Cardinality(); n != 2 {
+		t.Errorf("Unexpected system calls: %+v\n", p.syscallGroups["nv.web"])
+	}
+}
@@ -20,6 +20,7 @@ type ProbeConfig struct {
 	PolicyLookupFunc     func(conn *dp.Connection) (uint32, uint8, bool)
 	ProcPolicyLookupFunc func(id, riskType, pname, ppath string, pid, pgid, shellCmd int, proc *share.CLUSProcessProfileEntry) (string, string, string, string, bool, error)
 	IsK8sGroupWithProbe  func(svcGroup string) bool
+	ZeroDriftLookup      func(svcGroup, name, path string) (bool, bool)
 	ReportLearnProc      func(svcGroup string, proc *share.CLUSProcessProfileEntry)
 	ContainerInContainer bool
 	GetContainerPid      func(id string) int
@@ -34,6 +35,7 @@ type ProbeConfig struct {
 	KubePlatform         bool
 	KubeFlavor           string
 	WalkHelper           *workerlet.Tasker
+	Restricted           bool // no privileged access or host PID namespace
 }
 
 const (
@@ -48,6 +50,9 @@ const (
 	PROBE_REPORT_PROCESS_VIOLATION
 	PROBE_REPORT_PROCESS_DENIED
 	PROBE_HOST_NEW_IP
+	PROBE_REPORT_KERNEL_LOAD
+	PROBE_REPORT_KERNEL_LOAD_DENIED
+	PROBE_REPORT_ESCAPE
 )
 
 var ProbeMsgName = []string{
@@ -62,6 +67,9 @@ var ProbeMsgName = []string{
 	PROBE_REPORT_PROCESS_VIOLATION: "process_profile_violation",
 	PROBE_REPORT_PROCESS_DENIED:    "process_profile_denied",
 	PROBE_HOST_NEW_IP:         		"host_new_ip",
+	PROBE_REPORT_KERNEL_LOAD:        "kernel_load",
+	PROBE_REPORT_KERNEL_LOAD_DENIED: "kernel_load_denied",
+	PROBE_REPORT_ESCAPE:             "container_escape",
 }
 
 type ProbeMessage struct {
@@ -110,4 +118,5 @@ type ProbeProcess struct {
 	RuleID      string
 	Group       string
 	Msg         string
+	Mitre       []string
 }
@@ -937,6 +937,73 @@ func (rs *RPCService) GetFileMonitorFile(ctx context.Context, f *share.CLUSFilte
 	}
 }
 
+func (rs *RPCService) GetFileBaseline(ctx context.Context, f *share.CLUSFilter) (*share.CLUSFileBaselineArray, error) {
+	log.WithFields(log.Fields{"filter": f}).Debug("")
+
+	gInfoRLock()
+	c, ok := gInfo.activeContainers[f.Workload]
+	gInfoRUnlock()
+
+	if !ok {
+		return nil, grpc.Errorf(codes.NotFound, "Container not found")
+	}
+
+	baselines := fileWatcher.GetFileBaselines(c.pid)
+	return &share.CLUSFileBaselineArray{Baselines: baselines}, nil
+}
+
+func (rs *RPCService) ResetFileBaseline(ctx context.Context, req *share.CLUSFileBaselineReset) (*share.RPCVoid, error) {
+	log.WithFields(log.Fields{"workload": req.Workload, "paths": req.Paths}).Debug("")
+
+	gInfoRLock()
+	c, ok := gInfo.activeContainers[req.Workload]
+	gInfoRUnlock()
+
+	if !ok {
+		return nil, grpc.Errorf(codes.NotFound, "Container not found")
+	}
+
+	cnt, err := fileWatcher.ResetFileBaselines(c.pid, req.Paths)
+	if err != nil {
+		return nil, grpc.Errorf(codes.NotFound, err.Error())
+	}
+	log.WithFields(log.Fields{"workload": req.Workload, "files": cnt}).Info("File baseline reset")
+	return &share.RPCVoid{}, nil
+}
+
+func (rs *RPCService) GetForensicBundle(f *share.CLUSFilter, stream share.EnforcerService_GetForensicBundleServer) error {
+	log.WithFields(log.Fields{"filter": f}).Debug("")
+
+	gInfoRLock()
+	c, ok := gInfo.activeContainers[f.Workload]
+	gInfoRUnlock()
+
+	if !ok {
+		return grpc.Errorf(codes.NotFound, "Container not found")
+	}
+
+	data, err := collectForensicBundle(c)
+	if err != nil {
+		return grpc.Errorf(codes.Internal, err.Error())
+	}
+
+	log.WithFields(log.Fields{"workload": f.Workload, "size": len(data)}).Info("Forensic bundle collected")
+	for i := 0; i < len(data); i += packetSize {
+		end := i + packetSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if err := stream.Send(&share.CLUSForensicBundle{Bundle: data[i:end]}); err != nil {
+			if grpc.Code(err) != codes.Canceled {
+				log.WithFields(log.Fields{"err": err}).Error("GRPC send forensic bundle fail")
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
+
 func (rs *RPCService) GetProcess(ctx context.Context, f *share.CLUSFilter) (*share.CLUSProcessArray, error) {
 	log.WithFields(log.Fields{"filter": f}).Debug("")
 
@@ -4,6 +4,7 @@ package main
 import "C"
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -20,6 +21,7 @@ import (
 	"github.com/neuvector/neuvector/share/cluster"
 	"github.com/neuvector/neuvector/share/fsmon"
 	"github.com/neuvector/neuvector/share/global"
+	"github.com/neuvector/neuvector/share/otel"
 	"github.com/neuvector/neuvector/share/utils"
 	"github.com/neuvector/neuvector/share/container"
 )
@@ -145,6 +147,23 @@ func systemConfigUnmanagedWl(detectUnmanagedWl bool) {
 	dp.DPCtrlSetDetectUnmanagedWl(&duw)
 }
 
+func systemConfigHostNetPolicy(hostNetPolicy bool) {
+	if gInfo.hostNetPolicy == hostNetPolicy {
+		return
+	}
+	gInfo.hostNetPolicy = hostNetPolicy
+	programHostNodeDP(hostNetPolicy)
+}
+
+func systemConfigMeter(synFlood, portScan uint32) {
+	if gInfo.synFloodThreshold == synFlood && gInfo.portScanThreshold == portScan {
+		return
+	}
+	gInfo.synFloodThreshold = synFlood
+	gInfo.portScanThreshold = portScan
+	dp.DPCtrlConfigMeter(synFlood, portScan)
+}
+
 func systemConfigProc(nType cluster.ClusterNotifyType, key string, value []byte) {
 	switch nType {
 	case cluster.ClusterNotifyAdd, cluster.ClusterNotifyModify:
@@ -157,12 +176,20 @@ func systemConfigProc(nType cluster.ClusterNotifyType, key string, value []byte)
 		systemConfigXff(conf.XffEnabled)
 		systemConfigNetPolicy(conf.DisableNetPolicy)
 		systemConfigUnmanagedWl(conf.DetectUnmanagedWl)
+		systemConfigHostNetPolicy(conf.HostNetPolicyStatus)
+		systemConfigMeter(conf.SynFloodThreshold, conf.PortScanThreshold)
+		bench.SetKubeCISProfile(conf.KubeCISProfile)
+		bench.SetBenchMode(conf.BenchMode)
 	case cluster.ClusterNotifyDelete:
 		systemConfigPolicyMode(defaultPolicyMode)
 		systemConfigTapProxymesh(defaultTapProxymesh)
 		systemConfigXff(defaultXffEnabled)
 		systemConfigNetPolicy(defaultDisableNetPolicy)
 		systemConfigUnmanagedWl(defaultDetectUnmanagedWl)
+		systemConfigHostNetPolicy(defaultHostNetPolicy)
+		systemConfigMeter(0, 0)
+		bench.SetKubeCISProfile("")
+		bench.SetBenchMode("")
 	}
 }
 
@@ -178,6 +205,7 @@ func initWorkloadPolicyMap() map[string]*policy.WorkloadIPPolicyInfo {
 			RuleMap: make(map[string]*dp.DPPolicyIPRule),
 			AppMap: c.appMap,
 			PortMap: c.portMap,
+			QuarExceptions: c.quarExcepts,
 			Policy: dp.DPWorkloadIPPolicy{
 				WlID:        wlID,
 				WorkloadMac: nil,
@@ -195,22 +223,66 @@ func initWorkloadPolicyMap() map[string]*policy.WorkloadIPPolicyInfo {
 		}
 		workloadPolicyMap[wlID] = &pInfo
 	}
+	if gInfo.hostNetPolicy {
+		//host processes are enforced as one endpoint
+		workloadPolicyMap[share.CLUSHostNodeWL] = &policy.WorkloadIPPolicyInfo{
+			RuleMap: make(map[string]*dp.DPPolicyIPRule),
+			Policy: dp.DPWorkloadIPPolicy{
+				WlID:        share.CLUSHostNodeWL,
+				WorkloadMac: []string{hostNodeMacStr},
+				IPRules:     nil,
+				ApplyDir:    policyApplyDir,
+			},
+			HostNode:   true,
+			CapIntcp:   true,
+			Configured: false,
+		}
+	}
 	return workloadPolicyMap
 }
 
-func getPolicyConfig(newRuleKey string, slots, ruleslen int) []share.CLUSGroupIPPolicy {
+type policySlotData struct {
+	hash string
+	data []byte
+}
+
+// unzipped policy slots of the last version, indexed by the slot number
+var lastPolicySlots map[int]*policySlotData = make(map[int]*policySlotData)
+
+// The slot is read from the cluster only if its content is changed. Slots are decoded every time because
+// the decoded addresses are modified when the policy is parsed.
+func getPolicySlot(key string, idx int, hashes []string, newSlots map[int]*policySlotData) []byte {
+	if idx < len(hashes) {
+		if slot, ok := lastPolicySlots[idx]; ok && slot.hash == hashes[idx] {
+			newSlots[idx] = slot
+			return slot.data
+		}
+	}
+
+	value, _ := cluster.Get(key)
+	if value == nil {
+		return nil
+	}
+	uzb := utils.GunzipBytes(value)
+	if uzb == nil {
+		log.Error("Failed to unzip data")
+		return nil
+	}
+	if idx < len(hashes) {
+		newSlots[idx] = &policySlotData{hash: hashes[idx], data: uzb}
+	}
+	return uzb
+}
+
+func getPolicyConfig(newRuleKey string, slots, ruleslen int, hashes []string) []share.CLUSGroupIPPolicy {
 	pols := make([]share.CLUSGroupIPPolicy, ruleslen)
 	log.WithFields(log.Fields{"newRuleKey": newRuleKey, "slots": slots, "ruleslen": ruleslen}).Debug("")
+	newSlots := make(map[int]*policySlotData, len(hashes))
 	for i := 0; i < slots; i++ {
 		key := fmt.Sprintf("%s%v", newRuleKey, i)
 		//log.WithFields(log.Fields{"key": key,}).Debug("rule key")
-		if value, _ := cluster.Get(key); value != nil {
+		if uzb := getPolicySlot(key, i, hashes, newSlots); uzb != nil {
 			pol := make([]share.CLUSGroupIPPolicy, 0)
-			uzb := utils.GunzipBytes(value)
-			if uzb == nil {
-				log.Error("Failed to unzip data")
-				continue
-			}
 			err := json.Unmarshal(uzb, &pol)
 			if err != nil {
 				log.WithFields(log.Fields{"error": err}).Error("Cannot decode policy")
@@ -225,6 +297,7 @@ func getPolicyConfig(newRuleKey string, slots, ruleslen int) []share.CLUSGroupIP
 			}
 		}
 	}
+	lastPolicySlots = newSlots
 	return pols
 }
 
@@ -269,7 +342,7 @@ func systemConfigPolicyVersion(s share.CLUSGroupIPPolicyVer) []share.CLUSGroupIP
 	}
 
 	//combine group ip rules from separate slots
-	groupIPPolicy = getPolicyConfig(newRuleKey, s.SlotNo, s.RulesLen)
+	groupIPPolicy = getPolicyConfig(newRuleKey, s.SlotNo, s.RulesLen, s.SlotHash)
 
 	if groupIPPolicy != nil && len(groupIPPolicy) > 0 && s.WorkloadSlot > 0 && s.WorkloadLen > 0 {
 		groupIPPolicy = mergeWlPolicyConfig(groupIPPolicy, s.RulesLen, s.WorkloadSlot, s.WorkloadLen)
@@ -296,7 +369,7 @@ func systemConfigPolicy(nType cluster.ClusterNotifyType, key string, value []byt
 	if agentEnv.netPolicyPuller == 0 {
 		systemUpdatePolicy(s) // inline
 	} else {
-		nextNetworkPolicyVer = &s // regulator
+		setNextNetworkPolicyVer(&s) // regulator
 	}
 }
 
@@ -310,6 +383,20 @@ func systemUpdatePolicy(s share.CLUSGroupIPPolicyVer) bool {
 		return false
 	}
 
+	networkPolicyVerMutex.Lock()
+	lastNetworkPolicyVer = &s
+	networkPolicyVerMutex.Unlock()
+
+	// continue the trace of the policy calculation in the controller
+	ctx := otel.ContextWithTraceParent(context.Background(), s.TraceParent)
+	_, span := otel.StartSpan(ctx, "policy.apply", otel.SpanKindInternal)
+	span.SetAttribute("policy.version", s.PolicyIPRulesVersion)
+	span.SetAttribute("rules", len(groupIPPolicy))
+	defer func() {
+		span.End()
+		otel.RecordDuration("neuvector.policy.apply.duration", span.Duration())
+	}()
+
 	wm := initWorkloadPolicyMap()
 	hostPolicyChangeSet := pe.UpdateNetworkPolicy(groupIPPolicy, wm)
 
@@ -895,9 +982,24 @@ func updateDlpDetectionRules(drlist []*share.CLUSDlpRule,
 				Name: cdre.Name,
 				ID:   cdre.ID,
 			}
+			//checksum is validated on the text matched by the regex patterns
+			checksum := ""
+			for _, pc := range cdre.Patterns {
+				if pc.Key == share.DlpRuleKeyChecksum {
+					checksum = pc.Value
+				}
+			}
 			for _, pc := range cdre.Patterns {
 				//ignore empty pattern rule
-				if pc.Value == "" {
+				if pc.Value == "" || pc.Key == share.DlpRuleKeyChecksum {
+					continue
+				}
+				//file type is detected by the magic bytes, which are case sensitive
+				if pc.Key == share.DlpRuleKeyFileType {
+					if magic, ok := share.DlpFileTypeMagic[pc.Value]; ok {
+						pat := fmt.Sprintf("/%s/s; context %s", magic, share.DlpPatternContextBODY)
+						dpdlpre.Patterns = append(dpdlpre.Patterns, pat)
+					}
 					continue
 				}
 				pat := ""
@@ -910,6 +1012,9 @@ func updateDlpDetectionRules(drlist []*share.CLUSDlpRule,
 				} else {
 					pat = fmt.Sprintf("%s; context %s", pat, pc.Context)
 				}
+				if checksum != "" && pc.Op != share.CriteriaOpNotRegex {
+					pat = fmt.Sprintf("%s; checksum %s", pat, checksum)
+				}
 				dpdlpre.Patterns = append(dpdlpre.Patterns, pat)
 			}
 			sort.Slice(dpdlpre.Patterns, func(i, j int) bool {
@@ -966,6 +1071,8 @@ func dlpConfigRule(dlprules share.CLUSWorkloadDlpRules) {
 	var wlmacs utils.Set = utils.NewSet()
 	var dlprnid map[string]uint32 = make(map[string]uint32)
 
+	dlprules = mergeThreatSignatures(dlprules)
+
 	//no dlp rules to build detection tree
 	if len(dlprules.DlpRuleList) == 0 &&
 		(pe.DlpBldInfo == nil ||
@@ -1079,6 +1186,7 @@ func dlpConfigRuleVersion(nType cluster.ClusterNotifyType, key string, value []b
 		return
 	}
 	dlprules := dlpUpdateRuleVersion(s)
+	lastDlpRules = dlprules
 	dlpConfigRule(dlprules)
 	//when network policy is disabled, change workload's datapath via dlp
 	if gInfo.disableNetPolicy {
@@ -1107,7 +1215,7 @@ type systemConfigTask struct {
 
 // This is run in task thread context
 func (p *systemConfigTask) handler() {
-	systemUpdateProc(p.nType, p.key, p.value)
+	applyProfileUpdate(p.nType, p.key, p.value)
 }
 
 func systemUpdateHandler(nType cluster.ClusterNotifyType, key string, value []byte, modifyIdx uint64) {
@@ -1133,6 +1241,9 @@ func systemConfigFileMonitor(nType cluster.ClusterNotifyType, key string, value
 			fileWatcher.ContainerCleanup(1)
 			config := &fsmon.FsmonConfig{} // TODO:
 			config.Profile = &profile
+			if ok, access := getFileAccessProfile(name); ok {
+				config.Rule = access // applications allowed by the host services
+			}
 			if len(profile.Filters) > 0 {
 				config.Profile.Mode = share.PolicyModeEvaluate // always monitor mode
 				go fileWatcher.StartWatch("", 1, config, false, false)
@@ -1151,6 +1262,9 @@ func systemConfigFileAccessRule(nType cluster.ClusterNotifyType, key string, val
 		json.Unmarshal(value, &rule)
 		name := share.CLUSProfileKey2Name(key)
 		updateGroupProfileCache(nType, name, rule)
+		if name == "nodes" {
+			fileWatcher.UpdateAccessRules(name, 1, &rule)
+		}
 	}
 }
 
@@ -1259,6 +1373,11 @@ func addLearnedProcess(svcGroup string, proc *share.CLUSProcessProfileEntry) {
 		Uid:       proc.Uid,
 		Hash:      proc.Hash,
 		Action:    proc.Action,
+		Syscalls:  proc.Syscalls,
+	}
+	if proc.ArgsMatch != "" {
+		report.Args = proc.Args
+		report.ArgsMatch = proc.ArgsMatch
 	}
 	learnedProcessMtx.Lock()
 	lastReportTime = time.Now()
@@ -5,6 +5,7 @@ import "C"
 
 import (
 	"net"
+	"reflect"
 	"time"
 
 	"github.com/google/uuid"
@@ -59,11 +60,28 @@ func isContainerQuarantine(c *containerData) bool {
 	}
 }
 
+func containerQuarExceptions(c *containerData) []share.CLUSQuarException {
+	if !c.capIntcp {
+		return nil
+	}
+	if cfg, ok := gInfo.containerConfig[c.id]; ok && cfg.Quarantine {
+		return cfg.QuarExceptions
+	} else {
+		return nil
+	}
+}
+
+// All traffic is dropped before reaching dp, unless there are exceptions to be enforced by dp
+func isContainerQuarBlocked(c *containerData) bool {
+	return c.quar && len(c.quarExcepts) == 0
+}
+
 // Here we compare the difference between the new config from cluster and local cache.
 // In the case that the agent process restarts, we need to make sure all local states should
 // be consistent with the local cache.
 func taskConfigContainer(id string, newconf *share.CLUSWorkloadConfig) {
 	log.WithFields(log.Fields{"container": id, "config": *newconf}).Debug("")
+	var bPolicy bool
 	cid := ""
 	gInfo.containerConfig[id] = newconf
 	if c, ok := gInfo.activeContainers[id]; ok && c.capIntcp {
@@ -86,7 +104,10 @@ func taskConfigContainer(id string, newconf *share.CLUSWorkloadConfig) {
 				if pc, exist := gInfo.activeContainers[cid]; exist && pc.capIntcp {
 					inline := isContainerInline(pc)
 					quar := isContainerQuarantine(pc)
-					if inline != pc.inline || quar != pc.quar {
+					excepts := containerQuarExceptions(pc)
+					if inline != pc.inline || quar != pc.quar || !reflect.DeepEqual(excepts, pc.quarExcepts) {
+						bPolicy = len(excepts) > 0 || len(pc.quarExcepts) > 0
+						pc.quarExcepts = excepts
 						changeContainerWire(pc, inline, quar, &newconf.QuarReason)
 					}
 				}
@@ -94,9 +115,17 @@ func taskConfigContainer(id string, newconf *share.CLUSWorkloadConfig) {
 		}
 		inline := isContainerInline(c)
 		quar := isContainerQuarantine(c)
-		if inline != c.inline || quar != c.quar {
+		excepts := containerQuarExceptions(c)
+		if inline != c.inline || quar != c.quar || !reflect.DeepEqual(excepts, c.quarExcepts) {
+			bPolicy = bPolicy || len(excepts) > 0 || len(c.quarExcepts) > 0
+			c.quarExcepts = excepts
 			changeContainerWire(c, inline, quar, &newconf.QuarReason)
 		}
+
+		// the quarantine exceptions are enforced by the workload policy
+		if s := getLastNetworkPolicyVer(); bPolicy && s != nil {
+			systemUpdatePolicy(*s)
+		}
 	}
 }
 
@@ -302,7 +331,9 @@ func fileModifiedToIncidentLog(e *fsmon.MonitorMessage) *share.CLUSIncidentLog {
 	defer gInfoUnlock()
 
 	if e.ID == "" {
-		if e.Package {
+		if e.Drift {
+			eLog.ID = share.CLUSIncidHostFileContentDrift
+		} else if e.Package {
 			eLog.ID = share.CLUSIncidHostPackageUpdated
 			// invalidate scan cache
 			gInfo.hostScanCache = nil
@@ -313,7 +344,9 @@ func fileModifiedToIncidentLog(e *fsmon.MonitorMessage) *share.CLUSIncidentLog {
 		}
 	} else if c, ok := gInfo.activeContainers[e.ID]; ok {
 		eLog.WorkloadName = c.name
-		if e.Package {
+		if e.Drift {
+			eLog.ID = share.CLUSIncidContainerFileContentDrift
+		} else if e.Package {
 			eLog.ID = share.CLUSIncidContainerPackageUpdated
 			// invalidate scan cache
 			c.scanCache = nil
@@ -322,7 +355,9 @@ func fileModifiedToIncidentLog(e *fsmon.MonitorMessage) *share.CLUSIncidentLog {
 		}
 	} else {
 		eLog.WorkloadName = ""
-		if e.Package {
+		if e.Drift {
+			eLog.ID = share.CLUSIncidContainerFileContentDrift
+		} else if e.Package {
 			eLog.ID = share.CLUSIncidContainerPackageUpdated
 		} else {
 			eLog.ID = share.CLUSIncidContainerFileAccessViolation
@@ -351,6 +386,7 @@ func processToIncidentLog(s *probe.ProbeProcess, count int, start time.Time) *sh
 		RuleID:      s.RuleID,
 		Group:       s.Group,
 		Msg:         s.Msg,
+		Mitre:       s.Mitre,
 		Count:       count,
 		StartAt:     start,
 	}
@@ -420,6 +456,28 @@ func procDeniedToIncidentLog(s *probe.ProbeProcess, count int, start time.Time)
 	return eLog
 }
 
+func kernelLoadToIncidentLog(s *probe.ProbeProcess, count int, start time.Time, denied bool) *share.CLUSIncidentLog {
+	eLog := processToIncidentLog(s, count, start)
+	if s.ID == "" {
+		eLog.ID = share.CLUSIncidHostKernelLoad
+	} else {
+		eLog.ID = share.CLUSIncidContainerKernelLoad
+	}
+	if denied {
+		eLog.Action = share.PolicyActionDeny
+	} else {
+		eLog.Action = share.PolicyActionViolate
+	}
+	return eLog
+}
+
+func escapeToIncidentLog(s *probe.ProbeProcess, count int, start time.Time) *share.CLUSIncidentLog {
+	eLog := processToIncidentLog(s, count, start)
+	eLog.ID = share.CLUSIncidContainerEscape
+	eLog.Action = share.PolicyActionViolate
+	return eLog
+}
+
 func reportIncident(eLog *share.CLUSIncidentLog) {
 	log.WithFields(log.Fields{"eLog": *eLog}).Debug("")
 	eLog.LogUID = uuid.New().String()
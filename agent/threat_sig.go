@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share"
+)
+
+// Threat signatures pushed by the controller at runtime. They are compiled
+// into the same detection tree as the dlp/waf rules, so the last dlp rules
+// received from the cluster are kept to rebuild the tree when they change.
+var threatSigs []*share.CLUSThreatSignatureEntry
+var lastDlpRules share.CLUSWorkloadDlpRules
+
+type threatSigTask struct {
+	sigs *share.CLUSThreatSignatureArray
+}
+
+// This is run in task thread context
+func (p *threatSigTask) handler() {
+	log.WithFields(log.Fields{"version": p.sigs.Version, "count": len(p.sigs.Signatures)}).Info()
+	threatSigs = p.sigs.Signatures
+	dlpConfigRule(lastDlpRules)
+	setMiningPools(p.sigs.MiningPools)
+}
+
+func (rs *RPCService) SetThreatSignatures(ctx context.Context, sigs *share.CLUSThreatSignatureArray) (*share.RPCVoid, error) {
+	task := ContainerTask{task: TASK_CONFIG_SYSTEM, taskData: &threatSigTask{sigs: sigs}}
+	ContainerTaskChan <- &task
+	return &share.RPCVoid{}, nil
+}
+
+func threatSigRuleName(id uint32) string {
+	return fmt.Sprintf("threatsig.%d", id)
+}
+
+// Append threat signatures to the dlp rules as waf rules, applied to all
+// workloads that have datapath. The original rules are not modified.
+func mergeThreatSignatures(dlprules share.CLUSWorkloadDlpRules) share.CLUSWorkloadDlpRules {
+	if len(threatSigs) == 0 {
+		return dlprules
+	}
+
+	merged := share.CLUSWorkloadDlpRules{
+		DlpRuleList: make([]*share.CLUSDlpRule, len(dlprules.DlpRuleList), len(dlprules.DlpRuleList)+len(threatSigs)),
+		DlpWlRules:  make([]*share.CLUSDlpWorkloadRule, len(dlprules.DlpWlRules)),
+	}
+	copy(merged.DlpRuleList, dlprules.DlpRuleList)
+	copy(merged.DlpWlRules, dlprules.DlpWlRules)
+
+	settings := make([]*share.CLUSDlpSetting, 0, len(threatSigs))
+	for _, sig := range threatSigs {
+		rule := &share.CLUSDlpRule{
+			Name:     threatSigRuleName(sig.ID),
+			ID:       sig.ID,
+			Patterns: make([]share.CLUSDlpCriteriaEntry, len(sig.Patterns)),
+		}
+		for i, pt := range sig.Patterns {
+			rule.Patterns[i] = share.CLUSDlpCriteriaEntry{Key: "pattern", Op: pt.Op, Value: pt.Value, Context: pt.Context}
+		}
+		merged.DlpRuleList = append(merged.DlpRuleList, rule)
+		settings = append(settings, &share.CLUSDlpSetting{Name: rule.Name, Action: sig.Action})
+	}
+
+	// Keep the mode and waf direction already assigned to the workload
+	wlRules := make(map[string]*share.CLUSDlpWorkloadRule)
+	for _, dre := range dlprules.DlpWlRules {
+		if dre == nil {
+			continue
+		}
+		if e, ok := wlRules[dre.WorkloadId]; ok && (e.RuleType == share.WafWlRuleIn || e.RuleType == share.WafWlRuleOut) {
+			continue
+		}
+		wlRules[dre.WorkloadId] = dre
+	}
+
+	for id, c := range gInfo.activeContainers {
+		if !c.hasDatapath {
+			continue
+		}
+		dre := &share.CLUSDlpWorkloadRule{
+			WorkloadId:    id,
+			PolicyMode:    c.policyMode,
+			RuleListNames: settings,
+			RuleType:      share.WafWlRuleIn,
+		}
+		if e, ok := wlRules[id]; ok {
+			dre.PolicyMode = e.PolicyMode
+			if e.RuleType == share.WafWlRuleOut {
+				dre.RuleType = e.RuleType
+			}
+		}
+		merged.DlpWlRules = append(merged.DlpWlRules, dre)
+	}
+	return merged
+}
@@ -105,7 +105,9 @@ func statsLoop(bPassiveContainerDetect bool) {
 			gInfoRLock()
 			updateAgentStats(system)
 			updateContainerStats(system)
+			busy := checkCryptoMining()
 			gInfoRUnlock()
+			checkMiningCmdlines(busy, prober.GetContainerProcs)
 		case <-runStateTicker:
 			// Check container periodically in case container removal event is missed.
 			existing, stops := global.RT.ListContainerIDs()
@@ -254,9 +256,12 @@ func putFqdnIps() {
 	fqdnIpMutex.Unlock()
 
 	for _, fqdnip := range fqdnips {
-		key := share.CLUSFqdnIpKey(Host.ID, fqdnip.FqdnName)
-		value, _ := json.Marshal(fqdnip)
-		zb := utils.GzipBytes(value)
+		key, zb := fqdnIpKeyValue(fqdnip)
+		if zb == nil {
+			log.WithFields(log.Fields{"key": key}).Debug("Delete fqdn ip")
+			cluster.Delete(key)
+			continue
+		}
 		log.WithFields(log.Fields{"key": key, "fqdnip": fqdnip}).Debug("Put fqdn ip")
 		if err := cluster.PutBinary(key, zb); err != nil {
 			log.WithFields(log.Fields{"error": err}).Error("Error in putting to cluster")
@@ -264,6 +269,16 @@ func putFqdnIps() {
 	}
 }
 
+// Return the zipped value of the fqdn ips, nil when all learned ips have expired in dp and the key should be deleted
+func fqdnIpKeyValue(fqdnip *share.CLUSFqdnIp) (string, []byte) {
+	key := share.CLUSFqdnIpKey(Host.ID, fqdnip.FqdnName)
+	if len(fqdnip.FqdnIP) == 0 {
+		return key, nil
+	}
+	value, _ := json.Marshal(fqdnip)
+	return key, utils.GzipBytes(value)
+}
+
 // -- threats
 
 func putThreatLogs() {
@@ -281,6 +296,7 @@ func putThreatLogs() {
 			tl.slog.AgentName = Agent.Name
 			tl.slog.WorkloadID = c.id
 			tl.slog.WorkloadName = c.name
+			checkMiningThreatLog(c.id, tl.slog)
 			if tl.slog.PktIngress {
 				tl.slog.LocalPeer = isLocalHostIP(tl.slog.SrcIP)
 			} else {
@@ -393,7 +409,13 @@ func updateConnection() {
 					conn.ThreatID = C.THRT_ID_DNS_TUNNELING
 				}
 			}
+			checkMiningConnection(conn, c.id)
 			updateConnectionMap(conn, data.EPMAC, c.id)
+		} else if gInfo.hostNetPolicy && data.EPMAC.String() == hostNodeMacStr {
+			// connection of host processes, reported as the host endpoint
+			conn.AgentID = Agent.ID
+			conn.HostID = Host.ID
+			updateConnectionMap(conn, data.EPMAC, share.CLUSLearnedHostPrefix+Host.ID)
 		}
 	}
 }
@@ -470,6 +492,9 @@ func updateConnectionMap(conn *dp.Connection, EPMAC net.HardwareAddr, id string)
 			entry.Severity = conn.Severity
 			entry.ThreatID = conn.ThreatID
 		}
+		if conn.MeshID != "" {
+			entry.MeshID = conn.MeshID
+		}
 
 		connLog.WithFields(log.Fields{"connection": entry, "mac": EPMAC.String()}).Debug("")
 	} else if len(connectionMap) < connectionMapMax || conn.PolicyAction > C.DP_POLICY_ACTION_CHECK_APP {
@@ -613,6 +638,7 @@ func conn2CLUS(c *dp.Connection) *share.CLUSConnection {
 		TmpOpen:      c.TmpOpen,
 		UwlIp:        c.UwlIp,
 		FQDN:         fqdn,
+		MeshID:       c.MeshID,
 	}
 }
 
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/neuvector/neuvector/agent/dp"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func TestConnectionMeshID(t *testing.T) {
+	lomac, _ := net.ParseMAC("6c:6b:73:74:01:02")
+	spiffe := "spiffe://cluster.local/ns/default/sa/sleep"
+
+	newConn := func(meshID string) *dp.Connection {
+		return &dp.Connection{
+			ClientIP: net.IPv4(127, 0, 0, 1), ServerIP: net.IPv4(127, 0, 0, 1), ServerPort: 8080,
+			IPProto: syscall.IPPROTO_TCP, Ingress: true, Bytes: 100, Sessions: 1, MeshID: meshID,
+		}
+	}
+
+	connectionMap = make(map[string]*dp.Connection)
+	entry := newConn("")
+	entry.Network = share.NetworkProxyMesh
+	connectionMap[keyTCPUDPConnection(entry)] = entry
+
+	// identity is kept from the session which carries it
+	updateConnectionMap(newConn(spiffe), lomac, "wl1")
+	updateConnectionMap(newConn(""), lomac, "wl1")
+	if entry.MeshID != spiffe || entry.Sessions != 3 {
+		t.Errorf("Unexpected merged connection: %+v\n", entry)
+	}
+
+	if c := conn2CLUS(entry); c.MeshID != spiffe {
+		t.Errorf("Mesh identity is not reported: %+v\n", c)
+	}
+	connectionMap = make(map[string]*dp.Connection)
+}
+
+func TestFqdnIpKeyValue(t *testing.T) {
+	Host.ID = "host1"
+	defer func() { Host.ID = "" }()
+
+	// the learned ips are reported by dp
+	dpTaskCallback(&dp.DPTask{Task: dp.DP_TASK_FQDN_IP, Fqdns: &share.CLUSFqdnIp{
+		FqdnName: "*.example.com", FqdnIP: []net.IP{net.IPv4(1, 2, 3, 4).To4(), net.IPv4(1, 2, 3, 5).To4()},
+	}})
+	// all learned ips have expired
+	dpTaskCallback(&dp.DPTask{Task: dp.DP_TASK_FQDN_IP, Fqdns: &share.CLUSFqdnIp{
+		FqdnName: "*.cdn.example.com", FqdnIP: []net.IP{},
+	}})
+
+	fqdnIpMutex.Lock()
+	fqdnips := fqdnIpCache
+	fqdnIpCache = nil
+	fqdnIpMutex.Unlock()
+	if len(fqdnips) != 2 {
+		t.Fatalf("Unexpected fqdn ip updates: %+v\n", fqdnips)
+	}
+
+	key, zb := fqdnIpKeyValue(fqdnips[0])
+	if key != share.CLUSFqdnIpKey("host1", "*.example.com") || zb == nil {
+		t.Errorf("Unexpected fqdn ip key: %v\n", key)
+	} else {
+		var fqdnip share.CLUSFqdnIp
+		if err := json.Unmarshal(utils.GunzipBytes(zb), &fqdnip); err != nil {
+			t.Errorf("Failed to unmarshal fqdn ip: %v\n", err)
+		} else if fqdnip.FqdnName != "*.example.com" || len(fqdnip.FqdnIP) != 2 || !fqdnip.FqdnIP[1].Equal(net.IPv4(1, 2, 3, 5)) {
+			t.Errorf("Unexpected fqdn ip: %+v\n", fqdnip)
+		}
+	}
+
+	if key, zb = fqdnIpKeyValue(fqdnips[1]); key != share.CLUSFqdnIpKey("host1", "*.cdn.example.com") || zb != nil {
+		t.Errorf("Expired fqdn ips should be deleted: key=%v\n", key)
+	}
+}
@@ -26,6 +26,8 @@ type AgentEnvInfo struct {
 	memoryLimit          uint64
 	peakMemoryUsage      uint64
 	snapshotMemStep      uint64
+	restrictedMode       bool
+	hostSkipPorts        string
 }
 
 const (
@@ -51,8 +51,10 @@ const (
 	CONST_API_SYSTEM_CONFIG
 	CONST_API_IBMSA
 	CONST_API_FED
-	CONST_API_PWD_PROFILE   // i.e. for password profile
-	CONST_API_VULNERABILITY // i.e. for vulnerability profile
+	CONST_API_PWD_PROFILE     // i.e. for password profile
+	CONST_API_VULNERABILITY   // i.e. for vulnerability profile
+	CONST_API_RISK_APPROVAL   // i.e. for approving accepted risks
+	CONST_API_POLICY_APPROVAL // i.e. for approving network rule changes
 )
 
 // apiCategoryID to permissions mapping
@@ -79,8 +81,30 @@ var apiPermissions map[int8]uint64 = map[int8]uint64{ // key is apiCategoryID
 	CONST_API_SYSTEM_CONFIG:   share.PERM_SYSTEM_CONFIG,
 	CONST_API_IBMSA:           share.PERM_IBMSA,
 	CONST_API_FED:             share.PERM_FED,
-	CONST_API_PWD_PROFILE:     share.PERMS_PWD_PROFILE,  // i.e. for password profile
-	CONST_API_VULNERABILITY:   share.PERM_VULNERABILITY, // i.e. for vulnerability profile
+	CONST_API_PWD_PROFILE:     share.PERMS_PWD_PROFILE,    // i.e. for password profile
+	CONST_API_VULNERABILITY:   share.PERM_VULNERABILITY,   // i.e. for vulnerability profile
+	CONST_API_RISK_APPROVAL:   share.PERM_RISK_APPROVAL,   // i.e. for approving accepted risks
+	CONST_API_POLICY_APPROVAL: share.PERM_POLICY_APPROVAL, // i.e. for approving network rule changes
+}
+
+type apikeyScopePermits struct {
+	readPermits  uint64
+	writePermits uint64
+}
+
+// apikey scope to permissions mapping
+var apikeyScopes map[string]apikeyScopePermits = map[string]apikeyScopePermits{
+	api.ApikeyScopeScanRead: apikeyScopePermits{
+		readPermits: share.PERMS_RUNTIME_SCAN | share.PERM_REG_SCAN | share.PERM_VULNERABILITY,
+	},
+	api.ApikeyScopePolicyWrite: apikeyScopePermits{
+		readPermits:  share.PERMS_RUNTIME_POLICIES | share.PERM_ADM_CONTROL,
+		writePermits: share.PERMS_RUNTIME_POLICIES | share.PERM_ADM_CONTROL,
+	},
+	api.ApikeyScopeFedAdmin: apikeyScopePermits{
+		readPermits:  share.PERMS_FED_READ,
+		writePermits: share.PERMS_FED_WRITE,
+	},
 }
 
 // key is permission id that is visible to the world. Regarding to the value,
@@ -290,6 +314,18 @@ var PermissionOptions = []*api.RESTRolePermitOptionInternal{ // basic permission
 		ReadSupported:  true,
 		WriteSupported: true,
 	},
+	&api.RESTRolePermitOptionInternal{
+		ID:             share.PERM_RISK_APPROVAL_ID,
+		Value:          share.PERM_RISK_APPROVAL,
+		SupportScope:   CONST_PERM_SUPPORT_GLOBAL,
+		WriteSupported: true,
+	},
+	&api.RESTRolePermitOptionInternal{
+		ID:             share.PERM_POLICY_APPROVAL_ID,
+		Value:          share.PERM_POLICY_APPROVAL,
+		SupportScope:   CONST_PERM_SUPPORT_GLOBAL,
+		WriteSupported: true,
+	},
 }
 
 var HiddenPermissions = utils.NewSet(share.PERM_IBMSA_ID, share.PERM_FED_ID, share.PERM_CLOUD_ID, share.PERM_NV_RESOURCE_ID)
@@ -372,6 +408,12 @@ func clusUserRoleToREST(name string, r *share.CLUSUserRoleInternal) *api.RESTUse
 			if option.WriteSupported && ((r.WritePermits & option.Value) == option.Value) {
 				permission.Write = true
 			}
+			for _, scope := range r.DomainScopes {
+				if scope.ID == option.ID {
+					permission.Domains = scope.Domains
+					break
+				}
+			}
 			if permission.Read || permission.Write {
 				permissions = append(permissions, permission)
 			}
@@ -409,6 +451,77 @@ func getRolePermitValues(roleName, domain string) (uint64, uint64) {
 	return 0, 0
 }
 
+func isDomainInScope(domain string, scopeDomains []string) bool {
+	if domain == AccessDomainGlobal {
+		return false
+	}
+	for _, d := range scopeDomains {
+		if share.EqualMatch(d, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the permits of the role that are not effective on an object in the domains because they are restricted to other domains.
+// The restricted permits are effective when the object is in one of the scope domains, or all of its domains are if own is true.
+// Global objects(no domain) are never in any scope.
+func getRoleOutScopePermits(roleName string, domains []string, own bool) (uint64, uint64) {
+	rolesMutex.RLock()
+	defer rolesMutex.RUnlock()
+
+	role, ok := allRoles[roleName]
+	if !ok || len(role.DomainScopes) == 0 {
+		return 0, 0
+	}
+
+	var inReadPermits, inWritePermits, outReadPermits, outWritePermits uint64
+	for _, scope := range role.DomainScopes {
+		in := own && len(domains) > 0
+		for _, domain := range domains {
+			if isDomainInScope(domain, scope.Domains) {
+				if !own {
+					in = true
+					break
+				}
+			} else if own {
+				in = false
+				break
+			}
+		}
+		if in {
+			inReadPermits |= scope.ReadPermits
+			inWritePermits |= scope.WritePermits
+		} else {
+			outReadPermits |= scope.ReadPermits
+			outWritePermits |= scope.WritePermits
+		}
+	}
+	return outReadPermits &^ inReadPermits, outWritePermits &^ inWritePermits
+}
+
+func getRoleScopeDomains(roleName string) []string {
+	rolesMutex.RLock()
+	defer rolesMutex.RUnlock()
+
+	var domains []string
+	if role, ok := allRoles[roleName]; ok {
+		domainSet := utils.NewSet()
+		for _, scope := range role.DomainScopes {
+			if scope.ReadPermits == 0 && scope.WritePermits == 0 {
+				continue
+			}
+			for _, domain := range scope.Domains {
+				if !domainSet.Contains(domain) {
+					domainSet.Add(domain)
+					domains = append(domains, domain)
+				}
+			}
+		}
+	}
+	return domains
+}
+
 func getRestRolePermitValues(roleName, domain string) []*api.RESTRolePermission {
 	rolesMutex.RLock()
 	defer rolesMutex.RUnlock()
@@ -500,6 +613,28 @@ type UriApiNode struct {
 
 var uriRequiredPermitsMappings map[string]*UriApiNode // key is method
 
+// The wildcard node is matched first. If the rest of the uri doesn't match under the wildcard node, the node of the exact
+// uri part is tried, so v1/workload/request/* and v1/workload/*/pcap can both be mapped. Return false if nothing matches.
+func matchUriApiNode(ssUri []string, parentNode *UriApiNode) (int8, bool) {
+	if len(ssUri) == 0 {
+		return 0, false
+	}
+	if node, ok := parentNode.childNodes["**"]; ok {
+		// forward requests(for multi-clusters) reach here
+		return node.apiCategoryID, true
+	}
+	for _, s := range []string{"*", ssUri[0]} {
+		if node, ok := parentNode.childNodes[s]; ok {
+			if len(ssUri) == 1 {
+				return node.apiCategoryID, true
+			} else if apiCategoryID, ok := matchUriApiNode(ssUri[1:], node); ok {
+				return apiCategoryID, true
+			}
+		}
+	}
+	return 0, false
+}
+
 func getRequiredPermissions(r *http.Request) (int8, uint64) {
 	if r == nil {
 		return 0, 0
@@ -511,32 +646,9 @@ func getRequiredPermissions(r *http.Request) (int8, uint64) {
 	if err == nil {
 		// u.Path is like "/v1/log/event"
 		ss := strings.Split(u.Path, "/") // ss is like {"", "v1", "log", "event"}
-		ssEndIndex := len(ss) - 2        // it's 2 because we iterate from ss[1]
 		// ignore leading "" in ss
 		if parentNode, ok := uriRequiredPermitsMappings[r.Method]; ok {
-			for idx, s := range ss[1:] {
-				if node, ok := parentNode.childNodes["**"]; ok {
-					// forward requests(for multi-clusters) reach here
-					apiCategoryID = node.apiCategoryID
-					break
-				} else if node, ok := parentNode.childNodes["*"]; ok {
-					if idx == ssEndIndex {
-						apiCategoryID = node.apiCategoryID
-						break
-					} else {
-						parentNode = node
-					}
-				} else if node, ok := parentNode.childNodes[s]; ok {
-					if idx == ssEndIndex {
-						apiCategoryID = node.apiCategoryID
-						break
-					} else {
-						parentNode = node
-					}
-				} else {
-					break
-				}
-			}
+			apiCategoryID, _ = matchUriApiNode(ss[1:], parentNode)
 		}
 	}
 	requiredPermissions, _ := apiPermissions[apiCategoryID]
@@ -604,6 +716,7 @@ func CompileUriPermitsMapping() {
 				"v1/debug/dlp/rule",
 				"v1/debug/dlp/mac",
 				"v1/debug/system/stats",
+				"v1/debug/rate_limit",
 				"v1/debug/controller/sync",
 				"v1/debug/workload/intercept",
 				"v1/debug/registry/image/*",
@@ -665,27 +778,32 @@ func CompileUriPermitsMapping() {
 			CONST_API_GROUP: []string{
 				"v1/group",
 				"v1/group/*",
+				"v1/groups/automode",
 				"v1/service",
 				"v1/service/*",
 				"v1/file/group",
+				"v1/file/config/crd",
 			},
 			CONST_API_RT_POLICIES: []string{
 				"v1/workload/*/process",
 				"v1/workload/*/process_history",
 				"v1/workload/*/process_profile",
 				"v1/workload/*/file_profile",
+				"v1/workload/*/file_baseline",
 				"v1/dlp/sensor",
 				"v1/dlp/sensor/*",
 				"v1/dlp/group",
 				"v1/dlp/group/*",
 				"v1/dlp/rule",
 				"v1/dlp/rule/*",
+				"v1/dlp/pack",
 				"v1/waf/sensor",
 				"v1/waf/sensor/*",
 				"v1/waf/group",
 				"v1/waf/group/*",
 				"v1/waf/rule",
 				"v1/waf/rule/*",
+				"v1/threat/signature",
 				"v1/policy/rule",
 				"v1/policy/rule/*",
 				"v1/session",
@@ -694,6 +812,12 @@ func CompileUriPermitsMapping() {
 				"v1/conversation/*/*",
 				"v1/process_profile",
 				"v1/process_profile/*",
+				"v1/process_profile/*/seccomp",
+				"v1/group/*/profiles/export",
+				"v1/policy/rules/hits",
+				"v1/policy/rules/unused",
+				"v1/policy/changes",
+				"v1/policy/changes/*",
 				"v1/process_rules/*",
 				"v1/file_monitor",
 				"v1/file_monitor/*",
@@ -720,12 +844,17 @@ func CompileUriPermitsMapping() {
 				"v1/workload/*/compliance",
 				"v1/bench/host/*/docker",
 				"v1/bench/host/*/kubernetes",
+				"v1/bench/host/*/kubernetes/ckl",
 				"v1/custom_check/*",
 				"v1/custom_check",
 				"v1/compliance/asset",
+				"v1/report/regulatory",
+				"v1/report/regulatory/*",
 				"v1/list/compliance",
 				"v1/compliance/profile",
 				"v1/compliance/profile/*",
+				"v1/compliance/acceptance",
+				"v1/compliance/acceptance/*",
 			},
 			CONST_API_AUDIT_EVENTS: []string{
 				"v1/log/audit",
@@ -737,10 +866,14 @@ func CompileUriPermitsMapping() {
 				"v1/log/violation",
 				"v1/log/security",
 				"v1/log/violation/workload",
+				"v1/report/attack-matrix",
 			},
 			CONST_API_EVENTS: []string{
 				"v1/log/event",
+				"v1/log/audit-config",
 				"v1/log/activity",
+				"v1/stream/log",
+				"v1/log/export",
 			},
 			CONST_API_AUTHENTICATION: []string{
 				"v1/server",
@@ -770,16 +903,29 @@ func CompileUriPermitsMapping() {
 				"v2/system/config",
 				"v1/system/license",
 				"v1/system/summary",
+				"v1/system/backup",
+				"v1/system/backup/archive",
+				"v1/report/schedule",
+				"v1/report/schedule/*",
+				"v1/report/generated",
+				"v1/report/generated/*",
 				"v1/internal/system",
+				"v1/internal/system/kv",
+				"v1/system/kv_encryption",
 			},
 			CONST_API_FED: []string{
 				"v1/fed/join_token",
 				"v1/fed/cluster/*/**",
 				"v1/fed/view/*",
+				"v1/fed/report",
+				"v1/fed/quarantine",
+				"v1/fed/report/*",
 			},
 			CONST_API_VULNERABILITY: []string{
 				"v1/vulnerability/profile",
 				"v1/vulnerability/profile/*",
+				"v1/vulnerability/acceptance",
+				"v1/vulnerability/acceptance/*",
 			},
 		}
 
@@ -788,9 +934,12 @@ func CompileUriPermitsMapping() {
 				"v1/token_auth_server/*",
 				"v1/fed/ping_internal",
 				"v1/fed/joint_test_internal",
+				"v1/fed/failover_internal",
+				"v1/fed/report_internal",
 				"v1/auth",
 				"v1/fed_auth",
 				"v1/auth/*",
+				"v1/recovery_auth",
 				"v1/eula",
 			},
 			CONST_API_DEBUG: []string{
@@ -799,9 +948,11 @@ func CompileUriPermitsMapping() {
 				"v1/fed/leave",
 				"v1/fed/remove_internal",
 				"v1/fed/command_internal",
+				"v1/fed/failover",
 				"v1/debug/controller/sync/*",
 				"v1/controller/*/profiling",
 				"v1/enforcer/*/profiling",
+				"v1/workload/*/forensic", // the bundle has the environment of the container
 				"v1/file/config",
 				"v1/csp/file/support",
 			},
@@ -821,6 +972,9 @@ func CompileUriPermitsMapping() {
 				"v1/scan/result/repository",
 				"v1/scan/repository",
 			},
+			CONST_API_WORKLOAD: []string{
+				"v1/graphql", // read-only, each queried field is authorized separately
+			},
 			CONST_API_GROUP: []string{
 				"v1/group",
 				"v1/file/group", // export group
@@ -830,12 +984,19 @@ func CompileUriPermitsMapping() {
 				"v1/workload/request/*",
 				"v1/dlp/sensor",
 				"v1/waf/sensor",
+				"v1/waf/modsec",
+				"v1/threat/signature/bundle",
 				"v1/file/dlp",
 				"v1/file/dlp/config",
 				"v1/file/waf",
 				"v1/file/waf/config",
+				"v1/file/network_policy", // export network policy
 				"v1/system/request",
 				"v1/sniffer",
+				"v1/policy/changes",
+				"v1/policy/simulate",
+				"v1/workload/*/pcap",
+				"v1/workload/*/file_baseline",
 				"v1/file/group/config", // for providing similar function as crd import but do not rely on crd webhook
 			},
 			CONST_API_ADM_CONTROL: []string{
@@ -848,6 +1009,7 @@ func CompileUriPermitsMapping() {
 			CONST_API_COMPLIANCE: []string{
 				"v1/bench/host/*/docker",
 				"v1/bench/host/*/kubernetes",
+				"v1/compliance/acceptance",
 			},
 			CONST_API_AUTHENTICATION: []string{
 				"v1/server",
@@ -856,6 +1018,8 @@ func CompileUriPermitsMapping() {
 			CONST_API_AUTHORIZATION: []string{
 				"v1/user_role",
 				"v1/user",
+				"v1/user/*/mfa",
+				"v1/user/*/mfa/verify",
 				"v1/api_key",
 			},
 			CONST_API_PWD_PROFILE: []string{
@@ -865,6 +1029,11 @@ func CompileUriPermitsMapping() {
 			CONST_API_SYSTEM_CONFIG: []string{
 				"v1/system/license/update",
 				"v1/system/config/webhook",
+				"v1/system/backup",
+				"v1/system/backup/restore",
+				"v1/report/schedule",
+				"v1/report/schedule/*/run",
+				"v1/system/kv_encryption/rotate",
 			},
 			CONST_API_IBMSA: []string{
 				"v1/partner/ibm_sa/*/setup/*",
@@ -872,12 +1041,25 @@ func CompileUriPermitsMapping() {
 			CONST_API_FED: []string{
 				"v1/fed/demote",
 				"v1/fed/deploy",
+				"v1/fed/standby",
+				"v1/fed/quarantine",
 				"v1/fed/cluster/*/**",
 				"v1/policy/rules/promote",
 				"v1/admission/rule/promote",
 			},
 			CONST_API_VULNERABILITY: []string{
 				"v1/vulnerability/profile/*/entry",
+				"v1/vulnerability/acceptance",
+			},
+			CONST_API_RISK_APPROVAL: []string{
+				"v1/vulnerability/acceptance/*/approve",
+				"v1/vulnerability/acceptance/*/reject",
+				"v1/compliance/acceptance/*/approve",
+				"v1/compliance/acceptance/*/reject",
+			},
+			CONST_API_POLICY_APPROVAL: []string{
+				"v1/policy/changes/*/approve",
+				"v1/policy/changes/*/reject",
 			},
 		}
 
@@ -906,6 +1088,7 @@ func CompileUriPermitsMapping() {
 			},
 			CONST_API_GROUP: []string{
 				"v1/group/*",
+				"v1/groups/batch",
 				"v1/service/config",
 				"v1/service/config/network",
 				"v1/service/config/profile",
@@ -916,8 +1099,10 @@ func CompileUriPermitsMapping() {
 				"v1/dlp/group/*",
 				"v1/waf/sensor/*",
 				"v1/waf/group/*",
+				"v1/threat/signature/*",
 				"v1/policy/rule",
 				"v1/policy/rule/*",
+				"v1/policy/rules/batch",
 				"v1/conversation_endpoint/*",
 				"v1/process_profile/*",
 				"v1/file_monitor/*",
@@ -952,6 +1137,8 @@ func CompileUriPermitsMapping() {
 				"v1/system/config",
 				"v2/system/config",
 				"v1/system/config/webhook/*",
+				"v1/system/backup",
+				"v1/report/schedule/*",
 			},
 			CONST_API_FED: []string{
 				"v1/fed/cluster/*/**",
@@ -988,6 +1175,7 @@ func CompileUriPermitsMapping() {
 				"v1/waf/sensor/*",
 				"v1/policy/rule/*",
 				"v1/policy/rule",
+				"v1/policy/changes/*",
 				"v1/conversation/*/*",
 				"v1/response/rule/*",
 				"v1/response/rule",
@@ -999,6 +1187,7 @@ func CompileUriPermitsMapping() {
 			},
 			CONST_API_COMPLIANCE: []string{
 				"v1/compliance/profile/*/entry/*",
+				"v1/compliance/acceptance/*",
 			},
 			CONST_API_AUTHENTICATION: []string{
 				"v1/server/*",
@@ -1006,6 +1195,7 @@ func CompileUriPermitsMapping() {
 			CONST_API_AUTHORIZATION: []string{
 				"v1/user_role/*",
 				"v1/user/*",
+				"v1/user/*/mfa",
 				"v1/api_key/*",
 			},
 			CONST_API_PWD_PROFILE: []string{
@@ -1014,13 +1204,17 @@ func CompileUriPermitsMapping() {
 			CONST_API_SYSTEM_CONFIG: []string{
 				"v1/system/license",
 				"v1/system/config/webhook/*",
+				"v1/report/schedule/*",
+				"v1/report/generated/*",
 			},
 			CONST_API_FED: []string{
 				"v1/fed/cluster/*",
 				"v1/fed/cluster/*/**",
+				"v1/fed/quarantine/*",
 			},
 			CONST_API_VULNERABILITY: []string{
 				"v1/vulnerability/profile/*/entry/*",
+				"v1/vulnerability/acceptance/*",
 			},
 		}
 
@@ -1114,6 +1308,7 @@ func AddRole(name string, role *share.CLUSUserRoleInternal) {
 		r.Comment = role.Comment
 		r.ReadPermits = role.ReadPermits
 		r.WritePermits = role.WritePermits
+		r.DomainScopes = role.DomainScopes
 	} else {
 		allRoles[name] = role
 	}
@@ -1213,6 +1408,10 @@ func (drs DomainRole) hasGlobalPermissions(readPermsRequired, writePermsRequired
 	writePermsRequired &= share.PERMS_FED_WRITE
 	if role, ok := drs[AccessDomainGlobal]; ok {
 		readPermits, writePermits := getRolePermitValues(role, AccessDomainGlobal)
+		// permissions restricted to some domains are not global permissions
+		outReadPermits, outWritePermits := getRoleOutScopePermits(role, nil, false)
+		readPermits &^= outReadPermits
+		writePermits &^= outWritePermits
 		if (readPermsRequired == (readPermits & readPermsRequired)) && (writePermsRequired == (writePermits & writePermsRequired)) {
 			return true
 		}
@@ -1281,6 +1480,27 @@ func NewReaderAccessControl() *AccessControl {
 	}
 }
 
+// Access control of the reader role in the domains, all domains if it's empty
+func NewDomainReaderAccessControl(domains []string) *AccessControl {
+	if len(domains) == 0 {
+		return NewReaderAccessControl()
+	}
+	roles := make(map[string]string, len(domains))
+	wRoles := map[string]string{}
+	for _, domain := range domains {
+		roles[domain] = api.UserRoleReader
+		if strings.Contains(domain, "*") {
+			wRoles[domain] = api.UserRoleReader
+		}
+	}
+	return &AccessControl{
+		op:            AccessOPRead,
+		roles:         roles,
+		wRoles:        wRoles,
+		apiCategoryID: CONST_API_SKIP,
+	}
+}
+
 func NewAccessControl(r *http.Request, op AccessOP, roles DomainRole) *AccessControl {
 	wRoles := map[string]string{}
 	for domain, role := range roles {
@@ -1326,8 +1546,12 @@ func (acc *AccessControl) BoostPermissions(toBoost uint64) *AccessControl {
 	}
 }
 
-func (acc *AccessControl) isDomainRoleAllowedToAccess(role, domain string, readPermitsRequired, writePermitsRequired uint64, accNotFromCaller bool) bool {
+// objDomains are the domains of the object to access, which decide whether the permissions restricted to some domains are effective
+func (acc *AccessControl) isDomainRoleAllowedToAccess(role, domain string, objDomains []string, own bool, readPermitsRequired, writePermitsRequired uint64, accNotFromCaller bool) bool {
 	readPermits, writePermits := getRolePermitValues(role, domain)
+	outReadPermits, outWritePermits := getRoleOutScopePermits(role, objDomains, own)
+	readPermits &^= outReadPermits
+	writePermits &^= outWritePermits
 	if domain != AccessDomainGlobal || role != "" {
 		// Boost permissions only when the caller's global role is not None. Otherwise namespace user could be boosted to see other domain's objects.
 		// The purpose of permissions boost is to allow caller to see other types of objects, not to see other domain's objects.
@@ -1391,7 +1615,7 @@ func (acc *AccessControl) isOneAccessAllowed(domain string, readPermitsRequired,
 	// 1. acc.roles contains a key/value for the domain. The value is used to do role/permission matching
 	// 2. acc.roles does not contain a key/value for the domain. skip
 	if role, ok := acc.roles[domain]; ok {
-		if acc.isDomainRoleAllowedToAccess(role, domain, readPermitsRequired, writePermitsRequired, accNotFromCaller) {
+		if acc.isDomainRoleAllowedToAccess(role, domain, []string{domain}, false, readPermitsRequired, writePermitsRequired, accNotFromCaller) {
 			return true
 		}
 	}
@@ -1402,7 +1626,7 @@ func (acc *AccessControl) isOneAccessAllowed(domain string, readPermitsRequired,
 	for wDomain, role := range acc.wRoles {
 		if domain != AccessDomainGlobal { // { role -> "*" namespace } is role to namespace mapping. { role -> "*" } still cannot cover global namespace("") !!
 			if share.EqualMatch(wDomain, domain) { // if param domain matches a wildcard domain
-				if acc.isDomainRoleAllowedToAccess(role, domain, readPermitsRequired, writePermitsRequired, accNotFromCaller) {
+				if acc.isDomainRoleAllowedToAccess(role, domain, []string{domain}, false, readPermitsRequired, writePermitsRequired, accNotFromCaller) {
 					return true
 				}
 			}
@@ -1412,6 +1636,15 @@ func (acc *AccessControl) isOneAccessAllowed(domain string, readPermitsRequired,
 	return false
 }
 
+// Check whether the global role can access an object in the domains. The role's permissions restricted to some domains
+// are effective only when the object is in one of them, or all of the object's domains are in them if own is true
+func (acc *AccessControl) isGlobalAccessAllowed(domains []string, own bool, readPermitsRequired, writePermitsRequired uint64) bool {
+	if role, ok := acc.roles[AccessDomainGlobal]; ok {
+		return acc.isDomainRoleAllowedToAccess(role, AccessDomainGlobal, domains, own, readPermitsRequired, writePermitsRequired, acc.apiCategoryID == CONST_API_SKIP)
+	}
+	return false
+}
+
 // Return true if one of domains is allowed to access
 func (acc *AccessControl) isAccessAllowed(domains []string, readPermitsRequired, writePermitsRequired uint64) bool {
 	for _, domain := range domains {
@@ -1433,6 +1666,29 @@ func (acc *AccessControl) isOwnAllowed(domains []string, readPermitsRequired, wr
 	return true
 }
 
+// Returns true when the API this access control object is created for is covered by the apikey scopes for the op.
+// No scope means the apikey is only limited by its role
+func (acc *AccessControl) IsAllowedByApikeyScopes(scopes []string) bool {
+	if len(scopes) == 0 || acc.apiCategoryID == CONST_API_NO_AUTH {
+		return true
+	} else if acc.requiredPermissions == 0 {
+		return false
+	}
+
+	var permits uint64
+	for _, scope := range scopes {
+		if p, ok := apikeyScopes[scope]; ok {
+			if acc.op == AccessOPRead {
+				permits |= p.readPermits
+			} else if acc.op == AccessOPWrite {
+				permits |= p.writePermits
+			}
+		}
+	}
+
+	return acc.requiredPermissions == (permits & acc.requiredPermissions)
+}
+
 // returns true only when the access control object is created for user whose global role has the same permissions as fedAdmin role for read/write
 func (acc *AccessControl) IsFedAdmin() bool {
 	return acc.roles.hasGlobalPermissions(share.PERMS_FED_READ, share.PERMS_FED_WRITE)
@@ -1483,17 +1739,26 @@ func (acc *AccessControl) GetAdminDomains(writePermitsRequired uint64) []string
 	/*if acc.roles.IsClusterAdmin() || acc.roles.IsFedAdmin() {
 		return nil
 	} else*/ //->
+	list := make([]string, 0)
 	if role, ok := acc.roles[AccessDomainGlobal]; ok {
 		_, writePermits := getRolePermitValues(role, AccessDomainGlobal)
-		if writePermitsRequired == (writePermits & writePermitsRequired) {
+		_, outWritePermits := getRoleOutScopePermits(role, nil, false)
+		if writePermitsRequired == (writePermits & writePermitsRequired &^ outWritePermits) {
 			return nil
 		}
+		// the required permissions may be restricted to some domains
+		for _, domain := range getRoleScopeDomains(role) {
+			_, outWritePermits = getRoleOutScopePermits(role, []string{domain}, false)
+			if writePermitsRequired == (writePermits & writePermitsRequired &^ outWritePermits) {
+				list = append(list, domain)
+			}
+		}
 	}
 
-	list := make([]string, 0)
 	for domain, role := range acc.roles {
 		_, writePermits := getRolePermitValues(role, domain)
-		if writePermitsRequired == (writePermits & writePermitsRequired) {
+		_, outWritePermits := getRoleOutScopePermits(role, []string{domain}, false)
+		if writePermitsRequired == (writePermits & writePermitsRequired &^ outWritePermits) {
 			list = append(list, domain)
 		}
 	}
@@ -1522,19 +1787,19 @@ func (acc *AccessControl) Authorize(obj share.AccessObject, f share.GetAccessObj
 		// Global object
 		authz = acc.isOneAccessAllowed(AccessDomainGlobal, globalReadPermitsRequired, globalWritePermitsRequired)
 	} else if d2 == nil {
-		authz = acc.isAccessAllowed(d1, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isOneAccessAllowed(AccessDomainGlobal, globalReadPermitsRequired, globalWritePermitsRequired)
+		authz = acc.isAccessAllowed(d1, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isGlobalAccessAllowed(d1, false, globalReadPermitsRequired, globalWritePermitsRequired)
 	} else {
 		if len(d1) == 1 && len(d2) == 1 && d1[0] == share.HiddenFedDomain && d2[0] == share.HiddenFedDomain {
 			// d1 & d2 are slice with one entry, share.HiddenFedDomain, if the object requires fed role for access
 			authz = acc.isOneAccessAllowed(AccessDomainGlobal, globalReadPermitsRequired, globalWritePermitsRequired|share.PERM_FED)
 		} else {
 			if acc.op == AccessOPWrite {
-				a1 := acc.isAccessAllowed(d1, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isOneAccessAllowed(AccessDomainGlobal, globalReadPermitsRequired, globalWritePermitsRequired)
-				a2 := acc.isAccessAllowed(d2, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isOneAccessAllowed(AccessDomainGlobal, globalReadPermitsRequired, globalWritePermitsRequired)
+				a1 := acc.isAccessAllowed(d1, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isGlobalAccessAllowed(d1, false, globalReadPermitsRequired, globalWritePermitsRequired)
+				a2 := acc.isAccessAllowed(d2, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isGlobalAccessAllowed(d2, false, globalReadPermitsRequired, globalWritePermitsRequired)
 				authz = a1 && a2
 			} else {
-				a1 := acc.isAccessAllowed(d1, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isOneAccessAllowed(AccessDomainGlobal, globalReadPermitsRequired, globalWritePermitsRequired)
-				a2 := acc.isAccessAllowed(d2, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isOneAccessAllowed(AccessDomainGlobal, globalReadPermitsRequired, globalWritePermitsRequired)
+				a1 := acc.isAccessAllowed(d1, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isGlobalAccessAllowed(d1, false, globalReadPermitsRequired, globalWritePermitsRequired)
+				a2 := acc.isAccessAllowed(d2, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isGlobalAccessAllowed(d2, false, globalReadPermitsRequired, globalWritePermitsRequired)
 				authz = a1 || a2
 			}
 		}
@@ -1565,19 +1830,19 @@ func (acc *AccessControl) AuthorizeOwn(obj share.AccessObject, f share.GetAccess
 		// Global object
 		authz = acc.isOneAccessAllowed(AccessDomainGlobal, globalReadPermitsRequired, globalWritePermitsRequired)
 	} else if d2 == nil {
-		authz = acc.isOwnAllowed(d1, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isOneAccessAllowed(AccessDomainGlobal, globalReadPermitsRequired, globalWritePermitsRequired)
+		authz = acc.isOwnAllowed(d1, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isGlobalAccessAllowed(d1, true, globalReadPermitsRequired, globalWritePermitsRequired)
 	} else {
 		if len(d1) == 1 && len(d2) == 1 && d1[0] == share.HiddenFedDomain && d2[0] == share.HiddenFedDomain {
 			// d1 & d2 are slice with one entry, share.HiddenFedDomain, if the object requires fed role for access
 			authz = acc.isOneAccessAllowed(AccessDomainGlobal, globalReadPermitsRequired, globalWritePermitsRequired|share.PERM_FED)
 		} else {
 			if acc.op == AccessOPWrite {
-				a1 := acc.isOwnAllowed(d1, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isOneAccessAllowed(AccessDomainGlobal, globalReadPermitsRequired, globalWritePermitsRequired)
-				a2 := acc.isOwnAllowed(d2, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isOneAccessAllowed(AccessDomainGlobal, globalReadPermitsRequired, globalWritePermitsRequired)
+				a1 := acc.isOwnAllowed(d1, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isGlobalAccessAllowed(d1, true, globalReadPermitsRequired, globalWritePermitsRequired)
+				a2 := acc.isOwnAllowed(d2, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isGlobalAccessAllowed(d2, true, globalReadPermitsRequired, globalWritePermitsRequired)
 				authz = a1 && a2
 			} else {
-				a1 := acc.isOwnAllowed(d1, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isOneAccessAllowed(AccessDomainGlobal, globalReadPermitsRequired, globalWritePermitsRequired)
-				a2 := acc.isOwnAllowed(d2, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isOneAccessAllowed(AccessDomainGlobal, globalReadPermitsRequired, globalWritePermitsRequired)
+				a1 := acc.isOwnAllowed(d1, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isGlobalAccessAllowed(d1, true, globalReadPermitsRequired, globalWritePermitsRequired)
+				a2 := acc.isOwnAllowed(d2, domainReadPermitsRequired, domainWritePermitsRequired) || acc.isGlobalAccessAllowed(d2, true, globalReadPermitsRequired, globalWritePermitsRequired)
 				authz = a1 || a2
 			}
 		}
@@ -1600,6 +1865,11 @@ func (acc *AccessControl) GetRoleDomains() map[string][]string{
 	return roleDomains
 }
 
+func IsValidApikeyScope(scope string) bool {
+	_, ok := apikeyScopes[scope]
+	return ok
+}
+
 func ContainsNonSupportRole(role string) bool {
 	var roles = utils.NewSet(api.UserRoleFedAdmin, api.UserRoleFedReader, api.UserRoleIBMSA, api.UserRoleImportStatus)
 	return roles.Contains(role)
@@ -1071,9 +1071,9 @@ func TestSimplePermission(t *testing.T) {
 	}
 
 	readOnlyPermissions := utils.NewSet(share.PERM_AUDIT_EVENTS, share.PERM_SECURITY_EVENTS_BASIC, share.PERM_EVENTS)
-	writeOnlyPermissions := utils.NewSet(share.PERM_CICD_SCAN)
+	writeOnlyPermissions := utils.NewSet(share.PERM_CICD_SCAN, share.PERM_RISK_APPROVAL, share.PERM_POLICY_APPROVAL)
 	globalOnlyPermissions := utils.NewSet(share.PERM_CICD_SCAN, share.PERM_NV_RESOURCE, share.PERM_ADM_CONTROL, share.PERM_AUTHENTICATION, share.PERM_SYSTEM_CONFIG,
-		share.PERM_CLOUD, share.PERM_INFRA_BASIC, share.PERM_VULNERABILITY)
+		share.PERM_CLOUD, share.PERM_INFRA_BASIC, share.PERM_VULNERABILITY, share.PERM_RISK_APPROVAL, share.PERM_POLICY_APPROVAL)
 
 	globalReadPermissions := []uint64{share.PERM_NV_RESOURCE, share.PERM_RUNTIME_SCAN_BASIC, share.PERM_REG_SCAN, share.PERM_NETWORK_POLICY_BASIC, share.PERM_SYSTEM_POLICY_BASIC,
 		share.PERM_GROUP_BASIC, share.PERM_ADM_CONTROL, share.PERM_COMPLIANCE_BASIC, share.PERM_AUDIT_EVENTS, share.PERM_SECURITY_EVENTS_BASIC, share.PERM_EVENTS, share.PERM_AUTHENTICATION,
@@ -1081,7 +1081,8 @@ func TestSimplePermission(t *testing.T) {
 
 	globalWritePermissions := []uint64{share.PERM_NV_RESOURCE, share.PERM_RUNTIME_SCAN_BASIC, share.PERM_REG_SCAN, share.PERM_NETWORK_POLICY_BASIC, share.PERM_SYSTEM_POLICY_BASIC,
 		share.PERM_GROUP_BASIC, share.PERM_ADM_CONTROL, share.PERM_COMPLIANCE_BASIC, share.PERM_AUTHENTICATION, share.PERM_AUTHORIZATION, share.PERM_SYSTEM_CONFIG,
-		share.PERM_CLOUD, share.PERM_WORKLOAD_BASIC, share.PERM_INFRA_BASIC, share.PERM_CICD_SCAN, share.PERM_VULNERABILITY}
+		share.PERM_CLOUD, share.PERM_WORKLOAD_BASIC, share.PERM_INFRA_BASIC, share.PERM_CICD_SCAN, share.PERM_VULNERABILITY, share.PERM_RISK_APPROVAL,
+		share.PERM_POLICY_APPROVAL}
 
 	domainReadPermissions := []uint64{share.PERM_RUNTIME_SCAN_BASIC, share.PERM_REG_SCAN, share.PERM_NETWORK_POLICY_BASIC, share.PERM_SYSTEM_POLICY_BASIC, share.PERM_GROUP_BASIC,
 		share.PERM_COMPLIANCE_BASIC, share.PERM_AUTHORIZATION, share.PERM_SYSTEM_CONFIG, share.PERM_WORKLOAD_BASIC, share.PERM_AUDIT_EVENTS, share.PERM_SECURITY_EVENTS_BASIC, share.PERM_EVENTS}
@@ -1168,6 +1169,7 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 			"v1/debug/dlp/rule",
 			"v1/debug/dlp/mac",
 			"v1/debug/system/stats",
+			"v1/debug/rate_limit",
 			"v1/debug/controller/sync",
 			"v1/debug/workload/intercept",
 			"v1/debug/registry/image/*",
@@ -1229,27 +1231,32 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 		CONST_API_GROUP: []string{
 			"v1/group",
 			"v1/group/*",
+			"v1/groups/automode",
 			"v1/service",
 			"v1/service/*",
 			"v1/file/group",
+			"v1/file/config/crd",
 		},
 		CONST_API_RT_POLICIES: []string{
 			"v1/workload/*/process",
 			"v1/workload/*/process_history",
 			"v1/workload/*/process_profile",
 			"v1/workload/*/file_profile",
+			"v1/workload/*/file_baseline",
 			"v1/dlp/sensor",
 			"v1/dlp/sensor/*",
 			"v1/dlp/group",
 			"v1/dlp/group/*",
 			"v1/dlp/rule",
 			"v1/dlp/rule/*",
+			"v1/dlp/pack",
 			"v1/waf/sensor",
 			"v1/waf/sensor/*",
 			"v1/waf/group",
 			"v1/waf/group/*",
 			"v1/waf/rule",
 			"v1/waf/rule/*",
+			"v1/threat/signature",
 			"v1/policy/rule",
 			"v1/policy/rule/*",
 			"v1/session",
@@ -1258,6 +1265,12 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 			"v1/conversation/*/*",
 			"v1/process_profile",
 			"v1/process_profile/*",
+			"v1/process_profile/*/seccomp",
+			"v1/group/*/profiles/export",
+			"v1/policy/rules/hits",
+			"v1/policy/rules/unused",
+			"v1/policy/changes",
+			"v1/policy/changes/*",
 			"v1/process_rules/*",
 			"v1/file_monitor",
 			"v1/file_monitor/*",
@@ -1284,12 +1297,17 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 			"v1/workload/*/compliance",
 			"v1/bench/host/*/docker",
 			"v1/bench/host/*/kubernetes",
+			"v1/bench/host/*/kubernetes/ckl",
 			"v1/custom_check/*",
 			"v1/custom_check",
 			"v1/compliance/asset",
+			"v1/report/regulatory",
+			"v1/report/regulatory/*",
 			"v1/list/compliance",
 			"v1/compliance/profile",
 			"v1/compliance/profile/*",
+			"v1/compliance/acceptance",
+			"v1/compliance/acceptance/*",
 		},
 		CONST_API_AUDIT_EVENTS: []string{
 			"v1/log/audit",
@@ -1301,10 +1319,14 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 			"v1/log/violation",
 			"v1/log/security",
 			"v1/log/violation/workload",
+			"v1/report/attack-matrix",
 		},
 		CONST_API_EVENTS: []string{
 			"v1/log/event",
+			"v1/log/audit-config",
 			"v1/log/activity",
+			"v1/stream/log",
+			"v1/log/export",
 		},
 		CONST_API_AUTHENTICATION: []string{
 			"v1/server",
@@ -1334,16 +1356,29 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 			"v2/system/config",
 			"v1/system/license",
 			"v1/system/summary",
+			"v1/system/backup",
+			"v1/system/backup/archive",
+			"v1/report/schedule",
+			"v1/report/schedule/*",
+			"v1/report/generated",
+			"v1/report/generated/*",
 			"v1/internal/system",
+			"v1/internal/system/kv",
+			"v1/system/kv_encryption",
 		},
 		CONST_API_FED: []string{
 			"v1/fed/join_token",
 			"v1/fed/cluster/*/**",
 			"v1/fed/view/*",
+			"v1/fed/report",
+			"v1/fed/quarantine",
+			"v1/fed/report/*",
 		},
 		CONST_API_VULNERABILITY: []string{
 			"v1/vulnerability/profile",
 			"v1/vulnerability/profile/*",
+			"v1/vulnerability/acceptance",
+			"v1/vulnerability/acceptance/*",
 		},
 	}
 
@@ -1352,9 +1387,12 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 			"v1/token_auth_server/*",
 			"v1/fed/ping_internal",
 			"v1/fed/joint_test_internal",
+			"v1/fed/failover_internal",
+			"v1/fed/report_internal",
 			"v1/auth",
 			"v1/fed_auth",
 			"v1/auth/*",
+			"v1/recovery_auth",
 			"v1/eula",
 		},
 		CONST_API_DEBUG: []string{
@@ -1363,9 +1401,11 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 			"v1/fed/leave",
 			"v1/fed/remove_internal",
 			"v1/fed/command_internal",
+			"v1/fed/failover",
 			"v1/debug/controller/sync/*",
 			"v1/controller/*/profiling",
 			"v1/enforcer/*/profiling",
+			"v1/workload/*/forensic", // the bundle has the environment of the container
 			"v1/file/config",
 			"v1/csp/file/support",
 		},
@@ -1385,6 +1425,9 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 			"v1/scan/result/repository",
 			"v1/scan/repository",
 		},
+		CONST_API_WORKLOAD: []string{
+			"v1/graphql", // read-only, each queried field is authorized separately
+		},
 		CONST_API_GROUP: []string{
 			"v1/group",
 			"v1/file/group", // export group
@@ -1394,12 +1437,19 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 			"v1/workload/request/*",
 			"v1/dlp/sensor",
 			"v1/waf/sensor",
+			"v1/waf/modsec",
+			"v1/threat/signature/bundle",
 			"v1/file/dlp",
 			"v1/file/dlp/config",
 			"v1/file/waf",
 			"v1/file/waf/config",
+			"v1/file/network_policy", // export network policy
 			"v1/system/request",
 			"v1/sniffer",
+			"v1/policy/changes",
+			"v1/policy/simulate",
+			"v1/workload/*/pcap",
+			"v1/workload/*/file_baseline",
 			"v1/file/group/config", // for providing similar function as crd import but do not rely on crd webhook
 		},
 		CONST_API_ADM_CONTROL: []string{
@@ -1412,6 +1462,7 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 		CONST_API_COMPLIANCE: []string{
 			"v1/bench/host/*/docker",
 			"v1/bench/host/*/kubernetes",
+			"v1/compliance/acceptance",
 		},
 		CONST_API_AUTHENTICATION: []string{
 			"v1/server",
@@ -1420,6 +1471,8 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 		CONST_API_AUTHORIZATION: []string{
 			"v1/user_role",
 			"v1/user",
+			"v1/user/*/mfa",
+			"v1/user/*/mfa/verify",
 			"v1/api_key",
 		},
 		CONST_API_PWD_PROFILE: []string{
@@ -1429,6 +1482,11 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 		CONST_API_SYSTEM_CONFIG: []string{
 			"v1/system/license/update",
 			"v1/system/config/webhook",
+			"v1/system/backup",
+			"v1/system/backup/restore",
+			"v1/report/schedule",
+			"v1/report/schedule/*/run",
+			"v1/system/kv_encryption/rotate",
 		},
 		CONST_API_IBMSA: []string{
 			"v1/partner/ibm_sa/*/setup/*",
@@ -1436,12 +1494,25 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 		CONST_API_FED: []string{
 			"v1/fed/demote",
 			"v1/fed/deploy",
+			"v1/fed/standby",
+			"v1/fed/quarantine",
 			"v1/fed/cluster/*/**",
 			"v1/policy/rules/promote",
 			"v1/admission/rule/promote",
 		},
 		CONST_API_VULNERABILITY: []string{
 			"v1/vulnerability/profile/*/entry",
+			"v1/vulnerability/acceptance",
+		},
+		CONST_API_RISK_APPROVAL: []string{
+			"v1/vulnerability/acceptance/*/approve",
+			"v1/vulnerability/acceptance/*/reject",
+			"v1/compliance/acceptance/*/approve",
+			"v1/compliance/acceptance/*/reject",
+		},
+		CONST_API_POLICY_APPROVAL: []string{
+			"v1/policy/changes/*/approve",
+			"v1/policy/changes/*/reject",
 		},
 	}
 
@@ -1470,6 +1541,7 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 		},
 		CONST_API_GROUP: []string{
 			"v1/group/*",
+			"v1/groups/batch",
 			"v1/service/config",
 			"v1/service/config/network",
 			"v1/service/config/profile",
@@ -1480,8 +1552,10 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 			"v1/dlp/group/*",
 			"v1/waf/sensor/*",
 			"v1/waf/group/*",
+			"v1/threat/signature/*",
 			"v1/policy/rule",
 			"v1/policy/rule/*",
+			"v1/policy/rules/batch",
 			"v1/conversation_endpoint/*",
 			"v1/process_profile/*",
 			"v1/file_monitor/*",
@@ -1516,6 +1590,8 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 			"v1/system/config",
 			"v2/system/config",
 			"v1/system/config/webhook/*",
+			"v1/system/backup",
+			"v1/report/schedule/*",
 		},
 		CONST_API_FED: []string{
 			"v1/fed/cluster/*/**",
@@ -1552,6 +1628,7 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 			"v1/waf/sensor/*",
 			"v1/policy/rule/*",
 			"v1/policy/rule",
+			"v1/policy/changes/*",
 			"v1/conversation/*/*",
 			"v1/response/rule/*",
 			"v1/response/rule",
@@ -1563,6 +1640,7 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 		},
 		CONST_API_COMPLIANCE: []string{
 			"v1/compliance/profile/*/entry/*",
+			"v1/compliance/acceptance/*",
 		},
 		CONST_API_AUTHENTICATION: []string{
 			"v1/server/*",
@@ -1570,6 +1648,7 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 		CONST_API_AUTHORIZATION: []string{
 			"v1/user_role/*",
 			"v1/user/*",
+			"v1/user/*/mfa",
 			"v1/api_key/*",
 		},
 		CONST_API_PWD_PROFILE: []string{
@@ -1578,13 +1657,17 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 		CONST_API_SYSTEM_CONFIG: []string{
 			"v1/system/license",
 			"v1/system/config/webhook/*",
+			"v1/report/schedule/*",
+			"v1/report/generated/*",
 		},
 		CONST_API_FED: []string{
 			"v1/fed/cluster/*",
 			"v1/fed/cluster/*/**",
+			"v1/fed/quarantine/*",
 		},
 		CONST_API_VULNERABILITY: []string{
 			"v1/vulnerability/profile/*/entry/*",
+			"v1/vulnerability/acceptance/*",
 		},
 	}
 
@@ -1615,3 +1698,303 @@ func TestCompileApiUrisMappingMapping(t *testing.T) {
 
 	postTest()
 }
+
+func TestWildcardUriMapping(t *testing.T) {
+	preTest()
+
+	tests := []struct {
+		uri   string
+		apiID int8
+	}{
+		{"v1/workload/request/12345", CONST_API_RT_POLICIES},
+		{"v1/workload/12345/pcap", CONST_API_RT_POLICIES},
+		{"v1/workload/12345/file_baseline", CONST_API_RT_POLICIES},
+		{"v1/workload/12345/forensic", CONST_API_DEBUG},
+		{"v1/workload/request/pcap", CONST_API_RT_POLICIES},
+		{"v1/workload/12345/unknown", CONST_API_UNKNOWN},
+		{"v1/workload/12345/pcap/unknown", CONST_API_UNKNOWN},
+	}
+	for _, test := range tests {
+		r, _ := http.NewRequest(http.MethodPost, "https://10.1.1.1/"+test.uri, nil)
+		if apiCategoryID, _ := getRequiredPermissions(r); apiCategoryID != test.apiID {
+			t.Errorf("got wrong api category id for uri(%s): expected=%v, got=%v", test.uri, test.apiID, apiCategoryID)
+		}
+	}
+
+	postTest()
+}
+
+func TestApikeyScopes(t *testing.T) {
+	preTest()
+
+	roles := DomainRole{"": api.UserRoleAdmin}
+	cases := []struct {
+		method string
+		uri    string
+		scopes []string
+		allow  bool
+	}{
+		{http.MethodGet, "v1/group", nil, true},
+		{http.MethodGet, "v1/eula", []string{api.ApikeyScopeScanRead}, true},
+		{http.MethodGet, "v1/scan/registry", []string{api.ApikeyScopeScanRead}, true},
+		{http.MethodPost, "v1/scan/registry", []string{api.ApikeyScopeScanRead}, false},
+		{http.MethodGet, "v1/group", []string{api.ApikeyScopeScanRead}, false},
+		{http.MethodGet, "v1/group", []string{api.ApikeyScopePolicyWrite}, true},
+		{http.MethodPatch, "v1/group/g1", []string{api.ApikeyScopePolicyWrite}, true},
+		{http.MethodPatch, "v1/group/g1", []string{api.ApikeyScopeScanRead, api.ApikeyScopePolicyWrite}, true},
+		{http.MethodPatch, "v1/system/config", []string{api.ApikeyScopePolicyWrite}, false},
+		{http.MethodPatch, "v1/system/config", []string{api.ApikeyScopeFedAdmin}, true},
+		{http.MethodGet, "v1/unknown_api", []string{api.ApikeyScopeFedAdmin}, false},
+	}
+	for _, c := range cases {
+		r, _ := http.NewRequest(c.method, "https://10.1.1.1/"+c.uri, nil)
+		var op AccessOP = AccessOPWrite
+		if c.method == http.MethodGet {
+			op = AccessOPRead
+		}
+		acc := NewAccessControl(r, op, roles)
+		if allow := acc.IsAllowedByApikeyScopes(c.scopes); allow != c.allow {
+			t.Errorf("Unexpected scope check: %s %s, scopes=%v, allow=%v", c.method, c.uri, c.scopes, allow)
+		}
+	}
+
+	if !IsValidApikeyScope(api.ApikeyScopeScanRead) || IsValidApikeyScope("scan:write") {
+		t.Errorf("Unexpected apikey scope validation")
+	}
+
+	postTest()
+}
+
+func TestDomainScopedRoleAccess(t *testing.T) {
+	preTest()
+
+	role := &share.CLUSUserRoleInternal{
+		Name:         "role-scoped",
+		ReadPermits:  share.PERMS_RUNTIME_POLICIES | share.PERM_REG_SCAN,
+		WritePermits: share.PERMS_RUNTIME_POLICIES,
+		DomainScopes: []*share.CLUSRoleDomainScope{
+			&share.CLUSRoleDomainScope{
+				ReadPermits:  share.PERMS_RUNTIME_POLICIES,
+				WritePermits: share.PERMS_RUNTIME_POLICIES,
+				Domains:      []string{"ns1", "team-*"},
+			},
+		},
+	}
+	AddRole(role.Name, role)
+
+	readReq, _ := http.NewRequest(http.MethodGet, "https://10.1.1.1/v1/group/12345", nil)
+	writeReq, _ := http.NewRequest(http.MethodPatch, "https://10.1.1.1/v1/group/12345", nil)
+	for _, op := range []AccessOP{AccessOPRead, AccessOPWrite} {
+		req := readReq
+		if op == AccessOPWrite {
+			req = writeReq
+		}
+		acc := NewAccessControl(req, op, DomainRole{AccessDomainGlobal: role.Name})
+		if !acc.HasRequiredPermissions() {
+			t.Errorf("Required permissions are not met: op=%v", op)
+		}
+		for _, domains := range [][]string{[]string{"ns1"}, []string{"team-a"}, []string{"ns1", "ns2"}} {
+			if !acc.Authorize(&domainObjectTest{CreatorDomains: domains}, nil) {
+				t.Errorf("Authz fail: op=%v, domains=%+v", op, domains)
+			}
+		}
+		for _, domains := range [][]string{[]string{"ns2"}, []string{"team"}} {
+			if acc.Authorize(&domainObjectTest{CreatorDomains: domains}, nil) {
+				t.Errorf("Authz success: op=%v, domains=%+v", op, domains)
+			}
+		}
+		if acc.Authorize(&globalObject{}, nil) {
+			t.Errorf("Authz success on global object: op=%v", op)
+		}
+		if acc.AuthorizeOwn(&ownObject{members: []string{"ns1", "ns2"}}, nil) {
+			t.Errorf("Own authz success: op=%v", op)
+		}
+		if !acc.AuthorizeOwn(&ownObject{members: []string{"ns1", "team-b"}}, nil) {
+			t.Errorf("Own authz fail: op=%v", op)
+		}
+	}
+
+	acc := NewAccessControl(writeReq, AccessOPWrite, DomainRole{AccessDomainGlobal: role.Name})
+	if acc.HasGlobalPermissions(share.PERMS_RUNTIME_POLICIES, 0) {
+		t.Errorf("Restricted permissions should not be global")
+	}
+	if !acc.HasGlobalPermissions(share.PERM_REG_SCAN, 0) {
+		t.Errorf("Unrestricted permissions should be global")
+	}
+	if domains := acc.GetAdminDomains(share.PERMS_RUNTIME_POLICIES); len(domains) != 2 || domains[0] != "ns1" || domains[1] != "team-*" {
+		t.Errorf("Unexpected admin domains: %+v", domains)
+	}
+
+	// Domain role is restricted as well
+	acc = NewAccessControl(readReq, AccessOPRead, DomainRole{"ns1": role.Name, "ns2": role.Name})
+	if !acc.Authorize(&domainObjectTest{CreatorDomains: []string{"ns1"}}, nil) {
+		t.Errorf("Authz fail in scope domain")
+	}
+	if acc.Authorize(&domainObjectTest{CreatorDomains: []string{"ns2"}}, nil) {
+		t.Errorf("Authz success out of scope domain")
+	}
+
+	DeleteRole(role.Name)
+	postTest()
+}
+
+func TestRoleOutScopePermits(t *testing.T) {
+	preTest()
+
+	// workload basic permission is shared by both scopes
+	role := &share.CLUSUserRoleInternal{
+		Name:         "role-out-scope",
+		ReadPermits:  share.PERMS_RUNTIME_SCAN | share.PERMS_RUNTIME_POLICIES | share.PERM_REG_SCAN,
+		WritePermits: share.PERMS_RUNTIME_SCAN | share.PERMS_RUNTIME_POLICIES,
+		DomainScopes: []*share.CLUSRoleDomainScope{
+			&share.CLUSRoleDomainScope{
+				ID:           share.PERMS_RUNTIME_SCAN_ID,
+				ReadPermits:  share.PERMS_RUNTIME_SCAN,
+				WritePermits: share.PERMS_RUNTIME_SCAN,
+				Domains:      []string{"ns1"},
+			},
+			&share.CLUSRoleDomainScope{
+				ID:           share.PERMS_RUNTIME_POLICIES_ID,
+				ReadPermits:  share.PERMS_RUNTIME_POLICIES,
+				WritePermits: share.PERMS_RUNTIME_POLICIES,
+				Domains:      []string{"team-*"},
+			},
+		},
+	}
+	AddRole(role.Name, role)
+
+	var all uint64 = share.PERMS_RUNTIME_SCAN | share.PERMS_RUNTIME_POLICIES
+	tests := []struct {
+		domains []string
+		own     bool
+		out     uint64
+	}{
+		{nil, false, all},
+		{[]string{}, true, all},
+		{[]string{"ns2"}, false, all},
+		{[]string{"ns1"}, false, share.PERMS_RUNTIME_POLICIES &^ share.PERMS_RUNTIME_SCAN},
+		{[]string{"team-a"}, false, share.PERMS_RUNTIME_SCAN &^ share.PERMS_RUNTIME_POLICIES},
+		{[]string{"ns1", "team-a"}, false, 0},
+		{[]string{"ns1", "ns2"}, false, share.PERMS_RUNTIME_POLICIES &^ share.PERMS_RUNTIME_SCAN},
+		{[]string{"ns1", "ns2"}, true, all},
+		{[]string{"team-a", "team-b"}, true, share.PERMS_RUNTIME_SCAN &^ share.PERMS_RUNTIME_POLICIES},
+	}
+	for _, test := range tests {
+		outRead, outWrite := getRoleOutScopePermits(role.Name, test.domains, test.own)
+		if outRead != test.out || outWrite != test.out {
+			t.Errorf("Unexpected out scope permits: domains=%+v own=%v read=0x%x write=0x%x, expect=0x%x",
+				test.domains, test.own, outRead, outWrite, test.out)
+		}
+	}
+
+	// unknown role and role without scope are not restricted
+	if outRead, outWrite := getRoleOutScopePermits("role-unknown", nil, false); outRead != 0 || outWrite != 0 {
+		t.Errorf("Unexpected out scope permits of unknown role: read=0x%x write=0x%x", outRead, outWrite)
+	}
+	if outRead, outWrite := getRoleOutScopePermits(api.UserRoleAdmin, nil, false); outRead != 0 || outWrite != 0 {
+		t.Errorf("Unexpected out scope permits of admin: read=0x%x write=0x%x", outRead, outWrite)
+	}
+
+	DeleteRole(role.Name)
+	postTest()
+}
+
+func TestAdminDomains(t *testing.T) {
+	preTest()
+
+	role := &share.CLUSUserRoleInternal{
+		Name:         "role-admin-domains",
+		ReadPermits:  share.PERMS_RUNTIME_SCAN | share.PERMS_RUNTIME_POLICIES | share.PERM_REG_SCAN,
+		WritePermits: share.PERMS_RUNTIME_SCAN | share.PERMS_RUNTIME_POLICIES | share.PERM_REG_SCAN,
+		DomainScopes: []*share.CLUSRoleDomainScope{
+			&share.CLUSRoleDomainScope{
+				ID:           share.PERMS_RUNTIME_SCAN_ID,
+				ReadPermits:  share.PERMS_RUNTIME_SCAN,
+				WritePermits: share.PERMS_RUNTIME_SCAN,
+				Domains:      []string{"ns1"},
+			},
+			&share.CLUSRoleDomainScope{
+				ID:           share.PERMS_RUNTIME_POLICIES_ID,
+				ReadPermits:  share.PERMS_RUNTIME_POLICIES,
+				WritePermits: share.PERMS_RUNTIME_POLICIES,
+				Domains:      []string{"ns1", "ns2"},
+			},
+		},
+	}
+	AddRole(role.Name, role)
+
+	req, _ := http.NewRequest(http.MethodPost, "https://10.1.1.1/v1/group", nil)
+
+	// unrestricted permissions are not limited to any domain
+	acc := NewAccessControl(req, AccessOPWrite, DomainRole{AccessDomainGlobal: role.Name})
+	if domains := acc.GetAdminDomains(share.PERM_REG_SCAN); domains != nil {
+		t.Errorf("Unexpected admin domains of unrestricted permission: %+v", domains)
+	}
+	if domains := acc.GetAdminDomains(share.PERMS_RUNTIME_POLICIES); len(domains) != 2 || domains[0] != "ns1" || domains[1] != "ns2" {
+		t.Errorf("Unexpected admin domains: %+v", domains)
+	}
+	// all the required permissions are only in ns1
+	if domains := acc.GetAdminDomains(share.PERMS_RUNTIME_POLICIES | share.PERMS_RUNTIME_SCAN); len(domains) != 1 || domains[0] != "ns1" {
+		t.Errorf("Unexpected admin domains of combined permissions: %+v", domains)
+	}
+	if domains := acc.GetAdminDomains(share.PERM_FED); domains != nil {
+		t.Errorf("Unexpected admin domains of missing permission: %+v", domains)
+	}
+
+	// the scope is applied to domain roles
+	acc = NewAccessControl(req, AccessOPWrite, DomainRole{"ns2": role.Name, "ns3": role.Name})
+	if domains := acc.GetAdminDomains(share.PERMS_RUNTIME_POLICIES); len(domains) != 1 || domains[0] != "ns2" {
+		t.Errorf("Unexpected admin domains of domain roles: %+v", domains)
+	}
+
+	acc = NewAccessControl(req, AccessOPWrite, DomainRole{AccessDomainGlobal: api.UserRoleAdmin})
+	if domains := acc.GetAdminDomains(share.PERMS_RUNTIME_POLICIES); domains != nil {
+		t.Errorf("Unexpected admin domains of admin: %+v", domains)
+	}
+
+	DeleteRole(role.Name)
+	postTest()
+}
+
+func TestUserRoleScopeDomains(t *testing.T) {
+	preTest()
+
+	// both permissions have the workload basic permission, the domains are matched by the permission id
+	role := &share.CLUSUserRoleInternal{
+		Name:         "role-rest-scope",
+		ReadPermits:  share.PERMS_RUNTIME_SCAN | share.PERMS_RUNTIME_POLICIES | share.PERM_REG_SCAN,
+		WritePermits: share.PERMS_RUNTIME_SCAN | share.PERMS_RUNTIME_POLICIES,
+		DomainScopes: []*share.CLUSRoleDomainScope{
+			&share.CLUSRoleDomainScope{
+				ID:           share.PERMS_RUNTIME_SCAN_ID,
+				ReadPermits:  share.PERMS_RUNTIME_SCAN,
+				WritePermits: share.PERMS_RUNTIME_SCAN,
+				Domains:      []string{"ns1"},
+			},
+			&share.CLUSRoleDomainScope{
+				ID:           share.PERMS_RUNTIME_POLICIES_ID,
+				ReadPermits:  share.PERMS_RUNTIME_POLICIES,
+				WritePermits: share.PERMS_RUNTIME_POLICIES,
+				Domains:      []string{"team-*"},
+			},
+		},
+	}
+
+	expect := map[string][]string{
+		share.PERMS_RUNTIME_SCAN_ID:     []string{"ns1"},
+		share.PERMS_RUNTIME_POLICIES_ID: []string{"team-*"},
+		share.PERM_REG_SCAN_ID:          nil,
+	}
+	rrole := clusUserRoleToREST(role.Name, role)
+	if len(rrole.Permissions) != len(expect) {
+		t.Errorf("Unexpected permissions: %+v", rrole.Permissions)
+	}
+	for _, p := range rrole.Permissions {
+		domains, ok := expect[p.ID]
+		if !ok || len(p.Domains) != len(domains) || (len(domains) > 0 && p.Domains[0] != domains[0]) {
+			t.Errorf("Unexpected permission domains: id=%v domains=%+v, expect=%+v", p.ID, p.Domains, domains)
+		}
+	}
+
+	postTest()
+}
@@ -11,9 +11,12 @@ const RESTNvPageHeader string = "X-Nv-Page"
 const RESTRancherTokenHeader string = "X-R-Sess"
 const RESTMaskedValue string = "The value is masked"
 const RESTAPIKeyHeader string = "X-Auth-Apikey"
+const RESTDegradedHeader string = "X-Nv-Degraded"
 
 const RESTNvPageDashboard string = "dashboard"
 
+const DegradedKvQuorumLost string = "kv_quorum_lost"
+
 // Don't modify value or reorder
 const RESTErrNotFound int = 1
 const RESTErrMethodNotAllowed int = 2
@@ -66,6 +69,10 @@ const RESTErrPasswordExpired int = 48
 const RESTErrPromoteFail int = 49
 const RESTErrPlatformAuthDisabled int = 50
 const RESTErrRancherUnauthorized int = 51
+const RESTErrTooManyRequests int = 52
+const RESTErrMFARequired int = 53
+const RESTErrMFAEnrollRequired int = 54
+const RESTErrKvUnavailable int = 55
 
 const FilterPrefix string = "f_"
 const SortPrefix string = "s_"
@@ -76,6 +83,7 @@ const SupportFlag string = "support"
 const BriefFlag string = "brief"
 const VerboseFlag string = "verbose"
 const RawFlag string = "raw"
+const DryRunFlag string = "dry_run"
 const WithCapFlag string = "with_cap"
 const FilterServerCategory string = "category"
 const FilterServerType string = "type"
@@ -87,6 +95,17 @@ const QueryKeyShow string = "show"
 const QueryValueShowAccepted string = "accepted"
 const QueryScope string = "scope"
 const QueryDuration string = "token_duration"
+const QueryKeyProfileType string = "type"
+const QueryValueProfileAppArmor string = "apparmor"
+const QueryValueProfileSELinux string = "selinux"
+const QueryKeyGroups string = "groups"
+const QueryKeyTypes string = "types"
+const QueryKeyLogType string = "type"
+const QueryKeyStart string = "start" // unix time in seconds
+const QueryKeyEnd string = "end"     // unix time in seconds
+const QueryKeyLimit string = "limit"
+const QueryKeyRuleType string = "type"
+const QueryKeyDays string = "days"
 
 const OPeq string = "eq"
 const OPneq string = "neq"
@@ -146,6 +165,7 @@ const DomainImages string = "_images"
 
 const AuthServerLocal string = "local"
 const AuthServerPlatform string = "_platform_"
+const AuthServerRancher string = "_rancher_"
 
 const (
 	ServerCatAuth   string = "auth"
@@ -218,12 +238,14 @@ const UserIdleTimeoutMin uint32 = 30
 type RESTAuthPassword struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	MFACode  string `json:"mfa_code,omitempty"` // TOTP code or backup code, for local users who enrolled MFA
 }
 
 type RESTAuthToken struct {
-	Token    string `json:"token"`
-	State    string `json:"state"`
-	Redirect string `json:"redirect_endpoint"`
+	Token        string `json:"token"`
+	State        string `json:"state"`
+	Redirect     string `json:"redirect_endpoint"`
+	RefreshToken string `json:"refresh_token,omitempty"` // OpenID Connect only. Login with refresh token instead of code
 }
 
 type RESTAuthData struct {
@@ -252,8 +274,10 @@ type RESTToken struct {
 
 type RESTTokenData struct {
 	Token               *RESTToken `json:"token"`
-	PwdDaysUntilExpire  int        `json:"password_days_until_expire"`  // negative means we don't know it (for ldap/saml/oidc login).
-	PwdHoursUntilExpire int        `json:"password_hours_until_expire"` // the hours part beyond PwdDaysUntilExpire, 0 ~ 23
+	RefreshToken        string     `json:"refresh_token,omitempty"`       // refresh token issued by OpenID Connect server
+	MFAEnrollRequired   bool       `json:"mfa_enroll_required,omitempty"` // the session can only be used to enroll MFA
+	PwdDaysUntilExpire  int        `json:"password_days_until_expire"`    // negative means we don't know it (for ldap/saml/oidc login).
+	PwdHoursUntilExpire int        `json:"password_hours_until_expire"`   // the hours part beyond PwdDaysUntilExpire, 0 ~ 23
 	// If both PwdDaysUntilExpire/PwdDaysUntilExpire are 0, it means the password is already expired
 }
 
@@ -357,6 +381,8 @@ type RESTServerOIDC struct {
 	ClientSecret string   `json:"client_secret,cloak"`
 	GroupClaim   string   `json:"group_claim"`
 	Scopes       []string `json:"scopes"`
+	UsePKCE      bool     `json:"use_pkce"`
+	RefreshLogin bool     `json:"refresh_login"`
 
 	Enable           bool                      `json:"enable"`
 	DefaultRole      string                    `json:"default_role"`
@@ -433,6 +459,8 @@ type RESTServerOIDCConfig struct {
 	ClientSecret *string   `json:"client_secret,cloak"`
 	GroupClaim   *string   `json:"group_claim"`
 	Scopes       *[]string `json:"scopes,omitempty"`
+	UsePKCE      *bool     `json:"use_pkce,omitempty"`
+	RefreshLogin *bool     `json:"refresh_login,omitempty"`
 
 	Enable           *bool                      `json:"enable"`
 	DefaultRole      *string                    `json:"default_role"`
@@ -502,6 +530,19 @@ type RESTGroupExport struct {
 	PolicyMode string   `json:"policy_mode,omitempty"`
 }
 
+const (
+	NetPolicyExportKubernetes = "kubernetes"
+	NetPolicyExportCilium     = "cilium"
+	NetPolicyExportCalico     = "calico"
+)
+
+type RESTNetPolicyExport struct {
+	Groups      []string `json:"groups"`
+	Namespace   string   `json:"namespace,omitempty"` // export all container groups in the namespace when groups is empty
+	Format      string   `json:"format,omitempty"`    // kubernetes(default) / cilium / calico
+	LearnedOnly bool     `json:"learned_only,omitempty"`
+}
+
 type RESTAdmCtrlRulesExport struct {
 	ExportConfig bool     `json:"export_config"`
 	IDs          []uint32 `json:"ids"` // used when ExportRules is true
@@ -528,6 +569,7 @@ type RESTUser struct {
 	LoginCount            uint32              `json:"login_count"`
 	BlockedForFailedLogin bool                `json:"blocked_for_failed_login"`     // if the user is blocked for too mnay failed login
 	BlockedForPwdExpired  bool                `json:"blocked_for_password_expired"` // if the user is blocked for expired password
+	MFAEnabled            bool                `json:"mfa_enabled"`
 }
 
 type RESTUserConfig struct {
@@ -579,23 +621,63 @@ type RESTUserPwdConfigData struct {
 	Config *RESTUserPwdConfig `json:"config"`
 }
 
+type RESTRecoveryAuth struct {
+	Token       string `json:"token,cloak"`
+	NewPassword string `json:"new_password,cloak"`
+}
+
+type RESTRecoveryAuthData struct {
+	Recovery *RESTRecoveryAuth `json:"recovery"`
+}
+
+type RESTUserMFAEnroll struct {
+	Secret string `json:"secret"` // base32 encoded TOTP secret
+	URI    string `json:"uri"`    // otpauth URI for authenticator apps
+}
+
+type RESTUserMFAEnrollData struct {
+	MFA *RESTUserMFAEnroll `json:"mfa"`
+}
+
+type RESTUserMFAVerify struct {
+	Code string `json:"code,cloak"`
+}
+
+type RESTUserMFAVerifyData struct {
+	Config *RESTUserMFAVerify `json:"config"`
+}
+
+type RESTUserMFABackupCodesData struct {
+	BackupCodes []string `json:"backup_codes"`
+}
+
 // password profile
 type RESTPwdProfile struct {
-	Name                        string `json:"name"`
-	Comment                     string `json:"comment"`
-	MinLen                      int    `json:"min_len"`
-	MinUpperCount               int    `json:"min_uppercase_count"` // for alphabet characters
-	MinLowerCount               int    `json:"min_lowercase_count"` // for alphabet characters
-	MinDigitCount               int    `json:"min_digit_count"`
-	MinSpecialCount             int    `json:"min_special_count"`
-	EnablePwdExpiration         bool   `json:"enable_password_expiration"`
-	PwdExpireAfterDays          int    `json:"password_expire_after_days"` // must be > 0 when EnablePwdExpiration is true
-	EnablePwdHistory            bool   `json:"enable_password_history"`
-	PwdHistoryCount             int    `json:"password_keep_history_count"`
-	EnableBlockAfterFailedLogin bool   `json:"enable_block_after_failed_login"` // for "Block X minutes after N times consecutive failed attempts"
-	BlockAfterFailedCount       int    `json:"block_after_failed_login_count"`  // must be > 0 when EnableBlockAfterFailedLogin is true
-	BlockMinutes                int    `json:"block_minutes"`                   // must be > 0 when EnableBlockAfterFailedLogin is true
-	SessionTimeout              uint32 `json:"session_timeout"`                 // for default user session timeout (in seconds)
+	Name                        string              `json:"name"`
+	Comment                     string              `json:"comment"`
+	MinLen                      int                 `json:"min_len"`
+	MinUpperCount               int                 `json:"min_uppercase_count"` // for alphabet characters
+	MinLowerCount               int                 `json:"min_lowercase_count"` // for alphabet characters
+	MinDigitCount               int                 `json:"min_digit_count"`
+	MinSpecialCount             int                 `json:"min_special_count"`
+	EnablePwdExpiration         bool                `json:"enable_password_expiration"`
+	PwdExpireAfterDays          int                 `json:"password_expire_after_days"` // must be > 0 when EnablePwdExpiration is true
+	EnablePwdHistory            bool                `json:"enable_password_history"`
+	PwdHistoryCount             int                 `json:"password_keep_history_count"`
+	EnableBlockAfterFailedLogin bool                `json:"enable_block_after_failed_login"` // for "Block X minutes after N times consecutive failed attempts"
+	BlockAfterFailedCount       int                 `json:"block_after_failed_login_count"`  // must be > 0 when EnableBlockAfterFailedLogin is true
+	BlockMinutes                int                 `json:"block_minutes"`                   // must be > 0 when EnableBlockAfterFailedLogin is true
+	SessionTimeout              uint32              `json:"session_timeout"`                 // for default user session timeout (in seconds)
+	MFARequiredRoles            []string            `json:"mfa_required_roles"`              // local users of these global roles must enroll MFA
+	SessionPolicies             []RESTSessionPolicy `json:"session_policies"`
+}
+
+// 0 means no limit
+type RESTSessionPolicy struct {
+	Role        string `json:"role"`
+	IdleTimeout uint32 `json:"idle_timeout"` // in seconds
+	MaxLifetime uint32 `json:"max_lifetime"` // in seconds
+	MaxSessions int    `json:"max_sessions"` // maximum concurrent sessions of a user
 }
 
 type RESTPwdProfileBasic struct {
@@ -607,22 +689,24 @@ type RESTPwdProfileBasic struct {
 }
 
 type RESTPwdProfileConfig struct {
-	Name                        string  `json:"name"`
-	Active                      *bool   `json:"active,omitempty"`
-	Comment                     *string `json:"comment,omitempty"`
-	MinLen                      *int    `json:"min_len,omitempty"`
-	MinUpperCount               *int    `json:"min_uppercase_count,omitempty"` // for alphabet characters
-	MinLowerCount               *int    `json:"min_lowercase_count,omitempty"` // for alphabet characters
-	MinDigitCount               *int    `json:"min_digit_count,omitempty"`     // for 0 ~ 9
-	MinSpecialCount             *int    `json:"min_special_count,omitempty"`   // !”#$%&'()*+,-./:;<=>?@[\]^_`{|}~
-	EnablePwdExpiration         *bool   `json:"enable_password_expiration,omitempty"`
-	PwdExpireAfterDays          *int    `json:"password_expire_after_days,omitempty"` // must be > 0 when EnablePwdExpiration is true
-	EnablePwdHistory            *bool   `json:"enable_password_history,omitempty"`
-	PwdHistoryCount             *int    `json:"password_keep_history_count,omitempty"`
-	EnableBlockAfterFailedLogin *bool   `json:"enable_block_after_failed_login,omitempty"` // for "Block X minutes after N times consecutive failed attempts"
-	BlockAfterFailedCount       *int    `json:"block_after_failed_login_count,omitempty"`  // must be > 0 when EnableBlockAfterFailedLogin is true
-	BlockMinutes                *int    `json:"block_minutes,omitempty"`                   // must be > 0 when EnableBlockAfterFailedLogin is true
-	SessionTimeout              *uint32 `json:"session_timeout,omitempty"`                 // for default user session timeout (in seconds)
+	Name                        string               `json:"name"`
+	Active                      *bool                `json:"active,omitempty"`
+	Comment                     *string              `json:"comment,omitempty"`
+	MinLen                      *int                 `json:"min_len,omitempty"`
+	MinUpperCount               *int                 `json:"min_uppercase_count,omitempty"` // for alphabet characters
+	MinLowerCount               *int                 `json:"min_lowercase_count,omitempty"` // for alphabet characters
+	MinDigitCount               *int                 `json:"min_digit_count,omitempty"`     // for 0 ~ 9
+	MinSpecialCount             *int                 `json:"min_special_count,omitempty"`   // !”#$%&'()*+,-./:;<=>?@[\]^_`{|}~
+	EnablePwdExpiration         *bool                `json:"enable_password_expiration,omitempty"`
+	PwdExpireAfterDays          *int                 `json:"password_expire_after_days,omitempty"` // must be > 0 when EnablePwdExpiration is true
+	EnablePwdHistory            *bool                `json:"enable_password_history,omitempty"`
+	PwdHistoryCount             *int                 `json:"password_keep_history_count,omitempty"`
+	EnableBlockAfterFailedLogin *bool                `json:"enable_block_after_failed_login,omitempty"` // for "Block X minutes after N times consecutive failed attempts"
+	BlockAfterFailedCount       *int                 `json:"block_after_failed_login_count,omitempty"`  // must be > 0 when EnableBlockAfterFailedLogin is true
+	BlockMinutes                *int                 `json:"block_minutes,omitempty"`                   // must be > 0 when EnableBlockAfterFailedLogin is true
+	SessionTimeout              *uint32              `json:"session_timeout,omitempty"`                 // for default user session timeout (in seconds)
+	MFARequiredRoles            *[]string            `json:"mfa_required_roles,omitempty"`              // local users of these global roles must enroll MFA
+	SessionPolicies             *[]RESTSessionPolicy `json:"session_policies,omitempty"`
 }
 
 type RESTPwdProfilesData struct {
@@ -640,21 +724,23 @@ type RESTPwdProfileData struct {
 }
 
 type RESTPwdProfileConditional struct {
-	Name                        *string `json:"name,omitempty"`
-	Comment                     *string `json:"comment,omitempty"`
-	MinLen                      int     `json:"min_len"`
-	MinUpperCount               int     `json:"min_uppercase_count"` // for alphabet characters
-	MinLowerCount               int     `json:"min_lowercase_count"` // for alphabet characters
-	MinDigitCount               int     `json:"min_digit_count"`
-	MinSpecialCount             int     `json:"min_special_count"`
-	EnablePwdExpiration         *bool   `json:"enable_password_expiration,omitempty"`
-	PwdExpireAfterDays          *int    `json:"password_expire_after_days,omitempty"` // must be > 0 when EnablePwdExpiration is true
-	EnablePwdHistory            *bool   `json:"enable_password_history,omitempty"`
-	PwdHistoryCount             *int    `json:"password_keep_history_count,omitempty"`
-	EnableBlockAfterFailedLogin *bool   `json:"enable_block_after_failed_login,omitempty"` // for "Block X minutes after N times consecutive failed attempts"
-	BlockAfterFailedCount       *int    `json:"block_after_failed_login_count,omitempty"`  // must be > 0 when EnableBlockAfterFailedLogin is true
-	BlockMinutes                *int    `json:"block_minutes,omitempty"`                   // must be > 0 when EnableBlockAfterFailedLogin is true
-	SessionTimeout              *uint32 `json:"session_timeout,omitempty"`                 // for default user session timeout (in seconds)
+	Name                        *string             `json:"name,omitempty"`
+	Comment                     *string             `json:"comment,omitempty"`
+	MinLen                      int                 `json:"min_len"`
+	MinUpperCount               int                 `json:"min_uppercase_count"` // for alphabet characters
+	MinLowerCount               int                 `json:"min_lowercase_count"` // for alphabet characters
+	MinDigitCount               int                 `json:"min_digit_count"`
+	MinSpecialCount             int                 `json:"min_special_count"`
+	EnablePwdExpiration         *bool               `json:"enable_password_expiration,omitempty"`
+	PwdExpireAfterDays          *int                `json:"password_expire_after_days,omitempty"` // must be > 0 when EnablePwdExpiration is true
+	EnablePwdHistory            *bool               `json:"enable_password_history,omitempty"`
+	PwdHistoryCount             *int                `json:"password_keep_history_count,omitempty"`
+	EnableBlockAfterFailedLogin *bool               `json:"enable_block_after_failed_login,omitempty"` // for "Block X minutes after N times consecutive failed attempts"
+	BlockAfterFailedCount       *int                `json:"block_after_failed_login_count,omitempty"`  // must be > 0 when EnableBlockAfterFailedLogin is true
+	BlockMinutes                *int                `json:"block_minutes,omitempty"`                   // must be > 0 when EnableBlockAfterFailedLogin is true
+	SessionTimeout              *uint32             `json:"session_timeout,omitempty"`                 // for default user session timeout (in seconds)
+	MFARequiredRoles            []string            `json:"mfa_required_roles,omitempty"`
+	SessionPolicies             []RESTSessionPolicy `json:"session_policies,omitempty"`
 }
 
 type RESTPwdProfileDataConditional struct {
@@ -750,6 +836,7 @@ type RESTAgent struct {
 	State       string            `json:"connection_state"`
 	DisconnAt   string            `json:"disconnected_at"`
 	NvProtect   bool              `json:"nv_protect"`
+	Restricted  bool              `json:"restricted"`
 }
 
 const StateOnline string = "connected"
@@ -796,13 +883,14 @@ type RESTControllerData struct {
 }
 
 type RESTDomain struct {
-	Name             string            `json:"name"`
-	Workloads        int               `json:"workloads"`
-	RunningWorkloads int               `json:"running_workloads"`
-	RunningPods      int               `json:"running_pods"`
-	Services         int               `json:"services"`
-	Tags             []string          `json:"tags"`
-	Labels           map[string]string `json:"labels"`
+	Name                 string            `json:"name"`
+	Workloads            int               `json:"workloads"`
+	RunningWorkloads     int               `json:"running_workloads"`
+	RunningPods          int               `json:"running_pods"`
+	Services             int               `json:"services"`
+	Tags                 []string          `json:"tags"`
+	Labels               map[string]string `json:"labels"`
+	NewServicePolicyMode string            `json:"new_service_policy_mode,omitempty"`
 }
 
 type RESTDomainsData struct {
@@ -811,8 +899,9 @@ type RESTDomainsData struct {
 }
 
 type RESTDomainEntryConfig struct {
-	Name string    `json:"name"`
-	Tags *[]string `json:"tags,omitempty"`
+	Name                 string    `json:"name"`
+	Tags                 *[]string `json:"tags,omitempty"`
+	NewServicePolicyMode *string   `json:"new_service_policy_mode,omitempty"` // empty string to use the system setting
 }
 
 type RESTDomainEntryConfigData struct {
@@ -1071,6 +1160,7 @@ type RESTConversationEntry struct {
 	FQDN         string `json:"fqdn"`
 	Xff          bool   `json:"xff"`
 	ToSidecar    bool   `json:"to_sidecar"`
+	MeshID       string `json:"mesh_id,omitempty"`
 }
 
 type RESTConversationDetail struct {
@@ -1122,22 +1212,56 @@ type RESTGroupCaps struct {
 }
 
 type RESTGroupBrief struct {
-	Name            string   `json:"name"`
-	Comment         string   `json:"comment"`
-	Learned         bool     `json:"learned"`
-	Reserved        bool     `json:"reserved"`
-	PolicyMode      string   `json:"policy_mode,omitempty"`
-	ProfileMode     string   `json:"profile_mode,omitempty"`
-	NotScored       bool     `json:"not_scored"`
-	Domain          string   `json:"domain"`
-	CreaterDomains  []string `json:"creater_domains"`
-	Kind            string   `json:"kind"`
-	PlatformRole    string   `json:"platform_role"`
-	CfgType         string   `json:"cfg_type"` // CfgTypeLearned / CfgTypeUserCreated / CfgTypeGround / CfgTypeFederal (see above)
-	BaselineProfile string   `json:"baseline_profile"`
+	Name            string             `json:"name"`
+	Comment         string             `json:"comment"`
+	Learned         bool               `json:"learned"`
+	Reserved        bool               `json:"reserved"`
+	PolicyMode      string             `json:"policy_mode,omitempty"`
+	ProfileMode     string             `json:"profile_mode,omitempty"`
+	NotScored       bool               `json:"not_scored"`
+	Domain          string             `json:"domain"`
+	CreaterDomains  []string           `json:"creater_domains"`
+	Kind            string             `json:"kind"`
+	PlatformRole    string             `json:"platform_role"`
+	CfgType         string             `json:"cfg_type"`             // CfgTypeLearned / CfgTypeUserCreated / CfgTypeGround / CfgTypeFederal (see above)
+	ManagedBy       string             `json:"managed_by,omitempty"` // primary cluster name of the federal groups on managed clusters
+	BaselineProfile string             `json:"baseline_profile"`
+	Template        bool               `json:"template"`
+	AutoMode        *RESTGroupAutoMode `json:"auto_mode,omitempty"`
+	// Optional limit of the traffic allowed into the members, shared by the allow rules to the group
+	// without their own limit. Like the rule limit, it is metered by each dp thread.
+	RateLimit     uint32 `json:"rate_limit,omitempty"`
+	RateLimitUnit string `json:"rate_limit_unit,omitempty"` // "bytes" or "connections", per second
 	RESTGroupCaps
 }
 
+type RESTGroupAutoMode struct {
+	Hold                bool   `json:"hold"`
+	ModeAutoD2MDuration int64  `json:"mode_auto_d2m_duration"` // seconds, 0 means the system setting
+	ModeAutoM2PDuration int64  `json:"mode_auto_m2p_duration"` // seconds, 0 means the system setting
+	NoLearnDays         uint32 `json:"no_learn_days"`
+}
+
+type RESTGroupAutoModeConfig struct {
+	Hold                *bool   `json:"hold,omitempty"`
+	ModeAutoD2MDuration *int64  `json:"mode_auto_d2m_duration,omitempty"`
+	ModeAutoM2PDuration *int64  `json:"mode_auto_m2p_duration,omitempty"`
+	NoLearnDays         *uint32 `json:"no_learn_days,omitempty"`
+}
+
+type RESTAutoModeTransition struct {
+	Group      string `json:"group"`
+	PolicyMode string `json:"policy_mode"`
+	TargetMode string `json:"target_mode"`
+	Hold       bool   `json:"hold"`
+	Elapsed    int64  `json:"elapsed"`  // seconds of the good probes
+	Duration   int64  `json:"duration"` // seconds of the good probes to promote
+}
+
+type RESTAutoModeTransitionsData struct {
+	Transitions []*RESTAutoModeTransition `json:"transitions"`
+}
+
 type RESTGroup struct {
 	RESTGroupBrief
 	Criteria      []RESTCriteriaEntry  `json:"criteria"`
@@ -1155,10 +1279,15 @@ type RESTGroupDetail struct {
 }
 
 type RESTGroupConfig struct {
-	Name     string               `json:"name"`
-	Comment  *string              `json:"comment"`
-	Criteria *[]RESTCriteriaEntry `json:"criteria,omitempty"`
-	CfgType  string               `json:"cfg_type"` // CfgTypeLearned / CfgTypeUserCreated / CfgTypeGround / CfgTypeFederal (see above)
+	Name     string                   `json:"name"`
+	Comment  *string                  `json:"comment"`
+	Criteria *[]RESTCriteriaEntry     `json:"criteria,omitempty"`
+	CfgType  string                   `json:"cfg_type"` // CfgTypeLearned / CfgTypeUserCreated / CfgTypeGround / CfgTypeFederal (see above)
+	Template *bool                    `json:"template,omitempty"`
+	AutoMode *RESTGroupAutoModeConfig `json:"auto_mode,omitempty"`
+	// 0 removes the limit
+	RateLimit     *uint32 `json:"rate_limit,omitempty"`
+	RateLimitUnit *string `json:"rate_limit_unit,omitempty"`
 }
 
 type RESTCrdGroupConfig struct {
@@ -1188,6 +1317,23 @@ type RESTGroupBatchDelete struct {
 	Groups []string `json:"groups"`
 }
 
+// Operations in a batch request are applied with one kv transaction, i.e. either all or none of them take effect
+const (
+	BatchOpCreate string = "create"
+	BatchOpUpdate string = "update"
+	BatchOpDelete string = "delete"
+)
+
+type RESTGroupBatchOp struct {
+	Op     string           `json:"op"`               // create/update/delete
+	Config *RESTGroupConfig `json:"config,omitempty"` // for create/update
+	Name   string           `json:"name,omitempty"`   // for delete
+}
+
+type RESTGroupBatchData struct {
+	Ops []*RESTGroupBatchOp `json:"ops"`
+}
+
 const PolicyPortAny string = "any"
 const PolicyAppAny string = "any"
 const PolicyLearnedIDBase uint32 = share.PolicyLearnedIDBase
@@ -1209,8 +1355,16 @@ type RESTPolicyRule struct {
 	Disable      bool     `json:"disable"`
 	CreatedTS    int64    `json:"created_timestamp"`
 	LastModTS    int64    `json:"last_modified_timestamp"`
-	CfgType      string   `json:"cfg_type"` // CfgTypeLearned / CfgTypeUserCreated / CfgTypeGround / CfgTypeFederal (see above)
+	CfgType      string   `json:"cfg_type"`             // CfgTypeLearned / CfgTypeUserCreated / CfgTypeGround / CfgTypeFederal (see above)
+	ManagedBy    string   `json:"managed_by,omitempty"` // primary cluster name of the federal rules on managed clusters
 	Priority     uint32   `json:"priority"`
+	// Optional limit of the traffic allowed by the rule between the groups, 0 means no limit.
+	// Each dp thread of an enforcer meters the traffic it handles, so a node can pass up to
+	// the limit times the number of its dp threads.
+	RateLimit     uint32 `json:"rate_limit,omitempty"`
+	RateLimitUnit string `json:"rate_limit_unit,omitempty"` // "bytes" or "connections", per second
+	// Optional expiration time of a temporary rule, the rule is disabled by the controller when it expires.
+	ExpireTS int64 `json:"expire_timestamp,omitempty"`
 }
 
 type RESTPolicyRuleData struct {
@@ -1239,18 +1393,115 @@ type RESTPolicyRuleActionData struct {
 	Delete *[]uint32             `json:"delete,omitempty"`
 }
 
+type RESTPolicyRuleBatchOp struct {
+	Op     string                `json:"op"`               // create/update/delete
+	Rule   *RESTPolicyRule       `json:"rule,omitempty"`   // for create. new rules are appended to the end of the rule list
+	Config *RESTPolicyRuleConfig `json:"config,omitempty"` // for update
+	ID     uint32                `json:"id,omitempty"`     // for delete
+}
+
+type RESTPolicyRuleBatchData struct {
+	Ops []*RESTPolicyRuleBatchOp `json:"ops"`
+}
+
+type RESTPolicyRuleBatchCreatedData struct {
+	IDs []uint32 `json:"ids"` // ids of the created rules, in the order of the create operations
+}
+
+// Network rule changes that are submitted for approval when the policy change approval is enabled
+type RESTPolicyChangeConfig struct {
+	Comment string                   `json:"comment"`
+	Ops     []*RESTPolicyRuleBatchOp `json:"ops"`
+}
+
+type RESTPolicyChangeConfigData struct {
+	Config *RESTPolicyChangeConfig `json:"config"`
+}
+
+type RESTPolicyChangeOp struct {
+	Op       string          `json:"op"`               // create/update/delete
+	ID       uint32          `json:"id"`               // 0 for the new rule whose ID is assigned when approved
+	Before   *RESTPolicyRule `json:"before,omitempty"` // the current rule, for update/delete
+	After    *RESTPolicyRule `json:"after,omitempty"`  // the rule after the change, for create/update
+	Changes  []string        `json:"changes"`          // the modified fields of update
+	Conflict bool            `json:"conflict"`         // the rule is modified or removed after the change is submitted
+}
+
+type RESTPolicyChange struct {
+	ID            string                `json:"id"`
+	Comment       string                `json:"comment"`
+	Status        string                `json:"status"` // pending, approved, rejected
+	SubmittedBy   string                `json:"submitted_by"`
+	SubmittedAt   time.Time             `json:"submitted_at"`
+	ReviewedBy    string                `json:"reviewed_by"`
+	ReviewedAt    time.Time             `json:"reviewed_at"`
+	ReviewComment string                `json:"review_comment"`
+	CreatedIDs    []uint32              `json:"created_ids"`
+	Ops           []*RESTPolicyChangeOp `json:"ops"`
+}
+
+type RESTPolicyChangeData struct {
+	Change *RESTPolicyChange `json:"change"`
+}
+
+type RESTPolicyChangesData struct {
+	Changes []*RESTPolicyChange `json:"changes"`
+}
+
+type RESTPolicyChangeReview struct {
+	Comment string `json:"comment"`
+}
+
+type RESTPolicyChangeReviewData struct {
+	Review *RESTPolicyChangeReview `json:"review"`
+}
+
+// The proposed rules are the current rules modified by either the operations or a pending policy change.
+// The groups are assumed to be in Protect mode, all groups if it is empty.
+type RESTPolicySimulationConfig struct {
+	Ops      []*RESTPolicyRuleBatchOp `json:"ops,omitempty"`
+	ChangeID string                   `json:"change_id,omitempty"`
+	Groups   []string                 `json:"groups,omitempty"`
+	SinceTS  int64                    `json:"since_timestamp,omitempty"` // 0: all recorded conversations
+}
+
+type RESTPolicySimulationConfigData struct {
+	Config *RESTPolicySimulationConfig `json:"config"`
+}
+
+type RESTPolicySimulationFlow struct {
+	From          string `json:"from"`
+	To            string `json:"to"`
+	Port          string `json:"port"`
+	Application   string `json:"application,omitempty"`
+	Bytes         uint64 `json:"bytes"`
+	Sessions      uint32 `json:"sessions"`
+	LastSeenTS    int64  `json:"last_seen_timestamp"`
+	CurrentAction string `json:"current_action"`
+	CurrentRuleID uint32 `json:"current_rule_id"`
+	RuleID        uint32 `json:"rule_id"` // 0: no rule is matched, denied by default
+}
+
+type RESTPolicySimulationData struct {
+	Evaluated int                         `json:"evaluated"`
+	Denied    []*RESTPolicySimulationFlow `json:"denied"`
+}
+
 // Omit fields indicate that it's not modified.
 type RESTPolicyRuleConfig struct {
-	ID           uint32    `json:"id"`
-	Comment      *string   `json:"comment,omitempty"`
-	From         *string   `json:"from,omitempty"`  // group name
-	To           *string   `json:"to,omitempty"`    // group name
-	Ports        *string   `json:"ports,omitempty"` // free-style port list
-	Action       *string   `json:"action,omitempty"`
-	Applications *[]string `json:"applications,omitempty"`
-	Disable      *bool     `json:"disable,omitempty"`
-	CfgType      string    `json:"cfg_type"` // CfgTypeLearned / CfgTypeUserCreated / CfgTypeGround / CfgTypeFederal (see above)
-	Priority     uint32    `json:"priority,omitempty"`
+	ID            uint32    `json:"id"`
+	Comment       *string   `json:"comment,omitempty"`
+	From          *string   `json:"from,omitempty"`  // group name
+	To            *string   `json:"to,omitempty"`    // group name
+	Ports         *string   `json:"ports,omitempty"` // free-style port list
+	Action        *string   `json:"action,omitempty"`
+	Applications  *[]string `json:"applications,omitempty"`
+	Disable       *bool     `json:"disable,omitempty"`
+	CfgType       string    `json:"cfg_type"` // CfgTypeLearned / CfgTypeUserCreated / CfgTypeGround / CfgTypeFederal (see above)
+	Priority      uint32    `json:"priority,omitempty"`
+	RateLimit     *uint32   `json:"rate_limit,omitempty"`
+	RateLimitUnit *string   `json:"rate_limit_unit,omitempty"`
+	ExpireTS      *int64    `json:"expire_timestamp,omitempty"` // 0: never expire
 }
 
 type RESTPolicyRuleConfigData struct {
@@ -1258,6 +1509,41 @@ type RESTPolicyRuleConfigData struct {
 	Replicate bool                  `json:"replicate,omitempty"`
 }
 
+const (
+	RuleHitTypeNetwork   = "network"
+	RuleHitTypeProcess   = "process"
+	RuleHitTypeAdmission = "admission"
+)
+
+// Hits of a rule counted by the controller. Process rules are counted only when they deny a process.
+type RESTRuleHit struct {
+	Type      string `json:"type"`           // network, process or admission
+	ID        uint32 `json:"id,omitempty"`   // network and admission rule
+	UUID      string `json:"uuid,omitempty"` // process rule
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+	Group     string `json:"group,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Path      string `json:"path,omitempty"`
+	Comment   string `json:"comment,omitempty"`
+	CfgType   string `json:"cfg_type"`
+	Hits      uint64 `json:"hits"`
+	LastHitTS int64  `json:"last_hit_timestamp"` // 0: never hit
+	CreatedTS int64  `json:"created_timestamp,omitempty"`
+}
+
+type RESTRuleHitsData struct {
+	CountingSince int64          `json:"counting_since"` // the hits are counted since the controller started
+	Rules         []*RESTRuleHit `json:"rules"`
+}
+
+type RESTUnusedRulesData struct {
+	CountingSince int64          `json:"counting_since"`
+	Days          uint32         `json:"days"`
+	Complete      bool           `json:"complete"` // false if the hits are not counted over the whole period
+	Rules         []*RESTRuleHit `json:"rules"`
+}
+
 const (
 	WireInline  string = share.WireInline
 	WireDefault string = share.WireDefault
@@ -1265,8 +1551,9 @@ const (
 
 // Omit fields indicate that it's not modified.
 type RESTWorkloadConfigCfg struct {
-	Wire       *string `json:"wire,omitempty"`
-	Quarantine *bool   `json:"quarantine,omitempty"`
+	Wire           *string                    `json:"wire,omitempty"`
+	Quarantine     *bool                      `json:"quarantine,omitempty"`
+	QuarExceptions *[]share.CLUSQuarException `json:"quarantine_exceptions,omitempty"` // only applied when the workload is quarantined
 }
 
 type RESTWorkloadConfigCfgData struct {
@@ -1274,9 +1561,10 @@ type RESTWorkloadConfigCfgData struct {
 }
 
 type RESTWorkloadConfig struct {
-	Wire       string `json:"wire,omitempty"`
-	Quarantine bool   `json:"quarantine"`
-	QuarReason string `json:"quarantine_reason,omitempty"`
+	Wire           string                    `json:"wire,omitempty"`
+	Quarantine     bool                      `json:"quarantine"`
+	QuarReason     string                    `json:"quarantine_reason,omitempty"`
+	QuarExceptions []share.CLUSQuarException `json:"quarantine_exceptions,omitempty"`
 }
 
 type RESTWorkloadConfigData struct {
@@ -1463,6 +1751,7 @@ const MeterTypeSYNFlood string = "syn_flood"
 const MeterTypeICMPFlood string = "icmp_flood"
 const MeterTypeIPSrcSessionLimit string = "ip_src_session_limit"
 const MeterTypeTCPNoData string = "tcp_nodata"
+const MeterTypePortScan string = "port_scan"
 
 type RESTMeter struct {
 	Type       string `json:"type"`
@@ -1507,6 +1796,69 @@ type RESTAuditsData struct {
 	Audits []*Audit `json:"audits"`
 }
 
+type RESTMitreTechniqueReport struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Detections []string `json:"detections"` // built-in threats and incidents of the technique
+	Hits       int      `json:"hits"`       // in the recent threats and incidents
+	LastHitAt  string   `json:"last_hit_at,omitempty"`
+}
+
+type RESTMitreTacticReport struct {
+	ID         string                      `json:"id"`
+	Name       string                      `json:"name"`
+	Techniques []*RESTMitreTechniqueReport `json:"techniques"`
+}
+
+type RESTAttackMatrixData struct {
+	Tactics    []*RESTMitreTacticReport `json:"tactics"`
+	Techniques int                      `json:"covered_techniques"`
+	Hits       int                      `json:"hits"`
+}
+
+const (
+	RegulatoryStatusPass       = "pass"
+	RegulatoryStatusFail       = "fail"
+	RegulatoryStatusNotCovered = "not_covered"
+)
+
+type RESTRegulatoryControlReport struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Status         string   `json:"status"`          // failed if any compliance check of the control fails
+	Checks         int      `json:"checks"`          // built-in compliance checks of the control
+	FailedChecks   []string `json:"failed_checks"`   // in the compliance assets
+	AdmissionRules int      `json:"admission_rules"` // enabled admission control deny rules
+	Detections     []string `json:"detections"`      // built-in threats and incidents of the control
+	Hits           int      `json:"hits"`            // in the recent threats, incidents and violations
+	LastHitAt      string   `json:"last_hit_at,omitempty"`
+}
+
+type RESTRegulatoryFrameworkSummary struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	Controls           int    `json:"controls"`
+	PassedControls     int    `json:"passed_controls"`
+	FailedControls     int    `json:"failed_controls"`
+	NotCoveredControls int    `json:"not_covered_controls"`
+	FailedChecks       int    `json:"failed_checks"`
+	Hits               int    `json:"hits"`
+}
+
+type RESTRegulatoryPostureData struct {
+	Frameworks []*RESTRegulatoryFrameworkSummary `json:"frameworks"`
+}
+
+type RESTRegulatoryFrameworkData struct {
+	Framework *RESTRegulatoryFrameworkSummary `json:"framework"`
+	Controls  []*RESTRegulatoryControlReport  `json:"controls"`
+}
+
+type RESTConfigAuditsData struct {
+	Audits   []*ConfigAudit `json:"config_audits"`
+	Verified bool           `json:"verified"` // whether the hash chain of the returned records is intact
+}
+
 type RESTPolicyViolationsData struct {
 	Violations []*Violation `json:"violations"`
 }
@@ -1584,6 +1936,8 @@ type RESTSystemSummary struct {
 	CVEDBVersion     string   `json:"cvedb_version"`
 	CVEDBCreateTime  string   `json:"cvedb_create_time"`
 	CompoVersions    []string `json:"component_versions"`
+	KvDegraded       bool     `json:"kv_degraded"`
+	KvDegradedSince  string   `json:"kv_degraded_since,omitempty"`
 }
 
 type RESTSystemSummaryData struct {
@@ -1600,6 +1954,33 @@ type RESTSystemStatsData struct {
 	Stats *RESTSystemStats `json:"stats"`
 }
 
+type RESTGraphQLQuery struct {
+	Query string `json:"query"`
+}
+
+type RESTGraphQLError struct {
+	Message string   `json:"message"`
+	Path    []string `json:"path,omitempty"`
+}
+
+type RESTGraphQLData struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []*RESTGraphQLError    `json:"errors,omitempty"`
+}
+
+type RESTRateLimitStats struct {
+	Enabled         bool   `json:"enabled"`
+	Rate            uint   `json:"rate"`  // requests per second
+	Burst           uint   `json:"burst"` // max requests in a burst
+	ActiveClients   int    `json:"active_clients"`
+	AllowedRequests uint64 `json:"allowed_requests"`
+	LimitedRequests uint64 `json:"limited_requests"`
+}
+
+type RESTRateLimitStatsData struct {
+	Stats *RESTRateLimitStats `json:"stats"`
+}
+
 type RESTProxy struct {
 	URL      string `json:"url"`
 	Username string `json:"username"`
@@ -1607,18 +1988,83 @@ type RESTProxy struct {
 }
 
 const (
-	WebhookDefaultName = "default"
-	WebhookTypeSlack   = "Slack"
-	WebhookTypeJSON    = "JSON"
-	WebhookTypeTeams   = "Teams"
+	WebhookDefaultName   = "default"
+	WebhookTypeSlack     = "Slack"
+	WebhookTypeJSON      = "JSON"
+	WebhookTypeTeams     = "Teams"
+	WebhookTypePagerDuty = "PagerDuty"
 )
 
+// Header values are not returned. An empty value keeps the existing value of the same header.
+type RESTWebhookHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value,cloak"`
+}
+
 type RESTWebhook struct {
-	Name    string `json:"name"`
-	Url     string `json:"url"`
-	Enable  bool   `json:"enable"`
-	Type    string `json:"type"`
-	CfgType string `json:"cfg_type"` // CfgTypeUserCreated / CfgTypeFederal (see above)
+	Name       string              `json:"name"`
+	Url        string              `json:"url"`
+	Enable     bool                `json:"enable"`
+	Type       string              `json:"type"`
+	Headers    []RESTWebhookHeader `json:"headers,omitempty"`
+	RoutingKey string              `json:"routing_key,cloak,omitempty"` // PagerDuty integration key. An empty or masked value keeps the existing key.
+	CfgType    string              `json:"cfg_type"`                    // CfgTypeUserCreated / CfgTypeFederal (see above)
+}
+
+type RESTKafkaTopic struct {
+	Category string `json:"category"`
+	Topic    string `json:"topic"`
+}
+
+type RESTLogExporter struct {
+	Name          string            `json:"name"`
+	Type          string            `json:"type"` // splunk, elasticsearch or kafka
+	Enable        bool              `json:"enable"`
+	URL           string            `json:"url"`
+	Token         string            `json:"token,cloak"` // HEC token, or Elasticsearch api key
+	Username      string            `json:"username"`
+	Password      string            `json:"password,cloak"`
+	Index         string            `json:"index"`
+	Categories    []string          `json:"categories"`
+	CACert        string            `json:"ca_cert"`
+	SkipVerify    bool              `json:"skip_verify"`
+	Brokers       []string          `json:"brokers"`
+	Topic         string            `json:"topic"`
+	Topics        []*RESTKafkaTopic `json:"topics"`
+	TLS           bool              `json:"tls"`
+	SASLMechanism string            `json:"sasl_mechanism"` // PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512
+	PartitionKey  string            `json:"partition_key"`  // cluster or workload
+}
+
+type RESTFlowExport struct {
+	Enable    bool   `json:"enable"`
+	Collector string `json:"collector"` // host:port
+	Format    string `json:"format"`    // ipfix or netflow9
+}
+
+type RESTSMTPRoute struct {
+	Recipients []string `json:"recipients"`
+	Categories []string `json:"categories"`
+	Level      string   `json:"level"`
+}
+
+type RESTSMTPConfig struct {
+	Enable      bool             `json:"enable"`
+	Server      string           `json:"server"`
+	Port        uint16           `json:"port"`
+	Security    string           `json:"security"` // starttls, tls or none
+	SkipVerify  bool             `json:"skip_verify"`
+	Username    string           `json:"username"`
+	Password    string           `json:"password,cloak"`
+	From        string           `json:"from"`
+	Routes      []*RESTSMTPRoute `json:"routes"`
+	Digest      string           `json:"digest"` // hourly or daily
+	DigestLevel string           `json:"digest_level"`
+}
+
+type RESTLogCorrelation struct {
+	Enable bool   `json:"enable"`
+	Window uint32 `json:"window"` // in seconds
 }
 
 type RESTSystemWebhookConfigData struct {
@@ -1644,14 +2090,23 @@ type RESTSystemConfigConfig struct {
 	SyslogCategories          *[]string                        `json:"syslog_categories,omitempty"`
 	SyslogInJSON              *bool                            `json:"syslog_in_json,omitempty"`
 	SyslogServerCert          *string                          `json:"syslog_server_cert,omitempty"`
+	SyslogClientCert          *string                          `json:"syslog_client_cert,omitempty"`
+	SyslogClientKey           *string                          `json:"syslog_client_key,omitempty"`
+	SyslogStructuredData      *bool                            `json:"syslog_structured_data,omitempty"`
+	SyslogCategoryMaps        *[]RESTSyslogCategoryMap         `json:"syslog_category_maps,omitempty"`
 	SingleCVEPerSyslog        *bool                            `json:"single_cve_per_syslog,omitempty"`
 	SyslogCVEInLayers         *bool                            `json:"syslog_cve_in_layers,omitempty"`
 	AuthOrder                 *[]string                        `json:"auth_order,omitempty"`
 	AuthByPlatform            *bool                            `json:"auth_by_platform,omitempty"`
 	RancherEP                 *string                          `json:"rancher_ep,omitempty"`
+	RancherProjectRoles       *map[string]string               `json:"rancher_project_roles,omitempty"`
 	WebhookEnable             *bool                            `json:"webhook_status,omitempty"` // deprecated, kept for backward-compatibility, skip docs
 	WebhookUrl                *string                          `json:"webhook_url,omitempty"`    // deprecated, kept for backward-compatibility, skip docs
 	Webhooks                  *[]*RESTWebhook                  `json:"webhooks,omitempty"`
+	FlowExport                *RESTFlowExport                  `json:"flow_export,omitempty"`
+	LogExporters              *[]*RESTLogExporter              `json:"log_exporters,omitempty"`
+	SMTP                      *RESTSMTPConfig                  `json:"smtp,omitempty"`
+	LogCorrelation            *RESTLogCorrelation              `json:"log_correlation,omitempty"`
 	ClusterName               *string                          `json:"cluster_name,omitempty"`
 	ControllerDebug           *[]string                        `json:"controller_debug,omitempty"`
 	MonitorServiceMesh        *bool                            `json:"monitor_service_mesh,omitempty"`
@@ -1664,6 +2119,9 @@ type RESTSystemConfigConfig struct {
 	XffEnabled                *bool                            `json:"xff_enabled,omitempty"`
 	ScannerAutoscale          *RESTSystemConfigAutoscaleConfig `json:"scanner_autoscale,omitempty"`
 	NoTelemetryReport         *bool                            `json:"no_telemetry_report,omitempty"`
+	KubeCISProfile            *string                          `json:"kube_cis_profile,omitempty"`
+	BenchMode                 *string                          `json:"bench_mode,omitempty"`
+	PolicyChangeApproval      *bool                            `json:"policy_change_approval,omitempty"`
 	// InternalSubnets      *[]string `json:"configured_internal_subnets,omitempty"`
 }
 
@@ -1676,6 +2134,10 @@ type RESTSysNetConfigConfig struct {
 	NetServicePolicyMode *string `json:"net_service_policy_mode,omitempty"`
 	DisableNetPolicy     *bool   `json:"disable_net_policy,omitempty"`
 	DetectUnmanagedWl    *bool   `json:"detect_unmanaged_wl,omitempty"`
+	HostNetPolicyStatus  *bool   `json:"host_net_policy_status,omitempty"`
+	HostNetPolicyMode    *string `json:"host_net_policy_mode,omitempty"`
+	SynFloodThreshold    *uint32 `json:"syn_flood_threshold,omitempty"` // SYN per second to a workload, 0 to disable the detection
+	PortScanThreshold    *uint32 `json:"port_scan_threshold,omitempty"` // rejected connections per second from a source, 0 to disable the detection
 }
 
 type RESTSysAtmoConfigConfig struct {
@@ -1709,22 +2171,34 @@ type RESTSystemConfigSvcCfgV2 struct {
 }
 
 type RESTSystemConfigSyslogCfgV2 struct {
-	SyslogServer       *string   `json:"syslog_ip,omitempty"`
-	SyslogIPProto      *uint8    `json:"syslog_ip_proto,omitempty"`
-	SyslogPort         *uint16   `json:"syslog_port,omitempty"`
-	SyslogLevel        *string   `json:"syslog_level,omitempty"`
-	SyslogEnable       *bool     `json:"syslog_status,omitempty"`
-	SyslogCategories   *[]string `json:"syslog_categories,omitempty"`
-	SyslogInJSON       *bool     `json:"syslog_in_json,omitempty"`
-	SingleCVEPerSyslog *bool     `json:"single_cve_per_syslog"`
-	SyslogCVEInLayers  *bool     `json:"syslog_cve_in_layers,omitempty"`
-	SyslogServerCert   *string   `json:"syslog_server_cert,omitempty"`
+	SyslogServer         *string                  `json:"syslog_ip,omitempty"`
+	SyslogIPProto        *uint8                   `json:"syslog_ip_proto,omitempty"`
+	SyslogPort           *uint16                  `json:"syslog_port,omitempty"`
+	SyslogLevel          *string                  `json:"syslog_level,omitempty"`
+	SyslogEnable         *bool                    `json:"syslog_status,omitempty"`
+	SyslogCategories     *[]string                `json:"syslog_categories,omitempty"`
+	SyslogInJSON         *bool                    `json:"syslog_in_json,omitempty"`
+	SingleCVEPerSyslog   *bool                    `json:"single_cve_per_syslog"`
+	SyslogCVEInLayers    *bool                    `json:"syslog_cve_in_layers,omitempty"`
+	SyslogServerCert     *string                  `json:"syslog_server_cert,omitempty"`
+	SyslogClientCert     *string                  `json:"syslog_client_cert,omitempty"`
+	SyslogClientKey      *string                  `json:"syslog_client_key,omitempty"`
+	SyslogStructuredData *bool                    `json:"syslog_structured_data,omitempty"`
+	SyslogCategoryMaps   *[]RESTSyslogCategoryMap `json:"syslog_category_maps,omitempty"`
+}
+
+// Facility and level of the syslog messages of a category, empty value to use the default
+type RESTSyslogCategoryMap struct {
+	Category string `json:"category"`
+	Facility string `json:"facility"`
+	Level    string `json:"level"`
 }
 
 type RESTSystemConfigAuthCfgV2 struct {
-	AuthOrder      *[]string `json:"auth_order,omitempty"`
-	AuthByPlatform *bool     `json:"auth_by_platform,omitempty"`
-	RancherEP      *string   `json:"rancher_ep,omitempty"`
+	AuthOrder           *[]string          `json:"auth_order,omitempty"`
+	AuthByPlatform      *bool              `json:"auth_by_platform,omitempty"`
+	RancherEP           *string            `json:"rancher_ep,omitempty"`
+	RancherProjectRoles *map[string]string `json:"rancher_project_roles,omitempty"`
 }
 
 type RESTSystemConfigProxyCfgV2 struct {
@@ -1736,12 +2210,15 @@ type RESTSystemConfigProxyCfgV2 struct {
 
 type RESTSystemConfigMiscCfgV2 struct {
 	// InternalSubnets      *[]string `json:"configured_internal_subnets,omitempty"`
-	UnusedGroupAging   *uint8    `json:"unused_group_aging,omitempty"`
-	ClusterName        *string   `json:"cluster_name,omitempty"`
-	ControllerDebug    *[]string `json:"controller_debug,omitempty"`
-	MonitorServiceMesh *bool     `json:"monitor_service_mesh,omitempty"`
-	XffEnabled         *bool     `json:"xff_enabled,omitempty"`
-	NoTelemetryReport  *bool     `json:"no_telemetry_report,omitempty"`
+	UnusedGroupAging     *uint8    `json:"unused_group_aging,omitempty"`
+	ClusterName          *string   `json:"cluster_name,omitempty"`
+	ControllerDebug      *[]string `json:"controller_debug,omitempty"`
+	MonitorServiceMesh   *bool     `json:"monitor_service_mesh,omitempty"`
+	XffEnabled           *bool     `json:"xff_enabled,omitempty"`
+	NoTelemetryReport    *bool     `json:"no_telemetry_report,omitempty"`
+	KubeCISProfile       *string   `json:"kube_cis_profile,omitempty"`
+	BenchMode            *string   `json:"bench_mode,omitempty"`
+	PolicyChangeApproval *bool     `json:"policy_change_approval,omitempty"`
 }
 
 type RESTSystemConfigIBMSAVCfg2 struct {
@@ -1755,6 +2232,10 @@ type RESTSystemConfigConfigV2 struct {
 	AuthCfg          *RESTSystemConfigAuthCfgV2       `json:"auth_cfg,omitempty"`
 	ProxyCfg         *RESTSystemConfigProxyCfgV2      `json:"proxy_cfg,omitempty"`
 	Webhooks         *[]*RESTWebhook                  `json:"webhooks,omitempty"`
+	FlowExport       *RESTFlowExport                  `json:"flow_export,omitempty"`
+	LogExporters     *[]*RESTLogExporter              `json:"log_exporters,omitempty"`
+	SMTP             *RESTSMTPConfig                  `json:"smtp,omitempty"`
+	LogCorrelation   *RESTLogCorrelation              `json:"log_correlation,omitempty"`
 	IbmsaCfg         *RESTSystemConfigIBMSAVCfg2      `json:"ibmsa_cfg,omitempty"`
 	ScannerAutoscale *RESTSystemConfigAutoscaleConfig `json:"scanner_autoscale_cfg,omitempty"`
 	MiscCfg          *RESTSystemConfigMiscCfgV2       `json:"misc_cfg,omitempty"`
@@ -1788,13 +2269,22 @@ type RESTSystemConfig struct {
 	SyslogCategories          []string                  `json:"syslog_categories"`
 	SyslogInJSON              bool                      `json:"syslog_in_json"`
 	SyslogServerCert          string                    `json:"syslog_server_cert"`
+	SyslogClientCert          string                    `json:"syslog_client_cert"`
+	SyslogClientKey           string                    `json:"syslog_client_key,cloak"`
+	SyslogStructuredData      bool                      `json:"syslog_structured_data"`
+	SyslogCategoryMaps        []RESTSyslogCategoryMap   `json:"syslog_category_maps"`
 	SingleCVEPerSyslog        bool                      `json:"single_cve_per_syslog"`
 	SyslogCVEInLayers         bool                      `json:"syslog_cve_in_layers,omitempty"`
 	AuthOrder                 []string                  `json:"auth_order"`
 	AuthByPlatform            bool                      `json:"auth_by_platform"`
 	RancherEP                 string                    `json:"rancher_ep"`
+	RancherProjectRoles       map[string]string         `json:"rancher_project_roles"`
 	InternalSubnets           []string                  `json:"configured_internal_subnets,omitempty"`
 	Webhooks                  []RESTWebhook             `json:"webhooks"`
+	FlowExport                RESTFlowExport            `json:"flow_export"`
+	LogExporters              []RESTLogExporter         `json:"log_exporters"`
+	SMTP                      RESTSMTPConfig            `json:"smtp"`
+	LogCorrelation            RESTLogCorrelation        `json:"log_correlation"`
 	ClusterName               string                    `json:"cluster_name"`
 	ControllerDebug           []string                  `json:"controller_debug"`
 	MonitorServiceMesh        bool                      `json:"monitor_service_mesh"`
@@ -1811,12 +2301,19 @@ type RESTSystemConfig struct {
 	NetServicePolicyMode      string                    `json:"net_service_policy_mode"`
 	DisableNetPolicy          bool                      `json:"disable_net_policy"`
 	DetectUnmanagedWl         bool                      `json:"detect_unmanaged_wl"`
+	HostNetPolicyStatus       bool                      `json:"host_net_policy_status"`
+	HostNetPolicyMode         string                    `json:"host_net_policy_mode"`
+	SynFloodThreshold         uint32                    `json:"syn_flood_threshold"`
+	PortScanThreshold         uint32                    `json:"port_scan_threshold"`
 	ModeAutoD2M               bool                      `json:"mode_auto_d2m"`
 	ModeAutoD2MDuration       int64                     `json:"mode_auto_d2m_duration"`
 	ModeAutoM2P               bool                      `json:"mode_auto_m2p"`
 	ModeAutoM2PDuration       int64                     `json:"mode_auto_m2p_duration"`
 	ScannerAutoscale          RESTSystemConfigAutoscale `json:"scanner_autoscale"`
 	NoTelemetryReport         bool                      `json:"no_telemetry_report"`
+	KubeCISProfile            string                    `json:"kube_cis_profile"`
+	BenchMode                 string                    `json:"bench_mode"`
+	PolicyChangeApproval      bool                      `json:"policy_change_approval"`
 	CspType                   string                    `json:"csp_type"`
 }
 
@@ -1831,33 +2328,41 @@ type RESTSystemConfigNewSvcV2 struct {
 }
 
 type RESTSystemConfigSyslogV2 struct {
-	SyslogServer       string   `json:"syslog_ip"`
-	SyslogIPProto      uint8    `json:"syslog_ip_proto"`
-	SyslogPort         uint16   `json:"syslog_port"`
-	SyslogLevel        string   `json:"syslog_level"`
-	SyslogEnable       bool     `json:"syslog_status"`
-	SyslogCategories   []string `json:"syslog_categories"`
-	SyslogInJSON       bool     `json:"syslog_in_json"`
-	SingleCVEPerSyslog bool     `json:"single_cve_per_syslog"`
-	SyslogCVEInLayers  bool     `json:"syslog_cve_in_layers"`
-	SyslogServerCert   string   `json:"syslog_server_cert"`
+	SyslogServer         string                  `json:"syslog_ip"`
+	SyslogIPProto        uint8                   `json:"syslog_ip_proto"`
+	SyslogPort           uint16                  `json:"syslog_port"`
+	SyslogLevel          string                  `json:"syslog_level"`
+	SyslogEnable         bool                    `json:"syslog_status"`
+	SyslogCategories     []string                `json:"syslog_categories"`
+	SyslogInJSON         bool                    `json:"syslog_in_json"`
+	SingleCVEPerSyslog   bool                    `json:"single_cve_per_syslog"`
+	SyslogCVEInLayers    bool                    `json:"syslog_cve_in_layers"`
+	SyslogServerCert     string                  `json:"syslog_server_cert"`
+	SyslogClientCert     string                  `json:"syslog_client_cert"`
+	SyslogClientKey      string                  `json:"syslog_client_key,cloak"`
+	SyslogStructuredData bool                    `json:"syslog_structured_data"`
+	SyslogCategoryMaps   []RESTSyslogCategoryMap `json:"syslog_category_maps"`
 }
 
 type RESTSystemConfigAuthV2 struct {
-	AuthOrder      []string `json:"auth_order"`
-	AuthByPlatform bool     `json:"auth_by_platform"`
-	RancherEP      string   `json:"rancher_ep"`
+	AuthOrder           []string          `json:"auth_order"`
+	AuthByPlatform      bool              `json:"auth_by_platform"`
+	RancherEP           string            `json:"rancher_ep"`
+	RancherProjectRoles map[string]string `json:"rancher_project_roles"`
 }
 
 type RESTSystemConfigMiscV2 struct {
-	InternalSubnets    []string `json:"configured_internal_subnets,omitempty"`
-	UnusedGroupAging   uint8    `json:"unused_group_aging"`
-	ClusterName        string   `json:"cluster_name"`
-	ControllerDebug    []string `json:"controller_debug"`
-	MonitorServiceMesh bool     `json:"monitor_service_mesh"`
-	XffEnabled         bool     `json:"xff_enabled"`
-	NoTelemetryReport  bool     `json:"no_telemetry_report"`
-	CspType            string   `json:"csp_type"` // billing csp type (local or master cluster)
+	InternalSubnets      []string `json:"configured_internal_subnets,omitempty"`
+	UnusedGroupAging     uint8    `json:"unused_group_aging"`
+	ClusterName          string   `json:"cluster_name"`
+	ControllerDebug      []string `json:"controller_debug"`
+	MonitorServiceMesh   bool     `json:"monitor_service_mesh"`
+	XffEnabled           bool     `json:"xff_enabled"`
+	NoTelemetryReport    bool     `json:"no_telemetry_report"`
+	KubeCISProfile       string   `json:"kube_cis_profile"`
+	BenchMode            string   `json:"bench_mode"`
+	PolicyChangeApproval bool     `json:"policy_change_approval"`
+	CspType              string   `json:"csp_type"` // billing csp type (local or master cluster)
 }
 
 // for scanner autoscaling
@@ -1893,6 +2398,10 @@ type RESTSystemConfigNetSvcV2 struct {
 	NetServicePolicyMode string `json:"net_service_policy_mode"`
 	DisableNetPolicy     bool   `json:"disable_net_policy"`
 	DetectUnmanagedWl    bool   `json:"detect_unmanaged_wl"`
+	HostNetPolicyStatus  bool   `json:"host_net_policy_status"`
+	HostNetPolicyMode    string `json:"host_net_policy_mode"`
+	SynFloodThreshold    uint32 `json:"syn_flood_threshold"`
+	PortScanThreshold    uint32 `json:"port_scan_threshold"`
 }
 
 type RESTSystemConfigModeAutoV2 struct {
@@ -1908,6 +2417,10 @@ type RESTSystemConfigV2 struct {
 	Auth             RESTSystemConfigAuthV2     `json:"auth"`
 	Misc             RESTSystemConfigMiscV2     `json:"misc"`
 	Webhooks         []RESTWebhook              `json:"webhooks"`
+	FlowExport       RESTFlowExport             `json:"flow_export"`
+	LogExporters     []RESTLogExporter          `json:"log_exporters"`
+	SMTP             RESTSMTPConfig             `json:"smtp"`
+	LogCorrelation   RESTLogCorrelation         `json:"log_correlation"`
 	Proxy            RESTSystemConfigProxyV2    `json:"proxy"`
 	IBMSA            RESTSystemConfigIBMSAV2    `json:"ibmsa"`
 	NetSvc           RESTSystemConfigNetSvcV2   `json:"net_svc"`
@@ -1949,6 +2462,45 @@ type RESTInternalSubnetsData struct {
 	InternalSubnets *RESTInternalSubnets `json:"internal_subnets"`
 }
 
+type RESTKvPrefixStats struct {
+	Prefix string `json:"prefix"`
+	Keys   int    `json:"keys"`
+	Size   int64  `json:"size"`
+}
+
+type RESTKvStoreStats struct {
+	UpdatedAt     time.Time            `json:"updated_at"`
+	Keys          int                  `json:"keys"`
+	Size          int64                `json:"size"`
+	SizeLimit     int64                `json:"size_limit"`
+	Prefixes      []*RESTKvPrefixStats `json:"prefixes"` // largest prefixes first
+	LastCompactAt time.Time            `json:"last_compact_at"`
+	CompactedKeys int                  `json:"compacted_keys"`
+}
+
+type RESTKvStoreStatsData struct {
+	Stats *RESTKvStoreStats `json:"stats"`
+}
+
+type RESTKvDataKey struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RESTKvEncryption struct {
+	Enabled   bool             `json:"enabled"`
+	Provider  string           `json:"provider"`
+	KeyID     string           `json:"key_id"`
+	ActiveKey string           `json:"active_key"`
+	DataKeys  []*RESTKvDataKey `json:"data_keys"`
+	RotatedAt time.Time        `json:"rotated_at"`
+}
+
+type RESTKvEncryptionData struct {
+	Encryption  *RESTKvEncryption `json:"encryption"`
+	Reencrypted int               `json:"reencrypted,omitempty"` // number of kv values re-encrypted by rotation
+}
+
 type RESTServiceConfig struct {
 	Name            string  `json:"name"`
 	Domain          string  `json:"domain"`
@@ -1976,6 +2528,7 @@ type RESTService struct {
 	ServiceAddr     *RESTIPPort          `json:"service_addr,omitempty"`
 	IngressExposure bool                 `json:"ingress_exposure"`
 	EgressExposure  bool                 `json:"egress_exposure"`
+	Routes          []string             `json:"routes,omitempty"` // hosts of the OpenShift routes that expose the service
 	BaselineProfile string               `json:"baseline_profile"`
 	RESTGroupCaps
 }
@@ -1999,12 +2552,19 @@ type RESTServiceBatchConfigData struct {
 	Config *RESTServiceBatchConfig `json:"config"`
 }
 
+type RESTRegistryMirror struct {
+	Mirror   string `json:"mirror"`
+	Upstream string `json:"upstream"`
+}
+
 type RESTScanConfig struct {
-	AutoScan bool `json:"auto_scan"`
+	AutoScan        bool                  `json:"auto_scan"`
+	RegistryMirrors []*RESTRegistryMirror `json:"registry_mirrors"` // nil in the request means no change
 }
 
 type RESTScanConfigConfig struct {
-	AutoScan *bool `json:"auto_scan"`
+	AutoScan        *bool                  `json:"auto_scan"`
+	RegistryMirrors *[]*RESTRegistryMirror `json:"registry_mirrors,omitempty"`
 }
 
 type RESTScanConfigData struct {
@@ -2409,6 +2969,7 @@ type RESTBenchCheck struct {
 	Description string   `json:"description"`
 	Remediation string   `json:"remediation"`
 	Tags        []string `json:"tags"`
+	Controls    []string `json:"regulatory_controls,omitempty"`
 }
 
 type RESTBenchMeta struct {
@@ -2422,6 +2983,23 @@ type RESTBenchItem struct {
 	Location string   `json:"location,omitempty"`
 	Message  []string `json:"message"`
 	Group    string   `json:"group,omitempty"`
+
+	Stig *RESTBenchStig `json:"stig,omitempty"`
+}
+
+// The STIG rule of a check, in the STIG benchmark mode
+type RESTBenchStig struct {
+	VulnID   string `json:"vuln_id"`
+	RuleID   string `json:"rule_id"`
+	StigID   string `json:"stig_id"`
+	Severity string `json:"severity"`
+}
+
+type RESTBenchStigInfo struct {
+	StigID  string `json:"stig_id"`
+	Title   string `json:"title"`
+	Version string `json:"version"`
+	Release string `json:"release"`
 }
 
 type RESTBenchReport struct {
@@ -2429,6 +3007,8 @@ type RESTBenchReport struct {
 	RunAt          string           `json:"run_at"`
 	Version        string           `json:"cis_version"`
 	Items          []*RESTBenchItem `json:"items"`
+
+	Stig *RESTBenchStigInfo `json:"stig,omitempty"`
 }
 
 type RESTComplianceData struct {
@@ -2437,6 +3017,8 @@ type RESTComplianceData struct {
 	KubeVersion    string           `json:"kubernetes_cis_version"`
 	DockerVersion  string           `json:"docker_cis_version"`
 	Items          []*RESTBenchItem `json:"items"`
+
+	KubeStig *RESTBenchStigInfo `json:"kubernetes_stig,omitempty"`
 }
 
 type RESTComplianceAsset struct {
@@ -2451,6 +3033,7 @@ type RESTComplianceAsset struct {
 	Remediation string   `json:"remediation"`
 	Group       string   `json:"group"`
 	Tags        []string `json:"tags"`
+	Controls    []string `json:"regulatory_controls,omitempty"`
 	Workloads   []string `json:"workloads"`
 	Nodes       []string `json:"nodes"`
 	Images      []string `json:"images"`
@@ -2478,6 +3061,7 @@ const (
 type RESTComplianceProfileEntry struct {
 	TestNum string   `json:"test_number"`
 	Tags    []string `json:"tags"`
+	CfgType string   `json:"cfg_type,omitempty"` // CfgTypeUserCreated / CfgTypeGround (see above), ignored in config request
 }
 
 type RESTComplianceProfile struct {
@@ -2520,6 +3104,7 @@ type RESTVulnerabilityProfileEntry struct {
 	Days    uint     `json:"days"` // Only used for 'recent' vuln entries
 	Domains []string `json:"domains"`
 	Images  []string `json:"images"`
+	CfgType string   `json:"cfg_type,omitempty"` // CfgTypeUserCreated / CfgTypeGround (see above), ignored in config request
 }
 
 type RESTVulnerabilityProfile struct {
@@ -2550,13 +3135,18 @@ type RESTVulnerabilityProfileEntryConfigData struct {
 
 // Custom check
 type RESTCustomCheck struct {
-	Name   string `json:"name"`
-	Script string `json:"script"`
+	Name        string `json:"name"`
+	Script      string `json:"script"`
+	Description string `json:"description,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+	Profile     string `json:"profile,omitempty"` // Level 1, Level 2
+	Scored      bool   `json:"scored,omitempty"`
 }
 
 type RESTCustomChecks struct {
-	Group   string             `json:"group"`
-	Scripts []*RESTCustomCheck `json:"scripts"`
+	Group    string             `json:"group"`
+	Scripts  []*RESTCustomCheck `json:"scripts"`
+	Schedule *uint32            `json:"schedule,omitempty"` // in minutes, 0: run only when the scripts or the members change
 }
 
 type RESTCustomCheckConfig struct {
@@ -2595,15 +3185,21 @@ type RESTResponseRuleOptionData struct {
 }
 
 type RESTResponseRule struct {
-	ID         uint32                     `json:"id"`
-	Event      string                     `json:"event"`
-	Comment    string                     `json:"comment"`
-	Group      string                     `json:"group"`
-	Conditions []share.CLUSEventCondition `json:"conditions"`
-	Actions    []string                   `json:"actions"`
-	Webhooks   []string                   `json:"webhooks"`
-	Disable    bool                       `json:"disable"`
-	CfgType    string                     `json:"cfg_type"` // CfgTypeLearned / CfgTypeUserCreated / CfgTypeGround / CfgTypeFederal (see above)
+	ID              uint32                     `json:"id"`
+	Event           string                     `json:"event"`
+	Comment         string                     `json:"comment"`
+	Group           string                     `json:"group"`
+	Conditions      []share.CLUSEventCondition `json:"conditions"`
+	Actions         []string                   `json:"actions"`
+	Webhooks        []string                   `json:"webhooks"`
+	WebhookTemplate string                     `json:"webhook_template"`  // JSON payload with {{field}} placeholders, empty for the webhook's own format
+	WebhookSeverity string                     `json:"webhook_severity"`  // PagerDuty severity: critical, error, warning or info. Empty to map from the log level
+	WebhookDedupKey string                     `json:"webhook_dedup_key"` // PagerDuty dedup key with {{field}} placeholders, empty for the cluster, category and title
+	QuarExceptions  []share.CLUSQuarException  `json:"quarantine_exceptions"`
+	Disable         bool                       `json:"disable"`
+	CfgType         string                     `json:"cfg_type"`                   // CfgTypeLearned / CfgTypeUserCreated / CfgTypeGround / CfgTypeFederal (see above)
+	ManagedBy       string                     `json:"managed_by,omitempty"`       // primary cluster name of the federal rules on managed clusters
+	ExpireTS        int64                      `json:"expire_timestamp,omitempty"` // the rule is disabled by the controller when it expires
 }
 
 type RESTResponseRuleData struct {
@@ -2626,15 +3222,20 @@ type RESTResponseRuleActionData struct {
 
 // Omit fields indicate that it's not modified.
 type RESTResponseRuleConfig struct {
-	ID         uint32                      `json:"id"`
-	Comment    *string                     `json:"comment,omitempty"`
-	Group      *string                     `json:"group,omitempty"`
-	Event      *string                     `json:"event,omitempty"`
-	Conditions *[]share.CLUSEventCondition `json:"conditions,omitempty"`
-	Actions    *[]string                   `json:"actions,omitempty"`
-	Webhooks   *[]string                   `json:"webhooks,omitempty"`
-	Disable    *bool                       `json:"disable,omitempty"`
-	CfgType    string                      `json:"cfg_type"` // CfgTypeLearned / CfgTypeUserCreated / CfgTypeGround / CfgTypeFederal (see above)
+	ID              uint32                      `json:"id"`
+	Comment         *string                     `json:"comment,omitempty"`
+	Group           *string                     `json:"group,omitempty"`
+	Event           *string                     `json:"event,omitempty"`
+	Conditions      *[]share.CLUSEventCondition `json:"conditions,omitempty"`
+	Actions         *[]string                   `json:"actions,omitempty"`
+	Webhooks        *[]string                   `json:"webhooks,omitempty"`
+	WebhookTemplate *string                     `json:"webhook_template,omitempty"`
+	WebhookSeverity *string                     `json:"webhook_severity,omitempty"`
+	WebhookDedupKey *string                     `json:"webhook_dedup_key,omitempty"`
+	QuarExceptions  *[]share.CLUSQuarException  `json:"quarantine_exceptions,omitempty"`
+	Disable         *bool                       `json:"disable,omitempty"`
+	CfgType         string                      `json:"cfg_type"`                   // CfgTypeLearned / CfgTypeUserCreated / CfgTypeGround / CfgTypeFederal (see above)
+	ExpireTS        *int64                      `json:"expire_timestamp,omitempty"` // 0: never expire
 }
 
 type RESTResponseRuleConfigData struct {
@@ -2647,6 +3248,11 @@ type RESTProcessProfileEntryConfig struct {
 	Action          string `json:"action"`
 	Group           string `json:"group"`
 	AllowFileUpdate bool   `json:"allow_update"`
+	Args            string `json:"args,omitempty"`
+	ArgsMatch       string `json:"args_match,omitempty"` // exact, prefix or regex. Empty: any arguments
+	Unit            string `json:"unit,omitempty"`       // systemd unit of the host service, "nodes" group only
+	// Optional expiration time of a temporary entry, the entry is removed by the controller when it expires.
+	ExpireTS int64 `json:"expire_timestamp,omitempty"`
 }
 
 type RESTProcessProfileEntry struct {
@@ -2659,8 +3265,12 @@ type RESTProcessProfileEntry struct {
 	Uuid             string `json:"uuid"`
 	Group            string `json:"group,omitempty"`
 	AllowFileUpdate  bool   `json:"allow_update"`
+	Args             string `json:"args,omitempty"`
+	ArgsMatch        string `json:"args_match,omitempty"`
+	Unit             string `json:"unit,omitempty"`
 	CreatedTimeStamp int64  `json:"created_timestamp"`
 	UpdatedTimeStamp int64  `json:"last_modified_timestamp"`
+	ExpireTimeStamp  int64  `json:"expire_timestamp,omitempty"`
 }
 
 type RESTProcessProfile struct {
@@ -2670,6 +3280,20 @@ type RESTProcessProfile struct {
 	Baseline     string                     `json:"baseline"`
 	Mode         string                     `json:"mode"`
 	ProcessList  []*RESTProcessProfileEntry `json:"process_list"`
+	Syscalls     []string                   `json:"syscalls,omitempty"` // sampled system calls, x86_64 enforcers only
+	ZeroDrift    *RESTZeroDrift             `json:"zero_drift,omitempty"`
+}
+
+type RESTZeroDrift struct {
+	ReportOnly bool     `json:"report_only"`
+	Paths      []string `json:"paths"`
+	Names      []string `json:"names"`
+}
+
+type RESTZeroDriftConfig struct {
+	ReportOnly *bool     `json:"report_only,omitempty"`
+	Paths      *[]string `json:"paths,omitempty"`
+	Names      *[]string `json:"names,omitempty"`
 }
 
 type RESTProcessProfileData struct {
@@ -2685,6 +3309,7 @@ type RESTProcessProfileConfig struct {
 	AlertDisable   *bool                            `json:"alert_disabled,omitempty"`
 	HashEnable     *bool                            `json:"hash_enabled,omitempty"`
 	Baseline       *string                          `json:"baseline,omitempty"`
+	ZeroDrift      *RESTZeroDriftConfig             `json:"zero_drift,omitempty"`
 	ProcessChgList *[]RESTProcessProfileEntryConfig `json:"process_change_list,omitempty"`
 	ProcessDelList *[]RESTProcessProfileEntryConfig `json:"process_delete_list,omitempty"`
 }
@@ -2788,6 +3413,7 @@ type RESTDlpSensorConfig struct {
 	RuleChgList *[]RESTDlpRule `json:"change,omitempty"` //change list used by CLI
 	RuleDelList *[]RESTDlpRule `json:"delete,omitempty"` //delete list used by CLI
 	Rules       *[]RESTDlpRule `json:"rules,omitempty"`  //replace list used by GUI
+	Packs       *[]string      `json:"packs,omitempty"`  //rules of the dlp packs are added to the sensor
 	Comment     *string        `json:"comment,omitempty"`
 }
 
@@ -2795,6 +3421,16 @@ type RESTDlpSensorConfigData struct {
 	Config *RESTDlpSensorConfig `json:"config"`
 }
 
+type RESTDlpPack struct {
+	Name    string         `json:"name"`
+	Comment string         `json:"comment"`
+	Rules   []*RESTDlpRule `json:"rules"`
+}
+
+type RESTDlpPacksData struct {
+	Packs []*RESTDlpPack `json:"packs"`
+}
+
 type RESTDlpRuleConfig struct {
 	Name     string                 `json:"name"`
 	Patterns []RESTDlpCriteriaEntry `json:"patterns"`
@@ -2899,12 +3535,32 @@ type RESTWafSensorConfig struct {
 	RuleDelList *[]RESTWafRule `json:"delete,omitempty"` //delete list used by CLI
 	Rules       *[]RESTWafRule `json:"rules,omitempty"`  //replace list used by GUI
 	Comment     *string        `json:"comment,omitempty"`
+	ModSec      *RESTWafModSec `json:"modsec,omitempty"` // the converted rules are added to the replace list if it's used, otherwise to the change list
 }
 
 type RESTWafSensorConfigData struct {
 	Config *RESTWafSensorConfig `json:"config"`
 }
 
+type RESTWafModSec struct {
+	Rules       string `json:"rules"`                  // SecRule directives, such as the content of OWASP CRS rule files
+	MinSeverity string `json:"min_severity,omitempty"` // emergency/alert/critical/error/warning/notice/info/debug or 0-7. empty for all rules
+}
+
+type RESTWafModSecData struct {
+	Config *RESTWafModSec `json:"config"`
+}
+
+type RESTWafModSecSkipped struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+type RESTWafModSecResult struct {
+	Rules   []*RESTWafRule          `json:"rules"`
+	Skipped []*RESTWafModSecSkipped `json:"skipped"`
+}
+
 type RESTWafSensorData struct {
 	Sensor *RESTWafSensor `json:"sensor"`
 }
@@ -2954,6 +3610,52 @@ type RESTWafGroupConfigData struct {
 	Config *RESTWafGroupConfig `json:"config"`
 }
 
+// threat signature
+const MinThreatSigID = 50000
+const MaxThreatSigID = 60000
+
+type RESTThreatSignature struct {
+	ID       uint32                 `json:"id"`
+	Name     string                 `json:"name"`
+	Comment  string                 `json:"comment,omitempty"`
+	Severity string                 `json:"severity"`
+	Action   string                 `json:"action"`
+	Patterns []RESTWafCriteriaEntry `json:"patterns"`
+	Enable   bool                   `json:"enable"`
+}
+
+// The signed content of a threat signature package
+type RESTThreatSignatureBundle struct {
+	Version     string                 `json:"version"`
+	Signatures  []*RESTThreatSignature `json:"signatures"`
+	MiningPools []string               `json:"mining_pools,omitempty"` // ip, cidr or fqdn of known mining pools
+}
+
+type RESTThreatSignaturePackage struct {
+	Bundle    string `json:"bundle"`    // base64 encoded RESTThreatSignatureBundle
+	Signature string `json:"signature"` // base64 encoded ECDSA signature of the bundle's sha256 digest
+}
+
+type RESTThreatSignatureImportData struct {
+	URL     string                      `json:"url,omitempty"` // https url to download the package if it's not provided
+	Package *RESTThreatSignaturePackage `json:"package,omitempty"`
+}
+
+type RESTThreatSignatureData struct {
+	Version     string                 `json:"version"`
+	ImportedAt  string                 `json:"imported_at"`
+	Signatures  []*RESTThreatSignature `json:"signatures"`
+	MiningPools []string               `json:"mining_pools"`
+}
+
+type RESTThreatSignatureConfig struct {
+	Enable *bool `json:"enable,omitempty"`
+}
+
+type RESTThreatSignatureConfigData struct {
+	Config *RESTThreatSignatureConfig `json:"config"`
+}
+
 type RESTCrdWafGroupSetting struct {
 	Name   string `json:"name"`
 	Action string `json:"action"`
@@ -3180,15 +3882,18 @@ const (
 )
 
 type RESTAdmissionRule struct { // see type CLUSAdmissionRule
-	ID       uint32                  `json:"id"`
-	Category string                  `json:"category"`
-	Comment  string                  `json:"comment"`
-	Criteria []*RESTAdmRuleCriterion `json:"criteria"`
-	Disable  bool                    `json:"disable"`
-	Critical bool                    `json:"critical"`
-	CfgType  string                  `json:"cfg_type"`  // CfgTypeLearned / CfgTypeUserCreated / CfgTypeGround / CfgTypeFederal (see above)
-	RuleType string                  `json:"rule_type"` // ValidatingExceptRuleType / ValidatingDenyRuleType (see above)
-	RuleMode string                  `json:"rule_mode"` // "" / share.AdmCtrlModeMonitor / share.AdmCtrlModeProtect
+	ID        uint32                  `json:"id"`
+	Category  string                  `json:"category"`
+	Comment   string                  `json:"comment"`
+	Criteria  []*RESTAdmRuleCriterion `json:"criteria"`
+	Disable   bool                    `json:"disable"`
+	Critical  bool                    `json:"critical"`
+	CfgType   string                  `json:"cfg_type"`             // CfgTypeLearned / CfgTypeUserCreated / CfgTypeGround / CfgTypeFederal (see above)
+	ManagedBy string                  `json:"managed_by,omitempty"` // primary cluster name of the federal rules on managed clusters
+	RuleType  string                  `json:"rule_type"`            // ValidatingExceptRuleType / ValidatingDenyRuleType (see above)
+	RuleMode  string                  `json:"rule_mode"`            // "" / share.AdmCtrlModeMonitor / share.AdmCtrlModeProtect
+
+	Controls []string `json:"regulatory_controls,omitempty"` // of the deny rules
 }
 
 type RESTAdmissionRuleData struct {
@@ -3261,6 +3966,7 @@ type RESTFileMonitorFilterConfig struct {
 	Behavior  string   `json:"behavior"`
 	Apps      []string `json:"applications"`
 	Group     string   `json:"group"`
+	Baseline  bool     `json:"baseline"`
 }
 
 type RESTFileMonitorFilter struct {
@@ -3270,6 +3976,7 @@ type RESTFileMonitorFilter struct {
 	Apps             []string `json:"applications"`
 	CfgType          string   `json:"cfg_type"`
 	Group            string   `json:"group,omitempty"`
+	Baseline         bool     `json:"baseline"`
 	CreatedTimeStamp int64    `json:"created_timestamp"`
 	UpdatedTimeStamp int64    `json:"last_modified_timestamp"`
 }
@@ -3309,6 +4016,27 @@ type RESTFileMonitorFileData struct {
 	Files []*RESTFileMonitorFile `json:"files"`
 }
 
+type RESTFileBaseline struct {
+	Path       string `json:"path"`
+	Hash       string `json:"hash"`
+	BaselineAt int64  `json:"baseline_timestamp"`
+	Current    string `json:"current_hash,omitempty"`
+	Drifted    bool   `json:"drifted"`
+}
+
+type RESTFileBaselineData struct {
+	Baselines []*RESTFileBaseline `json:"baselines"`
+}
+
+// re-baseline all files if no path is given
+type RESTFileBaselineReset struct {
+	Paths []string `json:"paths,omitempty"`
+}
+
+type RESTFileBaselineResetData struct {
+	Reset *RESTFileBaselineReset `json:"reset"`
+}
+
 // uuid for process rules
 type RESTProcessUuidEntry struct {
 	Active int                     `json:"active"`
@@ -3352,9 +4080,10 @@ type RESTRolePermitOptionInternal struct {
 }
 
 type RESTRolePermission struct {
-	ID    string `json:"id"`
-	Read  bool   `json:"read"`
-	Write bool   `json:"write"`
+	ID      string   `json:"id"`
+	Read    bool     `json:"read"`
+	Write   bool     `json:"write"`
+	Domains []string `json:"domains,omitempty"` // namespaces the permission is restricted to. Only for permissions supported in namespaces
 }
 
 type RESTUserRole struct {
@@ -3402,6 +4131,212 @@ type RESTImportTaskData struct {
 	Data *RESTImportTask `json:"data"`
 }
 
+const (
+	ConfigDiffCreate = "create"
+	ConfigDiffModify = "modify"
+	ConfigDiffDelete = "delete"
+)
+
+// object that would be changed by importing the config file
+type RESTConfigDiffEntry struct {
+	Section  string `json:"section"`
+	Endpoint string `json:"endpoint"`
+	Key      string `json:"key"`
+	Action   string `json:"action"` // create, modify or delete
+}
+
+type RESTConfigDiff struct {
+	Created  int                    `json:"created"`
+	Modified int                    `json:"modified"`
+	Deleted  int                    `json:"deleted"`
+	Entries  []*RESTConfigDiffEntry `json:"entries"`
+}
+
+type RESTConfigDiffData struct {
+	Diff *RESTConfigDiff `json:"diff"`
+}
+
+// Configuration backup
+type RESTBackupStorage struct {
+	Type        string `json:"type"`   // s3, gcs or azure
+	Bucket      string `json:"bucket"` // bucket of s3/gcs, or container of azure blob
+	Prefix      string `json:"prefix"`
+	Region      string `json:"region"`
+	Endpoint    string `json:"endpoint"` // for s3 compatible storage
+	AccessKey   string `json:"access_key"`
+	SecretKey   string `json:"secret_key,cloak"`
+	AccountName string `json:"account_name"` // azure storage account
+	SASToken    string `json:"sas_token,cloak"`
+}
+
+type RESTBackupConfig struct {
+	Enabled    bool              `json:"enabled"`
+	Interval   uint32            `json:"interval"`  // in hours
+	Retention  uint32            `json:"retention"` // number of backups kept in the storage
+	Passphrase string            `json:"passphrase,cloak"`
+	Storage    RESTBackupStorage `json:"storage"`
+}
+
+type RESTBackupStatus struct {
+	LastRunAt     time.Time `json:"last_run_at"`
+	LastBackupAt  time.Time `json:"last_backup_at"`
+	LastBackup    string    `json:"last_backup"`
+	LastError     string    `json:"last_error"`
+	LastRestoreAt time.Time `json:"last_restore_at"`
+	LastRestored  string    `json:"last_restored"`
+}
+
+type RESTBackupConfigData struct {
+	Config *RESTBackupConfig `json:"config"`
+	Status *RESTBackupStatus `json:"status"`
+}
+
+type RESTBackupConfigConfig struct {
+	Enabled    *bool              `json:"enabled,omitempty"`
+	Interval   *uint32            `json:"interval,omitempty"`
+	Retention  *uint32            `json:"retention,omitempty"`
+	Passphrase *string            `json:"passphrase,omitempty,cloak"`
+	Storage    *RESTBackupStorage `json:"storage,omitempty"`
+}
+
+type RESTBackupConfigConfigData struct {
+	Config *RESTBackupConfigConfig `json:"config"`
+}
+
+type RESTBackup struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RESTBackupData struct {
+	Backup *RESTBackup `json:"backup"`
+}
+
+type RESTBackupsData struct {
+	Backups []*RESTBackup `json:"backups"`
+}
+
+type RESTBackupRestoreReq struct {
+	Name       string   `json:"name"`
+	Passphrase string   `json:"passphrase,omitempty"` // the configured passphrase is used if it's empty
+	Sections   []string `json:"sections,omitempty"`   // user/policy/config. all sections are restored if it's empty
+	DryRun     bool     `json:"dry_run,omitempty"`    // return the objects to be changed without restoring
+}
+
+type RESTReportSchedule struct {
+	Name       string    `json:"name"`
+	Comment    string    `json:"comment"`
+	Type       string    `json:"type"`   // compliance, vulnerability
+	Format     string    `json:"format"` // pdf, csv
+	Interval   uint32    `json:"interval"`
+	Retention  uint32    `json:"retention"`
+	OrgName    string    `json:"org_name"`
+	Title      string    `json:"title"`
+	Domains    []string  `json:"domains"`
+	Tags       []string  `json:"tags"`
+	Severity   string    `json:"severity"`
+	Recipients []string  `json:"recipients"`
+	Upload     bool      `json:"upload"`
+	LastRunAt  time.Time `json:"last_run_at"`
+	LastError  string    `json:"last_error"`
+}
+
+type RESTReportScheduleData struct {
+	Schedule *RESTReportSchedule `json:"schedule"`
+}
+
+type RESTReportSchedulesData struct {
+	Schedules []*RESTReportSchedule `json:"schedules"`
+}
+
+type RESTReportScheduleConfig struct {
+	Name       string    `json:"name"`
+	Comment    *string   `json:"comment,omitempty"`
+	Type       *string   `json:"type,omitempty"`
+	Format     *string   `json:"format,omitempty"`
+	Interval   *uint32   `json:"interval,omitempty"`
+	Retention  *uint32   `json:"retention,omitempty"`
+	OrgName    *string   `json:"org_name,omitempty"`
+	Title      *string   `json:"title,omitempty"`
+	Domains    *[]string `json:"domains,omitempty"`
+	Tags       *[]string `json:"tags,omitempty"`
+	Severity   *string   `json:"severity,omitempty"`
+	Recipients *[]string `json:"recipients,omitempty"`
+	Upload     *bool     `json:"upload,omitempty"`
+}
+
+type RESTReportScheduleConfigData struct {
+	Config *RESTReportScheduleConfig `json:"config"`
+}
+
+type RESTReport struct {
+	ID          string    `json:"id"`
+	Schedule    string    `json:"schedule"`
+	Type        string    `json:"type"`
+	Format      string    `json:"format"`
+	Title       string    `json:"title"`
+	FileName    string    `json:"file_name"`
+	Size        int       `json:"size"`
+	Entries     int       `json:"entries"`
+	GeneratedAt time.Time `json:"generated_at"`
+	GeneratedBy string    `json:"generated_by"`
+}
+
+type RESTReportData struct {
+	Report *RESTReport `json:"report"`
+}
+
+type RESTReportsData struct {
+	Reports []*RESTReport `json:"reports"`
+}
+
+const RiskAcceptanceExpired = "expired"
+
+type RESTRiskAcceptance struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"` // vulnerability, compliance
+	Name          string    `json:"name"` // cve name or compliance check number
+	Domains       []string  `json:"domains"`
+	Images        []string  `json:"images"`
+	Justification string    `json:"justification"`
+	ExpireAt      time.Time `json:"expire_at"`
+	Status        string    `json:"status"` // proposed, approved, rejected, expired
+	ProposedBy    string    `json:"proposed_by"`
+	ProposedAt    time.Time `json:"proposed_at"`
+	ReviewedBy    string    `json:"reviewed_by"`
+	ReviewedAt    time.Time `json:"reviewed_at"`
+	ReviewComment string    `json:"review_comment"`
+}
+
+type RESTRiskAcceptanceData struct {
+	Acceptance *RESTRiskAcceptance `json:"acceptance"`
+}
+
+type RESTRiskAcceptancesData struct {
+	Acceptances []*RESTRiskAcceptance `json:"acceptances"`
+}
+
+type RESTRiskAcceptanceConfig struct {
+	Name          string   `json:"name"`
+	Domains       []string `json:"domains"`
+	Images        []string `json:"images"`
+	Justification string   `json:"justification"`
+	ExpireDays    uint32   `json:"expire_days"`
+}
+
+type RESTRiskAcceptanceConfigData struct {
+	Config *RESTRiskAcceptanceConfig `json:"config"`
+}
+
+type RESTRiskAcceptanceReview struct {
+	Comment string `json:"comment"`
+}
+
+type RESTRiskAcceptanceReviewData struct {
+	Review *RESTRiskAcceptanceReview `json:"review"`
+}
+
 // fed system config
 type RESTFedSystemConfig struct {
 	Webhooks []RESTWebhook `json:"webhooks"`
@@ -3443,6 +4378,13 @@ const (
 	ApikeyExpireCustomHour string = "hours"
 )
 
+// An apikey with scopes can only call the APIs covered by its scopes, on top of what its role allows
+const (
+	ApikeyScopeScanRead    string = "scan:read"
+	ApikeyScopePolicyWrite string = "policy:write"
+	ApikeyScopeFedAdmin    string = "fed:admin"
+)
+
 type RESTApikeyData struct {
 	Apikey *RESTApikey `json:"apikey"`
 }
@@ -3462,6 +4404,8 @@ type RESTApikey struct {
 	ExpirationTimestamp int64               `json:"expiration_timestamp"`   // used in GET
 	CreatedTimestamp    int64               `json:"created_timestamp"`      // used in GET
 	CreatedByEntity     string              `json:"created_by_entity"`      // it could be username or apikey (access key)
+	Scopes              []string            `json:"scopes,omitempty"`
+	LastUsedTimestamp   int64               `json:"last_used_timestamp"` // 0 means never used
 }
 
 type RESTApikeyCreation struct {
@@ -3471,6 +4415,7 @@ type RESTApikeyCreation struct {
 	Description     string              `json:"description"`
 	Role            string              `json:"role"`
 	RoleDomains     map[string][]string `json:"role_domains,omitempty"` // role -> domains
+	Scopes          []string            `json:"scopes,omitempty"`       // empty means no limitation other than the role
 }
 
 type RESTApikeyGeneratedData struct {
@@ -30,6 +30,7 @@ const (
 	FedStatusClusterPinging        = "pinging"                 // for describing joint cluster only. short-lived (between license update and the immediate ping)
 	FedStatusClusterSyncing        = "syncing"                 // for describing joint cluster only. short-lived (when joint cluster is applying fed rules)
 	FedStatusClusterPending        = "pending"                 // for describing joint cluster only. when master cluster is not sure joint cluster has finished the joining fed operation
+	FedStatusMasterUnreachable     = "primary_unreachable"     // for describing master cluster only. when joint cluster hasn't heard from master cluster for several polling intervals
 )
 
 // master cluster: a promoted cluster. One per-federation
@@ -60,21 +61,43 @@ type RESTFedJointClusterInfo struct {
 }
 
 type RESTFedMembereshipData struct { // including all clusters in the federation
-	FedRole            string                     `json:"fed_role"`                 // FedRoleMaster / FedRoleJoint / FedRoleNone (see above)
-	LocalRestInfo      share.CLUSRestServerInfo   `json:"local_rest_info"`          //
-	MasterCluster      *RESTFedMasterClusterInfo  `json:"master_cluster,omitempty"` // master cluster
-	JointClusters      []*RESTFedJointClusterInfo `json:"joint_clusters"`           // all non-master clusters in the federation
-	UseProxy           string                     `json:"use_proxy"`                // http / https
-	DeployRepoScanData bool                       `json:"deploy_repo_scan_data"`    // whether fed repo scan data deployment is enabled
+	FedRole            string                          `json:"fed_role"`                 // FedRoleMaster / FedRoleJoint / FedRoleNone (see above)
+	LocalRestInfo      share.CLUSRestServerInfo        `json:"local_rest_info"`          //
+	MasterCluster      *RESTFedMasterClusterInfo       `json:"master_cluster,omitempty"` // master cluster
+	JointClusters      []*RESTFedJointClusterInfo      `json:"joint_clusters"`           // all non-master clusters in the federation
+	UseProxy           string                          `json:"use_proxy"`                // http / https
+	DeployRepoScanData bool                            `json:"deploy_repo_scan_data"`    // whether fed repo scan data deployment is enabled
+	StandbyID          string                          `json:"standby_id,omitempty"`     // id of the joint cluster that can take over the federation
+	FedProxy           *RESTFedProxyConfig             `json:"fed_proxy,omitempty"`
+	SyncSelectors      map[string]*RESTFedSyncSelector `json:"sync_selectors,omitempty"` // only on primary cluster
 }
 
 type RESTFedConfigData struct { // including all clusters in the federation
-	PingInterval       *uint32                   `json:"ping_interval,omitempty"` // in minute
-	PollInterval       *uint32                   `json:"poll_interval,omitempty"` // in minute
-	Name               *string                   `json:"name,omitempty"`          // cluster name
-	RestInfo           *share.CLUSRestServerInfo `json:"rest_info,omitempty"`
-	UseProxy           *string                   `json:"use_proxy,omitempty"`   // http / https
-	DeployRepoScanData *bool                     `json:"deploy_repo_scan_data"` // whether fed repo scan data deployment is enabled
+	PingInterval       *uint32                          `json:"ping_interval,omitempty"` // in minute
+	PollInterval       *uint32                          `json:"poll_interval,omitempty"` // in minute
+	Name               *string                          `json:"name,omitempty"`          // cluster name
+	RestInfo           *share.CLUSRestServerInfo        `json:"rest_info,omitempty"`
+	UseProxy           *string                          `json:"use_proxy,omitempty"`      // http / https
+	DeployRepoScanData *bool                            `json:"deploy_repo_scan_data"`    // whether fed repo scan data deployment is enabled
+	FedProxy           *RESTFedProxyConfig              `json:"fed_proxy,omitempty"`      // empty url/client_cert/pinned_certs means removing the config
+	SyncSelectors      *map[string]*RESTFedSyncSelector `json:"sync_selectors,omitempty"` // replace all selectors. key is share.FedSyncObj type
+}
+
+// Without selector, all federal objects of the type are deployed to managed clusters. Groups are selected by name and
+// the rules are selected by id. The rules that reference an unselected group are not deployed either.
+type RESTFedSyncSelector struct {
+	Include []string `json:"include"` // only these objects are deployed if it's not empty
+	Exclude []string `json:"exclude"` // these objects are not deployed
+}
+
+// egress proxy, client cert & pinned remote certs for the federation requests sent by local cluster
+type RESTFedProxyConfig struct {
+	URL         string   `json:"url"`
+	Username    string   `json:"username"`
+	Password    string   `json:"password,cloak"`
+	ClientCert  string   `json:"client_cert"`      // PEM
+	ClientKey   string   `json:"client_key,cloak"` // PEM
+	PinnedCerts []string `json:"pinned_certs"`     // sha256 fingerprints of remote fed rest server certs, like "ab:cd:..." or "abcd..."
 }
 
 type RESTFedPromoteReqData struct {
@@ -104,6 +127,7 @@ type RESTFedJoinReq struct { // from manager to local cluster
 	JoinToken     string                    `json:"join_token"`                // generated by the master cluster, i.e. RESTFedJoinToken.JoinToken
 	JointRestInfo *share.CLUSRestServerInfo `json:"joint_rest_info,omitempty"` // rest info about this joint cluster
 	UseProxy      *string                   `json:"use_proxy,omitempty"`
+	FedProxy      *RESTFedProxyConfig       `json:"fed_proxy,omitempty"`
 }
 
 type RESTFedJoinReqInternal struct { // from joining cluster to master cluster for joining federation.
@@ -144,6 +168,32 @@ type RESTFedRemovedReqInternal struct { // from master cluster to joint cluster
 	User string `json:"user"` // current operating user
 }
 
+type RESTFedStandbyReq struct { // from manager to master cluster
+	ID string `json:"id"` // id of the joint cluster to be the standby master cluster. empty means no standby master cluster
+}
+
+type RESTFedFailoverReq struct { // from manager to the standby joint cluster
+	Force bool `json:"force"` // true means taking over federation even the master cluster is still reachable
+}
+
+type RESTFedFailoverResp struct {
+	MasterCluster RESTFedMasterClusterInfo `json:"master_cluster"`   // info about this new master cluster
+	Rekeyed       []string                 `json:"rekeyed_clusters"` // names of the joint clusters that switch to this master cluster
+	Failed        []string                 `json:"failed_clusters"`  // names of the joint clusters that cannot be reached. they need to join federation again
+}
+
+type RESTFedFailoverReqInternal struct { // from the new master cluster to joint cluster for switching master cluster
+	Token         string                    `json:"token"`          // generated using joint cluster's old secret
+	StandbyID     string                    `json:"standby_id"`     // id of the joint cluster that takes over the federation
+	FedKvVersion  string                    `json:"fed_kv_version"` // kv version in the code of the new master cluster
+	PollInterval  uint32                    `json:"poll_interval"`  // in minute
+	Secret        string                    `json:"secret"`         // new secret of the joint cluster
+	CACert        string                    `json:"ca_cert"`        // ca cert for the federated rest server in the new master cluster
+	ClientKey     string                    `json:"client_key"`     // new client key for the joint cluster
+	ClientCert    string                    `json:"client_cert"`    // new client cert for the joint cluster
+	MasterCluster *RESTFedMasterClusterInfo `json:"master_cluster"` // info about the new master cluster
+}
+
 type RESTFedTokenResp struct {
 	Token string `json:"token"` // for issued by remote joint cluster
 }
@@ -202,14 +252,15 @@ type RESTFedPingResp struct { // from manager to joint cluster
 
 // for polling fed rules/settings from joint clusters to master cluster
 type RESTPollFedRulesReq struct {
-	ID           string            `json:"id"`                     // id of joint cluster
-	Name         string            `json:"name"`                   // name of joint cluster
-	JointTicket  string            `json:"joint_ticket"`           // generated using joint cluster's secret
-	FedKvVersion string            `json:"fed_kv_version"`         // kv version in the code of joint cluster
-	RestVersion  string            `json:"rest_version,omitempty"` // rest version in the code of joint cluster
-	Revisions    map[string]uint64 `json:"revisions"`              // key is fed rules type, value is the revision
-	CspType      string            `json:"csp_type"`               // joint cluster's billing csp type
-	Nodes        int               `json:"nodes"`
+	ID           string                          `json:"id"`                     // id of joint cluster
+	Name         string                          `json:"name"`                   // name of joint cluster
+	JointTicket  string                          `json:"joint_ticket"`           // generated using joint cluster's secret
+	FedKvVersion string                          `json:"fed_kv_version"`         // kv version in the code of joint cluster
+	RestVersion  string                          `json:"rest_version,omitempty"` // rest version in the code of joint cluster
+	Revisions    map[string]uint64               `json:"revisions"`              // key is fed rules type, value is the revision
+	CspType      string                          `json:"csp_type"`               // joint cluster's billing csp type
+	Nodes        int                             `json:"nodes"`
+	QuarImages   []*share.CLUSFedQuarantineImage `json:"quarantine_images,omitempty"` // images triggered fed-quarantine action on joint cluster
 }
 
 type RESTFedScanDataRevs struct {
@@ -219,13 +270,15 @@ type RESTFedScanDataRevs struct {
 }
 
 type RESTPollFedRulesResp struct {
-	Result             int                 `json:"result"`                // value: _fedSuccess/....
-	PollInterval       uint32              `json:"poll_interval"`         // in minute
-	Settings           []byte              `json:"settings,omitempty"`    // marshall of RESTFedRulesSettings, which contains only modified settings (for ~5.0.x)
-	Revisions          map[string]uint64   `json:"revisions"`             // key is fed rules type, value is the revision. It contains only revisions of modified settings
-	ScanDataRevs       RESTFedScanDataRevs `json:"scan_data_revs"`        // the latest revisions of all the fed registry/repo scan data on master cluster
-	DeployRepoScanData bool                `json:"deploy_repo_scan_data"` // for informing whether master cluster deploys repo scan data to managed clusters
-	CspType            string              `json:"csp_type"`              // master's billing csp type
+	Result             int                           `json:"result"`                // value: _fedSuccess/....
+	PollInterval       uint32                        `json:"poll_interval"`         // in minute
+	Settings           []byte                        `json:"settings,omitempty"`    // marshall of RESTFedRulesSettings, which contains only modified settings (for ~5.0.x)
+	Revisions          map[string]uint64             `json:"revisions"`             // key is fed rules type, value is the revision. It contains only revisions of modified settings
+	ScanDataRevs       RESTFedScanDataRevs           `json:"scan_data_revs"`        // the latest revisions of all the fed registry/repo scan data on master cluster
+	DeployRepoScanData bool                          `json:"deploy_repo_scan_data"` // for informing whether master cluster deploys repo scan data to managed clusters
+	CspType            string                        `json:"csp_type"`              // master's billing csp type
+	StandbyID          string                        `json:"standby_id,omitempty"`  // id of the joint cluster that can take over the federation
+	Standby            *share.CLUSFedStandbySnapshot `json:"standby,omitempty"`     // only for the standby joint cluster
 }
 
 type RESTPollFedScanDataReq struct {
@@ -290,3 +343,113 @@ type RESTNvUsage struct {
 	LocalClusterUsage RESTClusterCspUsage `json:"local_cluster_usage"` // local cluster' cspType & usage
 	CspConfigFrom     string              `json:"csp_config_from"`     // "master"/"joint"/ "": where is csp-config data from when collecting support config
 }
+
+// fed reports, only available on master cluster
+type RESTFedReportInternalResp struct { // from joint cluster to master cluster for the summarized scan/benchmark results
+	Result int                         `json:"result"` // value: _fedSuccess/....
+	Report *share.CLUSFedClusterReport `json:"report,omitempty"`
+}
+
+type RESTFedClusterReportSummary struct {
+	ID                   string `json:"id"`
+	Name                 string `json:"name"`
+	CollectedAtTimeStamp int64  `json:"collected_at_timestamp"`
+	CollectedAt          string `json:"collected_at"`
+	Images               int    `json:"images"`
+	HighVuls             int    `json:"high"` // sum of high vulnerabilities of all images
+	MedVuls              int    `json:"medium"`
+	ComplianceFailures   int    `json:"compliance_failures"` // failed checks on all workloads & nodes
+}
+
+type RESTFedReportData struct {
+	Clusters []*RESTFedClusterReportSummary `json:"clusters"`
+}
+
+type RESTFedImageVulReport struct {
+	Digest   string   `json:"digest"` // manifest digest if any managed cluster knows it; otherwise it's the image id
+	ImageIDs []string `json:"image_ids"`
+	Names    []string `json:"names"`
+	Clusters []string `json:"clusters"` // names of the clusters that have this image
+	HighVuls int      `json:"high"`
+	MedVuls  int      `json:"medium"`
+}
+
+type RESTFedVulReportData struct {
+	Images []*RESTFedImageVulReport `json:"images"`
+}
+
+type RESTFedComplianceReport struct {
+	Name        string   `json:"name"` // test number
+	Category    string   `json:"category"`
+	Level       string   `json:"level"`
+	Description string   `json:"description"`
+	Clusters    []string `json:"clusters"` // names of the clusters that fail this check
+	Workloads   int      `json:"workloads"`
+	Nodes       int      `json:"nodes"`
+}
+
+type RESTFedComplianceReportData struct {
+	Compliances []*RESTFedComplianceReport `json:"compliances"`
+}
+
+type RESTFedReportTrendPoint struct {
+	CollectedAtTimeStamp int64 `json:"collected_at_timestamp"`
+	Clusters             int   `json:"clusters"`
+	Images               int   `json:"images"` // distinct images in the federation
+	HighVuls             int   `json:"high"`
+	MedVuls              int   `json:"medium"`
+	ComplianceFailures   int   `json:"compliance_failures"`
+}
+
+type RESTFedReportTrendData struct {
+	Trend []*RESTFedReportTrendPoint `json:"trend"`
+}
+
+// endpoint in the conversation map across the clusters in federation
+type RESTFedConverEndpoint struct {
+	ID          string `json:"id"` // {cluster id}/{service}
+	ClusterID   string `json:"cluster_id"`
+	ClusterName string `json:"cluster_name"`
+	Service     string `json:"service"` // learned group. empty when the service in the cluster is unknown
+	Domain      string `json:"domain"`
+}
+
+type RESTFedConversation struct {
+	From         string   `json:"from"`
+	To           string   `json:"to"`
+	Ports        []string `json:"ports"`
+	Applications []string `json:"applications"`
+	Bytes        uint64   `json:"bytes"`
+	Sessions     uint32   `json:"sessions"`
+	LastSeenAt   string   `json:"last_seen_at"`
+	Confirmed    bool     `json:"confirmed"` // flows are seen in both clusters
+}
+
+type RESTFedConversationData struct {
+	Endpoints     []*RESTFedConverEndpoint `json:"endpoints"`
+	Conversations []*RESTFedConversation   `json:"conversations"`
+}
+
+type RESTFedQuarantineImage struct {
+	Digest      string `json:"digest"` // image id
+	Name        string `json:"name"`
+	Comment     string `json:"comment"`
+	User        string `json:"user"`    // who added the image by REST api
+	Cluster     string `json:"cluster"` // in which cluster the image triggered fed-quarantine response rule action
+	CreatedAtTS int64  `json:"created_at_timestamp"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type RESTFedQuarantineImagesData struct {
+	Images []*RESTFedQuarantineImage `json:"images"`
+}
+
+type RESTFedQuarantineImageConfig struct {
+	Digest  string  `json:"digest"`
+	Name    *string `json:"name,omitempty"`
+	Comment *string `json:"comment,omitempty"`
+}
+
+type RESTFedQuarantineImageConfigData struct {
+	Config *RESTFedQuarantineImageConfig `json:"config"`
+}
@@ -7,12 +7,23 @@ import (
 )
 
 const (
-	CategoryEvent     = "event"
-	CategoryViolation = "violation" // merged into CategoryRuntime in config, stil used in log category
-	CategoryThreat    = "threat"    // merged into CategoryRuntime in config, stil used in log category
-	CategoryIncident  = "incident"  // merged into CategoryRuntime in config, stil used in log category
-	CategoryRuntime   = "security-event"
-	CategoryAudit     = "audit"
+	CategoryEvent       = "event"
+	CategoryViolation   = "violation" // merged into CategoryRuntime in config, stil used in log category
+	CategoryThreat      = "threat"    // merged into CategoryRuntime in config, stil used in log category
+	CategoryIncident    = "incident"  // merged into CategoryRuntime in config, stil used in log category
+	CategoryRuntime     = "security-event"
+	CategoryAudit       = "audit"
+	CategoryConfigAudit = "config-audit" // state-changing REST calls, not in the default syslog categories
+)
+
+// log types pushed by the log stream
+const (
+	LogTypeActivity  = "activity"
+	LogTypeEvent     = "event"
+	LogTypeThreat    = "threat"
+	LogTypeIncident  = "incident"
+	LogTypeViolation = "violation"
+	LogTypeAudit     = "audit"
 )
 
 // syslog related
@@ -86,6 +97,8 @@ type LogCommon struct {
 	HostName          string `json:"host_name"`
 	AgentID           string `json:"enforcer_id"`
 	AgentName         string `json:"enforcer_name"`
+	RepeatCount       uint32 `json:"repeat_count,omitempty"`   // identical logs in the correlation window, only in the exported logs
+	CorrelationID     string `json:"correlation_id,omitempty"` // shared by the related exported logs
 }
 
 const (
@@ -156,6 +169,22 @@ const (
 	EventNameGroupAutoPromote            = "Group.Auto.Promote"
 	EventNameAuthDefAdminPwdUnchanged    = "User.Password.Alert"
 	EventNameScannerAutoScaleDisabled    = "Configuration.ScannerAutoScale.Disabled"
+	EventNameCrdDriftRepaired            = "Crd.Drift.Repair" // for crd Config repaired due to out-of-band modification
+	EventNameAuthAccountRecovered        = "User.Account.Recover"
+	EventNameConfigBackup                = "Configuration.Backup"
+	EventNameConfigBackupFail            = "Configuration.Backup.Failed"
+	EventNameConfigRestore               = "Configuration.Restore"
+	EventNameKvStoreSizeHigh             = "Controller.KV.Size.High"
+	EventNameKvKeyRotated                = "Controller.KV.Key.Rotate"
+	EventNameReportGenerated             = "Report.Generate"
+	EventNameReportGenerateFail          = "Report.Generate.Failed"
+	EventNameRiskAcceptanceProposed      = "RiskAcceptance.Propose"
+	EventNameRiskAcceptanceApproved      = "RiskAcceptance.Approve"
+	EventNameRiskAcceptanceRejected      = "RiskAcceptance.Reject"
+	EventNamePolicyRuleExpired           = "Policy.Rule.Expire"
+	EventNamePolicyChangeSubmitted       = "Policy.Change.Submit"
+	EventNamePolicyChangeApproved        = "Policy.Change.Approve"
+	EventNamePolicyChangeRejected        = "Policy.Change.Reject"
 )
 
 // TODO: these are not events but incidents
@@ -174,6 +203,12 @@ const (
 	EventNameContainerTunnelDetected      = "Container.Tunnel.Detected"
 	EventNameProcessProfileViolation      = "Process.Profile.Violation" // container
 	EventNameHostProcessProfileViolation  = "Host.Process.Violation"    // host
+	EventNameHostFileContentDrift         = "Host.FileContent.Drift"
+	EventNameContainerFileContentDrift    = "Container.FileContent.Drift"
+	EventNameHostKernelLoad               = "Host.Kernel.Load"
+	EventNameContainerKernelLoad          = "Container.Kernel.Load"
+	EventNameContainerEscape              = "Container.Escape.Attempt"
+	EventNameContainerCryptoMining        = "Container.Crypto.Mining"
 )
 
 // TODO: these are audit related
@@ -206,6 +241,12 @@ var incidentNameList []string = []string{
 	EventNameHostTunnelDetected,
 	EventNameProcessProfileViolation,
 	EventNameHostProcessProfileViolation,
+	EventNameHostFileContentDrift,
+	EventNameContainerFileContentDrift,
+	EventNameHostKernelLoad,
+	EventNameContainerKernelLoad,
+	EventNameContainerEscape,
+	EventNameContainerCryptoMining,
 }
 
 const (
@@ -248,6 +289,28 @@ type Event struct {
 	Msg             string            `json:"message"`
 }
 
+// A state-changing REST call in the config change audit trail. Records are chained: Hash is computed over
+// the record with Hash left empty, and PrevHash is the Hash of the record before it
+type ConfigAudit struct {
+	Seq               uint64            `json:"seq"`
+	ReportedTimeStamp int64             `json:"reported_timestamp"`
+	ReportedAt        string            `json:"reported_at"`
+	ControllerID      string            `json:"controller_id"`
+	ControllerName    string            `json:"controller_name"`
+	User              string            `json:"user"`
+	UserRoles         map[string]string `json:"user_roles"` // domain -> role
+	UserAddr          string            `json:"user_addr"`
+	UserSession       string            `json:"user_session"`
+	RESTMethod        string            `json:"rest_method"`
+	RESTRequest       string            `json:"rest_request"`
+	RESTBody          string            `json:"rest_body,omitempty"` // masked request body
+	RESTBefore        string            `json:"before,omitempty"`    // masked object before the change
+	RESTAfter         string            `json:"after,omitempty"`     // masked object after the change
+	Msg               string            `json:"message"`
+	PrevHash          string            `json:"prev_hash"`
+	Hash              string            `json:"hash"`
+}
+
 const (
 	ThreatActionMonitor = "alert"
 	ThreatActionAllow   = "allow"
@@ -262,38 +325,41 @@ const (
 
 type Threat struct {
 	LogCommon
-	ID              string `json:"id"`
-	ThreatID        uint32 `json:"threat_id"`
-	ClientWL        string `json:"client_workload_id"`
-	ClientWLName    string `json:"client_workload_name"`
-	ClientWLDomain  string `json:"client_workload_domain,omitempty"`
-	ClientWLImage   string `json:"client_workload_image,omitempty"`
-	ClientWLService string `json:"client_workload_service,omitempty"`
-	ServerWL        string `json:"server_workload_id"`
-	ServerWLName    string `json:"server_workload_name"`
-	ServerWLDomain  string `json:"server_workload_domain,omitempty"`
-	ServerWLImage   string `json:"server_workload_image,omitempty"`
-	ServerWLService string `json:"server_workload_service,omitempty"`
-	Severity        string `json:"severity"`
-	Action          string `json:"action"`
-	Count           uint32 `json:"count"`
-	EtherType       uint16 `json:"ether_type"`
-	ClientPort      uint16 `json:"client_port"`
-	ServerPort      uint16 `json:"server_port"`
-	ServerConnPort  uint16 `json:"server_conn_port"`
-	ICMPCode        uint8  `json:"icmp_code"`
-	ICMPType        uint8  `json:"icmp_type"`
-	IPProto         uint8  `json:"ip_proto"`
-	ClientIP        string `json:"client_ip"`
-	ServerIP        string `json:"server_ip"`
-	Application     string `json:"application"`
-	Sensor          string `json:"sensor"`
-	Group           string `json:"group"`
-	Target          string `json:"target"`
-	Monitor         bool   `json:"monitor"`
-	CapLen          uint16 `json:"cap_len,omitempty"`
-	Packet          string `json:"packet,omitempty"`
-	Msg             string `json:"message"`
+	ID              string   `json:"id"`
+	ThreatID        uint32   `json:"threat_id"`
+	ClientWL        string   `json:"client_workload_id"`
+	ClientWLName    string   `json:"client_workload_name"`
+	ClientWLDomain  string   `json:"client_workload_domain,omitempty"`
+	ClientWLImage   string   `json:"client_workload_image,omitempty"`
+	ClientWLService string   `json:"client_workload_service,omitempty"`
+	ServerWL        string   `json:"server_workload_id"`
+	ServerWLName    string   `json:"server_workload_name"`
+	ServerWLDomain  string   `json:"server_workload_domain,omitempty"`
+	ServerWLImage   string   `json:"server_workload_image,omitempty"`
+	ServerWLService string   `json:"server_workload_service,omitempty"`
+	Severity        string   `json:"severity"`
+	Action          string   `json:"action"`
+	Count           uint32   `json:"count"`
+	EtherType       uint16   `json:"ether_type"`
+	ClientPort      uint16   `json:"client_port"`
+	ServerPort      uint16   `json:"server_port"`
+	ServerConnPort  uint16   `json:"server_conn_port"`
+	ICMPCode        uint8    `json:"icmp_code"`
+	ICMPType        uint8    `json:"icmp_type"`
+	IPProto         uint8    `json:"ip_proto"`
+	ClientIP        string   `json:"client_ip"`
+	ServerIP        string   `json:"server_ip"`
+	Application     string   `json:"application"`
+	Sensor          string   `json:"sensor"`
+	Group           string   `json:"group"`
+	Target          string   `json:"target"`
+	Monitor         bool     `json:"monitor"`
+	CapLen          uint16   `json:"cap_len,omitempty"`
+	Packet          string   `json:"packet,omitempty"`
+	Msg             string   `json:"message"`
+	Mitre           []string `json:"mitre_techniques,omitempty"`
+	MitreTactics    []string `json:"mitre_tactics,omitempty"`
+	Controls        []string `json:"regulatory_controls,omitempty"`
 }
 
 type Violation struct {
@@ -320,6 +386,8 @@ type Violation struct {
 	ServerIP      string   `json:"server_ip"`
 	FQDN          string   `json:"fqdn"`
 	Xff           bool     `json:"xff"`
+	MeshID        string   `json:"mesh_id,omitempty"`
+	Controls      []string `json:"regulatory_controls,omitempty"`
 }
 
 const (
@@ -365,6 +433,9 @@ type Incident struct {
 	AggregationFrom int64    `json:"aggregation_from,omitempty"`
 	Count           int      `json:"count,omitempty"`
 	Msg             string   `json:"message"`
+	Mitre           []string `json:"mitre_techniques,omitempty"`
+	MitreTactics    []string `json:"mitre_tactics,omitempty"`
+	Controls        []string `json:"regulatory_controls,omitempty"`
 }
 
 type Audit struct {
@@ -115,3 +115,24 @@ type PrincipalCollection struct {
 	Collection
 	Data []Principal `json:"data,omitempty"`
 }
+
+type ProjectRoleTemplateBinding struct {
+	Resource
+	Annotations      map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Created          string            `json:"created,omitempty" yaml:"created,omitempty"`
+	CreatorID        string            `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
+	GroupID          string            `json:"groupId,omitempty" yaml:"groupId,omitempty"`
+	GroupPrincipalID string            `json:"groupPrincipalId,omitempty" yaml:"groupPrincipalId,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Name             string            `json:"name,omitempty" yaml:"name,omitempty"`
+	NamespaceId      string            `json:"namespaceId,omitempty" yaml:"namespaceId,omitempty"`
+	ProjectID        string            `json:"projectId,omitempty" yaml:"projectId,omitempty"`
+	RoleTemplateID   string            `json:"roleTemplateId,omitempty" yaml:"roleTemplateId,omitempty"`
+	UserID           string            `json:"userId,omitempty" yaml:"userId,omitempty"`
+	UserPrincipalID  string            `json:"userPrincipalId,omitempty" yaml:"userPrincipalId,omitempty"`
+}
+
+type ProjectRoleTemplateBindingCollection struct {
+	Collection
+	Data []ProjectRoleTemplateBinding `json:"data,omitempty"`
+}
@@ -16,11 +16,28 @@ const (
 
 type AutoModeHelper interface {
 	ConfigureCompleteDuration(mover int, dComplete time.Duration)
+	ConfigureGroupDuration(group string, d2m, m2p time.Duration)
 	AddGroup(mover int, group string) bool
 	RemoveGroup(group string)
 	Counts(mover int) int
 	Enabled() (bool, bool)
+	GroupEnabled(mover int, group string) bool
 	List(mover int) []string
+	Pending(mover int) []*PendingTransition
+}
+
+// The progress of a group waiting for the promotion
+type PendingTransition struct {
+	Group    string
+	Runs     int           // good probes
+	Life     int           // good probes to promote
+	Interval time.Duration // probe interval
+}
+
+// The dwell times of a group override the system-wide complete periods
+type groupDuration struct {
+	d2m time.Duration
+	m2p time.Duration
 }
 
 //////
@@ -37,6 +54,7 @@ type automode_ctx struct {
 	// data
 	d2m_members	map[string]*task
 	m2p_members map[string]*task
+	grp_durs    map[string]*groupDuration
 
 	// parameters
 	d2m_cmpl    time.Duration	// complete period
@@ -68,6 +86,7 @@ func Init(timerWheel *utils.TimerWheel, test_cb testFunc, decision_cb decisionFu
 	atmo_ctx = &automode_ctx {
 		d2m_members: make(map[string]*task),
 		m2p_members: make(map[string]*task),
+		grp_durs:    make(map[string]*groupDuration),
 		timerWheel:  timerWheel,
 		d2m_cmpl:    discover_complete,		// complete period
 		m2p_cmpl:    monitor_complete,
@@ -141,21 +160,56 @@ func (ctx *automode_ctx) ConfigureCompleteDuration(mover int, dComplete time.Dur
 	}
 }
 
+// A zero duration removes the dwell time of the group, the system-wide setting is applied
+func (ctx *automode_ctx) ConfigureGroupDuration(group string, d2m, m2p time.Duration) {
+	ctx.lock()
+	defer ctx.unlock()
+	if d2m == 0 && m2p == 0 {
+		delete(ctx.grp_durs, group)
+	} else {
+		log.WithFields(log.Fields{"group": group, "d2m": d2m, "m2p": m2p}).Debug("ATMO:")
+		ctx.grp_durs[group] = &groupDuration{d2m: d2m, m2p: m2p}
+	}
+}
+
+func (ctx *automode_ctx) groupDuration(mover int, group string) time.Duration {
+	if dur, ok := ctx.grp_durs[group]; ok {
+		switch mover {
+		case Discover2Monitor:
+			return dur.d2m
+		case Monitor2Protect:
+			return dur.m2p
+		}
+	}
+	return 0
+}
+
+// The group is promoted if the transition is enabled system-wide or has its own dwell time
+func (ctx *automode_ctx) GroupEnabled(mover int, group string) bool {
+	ctx.lock()
+	defer ctx.unlock()
+	switch mover {
+	case Discover2Monitor:
+		return ctx.bD2M || ctx.groupDuration(mover, group) > 0
+	case Monitor2Protect:
+		return ctx.bM2P || ctx.groupDuration(mover, group) > 0
+	}
+	return false
+}
+
 func (ctx *automode_ctx) AddGroup(mover int, group string) bool {
 	switch mover {
 		case Discover2Monitor:
 			ctx.removeMember(Monitor2Protect, group)
-			if !ctx.bD2M {
-				return false
-			}
 		case Monitor2Protect:
 			ctx.removeMember(Discover2Monitor, group)
-			if !ctx.bM2P {
-				return false
-			}
 		default:
 			return false
 	}
+	if !ctx.GroupEnabled(mover, group) {
+		ctx.removeMember(mover, group)
+		return false
+	}
 	return ctx.addMember(mover, group)
 }
 
@@ -165,6 +219,10 @@ func (ctx *automode_ctx) RemoveGroup(group string) {
 	// where is it?
 	ctx.removeMember(Discover2Monitor, group)
 	ctx.removeMember(Monitor2Protect, group)
+
+	ctx.lock()
+	delete(ctx.grp_durs, group)
+	ctx.unlock()
 }
 
 func (ctx *automode_ctx) Counts(mover int) int {
@@ -200,6 +258,27 @@ func (ctx *automode_ctx) List(mover int) []string {
 	return list
 }
 
+func (ctx *automode_ctx) Pending(mover int) []*PendingTransition {
+	var members map[string]*task
+
+	ctx.lock()
+	defer ctx.unlock()
+	switch mover {
+	case Discover2Monitor:
+		members = ctx.d2m_members
+	case Monitor2Protect:
+		members = ctx.m2p_members
+	default:
+		return nil
+	}
+
+	list := make([]*PendingTransition, 0, len(members))
+	for n, t := range members {
+		list = append(list, &PendingTransition{Group: n, Runs: t.runs, Life: ctx.lifeLocked(mover, n), Interval: t.interval})
+	}
+	return list
+}
+
 func (ctx *automode_ctx) Enabled() (bool, bool) {
 	// log.WithFields(log.Fields{"d2m": ctx.bD2M, "m2p": ctx.bM2P}).Debug("ATMO:")
 	return ctx.bD2M, ctx.bM2P
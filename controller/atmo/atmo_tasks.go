@@ -10,7 +10,7 @@ import (
 
 ////////////////////
 type probeFunc func(mover int, id string, probeDuration time.Duration) (bool, error)
-type lifeCntFunc func(mover int) int
+type lifeCntFunc func(mover int, group string) int
 type completeFunc func(mover int, group string, err error) error
 type task struct {
 	mover		int
@@ -56,7 +56,7 @@ func (t *task) Expire() {
 		}
 	}
 
-	if t.runs >= t.lifeFunc(t.mover) {
+	if t.runs >= t.lifeFunc(t.mover, t.id) {
 		// completed
 		t.CancelTimer()
 		go t.cmplFunc(t.mover, t.id, nil)
@@ -71,11 +71,24 @@ func (ctx *automode_ctx) prober(mover int, group string, dur time.Duration) (boo
 	return ctx.testfn(mover, group, dur)
 }
 
-func (ctx *automode_ctx) life(mover int) int {
+func (ctx *automode_ctx) life(mover int, group string) int {
+	ctx.lock()
+	defer ctx.unlock()
+	return ctx.lifeLocked(mover, group)
+}
+
+// the dwell time of the group takes precedence
+func (ctx *automode_ctx) lifeLocked(mover int, group string) int {
 	switch mover {
 	case Discover2Monitor:
+		if dur := ctx.groupDuration(mover, group); dur > 0 {
+			return (int)(dur / ctx.d2m_itl)
+		}
 		return ctx.d2m_life
 	case Monitor2Protect:
+		if dur := ctx.groupDuration(mover, group); dur > 0 {
+			return (int)(dur / ctx.m2p_itl)
+		}
 		return ctx.m2p_life
 	}
 	return 0 // kick-out unknown
@@ -164,6 +177,9 @@ func (ctx *automode_ctx) pruneMembers(mover int) {
 	}
 
 	for group, task := range members {
+		if ctx.groupDuration(mover, group) > 0 {
+			continue // scheduled by its own dwell time
+		}
 		task.CancelTimer()
 		delete(members, group)
 	}
@@ -96,4 +96,42 @@ func testAddGroups(t *testing.T) {
 			break
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestGroupDuration(t *testing.T) {
+	ctx := initEnv()
+
+	// system-wide auto mode is disabled, only the group with the dwell time is scheduled
+	ctx.ConfigureGroupDuration("nv.g1", time.Second*30, 0)
+	if ok := ctx.AddGroup(Discover2Monitor, "nv.g1"); !ok {
+		t.Errorf("Error: failed to add nv.g1\n")
+	}
+	if ok := ctx.AddGroup(Discover2Monitor, "nv.g2"); ok {
+		t.Errorf("Error: nv.g2 should not be added\n")
+	}
+	if ok := ctx.AddGroup(Monitor2Protect, "nv.g1"); ok {
+		t.Errorf("Error: nv.g1 should not be added\n")
+	}
+	if ok := ctx.AddGroup(Discover2Monitor, "nv.g1"); !ok {
+		t.Errorf("Error: failed to add nv.g1 again\n")
+	}
+
+	pending := ctx.Pending(Discover2Monitor)
+	if len(pending) != 1 || pending[0].Group != "nv.g1" || pending[0].Life != 6 {
+		t.Errorf("Error: unexpected pending transitions: %+v\n", pending)
+	}
+
+	// the group is kept when the system-wide setting is disabled
+	ctx.ConfigureCompleteDuration(Discover2Monitor, 0)
+	if ctx.Counts(Discover2Monitor) != 1 {
+		t.Errorf("Error: nv.g1 is pruned\n")
+	}
+
+	ctx.RemoveGroup("nv.g1")
+	if ctx.Counts(Discover2Monitor) != 0 || len(ctx.Pending(Discover2Monitor)) != 0 {
+		t.Errorf("Error: failed to remove nv.g1\n")
+	}
+	if ok := ctx.AddGroup(Discover2Monitor, "nv.g1"); ok {
+		t.Errorf("Error: nv.g1 should not be added after removal\n")
+	}
+}
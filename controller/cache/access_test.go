@@ -21,9 +21,9 @@ func TestAccessResource(t *testing.T) {
 	preTest()
 
 	wl1 := share.CLUSWorkload{ID: "1", Name: "c1", Domain: D1, Service: S1}
-	wlCacheMap[wl1.ID] = &workloadCache{workload: &wl1, displayName: "container1"}
+	wlCacheMap.set(wl1.ID, &workloadCache{workload: &wl1, displayName: "container1"})
 	wl2 := share.CLUSWorkload{ID: "2", Name: "c2", Domain: D2, Service: S2}
-	wlCacheMap[wl2.ID] = &workloadCache{workload: &wl2, displayName: "container2"}
+	wlCacheMap.set(wl2.ID, &workloadCache{workload: &wl2, displayName: "container2"})
 
 	r, _ := http.NewRequest(http.MethodGet, "https://10.1.1.1/v1/workload", nil)
 	acc := access.NewAccessControl(r, access.AccessOPRead, map[string]string{D1: api.UserRoleAdmin})
@@ -41,7 +41,7 @@ func TestAccessResource(t *testing.T) {
 		t.Errorf("Expected container 1, but returned container %s \n", wls[0].ID)
 	}
 
-	wlCacheMap = make(map[string]*workloadCache, 0)
+	wlCacheMap = newWorkloadCacheMap()
 	postTest()
 }
 
@@ -89,6 +89,7 @@ var critDisplayName map[string]string = map[string]string{
 	share.CriteriaKeyShareIpcWithHost:    "share host's IPC namespaces",
 	share.CriteriaKeyShareNetWithHost:    "share host's network",
 	share.CriteriaKeyAllowPrivEscalation: "allow privilege escalation",
+	share.CriteriaKeyPrivilegedSCC:       "privileged SCC",
 	share.CriteriaKeyPspCompliance:       "PSP best practice violation",
 	share.CriteriaKeyRequestLimit:        "resource limitation",
 	share.CriteriaKeyCustomPath:          "custom path violation",
@@ -297,6 +298,7 @@ func admissionRule2REST(rule *share.CLUSAdmissionRule) *api.RESTAdmissionRule {
 		RuleMode: rule.RuleMode,
 	}
 	r.CfgType, _ = cfgTypeMapping[rule.CfgType]
+	r.ManagedBy = getFedManagedBy(rule.CfgType)
 	if rule.CfgType == share.FederalCfg {
 		if r.RuleType == share.FedAdmCtrlExceptRulesType {
 			r.RuleType = api.ValidatingExceptRuleType
@@ -304,6 +306,9 @@ func admissionRule2REST(rule *share.CLUSAdmissionRule) *api.RESTAdmissionRule {
 			r.RuleType = api.ValidatingDenyRuleType
 		}
 	}
+	if r.RuleType == api.ValidatingDenyRuleType {
+		r.Controls = share.RegulatoryControls(share.AdmissionRuleRegulatoryAreas(rule))
+	}
 
 	return &r
 }
@@ -1357,6 +1362,8 @@ func isAdmissionRuleMet(admResObject *nvsysadmission.AdmResObject, c *nvsysadmis
 			met, positive = isStringCriterionMet(crt, strconv.FormatBool((c.RunAsUser == 0) || (c.RunAsUser == -1 && scannedImage.RunAsRoot)))
 		case share.CriteriaKeyAllowPrivEscalation:
 			met, positive = isStringCriterionMet(crt, strconv.FormatBool(c.AllowPrivilegeEscalation))
+		case share.CriteriaKeyPrivilegedSCC:
+			met, positive = isStringCriterionMet(crt, strconv.FormatBool(isPrivilegedSCC(getRequestedSCC(admResObject.Annotations))))
 		case share.CriteriaKeyMountVolumes:
 			met, positive = isSetCriterionMet(crt, c.VolMounts)
 		case share.CriteriaKeyEnvVars:
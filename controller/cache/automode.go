@@ -3,6 +3,7 @@ package cache
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"strings"
 	"time"
 
@@ -23,10 +24,39 @@ func automode_init(ctx *Context) {
 	atmoHelper = atmo.GetAutoModeHelper()
 }
 
+// cacheMutex read-locked
+func automode_last_learned_at(cache *groupCache) time.Time {
+	var last time.Time
+	if p, ok := profileGroups[cache.group.Name]; ok {
+		for _, e := range p.Process {
+			if e.CfgType == share.Learned && e.CreatedAt.After(last) {
+				last = e.CreatedAt
+			}
+		}
+	}
+	for id := range cache.usedByPolicy.Iter() {
+		if rule, ok := policyCache.ruleMap[id.(uint32)]; ok && rule.CfgType == share.Learned && rule.CreatedAt.After(last) {
+			last = rule.CreatedAt
+		}
+	}
+	return last
+}
+
+// The group waits when it is held or has learned a rule in the no-learn period
 func automode_d2m_test_func(group string) (bool, error) {
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
 	if cache, ok := groupCacheMap[group]; ok {
+		if am := cache.group.AutoMode; am != nil {
+			if am.Hold {
+				return false, nil
+			}
+			if am.NoLearnDays > 0 {
+				if time.Since(automode_last_learned_at(cache)) < time.Duration(am.NoLearnDays)*time.Hour*24 {
+					return false, nil
+				}
+			}
+		}
 		// member count > 0
 		return (cache.members.Cardinality() > 0), nil
 	}
@@ -40,6 +70,9 @@ func automode_m2p_test_func(group string, probeDuration int64) (bool, error) {
 		if cache.members.Cardinality() == 0 {
 			return false, nil // TBD
 		}
+		if am := cache.group.AutoMode; am != nil && am.Hold {
+			return false, nil // restart when the hold is released
+		}
 
 		var count int
 		var incd_last *api.Incident
@@ -133,6 +166,11 @@ func automode_promote_mode(group, mode string) error {
 		return common.ErrUnsupported
 	}
 
+	if grp.AutoMode != nil && grp.AutoMode.Hold {
+		log.WithFields(log.Fields{"group": group, "promote": mode}).Info("ATMO: held")
+		return nil
+	}
+
 	log.WithFields(log.Fields{"group": group, "mode": mode}).Debug("ATMO:")
 	// sync both policy and profile modes together
 	switch mode {
@@ -302,35 +340,83 @@ func automodeGroupDelete(name string, param interface{}) {
 
 func automodeGroupAdd(name string, param interface{}) {
 	cache := param.(*groupCache)
-	if bD2M, bM2P := atmoHelper.Enabled(); bD2M || bM2P {
-		var mover int
-		if !utils.DoesGroupHavePolicyMode(name) || name == api.AllHostGroup || cache.group == nil {
-			return
-		}
+	if !utils.DoesGroupHavePolicyMode(name) || name == api.AllHostGroup || cache.group == nil {
+		return
+	}
+
+	// the dwell times of the group are applied even if the system-wide auto mode is disabled
+	var d2m, m2p time.Duration
+	if am := cache.group.AutoMode; am != nil {
+		d2m = time.Duration(am.ModeAutoD2MDuration) * time.Second
+		m2p = time.Duration(am.ModeAutoM2PDuration) * time.Second
+	}
+	atmoHelper.ConfigureGroupDuration(name, d2m, m2p)
 
+	var mover int
+	switch cache.group.ProfileMode {
+	case share.PolicyModeLearn:
+		mover = atmo.Discover2Monitor
+	case share.PolicyModeEvaluate:
+		mover = atmo.Monitor2Protect
+	default:
+		atmoHelper.RemoveGroup(name)
+		return
+	}
+
+	if atmoHelper.GroupEnabled(mover, name) {
 		// log.WithFields(log.Fields{"name": name, "cache": cache, "group": cache.group}).Debug("ATMO:")
 		now := time.Now().Unix()
-		switch cache.group.ProfileMode {
-		case share.PolicyModeLearn:
-			mover = atmo.Discover2Monitor
+		switch mover {
+		case atmo.Discover2Monitor:
 			if cache.atmo_d2m > 0 {
 				return
 			}
 			cache.atmo_d2m = now
 			cache.atmo_m2p = 0
-		case share.PolicyModeEvaluate:
-			mover = atmo.Monitor2Protect
+		case atmo.Monitor2Protect:
 			if cache.atmo_m2p > 0 {
 				return
 			}
 			cache.atmo_m2p = now
 			cache.atmo_d2m = 0
-		default:
-			return
 		}
 		atmoHelper.AddGroup(mover, name)
 	} else {
+		atmoHelper.RemoveGroup(name)
 		cache.atmo_m2p = 0 // reset all
 		cache.atmo_d2m = 0
 	}
 }
+
+func (m CacheMethod) GetAutoModeTransitions(acc *access.AccessControl) []*api.RESTAutoModeTransition {
+	list := make([]*api.RESTAutoModeTransition, 0)
+
+	cacheMutexRLock()
+	defer cacheMutexRUnlock()
+	for _, mover := range []int{atmo.Discover2Monitor, atmo.Monitor2Protect} {
+		for _, p := range atmoHelper.Pending(mover) {
+			cache, ok := groupCacheMap[p.Group]
+			if !ok || !acc.Authorize(cache.group, nil) {
+				continue
+			}
+
+			t := &api.RESTAutoModeTransition{
+				Group:      p.Group,
+				PolicyMode: cache.group.PolicyMode,
+				Elapsed:    int64((time.Duration(p.Runs) * p.Interval).Seconds()),
+				Duration:   int64((time.Duration(p.Life) * p.Interval).Seconds()),
+			}
+			if mover == atmo.Discover2Monitor {
+				t.TargetMode = share.PolicyModeEvaluate
+			} else {
+				t.TargetMode = share.PolicyModeEnforce
+			}
+			if am := cache.group.AutoMode; am != nil {
+				t.Hold = am.Hold
+			}
+			list = append(list, t)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Group < list[j].Group })
+	return list
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/neuvector/neuvector/controller/api"
 	"github.com/neuvector/neuvector/controller/common"
 	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/controller/logstore"
 	admission "github.com/neuvector/neuvector/controller/nvk8sapi/nvvalidatewebhookcfg"
 	"github.com/neuvector/neuvector/controller/resource"
 	"github.com/neuvector/neuvector/controller/rpc"
@@ -167,7 +168,7 @@ var k8sHostInfoMap map[string]*k8sHostCache = make(map[string]*k8sHostCache) //
 var agentCacheMap map[string]*agentCache = make(map[string]*agentCache)
 var ctrlCacheMap map[string]*ctrlCache = make(map[string]*ctrlCache)
 var nvwlCacheMap map[string]*workloadCache = make(map[string]*workloadCache)
-var wlCacheMap map[string]*workloadCache = make(map[string]*workloadCache)
+var wlCacheMap *workloadCacheMap = newWorkloadCacheMap()
 var ipHostMap map[string]*hostDigest = make(map[string]*hostDigest)
 var tunnelHostMap map[string]string = make(map[string]string)
 var ipWLMap map[string]*workloadDigest = make(map[string]*workloadDigest)
@@ -178,6 +179,7 @@ var cachedSpecialSubnets map[string]share.CLUSSpecSubnet = make(map[string]share
 var effectiveSpecialSubnets map[string]share.CLUSSpecSubnet = make(map[string]share.CLUSSpecSubnet)
 var wlEphemeral []*workloadEphemeral
 var nodePodSAMap map[string]map[string]string = make(map[string]map[string]string) // key is node name, value is map[workload id]{service account of the pod}
+var podOwnerMap map[string]string = make(map[string]string)                        // key is domain/pod name, value is kind/name of the pod owner
 
 type Context struct {
 	k8sVersion               string
@@ -204,6 +206,8 @@ type Context struct {
 	NvSemanticVersion        string
 	StartStopFedPingPollFunc func(cmd, interval uint32, param1 interface{}) error
 	RestConfigFunc           func(cmd, interval uint32, param1 interface{}, param2 interface{}) error
+	LogStore                 logstore.Store           // nil if logs are not persisted
+	LogRetention             map[string]time.Duration // key is log type or "default"
 }
 
 type k8sProbeCmd struct {
@@ -314,6 +318,8 @@ func LeadChangeNotify(isLeader bool, leadAddr string) {
 	//policy because internal workload id for "nodes" and
 	//"fed.nodes" is changed from CLUSWLAddressGroup to
 	//CLUSHostAddrGroup
+	resetIPPolicyCalculation()
+	resetPolicyPush()
 	scheduleIPPolicyCalculation(false)
 	scheduleDlpRuleCalculation(false)
 
@@ -434,7 +440,7 @@ func getHostCache(id string) *hostCache {
 func getWorkloadCache(id string) *workloadCache {
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
-	if cache, ok := wlCacheMap[id]; ok {
+	if cache, ok := wlCacheMap.get(id); ok {
 		return cache
 	}
 	return nil
@@ -605,7 +611,7 @@ func getNvName(id string) *workloadNames {
 func (m CacheMethod) CanAccessWorkload(id string, acc *access.AccessControl) error {
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
-	if cache, ok := wlCacheMap[id]; ok {
+	if cache, ok := wlCacheMap.get(id); ok {
 		if !acc.Authorize(cache.workload, nil) {
 			return common.ErrObjectAccessDenied
 		}
@@ -617,7 +623,7 @@ func (m CacheMethod) CanAccessWorkload(id string, acc *access.AccessControl) err
 func (m CacheMethod) GetWorkload(id string, view string, acc *access.AccessControl) (*api.RESTWorkload, error) {
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
-	if cache, ok := wlCacheMap[id]; ok {
+	if cache, ok := wlCacheMap.get(id); ok {
 		if !acc.Authorize(cache.workload, nil) {
 			return nil, common.ErrObjectAccessDenied
 		}
@@ -626,7 +632,7 @@ func (m CacheMethod) GetWorkload(id string, view string, acc *access.AccessContr
 		switch view {
 		case api.QueryValueViewPod:
 			for child := range cache.children.Iter() {
-				if childCache, ok := wlCacheMap[child.(string)]; ok {
+				if childCache, ok := wlCacheMap.get(child.(string)); ok {
 					wl.Children = append(wl.Children, workload2REST(childCache))
 				}
 			}
@@ -648,7 +654,7 @@ func fakeWorkloadBrief(id string) *api.RESTWorkloadBrief {
 }
 
 func getWorkloadBrief(id string, view string, acc *access.AccessControl) (*api.RESTWorkloadBrief, error) {
-	if cache, ok := wlCacheMap[id]; ok {
+	if cache, ok := wlCacheMap.get(id); ok {
 		if !acc.Authorize(cache.workload, nil) {
 			return nil, common.ErrObjectAccessDenied
 		}
@@ -657,7 +663,7 @@ func getWorkloadBrief(id string, view string, acc *access.AccessControl) (*api.R
 		switch view {
 		case api.QueryValueViewPod:
 			for child := range cache.children.Iter() {
-				if childCache, ok := wlCacheMap[child.(string)]; ok {
+				if childCache, ok := wlCacheMap.get(child.(string)); ok {
 					wl.Children = append(wl.Children, workload2BriefREST(childCache))
 				}
 			}
@@ -682,14 +688,14 @@ func (m CacheMethod) GetWorkloadRisk(id string, acc *access.AccessControl) (*com
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
 
-	if cache, ok := wlCacheMap[id]; ok {
+	if cache, ok := wlCacheMap.get(id); ok {
 		if !acc.Authorize(cache.workload, nil) {
 			return nil, common.ErrObjectAccessDenied
 		}
 
 		wl := workload2Risk(cache)
 		for child := range cache.children.Iter() {
-			if childCache, ok := wlCacheMap[child.(string)]; ok {
+			if childCache, ok := wlCacheMap.get(child.(string)); ok {
 				wl.Children = append(wl.Children, workload2Risk(childCache))
 			}
 		}
@@ -708,7 +714,7 @@ func (m CacheMethod) GetFakeWorkloadBrief(id string) *api.RESTWorkloadBrief {
 func (m CacheMethod) GetWorkloadDetail(id string, view string, acc *access.AccessControl) (*api.RESTWorkloadDetail, error) {
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
-	if cache, ok := wlCacheMap[id]; ok {
+	if cache, ok := wlCacheMap.get(id); ok {
 		if !acc.Authorize(cache.workload, nil) {
 			return nil, common.ErrObjectAccessDenied
 		}
@@ -717,7 +723,7 @@ func (m CacheMethod) GetWorkloadDetail(id string, view string, acc *access.Acces
 		switch view {
 		case api.QueryValueViewPod:
 			for child := range cache.children.Iter() {
-				if childCache, ok := wlCacheMap[child.(string)]; ok {
+				if childCache, ok := wlCacheMap.get(child.(string)); ok {
 					wl.Children = append(wl.Children, workload2DetailREST(childCache))
 				}
 			}
@@ -736,7 +742,7 @@ func getWorkloadNameForLogging(id string) *workloadNames {
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
 	if id != "" {
-		if cache, ok := wlCacheMap[id]; ok {
+		if cache, ok := wlCacheMap.get(id); ok {
 			wl := cache.workload
 			names := &workloadNames{
 				name:    cache.podName,
@@ -792,7 +798,11 @@ func getCombinedThreatName(threatid, dlpid, wafid uint32) string {
 	if isWafThreatID(wafid) {
 		wname, _, _ = getWafThreatNameSensorGroup(wafid)
 	}
-	tname = common.ThreatName(threatid)
+	if isThreatSigID(threatid) {
+		tname, _, _ = getThreatSigNameSeverity(threatid)
+	} else {
+		tname = common.ThreatName(threatid)
+	}
 	if dname == "" && tname == "" && wname == "" {
 		return ""
 	} else if dname == "" && tname == "" {
@@ -857,7 +867,7 @@ func getWorkloadDlpGrp(id string, grpname *[]string) string {
 	defer cacheMutexRUnlock()
 	var gns string = ""
 	if id != "" {
-		if cache, ok := wlCacheMap[id]; ok {
+		if cache, ok := wlCacheMap.get(id); ok {
 			if grpname != nil {
 				for _, gn := range *grpname {
 					if cache.groups.Contains(gn) {
@@ -882,7 +892,7 @@ func getAppFromWorkloadIPPort(id string, ipproto uint8, port uint16) (uint32, ui
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
 
-	if cache, ok := wlCacheMap[id]; ok {
+	if cache, ok := wlCacheMap.get(id); ok {
 		key := utils.GetPortLink(ipproto, port)
 		if app, ok := cache.workload.Apps[key]; ok {
 			if app.Application > 0 {
@@ -959,7 +969,7 @@ func getMappedPortFromWorkloadIPPort(id string, ipproto uint8, port uint16) uint
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
 
-	if cache, ok := wlCacheMap[id]; ok {
+	if cache, ok := wlCacheMap.get(id); ok {
 		key := utils.GetPortLink(ipproto, port)
 		if mp, ok := cache.workload.Ports[key]; ok {
 			if mp.IPProto == ipproto && mp.Port == port {
@@ -998,7 +1008,7 @@ func isWorkloadSwarmService(id string) bool {
 	cacheMutexLock()
 	defer cacheMutexUnlock()
 
-	if cache, ok := wlCacheMap[id]; ok {
+	if cache, ok := wlCacheMap.get(id); ok {
 		_, ok := cache.workload.Labels[utils.DockerSwarmServiceKey]
 		return ok
 	}
@@ -1009,15 +1019,16 @@ func isWorkloadSwarmService(id string) bool {
 func (m CacheMethod) GetWorkloadConfig(id string, acc *access.AccessControl) (*api.RESTWorkloadConfig, error) {
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
-	if cache, ok := wlCacheMap[id]; ok {
+	if cache, ok := wlCacheMap.get(id); ok {
 		if !acc.Authorize(cache.workload, nil) {
 			return nil, common.ErrObjectAccessDenied
 		}
 
 		cfg := &api.RESTWorkloadConfig{
-			Wire:       cache.config.Wire,
-			Quarantine: cache.config.Quarantine,
-			QuarReason: cache.config.QuarReason,
+			Wire:           cache.config.Wire,
+			Quarantine:     cache.config.Quarantine,
+			QuarReason:     cache.config.QuarReason,
+			QuarExceptions: cache.config.QuarExceptions,
 		}
 		return cfg, nil
 	}
@@ -1211,22 +1222,22 @@ func (m CacheMethod) GetAllWorkloads(view string, acc *access.AccessControl) []*
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
 
-	wls := make([]*api.RESTWorkload, 0, len(wlCacheMap))
+	wls := make([]*api.RESTWorkload, 0, wlCacheMap.size())
 
 	switch view {
 	case api.QueryValueViewPod:
-		for _, cache := range wlCacheMap {
+		wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 			if !acc.Authorize(cache.workload, nil) {
-				continue
+				return true
 			}
 			if common.OEMIgnoreWorkload(cache.workload) {
-				continue
+				return true
 			}
 
 			if cache.workload.ShareNetNS == "" {
 				wl := workload2REST(cache)
 				for child := range cache.children.Iter() {
-					if childCache, ok := wlCacheMap[child.(string)]; ok {
+					if childCache, ok := wlCacheMap.get(child.(string)); ok {
 						wl.Children = append(wl.Children, workload2REST(childCache))
 					}
 				}
@@ -1235,32 +1246,35 @@ func (m CacheMethod) GetAllWorkloads(view string, acc *access.AccessControl) []*
 				})
 				wls = append(wls, wl)
 			}
-		}
+			return true
+		})
 	case api.QueryValueViewPodOnly:
-		for _, cache := range wlCacheMap {
+		wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 			if !acc.Authorize(cache.workload, nil) {
-				continue
+				return true
 			}
 			if common.OEMIgnoreWorkload(cache.workload) {
-				continue
+				return true
 			}
 
 			if cache.workload.ShareNetNS == "" {
 				wl := workload2REST(cache)
 				wls = append(wls, wl)
 			}
-		}
+			return true
+		})
 	default:
-		for _, cache := range wlCacheMap {
+		wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 			if !acc.Authorize(cache.workload, nil) {
-				continue
+				return true
 			}
 			if common.OEMIgnoreWorkload(cache.workload) {
-				continue
+				return true
 			}
 
 			wls = append(wls, workload2REST(cache))
-		}
+			return true
+		})
 	}
 	return wls
 }
@@ -1269,21 +1283,21 @@ func (m CacheMethod) GetAllWorkloadsBrief(view string, acc *access.AccessControl
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
 
-	wls := make([]*api.RESTWorkloadBrief, 0, len(wlCacheMap))
+	wls := make([]*api.RESTWorkloadBrief, 0, wlCacheMap.size())
 	switch view {
 	case api.QueryValueViewPod:
-		for _, cache := range wlCacheMap {
+		wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 			if !acc.Authorize(cache.workload, nil) {
-				continue
+				return true
 			}
 			if common.OEMIgnoreWorkload(cache.workload) {
-				continue
+				return true
 			}
 
 			if cache.workload.ShareNetNS == "" {
 				wl := workload2BriefREST(cache)
 				for child := range cache.children.Iter() {
-					if childCache, ok := wlCacheMap[child.(string)]; ok {
+					if childCache, ok := wlCacheMap.get(child.(string)); ok {
 						wl.Children = append(wl.Children, workload2BriefREST(childCache))
 					}
 				}
@@ -1292,32 +1306,35 @@ func (m CacheMethod) GetAllWorkloadsBrief(view string, acc *access.AccessControl
 				})
 				wls = append(wls, wl)
 			}
-		}
+			return true
+		})
 	case api.QueryValueViewPodOnly:
-		for _, cache := range wlCacheMap {
+		wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 			if !acc.Authorize(cache.workload, nil) {
-				continue
+				return true
 			}
 			if common.OEMIgnoreWorkload(cache.workload) {
-				continue
+				return true
 			}
 
 			if cache.workload.ShareNetNS == "" {
 				wl := workload2BriefREST(cache)
 				wls = append(wls, wl)
 			}
-		}
+			return true
+		})
 	default:
-		for _, cache := range wlCacheMap {
+		wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 			if !acc.Authorize(cache.workload, nil) {
-				continue
+				return true
 			}
 			if common.OEMIgnoreWorkload(cache.workload) {
-				continue
+				return true
 			}
 
 			wls = append(wls, workload2BriefREST(cache))
-		}
+			return true
+		})
 	}
 	return wls
 }
@@ -1326,28 +1343,29 @@ func (m CacheMethod) GetAllWorkloadsRisk(acc *access.AccessControl) []*common.Wo
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
 
-	wls := make([]*common.WorkloadRisk, 0, len(wlCacheMap))
-	for _, cache := range wlCacheMap {
+	wls := make([]*common.WorkloadRisk, 0, wlCacheMap.size())
+	wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 		if !acc.Authorize(cache.workload, nil) {
-			continue
+			return true
 		}
 		if common.OEMIgnoreWorkload(cache.workload) {
-			continue
+			return true
 		}
 		if !cache.workload.Running {
-			continue
+			return true
 		}
 
 		if cache.workload.ShareNetNS == "" {
 			wl := workload2Risk(cache)
 			for child := range cache.children.Iter() {
-				if childCache, ok := wlCacheMap[child.(string)]; ok {
+				if childCache, ok := wlCacheMap.get(child.(string)); ok {
 					wl.Children = append(wl.Children, workload2Risk(childCache))
 				}
 			}
 			wls = append(wls, wl)
 		}
-	}
+		return true
+	})
 	return wls
 }
 
@@ -1355,21 +1373,21 @@ func (m CacheMethod) GetAllWorkloadsDetail(view string, acc *access.AccessContro
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
 
-	wls := make([]*api.RESTWorkloadDetail, 0, len(wlCacheMap))
+	wls := make([]*api.RESTWorkloadDetail, 0, wlCacheMap.size())
 	switch view {
 	case api.QueryValueViewPod:
-		for _, cache := range wlCacheMap {
+		wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 			if !acc.Authorize(cache.workload, nil) {
-				continue
+				return true
 			}
 			if common.OEMIgnoreWorkload(cache.workload) {
-				continue
+				return true
 			}
 
 			if cache.workload.ShareNetNS == "" {
 				wl := workload2DetailREST(cache)
 				for child := range cache.children.Iter() {
-					if childCache, ok := wlCacheMap[child.(string)]; ok {
+					if childCache, ok := wlCacheMap.get(child.(string)); ok {
 						wl.Children = append(wl.Children, workload2DetailREST(childCache))
 					}
 				}
@@ -1378,32 +1396,35 @@ func (m CacheMethod) GetAllWorkloadsDetail(view string, acc *access.AccessContro
 				})
 				wls = append(wls, wl)
 			}
-		}
+			return true
+		})
 	case api.QueryValueViewPodOnly:
-		for _, cache := range wlCacheMap {
+		wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 			if !acc.Authorize(cache.workload, nil) {
-				continue
+				return true
 			}
 			if common.OEMIgnoreWorkload(cache.workload) {
-				continue
+				return true
 			}
 
 			if cache.workload.ShareNetNS == "" {
 				wl := workload2DetailREST(cache)
 				wls = append(wls, wl)
 			}
-		}
+			return true
+		})
 	default:
-		for _, cache := range wlCacheMap {
+		wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 			if !acc.Authorize(cache.workload, nil) {
-				continue
+				return true
 			}
 			if common.OEMIgnoreWorkload(cache.workload) {
-				continue
+				return true
 			}
 
 			wls = append(wls, workload2DetailREST(cache))
-		}
+			return true
+		})
 	}
 	return wls
 }
@@ -1496,12 +1517,12 @@ func (m CacheMethod) GetWorkloadCount(acc *access.AccessControl) (int, int, int)
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
 
-	for _, cache := range wlCacheMap {
+	wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 		if !acc.Authorize(cache.workload, nil) {
-			continue
+			return true
 		}
 		if common.OEMIgnoreWorkload(cache.workload) {
-			continue
+			return true
 		}
 
 		wl++
@@ -1511,7 +1532,8 @@ func (m CacheMethod) GetWorkloadCount(acc *access.AccessControl) (int, int, int)
 				runningPod++
 			}
 		}
-	}
+		return true
+	})
 
 	return wl, runningWL, runningPod
 }
@@ -1527,7 +1549,7 @@ func (m CacheMethod) GetWorkloadCountOnHost(hostID string, view string, acc *acc
 
 		var count int = 0
 		for id := range cache.workloads.Iter() {
-			if wlCache, ok := wlCacheMap[id.(string)]; ok {
+			if wlCache, ok := wlCacheMap.get(id.(string)); ok {
 				if !acc.Authorize(wlCache.workload, nil) {
 					continue
 				}
@@ -1553,7 +1575,7 @@ func (m CacheMethod) GetWorkloadCountOnHost(hostID string, view string, acc *acc
 func (m CacheMethod) GetAgentbyWorkload(wlID string, acc *access.AccessControl) (string, error) {
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
-	if cache, ok := wlCacheMap[wlID]; ok {
+	if cache, ok := wlCacheMap.get(wlID); ok {
 		if !acc.Authorize(cache.workload, nil) {
 			return "", common.ErrObjectAccessDenied
 		}
@@ -1570,7 +1592,7 @@ func (m CacheMethod) GetIP2WorkloadMap(hostID string) []*api.RESTDebugIP2Workloa
 		l := make([]*api.RESTDebugIP2Workload, len(ipWLMap))
 		i := 0
 		for ip, wlp := range ipWLMap {
-			if wlCache, ok := wlCacheMap[wlp.wlID]; ok {
+			if wlCache, ok := wlCacheMap.get(wlp.wlID); ok {
 				l[i] = &api.RESTDebugIP2Workload{IP: ip, Workload: workload2BriefREST(wlCache)}
 			} else {
 				l[i] = &api.RESTDebugIP2Workload{IP: ip, Workload: nil}
@@ -1593,7 +1615,7 @@ func (m CacheMethod) GetIP2WorkloadMap(hostID string) []*api.RESTDebugIP2Workloa
 		l := make([]*api.RESTDebugIP2Workload, len(hostCache.ipWLMap))
 		i := 0
 		for ip, wlp := range hostCache.ipWLMap {
-			if wlCache, ok := wlCacheMap[wlp.wlID]; ok {
+			if wlCache, ok := wlCacheMap.get(wlp.wlID); ok {
 				l[i] = &api.RESTDebugIP2Workload{IP: ip, Workload: workload2BriefREST(wlCache)}
 			} else {
 				l[i] = &api.RESTDebugIP2Workload{IP: ip, Workload: nil}
@@ -1674,9 +1696,12 @@ func startWorkerThread(ctx *Context) {
 		pruneTicker.Stop()
 	}
 
-	wlSuspected := utils.NewSet() // supicious workload ids
+	wlSuspected := utils.NewSet()     // supicious workload ids
+	orphanSuspected := utils.NewSet() // suspicious orphaned keys
 	pruneKvTicker := time.NewTicker(pruneKVPeriod)
 	pruneWorkloadKV(wlSuspected) // the first scan
+	ruleExpireTicker := time.NewTicker(ruleExpirePeriod)
+	wlStringTicker := time.NewTicker(wlStringRebuildPeriod)
 
 	noTelemetry := false
 	telemetryFreq := ctx.TelemetryFreq
@@ -1716,6 +1741,10 @@ func startWorkerThread(ctx *Context) {
 				}
 			case <-pruneTicker.C:
 				pruneGroupsByNamespace()
+			case <-ruleExpireTicker.C:
+				if isLeader() {
+					expireRules()
+				}
 			case <-unManagedWlTimer.C:
 				cacheMutexRLock()
 				refreshInternalIPNet()
@@ -1729,6 +1758,9 @@ func startWorkerThread(ctx *Context) {
 				cacheMutexUnlock()
 			case <-pruneKvTicker.C:
 				pruneWorkloadKV(wlSuspected)
+				updateKvStoreStats(compactOrphanKV(orphanSuspected))
+			case <-wlStringTicker.C:
+				rebuildWorkloadStrings()
 			case <-scannerTicker.C:
 				if isScanner() {
 					// Remove stalled scanner
@@ -1883,6 +1915,10 @@ func startWorkerThread(ctx *Context) {
 						o = ev.ResourceOld.(*resource.Pod)
 					}
 
+					if localDev.Host.Flavor == share.FlavorOpenShift {
+						ocPodLabelUpdate(n, o)
+					}
+
 					// Assume IP doesn't change. Ignore host mode containers.
 					if (o == nil || o.IPNet.IP == nil) && (n != nil && !n.HostNet && n.IPNet.IP != nil) {
 						addrOrchWorkloadAdd(&n.IPNet, n.Node)
@@ -1916,14 +1952,19 @@ func startWorkerThread(ctx *Context) {
 								QueryK8sVersion()
 							}
 						}
+						if owner := getPodOwnerRef(n); owner != "" {
+							cacheMutexLock()
+							podOwnerMap[n.Domain+"/"+n.Name] = owner
+							cacheMutexUnlock()
+						}
 						if n.SA != "" && len(n.ContainerIDs) > 0 {
 							cacheMutexLock()
 							for _, containerID := range n.ContainerIDs {
-								if wl, ok := wlCacheMap[containerID]; ok {
+								if wl, ok := wlCacheMap.get(containerID); ok {
 									if wl.serviceAccount != n.SA {
 										wl.serviceAccount = n.SA
 										if wl.workload.ShareNetNS != "" {
-											if parent, ok := wlCacheMap[wl.workload.ShareNetNS]; ok {
+											if parent, ok := wlCacheMap.get(wl.workload.ShareNetNS); ok {
 												parent.serviceAccount = n.SA
 											}
 										}
@@ -1941,6 +1982,7 @@ func startWorkerThread(ctx *Context) {
 						}
 					} else if o != nil && n == nil { // delete
 						cacheMutexLock()
+						delete(podOwnerMap, o.Domain+"/"+o.Name)
 						if podSAMap, ok := nodePodSAMap[o.Node]; podSAMap != nil {
 							for _, containerID := range o.ContainerIDs {
 								if _, ok = podSAMap[containerID]; ok {
@@ -1951,6 +1993,16 @@ func startWorkerThread(ctx *Context) {
 						cacheMutexUnlock()
 					}
 				case resource.RscTypeService:
+					if localDev.Host.Flavor == share.FlavorOpenShift {
+						var n, o *resource.Service
+						if ev.ResourceNew != nil {
+							n = ev.ResourceNew.(*resource.Service)
+						}
+						if ev.ResourceOld != nil {
+							o = ev.ResourceOld.(*resource.Service)
+						}
+						ocServiceSelectorUpdate(n, o)
+					}
 					if isLeader() {
 						var n, o *resource.Service
 						if ev.ResourceNew != nil {
@@ -1989,6 +2041,24 @@ func startWorkerThread(ctx *Context) {
 							createServiceIPGroup(n)
 						}
 					}
+				case resource.RscTypeRoute:
+					var n, o *resource.Route
+					if ev.ResourceNew != nil {
+						n = ev.ResourceNew.(*resource.Route)
+					}
+					if ev.ResourceOld != nil {
+						o = ev.ResourceOld.(*resource.Route)
+					}
+					routeUpdate(n, o)
+				case resource.RscTypeSCC:
+					var n, o *resource.SCC
+					if ev.ResourceNew != nil {
+						n = ev.ResourceNew.(*resource.SCC)
+					}
+					if ev.ResourceOld != nil {
+						o = ev.ResourceOld.(*resource.SCC)
+					}
+					sccUpdate(n, o)
 				case resource.RscTypeDeployment:
 					var n, o *resource.Deployment
 					if ev.ResourceNew != nil {
@@ -2101,12 +2171,18 @@ func Init(ctx *Context, leader bool, leadAddr, restoredFedRole string) CacheInte
 	clusHelper = kv.GetClusterHelper()
 	cfgHelper = kv.GetConfigHelper()
 	dispatchHelper = kv.GetDispatchHelper()
+	kv.SetProfilePushFunc(queuePolicyPush)
 
 	envParser := utils.NewEnvironParser(os.Environ())
 	if _, ok := envParser.Value(share.ENV_DISABLE_PCAP); ok {
 		cacher.disablePCAP = true
 	}
 
+	// Restore the logs before the log events are handled
+	if ctx.LogStore != nil {
+		initLogStore(ctx.LogStore)
+	}
+
 	registerEventHandlers()
 
 	policyApplyIngress = global.ORCH.ApplyPolicyAtIngress()
@@ -2181,7 +2257,7 @@ func (m CacheMethod) IsGroupMember(name, id string) bool {
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
 	if groupCache, ok := groupCacheMap[name]; ok {
-		if wlCache, ok := wlCacheMap[id]; ok {
+		if wlCache, ok := wlCacheMap.get(id); ok {
 			return share.IsGroupMember(groupCache.group, wlCache.workload, getDomainData(wlCache.workload.Domain))
 		}
 	}
@@ -2222,10 +2298,11 @@ func pruneWorkloadKV(suspected utils.Set) {
 	updated := utils.NewSet() // allow one update per round
 
 	cacheMutexRLock()
-	for id, _ := range wlCacheMap {
+	wlCacheMap.iter(func(id string, _ *workloadCache) bool {
 		ids.Add(id)
 		suspected.Remove(id) // remove the missing id
-	}
+		return true
+	})
 	cacheMutexRUnlock()
 
 	// Those keys are written at enforcers so they are not synchronized with the cacher
@@ -59,6 +59,19 @@ func filterComplianceLog(audit *api.Audit) *api.Audit {
 		domain = api.DomainNodes
 	}
 
+	// Remove the checks whose risks are accepted in the namespace
+	list := make([]string, 0, len(audit.Items))
+	for _, item := range audit.Items {
+		if tokens := strings.Split(item, " "); len(tokens) > 0 && cacher.IsComplianceRiskAccepted(tokens[0], domain) {
+			continue
+		}
+		list = append(list, item)
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	audit.Items = list
+
 	// Is the namespace tagged
 	tags, _ := cacher.GetDomainEffectiveTags(domain, access.NewReaderAccessControl())
 	if len(tags) > 0 {
@@ -122,6 +135,10 @@ func complianceProfile2REST(ccp *share.CLUSComplianceProfile) *api.RESTComplianc
 		rcp.Entries[i] = api.RESTComplianceProfileEntry{
 			TestNum: ce.TestNum,
 			Tags:    ce.Tags,
+			CfgType: api.CfgTypeUserCreated,
+		}
+		if ce.CfgType == share.GroundCfg {
+			rcp.Entries[i].CfgType = api.CfgTypeGround
 		}
 		i++
 	}
@@ -131,7 +148,12 @@ func complianceProfile2REST(ccp *share.CLUSComplianceProfile) *api.RESTComplianc
 }
 
 func complianceConfigUpdate(nType cluster.ClusterNotifyType, key string, value []byte) {
-	if share.CLUSComplianceKey2Type(key) != "profile" {
+	switch share.CLUSComplianceKey2Type(key) {
+	case "profile":
+	case "acceptance":
+		riskAcceptanceConfigUpdate(nType, key, value)
+		return
+	default:
 		return
 	}
 
@@ -241,26 +263,26 @@ func (m CacheMethod) GetRiskScoreMetrics(acc, accCaller *access.AccessControl) *
 
 	// get all running pod
 	epMap := make(map[string]*wlMini) // id ==> plicy mode
-	for _, cache := range wlCacheMap {
+	wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 		wl := cache.workload
 		if !acc.Authorize(wl, nil) {
-			continue
+			return true
 		}
 		if common.OEMIgnoreWorkload(wl) {
-			continue
+			return true
 		}
 		if cache.platformRole != "" && disableSystem {
 			// skip system containers
-			continue
+			return true
 		}
 		if !wl.Running {
-			continue
+			return true
 		}
 
 		var mode string
 		if gc, ok := groupCacheMap[cache.learnedGroupName]; ok {
 			if gc.group.NotScored {
-				continue
+				return true
 			}
 
 			mode = gc.group.PolicyMode
@@ -290,7 +312,8 @@ func (m CacheMethod) GetRiskScoreMetrics(acc, accCaller *access.AccessControl) *
 				}
 			}
 		}
-	}
+		return true
+	})
 
 	// host cve
 	var scoreHost bool
@@ -396,7 +419,7 @@ func (m CacheMethod) GetRiskScoreMetrics(acc, accCaller *access.AccessControl) *
 
 	cacheMutexRLock()
 	for id, cr := range ingress {
-		if cache, ok := wlCacheMap[id]; ok {
+		if cache, ok := wlCacheMap.get(id); ok {
 			wl := cache.workload
 			if !accCaller.Authorize(wl, nil) {
 				continue
@@ -418,7 +441,7 @@ func (m CacheMethod) GetRiskScoreMetrics(acc, accCaller *access.AccessControl) *
 		}
 	}
 	for id, cr := range egress {
-		if cache, ok := wlCacheMap[id]; ok {
+		if cache, ok := wlCacheMap.get(id); ok {
 			wl := cache.workload
 			if !accCaller.Authorize(wl, nil) {
 				continue
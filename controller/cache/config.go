@@ -36,7 +36,7 @@ func workloadConfig(nType cluster.ClusterNotifyType, key string, value []byte) {
 		json.Unmarshal(value, &cconf)
 
 		cacheMutexLock()
-		if cache, ok := wlCacheMap[id]; ok {
+		if cache, ok := wlCacheMap.get(id); ok {
 			cache.config = &cconf
 		}
 		cacheMutexUnlock()
@@ -196,6 +196,19 @@ func getDisableNetPolicyStatus() bool {
 	return systemConfigCache.DisableNetPolicy
 }
 
+func getHostNetPolicyStatus() bool {
+	return systemConfigCache.HostNetPolicyStatus
+}
+
+// Policy mode of host processes, independent of the "nodes" group mode
+// that is used by process profile
+func getHostNetPolicyMode() string {
+	if systemConfigCache.HostNetPolicyMode == "" {
+		return share.PolicyModeLearn
+	}
+	return systemConfigCache.HostNetPolicyMode
+}
+
 func getNewServiceProfileBaseline() string {
 	return systemConfigCache.NewServiceProfileBaseline
 }
@@ -244,6 +257,18 @@ func (m CacheMethod) GetModeAutoM2P() (bool, int64) {
 	return getModeAutoM2P()
 }
 
+// header values are cloaked in the REST response
+func webhookHeaders2REST(headers []share.CLUSWebhookHeader) []api.RESTWebhookHeader {
+	if len(headers) == 0 {
+		return nil
+	}
+	rhs := make([]api.RESTWebhookHeader, len(headers))
+	for i, h := range headers {
+		rhs[i] = api.RESTWebhookHeader{Name: h.Name, Value: h.Value}
+	}
+	return rhs
+}
+
 func (m CacheMethod) GetSystemConfig(acc *access.AccessControl) *api.RESTSystemConfig {
 	if !acc.Authorize(&systemConfigCache, nil) {
 		return nil
@@ -265,9 +290,13 @@ func (m CacheMethod) GetSystemConfig(acc *access.AccessControl) *api.RESTSystemC
 		SingleCVEPerSyslog:        systemConfigCache.SingleCVEPerSyslog,
 		SyslogCVEInLayers:         systemConfigCache.SyslogCVEInLayers,
 		SyslogServerCert:          systemConfigCache.SyslogServerCert,
+		SyslogClientCert:          systemConfigCache.SyslogClientCert,
+		SyslogClientKey:           systemConfigCache.SyslogClientKey,
+		SyslogStructuredData:      systemConfigCache.SyslogStructuredData,
 		AuthOrder:                 systemConfigCache.AuthOrder,
 		AuthByPlatform:            systemConfigCache.AuthByPlatform,
 		RancherEP:                 systemConfigCache.RancherEP,
+		RancherProjectRoles:       make(map[string]string, len(systemConfigCache.RancherProjectRoles)),
 		InternalSubnets:           systemConfigCache.InternalSubnets,
 		ClusterName:               systemConfigCache.ClusterName,
 		ControllerDebug:           systemConfigCache.ControllerDebug,
@@ -281,11 +310,21 @@ func (m CacheMethod) GetSystemConfig(acc *access.AccessControl) *api.RESTSystemC
 		NetServicePolicyMode:      systemConfigCache.NetServicePolicyMode,
 		DisableNetPolicy:          systemConfigCache.DisableNetPolicy,
 		DetectUnmanagedWl:         systemConfigCache.DetectUnmanagedWl,
+		HostNetPolicyStatus:       systemConfigCache.HostNetPolicyStatus,
+		HostNetPolicyMode:         getHostNetPolicyMode(),
+		SynFloodThreshold:         systemConfigCache.SynFloodThreshold,
+		PortScanThreshold:         systemConfigCache.PortScanThreshold,
 		ModeAutoD2M:               systemConfigCache.ModeAutoD2M,
 		ModeAutoD2MDuration:       systemConfigCache.ModeAutoD2MDuration,
 		ModeAutoM2P:               systemConfigCache.ModeAutoM2P,
 		ModeAutoM2PDuration:       systemConfigCache.ModeAutoM2PDuration,
 		NoTelemetryReport:         systemConfigCache.NoTelemetryReport,
+		KubeCISProfile:            systemConfigCache.KubeCISProfile,
+		BenchMode:                 systemConfigCache.BenchMode,
+		PolicyChangeApproval:      systemConfigCache.PolicyChangeApproval,
+	}
+	for template, role := range systemConfigCache.RancherProjectRoles {
+		rconf.RancherProjectRoles[template] = role
 	}
 	if systemConfigCache.SyslogIP != nil {
 		rconf.SyslogServer = systemConfigCache.SyslogIP.String()
@@ -296,9 +335,45 @@ func (m CacheMethod) GetSystemConfig(acc *access.AccessControl) *api.RESTSystemC
 		rconf.SyslogIPProto = 17
 	}
 
+	rconf.LogExporters = make([]api.RESTLogExporter, len(systemConfigCache.LogExporters))
+	for i, e := range systemConfigCache.LogExporters {
+		rconf.LogExporters[i] = api.RESTLogExporter{
+			Name: e.Name, Type: e.Type, Enable: e.Enable, URL: e.URL, Token: e.Token, Username: e.Username, Password: e.Password,
+			Index: e.Index, Categories: e.Categories, CACert: e.CACert, SkipVerify: e.SkipVerify,
+			Brokers: e.Brokers, Topic: e.Topic, TLS: e.TLS, SASLMechanism: e.SASLMechanism, PartitionKey: e.PartitionKey,
+		}
+		rconf.LogExporters[i].Topics = make([]*api.RESTKafkaTopic, len(e.Topics))
+		for j, t := range e.Topics {
+			rconf.LogExporters[i].Topics[j] = &api.RESTKafkaTopic{Category: t.Category, Topic: t.Topic}
+		}
+	}
+
+	rconf.LogCorrelation = api.RESTLogCorrelation{
+		Enable: systemConfigCache.LogCorrelation.Enable, Window: systemConfigCache.LogCorrelation.Window,
+	}
+	rconf.FlowExport = api.RESTFlowExport{
+		Enable: systemConfigCache.FlowExport.Enable, Collector: systemConfigCache.FlowExport.Collector,
+		Format: systemConfigCache.FlowExport.Format,
+	}
+	smtp := &systemConfigCache.SMTP
+	rconf.SMTP = api.RESTSMTPConfig{
+		Enable: smtp.Enable, Server: smtp.Server, Port: smtp.Port, Security: smtp.Security, SkipVerify: smtp.SkipVerify,
+		Username: smtp.Username, Password: smtp.Password, From: smtp.From, Digest: smtp.Digest, DigestLevel: smtp.DigestLevel,
+		Routes: make([]*api.RESTSMTPRoute, len(smtp.Routes)),
+	}
+	for i, r := range smtp.Routes {
+		rconf.SMTP.Routes[i] = &api.RESTSMTPRoute{Recipients: r.Recipients, Categories: r.Categories, Level: r.Level}
+	}
+
+	rconf.SyslogCategoryMaps = make([]api.RESTSyslogCategoryMap, len(systemConfigCache.SyslogCategoryMaps))
+	for i, m := range systemConfigCache.SyslogCategoryMaps {
+		rconf.SyslogCategoryMaps[i] = api.RESTSyslogCategoryMap{Category: m.Category, Facility: m.Facility, Level: m.Level}
+	}
+
 	rconf.Webhooks = make([]api.RESTWebhook, len(systemConfigCache.Webhooks))
 	for i, wh := range systemConfigCache.Webhooks {
-		rconf.Webhooks[i] = api.RESTWebhook{Name: wh.Name, Url: wh.Url, Enable: wh.Enable, Type: wh.Type, CfgType: api.CfgTypeUserCreated}
+		rconf.Webhooks[i] = api.RESTWebhook{Name: wh.Name, Url: wh.Url, Enable: wh.Enable, Type: wh.Type, RoutingKey: wh.RoutingKey, CfgType: api.CfgTypeUserCreated}
+		rconf.Webhooks[i].Headers = webhookHeaders2REST(wh.Headers)
 	}
 
 	proxy := systemConfigCache.RegistryHttpProxy
@@ -418,6 +493,12 @@ func systemConfigUpdate(nType cluster.ClusterNotifyType, key string, value []byt
 			scheduleIPPolicyCalculation(true)
 			scheduleDlpRuleCalculation(true)
 		}
+		if cfg.HostNetPolicyStatus != systemConfigCache.HostNetPolicyStatus {
+			scheduleIPPolicyCalculation(true)
+		} else if cfg.HostNetPolicyStatus &&
+			cfg.HostNetPolicyMode != systemConfigCache.HostNetPolicyMode {
+			scheduleIPPolicyCalculation(true)
+		}
 		if cfg.DisableNetPolicy != systemConfigCache.DisableNetPolicy && cfg.DisableNetPolicy == false {
 			bSchedulePolicy = true
 			scheduleDlpRuleCalculation(true)
@@ -458,10 +539,14 @@ func systemConfigUpdate(nType cluster.ClusterNotifyType, key string, value []byt
 	webhookCachTemp := make(map[string]*webhookCache, 0)
 	for _, h := range systemConfigCache.Webhooks {
 		if h.Enable {
-			webhookCachTemp[h.Name] = &webhookCache{conn: common.NewWebHook(h.Url), target: h.Type}
+			webhookCachTemp[h.Name] = &webhookCache{conn: common.NewWebHook(h.Url, h.Headers, h.RoutingKey), target: h.Type}
 		}
 	}
 	webhookCacheMap = webhookCachTemp
+	configFlowExport(&systemConfigCache.FlowExport)
+	configLogExporters(systemConfigCache.LogExporters)
+	configSMTP(&systemConfigCache.SMTP)
+	configLogCorrelation(&systemConfigCache.LogCorrelation)
 
 	syslogMutexLock()
 	defer syslogMutexUnlock()
@@ -487,6 +572,10 @@ func configInit() {
 	if systemConfigCache.SyslogEnable {
 		syslogger = common.NewSyslogger(&systemConfigCache.CLUSSyslogConfig)
 	}
+	configFlowExport(&systemConfigCache.FlowExport)
+	configLogExporters(systemConfigCache.LogExporters)
+	configSMTP(&systemConfigCache.SMTP)
+	configLogCorrelation(&systemConfigCache.LogCorrelation)
 	if localDev.Host.Platform == share.PlatformKubernetes && localDev.Host.Flavor == share.FlavorRancher {
 		if cctx.RancherSSO {
 			systemConfigCache.AuthByPlatform = true
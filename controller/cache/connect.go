@@ -65,14 +65,17 @@ type graphEntry struct {
 	xff          uint8
 	toSidecar    uint8
 	fqdn         string // server fqdn if it is egress direction. otherwise, the fqdn is empty
+	meshID       string // client identity carried by the service mesh sidecar
 }
 
 type graphKey struct {
 	port        uint16
 	ipproto     uint8
 	application uint32
-	cip         uint32 // client ip
-	sip         uint32 // server ip
+	cip         uint32            // client ip
+	sip         uint32            // server ip
+	cip6        [net.IPv6len]byte // client ipv6, empty for ipv4
+	sip6        [net.IPv6len]byte // server ipv6, empty for ipv4
 }
 
 type graphAttr struct {
@@ -131,6 +134,11 @@ func graphMutexRUnlock() {
 	cctx.MutexLog.WithFields(log.Fields{"goroutine": utils.GetGID()}).Debug("Released")
 }
 
+// Conversation entries not seen in the retention period are removed, so the graph doesn't keep growing in
+// large clusters with short-lived pods
+const conversationRetention = time.Duration(time.Hour * 24 * 7)
+const conversationPrunePeriod = time.Duration(time.Minute * 30)
+
 // Not to do grouping too often, Only when no new link is added for 10 seconds;
 const policyProcDelayIdle = time.Duration(time.Second * 10)
 
@@ -182,6 +190,7 @@ func conn2Violation(conn *share.CLUSConnection, server uint32) *api.Violation {
 		ClientIP:      net.IP(conn.ClientIP).String(),
 		ServerIP:      net.IP(conn.ServerIP).String(),
 		FQDN:          conn.FQDN,
+		MeshID:        conn.MeshID,
 		ClientName:    cwln.name,
 		ClientDomain:  cwln.domain,
 		ClientImage:   cwln.image,
@@ -191,6 +200,7 @@ func conn2Violation(conn *share.CLUSConnection, server uint32) *api.Violation {
 		ServerImage:   swln.image,
 		ServerService: swln.service,
 		Xff:           conn.Xff,
+		Controls:      violationRegulatoryControls,
 	}
 	if conn.PolicyAction == C.DP_POLICY_ACTION_DENY {
 		c.Level = api.LogLevelCRIT
@@ -422,6 +432,41 @@ func deleteConversationByPolicyId(fromNode, toNode string, id uint32) {
 	}
 }
 
+// Remove the conversation entries that are not seen since the given time. Caller must hold graphMutex.
+func pruneConversationByAge(before uint32) int {
+	var pruned int
+	for n := range wlGraph.All().Iter() {
+		node := n.(string)
+		outs := wlGraph.OutsByLink(node, graphLink)
+		if outs == nil {
+			continue
+		}
+		for o := range outs.Iter() {
+			a := wlGraph.Attr(node, graphLink, o.(string))
+			if a == nil {
+				continue
+			}
+			attr := a.(*graphAttr)
+			var found bool
+			for gkey, ge := range attr.entries {
+				if ge.last < before {
+					delete(attr.entries, gkey)
+					found = true
+					pruned++
+				}
+			}
+			if found {
+				if len(attr.entries) == 0 {
+					wlGraph.DeleteLink(node, graphLink, o.(string))
+				} else {
+					recalcConversation(attr)
+				}
+			}
+		}
+	}
+	return pruned
+}
+
 // obsolete. Use grpc instead
 func connectUpdate(nType cluster.ClusterNotifyType, key string, value []byte, modifyIdx uint64) {
 	if checkModifyIdx(syncCatgGraphIdx, modifyIdx) == false {
@@ -480,10 +525,22 @@ func UpdateConnections(conns []*share.CLUSConnection) {
 			continue
 		}
 
+		if local := hostNodeAttr(conn); local != nil {
+			if conn.Ingress {
+				sa = local
+			} else {
+				ca = local
+			}
+		}
+
 		if !postQualifyConnect(conn, ca, sa) {
 			continue
 		}
 
+		if conn.PolicyId != 0 {
+			countPolicyRuleHit(conn.PolicyId, uint64(conn.Sessions), int64(conn.LastSeenAt))
+		}
+
 		cctx.ConnLog.WithFields(log.Fields{
 			"agent":          container.ShortContainerId(conn.AgentID),
 			"host":           conn.HostID,
@@ -559,7 +616,15 @@ func preQualifyConnect(conn *share.CLUSConnection) bool {
 		}
 	}
 
-	if _, ok := wlCacheMap[localWL]; !ok {
+	if isHostNodeEndpoint(localWL, conn.HostID) {
+		if _, ok := hostCacheMap[conn.HostID]; !ok {
+			cctx.ConnLog.WithFields(log.Fields{"id": conn.HostID}).Debug("Ignore connection reported from a left host")
+			return false
+		}
+		return true
+	}
+
+	if _, ok := wlCacheMap.get(localWL); !ok {
 		cctx.ConnLog.WithFields(log.Fields{"id": localWL}).Debug("Ignore connection reported from a left container")
 		return false
 	}
@@ -567,9 +632,28 @@ func preQualifyConnect(conn *share.CLUSConnection) bool {
 	return true
 }
 
+// Connections of host processes are reported with the host as the local endpoint
+// when host network policy is enabled
+func isHostNodeEndpoint(name, hostID string) bool {
+	return hostID != "" && name == specialEPName(api.LearnedHostPrefix, hostID)
+}
+
+func hostNodeAttr(conn *share.CLUSConnection) *nodeAttr {
+	var localWL string
+	if conn.Ingress {
+		localWL = conn.ServerWL
+	} else {
+		localWL = conn.ClientWL
+	}
+	if !isHostNodeEndpoint(localWL, conn.HostID) {
+		return nil
+	}
+	return &nodeAttr{host: true, managed: true, hostID: conn.HostID}
+}
+
 // With cacheMutex hold
 func isWorkloadQuarantine(id string) bool {
-	if cache, ok := wlCacheMap[id]; ok {
+	if cache, ok := wlCacheMap.get(id); ok {
 		return cache.workload.Quarantine
 	}
 	return false
@@ -1530,36 +1614,38 @@ func (m CacheMethod) GetAllConverEndpoints(view string, acc *access.AccessContro
 
 	switch view {
 	case "":
-		for _, cache := range wlCacheMap {
+		wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 			if !acc.Authorize(cache.workload, nil) {
-				continue
+				return true
 			}
 			if common.OEMIgnoreWorkload(cache.workload) {
-				continue
+				return true
 			}
 			if cache.workload.ShareNetNS == "" {
 				eps = append(eps, workload2EndpointREST(cache, false))
 			}
-		}
+			return true
+		})
 	case api.QueryValueViewPod, api.QueryValueViewPodOnly:
-		for _, cache := range wlCacheMap {
+		wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 			if !acc.Authorize(cache.workload, nil) {
-				continue
+				return true
 			}
 			if common.OEMIgnoreWorkload(cache.workload) {
-				continue
+				return true
 			}
 			if cache.workload.ShareNetNS == "" {
 				ep := workload2EndpointREST(cache, true)
 				eps = append(eps, ep)
 			}
-		}
+			return true
+		})
 	}
 
 	all := wlGraph.All()
 
 	for n := range all.Iter() {
-		if _, ok := wlCacheMap[n.(string)]; !ok {
+		if _, ok := wlCacheMap.get(n.(string)); !ok {
 			if ep := getNonWorkloadEndpoint(n.(string)); ep != nil {
 				if !acc.Authorize(ep, nil) {
 					continue
@@ -1578,7 +1664,7 @@ func (m CacheMethod) GetConverEndpoint(name string, acc *access.AccessControl) (
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
 
-	if cache, ok := wlCacheMap[name]; ok {
+	if cache, ok := wlCacheMap.get(name); ok {
 		if !acc.Authorize(cache.workload, nil) {
 			return nil, common.ErrObjectAccessDenied
 		}
@@ -1633,9 +1719,10 @@ func (m CacheMethod) getApplicationConver(src, dst string, acc *access.AccessCon
 				PolicyAction: common.PolicyActionRESTString(entry.policyAction),
 				PolicyID:     entry.policyID,
 				LastSeenAt:   api.RESTTimeString(time.Unix(int64(entry.last), 0).UTC()),
-				CIP:          utils.Int2IPv4(key.cip).String(),
-				SIP:          utils.Int2IPv4(key.sip).String(),
+				CIP:          graphKeyIP(key.cip, key.cip6).String(),
+				SIP:          graphKeyIP(key.sip, key.sip6).String(),
 				FQDN:         entry.fqdn,
+				MeshID:       entry.meshID,
 			}
 			c.Application, _ = common.AppNameMap[key.application]
 			c.Server, _ = common.AppNameMap[entry.server]
@@ -1799,7 +1886,7 @@ func (m CacheMethod) GetAllApplicationConvers(
 				// The 'to' end is not visible to the login user, still include the conversation.
 				// Cannot add the endpoint to epsMap, which will have better performace if the endpoint is gonna used
 				// many times, because we use epsMap to check conversation duplication, see 'in-link' logic.
-				if cache, ok := wlCacheMap[o.(string)]; ok {
+				if cache, ok := wlCacheMap.get(o.(string)); ok {
 					to = workload2EndpointREST(cache, true)
 				} else {
 					to = getNonWorkloadEndpoint(o.(string))
@@ -1830,7 +1917,7 @@ func (m CacheMethod) GetAllApplicationConvers(
 			}
 
 			// the 'from' end is not visible to the login user, still include the conversation.
-			if cache, ok := wlCacheMap[o.(string)]; ok {
+			if cache, ok := wlCacheMap.get(o.(string)); ok {
 				from = workload2EndpointREST(cache, true)
 			} else {
 				from = getNonWorkloadEndpoint(o.(string))
@@ -1885,6 +1972,107 @@ func (m CacheMethod) GetAllApplicationConvers(
 	return convers, endpoints
 }
 
+type fedConverFlowKey struct {
+	ingress    bool
+	service    string
+	ip         string
+	port       uint16
+	mappedPort uint16
+	ipproto    uint8
+	app        uint32
+}
+
+func addFedConverFlows(flows map[fedConverFlowKey]*share.CLUSFedConverFlow, cache *workloadCache, attr *graphAttr, ingress bool) {
+	for key, entry := range attr.entries {
+		fk := fedConverFlowKey{ingress: ingress, service: cache.learnedGroupName, port: key.port, ipproto: key.ipproto, app: key.application}
+		if ingress {
+			fk.ip = graphKeyIP(key.cip, key.cip6).String()
+			fk.mappedPort = entry.mappedPort
+		} else {
+			fk.ip = graphKeyIP(key.sip, key.sip6).String()
+		}
+		flow, ok := flows[fk]
+		if !ok {
+			flow = &share.CLUSFedConverFlow{
+				Ingress:    ingress,
+				Service:    cache.learnedGroupName,
+				Domain:     cache.workload.Domain,
+				IP:         fk.ip,
+				Port:       fk.port,
+				MappedPort: fk.mappedPort,
+				IPProto:    fk.ipproto,
+			}
+			flow.Application, _ = common.AppNameMap[key.application]
+			flows[fk] = flow
+		}
+		flow.Bytes += entry.bytes
+		flow.Sessions += entry.sessions
+		if entry.last > flow.LastSeen {
+			flow.LastSeen = entry.last
+		}
+	}
+}
+
+// Flows between the workloads and the endpoints outside the cluster, and the host ips of the cluster.
+// Master cluster matches them to build the conversation map across the clusters in federation.
+func (m CacheMethod) GetFedConverFlows() ([]*share.CLUSFedConverFlow, []string) {
+	graphMutexRLock()
+	defer graphMutexRUnlock()
+
+	// It's OK to lock cacheMutex inside graphMutex
+	cacheMutexRLock()
+	defer cacheMutexRUnlock()
+
+	// remote endpoints are external/unmanaged endpoints and address groups
+	isRemote := func(node string) bool {
+		if _, ok := wlCacheMap.get(node); ok {
+			return false
+		}
+		if a := wlGraph.Attr(node, attrLink, dummyEP); a != nil {
+			attr := a.(*nodeAttr)
+			return !attr.managed && !attr.ipsvcgrp
+		}
+		return false
+	}
+
+	flows := make(map[fedConverFlowKey]*share.CLUSFedConverFlow)
+	for n := range wlGraph.All().Iter() {
+		cache, ok := wlCacheMap.get(n.(string))
+		if !ok || cache.learnedGroupName == "" {
+			continue
+		}
+		for o := range wlGraph.OutsByLink(cache.workload.ID, graphLink).Iter() {
+			if isRemote(o.(string)) {
+				if a := wlGraph.Attr(cache.workload.ID, graphLink, o.(string)); a != nil {
+					addFedConverFlows(flows, cache, a.(*graphAttr), false)
+				}
+			}
+		}
+		for o := range wlGraph.InsByLink(cache.workload.ID, graphLink).Iter() {
+			if isRemote(o.(string)) {
+				if a := wlGraph.Attr(o.(string), graphLink, cache.workload.ID); a != nil {
+					addFedConverFlows(flows, cache, a.(*graphAttr), true)
+				}
+			}
+		}
+	}
+
+	list := make([]*share.CLUSFedConverFlow, 0, len(flows))
+	for _, flow := range flows {
+		list = append(list, flow)
+	}
+
+	addrs := make([]string, 0, len(ipHostMap))
+	for ip, hd := range ipHostMap {
+		if hd.hostID != "" || hd.orched {
+			addrs = append(addrs, ip)
+		}
+	}
+	sort.Strings(addrs)
+
+	return list, addrs
+}
+
 // -------------------------------------------------
 
 func DeleteConver(src, dst string) {
@@ -38,7 +38,7 @@ func TestAliasEndpoint(t *testing.T) {
 	accReadAll := access.NewReaderAccessControl()
 
 	wl := share.CLUSWorkload{ID: "1", Name: "c1"}
-	wlCacheMap[wl.ID] = &workloadCache{workload: &wl, displayName: "container1"}
+	wlCacheMap.set(wl.ID, &workloadCache{workload: &wl, displayName: "container1"})
 
 	wlGraph = graph.NewGraph()
 
@@ -95,3 +95,31 @@ func TestAliasEndpoint(t *testing.T) {
 
 	postTest()
 }
+
+func TestPruneConversationByAge(t *testing.T) {
+	preTest()
+
+	wlGraph = graph.NewGraph()
+
+	wlGraph.AddLink("c1", graphLink, "c2", &graphAttr{entries: map[graphKey]*graphEntry{
+		graphKey{port: 80, ipproto: 6}:  &graphEntry{bytes: 100, sessions: 1, last: 100},
+		graphKey{port: 443, ipproto: 6}: &graphEntry{bytes: 200, sessions: 2, last: 300},
+	}})
+	wlGraph.AddLink("c1", graphLink, "c3", &graphAttr{entries: map[graphKey]*graphEntry{
+		graphKey{port: 80, ipproto: 6}: &graphEntry{bytes: 100, sessions: 1, last: 100},
+	}})
+
+	if pruned := pruneConversationByAge(200); pruned != 2 {
+		t.Errorf("Unexpected pruned entries: %d", pruned)
+	}
+	if a := wlGraph.Attr("c1", graphLink, "c2"); a == nil {
+		t.Errorf("Conversation should not be removed")
+	} else if attr := a.(*graphAttr); len(attr.entries) != 1 || attr.bytes != 200 || attr.sessions != 2 {
+		t.Errorf("Unexpected conversation: %+v", attr)
+	}
+	if a := wlGraph.Attr("c1", graphLink, "c3"); a != nil {
+		t.Errorf("Conversation without entry should be removed")
+	}
+
+	postTest()
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+)
+
+const defaultLogCorrelationWindow = 60
+
+var correlatorMutex sync.RWMutex
+var correlatorCfg share.CLUSLogCorrelation
+var correlator *common.Correlator
+
+// The correlator is kept in every controller, only the lead correlates the logs
+func configLogCorrelation(cfg *share.CLUSLogCorrelation) {
+	correlatorMutex.Lock()
+	defer correlatorMutex.Unlock()
+
+	if correlatorCfg == *cfg && (correlator != nil) == cfg.Enable {
+		return
+	}
+	if correlator != nil {
+		// The counts of the open windows are reported in the background
+		go correlator.Close()
+		correlator = nil
+	}
+	correlatorCfg = *cfg
+	if !cfg.Enable {
+		return
+	}
+
+	window := cfg.Window
+	if window == 0 {
+		window = defaultLogCorrelationWindow
+	}
+	log.WithFields(log.Fields{"window": window}).Info("Log correlation applied")
+	correlator = common.NewCorrelator(time.Duration(window)*time.Second, emitCorrelatedLog)
+}
+
+// Return true if the log is taken by the correlator, which sends it to the outputs
+func correlateLog(cat string, rlog interface{}) bool {
+	correlatorMutex.RLock()
+	defer correlatorMutex.RUnlock()
+
+	if correlator == nil {
+		return false
+	}
+	key, chain := correlateKeys(rlog)
+	correlator.Add(&common.CorrelateRecord{Category: cat, Time: time.Now(), Log: rlog, Key: key, Chain: chain})
+	return true
+}
+
+// Identical violations and threats are deduplicated. Logs of the same workload are correlated, so the network
+// violations, the threats and the incidents of an attack on a workload can be followed in the SIEM.
+func correlateKeys(rlog interface{}) (string, string) {
+	switch l := rlog.(type) {
+	case *api.Violation:
+		key := fmt.Sprintf("violation/%s/%s/%s/%s/%s/%d/%d/%d/%s/%s",
+			l.ClientWL, l.ClientIP, l.ServerWL, l.ServerIP, l.FQDN, l.ServerPort, l.IPProto, l.PolicyID, l.PolicyAction, l.Level)
+		if l.ClientWL != "" {
+			return key, l.ClientWL
+		}
+		return key, l.ServerWL
+	case *api.Threat:
+		key := fmt.Sprintf("threat/%d/%s/%s/%s/%s/%d/%d/%s",
+			l.ThreatID, l.ClientWL, l.ClientIP, l.ServerWL, l.ServerIP, l.ServerPort, l.IPProto, l.Action)
+		if l.Target == api.TargetClient {
+			return key, l.ClientWL
+		}
+		return key, l.ServerWL
+	case *api.Incident:
+		return "", l.WorkloadID
+	}
+	return "", ""
+}
+
+func emitCorrelatedLog(rec *common.CorrelateRecord, repeat uint32, correlationID string) {
+	if !isLeader() {
+		return
+	}
+
+	if repeat == 1 {
+		repeat = 0
+	}
+	switch l := rec.Log.(type) {
+	case *api.Violation:
+		v := *l
+		v.RepeatCount, v.CorrelationID = repeat, correlationID
+		outputViolation(&v)
+	case *api.Threat:
+		t := *l
+		t.RepeatCount, t.CorrelationID = repeat, correlationID
+		outputThreat(&t)
+	case *api.Incident:
+		i := *l
+		i.RepeatCount, i.CorrelationID = repeat, correlationID
+		outputIncident(&i)
+	}
+}
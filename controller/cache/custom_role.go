@@ -20,38 +20,61 @@ func parseUserRolePermissions(r *share.CLUSUserRole) *share.CLUSUserRoleInternal
 	for _, option := range access.PermissionOptions {
 		permissionOptions[option.ID] = option
 	}
-	var readPermits, writePermits uint64
+	var readPermits, writePermits, unscopedReadPermits, unscopedWritePermits uint64
+	var domainScopes []*share.CLUSRoleDomainScope
 	for _, permission := range r.Permissions {
 		if option, ok := permissionOptions[permission.ID]; ok {
 			if access.HiddenPermissions.Contains(permission.ID) {
 				continue
 			}
+			var read, write uint64
 			if len(option.ComplexPermits) > 0 {
 				for _, option2 := range option.ComplexPermits {
 					if permission.Read && option2.ReadSupported {
-						readPermits |= option2.Value
+						read |= option2.Value
 					}
 					if permission.Write && option2.WriteSupported {
-						writePermits |= option2.Value
+						write |= option2.Value
 					}
 				}
 			} else {
 				if permission.Read && option.ReadSupported {
-					readPermits |= option.Value
+					read |= option.Value
 				}
 				if permission.Write && option.WriteSupported {
-					writePermits |= option.Value
+					write |= option.Value
 				}
 			}
+			readPermits |= read
+			writePermits |= write
+			if len(permission.Domains) > 0 {
+				domainScopes = append(domainScopes, &share.CLUSRoleDomainScope{
+					ID:           permission.ID,
+					ReadPermits:  read,
+					WritePermits: write,
+					Domains:      permission.Domains,
+				})
+			} else {
+				unscopedReadPermits |= read
+				unscopedWritePermits |= write
+			}
 		}
 	}
 
+	// basic permissions shared by permissions without domain restriction are effective everywhere.
+	// A scope left without permits is kept for the domains of its permission.
+	for _, scope := range domainScopes {
+		scope.ReadPermits &^= unscopedReadPermits
+		scope.WritePermits &^= unscopedWritePermits
+	}
+
 	userRole := &share.CLUSUserRoleInternal{
 		Name:         r.Name,
 		Comment:      r.Comment,
 		Reserved:     r.Reserved,
 		ReadPermits:  readPermits,
 		WritePermits: writePermits,
+		DomainScopes: domainScopes,
 	}
 	return userRole
 }
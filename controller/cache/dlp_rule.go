@@ -267,7 +267,7 @@ func assocWl2PolicyIds(grp string, senset utils.Set, outside_wl2sensor map[strin
 	if grpcache, ok := groupCacheMap[grp]; ok {
 		for _, m := range grpcache.members.ToSlice() {
 			wlid := m.(string)
-			if wlcache, ok := wlCacheMap[wlid]; ok && wlcache.workload.HasDatapath {
+			if wlcache, ok := wlCacheMap.get(wlid); ok && wlcache.workload.HasDatapath {
 				inside_grps = inside_grps.Union(wlcache.groups)
 			}
 		}
@@ -306,7 +306,7 @@ func assocWl2PolicyIds(grp string, senset utils.Set, outside_wl2sensor map[strin
 		for _, m := range grpcache.members.ToSlice() {
 			wlid := m.(string)
 			//only include wl that has datapath to save memory and cpu
-			if wlcache, exist := wlCacheMap[wlid]; exist && !wlcache.workload.HasDatapath {
+			if wlcache, exist := wlCacheMap.get(wlid); exist && !wlcache.workload.HasDatapath {
 				continue
 			}
 			if rids, ok := wl2policies[wlid]; !ok {
@@ -323,7 +323,7 @@ func assocWl2PolicyIds(grp string, senset utils.Set, outside_wl2sensor map[strin
 			for _, om := range ogrpcache.members.ToSlice() {
 				owlid := om.(string)
 				//only include wl that has datapath to save memory and cpu
-				if wlcache, exist := wlCacheMap[owlid]; exist && !wlcache.workload.HasDatapath {
+				if wlcache, exist := wlCacheMap.get(owlid); exist && !wlcache.workload.HasDatapath {
 					continue
 				}
 				if orids, ok := outside_wl2policies[owlid]; !ok {
@@ -367,7 +367,7 @@ func assocWl2Sensors(grp string, senset utils.Set, wl2sensors map[string]map[str
 		for _, m := range grpcache.members.ToSlice() {
 			wlid := m.(string)
 			//only include wl that has datapath to save memory and cpu
-			if wlcache, exist := wlCacheMap[wlid]; exist && !wlcache.workload.HasDatapath {
+			if wlcache, exist := wlCacheMap.get(wlid); exist && !wlcache.workload.HasDatapath {
 				continue
 			}
 			if sam, ok := wl2sensors[wlid]; !ok {
@@ -583,7 +583,7 @@ func getWlRules(cgdrs *share.CLUSWorkloadDlpRules, wl2rules map[string]map[strin
 			RuleIds:       make([]uint32, 0),
 			RuleType:      ruletype,
 		}
-		if wlcache, ok := wlCacheMap[wlid]; ok {
+		if wlcache, ok := wlCacheMap.get(wlid); ok {
 			wlrule.PolicyMode, _ = getWorkloadEffectivePolicyMode(wlcache)
 		} else {
 			wlrule.PolicyMode = ""
@@ -69,6 +69,14 @@ func getDomainData(name string) *share.CLUSDomain {
 	return nil
 }
 
+// The policy mode of the new services in the domain overrides the system setting
+func getDomainNewServicePolicyMode(name string) string {
+	if domain := getDomainData(name); domain != nil && domain.NewServicePolicyMode != "" {
+		return domain.NewServicePolicyMode
+	}
+	return getNewServicePolicyMode()
+}
+
 func domainConfigUpdate(nType cluster.ClusterNotifyType, key string, value []byte) {
 	name := share.CLUSDomainKey2Name(key)
 	switch nType {
@@ -175,19 +183,24 @@ func (m CacheMethod) GetAllDomains(acc *access.AccessControl) ([]*api.RESTDomain
 		if !acc.Authorize(cache.domain, nil) {
 			continue
 		}
-		dmap[name] = &api.RESTDomain{Name: name, Tags: cache.domain.Tags, Labels: cache.domain.Labels}
+		dmap[name] = &api.RESTDomain{
+			Name:                 name,
+			Tags:                 cache.domain.Tags,
+			Labels:               cache.domain.Labels,
+			NewServicePolicyMode: cache.domain.NewServicePolicyMode,
+		}
 	}
 
 	domainMutex.RUnlock()
 
 	cacheMutexRLock()
 
-	for _, cache := range wlCacheMap {
+	wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 		if !acc.Authorize(cache.workload, nil) {
-			continue
+			return true
 		}
 		if common.OEMIgnoreWorkload(cache.workload) {
-			continue
+			return true
 		}
 
 		domain := cache.workload.Domain
@@ -204,7 +217,8 @@ func (m CacheMethod) GetAllDomains(acc *access.AccessControl) ([]*api.RESTDomain
 				}
 			}
 		}
-	}
+		return true
+	})
 	for _, cache := range groupCacheMap {
 		if err := authorizeService(cache, acc); err != nil {
 			continue
@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// On master cluster, the fed objects that are not selected by the sync selectors are not deployed. The objects that
+// reference an unselected fed group, like network rules and process profiles, are not deployed either, because
+// managed clusters delete them together with the group.
+
+type tFedSyncSelector struct {
+	include utils.Set
+	exclude utils.Set
+}
+
+type tFedSyncSelectors map[string]*tFedSyncSelector
+
+func newFedSyncSelectors(selectors map[string]*share.CLUSFedSyncSelector) tFedSyncSelectors {
+	s := make(tFedSyncSelectors, len(selectors))
+	for objType, sel := range selectors {
+		if sel != nil {
+			s[objType] = &tFedSyncSelector{
+				include: utils.NewSetFromStringSlice(sel.Include),
+				exclude: utils.NewSetFromStringSlice(sel.Exclude),
+			}
+		}
+	}
+	return s
+}
+
+func (s tFedSyncSelectors) selected(objType, name string) bool {
+	if sel, ok := s[objType]; ok {
+		if sel.include.Cardinality() > 0 && !sel.include.Contains(name) {
+			return false
+		}
+		return !sel.exclude.Contains(name)
+	}
+	return true
+}
+
+// non-fed groups, like "nodes", are not selectable
+func (s tFedSyncSelectors) groupSelected(name string) bool {
+	return !strings.HasPrefix(name, api.FederalGroupPrefix) || s.selected(share.FedSyncObjGroup, name)
+}
+
+func (s tFedSyncSelectors) ruleSelected(objType string, id uint32) bool {
+	return s.selected(objType, strconv.FormatUint(uint64(id), 10))
+}
+
+// The settings are built for polling, so only the lists are replaced and the objects from cache are not modified
+func (s tFedSyncSelectors) selectFedRules(settings *api.RESTFedRulesSettings) {
+	if len(s) == 0 {
+		return
+	}
+
+	if data := settings.GroupsData; data != nil {
+		groups := make([]*share.CLUSGroup, 0, len(data.Groups))
+		for _, g := range data.Groups {
+			if s.groupSelected(g.Name) {
+				groups = append(groups, g)
+			}
+		}
+		data.Groups = groups
+	}
+
+	if data := settings.NetworkRulesData; data != nil {
+		ids := utils.NewSet()
+		rules := make([]*share.CLUSPolicyRule, 0, len(data.Rules))
+		for _, r := range data.Rules {
+			if s.ruleSelected(share.FedSyncObjNetworkRule, r.ID) && s.groupSelected(r.From) && s.groupSelected(r.To) {
+				rules = append(rules, r)
+				ids.Add(r.ID)
+			}
+		}
+		heads := make([]*share.CLUSRuleHead, 0, len(rules))
+		for _, h := range data.RuleHeads {
+			if ids.Contains(h.ID) {
+				heads = append(heads, h)
+			}
+		}
+		data.Rules, data.RuleHeads = rules, heads
+	}
+
+	if data := settings.ResponseRulesData; data != nil {
+		rules := make(map[uint32]*share.CLUSResponseRule, len(data.Rules))
+		for id, r := range data.Rules {
+			if s.ruleSelected(share.FedSyncObjResponseRule, id) && (r.Group == "" || s.groupSelected(r.Group)) {
+				rules[id] = r
+			}
+		}
+		heads := make([]*share.CLUSRuleHead, 0, len(rules))
+		for _, h := range data.RuleHeads {
+			if _, ok := rules[h.ID]; ok {
+				heads = append(heads, h)
+			}
+		}
+		data.Rules, data.RuleHeads = rules, heads
+	}
+
+	if data := settings.AdmCtrlRulesData; data != nil {
+		for ruleType, admRules := range data.Rules {
+			if admRules == nil {
+				continue
+			}
+			ruleMap := make(map[uint32]*share.CLUSAdmissionRule, len(admRules.RuleMap))
+			for id, r := range admRules.RuleMap {
+				if s.ruleSelected(share.FedSyncObjAdmissionRule, id) {
+					ruleMap[id] = r
+				}
+			}
+			heads := make([]*share.CLUSRuleHead, 0, len(ruleMap))
+			for _, h := range admRules.RuleHeads {
+				if _, ok := ruleMap[h.ID]; ok {
+					heads = append(heads, h)
+				}
+			}
+			data.Rules[ruleType] = &share.CLUSAdmissionRules{RuleMap: ruleMap, RuleHeads: heads}
+		}
+	}
+
+	if data := settings.FileMonitorData; data != nil {
+		profiles := make([]*share.CLUSFileMonitorProfile, 0, len(data.Profiles))
+		for _, p := range data.Profiles {
+			if s.groupSelected(p.Group) {
+				profiles = append(profiles, p)
+			}
+		}
+		accessRules := make([]*share.CLUSFileAccessRule, 0, len(data.AccessRules))
+		for _, r := range data.AccessRules {
+			if s.groupSelected(r.Group) {
+				accessRules = append(accessRules, r)
+			}
+		}
+		data.Profiles, data.AccessRules = profiles, accessRules
+	}
+
+	if data := settings.ProcessProfilesData; data != nil {
+		profiles := make([]*share.CLUSProcessProfile, 0, len(data.Profiles))
+		for _, p := range data.Profiles {
+			if s.groupSelected(p.Group) {
+				profiles = append(profiles, p)
+			}
+		}
+		data.Profiles = profiles
+	}
+}
+
+// On managed clusters, the fed objects are labeled with the name of master cluster. It's kept out of
+// fedMembershipCache because the objects are converted with cacheMutex locked.
+var fedManagedBy atomic.Value
+
+func updateFedManagedBy(m *share.CLUSFedMembership) {
+	if m.FedRole == api.FedRoleJoint {
+		fedManagedBy.Store(m.MasterCluster.Name)
+	} else {
+		fedManagedBy.Store("")
+	}
+}
+
+func getFedManagedBy(cfgType share.TCfgType) string {
+	if cfgType == share.FederalCfg {
+		if name, ok := fedManagedBy.Load().(string); ok {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestSelectFedRules(t *testing.T) {
+	settings := api.RESTFedRulesSettings{
+		GroupsData: &share.CLUSFedGroupsData{Groups: []*share.CLUSGroup{
+			&share.CLUSGroup{Name: "fed.g1"}, &share.CLUSGroup{Name: "fed.g2"}, &share.CLUSGroup{Name: "fed.g3"},
+		}},
+		NetworkRulesData: &share.CLUSFedNetworkRulesData{
+			Rules: []*share.CLUSPolicyRule{
+				&share.CLUSPolicyRule{ID: 100001, From: "fed.g1", To: "nodes"},
+				&share.CLUSPolicyRule{ID: 100002, From: "fed.g1", To: "fed.g2"},
+				&share.CLUSPolicyRule{ID: 100003, From: "fed.g1", To: "fed.g1"},
+			},
+			RuleHeads: []*share.CLUSRuleHead{&share.CLUSRuleHead{ID: 100001}, &share.CLUSRuleHead{ID: 100002}, &share.CLUSRuleHead{ID: 100003}},
+		},
+		ResponseRulesData: &share.CLUSFedResponseRulesData{
+			Rules: map[uint32]*share.CLUSResponseRule{
+				100001: &share.CLUSResponseRule{ID: 100001},
+				100002: &share.CLUSResponseRule{ID: 100002, Group: "fed.g3"},
+			},
+			RuleHeads: []*share.CLUSRuleHead{&share.CLUSRuleHead{ID: 100001}, &share.CLUSRuleHead{ID: 100002}},
+		},
+		AdmCtrlRulesData: &share.CLUSFedAdmCtrlRulesData{Rules: map[string]*share.CLUSAdmissionRules{
+			share.FedAdmCtrlDenyRulesType: &share.CLUSAdmissionRules{
+				RuleMap:   map[uint32]*share.CLUSAdmissionRule{100001: &share.CLUSAdmissionRule{ID: 100001}, 100002: &share.CLUSAdmissionRule{ID: 100002}},
+				RuleHeads: []*share.CLUSRuleHead{&share.CLUSRuleHead{ID: 100001}, &share.CLUSRuleHead{ID: 100002}},
+			},
+		}},
+		ProcessProfilesData: &share.CLUSFedProcessProfileData{Profiles: []*share.CLUSProcessProfile{
+			&share.CLUSProcessProfile{Group: "fed.g1"}, &share.CLUSProcessProfile{Group: "fed.g2"},
+		}},
+	}
+	admRules := settings.AdmCtrlRulesData.Rules[share.FedAdmCtrlDenyRulesType]
+
+	selectors := newFedSyncSelectors(map[string]*share.CLUSFedSyncSelector{
+		share.FedSyncObjGroup:         &share.CLUSFedSyncSelector{Include: []string{"fed.g1", "fed.g2"}, Exclude: []string{"fed.g2"}},
+		share.FedSyncObjNetworkRule:   &share.CLUSFedSyncSelector{Exclude: []string{"100003"}},
+		share.FedSyncObjAdmissionRule: &share.CLUSFedSyncSelector{Include: []string{"100002"}},
+	})
+	selectors.selectFedRules(&settings)
+
+	if groups := settings.GroupsData.Groups; len(groups) != 1 || groups[0].Name != "fed.g1" {
+		t.Errorf("Unexpected groups: %+v", groups)
+	}
+	if data := settings.NetworkRulesData; len(data.Rules) != 1 || data.Rules[0].ID != 100001 ||
+		len(data.RuleHeads) != 1 || data.RuleHeads[0].ID != 100001 {
+		t.Errorf("Unexpected network rules: %+v", data)
+	}
+	if data := settings.ResponseRulesData; len(data.Rules) != 1 || data.Rules[100001] == nil ||
+		len(data.RuleHeads) != 1 || data.RuleHeads[0].ID != 100001 {
+		t.Errorf("Unexpected response rules: %+v", data)
+	}
+	if data := settings.AdmCtrlRulesData.Rules[share.FedAdmCtrlDenyRulesType]; len(data.RuleMap) != 1 || data.RuleMap[100002] == nil ||
+		len(data.RuleHeads) != 1 || data.RuleHeads[0].ID != 100002 {
+		t.Errorf("Unexpected admission rules: %+v", data)
+	}
+	if len(admRules.RuleMap) != 2 || len(admRules.RuleHeads) != 2 {
+		t.Errorf("Admission rules from cache should not be modified: %+v", admRules)
+	}
+	if profiles := settings.ProcessProfilesData.Profiles; len(profiles) != 1 || profiles[0].Group != "fed.g1" {
+		t.Errorf("Unexpected process profiles: %+v", profiles)
+	}
+}
+
+func TestFedManagedBy(t *testing.T) {
+	updateFedManagedBy(&share.CLUSFedMembership{FedRole: api.FedRoleJoint, MasterCluster: share.CLUSFedMasterClusterInfo{Name: "primary"}})
+	if name := getFedManagedBy(share.FederalCfg); name != "primary" {
+		t.Errorf("Unexpected managed by: %s", name)
+	}
+	if name := getFedManagedBy(share.UserCreated); name != "" {
+		t.Errorf("Unexpected managed by for local object: %s", name)
+	}
+
+	updateFedManagedBy(&share.CLUSFedMembership{FedRole: api.FedRoleMaster})
+	if name := getFedManagedBy(share.FederalCfg); name != "" {
+		t.Errorf("Unexpected managed by on master cluster: %s", name)
+	}
+}
@@ -201,6 +201,7 @@ func fedConfigUpdate(nType cluster.ClusterNotifyType, key string, value []byte)
 				cachedFedSettingBytes = nil
 			}
 			fedMembershipCache = m
+			updateFedManagedBy(&m)
 			if m.FedRole == api.FedRoleNone {
 				fedScanDataRevsCache = share.CLUSFedScanRevisions{}
 				fedScanResultMD5 = make(map[string]map[string]string)
@@ -281,7 +282,7 @@ func fedConfigUpdate(nType cluster.ClusterNotifyType, key string, value []byte)
 			fedWebhookCacheTemp := make(map[string]*webhookCache, 0)
 			for _, h := range cfg.Webhooks {
 				if h.Enable {
-					fedWebhookCacheTemp[h.Name] = &webhookCache{conn: common.NewWebHook(h.Url), target: h.Type}
+					fedWebhookCacheTemp[h.Name] = &webhookCache{conn: common.NewWebHook(h.Url, h.Headers, h.RoutingKey), target: h.Type}
 				}
 			}
 			fedWebhookCacheMap = fedWebhookCacheTemp
@@ -335,6 +336,17 @@ func (m CacheMethod) GetFedMember(statusMap map[int]string, acc *access.AccessCo
 		FedRole:       fedMembershipCache.FedRole,
 		LocalRestInfo: fedMembershipCache.LocalRestInfo,
 		UseProxy:      fedMembershipCache.UseProxy,
+		StandbyID:     fedMembershipCache.StandbyID,
+	}
+	if p := fedMembershipCache.FedProxy; p != nil {
+		s.FedProxy = &api.RESTFedProxyConfig{
+			URL:         p.URL,
+			Username:    p.Username,
+			Password:    p.Password,
+			ClientCert:  p.ClientCert,
+			ClientKey:   p.ClientKey,
+			PinnedCerts: p.PinnedCerts,
+		}
 	}
 	if fedMembershipCache.FedRole != api.FedRoleNone {
 		s.MasterCluster = &api.RESTFedMasterClusterInfo{
@@ -395,6 +407,17 @@ func (m CacheMethod) GetFedLocalRestInfo(acc *access.AccessControl) (share.CLUSR
 	return share.CLUSRestServerInfo{}, useProxy
 }
 
+func (m CacheMethod) GetFedProxyConfig(acc *access.AccessControl) *share.CLUSFedProxyConfig {
+	fedCacheMutexRLock()
+	defer fedCacheMutexRUnlock()
+
+	if fedMembershipCache.FedProxy != nil && acc.Authorize(&fedMembershipCache, nil) {
+		cfg := *fedMembershipCache.FedProxy
+		return &cfg
+	}
+	return nil
+}
+
 func (m CacheMethod) GetFedMasterCluster(acc *access.AccessControl) api.RESTFedMasterClusterInfo {
 	fedCacheMutexRLock()
 	defer fedCacheMutexRUnlock()
@@ -571,6 +594,7 @@ func (m CacheMethod) GetFedRules(reqRevs map[string]uint64, acc *access.AccessCo
 				case share.FedResponseRulesType:
 					current.ResponseRulesData = &share.CLUSFedResponseRulesData{Revision: fedRev}
 					current.ResponseRulesData.Rules, current.ResponseRulesData.RuleHeads = m.GetFedResponseRulesCache()
+					current.ResponseRulesData.QuarImages = fedResPolicyCache.quarImages
 				case share.FedFileMonitorProfilesType:
 					current.FileMonitorData = &share.CLUSFedFileMonitorData{Revision: fedRev}
 					current.FileMonitorData.Profiles, current.FileMonitorData.AccessRules = m.GetFedFileMonitorProfileCache()
@@ -581,6 +605,7 @@ func (m CacheMethod) GetFedRules(reqRevs map[string]uint64, acc *access.AccessCo
 				}
 			}
 			cacheMutexRUnlock()
+			newFedSyncSelectors(fedSettingsCache.SyncSelectors).selectFedRules(&current)
 			settings, _ = json.Marshal(current)
 
 			tempSettings := make([]byte, len(settings))
@@ -27,6 +27,7 @@ type monitorFilter struct {
 	path      string
 	regex     string
 	recursive bool
+	baseline  bool
 
 	// rule
 	behavior    string
@@ -95,6 +96,7 @@ func fsmonProfileConfigUpdate(nType cluster.ClusterNotifyType, key string, value
 			mf.path = flt.Path
 			mf.regex = flt.Regex
 			mf.recursive = flt.Recursive
+			mf.baseline = flt.Baseline
 			mf.behavior = flt.Behavior
 			mf.customerAdd = flt.CustomerAdd
 			newMp.filters[key] = mf
@@ -114,6 +116,7 @@ func fsmonProfileConfigUpdate(nType cluster.ClusterNotifyType, key string, value
 			mf.path = flt.Path
 			mf.regex = flt.Regex
 			mf.recursive = flt.Recursive
+			mf.baseline = flt.Baseline
 			mf.behavior = flt.Behavior
 			mf.customerAdd = flt.CustomerAdd
 			newMp.filters[key] = mf
@@ -242,6 +245,7 @@ func (m CacheMethod) GetAllFileMonitorProfile(scope string, acc *access.AccessCo
 					Filter:           filter.filter,
 					Recursive:        filter.recursive,
 					Behavior:         filter.behavior,
+					Baseline:         filter.baseline,
 					CreatedTimeStamp: filter.createdAt.Unix(),
 					UpdatedTimeStamp: filter.updatedAt.Unix(),
 				}
@@ -299,6 +303,7 @@ func (m CacheMethod) GetFileMonitorProfile(name string, acc *access.AccessContro
 			Filter:           filter.filter,
 			Recursive:        filter.recursive,
 			Behavior:         filter.behavior,
+			Baseline:         filter.baseline,
 			CreatedTimeStamp: filter.createdAt.Unix(),
 			UpdatedTimeStamp: filter.updatedAt.Unix(),
 		}
@@ -350,6 +355,7 @@ func (m CacheMethod) GetFedFileMonitorProfileCache() ([]*share.CLUSFileMonitorPr
 					Recursive:   filter.recursive,
 					CustomerAdd: filter.customerAdd,
 					Behavior:    filter.behavior,
+					Baseline:    filter.baseline,
 				}
 
 				fafrs[idx] = &share.CLUSFileAccessFilterRule{
@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+)
+
+var flowExportMutex sync.RWMutex
+var flowExportCfg share.CLUSFlowExport
+var flowExporter *common.FlowExporter
+
+// The exporter is kept in every controller, only the lead exports the connections. It's restarted only if the config changes.
+func configFlowExport(cfg *share.CLUSFlowExport) {
+	flowExportMutex.Lock()
+	defer flowExportMutex.Unlock()
+
+	if flowExporter != nil && flowExportCfg == *cfg {
+		return
+	}
+	if flowExporter != nil {
+		flowExporter.Close()
+		flowExporter = nil
+	}
+	flowExportCfg = *cfg
+	if !cfg.Enable {
+		return
+	}
+
+	exporter, err := common.NewFlowExporter(cfg.Collector, cfg.Format, 0)
+	if err != nil {
+		log.WithFields(log.Fields{"collector": cfg.Collector, "error": err}).Error("Failed to create flow exporter")
+		return
+	}
+	log.WithFields(log.Fields{"collector": cfg.Collector, "format": cfg.Format}).Info("Flow exporter applied")
+	flowExporter = exporter
+}
+
+// Agents only report the connections to the lead controller, so they are exported once. The connections are
+// queued and sent in the background; they are dropped if the collector cannot keep up.
+func ExportConnections(conns []*share.CLUSConnection) {
+	flowExportMutex.RLock()
+	defer flowExportMutex.RUnlock()
+
+	if flowExporter != nil {
+		flowExporter.Export(conns)
+	}
+}
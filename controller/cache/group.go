@@ -178,12 +178,24 @@ func group2BriefREST(cache *groupCache, withCap bool) *api.RESTGroupBrief {
 		Kind:            cache.group.Kind,
 		PlatformRole:    cache.group.PlatformRole,
 		BaselineProfile: cache.group.BaselineProfile,
+		Template:        cache.group.Template,
+		RateLimit:       cache.group.RateLimit,
+		RateLimitUnit:   cache.group.RateLimitUnit,
+	}
+	if am := cache.group.AutoMode; am != nil {
+		g.AutoMode = &api.RESTGroupAutoMode{
+			Hold:                am.Hold,
+			ModeAutoD2MDuration: am.ModeAutoD2MDuration,
+			ModeAutoM2PDuration: am.ModeAutoM2PDuration,
+			NoLearnDays:         am.NoLearnDays,
+		}
 	}
 	if withCap {
 		g.CapChgMode = &cache.capChgMode
 		g.CapScorable = &cache.capScorable
 	}
 	g.CfgType, _ = cfgTypeMapping[cache.group.CfgType]
+	g.ManagedBy = getFedManagedBy(cache.group.CfgType)
 	return g
 }
 
@@ -479,7 +491,8 @@ func groupConfigUpdate(nType cluster.ClusterNotifyType, key string, value []byte
 				refreshInternalIPNet()
 			} else if adds.Cardinality() > 0 {
 				for a := range adds.Iter() {
-					ipnet := &net.IPNet{IP: net.ParseIP(a.(string)), Mask: net.CIDRMask(32, 32)}
+					ip := net.ParseIP(a.(string))
+					ipnet := &net.IPNet{IP: ip, Mask: utils.IPHostMask(ip)}
 					updateInternalIPNet(ipnet, share.CLUSIPAddrScopeGlobal, true)
 				}
 			}
@@ -497,6 +510,7 @@ func groupConfigUpdate(nType cluster.ClusterNotifyType, key string, value []byte
 		if ok := isCreateWafGroup(&group); ok {
 			createWafGroup(group.Name, group.CfgType)
 		}
+		policyGroups := getPolicyMemberGroups(cache)
 
 		cacheMutexUnlock()
 
@@ -509,7 +523,12 @@ func groupConfigUpdate(nType cluster.ClusterNotifyType, key string, value []byte
 		log.WithFields(log.Fields{"group": cache.group}).Debug("Update group cache")
 
 		if cache.usedByPolicy.Cardinality() > 0 {
-			scheduleIPPolicyCalculation(false)
+			if isIPServiceGroup(&group) {
+				// addresses of the ip service group are added to other groups
+				scheduleIPPolicyCalculation(false)
+			} else {
+				scheduleGroupIPPolicyCalculation(false, policyGroups...)
+			}
 		} else if cache.members.Cardinality() > 0 {
 			// When a new workload joins and creates new group,
 			// need to triger policy recal fast so that the policy
@@ -517,7 +536,7 @@ func groupConfigUpdate(nType cluster.ClusterNotifyType, key string, value []byte
 
 			//nodes has a member whose id is empty string
 			if !utils.IsGroupNodes(group.Name) {
-				scheduleIPPolicyCalculation(true)
+				scheduleGroupIPPolicyCalculation(true, policyGroups...)
 			}
 		}
 
@@ -941,7 +960,7 @@ func (m CacheMethod) CreateService(svc *api.RESTServiceConfig, acc *access.Acces
 
 	var policyMode string
 	if svc.PolicyMode == nil || *svc.PolicyMode == "" {
-		policyMode = getNewServicePolicyMode()
+		policyMode = getDomainNewServicePolicyMode(svc.Domain)
 	} else {
 		policyMode = *svc.PolicyMode
 	}
@@ -1142,6 +1161,7 @@ func groupWorkloadLeave(id string, param interface{}) {
 	var memberLeave bool
 	bHasGroupProfile := utils.HasGroupProfiles(wlc.learnedGroupName)
 	dptCustomGrps := utils.NewSet()
+	leaveGroups := make([]string, 0)
 
 	cacheMutexLock()
 	for _, cache := range groupCacheMap {
@@ -1152,6 +1172,7 @@ func groupWorkloadLeave(id string, param interface{}) {
 				dptCustomGrps.Add(cache.group.Name)
 			}
 			memberLeave = true
+			leaveGroups = append(leaveGroups, cache.group.Name)
 			log.WithFields(log.Fields{"group": cache.group.Name}).Debug("Leave group")
 			if cache.members.Cardinality() == 0 && cacher.GetUnusedGroupAging() != 0 {
 				scheduleGroupRemoval(cache)
@@ -1164,7 +1185,7 @@ func groupWorkloadLeave(id string, param interface{}) {
 		//container used fqdn related policy
 		//it needs to inform dp that a fqdn
 		//is no longer needed
-		scheduleIPPolicyCalculation(false)
+		scheduleGroupIPPolicyCalculation(false, leaveGroups...)
 	}
 	cacheMutexUnlock()
 
@@ -1229,11 +1250,11 @@ func addImageRelatedContainer2group(cache *groupCache, wlc *workloadCache) {
 	}
 	if cache.group.CfgType != share.Learned {
 		if wlc.workload.ShareNetNS != "" {
-			if pwlc, ok := wlCacheMap[wlc.workload.ShareNetNS]; ok {
+			if pwlc, ok := wlCacheMap.get(wlc.workload.ShareNetNS); ok {
 				cache.members.Add(pwlc.workload.ID)
 				pwlc.groups.Add(cache.group.Name)
 				for child := range pwlc.children.Iter() {
-					if childCache, ok1 := wlCacheMap[child.(string)]; ok1 {
+					if childCache, ok1 := wlCacheMap.get(child.(string)); ok1 {
 						cache.members.Add(childCache.workload.ID)
 						childCache.groups.Add(cache.group.Name)
 					}
@@ -1241,7 +1262,7 @@ func addImageRelatedContainer2group(cache *groupCache, wlc *workloadCache) {
 			}
 		} else {
 			for child := range wlc.children.Iter() {
-				if childCache, ok := wlCacheMap[child.(string)]; ok {
+				if childCache, ok := wlCacheMap.get(child.(string)); ok {
 					cache.members.Add(childCache.workload.ID)
 					childCache.groups.Add(cache.group.Name)
 				}
@@ -1257,6 +1278,7 @@ func groupWorkloadJoin(id string, param interface{}) {
 	var memberUpdated bool
 	bHasGroupProfile := utils.HasGroupProfiles(wlc.learnedGroupName)
 	dptCustomGrpAdds := utils.NewSet()
+	leaveGroups := make([]string, 0)
 
 	cacheMutexLock()
 
@@ -1265,6 +1287,7 @@ func groupWorkloadJoin(id string, param interface{}) {
 		if cache, ok := groupCacheMap[wlc.svcChanged]; ok {
 			wlc.groups.Remove(wlc.svcChanged)
 			cache.members.Remove(wl.ID)
+			leaveGroups = append(leaveGroups, wlc.svcChanged)
 
 			log.WithFields(log.Fields{"group": cache.group.Name}).Debug("Leave learned group")
 			if cache.members.Cardinality() == 0 && cacher.GetUnusedGroupAging() != 0 {
@@ -1285,7 +1308,7 @@ func groupWorkloadJoin(id string, param interface{}) {
 	if cache, ok := groupCacheMap[wlc.learnedGroupName]; !ok || isDummyGroupCache(cache) {
 		if isLeader() {
 			if bHasGroupProfile {
-				createLearnedGroup(wlc, getNewServicePolicyMode(), getNewServiceProfileBaseline(), false, "", access.NewAdminAccessControl())
+				createLearnedGroup(wlc, getDomainNewServicePolicyMode(wlc.workload.Domain), getNewServiceProfileBaseline(), false, "", access.NewAdminAccessControl())
 				if localDev.Host.Platform == share.PlatformKubernetes {
 					updateK8sPodEvent(wlc.learnedGroupName, wlc.podName, wlc.workload.Domain)
 				}
@@ -1325,7 +1348,7 @@ func groupWorkloadJoin(id string, param interface{}) {
 	}
 
 	if memberUpdated {
-		scheduleIPPolicyCalculation(true)
+		scheduleGroupIPPolicyCalculation(true, append(leaveGroups, wlc.groups.ToStringSlice()...)...)
 		scheduleDlpRuleCalculation(true)
 	}
 
@@ -1372,7 +1395,7 @@ func svcipGroupJoin(svcipcache *groupCache) {
 func refreshGroupMember(cache *groupCache) {
 	// Remove group from it's members' group list
 	for m := range cache.members.Iter() {
-		if wlc, ok := wlCacheMap[m.(string)]; ok {
+		if wlc, ok := wlCacheMap.get(m.(string)); ok {
 			wlc.groups.Remove(cache.group.Name)
 		}
 	}
@@ -1418,9 +1441,9 @@ func refreshGroupMember(cache *groupCache) {
 	bHasCustomGroupProfile := utils.IsCustomProfileGroup(cache.group.Name)
 	dptLearnedGrpAdds := utils.NewSet()
 	// For every workload, re-calculate its membership
-	for _, wlc := range wlCacheMap {
+	wlCacheMap.iter(func(_ string, wlc *workloadCache) bool {
 		if !wlc.workload.Running {
-			continue
+			return true
 		}
 
 		if share.IsGroupMember(cache.group, wlc.workload, getDomainData(wlc.workload.Domain)) {
@@ -1438,7 +1461,8 @@ func refreshGroupMember(cache *groupCache) {
 				dptLearnedGrpAdds.Add(wlc.learnedGroupName)
 			}
 		}
-	}
+		return true
+	})
 
 	if bHasCustomGroupProfile {
 		dispatchHelper.CustomGroupUpdate(cache.group.Name, dptLearnedGrpAdds, isLeader())
@@ -1452,15 +1476,16 @@ func refreshLearnedGroupMembership() {
 	var notGroupedPods []*workloadCache
 
 	cacheMutexRLock()
-	for _, wlc := range wlCacheMap {
+	wlCacheMap.iter(func(_ string, wlc *workloadCache) bool {
 		if wlc.learnedGroupName == "" {
-			continue
+			return true
 		}
 
 		if _, ok := groupCacheMap[wlc.learnedGroupName]; !ok {
 			notGroupedPods = append(notGroupedPods, wlc)
 		}
-	}
+		return true
+	})
 	cacheMutexRUnlock()
 
 	for _, wlc := range notGroupedPods {
@@ -1885,6 +1910,9 @@ func group2Service(gc *groupCache, view string, withCap bool) *api.RESTService {
 			break
 		}
 	}
+	if sv.Routes = getRouteExposure(gc); len(sv.Routes) > 0 {
+		sv.IngressExposure = true
+	}
 
 	sv.Members = make([]*api.RESTWorkloadBrief, 0, gc.members.Cardinality())
 	for m := range gc.members.Iter() {
@@ -2000,9 +2028,9 @@ func domainChange(domain share.CLUSDomain) {
 	dptLearnedGrpAdds := utils.NewSet()
 	for _, cache := range groups {
 		cache.members.Clear() // reset
-		for _, wlc := range wlCacheMap {
+		wlCacheMap.iter(func(_ string, wlc *workloadCache) bool {
 			if !wlc.workload.Running {
-				continue
+				return true
 			}
 
 			if share.IsGroupMember(cache.group, wlc.workload, getDomainData(wlc.workload.Domain)) {
@@ -2012,7 +2040,8 @@ func domainChange(domain share.CLUSDomain) {
 			} else {
 				wlc.groups.Remove(cache.group.Name)
 			}
-		}
+			return true
+		})
 		dispatchHelper.CustomGroupUpdate(cache.group.Name, dptLearnedGrpAdds, isLeader())
 	}
 }
@@ -163,6 +163,88 @@ func TestGroupEqualMatch(t *testing.T) {
 	postTest()
 }
 
+func TestGroupSetMatch(t *testing.T) {
+	preTest()
+
+	tests := []struct {
+		ct     share.CLUSCriteriaEntry
+		labels map[string]string
+		match  bool
+	}{
+		{share.CLUSCriteriaEntry{Key: "tier", Value: "web, api-*", Op: share.CriteriaOpIn}, map[string]string{"tier": "api-v1"}, true},
+		{share.CLUSCriteriaEntry{Key: "tier", Value: "web, api-*", Op: share.CriteriaOpIn}, map[string]string{"tier": "db"}, false},
+		{share.CLUSCriteriaEntry{Key: "tier", Value: "web, api-*", Op: share.CriteriaOpIn}, map[string]string{}, false},
+		{share.CLUSCriteriaEntry{Key: "env", Value: "dev,test", Op: share.CriteriaOpNotIn}, map[string]string{"env": "prod"}, true},
+		{share.CLUSCriteriaEntry{Key: "env", Value: "dev,test", Op: share.CriteriaOpNotIn}, map[string]string{"env": "test"}, false},
+		{share.CLUSCriteriaEntry{Key: "env", Value: "dev,test", Op: share.CriteriaOpNotIn}, map[string]string{}, true},
+		{share.CLUSCriteriaEntry{Key: "canary", Op: share.CriteriaOpExist}, map[string]string{"canary": ""}, true},
+		{share.CLUSCriteriaEntry{Key: "canary", Op: share.CriteriaOpExist}, map[string]string{}, false},
+		{share.CLUSCriteriaEntry{Key: "canary", Op: share.CriteriaOpNotExist}, map[string]string{"canary": ""}, false},
+		{share.CLUSCriteriaEntry{Key: "canary", Op: share.CriteriaOpNotExist}, map[string]string{}, true},
+	}
+	for _, test := range tests {
+		wl := &share.CLUSWorkload{Labels: test.labels}
+		if share.IsWorkloadSelected(wl, []share.CLUSCriteriaEntry{test.ct}, nil) != test.match {
+			t.Errorf("Unexpected match result of %+v: labels=%v, expect=%v", test.ct, test.labels, test.match)
+		}
+	}
+
+	// in of the image
+	cts := []share.CLUSCriteriaEntry{
+		share.CLUSCriteriaEntry{Key: "image", Value: "redis,mysql:*", Op: share.CriteriaOpIn},
+		share.CLUSCriteriaEntry{Key: "domain", Value: "billing", Op: share.CriteriaOpEqual},
+	}
+	wlc := workloadCache{
+		workload: &share.CLUSWorkload{Image: "mysql:8.0", Domain: "billing"},
+	}
+	if share.IsWorkloadSelected(wlc.workload, cts, nil) == false {
+		t.Errorf("Workload %+v should be selected by %v.", *wlc.workload, cts)
+	}
+	wlc = workloadCache{
+		workload: &share.CLUSWorkload{Image: "oracle", Domain: "billing"},
+	}
+	if share.IsWorkloadSelected(wlc.workload, cts, nil) == true {
+		t.Errorf("Workload %+v should not be selected by %v.", *wlc.workload, cts)
+	}
+
+	postTest()
+}
+
+func TestGroupOwnerMatch(t *testing.T) {
+	preTest()
+
+	pod := &resource.Pod{
+		Name: "web-5d8f7c9b4-x2k7p", OwnerName: "web-5d8f7c9b4", OwnerType: "ReplicaSet",
+		Labels: map[string]string{"pod-template-hash": "5d8f7c9b4"},
+	}
+	if owner := getPodOwnerRef(pod); owner != "deployment/web" {
+		t.Errorf("Unexpected owner: %s", owner)
+	}
+	pod = &resource.Pod{Name: "db-0", OwnerName: "db", OwnerType: "StatefulSet"}
+	if owner := getPodOwnerRef(pod); owner != "statefulset/db" {
+		t.Errorf("Unexpected owner: %s", owner)
+	}
+
+	cts := []share.CLUSCriteriaEntry{
+		share.CLUSCriteriaEntry{Key: share.CriteriaKeyOwner, Value: "deployment/web*", Op: share.CriteriaOpEqual},
+		share.CLUSCriteriaEntry{Key: share.CriteriaKeyOwner, Value: "statefulset/", Op: share.CriteriaOpPrefix},
+	}
+	for _, owner := range []string{"deployment/web", "deployment/web-api", "statefulset/db"} {
+		wl := &share.CLUSWorkload{Owner: owner}
+		if share.IsWorkloadSelected(wl, cts, nil) == false {
+			t.Errorf("Workload %+v should be selected by %v.", *wl, cts)
+		}
+	}
+	for _, owner := range []string{"deployment/api", "daemonset/web", ""} {
+		wl := &share.CLUSWorkload{Owner: owner}
+		if share.IsWorkloadSelected(wl, cts, nil) == true {
+			t.Errorf("Workload %+v should not be selected by %v.", *wl, cts)
+		}
+	}
+
+	postTest()
+}
+
 func TestEqualMatch(t *testing.T) {
 	poss := [][]string{
 		[]string{"*", "nginx"},
@@ -258,3 +340,29 @@ func TestGroupPolicyMode(t *testing.T) {
 
 	postTest()
 }
+
+func TestDomainNewServicePolicyMode(t *testing.T) {
+	preTest()
+
+	systemConfigCache.NewServicePolicyMode = share.PolicyModeLearn
+	domainCacheMap["prod"] = &domainCache{domain: &share.CLUSDomain{Name: "prod", NewServicePolicyMode: share.PolicyModeEvaluate}}
+	domainCacheMap["dev"] = &domainCache{domain: &share.CLUSDomain{Name: "dev"}}
+	defer func() {
+		delete(domainCacheMap, "prod")
+		delete(domainCacheMap, "dev")
+	}()
+
+	tests := map[string]string{
+		"prod":    share.PolicyModeEvaluate,
+		"dev":     share.PolicyModeLearn,
+		"unknown": share.PolicyModeLearn,
+		"":        share.PolicyModeLearn,
+	}
+	for domain, mode := range tests {
+		if m := getDomainNewServicePolicyMode(domain); m != mode {
+			t.Errorf("Invalid new service policy mode: domain=%v mode=%v expect=%v", domain, m, mode)
+		}
+	}
+
+	postTest()
+}
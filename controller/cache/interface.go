@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"time"
+
 	"github.com/neuvector/neuvector/controller/access"
 	"github.com/neuvector/neuvector/controller/api"
 	"github.com/neuvector/neuvector/controller/common"
@@ -14,6 +16,7 @@ import (
 
 type CacheInterface interface {
 	GetRiskScoreMetrics(acc, accCaller *access.AccessControl) *api.RESTInternalSystemData
+	GetSecurityMetrics(acc *access.AccessControl) *common.SecurityMetrics
 
 	GetAllHosts(acc *access.AccessControl) []*api.RESTHost
 	GetAllHostsRisk(acc *access.AccessControl) []*common.WorkloadRisk
@@ -74,9 +77,12 @@ type CacheInterface interface {
 	GetService(name string, view string, withCap bool, acc *access.AccessControl) (*api.RESTService, error)
 	DlpSensorInGroups(sensor string) bool
 	IsGroupMember(name, id string) bool
+	GetAutoModeTransitions(acc *access.AccessControl) []*api.RESTAutoModeTransition
 	GetConfigKvData(key string) ([]byte, bool)
 
 	GetAllPolicyRules(scope string, acc *access.AccessControl) []*api.RESTPolicyRule
+	GetRuleHits(ruleType string, idleSince int64, acc *access.AccessControl) (int64, []*api.RESTRuleHit)
+	SimulatePolicy(rules []*share.CLUSPolicyRule, groups []string, since int64, acc *access.AccessControl) (int, []*api.RESTPolicySimulationFlow)
 	GetAllPolicyRulesCache(acc *access.AccessControl) []*share.CLUSPolicyRule
 	GetPolicyRuleCount(acc *access.AccessControl) int
 	GetPolicyRule(id uint32, acc *access.AccessControl) (*api.RESTPolicyRule, error)
@@ -90,12 +96,15 @@ type CacheInterface interface {
 	GetResponseRule(policyName string, id uint32, acc *access.AccessControl) (*api.RESTResponseRule, error)
 	GetWorkloadResponseRules(policyName, id string, acc *access.AccessControl) ([]*api.RESTResponseRule, error)
 	GetFedResponseRulesCache() (map[uint32]*share.CLUSResponseRule, []*share.CLUSRuleHead)
+	GetFedQuarantineImagesCache() []*share.CLUSFedQuarantineImage
+	GetPendingFedQuarantineImages() []*share.CLUSFedQuarantineImage
 	ResponseRule2REST(rule *share.CLUSResponseRule) *api.RESTResponseRule
 
 	GetConverEndpoint(name string, acc *access.AccessControl) (*api.RESTConversationEndpoint, error)
 	GetAllConverEndpoints(view string, acc *access.AccessControl) []*api.RESTConversationEndpoint
 	GetAllApplicationConvers(groupFilter, domainFilter string, acc *access.AccessControl) ([]*api.RESTConversationCompact, []*api.RESTConversationEndpoint)
 	GetApplicationConver(src, dst string, srcList, dstList []string, acc *access.AccessControl) (*api.RESTConversationDetail, error)
+	GetFedConverFlows() ([]*share.CLUSFedConverFlow, []string)
 
 	GetIP2WorkloadMap(hostID string) []*api.RESTDebugIP2Workload
 
@@ -120,6 +129,11 @@ type CacheInterface interface {
 	GetIncidentCount(acc *access.AccessControl) int
 	GetAudits(acc *access.AccessControl) []*api.Audit
 	GetAuditCount(acc *access.AccessControl) int
+	GetConfigAudits(acc *access.AccessControl) ([]*api.ConfigAudit, bool)
+	ConfigUpdateNotify() <-chan struct{}
+	SubscribeLogStream(types utils.Set) (uint32, <-chan *LogStreamEntry)
+	GetStoredLogs(logType string, start, end time.Time, limit int) ([]*LogStreamEntry, error)
+	UnsubscribeLogStream(id uint32)
 
 	// License
 	GetCurrentLicense(acc *access.AccessControl) api.RESTLicenseInfo
@@ -154,6 +168,7 @@ type CacheInterface interface {
 	// Compliance
 	GetComplianceProfile(name string, acc *access.AccessControl) (*api.RESTComplianceProfile, map[string][]string, error)
 	GetAllComplianceProfiles(acc *access.AccessControl) []*api.RESTComplianceProfile
+	IsComplianceRiskAccepted(testNum, domain string) bool
 
 	// Vulnerability
 	GetVulnerabilityProfile(name string, acc *access.AccessControl) (*api.RESTVulnerabilityProfile, error)
@@ -184,6 +199,7 @@ type CacheInterface interface {
 	GetFedMembershipRole(acc *access.AccessControl) (string, error)
 	GetFedMember(statusMap map[int]string, acc *access.AccessControl) (*api.RESTFedMembereshipData, error)
 	GetFedLocalRestInfo(acc *access.AccessControl) (share.CLUSRestServerInfo, int8)
+	GetFedProxyConfig(acc *access.AccessControl) *share.CLUSFedProxyConfig
 	GetFedMasterCluster(acc *access.AccessControl) api.RESTFedMasterClusterInfo
 	GetFedLocalJointCluster(acc *access.AccessControl) api.RESTFedJointClusterInfo
 	GetFedJoinedClusterToken(id, mainSessionID string, acc *access.AccessControl) (string, error)
@@ -233,6 +249,9 @@ type CacheInterface interface {
 	GetWafRuleSensorGroupById(id uint32) (string, string, *[]string)
 	GetWafRuleNames() *[]string
 
+	// Threat signature
+	GetThreatSignatures(acc *access.AccessControl) (*api.RESTThreatSignatureData, error)
+
 	// Custom role
 	AuthorizeCustomCheck(name string, acc *access.AccessControl) bool
 	AuthorizeFileMonitorProfile(name string, acc *access.AccessControl) bool
@@ -0,0 +1,192 @@
+package cache
+
+// Compaction of orphaned kv keys and size accounting of the kv store. Both are done by the lead controller in the
+// kv prune cycle. Keys of hosts and groups that no longer exist are removed when they are found orphaned in two
+// consecutive cycles, like the workload keys in pruneWorkloadKV().
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/cluster"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+const kvStoreSizeLimit = int64(1024 * 1024 * 1024)
+const kvStoreSizeHighRatio = 0.8
+const kvStatsPrefixMax = 100
+
+var kvStoreSizeHigh bool
+
+// keys are removed when they are suspected in the last round and still orphaned
+func lookupOrphanKeys(keys []string, nIndex int, curr, skip, suspected, nextSuspected utils.Set) []string {
+	var removed []string
+	for _, key := range keys {
+		id := share.CLUSKeyNthToken(key, nIndex)
+		if id == "" || curr.Contains(id) || skip.Contains(id) {
+			continue
+		}
+		if suspected.Contains(key) {
+			removed = append(removed, key)
+		} else {
+			nextSuspected.Add(key)
+		}
+	}
+	return removed
+}
+
+func compactOrphanKV(suspected utils.Set) int {
+	if !isLeader() {
+		suspected.Clear()
+		return 0
+	}
+
+	hosts := utils.NewSet()
+	cacheMutexRLock()
+	for id := range hostCacheMap {
+		hosts.Add(id)
+	}
+	cacheMutexRUnlock()
+
+	groups := utils.NewSet()
+	keys, _ := cluster.GetStoreKeys(share.CLUSConfigGroupStore)
+	for _, key := range keys {
+		groups.Add(share.CLUSGroupKey2Name(key))
+	}
+	if hosts.Cardinality() == 0 || groups.Cardinality() == 0 {
+		// play safe when the cache or the kv is not ready
+		return 0
+	}
+
+	nextSuspected := utils.NewSet()
+
+	// (1) host profiles: node/<host id>/
+	keys, _ = cluster.GetKeys(share.CLUSNodeStore, "/")
+	removed := lookupOrphanKeys(keys, 1, hosts, utils.NewSet(share.ProfileCommonGroup), suspected, nextSuspected)
+
+	// (2) group profiles on all nodes: node/common/profiles/<profile>/<group>
+	for _, profile := range []string{share.ProfileGroup, share.ProfileProcess, share.ProfileFileMonitor, share.ProfileFileAccess, share.ProfileScript} {
+		keys, _ = cluster.GetStoreKeys(fmt.Sprintf("%s%s/", share.CLUSNodeCommonProfileStore, profile))
+		removed = append(removed, lookupOrphanKeys(keys, 4, groups, utils.NewSet(), suspected, nextSuspected)...)
+	}
+
+	// (3) group configs: object/config/<endpoint>/<group>
+	for _, store := range []string{share.CLUSConfigProcessProfileStore, share.CLUSConfigFileMonitorStore, share.CLUSConfigFileAccessRuleStore} {
+		keys, _ = cluster.GetStoreKeys(store)
+		removed = append(removed, lookupOrphanKeys(keys, 3, groups, utils.NewSet(), suspected, nextSuspected)...)
+	}
+
+	suspected.Clear()
+	for key := range nextSuspected.Iter() {
+		suspected.Add(key)
+	}
+
+	if len(removed) > 0 {
+		txn := cluster.Transact()
+		for _, key := range removed {
+			if strings.HasSuffix(key, "/") {
+				txn.DeleteTree(key)
+			} else {
+				txn.Delete(key)
+			}
+		}
+		if ok, err := txn.Apply(); err != nil || !ok {
+			log.WithFields(log.Fields{"ok": ok, "error": err}).Error("Failed to remove orphaned keys")
+			removed = nil
+		} else {
+			log.WithFields(log.Fields{"removed": removed}).Info("Removed orphaned keys")
+		}
+		txn.Close()
+	}
+	return len(removed)
+}
+
+// ids in keys are not part of the prefix, like node/<host id>/ and bench/<workload id>/
+func kvStatsPrefix(key string) string {
+	tokens := strings.Split(key, "/")
+	depth := 3
+	switch tokens[0] {
+	case strings.TrimSuffix(share.CLUSNodeStore, "/"), strings.TrimSuffix(share.CLUSBenchStore, "/"):
+		depth = 1
+	}
+	if depth > len(tokens)-1 {
+		depth = len(tokens) - 1
+	}
+	if depth == 0 {
+		return key
+	}
+	return strings.Join(tokens[:depth], "/") + "/"
+}
+
+func collectKvStoreStats(pairs map[string]int) *share.CLUSKvStoreStats {
+	stats := &share.CLUSKvStoreStats{SizeLimit: kvStoreSizeLimit}
+	prefixes := make(map[string]*share.CLUSKvPrefixStats)
+	for key, size := range pairs {
+		prefix := kvStatsPrefix(key)
+		ps, ok := prefixes[prefix]
+		if !ok {
+			ps = &share.CLUSKvPrefixStats{Prefix: prefix}
+			prefixes[prefix] = ps
+		}
+		ps.Keys++
+		ps.Size += int64(len(key) + size)
+		stats.Keys++
+		stats.Size += int64(len(key) + size)
+	}
+
+	stats.Prefixes = make([]*share.CLUSKvPrefixStats, 0, len(prefixes))
+	for _, ps := range prefixes {
+		stats.Prefixes = append(stats.Prefixes, ps)
+	}
+	sort.Slice(stats.Prefixes, func(i, j int) bool {
+		if stats.Prefixes[i].Size != stats.Prefixes[j].Size {
+			return stats.Prefixes[i].Size > stats.Prefixes[j].Size
+		}
+		return stats.Prefixes[i].Prefix < stats.Prefixes[j].Prefix
+	})
+	if len(stats.Prefixes) > kvStatsPrefixMax {
+		stats.Prefixes = stats.Prefixes[:kvStatsPrefixMax]
+	}
+	return stats
+}
+
+func updateKvStoreStats(compacted int) {
+	if !isLeader() {
+		return
+	}
+
+	// list store by store to avoid reading the whole kv at once
+	pairs := make(map[string]int)
+	stores, _ := cluster.GetKeys("", "/")
+	for _, store := range stores {
+		kvs, err := cluster.List(store)
+		if err != nil {
+			continue
+		}
+		for _, kv := range kvs {
+			pairs[kv.Key] = len(kv.Value)
+		}
+	}
+
+	stats := collectKvStoreStats(pairs)
+	stats.UpdatedAt = time.Now().UTC()
+	stats.LastCompactAt = stats.UpdatedAt
+	stats.CompactedKeys = compacted
+	clusHelper.PutKvStoreStats(stats)
+
+	high := float64(stats.Size) >= float64(stats.SizeLimit)*kvStoreSizeHighRatio
+	if high && !kvStoreSizeHigh {
+		msg := fmt.Sprintf("Size of kv store is %d bytes, approaching the limit of %d bytes", stats.Size, stats.SizeLimit)
+		if len(stats.Prefixes) > 0 {
+			msg = fmt.Sprintf("%s. The largest prefix is %s with %d keys", msg, stats.Prefixes[0].Prefix, stats.Prefixes[0].Keys)
+		}
+		log.WithFields(log.Fields{"size": stats.Size, "limit": stats.SizeLimit}).Warn("kv store size is high")
+		CacheEvent(share.CLUSEvKvStoreSizeHigh, msg)
+	}
+	kvStoreSizeHigh = high
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func TestLookupOrphanKeys(t *testing.T) {
+	keys := []string{"node/h1/", "node/h2/", "node/common/"}
+	hosts := utils.NewSet("h1")
+	skip := utils.NewSet("common")
+
+	suspected := utils.NewSet()
+	next := utils.NewSet()
+	if removed := lookupOrphanKeys(keys, 1, hosts, skip, suspected, next); len(removed) != 0 {
+		t.Errorf("Keys should not be removed in the first round: %v", removed)
+	}
+	if next.Cardinality() != 1 || !next.Contains("node/h2/") {
+		t.Errorf("Unexpected suspected keys: %v", next)
+	}
+
+	// h2 is back in the 2nd round
+	suspected, next = next, utils.NewSet()
+	hosts.Add("h2")
+	if removed := lookupOrphanKeys(keys, 1, hosts, skip, suspected, next); len(removed) != 0 || next.Cardinality() != 0 {
+		t.Errorf("Key of existing host should not be removed: %v, %v", removed, next)
+	}
+
+	// h2 is missing in 2 rounds
+	hosts.Remove("h2")
+	suspected, next = utils.NewSet(), utils.NewSet()
+	lookupOrphanKeys(keys, 1, hosts, skip, suspected, next)
+	suspected, next = next, utils.NewSet()
+	if removed := lookupOrphanKeys(keys, 1, hosts, skip, suspected, next); !reflect.DeepEqual(removed, []string{"node/h2/"}) {
+		t.Errorf("Orphaned key should be removed: %v", removed)
+	}
+}
+
+func TestCollectKvStoreStats(t *testing.T) {
+	pairs := map[string]int{
+		"object/config/group/g1":          100,
+		"object/config/group/g2":          100,
+		"object/config/system":            50,
+		"node/h1/profiles/process/g1":     10,
+		"node/common/profiles/process/g1": 10,
+		"bench/wl1/report/container":      1000,
+		"scan/data/report/workload/wl1":   500,
+		"recalculate":                     1,
+	}
+	stats := collectKvStoreStats(pairs)
+	if stats.Keys != len(pairs) {
+		t.Errorf("Unexpected number of keys: %d", stats.Keys)
+	}
+
+	var total int64
+	prefixes := make(map[string]int)
+	for _, ps := range stats.Prefixes {
+		prefixes[ps.Prefix] = ps.Keys
+		total += ps.Size
+	}
+	if total != stats.Size {
+		t.Errorf("Unexpected size: %d, %d", total, stats.Size)
+	}
+	expect := map[string]int{
+		"object/config/group/": 2,
+		"object/config/":       1,
+		"node/":                2,
+		"bench/":               1,
+		"scan/data/report/":    1,
+		"recalculate":          1,
+	}
+	if !reflect.DeepEqual(prefixes, expect) {
+		t.Errorf("Unexpected prefixes: %v", prefixes)
+	}
+	if !sort.SliceIsSorted(stats.Prefixes, func(i, j int) bool { return stats.Prefixes[i].Size > stats.Prefixes[j].Size }) {
+		t.Errorf("Prefixes should be sorted by size")
+	}
+}
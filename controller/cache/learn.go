@@ -4,6 +4,7 @@ package cache
 import "C"
 
 import (
+	"context"
 	"encoding/json"
 	"net"
 	"reflect"
@@ -20,6 +21,7 @@ import (
 	"github.com/neuvector/neuvector/controller/graph"
 	"github.com/neuvector/neuvector/share"
 	"github.com/neuvector/neuvector/share/cluster"
+	"github.com/neuvector/neuvector/share/otel"
 	"github.com/neuvector/neuvector/share/utils"
 )
 
@@ -150,7 +152,7 @@ func qualifyPortLink(conn *share.CLUSConnection, port string, attr *polAttr) boo
 		// learn it if the dst is workload but we cannot find its app
 		cacheMutexRLock()
 		defer cacheMutexRUnlock()
-		if cache, ok := wlCacheMap[conn.ServerWL]; ok {
+		if cache, ok := wlCacheMap.get(conn.ServerWL); ok {
 			if _, found := cache.workload.Apps[port]; !found {
 				log.WithFields(log.Fields{
 					"serverWL": conn.ServerWL, "port": port,
@@ -303,8 +305,9 @@ func addConnectToGraph(conn *share.CLUSConnection, ca, sa *nodeAttr, stip *serve
 		}
 	}
 
-	gkey := graphKey{ipproto: uint8(conn.IPProto), port: stip.wlPort, application: conn.Application,
-		cip: utils.IPv42Int(conn.ClientIP), sip: utils.IPv42Int(conn.ServerIP)}
+	gkey := graphKey{ipproto: uint8(conn.IPProto), port: stip.wlPort, application: conn.Application}
+	gkey.cip, gkey.cip6 = graphKeyIPValue(conn.ClientIP)
+	gkey.sip, gkey.sip6 = graphKeyIPValue(conn.ServerIP)
 
 	// This is used to create conversations
 	var attr *graphAttr
@@ -367,6 +370,9 @@ func addConnectToGraph(conn *share.CLUSConnection, ca, sa *nodeAttr, stip *serve
 			ge.toSidecar = 0
 		}
 		ge.fqdn = conn.FQDN
+		if conn.MeshID != "" {
+			ge.meshID = conn.MeshID
+		}
 	} else {
 		ge := &graphEntry{
 			bytes:    conn.Bytes,
@@ -374,6 +380,7 @@ func addConnectToGraph(conn *share.CLUSConnection, ca, sa *nodeAttr, stip *serve
 			server:   stip.appServer,
 			last:     conn.LastSeenAt,
 			fqdn:     conn.FQDN,
+			meshID:   conn.MeshID,
 		}
 		if conn.Xff {
 			ge.xff = 1
@@ -1212,6 +1219,7 @@ func startPolicyThread() {
 	vulProfUpdateTimer.Stop()
 
 	syncCheckTicker := time.Tick(time.Second * time.Duration(120))
+	conversationPruneTicker := time.Tick(conversationPrunePeriod)
 
 	// In case there are already learned rule in cluster, fetch the rules.
 	// This can happen when controller restarts
@@ -1254,15 +1262,27 @@ func startPolicyThread() {
 					policyCalculated = false
 					continue
 				}
+				ctx, span := otel.StartSpan(context.Background(), "policy.calculate", otel.SpanKindInternal)
+				span.SetAttribute("delay.seconds", time.Since(firstPolicyCalculateAt).Seconds())
 				cacheMutexRLock()
-				newIPRules := calculateIPPolicyFromCache()
+				newIPRules, force := calculateIPPolicyFromCache()
 				cacheMutexRUnlock()
 				policyCalculated = false
-				putPolicyIPRulesToClusterScale(newIPRules)
+				putPolicyIPRulesToClusterScale(ctx, newIPRules, force)
+				span.SetAttribute("rules", len(newIPRules))
+				span.End()
+				otel.RecordDuration("neuvector.policy.calculation.duration", span.Duration())
 			case <-vulProfUpdateTimer.C:
 				scanVulProfUpdate()
 			case <-syncCheckTicker:
 				syncCheck(isLeader())
+			case <-conversationPruneTicker:
+				before := uint32(time.Now().Add(-conversationRetention).Unix())
+				graphMutexLock()
+				if pruned := pruneConversationByAge(before); pruned > 0 {
+					log.WithFields(log.Fields{"pruned": pruned}).Info("Removed old conversations")
+				}
+				graphMutexUnlock()
 			}
 		}
 	}()
@@ -1293,6 +1313,8 @@ type GraphSyncEntry struct {
 	Application  uint32
 	CIP          uint32
 	SIP          uint32
+	CIP6         net.IP `json:",omitempty"`
+	SIP6         net.IP `json:",omitempty"`
 	MappedPort   uint16
 	ThreatID     uint32
 	DlpID        uint32
@@ -1309,10 +1331,30 @@ type GraphSyncEntry struct {
 	Xff          uint8
 	ToSidecar    uint8
 	FQDN         string
+	MeshID       string `json:",omitempty"`
+}
+
+// ipv4 address is kept as integer, ipv6 address is kept in the 16-byte array
+func graphKeyIPValue(ip net.IP) (uint32, [net.IPv6len]byte) {
+	var ip6 [net.IPv6len]byte
+	if ip == nil {
+		return 0, ip6
+	} else if utils.IsIPv4(ip) {
+		return utils.IPv42Int(ip), ip6
+	}
+	copy(ip6[:], ip.To16())
+	return 0, ip6
+}
+
+func graphKeyIP(ip uint32, ip6 [net.IPv6len]byte) net.IP {
+	if ip6 == [net.IPv6len]byte{} {
+		return utils.Int2IPv4(ip)
+	}
+	return net.IP(ip6[:])
 }
 
 func graphEntry2Sync(k *graphKey, e *graphEntry) *GraphSyncEntry {
-	return &GraphSyncEntry{Ipproto: k.ipproto,
+	se := &GraphSyncEntry{Ipproto: k.ipproto,
 		Port: k.port, Application: k.application,
 		CIP: k.cip, SIP: k.sip,
 		MappedPort: e.mappedPort, Bytes: e.bytes,
@@ -1320,19 +1362,34 @@ func graphEntry2Sync(k *graphKey, e *graphEntry) *GraphSyncEntry {
 		Severity: e.severity, DlpSeverity: e.dlpSeverity, WafSeverity: e.wafSeverity,
 		ThreatID: e.threatID, DlpID: e.dlpID, WafID: e.wafID, PolicyAction: e.policyAction,
 		PolicyID: e.policyID, Last: e.last, Xff: e.xff, ToSidecar: e.toSidecar, FQDN: e.fqdn,
+		MeshID: e.meshID,
+	}
+	if k.cip6 != [net.IPv6len]byte{} {
+		se.CIP6 = graphKeyIP(k.cip, k.cip6)
 	}
+	if k.sip6 != [net.IPv6len]byte{} {
+		se.SIP6 = graphKeyIP(k.sip, k.sip6)
+	}
+	return se
 }
 
 func graphSync2Entry(e *GraphSyncEntry) (*graphKey, *graphEntry) {
 	gkey := graphKey{ipproto: e.Ipproto, port: e.Port,
 		application: e.Application, cip: e.CIP, sip: e.SIP,
 	}
+	if e.CIP6 != nil {
+		gkey.cip, gkey.cip6 = graphKeyIPValue(e.CIP6)
+	}
+	if e.SIP6 != nil {
+		gkey.sip, gkey.sip6 = graphKeyIPValue(e.SIP6)
+	}
 
 	gEntry := graphEntry{mappedPort: e.MappedPort,
 		bytes: e.Bytes, sessions: e.Sessions,
 		server: e.Server, severity: e.Severity, dlpSeverity: e.DlpSeverity, wafSeverity: e.WafSeverity,
 		threatID: e.ThreatID, dlpID: e.DlpID, wafID: e.WafID, policyAction: e.PolicyAction,
 		policyID: e.PolicyID, last: e.Last, xff: e.Xff, toSidecar: e.ToSidecar, fqdn: e.FQDN,
+		meshID: e.MeshID,
 	}
 	return &gkey, &gEntry
 }
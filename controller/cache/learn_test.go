@@ -58,7 +58,7 @@ func newCase(pm *caseParam) {
 	clusHelper = &mockCluster
 
 	for i, wl := range pm.wls {
-		wlCacheMap[wl.ID] = &workloadCache{workload: wl, learnedGroupName: pm.wlgs[i]}
+		wlCacheMap.set(wl.ID, &workloadCache{workload: wl, learnedGroupName: pm.wlgs[i]})
 	}
 	for _, g := range pm.groups {
 		groupCacheMap[g.Name] = &groupCache{group: g}
@@ -119,3 +119,30 @@ func TestPortAppReplace(t *testing.T) {
 
 	postTest()
 }
+
+func TestGraphKeyIPv6(t *testing.T) {
+	for _, str := range []string{"10.1.2.3", "fd00:10:244::5"} {
+		ip := net.ParseIP(str)
+		k := graphKey{ipproto: syscall.IPPROTO_TCP, port: 80}
+		k.cip, k.cip6 = graphKeyIPValue(ip)
+		k.sip, k.sip6 = graphKeyIPValue(ip)
+		if c := graphKeyIP(k.cip, k.cip6).String(); c != str {
+			t.Errorf("Unexpected client ip: expect=%v actual=%v\n", str, c)
+		}
+
+		sk, _ := graphSync2Entry(graphEntry2Sync(&k, &graphEntry{}))
+		if *sk != k {
+			t.Errorf("Graph key changed after sync: expect=%+v actual=%+v\n", k, *sk)
+		}
+	}
+}
+
+func TestGraphEntryMeshIDSync(t *testing.T) {
+	k := graphKey{ipproto: syscall.IPPROTO_TCP, port: 80}
+	e := graphEntry{fqdn: "www.example.com", meshID: "spiffe://cluster.local/ns/default/sa/sleep"}
+
+	_, se := graphSync2Entry(graphEntry2Sync(&k, &e))
+	if *se != e {
+		t.Errorf("Graph entry changed after sync: expect=%+v actual=%+v\n", e, *se)
+	}
+}
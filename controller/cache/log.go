@@ -4,6 +4,7 @@ package cache
 import "C"
 
 import (
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -68,6 +69,65 @@ var incidentCache []*api.Incident = make([]*api.Incident, logCacheSize)
 var curIncidentIndex int = 0
 var auditCache []*api.Audit = make([]*api.Audit, logCacheSize)
 var curAuditIndex int = 0
+var cfgAuditCache []*api.ConfigAudit = make([]*api.ConfigAudit, logCacheSize)
+var curCfgAuditIndex int = 0
+
+// The regulatory controls of every threat and violation, shared by the logs
+var threatRegulatoryControls []string = share.RegulatoryControls(share.CLUSThreatRegulatoryAreas)
+var violationRegulatoryControls []string = share.RegulatoryControls(share.CLUSViolationRegulatoryAreas)
+
+const logStreamBufferSize int = 256
+
+// log pushed to the subscribers of log stream
+type LogStreamEntry struct {
+	Type string
+	Log  interface{}
+}
+
+type logStreamSubscriber struct {
+	types utils.Set
+	ch    chan *LogStreamEntry
+}
+
+var logStreamMutex sync.RWMutex
+var logStreamSubs map[uint32]*logStreamSubscriber = make(map[uint32]*logStreamSubscriber)
+var logStreamSubID uint32
+
+// Subscribe to the logs of the specified types. Logs are not authorized here, the subscriber needs to check the access
+func (m CacheMethod) SubscribeLogStream(types utils.Set) (uint32, <-chan *LogStreamEntry) {
+	sub := &logStreamSubscriber{
+		types: types,
+		ch:    make(chan *LogStreamEntry, logStreamBufferSize),
+	}
+
+	logStreamMutex.Lock()
+	defer logStreamMutex.Unlock()
+	logStreamSubID++
+	logStreamSubs[logStreamSubID] = sub
+	return logStreamSubID, sub.ch
+}
+
+func (m CacheMethod) UnsubscribeLogStream(id uint32) {
+	logStreamMutex.Lock()
+	defer logStreamMutex.Unlock()
+	delete(logStreamSubs, id)
+}
+
+func streamLog(logType string, rlog interface{}) {
+	logStreamMutex.RLock()
+	defer logStreamMutex.RUnlock()
+	for id, sub := range logStreamSubs {
+		if !sub.types.Contains(logType) {
+			continue
+		}
+		select {
+		case sub.ch <- &LogStreamEntry{Type: logType, Log: rlog}:
+		default:
+			// don't block the log processing for slow subscribers
+			log.WithFields(log.Fields{"id": id, "type": logType}).Debug("log stream buffer full")
+		}
+	}
+}
 
 // This is currently used to record policy voilation logs. It's not really a traffic log,
 // but an aggregated record.
@@ -293,6 +353,90 @@ func (m CacheMethod) GetAuditCount(acc *access.AccessControl) int {
 	}
 }
 
+// Config audits are only visible to users who can see all events. Also return whether the hash chain is intact
+func (m CacheMethod) GetConfigAudits(acc *access.AccessControl) ([]*api.ConfigAudit, bool) {
+	logs := make([]*api.ConfigAudit, 0)
+	if !acc.HasGlobalPermissions(share.PERM_EVENTS, 0) {
+		return logs, true
+	}
+
+	verified := true
+	for i := 0; i < curCfgAuditIndex; i++ {
+		a := cfgAuditCache[i]
+		if a.Hash != configAuditHash(a) || (i > 0 && a.PrevHash != cfgAuditCache[i-1].Hash) {
+			log.WithFields(log.Fields{"seq": a.Seq}).Error("Config audit chain broken")
+			verified = false
+		}
+		logs = append(logs, a)
+	}
+
+	// The newest record must also match the chain head in the cluster, so the chain can't be rebuilt in memory
+	if curCfgAuditIndex > 0 {
+		last := cfgAuditCache[curCfgAuditIndex-1]
+		if head := clusHelper.GetConfigAuditHead(); head != nil && head.Seq == last.Seq && head.Hash != last.Hash {
+			log.WithFields(log.Fields{"seq": last.Seq}).Error("Config audit chain head mismatch")
+			verified = false
+		}
+	}
+
+	return logs, verified
+}
+
+func configAuditHash(a *api.ConfigAudit) string {
+	c := *a
+	c.Hash = ""
+	value, _ := json.Marshal(&c)
+	return fmt.Sprintf("%x", sha256.Sum256(value))
+}
+
+// Every controller receives REST write events from the cluster in the same order, so the chains are the same.
+// The lead controller keeps the chain head in the cluster, where the chain continues after all controllers restart.
+func recordConfigAudit(ev *share.CLUSEventLog) {
+	a := &api.ConfigAudit{
+		Seq:               1,
+		ReportedTimeStamp: ev.ReportedAt.Unix(),
+		ReportedAt:        api.RESTTimeString(ev.ReportedAt),
+		ControllerID:      ev.ControllerID,
+		ControllerName:    ev.ControllerName,
+		User:              ev.User,
+		UserRoles:         ev.UserRoles,
+		UserAddr:          ev.UserAddr,
+		UserSession:       ev.UserSession,
+		RESTMethod:        ev.RESTMethod,
+		RESTRequest:       ev.RESTRequest,
+		RESTBody:          ev.RESTBody,
+		RESTBefore:        ev.RESTBefore,
+		RESTAfter:         ev.RESTAfter,
+		Msg:               ev.Msg,
+	}
+	if curCfgAuditIndex > 0 {
+		last := cfgAuditCache[curCfgAuditIndex-1]
+		a.Seq = last.Seq + 1
+		a.PrevHash = last.Hash
+	} else if head := clusHelper.GetConfigAuditHead(); head != nil {
+		a.Seq = head.Seq + 1
+		a.PrevHash = head.Hash
+	}
+	a.Hash = configAuditHash(a)
+
+	if curCfgAuditIndex == logCacheSize {
+		_, cfgAuditCache = cfgAuditCache[0], cfgAuditCache[1:]
+		cfgAuditCache = append(cfgAuditCache, a)
+	} else {
+		cfgAuditCache[curCfgAuditIndex] = a
+		curCfgAuditIndex++
+	}
+
+	if isLeader() {
+		if err := clusHelper.PutConfigAuditHead(&share.CLUSConfigAuditHead{Seq: a.Seq, Hash: a.Hash}); err != nil {
+			log.WithFields(log.Fields{"seq": a.Seq, "error": err}).Error("Failed to save config audit chain head")
+		}
+		exportLog(api.CategoryConfigAudit, a.ReportedTimeStamp, a)
+		smtpNotify(api.CategoryConfigAudit, a.ReportedTimeStamp, a)
+		go sendSyslog(a, api.LogLevelINFO, api.CategoryConfigAudit, "config-audit")
+	}
+}
+
 func recordViolation(rlog *api.Violation) {
 	log.WithFields(log.Fields{"client": rlog.ClientName, "server": rlog.ServerName}).Debug("")
 
@@ -388,7 +532,7 @@ func webhookActivity(act *actionDesc, arg interface{}) {
 		title := fmt.Sprintf("%s", rlog.Name)
 		for _, w := range act.webhooks {
 			if whc := getWebhookCache(rlog.ResponseRuleID, w); whc != nil {
-				whc.conn.Notify(rlog, whc.target, rlog.Level, api.CategoryEvent, rlog.ClusterName, title)
+				whc.conn.Notify(rlog, whc.target, &act.whOpts, rlog.Level, api.CategoryEvent, rlog.ClusterName, title)
 			}
 		}
 	}
@@ -401,7 +545,7 @@ func webhookEvent(act *actionDesc, arg interface{}) {
 		title := fmt.Sprintf("%s", rlog.Name)
 		for _, w := range act.webhooks {
 			if whc := getWebhookCache(rlog.ResponseRuleID, w); whc != nil {
-				whc.conn.Notify(rlog, whc.target, rlog.Level, api.CategoryEvent, rlog.ClusterName, title)
+				whc.conn.Notify(rlog, whc.target, &act.whOpts, rlog.Level, api.CategoryEvent, rlog.ClusterName, title)
 			}
 		}
 	}
@@ -414,7 +558,7 @@ func webhookViolation(act *actionDesc, arg interface{}) {
 		title := fmt.Sprintf("%s -> %s", rlog.ClientName, rlog.ServerName)
 		for _, w := range act.webhooks {
 			if whc := getWebhookCache(rlog.ResponseRuleID, w); whc != nil {
-				whc.conn.Notify(rlog, whc.target, rlog.Level, api.CategoryViolation, rlog.ClusterName, title)
+				whc.conn.Notify(rlog, whc.target, &act.whOpts, rlog.Level, api.CategoryViolation, rlog.ClusterName, title)
 			}
 		}
 	}
@@ -434,7 +578,7 @@ func webhookThreat(act *actionDesc, arg interface{}) {
 		rlog.CapLen, rlog.Packet = 0, ""
 		for _, w := range act.webhooks {
 			if whc := getWebhookCache(rlog.ResponseRuleID, w); whc != nil {
-				whc.conn.Notify(rlog, whc.target, rlog.Level, api.CategoryThreat, rlog.ClusterName, title)
+				whc.conn.Notify(rlog, whc.target, &act.whOpts, rlog.Level, api.CategoryThreat, rlog.ClusterName, title)
 			}
 		}
 		rlog.CapLen, rlog.Packet = len, pkt
@@ -448,7 +592,7 @@ func webhookIncident(act *actionDesc, arg interface{}) {
 		title := fmt.Sprintf("%s at %s", rlog.Name, rlog.WorkloadName)
 		for _, w := range act.webhooks {
 			if whc := getWebhookCache(rlog.ResponseRuleID, w); whc != nil {
-				whc.conn.Notify(rlog, whc.target, rlog.Level, api.CategoryIncident, rlog.ClusterName, title)
+				whc.conn.Notify(rlog, whc.target, &act.whOpts, rlog.Level, api.CategoryIncident, rlog.ClusterName, title)
 			}
 		}
 	}
@@ -476,7 +620,7 @@ func webhookAudit(act *actionDesc, arg interface{}) {
 		}
 		for _, w := range act.webhooks {
 			if whc := getWebhookCache(rlog.ResponseRuleID, w); whc != nil {
-				whc.conn.Notify(rlog, whc.target, rlog.Level, api.CategoryAudit, rlog.ClusterName, title)
+				whc.conn.Notify(rlog, whc.target, &act.whOpts, rlog.Level, api.CategoryAudit, rlog.ClusterName, title)
 			}
 		}
 	}
@@ -507,7 +651,11 @@ func sendSyslog(elog interface{}, level, cat, header string) {
 func logActivity(arg interface{}) {
 	rlog := arg.(*api.Event)
 	recordActivity(rlog)
+	streamLog(api.LogTypeActivity, rlog)
+	storeLog(api.LogTypeActivity, rlog.ReportedTimeStamp, rlog)
 	if isLeader() {
+		exportLog(api.CategoryEvent, rlog.ReportedTimeStamp, rlog)
+		smtpNotify(api.CategoryEvent, rlog.ReportedTimeStamp, rlog)
 		go sendSyslog(rlog, rlog.Level, api.CategoryEvent, "activity")
 	}
 }
@@ -515,40 +663,71 @@ func logActivity(arg interface{}) {
 func logEvent(arg interface{}) {
 	rlog := arg.(*api.Event)
 	recordEvent(rlog)
+	streamLog(api.LogTypeEvent, rlog)
+	storeLog(api.LogTypeEvent, rlog.ReportedTimeStamp, rlog)
 	if isLeader() {
+		exportLog(api.CategoryEvent, rlog.ReportedTimeStamp, rlog)
+		smtpNotify(api.CategoryEvent, rlog.ReportedTimeStamp, rlog)
 		go sendSyslog(rlog, rlog.Level, api.CategoryEvent, "event")
 	}
 }
 
 func logViolation(arg interface{}) {
 	rlog := arg.(*api.Violation)
+	atomic.AddUint64(&violationCount, 1)
 	recordViolation(rlog)
-	if isLeader() {
-		go sendSyslog(rlog, rlog.Level, api.CategoryViolation, "violation")
+	streamLog(api.LogTypeViolation, rlog)
+	storeLog(api.LogTypeViolation, rlog.ReportedTimeStamp, rlog)
+	if isLeader() && !correlateLog(api.CategoryViolation, rlog) {
+		outputViolation(rlog)
 	}
 }
 
+// Send the log to the exporters, the email recipients and the syslog server
+func outputViolation(rlog *api.Violation) {
+	exportLog(api.CategoryViolation, rlog.ReportedTimeStamp, rlog)
+	smtpNotify(api.CategoryViolation, rlog.ReportedTimeStamp, rlog)
+	go sendSyslog(rlog, rlog.Level, api.CategoryViolation, "violation")
+}
+
 func logThreat(arg interface{}) {
 	rlog := arg.(*api.Threat)
+	atomic.AddUint64(&threatCount, 1)
 	recordThreat(rlog)
-	if isLeader() {
-		go func() {
-			len, pkt := rlog.CapLen, rlog.Packet
-			rlog.CapLen, rlog.Packet = 0, ""
-			sendSyslog(rlog, rlog.Level, api.CategoryThreat, "threat")
-			rlog.CapLen, rlog.Packet = len, pkt
-		}()
+	streamLog(api.LogTypeThreat, rlog)
+	storeLog(api.LogTypeThreat, rlog.ReportedTimeStamp, rlog)
+	if isLeader() && !correlateLog(api.CategoryThreat, rlog) {
+		outputThreat(rlog)
 	}
 }
 
+func outputThreat(rlog *api.Threat) {
+	exportThreat(rlog)
+	go func() {
+		len, pkt := rlog.CapLen, rlog.Packet
+		rlog.CapLen, rlog.Packet = 0, ""
+		sendSyslog(rlog, rlog.Level, api.CategoryThreat, "threat")
+		rlog.CapLen, rlog.Packet = len, pkt
+	}()
+}
+
 func logIncident(arg interface{}) {
 	rlog := arg.(*api.Incident)
+	atomic.AddUint64(&incidentCount, 1)
 	recordIncident(rlog)
-	if isLeader() {
-		go sendSyslog(rlog, rlog.Level, api.CategoryIncident, "incident")
+	streamLog(api.LogTypeIncident, rlog)
+	storeLog(api.LogTypeIncident, rlog.ReportedTimeStamp, rlog)
+	if isLeader() && !correlateLog(api.CategoryIncident, rlog) {
+		outputIncident(rlog)
 	}
 }
 
+func outputIncident(rlog *api.Incident) {
+	exportLog(api.CategoryIncident, rlog.ReportedTimeStamp, rlog)
+	smtpNotify(api.CategoryIncident, rlog.ReportedTimeStamp, rlog)
+	go sendSyslog(rlog, rlog.Level, api.CategoryIncident, "incident")
+}
+
 func fillVulAudit(l *api.Audit, cve string) {
 	v, ok := l.Vuls[cve]
 	if ok {
@@ -572,8 +751,13 @@ func fillVulAudit(l *api.Audit, cve string) {
 
 func logAudit(arg interface{}) {
 	rlog := arg.(*api.Audit)
+	countAuditMetrics(rlog)
 	recordAudit(rlog)
+	streamLog(api.LogTypeAudit, rlog)
+	storeLog(api.LogTypeAudit, rlog.ReportedTimeStamp, rlog)
 	if isLeader() {
+		exportLog(api.CategoryAudit, rlog.ReportedTimeStamp, rlog)
+		smtpNotify(api.CategoryAudit, rlog.ReportedTimeStamp, rlog)
 		if systemConfigCache.SingleCVEPerSyslog &&
 			(rlog.Name == api.EventNameContainerScanReport ||
 				rlog.Name == api.EventNameHostScanReport ||
@@ -679,6 +863,8 @@ func eventLogUpdate(nType cluster.ClusterNotifyType, key string, value []byte, m
 		for _, ev := range evs {
 			if ev.Event == share.CLUSEvAgentStop {
 				agentStopEventHandler(&ev)
+			} else if ev.Event == share.CLUSEvRESTWrite {
+				recordConfigAudit(&ev)
 			}
 
 			var rlog *api.Event
@@ -846,6 +1032,9 @@ func incidentLogUpdate(nType cluster.ClusterNotifyType, key string, value []byte
 		for _, incd := range incds {
 			var id string
 			var port uint16
+			if incd.RuleID != "" {
+				countProcessRuleHit(incd.RuleID, uint64(incd.Count), incd.ReportedAt.Unix())
+			}
 			if incd.LocalIP != nil && incd.RemoteIP != nil {
 				var lc *logConnect
 				if incd.ConnIngress {
@@ -950,6 +1139,9 @@ func auditLogUpdate(nType cluster.ClusterNotifyType, key string, value []byte, m
 			if rlog := auditLog2API(&audit); rlog != nil {
 				switch audit.ID {
 				case share.CLUSAuditAdmCtrlK8sReqAllowed, share.CLUSAuditAdmCtrlK8sReqViolation, share.CLUSAuditAdmCtrlK8sReqDenied:
+					if id, err := strconv.ParseUint(audit.Props[nvsysadmission.AuditLogPropRuleID], 10, 32); err == nil && id != 0 {
+						countAdmissionRuleHit(uint32(id), uint64(audit.Count), audit.ReportedAt.Unix())
+					}
 					admCtrlUpdate(share.EventAdmCtrl, rlog)
 				case share.CLUSAuditAwsLambdaScanWarning, share.CLUSAuditAwsLambdaScanNormal:
 					serverlessUpdate(share.EventServerless, rlog)
@@ -1161,6 +1353,20 @@ func syncEventTx() *syncDataMsg {
 	return &msg
 }
 
+func syncConfigAuditTx() *syncDataMsg {
+	msg := syncDataMsg{CatgName: syncCatgConfigAudit}
+
+	// Use event sync lock as config audits are recorded from events
+	syncLock(syncCatgEventIdx)
+	if curCfgAuditIndex > 0 {
+		audits := cfgAuditCache[0:curCfgAuditIndex]
+		msg.Data, _ = json.Marshal(audits)
+	}
+	msg.ModifyIdx = getModifyIdx(syncCatgEventIdx)
+	syncUnlock(syncCatgEventIdx)
+	return &msg
+}
+
 func syncThreatTx() *syncDataMsg {
 	msg := syncDataMsg{CatgName: syncCatgThreat}
 	syncLock(syncCatgThreatIdx)
@@ -1258,6 +1464,36 @@ func syncEventRx(msg *syncDataMsg) int {
 	return syncRxErrorNone
 }
 
+func syncConfigAuditRx(msg *syncDataMsg) int {
+	// Use event sync lock as config audits are recorded from events
+	syncLock(syncCatgEventIdx)
+	if validateModifyIdx(syncCatgEventIdx, msg.ModifyIdx) == false {
+		syncUnlock(syncCatgEventIdx)
+		// Introduce a delay before retry
+		time.Sleep(time.Second)
+		return syncRxErrorRetry
+	}
+
+	if msg.Data != nil {
+		var audits []*api.ConfigAudit
+		if err := json.Unmarshal(msg.Data, &audits); err != nil {
+			log.WithFields(log.Fields{"size": len(msg.Data)}).Error("unmarshal error")
+			syncUnlock(syncCatgEventIdx)
+			return syncRxErrorFailed
+		} else {
+			curCfgAuditIndex = len(audits)
+			for i, a := range audits {
+				cfgAuditCache[i] = a
+			}
+		}
+	} else {
+		curCfgAuditIndex = 0
+	}
+	setModifyIdx(syncCatgEventIdx, msg.ModifyIdx)
+	syncUnlock(syncCatgEventIdx)
+	return syncRxErrorNone
+}
+
 func syncThreatRx(msg *syncDataMsg) int {
 	syncLock(syncCatgThreatIdx)
 	if validateModifyIdx(syncCatgThreatIdx, msg.ModifyIdx) == false {
@@ -1429,9 +1665,16 @@ func threatLog2API(thrt *share.CLUSThreatLog, id string, port uint16) *api.Threa
 		if grpname != nil {
 			rlog.Group = getWorkloadDlpGrp(thrt.WorkloadID, grpname)
 		}
+	} else if isThreatSigID(thrt.ThreatID) {
+		rlog.Name, _, _ = getThreatSigNameSeverity(thrt.ThreatID)
 	} else {
 		rlog.Name = common.ThreatName(thrt.ThreatID)
+		if info, ok := common.LogThreatMap[thrt.ThreatID]; ok {
+			rlog.Mitre = info.Techniques
+			rlog.MitreTactics = share.MitreTactics(info.Techniques)
+		}
 	}
+	rlog.Controls = threatRegulatoryControls
 	rlog.ThreatID = thrt.ThreatID
 	rlog.Count = thrt.Count
 	rlog.ClusterName = systemConfigCache.ClusterName
@@ -1527,6 +1770,12 @@ func threatLog2API(thrt *share.CLUSThreatLog, id string, port uint16) *api.Threa
 	rlog.CapLen = thrt.CapLen
 
 	rlog.Severity, rlog.Level = common.SeverityString(thrt.Severity)
+	if isThreatSigID(thrt.ThreatID) {
+		// dp derives the severity from the action, use the one defined by the signature
+		if _, severity, level := getThreatSigNameSeverity(thrt.ThreatID); severity != "" {
+			rlog.Severity, rlog.Level = severity, level
+		}
+	}
 	if thrt.Tap {
 		rlog.Action = api.ThreatActionMonitor
 	} else {
@@ -1534,7 +1783,7 @@ func threatLog2API(thrt *share.CLUSThreatLog, id string, port uint16) *api.Threa
 		case C.DPI_ACTION_ALLOW, C.DPI_ACTION_BYPASS:
 			if isDlpThreatID(thrt.ThreatID) {
 				rlog.Action = api.ThreatActionMonitor
-			} else if isWafThreatID(thrt.ThreatID) {
+			} else if isWafThreatID(thrt.ThreatID) || isThreatSigID(thrt.ThreatID) {
 				rlog.Action = api.ThreatActionMonitor
 			} else {
 				rlog.Action = api.ThreatActionAllow
@@ -1605,6 +1854,9 @@ func incidentLog2API(incd *share.CLUSIncidentLog, id string, port uint16) *api.I
 	rlog.Count = incd.Count
 	rlog.AggregationFrom = incd.StartAt.Unix()
 	rlog.RuleID = incd.RuleID
+	rlog.Mitre = share.MergeMitreTechniques(share.CLUSIncidentMitreMap[incd.ID], incd.Mitre)
+	rlog.MitreTactics = share.MitreTactics(rlog.Mitre)
+	rlog.Controls = share.RegulatoryControls(share.CLUSIncidentRegulatoryMap[incd.ID])
 
 	if rlog.Action == share.PolicyActionDeny {
 		rlog.Level = api.LogLevelCRIT
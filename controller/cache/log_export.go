@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+)
+
+type logExporterCache struct {
+	cfg      share.CLUSLogExporter
+	exporter *common.LogExporter
+}
+
+var logExporterMutex sync.RWMutex
+var logExporterMap map[string]*logExporterCache = make(map[string]*logExporterCache) // key is exporter name
+
+// Exporters are kept in every controller, only the lead exports the logs. An exporter is restarted only if its config changes.
+func configLogExporters(cfgs []share.CLUSLogExporter) {
+	logExporterMutex.Lock()
+	defer logExporterMutex.Unlock()
+
+	newMap := make(map[string]*logExporterCache, len(cfgs))
+	for i := range cfgs {
+		cfg := &cfgs[i]
+		if !cfg.Enable {
+			continue
+		}
+		if c, ok := logExporterMap[cfg.Name]; ok && reflect.DeepEqual(&c.cfg, cfg) {
+			newMap[cfg.Name] = c
+			delete(logExporterMap, cfg.Name)
+			continue
+		}
+		exporter, err := common.NewLogExporter(cfg)
+		if err != nil {
+			log.WithFields(log.Fields{"name": cfg.Name, "error": err}).Error("Failed to create log exporter")
+			continue
+		}
+		log.WithFields(log.Fields{"name": cfg.Name, "type": cfg.Type}).Info("Log exporter applied")
+		newMap[cfg.Name] = &logExporterCache{cfg: *cfg, exporter: exporter}
+	}
+
+	// The removed exporters send the queued logs in the background
+	for _, c := range logExporterMap {
+		go c.exporter.Close()
+	}
+	logExporterMap = newMap
+}
+
+func exportLog(cat string, ts int64, rlog interface{}) {
+	logExporterMutex.RLock()
+	defer logExporterMutex.RUnlock()
+
+	var rec *common.LogExportRecord
+	for _, c := range logExporterMap {
+		if !c.exporter.Accept(cat) {
+			continue
+		}
+		if rec == nil {
+			data, err := json.Marshal(rlog)
+			if err != nil {
+				log.WithFields(log.Fields{"category": cat, "error": err}).Error("Failed to marshal log")
+				return
+			}
+			rec = &common.LogExportRecord{Category: cat, Time: time.Unix(ts, 0), Data: data}
+			rec.Cluster, rec.Workload = logExportKeys(rlog)
+		}
+		c.exporter.Export(rec)
+	}
+}
+
+// The cluster and the workload of the log, they can be used as the partition key of the exported logs
+func logExportKeys(rlog interface{}) (string, string) {
+	switch l := rlog.(type) {
+	case *api.Event:
+		return l.ClusterName, l.WorkloadID
+	case *api.Threat:
+		if l.Target == api.TargetClient {
+			return l.ClusterName, l.ClientWL
+		}
+		return l.ClusterName, l.ServerWL
+	case *api.Violation:
+		return l.ClusterName, l.ClientWL
+	case *api.Incident:
+		return l.ClusterName, l.WorkloadID
+	case *api.Audit:
+		return l.ClusterName, l.WorkloadID
+	}
+	return systemConfigCache.ClusterName, ""
+}
+
+// The packet is not exported or emailed with the threat
+func exportThreat(rlog *api.Threat) {
+	t := *rlog
+	t.CapLen, t.Packet = 0, ""
+	exportLog(api.CategoryThreat, t.ReportedTimeStamp, &t)
+	smtpNotify(api.CategoryThreat, t.ReportedTimeStamp, &t)
+}
@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/controller/logstore"
+)
+
+const logStoreQueueSize int = 4096
+const logStorePrunePeriod = time.Duration(time.Hour)
+const logStoreDefaultRetention = time.Duration(time.Hour * 24 * 30)
+const logStoreExportMax int = 100000
+
+type logStoreEntry struct {
+	logType string
+	at      time.Time
+	data    []byte
+}
+
+var logStore logstore.Store
+var logStoreQueue chan *logStoreEntry
+var logStoreDropped uint64
+
+var logStoreTypes []string = []string{
+	api.LogTypeActivity, api.LogTypeEvent, api.LogTypeThreat, api.LogTypeIncident, api.LogTypeViolation, api.LogTypeAudit,
+}
+
+func logStoreRetention(logType string) time.Duration {
+	if d, ok := cctx.LogRetention[logType]; ok {
+		return d
+	}
+	if d, ok := cctx.LogRetention["default"]; ok {
+		return d
+	}
+	return logStoreDefaultRetention
+}
+
+func decodeStoredLog(logType string, data []byte) (interface{}, error) {
+	var rlog interface{}
+	switch logType {
+	case api.LogTypeActivity, api.LogTypeEvent:
+		rlog = &api.Event{}
+	case api.LogTypeThreat:
+		rlog = &api.Threat{}
+	case api.LogTypeIncident:
+		rlog = &api.Incident{}
+	case api.LogTypeViolation:
+		rlog = &api.Violation{}
+	case api.LogTypeAudit:
+		rlog = &api.Audit{}
+	default:
+		return nil, common.ErrUnsupported
+	}
+	err := json.Unmarshal(data, rlog)
+	return rlog, err
+}
+
+// Fill the log caches with the stored logs
+func restoreStoredLogs(store logstore.Store) {
+	for _, logType := range logStoreTypes {
+		logs, err := store.List(logType, time.Now().Add(-logStoreRetention(logType)), time.Time{}, logCacheSize)
+		if err != nil {
+			log.WithFields(log.Fields{"type": logType, "error": err}).Error("Failed to read stored logs")
+			continue
+		}
+		for _, data := range logs {
+			rlog, err := decodeStoredLog(logType, data)
+			if err != nil {
+				continue
+			}
+			switch logType {
+			case api.LogTypeActivity:
+				recordActivity(rlog.(*api.Event))
+			case api.LogTypeEvent:
+				recordEvent(rlog.(*api.Event))
+			case api.LogTypeThreat:
+				recordThreat(rlog.(*api.Threat))
+			case api.LogTypeIncident:
+				recordIncident(rlog.(*api.Incident))
+			case api.LogTypeViolation:
+				recordViolation(rlog.(*api.Violation))
+			case api.LogTypeAudit:
+				recordAudit(rlog.(*api.Audit))
+			}
+		}
+		log.WithFields(log.Fields{"type": logType, "count": len(logs)}).Info("Restored logs")
+	}
+}
+
+func initLogStore(store logstore.Store) {
+	restoreStoredLogs(store)
+
+	logStoreQueue = make(chan *logStoreEntry, logStoreQueueSize)
+	logStore = store
+	go logStoreWriter(store)
+}
+
+func logStoreWriter(store logstore.Store) {
+	pruneTicker := time.NewTicker(logStorePrunePeriod)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case e := <-logStoreQueue:
+			if err := store.Put(e.logType, e.at, e.data); err != nil {
+				log.WithFields(log.Fields{"type": e.logType, "error": err}).Error("Failed to store log")
+			}
+		case <-pruneTicker.C:
+			for _, logType := range logStoreTypes {
+				before := time.Now().Add(-logStoreRetention(logType))
+				if pruned, err := store.Prune(logType, before); err != nil {
+					log.WithFields(log.Fields{"type": logType, "error": err}).Error("Failed to prune logs")
+				} else if pruned > 0 {
+					log.WithFields(log.Fields{"type": logType, "segments": pruned}).Info("Pruned logs")
+				}
+			}
+		}
+	}
+}
+
+// The log is marshaled here because it can be modified later, such as the threat packet is removed for syslog.
+// Logs are written in the background; they are dropped if the store cannot catch up.
+func storeLog(logType string, ts int64, rlog interface{}) {
+	if logStore == nil {
+		return
+	}
+
+	data, err := json.Marshal(rlog)
+	if err != nil {
+		log.WithFields(log.Fields{"type": logType, "error": err}).Error("Failed to marshal log")
+		return
+	}
+
+	select {
+	case logStoreQueue <- &logStoreEntry{logType: logType, at: time.Unix(ts, 0), data: data}:
+	default:
+		if dropped := atomic.AddUint64(&logStoreDropped, 1); dropped%1000 == 1 {
+			log.WithFields(log.Fields{"dropped": dropped}).Error("Log store queue is full")
+		}
+	}
+}
+
+func (m CacheMethod) GetStoredLogs(logType string, start, end time.Time, limit int) ([]*LogStreamEntry, error) {
+	if logStore == nil {
+		return nil, common.ErrUnsupported
+	}
+	if limit <= 0 || limit > logStoreExportMax {
+		limit = logStoreExportMax
+	}
+
+	logs, err := logStore.List(logType, start, end, limit)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*LogStreamEntry, 0, len(logs))
+	for _, data := range logs {
+		if rlog, err := decodeStoredLog(logType, data); err == nil {
+			entries = append(entries, &LogStreamEntry{Type: logType, Log: rlog})
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,213 @@
+package cache
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/controller/logstore"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func TestLogStream(t *testing.T) {
+	var m CacheMethod
+
+	id1, ch1 := m.SubscribeLogStream(utils.NewSet(api.LogTypeThreat, api.LogTypeIncident))
+	id2, ch2 := m.SubscribeLogStream(utils.NewSet(api.LogTypeEvent))
+
+	threat := &api.Threat{ID: "t1"}
+	streamLog(api.LogTypeThreat, threat)
+	streamLog(api.LogTypeViolation, &api.Violation{ID: "v1"})
+	streamLog(api.LogTypeEvent, &api.Event{})
+
+	if len(ch1) != 1 || len(ch2) != 1 {
+		t.Fatalf("Unexpected streamed log count: %d, %d", len(ch1), len(ch2))
+	}
+	if entry := <-ch1; entry.Type != api.LogTypeThreat || entry.Log != threat {
+		t.Errorf("Unexpected streamed log: %+v", entry)
+	}
+
+	// logs are dropped instead of blocking when the subscriber is slow
+	for i := 0; i < logStreamBufferSize+1; i++ {
+		streamLog(api.LogTypeEvent, &api.Event{})
+	}
+	if len(ch2) != logStreamBufferSize {
+		t.Errorf("Unexpected streamed log count: %d", len(ch2))
+	}
+
+	m.UnsubscribeLogStream(id1)
+	m.UnsubscribeLogStream(id2)
+	streamLog(api.LogTypeThreat, threat)
+	if len(ch1) != 0 || len(logStreamSubs) != 0 {
+		t.Errorf("Log streamed after unsubscribe: %d, %d", len(ch1), len(logStreamSubs))
+	}
+}
+
+func TestConfigAuditChain(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+
+	var m CacheMethod
+	for _, req := range []string{"/v1/group", "/v1/policy/rule", "/v1/user"} {
+		recordConfigAudit(&share.CLUSEventLog{
+			Event: share.CLUSEvRESTWrite, User: "admin", RESTMethod: "POST", RESTRequest: req, ReportedAt: time.Now().UTC(),
+		})
+	}
+
+	audits, verified := m.GetConfigAudits(access.NewReaderAccessControl())
+	if len(audits) != 3 || !verified {
+		t.Fatalf("Unexpected config audits: count=%d, verified=%v", len(audits), verified)
+	}
+	for i, a := range audits {
+		if a.Seq != uint64(i+1) {
+			t.Errorf("Unexpected seq: %d, expect=%d", a.Seq, i+1)
+		}
+		if i > 0 && a.PrevHash != audits[i-1].Hash {
+			t.Errorf("Config audit %d is not chained", a.Seq)
+		}
+	}
+
+	// tampered record breaks the chain
+	audits[1].User = "someone"
+	if _, verified = m.GetConfigAudits(access.NewReaderAccessControl()); verified {
+		t.Errorf("Tampered config audit is not detected")
+	}
+
+	// not visible to namespace users
+	r, _ := http.NewRequest(http.MethodGet, "https://10.1.1.1/v1/log/audit-config", nil)
+	acc := access.NewAccessControl(r, access.AccessOPRead, map[string]string{"ns1": api.UserRoleAdmin})
+	if nsAudits, _ := m.GetConfigAudits(acc); len(nsAudits) != 0 {
+		t.Errorf("Config audits visible to namespace user: %d", len(nsAudits))
+	}
+
+	// the chain head in the cluster doesn't match the newest record
+	audits[1].User = "admin"
+	last := *audits[2]
+	clusHelper.PutConfigAuditHead(&share.CLUSConfigAuditHead{Seq: last.Seq, Hash: "rebuilt"})
+	if _, verified = m.GetConfigAudits(access.NewReaderAccessControl()); verified {
+		t.Errorf("Config audit chain head mismatch is not detected")
+	}
+
+	// a restarted controller resumes the chain from the head in the cluster
+	curCfgAuditIndex = 0
+	clusHelper.PutConfigAuditHead(&share.CLUSConfigAuditHead{Seq: last.Seq, Hash: last.Hash})
+	recordConfigAudit(&share.CLUSEventLog{
+		Event: share.CLUSEvRESTWrite, User: "admin", RESTMethod: "PATCH", RESTRequest: "/v1/user/u1", ReportedAt: time.Now().UTC(),
+		RESTBefore: `{"user":{"role":"reader"}}`, RESTAfter: `{"user":{"role":"admin"}}`,
+	})
+	audits, verified = m.GetConfigAudits(access.NewReaderAccessControl())
+	if len(audits) != 1 || !verified {
+		t.Fatalf("Unexpected config audits: count=%d, verified=%v", len(audits), verified)
+	}
+	if audits[0].Seq != last.Seq+1 || audits[0].PrevHash != last.Hash {
+		t.Errorf("Config audit chain is not resumed: seq=%d, prev=%s", audits[0].Seq, audits[0].PrevHash)
+	}
+	if audits[0].RESTBefore == "" || audits[0].RESTAfter == "" {
+		t.Errorf("Changed object is not recorded")
+	}
+
+	curCfgAuditIndex = 0
+	postTest()
+}
+
+func TestStoredLogs(t *testing.T) {
+	preTest()
+
+	dir, _ := ioutil.TempDir("", "logstore")
+	defer os.RemoveAll(dir)
+	store, _ := logstore.NewFileStore(dir)
+	defer store.Close()
+
+	var m CacheMethod
+	if _, err := m.GetStoredLogs(api.LogTypeEvent, time.Time{}, time.Time{}, 0); err != common.ErrUnsupported {
+		t.Errorf("Logs should not be read without store: %v", err)
+	}
+
+	now := time.Now()
+	for i, name := range []string{"e1", "e2", "e3"} {
+		data, _ := json.Marshal(&api.Event{LogCommon: api.LogCommon{Name: name, ReportedTimeStamp: now.Unix()}})
+		store.Put(api.LogTypeEvent, now.Add(time.Second*time.Duration(i)), data)
+	}
+	data, _ := json.Marshal(&api.Threat{ID: "t1"})
+	store.Put(api.LogTypeThreat, now, data)
+
+	// Logs are restored to the cache
+	curEventIndex, curThrtIndex = 0, 0
+	thrtMap = make(map[string]*api.Threat)
+	restoreStoredLogs(store)
+	if curEventIndex != 3 || eventCache[2].Name != "e3" {
+		t.Errorf("Unexpected restored events: %d", curEventIndex)
+	}
+	if _, ok := thrtMap["t1"]; !ok || curThrtIndex != 1 {
+		t.Errorf("Threat is not restored: %d", curThrtIndex)
+	}
+
+	logStore = store
+	defer func() { logStore = nil }()
+	entries, err := m.GetStoredLogs(api.LogTypeEvent, time.Time{}, time.Time{}, 2)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Unexpected stored logs: count=%d error=%v", len(entries), err)
+	}
+	if ev, ok := entries[0].Log.(*api.Event); !ok || ev.Name != "e2" || entries[0].Type != api.LogTypeEvent {
+		t.Errorf("Unexpected stored log: %+v", entries[0])
+	}
+
+	curEventIndex, curThrtIndex = 0, 0
+	thrtMap = make(map[string]*api.Threat)
+	postTest()
+}
+
+func TestThreatLogIPv6(t *testing.T) {
+	preTest()
+
+	wl1 := share.CLUSWorkload{ID: "wl1", Name: "c1", Domain: "ns1"}
+	wl2 := share.CLUSWorkload{ID: "wl2", Name: "c2", Domain: "ns2"}
+	wlCacheMap.set(wl1.ID, &workloadCache{workload: &wl1, podName: "pod1"})
+	wlCacheMap.set(wl2.ID, &workloadCache{workload: &wl2, podName: "pod2"})
+	ipWLMap["fd00:10:244::5"] = &workloadDigest{wlID: wl2.ID, alive: true, managed: true}
+	defer func() {
+		wlCacheMap.remove(wl1.ID)
+		wlCacheMap.remove(wl2.ID)
+		delete(ipWLMap, "fd00:10:244::5")
+	}()
+
+	// wl2 attacks wl1, and an external ipv6 address attacks wl1
+	for _, src := range []string{"fd00:10:244::5", "2001:db8::1"} {
+		thrt := share.CLUSThreatLog{
+			ID: "t1", ThreatID: 1001, WorkloadID: wl1.ID, HostID: "h1", EtherType: syscall.ETH_P_IPV6,
+			IPProto: syscall.IPPROTO_TCP, SrcIP: net.ParseIP(src), DstIP: net.ParseIP("fd00:10:244::9"),
+			SrcPort: 40000, DstPort: 80, PktIngress: true, SessIngress: true,
+		}
+		lc := &logConnect{
+			hostID: thrt.HostID, ingress: thrt.PktIngress, ipproto: thrt.IPProto,
+			srcIP: thrt.SrcIP, dstIP: thrt.DstIP, srcPort: thrt.SrcPort, dstPort: thrt.DstPort,
+		}
+		id, _ := preProcessLogConnect(lc)
+		rlog := threatLog2API(&thrt, id, thrt.DstPort)
+		if rlog.ClientIP != src || rlog.ServerIP != "fd00:10:244::9" {
+			t.Errorf("Unexpected threat addresses: client=%s server=%s", rlog.ClientIP, rlog.ServerIP)
+		}
+		expect := wl2.ID
+		if src != "fd00:10:244::5" {
+			expect = api.LearnedExternal
+		}
+		if rlog.ClientWL != expect || rlog.ServerWL != wl1.ID {
+			t.Errorf("Unexpected threat endpoints: client=%s server=%s, expect client=%s", rlog.ClientWL, rlog.ServerWL, expect)
+		}
+	}
+
+	postTest()
+}
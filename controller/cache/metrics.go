@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"sync/atomic"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+)
+
+// Counted on every controller as all controllers receive the logs
+var violationCount, threatCount, incidentCount, admDenialCount uint64
+
+func countAuditMetrics(rlog *api.Audit) {
+	if rlog.Name == api.EventNameAdmCtrlK8sReqDenied {
+		atomic.AddUint64(&admDenialCount, 1)
+	}
+}
+
+func (m CacheMethod) GetSecurityMetrics(acc *access.AccessControl) *common.SecurityMetrics {
+	metrics := &common.SecurityMetrics{
+		WorkloadVuls: make(map[string]map[string]int),
+		HostVuls:     make(map[string]int),
+		PolicyModes:  make(map[string]int),
+		Violations:   atomic.LoadUint64(&violationCount),
+		Threats:      atomic.LoadUint64(&threatCount),
+		Incidents:    atomic.LoadUint64(&incidentCount),
+		AdmDenials:   atomic.LoadUint64(&admDenialCount),
+	}
+
+	cacheMutexRLock()
+	defer cacheMutexRUnlock()
+
+	wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
+		wl := cache.workload
+		if !acc.Authorize(wl, nil) || common.OEMIgnoreWorkload(wl) || !wl.Running {
+			return true
+		}
+
+		if wl.ShareNetNS == "" {
+			// pod
+			if gc, ok := groupCacheMap[cache.learnedGroupName]; ok && gc.group.PolicyMode != "" {
+				metrics.PolicyModes[gc.group.PolicyMode]++
+			}
+		} else if cache.scanBrief != nil {
+			// Only counts app containers, not pod
+			vuls, ok := metrics.WorkloadVuls[wl.Domain]
+			if !ok {
+				vuls = make(map[string]int)
+				metrics.WorkloadVuls[wl.Domain] = vuls
+			}
+			vuls[share.VulnSeverityHigh] += cache.scanBrief.HighVuls
+			vuls[share.VulnSeverityMedium] += cache.scanBrief.MedVuls
+		}
+		return true
+	})
+
+	for _, cache := range hostCacheMap {
+		if !acc.Authorize(cache.host, nil) || cache.scanBrief == nil {
+			continue
+		}
+		metrics.HostVuls[share.VulnSeverityHigh] += cache.scanBrief.HighVuls
+		metrics.HostVuls[share.VulnSeverityMedium] += cache.scanBrief.MedVuls
+	}
+
+	return metrics
+}
@@ -63,6 +63,8 @@ func host2REST(cache *hostCache, k8sCache *k8sHostCache) *api.RESTHost {
 	if k8sCache != nil && k8sCache.id == host.ID {
 		h.Labels = k8sCache.labels
 		h.Annotations = k8sCache.annotations
+	} else {
+		h.Labels = host.Labels
 	}
 
 	h.PolicyMode, h.ProfileMode = getHostPolicyMode(cache)
@@ -109,6 +111,7 @@ func agent2REST(cache *agentCache) *api.RESTAgent {
 		DisconnAt:   api.RESTTimeString(cache.disconnAt),
 		State:       cache.state,
 		NvProtect:   !config.DisableNvProtectMode,
+		Restricted:  agent.Restricted,
 	}
 
 	if c.Name == c.ID {
@@ -211,7 +214,7 @@ func workload2EndpointREST(cache *workloadCache, withChildren bool) *api.RESTCon
 
 	if withChildren {
 		for child := range cache.children.Iter() {
-			if childCache, ok := wlCacheMap[child.(string)]; ok {
+			if childCache, ok := wlCacheMap.get(child.(string)); ok {
 				r.Children = append(r.Children, workload2BriefREST(childCache))
 			}
 		}
@@ -809,6 +812,30 @@ func agentUpdate(nType cluster.ClusterNotifyType, key string, value []byte) {
 	}
 }
 
+var selfCtrlUpdateMutex sync.Mutex
+var selfCtrlUpdateCh chan struct{}
+
+// Return a channel that is closed when the next update of the self controller key is received. The updates
+// of the object store are handled in the order of the modify index, so the cache has all the changes written
+// before the key when it's closed.
+func SelfControllerUpdateNotify() <-chan struct{} {
+	selfCtrlUpdateMutex.Lock()
+	defer selfCtrlUpdateMutex.Unlock()
+	if selfCtrlUpdateCh == nil {
+		selfCtrlUpdateCh = make(chan struct{})
+	}
+	return selfCtrlUpdateCh
+}
+
+func selfControllerUpdateNotify() {
+	selfCtrlUpdateMutex.Lock()
+	defer selfCtrlUpdateMutex.Unlock()
+	if selfCtrlUpdateCh != nil {
+		close(selfCtrlUpdateCh)
+		selfCtrlUpdateCh = nil
+	}
+}
+
 func controllerUpdate(nType cluster.ClusterNotifyType, key string, value []byte) {
 	log.WithFields(log.Fields{"type": cluster.ClusterNotifyName[nType], "key": key}).Debug("")
 
@@ -858,6 +885,9 @@ func controllerUpdate(nType cluster.ClusterNotifyType, key string, value []byte)
 		if newCtrl {
 			evhdls.Trigger(EV_CONTROLLER_ADD, ctrl.ID, cc)
 		}
+		if ctrl.ID == localDev.Ctrler.ID {
+			selfControllerUpdateNotify()
+		}
 	case cluster.ClusterNotifyDelete:
 		id := share.CLUSDeviceKey2ID(key)
 
@@ -1190,6 +1220,46 @@ func setServiceAccount(node, wlID, wlName string, wlCache *workloadCache) {
 	}
 }
 
+// The pods of a deployment are owned by its replicaset, which is named as the deployment name plus
+// the pod-template-hash, so the deployment is taken as the owner.
+func getPodOwnerRef(pod *resource.Pod) string {
+	if pod.OwnerType == "" || pod.OwnerName == "" {
+		return ""
+	}
+
+	kind, name := pod.OwnerType, pod.OwnerName
+	if kind == "ReplicaSet" {
+		if hash, ok := pod.Labels["pod-template-hash"]; ok && strings.HasSuffix(name, "-"+hash) {
+			kind, name = "Deployment", strings.TrimSuffix(name, "-"+hash)
+		}
+	}
+	return fmt.Sprintf("%s/%s", strings.ToLower(kind), name)
+}
+
+// The pod events are usually received before the workload is reported. If not, the pod is queried.
+// The cacheMutex is locked by callers
+func setWorkloadOwner(wl *share.CLUSWorkload) {
+	if localDev.Host.Platform != share.PlatformKubernetes {
+		return
+	}
+	podname, ok := wl.Labels[container.KubeKeyPodName]
+	if !ok || wl.Domain == "" {
+		return
+	}
+
+	key := wl.Domain + "/" + podname
+	if owner, ok := podOwnerMap[key]; ok {
+		wl.Owner = owner
+	} else if wl.Running {
+		if obj, err := global.ORCH.GetResource(resource.RscTypePod, wl.Domain, podname); err == nil {
+			if owner = getPodOwnerRef(obj.(*resource.Pod)); owner != "" {
+				podOwnerMap[key] = owner
+				wl.Owner = owner
+			}
+		}
+	}
+}
+
 func appendProbeCmds(cmds []string) (bool, string, string, string) {
 	if len(cmds) > 0 {
 		exe := filepath.Base(cmds[0])
@@ -1337,6 +1407,7 @@ func workloadUpdate(nType cluster.ClusterNotifyType, key string, value []byte) {
 	case cluster.ClusterNotifyAdd, cluster.ClusterNotifyModify:
 		var wl share.CLUSWorkload
 		json.Unmarshal(value, &wl)
+		internWorkload(&wl)
 
 		// Check if it's NeuVector containers first
 		if wl.PlatformRole == container.PlatformContainerNeuVector {
@@ -1371,7 +1442,8 @@ func workloadUpdate(nType cluster.ClusterNotifyType, key string, value []byte) {
 		var workloadAgentChange bool
 
 		cacheMutexLock()
-		if wlCache, ok = wlCacheMap[wl.ID]; ok && !isDummyWorkloadCache(wlCache) {
+		setWorkloadOwner(&wl)
+		if wlCache, ok = wlCacheMap.get(wl.ID); ok && !isDummyWorkloadCache(wlCache) {
 			oldRunning := wlCache.workload.Running
 			oldQuar := wlCache.workload.Quarantine
 			oldSvc := wlCache.workload.Service
@@ -1381,7 +1453,7 @@ func workloadUpdate(nType cluster.ClusterNotifyType, key string, value []byte) {
 				log.WithFields(log.Fields{
 					"workload": container.ShortContainerId(wl.ID),
 				}).Debug("intf/ports changed")
-				defer scheduleIPPolicyCalculation(true)
+				defer scheduleGroupIPPolicyCalculation(true, wlCache.groups.ToStringSlice()...)
 			}
 
 			if wlCache.workload.AgentID != wl.AgentID {
@@ -1430,7 +1502,7 @@ func workloadUpdate(nType cluster.ClusterNotifyType, key string, value []byte) {
 			if wlCache == nil {
 				// New workload
 				wlCache = initWorkloadCache()
-				wlCacheMap[wl.ID] = wlCache
+				wlCacheMap.set(wl.ID, wlCache)
 			}
 
 			setServiceAccount(wl.HostName, wl.ID, wl.Name, wlCache)
@@ -1450,11 +1522,11 @@ func workloadUpdate(nType cluster.ClusterNotifyType, key string, value []byte) {
 
 			// Update parent's children list. Create a dummy parent if not exist
 			if wl.ShareNetNS != "" {
-				if parent, ok := wlCacheMap[wl.ShareNetNS]; !ok {
+				if parent, ok := wlCacheMap.get(wl.ShareNetNS); !ok {
 					wlParent := initWorkloadCache()
 					wlParent.serviceAccount = wlCache.serviceAccount
-					wlCacheMap[wl.ShareNetNS] = wlParent
-					wlCacheMap[wl.ShareNetNS].children.Add(wl.ID)
+					wlCacheMap.set(wl.ShareNetNS, wlParent)
+					wlParent.children.Add(wl.ID)
 				} else {
 					parent.serviceAccount = wlCache.serviceAccount
 					parent.children.Add(wl.ID)
@@ -1534,12 +1606,12 @@ func workloadUpdate(nType cluster.ClusterNotifyType, key string, value []byte) {
 
 		cacheMutexLock()
 
-		if wlCache, ok = wlCacheMap[id]; ok {
-			delete(wlCacheMap, id)
+		if wlCache, ok = wlCacheMap.get(id); ok {
+			wlCacheMap.remove(id)
 
 			// Update parent's children list.
 			if wlCache.workload.ShareNetNS != "" {
-				if parent, ok := wlCacheMap[wlCache.workload.ShareNetNS]; ok {
+				if parent, ok := wlCacheMap.get(wlCache.workload.ShareNetNS); ok {
 					parent.children.Remove(id)
 				}
 			}
@@ -1613,8 +1685,26 @@ func ObjectUpdateHandler(nType cluster.ClusterNotifyType, key string, value []by
 	}
 }
 
+var cfgUpdateMutex sync.Mutex
+var cfgUpdateCh chan struct{} = make(chan struct{})
+
+// Return a channel that is closed after the next config update from the cluster is applied to the cache
+func (m CacheMethod) ConfigUpdateNotify() <-chan struct{} {
+	cfgUpdateMutex.Lock()
+	defer cfgUpdateMutex.Unlock()
+	return cfgUpdateCh
+}
+
+func configUpdateNotify() {
+	cfgUpdateMutex.Lock()
+	defer cfgUpdateMutex.Unlock()
+	close(cfgUpdateCh)
+	cfgUpdateCh = make(chan struct{})
+}
+
 func configUpdate(nType cluster.ClusterNotifyType, key string, value []byte, modifyIdx uint64) {
 	value, _, _ = kv.UpgradeAndConvert(key, value)
+	defer configUpdateNotify()
 
 	config := share.CLUSConfigKey2Config(key)
 
@@ -1659,6 +1749,8 @@ func configUpdate(nType cluster.ClusterNotifyType, key string, value []byte, mod
 		wafRuleConfigUpdate(nType, key, value)
 	case share.CFGEndpointWafGroup:
 		wafGroupConfigUpdate(nType, key, value)
+	case share.CFGEndpointThreatSignature:
+		threatSigConfigUpdate(nType, key, value)
 	case share.CFGEndpointCompliance:
 		complianceConfigUpdate(nType, key, value)
 	case share.CFGEndpointVulnerability:
@@ -1711,6 +1803,7 @@ func registerEventHandlers() {
 		hostWorkloadStart,
 		groupWorkloadJoin,
 		scanWorkloadAdd,
+		responseWorkloadStart,
 	})
 	evhdls.Register(EV_WORKLOAD_STOP, []eventHandlerFunc{
 		hostWorkloadStop,
@@ -1751,6 +1844,7 @@ func registerEventHandlers() {
 	})
 	evhdls.Register(EV_AGENT_ONLINE, []eventHandlerFunc{
 		rpcAgentOnline,
+		threatSigAgentOnline,
 	})
 	evhdls.Register(EV_AGENT_OFFLINE, []eventHandlerFunc{
 		rpcAgentOffline,
@@ -1773,6 +1867,8 @@ func registerEventHandlers() {
 func deleteAgentFromCache(ac *agentCache) {
 	hostID := ac.agent.HostID
 	delete(agentCacheMap, ac.agent.ID)
+	// Not to wait for the policy push in progress with cacheMutex held
+	go policyPushAgentLeave(ac.agent.ID)
 
 	// Update host cache. If this is the last agent, remove the host.
 	if hostID == "" {
@@ -1833,7 +1929,7 @@ func logControllerEvent(ev share.TLogEvent, ctrl *share.CLUSController, msg stri
 
 func markWorkloadState(workloads utils.Set, state string) {
 	for m := range workloads.Iter() {
-		if cache, ok := wlCacheMap[m.(string)]; ok {
+		if cache, ok := wlCacheMap.get(m.(string)); ok {
 			cache.state = state
 		}
 	}
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"sort"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/resource"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// OpenShift routes expose the k8s services, which select the pods by labels. The pod labels are only
+// kept on OpenShift so the route exposure of the learned services can be calculated.
+
+const defaultPrivilegedSCC = "privileged"
+
+var ocRouteMap map[string]*resource.Route = make(map[string]*resource.Route)           // uid => route
+var ocSCCMap map[string]*resource.SCC = make(map[string]*resource.SCC)                 // name => scc
+var ocSvcSelectorMap map[string]map[string]string = make(map[string]map[string]string) // domain/service => selector
+var ocPodLabelMap map[string]map[string]string = make(map[string]map[string]string)    // domain/pod => labels
+var ocMutex sync.RWMutex
+
+func ocObjectKey(domain, name string) string {
+	return domain + "/" + name
+}
+
+func routeUpdate(n, o *resource.Route) {
+	ocMutex.Lock()
+	defer ocMutex.Unlock()
+	if n != nil {
+		log.WithFields(log.Fields{"name": n.Name, "domain": n.Domain, "host": n.Host, "services": n.Services}).Debug()
+		ocRouteMap[n.UID] = n
+	} else if o != nil {
+		delete(ocRouteMap, o.UID)
+	}
+}
+
+func sccUpdate(n, o *resource.SCC) {
+	ocMutex.Lock()
+	defer ocMutex.Unlock()
+	if n != nil {
+		log.WithFields(log.Fields{"name": n.Name, "privileged": n.Privileged}).Debug()
+		ocSCCMap[n.Name] = n
+	} else if o != nil {
+		delete(ocSCCMap, o.Name)
+	}
+}
+
+func ocServiceSelectorUpdate(n, o *resource.Service) {
+	ocMutex.Lock()
+	defer ocMutex.Unlock()
+	if n != nil {
+		ocSvcSelectorMap[ocObjectKey(n.Domain, n.Name)] = n.Selector
+	} else if o != nil {
+		delete(ocSvcSelectorMap, ocObjectKey(o.Domain, o.Name))
+	}
+}
+
+func ocPodLabelUpdate(n, o *resource.Pod) {
+	ocMutex.Lock()
+	defer ocMutex.Unlock()
+	if n != nil {
+		ocPodLabelMap[ocObjectKey(n.Domain, n.Name)] = n.Labels
+	} else if o != nil {
+		delete(ocPodLabelMap, ocObjectKey(o.Domain, o.Name))
+	}
+}
+
+func isSelectorMatched(selector, labels map[string]string) bool {
+	if len(selector) == 0 {
+		return false
+	}
+	for k, v := range selector {
+		if lv, ok := labels[k]; !ok || lv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Return the hosts of the routes that expose any member of the group. Caller must hold cacheMutex.
+func getRouteExposure(gc *groupCache) []string {
+	ocMutex.RLock()
+	defer ocMutex.RUnlock()
+
+	if len(ocRouteMap) == 0 {
+		return nil
+	}
+
+	pods := make([]map[string]string, 0)
+	for m := range gc.members.Iter() {
+		if wl, ok := wlCacheMap.get(m.(string)); ok && wl.podName != "" {
+			if labels, ok := ocPodLabelMap[ocObjectKey(wl.workload.Domain, wl.podName)]; ok {
+				pods = append(pods, labels)
+			}
+		}
+	}
+	if len(pods) == 0 {
+		return nil
+	}
+
+	hosts := utils.NewSet()
+	for _, route := range ocRouteMap {
+		if route.Domain != gc.group.Domain || route.Host == "" {
+			continue
+		}
+	ROUTE:
+		for _, svc := range route.Services {
+			selector := ocSvcSelectorMap[ocObjectKey(route.Domain, svc)]
+			for _, labels := range pods {
+				if isSelectorMatched(selector, labels) {
+					hosts.Add(route.Host + route.Path)
+					break ROUTE
+				}
+			}
+		}
+	}
+	if hosts.Cardinality() == 0 {
+		return nil
+	}
+
+	list := hosts.ToStringSlice()
+	sort.Strings(list)
+	return list
+}
+
+// An SCC is privileged if it allows the privileged containers. The default privileged SCC is assumed
+// before the SCCs are learned.
+func isPrivilegedSCC(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	ocMutex.RLock()
+	defer ocMutex.RUnlock()
+	if scc, ok := ocSCCMap[name]; ok {
+		return scc.Privileged
+	}
+	return name == defaultPrivilegedSCC
+}
+
+// The SCC that is required by the workload takes precedence over the one that admitted the pod
+func getRequestedSCC(annotations map[string]string) string {
+	if scc, ok := annotations[resource.OpenShiftRequiredSCCAnnotation]; ok && scc != "" {
+		return scc
+	}
+	return annotations[resource.OpenShiftSCCAnnotation]
+}
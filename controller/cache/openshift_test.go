@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/resource"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func TestRouteExposure(t *testing.T) {
+	preTest()
+
+	wl1 := share.CLUSWorkload{ID: "1", Domain: "demo"}
+	wl2 := share.CLUSWorkload{ID: "2", Domain: "demo"}
+	wlCacheMap.set(wl1.ID, &workloadCache{workload: &wl1, podName: "frontend-1"})
+	wlCacheMap.set(wl2.ID, &workloadCache{workload: &wl2, podName: "backend-1"})
+	ocPodLabelUpdate(&resource.Pod{Name: "frontend-1", Domain: "demo", Labels: map[string]string{"app": "frontend", "tier": "web"}}, nil)
+	ocPodLabelUpdate(&resource.Pod{Name: "backend-1", Domain: "demo", Labels: map[string]string{"app": "backend"}}, nil)
+	ocServiceSelectorUpdate(&resource.Service{Name: "frontend", Domain: "demo", Selector: map[string]string{"app": "frontend"}}, nil)
+	ocServiceSelectorUpdate(&resource.Service{Name: "backend", Domain: "demo", Selector: map[string]string{"app": "backend"}}, nil)
+
+	frontend := &groupCache{group: &share.CLUSGroup{Name: "nv.frontend.demo", Domain: "demo"}, members: utils.NewSet("1")}
+	backend := &groupCache{group: &share.CLUSGroup{Name: "nv.backend.demo", Domain: "demo"}, members: utils.NewSet("2")}
+
+	if hosts := getRouteExposure(frontend); hosts != nil {
+		t.Errorf("Unexpected exposure without route: %v", hosts)
+	}
+
+	r1 := &resource.Route{UID: "r1", Name: "frontend", Domain: "demo", Host: "frontend.apps.example.com", Services: []string{"frontend"}}
+	r2 := &resource.Route{UID: "r2", Name: "api", Domain: "demo", Host: "frontend.apps.example.com", Path: "/api", Services: []string{"frontend"}}
+	r3 := &resource.Route{UID: "r3", Name: "backend", Domain: "other", Host: "backend.apps.example.com", Services: []string{"backend"}}
+	routeUpdate(r1, nil)
+	routeUpdate(r2, nil)
+	routeUpdate(r3, nil)
+
+	expect := []string{"frontend.apps.example.com", "frontend.apps.example.com/api"}
+	if hosts := getRouteExposure(frontend); !reflect.DeepEqual(hosts, expect) {
+		t.Errorf("Unexpected exposure: expect=%v actual=%v", expect, hosts)
+	}
+	if hosts := getRouteExposure(backend); hosts != nil {
+		t.Errorf("Route in other namespace should not expose the service: %v", hosts)
+	}
+
+	routeUpdate(nil, r2)
+	expect = []string{"frontend.apps.example.com"}
+	if hosts := getRouteExposure(frontend); !reflect.DeepEqual(hosts, expect) {
+		t.Errorf("Unexpected exposure after route deleted: expect=%v actual=%v", expect, hosts)
+	}
+
+	ocRouteMap = make(map[string]*resource.Route)
+	ocSvcSelectorMap = make(map[string]map[string]string)
+	ocPodLabelMap = make(map[string]map[string]string)
+	wlCacheMap = newWorkloadCacheMap()
+	postTest()
+}
+
+func TestPrivilegedSCC(t *testing.T) {
+	preTest()
+
+	if !isPrivilegedSCC("privileged") || isPrivilegedSCC("restricted") || isPrivilegedSCC("") {
+		t.Errorf("Unexpected default privileged SCC")
+	}
+
+	sccUpdate(&resource.SCC{Name: "privileged", Privileged: true}, nil)
+	sccUpdate(&resource.SCC{Name: "custom-priv", Privileged: true}, nil)
+	sccUpdate(&resource.SCC{Name: "restricted"}, nil)
+	if !isPrivilegedSCC("custom-priv") || isPrivilegedSCC("restricted") {
+		t.Errorf("Unexpected privileged SCC")
+	}
+
+	annotations := map[string]string{resource.OpenShiftSCCAnnotation: "restricted"}
+	if scc := getRequestedSCC(annotations); scc != "restricted" {
+		t.Errorf("Unexpected requested SCC: %s", scc)
+	}
+	annotations[resource.OpenShiftRequiredSCCAnnotation] = "custom-priv"
+	if scc := getRequestedSCC(annotations); scc != "custom-priv" {
+		t.Errorf("Unexpected requested SCC: %s", scc)
+	}
+
+	ocSCCMap = make(map[string]*resource.SCC)
+	postTest()
+}
@@ -4,6 +4,7 @@ package cache
 import "C"
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -20,6 +21,7 @@ import (
 	"github.com/neuvector/neuvector/controller/kv"
 	"github.com/neuvector/neuvector/share"
 	"github.com/neuvector/neuvector/share/cluster"
+	"github.com/neuvector/neuvector/share/otel"
 	"github.com/neuvector/neuvector/share/utils"
 )
 
@@ -86,7 +88,13 @@ func policyRule2REST(rule *share.CLUSPolicyRule) *api.RESTPolicyRule {
 		LastModTS:    rule.LastModAt.Unix(),
 		Priority:     rule.Priority,
 	}
+	if rule.RateLimit > 0 {
+		r.RateLimit = rule.RateLimit
+		r.RateLimitUnit = rule.RateLimitUnit
+	}
+	r.ExpireTS = rule.ExpireAt
 	r.CfgType, _ = cfgTypeMapping[rule.CfgType]
+	r.ManagedBy = getFedManagedBy(rule.CfgType)
 
 	return &r
 }
@@ -241,7 +249,8 @@ func policyConfigUpdate(nType cluster.ClusterNotifyType, key string, value []byt
 		}
 	}
 
-	scheduleIPPolicyCalculation(false)
+	// rules are recalculated if they are changed
+	scheduleGroupIPPolicyCalculation(false)
 	scheduleDlpRuleCalculation(false)
 }
 
@@ -444,8 +453,8 @@ func getWorkloadAddress(wlCache *workloadCache) share.CLUSWorkloadAddr {
 		WlID: wlCache.workload.ID,
 	}
 	wlAddr.PolicyMode, _ = getWorkloadEffectivePolicyMode(wlCache)
-	for _, addrs := range wlCache.workload.Ifaces {
-		for _, addr := range addrs {
+	for _, name := range sortedIfaceNames(wlCache.workload.Ifaces) {
+		for _, addr := range wlCache.workload.Ifaces[name] {
 			switch addr.Scope {
 			case share.CLUSIPAddrScopeLocalhost:
 				wlAddr.LocalIP = append(wlAddr.LocalIP, addr.IPNet.IP)
@@ -457,8 +466,8 @@ func getWorkloadAddress(wlCache *workloadCache) share.CLUSWorkloadAddr {
 
 	hostID := wlCache.workload.HostID
 	if hostCache, ok := hostCacheMap[hostID]; ok {
-		for _, addrs := range hostCache.host.Ifaces {
-			for _, addr := range addrs {
+		for _, name := range sortedIfaceNames(hostCache.host.Ifaces) {
+			for _, addr := range hostCache.host.Ifaces[name] {
 				wlAddr.NatIP = append(wlAddr.NatIP, addr.IPNet.IP)
 			}
 		}
@@ -528,11 +537,11 @@ func fillPortsForWorkloadAddress(wlAddr *share.CLUSWorkloadAddr, ports string, a
 	var ok bool
 	var pp string
 
-	if wlCache, ok = wlCacheMap[wlAddr.WlID]; !ok {
+	if wlCache, ok = wlCacheMap.get(wlAddr.WlID); !ok {
 		log.WithFields(log.Fields{"workload": wlAddr.WlID}).Error("Cannot find workload")
 		return
 	} else if wlCache.workload.ShareNetNS != "" {
-		if wlCache1, ok1 := wlCacheMap[wlCache.workload.ShareNetNS]; ok1 {
+		if wlCache1, ok1 := wlCacheMap.get(wlCache.workload.ShareNetNS); ok1 {
 			wlCache = wlCache1
 		}
 	}
@@ -681,13 +690,15 @@ func fillAddrForGroup(name string, ports string, hostID string, apps []uint32, i
 		return groupAddrs
 	} else if cache, ok := groupCacheMap[name]; ok {
 		groupAddrs := make([]*share.CLUSWorkloadAddr, 0, cache.members.Cardinality())
-		for _, m := range cache.members.ToSlice() {
+		members := cache.members.ToStringSlice()
+		sort.Strings(members)
+		for _, m := range members {
 			wlAddr := share.CLUSWorkloadAddr{
-				WlID: m.(string),
+				WlID: m,
 			}
 			//set PolicyMode here to avoid 'Missing policy mode'
 			//errror at the adjustAction().
-			if wlCache1, ok1 := wlCacheMap[m.(string)]; ok1 {
+			if wlCache1, ok1 := wlCacheMap.get(m); ok1 {
 				if wlCache1.workload.HasDatapath == false {
 					continue
 				}
@@ -829,17 +840,17 @@ func getDefaultGroupPolicy() share.CLUSGroupIPPolicy {
 		ID: share.DefaultGroupRuleID,
 	}
 
-	policy.From = make([]*share.CLUSWorkloadAddr, 0, len(wlCacheMap))
+	policy.From = make([]*share.CLUSWorkloadAddr, 0, wlCacheMap.size())
 	wlLearnList = make([]*share.CLUSWorkloadAddr, 0)
 	wlEvalList = make([]*share.CLUSWorkloadAddr, 0)
 	wlEnforceList = make([]*share.CLUSWorkloadAddr, 0)
-	for _, cache := range wlCacheMap {
+	wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 		if cache.groups.Cardinality() == 0 {
 			/* workload not assigned group yet - skip */
-			continue
+			return true
 		}
 		if !cache.workload.Running {
-			continue
+			return true
 		}
 		/*
 		 * In a new deployment, a new workload info is sent by enforcer to cluster
@@ -852,14 +863,14 @@ func getDefaultGroupPolicy() share.CLUSGroupIPPolicy {
 		 * is in groupcache so that we can get correct mode information
 		 */
 		if _, ok := groupCacheMap[cache.learnedGroupName]; !ok {
-			continue
+			return true
 		}
 		//we only push container that has datapath, normally it is parent, but since oc4.9+
 		//parent pid could be 0, so it is also possible this is child
 		//we only want to carry one member of POD family to reduce policy recalculate size
 		//this can save cpu, memory and internal network bandwidth
 		if cache.workload.HasDatapath == false {
-			continue
+			return true
 		}
 		addr := getWorkloadAddress(cache)
 		policy.From = append(policy.From, &addr)
@@ -870,8 +881,24 @@ func getDefaultGroupPolicy() share.CLUSGroupIPPolicy {
 		} else if addr.PolicyMode == share.PolicyModeEnforce {
 			wlEnforceList = append(wlEnforceList, &addr)
 		}
+		return true
+	})
+	// Host processes are enforced with their own policy mode, so that host can be kept
+	// in discover mode while containers are protected. It is not part of the mode lists
+	// to avoid host being covered by the rules among containers.
+	if getHostNetPolicyStatus() {
+		policy.From = append(policy.From, &share.CLUSWorkloadAddr{
+			WlID:       share.CLUSHostNodeWL,
+			PolicyMode: getHostNetPolicyMode(),
+		})
 	}
 
+	// keep the same order, so the unchanged rules are in the same slots
+	sortWorkloadAddrs(policy.From)
+	sortWorkloadAddrs(wlLearnList)
+	sortWorkloadAddrs(wlEvalList)
+	sortWorkloadAddrs(wlEnforceList)
+
 	printOneGroupIPPolicy(&policy)
 	return policy
 }
@@ -891,6 +918,7 @@ func getServiceAddrForMode(mode string) []*share.CLUSWorkloadAddr {
 			}
 		}
 	}
+	sort.Slice(dstList, func(i, j int) bool { return dstList[i].NatIP[0].String() < dstList[j].NatIP[0].String() })
 	return dstList
 }
 
@@ -1075,13 +1103,24 @@ func ruleAdjustOrder(rule *share.CLUSPolicyRule) bool {
 	return false
 }
 
+// cacheMutex read-locked
+func isTemplateGroup(name string) bool {
+	if cache, ok := groupCacheMap[name]; ok {
+		return cache.group.Template
+	}
+	return false
+}
+
 func adjustPolicyRuleHeads() []*share.CLUSRuleHead {
 	adjRuleHeads := make([]*share.CLUSRuleHead, 0)
+	tmplRuleHeads := make([]*share.CLUSRuleHead, 0)
 	hostRuleHeads := make([]*share.CLUSRuleHead, 0)
 	for _, head := range policyCache.ruleHeads {
 		if rule, ok := policyCache.ruleMap[head.ID]; ok && !rule.Disable {
 			if ruleAdjustOrder(rule) {
 				hostRuleHeads = append(hostRuleHeads, head)
+			} else if rule.CfgType != share.FederalCfg && (isTemplateGroup(rule.From) || isTemplateGroup(rule.To)) {
+				tmplRuleHeads = append(tmplRuleHeads, head)
 			} else {
 				adjRuleHeads = append(adjRuleHeads, head)
 			}
@@ -1089,6 +1128,8 @@ func adjustPolicyRuleHeads() []*share.CLUSRuleHead {
 			adjRuleHeads = append(adjRuleHeads, head)
 		}
 	}
+	//rules of template groups are the baseline, overridden by the rules of the service groups
+	adjRuleHeads = append(adjRuleHeads, tmplRuleHeads...)
 	//user created host related deny rules are put to the end of list
 	adjRuleHeads = append(adjRuleHeads, hostRuleHeads...)
 
@@ -1120,8 +1161,9 @@ func isC2cRule(rule *share.CLUSPolicyRule) bool {
 	return false
 }
 
-func calculateIPPolicyFromCache() []share.CLUSGroupIPPolicy {
+func calculateIPPolicyFromCache() ([]share.CLUSGroupIPPolicy, bool) {
 	log.Debug("")
+	start := time.Now()
 
 	//simulator func to test policy capacity
 	//return kv.CalculateIPPolicyFromCacheFake()
@@ -1137,6 +1179,13 @@ func calculateIPPolicyFromCache() []share.CLUSGroupIPPolicy {
 	 */
 	adjustRuleHeads := adjustPolicyRuleHeads()
 
+	dirtyAll, dirtyGroups, force := fetchIPPolicyDirty()
+	if dirtyAll {
+		ipPolicyFullCalcAt = time.Now()
+	}
+	stats := ipPolicyCalcStats{}
+	usedRules := utils.NewSet()
+
 	c2cAny := false
 	for _, head := range adjustRuleHeads {
 		if rule, ok := policyCache.ruleMap[head.ID]; !ok {
@@ -1149,33 +1198,14 @@ func calculateIPPolicyFromCache() []share.CLUSGroupIPPolicy {
 				c2cAny = true
 			}
 
-			policy := share.CLUSGroupIPPolicy{
-				ID:     head.ID,
-				Action: C.DP_POLICY_ACTION_ALLOW,
+			usedRules.Add(rule.ID)
+			if policy := calculateRuleIPPolicy(rule, dirtyAll, dirtyGroups, &stats); policy != nil {
+				groupIPPolicies = append(groupIPPolicies, *policy)
+				printOneGroupIPPolicy(policy)
 			}
-			if rule.Action == share.PolicyActionDeny {
-				policy.Action = C.DP_POLICY_ACTION_DENY
-			}
-
-			// assume the from/to contains only one group
-			/*
-				log.WithFields(log.Fields{
-					"c2cAny": c2cAny, "from": rule.From, "to": rule.To, "ports": rule.Ports, "app": rule.Applications,
-				}).Debug("Calculate rule")
-			*/
-
-			policy.From = fillAddrForGroup(rule.From, "", rule.FromHost, nil, false)
-			if policy.From == nil || len(policy.From) == 0 {
-				continue
-			}
-			policy.To = fillAddrForGroup(rule.To, rule.Ports, rule.ToHost, rule.Applications, true)
-			if policy.To == nil || len(policy.To) == 0 {
-				continue
-			}
-			groupIPPolicies = append(groupIPPolicies, policy)
-			printOneGroupIPPolicy(&policy)
 		}
 	}
+	pruneIPPolicyRuleCache(usedRules)
 	if !c2cAny {
 		if policyApplyIngress {
 			policy := getMixedGroupPolicyForIngress()
@@ -1190,7 +1220,8 @@ func calculateIPPolicyFromCache() []share.CLUSGroupIPPolicy {
 	wlEvalList = nil
 	wlEnforceList = nil
 
-	return groupIPPolicies
+	logIPPolicyCalculation(dirtyAll, &stats, start)
+	return groupIPPolicies, force
 }
 
 func getPolicyIPRulesFromCluster() []share.CLUSGroupIPPolicy {
@@ -1250,14 +1281,13 @@ func preparePolicySlots(rules []share.CLUSGroupIPPolicy) ([][]byte, int, int, er
 			wl_slots = wl_lens
 		}
 		rules_wl := make([]share.CLUSGroupIPPolicy, wl_slots)
-		for i := 0; i < wl_lens; i++ {
-			if i < wl_slots {
-				rules_wl[i].ID = share.DefaultGroupRuleID
-				rules_wl[i].From = make([]*share.CLUSWorkloadAddr, 0)
-				rules_wl[i].From = append(rules_wl[i].From, rules[0].From[i])
-			} else {
-				rules_wl[i%wl_slots].From = append(rules_wl[i%wl_slots].From, rules[0].From[i])
-			}
+		for i := 0; i < wl_slots; i++ {
+			rules_wl[i].ID = share.DefaultGroupRuleID
+			rules_wl[i].From = make([]*share.CLUSWorkloadAddr, 0, wl_lens/wl_slots+1)
+		}
+		for _, addr := range rules[0].From {
+			idx := policyWorkloadSlot(addr.WlID, wl_slots)
+			rules_wl[idx].From = append(rules_wl[idx].From, addr)
 		}
 		new_rules := make([]share.CLUSGroupIPPolicy, 0)
 		new_rules = append(new_rules, rules_wl...)
@@ -1321,7 +1351,7 @@ func policyIPRulesCleanup(ruleKeys []string) {
 	txn.Apply()
 }
 
-func putPolicyIPRulesToClusterScale(rules []share.CLUSGroupIPPolicy) {
+func putPolicyIPRulesToClusterScale(ctx context.Context, rules []share.CLUSGroupIPPolicy, force bool) {
 	//
 	//GroupIPRules is not directly watched by consul, to improve performance
 	//change key from "network/GroupIPRules/" to "recalculate/policy/GroupIPRules/"
@@ -1339,6 +1369,13 @@ func putPolicyIPRulesToClusterScale(rules []share.CLUSGroupIPPolicy) {
 		return
 	}
 
+	// enforcers are not notified if no rule is changed
+	hashes := getPolicySlotHash(zbs)
+	if !force && !isPolicySlotChanged(hashes, wlslots, wlens) {
+		log.Debug("Policy rules not changed")
+		return
+	}
+
 	//put rules to cluster in separate slot
 	for i, zb := range zbs {
 		key := fmt.Sprintf("%s%d", newRuleKey, i)
@@ -1358,6 +1395,8 @@ func putPolicyIPRulesToClusterScale(rules []share.CLUSGroupIPPolicy) {
 		RulesLen:             len(rules) + wlslots - 1,
 		WorkloadSlot:         wlslots,
 		WorkloadLen:          wlens,
+		SlotHash:             hashes,
+		TraceParent:          otel.TraceParent(ctx),
 	}
 	log.WithFields(log.Fields{"PolicyIPRules": newRuleKey, "policyVer": polVer}).Debug("New policy rules written")
 
@@ -1369,6 +1408,9 @@ func putPolicyIPRulesToClusterScale(rules []share.CLUSGroupIPPolicy) {
 		return
 	}
 	policyIPRulesCleanup(oldKeys)
+
+	lastPolicySlotHash = hashes
+	lastPolicyWlSlots, lastPolicyWlLen = wlslots, wlens
 }
 
 func putPolicyIPRulesToCluster(rules []share.CLUSGroupIPPolicy) {
@@ -1381,7 +1423,13 @@ func putPolicyIPRulesToCluster(rules []share.CLUSGroupIPPolicy) {
 	//log.WithFields(log.Fields{"value": string(value), "len": len(value), "zb": len(zb)}).Debug("")
 }
 
+// scheduleIPPolicyCalculation schedules the policy calculation that recalculates all rules
 func scheduleIPPolicyCalculation(fast bool) {
+	markIPPolicyAllDirty()
+	scheduleIPPolicyCalculationInternal(fast)
+}
+
+func scheduleIPPolicyCalculationInternal(fast bool) {
 	log.WithFields(log.Fields{"fast": fast, "policyCalculated": policyCalculated}).Debug("")
 	//no need to reset timer if network policy is disabled
 	if getDisableNetPolicyStatus() {
@@ -0,0 +1,238 @@
+package cache
+
+// Incremental network policy calculation. The address list calculated for a rule is kept and reused until
+// the rule, its from/to groups or the members of the groups are changed, so a change of one group only
+// recalculates the rules of the group. The calculated rules are written in slots, and a slot keeps the same
+// content if its rules are not changed, so the enforcers only read the slots that are different.
+
+// #include "../../defs.h"
+import "C"
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// Recalculate all rules periodically in case any change is not tracked
+const ipPolicyFullCalcInterval = time.Minute * 10
+
+type ipPolicyCacheEntry struct {
+	rule   *share.CLUSPolicyRule
+	from   *groupCache
+	to     *groupCache
+	policy *share.CLUSGroupIPPolicy // nil if from or to has no address
+}
+
+type ipPolicyCalcStats struct {
+	calculated int
+	reused     int
+}
+
+var ipPolicyDirtyMutex sync.Mutex
+var ipPolicyDirtyAll bool = true
+var ipPolicyDirtyGroups utils.Set = utils.NewSet()
+var ipPolicyForcePut bool = true
+
+// Only accessed in the policy calculation
+var ipPolicyRuleCache map[uint32]*ipPolicyCacheEntry = make(map[uint32]*ipPolicyCacheEntry)
+var ipPolicyFullCalcAt time.Time
+var lastPolicySlotHash []string
+var lastPolicyWlSlots, lastPolicyWlLen int
+
+func markIPPolicyGroupDirty(groups ...string) {
+	ipPolicyDirtyMutex.Lock()
+	for _, name := range groups {
+		ipPolicyDirtyGroups.Add(name)
+	}
+	ipPolicyDirtyMutex.Unlock()
+}
+
+func markIPPolicyAllDirty() {
+	ipPolicyDirtyMutex.Lock()
+	ipPolicyDirtyAll = true
+	ipPolicyDirtyMutex.Unlock()
+}
+
+// Recalculate and write all rules, called when the controller becomes the lead
+func resetIPPolicyCalculation() {
+	ipPolicyDirtyMutex.Lock()
+	ipPolicyDirtyAll = true
+	ipPolicyForcePut = true
+	ipPolicyDirtyMutex.Unlock()
+}
+
+// scheduleGroupIPPolicyCalculation schedules the policy calculation that only recalculates the rules of the
+// given groups. The rules that are added, removed or modified are always recalculated.
+func scheduleGroupIPPolicyCalculation(fast bool, groups ...string) {
+	markIPPolicyGroupDirty(groups...)
+	scheduleIPPolicyCalculationInternal(fast)
+}
+
+func fetchIPPolicyDirty() (bool, utils.Set, bool) {
+	ipPolicyDirtyMutex.Lock()
+	defer ipPolicyDirtyMutex.Unlock()
+
+	all, groups, force := ipPolicyDirtyAll, ipPolicyDirtyGroups, ipPolicyForcePut
+	if time.Since(ipPolicyFullCalcAt) > ipPolicyFullCalcInterval {
+		all, force = true, true
+	}
+	ipPolicyDirtyAll = false
+	ipPolicyDirtyGroups = utils.NewSet()
+	ipPolicyForcePut = false
+	return all, groups, force
+}
+
+// Addresses of these endpoints are not from the group members, calculate them every time
+func getPolicyCacheGroup(name string) (*groupCache, bool) {
+	if utils.IsGroupNodes(name) || strings.HasPrefix(name, api.LearnedHostPrefix) || strings.HasPrefix(name, api.LearnedWorkloadPrefix) {
+		return nil, false
+	}
+	cache, ok := groupCacheMap[name]
+	return cache, ok
+}
+
+// The rule's own rate limit takes precedence over the limit of the group it leads to, which
+// is shared by all the allow rules to the group.
+func getRuleRateLimit(rule *share.CLUSPolicyRule) (uint32, string, uint32) {
+	if rule.RateLimit > 0 {
+		return rule.RateLimit, rule.RateLimitUnit, rule.ID
+	}
+	if cache, ok := groupCacheMap[rule.To]; ok && cache.group.RateLimit > 0 {
+		return cache.group.RateLimit, cache.group.RateLimitUnit, getGroupRateID(rule.To)
+	}
+	return 0, "", 0
+}
+
+func getGroupRateID(name string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return share.PolicyRateGroupIDBase | (h.Sum32() &^ share.PolicyRateGroupIDBase)
+}
+
+func calculateRuleIPPolicy(rule *share.CLUSPolicyRule, dirtyAll bool, dirtyGroups utils.Set, stats *ipPolicyCalcStats) *share.CLUSGroupIPPolicy {
+	from, fromOK := getPolicyCacheGroup(rule.From)
+	to, toOK := getPolicyCacheGroup(rule.To)
+	if e, ok := ipPolicyRuleCache[rule.ID]; ok && !dirtyAll && fromOK && toOK &&
+		e.rule == rule && e.from == from && e.to == to &&
+		!dirtyGroups.Contains(rule.From) && !dirtyGroups.Contains(rule.To) {
+		stats.reused++
+		return e.policy
+	}
+
+	stats.calculated++
+	policy := &share.CLUSGroupIPPolicy{
+		ID:     rule.ID,
+		Action: C.DP_POLICY_ACTION_ALLOW,
+	}
+	if rule.Action == share.PolicyActionDeny {
+		policy.Action = C.DP_POLICY_ACTION_DENY
+	} else if rate, unit, id := getRuleRateLimit(rule); rate > 0 {
+		policy.RateLimit = rate
+		policy.RateID = id
+		switch unit {
+		case share.PolicyRateUnitBytes:
+			policy.RateUnit = C.DP_POLICY_RATE_BPS
+		case share.PolicyRateUnitConnections:
+			policy.RateUnit = C.DP_POLICY_RATE_CPS
+		}
+	}
+
+	// assume the from/to contains only one group
+	policy.From = fillAddrForGroup(rule.From, "", rule.FromHost, nil, false)
+	if len(policy.From) > 0 {
+		policy.To = fillAddrForGroup(rule.To, rule.Ports, rule.ToHost, rule.Applications, true)
+	}
+	if len(policy.From) == 0 || len(policy.To) == 0 {
+		policy = nil
+	}
+
+	if fromOK && toOK {
+		ipPolicyRuleCache[rule.ID] = &ipPolicyCacheEntry{rule: rule, from: from, to: to, policy: policy}
+	} else {
+		delete(ipPolicyRuleCache, rule.ID)
+	}
+	return policy
+}
+
+// Remove results of the rules that are deleted or disabled
+func pruneIPPolicyRuleCache(used utils.Set) {
+	for id := range ipPolicyRuleCache {
+		if !used.Contains(id) {
+			delete(ipPolicyRuleCache, id)
+		}
+	}
+}
+
+// A workload is always put in the same slot, so adding or removing a workload only changes one slot
+func policyWorkloadSlot(wlID string, slots int) int {
+	h := fnv.New32a()
+	h.Write([]byte(wlID))
+	return int(h.Sum32() % uint32(slots))
+}
+
+func sortWorkloadAddrs(addrs []*share.CLUSWorkloadAddr) {
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i].WlID < addrs[j].WlID })
+}
+
+func sortedIfaceNames(ifaces map[string][]share.CLUSIPAddr) []string {
+	names := make([]string, 0, len(ifaces))
+	for name := range ifaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func getPolicySlotHash(zbs [][]byte) []string {
+	hashes := make([]string, len(zbs))
+	for i, zb := range zbs {
+		sum := md5.Sum(zb)
+		hashes[i] = hex.EncodeToString(sum[:])
+	}
+	return hashes
+}
+
+func isPolicySlotChanged(hashes []string, wlslots, wlens int) bool {
+	if wlslots != lastPolicyWlSlots || wlens != lastPolicyWlLen || len(hashes) != len(lastPolicySlotHash) {
+		return true
+	}
+	for i, h := range hashes {
+		if h != lastPolicySlotHash[i] {
+			return true
+		}
+	}
+	return false
+}
+
+func logIPPolicyCalculation(full bool, stats *ipPolicyCalcStats, start time.Time) {
+	log.WithFields(log.Fields{
+		"full": full, "calculated": stats.calculated, "reused": stats.reused, "elapsed": time.Since(start),
+	}).Debug("Policy calculated")
+}
+
+// Members of a learned group take the policy mode of the group, so the rules of the other groups that the
+// members are in are changed too. Caller must hold cacheMutex.
+func getPolicyMemberGroups(cache *groupCache) []string {
+	groups := utils.NewSet()
+	if cache.group.CfgType == share.Learned {
+		for m := range cache.members.Iter() {
+			if wlc, ok := wlCacheMap.get(m.(string)); ok {
+				for g := range wlc.groups.Iter() {
+					groups.Add(g)
+				}
+			}
+		}
+	}
+	return groups.ToStringSlice()
+}
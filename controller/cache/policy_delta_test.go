@@ -0,0 +1,383 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func addPolicyTestGroup(name string, members ...string) *groupCache {
+	cache := initGroupCache(share.UserCreated, name)
+	for _, m := range members {
+		wlc, ok := wlCacheMap.get(m)
+		if !ok {
+			wlc = &workloadCache{
+				workload: &share.CLUSWorkload{ID: m, HasDatapath: true, Running: true},
+				groups:   utils.NewSet(),
+			}
+			wlCacheMap.set(m, wlc)
+		}
+		wlc.groups.Add(name)
+		cache.members.Add(m)
+	}
+	groupCacheMap[name] = cache
+	return cache
+}
+
+func TestIncrementalRuleIPPolicy(t *testing.T) {
+	preTest()
+
+	oldGroups, oldWls := groupCacheMap, wlCacheMap
+	groupCacheMap = make(map[string]*groupCache)
+	wlCacheMap = newWorkloadCacheMap()
+	ipPolicyRuleCache = make(map[uint32]*ipPolicyCacheEntry)
+	defer func() {
+		groupCacheMap, wlCacheMap = oldGroups, oldWls
+		ipPolicyRuleCache = make(map[uint32]*ipPolicyCacheEntry)
+	}()
+
+	addPolicyTestGroup("g1", "w1", "w2")
+	g2 := addPolicyTestGroup("g2", "w3")
+	rule := &share.CLUSPolicyRule{ID: 10, From: "g1", To: "g2", Ports: "any"}
+
+	var stats ipPolicyCalcStats
+	p1 := calculateRuleIPPolicy(rule, false, utils.NewSet(), &stats)
+	if p1 == nil || len(p1.From) != 2 || len(p1.To) != 1 {
+		t.Fatalf("Unexpected policy: %+v", p1)
+	}
+	if p1.From[0].WlID != "w1" || p1.From[1].WlID != "w2" {
+		t.Errorf("Addresses are not sorted: %s, %s", p1.From[0].WlID, p1.From[1].WlID)
+	}
+
+	// Not changed
+	if p := calculateRuleIPPolicy(rule, false, utils.NewSet(), &stats); p != p1 || stats.reused != 1 {
+		t.Errorf("Policy should be reused: stats=%+v", stats)
+	}
+
+	// Changes of other groups don't recalculate the rule
+	addPolicyTestGroup("g3", "w4")
+	g2.members.Add("w4")
+	if p := calculateRuleIPPolicy(rule, false, utils.NewSet("g3"), &stats); p != p1 {
+		t.Errorf("Policy should be reused if its groups are not changed")
+	}
+	if p := calculateRuleIPPolicy(rule, false, utils.NewSet("g2"), &stats); p == p1 || len(p.To) != 2 {
+		t.Errorf("Policy should be recalculated when its group is changed: %+v", p)
+	}
+
+	// Group is replaced when its config is changed
+	p2 := calculateRuleIPPolicy(rule, false, utils.NewSet(), &stats)
+	addPolicyTestGroup("g1", "w1")
+	if p := calculateRuleIPPolicy(rule, false, utils.NewSet(), &stats); p == p2 || len(p.From) != 1 {
+		t.Errorf("Policy should be recalculated when group is replaced: %+v", p)
+	}
+
+	// Rule is replaced when it is changed
+	p3 := calculateRuleIPPolicy(rule, false, utils.NewSet(), &stats)
+	rule = &share.CLUSPolicyRule{ID: 10, From: "g1", To: "g3", Ports: "any"}
+	if p := calculateRuleIPPolicy(rule, false, utils.NewSet(), &stats); p == p3 || p.To[0].WlID != "w4" {
+		t.Errorf("Policy should be recalculated when rule is changed: %+v", p)
+	}
+	if p := calculateRuleIPPolicy(rule, true, utils.NewSet(), &stats); p == p3 {
+		t.Errorf("Policy should be recalculated in full calculation")
+	}
+
+	// Rules of deleted groups have no address
+	delete(groupCacheMap, "g3")
+	if p := calculateRuleIPPolicy(rule, false, utils.NewSet(), &stats); p != nil {
+		t.Errorf("Rule of deleted group should be ignored: %+v", p)
+	}
+	if _, ok := ipPolicyRuleCache[rule.ID]; ok {
+		t.Errorf("Rule of deleted group should not be cached")
+	}
+
+	pruneIPPolicyRuleCache(utils.NewSet())
+	if len(ipPolicyRuleCache) != 0 {
+		t.Errorf("Unused rules should be removed")
+	}
+
+	postTest()
+}
+
+func TestRuleIPPolicyRateLimit(t *testing.T) {
+	preTest()
+
+	oldGroups, oldWls := groupCacheMap, wlCacheMap
+	groupCacheMap = make(map[string]*groupCache)
+	wlCacheMap = newWorkloadCacheMap()
+	ipPolicyRuleCache = make(map[uint32]*ipPolicyCacheEntry)
+	defer func() {
+		groupCacheMap, wlCacheMap = oldGroups, oldWls
+		ipPolicyRuleCache = make(map[uint32]*ipPolicyCacheEntry)
+	}()
+
+	addPolicyTestGroup("g1", "w1")
+	addPolicyTestGroup("g2", "w2")
+	g3 := addPolicyTestGroup("g3", "w3")
+	g3.group.RateLimit = 1000
+	g3.group.RateLimitUnit = share.PolicyRateUnitBytes
+
+	var stats ipPolicyCalcStats
+
+	// No limit
+	rule := &share.CLUSPolicyRule{ID: 10, From: "g1", To: "g2", Ports: "any", Action: share.PolicyActionAllow}
+	if p := calculateRuleIPPolicy(rule, true, utils.NewSet(), &stats); p == nil || p.RateLimit != 0 || p.RateID != 0 {
+		t.Errorf("Unexpected rate limit: %+v", p)
+	}
+
+	// Rule limit is metered by the rule
+	rule = &share.CLUSPolicyRule{ID: 11, From: "g1", To: "g2", Ports: "any", Action: share.PolicyActionAllow,
+		RateLimit: 100, RateLimitUnit: share.PolicyRateUnitConnections}
+	if p := calculateRuleIPPolicy(rule, true, utils.NewSet(), &stats); p == nil ||
+		p.RateLimit != 100 || p.RateID != rule.ID || p.RateUnit == 0 {
+		t.Errorf("Unexpected rule rate limit: %+v", p)
+	}
+
+	// Rules to the group share the group limit
+	rule1 := &share.CLUSPolicyRule{ID: 12, From: "g1", To: "g3", Ports: "any", Action: share.PolicyActionAllow}
+	rule2 := &share.CLUSPolicyRule{ID: 13, From: "g2", To: "g3", Ports: "any", Action: share.PolicyActionAllow}
+	p1 := calculateRuleIPPolicy(rule1, true, utils.NewSet(), &stats)
+	p2 := calculateRuleIPPolicy(rule2, true, utils.NewSet(), &stats)
+	if p1 == nil || p2 == nil || p1.RateLimit != 1000 || p2.RateLimit != 1000 {
+		t.Fatalf("Group rate limit is not applied: %+v, %+v", p1, p2)
+	}
+	if p1.RateID != p2.RateID || p1.RateID&share.PolicyRateGroupIDBase == 0 {
+		t.Errorf("Rules to the group should share the rate id: %x, %x", p1.RateID, p2.RateID)
+	}
+
+	// Rule limit takes precedence over the group limit
+	rule1 = &share.CLUSPolicyRule{ID: 12, From: "g1", To: "g3", Ports: "any", Action: share.PolicyActionAllow,
+		RateLimit: 100, RateLimitUnit: share.PolicyRateUnitBytes}
+	if p := calculateRuleIPPolicy(rule1, true, utils.NewSet(), &stats); p == nil || p.RateLimit != 100 || p.RateID != rule1.ID {
+		t.Errorf("Rule rate limit should take precedence: %+v", p)
+	}
+
+	// Deny rule and rules from the group are not limited
+	rule = &share.CLUSPolicyRule{ID: 14, From: "g1", To: "g3", Ports: "any", Action: share.PolicyActionDeny}
+	if p := calculateRuleIPPolicy(rule, true, utils.NewSet(), &stats); p == nil || p.RateLimit != 0 {
+		t.Errorf("Deny rule should not be limited: %+v", p)
+	}
+	rule = &share.CLUSPolicyRule{ID: 15, From: "g3", To: "g1", Ports: "any", Action: share.PolicyActionAllow}
+	if p := calculateRuleIPPolicy(rule, true, utils.NewSet(), &stats); p == nil || p.RateLimit != 0 {
+		t.Errorf("Rule from the group should not be limited: %+v", p)
+	}
+
+	postTest()
+}
+
+func TestDefaultGroupPolicyHostNode(t *testing.T) {
+	preTest()
+
+	oldGroups, oldWls, oldConfig := groupCacheMap, wlCacheMap, systemConfigCache
+	groupCacheMap = make(map[string]*groupCache)
+	wlCacheMap = newWorkloadCacheMap()
+	defer func() {
+		groupCacheMap, wlCacheMap, systemConfigCache = oldGroups, oldWls, oldConfig
+	}()
+
+	g := addPolicyTestGroup(api.LearnedGroupPrefix+"w1", "w1")
+	g.group.PolicyMode = share.PolicyModeEvaluate
+	wlc, _ := wlCacheMap.get("w1")
+	wlc.learnedGroupName = g.group.Name
+
+	findHostNode := func(p share.CLUSGroupIPPolicy) *share.CLUSWorkloadAddr {
+		for _, addr := range p.From {
+			if addr.WlID == share.CLUSHostNodeWL {
+				return addr
+			}
+		}
+		return nil
+	}
+
+	// Disabled
+	systemConfigCache.HostNetPolicyStatus = false
+	if p := getDefaultGroupPolicy(); findHostNode(p) != nil || len(p.From) != 1 {
+		t.Errorf("Host node should not be added when host network policy is disabled: %+v", p.From)
+	}
+
+	// Enabled, host is in its own mode regardless of the container mode
+	systemConfigCache.HostNetPolicyStatus = true
+	systemConfigCache.HostNetPolicyMode = share.PolicyModeEnforce
+	p := getDefaultGroupPolicy()
+	if addr := findHostNode(p); addr == nil || addr.PolicyMode != share.PolicyModeEnforce {
+		t.Errorf("Unexpected host node: %+v", addr)
+	} else if len(addr.LocalIP) != 0 || len(addr.GlobalIP) != 0 || len(addr.NatIP) != 0 {
+		t.Errorf("Host node addresses should be filled by the enforcer: %+v", addr)
+	}
+	if len(p.From) != 2 {
+		t.Errorf("Unexpected default policy: %+v", p.From)
+	}
+
+	// Host node is not covered by the rules among containers of the same mode
+	for _, list := range [][]*share.CLUSWorkloadAddr{wlLearnList, wlEvalList, wlEnforceList} {
+		for _, addr := range list {
+			if addr.WlID == share.CLUSHostNodeWL {
+				t.Errorf("Host node should not be in the mode list: %+v", addr)
+			}
+		}
+	}
+	if len(wlEvalList) != 1 || wlEvalList[0].WlID != "w1" || len(wlEnforceList) != 0 {
+		t.Errorf("Unexpected mode lists: eval=%d enforce=%d", len(wlEvalList), len(wlEnforceList))
+	}
+
+	// Default host mode
+	systemConfigCache.HostNetPolicyMode = ""
+	if addr := findHostNode(getDefaultGroupPolicy()); addr == nil || addr.PolicyMode != share.PolicyModeLearn {
+		t.Errorf("Host node should be in discover mode by default: %+v", addr)
+	}
+
+	postTest()
+}
+
+func TestPolicyWorkloadSlots(t *testing.T) {
+	preTest()
+
+	makeRules := func(wls int) []share.CLUSGroupIPPolicy {
+		def := share.CLUSGroupIPPolicy{ID: share.DefaultGroupRuleID}
+		for i := 0; i < wls; i++ {
+			def.From = append(def.From, &share.CLUSWorkloadAddr{WlID: fmt.Sprintf("wl-%d", i)})
+		}
+		sortWorkloadAddrs(def.From)
+		rules := []share.CLUSGroupIPPolicy{def}
+		for i := 0; i < 20; i++ {
+			rules = append(rules, share.CLUSGroupIPPolicy{
+				ID:   uint32(i + 1),
+				From: []*share.CLUSWorkloadAddr{&share.CLUSWorkloadAddr{WlID: "wl-0"}},
+				To:   []*share.CLUSWorkloadAddr{&share.CLUSWorkloadAddr{WlID: "wl-1"}},
+			})
+		}
+		return rules
+	}
+
+	zbs1, wlslots1, wlens1, err := preparePolicySlots(makeRules(100))
+	if err != nil {
+		t.Fatalf("Failed to prepare slots: %v", err)
+	}
+	zbs2, wlslots2, wlens2, _ := preparePolicySlots(makeRules(100))
+	h1, h2 := getPolicySlotHash(zbs1), getPolicySlotHash(zbs2)
+	for i := range h1 {
+		if h1[i] != h2[i] {
+			t.Errorf("Slot %d is changed without rule change", i)
+		}
+	}
+
+	lastPolicySlotHash, lastPolicyWlSlots, lastPolicyWlLen = h1, wlslots1, wlens1
+	defer func() {
+		lastPolicySlotHash, lastPolicyWlSlots, lastPolicyWlLen = nil, 0, 0
+	}()
+	if isPolicySlotChanged(h2, wlslots2, wlens2) {
+		t.Errorf("Slots should not be changed")
+	}
+
+	// A new workload only changes its own slot
+	zbs3, wlslots3, wlens3, _ := preparePolicySlots(makeRules(101))
+	h3 := getPolicySlotHash(zbs3)
+	if wlslots3 != wlslots1 || wlens3 != wlens1+1 || len(h3) != len(h1) {
+		t.Fatalf("Unexpected slots: wlslots=%d wlens=%d slots=%d", wlslots3, wlens3, len(h3))
+	}
+	var changed int
+	for i := range h1 {
+		if h1[i] != h3[i] {
+			changed++
+		}
+	}
+	if changed != 1 {
+		t.Errorf("Unexpected number of changed slots: %d", changed)
+	}
+	if !isPolicySlotChanged(h3, wlslots3, wlens3) {
+		t.Errorf("Slots should be changed")
+	}
+
+	postTest()
+}
+
+func TestExpiredRules(t *testing.T) {
+	preTest()
+
+	now := int64(1700000000)
+	policyCache.ruleMap = map[uint32]*share.CLUSPolicyRule{
+		1: {ID: 1, CfgType: share.UserCreated},
+		2: {ID: 2, CfgType: share.UserCreated, ExpireAt: now - 1},
+		3: {ID: 3, CfgType: share.UserCreated, ExpireAt: now + 60},
+		4: {ID: 4, CfgType: share.UserCreated, ExpireAt: now - 1, Disable: true},
+		5: {ID: 5, CfgType: share.GroundCfg, ExpireAt: now - 1},
+	}
+	localResPolicyCache.ruleMap = map[uint32]*share.CLUSResponseRule{
+		1: {ID: 1, CfgType: share.UserCreated, ExpireAt: now},
+		2: {ID: 2, CfgType: share.FederalCfg, ExpireAt: now},
+	}
+	profileGroups = map[string]*share.CLUSProcessProfile{
+		"g1": {Group: "g1", Process: []*share.CLUSProcessProfileEntry{
+			{Name: "sh", Path: "/bin/sh", CfgType: share.UserCreated},
+			{Name: "curl", Path: "/usr/bin/curl", CfgType: share.UserCreated, ExpireAt: now - 60},
+		}},
+		"g2": {Group: "g2", Process: []*share.CLUSProcessProfileEntry{
+			{Name: "curl", Path: "/usr/bin/curl", CfgType: share.UserCreated, ExpireAt: now + 60},
+		}},
+	}
+	defer func() {
+		policyCache.ruleMap = make(map[uint32]*share.CLUSPolicyRule)
+		localResPolicyCache.ruleMap = make(map[uint32]*share.CLUSResponseRule)
+		profileGroups = make(map[string]*share.CLUSProcessProfile)
+	}()
+
+	expired := getExpiredRules(now)
+	if len(expired.policyRules) != 1 || expired.policyRules[0] != 2 {
+		t.Errorf("Unexpected expired network rules: %v", expired.policyRules)
+	}
+	if len(expired.responseRules) != 1 || expired.responseRules[0] != 1 {
+		t.Errorf("Unexpected expired response rules: %v", expired.responseRules)
+	}
+	if len(expired.procGroups) != 1 || expired.procGroups[0] != "g1" {
+		t.Errorf("Unexpected groups with expired process rules: %v", expired.procGroups)
+	}
+
+	postTest()
+}
+
+func TestRuleHits(t *testing.T) {
+	preTest()
+
+	now := time.Now()
+	old := now.Add(-time.Hour * 24 * 60)
+	policyCache.ruleHeads = []*share.CLUSRuleHead{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+	policyCache.ruleMap = map[uint32]*share.CLUSPolicyRule{
+		1: {ID: 1, From: "g1", To: "g2", CfgType: share.UserCreated, CreatedAt: old},
+		2: {ID: 2, From: "g1", To: "g3", CfgType: share.UserCreated, CreatedAt: old},
+		3: {ID: 3, From: "g2", To: "g3", CfgType: share.UserCreated, CreatedAt: now},
+		4: {ID: 4, From: "g3", To: "g1", CfgType: share.UserCreated, CreatedAt: old, Disable: true},
+	}
+	defer func() {
+		policyCache.ruleHeads = make([]*share.CLUSRuleHead, 0)
+		policyCache.ruleMap = make(map[uint32]*share.CLUSPolicyRule)
+		policyRuleHits = make(map[uint32]*ruleHitCounter)
+	}()
+
+	countPolicyRuleHit(1, 3, now.Unix())
+	countPolicyRuleHit(1, 0, now.Unix()-10)
+	countPolicyRuleHit(2, 1, now.Unix()-3600*24*40)
+
+	acc := access.NewReaderAccessControl()
+	_, hits := cacher.GetRuleHits(api.RuleHitTypeNetwork, 0, acc)
+	if len(hits) != 4 {
+		t.Fatalf("Unexpected rule hits: %d", len(hits))
+	}
+	if hits[0].ID != 1 || hits[0].Hits != 4 || hits[0].LastHitTS != now.Unix() {
+		t.Errorf("Unexpected hits of rule 1: %+v", hits[0])
+	}
+	if hits[2].Hits != 0 || hits[2].LastHitTS != 0 {
+		t.Errorf("Unexpected hits of rule 3: %+v", hits[2])
+	}
+
+	// Rule 1 is hit recently, rule 3 is created recently and rule 4 is disabled
+	_, unused := cacher.GetRuleHits(api.RuleHitTypeNetwork, now.Add(-time.Hour*24*30).Unix(), acc)
+	if len(unused) != 1 || unused[0].ID != 2 {
+		t.Errorf("Unexpected unused rules: %+v", unused)
+	}
+
+	postTest()
+}
@@ -0,0 +1,168 @@
+package cache
+
+// The profiles written to the node profile store are also pushed to the enforcers of the node by grpc. The
+// profiles of a node are batched for a short time, compressed and sent with a sequence number. The enforcer
+// applies a batch only if it follows the last one; otherwise it answers with a resync and reloads the profiles
+// from the kv, and a new sequence is started. The kv store is still the source of truth, the push delivers the
+// changes in fewer calls and lets the enforcer skip the kv notifications that were already applied.
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/rpc"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/cluster"
+)
+
+const policyPushDelay = time.Millisecond * 500
+const policyPushBatchMax = 256
+
+type policyPushState struct {
+	epoch       uint64
+	seq         uint64
+	acked       uint64
+	unsupported bool // enforcer of the old version
+}
+
+var policyPushMutex sync.Mutex
+var policyPushQueue map[string][]*share.CLUSPolicyPushEntry = make(map[string][]*share.CLUSPolicyPushEntry) // key is host id
+var policyPushScheduled bool
+
+// Batches to an enforcer must be sent in order
+var policyPushSendMutex sync.Mutex
+var policyPushAgents map[string]*policyPushState = make(map[string]*policyPushState) // key is agent id
+
+// Called by the dispatcher with its lock held, so it only queues the entries
+func queuePolicyPush(node string, entries []*share.CLUSPolicyPushEntry) {
+	policyPushMutex.Lock()
+	defer policyPushMutex.Unlock()
+
+	policyPushQueue[node] = append(policyPushQueue[node], entries...)
+	if len(policyPushQueue[node]) >= policyPushBatchMax {
+		go flushPolicyPush()
+	} else if !policyPushScheduled {
+		policyPushScheduled = true
+		time.AfterFunc(policyPushDelay, flushPolicyPush)
+	}
+}
+
+func fetchPolicyPushQueue() map[string][]*share.CLUSPolicyPushEntry {
+	policyPushMutex.Lock()
+	defer policyPushMutex.Unlock()
+
+	queue := policyPushQueue
+	policyPushQueue = make(map[string][]*share.CLUSPolicyPushEntry)
+	policyPushScheduled = false
+	return queue
+}
+
+func getPolicyPushAgents(node string) []string {
+	cacheMutexRLock()
+	defer cacheMutexRUnlock()
+
+	agents := make([]string, 0)
+	if hc, ok := hostCacheMap[node]; ok {
+		for id := range hc.agents.Iter() {
+			if ac, ok := agentCacheMap[id.(string)]; ok && ac.state == api.StateOnline {
+				agents = append(agents, id.(string))
+			}
+		}
+	}
+	return agents
+}
+
+func flushPolicyPush() {
+	queue := fetchPolicyPushQueue()
+	if len(queue) == 0 {
+		return
+	}
+
+	nodeAgents := make(map[string][]string, len(queue))
+	for node := range queue {
+		nodeAgents[node] = getPolicyPushAgents(node)
+	}
+
+	policyPushSendMutex.Lock()
+	defer policyPushSendMutex.Unlock()
+
+	for node, entries := range queue {
+		agents := nodeAgents[node]
+		if len(agents) == 0 {
+			continue
+		}
+
+		compress, data, err := cluster.EncodePolicyPushEntries(entries)
+		if err != nil {
+			log.WithFields(log.Fields{"node": node, "error": err}).Error("Failed to encode policy push")
+			continue
+		}
+		for _, id := range agents {
+			pushPolicyBatch(id, &share.CLUSPolicyPushBatch{
+				CtrlID:      localDev.Ctrler.ID,
+				Compression: compress,
+				Count:       uint32(len(entries)),
+				Entries:     data,
+			})
+		}
+	}
+}
+
+func resetPolicyPushState(state *policyPushState) {
+	state.epoch = uint64(time.Now().UnixNano())
+	state.seq = 0
+	state.acked = 0
+}
+
+// Caller must hold policyPushSendMutex
+func pushPolicyBatch(agentID string, batch *share.CLUSPolicyPushBatch) {
+	state, ok := policyPushAgents[agentID]
+	if !ok {
+		state = &policyPushState{}
+		resetPolicyPushState(state)
+		policyPushAgents[agentID] = state
+	}
+	if state.unsupported {
+		return
+	}
+
+	state.seq++
+	batch.Epoch, batch.Seq = state.epoch, state.seq
+	ack, err := rpc.PushPolicyBatch(agentID, batch)
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.Unimplemented {
+			log.WithFields(log.Fields{"agent": agentID}).Info("Policy push is not supported")
+			state.unsupported = true
+		} else {
+			// The enforcer still receives the changes from the kv; start a new sequence for the next batch
+			log.WithFields(log.Fields{"agent": agentID, "error": err}).Error("Failed to push policy")
+			resetPolicyPushState(state)
+		}
+		return
+	}
+
+	if ack.Resync {
+		log.WithFields(log.Fields{"agent": agentID, "seq": batch.Seq, "acked": ack.Seq}).Info("Policy push resync")
+		resetPolicyPushState(state)
+	} else {
+		state.acked = ack.Seq
+	}
+}
+
+// Enforcers of a new controller lead start with a new sequence
+func resetPolicyPush() {
+	policyPushSendMutex.Lock()
+	policyPushAgents = make(map[string]*policyPushState)
+	policyPushSendMutex.Unlock()
+}
+
+func policyPushAgentLeave(agentID string) {
+	policyPushSendMutex.Lock()
+	delete(policyPushAgents, agentID)
+	policyPushSendMutex.Unlock()
+}
@@ -0,0 +1,233 @@
+package cache
+
+// #include "../../defs.h"
+import "C"
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// What-if simulation of the network rules. The recorded conversations are matched against the proposed rules
+// in order, the first matched rule decides the action, and a conversation that matches no rule is denied in
+// Protect mode. A conversation is enforced by the managed workloads at either end, so it is reported only if
+// one of them is in Protect mode, or assumed to be. The endpoints are matched by the groups that the workloads
+// are members of, and the other endpoints by the group names in the graph; the fqdn and address criteria of
+// the groups are not evaluated against the addresses of the conversations.
+
+type simPortRange struct {
+	ipproto uint8
+	low     uint16
+	high    uint16
+}
+
+type simRule struct {
+	rule  *share.CLUSPolicyRule
+	ports []simPortRange // nil: any port
+	apps  utils.Set      // nil: any application
+}
+
+type simFlowKey struct {
+	port        uint16
+	ipproto     uint8
+	application uint32
+}
+
+type simFlow struct {
+	bytes        uint64
+	sessions     uint32
+	last         uint32
+	policyAction uint8
+	policyID     uint32
+}
+
+func newSimRule(rule *share.CLUSPolicyRule) *simRule {
+	sr := &simRule{rule: rule}
+	if rule.Ports != "" && rule.Ports != api.PolicyPortAny {
+		sr.ports = make([]simPortRange, 0)
+		for _, p := range strings.Split(rule.Ports, ",") {
+			if ipproto, low, high, err := utils.ParsePortRangeLink(strings.TrimSpace(p)); err == nil {
+				sr.ports = append(sr.ports, simPortRange{ipproto: ipproto, low: low, high: high})
+			}
+		}
+	}
+	for _, app := range rule.Applications {
+		if app == C.DP_POLICY_APP_ANY {
+			sr.apps = nil
+			break
+		}
+		if sr.apps == nil {
+			sr.apps = utils.NewSet()
+		}
+		sr.apps.Add(app)
+	}
+	return sr
+}
+
+func (sr *simRule) match(from, to utils.Set, key *simFlowKey) bool {
+	if sr.rule.Disable || !from.Contains(sr.rule.From) || !to.Contains(sr.rule.To) {
+		return false
+	}
+	if sr.apps != nil && !sr.apps.Contains(key.application) {
+		return false
+	}
+	if sr.ports == nil {
+		return true
+	}
+	for _, p := range sr.ports {
+		if (p.ipproto == 0 || p.ipproto == key.ipproto) && key.port >= p.low && key.port <= p.high {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the id of the first matched rule and if the conversation is allowed
+func simulateFlow(rules []*simRule, from, to utils.Set, key *simFlowKey) (uint32, bool) {
+	for _, sr := range rules {
+		if sr.match(from, to, key) {
+			return sr.rule.ID, sr.rule.Action == share.PolicyActionAllow
+		}
+	}
+	return 0, false
+}
+
+// Groups of an endpoint in the graph. graphMutex and cacheMutex are owned by caller
+func getSimEndpointGroups(node string) utils.Set {
+	if wlc, ok := wlCacheMap.get(node); ok {
+		return wlc.groups
+	}
+	groups := utils.NewSet()
+	if a := wlGraph.Attr(node, attrLink, dummyEP); a != nil {
+		attr := a.(*nodeAttr)
+		if attr.addrgrp || attr.ipsvcgrp {
+			groups.Add(node)
+		} else if attr.external {
+			groups.Add(api.LearnedExternal)
+		} else if attr.host {
+			groups.Add(api.AllHostGroup)
+		}
+	}
+	return groups
+}
+
+// Return the workload if it enforces the rules in Protect mode. cacheMutex is owned by caller
+func getSimProtectWorkload(node string, protect utils.Set) *workloadCache {
+	wlc, ok := wlCacheMap.get(node)
+	if !ok {
+		return nil
+	}
+	if protect.Cardinality() == 0 || protect.Contains(wlc.learnedGroupName) {
+		return wlc
+	}
+	if mode, _ := getWorkloadEffectivePolicyMode(wlc); mode == share.PolicyModeEnforce {
+		return wlc
+	}
+	return nil
+}
+
+func getSimFlows(attr *graphAttr, since int64) map[simFlowKey]*simFlow {
+	flows := make(map[simFlowKey]*simFlow)
+	for key, entry := range attr.entries {
+		if int64(entry.last) < since {
+			continue
+		}
+		fk := simFlowKey{port: key.port, ipproto: key.ipproto, application: key.application}
+		f, ok := flows[fk]
+		if !ok {
+			f = &simFlow{}
+			flows[fk] = f
+		}
+		f.bytes += entry.bytes
+		f.sessions += entry.sessions
+		// The current action is the one of the latest entry
+		if entry.last >= f.last {
+			f.last = entry.last
+			f.policyAction = entry.policyAction
+			f.policyID = entry.policyID
+		}
+	}
+	return flows
+}
+
+// Replay the conversations seen since the time against the rules, and return the number of evaluated
+// conversations and the ones that would be denied when the groups are in Protect mode.
+func (m CacheMethod) SimulatePolicy(rules []*share.CLUSPolicyRule, groups []string, since int64,
+	acc *access.AccessControl) (int, []*api.RESTPolicySimulationFlow) {
+
+	simRules := make([]*simRule, 0, len(rules))
+	for _, rule := range rules {
+		simRules = append(simRules, newSimRule(rule))
+	}
+	protect := utils.NewSetFromSliceKind(groups)
+
+	var evaluated int
+	denied := make([]*api.RESTPolicySimulationFlow, 0)
+
+	graphMutexRLock()
+	defer graphMutexRUnlock()
+	cacheMutexRLock()
+	defer cacheMutexRUnlock()
+
+	for n := range wlGraph.All().Iter() {
+		src := n.(string)
+		outs := wlGraph.OutsByLink(src, graphLink)
+		if outs.Cardinality() == 0 {
+			continue
+		}
+		srcGroups := getSimEndpointGroups(src)
+		srcWl := getSimProtectWorkload(src, protect)
+		for o := range outs.Iter() {
+			dst := o.(string)
+			dstWl := getSimProtectWorkload(dst, protect)
+			if srcWl == nil && dstWl == nil {
+				continue
+			}
+			if (srcWl == nil || !acc.Authorize(srcWl.workload, nil)) && (dstWl == nil || !acc.Authorize(dstWl.workload, nil)) {
+				continue
+			}
+			a := wlGraph.Attr(src, graphLink, dst)
+			if a == nil {
+				continue
+			}
+
+			dstGroups := getSimEndpointGroups(dst)
+			for key, f := range getSimFlows(a.(*graphAttr), since) {
+				evaluated++
+				id, allowed := simulateFlow(simRules, srcGroups, dstGroups, &key)
+				if allowed {
+					continue
+				}
+				flow := &api.RESTPolicySimulationFlow{
+					From:          src,
+					To:            dst,
+					Port:          utils.GetPortLink(key.ipproto, key.port),
+					Bytes:         f.bytes,
+					Sessions:      f.sessions,
+					LastSeenTS:    int64(f.last),
+					CurrentAction: common.PolicyActionRESTString(f.policyAction),
+					CurrentRuleID: f.policyID,
+					RuleID:        id,
+				}
+				flow.Application, _ = common.AppNameMap[key.application]
+				denied = append(denied, flow)
+			}
+		}
+	}
+
+	sort.Slice(denied, func(i, j int) bool {
+		if denied[i].From != denied[j].From {
+			return denied[i].From < denied[j].From
+		} else if denied[i].To != denied[j].To {
+			return denied[i].To < denied[j].To
+		}
+		return denied[i].Port < denied[j].Port
+	})
+	return evaluated, denied
+}
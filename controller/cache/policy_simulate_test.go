@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/graph"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func TestSimulatePolicy(t *testing.T) {
+	preTest()
+
+	var cacher CacheMethod
+	accReadAll := access.NewReaderAccessControl()
+
+	for _, name := range []string{"nv.a", "nv.b"} {
+		groupCacheMap[name] = &groupCache{group: &share.CLUSGroup{Name: name, PolicyMode: share.PolicyModeLearn}}
+	}
+	c1 := share.CLUSWorkload{ID: "c1", Running: true}
+	c2 := share.CLUSWorkload{ID: "c2", Running: true}
+	wlCacheMap.set(c1.ID, &workloadCache{workload: &c1, learnedGroupName: "nv.a", groups: utils.NewSet("nv.a", api.AllContainerGroup)})
+	wlCacheMap.set(c2.ID, &workloadCache{workload: &c2, learnedGroupName: "nv.b", groups: utils.NewSet("nv.b", api.AllContainerGroup)})
+
+	wlGraph = graph.NewGraph()
+	wlGraph.AddLink("ex", attrLink, dummyEP, &nodeAttr{external: true})
+	wlGraph.AddLink("c1", graphLink, "c2", &graphAttr{entries: map[graphKey]*graphEntry{
+		graphKey{port: 80, ipproto: 6, cip: 1}:  &graphEntry{bytes: 100, sessions: 1, last: 100},
+		graphKey{port: 80, ipproto: 6, cip: 2}:  &graphEntry{bytes: 100, sessions: 1, last: 200},
+		graphKey{port: 443, ipproto: 6, cip: 1}: &graphEntry{bytes: 200, sessions: 2, last: 300},
+	}})
+	wlGraph.AddLink("c1", graphLink, "ex", &graphAttr{entries: map[graphKey]*graphEntry{
+		graphKey{port: 443, ipproto: 6}: &graphEntry{bytes: 100, sessions: 1, last: 100},
+	}})
+	wlGraph.AddLink("ex", graphLink, "c2", &graphAttr{entries: map[graphKey]*graphEntry{
+		graphKey{port: 22, ipproto: 6}: &graphEntry{bytes: 100, sessions: 1, last: 100},
+	}})
+
+	rules := []*share.CLUSPolicyRule{
+		&share.CLUSPolicyRule{ID: 1, From: "nv.a", To: "nv.b", Ports: "tcp/80", Action: share.PolicyActionAllow},
+		&share.CLUSPolicyRule{ID: 2, From: "nv.a", To: "nv.b", Ports: "tcp/443", Action: share.PolicyActionAllow, Disable: true},
+		&share.CLUSPolicyRule{ID: 3, From: api.AllContainerGroup, To: api.LearnedExternal, Ports: api.PolicyPortAny, Action: share.PolicyActionAllow},
+	}
+
+	// Only c1 is in Protect mode, the conversation from external to c2 is not enforced
+	evaluated, denied := cacher.SimulatePolicy(rules, []string{"nv.a"}, 0, accReadAll)
+	if evaluated != 3 || len(denied) != 1 {
+		t.Fatalf("Unexpected simulation: evaluated=%d, denied=%+v", evaluated, denied)
+	}
+	if f := denied[0]; f.From != "c1" || f.To != "c2" || f.Port != "tcp/443" || f.RuleID != 0 || f.Sessions != 2 {
+		t.Errorf("Unexpected denied flow: %+v", f)
+	}
+
+	// All groups are in Protect mode
+	rules = append(rules, &share.CLUSPolicyRule{ID: 4, From: api.LearnedExternal, To: "nv.b", Ports: "tcp/22", Action: share.PolicyActionDeny})
+	evaluated, denied = cacher.SimulatePolicy(rules, nil, 0, accReadAll)
+	if evaluated != 4 || len(denied) != 2 {
+		t.Fatalf("Unexpected simulation: evaluated=%d, denied=%+v", evaluated, denied)
+	}
+	if f := denied[1]; f.From != "ex" || f.To != "c2" || f.RuleID != 4 {
+		t.Errorf("Unexpected denied flow: %+v", f)
+	}
+
+	// Conversations not seen since the time are not evaluated
+	evaluated, denied = cacher.SimulatePolicy(rules, nil, 150, accReadAll)
+	if evaluated != 2 || len(denied) != 1 {
+		t.Errorf("Unexpected simulation: evaluated=%d, denied=%+v", evaluated, denied)
+	}
+
+	postTest()
+}
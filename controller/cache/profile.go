@@ -87,6 +87,13 @@ func handleProfileReport(gproc map[string][]*share.CLUSProcessProfileEntry) erro
 				}
 			}
 			for _, proc := range procs {
+				if len(proc.Syscalls) > 0 {
+					if ret, ok := common.MergeSyscalls(profile.Syscalls, proc.Syscalls); ok {
+						profile.Syscalls = ret
+						update = true
+					}
+					proc.Syscalls = nil // a group attribute
+				}
 				if proc.Action == share.PolicyActionLearn {
 					proc.Action = share.PolicyActionAllow
 					proc.Uuid = ruleid.NewUuid()
@@ -120,6 +127,10 @@ func handleProfileReport(gproc map[string][]*share.CLUSProcessProfileEntry) erro
 			}
 
 			for _, proc := range procs {
+				if len(proc.Syscalls) > 0 {
+					profile.Syscalls, _ = common.MergeSyscalls(profile.Syscalls, proc.Syscalls)
+					proc.Syscalls = nil // a group attribute
+				}
 				if proc.Action == share.PolicyActionLearn {
 					proc.Action = share.PolicyActionAllow
 					proc.Uuid = ruleid.NewUuid()
@@ -183,6 +194,20 @@ func (m CacheMethod) CreateProcessProfileTxn(txn *cluster.ClusterTransact, group
 	// txn.Apply() is called in caller
 }
 
+func zeroDrift2REST(zd *share.CLUSZeroDrift) *api.RESTZeroDrift {
+	if zd == nil {
+		return nil
+	}
+	r := &api.RESTZeroDrift{ReportOnly: zd.ReportOnly, Paths: zd.Paths, Names: zd.Names}
+	if r.Paths == nil {
+		r.Paths = make([]string, 0)
+	}
+	if r.Names == nil {
+		r.Names = make([]string, 0)
+	}
+	return r
+}
+
 func (m *CacheMethod) GetProcessProfile(group string, acc *access.AccessControl) (*api.RESTProcessProfile, error) {
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
@@ -198,15 +223,17 @@ func (m *CacheMethod) GetProcessProfile(group string, acc *access.AccessControl)
 			HashEnable:   p.HashEnable,
 			Mode:         p.Mode,
 			ProcessList:  make([]*api.RESTProcessProfileEntry, 0),
+			Syscalls:     p.Syscalls,
+			ZeroDrift:    zeroDrift2REST(p.ZeroDrift),
 		}
 
-		var lastName, lastPath string
+		var lastName, lastPath, lastArgs string
 		var lastCfgType share.TCfgType
 		for _, gproc := range p.Process {
 			// Sorted slices by Name(s), then Path(s)
 			// existing data, skip duplicate entry by comparing Name and Path
 			// still allow different Names but the same Path (could be wildcard or symbolic links like busybox)
-			if gproc.Name == lastName && gproc.Path == lastPath && gproc.CfgType == lastCfgType {
+			if gproc.Name == lastName && gproc.Path == lastPath && gproc.ArgsMatch+gproc.Args+gproc.Unit == lastArgs && gproc.CfgType == lastCfgType {
 				//	log.WithFields(log.Fields{"lastName": lastName, "lastPath": lastPath, "User": gproc.User}).Debug("PROC: ")
 				continue
 			}
@@ -219,8 +246,12 @@ func (m *CacheMethod) GetProcessProfile(group string, acc *access.AccessControl)
 				//Uid:    gproc.Uid,
 				Action:           gproc.Action,
 				AllowFileUpdate:  gproc.AllowFileUpdate,
+				Args:             gproc.Args,
+				ArgsMatch:        gproc.ArgsMatch,
+				Unit:             gproc.Unit,
 				CreatedTimeStamp: gproc.CreatedAt.Unix(),
 				UpdatedTimeStamp: gproc.UpdatedAt.Unix(),
+				ExpireTimeStamp:  gproc.ExpireAt,
 			}
 
 			proc.CfgType, _ = cfgTypeMapping[gproc.CfgType]
@@ -229,6 +260,7 @@ func (m *CacheMethod) GetProcessProfile(group string, acc *access.AccessControl)
 			// store for reference
 			lastName = proc.Name
 			lastPath = proc.Path
+			lastArgs = gproc.ArgsMatch + gproc.Args + gproc.Unit
 			lastCfgType = gproc.CfgType
 		}
 		return &resp, nil
@@ -275,6 +307,7 @@ func (m *CacheMethod) GetAllProcessProfile(scope string, acc *access.AccessContr
 				HashEnable:   p.HashEnable,
 				Mode:         p.Mode,
 				ProcessList:  make([]*api.RESTProcessProfileEntry, 0),
+				ZeroDrift:    zeroDrift2REST(p.ZeroDrift),
 			}
 			for _, gproc := range p.Process {
 				proc := &api.RESTProcessProfileEntry{
@@ -285,8 +318,12 @@ func (m *CacheMethod) GetAllProcessProfile(scope string, acc *access.AccessContr
 					//Uid:    gproc.Uid,
 					Action:           gproc.Action,
 					AllowFileUpdate:  gproc.AllowFileUpdate,
+					Args:             gproc.Args,
+					ArgsMatch:        gproc.ArgsMatch,
+					Unit:             gproc.Unit,
 					CreatedTimeStamp: gproc.CreatedAt.Unix(),
 					UpdatedTimeStamp: gproc.UpdatedAt.Unix(),
+					ExpireTimeStamp:  gproc.ExpireAt,
 				}
 
 				proc.CfgType, _ = cfgTypeMapping[gproc.CfgType]
@@ -350,6 +387,9 @@ func (m *CacheMethod) GetFedProcessProfileCache() []*share.CLUSProcessProfile {
 				UpdatedAt:       gproc.UpdatedAt,
 				Uuid:            gproc.Uuid,
 				AllowFileUpdate: gproc.AllowFileUpdate,
+				Args:            gproc.Args,
+				ArgsMatch:       gproc.ArgsMatch,
+				Unit:            gproc.Unit,
 				//Uid:     gproc.Uid,
 			}
 			resp.Process = append(resp.Process, proc)
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+)
+
+// keep the written process profiles
+type profileMockCluster struct {
+	kv.MockCluster
+	profiles map[string]*share.CLUSProcessProfile
+}
+
+func (m *profileMockCluster) PutProcessProfile(group string, pg *share.CLUSProcessProfile) error {
+	m.profiles[group] = pg
+	return nil
+}
+
+func TestProfileReportSyscalls(t *testing.T) {
+	preTest()
+
+	mockCluster := &profileMockCluster{profiles: make(map[string]*share.CLUSProcessProfile)}
+	mockCluster.Init(nil, nil)
+	clusHelper = mockCluster
+
+	// samples from two enforcers, one of the system calls is reported by both
+	gproc := map[string][]*share.CLUSProcessProfileEntry{
+		"nv.web": {
+			{Name: "nginx", Path: "/usr/sbin/nginx", Syscalls: []string{"epoll_wait"}},
+			{Name: "nginx", Path: "/usr/sbin/nginx", Syscalls: []string{"accept4"}},
+			{Name: "nginx", Path: "/usr/sbin/nginx", Syscalls: []string{"epoll_wait"}},
+		},
+	}
+	if err := handleProfileReport(gproc); err != nil {
+		t.Errorf("Failed to handle profile report: %v\n", err)
+	}
+
+	profile, ok := mockCluster.profiles["nv.web"]
+	if !ok {
+		t.Errorf("Profile is not updated\n")
+		postTest()
+		return
+	}
+	if expect := []string{"accept4", "epoll_wait"}; !reflect.DeepEqual(profile.Syscalls, expect) {
+		t.Errorf("Unexpected system calls: %+v, expect=%+v\n", profile.Syscalls, expect)
+	}
+	if len(profile.Process) != 2 { // the fixed content of the mock
+		t.Errorf("System call reports should not add processes: %+v\n", profile.Process)
+	}
+	for _, proc := range profile.Process {
+		if len(proc.Syscalls) != 0 {
+			t.Errorf("System calls should not be kept in the process entry: %+v\n", proc)
+		}
+	}
+
+	// nothing new, no update
+	delete(mockCluster.profiles, "nv.web")
+	gproc = map[string][]*share.CLUSProcessProfileEntry{
+		"nv.web": {
+			{Name: "nginx", Path: "/usr/sbin/nginx"},
+		},
+	}
+	handleProfileReport(gproc)
+	if _, ok := mockCluster.profiles["nv.web"]; ok {
+		t.Errorf("Profile should not be updated\n")
+	}
+
+	postTest()
+}
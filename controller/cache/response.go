@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -35,6 +37,8 @@ type actionDesc struct {
 	id       uint32
 	actions  []string
 	webhooks []string
+	whOpts   common.WebhookOptions
+	excepts  []share.CLUSQuarException
 }
 
 type responseActionFunc struct {
@@ -58,6 +62,8 @@ type resPolicyCacheType struct {
 	ruleMap      map[uint32]*share.CLUSResponseRule
 	ruleHeads    []*share.CLUSRuleHead
 	ruleOrderMap map[uint32]int
+	quarImages   []*share.CLUSFedQuarantineImage // only for fed policy
+	quarDigests  utils.Set
 }
 
 var localResPolicyCache resPolicyCacheType = resPolicyCacheType{
@@ -70,8 +76,14 @@ var fedResPolicyCache resPolicyCacheType = resPolicyCacheType{
 	ruleMap:      make(map[uint32]*share.CLUSResponseRule),
 	ruleHeads:    make([]*share.CLUSRuleHead, 0),
 	ruleOrderMap: make(map[uint32]int, 0),
+	quarImages:   make([]*share.CLUSFedQuarantineImage, 0),
+	quarDigests:  utils.NewSet(),
 }
 
+// images triggered fed-quarantine action but not in the fed quarantine list yet. key is normalized digest
+var pendingFedQuarImages map[string]*share.CLUSFedQuarantineImage = make(map[string]*share.CLUSFedQuarantineImage)
+var pendingFedQuarMutex sync.Mutex
+
 var logLevelMap map[string]int = map[string]int{
 	api.LogLevelDEBUG:   1,
 	api.LogLevelINFO:    2,
@@ -107,13 +119,15 @@ func selectResPolicyCache(policyName string) *resPolicyCacheType {
 
 func (m CacheMethod) ResponseRule2REST(rule *share.CLUSResponseRule) *api.RESTResponseRule {
 	restRule := &api.RESTResponseRule{
-		ID:      rule.ID,
-		Event:   rule.Event,
-		Comment: rule.Comment,
-		Group:   rule.Group,
-		Disable: rule.Disable,
+		ID:       rule.ID,
+		Event:    rule.Event,
+		Comment:  rule.Comment,
+		Group:    rule.Group,
+		Disable:  rule.Disable,
+		ExpireTS: rule.ExpireAt,
 	}
 	restRule.CfgType, _ = cfgTypeMapping[rule.CfgType]
+	restRule.ManagedBy = getFedManagedBy(rule.CfgType)
 	conditions := make([]share.CLUSEventCondition, len(rule.Conditions))
 	for i := 0; i < len(rule.Conditions); i++ {
 		conditions[i] = rule.Conditions[i]
@@ -130,6 +144,14 @@ func (m CacheMethod) ResponseRule2REST(rule *share.CLUSResponseRule) *api.RESTRe
 	} else {
 		restRule.Webhooks = rule.Webhooks
 	}
+	restRule.WebhookTemplate = rule.WebhookTemplate
+	restRule.WebhookSeverity = rule.WebhookSeverity
+	restRule.WebhookDedupKey = rule.WebhookDedupKey
+	if len(rule.QuarExceptions) == 0 {
+		restRule.QuarExceptions = make([]share.CLUSQuarException, 0)
+	} else {
+		restRule.QuarExceptions = rule.QuarExceptions
+	}
 	return restRule
 }
 
@@ -142,6 +164,11 @@ func responseRuleConfigUpdate(nType cluster.ClusterNotifyType, key string, value
 		return
 	}
 
+	if cfgType == share.CLUSResCfgQuarantine {
+		quarantineImagesUpdate(resPolicyCache, nType, value)
+		return
+	}
+
 	cacheMutexLock()
 	defer cacheMutexUnlock()
 
@@ -303,13 +330,178 @@ func lookup(desc *eventDesc) []actionDesc {
 				}
 			}
 			if len(rule.Conditions) == 0 || matchConditions(desc, rule.Conditions) {
-				ret = append(ret, actionDesc{id: rule.ID, actions: rule.Actions, webhooks: rule.Webhooks})
+				ret = append(ret, actionDesc{
+					id: rule.ID, actions: rule.Actions, webhooks: rule.Webhooks, excepts: rule.QuarExceptions,
+					whOpts: common.WebhookOptions{
+						Template: rule.WebhookTemplate, Severity: rule.WebhookSeverity, DedupKey: rule.WebhookDedupKey,
+					},
+				})
 			}
 		}
 	}
 	return ret
 }
 
+// Source of the threat, it is the peer of the threat target
+func threatSourceWorkload(rlog *api.Threat) string {
+	switch rlog.Target {
+	case api.TargetServer:
+		return rlog.ClientWL
+	case api.TargetClient:
+		return rlog.ServerWL
+	}
+	return ""
+}
+
+func quarantineWorkload(wlID, event string, ruleID uint32, excepts []share.CLUSQuarException) {
+	quarantineWorkloadWithReason(wlID, share.QuarantineReasonEvent(event, ruleID), excepts)
+}
+
+func quarantineWorkloadWithReason(wlID, reason string, excepts []share.CLUSQuarException) {
+	cacheMutexRLock()
+	wlc, ok := wlCacheMap.get(wlID)
+	if !ok {
+		log.WithFields(log.Fields{
+			"workload": wlID, "reason": reason,
+		}).Debug("Cannot find workload to quarantine")
+	} else if wlc.workload.ShareNetNS != "" {
+		if parent, ok := wlCacheMap.get(wlc.workload.ShareNetNS); ok {
+			wlc = parent
+		} else {
+			log.WithFields(log.Fields{
+				"container": wlID, "parent": wlc.workload.ShareNetNS,
+			}).Error("cannot find parent")
+			wlc = nil
+		}
+	}
+	cacheMutexRUnlock()
+
+	if wlc != nil {
+		if !wlc.workload.CapIntcp {
+			log.WithFields(log.Fields{
+				"workload": wlc.workload.ID, "reason": reason,
+			}).Debug("workload cannot be quarantined")
+		} else if wlc.workload.Quarantine {
+			log.WithFields(log.Fields{
+				"workload": wlc.workload.ID, "reason": reason,
+			}).Error("workload is already quarantined")
+		} else {
+			log.WithFields(log.Fields{
+				"wrokload": wlc.workload.ID, "reason": reason,
+			}).Debug("Need quarantine")
+
+			cconf, rev := clusHelper.GetWorkloadConfigRev(wlc.workload.HostID, wlc.workload.ID)
+			if cconf == nil {
+				cconf = &share.CLUSWorkloadConfig{Wire: share.WireDefault}
+			}
+			if !cconf.Quarantine {
+				cconf.Quarantine = true
+				cconf.QuarReason = reason
+				cconf.QuarExceptions = excepts
+				if err := clusHelper.PutWorkloadConfigRev(wlc.workload.HostID, wlc.workload.ID, cconf, rev); err != nil {
+					log.WithFields(log.Fields{"error": err, "rev": rev}).Error("")
+				}
+			}
+		}
+	}
+}
+
+func quarantineReasonFedImage(digest string) string {
+	return fmt.Sprintf("%s (image %s)", share.EventActionFedQuarantine, digest)
+}
+
+func quarantineImagesUpdate(resPolicyCache *resPolicyCacheType, nType cluster.ClusterNotifyType, value []byte) {
+	images := make([]*share.CLUSFedQuarantineImage, 0)
+	if nType != cluster.ClusterNotifyDelete {
+		json.Unmarshal(value, &images)
+	}
+	digests := utils.NewSet()
+	for _, img := range images {
+		digests.Add(utils.NormalizeImageDigest(img.Digest))
+	}
+
+	pendingFedQuarMutex.Lock()
+	for digest := range pendingFedQuarImages {
+		if digests.Contains(digest) {
+			delete(pendingFedQuarImages, digest)
+		}
+	}
+	pendingFedQuarMutex.Unlock()
+
+	wlDigests := make(map[string]string)
+	cacheMutexLock()
+	resPolicyCache.quarImages = images
+	resPolicyCache.quarDigests = digests
+	if isLeader() && digests.Cardinality() > 0 {
+		wlCacheMap.iter(func(id string, wlc *workloadCache) bool {
+			if wlc.workload != nil && wlc.workload.Running && !wlc.workload.Quarantine {
+				if digest := utils.NormalizeImageDigest(wlc.workload.ImageID); digests.Contains(digest) {
+					wlDigests[id] = digest
+				}
+			}
+			return true
+		})
+	}
+	cacheMutexUnlock()
+
+	for id, digest := range wlDigests {
+		quarantineWorkloadWithReason(id, quarantineReasonFedImage(digest), nil)
+	}
+}
+
+// Quarantine the started workload if its image is in the fed quarantine list
+func responseWorkloadStart(id string, param interface{}) {
+	if !isLeader() {
+		return
+	}
+
+	wlc := param.(*workloadCache)
+	digest := utils.NormalizeImageDigest(wlc.workload.ImageID)
+	cacheMutexRLock()
+	quarantine := digest != "" && fedResPolicyCache.quarDigests.Contains(digest)
+	cacheMutexRUnlock()
+	if quarantine {
+		quarantineWorkloadWithReason(id, quarantineReasonFedImage(digest), nil)
+	}
+}
+
+// The image is added to the fed quarantine list by rest package. It's removed from the pending list when
+// the fed quarantine list that contains it is synced from master cluster.
+func addPendingFedQuarantineImage(wlID, comment string) {
+	var img *share.CLUSFedQuarantineImage
+	cacheMutexRLock()
+	if wlc, ok := wlCacheMap.get(wlID); ok && wlc.workload != nil && wlc.workload.ImageID != "" {
+		if digest := utils.NormalizeImageDigest(wlc.workload.ImageID); !fedResPolicyCache.quarDigests.Contains(digest) {
+			img = &share.CLUSFedQuarantineImage{
+				Digest:    wlc.workload.ImageID,
+				Name:      wlc.workload.Image,
+				Comment:   comment,
+				CreatedAt: time.Now().UTC(),
+			}
+		}
+	}
+	cacheMutexRUnlock()
+
+	if img != nil {
+		pendingFedQuarMutex.Lock()
+		if _, ok := pendingFedQuarImages[utils.NormalizeImageDigest(img.Digest)]; !ok {
+			pendingFedQuarImages[utils.NormalizeImageDigest(img.Digest)] = img
+		}
+		pendingFedQuarMutex.Unlock()
+	}
+}
+
+func (m CacheMethod) GetPendingFedQuarantineImages() []*share.CLUSFedQuarantineImage {
+	pendingFedQuarMutex.Lock()
+	defer pendingFedQuarMutex.Unlock()
+
+	images := make([]*share.CLUSFedQuarantineImage, 0, len(pendingFedQuarImages))
+	for _, img := range pendingFedQuarImages {
+		images = append(images, img)
+	}
+	return images
+}
+
 func responseRuleLookup(desc *eventDesc) {
 
 	react, ok := responseFuncs[desc.event]
@@ -352,56 +544,17 @@ func responseRuleLookup(desc *eventDesc) {
 				react.webhookFunc(&actDesc, desc.arg)
 			}
 
-			if !desc.noQuar && action == share.EventActionQuarantine && isLeader() && strings.Index(desc.name, "AdmCtrl.") != 0 {
-				cacheMutexRLock()
-				wlc, ok := wlCacheMap[desc.id]
-				if !ok {
-					log.WithFields(log.Fields{
-						"workload": desc.id, "event": desc.event, "rule": id,
-					}).Debug("Cannot find workload to quarantine")
-				} else if wlc.workload.ShareNetNS != "" {
-					if parent, ok := wlCacheMap[wlc.workload.ShareNetNS]; ok {
-						wlc = parent
-					} else {
-						log.WithFields(log.Fields{
-							"container": desc.id, "parent": wlc.workload.ShareNetNS,
-						}).Error("cannot find parent")
-						wlc = nil
-					}
+			if !desc.noQuar && (action == share.EventActionQuarantine || action == share.EventActionFedQuarantine) &&
+				isLeader() && strings.Index(desc.name, "AdmCtrl.") != 0 {
+				quarantineWorkload(desc.id, desc.event, id, actDesc.excepts)
+				if action == share.EventActionFedQuarantine {
+					addPendingFedQuarantineImage(desc.id, share.QuarantineReasonEvent(desc.event, id))
 				}
-				cacheMutexRUnlock()
-
-				if wlc != nil {
-					if !wlc.workload.CapIntcp {
-						log.WithFields(log.Fields{
-							"workload": wlc.workload.ID, "event": desc.event, "rule": id,
-						}).Debug("workload cannot be quarantined")
-					} else if wlc.workload.Quarantine {
-						log.WithFields(log.Fields{
-							"workload": wlc.workload.ID, "event": desc.event, "rule": id,
-						}).Error("workload is already quarantined")
-					} else {
-						log.WithFields(log.Fields{
-							"wrokload": wlc.workload.ID, "event": desc.event, "rule": id,
-						}).Debug("Need quarantine")
-
-						var cconf share.CLUSWorkloadConfig
-						key := share.CLUSUniconfWorkloadKey(wlc.workload.HostID, wlc.workload.ID)
-						value, rev, _ := cluster.GetRev(key)
-						if value != nil {
-							json.Unmarshal(value, &cconf)
-						} else {
-							cconf.Wire = share.WireDefault
-						}
-						if !cconf.Quarantine {
-							cconf.Quarantine = true
-							cconf.QuarReason = share.QuarantineReasonEvent(desc.event, id)
-							value, _ = json.Marshal(&cconf)
-							if err := cluster.PutRev(key, value, rev); err != nil {
-								log.WithFields(log.Fields{"error": err, "rev": rev}).Error("")
-							}
-						}
-					}
+			}
+
+			if action == share.EventActionQuarantineSource && desc.event == share.EventThreat && isLeader() {
+				if src := threatSourceWorkload(desc.arg.(*api.Threat)); src != "" {
+					quarantineWorkload(src, desc.event, id, actDesc.excepts)
 				}
 			}
 		}
@@ -519,7 +672,7 @@ func (m CacheMethod) GetWorkloadResponseRules(policyName, id string, acc *access
 	ret := make([]*api.RESTResponseRule, 0)
 	cacheMutexRLock()
 	defer cacheMutexRUnlock()
-	if cache, ok := wlCacheMap[id]; ok {
+	if cache, ok := wlCacheMap.get(id); ok {
 		if !acc.Authorize(cache.workload, nil) {
 			return nil, common.ErrObjectAccessDenied
 		}
@@ -552,3 +705,12 @@ func (m CacheMethod) GetFedResponseRulesCache() (map[uint32]*share.CLUSResponseR
 
 	return resPolicyCache.ruleMap, resPolicyCache.ruleHeads
 }
+
+func (m CacheMethod) GetFedQuarantineImagesCache() []*share.CLUSFedQuarantineImage {
+	cacheMutexRLock()
+	defer cacheMutexRUnlock()
+
+	images := make([]*share.CLUSFedQuarantineImage, len(fedResPolicyCache.quarImages))
+	copy(images, fedResPolicyCache.quarImages)
+	return images
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"encoding/json"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/cluster"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func TestThreatQuarantineSource(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+
+	oldLeader := cacher.isLeader
+	cacher.isLeader = true
+	cctx.StartStopFedPingPollFunc = func(cmd, interval uint32, param1 interface{}) error { return nil }
+
+	wl1 := share.CLUSWorkload{ID: "wl1", Name: "c1", HostID: "h1", CapIntcp: true}
+	wl2 := share.CLUSWorkload{ID: "wl2", Name: "c2", HostID: "h2", CapIntcp: true}
+	wlCacheMap.set(wl1.ID, &workloadCache{workload: &wl1})
+	wlCacheMap.set(wl2.ID, &workloadCache{workload: &wl2})
+	ipWLMap["10.1.0.2"] = &workloadDigest{wlID: wl2.ID, alive: true, managed: true}
+	_, subnet, _ := net.ParseCIDR("10.1.0.0/16")
+	cachedInternalSubnets[subnet.String()] = share.CLUSSubnet{Subnet: *subnet, Scope: share.CLUSIPAddrScopeGlobal}
+
+	localResPolicyCache.ruleMap = map[uint32]*share.CLUSResponseRule{
+		1: {ID: 1, Event: share.EventThreat, Actions: []string{share.EventActionQuarantineSource}, CfgType: share.UserCreated},
+	}
+	localResPolicyCache.ruleHeads = []*share.CLUSRuleHead{{ID: 1, CfgType: share.UserCreated}}
+	defer func() {
+		cacher.isLeader = oldLeader
+		cctx.StartStopFedPingPollFunc = nil
+		wlCacheMap.remove(wl1.ID)
+		wlCacheMap.remove(wl2.ID)
+		delete(ipWLMap, "10.1.0.2")
+		delete(cachedInternalSubnets, subnet.String())
+		localResPolicyCache.ruleMap = make(map[uint32]*share.CLUSResponseRule)
+		localResPolicyCache.ruleHeads = make([]*share.CLUSRuleHead, 0)
+		setModifyIdx(syncCatgThreatIdx, 0)
+		curThrtIndex = 0
+		thrtMap = make(map[string]*api.Threat)
+	}()
+
+	var modifyIdx uint64
+	fireThreat := func(src string) {
+		thrts := []share.CLUSThreatLog{share.CLUSThreatLog{
+			ID: src, ThreatID: 1001, WorkloadID: wl1.ID, HostID: wl1.HostID, EtherType: syscall.ETH_P_IP,
+			IPProto: syscall.IPPROTO_TCP, SrcIP: net.ParseIP(src), DstIP: net.ParseIP("10.1.0.1"),
+			SrcPort: 40000, DstPort: 80, PktIngress: true, SessIngress: true,
+		}}
+		value, _ := json.Marshal(&thrts)
+		modifyIdx++
+		threatLogUpdate(cluster.ClusterNotifyAdd, share.CLUSThreatLogKey(wl1.HostID, src), utils.GzipBytes(value), modifyIdx)
+	}
+
+	// The source IP doesn't resolve to a workload: an unknown address in the
+	// internal subnet and an external address
+	for _, src := range []string{"10.1.0.9", "203.0.113.9"} {
+		fireThreat(src)
+		for _, wl := range []*share.CLUSWorkload{&wl1, &wl2} {
+			if cconf, _ := clusHelper.GetWorkloadConfigRev(wl.HostID, wl.ID); cconf != nil {
+				t.Errorf("Workload should not be quarantined by threat from %s: %s %+v", src, wl.ID, cconf)
+			}
+		}
+	}
+
+	// wl2 attacks wl1, the source is quarantined, not the reporting workload
+	fireThreat("10.1.0.2")
+	if cconf, _ := clusHelper.GetWorkloadConfigRev(wl1.HostID, wl1.ID); cconf != nil {
+		t.Errorf("Victim of the threat should not be quarantined: %+v", cconf)
+	}
+	cconf, _ := clusHelper.GetWorkloadConfigRev(wl2.HostID, wl2.ID)
+	if cconf == nil || !cconf.Quarantine || cconf.Wire != share.WireDefault {
+		t.Fatalf("Source of the threat is not quarantined: %+v", cconf)
+	}
+	if cconf.QuarReason != share.QuarantineReasonEvent(share.EventThreat, 1) {
+		t.Errorf("Unexpected quarantine reason: %s", cconf.QuarReason)
+	}
+
+	postTest()
+}
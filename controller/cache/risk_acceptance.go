@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/cluster"
+	scanUtils "github.com/neuvector/neuvector/share/scan"
+)
+
+// The accepted risks take effect in the default vulnerability and compliance profiles after they are approved,
+// until they expire.
+
+var raCacheMap map[string]*share.CLUSRiskAcceptance = make(map[string]*share.CLUSRiskAcceptance) // key => acceptance
+var raMutex sync.RWMutex
+var raExpireTimer *time.Timer
+
+func isRiskAcceptanceEffective(ra *share.CLUSRiskAcceptance, now time.Time) bool {
+	return ra.Status == share.RiskAcceptanceApproved && now.Before(ra.ExpireAt)
+}
+
+func riskAcceptanceConfigUpdate(nType cluster.ClusterNotifyType, key string, value []byte) {
+	log.WithFields(log.Fields{"type": cluster.ClusterNotifyName[nType], "key": key}).Debug()
+
+	var riskType string
+	switch nType {
+	case cluster.ClusterNotifyAdd, cluster.ClusterNotifyModify:
+		var ra share.CLUSRiskAcceptance
+		if err := json.Unmarshal(value, &ra); err != nil {
+			log.WithFields(log.Fields{"err": err}).Debug("Fail to decode")
+			return
+		}
+
+		raMutex.Lock()
+		raCacheMap[key] = &ra
+		raMutex.Unlock()
+		riskType = ra.Type
+	case cluster.ClusterNotifyDelete:
+		raMutex.Lock()
+		if ra, ok := raCacheMap[key]; ok {
+			riskType = ra.Type
+			delete(raCacheMap, key)
+		}
+		raMutex.Unlock()
+	}
+
+	if riskType == share.RiskAcceptanceVulnerability {
+		refreshVulnerabilityAcceptance()
+	}
+	scheduleRiskAcceptanceExpiry()
+}
+
+// Re-evaluate the acceptances when the nearest one expires
+func scheduleRiskAcceptanceExpiry() {
+	now := time.Now()
+	var next time.Time
+
+	raMutex.Lock()
+	defer raMutex.Unlock()
+	for _, ra := range raCacheMap {
+		if isRiskAcceptanceEffective(ra, now) && (next.IsZero() || ra.ExpireAt.Before(next)) {
+			next = ra.ExpireAt
+		}
+	}
+
+	if raExpireTimer != nil {
+		raExpireTimer.Stop()
+		raExpireTimer = nil
+	}
+	if !next.IsZero() {
+		raExpireTimer = time.AfterFunc(next.Sub(now)+time.Second, func() {
+			log.Info("Accepted risk expired")
+			refreshVulnerabilityAcceptance()
+			scheduleRiskAcceptanceExpiry()
+		})
+	}
+}
+
+// The approved vulnerabilities are added to the default vulnerability profile as the extra entries
+func acceptedVulnerabilityEntries() []api.RESTVulnerabilityProfileEntry {
+	now := time.Now()
+	entries := make([]api.RESTVulnerabilityProfileEntry, 0)
+
+	raMutex.RLock()
+	defer raMutex.RUnlock()
+	for _, ra := range raCacheMap {
+		if ra.Type == share.RiskAcceptanceVulnerability && isRiskAcceptanceEffective(ra, now) {
+			entries = append(entries, api.RESTVulnerabilityProfileEntry{
+				Name:    ra.Name,
+				Comment: ra.Justification,
+				Domains: ra.Domains,
+				Images:  ra.Images,
+			})
+		}
+	}
+	return entries
+}
+
+func makeVulnerabilityProfileFilter(rp *api.RESTVulnerabilityProfile) scanUtils.VPFInterface {
+	if rp.Name != share.DefaultVulnerabilityProfileName {
+		return scanUtils.MakeVulnerabilityProfileFilter(rp)
+	}
+
+	entries := acceptedVulnerabilityEntries()
+	if len(entries) == 0 {
+		return scanUtils.MakeVulnerabilityProfileFilter(rp)
+	}
+
+	frp := &api.RESTVulnerabilityProfile{
+		Name:    rp.Name,
+		Entries: make([]api.RESTVulnerabilityProfileEntry, 0, len(rp.Entries)+len(entries)),
+	}
+	frp.Entries = append(frp.Entries, rp.Entries...)
+	frp.Entries = append(frp.Entries, entries...)
+	return scanUtils.MakeVulnerabilityProfileFilter(frp)
+}
+
+// Rebuild the filter of the default vulnerability profile and re-filter the scan results
+func refreshVulnerabilityAcceptance() {
+	name := share.DefaultVulnerabilityProfileName
+
+	vpMutex.Lock()
+	c, ok := vpCacheMap[name]
+	if ok {
+		if c.updateCtx != nil && !errors.Is(c.updateCtx.Err(), context.Canceled) {
+			c.updateCancel()
+		}
+		vpCacheMap[name] = &vpCache{
+			profile: c.profile,
+			rp:      c.rp,
+			intf:    makeVulnerabilityProfileFilter(c.rp),
+		}
+	}
+	vpMutex.Unlock()
+
+	if ok && vulProfUpdateTimer != nil {
+		vulProfUpdateTimer.Reset(vulProfUpdateDelayIdle)
+	}
+}
+
+func riskDomainMatch(patterns []string, domain string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if strings.Contains(p, "*") {
+			if regexp.MustCompile("^" + strings.Replace(regexp.QuoteMeta(p), `\*`, ".*", -1) + "$").MatchString(domain) {
+				return true
+			}
+		} else if p == domain {
+			return true
+		}
+	}
+	return false
+}
+
+func (m CacheMethod) IsComplianceRiskAccepted(testNum, domain string) bool {
+	now := time.Now()
+
+	raMutex.RLock()
+	defer raMutex.RUnlock()
+	for _, ra := range raCacheMap {
+		if ra.Type == share.RiskAcceptanceCompliance && ra.Name == testNum && isRiskAcceptanceEffective(ra, now) &&
+			riskDomainMatch(ra.Domains, domain) {
+			return true
+		}
+	}
+	return false
+}
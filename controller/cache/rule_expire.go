@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share"
+)
+
+// Temporary rules, such as an allowance during a maintenance window, are given an expiration time. The lead
+// controller disables the expired network and response rules, and removes the expired process rules, which
+// cannot be disabled. The federal and CRD rules are not owned by the cluster and cannot expire.
+
+const ruleExpirePeriod = time.Duration(time.Minute * 1)
+
+type expiredRules struct {
+	policyRules   []uint32
+	responseRules []uint32
+	procGroups    []string
+}
+
+func isRuleExpired(expireAt int64, now int64) bool {
+	return expireAt > 0 && expireAt <= now
+}
+
+func isRuleExpirable(cfgType share.TCfgType) bool {
+	return cfgType != share.FederalCfg && cfgType != share.GroundCfg
+}
+
+// cacheMutex is owned by caller
+func getExpiredRules(now int64) *expiredRules {
+	expired := &expiredRules{}
+	for id, r := range policyCache.ruleMap {
+		if !r.Disable && isRuleExpirable(r.CfgType) && isRuleExpired(r.ExpireAt, now) {
+			expired.policyRules = append(expired.policyRules, id)
+		}
+	}
+	for id, r := range localResPolicyCache.ruleMap {
+		if !r.Disable && isRuleExpirable(r.CfgType) && isRuleExpired(r.ExpireAt, now) {
+			expired.responseRules = append(expired.responseRules, id)
+		}
+	}
+	for group, pg := range profileGroups {
+		for _, p := range pg.Process {
+			if isRuleExpirable(p.CfgType) && isRuleExpired(p.ExpireAt, now) {
+				expired.procGroups = append(expired.procGroups, group)
+				break
+			}
+		}
+	}
+	return expired
+}
+
+func logRuleExpireEvent(group, msg string) {
+	clog := share.CLUSEventLog{
+		Event:      share.CLUSEvPolicyRuleExpired,
+		GroupName:  group,
+		ReportedAt: time.Now().UTC(),
+		Msg:        msg,
+	}
+	cctx.EvQueue.Append(&clog)
+}
+
+// The rules are read again from the kv store under the policy lock, as the cache can be out of date.
+func expireRules() {
+	now := time.Now().Unix()
+
+	cacheMutexRLock()
+	expired := getExpiredRules(now)
+	cacheMutexRUnlock()
+
+	if len(expired.policyRules) == 0 && len(expired.responseRules) == 0 && len(expired.procGroups) == 0 {
+		return
+	}
+
+	lock, err := clusHelper.AcquireLock(share.CLUSLockPolicyKey, policyClusterLockWait)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to acquire cluster lock")
+		return
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	for _, id := range expired.policyRules {
+		if r, _ := clusHelper.GetPolicyRule(id); r != nil && !r.Disable && isRuleExpired(r.ExpireAt, now) {
+			r.Disable = true
+			r.LastModAt = time.Now().UTC()
+			if err := clusHelper.PutPolicyRule(r); err != nil {
+				log.WithFields(log.Fields{"id": id, "error": err}).Error("Failed to disable expired rule")
+				continue
+			}
+			log.WithFields(log.Fields{"id": id}).Info("Disable expired network rule")
+			logRuleExpireEvent("", fmt.Sprintf("Network rule %d from %s to %s is expired and disabled.", id, r.From, r.To))
+		}
+	}
+
+	for _, id := range expired.responseRules {
+		if r, _ := clusHelper.GetResponseRule(share.DefaultPolicyName, id); r != nil && !r.Disable && isRuleExpired(r.ExpireAt, now) {
+			r.Disable = true
+			if err := clusHelper.PutResponseRule(share.DefaultPolicyName, r); err != nil {
+				log.WithFields(log.Fields{"id": id, "error": err}).Error("Failed to disable expired response rule")
+				continue
+			}
+			log.WithFields(log.Fields{"id": id}).Info("Disable expired response rule")
+			logRuleExpireEvent(r.Group, fmt.Sprintf("Response rule %d of %s event is expired and disabled.", id, r.Event))
+		}
+	}
+
+	for _, group := range expired.procGroups {
+		pg := clusHelper.GetProcessProfile(group)
+		if pg == nil {
+			continue
+		}
+
+		list := make([]*share.CLUSProcessProfileEntry, 0, len(pg.Process))
+		removed := make([]*share.CLUSProcessProfileEntry, 0)
+		for _, p := range pg.Process {
+			if isRuleExpirable(p.CfgType) && isRuleExpired(p.ExpireAt, now) {
+				removed = append(removed, p)
+			} else {
+				list = append(list, p)
+			}
+		}
+		if len(removed) == 0 {
+			continue
+		}
+
+		pg.Process = list
+		if err := clusHelper.PutProcessProfile(group, pg); err != nil {
+			log.WithFields(log.Fields{"group": group, "error": err}).Error("Failed to remove expired process rules")
+			continue
+		}
+		for _, p := range removed {
+			log.WithFields(log.Fields{"group": group, "name": p.Name, "path": p.Path}).Info("Remove expired process rule")
+			logRuleExpireEvent(group, fmt.Sprintf("Process rule %s:%s of group %s is expired and removed.", p.Name, p.Path, group))
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	admission "github.com/neuvector/neuvector/controller/nvk8sapi/nvvalidatewebhookcfg"
+	"github.com/neuvector/neuvector/share"
+)
+
+// Hits of the rules are counted from the reports that every controller receives: network rules from the
+// connections reported by enforcers, process rules from the incidents and admission rules from the audits.
+// An allowed process is not reported, so only the process rules that deny are counted. The counters
+// are kept in memory and start from zero when the controller starts.
+
+type ruleHitCounter struct {
+	hits      uint64
+	lastHitAt int64
+}
+
+var ruleHitMutex sync.RWMutex
+var ruleHitSince int64 = time.Now().Unix()
+var policyRuleHits map[uint32]*ruleHitCounter = make(map[uint32]*ruleHitCounter)
+var processRuleHits map[string]*ruleHitCounter = make(map[string]*ruleHitCounter) // key is rule uuid
+var admissionRuleHits map[uint32]*ruleHitCounter = make(map[uint32]*ruleHitCounter)
+
+func countRuleHit(c *ruleHitCounter, count uint64, at int64) {
+	if count == 0 {
+		count = 1
+	}
+	c.hits += count
+	if at > c.lastHitAt {
+		c.lastHitAt = at
+	}
+}
+
+func countPolicyRuleHit(id uint32, count uint64, at int64) {
+	ruleHitMutex.Lock()
+	defer ruleHitMutex.Unlock()
+	c, ok := policyRuleHits[id]
+	if !ok {
+		c = &ruleHitCounter{}
+		policyRuleHits[id] = c
+	}
+	countRuleHit(c, count, at)
+}
+
+func countProcessRuleHit(uuid string, count uint64, at int64) {
+	ruleHitMutex.Lock()
+	defer ruleHitMutex.Unlock()
+	c, ok := processRuleHits[uuid]
+	if !ok {
+		c = &ruleHitCounter{}
+		processRuleHits[uuid] = c
+	}
+	countRuleHit(c, count, at)
+}
+
+func countAdmissionRuleHit(id uint32, count uint64, at int64) {
+	ruleHitMutex.Lock()
+	defer ruleHitMutex.Unlock()
+	c, ok := admissionRuleHits[id]
+	if !ok {
+		c = &ruleHitCounter{}
+		admissionRuleHits[id] = c
+	}
+	countRuleHit(c, count, at)
+}
+
+// A rule is unused if it is not hit since idleSince, and it is not created after that
+func isRuleUnused(hit *api.RESTRuleHit, idleSince int64) bool {
+	if idleSince == 0 {
+		return false
+	}
+	return hit.LastHitTS < idleSince && hit.CreatedTS < idleSince
+}
+
+func fillRuleHit(hit *api.RESTRuleHit, c *ruleHitCounter) {
+	if c != nil {
+		hit.Hits = c.hits
+		hit.LastHitTS = c.lastHitAt
+	}
+}
+
+// Return the hits of the rules of the type, all types if ruleType is empty. When idleSince is not 0, only the
+// enabled rules that are not hit since then are returned.
+func (m CacheMethod) GetRuleHits(ruleType string, idleSince int64, acc *access.AccessControl) (int64, []*api.RESTRuleHit) {
+	hits := make([]*api.RESTRuleHit, 0)
+	add := func(hit *api.RESTRuleHit) {
+		if idleSince == 0 || isRuleUnused(hit, idleSince) {
+			hits = append(hits, hit)
+		}
+	}
+
+	cacheMutexRLock()
+	defer cacheMutexRUnlock()
+	ruleHitMutex.RLock()
+	defer ruleHitMutex.RUnlock()
+
+	if ruleType == "" || ruleType == api.RuleHitTypeNetwork {
+		for _, head := range policyCache.ruleHeads {
+			rule, ok := policyCache.ruleMap[head.ID]
+			if !ok || (idleSince != 0 && rule.Disable) || !acc.Authorize(rule, getAccessObjectFuncNoLock) {
+				continue
+			}
+			hit := &api.RESTRuleHit{
+				Type:      api.RuleHitTypeNetwork,
+				ID:        rule.ID,
+				From:      rule.From,
+				To:        rule.To,
+				Comment:   rule.Comment,
+				CreatedTS: rule.CreatedAt.Unix(),
+			}
+			hit.CfgType, _ = cfgTypeMapping[rule.CfgType]
+			fillRuleHit(hit, policyRuleHits[rule.ID])
+			add(hit)
+		}
+	}
+
+	if ruleType == "" || ruleType == api.RuleHitTypeProcess {
+		for _, pg := range profileGroups {
+			if !acc.Authorize(pg, getAccessObjectFuncNoLock) {
+				continue
+			}
+			for _, p := range pg.Process {
+				if p.Action != share.PolicyActionDeny {
+					continue
+				}
+				hit := &api.RESTRuleHit{
+					Type:      api.RuleHitTypeProcess,
+					UUID:      p.Uuid,
+					Group:     pg.Group,
+					Name:      p.Name,
+					Path:      p.Path,
+					CreatedTS: p.CreatedAt.Unix(),
+				}
+				hit.CfgType, _ = cfgTypeMapping[p.CfgType]
+				fillRuleHit(hit, processRuleHits[p.Uuid])
+				add(hit)
+			}
+		}
+	}
+
+	if (ruleType == "" || ruleType == api.RuleHitTypeAdmission) && acc.HasGlobalPermissions(share.PERM_ADM_CONTROL, 0) {
+		for _, ruleType := range []string{share.FedAdmCtrlExceptRulesType, share.FedAdmCtrlDenyRulesType, api.ValidatingExceptRuleType, api.ValidatingDenyRuleType} {
+			admPolicyCache := selectAdminPolicyCache(admission.NvAdmValidateType, ruleType)
+			if admPolicyCache == nil {
+				continue
+			}
+			for _, head := range admPolicyCache.RuleHeads {
+				rule, ok := admPolicyCache.RuleMap[head.ID]
+				if !ok || rule.Critical || (idleSince != 0 && rule.Disable) || !acc.Authorize(rule, nil) {
+					continue
+				}
+				hit := &api.RESTRuleHit{
+					Type:    api.RuleHitTypeAdmission,
+					ID:      rule.ID,
+					Comment: rule.Comment,
+				}
+				hit.CfgType, _ = cfgTypeMapping[rule.CfgType]
+				fillRuleHit(hit, admissionRuleHits[rule.ID])
+				add(hit)
+			}
+		}
+	}
+
+	return ruleHitSince, hits
+}
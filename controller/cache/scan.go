@@ -25,6 +25,7 @@ import (
 	"github.com/neuvector/neuvector/share"
 	"github.com/neuvector/neuvector/share/cluster"
 	"github.com/neuvector/neuvector/share/global"
+	"github.com/neuvector/neuvector/share/otel"
 	scanUtils "github.com/neuvector/neuvector/share/scan"
 	"github.com/neuvector/neuvector/share/utils"
 )
@@ -149,6 +150,19 @@ func (t *scanTask) rpcScanRunning(scanner string, info *scanInfo) {
 	var result *share.ScanResult
 	var err error
 
+	_, span := otel.StartSpan(context.Background(), "scan.run", otel.SpanKindInternal)
+	span.SetAttribute("scan.id", t.id)
+	span.SetAttribute("scan.type", info.objType.String())
+	span.SetAttribute("scanner", scanner)
+	defer func() {
+		if result != nil {
+			span.SetAttribute("scan.result", result.Error.String())
+		}
+		span.SetError(err)
+		span.End()
+		otel.RecordDuration("neuvector.scan.duration", span.Duration(), "scan.type", info.objType.String())
+	}()
+
 	if info.objType == share.ScanObjectType_CONTAINER {
 		result, err = rpc.ScanRunning(scanner, info.agentId, t.id, share.ScanObjectType_CONTAINER, scanReqTimeout)
 	} else if info.objType == share.ScanObjectType_HOST {
@@ -762,8 +776,12 @@ func scanConfigUpdate(nType cluster.ClusterNotifyType, key string, value []byte)
 		} else if !cfg.AutoScan && scanCfg.AutoScan {
 			disableAutoScan()
 		}
+		scanCfg.RegistryMirrors = cfg.RegistryMirrors
+		scan.SetRegistryMirrors(cfg.RegistryMirrors)
 	case cluster.ClusterNotifyDelete:
 		disableAutoScan()
+		scanCfg.RegistryMirrors = nil
+		scan.SetRegistryMirrors(nil)
 	}
 }
 
@@ -1107,12 +1125,13 @@ func ScanUpdateHandler(nType cluster.ClusterNotifyType, key string, value []byte
 func scanLicenseUpdate(id string, param interface{}) {
 
 	// Cache lock must be within scan lock, so get the map first
-	wls := make(map[string]struct{ a, d string }, len(wlCacheMap))
+	wls := make(map[string]struct{ a, d string }, wlCacheMap.size())
 	hosts := make(map[string]string, len(agentCacheMap))
 	cacheMutexRLock()
-	for id, cache := range wlCacheMap {
+	wlCacheMap.iter(func(id string, cache *workloadCache) bool {
 		wls[id] = struct{ a, d string }{a: cache.workload.AgentID, d: cache.workload.Domain}
-	}
+		return true
+	})
 	for id, cache := range agentCacheMap {
 		hosts[cache.agent.HostID] = id
 	}
@@ -1258,6 +1277,7 @@ func scanInit() {
 	acc := access.NewReaderAccessControl()
 	cfg, _ := clusHelper.GetScanConfigRev(acc)
 	scanCfg = *cfg
+	scan.SetRegistryMirrors(scanCfg.RegistryMirrors)
 
 	key := share.CLUSVulnerabilityProfileKey(share.DefaultVulnerabilityProfileName)
 	if value, err := cluster.Get(key); err == nil {
@@ -1280,6 +1300,10 @@ func (m CacheMethod) GetScanConfig(acc *access.AccessControl) (*api.RESTScanConf
 	} else {
 		cfg = &api.RESTScanConfig{AutoScan: false}
 	}
+	cfg.RegistryMirrors = make([]*api.RESTRegistryMirror, len(scanCfg.RegistryMirrors))
+	for i, m := range scanCfg.RegistryMirrors {
+		cfg.RegistryMirrors[i] = &api.RESTRegistryMirror{Mirror: m.Mirror, Upstream: m.Upstream}
+	}
 
 	return cfg, nil
 }
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+)
+
+var smtpMutex sync.RWMutex
+var smtpCfg share.CLUSSMTPConfig
+var smtpNotifier *common.SMTPNotifier
+
+// The notifier is kept in every controller, only the lead sends the emails. It's restarted only if the config changes.
+func configSMTP(cfg *share.CLUSSMTPConfig) {
+	smtpMutex.Lock()
+	defer smtpMutex.Unlock()
+
+	if smtpNotifier != nil && reflect.DeepEqual(&smtpCfg, cfg) {
+		return
+	}
+	if smtpNotifier != nil {
+		// The pending digests are sent in the background
+		go smtpNotifier.Close()
+		smtpNotifier = nil
+	}
+	smtpCfg = *cfg
+	if !cfg.Enable {
+		return
+	}
+
+	notifier, err := common.NewSMTPNotifier(cfg)
+	if err != nil {
+		log.WithFields(log.Fields{"server": cfg.Server, "error": err}).Error("Failed to create SMTP notifier")
+		return
+	}
+	log.WithFields(log.Fields{"server": cfg.Server, "digest": cfg.Digest}).Info("SMTP notifier applied")
+	smtpNotifier = notifier
+}
+
+func smtpNotify(cat string, ts int64, rlog interface{}) {
+	smtpMutex.RLock()
+	defer smtpMutex.RUnlock()
+
+	if smtpNotifier == nil {
+		return
+	}
+
+	var level, cluster, title string
+	switch l := rlog.(type) {
+	case *api.Event:
+		level, cluster, title = l.Level, l.ClusterName, l.Name
+	case *api.Threat:
+		level, cluster, title = l.Level, l.ClusterName, l.Name
+	case *api.Violation:
+		level, cluster, title = l.Level, l.ClusterName, l.Name
+	case *api.Incident:
+		level, cluster, title = l.Level, l.ClusterName, l.Name
+	case *api.Audit:
+		level, cluster, title = l.Level, l.ClusterName, l.Name
+	case *api.ConfigAudit:
+		level, cluster, title = api.LogLevelINFO, systemConfigCache.ClusterName, fmt.Sprintf("%s %s", l.RESTMethod, l.RESTRequest)
+	default:
+		return
+	}
+	smtpNotifier.Notify(rlog, level, cat, cluster, title, time.Unix(ts, 0))
+}
@@ -68,6 +68,8 @@ const (
 	syncCatgIncident = "incident"
 	syncCatgAudit    = "audit"
 	syncCatgActivity = "activity"
+
+	syncCatgConfigAudit = "config_audit"
 )
 
 var syncCatgArray = []syncCatgInfo{
@@ -77,6 +79,7 @@ var syncCatgArray = []syncCatgInfo{
 	{syncCatgIncident, syncIncidentTx, syncIncidentRx},
 	{syncCatgAudit, syncAuditTx, syncAuditRx},
 	{syncCatgActivity, syncActivityTx, syncActivityRx},
+	{syncCatgConfigAudit, syncConfigAuditTx, syncConfigAuditRx},
 }
 
 type ctrlResyncFilter struct {
@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"encoding/json"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/controller/rpc"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/cluster"
+)
+
+var threatSigBundle share.CLUSThreatSignatureBundle
+var threatSigMap map[uint32]*share.CLUSThreatSignature = make(map[uint32]*share.CLUSThreatSignature)
+
+var threatSigSeverityLevel = map[string]string{
+	api.SeverityInfo:     api.LogLevelINFO,
+	api.SeverityLow:      api.LogLevelNOTICE,
+	api.SeverityMedium:   api.LogLevelWARNING,
+	api.SeverityHigh:     api.LogLevelERR,
+	api.SeverityCritical: api.LogLevelCRIT,
+}
+
+func isThreatSigID(id uint32) bool {
+	return id >= uint32(api.MinThreatSigID) && id < uint32(api.MaxThreatSigID)
+}
+
+// Return the name, severity and log level of a threat signature
+func getThreatSigNameSeverity(id uint32) (string, string, string) {
+	cacheMutexRLock()
+	defer cacheMutexRUnlock()
+
+	if sig, ok := threatSigMap[id]; ok {
+		return sig.Name, sig.Severity, threatSigSeverityLevel[sig.Severity]
+	}
+	return common.ThreatName(id), "", ""
+}
+
+// Only the enabled signatures are pushed to the enforcers. Called with cache lock held.
+func threatSigArray() *share.CLUSThreatSignatureArray {
+	arr := &share.CLUSThreatSignatureArray{
+		Version:     threatSigBundle.Version,
+		Signatures:  make([]*share.CLUSThreatSignatureEntry, 0, len(threatSigBundle.Signatures)),
+		MiningPools: threatSigBundle.MiningPools,
+	}
+	for _, sig := range threatSigBundle.Signatures {
+		if sig.Disable {
+			continue
+		}
+		entry := &share.CLUSThreatSignatureEntry{
+			ID:       sig.ID,
+			Name:     sig.Name,
+			Action:   sig.Action,
+			Patterns: make([]*share.CLUSThreatSignaturePattern, len(sig.Patterns)),
+		}
+		for i, pt := range sig.Patterns {
+			entry.Patterns[i] = &share.CLUSThreatSignaturePattern{Op: pt.Op, Value: pt.Value, Context: pt.Context}
+		}
+		arr.Signatures = append(arr.Signatures, entry)
+	}
+	return arr
+}
+
+func pushThreatSignatures(agents []string, arr *share.CLUSThreatSignatureArray) {
+	for _, id := range agents {
+		if err := rpc.SetThreatSignatures(id, arr); err != nil {
+			log.WithFields(log.Fields{"agent": id, "error": err}).Error("Failed to push threat signatures")
+		}
+	}
+}
+
+func threatSigConfigUpdate(nType cluster.ClusterNotifyType, key string, value []byte) {
+	log.WithFields(log.Fields{"type": cluster.ClusterNotifyName[nType], "key": key}).Debug()
+
+	var bundle share.CLUSThreatSignatureBundle
+	switch nType {
+	case cluster.ClusterNotifyAdd, cluster.ClusterNotifyModify:
+		if err := json.Unmarshal(value, &bundle); err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Fail to decode")
+			return
+		}
+	}
+
+	agents := make([]string, 0)
+
+	cacheMutexLock()
+	threatSigBundle = bundle
+	threatSigMap = make(map[uint32]*share.CLUSThreatSignature, len(bundle.Signatures))
+	for _, sig := range bundle.Signatures {
+		threatSigMap[sig.ID] = sig
+	}
+	arr := threatSigArray()
+	if isLeader() {
+		for id, cache := range agentCacheMap {
+			if cache.state == api.StateOnline {
+				agents = append(agents, id)
+			}
+		}
+	}
+	cacheMutexUnlock()
+
+	// an empty list is pushed too, so the enforcers remove the deleted signatures
+	if len(agents) > 0 {
+		go pushThreatSignatures(agents, arr)
+	}
+}
+
+func threatSigAgentOnline(id string, param interface{}) {
+	if !isLeader() {
+		return
+	}
+
+	cacheMutexRLock()
+	arr := threatSigArray()
+	cacheMutexRUnlock()
+
+	if len(arr.Signatures) > 0 || len(arr.MiningPools) > 0 {
+		go pushThreatSignatures([]string{id}, arr)
+	}
+}
+
+func threatSig2REST(sig *share.CLUSThreatSignature) *api.RESTThreatSignature {
+	rsig := &api.RESTThreatSignature{
+		ID:       sig.ID,
+		Name:     sig.Name,
+		Comment:  sig.Comment,
+		Severity: sig.Severity,
+		Action:   sig.Action,
+		Patterns: make([]api.RESTWafCriteriaEntry, len(sig.Patterns)),
+		Enable:   !sig.Disable,
+	}
+	for i, pt := range sig.Patterns {
+		rsig.Patterns[i] = api.RESTWafCriteriaEntry{Key: pt.Key, Value: pt.Value, Op: pt.Op, Context: pt.Context}
+	}
+	return rsig
+}
+
+func (m *CacheMethod) GetThreatSignatures(acc *access.AccessControl) (*api.RESTThreatSignatureData, error) {
+	cacheMutexRLock()
+	defer cacheMutexRUnlock()
+
+	if !acc.Authorize(&threatSigBundle, nil) {
+		return nil, common.ErrObjectAccessDenied
+	}
+
+	data := &api.RESTThreatSignatureData{
+		Version:     threatSigBundle.Version,
+		Signatures:  make([]*api.RESTThreatSignature, 0, len(threatSigBundle.Signatures)),
+		MiningPools: threatSigBundle.MiningPools,
+	}
+	if data.MiningPools == nil {
+		data.MiningPools = make([]string, 0)
+	}
+	if !threatSigBundle.ImportedAt.IsZero() {
+		data.ImportedAt = api.RESTTimeString(threatSigBundle.ImportedAt)
+	}
+	for _, sig := range threatSigBundle.Signatures {
+		data.Signatures = append(data.Signatures, threatSig2REST(sig))
+	}
+	return data, nil
+}
@@ -92,11 +92,12 @@ func getUsageReport() *share.CLUSSystemUsageReport {
 	for _, cache := range hostCacheMap {
 		r.CPUCores += int(cache.host.CPUs)
 	}
-	for _, cache := range wlCacheMap {
+	wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
 		if cache.workload.Running && cache.workload.ShareNetNS == "" {
 			r.RunningPods++
 		}
-	}
+		return true
+	})
 	for _, cache := range groupCacheMap {
 		if cache.group.PolicyMode == share.PolicyModeEvaluate {
 			r.MonitorGroups++
@@ -42,6 +42,10 @@ func vulnerabilityProfile2REST(cvp *share.CLUSVulnerabilityProfile) *api.RESTVul
 			Days:    ce.Days,
 			Domains: ce.Domains,
 			Images:  ce.Images,
+			CfgType: api.CfgTypeUserCreated,
+		}
+		if ce.CfgType == share.GroundCfg {
+			rvp.Entries[i].CfgType = api.CfgTypeGround
 		}
 		i++
 	}
@@ -52,7 +56,12 @@ func vulnerabilityProfile2REST(cvp *share.CLUSVulnerabilityProfile) *api.RESTVul
 func vulnerabilityConfigUpdate(nType cluster.ClusterNotifyType, key string, value []byte) {
 	log.WithFields(log.Fields{"type": cluster.ClusterNotifyName[nType], "key": key}).Debug()
 
-	if share.CLUSVulnerabilityKey2Type(key) != "profile" {
+	switch share.CLUSVulnerabilityKey2Type(key) {
+	case "profile":
+	case "acceptance":
+		riskAcceptanceConfigUpdate(nType, key, value)
+		return
+	default:
 		return
 	}
 
@@ -70,7 +79,7 @@ func vulnerabilityConfigUpdate(nType cluster.ClusterNotifyType, key string, valu
 			profile: &cvp,
 			rp:      vulnerabilityProfile2REST(&cvp),
 		}
-		cache.intf = scanUtils.MakeVulnerabilityProfileFilter(cache.rp)
+		cache.intf = makeVulnerabilityProfileFilter(cache.rp)
 
 		vpMutex.Lock()
 		if c, ok := vpCacheMap[name]; ok {
@@ -283,7 +283,7 @@ func assocWafWl2PolicyIds(grp string, senset utils.Set, outside_wl2sensor map[st
 	if grpcache, ok := groupCacheMap[grp]; ok {
 		for _, m := range grpcache.members.ToSlice() {
 			wlid := m.(string)
-			if wlcache, ok := wlCacheMap[wlid]; ok && wlcache.workload.HasDatapath {
+			if wlcache, ok := wlCacheMap.get(wlid); ok && wlcache.workload.HasDatapath {
 				inside_grps = inside_grps.Union(wlcache.groups)
 			}
 		}
@@ -322,7 +322,7 @@ func assocWafWl2PolicyIds(grp string, senset utils.Set, outside_wl2sensor map[st
 		for _, m := range grpcache.members.ToSlice() {
 			wlid := m.(string)
 			//only include wl that has datapath to save memory and cpu
-			if wlcache, exist := wlCacheMap[wlid]; exist && !wlcache.workload.HasDatapath {
+			if wlcache, exist := wlCacheMap.get(wlid); exist && !wlcache.workload.HasDatapath {
 				continue
 			}
 			if rids, ok := wl2policies[wlid]; !ok {
@@ -339,7 +339,7 @@ func assocWafWl2PolicyIds(grp string, senset utils.Set, outside_wl2sensor map[st
 			for _, om := range ogrpcache.members.ToSlice() {
 				owlid := om.(string)
 				//only include wl that has datapath to save memory and cpu
-				if wlcache, exist := wlCacheMap[owlid]; exist && !wlcache.workload.HasDatapath {
+				if wlcache, exist := wlCacheMap.get(owlid); exist && !wlcache.workload.HasDatapath {
 					continue
 				}
 				if orids, ok := outside_wl2policies[owlid]; !ok {
@@ -383,7 +383,7 @@ func assocWafWl2Sensors(grp string, senset utils.Set, wl2sensors map[string]map[
 		for _, m := range grpcache.members.ToSlice() {
 			wlid := m.(string)
 			//only include wl that has datapath to save memory and cpu
-			if wlcache, exist := wlCacheMap[wlid]; exist && !wlcache.workload.HasDatapath {
+			if wlcache, exist := wlCacheMap.get(wlid); exist && !wlcache.workload.HasDatapath {
 				continue
 			}
 			if sam, ok := wl2sensors[wlid]; !ok {
@@ -573,7 +573,7 @@ func getWafWlRules(cgdrs *share.CLUSWorkloadDlpRules, wl2rules map[string]map[st
 			RuleIds:       make([]uint32, 0),
 			RuleType:      ruletype,
 		}
-		if wlcache, ok := wlCacheMap[wlid]; ok {
+		if wlcache, ok := wlCacheMap.get(wlid); ok {
 			wlrule.PolicyMode, _ = getWorkloadEffectivePolicyMode(wlcache)
 		} else {
 			wlrule.PolicyMode = ""
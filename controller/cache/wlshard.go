@@ -0,0 +1,151 @@
+package cache
+
+// Workload cache of large clusters. Go maps never release their buckets when entries are deleted, so a map that
+// once held all workloads of a busy cluster keeps the memory after the pods are gone. Workloads are split into
+// shards by id, and a shard is rebuilt when most of its entries are deleted, so the memory is released in small
+// steps without copying the whole cache. Access is protected by cacheMutex as the other cache maps.
+// The strings repeated in many workloads are interned, so each workload doesn't keep its own copy. The interned
+// strings are rebuilt from the cached workloads periodically, so the strings of the removed workloads are released.
+
+import (
+	"hash/fnv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+const wlCacheShardCount = 64
+
+// A shard is rebuilt when its size drops below 1/wlShardCompactRatio of the peak
+const wlShardCompactRatio = 4
+const wlShardCompactMin = 64
+
+// Image, host and label strings are shared by many workloads
+const wlStringInternMax = 200000
+
+var wlStrings *utils.Interner = utils.NewInterner(wlStringInternMax)
+
+const wlStringRebuildPeriod = time.Minute * 30
+
+type workloadCacheShard struct {
+	wls  map[string]*workloadCache
+	peak int
+}
+
+type workloadCacheMap struct {
+	shards [wlCacheShardCount]workloadCacheShard
+	count  int
+}
+
+func newWorkloadCacheMap() *workloadCacheMap {
+	m := &workloadCacheMap{}
+	for i := range m.shards {
+		m.shards[i].wls = make(map[string]*workloadCache)
+	}
+	return m
+}
+
+func (m *workloadCacheMap) shard(id string) *workloadCacheShard {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return &m.shards[h.Sum32()%wlCacheShardCount]
+}
+
+func (m *workloadCacheMap) get(id string) (*workloadCache, bool) {
+	cache, ok := m.shard(id).wls[id]
+	return cache, ok
+}
+
+func (m *workloadCacheMap) set(id string, cache *workloadCache) {
+	s := m.shard(id)
+	if _, ok := s.wls[id]; !ok {
+		m.count++
+	}
+	s.wls[id] = cache
+	if len(s.wls) > s.peak {
+		s.peak = len(s.wls)
+	}
+}
+
+func (m *workloadCacheMap) remove(id string) {
+	s := m.shard(id)
+	if _, ok := s.wls[id]; !ok {
+		return
+	}
+	delete(s.wls, id)
+	m.count--
+
+	if s.peak >= wlShardCompactMin && len(s.wls) < s.peak/wlShardCompactRatio {
+		wls := make(map[string]*workloadCache, len(s.wls))
+		for k, v := range s.wls {
+			wls[k] = v
+		}
+		s.wls = wls
+		s.peak = len(wls)
+	}
+}
+
+func (m *workloadCacheMap) size() int {
+	return m.count
+}
+
+// iter calls fn for each workload until fn returns false. Workloads must not be added or removed in fn.
+func (m *workloadCacheMap) iter(fn func(id string, cache *workloadCache) bool) {
+	for i := range m.shards {
+		for id, cache := range m.shards[i].wls {
+			if !fn(id, cache) {
+				return
+			}
+		}
+	}
+}
+
+func internWorkload(wl *share.CLUSWorkload) {
+	wl.AgentID = wlStrings.Intern(wl.AgentID)
+	wl.HostName = wlStrings.Intern(wl.HostName)
+	wl.HostID = wlStrings.Intern(wl.HostID)
+	wl.Image = wlStrings.Intern(wl.Image)
+	wl.ImageID = wlStrings.Intern(wl.ImageID)
+	wl.NetworkMode = wlStrings.Intern(wl.NetworkMode)
+	wl.Service = wlStrings.Intern(wl.Service)
+	wl.Domain = wlStrings.Intern(wl.Domain)
+	wl.Author = wlStrings.Intern(wl.Author)
+	wl.PlatformRole = wlStrings.Intern(wl.PlatformRole)
+	if len(wl.Labels) > 0 {
+		labels := make(map[string]string, len(wl.Labels))
+		for k, v := range wl.Labels {
+			labels[wlStrings.Intern(k)] = wlStrings.Intern(v)
+		}
+		wl.Labels = labels
+	}
+}
+
+func rebuildWorkloadStrings() {
+	cacheMutexRLock()
+	released := wlStrings.Rebuild(func(keep func(s string)) {
+		wlCacheMap.iter(func(_ string, cache *workloadCache) bool {
+			wl := cache.workload
+			keep(wl.AgentID)
+			keep(wl.HostName)
+			keep(wl.HostID)
+			keep(wl.Image)
+			keep(wl.ImageID)
+			keep(wl.NetworkMode)
+			keep(wl.Service)
+			keep(wl.Domain)
+			keep(wl.Author)
+			keep(wl.PlatformRole)
+			for k, v := range wl.Labels {
+				keep(k)
+				keep(v)
+			}
+			return true
+		})
+	})
+	cacheMutexRUnlock()
+
+	log.WithFields(log.Fields{"released": released, "size": wlStrings.Size()}).Debug()
+}
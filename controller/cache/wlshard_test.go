@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func TestWorkloadCacheMap(t *testing.T) {
+	m := newWorkloadCacheMap()
+	for i := 0; i < 10000; i++ {
+		id := fmt.Sprintf("wl-%d", i)
+		m.set(id, &workloadCache{workload: &share.CLUSWorkload{ID: id}})
+	}
+	m.set("wl-0", &workloadCache{workload: &share.CLUSWorkload{ID: "wl-0", Name: "new"}})
+	if m.size() != 10000 {
+		t.Errorf("Unexpected size: %d", m.size())
+	}
+	if c, ok := m.get("wl-0"); !ok || c.workload.Name != "new" {
+		t.Errorf("Workload is not replaced: %+v", c)
+	}
+
+	for i := 0; i < 9990; i++ {
+		m.remove(fmt.Sprintf("wl-%d", i))
+	}
+	m.remove("wl-0")
+	if m.size() != 10 {
+		t.Errorf("Unexpected size after removal: %d", m.size())
+	}
+	for i := range m.shards {
+		if m.shards[i].peak >= wlShardCompactMin {
+			t.Errorf("Shard %d is not compacted: peak=%d", i, m.shards[i].peak)
+		}
+	}
+
+	var count int
+	m.iter(func(id string, cache *workloadCache) bool {
+		if id != cache.workload.ID {
+			t.Errorf("Unexpected workload: %s %s", id, cache.workload.ID)
+		}
+		count++
+		return true
+	})
+	if count != 10 {
+		t.Errorf("Unexpected iterated count: %d", count)
+	}
+
+	count = 0
+	m.iter(func(id string, cache *workloadCache) bool {
+		count++
+		return count < 3
+	})
+	if count != 3 {
+		t.Errorf("Iteration should be stopped: %d", count)
+	}
+}
+
+func TestInternWorkload(t *testing.T) {
+	wl1 := share.CLUSWorkload{ID: "1", Image: string([]byte("nginx:1.19")), Labels: map[string]string{"app": "web"}}
+	wl2 := share.CLUSWorkload{ID: "2", Image: string([]byte("nginx:1.19")), Labels: map[string]string{"app": "web"}}
+	internWorkload(&wl1)
+	internWorkload(&wl2)
+	if wl1.Image != wl2.Image || wl2.Labels["app"] != "web" {
+		t.Errorf("Unexpected workload: %+v", wl2)
+	}
+}
+
+func TestRebuildWorkloadStrings(t *testing.T) {
+	oldWls, oldStrings := wlCacheMap, wlStrings
+	wlCacheMap = newWorkloadCacheMap()
+	wlStrings = utils.NewInterner(wlStringInternMax)
+	defer func() {
+		wlCacheMap, wlStrings = oldWls, oldStrings
+	}()
+
+	wl1 := share.CLUSWorkload{ID: "1", Image: "nginx:1.19", Labels: map[string]string{"app": "web"}}
+	wl2 := share.CLUSWorkload{ID: "2", Image: "redis:6", Labels: map[string]string{"app": "db"}}
+	internWorkload(&wl1)
+	internWorkload(&wl2)
+	wlCacheMap.set(wl1.ID, &workloadCache{workload: &wl1})
+	wlCacheMap.set(wl2.ID, &workloadCache{workload: &wl2})
+	if wlStrings.Size() != 5 {
+		t.Errorf("Unexpected interned strings: %d", wlStrings.Size())
+	}
+
+	// Strings only used by the removed workload are released
+	wlCacheMap.remove(wl2.ID)
+	rebuildWorkloadStrings()
+	if wlStrings.Size() != 3 {
+		t.Errorf("Strings of the removed workload are not released: %d", wlStrings.Size())
+	}
+}
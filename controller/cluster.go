@@ -27,6 +27,9 @@ var ClusterConnected bool = false
 var selfRejoin bool = false
 var clusterFailed bool = false
 var leadFailTime time.Time
+var kvQuorumGen uint32
+
+const cacheResyncTimeout = time.Duration(time.Second * 30)
 
 const recoveryThreshold = time.Duration(time.Second * 6)
 const clusterCheckInterval time.Duration = time.Second * 2
@@ -101,6 +104,9 @@ func leadChangeHandler(newLead, oldLead string) {
 		clusterFailed = true
 		Ctrler.Leader = false
 		cluster.PauseAllWatchers(true)
+		// REST serves the cached state in read-only mode until the cluster is recovered
+		atomic.AddUint32(&kvQuorumGen, 1)
+		rest.KvQuorumNotify(true)
 
 		cache.LeadChangeNotify(false, "")
 		cache.ScannerChangeNotify(false)
@@ -147,6 +153,7 @@ func leadChangeHandler(newLead, oldLead string) {
 			}
 			leadFailTime = time.Time{}
 			cluster.ResumeAllWatchers()
+			go leaveKvReadOnlyMode(atomic.AddUint32(&kvQuorumGen, 1))
 		}
 		clusterFailed = false
 
@@ -162,6 +169,33 @@ func leadChangeHandler(newLead, oldLead string) {
 	}
 }
 
+// REST stays in read-only mode until the cache is re-synced with the changes made while the cluster was
+// failed. The self controller key is written after the watchers are resumed, the cache has all the changes
+// before it when its update is received.
+func leaveKvReadOnlyMode(gen uint32) {
+	key := share.CLUSControllerKey(Host.ID, Ctrler.ID)
+	for atomic.LoadUint32(&kvQuorumGen) == gen {
+		synced := cache.SelfControllerUpdateNotify()
+		value, _ := json.Marshal(Ctrler)
+		if err := cluster.Put(key, value); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("")
+			time.Sleep(clusterCheckInterval)
+			continue
+		}
+
+		select {
+		case <-synced:
+			// the cluster can fail again when waiting
+			if atomic.LoadUint32(&kvQuorumGen) == gen {
+				rest.KvQuorumNotify(false)
+			}
+			return
+		case <-time.After(cacheResyncTimeout):
+			log.Warn("Cache is not re-synced, retry")
+		}
+	}
+}
+
 func ctlrMemberUpdateHandler(nType cluster.ClusterNotifyType, memberAddr string, member string) {
 	log.WithFields(log.Fields{
 		"type": cluster.ClusterNotifyName[nType], "member": memberAddr,
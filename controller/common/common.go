@@ -55,6 +55,17 @@ type WorkloadRisk struct {
 	SetidBenchValue  []byte
 }
 
+// Security posture of the cluster. Counters are the logs received since the controller started.
+type SecurityMetrics struct {
+	WorkloadVuls map[string]map[string]int // namespace -> severity -> vulnerabilities of running containers
+	HostVuls     map[string]int            // severity -> vulnerabilities
+	PolicyModes  map[string]int            // policy mode -> running pods
+	Violations   uint64
+	Threats      uint64
+	Incidents    uint64
+	AdmDenials   uint64
+}
+
 type RPCEndpoint struct {
 	ID            string
 	Leader        bool
@@ -117,6 +128,8 @@ var DefaultSystemConfig = share.CLUSSystemConfig{
 	NetServicePolicyMode: share.PolicyModeLearn,
 	DisableNetPolicy:     false,
 	DetectUnmanagedWl:    false,
+	HostNetPolicyStatus:  false,
+	HostNetPolicyMode:    share.PolicyModeLearn,
 }
 
 func ActionString(action uint8) string {
@@ -204,39 +217,42 @@ const WafPrefix string = "WAF."
 
 // Threat attributes are separated into two places. Eventually they will be generated from a single source
 type LogThreatInfo struct {
-	Name string
+	Name       string
+	Techniques []string // MITRE ATT&CK techniques
 }
 
 var LogThreatMap = map[uint32]LogThreatInfo{
-	C.THRT_ID_SYN_FLOOD:         {"TCP.SYN.Flood"},
-	C.THRT_ID_ICMP_FLOOD:        {"ICMP.Flood"},
-	C.THRT_ID_IP_SRC_SESSION:    {"Source.IP.Session.Limit"},
-	C.THRT_ID_BAD_PACKET:        {"Invalid.Packet.Format"},
-	C.THRT_ID_IP_TEARDROP:       {"IP.Fragment.Teardrop"},
-	C.THRT_ID_TCP_SYN_DATA:      {"TCP.SYN.With.Data"},
-	C.THRT_ID_TCP_SPLIT_HDSHK:   {"TCP.Split.Handshake"},
-	C.THRT_ID_TCP_NODATA:        {"TCP.No.Client.Data"},
-	C.THRT_ID_TCP_SMALL_WINDOW:  {"TCP.Small.Window"},
-	C.THRT_ID_TCP_SMALL_MSS:     {"TCP.SACK.DDoS.With.Small.MSS"},
-	C.THRT_ID_PING_DEATH:        {"Ping.Death"},
-	C.THRT_ID_DNS_LOOP_PTR:      {"DNS.Loop.Pointer"},
-	C.THRT_ID_SSH_VER_1:         {"SSH.Version.1"},
-	C.THRT_ID_SSL_HEARTBLEED:    {"SSL.Heartbleed"},
-	C.THRT_ID_SSL_CIPHER_OVF:    {"SSL.Cipher.Overflow"},
-	C.THRT_ID_SSL_VER_2OR3:      {"SSL.Version.2or3"},
-	C.THRT_ID_SSL_TLS_1DOT0:     {"SSL.TLS1.0"},
-	C.THRT_ID_HTTP_NEG_LEN:      {"HTTP.Negative.Body.Length"},
-	C.THRT_ID_HTTP_SMUGGLING:    {"HTTP.Request.Smuggling"},
-	C.THRT_ID_HTTP_SLOWLORIS:    {"HTTP.Request.Slowloris"},
-	C.THRT_ID_DNS_OVERFLOW:      {"DNS.Stack.Overflow"},
-	C.THRT_ID_MYSQL_ACCESS_DENY: {"MySQL.Access.Deny"},
-	C.THRT_ID_DNS_ZONE_TRANSFER: {"DNS.Zone.Transfer"},
-	C.THRT_ID_ICMP_TUNNELING:    {"ICMP.Tunneling"},
-	C.THRT_ID_DNS_TYPE_NULL:     {"DNS.Type.Null"},
-	C.THRT_ID_SQL_INJECTION:     {"SQL.Injection"},
-	C.THRT_ID_APACHE_STRUTS_RCE: {"Apache.Struts.Remote.Code.Execution"},
-	C.THRT_ID_DNS_TUNNELING:     {"DNS.Tunneling"},
-	C.THRT_ID_K8S_EXTIP_MITM:    {"K8S.externalIPs.MitM"},
+	C.THRT_ID_SYN_FLOOD:         {"TCP.SYN.Flood", []string{share.MitreTechNetworkDoS}},
+	C.THRT_ID_ICMP_FLOOD:        {"ICMP.Flood", []string{share.MitreTechNetworkDoS}},
+	C.THRT_ID_IP_SRC_SESSION:    {"Source.IP.Session.Limit", []string{share.MitreTechEndpointDoS}},
+	C.THRT_ID_PORT_SCAN:         {"TCP.Port.Scan", []string{share.MitreTechNetworkServiceDisc}},
+	C.THRT_ID_BAD_PACKET:        {"Invalid.Packet.Format", []string{share.MitreTechEndpointDoS}},
+	C.THRT_ID_IP_TEARDROP:       {"IP.Fragment.Teardrop", []string{share.MitreTechEndpointDoS}},
+	C.THRT_ID_TCP_SYN_DATA:      {"TCP.SYN.With.Data", []string{share.MitreTechBoundaryBridging}},
+	C.THRT_ID_TCP_SPLIT_HDSHK:   {"TCP.Split.Handshake", []string{share.MitreTechBoundaryBridging}},
+	C.THRT_ID_TCP_NODATA:        {"TCP.No.Client.Data", []string{share.MitreTechEndpointDoS}},
+	C.THRT_ID_TCP_SMALL_WINDOW:  {"TCP.Small.Window", []string{share.MitreTechEndpointDoS}},
+	C.THRT_ID_TCP_SMALL_MSS:     {"TCP.SACK.DDoS.With.Small.MSS", []string{share.MitreTechEndpointDoS}},
+	C.THRT_ID_PING_DEATH:        {"Ping.Death", []string{share.MitreTechEndpointDoS}},
+	C.THRT_ID_DNS_LOOP_PTR:      {"DNS.Loop.Pointer", []string{share.MitreTechExploitPublicApp}},
+	C.THRT_ID_SSH_VER_1:         {"SSH.Version.1", []string{share.MitreTechAdversaryInTheMiddle}},
+	C.THRT_ID_SSL_HEARTBLEED:    {"SSL.Heartbleed", []string{share.MitreTechExploitPublicApp}},
+	C.THRT_ID_SSL_CIPHER_OVF:    {"SSL.Cipher.Overflow", []string{share.MitreTechExploitPublicApp}},
+	C.THRT_ID_SSL_VER_2OR3:      {"SSL.Version.2or3", []string{share.MitreTechAdversaryInTheMiddle}},
+	C.THRT_ID_SSL_TLS_1DOT0:     {"SSL.TLS1.0", []string{share.MitreTechAdversaryInTheMiddle}},
+	C.THRT_ID_HTTP_NEG_LEN:      {"HTTP.Negative.Body.Length", []string{share.MitreTechExploitPublicApp}},
+	C.THRT_ID_HTTP_SMUGGLING:    {"HTTP.Request.Smuggling", []string{share.MitreTechExploitPublicApp}},
+	C.THRT_ID_HTTP_SLOWLORIS:    {"HTTP.Request.Slowloris", []string{share.MitreTechEndpointDoS}},
+	C.THRT_ID_DNS_OVERFLOW:      {"DNS.Stack.Overflow", []string{share.MitreTechExploitPublicApp}},
+	C.THRT_ID_MYSQL_ACCESS_DENY: {"MySQL.Access.Deny", []string{share.MitreTechBruteForce}},
+	C.THRT_ID_DNS_ZONE_TRANSFER: {"DNS.Zone.Transfer", []string{share.MitreTechGatherDNS}},
+	C.THRT_ID_ICMP_TUNNELING:    {"ICMP.Tunneling", []string{share.MitreTechNonAppLayerProtocol}},
+	C.THRT_ID_DNS_TYPE_NULL:     {"DNS.Type.Null", []string{share.MitreTechDNSProtocol}},
+	C.THRT_ID_SQL_INJECTION:     {"SQL.Injection", []string{share.MitreTechExploitPublicApp}},
+	C.THRT_ID_APACHE_STRUTS_RCE: {"Apache.Struts.Remote.Code.Execution", []string{share.MitreTechExploitPublicApp}},
+	C.THRT_ID_DNS_TUNNELING:     {"DNS.Tunneling", []string{share.MitreTechDNSProtocol}},
+	C.THRT_ID_K8S_EXTIP_MITM:    {"K8S.externalIPs.MitM", []string{share.MitreTechAdversaryInTheMiddle}},
+	C.THRT_ID_CRYPTO_MINING:     {"Crypto.Mining.Stratum", []string{share.MitreTechResourceHijacking}},
 }
 
 func ThreatName(id uint32) string {
@@ -324,6 +340,22 @@ var LogEventMap = map[share.TLogEvent]LogEventInfo{
 	share.CLUSEvGroupAutoPromote:            {api.EventNameGroupAutoPromote, api.EventCatGroup, api.LogLevelINFO},
 	share.CLUSEvAuthDefAdminPwdUnchanged:    {api.EventNameAuthDefAdminPwdUnchanged, api.EventCatAuth, api.LogLevelWARNING},
 	share.CLUSEvScannerAutoScaleDisabled:    {api.EventNameScannerAutoScaleDisabled, api.EventCatConfig, api.LogLevelNOTICE},
+	share.CLUSEvCrdDriftRepaired:            {api.EventNameCrdDriftRepaired, api.EventCatCrd, api.LogLevelWARNING},
+	share.CLUSEvAuthAccountRecovered:        {api.EventNameAuthAccountRecovered, api.EventCatAuth, api.LogLevelWARNING},
+	share.CLUSEvConfigBackup:                {api.EventNameConfigBackup, api.EventCatConfig, api.LogLevelNOTICE},
+	share.CLUSEvConfigBackupFail:            {api.EventNameConfigBackupFail, api.EventCatConfig, api.LogLevelERR},
+	share.CLUSEvConfigRestore:               {api.EventNameConfigRestore, api.EventCatConfig, api.LogLevelNOTICE},
+	share.CLUSEvKvStoreSizeHigh:             {api.EventNameKvStoreSizeHigh, api.EventCatController, api.LogLevelWARNING},
+	share.CLUSEvKvKeyRotated:                {api.EventNameKvKeyRotated, api.EventCatConfig, api.LogLevelNOTICE},
+	share.CLUSEvReportGenerated:             {api.EventNameReportGenerated, api.EventCatConfig, api.LogLevelINFO},
+	share.CLUSEvReportGenerateFail:          {api.EventNameReportGenerateFail, api.EventCatConfig, api.LogLevelERR},
+	share.CLUSEvRiskAcceptanceProposed:      {api.EventNameRiskAcceptanceProposed, api.EventCatConfig, api.LogLevelNOTICE},
+	share.CLUSEvRiskAcceptanceApproved:      {api.EventNameRiskAcceptanceApproved, api.EventCatConfig, api.LogLevelNOTICE},
+	share.CLUSEvRiskAcceptanceRejected:      {api.EventNameRiskAcceptanceRejected, api.EventCatConfig, api.LogLevelNOTICE},
+	share.CLUSEvPolicyRuleExpired:           {api.EventNamePolicyRuleExpired, api.EventCatConfig, api.LogLevelNOTICE},
+	share.CLUSEvPolicyChangeSubmitted:       {api.EventNamePolicyChangeSubmitted, api.EventCatConfig, api.LogLevelNOTICE},
+	share.CLUSEvPolicyChangeApproved:        {api.EventNamePolicyChangeApproved, api.EventCatConfig, api.LogLevelNOTICE},
+	share.CLUSEvPolicyChangeRejected:        {api.EventNamePolicyChangeRejected, api.EventCatConfig, api.LogLevelNOTICE},
 }
 
 type LogIncidentInfo struct {
@@ -344,6 +376,12 @@ var LogIncidentMap = map[share.TLogIncident]LogIncidentInfo{
 	share.CLUSIncidContainerTunnel:              {api.EventNameContainerTunnelDetected, api.LogLevelWARNING},
 	share.CLUSIncidContainerProcessViolation:    {api.EventNameProcessProfileViolation, api.LogLevelWARNING},
 	share.CLUSIncidHostProcessViolation:         {api.EventNameHostProcessProfileViolation, api.LogLevelWARNING},
+	share.CLUSIncidHostFileContentDrift:         {api.EventNameHostFileContentDrift, api.LogLevelWARNING},
+	share.CLUSIncidContainerFileContentDrift:    {api.EventNameContainerFileContentDrift, api.LogLevelWARNING},
+	share.CLUSIncidHostKernelLoad:               {api.EventNameHostKernelLoad, api.LogLevelWARNING},
+	share.CLUSIncidContainerKernelLoad:          {api.EventNameContainerKernelLoad, api.LogLevelCRIT},
+	share.CLUSIncidContainerEscape:              {api.EventNameContainerEscape, api.LogLevelCRIT},
+	share.CLUSIncidContainerCryptoMining:        {api.EventNameContainerCryptoMining, api.LogLevelCRIT},
 }
 
 type LogAuditInfo struct {
@@ -387,6 +425,19 @@ func LevelToPrio(level string) (syslog.Priority, bool) {
 	return syslog.LOG_INFO, false
 }
 
+var syslogFacilities map[string]syslog.Priority = map[string]syslog.Priority{
+	"kern": syslog.LOG_KERN, "user": syslog.LOG_USER, "mail": syslog.LOG_MAIL, "daemon": syslog.LOG_DAEMON,
+	"auth": syslog.LOG_AUTH, "syslog": syslog.LOG_SYSLOG, "lpr": syslog.LOG_LPR, "news": syslog.LOG_NEWS,
+	"uucp": syslog.LOG_UUCP, "cron": syslog.LOG_CRON, "authpriv": syslog.LOG_AUTHPRIV, "ftp": syslog.LOG_FTP,
+	"local0": syslog.LOG_LOCAL0, "local1": syslog.LOG_LOCAL1, "local2": syslog.LOG_LOCAL2, "local3": syslog.LOG_LOCAL3,
+	"local4": syslog.LOG_LOCAL4, "local5": syslog.LOG_LOCAL5, "local6": syslog.LOG_LOCAL6, "local7": syslog.LOG_LOCAL7,
+}
+
+func FacilityToPrio(facility string) (syslog.Priority, bool) {
+	prio, ok := syslogFacilities[strings.ToLower(facility)]
+	return prio, ok
+}
+
 func LevelToString(level string) string {
 	switch level {
 	case api.LogLevelEMERG:
@@ -468,6 +519,9 @@ var AppNameMap map[uint32]string = map[uint32]string{
 	C.DPI_APP_TNS:           "Oracle",
 	C.DPI_APP_TDS:           "MSSQL",
 	C.DPI_APP_GRPC:          "GRPC",
+	C.DPI_APP_MQTT:          "MQTT",
+	C.DPI_APP_AMQP:          "AMQP",
+	C.DPI_APP_STRATUM:       "Stratum",
 }
 
 var appName2IDMap map[string]uint32
@@ -537,6 +591,17 @@ func compareProc(p1, p2 *share.CLUSProcessProfileEntry) int {
 	ret := compareProcField(p1.Name, p2.Name)
 	if ret == 0 {
 		ret = compareProcField(p1.Path, p2.Path)
+		if ret == 0 { // argument or unit constrained rules are placed before the unconstrained one
+			if ret = compareProcField(p1.Args, p2.Args); ret == 0 {
+				ret = compareProcField(p1.ArgsMatch, p2.ArgsMatch)
+			}
+			if ret == 0 {
+				ret = compareProcField(p1.Unit, p2.Unit)
+			}
+			if ret != 0 {
+				return ret
+			}
+		}
 		dir1, base1 := filepath.Split(p1.Path)
 		dir2, base2 := filepath.Split(p2.Path)
 		if ret == 0 { // comparing cfgFlag: learned has the lowest priority
@@ -607,6 +672,20 @@ func FindProcessInProfile(list []*share.CLUSProcessProfileEntry, p *share.CLUSPr
 	return low, false
 }
 
+// MergeSyscalls adds the system calls into a sorted list
+func MergeSyscalls(list []string, calls []string) ([]string, bool) {
+	var changed bool
+	for _, name := range calls {
+		if i := sort.SearchStrings(list, name); i == len(list) || list[i] != name {
+			list = append(list, "")
+			copy(list[i+1:], list[i:])
+			list[i] = name
+			changed = true
+		}
+	}
+	return list, changed
+}
+
 func MergeProcess(list []*share.CLUSProcessProfileEntry, p *share.CLUSProcessProfileEntry, bForcedUpdate bool) ([]*share.CLUSProcessProfileEntry, bool) {
 	insert, found := FindProcessInProfile(list, p)
 	if found {
@@ -628,6 +707,10 @@ func MergeProcess(list []*share.CLUSProcessProfileEntry, p *share.CLUSProcessPro
 				pp.AllowFileUpdate = p.AllowFileUpdate
 				changed = true
 			}
+			if pp.ExpireAt != p.ExpireAt {
+				pp.ExpireAt = p.ExpireAt
+				changed = true
+			}
 		}
 
 		if p.ProbeCmds != nil {
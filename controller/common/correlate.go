@@ -0,0 +1,144 @@
+package common
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const correlateSweepPeriod = time.Second * 5
+
+type CorrelateRecord struct {
+	Category string
+	Time     time.Time // when the log is received
+	Log      interface{}
+	Key      string // identical logs have the same key, empty if the log is never deduplicated
+	Chain    string // related logs have the same chain, such as the logs of the same workload
+}
+
+// The emitted log is the first one of the identical logs with repeat 1, or the last one with the number of the
+// identical logs when the window closes
+type CorrelateEmitFunc func(rec *CorrelateRecord, repeat uint32, correlationID string)
+
+type correlateDup struct {
+	start  time.Time
+	count  uint32
+	last   *CorrelateRecord
+	corrID string
+}
+
+type correlateChain struct {
+	id       string
+	lastSeen time.Time
+}
+
+// Correlator suppresses the identical logs in a window and reports them once with a count when the window closes.
+// The logs of the same chain that arrive within a window of each other share a correlation ID.
+type Correlator struct {
+	window time.Duration
+	emit   CorrelateEmitFunc
+	mutex  sync.Mutex
+	dups   map[string]*correlateDup
+	chains map[string]*correlateChain
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewCorrelator(window time.Duration, emit CorrelateEmitFunc) *Correlator {
+	c := &Correlator{
+		window: window,
+		emit:   emit,
+		dups:   make(map[string]*correlateDup),
+		chains: make(map[string]*correlateChain),
+		stopCh: make(chan struct{}),
+	}
+	c.wg.Add(1)
+	go c.run()
+	return c
+}
+
+// Stop the correlator, the counts of the open windows are reported before it returns
+func (c *Correlator) Close() {
+	close(c.stopCh)
+	c.wg.Wait()
+	c.flush(func(*correlateDup) bool { return true })
+}
+
+func (c *Correlator) Add(rec *CorrelateRecord) {
+	c.mutex.Lock()
+	corrID := c.correlationID(rec)
+	if rec.Key != "" {
+		if dup, ok := c.dups[rec.Key]; ok {
+			dup.count++
+			dup.last = rec
+			c.mutex.Unlock()
+			return
+		}
+		c.dups[rec.Key] = &correlateDup{start: rec.Time, count: 1, last: rec, corrID: corrID}
+	}
+	c.mutex.Unlock()
+
+	c.emit(rec, 1, corrID)
+}
+
+func (c *Correlator) correlationID(rec *CorrelateRecord) string {
+	if rec.Chain == "" {
+		return ""
+	}
+	if chain, ok := c.chains[rec.Chain]; ok && rec.Time.Sub(chain.lastSeen) <= c.window {
+		if rec.Time.After(chain.lastSeen) {
+			chain.lastSeen = rec.Time
+		}
+		return chain.id
+	}
+	chain := &correlateChain{id: uuid.New().String(), lastSeen: rec.Time}
+	c.chains[rec.Chain] = chain
+	return chain.id
+}
+
+// Close the windows that end before now
+func (c *Correlator) Sweep(now time.Time) {
+	c.flush(func(dup *correlateDup) bool { return now.Sub(dup.start) >= c.window })
+
+	c.mutex.Lock()
+	for key, chain := range c.chains {
+		if now.Sub(chain.lastSeen) > c.window {
+			delete(c.chains, key)
+		}
+	}
+	c.mutex.Unlock()
+}
+
+func (c *Correlator) flush(expired func(dup *correlateDup) bool) {
+	var repeated []*correlateDup
+	c.mutex.Lock()
+	for key, dup := range c.dups {
+		if expired(dup) {
+			if dup.count > 1 {
+				repeated = append(repeated, dup)
+			}
+			delete(c.dups, key)
+		}
+	}
+	c.mutex.Unlock()
+
+	for _, dup := range repeated {
+		c.emit(dup.last, dup.count, dup.corrID)
+	}
+}
+
+func (c *Correlator) run() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(correlateSweepPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			c.Sweep(now)
+		case <-c.stopCh:
+			return
+		}
+	}
+}
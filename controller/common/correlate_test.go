@@ -0,0 +1,81 @@
+package common
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type correlateEmitted struct {
+	log    string
+	repeat uint32
+	corrID string
+}
+
+func TestCorrelateDedup(t *testing.T) {
+	var mutex sync.Mutex
+	var emitted []correlateEmitted
+	c := NewCorrelator(time.Minute, func(rec *CorrelateRecord, repeat uint32, corrID string) {
+		mutex.Lock()
+		emitted = append(emitted, correlateEmitted{log: rec.Log.(string), repeat: repeat, corrID: corrID})
+		mutex.Unlock()
+	})
+	defer c.Close()
+
+	now := time.Now()
+	c.Add(&CorrelateRecord{Time: now, Log: "v1", Key: "k1", Chain: "wl1"})
+	c.Add(&CorrelateRecord{Time: now.Add(time.Second), Log: "v2", Key: "k1", Chain: "wl1"})
+	c.Add(&CorrelateRecord{Time: now.Add(time.Second * 2), Log: "v3", Key: "k1", Chain: "wl1"})
+	c.Add(&CorrelateRecord{Time: now.Add(time.Second * 3), Log: "t1", Key: "k2", Chain: "wl1"})
+	c.Add(&CorrelateRecord{Time: now.Add(time.Second * 4), Log: "i1", Chain: "wl2"})
+
+	mutex.Lock()
+	if len(emitted) != 3 || emitted[0].log != "v1" || emitted[1].log != "t1" || emitted[2].log != "i1" {
+		t.Fatalf("Unexpected logs: %+v", emitted)
+	}
+	if emitted[0].repeat != 1 || emitted[0].corrID == "" || emitted[0].corrID != emitted[1].corrID ||
+		emitted[0].corrID == emitted[2].corrID {
+		t.Errorf("Unexpected correlation: %+v", emitted)
+	}
+	mutex.Unlock()
+
+	// the window of k1 is still open
+	c.Sweep(now.Add(time.Second * 30))
+	mutex.Lock()
+	if len(emitted) != 3 {
+		t.Fatalf("Unexpected logs: %+v", emitted)
+	}
+	mutex.Unlock()
+
+	c.Sweep(now.Add(time.Minute))
+	mutex.Lock()
+	if len(emitted) != 4 || emitted[3].log != "v3" || emitted[3].repeat != 3 || emitted[3].corrID != emitted[0].corrID {
+		t.Fatalf("Unexpected repeated log: %+v", emitted)
+	}
+	mutex.Unlock()
+
+	// a new window and a new correlation after the chain expires
+	c.Sweep(now.Add(time.Minute * 3))
+	c.Add(&CorrelateRecord{Time: now.Add(time.Minute * 3), Log: "v4", Key: "k1", Chain: "wl1"})
+	mutex.Lock()
+	if len(emitted) != 5 || emitted[4].log != "v4" || emitted[4].corrID == emitted[0].corrID {
+		t.Errorf("Unexpected logs: %+v", emitted)
+	}
+	mutex.Unlock()
+}
+
+func TestCorrelateClose(t *testing.T) {
+	var repeats []uint32
+	c := NewCorrelator(time.Hour, func(rec *CorrelateRecord, repeat uint32, corrID string) {
+		repeats = append(repeats, repeat)
+	})
+	now := time.Now()
+	c.Add(&CorrelateRecord{Time: now, Log: "v1", Key: "k1"})
+	c.Add(&CorrelateRecord{Time: now, Log: "v1", Key: "k1"})
+	c.Add(&CorrelateRecord{Time: now, Log: "v2", Key: "k2"})
+	// the repeated logs are reported when it stops
+	c.Close()
+	if len(repeats) != 3 || repeats[2] != 2 {
+		t.Errorf("Unexpected repeats: %v", repeats)
+	}
+}
@@ -0,0 +1,337 @@
+package common
+
+// #include "../../defs.h"
+import "C"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share"
+)
+
+const (
+	FlowExportIPFIX     = "ipfix"
+	FlowExportNetflowV9 = "netflow9"
+)
+
+const flowExportMaxPacket = 1400
+const flowExportDialTimeout = time.Second * 10
+const flowExportQueueSize = 256
+
+const (
+	flowVersionNetflowV9 = 9
+	flowVersionIPFIX     = 10
+
+	flowNetflowV9HdrLen = 20
+	flowIPFIXHdrLen     = 16
+	flowSetHdrLen       = 4
+
+	flowNetflowV9TemplateSetID = 0
+	flowIPFIXTemplateSetID     = 2
+
+	flowTemplateIPv4 = 256
+	flowTemplateIPv6 = 257
+)
+
+// Information element IDs, same in NetFlow v9 and IPFIX except the flow time
+const (
+	flowFieldOctetDelta    = 1
+	flowFieldFlows         = 3
+	flowFieldProtocol      = 4
+	flowFieldSrcPort       = 7
+	flowFieldSrcIPv4       = 8
+	flowFieldDstPort       = 11
+	flowFieldDstIPv4       = 12
+	flowFieldLastSwitched  = 21
+	flowFieldFirstSwitched = 22
+	flowFieldSrcIPv6       = 27
+	flowFieldDstIPv6       = 28
+	flowFieldDirection     = 61
+	flowFieldFwdStatus     = 89
+	flowFieldFlowStartSecs = 150
+	flowFieldFlowEndSecs   = 151
+	flowFwdStatusForwarded = 0x40
+	flowFwdStatusDropped   = 0x80
+	flowDirectionIngress   = 0
+	flowDirectionEgress    = 1
+)
+
+type flowField struct {
+	id     uint16
+	length uint16
+}
+
+// FlowExporter sends connection records to an IPFIX or NetFlow v9 collector over UDP.
+// Templates are sent with every message so the collector can decode from any point.
+// The reports are queued in a bounded buffer and sent in the background; when the
+// collector cannot keep up, new reports are dropped instead of blocking the caller.
+type FlowExporter struct {
+	format   string
+	addr     string
+	domainID uint32
+	start    time.Time
+	seq      uint32
+	conn     net.Conn
+	queue    chan []*share.CLUSConnection
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+	dropped  uint64
+}
+
+func NewFlowExporter(addr, format string, domainID uint32) (*FlowExporter, error) {
+	if format == "" {
+		format = FlowExportIPFIX
+	}
+	if format != FlowExportIPFIX && format != FlowExportNetflowV9 {
+		return nil, fmt.Errorf("Unsupported flow export format: %s", format)
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, err
+	}
+	e := &FlowExporter{
+		format:   format,
+		addr:     addr,
+		domainID: domainID,
+		start:    time.Now(),
+		queue:    make(chan []*share.CLUSConnection, flowExportQueueSize),
+		stopCh:   make(chan struct{}),
+	}
+	e.wg.Add(1)
+	go e.run()
+	return e, nil
+}
+
+// Stop the exporter, the queued reports are discarded
+func (e *FlowExporter) Close() {
+	close(e.stopCh)
+	e.wg.Wait()
+	if e.conn != nil {
+		e.conn.Close()
+		e.conn = nil
+	}
+}
+
+// Return false if the report is dropped because the queue is full
+func (e *FlowExporter) Export(conns []*share.CLUSConnection) bool {
+	select {
+	case e.queue <- conns:
+		return true
+	default:
+		if dropped := atomic.AddUint64(&e.dropped, 1); dropped%1000 == 1 {
+			log.WithFields(log.Fields{"addr": e.addr, "dropped": dropped}).Error("Flow export queue is full")
+		}
+		return false
+	}
+}
+
+func (e *FlowExporter) Dropped() uint64 {
+	return atomic.LoadUint64(&e.dropped)
+}
+
+func (e *FlowExporter) run() {
+	defer e.wg.Done()
+
+	for {
+		select {
+		case conns := <-e.queue:
+			e.send(conns)
+		case <-e.stopCh:
+			return
+		}
+	}
+}
+
+func (e *FlowExporter) send(conns []*share.CLUSConnection) error {
+	if e.conn == nil {
+		conn, err := net.DialTimeout("udp", e.addr, flowExportDialTimeout)
+		if err != nil {
+			log.WithFields(log.Fields{"addr": e.addr, "error": err}).Error("Failed to connect flow collector")
+			return err
+		}
+		e.conn = conn
+	}
+
+	for _, pkt := range e.encode(conns, time.Now()) {
+		if _, err := e.conn.Write(pkt); err != nil {
+			log.WithFields(log.Fields{"addr": e.addr, "error": err}).Error("Failed to send flow records")
+			e.conn.Close()
+			e.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *FlowExporter) fields(v6 bool) []flowField {
+	var fields []flowField
+	if v6 {
+		fields = []flowField{{flowFieldSrcIPv6, net.IPv6len}, {flowFieldDstIPv6, net.IPv6len}}
+	} else {
+		fields = []flowField{{flowFieldSrcIPv4, net.IPv4len}, {flowFieldDstIPv4, net.IPv4len}}
+	}
+	fields = append(fields,
+		flowField{flowFieldSrcPort, 2},
+		flowField{flowFieldDstPort, 2},
+		flowField{flowFieldProtocol, 1},
+		flowField{flowFieldOctetDelta, 8},
+		flowField{flowFieldFlows, 4},
+	)
+	if e.format == FlowExportIPFIX {
+		fields = append(fields, flowField{flowFieldFlowStartSecs, 4}, flowField{flowFieldFlowEndSecs, 4})
+	} else {
+		fields = append(fields, flowField{flowFieldFirstSwitched, 4}, flowField{flowFieldLastSwitched, 4})
+	}
+	return append(fields, flowField{flowFieldDirection, 1}, flowField{flowFieldFwdStatus, 1})
+}
+
+func flowRecordLen(fields []flowField) int {
+	var n int
+	for _, f := range fields {
+		n += int(f.length)
+	}
+	return n
+}
+
+// Return sysUptime in ms of the given unix time in seconds, used by NetFlow v9
+func (e *FlowExporter) uptime(now time.Time, at uint32) uint32 {
+	up := now.Sub(e.start)
+	if at != 0 {
+		up -= now.Sub(time.Unix(int64(at), 0))
+	}
+	if up < 0 {
+		return 0
+	}
+	return uint32(up / time.Millisecond)
+}
+
+func (e *FlowExporter) writeRecord(buf *bytes.Buffer, conn *share.CLUSConnection, now time.Time, v6 bool) {
+	if v6 {
+		buf.Write(net.IP(conn.ClientIP).To16())
+		buf.Write(net.IP(conn.ServerIP).To16())
+	} else {
+		buf.Write(net.IP(conn.ClientIP).To4())
+		buf.Write(net.IP(conn.ServerIP).To4())
+	}
+	binary.Write(buf, binary.BigEndian, uint16(conn.ClientPort))
+	binary.Write(buf, binary.BigEndian, uint16(conn.ServerPort))
+	buf.WriteByte(uint8(conn.IPProto))
+	binary.Write(buf, binary.BigEndian, conn.Bytes)
+	binary.Write(buf, binary.BigEndian, conn.Sessions)
+	if e.format == FlowExportIPFIX {
+		binary.Write(buf, binary.BigEndian, conn.FirstSeenAt)
+		binary.Write(buf, binary.BigEndian, conn.LastSeenAt)
+	} else {
+		binary.Write(buf, binary.BigEndian, e.uptime(now, conn.FirstSeenAt))
+		binary.Write(buf, binary.BigEndian, e.uptime(now, conn.LastSeenAt))
+	}
+	if conn.Ingress {
+		buf.WriteByte(flowDirectionIngress)
+	} else {
+		buf.WriteByte(flowDirectionEgress)
+	}
+	if conn.PolicyAction == C.DP_POLICY_ACTION_DENY {
+		buf.WriteByte(flowFwdStatusDropped)
+	} else {
+		buf.WriteByte(flowFwdStatusForwarded)
+	}
+}
+
+func (e *FlowExporter) writeTemplateSet(buf *bytes.Buffer) {
+	setID := uint16(flowIPFIXTemplateSetID)
+	if e.format == FlowExportNetflowV9 {
+		setID = flowNetflowV9TemplateSetID
+	}
+	v4, v6 := e.fields(false), e.fields(true)
+	length := flowSetHdrLen + 4*2 + 4*len(v4) + 4*len(v6)
+
+	binary.Write(buf, binary.BigEndian, setID)
+	binary.Write(buf, binary.BigEndian, uint16(length))
+	for i, fields := range [][]flowField{v4, v6} {
+		binary.Write(buf, binary.BigEndian, uint16(flowTemplateIPv4+i))
+		binary.Write(buf, binary.BigEndian, uint16(len(fields)))
+		for _, f := range fields {
+			binary.Write(buf, binary.BigEndian, f.id)
+			binary.Write(buf, binary.BigEndian, f.length)
+		}
+	}
+}
+
+func (e *FlowExporter) writeHeader(buf *bytes.Buffer, now time.Time, count int) {
+	if e.format == FlowExportIPFIX {
+		binary.Write(buf, binary.BigEndian, uint16(flowVersionIPFIX))
+		binary.Write(buf, binary.BigEndian, uint16(0)) // length, filled when the message is done
+		binary.Write(buf, binary.BigEndian, uint32(now.Unix()))
+		binary.Write(buf, binary.BigEndian, e.seq)
+		binary.Write(buf, binary.BigEndian, e.domainID)
+		e.seq += uint32(count)
+	} else {
+		binary.Write(buf, binary.BigEndian, uint16(flowVersionNetflowV9))
+		binary.Write(buf, binary.BigEndian, uint16(count+2)) // 2 template records
+		binary.Write(buf, binary.BigEndian, e.uptime(now, 0))
+		binary.Write(buf, binary.BigEndian, uint32(now.Unix()))
+		binary.Write(buf, binary.BigEndian, e.seq)
+		binary.Write(buf, binary.BigEndian, e.domainID)
+		e.seq++
+	}
+}
+
+func (e *FlowExporter) encode(conns []*share.CLUSConnection, now time.Time) [][]byte {
+	var v4, v6 []*share.CLUSConnection
+	for _, conn := range conns {
+		if net.IP(conn.ClientIP).To4() != nil && net.IP(conn.ServerIP).To4() != nil {
+			v4 = append(v4, conn)
+		} else if net.IP(conn.ClientIP).To16() != nil && net.IP(conn.ServerIP).To16() != nil {
+			v6 = append(v6, conn)
+		}
+	}
+
+	hdrLen := flowIPFIXHdrLen
+	if e.format == FlowExportNetflowV9 {
+		hdrLen = flowNetflowV9HdrLen
+	}
+	var tmpl bytes.Buffer
+	e.writeTemplateSet(&tmpl)
+
+	pkts := make([][]byte, 0)
+	for i, recs := range [][]*share.CLUSConnection{v4, v6} {
+		v6 := i == 1
+		recLen := flowRecordLen(e.fields(v6))
+		perPkt := (flowExportMaxPacket - hdrLen - tmpl.Len() - flowSetHdrLen) / recLen
+
+		for len(recs) > 0 {
+			n := len(recs)
+			if n > perPkt {
+				n = perPkt
+			}
+
+			var buf bytes.Buffer
+			e.writeHeader(&buf, now, n)
+			buf.Write(tmpl.Bytes())
+
+			setLen := flowSetHdrLen + n*recLen
+			pad := (4 - setLen%4) % 4
+			binary.Write(&buf, binary.BigEndian, uint16(flowTemplateIPv4+i))
+			binary.Write(&buf, binary.BigEndian, uint16(setLen+pad))
+			for _, conn := range recs[:n] {
+				e.writeRecord(&buf, conn, now, v6)
+			}
+			buf.Write(make([]byte, pad))
+
+			pkt := buf.Bytes()
+			if e.format == FlowExportIPFIX {
+				binary.BigEndian.PutUint16(pkt[2:], uint16(len(pkt)))
+			}
+			pkts = append(pkts, pkt)
+			recs = recs[n:]
+		}
+	}
+	return pkts
+}
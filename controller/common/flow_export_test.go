@@ -0,0 +1,152 @@
+package common
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestFlowExportIPFIX(t *testing.T) {
+	e, err := NewFlowExporter("127.0.0.1:4739", FlowExportIPFIX, 7)
+	if err != nil {
+		t.Fatalf("Failed to create exporter: %v", err)
+	}
+	defer e.Close()
+
+	now := time.Now()
+	conns := []*share.CLUSConnection{
+		&share.CLUSConnection{
+			ClientIP: net.ParseIP("10.1.1.1").To4(), ServerIP: net.ParseIP("10.1.1.2").To4(),
+			ClientPort: 34567, ServerPort: 80, IPProto: 6, Bytes: 1000, Sessions: 2,
+			FirstSeenAt: uint32(now.Unix() - 10), LastSeenAt: uint32(now.Unix()),
+		},
+		&share.CLUSConnection{
+			ClientIP: net.ParseIP("fd00::1"), ServerIP: net.ParseIP("fd00::2"),
+			ServerPort: 443, IPProto: 6, Bytes: 10, Sessions: 1, Ingress: true,
+		},
+		&share.CLUSConnection{ClientIP: []byte{1, 2}, ServerIP: []byte{3, 4}},
+	}
+
+	pkts := e.encode(conns, now)
+	if len(pkts) != 2 {
+		t.Fatalf("Unexpected packet count: %d", len(pkts))
+	}
+	for _, pkt := range pkts {
+		if v := binary.BigEndian.Uint16(pkt[0:]); v != flowVersionIPFIX {
+			t.Errorf("Unexpected version: %d", v)
+		}
+		if l := binary.BigEndian.Uint16(pkt[2:]); int(l) != len(pkt) {
+			t.Errorf("Unexpected message length: %d, expect %d", l, len(pkt))
+		}
+		if d := binary.BigEndian.Uint32(pkt[12:]); d != 7 {
+			t.Errorf("Unexpected domain: %d", d)
+		}
+		if len(pkt)%4 != 0 {
+			t.Errorf("Message is not padded: %d", len(pkt))
+		}
+	}
+	if seq := binary.BigEndian.Uint32(pkts[1][8:]); seq != 1 {
+		t.Errorf("Unexpected sequence: %d", seq)
+	}
+}
+
+func TestFlowExportIPv4In16Bytes(t *testing.T) {
+	now := time.Now()
+	conn := func(client, server net.IP) []*share.CLUSConnection {
+		return []*share.CLUSConnection{
+			&share.CLUSConnection{ClientIP: client, ServerIP: server, ServerPort: 80, IPProto: 6, Sessions: 1},
+		}
+	}
+
+	e4, _ := NewFlowExporter("127.0.0.1:4739", FlowExportIPFIX, 0)
+	e16, _ := NewFlowExporter("127.0.0.1:4739", FlowExportIPFIX, 0)
+	defer e4.Close()
+	defer e16.Close()
+	pkts4 := e4.encode(conn(net.ParseIP("10.1.1.1").To4(), net.ParseIP("10.1.1.2").To4()), now)
+	pkts16 := e16.encode(conn(net.ParseIP("10.1.1.1"), net.ParseIP("10.1.1.2")), now)
+	if len(pkts16) != 1 || len(pkts4) != 1 || !bytes.Equal(pkts4[0], pkts16[0]) {
+		t.Errorf("IPv4 addresses in 16-byte form should be exported as IPv4")
+	}
+}
+
+func TestFlowExportNetflowV9Split(t *testing.T) {
+	e, _ := NewFlowExporter("127.0.0.1:2055", FlowExportNetflowV9, 0)
+	defer e.Close()
+
+	conns := make([]*share.CLUSConnection, 100)
+	for i := range conns {
+		conns[i] = &share.CLUSConnection{
+			ClientIP: net.IPv4(10, 0, 0, byte(i)).To4(), ServerIP: net.IPv4(10, 0, 1, 1).To4(),
+			ServerPort: 53, IPProto: 17, Sessions: 1,
+		}
+	}
+
+	pkts := e.encode(conns, time.Now())
+	if len(pkts) < 2 {
+		t.Fatalf("Records should be split: %d", len(pkts))
+	}
+	var total int
+	for i, pkt := range pkts {
+		if len(pkt) > flowExportMaxPacket {
+			t.Errorf("Packet too large: %d", len(pkt))
+		}
+		if v := binary.BigEndian.Uint16(pkt[0:]); v != flowVersionNetflowV9 {
+			t.Errorf("Unexpected version: %d", v)
+		}
+		if seq := binary.BigEndian.Uint32(pkt[12:]); seq != uint32(i) {
+			t.Errorf("Unexpected sequence: %d", seq)
+		}
+		// count includes 2 template records
+		total += int(binary.BigEndian.Uint16(pkt[2:])) - 2
+	}
+	if total != len(conns) {
+		t.Errorf("Unexpected record count: %d", total)
+	}
+}
+
+func TestFlowExportQueue(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer pc.Close()
+
+	e, _ := NewFlowExporter(pc.LocalAddr().String(), FlowExportIPFIX, 0)
+	defer e.Close()
+
+	conns := []*share.CLUSConnection{
+		&share.CLUSConnection{
+			ClientIP: net.ParseIP("10.1.1.1").To4(), ServerIP: net.ParseIP("10.1.1.2").To4(),
+			ServerPort: 80, IPProto: 6, Sessions: 1,
+		},
+	}
+	if !e.Export(conns) {
+		t.Fatalf("Report should be queued")
+	}
+	buf := make([]byte, flowExportMaxPacket)
+	pc.SetReadDeadline(time.Now().Add(time.Second * 5))
+	if n, _, err := pc.ReadFrom(buf); err != nil {
+		t.Errorf("Flow records are not received: %v", err)
+	} else if v := binary.BigEndian.Uint16(buf[0:]); n < flowIPFIXHdrLen || v != flowVersionIPFIX {
+		t.Errorf("Unexpected message: len=%d version=%d", n, v)
+	}
+
+	// reports are dropped when the queue is full
+	full := &FlowExporter{queue: make(chan []*share.CLUSConnection, 1)}
+	if !full.Export(conns) || full.Export(conns) || full.Dropped() != 1 {
+		t.Errorf("Report should be dropped when the queue is full: dropped=%d", full.Dropped())
+	}
+}
+
+func TestFlowExportInvalidFormat(t *testing.T) {
+	if _, err := NewFlowExporter("127.0.0.1:2055", "sflow", 0); err == nil {
+		t.Errorf("Unsupported format should fail")
+	}
+	if _, err := NewFlowExporter("127.0.0.1", FlowExportIPFIX, 0); err == nil {
+		t.Errorf("Address without port should fail")
+	}
+}
@@ -0,0 +1,791 @@
+package common
+
+// A minimal Kafka producer for the log exporter. It only implements the requests that are needed to publish
+// records: metadata, produce (record batch v2, not compressed) and SASL PLAIN/SCRAM authentication.
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	KafkaSASLPlain            = "PLAIN"
+	KafkaSASLScram256         = "SCRAM-SHA-256"
+	KafkaSASLScram512         = "SCRAM-SHA-512"
+	KafkaPartitionKeyCluster  = "cluster"
+	KafkaPartitionKeyWorkload = "workload"
+)
+
+const (
+	kafkaApiProduce       int16 = 0
+	kafkaApiMetadata      int16 = 3
+	kafkaApiSaslHandshake int16 = 17
+	kafkaApiSaslAuth      int16 = 36
+)
+
+const kafkaClientID = "neuvector"
+const kafkaAcksAll int16 = -1
+const kafkaMaxResponse = 64 * 1024 * 1024
+
+// Errors that can succeed after the metadata is refreshed or later
+var kafkaRetriableErrors = map[int16]string{
+	3:  "UNKNOWN_TOPIC_OR_PARTITION",
+	5:  "LEADER_NOT_AVAILABLE",
+	6:  "NOT_LEADER_OR_FOLLOWER",
+	7:  "REQUEST_TIMED_OUT",
+	13: "NETWORK_EXCEPTION",
+	19: "NOT_ENOUGH_REPLICAS",
+	20: "NOT_ENOUGH_REPLICAS_AFTER_APPEND",
+}
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// -- encoding
+
+type kafkaEncoder struct {
+	b []byte
+}
+
+func (e *kafkaEncoder) putInt8(v int8) {
+	e.b = append(e.b, byte(v))
+}
+
+func (e *kafkaEncoder) putInt16(v int16) {
+	e.b = append(e.b, byte(v>>8), byte(v))
+}
+
+func (e *kafkaEncoder) putInt32(v int32) {
+	e.b = append(e.b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func (e *kafkaEncoder) putInt64(v int64) {
+	e.putInt32(int32(v >> 32))
+	e.putInt32(int32(v))
+}
+
+func (e *kafkaEncoder) putString(s string) {
+	e.putInt16(int16(len(s)))
+	e.b = append(e.b, s...)
+}
+
+func (e *kafkaEncoder) putBytes(b []byte) {
+	e.putInt32(int32(len(b)))
+	e.b = append(e.b, b...)
+}
+
+// zigzag varint as used in the record
+func (e *kafkaEncoder) putVarint(v int64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], v)
+	e.b = append(e.b, buf[:n]...)
+}
+
+// nil is encoded as length -1
+func (e *kafkaEncoder) putVarBytes(b []byte) {
+	if b == nil {
+		e.putVarint(-1)
+		return
+	}
+	e.putVarint(int64(len(b)))
+	e.b = append(e.b, b...)
+}
+
+type kafkaDecoder struct {
+	b   []byte
+	off int
+	err error
+}
+
+func (d *kafkaDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || d.off+n > len(d.b) {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := d.b[d.off : d.off+n]
+	d.off += n
+	return b
+}
+
+func (d *kafkaDecoder) int8() int8 {
+	if b := d.next(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int16() int16 {
+	if b := d.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int32() int32 {
+	if b := d.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int64() int64 {
+	if b := d.next(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+// Nullable string is returned as empty string
+func (d *kafkaDecoder) string() string {
+	if n := d.int16(); n > 0 {
+		return string(d.next(int(n)))
+	}
+	return ""
+}
+
+func (d *kafkaDecoder) bytes() []byte {
+	if n := d.int32(); n > 0 {
+		return d.next(int(n))
+	}
+	return nil
+}
+
+func (d *kafkaDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.b[d.off:])
+	if n <= 0 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	d.off += n
+	return v
+}
+
+func (d *kafkaDecoder) varBytes() []byte {
+	if n := d.varint(); n >= 0 {
+		return d.next(int(n))
+	}
+	return nil
+}
+
+// -- connection
+
+type kafkaConn struct {
+	conn   net.Conn
+	corrID int32
+}
+
+func (c *kafkaConn) request(apiKey, apiVersion int16, body []byte, timeout time.Duration) ([]byte, error) {
+	c.corrID++
+
+	// request header v1
+	e := kafkaEncoder{b: make([]byte, 0, len(body)+32)}
+	e.putInt32(0)
+	e.putInt16(apiKey)
+	e.putInt16(apiVersion)
+	e.putInt32(c.corrID)
+	e.putString(kafkaClientID)
+	e.b = append(e.b, body...)
+	binary.BigEndian.PutUint32(e.b, uint32(len(e.b)-4))
+
+	c.conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := c.conn.Write(e.b); err != nil {
+		return nil, err
+	}
+
+	var hdr [8]byte
+	if _, err := io.ReadFull(c.conn, hdr[:]); err != nil {
+		return nil, err
+	}
+	size := int32(binary.BigEndian.Uint32(hdr[:4]))
+	if size < 4 || size > kafkaMaxResponse {
+		return nil, fmt.Errorf("Invalid response size %d", size)
+	}
+	if corrID := int32(binary.BigEndian.Uint32(hdr[4:])); corrID != c.corrID {
+		return nil, fmt.Errorf("Unexpected correlation id %d, expect %d", corrID, c.corrID)
+	}
+	resp := make([]byte, size-4)
+	if _, err := io.ReadFull(c.conn, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *kafkaConn) close() {
+	c.conn.Close()
+}
+
+// -- SASL
+
+func (c *kafkaConn) saslAuthenticate(data []byte, timeout time.Duration) ([]byte, error) {
+	var e kafkaEncoder
+	e.putBytes(data)
+	resp, err := c.request(kafkaApiSaslAuth, 0, e.b, timeout)
+	if err != nil {
+		return nil, err
+	}
+	d := kafkaDecoder{b: resp}
+	code := d.int16()
+	msg := d.string()
+	out := d.bytes()
+	if d.err != nil {
+		return nil, d.err
+	} else if code != 0 {
+		return nil, fmt.Errorf("SASL authentication failed: error=%d %s", code, msg)
+	}
+	return out, nil
+}
+
+type scramClient struct {
+	hashFn      func() hash.Hash
+	username    string
+	password    string
+	nonce       string
+	clientFirst string
+	authMsg     string
+	saltedPass  []byte
+}
+
+func newScramClient(mechanism, username, password, nonce string) *scramClient {
+	c := &scramClient{hashFn: sha256.New, username: username, password: password, nonce: nonce}
+	if mechanism == KafkaSASLScram512 {
+		c.hashFn = sha512.New
+	}
+	return c
+}
+
+func (c *scramClient) hmac(key []byte, msg string) []byte {
+	h := hmac.New(c.hashFn, key)
+	h.Write([]byte(msg))
+	return h.Sum(nil)
+}
+
+func parseScramMessage(msg string) map[string]string {
+	attrs := make(map[string]string)
+	for _, kv := range strings.Split(msg, ",") {
+		if len(kv) > 2 && kv[1] == '=' {
+			attrs[kv[:1]] = kv[2:]
+		}
+	}
+	return attrs
+}
+
+// PBKDF2 with HMAC as the pseudorandom function (RFC 2898)
+func (c *scramClient) pbkdf2(password, salt []byte, iter int) []byte {
+	return pbkdf2.Key(password, salt, iter, c.hashFn().Size(), c.hashFn)
+}
+
+func (c *scramClient) first() string {
+	user := strings.NewReplacer("=", "=3D", ",", "=2C").Replace(c.username)
+	c.clientFirst = fmt.Sprintf("n=%s,r=%s", user, c.nonce)
+	return "n,," + c.clientFirst
+}
+
+func (c *scramClient) final(serverFirst string) (string, error) {
+	attrs := parseScramMessage(serverFirst)
+	nonce := attrs["r"]
+	salt, err := base64.StdEncoding.DecodeString(attrs["s"])
+	if err != nil || !strings.HasPrefix(nonce, c.nonce) || len(nonce) == len(c.nonce) {
+		return "", fmt.Errorf("Invalid SCRAM server message")
+	}
+	iter, err := strconv.Atoi(attrs["i"])
+	if err != nil || iter <= 0 {
+		return "", fmt.Errorf("Invalid SCRAM iteration count")
+	}
+
+	c.saltedPass = c.pbkdf2([]byte(c.password), salt, iter)
+	clientKey := c.hmac(c.saltedPass, "Client Key")
+	h := c.hashFn()
+	h.Write(clientKey)
+	storedKey := h.Sum(nil)
+
+	finalBare := "c=biws,r=" + nonce // biws is base64 of the gs2 header "n,,"
+	c.authMsg = c.clientFirst + "," + serverFirst + "," + finalBare
+	proof := c.hmac(storedKey, c.authMsg)
+	for i := range proof {
+		proof[i] ^= clientKey[i]
+	}
+	return finalBare + ",p=" + base64.StdEncoding.EncodeToString(proof), nil
+}
+
+func (c *scramClient) verify(serverFinal string) error {
+	attrs := parseScramMessage(serverFinal)
+	if e, ok := attrs["e"]; ok {
+		return fmt.Errorf("SCRAM authentication failed: %s", e)
+	}
+	serverSig := c.hmac(c.hmac(c.saltedPass, "Server Key"), c.authMsg)
+	if attrs["v"] != base64.StdEncoding.EncodeToString(serverSig) {
+		return fmt.Errorf("Invalid SCRAM server signature")
+	}
+	return nil
+}
+
+// -- producer
+
+type kafkaHeader struct {
+	key   string
+	value []byte
+}
+
+type kafkaMessage struct {
+	topic   string
+	key     []byte
+	value   []byte
+	headers []kafkaHeader
+	time    time.Time
+}
+
+type kafkaPartition struct {
+	id     int32
+	leader int32
+}
+
+// kafkaProducer is not thread-safe; it's used by the exporter goroutine only.
+type kafkaProducer struct {
+	bootstrap []string
+	tlsConfig *tls.Config // nil if TLS is not enabled
+	mechanism string
+	username  string
+	password  string
+	timeout   time.Duration
+	brokers   map[int32]string // broker id to address
+	conns     map[int32]*kafkaConn
+	topics    map[string][]kafkaPartition
+	stale     bool
+	counter   uint32 // for round-robin partitioning of the messages without key
+}
+
+func newKafkaProducer(bootstrap []string, tlsConfig *tls.Config, mechanism, username, password string, timeout time.Duration) *kafkaProducer {
+	return &kafkaProducer{
+		bootstrap: bootstrap,
+		tlsConfig: tlsConfig,
+		mechanism: mechanism,
+		username:  username,
+		password:  password,
+		timeout:   timeout,
+		brokers:   make(map[int32]string),
+		conns:     make(map[int32]*kafkaConn),
+		topics:    make(map[string][]kafkaPartition),
+	}
+}
+
+func (p *kafkaProducer) close() {
+	for id, c := range p.conns {
+		c.close()
+		delete(p.conns, id)
+	}
+}
+
+func (p *kafkaProducer) connect(addr string) (*kafkaConn, error) {
+	dialer := &net.Dialer{Timeout: p.timeout}
+	var conn net.Conn
+	var err error
+	if p.tlsConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, p.tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &kafkaConn{conn: conn}
+	if p.mechanism != "" {
+		if err = p.authenticate(c); err != nil {
+			c.close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (p *kafkaProducer) authenticate(c *kafkaConn) error {
+	var e kafkaEncoder
+	e.putString(p.mechanism)
+	resp, err := c.request(kafkaApiSaslHandshake, 1, e.b, p.timeout)
+	if err != nil {
+		return err
+	}
+	d := kafkaDecoder{b: resp}
+	if code := d.int16(); d.err != nil {
+		return d.err
+	} else if code != 0 {
+		return fmt.Errorf("SASL mechanism %s is not enabled: error=%d", p.mechanism, code)
+	}
+
+	if p.mechanism == KafkaSASLPlain {
+		_, err = c.saslAuthenticate([]byte("\x00"+p.username+"\x00"+p.password), p.timeout)
+		return err
+	}
+
+	nonce := make([]byte, 24)
+	if _, err = rand.Read(nonce); err != nil {
+		return err
+	}
+	scram := newScramClient(p.mechanism, p.username, p.password, base64.RawStdEncoding.EncodeToString(nonce))
+	serverFirst, err := c.saslAuthenticate([]byte(scram.first()), p.timeout)
+	if err != nil {
+		return err
+	}
+	final, err := scram.final(string(serverFirst))
+	if err != nil {
+		return err
+	}
+	serverFinal, err := c.saslAuthenticate([]byte(final), p.timeout)
+	if err != nil {
+		return err
+	}
+	return scram.verify(string(serverFinal))
+}
+
+// Get the brokers and the partition leaders of the topics from one of the bootstrap brokers
+func (p *kafkaProducer) refreshMetadata(topics []string) error {
+	var e kafkaEncoder
+	e.putInt32(int32(len(topics)))
+	for _, topic := range topics {
+		e.putString(topic)
+	}
+
+	var err error
+	for _, addr := range p.bootstrap {
+		var c *kafkaConn
+		var resp []byte
+		if c, err = p.connect(addr); err != nil {
+			continue
+		}
+		resp, err = c.request(kafkaApiMetadata, 1, e.b, p.timeout)
+		c.close()
+		if err != nil {
+			continue
+		}
+		if err = p.parseMetadata(resp); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// Metadata response v1
+func (p *kafkaProducer) parseMetadata(resp []byte) error {
+	d := kafkaDecoder{b: resp}
+	brokers := make(map[int32]string)
+	for n := d.int32(); n > 0 && d.err == nil; n-- {
+		id := d.int32()
+		host := d.string()
+		port := d.int32()
+		d.string() // rack
+		brokers[id] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	d.int32() // controller
+
+	topics := make(map[string][]kafkaPartition)
+	for n := d.int32(); n > 0 && d.err == nil; n-- {
+		code := d.int16()
+		name := d.string()
+		d.int8() // internal
+		parts := make([]kafkaPartition, 0)
+		for m := d.int32(); m > 0 && d.err == nil; m-- {
+			d.int16() // partition error
+			part := kafkaPartition{id: d.int32(), leader: d.int32()}
+			for r := d.int32(); r > 0 && d.err == nil; r-- {
+				d.int32() // replica
+			}
+			for r := d.int32(); r > 0 && d.err == nil; r-- {
+				d.int32() // isr
+			}
+			parts = append(parts, part)
+		}
+		if code != 0 {
+			log.WithFields(log.Fields{"topic": name, "error": code}).Error("Failed to get kafka topic")
+			continue
+		}
+		sort.Slice(parts, func(i, j int) bool { return parts[i].id < parts[j].id })
+		topics[name] = parts
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	// Broker addresses can be changed, reconnect
+	p.close()
+	p.brokers = brokers
+	p.topics = topics
+	p.stale = false
+	return nil
+}
+
+func (p *kafkaProducer) getConn(id int32) (*kafkaConn, error) {
+	if c, ok := p.conns[id]; ok {
+		return c, nil
+	}
+	addr, ok := p.brokers[id]
+	if !ok {
+		return nil, fmt.Errorf("Unknown kafka broker %d", id)
+	}
+	c, err := p.connect(addr)
+	if err != nil {
+		return nil, err
+	}
+	p.conns[id] = c
+	return c, nil
+}
+
+func (p *kafkaProducer) closeConn(id int32) {
+	if c, ok := p.conns[id]; ok {
+		c.close()
+		delete(p.conns, id)
+	}
+}
+
+// Same as the murmur2 hash used by the default partitioner of the java client, so the messages with the
+// same key go to the same partition as other producers.
+func murmur2(data []byte) int32 {
+	const seed uint32 = 0x9747b28c
+	const m uint32 = 0x5bd1e995
+	const r = 24
+
+	length := len(data)
+	h := seed ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := uint32(data[i]) | uint32(data[i+1])<<8 | uint32(data[i+2])<<16 | uint32(data[i+3])<<24
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+
+	tail := length &^ 3
+	switch length % 4 {
+	case 3:
+		h ^= uint32(data[tail+2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(data[tail+1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(data[tail])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return int32(h)
+}
+
+func (p *kafkaProducer) partition(m *kafkaMessage, parts []kafkaPartition) kafkaPartition {
+	if len(m.key) == 0 {
+		p.counter++
+		return parts[p.counter%uint32(len(parts))]
+	}
+	return parts[int(murmur2(m.key)&0x7fffffff)%len(parts)]
+}
+
+func encodeRecordBatch(msgs []*kafkaMessage) []byte {
+	baseTime := msgs[0].time.UnixNano() / int64(time.Millisecond)
+	maxTime := baseTime
+
+	// attributes to the end, covered by crc
+	var e kafkaEncoder
+	e.putInt16(0) // attributes: no compression, create time
+	e.putInt32(int32(len(msgs) - 1))
+	e.putInt64(baseTime)
+	e.putInt64(0)  // max timestamp, filled later
+	e.putInt64(-1) // producer id
+	e.putInt16(-1) // producer epoch
+	e.putInt32(-1) // base sequence
+	e.putInt32(int32(len(msgs)))
+	for i, m := range msgs {
+		ts := m.time.UnixNano() / int64(time.Millisecond)
+		if ts > maxTime {
+			maxTime = ts
+		}
+
+		var r kafkaEncoder
+		r.putInt8(0) // attributes
+		r.putVarint(ts - baseTime)
+		r.putVarint(int64(i))
+		r.putVarBytes(m.key)
+		r.putVarBytes(m.value)
+		r.putVarint(int64(len(m.headers)))
+		for _, h := range m.headers {
+			r.putVarBytes([]byte(h.key))
+			r.putVarBytes(h.value)
+		}
+		e.putVarint(int64(len(r.b)))
+		e.b = append(e.b, r.b...)
+	}
+	binary.BigEndian.PutUint64(e.b[14:], uint64(maxTime))
+
+	var batch kafkaEncoder
+	batch.putInt64(0)                   // base offset
+	batch.putInt32(int32(len(e.b) + 9)) // length after this field
+	batch.putInt32(-1)                  // partition leader epoch
+	batch.putInt8(2)                    // magic
+	batch.putInt32(int32(crc32.Checksum(e.b, crc32cTable)))
+	batch.b = append(batch.b, e.b...)
+	return batch.b
+}
+
+// topic -> partition -> messages
+type kafkaTopicMessages map[string]map[int32][]*kafkaMessage
+
+// Produce request v3
+func encodeProduceRequest(data kafkaTopicMessages, timeout time.Duration) []byte {
+	var e kafkaEncoder
+	e.putInt16(-1) // transactional id
+	e.putInt16(kafkaAcksAll)
+	e.putInt32(int32(timeout / time.Millisecond))
+	e.putInt32(int32(len(data)))
+	for topic, parts := range data {
+		e.putString(topic)
+		e.putInt32(int32(len(parts)))
+		for id, msgs := range parts {
+			e.putInt32(id)
+			e.putBytes(encodeRecordBatch(msgs))
+		}
+	}
+	return e.b
+}
+
+// Produce response v3, return the error code of each topic partition
+func decodeProduceResponse(resp []byte) (map[string]map[int32]int16, error) {
+	d := kafkaDecoder{b: resp}
+	results := make(map[string]map[int32]int16)
+	for n := d.int32(); n > 0 && d.err == nil; n-- {
+		topic := d.string()
+		results[topic] = make(map[int32]int16)
+		for m := d.int32(); m > 0 && d.err == nil; m-- {
+			id := d.int32()
+			results[topic][id] = d.int16()
+			d.int64() // base offset
+			d.int64() // log append time
+		}
+	}
+	return results, d.err
+}
+
+// Publish the messages to the partition leaders, and return the messages that can be retried and the number
+// of the messages rejected by the brokers.
+func (p *kafkaProducer) produce(msgs []*kafkaMessage) ([]*kafkaMessage, int, error) {
+	topicSet := make(map[string]bool)
+	for _, m := range msgs {
+		topicSet[m.topic] = true
+		if _, ok := p.topics[m.topic]; !ok {
+			p.stale = true
+		}
+	}
+	if p.stale {
+		topics := make([]string, 0, len(topicSet)+len(p.topics))
+		for topic := range p.topics {
+			topicSet[topic] = true
+		}
+		for topic := range topicSet {
+			topics = append(topics, topic)
+		}
+		if err := p.refreshMetadata(topics); err != nil {
+			p.stale = true
+			return msgs, 0, err
+		}
+	}
+
+	var lastErr error
+	retry := make([]*kafkaMessage, 0)
+	brokers := make(map[int32]kafkaTopicMessages)
+	for _, m := range msgs {
+		parts := p.topics[m.topic]
+		if len(parts) == 0 {
+			retry = append(retry, m)
+			lastErr = fmt.Errorf("Kafka topic %s is not available", m.topic)
+			p.stale = true
+			continue
+		}
+		part := p.partition(m, parts)
+		if part.leader < 0 {
+			retry = append(retry, m)
+			p.stale = true
+			continue
+		}
+		if _, ok := brokers[part.leader]; !ok {
+			brokers[part.leader] = make(kafkaTopicMessages)
+		}
+		if _, ok := brokers[part.leader][m.topic]; !ok {
+			brokers[part.leader][m.topic] = make(map[int32][]*kafkaMessage)
+		}
+		brokers[part.leader][m.topic][part.id] = append(brokers[part.leader][m.topic][part.id], m)
+	}
+
+	var rejected int
+	for id, data := range brokers {
+		results, err := p.produceToBroker(id, data)
+		if err != nil {
+			for _, parts := range data {
+				for _, pmsgs := range parts {
+					retry = append(retry, pmsgs...)
+				}
+			}
+			lastErr = err
+			p.stale = true
+			continue
+		}
+
+		for topic, parts := range data {
+			for pid, pmsgs := range parts {
+				code, ok := results[topic][pid]
+				if !ok {
+					retry = append(retry, pmsgs...)
+				} else if code == 0 {
+					continue
+				} else if name, ok := kafkaRetriableErrors[code]; ok {
+					retry = append(retry, pmsgs...)
+					lastErr = fmt.Errorf("Kafka topic %s partition %d: %s", topic, pid, name)
+					p.stale = true
+				} else {
+					rejected += len(pmsgs)
+					lastErr = fmt.Errorf("Kafka topic %s partition %d: error=%d", topic, pid, code)
+				}
+			}
+		}
+	}
+	return retry, rejected, lastErr
+}
+
+func (p *kafkaProducer) produceToBroker(id int32, data kafkaTopicMessages) (map[string]map[int32]int16, error) {
+	c, err := p.getConn(id)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.request(kafkaApiProduce, 3, encodeProduceRequest(data, p.timeout), p.timeout)
+	if err != nil {
+		p.closeConn(id)
+		return nil, err
+	}
+	results, err := decodeProduceResponse(resp)
+	if err != nil {
+		p.closeConn(id)
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,291 @@
+package common
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+type mockKafkaRecord struct {
+	topic     string
+	partition int32
+	key       string
+	value     string
+	category  string
+}
+
+// A single broker that serves the metadata and produce requests
+type mockKafkaBroker struct {
+	ln        net.Listener
+	mutex     sync.Mutex
+	records   []*mockKafkaRecord
+	failFirst int16 // error code of the first produce request
+	produces  int
+	badCRC    bool
+}
+
+func newMockKafkaBroker(t *testing.T) *mockKafkaBroker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	b := &mockKafkaBroker{ln: ln}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *mockKafkaBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		req := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+		d := kafkaDecoder{b: req}
+		apiKey := d.int16()
+		d.int16() // version
+		corrID := d.int32()
+		d.string() // client id
+
+		var e kafkaEncoder
+		e.putInt32(0)
+		e.putInt32(corrID)
+		switch apiKey {
+		case kafkaApiMetadata:
+			b.metadata(&d, &e)
+		case kafkaApiProduce:
+			b.produce(&d, &e)
+		default:
+			return
+		}
+		binary.BigEndian.PutUint32(e.b, uint32(len(e.b)-4))
+		conn.Write(e.b)
+	}
+}
+
+func (b *mockKafkaBroker) metadata(d *kafkaDecoder, e *kafkaEncoder) {
+	host, port, _ := net.SplitHostPort(b.ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+	e.putInt32(1)
+	e.putInt32(1)
+	e.putString(host)
+	e.putInt32(int32(p))
+	e.putInt16(-1)
+	e.putInt32(1)
+
+	n := d.int32()
+	e.putInt32(n)
+	for ; n > 0; n-- {
+		e.putInt16(0)
+		e.putString(d.string())
+		e.putInt8(0)
+		e.putInt32(2) // 2 partitions
+		for i := int32(0); i < 2; i++ {
+			e.putInt16(0)
+			e.putInt32(i)
+			e.putInt32(1)
+			e.putInt32(0)
+			e.putInt32(0)
+		}
+	}
+}
+
+func (b *mockKafkaBroker) produce(d *kafkaDecoder, e *kafkaEncoder) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.produces++
+
+	d.string() // transactional id
+	d.int16()  // acks
+	d.int32()  // timeout
+	n := d.int32()
+	e.putInt32(n)
+	for ; n > 0; n-- {
+		topic := d.string()
+		e.putString(topic)
+		m := d.int32()
+		e.putInt32(m)
+		for ; m > 0; m-- {
+			partition := d.int32()
+			code := b.decodeBatch(topic, partition, d.bytes())
+			if b.produces == 1 && b.failFirst != 0 {
+				code = b.failFirst
+			}
+			e.putInt32(partition)
+			e.putInt16(code)
+			e.putInt64(0)
+			e.putInt64(-1)
+		}
+	}
+	e.putInt32(0)
+}
+
+// Decode the record batch, return the error code
+func (b *mockKafkaBroker) decodeBatch(topic string, partition int32, batch []byte) int16 {
+	d := kafkaDecoder{b: batch}
+	d.int64()
+	if int(d.int32()) != len(batch)-12 {
+		return 87
+	}
+	d.int32()
+	if d.int8() != 2 {
+		return 87
+	}
+	if crc := uint32(d.int32()); crc != crc32.Checksum(batch[21:], crc32cTable) {
+		b.badCRC = true
+		return 2
+	}
+	d.next(2 + 4 + 8 + 8 + 8 + 2 + 4)
+	records := make([]*mockKafkaRecord, 0)
+	for n := d.int32(); n > 0; n-- {
+		d.varint() // length
+		d.int8()
+		d.varint()
+		d.varint()
+		r := &mockKafkaRecord{topic: topic, partition: partition}
+		r.key = string(d.varBytes())
+		r.value = string(d.varBytes())
+		for h := d.varint(); h > 0; h-- {
+			if string(d.varBytes()) == "category" {
+				r.category = string(d.varBytes())
+			} else {
+				d.varBytes()
+			}
+		}
+		records = append(records, r)
+	}
+	if d.err != nil {
+		return 87
+	}
+	if b.produces > 1 || b.failFirst == 0 {
+		b.records = append(b.records, records...)
+	}
+	return 0
+}
+
+func TestMurmur2(t *testing.T) {
+	// from the tests of the java client
+	cases := map[string]int32{
+		"21":                         -973932308,
+		"foobar":                     -790332482,
+		"a-little-bit-long-string":   -985981536,
+		"a-little-bit-longer-string": -1486304829,
+		"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8": -58897971,
+		"abc": 479470107,
+	}
+	for k, v := range cases {
+		if h := murmur2([]byte(k)); h != v {
+			t.Errorf("Unexpected hash: key=%s hash=%d expect=%d", k, h, v)
+		}
+	}
+}
+
+func TestScram(t *testing.T) {
+	// RFC 7677
+	c := newScramClient(KafkaSASLScram256, "user", "pencil", "rOprNGfwEbeRWgbNEkqO")
+	if first := c.first(); first != "n,,n=user,r=rOprNGfwEbeRWgbNEkqO" {
+		t.Errorf("Unexpected client first message: %s", first)
+	}
+	final, err := c.final("r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096")
+	if err != nil || final != "c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=" {
+		t.Errorf("Unexpected client final message: %s %v", final, err)
+	}
+	if err := c.verify("v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4="); err != nil {
+		t.Errorf("Failed to verify server signature: %v", err)
+	}
+	if err := c.verify("v=AAAA"); err == nil {
+		t.Errorf("Invalid server signature should fail")
+	}
+	if _, err := c.final("r=other,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096"); err == nil {
+		t.Errorf("Server nonce should start with the client nonce")
+	}
+}
+
+func TestLogExportKafka(t *testing.T) {
+	broker := newMockKafkaBroker(t)
+	defer broker.ln.Close()
+
+	e, err := NewLogExporter(&share.CLUSLogExporter{
+		Name: "k1", Type: LogExporterKafka, Brokers: []string{broker.ln.Addr().String()},
+		Topic: "nv-events", Topics: []share.CLUSKafkaTopic{{Category: api.CategoryRuntime, Topic: "nv-security"}},
+		Categories: []string{api.CategoryEvent, api.CategoryRuntime}, PartitionKey: KafkaPartitionKeyWorkload,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create exporter: %v", err)
+	}
+
+	now := time.Now()
+	e.Export(&LogExportRecord{Category: api.CategoryEvent, Time: now, Data: []byte(`{"id":"e0"}`), Workload: "wl1"})
+	e.Export(&LogExportRecord{Category: api.CategoryThreat, Time: now, Data: []byte(`{"id":"t0"}`), Workload: "wl1"})
+	e.Export(&LogExportRecord{Category: api.CategoryViolation, Time: now, Data: []byte(`{"id":"v0"}`), Workload: "wl2"})
+	e.Close()
+
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	if broker.badCRC || len(broker.records) != 3 {
+		t.Fatalf("Unexpected records: crc=%v count=%d", broker.badCRC, len(broker.records))
+	}
+	for _, r := range broker.records {
+		switch r.value {
+		case `{"id":"e0"}`:
+			if r.topic != "nv-events" || r.category != api.CategoryEvent {
+				t.Errorf("Unexpected event record: %+v", r)
+			}
+		case `{"id":"t0"}`, `{"id":"v0"}`:
+			if r.topic != "nv-security" {
+				t.Errorf("Unexpected security record: %+v", r)
+			}
+		}
+		if expect := int32(murmur2([]byte(r.key))&0x7fffffff) % 2; r.partition != expect {
+			t.Errorf("Unexpected partition: %+v", r)
+		}
+	}
+	if sent, failed, _ := e.Stats(); sent != 3 || failed != 0 {
+		t.Errorf("Unexpected stats: sent=%d failed=%d", sent, failed)
+	}
+}
+
+func TestLogExportKafkaRetry(t *testing.T) {
+	broker := newMockKafkaBroker(t)
+	broker.failFirst = 6 // NOT_LEADER_OR_FOLLOWER
+	defer broker.ln.Close()
+
+	e, _ := NewLogExporter(&share.CLUSLogExporter{
+		Name: "k1", Type: LogExporterKafka, Brokers: []string{broker.ln.Addr().String()}, Topic: "nv",
+		Categories: []string{api.CategoryAudit},
+	})
+	e.Export(&LogExportRecord{Category: api.CategoryAudit, Time: time.Now(), Data: []byte(`{"id":"a0"}`)})
+
+	// wait for the flush and the retry
+	time.Sleep(logExportFlushPeriod + logExportRetryDelay + time.Second)
+	e.Close()
+
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	if broker.produces != 2 || len(broker.records) != 1 || broker.records[0].key != "" {
+		t.Errorf("Unexpected produce: requests=%d records=%d", broker.produces, len(broker.records))
+	}
+	if sent, failed, _ := e.Stats(); sent != 1 || failed != 0 {
+		t.Errorf("Unexpected stats: sent=%d failed=%d", sent, failed)
+	}
+}
@@ -0,0 +1,202 @@
+package common
+
+// Envelope encryption of the cloaked fields in kv. When it's enabled, cloaked values are encrypted by an AES-GCM data
+// key, which is stored in kv wrapped by a key encryption key from the local master key file or an external KMS.
+// Values encrypted by the built-in password key are still accepted, so existing kv data is readable before they are
+// re-encrypted.
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+const kvCipherPrefix = "nvenc:v1:"
+const KvDataKeySize = 32
+
+// returns the unwrapped data key of the id, nil if the key is unknown
+type KvDataKeyLoader func(id string) []byte
+
+var kvKeyMutex sync.RWMutex
+var kvDataKeys map[string][]byte = make(map[string][]byte)
+var kvActiveKeyID string
+var kvKeyLoader KvDataKeyLoader
+
+var errKvInvalidCipher = errors.New("Invalid ciphertext")
+
+var kvCipherRegex = regexp.MustCompile(`"nvenc:v1:[A-Za-z0-9_\-]+:[A-Za-z0-9+/=]+"`)
+
+func NewKvDataKey() ([]byte, error) {
+	key := make([]byte, KvDataKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// SetKvDataKeys replaces the data keys in use. New values are encrypted by the active key; an empty active key
+// disables envelope encryption.
+func SetKvDataKeys(active string, keys map[string][]byte) {
+	kvKeyMutex.Lock()
+	defer kvKeyMutex.Unlock()
+
+	kvDataKeys = make(map[string][]byte, len(keys))
+	for id, key := range keys {
+		kvDataKeys[id] = key
+	}
+	if _, ok := kvDataKeys[active]; ok {
+		kvActiveKeyID = active
+	} else {
+		kvActiveKeyID = ""
+	}
+}
+
+func SetKvDataKeyLoader(loader KvDataKeyLoader) {
+	kvKeyMutex.Lock()
+	defer kvKeyMutex.Unlock()
+	kvKeyLoader = loader
+}
+
+func GetKvActiveKeyID() string {
+	kvKeyMutex.RLock()
+	defer kvKeyMutex.RUnlock()
+	return kvActiveKeyID
+}
+
+func getKvDataKey(id string) []byte {
+	kvKeyMutex.RLock()
+	key, ok := kvDataKeys[id]
+	loader := kvKeyLoader
+	kvKeyMutex.RUnlock()
+	if ok || loader == nil {
+		return key
+	}
+
+	// the key could be created by another controller after rotation
+	if key = loader(id); key != nil {
+		kvKeyMutex.Lock()
+		kvDataKeys[id] = key
+		kvKeyMutex.Unlock()
+	}
+	return key
+}
+
+func IsKvEnvelopeEncrypted(value string) bool {
+	return strings.HasPrefix(value, kvCipherPrefix)
+}
+
+// Return the key id of an envelope-encrypted value
+func KvEnvelopeKeyID(value string) string {
+	if !IsKvEnvelopeEncrypted(value) {
+		return ""
+	}
+	if i := strings.Index(value[len(kvCipherPrefix):], ":"); i > 0 {
+		return value[len(kvCipherPrefix) : len(kvCipherPrefix)+i]
+	}
+	return ""
+}
+
+func encryptWithDataKey(id string, key []byte, plain string) (string, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	// key id is authenticated, so a value cannot be moved under another key
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), []byte(id))
+	return kvCipherPrefix + id + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptWithDataKey(id string, key []byte, encrypted string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encrypted[len(kvCipherPrefix)+len(id)+1:])
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errKvInvalidCipher
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(id))
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// EncryptCloaked encrypts a cloaked value by the active data key, or the built-in key if envelope encryption is
+// not enabled. The value must not be written to kv when it fails.
+func EncryptCloaked(plain string) (string, error) {
+	if plain == "" {
+		return "", nil
+	}
+
+	kvKeyMutex.RLock()
+	id := kvActiveKeyID
+	key := kvDataKeys[id]
+	kvKeyMutex.RUnlock()
+	if id == "" {
+		return utils.EncryptPassword(plain), nil
+	}
+
+	encrypted, err := encryptWithDataKey(id, key, plain)
+	if err != nil {
+		log.WithFields(log.Fields{"key": id, "error": err}).Error("Failed to encrypt")
+		return "", err
+	}
+	return encrypted, nil
+}
+
+func DecryptCloaked(encrypted string) string {
+	if !IsKvEnvelopeEncrypted(encrypted) {
+		return utils.DecryptPassword(encrypted)
+	}
+
+	id := KvEnvelopeKeyID(encrypted)
+	key := getKvDataKey(id)
+	if key == nil {
+		log.WithFields(log.Fields{"key": id}).Error("Unknown data key")
+		return ""
+	}
+	plain, err := decryptWithDataKey(id, key, encrypted)
+	if err != nil {
+		log.WithFields(log.Fields{"key": id, "error": err}).Error("Failed to decrypt")
+		return ""
+	}
+	return plain
+}
+
+// ExportCloaked converts the envelope-encrypted values in a json document to the built-in encryption, so the
+// exported configuration can be imported by clusters that don't share the data keys.
+func ExportCloaked(value []byte) []byte {
+	if !strings.Contains(string(value), kvCipherPrefix) {
+		return value
+	}
+	return kvCipherRegex.ReplaceAllFunc(value, func(quoted []byte) []byte {
+		plain := DecryptCloaked(string(quoted[1 : len(quoted)-1]))
+		return []byte(`"` + utils.EncryptPassword(plain) + `"`)
+	})
+}
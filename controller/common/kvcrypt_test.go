@@ -0,0 +1,98 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func TestKvEnvelopeEncryption(t *testing.T) {
+	defer SetKvDataKeys("", nil)
+
+	legacy, _ := EncryptCloaked("secret")
+	if IsKvEnvelopeEncrypted(legacy) || utils.DecryptPassword(legacy) != "secret" {
+		t.Errorf("Built-in key should be used when encryption is disabled: %s", legacy)
+	}
+
+	key1, _ := NewKvDataKey()
+	SetKvDataKeys("key1", map[string][]byte{"key1": key1})
+	encrypted, _ := EncryptCloaked("secret")
+	if !IsKvEnvelopeEncrypted(encrypted) || KvEnvelopeKeyID(encrypted) != "key1" {
+		t.Errorf("Unexpected encrypted value: %s", encrypted)
+	}
+	if plain := DecryptCloaked(encrypted); plain != "secret" {
+		t.Errorf("Failed to decrypt: %s", plain)
+	}
+	if plain := DecryptCloaked(legacy); plain != "secret" {
+		t.Errorf("Failed to decrypt legacy value: %s", plain)
+	}
+
+	// value is authenticated with its key id
+	tampered := strings.Replace(encrypted, "key1", "key2", 1)
+	key2, _ := NewKvDataKey()
+	SetKvDataKeys("key2", map[string][]byte{"key1": key1, "key2": key1})
+	if plain := DecryptCloaked(tampered); plain != "" {
+		t.Errorf("Tampered value should not be decrypted: %s", plain)
+	}
+
+	// unknown keys are loaded by the loader
+	SetKvDataKeys("key2", map[string][]byte{"key2": key2})
+	if plain := DecryptCloaked(encrypted); plain != "" {
+		t.Errorf("Value of unknown key should not be decrypted: %s", plain)
+	}
+	SetKvDataKeyLoader(func(id string) []byte {
+		if id == "key1" {
+			return key1
+		}
+		return nil
+	})
+	defer SetKvDataKeyLoader(nil)
+	if plain := DecryptCloaked(encrypted); plain != "secret" {
+		t.Errorf("Failed to decrypt with loaded key: %s", plain)
+	}
+}
+
+func TestExportCloaked(t *testing.T) {
+	defer SetKvDataKeys("", nil)
+
+	key, _ := NewKvDataKey()
+	SetKvDataKeys("key1", map[string][]byte{"key1": key})
+
+	var enc EncryptMarshaller
+	var dec DecryptUnmarshaller
+	secret := "mary321"
+	value, _ := enc.Marshal(&maskUser{Username: "mary", Password: "mary123", Secret: &secret})
+	if !strings.Contains(string(value), kvCipherPrefix) {
+		t.Errorf("Value is not envelope-encrypted: %s", value)
+	}
+
+	exported := ExportCloaked(value)
+	if strings.Contains(string(exported), kvCipherPrefix) {
+		t.Errorf("Exported value should use the built-in key: %s", exported)
+	}
+
+	// exported value can be read without the data key
+	SetKvDataKeys("", nil)
+	var user maskUser
+	if err := dec.Unmarshal(exported, &user); err != nil || user.Username != "mary" || user.Password != "mary123" || *user.Secret != "mary321" {
+		t.Errorf("Unexpected exported value: %+v, %v", user, err)
+	}
+}
+
+func TestEncryptCloakedFailure(t *testing.T) {
+	defer SetKvDataKeys("", nil)
+
+	// an invalid data key cannot encrypt
+	SetKvDataKeys("bad", map[string][]byte{"bad": []byte("short")})
+	if value, err := EncryptCloaked("secret"); err == nil || value != "" {
+		t.Errorf("Encryption should fail: value=%s", value)
+	}
+
+	// the value is not written to kv in plain text or empty
+	var enc EncryptMarshaller
+	secret := "mary321"
+	if value, err := enc.Marshal(&maskUser{Username: "mary", Password: "mary123", Secret: &secret}); err == nil {
+		t.Errorf("Marshal should fail: %s", value)
+	}
+}
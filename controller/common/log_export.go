@@ -0,0 +1,411 @@
+package common
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+const (
+	LogExporterSplunk  = "splunk"
+	LogExporterElastic = "elasticsearch"
+	LogExporterKafka   = "kafka"
+)
+
+const logExportQueueSize = 10000
+const logExportBatchMax = 500
+const logExportFlushPeriod = time.Second * 2
+const logExportRetryMax = 3
+const logExportRetryDelay = time.Second
+const logExportTimeout = time.Second * 15
+const logExportSource = "neuvector"
+const logExportDefaultIndex = "neuvector"
+
+type LogExportRecord struct {
+	Category string
+	Time     time.Time
+	Data     []byte // log in json
+	Cluster  string
+	Workload string
+}
+
+// LogExporter pushes logs to Splunk HTTP Event Collector, Elasticsearch bulk API or Kafka in batches. The logs are
+// queued in a bounded buffer; when the collector cannot keep up, new logs are dropped instead of blocking the
+// log processing. A failed batch is retried with backoff.
+type LogExporter struct {
+	cfg     share.CLUSLogExporter
+	catSet  utils.Set
+	client  *http.Client
+	kafka   *kafkaProducer
+	queue   chan *LogExportRecord
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+	sent    uint64
+	failed  uint64
+	dropped uint64
+}
+
+// Convert the configured categories to the log categories
+func LogExportCategories(cats []string) utils.Set {
+	catSet := utils.NewSet()
+	for _, cat := range cats {
+		if cat == api.CategoryRuntime {
+			catSet.Add(api.CategoryViolation)
+			catSet.Add(api.CategoryThreat)
+			catSet.Add(api.CategoryIncident)
+		} else {
+			catSet.Add(cat)
+		}
+	}
+	return catSet
+}
+
+func NewLogExporter(cfg *share.CLUSLogExporter) (*LogExporter, error) {
+	switch cfg.Type {
+	case LogExporterSplunk, LogExporterElastic, LogExporterKafka:
+	default:
+		return nil, fmt.Errorf("Unsupported log exporter type: %s", cfg.Type)
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.SkipVerify}
+	if cfg.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(cfg.CACert)) {
+			return nil, fmt.Errorf("Invalid CA certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	e := &LogExporter{
+		cfg:    *cfg,
+		catSet: LogExportCategories(cfg.Categories),
+		client: &http.Client{
+			Timeout: logExportTimeout,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+		},
+		queue:  make(chan *LogExportRecord, logExportQueueSize),
+		stopCh: make(chan struct{}),
+	}
+	if cfg.Type == LogExporterKafka {
+		if !cfg.TLS {
+			tlsConfig = nil
+		}
+		e.kafka = newKafkaProducer(cfg.Brokers, tlsConfig, cfg.SASLMechanism, cfg.Username, cfg.Password, logExportTimeout)
+	}
+	e.wg.Add(1)
+	go e.run()
+	return e, nil
+}
+
+// Stop the exporter, the queued logs are sent before it returns
+func (e *LogExporter) Close() {
+	close(e.stopCh)
+	e.wg.Wait()
+	if e.kafka != nil {
+		e.kafka.close()
+	}
+}
+
+func (e *LogExporter) Accept(cat string) bool {
+	return e.catSet.Contains(cat)
+}
+
+// Return false if the log is dropped because the queue is full
+func (e *LogExporter) Export(rec *LogExportRecord) bool {
+	select {
+	case e.queue <- rec:
+		return true
+	default:
+		if dropped := atomic.AddUint64(&e.dropped, 1); dropped%1000 == 1 {
+			log.WithFields(log.Fields{"name": e.cfg.Name, "dropped": dropped}).Error("Log export queue is full")
+		}
+		return false
+	}
+}
+
+func (e *LogExporter) Stats() (sent, failed, dropped uint64) {
+	return atomic.LoadUint64(&e.sent), atomic.LoadUint64(&e.failed), atomic.LoadUint64(&e.dropped)
+}
+
+func (e *LogExporter) run() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(logExportFlushPeriod)
+	defer ticker.Stop()
+
+	batch := make([]*LogExportRecord, 0, logExportBatchMax)
+	for {
+		select {
+		case rec := <-e.queue:
+			if batch = append(batch, rec); len(batch) >= logExportBatchMax {
+				e.flush(batch)
+				batch = make([]*LogExportRecord, 0, logExportBatchMax)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				e.flush(batch)
+				batch = make([]*LogExportRecord, 0, logExportBatchMax)
+			}
+		case <-e.stopCh:
+		drain:
+			for {
+				select {
+				case rec := <-e.queue:
+					batch = append(batch, rec)
+				default:
+					break drain
+				}
+			}
+			// one attempt for the remaining logs
+			for len(batch) > 0 {
+				n := len(batch)
+				if n > logExportBatchMax {
+					n = logExportBatchMax
+				}
+				if retry := e.send(batch[:n]); len(retry) > 0 {
+					atomic.AddUint64(&e.failed, uint64(len(retry)))
+				}
+				batch = batch[n:]
+			}
+			return
+		}
+	}
+}
+
+// Retry with backoff until the batch is sent, or give up if the exporter stops
+func (e *LogExporter) flush(batch []*LogExportRecord) {
+	delay := logExportRetryDelay
+	batch = e.send(batch)
+	for i := 0; i < logExportRetryMax && len(batch) > 0; i++ {
+		select {
+		case <-time.After(delay):
+		case <-e.stopCh:
+			i = logExportRetryMax
+			continue
+		}
+		delay *= 2
+		batch = e.send(batch)
+	}
+	if len(batch) == 0 {
+		return
+	}
+	atomic.AddUint64(&e.failed, uint64(len(batch)))
+	log.WithFields(log.Fields{"name": e.cfg.Name, "count": len(batch)}).Error("Failed to export logs")
+}
+
+// Send a batch and return the logs that can be retried. The logs rejected by the collector are not retried.
+func (e *LogExporter) send(batch []*LogExportRecord) []*LogExportRecord {
+	var retry []*LogExportRecord
+	var rejected int
+	var err error
+	switch e.cfg.Type {
+	case LogExporterSplunk:
+		retry, rejected, err = e.sendSplunk(batch)
+	case LogExporterElastic:
+		retry, rejected, err = e.sendElastic(batch)
+	case LogExporterKafka:
+		retry, rejected, err = e.sendKafka(batch)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"name": e.cfg.Name, "count": len(batch), "error": err}).Error()
+	}
+	atomic.AddUint64(&e.sent, uint64(len(batch)-len(retry)-rejected))
+	atomic.AddUint64(&e.failed, uint64(rejected))
+	return retry
+}
+
+func (e *LogExporter) post(url, contentType string, body []byte) (int, []byte, error) {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	switch {
+	case e.cfg.Type == LogExporterSplunk:
+		req.Header.Set("Authorization", "Splunk "+e.cfg.Token)
+	case e.cfg.Token != "":
+		req.Header.Set("Authorization", "ApiKey "+e.cfg.Token)
+	case e.cfg.Username != "":
+		req.SetBasicAuth(e.cfg.Username, e.cfg.Password)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, data, err
+}
+
+func isRetryStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+type splunkEvent struct {
+	Time       float64         `json:"time"`
+	Source     string          `json:"source"`
+	SourceType string          `json:"sourcetype"`
+	Index      string          `json:"index,omitempty"`
+	Event      json.RawMessage `json:"event"`
+}
+
+func formatSplunkBatch(batch []*LogExportRecord, index string) []byte {
+	var buf bytes.Buffer
+	for _, rec := range batch {
+		data, _ := json.Marshal(&splunkEvent{
+			Time:       float64(rec.Time.UnixNano()/int64(time.Millisecond)) / 1000,
+			Source:     logExportSource,
+			SourceType: fmt.Sprintf("%s:%s", logExportSource, rec.Category),
+			Index:      index,
+			Event:      json.RawMessage(rec.Data),
+		})
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// The batch is sent to HEC as a whole, so it's either accepted or retried as a whole
+func (e *LogExporter) sendSplunk(batch []*LogExportRecord) ([]*LogExportRecord, int, error) {
+	url := strings.TrimSuffix(e.cfg.URL, "/") + "/services/collector/event"
+	status, data, err := e.post(url, "application/json", formatSplunkBatch(batch, e.cfg.Index))
+	if err != nil {
+		return batch, 0, err
+	} else if status == http.StatusOK {
+		return nil, 0, nil
+	} else if isRetryStatus(status) {
+		return batch, 0, fmt.Errorf("Status %d: %s", status, string(data))
+	}
+	return nil, len(batch), fmt.Errorf("Status %d: %s", status, string(data))
+}
+
+func formatElasticBatch(batch []*LogExportRecord, index string) []byte {
+	if index == "" {
+		index = logExportDefaultIndex
+	}
+	action, _ := json.Marshal(map[string]interface{}{"index": map[string]string{"_index": index}})
+
+	var buf bytes.Buffer
+	for _, rec := range batch {
+		buf.Write(action)
+		buf.WriteByte('\n')
+		// add the timestamp and category to the log object
+		fmt.Fprintf(&buf, `{"@timestamp":"%s","category":"%s"`, rec.Time.UTC().Format(time.RFC3339Nano), rec.Category)
+		if len(rec.Data) > 2 && rec.Data[0] == '{' {
+			buf.WriteByte(',')
+			buf.Write(rec.Data[1:])
+		} else {
+			buf.WriteByte('}')
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+type elasticBulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+	} `json:"items"`
+}
+
+// Only the rejected items that can succeed later are retried
+func (e *LogExporter) sendElastic(batch []*LogExportRecord) ([]*LogExportRecord, int, error) {
+	url := strings.TrimSuffix(e.cfg.URL, "/") + "/_bulk"
+	status, data, err := e.post(url, "application/x-ndjson", formatElasticBatch(batch, e.cfg.Index))
+	if err != nil {
+		return batch, 0, err
+	} else if isRetryStatus(status) {
+		return batch, 0, fmt.Errorf("Status %d: %s", status, string(data))
+	} else if status != http.StatusOK {
+		return nil, len(batch), fmt.Errorf("Status %d: %s", status, string(data))
+	}
+
+	var resp elasticBulkResponse
+	if err := json.Unmarshal(data, &resp); err != nil || !resp.Errors {
+		return nil, 0, nil
+	}
+
+	retry := make([]*LogExportRecord, 0)
+	var rejected int
+	for i, item := range resp.Items {
+		if i >= len(batch) {
+			break
+		}
+		for _, result := range item {
+			if isRetryStatus(result.Status) {
+				retry = append(retry, batch[i])
+			} else if result.Status >= http.StatusBadRequest {
+				rejected++
+			}
+		}
+	}
+	if rejected > 0 {
+		return retry, rejected, fmt.Errorf("%d logs rejected", rejected)
+	}
+	return retry, 0, nil
+}
+
+// The topic of the category, the runtime categories can share the topic of security-event
+func (e *LogExporter) kafkaTopic(cat string) string {
+	for _, t := range e.cfg.Topics {
+		if t.Category == cat {
+			return t.Topic
+		}
+	}
+	switch cat {
+	case api.CategoryViolation, api.CategoryThreat, api.CategoryIncident:
+		for _, t := range e.cfg.Topics {
+			if t.Category == api.CategoryRuntime {
+				return t.Topic
+			}
+		}
+	}
+	return e.cfg.Topic
+}
+
+func (e *LogExporter) sendKafka(batch []*LogExportRecord) ([]*LogExportRecord, int, error) {
+	msgs := make([]*kafkaMessage, len(batch))
+	recs := make(map[*kafkaMessage]*LogExportRecord, len(batch))
+	for i, rec := range batch {
+		m := &kafkaMessage{
+			topic:   e.kafkaTopic(rec.Category),
+			value:   rec.Data,
+			headers: []kafkaHeader{{key: "category", value: []byte(rec.Category)}},
+			time:    rec.Time,
+		}
+		switch e.cfg.PartitionKey {
+		case KafkaPartitionKeyCluster:
+			m.key = []byte(rec.Cluster)
+		case KafkaPartitionKeyWorkload:
+			m.key = []byte(rec.Workload)
+		}
+		msgs[i] = m
+		recs[m] = rec
+	}
+
+	retryMsgs, rejected, err := e.kafka.produce(msgs)
+	retry := make([]*LogExportRecord, len(retryMsgs))
+	for i, m := range retryMsgs {
+		retry[i] = recs[m]
+	}
+	return retry, rejected, err
+}
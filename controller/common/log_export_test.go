@@ -0,0 +1,148 @@
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestLogExportSplunk(t *testing.T) {
+	var mutex sync.Mutex
+	var events []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/collector/event" || r.Header.Get("Authorization") != "Splunk token1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		scanner := bufio.NewScanner(r.Body)
+		mutex.Lock()
+		for scanner.Scan() {
+			var ev map[string]interface{}
+			json.Unmarshal(scanner.Bytes(), &ev)
+			events = append(events, ev)
+		}
+		mutex.Unlock()
+		w.Write([]byte(`{"text":"Success","code":0}`))
+	}))
+	defer server.Close()
+
+	e, err := NewLogExporter(&share.CLUSLogExporter{
+		Name: "s1", Type: LogExporterSplunk, URL: server.URL, Token: "token1", Index: "sec",
+		Categories: []string{api.CategoryRuntime},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create exporter: %v", err)
+	}
+	if !e.Accept(api.CategoryThreat) || e.Accept(api.CategoryAudit) {
+		t.Errorf("Unexpected categories: %v", e.catSet)
+	}
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		e.Export(&LogExportRecord{Category: api.CategoryThreat, Time: now, Data: []byte(fmt.Sprintf(`{"id":"t%d"}`, i))})
+	}
+	e.Close()
+
+	if len(events) != 3 {
+		t.Fatalf("Unexpected event count: %d", len(events))
+	}
+	ev := events[2]
+	if ev["sourcetype"] != "neuvector:threat" || ev["index"] != "sec" || ev["event"].(map[string]interface{})["id"] != "t2" {
+		t.Errorf("Unexpected event: %+v", ev)
+	}
+	if sent, failed, dropped := e.Stats(); sent != 3 || failed != 0 || dropped != 0 {
+		t.Errorf("Unexpected stats: sent=%d failed=%d dropped=%d", sent, failed, dropped)
+	}
+}
+
+func TestLogExportElasticRetry(t *testing.T) {
+	var mutex sync.Mutex
+	var requests int
+	docs := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		if r.URL.Path != "/_bulk" || user != "elastic" || pass != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		lines := bytes.Split(bytes.TrimSpace(body), []byte("\n"))
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		requests++
+		// the first request: the 2nd doc is throttled and the 3rd doc is invalid
+		items := make([]string, 0)
+		for i := 1; i < len(lines); i += 2 {
+			var doc map[string]interface{}
+			json.Unmarshal(lines[i], &doc)
+			id := doc["id"].(string)
+			status := 201
+			if requests == 1 && id == "a1" {
+				status = 429
+			} else if requests == 1 && id == "a2" {
+				status = 400
+			} else {
+				docs[id]++
+			}
+			if doc["category"] != api.CategoryAudit || doc["@timestamp"] == nil {
+				status = 400
+			}
+			items = append(items, fmt.Sprintf(`{"index":{"status":%d}}`, status))
+		}
+		fmt.Fprintf(w, `{"errors":true,"items":[%s]}`, strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	e, _ := NewLogExporter(&share.CLUSLogExporter{
+		Name: "e1", Type: LogExporterElastic, URL: server.URL + "/", Username: "elastic", Password: "pass",
+		Categories: []string{api.CategoryAudit},
+	})
+	for i := 0; i < 3; i++ {
+		e.Export(&LogExportRecord{Category: api.CategoryAudit, Time: time.Now(), Data: []byte(fmt.Sprintf(`{"id":"a%d"}`, i))})
+	}
+
+	// wait for the flush and the retry
+	time.Sleep(logExportFlushPeriod + logExportRetryDelay + time.Second)
+	e.Close()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if requests != 2 || docs["a0"] != 1 || docs["a1"] != 1 || docs["a2"] != 0 {
+		t.Errorf("Unexpected requests: requests=%d docs=%+v", requests, docs)
+	}
+	if sent, failed, _ := e.Stats(); sent != 2 || failed != 1 {
+		t.Errorf("Unexpected stats: sent=%d failed=%d", sent, failed)
+	}
+}
+
+func TestLogExportBackpressure(t *testing.T) {
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer server.Close()
+
+	e, _ := NewLogExporter(&share.CLUSLogExporter{Name: "s1", Type: LogExporterSplunk, URL: server.URL, Token: "t"})
+	var dropped int
+	for i := 0; i < logExportQueueSize+logExportBatchMax*2; i++ {
+		if !e.Export(&LogExportRecord{Category: api.CategoryEvent, Time: time.Now(), Data: []byte(`{}`)}) {
+			dropped++
+		}
+	}
+	if _, _, d := e.Stats(); dropped == 0 || d != uint64(dropped) {
+		t.Errorf("Logs should be dropped when the queue is full: dropped=%d stats=%d", dropped, d)
+	}
+	close(block)
+}
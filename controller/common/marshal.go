@@ -160,7 +160,7 @@ func unmarshal(cloak string, data interface{}) error {
 				switch cloak {
 				case cloakDecrypt:
 					if val.CanSet() {
-						s := utils.DecryptPassword(val.Interface().(string))
+						s := DecryptCloaked(val.Interface().(string))
 						val.SetString(s)
 					}
 				}
@@ -229,6 +229,10 @@ func unmarshalValue(cloak string, v reflect.Value) error {
 
 func marshal(cloak string, data interface{}) (interface{}, error) {
 	v := reflect.ValueOf(data)
+	if !v.IsValid() {
+		// nil interface, e.g. null value in map[string]interface{}
+		return nil, nil
+	}
 	t := v.Type()
 
 	if t.Kind() == reflect.Ptr {
@@ -286,7 +290,10 @@ func marshal(cloak string, data interface{}) (interface{}, error) {
 					m := api.RESTMaskedValue
 					val = reflect.ValueOf(m)
 				case cloakEncrypt:
-					m := utils.EncryptPassword(val.Interface().(string))
+					m, err := EncryptCloaked(val.Interface().(string))
+					if err != nil {
+						return nil, err
+					}
 					val = reflect.ValueOf(m)
 				}
 			}
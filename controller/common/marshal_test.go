@@ -141,6 +141,16 @@ func TestSpecialType(t *testing.T) {
 	}
 }
 
+func TestNilInterface(t *testing.T) {
+	var m EmptyMarshaller
+
+	d := map[string]interface{}{"a": nil, "b": []interface{}{nil, "x"}}
+	body, err := m.Marshal(d)
+	if err != nil || string(body) != `{"a":null,"b":[null,"x"]}` {
+		t.Errorf("Incorrect nil interface marshal: %s, %v", string(body[:]), err)
+	}
+}
+
 func TestAuthServer(t *testing.T) {
 	body := "{\"config\":{\"ldap\":{\"base_dn\":\"abc\",\"bind_dn\":\"abc\",\"bind_password\":\"very sensitive\",\"directory\":\"OpenLDAP\",\"enable\":true,\"hostname\":\"1.2.3.4\",\"role_groups\":{\"admin\":[],\"reader\":[]}},\"name\":\"ldap1\"}}"
 
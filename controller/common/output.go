@@ -2,14 +2,19 @@ package common
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
+	"regexp"
 	"strings"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
 	log "github.com/sirupsen/logrus"
 
@@ -24,14 +29,28 @@ const notificationHeader = "notification"
 const syslogTimeout = time.Second * 30
 const syslogDialTimeout = time.Second * 30
 
+// SD-ID of the structured data. 32473 is the enterprise number reserved for documentation (RFC 5612), as
+// there is no registered number for the product.
+const syslogSDID = "neuvector@32473"
+const syslogSDParamNameMax = 32
+
+type syslogCategoryMap struct {
+	facility syslog.Priority
+	level    string
+}
+
 type Syslogger struct {
-	writer     *syslog.Writer
-	proto      string
-	addr       string
-	catSet     utils.Set
-	prio       syslog.Priority
-	inJSON     bool
-	serverCert string
+	writer         *syslog.Writer
+	proto          string
+	addr           string
+	catSet         utils.Set
+	catMaps        map[string]*syslogCategoryMap
+	prio           syslog.Priority
+	inJSON         bool
+	structuredData bool
+	serverCert     string
+	clientCert     string
+	clientKey      string
 }
 
 func NewSyslogger(cfg *share.CLUSSyslogConfig) *Syslogger {
@@ -59,13 +78,33 @@ func NewSyslogger(cfg *share.CLUSSyslogConfig) *Syslogger {
 			catSet.Add(cat)
 		}
 	}
+	catMaps := make(map[string]*syslogCategoryMap)
+	for _, m := range cfg.SyslogCategoryMaps {
+		cm := &syslogCategoryMap{facility: syslogFacility, level: m.Level}
+		if m.Facility != "" {
+			if f, ok := FacilityToPrio(m.Facility); ok {
+				cm.facility = f
+			}
+		}
+		if m.Category == api.CategoryRuntime {
+			catMaps[api.CategoryViolation] = cm
+			catMaps[api.CategoryThreat] = cm
+			catMaps[api.CategoryIncident] = cm
+		} else {
+			catMaps[m.Category] = cm
+		}
+	}
 	return &Syslogger{
-		proto:      proto,
-		addr:       fmt.Sprintf("%s:%d", server, cfg.SyslogPort),
-		catSet:     catSet,
-		prio:       prio,
-		inJSON:     cfg.SyslogInJSON,
-		serverCert: cfg.SyslogServerCert,
+		proto:          proto,
+		addr:           fmt.Sprintf("%s:%d", server, cfg.SyslogPort),
+		catSet:         catSet,
+		catMaps:        catMaps,
+		prio:           prio,
+		inJSON:         cfg.SyslogInJSON,
+		structuredData: cfg.SyslogStructuredData,
+		serverCert:     cfg.SyslogServerCert,
+		clientCert:     cfg.SyslogClientCert,
+		clientKey:      cfg.SyslogClientKey,
 	}
 }
 
@@ -84,19 +123,33 @@ func (s *Syslogger) Send(elog interface{}, level, cat, header string) error {
 		return nil
 	}
 
+	// The level of the log decides whether it's sent, the category map decides how it's sent
+	facility := syslogFacility
+	if cm, ok := s.catMaps[cat]; ok {
+		facility = cm.facility
+		if cm.level != "" {
+			prio, _ = LevelToPrio(cm.level)
+		}
+	}
+
+	var logText string
 	if s.inJSON {
 		if data, _ := json.Marshal(elog); len(data) > 2 {
-			logText := fmt.Sprintf("{\"%s\": \"%s\", %s", notificationHeader, header, string(data[1:][:]))
-			return s.send(logText, prio)
+			logText = fmt.Sprintf("{\"%s\": \"%s\", %s", notificationHeader, header, string(data[1:][:]))
 		}
 	} else {
-		if logText := struct2Text(elog); logText != "" {
+		if logText = struct2Text(elog); logText != "" {
 			logText = fmt.Sprintf("%s=%s,%s", notificationHeader, header, logText)
-			return s.send(logText, prio)
 		}
 	}
+	if logText == "" {
+		return nil
+	}
 
-	return nil
+	if s.structuredData {
+		logText = fmt.Sprintf("%s %s", struct2StructuredData(elog, header), logText)
+	}
+	return s.send(logText, facility|prio)
 }
 
 func appendLogField(logText string, tag string, v reflect.Value) string {
@@ -154,32 +207,62 @@ func struct2Text(elog interface{}) string {
 	return logText
 }
 
-func (s *Syslogger) sendWithLevel(text string, prio syslog.Priority) error {
-	switch prio {
-	case syslog.LOG_EMERG:
-		return s.writer.Emerg(text)
-	case syslog.LOG_ALERT:
-		return s.writer.Alert(text)
-	case syslog.LOG_CRIT:
-		return s.writer.Crit(text)
-	case syslog.LOG_ERR:
-		return s.writer.Err(text)
-	case syslog.LOG_WARNING:
-		return s.writer.Warning(text)
-	case syslog.LOG_NOTICE:
-		return s.writer.Notice(text)
-	case syslog.LOG_INFO:
-		return s.writer.Info(text)
-	case syslog.LOG_DEBUG:
-		return s.writer.Debug(text)
-	default:
-		return s.writer.Debug(text)
+var sdParamValueEscaper *strings.Replacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// Call the function with the json name and value of each non-empty scalar field of the log
+func forEachLogScalar(elog interface{}, fn func(name string, v reflect.Value)) {
+	visit := func(tag string, v reflect.Value) {
+		if tag == "" || tag == "-" {
+			return
+		}
+		switch v.Kind() {
+		case reflect.String, reflect.Bool,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+		default:
+			return
+		}
+		if isEmptyValue(v) {
+			return
+		}
+		fn(strings.Split(tag, ",")[0], v)
+	}
+
+	v := reflect.ValueOf(elog).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		t := v.Type().Field(i)
+		f := v.Field(i)
+
+		if f.Kind() == reflect.Struct && t.Anonymous {
+			for j := 0; j < f.NumField(); j++ {
+				visit(f.Type().Field(j).Tag.Get("json"), f.Field(j))
+			}
+		} else {
+			visit(t.Tag.Get("json"), f)
+		}
 	}
 }
 
+// Return the RFC 5424 structured data element of the scalar fields of the log. Only scalar values are the
+// event attributes, the others are in the message.
+func struct2StructuredData(elog interface{}, header string) string {
+	var sd bytes.Buffer
+
+	fmt.Fprintf(&sd, "[%s %s=\"%s\"", syslogSDID, notificationHeader, header)
+	forEachLogScalar(elog, func(name string, v reflect.Value) {
+		if len(name) > syslogSDParamNameMax || strings.ContainsAny(name, "= ]\"") {
+			return
+		}
+		fmt.Fprintf(&sd, " %s=\"%s\"", name, sdParamValueEscaper.Replace(fmt.Sprintf("%v", v)))
+	})
+	sd.WriteByte(']')
+	return sd.String()
+}
+
 func (s *Syslogger) send(text string, prio syslog.Priority) error {
 	if s.writer != nil {
-		if err := s.sendWithLevel(text, prio); err == nil {
+		if _, err := s.writer.WriteWithPriority(prio, []byte(text)); err == nil {
 			return nil
 		}
 
@@ -188,19 +271,44 @@ func (s *Syslogger) send(text string, prio syslog.Priority) error {
 	if wr, err := s.makeDial(prio, syslogDialTimeout); err != nil {
 		return err
 	} else {
-		wr.SetFormatter(syslog.RFC5424Formatter)
+		if s.structuredData {
+			wr.SetFormatter(syslog.RFC5424StructuredDataFormatter)
+		} else {
+			wr.SetFormatter(syslog.RFC5424Formatter)
+		}
 		wr.SetSendTimeout(syslogTimeout)
 		s.writer = wr
-		return s.sendWithLevel(text, prio)
+		_, err = wr.WriteWithPriority(prio, []byte(text))
+		return err
 	}
 }
 
+// The server certificate is used as the CA to verify the server. The client certificate is presented
+// if the server requires the client authentication.
+func (s *Syslogger) tlsConfig() (*tls.Config, error) {
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM([]byte(s.serverCert))
+	cfg := &tls.Config{RootCAs: pool}
+	if s.clientCert != "" {
+		cert, err := tls.X509KeyPair([]byte(s.clientCert), []byte(s.clientKey))
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
 func (s *Syslogger) makeDial(prio syslog.Priority, timeout time.Duration) (*syslog.Writer, error) {
 	if s.proto == "tcp+tls" {
-		return syslog.DialWithTLSCert("tcp+tls", s.addr, timeout, syslogFacility|prio, "neuvector", []byte(s.serverCert))
+		cfg, err := s.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		return syslog.DialWithTLSConfig("tcp+tls", s.addr, timeout, prio, "neuvector", cfg)
 	}
 
-	return syslog.Dial(s.proto, s.addr, timeout, syslogFacility|prio, "neuvector")
+	return syslog.Dial(s.proto, s.addr, timeout, prio, "neuvector")
 }
 
 // --
@@ -210,14 +318,42 @@ const contentType = "application/json"
 const webhookInfo = "Neuvector webhook is configured."
 const requestTimeout = time.Duration(5 * time.Second)
 
+const (
+	slackHeaderMax        = 150
+	slackTextMax          = 3000
+	slackFieldMax         = 10
+	pagerDutySummaryMax   = 1024
+	pagerDutyDedupKeyMax  = 255
+	pagerDutyEventTrigger = "trigger"
+)
+
+// PagerDuty event severities
+const (
+	PagerDutySeverityCritical = "critical"
+	PagerDutySeverityError    = "error"
+	PagerDutySeverityWarning  = "warning"
+	PagerDutySeverityInfo     = "info"
+)
+
 type Webhook struct {
-	url    string
-	client *http.Client
+	url        string
+	headers    []share.CLUSWebhookHeader
+	routingKey string // PagerDuty integration key
+	client     *http.Client
+}
+
+// Options of the response rule that sends the notification
+type WebhookOptions struct {
+	Template string // JSON payload template, empty for the format of the webhook type
+	Severity string // PagerDuty severity, empty to map from the log level
+	DedupKey string // PagerDuty dedup key with {{field}} placeholders, empty for the default key
 }
 
-func NewWebHook(url string) *Webhook {
+func NewWebHook(url string, headers []share.CLUSWebhookHeader, routingKey string) *Webhook {
 	w := &Webhook{
-		url: url,
+		url:        url,
+		headers:    headers,
+		routingKey: routingKey,
 		client: &http.Client{
 			Timeout: requestTimeout,
 			Transport: &http.Transport{
@@ -239,26 +375,272 @@ func (w *Webhook) Validate() error {
 	return w.httpRequest(jsonValue)
 }
 
-func (w *Webhook) Notify(elog interface{}, target, level, category, cluster, title string) {
+// The payload template is a JSON document. The placeholders, {{field}}, in its string values are
+// replaced by the log fields with the same json name, plus level, cluster, category and title.
+// A string value that is a single placeholder takes the type of the field.
+var webhookTmplRegex *regexp.Regexp = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_]+)\s*\}\}`)
+
+func ValidateWebhookTemplate(tmpl string) error {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(tmpl), &doc); err != nil {
+		return fmt.Errorf("Invalid JSON payload template: %s", err.Error())
+	}
+	return nil
+}
+
+func ValidatePagerDutySeverity(severity string) bool {
+	switch severity {
+	case PagerDutySeverityCritical, PagerDutySeverityError, PagerDutySeverityWarning, PagerDutySeverityInfo:
+		return true
+	}
+	return false
+}
+
+func fillWebhookText(text string, fields map[string]interface{}) string {
+	return webhookTmplRegex.ReplaceAllStringFunc(text, func(s string) string {
+		name := webhookTmplRegex.FindStringSubmatch(s)[1]
+		switch val := fields[name].(type) {
+		case nil:
+			return ""
+		case string:
+			return val
+		default:
+			data, _ := json.Marshal(val)
+			return string(data)
+		}
+	})
+}
+
+func fillWebhookTemplate(node interface{}, fields map[string]interface{}) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			v[key] = fillWebhookTemplate(val, fields)
+		}
+	case []interface{}:
+		for i, val := range v {
+			v[i] = fillWebhookTemplate(val, fields)
+		}
+	case string:
+		if m := webhookTmplRegex.FindStringSubmatch(v); m != nil && m[0] == v {
+			return fields[m[1]]
+		}
+		return fillWebhookText(v, fields)
+	}
+	return node
+}
+
+func webhookFields(elog interface{}, level, category, cluster, title string) map[string]interface{} {
+	fields := make(map[string]interface{})
+	data, _ := json.Marshal(elog)
+	json.Unmarshal(data, &fields)
+	fields["level"] = strings.ToUpper(LevelToString(level))
+	fields["cluster"] = cluster
+	fields["category"] = category
+	fields["title"] = title
+	return fields
+}
+
+func renderWebhookTemplate(tmpl string, elog interface{}, level, category, cluster, title string) ([]byte, error) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(tmpl), &doc); err != nil {
+		return nil, err
+	}
+
+	fields := webhookFields(elog, level, category, cluster, title)
+	return json.Marshal(fillWebhookTemplate(doc, fields))
+}
+
+func truncateText(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	// don't cut in the middle of a multi-byte character
+	text = text[:max-3]
+	for len(text) > 0 && !utf8.ValidString(text) {
+		text = text[:len(text)-1]
+	}
+	return text + "..."
+}
+
+type logFact struct {
+	name  string
+	value string
+}
+
+func logFacts(elog interface{}) []logFact {
+	facts := make([]logFact, 0)
+	forEachLogScalar(elog, func(name string, v reflect.Value) {
+		facts = append(facts, logFact{name: name, value: fmt.Sprintf("%v", v)})
+	})
+	return facts
+}
+
+var slackEscaper *strings.Replacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Block Kit message. The text is the fallback shown in the notifications.
+func slackPayload(elog interface{}, logText, level, category, cluster, title string) []byte {
+	header := fmt.Sprintf("%s: %s level", strings.Title(category), strings.ToUpper(LevelToString(level)))
+	text := fmt.Sprintf("*%s*\n_%s_\n>>> %s=%s,%s", header, title, notificationHeader, category, logText)
+
+	blocks := []map[string]interface{}{
+		{"type": "header", "text": map[string]interface{}{"type": "plain_text", "text": truncateText(header, slackHeaderMax)}},
+		{"type": "section", "text": map[string]interface{}{"type": "mrkdwn", "text": truncateText("*"+slackEscaper.Replace(title)+"*", slackTextMax)}},
+	}
+
+	facts := logFacts(elog)
+	if len(facts) > 0 {
+		n := len(facts)
+		if n > slackFieldMax {
+			n = slackFieldMax
+		}
+		fields := make([]map[string]interface{}, n)
+		for i, f := range facts[:n] {
+			text := fmt.Sprintf("*%s*\n%s", f.name, slackEscaper.Replace(f.value))
+			fields[i] = map[string]interface{}{"type": "mrkdwn", "text": truncateText(text, slackTextMax/slackFieldMax)}
+		}
+		blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
+	}
+	if len(facts) > slackFieldMax {
+		rest := make([]string, 0, len(facts)-slackFieldMax)
+		for _, f := range facts[slackFieldMax:] {
+			rest = append(rest, fmt.Sprintf("%s=%s", f.name, slackEscaper.Replace(f.value)))
+		}
+		blocks = append(blocks, map[string]interface{}{
+			"type":     "context",
+			"elements": []map[string]interface{}{{"type": "mrkdwn", "text": truncateText(strings.Join(rest, ", "), slackTextMax)}},
+		})
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"username": fmt.Sprintf("NeuVector - %s", cluster),
+		"text":     text,
+		"blocks":   blocks,
+	})
+	return data
+}
+
+// Message with an adaptive card attachment
+func teamsPayload(elog interface{}, level, category, cluster, title string) []byte {
+	color := "default"
+	switch level {
+	case api.LogLevelEMERG, api.LogLevelALERT, api.LogLevelCRIT, api.LogLevelERR:
+		color = "attention"
+	case api.LogLevelWARNING:
+		color = "warning"
+	}
+
+	facts := []map[string]string{{"title": "cluster", "value": cluster}}
+	for _, f := range logFacts(elog) {
+		facts = append(facts, map[string]string{"title": f.name, "value": f.value})
+	}
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body": []map[string]interface{}{
+			{
+				"type": "TextBlock", "size": "Medium", "weight": "Bolder", "color": color, "wrap": true,
+				"text": fmt.Sprintf("%s: %s level", strings.Title(category), strings.ToUpper(LevelToString(level))),
+			},
+			{"type": "TextBlock", "text": title, "wrap": true},
+			{"type": "FactSet", "facts": facts},
+		},
+		"msteams": map[string]string{"width": "Full"},
+	}
+	data, _ := json.Marshal(map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{"contentType": "application/vnd.microsoft.card.adaptive", "content": card},
+		},
+	})
+	return data
+}
+
+func pagerDutySeverity(level string) string {
+	switch level {
+	case api.LogLevelEMERG, api.LogLevelALERT, api.LogLevelCRIT:
+		return PagerDutySeverityCritical
+	case api.LogLevelERR:
+		return PagerDutySeverityError
+	case api.LogLevelWARNING:
+		return PagerDutySeverityWarning
+	}
+	return PagerDutySeverityInfo
+}
+
+// Events API v2 trigger event. The alerts of the same dedup key are grouped into one incident, by default
+// it's the cluster, category and title, such as the client and server of a violation.
+func pagerDutyPayload(elog interface{}, routingKey string, opts *WebhookOptions, level, category, cluster, title string) []byte {
+	fields := webhookFields(elog, level, category, cluster, title)
+
+	severity := pagerDutySeverity(level)
+	var dedupKey string
+	if opts != nil {
+		if opts.Severity != "" {
+			severity = opts.Severity
+		}
+		if opts.DedupKey != "" {
+			dedupKey = fillWebhookText(opts.DedupKey, fields)
+		}
+	}
+	if dedupKey == "" {
+		dedupKey = fmt.Sprintf("neuvector/%s/%s/%s", cluster, category, title)
+	}
+	if len(dedupKey) > pagerDutyDedupKeyMax {
+		sum := sha256.Sum256([]byte(dedupKey))
+		dedupKey = hex.EncodeToString(sum[:])
+	}
+
+	source := cluster
+	if source == "" {
+		source = "neuvector"
+	}
+	payload := map[string]interface{}{
+		"summary":        truncateText(fmt.Sprintf("%s: %s", strings.Title(category), title), pagerDutySummaryMax),
+		"source":         source,
+		"severity":       severity,
+		"group":          category,
+		"custom_details": elog,
+	}
+	if name, ok := fields["name"].(string); ok && name != "" {
+		payload["class"] = name
+	}
+	if at, ok := fields["reported_at"].(string); ok && at != "" {
+		payload["timestamp"] = at
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"routing_key":  routingKey,
+		"event_action": pagerDutyEventTrigger,
+		"dedup_key":    dedupKey,
+		"client":       "NeuVector",
+		"payload":      payload,
+	})
+	return data
+}
+
+func (w *Webhook) Notify(elog interface{}, target string, opts *WebhookOptions, level, category, cluster, title string) {
 	log.WithFields(log.Fields{"title": title}).Debug()
 
+	if opts != nil && opts.Template != "" {
+		if data, err := renderWebhookTemplate(opts.Template, elog, level, category, cluster, title); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Failed to render webhook payload")
+		} else {
+			w.httpRequest(data)
+		}
+		return
+	}
+
 	if logText := struct2Text(elog); logText != "" {
 		var data []byte
 		if target == api.WebhookTypeSlack {
-			// Prefix category
-			logText = fmt.Sprintf("%s=%s,%s", notificationHeader, category, logText)
-			// Prefix category and title with styles
-			logText = fmt.Sprintf("*%s: %s level*\n_%s_\n>>> %s", strings.Title(category), strings.ToUpper(LevelToString(level)), title, logText)
-			fields := make(map[string]string)
-			fields["text"] = logText
-			fields["username"] = fmt.Sprintf("NeuVector - %s", cluster)
-			data, _ = json.Marshal(fields)
+			data = slackPayload(elog, logText, level, category, cluster, title)
 		} else if target == api.WebhookTypeTeams {
-			fields := make(map[string]string)
-			fields["title"] = fmt.Sprintf("%s: %s level", strings.Title(category), strings.ToUpper(LevelToString(level)))
-			logText = fmt.Sprintf("%s=%s,%s", notificationHeader, category, logText)
-			fields["text"] = fmt.Sprintf("%s\n> %s", title, logText)
-			data, _ = json.Marshal(fields)
+			data = teamsPayload(elog, level, category, cluster, title)
+		} else if target == api.WebhookTypePagerDuty {
+			data = pagerDutyPayload(elog, w.routingKey, opts, level, category, cluster, title)
 		} else if target == api.WebhookTypeJSON {
 			extra := fmt.Sprintf("{\"level\":\"%s\",\"cluster\":\"%s\",", strings.ToUpper(LevelToString(level)), cluster)
 			data, _ = json.Marshal(elog)
@@ -277,13 +659,24 @@ func (w *Webhook) httpRequest(data []byte) error {
 	var resp *http.Response
 	retry := 0
 	for retry < 3 {
-		resp, err = w.client.Post(w.url, contentType, bytes.NewBuffer(data))
+		var req *http.Request
+		if req, err = http.NewRequest(http.MethodPost, w.url, bytes.NewBuffer(data)); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Webhook create HTTP request fail")
+			return err
+		}
+		req.Header.Set("Content-Type", contentType)
+		for _, h := range w.headers {
+			req.Header.Set(h.Name, h.Value)
+		}
+
+		resp, err = w.client.Do(req)
 		if err != nil {
 			log.WithFields(log.Fields{"error": err}).Error("Webhook Send HTTP fail")
 			return err
 		} else {
 			resp.Body.Close()
-			if resp.StatusCode == http.StatusOK {
+			// PagerDuty responds 202 Accepted
+			if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
 				return nil
 			} else {
 				err = fmt.Errorf("HTTP response: %s", resp.Status)
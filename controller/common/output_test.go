@@ -0,0 +1,271 @@
+package common
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestWebhookTemplate(t *testing.T) {
+	rlog := &api.Incident{
+		LogCommon: api.LogCommon{Name: api.EventNameContainerEscape, Level: api.LogLevelCRIT},
+		Count:     3,
+		Mitre:     []string{"T1611"},
+	}
+	rlog.WorkloadName = "nginx"
+
+	tmpl := `{"summary": "{{name}} at {{ workload_name }}", "count": "{{count}}", "tags": ["{{mitre_techniques}}", "{{cluster}}"], "detail": {"severity": "{{level}}", "missing": "{{no_such_field}}"}}`
+	if err := ValidateWebhookTemplate(tmpl); err != nil {
+		t.Errorf("Unexpected error: %v\n", err)
+	}
+
+	data, err := renderWebhookTemplate(tmpl, rlog, rlog.Level, api.CategoryIncident, "cluster.local", "title")
+	if err != nil {
+		t.Errorf("Unexpected error: %v\n", err)
+	}
+
+	var payload struct {
+		Summary string        `json:"summary"`
+		Count   int           `json:"count"`
+		Tags    []interface{} `json:"tags"`
+		Detail  struct {
+			Severity string      `json:"severity"`
+			Missing  interface{} `json:"missing"`
+		} `json:"detail"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Errorf("Invalid payload: %s, %v\n", string(data), err)
+	}
+	if payload.Summary != api.EventNameContainerEscape+" at nginx" || payload.Count != 3 ||
+		len(payload.Tags) != 2 || payload.Tags[1] != "cluster.local" || payload.Detail.Severity != "CRITICAL" ||
+		payload.Detail.Missing != nil {
+		t.Errorf("Unexpected payload: %s\n", string(data))
+	}
+	if tags, ok := payload.Tags[0].([]interface{}); !ok || len(tags) != 1 || tags[0] != "T1611" {
+		t.Errorf("Unexpected payload: %s\n", string(data))
+	}
+
+	if err := ValidateWebhookTemplate(`{"summary": {{name}}}`); err == nil {
+		t.Errorf("Invalid template should fail\n")
+	}
+}
+
+func TestWebhookSlackBlocks(t *testing.T) {
+	rlog := &api.Violation{
+		LogCommon:  api.LogCommon{Name: "Network.Violation", Level: api.LogLevelWARNING},
+		ClientName: "<client>",
+		ServerName: "server",
+	}
+	data := slackPayload(rlog, struct2Text(rlog), rlog.Level, api.CategoryViolation, "cluster.local", "<client> -> server")
+
+	var payload struct {
+		Username string `json:"username"`
+		Text     string `json:"text"`
+		Blocks   []struct {
+			Type string `json:"type"`
+			Text struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"text"`
+			Fields []struct {
+				Text string `json:"text"`
+			} `json:"fields"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("Invalid payload: %s, %v\n", string(data), err)
+	}
+	if payload.Username != "NeuVector - cluster.local" || !strings.Contains(payload.Text, "notification=violation") {
+		t.Errorf("Unexpected fallback text: %s\n", string(data))
+	}
+	if len(payload.Blocks) < 3 || payload.Blocks[0].Type != "header" || payload.Blocks[0].Text.Text != "Violation: WARNING level" ||
+		payload.Blocks[1].Text.Text != "*&lt;client&gt; -&gt; server*" || len(payload.Blocks[2].Fields) == 0 ||
+		len(payload.Blocks[2].Fields) > slackFieldMax {
+		t.Errorf("Unexpected blocks: %s\n", string(data))
+	}
+}
+
+func TestWebhookTeamsCard(t *testing.T) {
+	rlog := &api.Incident{LogCommon: api.LogCommon{Name: api.EventNameContainerEscape, Level: api.LogLevelCRIT}}
+	rlog.WorkloadName = "nginx"
+	data := teamsPayload(rlog, rlog.Level, api.CategoryIncident, "cluster.local", "title")
+
+	var payload struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string `json:"type"`
+				Body []struct {
+					Type  string `json:"type"`
+					Color string `json:"color"`
+					Facts []struct {
+						Title string `json:"title"`
+						Value string `json:"value"`
+					} `json:"facts"`
+				} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("Invalid payload: %s, %v\n", string(data), err)
+	}
+	if payload.Type != "message" || len(payload.Attachments) != 1 ||
+		payload.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Fatalf("Unexpected payload: %s\n", string(data))
+	}
+	card := payload.Attachments[0].Content
+	if card.Type != "AdaptiveCard" || len(card.Body) != 3 || card.Body[0].Color != "attention" {
+		t.Errorf("Unexpected card: %s\n", string(data))
+	}
+	facts := make(map[string]string)
+	for _, f := range card.Body[2].Facts {
+		facts[f.Title] = f.Value
+	}
+	if facts["cluster"] != "cluster.local" || facts["workload_name"] != "nginx" || facts["level"] != api.LogLevelCRIT {
+		t.Errorf("Unexpected facts: %+v\n", facts)
+	}
+}
+
+func TestWebhookPagerDuty(t *testing.T) {
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	rlog := &api.Threat{
+		LogCommon: api.LogCommon{Name: "SQL.Injection", Level: api.LogLevelCRIT, ReportedAt: "2024-01-02T03:04:05Z"},
+	}
+	rlog.ServerWLName = "db"
+
+	type pdEvent struct {
+		RoutingKey  string `json:"routing_key"`
+		EventAction string `json:"event_action"`
+		DedupKey    string `json:"dedup_key"`
+		Payload     struct {
+			Summary   string `json:"summary"`
+			Source    string `json:"source"`
+			Severity  string `json:"severity"`
+			Timestamp string `json:"timestamp"`
+			Class     string `json:"class"`
+		} `json:"payload"`
+	}
+
+	w := NewWebHook(server.URL, nil, "key1")
+	if err := w.httpRequest(pagerDutyPayload(rlog, w.routingKey, nil, rlog.Level, api.CategoryThreat, "c1", "SQL.Injection -> db")); err != nil {
+		t.Errorf("Accepted response should succeed: %v\n", err)
+	}
+	var ev pdEvent
+	if err := json.Unmarshal(received, &ev); err != nil {
+		t.Fatalf("Invalid payload: %s, %v\n", string(received), err)
+	}
+	if ev.RoutingKey != "key1" || ev.EventAction != "trigger" || ev.DedupKey != "neuvector/c1/threat/SQL.Injection -> db" ||
+		ev.Payload.Severity != PagerDutySeverityCritical || ev.Payload.Summary != "Threat: SQL.Injection -> db" ||
+		ev.Payload.Source != "c1" || ev.Payload.Timestamp != "2024-01-02T03:04:05Z" || ev.Payload.Class != "SQL.Injection" {
+		t.Errorf("Unexpected event: %s\n", string(received))
+	}
+
+	// severity and dedup key of the rule
+	opts := &WebhookOptions{Severity: PagerDutySeverityWarning, DedupKey: "{{cluster}}/{{name}}/{{server_workload_name}}"}
+	json.Unmarshal(pagerDutyPayload(rlog, "key1", opts, rlog.Level, api.CategoryThreat, "c1", "title"), &ev)
+	if ev.Payload.Severity != PagerDutySeverityWarning || ev.DedupKey != "c1/SQL.Injection/db" {
+		t.Errorf("Unexpected event: %+v\n", ev)
+	}
+
+	opts = &WebhookOptions{DedupKey: strings.Repeat("x", 300)}
+	json.Unmarshal(pagerDutyPayload(rlog, "key1", opts, api.LogLevelNOTICE, api.CategoryThreat, "c1", "title"), &ev)
+	if len(ev.DedupKey) != 64 || ev.Payload.Severity != PagerDutySeverityInfo {
+		t.Errorf("Unexpected event: %+v\n", ev)
+	}
+}
+
+func TestSyslogStructuredData(t *testing.T) {
+	rlog := &api.Threat{
+		LogCommon: api.LogCommon{Name: "Ping.Death", Level: api.LogLevelCRIT},
+		ID:        "t1",
+		Count:     2,
+		Msg:       `a "quoted" [msg] \ end`,
+	}
+	sd := struct2StructuredData(rlog, "threat")
+	if !strings.HasPrefix(sd, `[neuvector@32473 notification="threat" name="Ping.Death" level="Critical"`) || !strings.HasSuffix(sd, "]") {
+		t.Errorf("Unexpected structured data: %s", sd)
+	}
+	if !strings.Contains(sd, ` id="t1"`) || !strings.Contains(sd, ` count="2"`) {
+		t.Errorf("Missing fields in structured data: %s", sd)
+	}
+	if !strings.Contains(sd, ` message="a \"quoted\" [msg\] \\ end"`) {
+		t.Errorf("Value is not escaped in structured data: %s", sd)
+	}
+	if strings.Contains(sd, "cluster_name") {
+		t.Errorf("Empty value in structured data: %s", sd)
+	}
+}
+
+func TestSyslogCategoryMap(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	lines := make(chan string, 4)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	s := NewSyslogger(&share.CLUSSyslogConfig{
+		SyslogIP:             addr.IP,
+		SyslogIPProto:        syscall.IPPROTO_TCP,
+		SyslogPort:           uint16(addr.Port),
+		SyslogLevel:          api.LogLevelINFO,
+		SyslogCategories:     []string{api.CategoryEvent, api.CategoryRuntime},
+		SyslogStructuredData: true,
+		SyslogCategoryMaps: []share.CLUSSyslogCategoryMap{
+			{Category: api.CategoryRuntime, Facility: "auth", Level: api.LogLevelALERT},
+		},
+	})
+	defer s.Close()
+
+	// auth(4)<<3 | alert(1)
+	if err := s.Send(&api.Threat{LogCommon: api.LogCommon{Name: "t", Level: api.LogLevelCRIT}}, api.LogLevelCRIT, api.CategoryThreat, "threat"); err != nil {
+		t.Fatalf("Failed to send: %v", err)
+	}
+	// local0(16)<<3 | warning(4)
+	if err := s.Send(&api.Event{LogCommon: api.LogCommon{Name: "e", Level: api.LogLevelWARNING}}, api.LogLevelWARNING, api.CategoryEvent, "event"); err != nil {
+		t.Fatalf("Failed to send: %v", err)
+	}
+	// filtered by level
+	s.Send(&api.Event{LogCommon: api.LogCommon{Name: "d"}}, api.LogLevelDEBUG, api.CategoryEvent, "event")
+
+	for _, expect := range []string{"<33>1 ", "<132>1 "} {
+		select {
+		case line := <-lines:
+			if !strings.HasPrefix(line, expect) || !strings.Contains(line, " neuvector [neuvector@32473 notification=") {
+				t.Errorf("Unexpected syslog message: %s", line)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("Syslog message is not received")
+		}
+	}
+}
@@ -0,0 +1,445 @@
+package common
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+	syslog "github.com/neuvector/neuvector/share/utils/srslog"
+)
+
+const (
+	SMTPSecurityStartTLS = "starttls"
+	SMTPSecurityTLS      = "tls"
+	SMTPSecurityNone     = "none"
+)
+
+const (
+	SMTPDigestHourly = "hourly"
+	SMTPDigestDaily  = "daily"
+)
+
+const smtpQueueSize = 1000
+const smtpDigestMax = 500 // events kept for a recipient in one digest
+const smtpTimeout = time.Second * 30
+const smtpSubjectPrefix = "[NeuVector]"
+const smtpDefaultDigestLevel = api.LogLevelNOTICE
+
+type smtpRoute struct {
+	recipients []string
+	catSet     utils.Set
+	prio       syslog.Priority
+}
+
+type smtpEvent struct {
+	level      string
+	category   string
+	cluster    string
+	title      string
+	time       time.Time
+	facts      []logFact
+	recipients []string
+}
+
+type smtpDigest struct {
+	events  []*smtpEvent
+	dropped int
+}
+
+// SMTPNotifier emails the events to the recipients of the matching routes. When the digest is enabled, the events
+// of low severity are aggregated for each recipient and sent in one email every hour or day. The events are queued
+// in a bounded buffer and sent in the background, so a slow mail server doesn't block the log processing.
+type SMTPNotifier struct {
+	cfg        share.CLUSSMTPConfig
+	routes     []smtpRoute
+	digestPrio syslog.Priority
+	period     time.Duration
+	digests    map[string]*smtpDigest // key is recipient, only accessed by the sending goroutine
+	queue      chan *smtpEvent
+	stopCh     chan struct{}
+	wg         sync.WaitGroup
+	sent       uint64
+	failed     uint64
+	dropped    uint64
+}
+
+func SMTPDigestPeriod(digest string) (time.Duration, bool) {
+	switch digest {
+	case "":
+		return 0, true
+	case SMTPDigestHourly:
+		return time.Hour, true
+	case SMTPDigestDaily:
+		return time.Hour * 24, true
+	}
+	return 0, false
+}
+
+func SMTPDefaultPort(security string) uint16 {
+	switch security {
+	case SMTPSecurityTLS:
+		return 465
+	case SMTPSecurityNone:
+		return 25
+	}
+	return 587
+}
+
+func NewSMTPNotifier(cfg *share.CLUSSMTPConfig) (*SMTPNotifier, error) {
+	switch cfg.Security {
+	case "", SMTPSecurityStartTLS, SMTPSecurityTLS, SMTPSecurityNone:
+	default:
+		return nil, fmt.Errorf("Unsupported SMTP security: %s", cfg.Security)
+	}
+	period, ok := SMTPDigestPeriod(cfg.Digest)
+	if !ok {
+		return nil, fmt.Errorf("Unsupported SMTP digest: %s", cfg.Digest)
+	}
+
+	n := &SMTPNotifier{
+		cfg:     *cfg,
+		routes:  make([]smtpRoute, 0, len(cfg.Routes)),
+		period:  period,
+		digests: make(map[string]*smtpDigest),
+		queue:   make(chan *smtpEvent, smtpQueueSize),
+		stopCh:  make(chan struct{}),
+	}
+	if n.cfg.Security == "" {
+		n.cfg.Security = SMTPSecurityStartTLS
+	}
+	if n.cfg.Port == 0 {
+		n.cfg.Port = SMTPDefaultPort(n.cfg.Security)
+	}
+	for _, r := range cfg.Routes {
+		route := smtpRoute{recipients: r.Recipients, catSet: LogExportCategories(r.Categories), prio: syslog.LOG_DEBUG}
+		if r.Level != "" {
+			if route.prio, ok = LevelToPrio(r.Level); !ok {
+				return nil, fmt.Errorf("Invalid SMTP route level: %s", r.Level)
+			}
+		}
+		n.routes = append(n.routes, route)
+	}
+	if period > 0 {
+		level := cfg.DigestLevel
+		if level == "" {
+			level = smtpDefaultDigestLevel
+		}
+		if n.digestPrio, ok = LevelToPrio(level); !ok {
+			return nil, fmt.Errorf("Invalid SMTP digest level: %s", level)
+		}
+	}
+
+	n.wg.Add(1)
+	go n.run()
+	return n, nil
+}
+
+// Stop the notifier, the queued events and the pending digests are sent before it returns
+func (n *SMTPNotifier) Close() {
+	close(n.stopCh)
+	n.wg.Wait()
+}
+
+func (n *SMTPNotifier) Stats() (sent, failed, dropped uint64) {
+	return atomic.LoadUint64(&n.sent), atomic.LoadUint64(&n.failed), atomic.LoadUint64(&n.dropped)
+}
+
+// The recipients of the routes that match the category and the level of the event
+func (n *SMTPNotifier) recipients(level, category string) []string {
+	prio, _ := LevelToPrio(level)
+	rcptSet := utils.NewSet()
+	for _, r := range n.routes {
+		if prio <= r.prio && r.catSet.Contains(category) {
+			for _, rcpt := range r.recipients {
+				rcptSet.Add(rcpt)
+			}
+		}
+	}
+	if rcptSet.Cardinality() == 0 {
+		return nil
+	}
+	list := rcptSet.ToStringSlice()
+	sort.Strings(list)
+	return list
+}
+
+// Return false if the event is dropped because the queue is full
+func (n *SMTPNotifier) Notify(elog interface{}, level, category, cluster, title string, ts time.Time) bool {
+	recipients := n.recipients(level, category)
+	if len(recipients) == 0 {
+		return true
+	}
+
+	ev := &smtpEvent{
+		level: level, category: category, cluster: cluster, title: title, time: ts,
+		facts: logFacts(elog), recipients: recipients,
+	}
+	select {
+	case n.queue <- ev:
+		return true
+	default:
+		if dropped := atomic.AddUint64(&n.dropped, 1); dropped%100 == 1 {
+			log.WithFields(log.Fields{"dropped": dropped}).Error("SMTP notification queue is full")
+		}
+		return false
+	}
+}
+
+func (n *SMTPNotifier) run() {
+	defer n.wg.Done()
+
+	var tickC <-chan time.Time
+	if n.period > 0 {
+		ticker := time.NewTicker(n.period)
+		defer ticker.Stop()
+		tickC = ticker.C
+	}
+
+	for {
+		select {
+		case ev := <-n.queue:
+			n.handle(ev)
+		case <-tickC:
+			n.flushDigests()
+		case <-n.stopCh:
+		drain:
+			for {
+				select {
+				case ev := <-n.queue:
+					n.handle(ev)
+				default:
+					break drain
+				}
+			}
+			n.flushDigests()
+			return
+		}
+	}
+}
+
+func (n *SMTPNotifier) handle(ev *smtpEvent) {
+	if n.period == 0 {
+		n.send(ev.recipients, eventSubject(ev), eventBody(ev))
+		return
+	}
+	if prio, _ := LevelToPrio(ev.level); prio < n.digestPrio {
+		n.send(ev.recipients, eventSubject(ev), eventBody(ev))
+		return
+	}
+	for _, rcpt := range ev.recipients {
+		d, ok := n.digests[rcpt]
+		if !ok {
+			d = &smtpDigest{}
+			n.digests[rcpt] = d
+		}
+		if len(d.events) < smtpDigestMax {
+			d.events = append(d.events, ev)
+		} else {
+			d.dropped++
+		}
+	}
+}
+
+func (n *SMTPNotifier) flushDigests() {
+	rcpts := make([]string, 0, len(n.digests))
+	for rcpt := range n.digests {
+		rcpts = append(rcpts, rcpt)
+	}
+	sort.Strings(rcpts)
+	for _, rcpt := range rcpts {
+		d := n.digests[rcpt]
+		subject := fmt.Sprintf("%s Digest: %d events", smtpSubjectPrefix, len(d.events)+d.dropped)
+		n.send([]string{rcpt}, subject, digestBody(d))
+	}
+	n.digests = make(map[string]*smtpDigest)
+}
+
+func eventSubject(ev *smtpEvent) string {
+	if ev.cluster == "" {
+		return fmt.Sprintf("%s %s %s: %s", smtpSubjectPrefix, ev.level, ev.category, ev.title)
+	}
+	return fmt.Sprintf("%s %s %s: %s (%s)", smtpSubjectPrefix, ev.level, ev.category, ev.title, ev.cluster)
+}
+
+func writeEvent(buf *bytes.Buffer, ev *smtpEvent) {
+	fmt.Fprintf(buf, "%s\n", ev.title)
+	fmt.Fprintf(buf, "  level: %s\n  category: %s\n", ev.level, ev.category)
+	if ev.cluster != "" {
+		fmt.Fprintf(buf, "  cluster: %s\n", ev.cluster)
+	}
+	fmt.Fprintf(buf, "  time: %s\n", ev.time.UTC().Format(time.RFC3339))
+	for _, f := range ev.facts {
+		fmt.Fprintf(buf, "  %s: %s\n", f.name, f.value)
+	}
+}
+
+func eventBody(ev *smtpEvent) []byte {
+	var buf bytes.Buffer
+	writeEvent(&buf, ev)
+	return buf.Bytes()
+}
+
+func digestBody(d *smtpDigest) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%d events were reported since the last digest.\n\n", len(d.events)+d.dropped)
+	for _, ev := range d.events {
+		writeEvent(&buf, ev)
+		buf.WriteString("\n")
+	}
+	if d.dropped > 0 {
+		fmt.Fprintf(&buf, "%d more events are not listed.\n", d.dropped)
+	}
+	return buf.Bytes()
+}
+
+func (n *SMTPNotifier) message(to []string, subject string, body []byte) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	qp := quotedprintable.NewWriter(&buf)
+	qp.Write(bytes.Replace(body, []byte("\n"), []byte("\r\n"), -1))
+	qp.Close()
+	return buf.Bytes()
+}
+
+type SMTPAttachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// SendSMTPAttachment sends an email with the attachment to the recipients directly, such as the generated report.
+// The routes and the digest of the config are not used.
+func SendSMTPAttachment(cfg *share.CLUSSMTPConfig, to []string, subject string, body []byte, att *SMTPAttachment) error {
+	n := &SMTPNotifier{cfg: *cfg}
+	if n.cfg.Security == "" {
+		n.cfg.Security = SMTPSecurityStartTLS
+	}
+	if n.cfg.Port == 0 {
+		n.cfg.Port = SMTPDefaultPort(n.cfg.Security)
+	}
+	return n.sendMail(to, n.attachmentMessage(to, subject, body, att))
+}
+
+func (n *SMTPNotifier) attachmentMessage(to []string, subject string, body []byte, att *SMTPAttachment) []byte {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mw.Boundary())
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Type", "text/plain; charset=utf-8")
+	h.Set("Content-Transfer-Encoding", "quoted-printable")
+	pw, _ := mw.CreatePart(h)
+	qp := quotedprintable.NewWriter(pw)
+	qp.Write(bytes.Replace(body, []byte("\n"), []byte("\r\n"), -1))
+	qp.Close()
+
+	h = make(textproto.MIMEHeader)
+	h.Set("Content-Type", att.ContentType)
+	h.Set("Content-Transfer-Encoding", "base64")
+	h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": att.Name}))
+	pw, _ = mw.CreatePart(h)
+	data := base64.StdEncoding.EncodeToString(att.Data)
+	for len(data) > 76 {
+		pw.Write([]byte(data[:76] + "\r\n"))
+		data = data[76:]
+	}
+	pw.Write([]byte(data + "\r\n"))
+	mw.Close()
+	return buf.Bytes()
+}
+
+func (n *SMTPNotifier) send(to []string, subject string, body []byte) {
+	if err := n.sendMail(to, n.message(to, subject, body)); err != nil {
+		atomic.AddUint64(&n.failed, 1)
+		log.WithFields(log.Fields{"server": n.cfg.Server, "to": to, "error": err}).Error("Failed to send email")
+		return
+	}
+	atomic.AddUint64(&n.sent, 1)
+}
+
+func (n *SMTPNotifier) sendMail(to []string, msg []byte) error {
+	addr := net.JoinHostPort(n.cfg.Server, strconv.Itoa(int(n.cfg.Port)))
+	tlsConfig := &tls.Config{ServerName: n.cfg.Server, InsecureSkipVerify: n.cfg.SkipVerify}
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+
+	var conn net.Conn
+	var err error
+	if n.cfg.Security == SMTPSecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	c, err := smtp.NewClient(conn, n.cfg.Server)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if n.cfg.Security == SMTPSecurityStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("STARTTLS is not supported by the server")
+		}
+		if err = c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if n.cfg.Username != "" {
+		if err = c.Auth(smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Server)); err != nil {
+			return err
+		}
+	}
+	if err = c.Mail(n.cfg.From); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err = c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(msg); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
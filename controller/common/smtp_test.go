@@ -0,0 +1,209 @@
+package common
+
+import (
+	"bufio"
+	"io/ioutil"
+	"mime/quotedprintable"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+type mockMail struct {
+	from    string
+	to      []string
+	subject string
+	body    string
+}
+
+// A minimal SMTP server without the extensions
+type mockSMTPServer struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	mails    []mockMail
+}
+
+func newMockSMTPServer(t *testing.T) *mockSMTPServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	s := &mockSMTPServer{listener: l}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *mockSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 mock")
+	var mail mockMail
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250 mock")
+		case "MAIL":
+			mail = mockMail{from: strings.Trim(strings.TrimPrefix(line, "MAIL FROM:"), "<>")}
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			mail.to = append(mail.to, strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>"))
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 Go ahead")
+			data, _ := tp.ReadDotBytes()
+			header, _ := textproto.NewReader(bufio.NewReader(strings.NewReader(string(data)))).ReadMIMEHeader()
+			mail.subject = header.Get("Subject")
+			if i := strings.Index(string(data), "\n\n"); i > 0 {
+				body, _ := ioutil.ReadAll(quotedprintable.NewReader(strings.NewReader(string(data[i+2:]))))
+				mail.body = string(body)
+			}
+			s.mutex.Lock()
+			s.mails = append(s.mails, mail)
+			s.mutex.Unlock()
+			tp.PrintfLine("250 OK")
+		case "QUIT":
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("502 Not implemented")
+		}
+	}
+}
+
+func (s *mockSMTPServer) port() uint16 {
+	return uint16(s.listener.Addr().(*net.TCPAddr).Port)
+}
+
+func TestSMTPRouting(t *testing.T) {
+	server := newMockSMTPServer(t)
+	defer server.listener.Close()
+
+	n, err := NewSMTPNotifier(&share.CLUSSMTPConfig{
+		Enable: true, Server: "127.0.0.1", Port: server.port(), Security: SMTPSecurityNone, From: "nv@example.com",
+		Routes: []share.CLUSSMTPRoute{
+			{Recipients: []string{"sec@example.com"}, Categories: []string{api.CategoryRuntime}, Level: api.LogLevelWARNING},
+			{Recipients: []string{"ops@example.com", "sec@example.com"}, Categories: []string{api.CategoryEvent}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create notifier: %v", err)
+	}
+
+	violation := &api.Violation{LogCommon: api.LogCommon{Name: "Network.Violation", Level: api.LogLevelWARNING}, ClientName: "c1"}
+	n.Notify(violation, api.LogLevelWARNING, api.CategoryViolation, "cluster1", "Network.Violation", time.Unix(0, 0))
+	n.Notify(violation, api.LogLevelINFO, api.CategoryViolation, "cluster1", "Network.Violation", time.Unix(0, 0))
+	n.Notify(&api.Audit{}, api.LogLevelCRIT, api.CategoryAudit, "cluster1", "Admission.Denied", time.Unix(0, 0))
+	n.Notify(&api.Event{}, api.LogLevelINFO, api.CategoryEvent, "", "Controller.Join", time.Unix(0, 0))
+	n.Close()
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if len(server.mails) != 2 {
+		t.Fatalf("Unexpected mails: %+v", server.mails)
+	}
+	m := server.mails[0]
+	if m.from != "nv@example.com" || len(m.to) != 1 || m.to[0] != "sec@example.com" ||
+		m.subject != "[NeuVector] Warning violation: Network.Violation (cluster1)" {
+		t.Errorf("Unexpected mail: %+v", m)
+	}
+	if !strings.Contains(m.body, "client_name: c1") {
+		t.Errorf("Unexpected body: %s", m.body)
+	}
+	m = server.mails[1]
+	if len(m.to) != 2 || m.to[0] != "ops@example.com" || m.to[1] != "sec@example.com" {
+		t.Errorf("Unexpected recipients: %+v", m.to)
+	}
+	if sent, failed, dropped := n.Stats(); sent != 2 || failed != 0 || dropped != 0 {
+		t.Errorf("Unexpected stats: sent=%v failed=%v dropped=%v", sent, failed, dropped)
+	}
+}
+
+func TestSMTPDigest(t *testing.T) {
+	server := newMockSMTPServer(t)
+	defer server.listener.Close()
+
+	n, err := NewSMTPNotifier(&share.CLUSSMTPConfig{
+		Enable: true, Server: "127.0.0.1", Port: server.port(), Security: SMTPSecurityNone, From: "nv@example.com",
+		Routes: []share.CLUSSMTPRoute{
+			{Recipients: []string{"sec@example.com"}, Categories: []string{api.CategoryRuntime, api.CategoryEvent}},
+		},
+		Digest: SMTPDigestDaily, DigestLevel: api.LogLevelWARNING,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create notifier: %v", err)
+	}
+
+	n.Notify(&api.Event{}, api.LogLevelINFO, api.CategoryEvent, "", "Controller.Join", time.Unix(0, 0))
+	n.Notify(&api.Incident{}, api.LogLevelCRIT, api.CategoryIncident, "", "Host.Privilege.Escalation", time.Unix(0, 0))
+	n.Notify(&api.Violation{}, api.LogLevelWARNING, api.CategoryViolation, "", "Network.Violation", time.Unix(0, 0))
+	// the pending digest is sent when the notifier stops
+	n.Close()
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if len(server.mails) != 2 {
+		t.Fatalf("Unexpected mails: %+v", server.mails)
+	}
+	if !strings.Contains(server.mails[0].subject, "Host.Privilege.Escalation") {
+		t.Errorf("Unexpected immediate mail: %+v", server.mails[0])
+	}
+	digest := server.mails[1]
+	if digest.subject != "[NeuVector] Digest: 2 events" ||
+		!strings.Contains(digest.body, "Controller.Join") || !strings.Contains(digest.body, "Network.Violation") {
+		t.Errorf("Unexpected digest: %+v", digest)
+	}
+}
+
+func TestSMTPAttachment(t *testing.T) {
+	server := newMockSMTPServer(t)
+	defer server.listener.Close()
+
+	cfg := &share.CLUSSMTPConfig{Server: "127.0.0.1", Port: server.port(), Security: SMTPSecurityNone, From: "nv@example.com"}
+	att := &SMTPAttachment{Name: "report.csv", ContentType: "text/csv", Data: []byte("Name,Level\n")}
+	if err := SendSMTPAttachment(cfg, []string{"sec@example.com"}, "Compliance Report", []byte("Report attached"), att); err != nil {
+		t.Fatalf("Failed to send: %v", err)
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if len(server.mails) != 1 || server.mails[0].subject != "Compliance Report" {
+		t.Fatalf("Unexpected mails: %+v", server.mails)
+	}
+	body := server.mails[0].body
+	if !strings.Contains(body, "Report attached") || !strings.Contains(body, `attachment; filename=report.csv`) ||
+		!strings.Contains(body, "TmFtZSxMZXZlbAo") {
+		t.Errorf("Unexpected body: %s", body)
+	}
+}
+
+func TestSMTPConfig(t *testing.T) {
+	for _, cfg := range []share.CLUSSMTPConfig{
+		{Security: "ssl"},
+		{Digest: "weekly"},
+		{Routes: []share.CLUSSMTPRoute{{Level: "High"}}},
+		{Digest: SMTPDigestHourly, DigestLevel: "Low"},
+	} {
+		if _, err := NewSMTPNotifier(&cfg); err == nil {
+			t.Errorf("Invalid config should fail: %+v", cfg)
+		}
+	}
+}
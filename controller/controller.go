@@ -17,6 +17,7 @@ import (
 	"github.com/neuvector/neuvector/controller/cache"
 	"github.com/neuvector/neuvector/controller/common"
 	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/controller/logstore"
 	nvcrd "github.com/neuvector/neuvector/controller/nvk8sapi/neuvectorcrd"
 	admission "github.com/neuvector/neuvector/controller/nvk8sapi/nvvalidatewebhookcfg"
 	"github.com/neuvector/neuvector/controller/opa"
@@ -28,6 +29,7 @@ import (
 	"github.com/neuvector/neuvector/share/cluster"
 	"github.com/neuvector/neuvector/share/container"
 	"github.com/neuvector/neuvector/share/global"
+	"github.com/neuvector/neuvector/share/otel"
 	scanUtils "github.com/neuvector/neuvector/share/scan"
 	"github.com/neuvector/neuvector/share/system"
 	"github.com/neuvector/neuvector/share/utils"
@@ -251,6 +253,27 @@ func main() {
 	cspPauseInterval := flag.Uint("csp_pause_interval", 240, "")                       // in minutes, for testing only
 	noRmNsGrps := flag.Bool("no_rm_nsgroups", false, "Not to remove groups when namespace was deleted")
 	autoProfile := flag.Int("apc", 1, "Enable auto profile collection")
+	threatSigKey := flag.String("threat_sig_key", "", "Public key file to verify threat signature bundles")
+	restRateLimit := flag.Uint("rest_rate_limit", 0, "REST API requests per second allowed for each user, apikey or client IP, 0 for no limit")
+	restRateBurst := flag.Uint("rest_rate_burst", 0, "REST API requests allowed in a burst for each user, apikey or client IP")
+	apiGRPCPort := flag.Uint("api_grpc_port", 0, "External gRPC API server port, 0 to disable")
+	metricsPort := flag.Uint("metrics_port", 0, "Prometheus metrics server port, 0 to disable")
+	recoveryUser := flag.String("recovery_token", "", "Create a one-time token to recover the built-in admin account and exit, the value must be admin")
+	etcdEndpoints := flag.String("etcd_endpoints", "", "Use external etcd instead of embedded consul as cluster kv store, comma separated endpoint urls")
+	etcdCA := flag.String("etcd_ca", "", "CA cert file of etcd")
+	etcdCert := flag.String("etcd_cert", "", "Client cert file for etcd")
+	etcdKey := flag.String("etcd_key", "", "Client key file for etcd")
+	kvEncrypt := flag.String("kv_encrypt", "", "Key provider to encrypt secrets in kv: local, aws_kms or vault")
+	kvEncryptKeyFile := flag.String("kv_encrypt_key_file", "", "Master key file of the local key provider")
+	kvKmsKey := flag.String("kv_kms_key", "", "AWS KMS key id or arn, or Vault transit key name")
+	kvKmsRegion := flag.String("kv_kms_region", "", "AWS KMS region")
+	kvKmsAddr := flag.String("kv_kms_addr", "", "Vault address, or AWS KMS endpoint")
+	kvKmsVaultMount := flag.String("kv_kms_vault_mount", "transit", "Mount path of Vault transit secrets engine")
+	logStoreDir := flag.String("log_store_dir", "", "Directory to persist event and security logs, empty to keep logs in memory only")
+	logRetention := flag.String("log_retention", "", "Retention of persisted logs by type, such as event=30d,threat=90d,default=30d")
+	crdKeepRejected := flag.Bool("crd_keep_rejected", false, "Keep rejected NvSecurityRule, NvClusterSecurityRule and NvWafSecurityRule objects and report the error in their status instead of deleting them")
+	otelEndpoint := flag.String("otel_endpoint", "", "OpenTelemetry collector OTLP/HTTP endpoint, such as http://otel-collector:4318")
+	otelSampleRatio := flag.Float64("otel_sample_ratio", 1, "Ratio of the traces to sample")
 	flag.Parse()
 
 	if *debug {
@@ -279,7 +302,23 @@ func main() {
 		bindAddr = *bind
 		log.WithFields(log.Fields{"bind": bindAddr}).Info()
 	}
-	if *restPort > 65535 || *fedPort > 65535 || *rpcPort > 65535 || *lanPort > 65535 {
+	if *etcdEndpoints != "" {
+		etcdCfg := &cluster.EtcdConfig{
+			Endpoints: strings.Split(*etcdEndpoints, ","),
+			CACert:    *etcdCA,
+			Cert:      *etcdCert,
+			Key:       *etcdKey,
+		}
+		if err := cluster.UseEtcd(etcdCfg); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Invalid etcd setting. Exit!")
+			os.Exit(-2)
+		}
+	}
+	// the kv driver is selected before any kv write
+	if *recoveryUser != "" {
+		os.Exit(mintRecoveryToken(*recoveryUser))
+	}
+	if *restPort > 65535 || *fedPort > 65535 || *rpcPort > 65535 || *lanPort > 65535 || *apiGRPCPort > 65535 || *metricsPort > 65535 {
 		log.Error("Invalid port value. Exit!")
 		os.Exit(-2)
 	}
@@ -383,6 +422,14 @@ func main() {
 	Host.Network = network
 	Host.StorageDriver = global.RT.GetStorageDriver()
 
+	otelCfg := otel.Config{
+		Endpoint: *otelEndpoint, ServiceName: "neuvector-controller", InstanceID: Ctrler.ID, HostName: Host.Name,
+		SampleRatio: *otelSampleRatio,
+	}
+	if err := otel.Init(otelCfg); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Invalid OpenTelemetry setting")
+	}
+
 	Ctrler.Domain = global.ORCH.GetDomain(Ctrler.Labels)
 	parentCtrler.Domain = global.ORCH.GetDomain(parentCtrler.Labels)
 	resource.NvAdmSvcNamespace = Ctrler.Domain
@@ -498,6 +545,19 @@ func main() {
 	log.WithFields(log.Fields{"ctrler": Ctrler, "lead": lead, "self": self, "new-cluster": isNewCluster,
 		"noDefAdmin": *noDefAdmin, "cspEnv": *cspEnv}).Info()
 
+	kvEncryptCfg := &kv.KvEncryptConfig{
+		Provider:   *kvEncrypt,
+		KeyFile:    *kvEncryptKeyFile,
+		KmsKey:     *kvKmsKey,
+		KmsRegion:  *kvKmsRegion,
+		KmsAddr:    *kvKmsAddr,
+		VaultMount: *kvKmsVaultMount,
+	}
+	if err := kv.InitKvEncryption(kvEncryptCfg); err != nil {
+		log.WithFields(log.Fields{"provider": *kvEncrypt, "error": err}).Error("Failed to initialize kv encryption. Exit!")
+		os.Exit(-2)
+	}
+
 	restoredFedRole := ""
 	purgeFedRulesOnJoint := false
 	if Ctrler.Leader {
@@ -562,6 +622,8 @@ func main() {
 		if isNewCluster && *noDefAdmin {
 			kv.GetClusterHelper().DeleteUser(common.DefaultAdminUser)
 		}
+		// encrypt the restored and upgraded secrets by the active data key
+		kv.ReencryptKvSecrets()
 		setConfigLoaded()
 	} else {
 		// The lead can take some time to restore the PV. Synchronize here so when non-lead
@@ -629,6 +691,18 @@ func main() {
 		*telemetryFreq = 0
 	}
 
+	var logStore logstore.Store
+	logRetentions, err := logstore.ParseRetention(*logRetention)
+	if err != nil {
+		log.WithFields(log.Fields{"retention": *logRetention, "error": err}).Error("Invalid log retention")
+		logRetentions = nil
+	}
+	if *logStoreDir != "" {
+		if logStore, err = logstore.NewFileStore(*logStoreDir); err != nil {
+			log.WithFields(log.Fields{"dir": *logStoreDir, "error": err}).Error("Failed to open log store")
+		}
+	}
+
 	// Initialize cache
 	// - Start policy learning thread and build learnedPolicyRuleWrapper from KV
 	cctx := cache.Context{
@@ -654,6 +728,8 @@ func main() {
 		NvSemanticVersion:        nvSemanticVersion,
 		StartStopFedPingPollFunc: rest.StartStopFedPingPoll,
 		RestConfigFunc:           rest.RestConfig,
+		LogStore:                 logStore,
+		LogRetention:             logRetentions,
 	}
 	cacher = cache.Init(&cctx, Ctrler.Leader, lead, restoredFedRole)
 	cache.ScannerChangeNotify(Ctrler.Leader)
@@ -717,6 +793,12 @@ func main() {
 		CspType:            cspType,
 		CspPauseInterval:   *cspPauseInterval,
 		CheckCrdSchemaFunc: nvcrd.CheckCrdSchema,
+		ThreatSigKeyFile:   *threatSigKey,
+		RESTRateLimit:      *restRateLimit,
+		RESTRateBurst:      *restRateBurst,
+		APIGRPCPort:        *apiGRPCPort,
+		MetricsPort:        *metricsPort,
+		CrdKeepRejected:    *crdKeepRejected,
 	}
 	rest.InitContext(&rctx)
 
@@ -747,6 +829,9 @@ func main() {
 			cacher.SyncAdmCtrlStateToK8s(resource.NvAdmSvcName, resource.NvAdmValidatingName)
 		}
 		go rest.CleanupSessCfgCache()
+		go rest.CrdDriftProc()
+		go rest.ConfigBackupProc()
+		go rest.ReportScheduleProc()
 		go rest.AdmissionRestServer(*admctrlPort, false, *debug)
 		go rest.CrdValidateRestServer(*crdvalidatectrlPort, false, *debug)
 	}
@@ -822,8 +907,12 @@ func main() {
 	atomic.StoreInt32(&exitingFlag, 1)
 
 	cache.Close()
+	if logStore != nil {
+		logStore.Close()
+	}
 	orchConnector.Close()
 	ctrlDeleteLocalInfo()
 	cluster.LeaveCluster(true)
 	grpcServer.Stop()
+	otel.Close()
 }
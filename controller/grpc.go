@@ -378,6 +378,7 @@ func agentReportWorker(ch chan []*share.CLUSConnection) {
 		select {
 		case conns := <-ch:
 			cache.UpdateConnections(conns)
+			cache.ExportConnections(conns)
 
 			var wg sync.WaitGroup
 			eps := cacher.GetAllControllerRPCEndpoints(access.NewReaderAccessControl())
@@ -421,6 +422,9 @@ func (as *ControllerAgentService) ReportProcProfile(ctx context.Context, profs *
 				Uid:       prof.Uid,
 				Hash:      prof.Hash,
 				Action:    prof.Action,
+				Args:      prof.Args,
+				ArgsMatch: prof.ArgsMatch,
+				Syscalls:  prof.Syscalls,
 				CfgType:   share.Learned,
 				CreatedAt: time.Now().UTC(),
 				UpdatedAt: time.Now().UTC(),
@@ -2,11 +2,14 @@ package kv
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -35,6 +38,15 @@ type ConfigHelper interface {
 	Import(eps []*common.RPCEndpoint, localCtrlerID, localCtrlerIP string, loginDomainRoles access.DomainRole, importTask share.CLUSImportTask,
 		tempToken string, revertFedRoles RevertFedRolesFunc, postImportOp PostImportFunc, pauseResumeStoreWatcher PauseResumeStoreWatcherFunc,
 		ignoreFed bool) error
+	Diff(filename string, sections []string) ([]*ConfigDiffEntry, error)
+}
+
+// An object that would be created/modified/deleted by importing a config file
+type ConfigDiffEntry struct {
+	Section  string
+	Endpoint string
+	Key      string
+	Action   string
 }
 
 var ErrInvalidFileFormat = errors.New("Invalid file format")
@@ -43,6 +55,7 @@ var ErrIOWrite = errors.New("Failed on IO write")
 var ErrCluster = errors.New("Failed to access cluster")
 var ErrIncompatibleFedRole = errors.New("File is from an incompatible federal-role cluster")
 var ErrIncompatibleFedRoleEx = errors.New(`It's not allowed to import from federal-managed cluster to standalone cluster. To override it, select "Import configuration as standalone cluster" and try again`)
+var ErrNoSectionToImport = errors.New("File has no configuration of the selected sections")
 
 type configHelper struct {
 	id          string
@@ -485,6 +498,22 @@ func (c *configHelper) sections2Endpoints(sections []string) []*cfgEndpoint {
 	return eps
 }
 
+// The endpoints to import are the ones in the file's sections, limited by the requested sections if any.
+func (c *configHelper) importEndpoints(fileSections, sections []string) ([]*cfgEndpoint, []string) {
+	requested := utils.NewSetFromStringSlice(sections)
+	if requested.Cardinality() == 0 || requested.Contains(api.ConfSectionAll) {
+		return c.sections2Endpoints(fileSections), fileSections
+	}
+
+	effective := make([]string, 0, len(fileSections))
+	for _, s := range fileSections {
+		if requested.Contains(s) {
+			effective = append(effective, s)
+		}
+	}
+	return c.sections2Endpoints(effective), effective
+}
+
 // 1. When import, cluster name is always replaced with the cluster name(if available) specified in the backup file
 // 2. When import, fed rules are always replaced with the fed rules specified in the backup file.
 // 3. For clusters in fed, Import() doesn't change the existing clusters membership.
@@ -571,7 +600,14 @@ func (c *configHelper) importInternal(rpcEps []*common.RPCEndpoint, localCtrlerI
 	importInfo := fedRulesRevInfo{}
 
 	// Get all the endpoints to be imported
-	eps := c.sections2Endpoints(header.Sections)
+	eps, sections := c.importEndpoints(header.Sections, importTask.Sections)
+	if len(eps) == 0 {
+		return ErrNoSectionToImport
+	}
+	epNames := utils.NewSet()
+	for _, ep := range eps {
+		epNames.Add(ep.name)
+	}
 
 	importTask.Percentage += 1
 	importTask.LastUpdateTime = time.Now().UTC()
@@ -625,6 +661,9 @@ func (c *configHelper) importInternal(rpcEps []*common.RPCEndpoint, localCtrlerI
 			return share.CLUSConfigKey2Config(key), nil
 		},
 		func(ep *cfgEndpoint, txn *cluster.ClusterTransact) error {
+			if !epNames.Contains(ep.name) { // not in the sections to import
+				return nil
+			}
 			if skipKeys, ok := _skipKeyInfo[ep.name]; ok {
 				for _, skipKeyPath := range skipKeys {
 					if key == skipKeyPath { // this key should be skipped
@@ -776,12 +815,12 @@ func (c *configHelper) importInternal(rpcEps []*common.RPCEndpoint, localCtrlerI
 		} else {
 			accAdmin := access.NewFedAdminAccessControl()
 			if currFedRole == api.FedRoleMaster ||
-				(importFedRole == api.FedRoleMaster && len(header.Sections) == 1 && header.Sections[0] == api.ConfSectionPolicy) {
+				(importFedRole == api.FedRoleMaster && len(sections) == 1 && sections[0] == api.ConfSectionPolicy) {
 				// For these 2 cases, we need to promote default admin to fedAdmin explicitly:
 				// 1. non-master cluster's All/User backup file(from 3.0/3.1) to master cluster (because default admin is overwritten to be non-fedAdmin)
 				// 2. master cluster's Policy backup file to standalone cluster (because default admin's role is not updated by Policy backup file)
 				clusHelper.ConfigFedRole(common.DefaultAdminUser, api.UserRoleFedAdmin, accAdmin)
-			} else if ((currFedRole == api.FedRoleNone && len(header.Sections) == 1 && header.Sections[0] == api.ConfSectionUser) ||
+			} else if ((currFedRole == api.FedRoleNone && len(sections) == 1 && sections[0] == api.ConfSectionUser) ||
 				currFedRole == api.FedRoleJoint) && importFedRole == api.FedRoleMaster {
 				// For these 2 cases, we need to demote all fedAdmin users to admin explicitly explicitly:
 				// 1. When import master cluster's backup file to joint cluster, default admin is overwritten to be fedAdmin.
@@ -807,6 +846,9 @@ func (c *configHelper) importInternal(rpcEps []*common.RPCEndpoint, localCtrlerI
 	clusHelper.PutVulnerabilityProfileIfNotExist(profile)
 	createDefaultComplianceProfile()
 
+	// imported secrets are encrypted by the built-in key
+	ReencryptKvSecrets()
+
 	if len(importInfo.fedRulesRevValue) > 0 {
 		var fedRulesRev share.CLUSFedRulesRevision
 		if err := json.Unmarshal([]byte(importInfo.fedRulesRevValue), &fedRulesRev); err == nil {
@@ -817,6 +859,152 @@ func (c *configHelper) importInternal(rpcEps []*common.RPCEndpoint, localCtrlerI
 	return nil
 }
 
+// Diff compares the config file with the current config of the sections to import, without changing anything.
+func (c *configHelper) Diff(filename string, sections []string) ([]*ConfigDiffEntry, error) {
+	log.WithFields(log.Fields{"sections": sections}).Debug()
+
+	file, err := os.Open(filename)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to open file")
+		return nil, ErrInvalidFileFormat
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to read header")
+		return nil, ErrInvalidFileFormat
+	}
+	var header configHeader
+	if err = json.Unmarshal(line, &header); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to unmarshal header")
+		return nil, ErrInvalidFileFormat
+	}
+
+	eps, _ := c.importEndpoints(header.Sections, sections)
+	if len(eps) == 0 {
+		return nil, ErrNoSectionToImport
+	}
+
+	// endpoint -> key -> value in the file
+	imported := make(map[string]map[string]string)
+	for {
+		key, value, err := readKeyValue(r)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Failed to read line")
+			return nil, ErrInvalidFileFormat
+		}
+		name := share.CLUSConfigKey2Config(key)
+		if _, ok := imported[name]; !ok {
+			imported[name] = make(map[string]string)
+		}
+		imported[name][key] = value
+	}
+
+	// current values are read in the same way as export, so they can be compared with the values in the file
+	fedRole, _ := getFedRole()
+	entries := make([]*ConfigDiffEntry, 0)
+	for _, ep := range eps {
+		var buf bytes.Buffer
+		w := bufio.NewWriter(&buf)
+		if err := ep.write(w, fedRole); err != nil {
+			log.WithFields(log.Fields{"endpoint": ep.name, "error": err}).Error("Failed to read key/value")
+			return nil, ErrCluster
+		}
+		w.Flush()
+
+		current := make(map[string]string)
+		cr := bufio.NewReader(&buf)
+		for {
+			key, value, err := readKeyValue(cr)
+			if err != nil {
+				break
+			}
+			current[key] = value
+		}
+		entries = append(entries, ep.diff(current, imported[ep.name])...)
+	}
+
+	return entries, nil
+}
+
+// The keys that import doesn't change
+func skipDiffKey(epName, key string) bool {
+	if skipCertFilter(epName, key) {
+		return true
+	}
+	if epName == share.CFGEndpointFederation {
+		switch share.CLUSKeyNthToken(key, 3) {
+		case share.CLUSFedMembershipSubKey, share.CLUSFedClustersSubKey, share.CLUSFedRulesRevisionSubKey:
+			return true
+		}
+	}
+	return false
+}
+
+// Values are compared as json. Login states of users are reset by import, so they are ignored.
+// Objects with secrets that were encrypted again after the export are reported as modified.
+func sameConfigValue(epName, v1, v2 string) bool {
+	if v1 == v2 {
+		return true
+	}
+	var o1, o2 interface{}
+	if json.Unmarshal([]byte(v1), &o1) != nil || json.Unmarshal([]byte(v2), &o2) != nil {
+		return false
+	}
+	if epName == share.CFGEndpointUser {
+		for _, o := range []interface{}{o1, o2} {
+			if m, ok := o.(map[string]interface{}); ok {
+				for _, field := range []string{"last_login_at", "login_count", "failed_login_count", "block_login_since", "pwd_reset_time"} {
+					delete(m, field)
+				}
+			}
+		}
+	}
+	return reflect.DeepEqual(o1, o2)
+}
+
+// Values in the file could be from an older version, both values are upgraded in the same way as import
+func upgradeDiffValue(key, value string) string {
+	if array, err := upgrade(key, []byte(value)); err == nil {
+		return string(array)
+	}
+	return value
+}
+
+func (ep cfgEndpoint) diff(current, imported map[string]string) []*ConfigDiffEntry {
+	entries := make([]*ConfigDiffEntry, 0)
+	add := func(key, action string) {
+		entries = append(entries, &ConfigDiffEntry{Section: ep.section, Endpoint: ep.name, Key: key, Action: action})
+	}
+
+	for key, value := range imported {
+		if len(value) == 0 || skipDiffKey(ep.name, key) {
+			continue
+		}
+		if old, ok := current[key]; !ok || len(old) == 0 {
+			add(key, api.ConfigDiffCreate)
+		} else if !sameConfigValue(ep.name, upgradeDiffValue(key, old), upgradeDiffValue(key, value)) {
+			add(key, api.ConfigDiffModify)
+		}
+	}
+	for key, value := range current {
+		if len(value) == 0 || skipDiffKey(ep.name, key) || len(imported[key]) > 0 {
+			continue
+		}
+		// keys that are not purged are kept by import
+		if ep.purgeFilter == nil || ep.purgeFilter(ep.name, key) {
+			add(key, api.ConfigDiffDelete)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	return entries
+}
+
 func backupVersionFileName() string {
 	return fmt.Sprintf("%s%s.backup", configBackupDir, "version")
 }
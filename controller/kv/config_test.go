@@ -0,0 +1,83 @@
+package kv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestImportEndpoints(t *testing.T) {
+	var c configHelper
+	all := []string{api.ConfSectionUser, api.ConfSectionConfig, api.ConfSectionPolicy}
+
+	eps, sections := c.importEndpoints(all, nil)
+	if len(eps) != len(cfgEndpoints) || !reflect.DeepEqual(sections, all) {
+		t.Errorf("All sections should be imported: %v", sections)
+	}
+
+	eps, sections = c.importEndpoints(all, []string{api.ConfSectionUser})
+	if !reflect.DeepEqual(sections, []string{api.ConfSectionUser}) || len(eps) == 0 {
+		t.Errorf("Unexpected sections: %v", sections)
+	}
+	for _, ep := range eps {
+		if ep.section != api.ConfSectionUser {
+			t.Errorf("Unexpected endpoint: %s", ep.name)
+		}
+	}
+
+	if eps, _ = c.importEndpoints([]string{api.ConfSectionPolicy}, []string{api.ConfSectionUser}); len(eps) != 0 {
+		t.Errorf("Sections not in the file should not be imported: %d", len(eps))
+	}
+}
+
+func TestThreatSignatureEndpoint(t *testing.T) {
+	var c configHelper
+
+	// signed bundles are exported & imported with the policy section
+	eps, _ := c.importEndpoints([]string{api.ConfSectionPolicy}, []string{api.ConfSectionPolicy})
+	for _, ep := range eps {
+		if ep.name == share.CFGEndpointThreatSignature {
+			if ep.key != share.CLUSConfigThreatSignatureKey || ep.isStore {
+				t.Errorf("Unexpected threat signature endpoint: %+v", ep)
+			}
+			return
+		}
+	}
+	t.Errorf("Threat signature bundle is not exported")
+}
+
+func TestConfigEndpointDiff(t *testing.T) {
+	preTest()
+	defer postTest()
+
+	ep := cfgEndpoint{name: share.CFGEndpointUser, key: share.CLUSConfigUserStore, isStore: true, section: api.ConfSectionUser}
+	current := map[string]string{
+		"object/config/user/admin":  `{"fullname":"admin","role":"admin","login_count":10,"last_login_at":"2023-01-01T00:00:00Z"}`,
+		"object/config/user/reader": `{"fullname":"reader","role":"reader"}`,
+		"object/config/user/old":    `{"fullname":"old","role":"reader"}`,
+		"object/config/user/empty":  "",
+	}
+	imported := map[string]string{
+		"object/config/user/admin":  `{"role":"admin", "fullname":"admin","login_count":0}`,
+		"object/config/user/reader": `{"fullname":"reader","role":"admin"}`,
+		"object/config/user/new":    `{"fullname":"new","role":"reader"}`,
+	}
+
+	var diffs []string
+	for _, e := range ep.diff(current, imported) {
+		if e.Endpoint != share.CFGEndpointUser || e.Section != api.ConfSectionUser {
+			t.Errorf("Unexpected entry: %+v", e)
+		}
+		diffs = append(diffs, e.Action+" "+e.Key)
+	}
+	expect := []string{
+		"create object/config/user/new",
+		"delete object/config/user/old",
+		"modify object/config/user/reader",
+	}
+	if !reflect.DeepEqual(diffs, expect) {
+		t.Errorf("Unexpected diff: %v", diffs)
+	}
+}
@@ -50,6 +50,7 @@ type nodeMemberCache struct {
 
 type FuncIsGroupMember func(name, id string) bool
 type FuncGetConfigKVData func(key string) ([]byte, bool)
+type FuncPushProfile func(node string, entries []*share.CLUSPolicyPushEntry)
 type kvDispatcher struct {
 	mutex          sync.RWMutex
 	nodes          utils.Set                    // debug purpose: keep a record of all nodes
@@ -59,6 +60,8 @@ type kvDispatcher struct {
 	profileKeys    []keyMappingHelper           // utility: profile keys in the object/ store
 	matchedGrpFunc FuncIsGroupMember            // help purging custom groups when workloads leave.
 	getKvDataFunc  FuncGetConfigKVData
+	pushFunc       FuncPushProfile                         // push the written profiles to enforcers by grpc
+	pending        map[string][]*share.CLUSPolicyPushEntry // [nodeID]: profiles written in the current transaction
 }
 
 //////////////////////////////////
@@ -221,6 +224,26 @@ func (dpt *kvDispatcher) refreshCustomGroups(name string, serviceGrps utils.Set,
 	dpt.customs.Add(name)   // reserved
 }
 
+// The profiles are pushed after the transaction is applied, so enforcers don't get what is not in the kv
+func (dpt *kvDispatcher) addPending(node, key string, value []byte, bDelete bool) {
+	if dpt.pushFunc == nil {
+		return
+	}
+	if dpt.pending == nil {
+		dpt.pending = make(map[string][]*share.CLUSPolicyPushEntry)
+	}
+	dpt.pending[node] = append(dpt.pending[node], &share.CLUSPolicyPushEntry{Key: key, Value: value, Delete: bDelete})
+}
+
+func (dpt *kvDispatcher) flushPending(bApplied bool) {
+	if bApplied && dpt.pushFunc != nil {
+		for node, entries := range dpt.pending {
+			dpt.pushFunc(node, entries)
+		}
+	}
+	dpt.pending = nil
+}
+
 func (dpt *kvDispatcher) copyProfileKeys(node, group string, txn *cluster.ClusterTransact) {
 	var value []byte
 	var ok bool
@@ -235,7 +258,9 @@ func (dpt *kvDispatcher) copyProfileKeys(node, group string, txn *cluster.Cluste
 				continue
 			}
 		}
-		txn.PutQuiet(to, utils.GzipBytes(value))
+		zb := utils.GzipBytes(value)
+		txn.PutQuiet(to, zb)
+		dpt.addPending(node, to, zb, false)
 	}
 }
 
@@ -245,6 +270,7 @@ func (dpt *kvDispatcher) removeProfileKeys(node, group string, txn *cluster.Clus
 		key := fmt.Sprintf("%s%s/%s%s", share.CLUSNodeStore, node, m.prf, group)
 		// log.WithFields(log.Fields{"key": key}).Debug("DPT:")
 		txn.Delete(key)
+		dpt.addPending(node, key, nil, true)
 	}
 }
 
@@ -304,9 +330,11 @@ func (dpt *kvDispatcher) WorkloadJoin(node, group, id string, customGrps utils.S
 			dpt.copyProfileKeys(node, group, txn)
 		}
 
-		if ok, err := txn.Apply(); err != nil || !ok {
+		ok, err := txn.Apply()
+		if err != nil || !ok {
 			log.WithFields(log.Fields{"ok": ok, "error": err, "group": group, "node": node}).Error("write failed")
 		}
+		dpt.flushPending(err == nil && ok)
 		txn.Close()
 	}
 }
@@ -338,9 +366,11 @@ func (dpt *kvDispatcher) WorkloadLeave(node, group, id string, customGrps utils.
 				dpt.removeProfileKeys(node, custom.(string), txn)
 			}
 
-			if _, err := txn.Apply(); err != nil {
+			ok, err := txn.Apply()
+			if err != nil {
 				log.WithFields(log.Fields{"error": err, "group": group}).Error("delete failed")
 			}
+			dpt.flushPending(err == nil && ok)
 			txn.Close()
 		}
 	}
@@ -396,9 +426,11 @@ func (dpt *kvDispatcher) CustomGroupUpdate(group string, serviceGrps utils.Set,
 			dpt.removeProfileKeys(n.(string), group, txn)
 		}
 
-		if _, err := txn.Apply(); err != nil {
+		ok, err := txn.Apply()
+		if err != nil {
 			log.WithFields(log.Fields{"error": err, "group": group}).Error("delete failed")
 		}
+		dpt.flushPending(err == nil && ok)
 		txn.Close()
 	}
 }
@@ -423,9 +455,11 @@ func (dpt *kvDispatcher) CustomGroupDelete(group string, bLeader bool) {
 			dpt.removeProfileKeys(n.(string), group, txn)
 		}
 
-		if _, err := txn.Apply(); err != nil {
+		ok, err := txn.Apply()
+		if err != nil {
 			log.WithFields(log.Fields{"error": err, "group": group}).Error("delete failed")
 		}
+		dpt.flushPending(err == nil && ok)
 		txn.Close()
 	}
 }
@@ -460,6 +494,7 @@ func (dpt *kvDispatcher) PutProfile(group, subkey string, value []byte, txn *clu
 	dpt.lockR()
 	defer dpt.unlockR()
 	if nodes, ok := dpt.group2nodes[group]; ok {
+		pushes := make(map[string][]*share.CLUSPolicyPushEntry)
 		for node := range nodes.Iter() {
 			key := share.CLUSNodeProfileKey(node.(string), subkey)
 			// log.WithFields(log.Fields{"key": key}).Debug("DPT:")
@@ -468,11 +503,17 @@ func (dpt *kvDispatcher) PutProfile(group, subkey string, value []byte, txn *clu
 				continue
 			}
 			txn.PutQuiet(key, value)
+			pushes[node.(string)] = []*share.CLUSPolicyPushEntry{&share.CLUSPolicyPushEntry{Key: key, Value: value}}
 		}
 
-		// local request
+		// local request, the external transaction is not known to be applied
 		if !bExternalTxnReq && txn != nil {
-			_, err = txn.Apply()
+			var applied bool
+			if applied, err = txn.Apply(); err == nil && applied && dpt.pushFunc != nil {
+				for node, entries := range pushes {
+					dpt.pushFunc(node, entries)
+				}
+			}
 		}
 
 		if err != nil {
@@ -515,6 +556,12 @@ func initDispatcher(matcher FuncIsGroupMember, getter FuncGetConfigKVData) {
 	}
 }
 
+func SetProfilePushFunc(fn FuncPushProfile) {
+	dispatcher.lock()
+	dispatcher.pushFunc = fn
+	dispatcher.unlock()
+}
+
 func GetDispatchHelper() DispatcherHelper {
 	return dispatcher
 }
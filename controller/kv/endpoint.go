@@ -14,6 +14,7 @@ import (
 
 	"github.com/neuvector/neuvector/controller/access"
 	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
 	"github.com/neuvector/neuvector/share"
 	"github.com/neuvector/neuvector/share/cluster"
 	"github.com/neuvector/neuvector/share/utils"
@@ -109,6 +110,10 @@ var cfgEndpoints []*cfgEndpoint = []*cfgEndpoint{
 		section: api.ConfSectionConfig, lock: share.CLUSLockConfigKey},
 	&cfgEndpoint{name: share.CFGEndpointScan, key: share.CLUSConfigScanKey, isStore: false,
 		section: api.ConfSectionConfig, lock: share.CLUSLockConfigKey},
+	&cfgEndpoint{name: share.CFGEndpointBackup, key: share.CLUSConfigBackupKey, isStore: false,
+		section: api.ConfSectionConfig, lock: share.CLUSLockConfigKey},
+	&cfgEndpoint{name: share.CFGEndpointReport, key: share.CLUSConfigReportStore, isStore: true,
+		section: api.ConfSectionConfig, lock: share.CLUSLockConfigKey},
 	sigstoreCfgEndpoint,
 	registryCfgEndpoint,
 	&cfgEndpoint{name: share.CFGEndpointAdmissionControl, key: share.CLUSConfigAdmissionControlStore, isStore: true,
@@ -116,6 +121,8 @@ var cfgEndpoints []*cfgEndpoint = []*cfgEndpoint{
 	groupCfgEndpoint,
 	&cfgEndpoint{name: share.CFGEndpointPolicy, key: share.CLUSConfigPolicyStore, isStore: true,
 		section: api.ConfSectionPolicy, lock: share.CLUSLockPolicyKey},
+	&cfgEndpoint{name: share.CFGEndpointPolicyChange, key: share.CLUSConfigPolicyChangeStore, isStore: true,
+		section: api.ConfSectionPolicy, lock: share.CLUSLockPolicyKey},
 	pprofileCfgEndpoint,
 	fmonitorCfgEndpoint,
 	faccessCfgEndpoint,
@@ -131,6 +138,8 @@ var cfgEndpoints []*cfgEndpoint = []*cfgEndpoint{
 		section: api.ConfSectionPolicy, lock: share.CLUSLockPolicyKey},
 	&cfgEndpoint{name: share.CFGEndpointWafGroup, key: share.CLUSConfigWafGroupStore, isStore: true,
 		section: api.ConfSectionPolicy, lock: share.CLUSLockPolicyKey},
+	&cfgEndpoint{name: share.CFGEndpointThreatSignature, key: share.CLUSConfigThreatSignatureKey, isStore: false,
+		section: api.ConfSectionPolicy, lock: share.CLUSLockPolicyKey},
 	&cfgEndpoint{name: share.CFGEndpointScript, key: share.CLUSConfigScriptStore, isStore: true,
 		section: api.ConfSectionConfig, lock: share.CLUSLockConfigKey},
 	&cfgEndpoint{name: share.CFGEndpointCompliance, key: share.CLUSConfigComplianceStore, isStore: true,
@@ -588,6 +597,8 @@ func (ep cfgEndpoint) write(writer *bufio.Writer, fedRole string) error {
 							continue
 						}
 					}
+					// exported values are portable to other clusters
+					value = common.ExportCloaked(value)
 					line := fmt.Sprintf("%s\n%s\n", key, value)
 					if _, err = writer.WriteString(line); err != nil {
 						return err
@@ -599,7 +610,7 @@ func (ep cfgEndpoint) write(writer *bufio.Writer, fedRole string) error {
 		}
 	} else {
 		if value, err := cluster.Get(ep.key); err == nil || err == cluster.ErrKeyNotFound {
-			line := fmt.Sprintf("%s\n%s\n", ep.key, value)
+			line := fmt.Sprintf("%s\n%s\n", ep.key, common.ExportCloaked(value))
 			if _, err = writer.WriteString(line); err != nil {
 				return err
 			}
@@ -50,8 +50,12 @@ type ClusterHelper interface {
 	GetSystemConfigRev(acc *access.AccessControl) (*share.CLUSSystemConfig, uint64)
 	PutSystemConfigRev(conf *share.CLUSSystemConfig, rev uint64) error
 	GetScanConfigRev(acc *access.AccessControl) (*share.CLUSScanConfig, uint64)
+	GetThreatSignatureBundleRev(acc *access.AccessControl) (*share.CLUSThreatSignatureBundle, uint64)
+	PutThreatSignatureBundleRev(bundle *share.CLUSThreatSignatureBundle, rev uint64) error
 	GetFedSystemConfigRev(acc *access.AccessControl) (*share.CLUSSystemConfig, uint64)
 	PutFedSystemConfigRev(conf *share.CLUSSystemConfig, rev uint64) error
+	GetWorkloadConfigRev(hostID, wlID string) (*share.CLUSWorkloadConfig, uint64)
+	PutWorkloadConfigRev(hostID, wlID string, conf *share.CLUSWorkloadConfig, rev uint64) error
 
 	GetDomain(name string, acc *access.AccessControl) (*share.CLUSDomain, uint64, error)
 	PutDomain(cd *share.CLUSDomain, rev *uint64) error
@@ -90,6 +94,9 @@ type ClusterHelper interface {
 	PutResponseRuleRev(policyName string, rule *share.CLUSResponseRule, rev uint64) error
 	DeleteResponseRule(policyName string, id uint32) error
 	DeleteResponseRuleTxn(policyName string, txn *cluster.ClusterTransact, id uint32) error
+	GetResponseQuarantineImages(policyName string) ([]*share.CLUSFedQuarantineImage, uint64)
+	PutResponseQuarantineImagesRev(policyName string, images []*share.CLUSFedQuarantineImage, rev uint64) error
+	PutResponseQuarantineImagesTxn(policyName string, txn *cluster.ClusterTransact, images []*share.CLUSFedQuarantineImage) error
 
 	GetAllServers(acc *access.AccessControl) map[string]*share.CLUSServer
 	GetServerRev(name string, acc *access.AccessControl) (*share.CLUSServer, uint64, error)
@@ -107,6 +114,7 @@ type ClusterHelper interface {
 
 	GetApikeyRev(name string, acc *access.AccessControl) (*share.CLUSApikey, uint64, error)
 	CreateApikey(apikey *share.CLUSApikey) error
+	PutApikeyRev(apikey *share.CLUSApikey, rev uint64) error
 	GetAllApikeysNoAuth() map[string]*share.CLUSApikey
 	DeleteApikey(name string) error
 
@@ -279,6 +287,56 @@ type ClusterHelper interface {
 	GetImportTask() (share.CLUSImportTask, error)
 	PutImportTask(importTask *share.CLUSImportTask) error
 
+	// config audit chain
+	GetConfigAuditHead() *share.CLUSConfigAuditHead
+	PutConfigAuditHead(head *share.CLUSConfigAuditHead) error
+
+	// account recovery
+	GetRecoveryToken() (*share.CLUSRecoveryToken, error)
+	PutRecoveryToken(token *share.CLUSRecoveryToken) error
+	DeleteRecoveryToken() error
+
+	// configuration backup
+	GetBackupConfigRev() (*share.CLUSBackupConfig, uint64)
+	PutBackupConfigRev(cfg *share.CLUSBackupConfig, rev uint64) error
+	GetBackupStatus() *share.CLUSBackupStatus
+	PutBackupStatus(status *share.CLUSBackupStatus) error
+	GetKvStoreStats() *share.CLUSKvStoreStats
+	PutKvStoreStats(stats *share.CLUSKvStoreStats) error
+
+	// fed report
+	GetAllFedClusterReports() map[string]*share.CLUSFedClusterReport
+	PutFedClusterReport(report *share.CLUSFedClusterReport) error
+	DeleteFedClusterReport(id string) error
+	GetFedReportTrend() *share.CLUSFedReportTrend
+	PutFedReportTrend(trend *share.CLUSFedReportTrend) error
+	DeleteFedReports() error
+
+	// compliance and vulnerability reports
+	GetAllReportSchedules() map[string]*share.CLUSReportSchedule
+	GetReportScheduleRev(name string) (*share.CLUSReportSchedule, uint64, error)
+	PutReportScheduleRev(schedule *share.CLUSReportSchedule, rev uint64) error
+	DeleteReportSchedule(name string) error
+	GetReportStatus() *share.CLUSReportStatus
+	PutReportStatus(status *share.CLUSReportStatus) error
+	GetAllReports() []*share.CLUSReport
+	GetReport(id string) (*share.CLUSReport, error)
+	PutReport(report *share.CLUSReport) error
+	DeleteReport(id string) error
+
+	// accepted risks of vulnerabilities and compliance checks
+	GetAllRiskAcceptances(riskType string) []*share.CLUSRiskAcceptance
+	GetRiskAcceptanceRev(riskType, id string) (*share.CLUSRiskAcceptance, uint64, error)
+	PutRiskAcceptanceRev(ra *share.CLUSRiskAcceptance, rev uint64) error
+	DeleteRiskAcceptance(riskType, id string) error
+
+	// network rule changes pending for approval
+	GetAllPolicyChanges() []*share.CLUSPolicyChange
+	GetPolicyChangeRev(id string) (*share.CLUSPolicyChange, uint64, error)
+	PutPolicyChangeRev(pc *share.CLUSPolicyChange, rev uint64) error
+	PutPolicyChangeTxn(txn *cluster.ClusterTransact, pc *share.CLUSPolicyChange, rev uint64) error
+	DeletePolicyChange(id string) error
+
 	// sigstore verification
 	CreateSigstoreRootOfTrust(rootOfTrust *share.CLUSSigstoreRootOfTrust, txn *cluster.ClusterTransact) error
 	UpdateSigstoreRootOfTrust(rootOfTrust *share.CLUSSigstoreRootOfTrust, txn *cluster.ClusterTransact, rev *uint64) error
@@ -565,8 +623,11 @@ func (m clusterHelper) GetSystemConfigRev(acc *access.AccessControl) (*share.CLU
 
 func (m clusterHelper) PutSystemConfigRev(conf *share.CLUSSystemConfig, rev uint64) error {
 	key := share.CLUSConfigSystemKey
-	value, _ := enc.Marshal(conf)
-	err := cluster.PutRev(key, value, rev)
+	value, err := enc.Marshal(conf)
+	if err != nil {
+		return err
+	}
+	err = cluster.PutRev(key, value, rev)
 	if err == nil {
 		return cluster.Put(share.NetworkSystemKey, value)
 	}
@@ -595,6 +656,32 @@ func (m clusterHelper) GetScanConfigRev(acc *access.AccessControl) (*share.CLUSS
 	}
 }
 
+func (m clusterHelper) GetThreatSignatureBundleRev(acc *access.AccessControl) (*share.CLUSThreatSignatureBundle, uint64) {
+	var bundle share.CLUSThreatSignatureBundle
+
+	value, rev, _ := m.get(share.CLUSConfigThreatSignatureKey)
+	if value == nil {
+		return nil, 0
+	}
+	json.Unmarshal(value, &bundle)
+	if !acc.Authorize(&bundle, nil) {
+		return nil, 0
+	}
+	return &bundle, rev
+}
+
+func (m clusterHelper) PutThreatSignatureBundleRev(bundle *share.CLUSThreatSignatureBundle, rev uint64) error {
+	value, err := enc.Marshal(bundle)
+	if err != nil {
+		return err
+	}
+	if rev == 0 {
+		return cluster.Put(share.CLUSConfigThreatSignatureKey, value)
+	} else {
+		return cluster.PutRev(share.CLUSConfigThreatSignatureKey, value, rev)
+	}
+}
+
 func (m clusterHelper) GetFedSystemConfigRev(acc *access.AccessControl) (*share.CLUSSystemConfig, uint64) {
 	var conf share.CLUSSystemConfig
 
@@ -615,7 +702,10 @@ func (m clusterHelper) GetFedSystemConfigRev(acc *access.AccessControl) (*share.
 func (m clusterHelper) PutFedSystemConfigRev(conf *share.CLUSSystemConfig, rev uint64) error {
 	key := share.CLUSFedKey(share.CFGEndpointSystem)
 	conf.CfgType = share.FederalCfg
-	value, _ := enc.Marshal(conf)
+	value, err := enc.Marshal(conf)
+	if err != nil {
+		return err
+	}
 	if rev == 0 {
 		return cluster.Put(key, value)
 	} else {
@@ -623,6 +713,24 @@ func (m clusterHelper) PutFedSystemConfigRev(conf *share.CLUSSystemConfig, rev u
 	}
 }
 
+// Workload config is read by the enforcer, return nil if it's not written yet
+func (m clusterHelper) GetWorkloadConfigRev(hostID, wlID string) (*share.CLUSWorkloadConfig, uint64) {
+	key := share.CLUSUniconfWorkloadKey(hostID, wlID)
+	value, rev, _ := cluster.GetRev(key)
+	if value == nil {
+		return nil, rev
+	}
+	var conf share.CLUSWorkloadConfig
+	json.Unmarshal(value, &conf)
+	return &conf, rev
+}
+
+func (m clusterHelper) PutWorkloadConfigRev(hostID, wlID string, conf *share.CLUSWorkloadConfig, rev uint64) error {
+	key := share.CLUSUniconfWorkloadKey(hostID, wlID)
+	value, _ := json.Marshal(conf)
+	return cluster.PutRev(key, value, rev)
+}
+
 func (m clusterHelper) GetDomain(name string, acc *access.AccessControl) (*share.CLUSDomain, uint64, error) {
 	key := share.CLUSDomainKey(name)
 	if value, rev, _ := m.get(key); value != nil {
@@ -640,13 +748,19 @@ func (m clusterHelper) GetDomain(name string, acc *access.AccessControl) (*share
 
 func (m clusterHelper) PutDomainIfNotExist(domain *share.CLUSDomain) error {
 	key := share.CLUSDomainKey(domain.Name)
-	value, _ := enc.Marshal(domain)
+	value, err := enc.Marshal(domain)
+	if err != nil {
+		return err
+	}
 	return cluster.PutIfNotExist(key, value, true)
 }
 
 func (m clusterHelper) PutDomain(domain *share.CLUSDomain, rev *uint64) error {
 	key := share.CLUSDomainKey(domain.Name)
-	value, _ := enc.Marshal(domain)
+	value, err := enc.Marshal(domain)
+	if err != nil {
+		return err
+	}
 	if rev == nil {
 		return cluster.Put(key, value)
 	} else {
@@ -892,13 +1006,19 @@ func (m clusterHelper) DeletePolicyRuleTxn(txn *cluster.ClusterTransact, id uint
 
 func (m clusterHelper) PutPolicyVer(s *share.CLUSGroupIPPolicyVer) error {
 	key := share.CLUSPolicyIPRulesKey(s.Key)
-	value, _ := enc.Marshal(s)
+	value, err := enc.Marshal(s)
+	if err != nil {
+		return err
+	}
 	return cluster.Put(key, value)
 }
 
 func (m clusterHelper) PutDlpVer(s *share.CLUSDlpRuleVer) error {
 	key := share.CLUSDlpWorkloadRulesKey(s.Key)
-	value, _ := enc.Marshal(s)
+	value, err := enc.Marshal(s)
+	if err != nil {
+		return err
+	}
 	return cluster.Put(key, value)
 }
 
@@ -969,6 +1089,30 @@ func (m clusterHelper) DeleteResponseRuleTxn(policyName string, txn *cluster.Clu
 	return nil
 }
 
+func (m clusterHelper) GetResponseQuarantineImages(policyName string) ([]*share.CLUSFedQuarantineImage, uint64) {
+	images := make([]*share.CLUSFedQuarantineImage, 0)
+	key := share.CLUSResponseQuarantineKey(policyName)
+	if value, rev, _ := m.get(key); value != nil {
+		json.Unmarshal(value, &images)
+		return images, rev
+	}
+
+	return images, 0
+}
+
+func (m clusterHelper) PutResponseQuarantineImagesRev(policyName string, images []*share.CLUSFedQuarantineImage, rev uint64) error {
+	key := share.CLUSResponseQuarantineKey(policyName)
+	value, _ := json.Marshal(images)
+	return cluster.PutRev(key, value, rev)
+}
+
+func (m clusterHelper) PutResponseQuarantineImagesTxn(policyName string, txn *cluster.ClusterTransact, images []*share.CLUSFedQuarantineImage) error {
+	key := share.CLUSResponseQuarantineKey(policyName)
+	value, _ := json.Marshal(images)
+	txn.Put(key, value)
+	return nil
+}
+
 // Server
 
 func (m clusterHelper) GetAllServers(acc *access.AccessControl) map[string]*share.CLUSServer {
@@ -1008,13 +1152,19 @@ func (m clusterHelper) GetServerRev(name string, acc *access.AccessControl) (*sh
 
 func (m clusterHelper) PutServerRev(server *share.CLUSServer, rev uint64) error {
 	key := share.CLUSServerKey(server.Name)
-	value, _ := enc.Marshal(server)
+	value, err := enc.Marshal(server)
+	if err != nil {
+		return err
+	}
 	return cluster.PutRev(key, value, rev)
 }
 
 func (m clusterHelper) PutServerIfNotExist(server *share.CLUSServer) error {
 	key := share.CLUSServerKey(server.Name)
-	value, _ := enc.Marshal(server)
+	value, err := enc.Marshal(server)
+	if err != nil {
+		return err
+	}
 	return cluster.PutIfNotExist(key, value, true)
 }
 
@@ -1164,7 +1314,10 @@ func (m clusterHelper) GetAllProcessProfileSubKeys(scope string) utils.Set {
 // Scanner
 func (m clusterHelper) PutScannerTxn(txn *cluster.ClusterTransact, s *share.CLUSScanner) error {
 	key := share.CLUSScannerKey(s.ID)
-	value, _ := enc.Marshal(s)
+	value, err := enc.Marshal(s)
+	if err != nil {
+		return err
+	}
 	txn.Put(key, value)
 	return nil
 }
@@ -1623,13 +1776,19 @@ func (m clusterHelper) GetAllRegistry(scope string) []*share.CLUSRegistryConfig
 
 func (m clusterHelper) PutRegistry(config *share.CLUSRegistryConfig, rev uint64) error {
 	key := share.CLUSRegistryConfigKey(config.Name)
-	value, _ := enc.Marshal(config)
+	value, err := enc.Marshal(config)
+	if err != nil {
+		return err
+	}
 	return cluster.PutRev(key, value, rev)
 }
 
 func (m clusterHelper) PutRegistryIfNotExist(config *share.CLUSRegistryConfig) error {
 	key := share.CLUSRegistryConfigKey(config.Name)
-	value, _ := enc.Marshal(config)
+	value, err := enc.Marshal(config)
+	if err != nil {
+		return err
+	}
 	if m.persist {
 		createRegistryDir(config.Name)
 	}
@@ -1898,8 +2057,11 @@ func (m clusterHelper) GetObjectCertRev(cn string) (*share.CLUSX509Cert, uint64,
 // returns pre-existing cert object in kv if it already in kv
 func (m clusterHelper) PutObjectCert(cn, keyPath, certPath string, cert *share.CLUSX509Cert) error {
 	key := share.CLUSObjectCertKey(cn)
-	value, _ := enc.Marshal(cert)
-	err := cluster.PutIfNotExist(key, value, true)
+	value, err := enc.Marshal(cert)
+	if err != nil {
+		return err
+	}
+	err = cluster.PutIfNotExist(key, value, true)
 	if err == nil {
 		// don't know why: after rolling upgrade(replicas/maxSurge=3), there could be a short period that controller cannot get/put kv
 		// (GetRev returns "Key not found" error & Put/PutRev return "CAS put error" & PutIfNotExist returns nil : is it because kv is not syned yet?)
@@ -2144,7 +2306,10 @@ func (m clusterHelper) GetFedMembership() *share.CLUSFedMembership {
 
 func (m clusterHelper) PutFedMembership(s *share.CLUSFedMembership) error {
 	key := share.CLUSFedKey(share.CLUSFedMembershipSubKey)
-	value, _ := enc.Marshal(s)
+	value, err := enc.Marshal(s)
+	if err != nil {
+		return err
+	}
 	if err := cluster.Put(key, value); err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("")
 		return err
@@ -2203,7 +2368,10 @@ func (m clusterHelper) GetFedJointCluster(id string) *share.CLUSFedJointClusterI
 }
 
 func (m clusterHelper) PutFedJointCluster(jointCluster *share.CLUSFedJointClusterInfo) error {
-	value, _ := enc.Marshal(jointCluster)
+	value, err := enc.Marshal(jointCluster)
+	if err != nil {
+		return err
+	}
 	key := share.CLUSFedJointClusterKey(jointCluster.ID)
 	if err := cluster.Put(key, value); err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("")
@@ -2627,7 +2795,10 @@ func (m clusterHelper) GetAwsProjectCfg(projectName string, acc *access.AccessCo
 
 func (m clusterHelper) PutAwsProjectCfg(projectName string, record *share.CLUSAwsProjectCfg) error {
 	key := share.CLUSCloudCfgKey(share.CloudAws, projectName)
-	value, _ := enc.Marshal(record)
+	value, err := enc.Marshal(record)
+	if err != nil {
+		return err
+	}
 	return cluster.Put(key, value)
 }
 
@@ -2644,7 +2815,10 @@ func (m clusterHelper) GetAwsCloudResource(projectName string) (*share.CLUSAwsRe
 
 func (m clusterHelper) PutAwsCloudResource(project *share.CLUSAwsResource) error {
 	key := share.CLUSCloudKey(share.CloudAws, project.ProjectName)
-	value, _ := enc.Marshal(project)
+	value, err := enc.Marshal(project)
+	if err != nil {
+		return err
+	}
 	return cluster.Put(key, value)
 }
 
@@ -2888,6 +3062,223 @@ func (m clusterHelper) PutImportTask(importTask *share.CLUSImportTask) error {
 	return cluster.Put(key, value)
 }
 
+func (m clusterHelper) GetConfigAuditHead() *share.CLUSConfigAuditHead {
+	if value, _, _ := m.get(share.CLUSConfigAuditHeadKey); value != nil {
+		var head share.CLUSConfigAuditHead
+		if json.Unmarshal(value, &head) == nil {
+			return &head
+		}
+	}
+	return nil
+}
+
+func (m clusterHelper) PutConfigAuditHead(head *share.CLUSConfigAuditHead) error {
+	value, _ := json.Marshal(head)
+	return cluster.PutQuiet(share.CLUSConfigAuditHeadKey, value)
+}
+
+// account recovery
+func (m clusterHelper) GetRecoveryToken() (*share.CLUSRecoveryToken, error) {
+	if value, _, _ := m.get(share.CLUSRecoveryTokenKey); value != nil {
+		var token share.CLUSRecoveryToken
+		json.Unmarshal(value, &token)
+		return &token, nil
+	}
+	return nil, common.ErrObjectNotFound
+}
+
+func (m clusterHelper) PutRecoveryToken(token *share.CLUSRecoveryToken) error {
+	value, _ := json.Marshal(token)
+	return cluster.PutQuiet(share.CLUSRecoveryTokenKey, value)
+}
+
+func (m clusterHelper) DeleteRecoveryToken() error {
+	return cluster.Delete(share.CLUSRecoveryTokenKey)
+}
+
+// configuration backup
+func (m clusterHelper) GetBackupConfigRev() (*share.CLUSBackupConfig, uint64) {
+	var cfg share.CLUSBackupConfig
+	value, rev, _ := m.get(share.CLUSConfigBackupKey)
+	if value != nil {
+		dec.Unmarshal(value, &cfg)
+	}
+	return &cfg, rev
+}
+
+func (m clusterHelper) PutBackupConfigRev(cfg *share.CLUSBackupConfig, rev uint64) error {
+	value, err := enc.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if rev == 0 {
+		return cluster.Put(share.CLUSConfigBackupKey, value)
+	} else {
+		return cluster.PutRev(share.CLUSConfigBackupKey, value, rev)
+	}
+}
+
+func (m clusterHelper) GetBackupStatus() *share.CLUSBackupStatus {
+	var status share.CLUSBackupStatus
+	if value, _, _ := m.get(share.CLUSBackupStatusKey); value != nil {
+		json.Unmarshal(value, &status)
+	}
+	return &status
+}
+
+func (m clusterHelper) PutBackupStatus(status *share.CLUSBackupStatus) error {
+	value, _ := json.Marshal(status)
+	return cluster.PutQuiet(share.CLUSBackupStatusKey, value)
+}
+
+func (m clusterHelper) GetKvStoreStats() *share.CLUSKvStoreStats {
+	var stats share.CLUSKvStoreStats
+	if value, _, _ := m.get(share.CLUSKvStoreStatsKey); value != nil {
+		json.Unmarshal(value, &stats)
+	}
+	return &stats
+}
+
+func (m clusterHelper) PutKvStoreStats(stats *share.CLUSKvStoreStats) error {
+	value, _ := json.Marshal(stats)
+	return cluster.PutQuiet(share.CLUSKvStoreStatsKey, value)
+}
+
+// fed reports could be big so they are always compressed
+func (m clusterHelper) GetAllFedClusterReports() map[string]*share.CLUSFedClusterReport {
+	reports := make(map[string]*share.CLUSFedClusterReport)
+	keys, _ := cluster.GetStoreKeys(share.CLUSFedClusterReportKey(""))
+	for _, key := range keys {
+		if value, err := cluster.Get(key); err == nil {
+			if uzb := utils.GunzipBytes(value); uzb != nil {
+				var report share.CLUSFedClusterReport
+				if json.Unmarshal(uzb, &report) == nil && report.ID != "" {
+					reports[report.ID] = &report
+				}
+			}
+		}
+	}
+	return reports
+}
+
+func (m clusterHelper) PutFedClusterReport(report *share.CLUSFedClusterReport) error {
+	value, _ := json.Marshal(report)
+	return cluster.PutBinary(share.CLUSFedClusterReportKey(report.ID), utils.GzipBytes(value))
+}
+
+func (m clusterHelper) DeleteFedClusterReport(id string) error {
+	return cluster.Delete(share.CLUSFedClusterReportKey(id))
+}
+
+func (m clusterHelper) GetFedReportTrend() *share.CLUSFedReportTrend {
+	if value, err := cluster.Get(share.CLUSFedReportTrendKey); err == nil {
+		if uzb := utils.GunzipBytes(value); uzb != nil {
+			var trend share.CLUSFedReportTrend
+			if json.Unmarshal(uzb, &trend) == nil {
+				return &trend
+			}
+		}
+	}
+	return &share.CLUSFedReportTrend{Points: make([]*share.CLUSFedReportTrendPoint, 0)}
+}
+
+func (m clusterHelper) PutFedReportTrend(trend *share.CLUSFedReportTrend) error {
+	value, _ := json.Marshal(trend)
+	return cluster.PutBinary(share.CLUSFedReportTrendKey, utils.GzipBytes(value))
+}
+
+func (m clusterHelper) DeleteFedReports() error {
+	return cluster.DeleteTree(share.CLUSFedReportStore)
+}
+
+func (m clusterHelper) GetAllReportSchedules() map[string]*share.CLUSReportSchedule {
+	keys, _ := cluster.GetStoreKeys(share.CLUSConfigReportStore)
+	schedules := make(map[string]*share.CLUSReportSchedule, len(keys))
+	for _, key := range keys {
+		if value, _, _ := m.get(key); value != nil {
+			var schedule share.CLUSReportSchedule
+			if json.Unmarshal(value, &schedule) == nil {
+				schedules[schedule.Name] = &schedule
+			}
+		}
+	}
+	return schedules
+}
+
+func (m clusterHelper) GetReportScheduleRev(name string) (*share.CLUSReportSchedule, uint64, error) {
+	if value, rev, _ := m.get(share.CLUSConfigReportKey(name)); value != nil {
+		var schedule share.CLUSReportSchedule
+		json.Unmarshal(value, &schedule)
+		return &schedule, rev, nil
+	}
+	return nil, 0, common.ErrObjectNotFound
+}
+
+func (m clusterHelper) PutReportScheduleRev(schedule *share.CLUSReportSchedule, rev uint64) error {
+	key := share.CLUSConfigReportKey(schedule.Name)
+	value, _ := json.Marshal(schedule)
+	if rev == 0 {
+		return cluster.Put(key, value)
+	} else {
+		return cluster.PutRev(key, value, rev)
+	}
+}
+
+func (m clusterHelper) DeleteReportSchedule(name string) error {
+	return cluster.Delete(share.CLUSConfigReportKey(name))
+}
+
+func (m clusterHelper) GetReportStatus() *share.CLUSReportStatus {
+	var status share.CLUSReportStatus
+	if value, _, _ := m.get(share.CLUSReportStatusKey); value != nil {
+		json.Unmarshal(value, &status)
+	}
+	if status.Schedules == nil {
+		status.Schedules = make(map[string]*share.CLUSReportScheduleStatus)
+	}
+	return &status
+}
+
+func (m clusterHelper) PutReportStatus(status *share.CLUSReportStatus) error {
+	value, _ := json.Marshal(status)
+	return cluster.PutQuiet(share.CLUSReportStatusKey, value)
+}
+
+// generated reports are always compressed like fed reports
+func (m clusterHelper) GetAllReports() []*share.CLUSReport {
+	keys, _ := cluster.GetStoreKeys(share.CLUSReportStore)
+	reports := make([]*share.CLUSReport, 0, len(keys))
+	for _, key := range keys {
+		if report, err := m.GetReport(share.CLUSKeyLastToken(key)); err == nil {
+			reports = append(reports, report)
+		}
+	}
+	return reports
+}
+
+func (m clusterHelper) GetReport(id string) (*share.CLUSReport, error) {
+	value, err := cluster.Get(share.CLUSReportKey(id))
+	if err != nil {
+		return nil, common.ErrObjectNotFound
+	}
+	if uzb := utils.GunzipBytes(value); uzb != nil {
+		var report share.CLUSReport
+		if json.Unmarshal(uzb, &report) == nil && report.ID != "" {
+			return &report, nil
+		}
+	}
+	return nil, common.ErrObjectNotFound
+}
+
+func (m clusterHelper) PutReport(report *share.CLUSReport) error {
+	value, _ := json.Marshal(report)
+	return cluster.PutBinary(share.CLUSReportKey(report.ID), utils.GzipBytes(value))
+}
+
+func (m clusterHelper) DeleteReport(id string) error {
+	return cluster.Delete(share.CLUSReportKey(id))
+}
+
 func (m clusterHelper) GetApikeyRev(name string, acc *access.AccessControl) (*share.CLUSApikey, uint64, error) {
 	key := share.CLUSApikeyKey(url.QueryEscape(name))
 	if value, rev, _ := m.get(key); value != nil {
@@ -2908,6 +3299,12 @@ func (m clusterHelper) CreateApikey(apikey *share.CLUSApikey) error {
 	return cluster.PutIfNotExist(key, value, false)
 }
 
+func (m clusterHelper) PutApikeyRev(apikey *share.CLUSApikey, rev uint64) error {
+	key := share.CLUSApikeyKey(url.QueryEscape(apikey.Name))
+	value, _ := json.Marshal(apikey)
+	return cluster.PutRev(key, value, rev)
+}
+
 // caller needs to decide whether to authorize accessing each returned apikey object
 func (m clusterHelper) GetAllApikeysNoAuth() map[string]*share.CLUSApikey {
 	apikeys := make(map[string]*share.CLUSApikey)
@@ -3147,3 +3544,88 @@ func (m clusterHelper) GetAllSigstoreVerifiersForRoot(rootName string) ([]*share
 	}
 	return verifiers, nil
 }
+
+func (m clusterHelper) GetAllRiskAcceptances(riskType string) []*share.CLUSRiskAcceptance {
+	keys, _ := cluster.GetStoreKeys(share.CLUSRiskAcceptanceStore(riskType))
+	list := make([]*share.CLUSRiskAcceptance, 0, len(keys))
+	for _, key := range keys {
+		if value, _, _ := m.get(key); value != nil {
+			var ra share.CLUSRiskAcceptance
+			if json.Unmarshal(value, &ra) == nil {
+				list = append(list, &ra)
+			}
+		}
+	}
+	return list
+}
+
+func (m clusterHelper) GetRiskAcceptanceRev(riskType, id string) (*share.CLUSRiskAcceptance, uint64, error) {
+	if value, rev, _ := m.get(share.CLUSRiskAcceptanceKey(riskType, id)); value != nil {
+		var ra share.CLUSRiskAcceptance
+		json.Unmarshal(value, &ra)
+		return &ra, rev, nil
+	}
+	return nil, 0, common.ErrObjectNotFound
+}
+
+func (m clusterHelper) PutRiskAcceptanceRev(ra *share.CLUSRiskAcceptance, rev uint64) error {
+	key := share.CLUSRiskAcceptanceKey(ra.Type, ra.ID)
+	value, _ := json.Marshal(ra)
+	if rev == 0 {
+		return cluster.Put(key, value)
+	} else {
+		return cluster.PutRev(key, value, rev)
+	}
+}
+
+func (m clusterHelper) DeleteRiskAcceptance(riskType, id string) error {
+	return cluster.Delete(share.CLUSRiskAcceptanceKey(riskType, id))
+}
+
+func (m clusterHelper) GetAllPolicyChanges() []*share.CLUSPolicyChange {
+	keys, _ := cluster.GetStoreKeys(share.CLUSConfigPolicyChangeStore)
+	list := make([]*share.CLUSPolicyChange, 0, len(keys))
+	for _, key := range keys {
+		if value, _, _ := m.get(key); value != nil {
+			var pc share.CLUSPolicyChange
+			if json.Unmarshal(value, &pc) == nil {
+				list = append(list, &pc)
+			}
+		}
+	}
+	return list
+}
+
+func (m clusterHelper) GetPolicyChangeRev(id string) (*share.CLUSPolicyChange, uint64, error) {
+	if value, rev, _ := m.get(share.CLUSPolicyChangeKey(id)); value != nil {
+		var pc share.CLUSPolicyChange
+		json.Unmarshal(value, &pc)
+		return &pc, rev, nil
+	}
+	return nil, 0, common.ErrObjectNotFound
+}
+
+func (m clusterHelper) PutPolicyChangeRev(pc *share.CLUSPolicyChange, rev uint64) error {
+	key := share.CLUSPolicyChangeKey(pc.ID)
+	value, _ := json.Marshal(pc)
+	if rev == 0 {
+		return cluster.Put(key, value)
+	} else {
+		return cluster.PutRev(key, value, rev)
+	}
+}
+
+func (m clusterHelper) PutPolicyChangeTxn(txn *cluster.ClusterTransact, pc *share.CLUSPolicyChange, rev uint64) error {
+	key := share.CLUSPolicyChangeKey(pc.ID)
+	value, _ := json.Marshal(pc)
+	if rev == 0 {
+		txn.Put(key, value)
+	} else {
+		txn.PutRev(key, value, rev)
+	}
+	return nil
+}
+
+func (m clusterHelper) DeletePolicyChange(id string) error {
+	return cluster.Delete(share.CLUSPolicyChangeKey(id))
+}
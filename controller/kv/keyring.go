@@ -0,0 +1,331 @@
+package kv
+
+// Keyring of the kv envelope encryption. The data keys are generated by controllers and stored in kv wrapped by
+// the key provider. Rotation creates a new active data key, rewraps all data keys with the current key encryption
+// key of the provider and re-encrypts the cloaked values in kv.
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/cluster"
+)
+
+const kvKeyringLockWait = time.Second * 30
+
+var ErrKvEncryptionDisabled = errors.New("kv encryption is not enabled")
+
+var kvEncryptMutex sync.Mutex
+var kvEncryptCfg *KvEncryptConfig
+
+// kv keys with cloaked fields
+type kvCloakedKey struct {
+	prefix    string
+	tokens    int  // number of tokens of the keys in the store, 0 if the prefix is the key
+	uncloaked bool // the value is decrypted by UpgradeAndConvert()
+	newObj    func() interface{}
+}
+
+var kvCloakedKeys = []kvCloakedKey{
+	{share.CLUSConfigSystemKey, 0, true, func() interface{} { return &share.CLUSSystemConfig{} }},
+	{share.CLUSFedKey(share.CFGEndpointSystem), 0, true, func() interface{} { return &share.CLUSSystemConfig{} }},
+	{share.CLUSConfigServerStore, 4, true, func() interface{} { return &share.CLUSServer{} }},
+	{share.CLUSConfigRegistryStore, 4, true, func() interface{} { return &share.CLUSRegistryConfig{} }},
+	{share.CLUSConfigCloudStore, 5, true, func() interface{} { return &share.CLUSAwsProjectCfg{} }},
+	{share.CLUSCloudStore, 4, true, func() interface{} { return &share.CLUSAwsResource{} }},
+	{share.CLUSConfigBackupKey, 0, false, func() interface{} { return &share.CLUSBackupConfig{} }},
+	{share.CLUSFedKey(share.CLUSFedMembershipSubKey), 0, false, func() interface{} { return &share.CLUSFedMembership{} }},
+	{share.CLUSConfigFederationStore + share.CLUSFedClustersSubKey + "/", 5, false, func() interface{} { return &share.CLUSFedJointClusterInfo{} }},
+	{share.CLUSCertStore, 3, false, func() interface{} { return &share.CLUSX509Cert{} }},
+	{share.CLUSConfigUserStore, 4, false, func() interface{} { return &share.CLUSUser{} }},
+}
+
+func newKvDataKeyID() string {
+	b, _ := common.NewKvDataKey()
+	return hex.EncodeToString(b[:6])
+}
+
+func getKvKeyring() *share.CLUSKvKeyring {
+	value, _ := cluster.Get(share.CLUSKvKeyringKey)
+	if value == nil {
+		return nil
+	}
+	var keyring share.CLUSKvKeyring
+	if err := json.Unmarshal(value, &keyring); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Invalid keyring")
+		return nil
+	}
+	return &keyring
+}
+
+func GetKvKeyring() *share.CLUSKvKeyring {
+	return getKvKeyring()
+}
+
+func unwrapKvDataKeys(provider kvKeyProvider, keyring *share.CLUSKvKeyring) (map[string][]byte, error) {
+	keys := make(map[string][]byte, len(keyring.DataKeys))
+	for _, dk := range keyring.DataKeys {
+		key, err := provider.unwrapKey(dk.WrappedKey)
+		if err != nil {
+			log.WithFields(log.Fields{"key": dk.ID, "error": err}).Error("Failed to unwrap data key")
+			return nil, err
+		}
+		keys[dk.ID] = key
+	}
+	return keys, nil
+}
+
+// data keys are all rewrapped, so the previous key encryption key can be retired
+func rewrapKvDataKeys(provider kvKeyProvider, keyring *share.CLUSKvKeyring, keys map[string][]byte) error {
+	for _, dk := range keyring.DataKeys {
+		wrapped, err := provider.wrapKey(keys[dk.ID])
+		if err != nil {
+			log.WithFields(log.Fields{"key": dk.ID, "error": err}).Error("Failed to wrap data key")
+			return err
+		}
+		dk.WrappedKey = wrapped
+	}
+	keyring.Provider = provider.name()
+	keyring.KeyID = provider.keyID()
+	return nil
+}
+
+func addKvDataKey(provider kvKeyProvider, keyring *share.CLUSKvKeyring, keys map[string][]byte) error {
+	key, err := common.NewKvDataKey()
+	if err != nil {
+		return err
+	}
+	wrapped, err := provider.wrapKey(key)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to wrap data key")
+		return err
+	}
+	id := newKvDataKeyID()
+	keyring.DataKeys = append(keyring.DataKeys, &share.CLUSKvDataKey{ID: id, WrappedKey: wrapped, CreatedAt: time.Now().UTC()})
+	keyring.ActiveKey = id
+	keys[id] = key
+	return nil
+}
+
+func putKvKeyring(keyring *share.CLUSKvKeyring) error {
+	value, _ := json.Marshal(keyring)
+	return cluster.Put(share.CLUSKvKeyringKey, value)
+}
+
+// Data keys created by other controllers are loaded when a value encrypted by them is read. The active key
+// is switched too, so the new values are encrypted by the data key after rotation.
+func loadKvDataKey(id string) []byte {
+	kvEncryptMutex.Lock()
+	cfg := kvEncryptCfg
+	kvEncryptMutex.Unlock()
+	if cfg == nil {
+		return nil
+	}
+
+	keyring := getKvKeyring()
+	if keyring == nil {
+		return nil
+	}
+	provider, err := newKvKeyProvider(cfg)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to create key provider")
+		return nil
+	}
+	keys, err := unwrapKvDataKeys(provider, keyring)
+	if err != nil {
+		return nil
+	}
+	common.SetKvDataKeys(keyring.ActiveKey, keys)
+	return keys[id]
+}
+
+// InitKvEncryption loads the keyring from kv, or creates it if this is the first controller with the encryption
+// enabled. Data keys are rewrapped when the key encryption key of the provider is changed.
+func InitKvEncryption(cfg *KvEncryptConfig) error {
+	if cfg.Provider == "" {
+		if getKvKeyring() != nil {
+			log.Error("kv encryption keyring exists but no key provider is configured")
+		}
+		return nil
+	}
+
+	provider, err := newKvKeyProvider(cfg)
+	if err != nil {
+		return err
+	}
+
+	lock, err := clusHelper.AcquireLock(share.CLUSLockKvKeyringKey, kvKeyringLockWait)
+	if err != nil {
+		return err
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	keys := make(map[string][]byte)
+	keyring := getKvKeyring()
+	if keyring == nil {
+		keyring = &share.CLUSKvKeyring{Provider: provider.name(), KeyID: provider.keyID(), RotatedAt: time.Now().UTC()}
+		if err = addKvDataKey(provider, keyring, keys); err != nil {
+			return err
+		}
+		if err = putKvKeyring(keyring); err != nil {
+			return err
+		}
+		log.WithFields(log.Fields{"provider": keyring.Provider, "key": keyring.ActiveKey}).Info("Created kv keyring")
+	} else {
+		if keys, err = unwrapKvDataKeys(provider, keyring); err != nil {
+			return err
+		}
+		if keyring.Provider != provider.name() || keyring.KeyID != provider.keyID() {
+			log.WithFields(log.Fields{
+				"from": keyring.Provider + ":" + keyring.KeyID, "to": provider.name() + ":" + provider.keyID(),
+			}).Info("Rewrap data keys")
+			if err = rewrapKvDataKeys(provider, keyring, keys); err != nil {
+				return err
+			}
+			if err = putKvKeyring(keyring); err != nil {
+				return err
+			}
+		}
+	}
+
+	kvEncryptMutex.Lock()
+	kvEncryptCfg = cfg
+	kvEncryptMutex.Unlock()
+
+	common.SetKvDataKeys(keyring.ActiveKey, keys)
+	common.SetKvDataKeyLoader(loadKvDataKey)
+	return nil
+}
+
+func IsKvEncryptionEnabled() bool {
+	return common.GetKvActiveKeyID() != ""
+}
+
+// RotateKvEncryptionKey creates a new active data key and re-encrypts the cloaked values with it. The key
+// provider is re-created, so a new master key in the key file is picked up.
+func RotateKvEncryptionKey() (*share.CLUSKvKeyring, int, error) {
+	kvEncryptMutex.Lock()
+	cfg := kvEncryptCfg
+	kvEncryptMutex.Unlock()
+	if cfg == nil {
+		return nil, 0, ErrKvEncryptionDisabled
+	}
+
+	provider, err := newKvKeyProvider(cfg)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	lock, err := clusHelper.AcquireLock(share.CLUSLockKvKeyringKey, kvKeyringLockWait)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	keyring := getKvKeyring()
+	if keyring == nil {
+		return nil, 0, common.ErrObjectNotFound
+	}
+	keys, err := unwrapKvDataKeys(provider, keyring)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err = rewrapKvDataKeys(provider, keyring, keys); err != nil {
+		return nil, 0, err
+	}
+	if err = addKvDataKey(provider, keyring, keys); err != nil {
+		return nil, 0, err
+	}
+	keyring.RotatedAt = time.Now().UTC()
+	if err = putKvKeyring(keyring); err != nil {
+		return nil, 0, err
+	}
+	common.SetKvDataKeys(keyring.ActiveKey, keys)
+	log.WithFields(log.Fields{"provider": keyring.Provider, "key": keyring.ActiveKey}).Info("Rotated kv data key")
+
+	return keyring, ReencryptKvSecrets(), nil
+}
+
+func lookupCloakedKey(key string) *kvCloakedKey {
+	for i, ck := range kvCloakedKeys {
+		if ck.tokens == 0 {
+			if key == ck.prefix {
+				return &kvCloakedKeys[i]
+			}
+		} else if strings.HasPrefix(key, ck.prefix) && share.CLUSKeyLength(key) == ck.tokens {
+			return &kvCloakedKeys[i]
+		}
+	}
+	return nil
+}
+
+func reencryptKvValue(ck *kvCloakedKey, key string) bool {
+	value, rev, err := cluster.GetRev(key)
+	if err != nil || len(value) == 0 {
+		return false
+	}
+
+	active := common.GetKvActiveKeyID()
+	value, _, wrt := UpgradeAndConvert(key, value)
+	if wrt {
+		// the upgraded value is written back
+		if _, rev, err = cluster.GetRev(key); err != nil {
+			return false
+		}
+	}
+	obj := ck.newObj()
+	if ck.uncloaked {
+		err = json.Unmarshal(value, obj)
+	} else {
+		err = dec.Unmarshal(value, obj)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"key": key, "error": err}).Error("Failed to read value")
+		return false
+	}
+
+	newValue, err := enc.Marshal(obj)
+	if err != nil {
+		return false
+	}
+	if err = cluster.PutRev(key, newValue, rev); err != nil {
+		// the value is updated by others and encrypted by the active key of that controller
+		log.WithFields(log.Fields{"key": key, "active": active, "error": err}).Info("Skip updated value")
+		return false
+	}
+	if key == share.CLUSConfigSystemKey {
+		cluster.Put(share.NetworkSystemKey, newValue)
+	}
+	return true
+}
+
+// ReencryptKvSecrets rewrites the values with cloaked fields, so they are encrypted by the active data key.
+// It returns the number of values re-encrypted.
+func ReencryptKvSecrets() int {
+	if !IsKvEncryptionEnabled() {
+		return 0
+	}
+
+	var count int
+	for i, ck := range kvCloakedKeys {
+		keys := []string{ck.prefix}
+		if ck.tokens > 0 {
+			keys, _ = cluster.GetStoreKeys(ck.prefix)
+		}
+		for _, key := range keys {
+			if lookupCloakedKey(key) == &kvCloakedKeys[i] && reencryptKvValue(&kvCloakedKeys[i], key) {
+				count++
+			}
+		}
+	}
+	log.WithFields(log.Fields{"count": count, "key": common.GetKvActiveKeyID()}).Info("Re-encrypted kv secrets")
+	return count
+}
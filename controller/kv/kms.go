@@ -0,0 +1,372 @@
+package kv
+
+// Key encryption key providers of the kv envelope encryption. The data keys are wrapped by a local master key,
+// AWS KMS or Vault transit secrets engine, so the data keys stored in kv alone cannot decrypt the secrets.
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+
+	"github.com/neuvector/neuvector/share"
+)
+
+const kmsRequestTimeout = time.Second * 30
+const kmsMasterKeySize = 32
+
+type KvEncryptConfig struct {
+	Provider   string
+	KeyFile    string // local master key file
+	KmsKey     string // AWS KMS key id or arn, or Vault transit key name
+	KmsRegion  string
+	KmsAddr    string // Vault address or AWS KMS endpoint
+	VaultMount string
+}
+
+type kvKeyProvider interface {
+	name() string
+	keyID() string
+	wrapKey(key []byte) (string, error)
+	unwrapKey(wrapped string) ([]byte, error)
+}
+
+var errKmsInvalidWrappedKey = errors.New("Invalid wrapped key")
+
+func newKvKeyProvider(cfg *KvEncryptConfig) (kvKeyProvider, error) {
+	switch cfg.Provider {
+	case share.KvKeyProviderLocal:
+		return newLocalKeyProvider(cfg.KeyFile)
+	case share.KvKeyProviderAwsKMS:
+		return newAwsKMSKeyProvider(cfg)
+	case share.KvKeyProviderVault:
+		return newVaultKeyProvider(cfg)
+	}
+	return nil, fmt.Errorf("Unsupported key provider: %s", cfg.Provider)
+}
+
+func createKmsHttpClient() *http.Client {
+	transport := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+	return &http.Client{Transport: transport, Timeout: kmsRequestTimeout}
+}
+
+func sendKmsRequest(client *http.Client, req *http.Request, resp interface{}) error {
+	r, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if r.StatusCode != http.StatusOK {
+		msg := string(body)
+		if len(msg) > 256 {
+			msg = msg[:256]
+		}
+		return fmt.Errorf("%s %s: %s, %s", req.Method, req.URL.Path, r.Status, msg)
+	}
+	return json.Unmarshal(body, resp)
+}
+
+// -- local master key
+
+// The master key file has one base64 or hex encoded 256-bit key in each line. The first key wraps new data keys
+// and the others are kept to unwrap the data keys wrapped before the master key is rotated.
+type localKeyProvider struct {
+	ids  []string
+	keys map[string][]byte
+}
+
+func parseMasterKey(line string) ([]byte, error) {
+	if key, err := hex.DecodeString(line); err == nil && len(key) == kmsMasterKeySize {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(line); err == nil && len(key) == kmsMasterKeySize {
+		return key, nil
+	}
+	return nil, fmt.Errorf("Master key must be a base64 or hex encoded %d-byte key", kmsMasterKeySize)
+}
+
+func newLocalKeyProvider(filename string) (*localKeyProvider, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p := &localKeyProvider{keys: make(map[string][]byte)}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := parseMasterKey(line)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(key)
+		id := hex.EncodeToString(sum[:8])
+		if _, ok := p.keys[id]; !ok {
+			p.ids = append(p.ids, id)
+			p.keys[id] = key
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(p.ids) == 0 {
+		return nil, fmt.Errorf("No master key in %s", filename)
+	}
+	return p, nil
+}
+
+func (p *localKeyProvider) name() string {
+	return share.KvKeyProviderLocal
+}
+
+func (p *localKeyProvider) keyID() string {
+	return p.ids[0]
+}
+
+func (p *localKeyProvider) wrapKey(key []byte) (string, error) {
+	block, err := aes.NewCipher(p.keys[p.ids[0]])
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, key, nil)
+	return p.ids[0] + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (p *localKeyProvider) unwrapKey(wrapped string) ([]byte, error) {
+	tokens := strings.SplitN(wrapped, ":", 2)
+	if len(tokens) != 2 {
+		return nil, errKmsInvalidWrappedKey
+	}
+	master, ok := p.keys[tokens[0]]
+	if !ok {
+		return nil, fmt.Errorf("Master key %s is not found", tokens[0])
+	}
+	sealed, err := base64.StdEncoding.DecodeString(tokens[1])
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(master)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errKmsInvalidWrappedKey
+	}
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+}
+
+// -- AWS KMS
+
+// Credentials are resolved by the default chain of aws sdk, including environment variables, web identity of
+// the service account and instance role.
+type awsKMSKeyProvider struct {
+	client   *http.Client
+	signer   *v4.Signer
+	endpoint string
+	region   string
+	key      string
+}
+
+func newAwsKMSKeyProvider(cfg *KvEncryptConfig) (*awsKMSKeyProvider, error) {
+	if cfg.KmsKey == "" {
+		return nil, errors.New("KMS key is not specified")
+	}
+
+	region := cfg.KmsRegion
+	if region == "" && strings.HasPrefix(cfg.KmsKey, "arn:") {
+		// arn:aws:kms:<region>:<account>:key/<id>
+		if tokens := strings.Split(cfg.KmsKey, ":"); len(tokens) > 3 {
+			region = tokens[3]
+		}
+	}
+	if region == "" {
+		return nil, errors.New("KMS region is not specified")
+	}
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(region)})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := strings.TrimRight(cfg.KmsAddr, "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com", region)
+	}
+	return &awsKMSKeyProvider{
+		client:   createKmsHttpClient(),
+		signer:   v4.NewSigner(sess.Config.Credentials),
+		endpoint: endpoint,
+		region:   region,
+		key:      cfg.KmsKey,
+	}, nil
+}
+
+func (p *awsKMSKeyProvider) call(action string, body, resp interface{}) error {
+	data, _ := json.Marshal(body)
+	req, err := http.NewRequest("POST", p.endpoint+"/", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	if _, err = p.signer.Sign(req, bytes.NewReader(data), "kms", p.region, time.Now()); err != nil {
+		return err
+	}
+	return sendKmsRequest(p.client, req, resp)
+}
+
+func (p *awsKMSKeyProvider) name() string {
+	return share.KvKeyProviderAwsKMS
+}
+
+func (p *awsKMSKeyProvider) keyID() string {
+	return p.key
+}
+
+// blobs in the requests and responses are base64 encoded
+func (p *awsKMSKeyProvider) wrapKey(key []byte) (string, error) {
+	var resp struct {
+		CiphertextBlob string `json:"CiphertextBlob"`
+	}
+	req := map[string]string{"KeyId": p.key, "Plaintext": base64.StdEncoding.EncodeToString(key)}
+	if err := p.call("Encrypt", req, &resp); err != nil {
+		return "", err
+	}
+	return resp.CiphertextBlob, nil
+}
+
+// The ciphertext blob identifies the key that encrypted it, so data keys wrapped by the previous KMS key can be
+// unwrapped as long as the key is accessible.
+func (p *awsKMSKeyProvider) unwrapKey(wrapped string) ([]byte, error) {
+	var resp struct {
+		Plaintext string `json:"Plaintext"`
+	}
+	if err := p.call("Decrypt", map[string]string{"CiphertextBlob": wrapped}, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Plaintext)
+}
+
+// -- Vault transit
+
+// The token is read from VAULT_TOKEN environment variable. Vault keeps all versions of the transit key, so data
+// keys wrapped before the transit key is rotated can still be unwrapped.
+type vaultKeyProvider struct {
+	client    *http.Client
+	addr      string
+	mount     string
+	key       string
+	token     string
+	namespace string
+}
+
+func newVaultKeyProvider(cfg *KvEncryptConfig) (*vaultKeyProvider, error) {
+	if cfg.KmsAddr == "" || cfg.KmsKey == "" {
+		return nil, errors.New("Vault address or transit key is not specified")
+	}
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return nil, errors.New("VAULT_TOKEN is not set")
+	}
+	mount := strings.Trim(cfg.VaultMount, "/")
+	if mount == "" {
+		mount = "transit"
+	}
+	return &vaultKeyProvider{
+		client:    createKmsHttpClient(),
+		addr:      strings.TrimRight(cfg.KmsAddr, "/"),
+		mount:     mount,
+		key:       cfg.KmsKey,
+		token:     token,
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+	}, nil
+}
+
+func (p *vaultKeyProvider) call(action string, body map[string]string, resp interface{}) error {
+	data, _ := json.Marshal(body)
+	url := fmt.Sprintf("%s/v1/%s/%s/%s", p.addr, p.mount, action, p.key)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+	return sendKmsRequest(p.client, req, resp)
+}
+
+func (p *vaultKeyProvider) name() string {
+	return share.KvKeyProviderVault
+}
+
+func (p *vaultKeyProvider) keyID() string {
+	return p.mount + "/" + p.key
+}
+
+func (p *vaultKeyProvider) wrapKey(key []byte) (string, error) {
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	if err := p.call("encrypt", map[string]string{"plaintext": base64.StdEncoding.EncodeToString(key)}, &resp); err != nil {
+		return "", err
+	}
+	return resp.Data.Ciphertext, nil
+}
+
+func (p *vaultKeyProvider) unwrapKey(wrapped string) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := p.call("decrypt", map[string]string{"ciphertext": wrapped}, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+}
@@ -0,0 +1,193 @@
+package kv
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/neuvector/neuvector/share"
+)
+
+func writeMasterKeyFile(t *testing.T, lines ...string) string {
+	f, err := ioutil.TempFile("", "nvkey")
+	if err != nil {
+		t.Fatalf("Failed to create key file: %s", err)
+	}
+	f.WriteString(strings.Join(lines, "\n"))
+	f.Close()
+	return f.Name()
+}
+
+func TestLocalKeyProvider(t *testing.T) {
+	master1 := hex.EncodeToString(bytes.Repeat([]byte{1}, kmsMasterKeySize))
+	master2 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, kmsMasterKeySize))
+
+	file := writeMasterKeyFile(t, master1)
+	defer os.Remove(file)
+	p1, err := newKvKeyProvider(&KvEncryptConfig{Provider: share.KvKeyProviderLocal, KeyFile: file})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %s", err)
+	}
+	key := bytes.Repeat([]byte{9}, 32)
+	wrapped, err := p1.wrapKey(key)
+	if err != nil {
+		t.Fatalf("Failed to wrap key: %s", err)
+	}
+
+	// new master key is added in front of the old one
+	file2 := writeMasterKeyFile(t, "# rotated", master2, master1)
+	defer os.Remove(file2)
+	p2, err := newKvKeyProvider(&KvEncryptConfig{Provider: share.KvKeyProviderLocal, KeyFile: file2})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %s", err)
+	}
+	if p2.keyID() == p1.keyID() {
+		t.Errorf("Key id should be changed after rotation: %s", p2.keyID())
+	}
+	if unwrapped, err := p2.unwrapKey(wrapped); err != nil || !bytes.Equal(unwrapped, key) {
+		t.Errorf("Failed to unwrap key: %v", err)
+	}
+
+	file3 := writeMasterKeyFile(t, master2)
+	defer os.Remove(file3)
+	p3, _ := newKvKeyProvider(&KvEncryptConfig{Provider: share.KvKeyProviderLocal, KeyFile: file3})
+	if _, err := p3.unwrapKey(wrapped); err == nil {
+		t.Errorf("Key should not be unwrapped without the master key")
+	}
+
+	file4 := writeMasterKeyFile(t, "short")
+	defer os.Remove(file4)
+	if _, err := newKvKeyProvider(&KvEncryptConfig{Provider: share.KvKeyProviderLocal, KeyFile: file4}); err == nil {
+		t.Errorf("Invalid master key should be rejected")
+	}
+}
+
+func TestVaultKeyProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/nv":
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"ciphertext": "vault:v1:" + req["plaintext"]}})
+		case "/v1/transit/decrypt/nv":
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"plaintext": strings.TrimPrefix(req["ciphertext"], "vault:v1:")}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	os.Setenv("VAULT_TOKEN", "token")
+	defer os.Unsetenv("VAULT_TOKEN")
+
+	p, err := newKvKeyProvider(&KvEncryptConfig{Provider: share.KvKeyProviderVault, KmsAddr: server.URL, KmsKey: "nv"})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %s", err)
+	}
+	key := []byte("data key")
+	wrapped, err := p.wrapKey(key)
+	if err != nil || !strings.HasPrefix(wrapped, "vault:v1:") {
+		t.Fatalf("Failed to wrap key: %s, %v", wrapped, err)
+	}
+	if unwrapped, err := p.unwrapKey(wrapped); err != nil || !bytes.Equal(unwrapped, key) {
+		t.Errorf("Failed to unwrap key: %v", err)
+	}
+}
+
+func TestAwsKMSKeyProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		if !strings.Contains(r.Header.Get("Authorization"), "/us-west-2/kms/aws4_request") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.Encrypt":
+			json.NewEncoder(w).Encode(map[string]string{"CiphertextBlob": base64.StdEncoding.EncodeToString([]byte(req["KeyId"] + "|" + req["Plaintext"]))})
+		case "TrentService.Decrypt":
+			blob, _ := base64.StdEncoding.DecodeString(req["CiphertextBlob"])
+			json.NewEncoder(w).Encode(map[string]string{"Plaintext": strings.SplitN(string(blob), "|", 2)[1]})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	os.Setenv("AWS_ACCESS_KEY_ID", "id")
+	os.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	defer os.Unsetenv("AWS_ACCESS_KEY_ID")
+	defer os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+
+	// region is taken from the key arn
+	cfg := &KvEncryptConfig{Provider: share.KvKeyProviderAwsKMS, KmsAddr: server.URL, KmsKey: "arn:aws:kms:us-west-2:111122223333:key/nv"}
+	p, err := newKvKeyProvider(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %s", err)
+	}
+	key := []byte("data key")
+	wrapped, err := p.wrapKey(key)
+	if err != nil {
+		t.Fatalf("Failed to wrap key: %v", err)
+	}
+	if unwrapped, err := p.unwrapKey(wrapped); err != nil || !bytes.Equal(unwrapped, key) {
+		t.Errorf("Failed to unwrap key: %v", err)
+	}
+
+	if _, err := newKvKeyProvider(&KvEncryptConfig{Provider: share.KvKeyProviderAwsKMS, KmsKey: "alias/nv"}); err == nil {
+		t.Errorf("Region should be required")
+	}
+}
+
+func TestLookupCloakedKey(t *testing.T) {
+	tests := map[string]bool{
+		share.CLUSConfigSystemKey:                                true,
+		share.CLUSServerKey("ldap1"):                             true,
+		share.CLUSRegistryConfigKey("reg1"):                      true,
+		share.CLUSFedJointClusterKey("id1"):                      true,
+		share.CLUSFedJointClusterStatusKey("id1"):                false,
+		share.CLUSCloudFuncKey(share.CloudAws, "p", "r", "func"): false,
+		share.CLUSObjectCertKey("cn"):                            true,
+		share.CLUSUserKey("admin"):                               true,
+		share.CLUSConfigUserRoleStore + "role1":                  false,
+	}
+	for key, expect := range tests {
+		if ck := lookupCloakedKey(key); (ck != nil) != expect {
+			t.Errorf("Unexpected lookup result: %s", key)
+		}
+	}
+}
+
+func TestUpgradeWebhookHeaders(t *testing.T) {
+	webhooks := []share.CLUSWebhook{
+		{Name: "w1", Headers: []share.CLUSWebhookHeader{{Name: "Authorization", Value: "Bearer token"}}},
+	}
+	if !upgradeWebhookHeaders(webhooks) {
+		t.Errorf("Plain text header value should be upgraded")
+	}
+	h := webhooks[0].Headers[0]
+	if !h.Cloaked || h.Value == "Bearer token" {
+		t.Errorf("Header value is not cloaked: %+v", h)
+	}
+	if upgradeWebhookHeaders(webhooks) {
+		t.Errorf("Cloaked header value should not be upgraded again")
+	}
+
+	var cfg share.CLUSSystemConfig
+	cfg.Webhooks = webhooks
+	dec.Uncloak(&cfg)
+	if cfg.Webhooks[0].Headers[0].Value != "Bearer token" {
+		t.Errorf("Unexpected header value: %s", cfg.Webhooks[0].Headers[0].Value)
+	}
+}
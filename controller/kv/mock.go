@@ -33,11 +33,23 @@ type MockCluster struct {
 	ClusterHelper
 
 	sysconfig            share.CLUSSystemConfig
+	threatSigBundle      share.CLUSThreatSignatureBundle
 	customrolesCluster   map[string]*share.CLUSUserRole
 	activePwdProfileName string
 	pwdProfileCluster    map[string]*share.CLUSPwdProfile
 	usersCluster         map[string]*share.CLUSUser
 	apikeysCluster       map[string]*share.CLUSApikey
+	configAuditHead      *share.CLUSConfigAuditHead
+	recoveryToken        *share.CLUSRecoveryToken
+	backupConfig         *share.CLUSBackupConfig
+	backupStatus         *share.CLUSBackupStatus
+	reportSchedules      map[string]*share.CLUSReportSchedule
+	reportStatus         *share.CLUSReportStatus
+	reports              map[string]*share.CLUSReport
+	riskAcceptances      map[string]*share.CLUSRiskAcceptance
+	policyChanges        map[string]*share.CLUSPolicyChange
+	fedQuarImages        []*share.CLUSFedQuarantineImage
+	wlConfigs            map[string]*share.CLUSWorkloadConfig
 	serversCluster       map[string]*share.CLUSServer
 	registries           map[string]*share.CLUSRegistryConfig
 
@@ -48,6 +60,7 @@ type MockCluster struct {
 	groupsCluster map[string]*share.CLUSGroup
 
 	complianceProfiles map[string]*share.CLUSComplianceProfile
+	customChecks       map[string]*share.CLUSCustomCheckGroup
 
 	awsCloudResource map[string]*share.CLUSAwsResource
 	awsProjectCfg    map[string]*share.CLUSAwsProjectCfg
@@ -87,6 +100,7 @@ func (m *MockCluster) Init(rules []*share.CLUSPolicyRule, groups []*share.CLUSGr
 	m.apikeysCluster = make(map[string]*share.CLUSApikey)
 	m.serversCluster = make(map[string]*share.CLUSServer)
 	m.registries = make(map[string]*share.CLUSRegistryConfig)
+	m.wlConfigs = make(map[string]*share.CLUSWorkloadConfig)
 
 	m.ScanSums = make(map[string]*share.CLUSRegistryImageSummary, 0)
 	m.ScanRpts = make(map[string]*share.CLUSScanReport, 0)
@@ -99,6 +113,13 @@ func (m *MockCluster) Init(rules []*share.CLUSPolicyRule, groups []*share.CLUSGr
 		},
 	}
 
+	m.customChecks = make(map[string]*share.CLUSCustomCheckGroup)
+
+	m.reportSchedules = make(map[string]*share.CLUSReportSchedule)
+	m.reports = make(map[string]*share.CLUSReport)
+	m.riskAcceptances = make(map[string]*share.CLUSRiskAcceptance)
+	m.policyChanges = make(map[string]*share.CLUSPolicyChange)
+
 	m.awsCloudResource = make(map[string]*share.CLUSAwsResource)
 	m.awsProjectCfg = make(map[string]*share.CLUSAwsProjectCfg)
 }
@@ -261,6 +282,34 @@ func (m *MockCluster) PutSystemConfigRev(conf *share.CLUSSystemConfig, rev uint6
 	return nil
 }
 
+func (m *MockCluster) GetThreatSignatureBundleRev(acc *access.AccessControl) (*share.CLUSThreatSignatureBundle, uint64) {
+	if m.threatSigBundle.Version == "" && len(m.threatSigBundle.Signatures) == 0 {
+		return nil, 0
+	}
+	// Make copy
+	bundle := m.threatSigBundle
+	return &bundle, 0
+}
+
+func (m *MockCluster) PutThreatSignatureBundleRev(bundle *share.CLUSThreatSignatureBundle, rev uint64) error {
+	m.threatSigBundle = *bundle
+	return nil
+}
+
+func (m *MockCluster) GetWorkloadConfigRev(hostID, wlID string) (*share.CLUSWorkloadConfig, uint64) {
+	if conf, ok := m.wlConfigs[share.CLUSUniconfWorkloadKey(hostID, wlID)]; ok {
+		c := *conf
+		return &c, 0
+	}
+	return nil, 0
+}
+
+func (m *MockCluster) PutWorkloadConfigRev(hostID, wlID string, conf *share.CLUSWorkloadConfig, rev uint64) error {
+	c := *conf
+	m.wlConfigs[share.CLUSUniconfWorkloadKey(hostID, wlID)] = &c
+	return nil
+}
+
 func (m *MockCluster) GetRegistry(name string, acc *access.AccessControl) (*share.CLUSRegistryConfig, uint64, error) {
 	if r, ok := m.registries[name]; ok {
 		if !acc.Authorize(r, nil) {
@@ -323,6 +372,10 @@ func (m *MockCluster) PutGroup(group *share.CLUSGroup, create bool) error {
 	return nil
 }
 
+func (m *MockCluster) PutGroupTxn(txn *cluster.ClusterTransact, group *share.CLUSGroup) error {
+	return m.PutGroup(group, false)
+}
+
 func (m *MockCluster) DeleteGroup(name string) error {
 	if _, ok := m.groupsCluster[name]; ok {
 		delete(m.groupsCluster, name)
@@ -332,6 +385,10 @@ func (m *MockCluster) DeleteGroup(name string) error {
 	}
 }
 
+func (m *MockCluster) DeleteGroupTxn(txn *cluster.ClusterTransact, name string) error {
+	return m.DeleteGroup(name)
+}
+
 func (m *MockCluster) GetPolicyRuleList() []*share.CLUSRuleHead {
 	return m.rulesHead
 }
@@ -347,7 +404,8 @@ func (m *MockCluster) PutPolicyRuleListTxn(txn *cluster.ClusterTransact, crhs []
 
 func (m *MockCluster) GetPolicyRule(id uint32) (*share.CLUSPolicyRule, uint64) {
 	if r, ok := m.rulesCluster[id]; ok {
-		return r, m.ruleRev
+		clone := *r
+		return &clone, m.ruleRev
 	} else {
 		return nil, 0
 	}
@@ -423,6 +481,17 @@ func (m *MockCluster) GetResponseRuleList(policyName string) []*share.CLUSRuleHe
 	return nil
 }
 
+func (m *MockCluster) GetResponseQuarantineImages(policyName string) ([]*share.CLUSFedQuarantineImage, uint64) {
+	images := make([]*share.CLUSFedQuarantineImage, len(m.fedQuarImages))
+	copy(images, m.fedQuarImages)
+	return images, 0
+}
+
+func (m *MockCluster) PutResponseQuarantineImagesRev(policyName string, images []*share.CLUSFedQuarantineImage, rev uint64) error {
+	m.fedQuarImages = images
+	return nil
+}
+
 func (m *MockCluster) PutRegistryImageSummary(name, id string, sum *share.CLUSRegistryImageSummary) error {
 	return nil
 }
@@ -510,8 +579,27 @@ func (m *MockCluster) GetAwsProjectCfg(projectName string, acc *access.AccessCon
 	return nil, common.ErrObjectNotFound
 }
 
+func (m *MockCluster) GetCustomCheckConfig(group string) (*share.CLUSCustomCheckGroup, uint64) {
+	if conf, ok := m.customChecks[group]; ok {
+		clone := *conf
+		return &clone, 0
+	}
+	return nil, 0
+}
+
 func (m *MockCluster) GetAllCustomCheckConfig() map[string]*share.CLUSCustomCheckGroup {
-	return make(map[string]*share.CLUSCustomCheckGroup)
+	scripts := make(map[string]*share.CLUSCustomCheckGroup)
+	for group, conf := range m.customChecks {
+		clone := *conf
+		scripts[group] = &clone
+	}
+	return scripts
+}
+
+func (m *MockCluster) PutCustomCheckConfig(group string, conf *share.CLUSCustomCheckGroup, rev uint64) error {
+	clone := *conf
+	m.customChecks[group] = &clone
+	return nil
 }
 
 func (m *MockCluster) PutRegistryImageSummaryAndReport(name, id, fedRole string, sum *share.CLUSRegistryImageSummary, report *share.CLUSScanReport) error {
@@ -552,6 +640,12 @@ func (m *MockCluster) CreateApikey(apikey *share.CLUSApikey) error {
 	return nil
 }
 
+func (m *MockCluster) PutApikeyRev(apikey *share.CLUSApikey, rev uint64) error {
+	clone := *apikey
+	m.apikeysCluster[apikey.Name] = &clone
+	return nil
+}
+
 func (m *MockCluster) DeleteApikey(name string) error {
 	if _, ok := m.apikeysCluster[name]; ok {
 		delete(m.apikeysCluster, name)
@@ -560,3 +654,214 @@ func (m *MockCluster) DeleteApikey(name string) error {
 		return common.ErrObjectNotFound
 	}
 }
+
+func (m *MockCluster) GetConfigAuditHead() *share.CLUSConfigAuditHead {
+	if m.configAuditHead == nil {
+		return nil
+	}
+	head := *m.configAuditHead
+	return &head
+}
+
+func (m *MockCluster) PutConfigAuditHead(head *share.CLUSConfigAuditHead) error {
+	clone := *head
+	m.configAuditHead = &clone
+	return nil
+}
+
+func (m *MockCluster) GetRecoveryToken() (*share.CLUSRecoveryToken, error) {
+	if m.recoveryToken != nil {
+		clone := *m.recoveryToken
+		return &clone, nil
+	}
+	return nil, common.ErrObjectNotFound
+}
+
+func (m *MockCluster) PutRecoveryToken(token *share.CLUSRecoveryToken) error {
+	clone := *token
+	m.recoveryToken = &clone
+	return nil
+}
+
+func (m *MockCluster) DeleteRecoveryToken() error {
+	if m.recoveryToken == nil {
+		return common.ErrObjectNotFound
+	}
+	m.recoveryToken = nil
+	return nil
+}
+
+func (m *MockCluster) GetBackupConfigRev() (*share.CLUSBackupConfig, uint64) {
+	if m.backupConfig != nil {
+		clone := *m.backupConfig
+		return &clone, 0
+	}
+	return &share.CLUSBackupConfig{}, 0
+}
+
+func (m *MockCluster) PutBackupConfigRev(cfg *share.CLUSBackupConfig, rev uint64) error {
+	clone := *cfg
+	m.backupConfig = &clone
+	return nil
+}
+
+func (m *MockCluster) GetBackupStatus() *share.CLUSBackupStatus {
+	if m.backupStatus != nil {
+		clone := *m.backupStatus
+		return &clone
+	}
+	return &share.CLUSBackupStatus{}
+}
+
+func (m *MockCluster) PutBackupStatus(status *share.CLUSBackupStatus) error {
+	clone := *status
+	m.backupStatus = &clone
+	return nil
+}
+
+func (m *MockCluster) GetAllReportSchedules() map[string]*share.CLUSReportSchedule {
+	schedules := make(map[string]*share.CLUSReportSchedule, len(m.reportSchedules))
+	for name, schedule := range m.reportSchedules {
+		clone := *schedule
+		schedules[name] = &clone
+	}
+	return schedules
+}
+
+func (m *MockCluster) GetReportScheduleRev(name string) (*share.CLUSReportSchedule, uint64, error) {
+	if schedule, ok := m.reportSchedules[name]; ok {
+		clone := *schedule
+		return &clone, 0, nil
+	}
+	return nil, 0, common.ErrObjectNotFound
+}
+
+func (m *MockCluster) PutReportScheduleRev(schedule *share.CLUSReportSchedule, rev uint64) error {
+	clone := *schedule
+	m.reportSchedules[schedule.Name] = &clone
+	return nil
+}
+
+func (m *MockCluster) DeleteReportSchedule(name string) error {
+	if _, ok := m.reportSchedules[name]; !ok {
+		return common.ErrObjectNotFound
+	}
+	delete(m.reportSchedules, name)
+	return nil
+}
+
+func (m *MockCluster) GetReportStatus() *share.CLUSReportStatus {
+	status := &share.CLUSReportStatus{Schedules: make(map[string]*share.CLUSReportScheduleStatus)}
+	if m.reportStatus != nil {
+		for name, s := range m.reportStatus.Schedules {
+			clone := *s
+			status.Schedules[name] = &clone
+		}
+	}
+	return status
+}
+
+func (m *MockCluster) PutReportStatus(status *share.CLUSReportStatus) error {
+	m.reportStatus = status
+	return nil
+}
+
+func (m *MockCluster) GetAllReports() []*share.CLUSReport {
+	reports := make([]*share.CLUSReport, 0, len(m.reports))
+	for _, report := range m.reports {
+		clone := *report
+		reports = append(reports, &clone)
+	}
+	return reports
+}
+
+func (m *MockCluster) GetReport(id string) (*share.CLUSReport, error) {
+	if report, ok := m.reports[id]; ok {
+		clone := *report
+		return &clone, nil
+	}
+	return nil, common.ErrObjectNotFound
+}
+
+func (m *MockCluster) PutReport(report *share.CLUSReport) error {
+	clone := *report
+	m.reports[report.ID] = &clone
+	return nil
+}
+
+func (m *MockCluster) DeleteReport(id string) error {
+	if _, ok := m.reports[id]; !ok {
+		return common.ErrObjectNotFound
+	}
+	delete(m.reports, id)
+	return nil
+}
+
+func (m *MockCluster) GetAllRiskAcceptances(riskType string) []*share.CLUSRiskAcceptance {
+	list := make([]*share.CLUSRiskAcceptance, 0)
+	for _, ra := range m.riskAcceptances {
+		if ra.Type == riskType {
+			clone := *ra
+			list = append(list, &clone)
+		}
+	}
+	return list
+}
+
+func (m *MockCluster) GetRiskAcceptanceRev(riskType, id string) (*share.CLUSRiskAcceptance, uint64, error) {
+	if ra, ok := m.riskAcceptances[share.CLUSRiskAcceptanceKey(riskType, id)]; ok {
+		clone := *ra
+		return &clone, 0, nil
+	}
+	return nil, 0, common.ErrObjectNotFound
+}
+
+func (m *MockCluster) PutRiskAcceptanceRev(ra *share.CLUSRiskAcceptance, rev uint64) error {
+	clone := *ra
+	m.riskAcceptances[share.CLUSRiskAcceptanceKey(ra.Type, ra.ID)] = &clone
+	return nil
+}
+
+func (m *MockCluster) DeleteRiskAcceptance(riskType, id string) error {
+	key := share.CLUSRiskAcceptanceKey(riskType, id)
+	if _, ok := m.riskAcceptances[key]; !ok {
+		return common.ErrObjectNotFound
+	}
+	delete(m.riskAcceptances, key)
+	return nil
+}
+
+func (m *MockCluster) GetAllPolicyChanges() []*share.CLUSPolicyChange {
+	list := make([]*share.CLUSPolicyChange, 0, len(m.policyChanges))
+	for _, pc := range m.policyChanges {
+		clone := *pc
+		list = append(list, &clone)
+	}
+	return list
+}
+
+func (m *MockCluster) GetPolicyChangeRev(id string) (*share.CLUSPolicyChange, uint64, error) {
+	if pc, ok := m.policyChanges[id]; ok {
+		clone := *pc
+		return &clone, 0, nil
+	}
+	return nil, 0, common.ErrObjectNotFound
+}
+
+func (m *MockCluster) PutPolicyChangeRev(pc *share.CLUSPolicyChange, rev uint64) error {
+	clone := *pc
+	m.policyChanges[pc.ID] = &clone
+	return nil
+}
+
+func (m *MockCluster) PutPolicyChangeTxn(txn *cluster.ClusterTransact, pc *share.CLUSPolicyChange, rev uint64) error {
+	return m.PutPolicyChangeRev(pc, rev)
+}
+
+func (m *MockCluster) DeletePolicyChange(id string) error {
+	if _, ok := m.policyChanges[id]; !ok {
+		return common.ErrObjectNotFound
+	}
+	delete(m.policyChanges, id)
+	return nil
+}
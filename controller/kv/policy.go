@@ -77,6 +77,11 @@ func DeletePolicyByGroup(name string) int { // return # of deleted policies
 	return DeletePolicyByGroups([]string{name})
 }
 
+// Unlike calling DeletePolicyByGroupTxn() for each group, the policy rule list is written once in the transaction
+func DeletePolicyByGroupsTxn(txn *cluster.ClusterTransact, names []string) int {
+	return deletePolicyByGroupsTxn(txn, names)
+}
+
 func DeletePolicyByGroupTxn(txn *cluster.ClusterTransact, name string) error {
 	deletePolicyByGroupsTxn(txn, []string{name})
 
@@ -107,15 +112,22 @@ func DeletePolicyByCfgTypeTxn(txn *cluster.ClusterTransact, cfgType share.TCfgTy
 	writePolicyToKvClusterTxn(txn, dels, keeps)
 }
 
-func deleteResponseRuleByGroupTxn(txn *cluster.ClusterTransact, name string, cfgType *share.TCfgType) int {
-	isFedGroup := strings.HasPrefix(name, api.FederalGroupPrefix)
-	if cfgType != nil && *cfgType == share.FederalCfg && name == api.LearnedExternal {
-		isFedGroup = true
-	}
+func deleteResponseRuleByGroupsTxn(txn *cluster.ClusterTransact, names []string, cfgType *share.TCfgType) int {
 	delCount := 0
 	policyNames := []string{share.DefaultPolicyName, share.FedPolicyName}
 	for _, policyName := range policyNames {
-		if (isFedGroup && policyName != share.FedPolicyName) || (!isFedGroup && policyName == share.FedPolicyName) {
+		isFedPolicy := policyName == share.FedPolicyName
+		groups := utils.NewSet()
+		for _, name := range names {
+			if strings.HasPrefix(name, api.FederalGroupPrefix) || (cfgType != nil && *cfgType == share.FederalCfg && name == api.LearnedExternal) {
+				if isFedPolicy {
+					groups.Add(name)
+				}
+			} else if !isFedPolicy {
+				groups.Add(name)
+			}
+		}
+		if groups.Cardinality() == 0 {
 			continue
 		}
 		dels := utils.NewSet()
@@ -126,9 +138,9 @@ func deleteResponseRuleByGroupTxn(txn *cluster.ClusterTransact, name string, cfg
 			if crh.ID > api.StartingFedAdmRespRuleID {
 				rhCfgType = share.FederalCfg
 			}
-			if (isFedGroup && rhCfgType == share.FederalCfg) || (!isFedGroup && rhCfgType != share.FederalCfg) {
+			if (isFedPolicy && rhCfgType == share.FederalCfg) || (!isFedPolicy && rhCfgType != share.FederalCfg) {
 				if r, _ := clusHelper.GetResponseRule(policyName, crh.ID); r != nil {
-					if r.Group == name {
+					if groups.Contains(r.Group) {
 						// To be deleted
 						dels.Add(crh.ID)
 					} else {
@@ -152,6 +164,10 @@ func deleteResponseRuleByGroupTxn(txn *cluster.ClusterTransact, name string, cfg
 	return delCount
 }
 
+func deleteResponseRuleByGroupTxn(txn *cluster.ClusterTransact, name string, cfgType *share.TCfgType) int {
+	return deleteResponseRuleByGroupsTxn(txn, []string{name}, cfgType)
+}
+
 func DeleteResponseRuleByGroup(name string) int {
 	txn := cluster.Transact()
 	defer txn.Close()
@@ -172,3 +188,8 @@ func DeleteResponseRuleByGroupTxn(txn *cluster.ClusterTransact, name string, cfg
 
 	return nil
 }
+
+// Unlike calling DeleteResponseRuleByGroupTxn() for each group, the response rule list is written once in the transaction
+func DeleteResponseRuleByGroupsTxn(txn *cluster.ClusterTransact, names []string) int {
+	return deleteResponseRuleByGroupsTxn(txn, names, nil)
+}
@@ -88,10 +88,32 @@ func upgradeSystemConfig(cfg *share.CLUSSystemConfig) (bool, bool) {
 		cfg.SyslogCategories = cats
 		upd = true
 	}
+	if upgradeWebhookHeaders(cfg.Webhooks) {
+		upd = true
+	}
 
 	return upd, upd
 }
 
+// header values were kept in plain text before they were cloaked
+func upgradeWebhookHeaders(webhooks []share.CLUSWebhook) bool {
+	var upd bool
+	for i := range webhooks {
+		for j, h := range webhooks[i].Headers {
+			if !h.Cloaked {
+				value, err := common.EncryptCloaked(h.Value)
+				if err != nil {
+					continue // kept in plain text until it can be encrypted
+				}
+				webhooks[i].Headers[j].Value = value
+				webhooks[i].Headers[j].Cloaked = true
+				upd = true
+			}
+		}
+	}
+	return upd
+}
+
 func upgradeServer(server *share.CLUSServer) (bool, bool) {
 	var upd bool
 	if server.LDAP != nil && server.LDAP.Type == "" {
@@ -397,7 +419,10 @@ func upgradeAdmissionCert(value []byte) (*share.CLUSAdmissionCertCloaked, bool,
 			CertNew:   string(certOld.Cert),
 			Cloaked:   true,
 		}
-		valueNew, _ := enc.Marshal(&certNew) // valueNew is byte slice of cloaked object
+		valueNew, err := enc.Marshal(&certNew) // valueNew is byte slice of cloaked object
+		if err != nil {
+			return nil, false, false
+		}
 		// we need to do json.Unmarshal here so the return of doUpgrade() can be json.Marshal and wtitten to kv later
 		var cert share.CLUSAdmissionCertCloaked
 		json.Unmarshal(valueNew, &cert)
@@ -427,6 +452,14 @@ func doUpgrade(key string, value []byte) (interface{}, bool) {
 			if upd, wrt := upgradeSystemConfig(&cfg); upd {
 				return &cfg, wrt
 			}
+		case share.CFGEndpointFederation:
+			if key == share.CLUSFedKey(share.CFGEndpointSystem) {
+				var cfg share.CLUSSystemConfig
+				json.Unmarshal(value, &cfg)
+				if upgradeWebhookHeaders(cfg.Webhooks) {
+					return &cfg, true
+				}
+			}
 		case share.CFGEndpointServer:
 			var cfg share.CLUSServer
 			json.Unmarshal(value, &cfg)
@@ -633,6 +666,15 @@ func UpgradeAndConvert(key string, value []byte) ([]byte, error, bool) {
 				v = &cfg
 			}
 			dec.Uncloak(v)
+		case share.CFGEndpointFederation:
+			if key == share.CLUSFedKey(share.CFGEndpointSystem) {
+				if v == nil {
+					var cfg share.CLUSSystemConfig
+					json.Unmarshal(value, &cfg)
+					v = &cfg
+				}
+				dec.Uncloak(v)
+			}
 		case share.CFGEndpointServer:
 			if v == nil {
 				var cfg share.CLUSServer
@@ -0,0 +1,262 @@
+package logstore
+
+// Persistent log store of the controller. Logs of each type are appended to daily segment files, one json
+// log per line, so the logs can be reloaded after the controller restarts and the logs older than the
+// retention are removed by deleting whole segments.
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const segmentTimeFormat = "20060102"
+const segmentSuffix = ".log"
+const maxLogLineSize = 1024 * 1024 * 4
+
+type Store interface {
+	// Put appends a log of the type, data is the log in json
+	Put(logType string, at time.Time, data []byte) error
+	// List returns the last limit logs between start and end, oldest first. 0 limit means no limit.
+	List(logType string, start, end time.Time, limit int) ([][]byte, error)
+	// Prune removes the logs before the time, return the number of removed segments
+	Prune(logType string, before time.Time) (int, error)
+	Close() error
+}
+
+type segmentFile struct {
+	name string
+	file *os.File
+}
+
+type fileStore struct {
+	mutex sync.Mutex
+	dir   string
+	files map[string]*segmentFile // key is log type
+}
+
+func NewFileStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &fileStore{dir: dir, files: make(map[string]*segmentFile)}, nil
+}
+
+func segmentName(at time.Time) string {
+	return at.UTC().Format(segmentTimeFormat) + segmentSuffix
+}
+
+func segmentTime(name string) (time.Time, bool) {
+	if !strings.HasSuffix(name, segmentSuffix) {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(segmentTimeFormat, strings.TrimSuffix(name, segmentSuffix))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// Return the segments of the log type, oldest first
+func (s *fileStore) segments(logType string) ([]string, error) {
+	files, err := ioutil.ReadDir(filepath.Join(s.dir, logType))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		if _, ok := segmentTime(f.Name()); ok && !f.IsDir() {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *fileStore) Put(logType string, at time.Time, data []byte) error {
+	if bytes.IndexByte(data, '\n') >= 0 {
+		return fmt.Errorf("Invalid log data")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	name := segmentName(at)
+	sf, ok := s.files[logType]
+	if !ok || sf.name != name {
+		if ok {
+			sf.file.Close()
+			delete(s.files, logType)
+		}
+
+		dir := filepath.Join(s.dir, logType)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		sf = &segmentFile{name: name, file: f}
+		s.files[logType] = sf
+	}
+
+	line := make([]byte, 0, len(data)+24)
+	line = strconv.AppendInt(line, at.UnixNano(), 10)
+	line = append(line, '\t')
+	line = append(line, data...)
+	line = append(line, '\n')
+	_, err := sf.file.Write(line)
+	return err
+}
+
+func (s *fileStore) readSegment(logType, name string, start, end time.Time, cb func(data []byte)) error {
+	f, err := os.Open(filepath.Join(s.dir, logType, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		i := bytes.IndexByte(line, '\t')
+		if i <= 0 {
+			continue
+		}
+		// The last line can be partial if the controller was stopped in writing
+		ts, err := strconv.ParseInt(string(line[:i]), 10, 64)
+		if err != nil {
+			continue
+		}
+		at := time.Unix(0, ts)
+		if (!start.IsZero() && at.Before(start)) || (!end.IsZero() && at.After(end)) {
+			continue
+		}
+		data := make([]byte, len(line)-i-1)
+		copy(data, line[i+1:])
+		cb(data)
+	}
+	return scanner.Err()
+}
+
+func (s *fileStore) List(logType string, start, end time.Time, limit int) ([][]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	names, err := s.segments(logType)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := make([][]byte, 0)
+	for _, name := range names {
+		t, _ := segmentTime(name)
+		if (!start.IsZero() && t.Add(time.Hour*24).Before(start)) || (!end.IsZero() && t.After(end)) {
+			continue
+		}
+		err := s.readSegment(logType, name, start, end, func(data []byte) {
+			logs = append(logs, data)
+			if limit > 0 && len(logs) > limit*2 {
+				logs = append(logs[:0], logs[len(logs)-limit:]...)
+			}
+		})
+		if err != nil {
+			log.WithFields(log.Fields{"type": logType, "segment": name, "error": err}).Error("Failed to read logs")
+		}
+	}
+	if limit > 0 && len(logs) > limit {
+		logs = logs[len(logs)-limit:]
+	}
+	return logs, nil
+}
+
+// A segment is removed only if all its logs are before the time
+func (s *fileStore) Prune(logType string, before time.Time) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	names, err := s.segments(logType)
+	if err != nil {
+		return 0, err
+	}
+
+	var pruned int
+	for _, name := range names {
+		t, _ := segmentTime(name)
+		if t.Add(time.Hour * 24).After(before) {
+			break
+		}
+		if sf, ok := s.files[logType]; ok && sf.name == name {
+			sf.file.Close()
+			delete(s.files, logType)
+		}
+		if err := os.Remove(filepath.Join(s.dir, logType, name)); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+func (s *fileStore) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for logType, sf := range s.files {
+		sf.file.Close()
+		delete(s.files, logType)
+	}
+	return nil
+}
+
+// ParseRetention parses the retention of each log type, such as "event=30d,threat=2160h".
+func ParseRetention(value string) (map[string]time.Duration, error) {
+	retention := make(map[string]time.Duration)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("Invalid retention: %s", item)
+		}
+		d, err := parseDuration(kv[1])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid retention: %s", item)
+		}
+		retention[strings.TrimSpace(kv[0])] = d
+	}
+	return retention, nil
+}
+
+// Support days besides the units of time.ParseDuration
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.ParseUint(strings.TrimSuffix(value, "d"), 10, 32)
+		if err != nil || days == 0 {
+			return 0, fmt.Errorf("Invalid duration")
+		}
+		return time.Hour * 24 * time.Duration(days), nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("Invalid duration")
+	}
+	return d, nil
+}
@@ -0,0 +1,105 @@
+package logstore
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestPutList(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "logstore")
+	defer os.RemoveAll(dir)
+
+	s, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer s.Close()
+
+	base := time.Date(2020, 1, 1, 23, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		// 2 hours apart, so the logs are in multiple segments
+		if err := s.Put("event", base.Add(time.Hour*2*time.Duration(i)), []byte(fmt.Sprintf(`{"i":%d}`, i))); err != nil {
+			t.Fatalf("Failed to put log: %v", err)
+		}
+	}
+	if err := s.Put("event", base, []byte("a\nb")); err == nil {
+		t.Errorf("Log with newline should be rejected")
+	}
+
+	logs, _ := s.List("event", time.Time{}, time.Time{}, 0)
+	if len(logs) != 10 || string(logs[0]) != `{"i":0}` || string(logs[9]) != `{"i":9}` {
+		t.Errorf("Unexpected logs: %d", len(logs))
+	}
+
+	logs, _ = s.List("event", time.Time{}, time.Time{}, 3)
+	if len(logs) != 3 || string(logs[0]) != `{"i":7}` || string(logs[2]) != `{"i":9}` {
+		t.Errorf("Unexpected limited logs: %d", len(logs))
+	}
+
+	logs, _ = s.List("event", base.Add(time.Hour*2), base.Add(time.Hour*6), 0)
+	if len(logs) != 3 || string(logs[0]) != `{"i":1}` || string(logs[2]) != `{"i":3}` {
+		t.Errorf("Unexpected logs in range: %d", len(logs))
+	}
+
+	logs, _ = s.List("threat", time.Time{}, time.Time{}, 0)
+	if len(logs) != 0 {
+		t.Errorf("Unexpected logs of another type: %d", len(logs))
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "logstore")
+	defer os.RemoveAll(dir)
+
+	s, _ := NewFileStore(dir)
+	defer s.Close()
+
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		s.Put("audit", base.Add(time.Hour*24*time.Duration(i)), []byte(fmt.Sprintf(`{"i":%d}`, i)))
+	}
+
+	// The segment of the 3rd day has logs after the time, it is kept
+	pruned, err := s.Prune("audit", base.Add(time.Hour*24*2))
+	if err != nil || pruned != 2 {
+		t.Errorf("Unexpected prune: pruned=%d error=%v", pruned, err)
+	}
+	logs, _ := s.List("audit", time.Time{}, time.Time{}, 0)
+	if len(logs) != 3 || string(logs[0]) != `{"i":2}` {
+		t.Errorf("Unexpected logs after prune: %d", len(logs))
+	}
+
+	// The current segment can be written after it's pruned
+	pruned, _ = s.Prune("audit", base.Add(time.Hour*24*10))
+	if pruned != 3 {
+		t.Errorf("Unexpected prune: pruned=%d", pruned)
+	}
+	s.Put("audit", base.Add(time.Hour*24*4), []byte(`{"i":4}`))
+	logs, _ = s.List("audit", time.Time{}, time.Time{}, 0)
+	if len(logs) != 1 {
+		t.Errorf("Unexpected logs after write: %d", len(logs))
+	}
+}
+
+func TestParseRetention(t *testing.T) {
+	r, err := ParseRetention("event=30d, threat=2160h,default=1d")
+	if err != nil {
+		t.Fatalf("Failed to parse retention: %v", err)
+	}
+	if r["event"] != time.Hour*24*30 || r["threat"] != time.Hour*2160 || r["default"] != time.Hour*24 {
+		t.Errorf("Unexpected retention: %+v", r)
+	}
+
+	if r, err = ParseRetention(""); err != nil || len(r) != 0 {
+		t.Errorf("Unexpected empty retention: %+v %v", r, err)
+	}
+
+	for _, v := range []string{"event", "event=", "=30d", "event=0d", "event=-1h", "event=abc"} {
+		if _, err := ParseRetention(v); err == nil {
+			t.Errorf("Invalid retention should fail: %s", v)
+		}
+	}
+}
@@ -24,6 +24,9 @@ import (
 	"github.com/neuvector/neuvector/share/utils"
 )
 
+// crds whose status is updated through the status subresource by controller
+var crdStatusNames utils.Set = utils.NewSet(resource.NvSecurityRuleName, resource.NvClusterSecurityRuleName, resource.NvWafSecurityRuleName)
+
 type nvCrdSchmaBuilder struct {
 	schemaTypeArray   string
 	schemaTypeBoolean string
@@ -48,6 +51,8 @@ func (b *nvCrdSchmaBuilder) Init() {
 		share.FileAccessBehaviorMonitor, share.FileAccessBehaviorBlock,
 		share.DlpPatternContextURI, share.DlpPatternContextHEAD, share.DlpPatternContextBODY, share.DlpPatternContextPACKET,
 		share.CriteriaOpRegex, share.CriteriaOpNotRegex, share.DlpRuleKeyPattern,
+		resource.NvCrdConditionAccepted, resource.NvCrdConditionSynced, resource.NvCrdConditionError,
+		resource.NvCrdConditionTrue, resource.NvCrdConditionFalse,
 	}
 	b.enumMap = make(map[string][]byte, len(enums))
 	for _, k := range enums {
@@ -569,7 +574,7 @@ func (b *nvCrdSchmaBuilder) buildNvSecurityCrdAdmCtrlV1Schema() *apiextv1.JSONSc
 										Items: &apiextv1.JSONSchemaPropsOrArray{
 											Schema: &apiextv1.JSONSchemaProps{
 												Type:     &b.schemaTypeObject,
-												Required: []string{"name", "op", "value"},
+												Required: []string{"name", "op"}, // no value for exist/notExist ops of custom criteria
 												Properties: map[string]*apiextv1.JSONSchemaProps{
 													"name": &apiextv1.JSONSchemaProps{
 														Type: &b.schemaTypeString,
@@ -695,7 +700,7 @@ func (b *nvCrdSchmaBuilder) buildNvSecurityCrdAdmCtrlV1B1Schema() *apiextv1b1.JS
 										Items: &apiextv1b1.JSONSchemaPropsOrArray{
 											Schema: &apiextv1b1.JSONSchemaProps{
 												Type:     &b.schemaTypeObject,
-												Required: []string{"name", "op", "value"},
+												Required: []string{"name", "op"}, // no value for exist/notExist ops of custom criteria
 												Properties: map[string]*apiextv1b1.JSONSchemaProps{
 													"name": &apiextv1b1.JSONSchemaProps{
 														Type: &b.schemaTypeString,
@@ -761,6 +766,27 @@ func (b *nvCrdSchmaBuilder) buildNvSecurityCrdDlpWafV1Schema() *apiextv1.JSONSch
 				Type:     &b.schemaTypeObject,
 				Required: []string{"sensor"},
 				Properties: map[string]*apiextv1.JSONSchemaProps{
+					"groups": &apiextv1.JSONSchemaProps{
+						Type: &b.schemaTypeArray,
+						Items: &apiextv1.JSONSchemaPropsOrArray{
+							Schema: &apiextv1.JSONSchemaProps{
+								Type:     &b.schemaTypeObject,
+								Required: []string{"name", "action"},
+								Properties: map[string]*apiextv1.JSONSchemaProps{
+									"name": &apiextv1.JSONSchemaProps{
+										Type: &b.schemaTypeString,
+									},
+									"action": &apiextv1.JSONSchemaProps{
+										Type: &b.schemaTypeString,
+										Enum: []*apiextv1.JSON{
+											&apiextv1.JSON{Raw: b.enumMap[share.PolicyActionAllow]},
+											&apiextv1.JSON{Raw: b.enumMap[share.PolicyActionDeny]},
+										},
+									},
+								},
+							},
+						},
+					},
 					"sensor": &apiextv1.JSONSchemaProps{
 						Type:     &b.schemaTypeObject,
 						Required: []string{"name"},
@@ -858,6 +884,27 @@ func (b *nvCrdSchmaBuilder) buildNvSecurityCrdDlpWafV1B1Schema() *apiextv1b1.JSO
 				Type:     &b.schemaTypeObject,
 				Required: []string{"sensor"},
 				Properties: map[string]*apiextv1b1.JSONSchemaProps{
+					"groups": &apiextv1b1.JSONSchemaProps{
+						Type: &b.schemaTypeArray,
+						Items: &apiextv1b1.JSONSchemaPropsOrArray{
+							Schema: &apiextv1b1.JSONSchemaProps{
+								Type:     &b.schemaTypeObject,
+								Required: []string{"name", "action"},
+								Properties: map[string]*apiextv1b1.JSONSchemaProps{
+									"name": &apiextv1b1.JSONSchemaProps{
+										Type: &b.schemaTypeString,
+									},
+									"action": &apiextv1b1.JSONSchemaProps{
+										Type: &b.schemaTypeString,
+										Enum: []*apiextv1b1.JSON{
+											&apiextv1b1.JSON{Raw: b.enumMap[share.PolicyActionAllow]},
+											&apiextv1b1.JSON{Raw: b.enumMap[share.PolicyActionDeny]},
+										},
+									},
+								},
+							},
+						},
+					},
 					"sensor": &apiextv1b1.JSONSchemaProps{
 						Type:     &b.schemaTypeObject,
 						Required: []string{"name"},
@@ -946,6 +993,284 @@ func (b *nvCrdSchmaBuilder) buildNvCspUsageV1B1Schema() *apiextv1b1.JSONSchemaPr
 	return schema
 }
 
+func (b *nvCrdSchmaBuilder) buildNvSecurityCrdVulnProfileV1Schema() *apiextv1.JSONSchemaProps {
+	schema := &apiextv1.JSONSchemaProps{
+		Type: &b.schemaTypeObject,
+		Properties: map[string]*apiextv1.JSONSchemaProps{
+			"spec": &apiextv1.JSONSchemaProps{
+				Type:     &b.schemaTypeObject,
+				Required: []string{"entries"},
+				Properties: map[string]*apiextv1.JSONSchemaProps{
+					"entries": &apiextv1.JSONSchemaProps{
+						Type: &b.schemaTypeArray,
+						Items: &apiextv1.JSONSchemaPropsOrArray{
+							Schema: &apiextv1.JSONSchemaProps{
+								Type:     &b.schemaTypeObject,
+								Required: []string{"name"},
+								Properties: map[string]*apiextv1.JSONSchemaProps{
+									"name": &apiextv1.JSONSchemaProps{
+										Type: &b.schemaTypeString,
+									},
+									"comment": &apiextv1.JSONSchemaProps{
+										Type: &b.schemaTypeString,
+									},
+									"days": &apiextv1.JSONSchemaProps{
+										Type: &b.schemaTypeInteger,
+									},
+									"domains": &apiextv1.JSONSchemaProps{
+										Type: &b.schemaTypeArray,
+										Items: &apiextv1.JSONSchemaPropsOrArray{
+											Schema: &apiextv1.JSONSchemaProps{
+												Type: &b.schemaTypeString,
+											},
+										},
+									},
+									"images": &apiextv1.JSONSchemaProps{
+										Type: &b.schemaTypeArray,
+										Items: &apiextv1.JSONSchemaPropsOrArray{
+											Schema: &apiextv1.JSONSchemaProps{
+												Type: &b.schemaTypeString,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return schema
+}
+
+func (b *nvCrdSchmaBuilder) buildNvSecurityCrdCompProfileV1Schema() *apiextv1.JSONSchemaProps {
+	schema := &apiextv1.JSONSchemaProps{
+		Type: &b.schemaTypeObject,
+		Properties: map[string]*apiextv1.JSONSchemaProps{
+			"spec": &apiextv1.JSONSchemaProps{
+				Type:     &b.schemaTypeObject,
+				Required: []string{"entries"},
+				Properties: map[string]*apiextv1.JSONSchemaProps{
+					"disable_system": &apiextv1.JSONSchemaProps{
+						Type: &b.schemaTypeBoolean,
+					},
+					"entries": &apiextv1.JSONSchemaProps{
+						Type: &b.schemaTypeArray,
+						Items: &apiextv1.JSONSchemaPropsOrArray{
+							Schema: &apiextv1.JSONSchemaProps{
+								Type:     &b.schemaTypeObject,
+								Required: []string{"test_number", "tags"},
+								Properties: map[string]*apiextv1.JSONSchemaProps{
+									"test_number": &apiextv1.JSONSchemaProps{
+										Type: &b.schemaTypeString,
+									},
+									"tags": &apiextv1.JSONSchemaProps{
+										Type: &b.schemaTypeArray,
+										Items: &apiextv1.JSONSchemaPropsOrArray{
+											Schema: &apiextv1.JSONSchemaProps{
+												Type: &b.schemaTypeString,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return schema
+}
+
+func (b *nvCrdSchmaBuilder) buildNvSecurityCrdVulnProfileV1B1Schema() *apiextv1b1.JSONSchemaProps {
+	schema := &apiextv1b1.JSONSchemaProps{
+		Type: &b.schemaTypeObject,
+		Properties: map[string]*apiextv1b1.JSONSchemaProps{
+			"spec": &apiextv1b1.JSONSchemaProps{
+				Type:     &b.schemaTypeObject,
+				Required: []string{"entries"},
+				Properties: map[string]*apiextv1b1.JSONSchemaProps{
+					"entries": &apiextv1b1.JSONSchemaProps{
+						Type: &b.schemaTypeArray,
+						Items: &apiextv1b1.JSONSchemaPropsOrArray{
+							Schema: &apiextv1b1.JSONSchemaProps{
+								Type:     &b.schemaTypeObject,
+								Required: []string{"name"},
+								Properties: map[string]*apiextv1b1.JSONSchemaProps{
+									"name": &apiextv1b1.JSONSchemaProps{
+										Type: &b.schemaTypeString,
+									},
+									"comment": &apiextv1b1.JSONSchemaProps{
+										Type: &b.schemaTypeString,
+									},
+									"days": &apiextv1b1.JSONSchemaProps{
+										Type: &b.schemaTypeInteger,
+									},
+									"domains": &apiextv1b1.JSONSchemaProps{
+										Type: &b.schemaTypeArray,
+										Items: &apiextv1b1.JSONSchemaPropsOrArray{
+											Schema: &apiextv1b1.JSONSchemaProps{
+												Type: &b.schemaTypeString,
+											},
+										},
+									},
+									"images": &apiextv1b1.JSONSchemaProps{
+										Type: &b.schemaTypeArray,
+										Items: &apiextv1b1.JSONSchemaPropsOrArray{
+											Schema: &apiextv1b1.JSONSchemaProps{
+												Type: &b.schemaTypeString,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return schema
+}
+
+func (b *nvCrdSchmaBuilder) buildNvSecurityCrdCompProfileV1B1Schema() *apiextv1b1.JSONSchemaProps {
+	schema := &apiextv1b1.JSONSchemaProps{
+		Type: &b.schemaTypeObject,
+		Properties: map[string]*apiextv1b1.JSONSchemaProps{
+			"spec": &apiextv1b1.JSONSchemaProps{
+				Type:     &b.schemaTypeObject,
+				Required: []string{"entries"},
+				Properties: map[string]*apiextv1b1.JSONSchemaProps{
+					"disable_system": &apiextv1b1.JSONSchemaProps{
+						Type: &b.schemaTypeBoolean,
+					},
+					"entries": &apiextv1b1.JSONSchemaProps{
+						Type: &b.schemaTypeArray,
+						Items: &apiextv1b1.JSONSchemaPropsOrArray{
+							Schema: &apiextv1b1.JSONSchemaProps{
+								Type:     &b.schemaTypeObject,
+								Required: []string{"test_number", "tags"},
+								Properties: map[string]*apiextv1b1.JSONSchemaProps{
+									"test_number": &apiextv1b1.JSONSchemaProps{
+										Type: &b.schemaTypeString,
+									},
+									"tags": &apiextv1b1.JSONSchemaProps{
+										Type: &b.schemaTypeArray,
+										Items: &apiextv1b1.JSONSchemaPropsOrArray{
+											Schema: &apiextv1b1.JSONSchemaProps{
+												Type: &b.schemaTypeString,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return schema
+}
+
+// status subresource of NvSecurityRule/NvClusterSecurityRule/NvWafSecurityRule that is updated by controller
+func (b *nvCrdSchmaBuilder) buildNvSecurityCrdRuleStatusV1Schema() *apiextv1.JSONSchemaProps {
+	schema := &apiextv1.JSONSchemaProps{
+		Type: &b.schemaTypeObject,
+		Properties: map[string]*apiextv1.JSONSchemaProps{
+			"observedGeneration": &apiextv1.JSONSchemaProps{
+				Type: &b.schemaTypeInteger,
+			},
+			"conditions": &apiextv1.JSONSchemaProps{
+				Type: &b.schemaTypeArray,
+				Items: &apiextv1.JSONSchemaPropsOrArray{
+					Schema: &apiextv1.JSONSchemaProps{
+						Type:     &b.schemaTypeObject,
+						Required: []string{"type", "status"},
+						Properties: map[string]*apiextv1.JSONSchemaProps{
+							"type": &apiextv1.JSONSchemaProps{
+								Type: &b.schemaTypeString,
+								Enum: []*apiextv1.JSON{
+									&apiextv1.JSON{Raw: b.enumMap[resource.NvCrdConditionAccepted]},
+									&apiextv1.JSON{Raw: b.enumMap[resource.NvCrdConditionSynced]},
+									&apiextv1.JSON{Raw: b.enumMap[resource.NvCrdConditionError]},
+								},
+							},
+							"status": &apiextv1.JSONSchemaProps{
+								Type: &b.schemaTypeString,
+								Enum: []*apiextv1.JSON{
+									&apiextv1.JSON{Raw: b.enumMap[resource.NvCrdConditionTrue]},
+									&apiextv1.JSON{Raw: b.enumMap[resource.NvCrdConditionFalse]},
+								},
+							},
+							"reason": &apiextv1.JSONSchemaProps{
+								Type: &b.schemaTypeString,
+							},
+							"message": &apiextv1.JSONSchemaProps{
+								Type: &b.schemaTypeString,
+							},
+							"lastTransitionTime": &apiextv1.JSONSchemaProps{
+								Type: &b.schemaTypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return schema
+}
+
+// status subresource of NvSecurityRule/NvClusterSecurityRule/NvWafSecurityRule that is updated by controller
+func (b *nvCrdSchmaBuilder) buildNvSecurityCrdRuleStatusV1B1Schema() *apiextv1b1.JSONSchemaProps {
+	schema := &apiextv1b1.JSONSchemaProps{
+		Type: &b.schemaTypeObject,
+		Properties: map[string]*apiextv1b1.JSONSchemaProps{
+			"observedGeneration": &apiextv1b1.JSONSchemaProps{
+				Type: &b.schemaTypeInteger,
+			},
+			"conditions": &apiextv1b1.JSONSchemaProps{
+				Type: &b.schemaTypeArray,
+				Items: &apiextv1b1.JSONSchemaPropsOrArray{
+					Schema: &apiextv1b1.JSONSchemaProps{
+						Type:     &b.schemaTypeObject,
+						Required: []string{"type", "status"},
+						Properties: map[string]*apiextv1b1.JSONSchemaProps{
+							"type": &apiextv1b1.JSONSchemaProps{
+								Type: &b.schemaTypeString,
+								Enum: []*apiextv1b1.JSON{
+									&apiextv1b1.JSON{Raw: b.enumMap[resource.NvCrdConditionAccepted]},
+									&apiextv1b1.JSON{Raw: b.enumMap[resource.NvCrdConditionSynced]},
+									&apiextv1b1.JSON{Raw: b.enumMap[resource.NvCrdConditionError]},
+								},
+							},
+							"status": &apiextv1b1.JSONSchemaProps{
+								Type: &b.schemaTypeString,
+								Enum: []*apiextv1b1.JSON{
+									&apiextv1b1.JSON{Raw: b.enumMap[resource.NvCrdConditionTrue]},
+									&apiextv1b1.JSON{Raw: b.enumMap[resource.NvCrdConditionFalse]},
+								},
+							},
+							"reason": &apiextv1b1.JSONSchemaProps{
+								Type: &b.schemaTypeString,
+							},
+							"message": &apiextv1b1.JSONSchemaProps{
+								Type: &b.schemaTypeString,
+							},
+							"lastTransitionTime": &apiextv1b1.JSONSchemaProps{
+								Type: &b.schemaTypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return schema
+}
+
 func (b *nvCrdSchmaBuilder) buildNvSecurityCrdByApiExtV1(nvCrdMetaName string, version *string) *apiextv1.CustomResourceDefinitionVersion {
 
 	v1 := &apiextv1.CustomResourceDefinitionVersion{
@@ -957,10 +1282,24 @@ func (b *nvCrdSchmaBuilder) buildNvSecurityCrdByApiExtV1(nvCrdMetaName string, v
 	switch nvCrdMetaName {
 	case resource.NvSecurityRuleName, resource.NvClusterSecurityRuleName:
 		v1.Schema.OpenAPIV3Schema = b.buildNvSeurityCrdNwPolicyV1Schema()
+		v1.Schema.OpenAPIV3Schema.Properties["status"] = b.buildNvSecurityCrdRuleStatusV1Schema()
+		v1.Subresources = &apiextv1.CustomResourceSubresources{
+			Status: &apiextv1.CustomResourceSubresourceStatus{},
+		}
 	case resource.NvAdmCtrlSecurityRuleName:
 		v1.Schema.OpenAPIV3Schema = b.buildNvSecurityCrdAdmCtrlV1Schema()
-	case resource.NvDlpSecurityRuleName, resource.NvWafSecurityRuleName:
+	case resource.NvDlpSecurityRuleName:
+		v1.Schema.OpenAPIV3Schema = b.buildNvSecurityCrdDlpWafV1Schema()
+	case resource.NvWafSecurityRuleName:
 		v1.Schema.OpenAPIV3Schema = b.buildNvSecurityCrdDlpWafV1Schema()
+		v1.Schema.OpenAPIV3Schema.Properties["status"] = b.buildNvSecurityCrdRuleStatusV1Schema()
+		v1.Subresources = &apiextv1.CustomResourceSubresources{
+			Status: &apiextv1.CustomResourceSubresourceStatus{},
+		}
+	case resource.NvVulnProfileName:
+		v1.Schema.OpenAPIV3Schema = b.buildNvSecurityCrdVulnProfileV1Schema()
+	case resource.NvCompProfileName:
+		v1.Schema.OpenAPIV3Schema = b.buildNvSecurityCrdCompProfileV1Schema()
 	case resource.NvCspUsageName:
 		v1.Schema.OpenAPIV3Schema = b.buildNvCspUsageV1Schema()
 	}
@@ -978,10 +1317,24 @@ func (b *nvCrdSchmaBuilder) buildNvSecurityCrdByApiExtV1B1(nvCrdMetaName string,
 	switch nvCrdMetaName {
 	case resource.NvSecurityRuleName, resource.NvClusterSecurityRuleName:
 		v1.Schema.OpenAPIV3Schema = b.buildNvSeurityCrdNwPolicyV1B1Schema()
+		v1.Schema.OpenAPIV3Schema.Properties["status"] = b.buildNvSecurityCrdRuleStatusV1B1Schema()
+		v1.Subresources = &apiextv1b1.CustomResourceSubresources{
+			Status: &apiextv1b1.CustomResourceSubresourceStatus{},
+		}
 	case resource.NvAdmCtrlSecurityRuleName:
 		v1.Schema.OpenAPIV3Schema = b.buildNvSecurityCrdAdmCtrlV1B1Schema()
-	case resource.NvDlpSecurityRuleName, resource.NvWafSecurityRuleName:
+	case resource.NvDlpSecurityRuleName:
 		v1.Schema.OpenAPIV3Schema = b.buildNvSecurityCrdDlpWafV1B1Schema()
+	case resource.NvWafSecurityRuleName:
+		v1.Schema.OpenAPIV3Schema = b.buildNvSecurityCrdDlpWafV1B1Schema()
+		v1.Schema.OpenAPIV3Schema.Properties["status"] = b.buildNvSecurityCrdRuleStatusV1B1Schema()
+		v1.Subresources = &apiextv1b1.CustomResourceSubresources{
+			Status: &apiextv1b1.CustomResourceSubresourceStatus{},
+		}
+	case resource.NvVulnProfileName:
+		v1.Schema.OpenAPIV3Schema = b.buildNvSecurityCrdVulnProfileV1B1Schema()
+	case resource.NvCompProfileName:
+		v1.Schema.OpenAPIV3Schema = b.buildNvSecurityCrdCompProfileV1B1Schema()
 	case resource.NvCspUsageName:
 		v1.Schema.OpenAPIV3Schema = b.buildNvCspUsageV1B1Schema()
 	}
@@ -1016,6 +1369,7 @@ func createK8sCrdSchema(crdInfo *resource.NvCrdInfo) error {
 		}
 		v := builder.buildNvSecurityCrdByApiExtV1B1(crdInfo.MetaName, &crdInfo.SpecVersion)
 		res.Spec.Validation = v.Schema
+		res.Spec.Subresources = v.Subresources
 		err = global.ORCH.AddResource(resource.RscTypeCrd, res)
 	} else {
 		res := &apiextv1.CustomResourceDefinition{
@@ -1039,12 +1393,118 @@ func createK8sCrdSchema(crdInfo *resource.NvCrdInfo) error {
 		}
 		v := builder.buildNvSecurityCrdByApiExtV1(crdInfo.MetaName, &crdInfo.SpecVersion)
 		res.Spec.Versions = append(res.Spec.Versions, v)
+		// older versions are still served & converted to the storage version by the crd webhook server
+		if conversion, oldVersions := rest.GetCrdConversion(crdInfo.MetaName); conversion != nil {
+			for _, oldVersion := range oldVersions {
+				ver := oldVersion
+				vOld := builder.buildNvSecurityCrdByApiExtV1(crdInfo.MetaName, &ver)
+				vOld.Storage = func() *bool { b := false; return &b }()
+				res.Spec.Versions = append(res.Spec.Versions, vOld)
+			}
+			res.Spec.Conversion = conversion
+		}
 		err = global.ORCH.AddResource(resource.RscTypeCrd, res)
 	}
 
 	return err
 }
 
+func hasCrdSubresourcesV1(expected, cur *apiextv1.CustomResourceDefinitionVersion) bool {
+	if expected.Subresources != nil && expected.Subresources.Status != nil {
+		return cur.Subresources != nil && cur.Subresources.Status != nil
+	}
+	return true
+}
+
+func hasCrdServedVersionsV1(crdMetaName string, crdSpec *apiextv1.CustomResourceDefinitionSpec) bool {
+	conversion, oldVersions := rest.GetCrdConversion(crdMetaName)
+	if conversion == nil {
+		return true
+	}
+	for _, oldVersion := range oldVersions {
+		served := false
+		for _, ver := range crdSpec.Versions {
+			if ver.GetName() == oldVersion && ver.GetServed() {
+				served = true
+				break
+			}
+		}
+		if !served {
+			return false
+		}
+	}
+	return true
+}
+
+// only the crds that need the status subresource or older served versions are upgraded in place
+func isCrdUpgradable(crdMetaName string) bool {
+	if crdStatusNames.Contains(crdMetaName) {
+		return true
+	}
+	conversion, _ := rest.GetCrdConversion(crdMetaName)
+	return conversion != nil
+}
+
+// upgrade the schema of an existing CustomResourceDefinition resource. It's updated with the resource version it's read with
+func upgradeK8sCrdSchema(crdInfo *resource.NvCrdInfo) error {
+	obj, err := global.ORCH.GetResource(resource.RscTypeCrd, k8s.AllNamespaces, crdInfo.MetaName)
+	if err != nil {
+		return err
+	}
+
+	var builder nvCrdSchmaBuilder
+	builder.Init()
+	switch crdRes := obj.(type) {
+	case *apiextv1.CustomResourceDefinition:
+		if crdRes.Spec == nil {
+			return fmt.Errorf("invalid crd spec")
+		}
+		v := builder.buildNvSecurityCrdByApiExtV1(crdInfo.MetaName, &crdInfo.SpecVersion)
+		versions := []*apiextv1.CustomResourceDefinitionVersion{v}
+		if conversion, oldVersions := rest.GetCrdConversion(crdInfo.MetaName); conversion != nil {
+			for _, oldVersion := range oldVersions {
+				ver := oldVersion
+				vOld := builder.buildNvSecurityCrdByApiExtV1(crdInfo.MetaName, &ver)
+				vOld.Storage = func() *bool { b := false; return &b }()
+				versions = append(versions, vOld)
+			}
+			crdRes.Spec.Conversion = conversion
+		}
+		// other versions could still have stored objects, keep them
+		for _, cur := range crdRes.Spec.Versions {
+			found := false
+			for _, ver := range versions {
+				if cur.GetName() == ver.GetName() {
+					found = true
+					break
+				}
+			}
+			if !found {
+				cur.Storage = func() *bool { b := false; return &b }()
+				versions = append(versions, cur)
+			}
+		}
+		crdRes.Spec.Versions = versions
+	case *apiextv1b1.CustomResourceDefinition:
+		if crdRes.Spec == nil {
+			return fmt.Errorf("invalid crd spec")
+		}
+		v := builder.buildNvSecurityCrdByApiExtV1B1(crdInfo.MetaName, &crdInfo.SpecVersion)
+		if len(crdRes.Spec.Versions) > 0 && crdRes.Spec.Versions[0].Schema != nil {
+			// the schema is defined per version
+			crdRes.Spec.Versions[0].Schema = v.Schema
+			crdRes.Spec.Versions[0].Subresources = v.Subresources
+		} else {
+			crdRes.Spec.Validation = v.Schema
+			crdRes.Spec.Subresources = v.Subresources
+		}
+	default:
+		return fmt.Errorf("unsupported crd type")
+	}
+
+	return global.ORCH.UpdateResource(resource.RscTypeCrd, obj)
+}
+
 func (b *nvCrdSchmaBuilder) compJSONSchemaV1(srcSchema, destSchema *apiextv1.JSONSchemaProps) bool {
 	if srcSchema == nil && destSchema == nil {
 		return true
@@ -1222,7 +1682,8 @@ func isCrdUpToDate(leader bool, crdInfo *resource.NvCrdInfo) (bool, bool, error)
 								builder.Init()
 								if expected := builder.buildNvSecurityCrdByApiExtV1(crdInfo.MetaName, &crdInfo.SpecVersion); expected != nil && expected.Schema != nil {
 									if v0.GetServed() == expected.GetServed() && v0.GetStorage() == expected.GetStorage() && v0.GetName() == expected.GetName() {
-										crdUpToDate = builder.compJSONSchemaV1(expected.Schema.OpenAPIV3Schema, schema.OpenAPIV3Schema)
+										crdUpToDate = builder.compJSONSchemaV1(expected.Schema.OpenAPIV3Schema, schema.OpenAPIV3Schema) &&
+											hasCrdSubresourcesV1(expected, v0) && hasCrdServedVersionsV1(crdInfo.MetaName, crdSpec)
 									}
 								}
 							}
@@ -1246,7 +1707,8 @@ func isCrdUpToDate(leader bool, crdInfo *resource.NvCrdInfo) (bool, bool, error)
 									var builder nvCrdSchmaBuilder
 									builder.Init()
 									if expected := builder.buildNvSecurityCrdByApiExtV1B1(crdInfo.MetaName, &crdInfo.SpecVersion); expected != nil && expected.Schema != nil {
-										crdUpToDate = builder.compJSONSchemaV1B1(expected.Schema.OpenAPIV3Schema, schema.OpenAPIV3Schema)
+										crdUpToDate = builder.compJSONSchemaV1B1(expected.Schema.OpenAPIV3Schema, schema.OpenAPIV3Schema) &&
+											(expected.Subresources == nil || crdSpec.Subresources != nil || v0.Subresources != nil)
 									}
 								}
 							}
@@ -1330,6 +1792,32 @@ func CheckCrdSchema(leader, create bool, cspType share.TCspType) []string {
 			LockKey:           share.CLUSLockPolicyKey,
 			KvCrdKind:         resource.NvWafSecurityRuleKind,
 		},
+		&resource.NvCrdInfo{
+			RscType:           resource.RscTypeCrdVulnProfile,
+			MetaName:          resource.NvVulnProfileName,
+			SpecScope:         resource.NvClusterSecurityRuleScope,
+			SpecGroup:         common.OEMClusterSecurityRuleGroup,
+			SpecVersion:       resource.NvVulnProfileVersion,
+			SpecNamesPlural:   resource.NvVulnProfilePlural,
+			SpecNamesKind:     resource.NvVulnProfileKind,
+			SpecNamesSingular: resource.NvVulnProfileSingular,
+			SpecNamesListKind: resource.NvVulnProfileListKind,
+			LockKey:           share.CLUSLockPolicyKey,
+			KvCrdKind:         resource.NvVulnProfileKind,
+		},
+		&resource.NvCrdInfo{
+			RscType:           resource.RscTypeCrdCompProfile,
+			MetaName:          resource.NvCompProfileName,
+			SpecScope:         resource.NvClusterSecurityRuleScope,
+			SpecGroup:         common.OEMClusterSecurityRuleGroup,
+			SpecVersion:       resource.NvCompProfileVersion,
+			SpecNamesPlural:   resource.NvCompProfilePlural,
+			SpecNamesKind:     resource.NvCompProfileKind,
+			SpecNamesSingular: resource.NvCompProfileSingular,
+			SpecNamesListKind: resource.NvCompProfileListKind,
+			LockKey:           share.CLUSLockPolicyKey,
+			KvCrdKind:         resource.NvCompProfileKind,
+		},
 	}
 	if cspType != share.CSP_NONE {
 		nvCrdInfo = []*resource.NvCrdInfo{
@@ -1356,9 +1844,17 @@ func CheckCrdSchema(leader, create bool, cspType share.TCspType) []string {
 	crdOutOfDate := make([]string, 0, len(nvCrdInfo))
 	errors := make([]string, 0, len(nvCrdInfo))
 	for _, crdInfo := range nvCrdInfo {
-		// [2023/04] no more crd schema upgrade.
+		// [2023/04] no more crd schema upgrade, except for the status subresource & served versions that controller depends on
 		crdConfigured, crdUpToDate, err := isCrdUpToDate(leader, crdInfo)
 		if crdConfigured {
+			if !crdUpToDate && leader && create && isCrdUpgradable(crdInfo.MetaName) {
+				if err = upgradeK8sCrdSchema(crdInfo); err == nil {
+					log.WithFields(log.Fields{"crd": crdInfo.MetaName}).Info("upgraded crd schema in k8s")
+					crdUpToDate = true
+				} else {
+					log.WithFields(log.Fields{"crd": crdInfo.MetaName, "err": err}).Error("failed to upgrade crd schema")
+				}
+			}
 			if leader && create {
 				if crdInfo.RscType != resource.RscTypeCrdNvCspUsage {
 					rest.CrossCheckCrd(crdInfo.SpecNamesKind, crdInfo.RscType, crdInfo.KvCrdKind, crdInfo.LockKey, false)
@@ -32,6 +32,7 @@ const (
 	AuditLogPropNamespace   = "Namespace"
 	AuditLogPropFirstLogAt  = "FirstLogAt"
 	AuditLogPropLastLogAt   = "LastLogAt"
+	AuditLogPropRuleID      = "RuleID"
 )
 
 type ScannedImageSummary struct {
@@ -403,6 +404,12 @@ func getAdmK8sDenyRuleOptions() map[string]*api.RESTAdmissionRuleOption {
 				Values:   boolTrueOp,
 				MatchSrc: api.MatchSrcBoth,
 			},
+			share.CriteriaKeyPrivilegedSCC: &api.RESTAdmissionRuleOption{
+				Name:     share.CriteriaKeyPrivilegedSCC,
+				Ops:      []string{share.CriteriaOpEqual},
+				Values:   boolTrueOp,
+				MatchSrc: api.MatchSrcYaml,
+			},
 			share.CriteriaKeyRequestLimit: &api.RESTAdmissionRuleOption{
 				Name:     share.CriteriaKeyRequestLimit,
 				Ops:      []string{},
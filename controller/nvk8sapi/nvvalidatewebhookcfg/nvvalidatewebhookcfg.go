@@ -62,6 +62,7 @@ type ValidatingWebhookConfigInfo struct {
 const (
 	UriAdmCtrlPrefix   = "/v1"
 	UriAdmCtrlNvStatus = "nvstatus"
+	UriCrdConvert      = "/v1/crdconvert" // served by the crd webhook server
 )
 
 const (
@@ -194,6 +195,10 @@ func SetCABundle(svcName string, caBundle []byte) {
 	resource.GetK8sVersion()
 }
 
+func GetCABundle(svcName string) []byte {
+	return []byte(admCaBundle[svcName])
+}
+
 func ResetCABundle(svcName string, caBundle []byte) bool { // return true if reset
 	newCert := string(caBundle)
 	oldCert := admCaBundle[svcName]
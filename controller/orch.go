@@ -92,6 +92,14 @@ func (c *orchConn) cbResourceWatcher(rt string, event string, res interface{}, o
 					LockKey:   share.CLUSLockPolicyKey,
 					KvCrdKind: resource.NvWafSecurityRuleKind,
 				},
+				resource.NvVulnProfileName: &resource.NvCrdInfo{
+					LockKey:   share.CLUSLockPolicyKey,
+					KvCrdKind: resource.NvVulnProfileKind,
+				},
+				resource.NvCompProfileName: &resource.NvCrdInfo{
+					LockKey:   share.CLUSLockPolicyKey,
+					KvCrdKind: resource.NvCompProfileKind,
+				},
 			}
 			if crd, ok := res.(*apiextv1b1.CustomResourceDefinition); ok {
 				if crdInfo, ok := nvCrdInfo[*crd.Metadata.Name]; ok {
@@ -165,6 +173,8 @@ func (c *orchConn) Start(ocImageRegistered bool, cspType share.TCspType) {
 		resource.RscTypeCrdAdmCtrlSecurityRule,
 		resource.RscTypeCrdDlpSecurityRule,
 		resource.RscTypeCrdWafSecurityRule,
+		resource.RscTypeCrdVulnProfile,
+		resource.RscTypeCrdCompProfile,
 	}
 	for _, r := range rscTypes {
 		global.ORCH.RegisterResource(r)
@@ -177,6 +187,13 @@ func (c *orchConn) Start(ocImageRegistered bool, cspType share.TCspType) {
 	if ocImageRegistered {
 		r = resource.RscTypeImage
 		global.ORCH.StartWatchResource(r, k8s.AllNamespaces, c.cbResourceWatcher, c.cbWatcherState)
+
+		// Routes for the exposure of the services and SCCs for the admission control
+		for _, r := range []string{resource.RscTypeRoute, resource.RscTypeSCC} {
+			if err := global.ORCH.RegisterResource(r); err == nil {
+				global.ORCH.StartWatchResource(r, k8s.AllNamespaces, c.cbResourceWatcher, nil)
+			}
+		}
 	}
 }
 
@@ -0,0 +1,46 @@
+package main
+
+// Break-glass recovery of the built-in admin account. The token is minted by running the controller
+// binary with -recovery_token inside a controller pod, so only those who can exec into the pod can
+// recover the account. The token is printed to stdout only; the cluster keeps its hash.
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/cluster"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+const recoveryTokenLife = time.Minute * 15
+
+func mintRecoveryToken(username string) int {
+	// other accounts are recovered by the admin
+	if username != common.DefaultAdminUser {
+		fmt.Fprintf(os.Stderr, "Only the built-in %s account can be recovered\n", common.DefaultAdminUser)
+		return -2
+	}
+
+	token := utils.GetRandomID(32, "")
+	rt := share.CLUSRecoveryToken{
+		Username:  username,
+		TokenHash: utils.HashPassword(token),
+		ExpireAt:  time.Now().UTC().Add(recoveryTokenLife),
+	}
+	value, _ := json.Marshal(&rt)
+	if err := cluster.PutQuiet(share.CLUSRecoveryTokenKey, value); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to write recovery token")
+		return -2
+	}
+
+	log.WithFields(log.Fields{"user": username, "expire": rt.ExpireAt}).Info("Recovery token is created")
+	fmt.Fprintf(os.Stdout, "Recovery token for user %s (valid until %s, can be used once):\n%s\n",
+		username, rt.ExpireAt.Format(time.RFC3339), token)
+	return 0
+}
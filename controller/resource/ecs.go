@@ -0,0 +1,188 @@
+package resource
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	orchAPI "github.com/neuvector/neuvector/share/orchestration"
+)
+
+// ECS has no watch API, the tasks of the cluster are polled. The tasks in the awsvpc network mode, including
+// the Fargate tasks that cannot be protected by the enforcers, are reported as pods, so their addresses are
+// recognized as the workloads instead of the external peers.
+
+const ecsTaskPollInterval = time.Second * 30
+
+type ecs struct {
+	*noop
+
+	lock    sync.Mutex
+	cluster string
+	client  *orchAPI.ECSClient
+	stopCh  chan struct{}
+}
+
+func newECSDriver(platform, flavor, network string) *ecs {
+	return &ecs{noop: newNoopDriver(platform, flavor, network)}
+}
+
+func (d *ecs) connect() error {
+	if d.client != nil {
+		return nil
+	}
+
+	meta, err := orchAPI.GetECSTaskMetadata()
+	if err != nil {
+		return err
+	}
+
+	region, _ := orchAPI.ParseECSTaskArn(meta.TaskARN)
+	client, err := orchAPI.NewECSClient(region)
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{"cluster": meta.Cluster, "region": region}).Info("ECS connected")
+	d.cluster = meta.Cluster
+	d.client = client
+	return nil
+}
+
+func xlateECSTask(task *orchAPI.ECSTask) *Pod {
+	ip := net.ParseIP(task.PrivateIPv4())
+	if ip == nil {
+		return nil
+	}
+
+	pod := &Pod{
+		UID:          task.TaskArn,
+		Name:         task.TaskArn[strings.LastIndex(task.TaskArn, "/")+1:],
+		IPNet:        net.IPNet{IP: ip, Mask: net.CIDRMask(32, 32)},
+		Running:      task.LastStatus == "RUNNING",
+		OwnerName:    orchAPI.ECSServiceName(task.Group),
+		ContainerIDs: make([]string, 0, len(task.Containers)),
+	}
+	if pod.OwnerName != "" {
+		pod.OwnerType = "service"
+	}
+	for _, c := range task.Containers {
+		if c.RuntimeID != "" {
+			pod.ContainerIDs = append(pod.ContainerIDs, c.RuntimeID)
+		}
+	}
+	return pod
+}
+
+func (d *ecs) pollTasks(cb orchAPI.WatchCallback) error {
+	tasks, err := d.client.ListTasks(d.cluster)
+	if err != nil {
+		return err
+	}
+
+	pods := make(map[string]*Pod, len(tasks))
+	for _, task := range tasks {
+		if pod := xlateECSTask(task); pod != nil {
+			pods[pod.UID] = pod
+			if ev, old := d.updateResourceCache(RscTypePod, pod.UID, pod); ev != "" {
+				cb(RscTypePod, ev, pod, old)
+			}
+		}
+	}
+
+	d.noop.lock.RLock()
+	stopped := make([]string, 0)
+	for id := range d.resCaches[RscTypePod] {
+		if _, ok := pods[id]; !ok {
+			stopped = append(stopped, id)
+		}
+	}
+	d.noop.lock.RUnlock()
+
+	for _, id := range stopped {
+		if ev, old := d.deleteResourceCache(RscTypePod, id); ev != "" {
+			cb(RscTypePod, ev, nil, old)
+		}
+	}
+	return nil
+}
+
+func (d *ecs) StartWatchResource(rt, ns string, wcb orchAPI.WatchCallback, scb orchAPI.StateCallback) error {
+	if rt != RscTypePod {
+		return ErrResourceNotSupported
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.stopCh != nil {
+		return nil
+	}
+	if err := d.connect(); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to connect to ECS")
+		return err
+	}
+
+	stopCh := make(chan struct{})
+	d.stopCh = stopCh
+	go func() {
+		ticker := time.NewTicker(ecsTaskPollInterval)
+		defer ticker.Stop()
+
+		var state string
+		for {
+			newState := ConnStateConnected
+			err := d.pollTasks(wcb)
+			if err != nil {
+				log.WithFields(log.Fields{"error": err}).Error("Failed to list ECS tasks")
+				newState = ConnStateDisconnected
+			}
+			if scb != nil && newState != state {
+				scb(newState, err)
+			}
+			state = newState
+
+			select {
+			case <-ticker.C:
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (d *ecs) StopWatchResource(rt string) error {
+	if rt != RscTypePod {
+		return ErrResourceNotSupported
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.stopCh != nil {
+		close(d.stopCh)
+		d.stopCh = nil
+	}
+	return nil
+}
+
+func (d *ecs) StopWatchAllResources() error {
+	return d.StopWatchResource(RscTypePod)
+}
+
+func (d *ecs) ListResource(rt string) ([]interface{}, error) {
+	if rt != RscTypePod {
+		return nil, ErrResourceNotSupported
+	}
+
+	d.noop.lock.RLock()
+	defer d.noop.lock.RUnlock()
+	list := make([]interface{}, 0, len(d.resCaches[RscTypePod]))
+	for _, pod := range d.resCaches[RscTypePod] {
+		list = append(list, pod)
+	}
+	return list, nil
+}
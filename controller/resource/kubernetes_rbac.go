@@ -137,6 +137,7 @@ var rbacRoleVerbs utils.Set = utils.NewSet("get", "list", "watch")
 var admissionRoleVerbs utils.Set = utils.NewSet("create", "delete", "get", "list", "update", "watch")
 var crdRoleVerbs utils.Set = utils.NewSet("create", "get", "update", "watch")
 var crdPolicyRoleVerbs utils.Set = utils.NewSet("delete", "list")
+var crdStatusRoleVerbs utils.Set = utils.NewSet("get", "update")
 
 var ctrlerSubjectWanted string = "controller"
 var updaterSubjectWanted string = "updater"
@@ -196,6 +197,11 @@ var rbacRolesWanted map[string]*k8sRbacRoleInfo = map[string]*k8sRbacRoleInfo{ /
 				resources: utils.NewSet(RscTypeCrdClusterSecurityRule, RscTypeCrdSecurityRule),
 				verbs:     crdPolicyRoleVerbs,
 			},
+			&k8sRbacRoleRuleInfo{
+				apiGroup:  constApiGroupNV,
+				resources: utils.NewSet(RscTypeCrdClusterSecurityRule+"/status", RscTypeCrdSecurityRule+"/status"),
+				verbs:     crdStatusRoleVerbs,
+			},
 		},
 	},
 	nvCrdAdmCtrlRole: &k8sRbacRoleInfo{
@@ -226,6 +232,31 @@ var rbacRolesWanted map[string]*k8sRbacRoleInfo = map[string]*k8sRbacRoleInfo{ /
 				resources: utils.NewSet(RscTypeCrdWafSecurityRule),
 				verbs:     crdPolicyRoleVerbs,
 			},
+			&k8sRbacRoleRuleInfo{
+				apiGroup:  constApiGroupNV,
+				resources: utils.NewSet(RscTypeCrdWafSecurityRule + "/status"),
+				verbs:     crdStatusRoleVerbs,
+			},
+		},
+	},
+	nvCrdVulnProfileRole: &k8sRbacRoleInfo{
+		name: nvCrdVulnProfileRole,
+		rules: []*k8sRbacRoleRuleInfo{
+			&k8sRbacRoleRuleInfo{
+				apiGroup:  constApiGroupNV,
+				resources: utils.NewSet(RscTypeCrdVulnProfile),
+				verbs:     crdPolicyRoleVerbs,
+			},
+		},
+	},
+	nvCrdCompProfileRole: &k8sRbacRoleInfo{
+		name: nvCrdCompProfileRole,
+		rules: []*k8sRbacRoleRuleInfo{
+			&k8sRbacRoleRuleInfo{
+				apiGroup:  constApiGroupNV,
+				resources: utils.NewSet(RscTypeCrdCompProfile),
+				verbs:     crdPolicyRoleVerbs,
+			},
 		},
 	},
 	NvScannerRole: &k8sRbacRoleInfo{ // it's actually for updater pod
@@ -286,6 +317,14 @@ var rbacRoleBindingsWanted map[string]*k8sRbacBindingInfo = map[string]*k8sRbacB
 		subjects: ctrlerSubjectsWanted,
 		rbacRole: rbacRolesWanted[nvCrdWafRole],
 	},
+	nvCrdVulnProfileBinding: &k8sRbacBindingInfo{
+		subjects: ctrlerSubjectsWanted,
+		rbacRole: rbacRolesWanted[nvCrdVulnProfileRole],
+	},
+	nvCrdCompProfileBinding: &k8sRbacBindingInfo{
+		subjects: ctrlerSubjectsWanted,
+		rbacRole: rbacRolesWanted[nvCrdCompProfileRole],
+	},
 	nvViewRoleBinding: &k8sRbacBindingInfo{
 		subjects: ctrlerSubjectsWanted,
 		rbacRole: rbacRolesWanted[k8sClusterRoleView],
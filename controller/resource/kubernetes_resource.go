@@ -93,6 +93,10 @@ const (
 	nvCrdDlpRoleBinding     = nvCrdDlpRole
 	nvCrdWafRole            = "neuvector-binding-nvwafsecurityrules"
 	nvCrdWafRoleBinding     = nvCrdWafRole
+	nvCrdVulnProfileRole    = "neuvector-binding-nvvulnerabilityprofiles"
+	nvCrdVulnProfileBinding = nvCrdVulnProfileRole
+	nvCrdCompProfileRole    = "neuvector-binding-nvcomplianceprofiles"
+	nvCrdCompProfileBinding = nvCrdCompProfileRole
 	NvScannerRole           = "neuvector-binding-scanner"
 	NvScannerRoleBinding    = NvScannerRole
 	NvAdminRoleBinding      = "neuvector-admin"
@@ -128,6 +132,9 @@ const (
 	AdmissionK8sIoV1Beta1 = "admission.k8s.io/v1beta1"
 
 	K8sKindAdmissionReview = "AdmissionReview"
+
+	ApiextensionsK8sIoV1    = "apiextensions.k8s.io/v1"
+	K8sKindConversionReview = "ConversionReview"
 )
 
 type resourceWatcher struct {
@@ -204,6 +211,31 @@ type K8sAdmRegValidatingWebhookConfiguration struct {
 	Webhooks []*K8sAdmRegWebhook
 }
 
+//--- for ConversionReview in apiextensions v1
+type K8sConversionRequest struct {
+	UID               string            `json:"uid"`
+	DesiredAPIVersion string            `json:"desiredAPIVersion"`
+	Objects           []json.RawMessage `json:"objects"`
+}
+
+type K8sConversionResult struct {
+	Status  string `json:"status"` // "Success" / "Failure"
+	Message string `json:"message,omitempty"`
+}
+
+type K8sConversionResponse struct {
+	UID              string              `json:"uid"`
+	ConvertedObjects []json.RawMessage   `json:"convertedObjects"`
+	Result           K8sConversionResult `json:"result"`
+}
+
+type K8sConversionReview struct {
+	APIVersion string                 `json:"apiVersion"`
+	Kind       string                 `json:"kind"`
+	Request    *K8sConversionRequest  `json:"request,omitempty"`
+	Response   *K8sConversionResponse `json:"response,omitempty"`
+}
+
 type NvAdmRegRuleSetting struct {
 	ApiGroups  utils.Set
 	Operations utils.Set
@@ -272,7 +304,7 @@ var AdmResForOpsSettings = []*NvAdmRegRuleSetting{
 }
 
 var crdResForAllOpSet = utils.NewSet(RscTypeCrdSecurityRule, RscTypeCrdClusterSecurityRule, RscTypeCrdAdmCtrlSecurityRule, RscTypeCrdDlpSecurityRule,
-	RscTypeCrdWafSecurityRule)
+	RscTypeCrdWafSecurityRule, RscTypeCrdVulnProfile, RscTypeCrdCompProfile)
 var CrdResForOpsSettings = []*NvAdmRegRuleSetting{
 	&NvAdmRegRuleSetting{
 		ApiGroups:  allApiGroups,
@@ -449,6 +481,30 @@ var resourceMakers map[string]k8sResource = map[string]k8sResource{
 			},
 		},
 	},
+	RscTypeRoute: k8sResource{
+		apiGroup: "route.openshift.io",
+		makers: []*resourceMaker{
+			&resourceMaker{
+				"v1",
+				func() k8s.Resource { return new(ocRoute) },
+				func() k8s.ResourceList { return new(ocRouteList) },
+				xlateRoute,
+				nil,
+			},
+		},
+	},
+	RscTypeSCC: k8sResource{
+		apiGroup: "security.openshift.io",
+		makers: []*resourceMaker{
+			&resourceMaker{
+				"v1",
+				func() k8s.Resource { return new(ocSecurityContextConstraints) },
+				func() k8s.ResourceList { return new(ocSecurityContextConstraintsList) },
+				xlateSCC,
+				nil,
+			},
+		},
+	},
 	k8sRscTypeRole: k8sResource{
 		apiGroup: k8sRbacApiGroup,
 		makers: []*resourceMaker{
@@ -605,6 +661,30 @@ var resourceMakers map[string]k8sResource = map[string]k8sResource{
 			},
 		},
 	},
+	RscTypeCrdVulnProfile: k8sResource{
+		apiGroup: constApiGroupNV,
+		makers: []*resourceMaker{
+			&resourceMaker{
+				"v1",
+				func() k8s.Resource { return new(NvVulnProfileSecurityRule) },
+				func() k8s.ResourceList { return new(NvVulnProfileSecurityRuleList) },
+				xlateCrdVulnProfile,
+				nil,
+			},
+		},
+	},
+	RscTypeCrdCompProfile: k8sResource{
+		apiGroup: constApiGroupNV,
+		makers: []*resourceMaker{
+			&resourceMaker{
+				"v1",
+				func() k8s.Resource { return new(NvCompProfileSecurityRule) },
+				func() k8s.ResourceList { return new(NvCompProfileSecurityRuleList) },
+				xlateCrdCompProfile,
+				nil,
+			},
+		},
+	},
 	RscTypeCrdNvCspUsage: k8sResource{
 		apiGroup: "susecloud.net",
 		makers: []*resourceMaker{
@@ -1044,6 +1124,61 @@ func xlateImage(obj k8s.Resource) (string, interface{}) {
 	return "", nil
 }
 
+func xlateRoute(obj k8s.Resource) (string, interface{}) {
+	if o, ok := obj.(*ocRoute); ok {
+		if o.Metadata == nil {
+			return "", nil
+		}
+		meta := o.Metadata
+		r := &Route{
+			UID:      meta.GetUid(),
+			Name:     meta.GetName(),
+			Domain:   meta.GetNamespace(),
+			Services: make([]string, 0),
+		}
+		if o.Spec != nil {
+			r.Host = o.Spec.Host
+			r.Path = o.Spec.Path
+			for _, to := range append([]ocRouteTargetReference{o.Spec.To}, o.Spec.AlternateBackends...) {
+				if (to.Kind == "" || to.Kind == "Service") && to.Name != "" {
+					r.Services = append(r.Services, to.Name)
+				}
+			}
+			if o.Spec.TLS != nil {
+				r.TLSTermination = o.Spec.TLS.Termination
+			}
+		}
+		return r.UID, r
+	}
+
+	return "", nil
+}
+
+func xlateSCC(obj k8s.Resource) (string, interface{}) {
+	if o, ok := obj.(*ocSecurityContextConstraints); ok {
+		if o.Metadata == nil {
+			return "", nil
+		}
+		meta := o.Metadata
+		r := &SCC{
+			UID:          meta.GetUid(),
+			Name:         meta.GetName(),
+			Privileged:   o.AllowPrivilegedContainer,
+			HostNetwork:  o.AllowHostNetwork,
+			HostPID:      o.AllowHostPID,
+			HostIPC:      o.AllowHostIPC,
+			RunAsAnyUser: o.RunAsUser.Type == "RunAsAny",
+			Users:        o.Users,
+			Groups:       o.Groups,
+		}
+		// privilege escalation is allowed if it is not set
+		r.PrivilegeEscalation = o.AllowPrivilegeEscalation == nil || *o.AllowPrivilegeEscalation
+		return r.UID, r
+	}
+
+	return "", nil
+}
+
 func xlateCrd(obj k8s.Resource) (string, interface{}) {
 	if o, ok := obj.(*apiextv1b1.CustomResourceDefinition); ok {
 		if o.Metadata == nil {
@@ -1154,6 +1289,38 @@ func xlateCrdWafSecurityRule(obj k8s.Resource) (string, interface{}) {
 	return "", nil
 }
 
+func xlateCrdVulnProfile(obj k8s.Resource) (string, interface{}) {
+	if o, ok := obj.(*NvVulnProfileSecurityRule); ok {
+		if o.Metadata == nil {
+			return "", nil
+		}
+		meta := o.Metadata
+		r := &CRD{
+			UID:  meta.GetUid(),
+			Name: meta.GetName(),
+		}
+		return r.UID, o
+	}
+
+	return "", nil
+}
+
+func xlateCrdCompProfile(obj k8s.Resource) (string, interface{}) {
+	if o, ok := obj.(*NvCompProfileSecurityRule); ok {
+		if o.Metadata == nil {
+			return "", nil
+		}
+		meta := o.Metadata
+		r := &CRD{
+			UID:  meta.GetUid(),
+			Name: meta.GetName(),
+		}
+		return r.UID, o
+	}
+
+	return "", nil
+}
+
 func xlateCrdCspUsage(obj k8s.Resource) (string, interface{}) {
 	if o, ok := obj.(*NvCspUsage); ok {
 		if o.Metadata == nil {
@@ -1314,6 +1481,22 @@ func (d *kubernetes) RegisterResource(rt string) error {
 			k8s.RegisterList("image.openshift.io", "v1", "imagestreams", true, &ocImageStreamList{})
 			d.lock.Unlock()
 		}
+	case RscTypeRoute:
+		_, err = d.discoverResource(rt)
+		if err == nil {
+			d.lock.Lock()
+			k8s.Register("route.openshift.io", "v1", ocResRoutes, true, &ocRoute{})
+			k8s.RegisterList("route.openshift.io", "v1", ocResRoutes, true, &ocRouteList{})
+			d.lock.Unlock()
+		}
+	case RscTypeSCC:
+		_, err = d.discoverResource(rt)
+		if err == nil {
+			d.lock.Lock()
+			k8s.Register("security.openshift.io", "v1", ocResSCCs, false, &ocSecurityContextConstraints{})
+			k8s.RegisterList("security.openshift.io", "v1", ocResSCCs, false, &ocSecurityContextConstraintsList{})
+			d.lock.Unlock()
+		}
 	case RscTypeCrdSecurityRule:
 		d.lock.Lock()
 		k8s.Register("neuvector.com", "v1", NvSecurityRulePlural, true, &NvSecurityRule{})
@@ -1348,6 +1531,20 @@ func (d *kubernetes) RegisterResource(rt string) error {
 		k8s.RegisterList("neuvector.com", "v1", NvWafSecurityRulePlural, false, &NvWafSecurityRuleList{})
 		d.lock.Unlock()
 
+		_, err = d.discoverResource(rt)
+	case RscTypeCrdVulnProfile:
+		d.lock.Lock()
+		k8s.Register("neuvector.com", "v1", NvVulnProfilePlural, false, &NvVulnProfileSecurityRule{})
+		k8s.RegisterList("neuvector.com", "v1", NvVulnProfilePlural, false, &NvVulnProfileSecurityRuleList{})
+		d.lock.Unlock()
+
+		_, err = d.discoverResource(rt)
+	case RscTypeCrdCompProfile:
+		d.lock.Lock()
+		k8s.Register("neuvector.com", "v1", NvCompProfilePlural, false, &NvCompProfileSecurityRule{})
+		k8s.RegisterList("neuvector.com", "v1", NvCompProfilePlural, false, &NvCompProfileSecurityRuleList{})
+		d.lock.Unlock()
+
 		_, err = d.discoverResource(rt)
 	case RscTypeCrdNvCspUsage:
 		d.lock.Lock()
@@ -1645,9 +1842,12 @@ func (d *kubernetes) GetResource(rt, namespace, name string) (interface{}, error
 	switch rt {
 	//case RscTypeMutatingWebhookConfiguration:
 	case RscTypeNamespace, RscTypeService, K8sRscTypeClusRole, K8sRscTypeClusRoleBinding, k8sRscTypeRole, k8sRscTypeRoleBinding, RscTypeValidatingWebhookConfiguration,
-		RscTypeCrd, RscTypeConfigMap, RscTypeCrdSecurityRule, RscTypeCrdClusterSecurityRule, RscTypeCrdAdmCtrlSecurityRule, RscTypeCrdDlpSecurityRule, RscTypeCrdWafSecurityRule,
-		RscTypeDeployment, RscTypeReplicaSet, RscTypeStatefulSet, RscTypeCrdNvCspUsage:
+		RscTypeCrd, RscTypeConfigMap, RscTypeCrdAdmCtrlSecurityRule, RscTypeCrdDlpSecurityRule,
+		RscTypeCrdVulnProfile, RscTypeCrdCompProfile, RscTypeDeployment, RscTypeReplicaSet, RscTypeStatefulSet, RscTypeCrdNvCspUsage:
 		return d.getResource(rt, namespace, name)
+	case RscTypeCrdSecurityRule, RscTypeCrdClusterSecurityRule, RscTypeCrdWafSecurityRule:
+		// controller only reads/writes the status subresource of these crd objects
+		return d.getResource(rt, namespace, name, k8s.Subresource("status"))
 	case RscTypePod, RscTypeNode, RscTypeCronJob, RscTypeDaemonSet:
 		if r, err := d.getResource(rt, namespace, name); err == nil {
 			if maker, err := d.discoverResource(rt); err == nil {
@@ -1663,7 +1863,7 @@ func (d *kubernetes) GetResource(rt, namespace, name string) (interface{}, error
 	return nil, ErrResourceNotSupported
 }
 
-func (d *kubernetes) getResource(rt, namespace, name string) (interface{}, error) {
+func (d *kubernetes) getResource(rt, namespace, name string, options ...k8s.Option) (interface{}, error) {
 	log.WithFields(log.Fields{"resource": rt}).Debug()
 
 	maker, err := d.discoverResource(rt)
@@ -1680,7 +1880,7 @@ func (d *kubernetes) getResource(rt, namespace, name string) (interface{}, error
 	obj := maker.newObject()
 	d.lock.Lock()
 	defer d.lock.Unlock()
-	err = d.client.Get(context.Background(), namespace, name, obj)
+	err = d.client.Get(context.Background(), namespace, name, obj, options...)
 
 	return obj, err
 }
@@ -1740,11 +1940,13 @@ func (d *kubernetes) UpdateResource(rt string, res interface{}) error {
 	//case RscTypeMutatingWebhookConfiguration:
 	case RscTypeValidatingWebhookConfiguration, RscTypeCrd, RscTypeCrdNvCspUsage:
 		return d.updateResource(rt, res)
+	case RscTypeCrdSecurityRule, RscTypeCrdClusterSecurityRule, RscTypeCrdWafSecurityRule:
+		return d.updateResource(rt, res, k8s.Subresource("status"))
 	}
 	return ErrResourceNotSupported
 }
 
-func (d *kubernetes) updateResource(rt string, res interface{}) error {
+func (d *kubernetes) updateResource(rt string, res interface{}, options ...k8s.Option) error {
 	log.WithFields(log.Fields{"resource": rt}).Debug()
 	defer log.WithFields(log.Fields{"resource": rt}).Debug("leave")
 
@@ -1766,7 +1968,7 @@ func (d *kubernetes) updateResource(rt string, res interface{}) error {
 
 	d.lock.Lock()
 	defer d.lock.Unlock()
-	err = d.client.Update(context.Background(), obj)
+	err = d.client.Update(context.Background(), obj, options...)
 
 	return err
 }
@@ -1775,7 +1977,8 @@ func (d *kubernetes) DeleteResource(rt string, res interface{}) error {
 	switch rt {
 	//case RscTypeMutatingWebhookConfiguration:
 	case RscTypeValidatingWebhookConfiguration, RscTypeCrd, RscTypeCrdSecurityRule, RscTypeCrdClusterSecurityRule,
-		RscTypeCrdAdmCtrlSecurityRule, RscTypeCrdDlpSecurityRule, RscTypeCrdWafSecurityRule, RscTypeCrdNvCspUsage:
+		RscTypeCrdAdmCtrlSecurityRule, RscTypeCrdDlpSecurityRule, RscTypeCrdWafSecurityRule, RscTypeCrdVulnProfile, RscTypeCrdCompProfile,
+		RscTypeCrdNvCspUsage:
 		return d.deleteResource(rt, res)
 	}
 	return ErrResourceNotSupported
@@ -1912,6 +2115,18 @@ func AdjustAdmResForOC() {
 			verbs:     rbacRoleVerbs,
 		}
 		roleInfo.rules = append(roleInfo.rules, rule)
+		rule = &k8sRbacRoleRuleInfo{
+			apiGroup:  "route.openshift.io",
+			resources: utils.NewSet(ocResRoutes),
+			verbs:     rbacRoleVerbs,
+		}
+		roleInfo.rules = append(roleInfo.rules, rule)
+		rule = &k8sRbacRoleRuleInfo{
+			apiGroup:  "security.openshift.io",
+			resources: utils.NewSet(ocResSCCs),
+			verbs:     rbacRoleVerbs,
+		}
+		roleInfo.rules = append(roleInfo.rules, rule)
 	}
 	// ocVersionMajor == 0 : if k8s RBAC neuvector-binding-co is missing, we cannot get oc version. In this case treat it as oc 4.x
 	if ocVersionMajor == 0 || ocVersionMajor > 3 {
@@ -1923,6 +2138,12 @@ func AdjustAdmResForOC() {
 					resources: utils.NewSet(clusterOperators),
 					verbs:     utils.NewSet("get", "list"),
 				},
+				// for the SCC checks in the OpenShift benchmark
+				&k8sRbacRoleRuleInfo{
+					apiGroup:  "security.openshift.io",
+					resources: utils.NewSet(ocResSCCs),
+					verbs:     utils.NewSet("get", "list"),
+				},
 			}}
 		rbacRoleBindingsWanted[nvOperatorsRoleBinding] = &k8sRbacBindingInfo{
 			subjects: enforcerSubjecstWanted,
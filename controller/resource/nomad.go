@@ -0,0 +1,412 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	orchAPI "github.com/neuvector/neuvector/share/orchestration"
+)
+
+// Nomad resources are watched by the blocking queries of the HTTP API. Each response returns the full list
+// of the resource, which is compared with the cache to generate the add, modify and delete events.
+// Nodes, namespaces, services, jobs and allocations are translated to the kubernetes-like resources, and an
+// allocation, whose tasks share the network, is reported as a pod.
+
+const (
+	nomadDefaultAddr    = "http://127.0.0.1:4646"
+	nomadAddrEnv        = "NOMAD_ADDR"
+	nomadTokenEnv       = "NOMAD_TOKEN"
+	nomadBlockingWait   = "5m"
+	nomadRequestTimeout = time.Minute * 6
+	nomadRetryInterval  = time.Second * 10
+)
+
+type nomadNode struct {
+	ID      string `json:"ID"`
+	Name    string `json:"Name"`
+	Address string `json:"Address"`
+}
+
+type nomadNamespace struct {
+	Name string            `json:"Name"`
+	Meta map[string]string `json:"Meta"`
+}
+
+type nomadServiceStub struct {
+	Namespace string `json:"Namespace"`
+	Services  []struct {
+		ServiceName string   `json:"ServiceName"`
+		Tags        []string `json:"Tags"`
+	} `json:"Services"`
+}
+
+type nomadJob struct {
+	ID         string `json:"ID"`
+	Namespace  string `json:"Namespace"`
+	Type       string `json:"Type"`
+	Status     string `json:"Status"`
+	JobSummary *struct {
+		Summary map[string]struct {
+			Running int32 `json:"Running"`
+		} `json:"Summary"`
+	} `json:"JobSummary"`
+}
+
+type nomadAllocation struct {
+	ID                 string `json:"ID"`
+	Name               string `json:"Name"`
+	Namespace          string `json:"Namespace"`
+	NodeName           string `json:"NodeName"`
+	JobID              string `json:"JobID"`
+	TaskGroup          string `json:"TaskGroup"`
+	DesiredStatus      string `json:"DesiredStatus"`
+	ClientStatus       string `json:"ClientStatus"`
+	AllocatedResources *struct {
+		Shared struct {
+			Networks []struct {
+				Mode string `json:"Mode"`
+				IP   string `json:"IP"`
+			} `json:"Networks"`
+		} `json:"Shared"`
+	} `json:"AllocatedResources"`
+}
+
+type nomadWatcher struct {
+	path   string
+	ns     string
+	stopCh chan struct{}
+}
+
+type nomad struct {
+	*noop
+
+	lock     sync.Mutex
+	addr     string
+	token    string
+	client   *http.Client
+	watchers map[string]*nomadWatcher
+}
+
+var nomadResourcePaths = map[string]string{
+	RscTypeNode:       "/v1/nodes",
+	RscTypeNamespace:  "/v1/namespaces",
+	RscTypeService:    "/v1/services?namespace=*",
+	RscTypeDeployment: "/v1/jobs?namespace=*",
+	RscTypePod:        "/v1/allocations?namespace=*&resources=true",
+}
+
+func newNomadDriver(platform, flavor, network string) *nomad {
+	addr := os.Getenv(nomadAddrEnv)
+	if addr == "" {
+		addr = nomadDefaultAddr
+	}
+	return &nomad{
+		noop:     newNoopDriver(platform, flavor, network),
+		addr:     strings.TrimRight(addr, "/"),
+		token:    os.Getenv(nomadTokenEnv),
+		client:   &http.Client{Transport: &http.Transport{Proxy: http.ProxyFromEnvironment}, Timeout: nomadRequestTimeout},
+		watchers: make(map[string]*nomadWatcher),
+	}
+}
+
+// Return the X-Nomad-Index of the response, which is used as the index of the next blocking query
+func (d *nomad) query(path string, index uint64, resp interface{}) (uint64, error) {
+	url := d.addr + path
+	if index > 0 {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		url = fmt.Sprintf("%s%sindex=%d&wait=%s", url, sep, index, nomadBlockingWait)
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return 0, err
+	}
+	if d.token != "" {
+		req.Header.Set("X-Nomad-Token", d.token)
+	}
+
+	r, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Body.Close()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return 0, err
+	}
+	if r.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("GET %s: %s, %s", path, r.Status, strings.TrimSpace(string(body)))
+	}
+
+	newIndex, _ := strconv.ParseUint(r.Header.Get("X-Nomad-Index"), 10, 64)
+	return newIndex, json.Unmarshal(body, resp)
+}
+
+func xlateNomadNode(n *nomadNode) *Node {
+	node := &Node{UID: n.ID, Name: n.Name}
+	if ip := net.ParseIP(n.Address); ip != nil {
+		if ip.To4() != nil {
+			node.IPNets = []net.IPNet{{IP: ip, Mask: net.CIDRMask(32, 32)}}
+		} else {
+			node.IPNets = []net.IPNet{{IP: ip, Mask: net.CIDRMask(128, 128)}}
+		}
+	}
+	return node
+}
+
+func xlateNomadJob(j *nomadJob) *Deployment {
+	deploy := &Deployment{UID: j.Namespace + "/" + j.ID, Name: j.ID, Domain: j.Namespace}
+	if j.JobSummary != nil {
+		for _, s := range j.JobSummary.Summary {
+			deploy.Replicas += s.Running
+		}
+	}
+	return deploy
+}
+
+// Allocations in the host network mode have no address of their own and are not reported.
+func xlateNomadAllocation(a *nomadAllocation) *Pod {
+	if a.DesiredStatus != "run" || a.AllocatedResources == nil {
+		return nil
+	}
+	switch a.ClientStatus {
+	case "complete", "failed", "lost":
+		return nil
+	}
+
+	var ip net.IP
+	for _, n := range a.AllocatedResources.Shared.Networks {
+		if n.Mode != "" && n.Mode != "host" {
+			if ip = net.ParseIP(n.IP); ip != nil {
+				break
+			}
+		}
+	}
+	if ip == nil || ip.To4() == nil {
+		return nil
+	}
+
+	return &Pod{
+		UID:       a.ID,
+		Name:      a.Name,
+		Domain:    a.Namespace,
+		Node:      a.NodeName,
+		IPNet:     net.IPNet{IP: ip, Mask: net.CIDRMask(32, 32)},
+		Running:   a.ClientStatus == "running",
+		OwnerName: a.JobID,
+		OwnerType: "job",
+	}
+}
+
+func (d *nomad) list(rt, path, ns string) (map[string]interface{}, uint64, error) {
+	var index uint64
+	var err error
+	objs := make(map[string]interface{})
+
+	switch rt {
+	case RscTypeNode:
+		var nodes []*nomadNode
+		if index, err = d.query(path, 0, &nodes); err == nil {
+			for _, n := range nodes {
+				objs[n.ID] = xlateNomadNode(n)
+			}
+		}
+	case RscTypeNamespace:
+		var nss []*nomadNamespace
+		if index, err = d.query(path, 0, &nss); err == nil {
+			for _, n := range nss {
+				objs[n.Name] = &Namespace{UID: n.Name, Name: n.Name, Labels: n.Meta}
+			}
+		}
+	case RscTypeService:
+		var stubs []*nomadServiceStub
+		if index, err = d.query(path, 0, &stubs); err == nil {
+			for _, stub := range stubs {
+				for _, s := range stub.Services {
+					uid := stub.Namespace + "/" + s.ServiceName
+					objs[uid] = &Service{UID: uid, Name: s.ServiceName, Domain: stub.Namespace}
+				}
+			}
+		}
+	case RscTypeDeployment:
+		var jobs []*nomadJob
+		if index, err = d.query(path, 0, &jobs); err == nil {
+			for _, j := range jobs {
+				if ns == "" || j.Namespace == ns {
+					deploy := xlateNomadJob(j)
+					objs[deploy.UID] = deploy
+				}
+			}
+		}
+	case RscTypePod:
+		var allocs []*nomadAllocation
+		if index, err = d.query(path, 0, &allocs); err == nil {
+			for _, a := range allocs {
+				if pod := xlateNomadAllocation(a); pod != nil {
+					objs[pod.UID] = pod
+				}
+			}
+		}
+	default:
+		return nil, 0, ErrResourceNotSupported
+	}
+	return objs, index, err
+}
+
+func (d *nomad) sync(rt string, objs map[string]interface{}, cb orchAPI.WatchCallback) {
+	for id, obj := range objs {
+		if ev, old := d.updateResourceCache(rt, id, obj); ev != "" {
+			cb(rt, ev, obj, old)
+		}
+	}
+
+	d.noop.lock.RLock()
+	removed := make([]string, 0)
+	for id := range d.resCaches[rt] {
+		if _, ok := objs[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	d.noop.lock.RUnlock()
+
+	for _, id := range removed {
+		if ev, old := d.deleteResourceCache(rt, id); ev != "" {
+			cb(rt, ev, nil, old)
+		}
+	}
+}
+
+// The blocking query returns when the index of the resource is changed or the wait time is reached, the
+// list is queried again when the index is changed.
+func (d *nomad) waitIndex(path string, index uint64) (uint64, error) {
+	var discard json.RawMessage
+	return d.query(path, index, &discard)
+}
+
+func (d *nomad) watch(rt string, w *nomadWatcher, wcb orchAPI.WatchCallback, scb orchAPI.StateCallback) {
+	var state string
+	var index uint64
+	for {
+		newState := ConnStateConnected
+		objs, newIndex, err := d.list(rt, w.path, w.ns)
+		if err == nil {
+			d.sync(rt, objs, wcb)
+			index = newIndex
+			for err == nil && newIndex == index {
+				select {
+				case <-w.stopCh:
+					return
+				default:
+				}
+				newIndex, err = d.waitIndex(w.path, index)
+			}
+		}
+		if err != nil {
+			log.WithFields(log.Fields{"resource": rt, "error": err}).Error("Failed to watch nomad resource")
+			newState = ConnStateDisconnected
+		}
+		if scb != nil && newState != state {
+			scb(newState, err)
+		}
+		state = newState
+
+		if err != nil {
+			select {
+			case <-time.After(nomadRetryInterval):
+			case <-w.stopCh:
+				return
+			}
+		}
+	}
+}
+
+func (d *nomad) StartWatchResource(rt, ns string, wcb orchAPI.WatchCallback, scb orchAPI.StateCallback) error {
+	path, ok := nomadResourcePaths[rt]
+	if !ok {
+		return ErrResourceNotSupported
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if _, ok := d.watchers[rt]; ok {
+		return nil
+	}
+
+	w := &nomadWatcher{path: path, ns: ns, stopCh: make(chan struct{})}
+	d.watchers[rt] = w
+	go d.watch(rt, w, wcb, scb)
+	return nil
+}
+
+func (d *nomad) StopWatchResource(rt string) error {
+	if _, ok := nomadResourcePaths[rt]; !ok {
+		return ErrResourceNotSupported
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if w, ok := d.watchers[rt]; ok {
+		close(w.stopCh)
+		delete(d.watchers, rt)
+	}
+	return nil
+}
+
+func (d *nomad) StopWatchAllResources() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for rt, w := range d.watchers {
+		close(w.stopCh)
+		delete(d.watchers, rt)
+	}
+	return nil
+}
+
+func (d *nomad) ListResource(rt string) ([]interface{}, error) {
+	path, ok := nomadResourcePaths[rt]
+	if !ok {
+		return nil, ErrResourceNotSupported
+	}
+
+	objs, _, err := d.list(rt, path, "")
+	if err != nil {
+		return nil, err
+	}
+	list := make([]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		list = append(list, obj)
+	}
+	return list, nil
+}
+
+func (d *nomad) GetResource(rt, namespace, name string) (interface{}, error) {
+	var id string
+	switch rt {
+	case RscTypeNamespace:
+		id = name
+	case RscTypeService, RscTypeDeployment:
+		id = namespace + "/" + name
+	default:
+		return nil, ErrResourceNotSupported
+	}
+
+	if obj := d.locateResourceCache(rt, id); obj != nil {
+		return obj, nil
+	}
+	return nil, fmt.Errorf("%s %s not found", rt, id)
+}
@@ -0,0 +1,47 @@
+package resource
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNomadAllocation(t *testing.T) {
+	data := `[{"ID":"a1","Name":"shop.cart[0]","Namespace":"prod","NodeName":"n1","JobID":"shop","TaskGroup":"cart",
+		"DesiredStatus":"run","ClientStatus":"running",
+		"AllocatedResources":{"Shared":{"Networks":[{"Mode":"bridge","IP":"172.26.64.10"}]}}},
+		{"ID":"a2","Name":"shop.web[0]","Namespace":"prod","JobID":"shop","DesiredStatus":"run","ClientStatus":"running",
+		"AllocatedResources":{"Shared":{"Networks":[{"Mode":"host","IP":"10.1.1.5"}]}}},
+		{"ID":"a3","Name":"shop.cart[1]","Namespace":"prod","JobID":"shop","DesiredStatus":"stop","ClientStatus":"complete",
+		"AllocatedResources":{"Shared":{"Networks":[{"Mode":"bridge","IP":"172.26.64.11"}]}}}]`
+
+	var allocs []*nomadAllocation
+	if err := json.Unmarshal([]byte(data), &allocs); err != nil {
+		t.Fatalf("Failed to parse allocations: %v", err)
+	}
+
+	pod := xlateNomadAllocation(allocs[0])
+	if pod == nil || pod.IPNet.String() != "172.26.64.10/32" || !pod.Running ||
+		pod.Domain != "prod" || pod.OwnerName != "shop" || pod.Node != "n1" {
+		t.Errorf("Unexpected pod: %+v", pod)
+	}
+	if pod = xlateNomadAllocation(allocs[1]); pod != nil {
+		t.Errorf("Host network allocation should be ignored: %+v", pod)
+	}
+	if pod = xlateNomadAllocation(allocs[2]); pod != nil {
+		t.Errorf("Stopped allocation should be ignored: %+v", pod)
+	}
+}
+
+func TestNomadJob(t *testing.T) {
+	data := `{"ID":"shop","Namespace":"prod","Type":"service","Status":"running",
+		"JobSummary":{"Summary":{"cart":{"Running":2},"web":{"Running":3}}}}`
+
+	var job nomadJob
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		t.Fatalf("Failed to parse job: %v", err)
+	}
+	deploy := xlateNomadJob(&job)
+	if deploy.UID != "prod/shop" || deploy.Domain != "prod" || deploy.Replicas != 5 {
+		t.Errorf("Unexpected deployment: %+v", deploy)
+	}
+}
@@ -154,6 +154,10 @@ func Register(platform, flavor, network string) orchAPI.ResourceDriver {
 		return newKubernetesDriver(platform, flavor, network)
 	case share.PlatformDocker:
 		return newSwarmDriver(platform, flavor, network)
+	case share.PlatformAmazonECS:
+		return newECSDriver(platform, flavor, network)
+	case share.PlatformNomad:
+		return newNomadDriver(platform, flavor, network)
 	default:
 		driver := &noop{}
 		return driver
@@ -46,6 +46,38 @@ const NvWafSecurityRuleKind = "NvWafSecurityRule"
 const NvWafSecurityRuleListKind = "NvWafSecurityRuleList"
 const NvWafSecurityRuleSingular = "nvwafsecurityrule"
 
+const NvVulnProfileName = "nvvulnerabilityprofiles.neuvector.com"
+const NvVulnProfileVersion = "v1"
+const NvVulnProfilePlural = "nvvulnerabilityprofiles"
+const NvVulnProfileKind = "NvVulnerabilityProfile"
+const NvVulnProfileListKind = "NvVulnerabilityProfileList"
+const NvVulnProfileSingular = "nvvulnerabilityprofile"
+
+const NvCompProfileName = "nvcomplianceprofiles.neuvector.com"
+const NvCompProfileVersion = "v1"
+const NvCompProfilePlural = "nvcomplianceprofiles"
+const NvCompProfileKind = "NvComplianceProfile"
+const NvCompProfileListKind = "NvComplianceProfileList"
+const NvCompProfileSingular = "nvcomplianceprofile"
+
+// Older versions that are still served by the neuvector.com crds. Objects are stored in the version of each crd
+// and k8s calls the conversion webhook in controller to convert them between the served versions
+const NvCrdVersionV1Alpha1 = "v1alpha1"
+
+var NvCrdServedOldVersions = []string{NvCrdVersionV1Alpha1}
+
+// condition types in the status of NvSecurityRule/NvClusterSecurityRule/NvWafSecurityRule objects
+const (
+	NvCrdConditionAccepted = "Accepted" // the spec passed validation
+	NvCrdConditionSynced   = "Synced"   // a spec of the object is in effect in NeuVector
+	NvCrdConditionError    = "Error"    // the spec is rejected or processed with warning
+)
+
+const (
+	NvCrdConditionTrue  = "True"
+	NvCrdConditionFalse = "False"
+)
+
 // csp billing adapter
 const NvCspUsageName = "cspadapterusagerecords.susecloud.net"
 const NvCspUsagePlural = "cspadapterusagerecords"
@@ -78,10 +110,28 @@ type NvSecurityParse struct {
 	DlpGroupCfg       *api.RESTCrdDlpGroupConfig // per-group's dlp sensor configuration
 	WafGroupCfg       *api.RESTCrdWafGroupConfig // per-group's waf sensor configuration
 	AdmCtrlCfg        *NvCrdAdmCtrlConfig
-	AdmCtrlRulesCfg   map[string][]*NvCrdAdmCtrlRule // map key is "deny" / "exception"
-	DlpSensorCfg      *api.RESTDlpSensorConfig       // dlp sensor defined by this crd object
-	WafSensorCfg      *api.RESTWafSensorConfig       // waf sensor defined by this crd object
-	Uid               string                         // Metadata.Uid from AdmissionReview request
+	AdmCtrlRulesCfg   map[string][]*NvCrdAdmCtrlRule      // map key is "deny" / "exception"
+	DlpSensorCfg      *api.RESTDlpSensorConfig            // dlp sensor defined by this crd object
+	DlpSensorGroups   map[string]string                   // groups the dlp sensor defined by this crd object is applied to. map key is group name, value is action
+	WafSensorGroups   map[string]string                   // groups the waf sensor defined by this crd object is applied to. map key is group name, value is action
+	WafSensorCfg      *api.RESTWafSensorConfig            // waf sensor defined by this crd object
+	VulnProfileCfg    *api.RESTVulnerabilityProfileConfig // entries of the default vulnerability profile defined by this crd object
+	CompProfileCfg    *api.RESTComplianceProfileConfig    // entries of the default compliance profile defined by this crd object
+	Uid               string                              // Metadata.Uid from AdmissionReview request
+}
+
+type NvCrdCondition struct {
+	Type               string `json:"type"`   // NvCrdConditionAccepted / NvCrdConditionSynced / NvCrdConditionError
+	Status             string `json:"status"` // NvCrdConditionTrue / NvCrdConditionFalse
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+// status subresource of NvSecurityRule/NvClusterSecurityRule/NvWafSecurityRule objects, reported by controller after the object is processed
+type NvSecurityRuleStatus struct {
+	ObservedGeneration int64             `json:"observedGeneration,omitempty"`
+	Conditions         []*NvCrdCondition `json:"conditions,omitempty"`
 }
 
 type NvSecurityTarget struct {
@@ -145,10 +195,11 @@ type NvSecurityRulePartial struct {
 }
 
 type NvSecurityRule struct {
-	Kind       *string            `json:"kind,omitempty"`
-	ApiVersion *string            `json:"apiVersion,omitempty"`
-	Metadata   *metav1.ObjectMeta `json:"metadata"`
-	Spec       NvSecurityRuleSpec `json:"spec"`
+	Kind       *string               `json:"kind,omitempty"`
+	ApiVersion *string               `json:"apiVersion,omitempty"`
+	Metadata   *metav1.ObjectMeta    `json:"metadata"`
+	Spec       NvSecurityRuleSpec    `json:"spec"`
+	Status     *NvSecurityRuleStatus `json:"status,omitempty"`
 }
 
 type NvSecurityRuleList struct {
@@ -160,10 +211,11 @@ type NvSecurityRuleList struct {
 }
 
 type NvClusterSecurityRule struct {
-	Kind       *string            `json:"kind,omitempty"`
-	ApiVersion *string            `json:"apiVersion,omitempty"`
-	Metadata   *metav1.ObjectMeta `json:"metadata"`
-	Spec       NvSecurityRuleSpec `json:"spec"`
+	Kind       *string               `json:"kind,omitempty"`
+	ApiVersion *string               `json:"apiVersion,omitempty"`
+	Metadata   *metav1.ObjectMeta    `json:"metadata"`
+	Spec       NvSecurityRuleSpec    `json:"spec"`
+	Status     *NvSecurityRuleStatus `json:"status,omitempty"`
 }
 
 type NvClusterSecurityRuleList struct {
@@ -260,8 +312,15 @@ type NvSecurityDlpSensor struct {
 	RuleList []*NvSecurityDlpRule `json:"rules"`
 }
 
+// group that the dlp sensor is applied to
+type NvSecurityDlpGroupBinding struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+}
+
 type NvSecurityDlpSpec struct {
-	Sensor *NvSecurityDlpSensor `json:"sensor"`
+	Sensor *NvSecurityDlpSensor         `json:"sensor"`
+	Groups []*NvSecurityDlpGroupBinding `json:"groups,omitempty"`
 }
 
 type NvDlpSecurityRule struct {
@@ -299,15 +358,23 @@ type NvSecurityWafSensor struct {
 	RuleList []*NvSecurityWafRule `json:"rules"`
 }
 
+// group that the waf sensor is applied to
+type NvSecurityWafGroupBinding struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+}
+
 type NvSecurityWafSpec struct {
-	Sensor *NvSecurityWafSensor `json:"sensor"`
+	Sensor *NvSecurityWafSensor         `json:"sensor"`
+	Groups []*NvSecurityWafGroupBinding `json:"groups,omitempty"`
 }
 
 type NvWafSecurityRule struct {
-	Kind       *string            `json:"kind,omitempty"`
-	ApiVersion *string            `json:"apiVersion,omitempty"`
-	Metadata   *metav1.ObjectMeta `json:"metadata"`
-	Spec       NvSecurityWafSpec  `json:"spec"`
+	Kind       *string               `json:"kind,omitempty"`
+	ApiVersion *string               `json:"apiVersion,omitempty"`
+	Metadata   *metav1.ObjectMeta    `json:"metadata"`
+	Spec       NvSecurityWafSpec     `json:"spec"`
+	Status     *NvSecurityRuleStatus `json:"status,omitempty"`
 }
 
 type NvWafSecurityRuleList struct {
@@ -326,6 +393,76 @@ func (m *NvWafSecurityRuleList) GetMetadata() *metav1.ListMeta {
 	return m.Metadata
 }
 
+// Vulnerability profile
+type NvSecurityVulnProfileEntry struct {
+	Name    string   `json:"name"`
+	Comment string   `json:"comment,omitempty"`
+	Days    uint     `json:"days,omitempty"` // Only used for 'recent' vuln entries
+	Domains []string `json:"domains,omitempty"`
+	Images  []string `json:"images,omitempty"`
+}
+
+type NvSecurityVulnProfileSpec struct {
+	Entries []*NvSecurityVulnProfileEntry `json:"entries"`
+}
+
+type NvVulnProfileSecurityRule struct {
+	Kind       *string                   `json:"kind,omitempty"`
+	ApiVersion *string                   `json:"apiVersion,omitempty"`
+	Metadata   *metav1.ObjectMeta        `json:"metadata"`
+	Spec       NvSecurityVulnProfileSpec `json:"spec"`
+}
+
+type NvVulnProfileSecurityRuleList struct {
+	Kind             *string                      `json:"kind,omitempty"`
+	ApiVersion       *string                      `json:"apiVersion,omitempty"`
+	Metadata         *metav1.ListMeta             `json:"metadata"`
+	Items            []*NvVulnProfileSecurityRule `json:"items"`
+	XXX_unrecognized []byte                       `json:"-"`
+}
+
+func (m *NvVulnProfileSecurityRule) GetMetadata() *metav1.ObjectMeta {
+	return m.Metadata
+}
+
+func (m *NvVulnProfileSecurityRuleList) GetMetadata() *metav1.ListMeta {
+	return m.Metadata
+}
+
+// Compliance profile
+type NvSecurityCompProfileEntry struct {
+	TestNum string   `json:"test_number"`
+	Tags    []string `json:"tags"`
+}
+
+type NvSecurityCompProfileSpec struct {
+	DisableSystem *bool                         `json:"disable_system,omitempty"`
+	Entries       []*NvSecurityCompProfileEntry `json:"entries"`
+}
+
+type NvCompProfileSecurityRule struct {
+	Kind       *string                   `json:"kind,omitempty"`
+	ApiVersion *string                   `json:"apiVersion,omitempty"`
+	Metadata   *metav1.ObjectMeta        `json:"metadata"`
+	Spec       NvSecurityCompProfileSpec `json:"spec"`
+}
+
+type NvCompProfileSecurityRuleList struct {
+	Kind             *string                      `json:"kind,omitempty"`
+	ApiVersion       *string                      `json:"apiVersion,omitempty"`
+	Metadata         *metav1.ListMeta             `json:"metadata"`
+	Items            []*NvCompProfileSecurityRule `json:"items"`
+	XXX_unrecognized []byte                       `json:"-"`
+}
+
+func (m *NvCompProfileSecurityRule) GetMetadata() *metav1.ObjectMeta {
+	return m.Metadata
+}
+
+func (m *NvCompProfileSecurityRuleList) GetMetadata() *metav1.ListMeta {
+	return m.Metadata
+}
+
 // csp billing adapter integration
 type NvCspUsage struct {
 	Kind             *string            `json:"kind,omitempty"`
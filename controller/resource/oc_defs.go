@@ -6,9 +6,16 @@ import (
 
 const (
 	ocResImageStreams = "imagestreams"
+	ocResRoutes       = "routes"
+	ocResSCCs         = "securitycontextconstraints"
 	clusterOperators  = "clusteroperators"
 )
 
+const (
+	OpenShiftSCCAnnotation         = "openshift.io/scc"          // set by OpenShift on the pod for the SCC that admitted it
+	OpenShiftRequiredSCCAnnotation = "openshift.io/required-scc" // set by the user to require the SCC
+)
+
 type ocImageStreamTag struct {
 	Name             string            `json:"name,omitempty"`
 	Annotations      map[string]string `json:"annotations"`
@@ -69,3 +76,86 @@ func (m *ocImageStreamList) GetMetadata() *metav1.ListMeta {
 	}
 	return nil
 }
+
+type ocRouteTargetReference struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+type ocTLSConfig struct {
+	Termination                   string `json:"termination"`
+	InsecureEdgeTerminationPolicy string `json:"insecureEdgeTerminationPolicy,omitempty"`
+}
+
+type ocRouteSpec struct {
+	Host              string                   `json:"host,omitempty"`
+	Path              string                   `json:"path,omitempty"`
+	To                ocRouteTargetReference   `json:"to"`
+	AlternateBackends []ocRouteTargetReference `json:"alternateBackends,omitempty"`
+	TLS               *ocTLSConfig             `json:"tls,omitempty"`
+	XXX_unrecognized  []byte                   `json:"-"`
+}
+
+type ocRoute struct {
+	Metadata         *metav1.ObjectMeta `json:"metadata"`
+	Spec             *ocRouteSpec       `json:"spec"`
+	XXX_unrecognized []byte             `json:"-"`
+}
+
+func (m *ocRoute) GetMetadata() *metav1.ObjectMeta {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type ocRouteList struct {
+	Metadata *metav1.ListMeta `json:"metadata"`
+	Items    []*ocRoute       `json:"items"`
+}
+
+func (m *ocRouteList) GetMetadata() *metav1.ListMeta {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// SecurityContextConstraints keeps the settings at the top level instead of in the spec
+type ocSecurityContextConstraints struct {
+	Metadata                 *metav1.ObjectMeta `json:"metadata"`
+	Priority                 *int32             `json:"priority,omitempty"`
+	AllowPrivilegedContainer bool               `json:"allowPrivilegedContainer"`
+	AllowHostNetwork         bool               `json:"allowHostNetwork"`
+	AllowHostPID             bool               `json:"allowHostPID"`
+	AllowHostIPC             bool               `json:"allowHostIPC"`
+	AllowHostPorts           bool               `json:"allowHostPorts"`
+	AllowPrivilegeEscalation *bool              `json:"allowPrivilegeEscalation,omitempty"`
+	AllowedCapabilities      []string           `json:"allowedCapabilities,omitempty"`
+	RunAsUser                struct {
+		Type string `json:"type,omitempty"`
+	} `json:"runAsUser"`
+	Users            []string `json:"users,omitempty"`
+	Groups           []string `json:"groups,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *ocSecurityContextConstraints) GetMetadata() *metav1.ObjectMeta {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type ocSecurityContextConstraintsList struct {
+	Metadata *metav1.ListMeta                `json:"metadata"`
+	Items    []*ocSecurityContextConstraints `json:"items"`
+}
+
+func (m *ocSecurityContextConstraintsList) GetMetadata() *metav1.ListMeta {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
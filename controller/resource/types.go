@@ -25,6 +25,8 @@ const (
 	RscTypePod                            = "pod"
 	RscTypeRBAC                           = "rbac"
 	RscTypeImage                          = "image"
+	RscTypeRoute                          = "route"
+	RscTypeSCC                            = "scc"
 	RscTypeCrd                            = "customresourcedefinition"
 	RscTypeConfigMap                      = "configmap"
 	RscTypeMutatingWebhookConfiguration   = "mutatingwebhookconfiguration"   // case sensitive!
@@ -34,6 +36,8 @@ const (
 	RscTypeCrdAdmCtrlSecurityRule         = "nvadmissioncontrolsecurityrules"
 	RscTypeCrdDlpSecurityRule             = "nvdlpsecurityrules"
 	RscTypeCrdWafSecurityRule             = "nvwafsecurityrules"
+	RscTypeCrdVulnProfile                 = "nvvulnerabilityprofiles"
+	RscTypeCrdCompProfile                 = "nvcomplianceprofiles"
 	RscTypeCrdNvCspUsage                  = "cspadapterusagerecords" // case sensitive
 	RscTypeRbacRoles                      = "roles"
 	RscTypeRbacClusterRoles               = "clusterroles"
@@ -62,8 +66,8 @@ const (
 const RscCspUsageName = "neuvector-usage"
 
 // ValidatingWebhookConfiguration resource instance (neuvector-validating-admission-webhook) contains 2 webhooks:
-// 	1. neuvector-validating-admission-webhook.neuvector.svc
-// 	2. neuvector-validating-status-webhook.neuvector.svc
+//  1. neuvector-validating-admission-webhook.neuvector.svc
+//  2. neuvector-validating-status-webhook.neuvector.svc
 var NvAdmMutatingName = "neuvector-mutating-admission-webhook"     // ValidatingWebhookConfiguration resource instance metadata name
 var NvAdmValidatingName = "neuvector-validating-admission-webhook" // ValidatingWebhookConfiguration resource instance metadata name
 var NvCrdValidatingName = "neuvector-validating-crd-webhook"       // ValidatingWebhookConfiguration resource instance metadata name
@@ -172,6 +176,29 @@ type Image struct {
 	Tags   []ImageTag
 }
 
+type Route struct {
+	UID            string
+	Name           string
+	Domain         string
+	Host           string
+	Path           string
+	Services       []string // target services, including the alternate backends
+	TLSTermination string   // empty if the route is not secured
+}
+
+type SCC struct {
+	UID                 string
+	Name                string
+	Privileged          bool
+	HostNetwork         bool
+	HostPID             bool
+	HostIPC             bool
+	PrivilegeEscalation bool
+	RunAsAnyUser        bool
+	Users               []string
+	Groups              []string
+}
+
 type RBAC struct {
 	Name   string
 	Domain string
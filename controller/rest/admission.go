@@ -1302,6 +1302,51 @@ func handlerGetAdmissionTest(w http.ResponseWriter, r *http.Request, ps httprout
 	}
 }
 
+func exportAdmCtrlConfig(acc *access.AccessControl) (*resource.NvSecurityAdmCtrlConfig, error) {
+	enable := false
+	mode := share.AdmCtrlModeProtect
+	admClientMode := share.AdmClientModeSvc
+	if k8sPlatform {
+		state, err := cacher.GetAdmissionState(acc)
+		if err != nil {
+			return nil, err
+		}
+		enable = *state.Enable
+		mode = *state.Mode
+		admClientMode = *state.AdmClientMode
+	}
+	cfg := &resource.NvSecurityAdmCtrlConfig{
+		Enable:        &enable,
+		Mode:          &mode,
+		AdmClientMode: &admClientMode,
+	}
+	return cfg, nil
+}
+
+func exportAdmCtrlRule(rule *api.RESTAdmissionRule) *resource.NvSecurityAdmCtrlRule {
+	actionAllow := api.ValidatingAllowRuleType
+	actionDeny := api.ValidatingDenyRuleType
+	action := &actionDeny
+	if rule.RuleType == api.ValidatingExceptRuleType || rule.RuleType == share.FedAdmCtrlExceptRulesType {
+		action = &actionAllow
+	}
+	ruleItem := &resource.NvSecurityAdmCtrlRule{
+		Action:   action,
+		Criteria: rule.Criteria,
+	}
+	if rule.Critical {
+		ruleItem.ID = &rule.ID
+		ruleItem.Disabled = &rule.Disable
+	}
+	if *ruleItem.Action == actionDeny {
+		ruleItem.RuleMode = &rule.RuleMode
+	}
+	if rule.Comment != "" {
+		ruleItem.Comment = &rule.Comment
+	}
+	return ruleItem
+}
+
 func handlerAdmCtrlExport(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
 	defer r.Body.Close()
@@ -1333,25 +1378,11 @@ func handlerAdmCtrlExport(w http.ResponseWriter, r *http.Request, ps httprouter.
 		Spec: resource.NvSecurityAdmCtrlSpec{},
 	}
 
-	enable := false
-	mode := share.AdmCtrlModeProtect
-	admClientMode := share.AdmClientModeSvc
 	if rconf.ExportConfig {
 		// export admission control config
-		if k8sPlatform {
-			state, err := cacher.GetAdmissionState(acc)
-			if err != nil {
-				restRespNotFoundLogAccessDenied(w, login, err)
-				return
-			}
-			enable = *state.Enable
-			mode = *state.Mode
-			admClientMode = *state.AdmClientMode
-		}
-		resp.Spec.Config = &resource.NvSecurityAdmCtrlConfig{
-			Enable:        &enable,
-			Mode:          &mode,
-			AdmClientMode: &admClientMode,
+		if resp.Spec.Config, err = exportAdmCtrlConfig(acc); err != nil {
+			restRespNotFoundLogAccessDenied(w, login, err)
+			return
 		}
 	}
 
@@ -1364,8 +1395,6 @@ func handlerAdmCtrlExport(w http.ResponseWriter, r *http.Request, ps httprouter.
 		defer clusHelper.ReleaseLock(lock)
 
 		var admissionRules []*resource.NvSecurityAdmCtrlRule
-		actionAllow := api.ValidatingAllowRuleType
-		actionDeny := api.ValidatingDenyRuleType
 		// export selected admission control rules
 		var ids utils.Set = utils.NewSet()
 		admissionRules = make([]*resource.NvSecurityAdmCtrlRule, 0, len(rconf.IDs))
@@ -1378,25 +1407,7 @@ func handlerAdmCtrlExport(w http.ResponseWriter, r *http.Request, ps httprouter.
 				restRespNotFoundLogAccessDenied(w, login, err)
 				return
 			}
-			action := &actionDeny
-			if rule.RuleType == api.ValidatingExceptRuleType || rule.RuleType == share.FedAdmCtrlExceptRulesType {
-				action = &actionAllow
-			}
-			ruleItem := resource.NvSecurityAdmCtrlRule{
-				Action:   action,
-				Criteria: rule.Criteria,
-			}
-			if rule.Critical {
-				ruleItem.ID = &rule.ID
-				ruleItem.Disabled = &rule.Disable
-			}
-			if *ruleItem.Action == actionDeny {
-				ruleItem.RuleMode = &rule.RuleMode
-			}
-			if rule.Comment != "" {
-				ruleItem.Comment = &rule.Comment
-			}
-			admissionRules = append(admissionRules, &ruleItem)
+			admissionRules = append(admissionRules, exportAdmCtrlRule(rule))
 			ids.Add(id)
 		}
 		resp.Spec.Rules = admissionRules
@@ -45,6 +45,7 @@ import (
 	"github.com/neuvector/neuvector/controller/rpc"
 	"github.com/neuvector/neuvector/share"
 	"github.com/neuvector/neuvector/share/global"
+	"github.com/neuvector/neuvector/share/otel"
 	"github.com/neuvector/neuvector/share/scan/secrets"
 	"github.com/neuvector/neuvector/share/utils"
 )
@@ -974,6 +975,9 @@ func cacheAdmCtrlAudit(auditId share.TLogAudit, result *nvsysadmission.AdmResult
 		alog.Props[nvsysadmission.AuditLogPropMessage] = result.Msg
 		alog.Props[nvsysadmission.AuditLogPropUser] = result.User
 		alog.Props[nvsysadmission.AuditLogPropFirstLogAt] = api.RESTTimeString(alog.ReportedAt)
+		if result.RuleID != 0 {
+			alog.Props[nvsysadmission.AuditLogPropRuleID] = strconv.FormatUint(uint64(result.RuleID), 10)
+		}
 
 		if auditId == share.CLUSAuditAdmCtrlK8sReqDenied && len(admResObject.OwnerUIDs) > 0 {
 			_, alog = aggregateDenyLogs(result, admResObject.OwnerUIDs[0], alog)
@@ -1424,9 +1428,23 @@ func (whsvr *WebhookServer) serveWithTimeStamps(w http.ResponseWriter, r *http.R
 func (whsvr *WebhookServer) serve(w http.ResponseWriter, r *http.Request) {
 	var stamps api.AdmCtlTimeStamps
 
+	// kube-apiserver propagates its trace to the webhooks when its tracing is enabled
+	ctx := otel.ContextWithTraceParent(r.Context(), r.Header.Get(otel.TraceParentHeader))
+	_, span := otel.StartSpan(ctx, "admission.review", otel.SpanKindServer)
+
 	stamps.Start = time.Now()
 	whsvr.serveWithTimeStamps(w, r, &stamps)
 	diff := time.Now().Sub(stamps.Start)
+
+	span.SetAttribute("http.route", r.URL.Path)
+	if stamps.Image != "" {
+		span.SetAttribute("image", stamps.Image)
+	}
+	if !stamps.Fetched.IsZero() {
+		span.SetAttribute("image.fetch.seconds", stamps.Fetched.Sub(stamps.GonnaFetch).Seconds())
+	}
+	span.End()
+	otel.RecordDuration("neuvector.admission.duration", diff)
 	if diff.Seconds() >= 28 {
 		log.WithFields(log.Fields{"image": stamps.Image, "seconds": diff.Seconds(),
 			"fetch": stamps.Fetched.Sub(stamps.GonnaFetch).Seconds()}).Warn("unexpected")
@@ -1475,6 +1493,9 @@ func restartWebhookServer(svcName string) error {
 		if nvAdmName, ok := k8sInfo[svcName]; ok {
 			cacher.SyncAdmCtrlStateToK8s(svcName, nvAdmName)
 		}
+		if svcName == resource.NvCrdSvcName {
+			syncCrdConversion()
+		}
 	}
 
 	var whsvr *WebhookServer
@@ -1588,6 +1609,9 @@ func k8sWebhookRestServer(svcName string, port uint, clientAuth, debug bool) {
 			}
 		}
 	}
+	if svcName == resource.NvCrdSvcName {
+		mux.HandleFunc(admission.UriCrdConvert, whsvr.crdconvert)
+	}
 	whsvr.server.Handler = mux
 
 	// start webhook server in new routine
@@ -0,0 +1,268 @@
+package rest
+
+// External gRPC API. Each call is served by the REST handlers, so it is authenticated,
+// authorized, rate limited and logged exactly as the equivalent REST requests.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/cluster"
+)
+
+const apiGRPCTokenKey = "x-auth-token"
+const apiGRPCApikeyKey = "x-auth-apikey"
+
+type apiGRPCService struct {
+	handler http.Handler
+}
+
+type apiGRPCRespWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *apiGRPCRespWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *apiGRPCRespWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+func (w *apiGRPCRespWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func restStatus2GRPCCode(status int) codes.Code {
+	switch status {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized, http.StatusRequestTimeout:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusInternalServerError:
+		return codes.Internal
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Unknown
+	}
+}
+
+// Serve the call with the REST handler. The response is decoded into resp if the request succeeds.
+func (s *apiGRPCService) call(ctx context.Context, method, path string, data, resp interface{}) error {
+	var body []byte
+	if data != nil {
+		body, _ = json.Marshal(data)
+	}
+	r, err := http.NewRequest(method, path, bytes.NewReader(body))
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	r = r.WithContext(ctx)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(apiGRPCTokenKey); len(v) == 1 {
+			r.Header.Set(api.RESTTokenHeader, v[0])
+		}
+		if v := md.Get(apiGRPCApikeyKey); len(v) == 1 {
+			r.Header.Set(api.RESTAPIKeyHeader, v[0])
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		r.RemoteAddr = p.Addr.String()
+	}
+
+	w := &apiGRPCRespWriter{header: make(http.Header)}
+	s.handler.ServeHTTP(w, r)
+
+	if w.status != http.StatusOK {
+		var restErr api.RESTError
+		msg := http.StatusText(w.status)
+		if json.Unmarshal(w.body.Bytes(), &restErr) == nil && restErr.Message != "" {
+			msg = restErr.Message
+		}
+		return status.Error(restStatus2GRPCCode(w.status), msg)
+	}
+	if resp != nil {
+		if err := json.Unmarshal(w.body.Bytes(), resp); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+	return nil
+}
+
+func (s *apiGRPCService) StreamScanResults(filter *share.APIScanFilter, stream share.APIServiceV1_StreamScanResultsServer) error {
+	ctx := stream.Context()
+
+	path := "/v1/workload"
+	if filter.Domain != "" {
+		path = fmt.Sprintf("%s?%s%s=%s", path, api.FilterPrefix, api.FilterByDomain, url.QueryEscape(filter.Domain))
+	}
+	var wls api.RESTWorkloadsData
+	if err := s.call(ctx, http.MethodGet, path, nil, &wls); err != nil {
+		return err
+	}
+
+	for _, wl := range wls.Workloads {
+		if err := ctx.Err(); err != nil {
+			return status.Error(codes.Canceled, err.Error())
+		}
+
+		result := &share.APIWorkloadScanResult{
+			WorkloadID:   wl.ID,
+			WorkloadName: wl.Name,
+			Domain:       wl.Domain,
+			Image:        wl.Image,
+		}
+		if wl.ScanSummary != nil {
+			result.ScanStatus = wl.ScanSummary.Status
+			result.HighCount = int32(wl.ScanSummary.HighVuls)
+			result.MediumCount = int32(wl.ScanSummary.MedVuls)
+		}
+		if result.ScanStatus == api.ScanStatusFinished {
+			var report api.RESTScanReportData
+			err := s.call(ctx, http.MethodGet, fmt.Sprintf("/v1/scan/workload/%s", wl.ID), nil, &report)
+			if err != nil && status.Code(err) != codes.NotFound {
+				return err
+			}
+			// The workload can be removed after it's listed, or has no report
+			if err == nil && report.Report != nil {
+				result.Vulnerabilities = make([]*share.APIVulnerability, len(report.Report.Vuls))
+				for i, v := range report.Report.Vuls {
+					result.Vulnerabilities[i] = &share.APIVulnerability{
+						Name:           v.Name,
+						Severity:       v.Severity,
+						Score:          v.Score,
+						ScoreV3:        v.ScoreV3,
+						PackageName:    v.PackageName,
+						PackageVersion: v.PackageVersion,
+						FixedVersion:   v.FixedVersion,
+						Link:           v.Link,
+					}
+				}
+			}
+		}
+		if err := stream.Send(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *apiGRPCService) ApplyPolicyRules(ctx context.Context, batch *share.APIPolicyRuleBatch) (*share.APIPolicyRuleBatchResult, error) {
+	data := api.RESTPolicyRuleBatchData{Ops: make([]*api.RESTPolicyRuleBatchOp, 0, len(batch.Ops))}
+	for _, op := range batch.Ops {
+		rule := op.Rule
+		if rule == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Missing rule in %s operation", op.Op)
+		}
+		apps := rule.Applications
+		if apps == nil {
+			apps = []string{}
+		}
+
+		switch op.Op {
+		case api.BatchOpCreate:
+			data.Ops = append(data.Ops, &api.RESTPolicyRuleBatchOp{Op: op.Op, Rule: &api.RESTPolicyRule{
+				Comment: rule.Comment, From: rule.From, To: rule.To, Ports: rule.Ports,
+				Action: rule.Action, Applications: apps, Disable: rule.Disable,
+			}})
+		case api.BatchOpUpdate:
+			data.Ops = append(data.Ops, &api.RESTPolicyRuleBatchOp{Op: op.Op, Config: &api.RESTPolicyRuleConfig{
+				ID: rule.ID, Comment: &rule.Comment, From: &rule.From, To: &rule.To, Ports: &rule.Ports,
+				Action: &rule.Action, Applications: &apps, Disable: &rule.Disable,
+			}})
+		case api.BatchOpDelete:
+			data.Ops = append(data.Ops, &api.RESTPolicyRuleBatchOp{Op: op.Op, ID: rule.ID})
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "Unsupported operation %s", op.Op)
+		}
+	}
+
+	var resp api.RESTPolicyRuleBatchCreatedData
+	if err := s.call(ctx, http.MethodPatch, "/v1/policy/rules/batch", &data, &resp); err != nil {
+		return nil, err
+	}
+	return &share.APIPolicyRuleBatchResult{IDs: resp.IDs}, nil
+}
+
+func (s *apiGRPCService) StreamConversations(filter *share.APIConversationFilter, stream share.APIServiceV1_StreamConversationsServer) error {
+	ctx := stream.Context()
+
+	query := url.Values{}
+	if filter.Group != "" {
+		query.Set(api.FilterPrefix+api.FilterByGroup, filter.Group)
+	}
+	if filter.Domain != "" {
+		query.Set(api.FilterPrefix+api.FilterByDomain, filter.Domain)
+	}
+	path := "/v1/conversation"
+	if len(query) > 0 {
+		path = fmt.Sprintf("%s?%s", path, query.Encode())
+	}
+	var convers api.RESTConversationsData
+	if err := s.call(ctx, http.MethodGet, path, nil, &convers); err != nil {
+		return err
+	}
+
+	names := make(map[string]string, len(convers.Endpoints))
+	for _, ep := range convers.Endpoints {
+		names[ep.ID] = ep.DisplayName
+	}
+	for _, c := range convers.Convers {
+		if err := ctx.Err(); err != nil {
+			return status.Error(codes.Canceled, err.Error())
+		}
+
+		conver := &share.APIConversation{From: c.From, FromName: names[c.From], To: c.To, ToName: names[c.To]}
+		if c.RESTConversationReport != nil {
+			conver.Bytes = c.Bytes
+			conver.Sessions = c.Sessions
+			conver.Severity = c.Severity
+			conver.PolicyAction = c.PolicyAction
+			conver.Protocols = c.Protos
+			conver.Applications = c.Apps
+			conver.Ports = c.Ports
+		}
+		if err := stream.Send(conver); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startAPIGRPCServer(port uint, handler http.Handler) {
+	log.WithFields(log.Fields{"port": port}).Info("Start API gRPC server")
+
+	server, err := cluster.NewGRPCServerTLS(fmt.Sprintf(":%d", port), defaultSSLCertFile, defaultSSLKeyFile)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Fail to create API gRPC server")
+		return
+	}
+	share.RegisterAPIServiceV1Server(server.GetServer(), &apiGRPCService{handler: handler})
+	server.Start()
+}
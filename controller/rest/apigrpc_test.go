@@ -0,0 +1,129 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestAPIGRPCApplyPolicyRules(t *testing.T) {
+	preTest()
+
+	rule1 := share.CLUSPolicyRule{
+		ID: 100, From: "g1", To: "g2", Action: share.PolicyActionAllow,
+		Ports: api.PolicyPortAny, Applications: []uint32{}, CfgType: share.UserCreated,
+	}
+	groups := []*share.CLUSGroup{
+		&share.CLUSGroup{Name: "g1", CfgType: share.UserCreated},
+		&share.CLUSGroup{Name: "g2", CfgType: share.UserCreated},
+	}
+
+	var mockCluster kv.MockCluster
+	initKvAndCache(&mockCluster, []*share.CLUSPolicyRule{&rule1}, groups)
+
+	svc := &apiGRPCService{handler: router}
+	batch := &share.APIPolicyRuleBatch{Ops: []*share.APIPolicyRuleOp{
+		&share.APIPolicyRuleOp{Op: api.BatchOpUpdate, Rule: &share.APIPolicyRule{
+			ID: 100, From: "g1", To: "g2", Ports: api.PolicyPortAny, Action: share.PolicyActionDeny,
+		}},
+		&share.APIPolicyRuleOp{Op: api.BatchOpCreate, Rule: &share.APIPolicyRule{
+			From: "g2", To: "g1", Ports: api.PolicyPortAny, Action: share.PolicyActionAllow,
+		}},
+	}}
+
+	// Without token
+	if _, err := svc.ApplyPolicyRules(context.Background(), batch); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Unexpected error without token: %v", err)
+	}
+
+	login := mockLoginUser("admin", api.UserRoleAdmin, api.FedRoleNone, make(map[string][]string))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(apiGRPCTokenKey, login.token))
+
+	result, err := svc.ApplyPolicyRules(ctx, batch)
+	if err != nil {
+		t.Fatalf("Failed to apply rules: %v", err)
+	}
+	if len(result.IDs) != 1 {
+		t.Errorf("Unexpected created rules: %+v", result.IDs)
+	}
+	if r, _ := clusHelper.GetPolicyRule(100); r == nil || r.Action != share.PolicyActionDeny {
+		t.Errorf("Rule is not updated: %+v", r)
+	}
+
+	// Errors of REST handlers are converted
+	batch.Ops = []*share.APIPolicyRuleOp{&share.APIPolicyRuleOp{Op: api.BatchOpDelete, Rule: &share.APIPolicyRule{ID: 999}}}
+	if _, err = svc.ApplyPolicyRules(ctx, batch); status.Code(err) != codes.NotFound {
+		t.Errorf("Unexpected error for missing rule: %v", err)
+	}
+	batch.Ops = []*share.APIPolicyRuleOp{&share.APIPolicyRuleOp{Op: "move", Rule: &share.APIPolicyRule{ID: 100}}}
+	if _, err = svc.ApplyPolicyRules(ctx, batch); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Unexpected error for unsupported operation: %v", err)
+	}
+
+	login._logout()
+	postTest()
+}
+
+type mockScanResultStream struct {
+	grpc.ServerStream
+	results []*share.APIWorkloadScanResult
+}
+
+func (m *mockScanResultStream) Context() context.Context {
+	return context.Background()
+}
+
+func (m *mockScanResultStream) Send(result *share.APIWorkloadScanResult) error {
+	m.results = append(m.results, result)
+	return nil
+}
+
+func TestAPIGRPCStreamScanResults(t *testing.T) {
+	preTest()
+
+	summary := &api.RESTScanBrief{Status: api.ScanStatusFinished}
+	wls := api.RESTWorkloadsData{Workloads: []*api.RESTWorkload{
+		&api.RESTWorkload{RESTWorkloadBrief: api.RESTWorkloadBrief{ID: "wl1", ScanSummary: summary}},
+		&api.RESTWorkload{RESTWorkloadBrief: api.RESTWorkloadBrief{ID: "wl2", ScanSummary: summary}},
+		&api.RESTWorkload{RESTWorkloadBrief: api.RESTWorkloadBrief{ID: "wl3", ScanSummary: summary}},
+	}}
+	reports := map[string]*api.RESTScanReportData{
+		"/v1/scan/workload/wl1": &api.RESTScanReportData{},
+		"/v1/scan/workload/wl3": &api.RESTScanReportData{Report: &api.RESTScanReport{
+			Vuls: []*api.RESTVulnerability{&api.RESTVulnerability{Name: "CVE-2020-0001"}},
+		}},
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/workload" {
+			restRespSuccess(w, r, &wls, nil, nil, nil, "")
+		} else if report, ok := reports[r.URL.Path]; ok {
+			restRespSuccess(w, r, report, nil, nil, nil, "")
+		} else {
+			restRespError(w, http.StatusNotFound, api.RESTErrObjectNotFound)
+		}
+	})
+
+	// Workloads without report, or removed after listed, are still streamed
+	stream := &mockScanResultStream{}
+	svc := &apiGRPCService{handler: handler}
+	if err := svc.StreamScanResults(&share.APIScanFilter{}, stream); err != nil {
+		t.Fatalf("Failed to stream scan results: %v", err)
+	}
+	if len(stream.results) != 3 {
+		t.Fatalf("Unexpected scan results: %d", len(stream.results))
+	}
+	if len(stream.results[0].Vulnerabilities) != 0 || len(stream.results[2].Vulnerabilities) != 1 {
+		t.Errorf("Unexpected vulnerabilities: %+v", stream.results)
+	}
+
+	postTest()
+}
@@ -10,6 +10,8 @@ import (
 	"math"
 	mathRand "math/rand"
 	"net/http"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -46,6 +48,8 @@ type loginSession struct {
 	timer           *time.Timer
 	domainRoles     access.DomainRole // map: domain -> role
 	loginType       int               // 0=user (default), 1=apikey
+	apikeyScopes    []string          // only for apikey login. empty means no limitation other than the role
+	mfaEnroll       bool              // user must enroll MFA before the session can be used for other requests
 
 	nvPage string // could change in every request even in the same login session
 }
@@ -61,6 +65,7 @@ type tokenClaim struct {
 	MainSessionUser string            `json:"main_session_user"` // from fullname in master login token's claim. empty when the token is generated for local cluster login
 	Timeout         uint32            `json:"timeout"`
 	Roles           access.DomainRole `json:"roles"`
+	MFAEnroll       bool              `json:"mfa_enroll,omitempty"`
 	jwt.StandardClaims
 }
 
@@ -117,6 +122,11 @@ const _interactiveSessionID = ""
 const _rancherSessionPrefix = "rancher:"
 const _halfHourBefore = time.Duration(-30) * time.Minute
 
+const rancherProjectIDLabel = "field.cattle.io/projectId"
+
+// Rancher API and session tokens are in "token-{name}:{key}" format
+var rancherTokenRegex = regexp.MustCompile(`^token-[a-z0-9]+:[a-z0-9]+$`)
+
 const (
 	userOK = iota
 	userInvalidRequest
@@ -134,6 +144,8 @@ const (
 
 const loginTypeApikey int = 1
 
+const apikeyLastUsedUpdatePeriod int64 = 60 // in seconds. avoid writing to kv on every request made with the same apikey
+
 var rancherCookieCache = make(map[string]int64) // key is rancher cookie, value is seconds since the epoch(ValidUntil)
 var rancherCookieMutex sync.RWMutex
 
@@ -158,10 +170,11 @@ func newLoginSessionFromToken(token string, claims *tokenClaim, now time.Time) (
 		server:          claims.Server,
 		timeout:         claims.Timeout,
 		remote:          claims.Remote,
-		loginAt:         now,
+		loginAt:         time.Unix(claims.IssuedAt, 0).Add(-_halfHourBefore), // the token could be issued by other controllers
 		lastAt:          now,
 		eolAt:           time.Unix(claims.ExpiresAt, 0),
 		domainRoles:     claims.Roles,
+		mfaEnroll:       claims.MFAEnroll,
 	}
 	if rc := _registerLoginSession(s); rc != userOK {
 		updateFedLoginSession(s)
@@ -193,6 +206,7 @@ func newLoginSessionFromUser(user *share.CLUSUser, roles access.DomainRole, remo
 		lastAt:          now,
 		eolAt:           time.Unix(claims.ExpiresAt, 0),
 		domainRoles:     roles,
+		mfaEnroll:       claims.MFAEnroll,
 	}
 	// for federal login, give it longer timeout so it doesn't time out as easily as interactive login
 	if mainSessionID != _interactiveSessionID && !strings.HasPrefix(mainSessionID, _rancherSessionPrefix) {
@@ -213,6 +227,11 @@ func newLoginSessionFromUser(user *share.CLUSUser, roles access.DomainRole, remo
 
 // with userMutex locked when calling this
 func _registerLoginSession(login *loginSession) int {
+	if !_enforceMaxSessions(login) {
+		log.WithFields(log.Fields{"user": login.fullname}).Info("Session limit reached")
+		return userTooMany
+	}
+
 	if _, ok := loginUserAccounting[login.fullname]; ok {
 		if loginUserAccounting[login.fullname] == MaxPerDomainLoginUsers {
 			log.WithFields(log.Fields{"user": login.fullname}).Info("User login limit reached")
@@ -235,7 +254,7 @@ func _registerLoginSession(login *loginSession) int {
 			timeout = time.Duration(jwtImportStatusTokenLife)
 		}
 	} else {
-		timeout = getUserTimeout(login.timeout)
+		timeout = login.getIdleTimeout()
 	}
 	login.timer = time.AfterFunc(timeout, func() { login.expire() })
 	loginSessions[login.token] = login
@@ -392,11 +411,86 @@ func (s *loginSession) expire() {
 	s._expire()
 }
 
+// with userMutex locked when calling this
+func (s *loginSession) _revoke(msg string) {
+	log.WithFields(log.Fields{"id": s.id, "user": s.fullname}).Info(msg)
+	authLog(share.CLUSEvAuthLogout, s.fullname, s.remote, s.id, s.domainRoles, msg)
+	s._delete()
+}
+
+// Session policy of the global role in the active password profile. It only applies to interactive sessions.
+func (s *loginSession) getSessionPolicy() *share.CLUSSessionPolicy {
+	if s.loginType == loginTypeApikey || s.mainSessionID != _interactiveSessionID {
+		return nil
+	}
+	return getSessionPolicy(s.domainRoles[access.AccessDomainGlobal])
+}
+
+func getSessionPolicy(role string) *share.CLUSSessionPolicy {
+	profile, _ := cacher.GetPwdProfile(share.CLUSSysPwdProfileName)
+	for i, p := range profile.SessionPolicies {
+		if p.Role == role {
+			return &profile.SessionPolicies[i]
+		}
+	}
+	return nil
+}
+
+// Session idle timeout, limited by the session policy
+func (s *loginSession) getIdleTimeout() time.Duration {
+	timeout := getUserTimeout(s.timeout)
+	if p := s.getSessionPolicy(); p != nil && p.IdleTimeout > 0 {
+		if t := getUserTimeout(p.IdleTimeout); t < timeout {
+			timeout = t
+		}
+	}
+	return timeout
+}
+
+// Policy is checked on every request so its change applies to the existing sessions as well
+func (s *loginSession) isLifetimeExceeded(now time.Time) bool {
+	if p := s.getSessionPolicy(); p != nil && p.MaxLifetime > 0 {
+		return now.Sub(s.loginAt) >= getUserTimeout(p.MaxLifetime)
+	}
+	return false
+}
+
+// Revoke the oldest sessions of the user if the new session exceeds the limit of the session policy.
+// Return false if the new session itself is the oldest one. Only sessions known by this controller are counted,
+// revoked tokens are rejected by other controllers as well. With userMutex locked when calling this
+func _enforceMaxSessions(login *loginSession) bool {
+	p := login.getSessionPolicy()
+	if p == nil || p.MaxSessions <= 0 {
+		return true
+	}
+
+	sessions := []*loginSession{login}
+	for _, s := range loginSessions {
+		if s != login && s.fullname == login.fullname && s.getSessionPolicy() != nil {
+			sessions = append(sessions, s)
+		}
+	}
+	if len(sessions) <= p.MaxSessions {
+		return true
+	}
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].loginAt.Before(sessions[j].loginAt) })
+	msg := fmt.Sprintf("User %s session is revoked because of the limit of %d concurrent sessions", login.fullname, p.MaxSessions)
+	for _, s := range sessions[:len(sessions)-p.MaxSessions] {
+		if s == login {
+			_deleteSessionToken(s)
+			return false
+		}
+		s._revoke(msg)
+	}
+	return true
+}
+
 // with userMutex locked when calling this
 func (s *loginSession) _updateTimeout(tmo uint32) {
 	s.timeout = tmo
 	elapsed := time.Since(s.lastAt)
-	timeout := getUserTimeout(tmo)
+	timeout := s.getIdleTimeout()
 	if elapsed >= timeout {
 		// Timeout
 		s._expire()
@@ -470,7 +564,9 @@ func checkRancherUserRole(cfg *api.RESTSystemConfig, rsessToken string, acc *acc
 					var rancherPrincipals api.PrincipalCollection
 					if err = json.Unmarshal(data, &rancherPrincipals); err == nil {
 						rancherUser.valid = true
+						allPrincipalIDs := utils.NewSet()
 						for _, p := range rancherPrincipals.Data {
+							allPrincipalIDs.Add(p.ID)
 							var pid string
 							var subType uint8 = resource.SUBJECT_USER
 							if principalIDs.Contains(p.ID) {
@@ -507,6 +603,18 @@ func checkRancherUserRole(cfg *api.RESTSystemConfig, rsessToken string, acc *acc
 								log.WithFields(log.Fields{"id": pid, "subType": subType}).Debug("no deduced role")
 							}
 						}
+						if len(cfg.RancherProjectRoles) > 0 {
+							if projectRoles := getRancherProjectDomainRoles(cfg, cookie, rancherUser.id, allPrincipalIDs, acc); len(projectRoles) > 0 {
+								if domainRoles == nil {
+									domainRoles = make(map[string]string, len(projectRoles))
+								}
+								for d, rNew := range projectRoles {
+									if rOld, ok := domainRoles[d]; !ok || rOld == api.UserRoleNone || (rOld == api.UserRoleReader && rNew != api.UserRoleNone) {
+										domainRoles[d] = rNew
+									}
+								}
+							}
+						}
 						if domainRoles != nil {
 							if role, ok := domainRoles[""]; ok {
 								if role == api.UserRoleFedAdmin || role == api.UserRoleAdmin {
@@ -540,6 +648,70 @@ func checkRancherUserRole(cfg *api.RESTSystemConfig, rsessToken string, acc *acc
 	return rancherUser, err
 }
 
+// Rancher project role bindings of the user or the user's groups are mapped to the roles of the namespaces in the projects
+func getRancherProjectDomainRoles(cfg *api.RESTSystemConfig, cookie *http.Cookie, userID string, principalIDs utils.Set,
+	acc *access.AccessControl) map[string]string {
+	urlStr := fmt.Sprintf("%s/v3/projectroletemplatebindings", cfg.RancherEP)
+	data, _, _, err := sendRestRequest("rancher", http.MethodGet, urlStr, "", "", "", "", cookie, []byte{}, true, nil, acc)
+	if err == nil {
+		var bindings api.ProjectRoleTemplateBindingCollection
+		if err = json.Unmarshal(data, &bindings); err == nil {
+			domains, _ := cacher.GetAllDomains(access.NewReaderAccessControl())
+			return rancherProjectDomainRoles(bindings.Data, userID, principalIDs, cfg.RancherProjectRoles, domains)
+		}
+	}
+	log.WithFields(log.Fields{"url": urlStr, "err": err}).Error()
+	return nil
+}
+
+func rancherProjectDomainRoles(bindings []api.ProjectRoleTemplateBinding, userID string, principalIDs utils.Set,
+	projectRoles map[string]string, domains []*api.RESTDomain) map[string]string {
+	// project id is in "{cluster id}:{project id}" format, namespaces are labeled with the project id only
+	projects := make(map[string]string)
+	for _, b := range bindings {
+		if b.UserID != userID && !principalIDs.Contains(b.UserPrincipalID) && !principalIDs.Contains(b.GroupPrincipalID) {
+			continue
+		}
+		role, ok := projectRoles[b.RoleTemplateID]
+		if !ok {
+			continue
+		}
+		project := b.ProjectID
+		if i := strings.Index(project, ":"); i >= 0 {
+			project = project[i+1:]
+		}
+		if project == "" {
+			continue
+		}
+		if old, ok := projects[project]; !ok || old == api.UserRoleNone || (old == api.UserRoleReader && role != api.UserRoleNone) {
+			projects[project] = role
+		}
+	}
+
+	domainRoles := make(map[string]string)
+	if len(projects) > 0 {
+		for _, d := range domains {
+			if role, ok := projects[d.Labels[rancherProjectIDLabel]]; ok {
+				domainRoles[d.Name] = role
+			}
+		}
+	}
+	return domainRoles
+}
+
+// Return the apikey account if the "name:secret" key matches it
+func verifyApikey(key string) (*share.CLUSApikey, uint64) {
+	parts := strings.Split(key, ":")
+	if len(parts) != 2 {
+		return nil, 0
+	}
+	apikeyAccount, rev, _ := clusHelper.GetApikeyRev(parts[0], access.NewReaderAccessControl())
+	if apikeyAccount == nil || utils.HashPassword(parts[1]) != apikeyAccount.SecretKeyHash {
+		return nil, 0
+	}
+	return apikeyAccount, rev
+}
+
 // with userMutex locked when calling this
 func restReq2User(r *http.Request) (*loginSession, int, string) {
 	var rsessToken string
@@ -556,20 +728,7 @@ func restReq2User(r *http.Request) (*loginSession, int, string) {
 		if !ok2 || len(apikey) != 1 {
 			return nil, userInvalidRequest, rsessToken
 		} else {
-			parts := strings.Split(apikey[0], ":")
-			if len(parts) == 2 {
-				apikeyAccount, _, _ := clusHelper.GetApikeyRev(parts[0], access.NewReaderAccessControl())
-
-				if apikeyAccount == nil {
-					return nil, userInvalidRequest, rsessToken
-				}
-
-				// check password
-				hash := utils.HashPassword(parts[1])
-				if hash != apikeyAccount.SecretKeyHash {
-					return nil, userInvalidRequest, rsessToken
-				}
-
+			if apikeyAccount, rev := verifyApikey(apikey[0]); apikeyAccount != nil {
 				// check timeout
 				now := time.Now()
 				if now.UTC().Unix() >= apikeyAccount.ExpirationTimestamp {
@@ -589,12 +748,23 @@ func restReq2User(r *http.Request) (*loginSession, int, string) {
 					return nil, userInvalidRequest, rsessToken
 				}
 
+				if nowUnix := now.UTC().Unix(); nowUnix-apikeyAccount.LastUsedTimestamp >= apikeyLastUsedUpdatePeriod {
+					apikeyAccount.LastUsedTimestamp = nowUnix
+					go func() {
+						// it's fine to fail when the apikey is being modified by others
+						if err := clusHelper.PutApikeyRev(apikeyAccount, rev); err != nil {
+							log.WithFields(log.Fields{"apikey": apikeyAccount.Name, "error": err}).Debug("Failed to update last used time")
+						}
+					}()
+				}
+
 				s := &loginSession{
-					id:          utils.GetRandomID(idLength, ""),
-					fullname:    apikeyAccount.Name,
-					remote:      r.RemoteAddr,
-					domainRoles: roles,
-					loginType:   loginTypeApikey,
+					id:           utils.GetRandomID(idLength, ""),
+					fullname:     apikeyAccount.Name,
+					remote:       r.RemoteAddr,
+					domainRoles:  roles,
+					loginType:    loginTypeApikey,
+					apikeyScopes: apikeyAccount.Scopes,
 				}
 
 				return s, userOK, rsessToken
@@ -696,9 +866,15 @@ func restReq2User(r *http.Request) (*loginSession, int, string) {
 		}
 	}
 
+	if login.isLifetimeExceeded(now) {
+		log.WithFields(log.Fields{"id": login.id, "user": login.fullname}).Info("Session lifetime exceeded")
+		login._expire()
+		return nil, userTimeout, rsessToken
+	}
+
 	// For auth token generated for IBM SA setup, it has a fixed 30 minutes time-out
 	if role, _ := login.domainRoles[access.AccessDomainGlobal]; role != api.UserRoleIBMSA && role != api.UserRoleImportStatus {
-		login.timer.Reset(getUserTimeout(login.timeout))
+		login.timer.Reset(login.getIdleTimeout())
 		login.lastAt = now
 		// on other controllers, if the token is already known, its login session timer is ticking based on the last request to other controllers.
 		// simply reset the login session timer on this controller cannot prevent the timer on other controllers from calling expire().
@@ -733,6 +909,20 @@ func restReq2User(r *http.Request) (*loginSession, int, string) {
 	return login, userOK, rsessToken
 }
 
+// Whether the login session of a long-lived request, like log stream, is still valid. It doesn't reset the session timer
+func isLoginSessionAlive(r *http.Request, login *loginSession) bool {
+	userMutex.Lock()
+	defer userMutex.Unlock()
+
+	if login.loginType == loginTypeApikey {
+		// apikey could be deleted or expired
+		_, rc, _ := restReq2User(r)
+		return rc == userOK
+	}
+	s, ok := loginSessions[login.token]
+	return ok && s == login
+}
+
 // op is derived by HTTP request method, but the caller can overwrite it.
 func getAccessControl(w http.ResponseWriter, r *http.Request, op access.AccessOP) (*access.AccessControl, *loginSession) {
 	if op == "" {
@@ -771,7 +961,18 @@ func getAccessControl(w http.ResponseWriter, r *http.Request, op access.AccessOP
 		return nil, login
 	}
 
+	if login.mfaEnroll && !isMFAEnrollRequest(r, login) {
+		log.WithFields(log.Fields{"user": login.fullname, "URL": r.URL.String()}).Error("MFA enrollment is required")
+		restRespError(w, http.StatusForbidden, api.RESTErrMFAEnrollRequired)
+		return nil, login
+	}
+
 	acc := access.NewAccessControl(r, op, login.domainRoles)
+	if login.loginType == loginTypeApikey && !acc.IsAllowedByApikeyScopes(login.apikeyScopes) {
+		log.WithFields(log.Fields{"apikey": login.fullname, "scopes": login.apikeyScopes, "URL": r.URL.String()}).Error("Not allowed by apikey scopes")
+		restRespAccessDenied(w, login)
+		return nil, login
+	}
 	return acc, login
 }
 
@@ -1087,7 +1288,7 @@ func ResetLoginTokenTimer(tokenInfo *share.CLUSLoginTokenInfo) {
 		if login, ok := loginSessions[tokenInfo.LoginToken]; ok {
 			// if this controller does know this token, reset its timer.
 			if tokenInfo.LoginID == login.id {
-				login.timer.Reset(getUserTimeout(login.timeout))
+				login.timer.Reset(login.getIdleTimeout())
 				login.lastAt = time.Now()
 			}
 		} else {
@@ -1329,6 +1530,7 @@ func jwtGenerateToken(user *share.CLUSUser, roles access.DomainRole, remote, mai
 		MainSessionUser: mainSessionUser,
 		Timeout:         user.Timeout,
 		Roles:           roles,
+		MFAEnroll:       isMFAEnrollRequired(user),
 		StandardClaims: jwt.StandardClaims{
 			Id:        id,
 			Subject:   installID,
@@ -1336,6 +1538,11 @@ func jwtGenerateToken(user *share.CLUSUser, roles access.DomainRole, remote, mai
 			ExpiresAt: now.Add(jwtTokenLife).Unix(),
 		},
 	}
+	if mainSessionID == _interactiveSessionID {
+		if p := getSessionPolicy(roles[access.AccessDomainGlobal]); p != nil && p.MaxLifetime > 0 && getUserTimeout(p.MaxLifetime) < jwtTokenLife {
+			c.StandardClaims.ExpiresAt = now.Add(getUserTimeout(p.MaxLifetime)).Unix()
+		}
+	}
 	if r, ok := roles[access.AccessDomainGlobal]; ok && (r == api.UserRoleIBMSA || r == api.UserRoleImportStatus) && len(roles) == 1 {
 		if r == api.UserRoleIBMSA {
 			c.Timeout = uint32(30 * 60) // jwtIbmSaTokenLife, 30 minutes
@@ -1891,9 +2098,14 @@ func localPasswordAuth(pw *api.RESTAuthPassword, acc *access.AccessControl) (*sh
 			user.BlockLoginSince = time.Time{}
 		}
 
-		// Validate password
-		hash := utils.HashPassword(pw.Password)
-		if hash != user.PasswordHash {
+		// Validate password, and MFA code if it's given. Wrong MFA code is counted as failed login as well.
+		var authErr error
+		if hash := utils.HashPassword(pw.Password); hash != user.PasswordHash {
+			authErr = errors.New("Wrong password")
+		} else if user.MFAEnabled && pw.MFACode != "" && !verifyUserMFACode(user, pw.MFACode, now) {
+			authErr = errors.New("Wrong MFA code")
+		}
+		if authErr != nil {
 			userBlocked := false
 			if pwdProfile.EnableBlockAfterFailedLogin {
 				user.FailedLoginCount++
@@ -1905,7 +2117,7 @@ func localPasswordAuth(pw *api.RESTAuthPassword, acc *access.AccessControl) (*sh
 			if user.FailedLoginCount != origFailedLoginCount || user.BlockLoginSince != origBlockLoginSince {
 				clusHelper.PutUserRev(user, rev)
 			}
-			return nil, userBlocked, false, true, blockAfterFailedCount, errors.New("Wrong password")
+			return nil, userBlocked, false, true, blockAfterFailedCount, authErr
 		} else {
 			if pwdProfile.EnablePwdExpiration && pwdProfile.PwdExpireAfterDays > 0 && !user.PwdResetTime.IsZero() {
 				pwdValidUnit := _pwdValidUnit
@@ -1920,6 +2132,11 @@ func localPasswordAuth(pw *api.RESTAuthPassword, acc *access.AccessControl) (*sh
 			}
 		}
 
+		// Ask for the MFA code after the password is verified
+		if user.MFAEnabled && pw.MFACode == "" {
+			return nil, false, false, true, blockAfterFailedCount, errMFARequired
+		}
+
 		user.LoginCount++
 		if now.After(user.LastLoginAt) {
 			user.LastLoginAt = now
@@ -2155,6 +2372,12 @@ func handlerAuthLogin(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 		}
 
 		if user == nil {
+			if userFound && (errLocalAuth == errMFARequired || err == errMFARequired) {
+				log.WithFields(log.Fields{"user": auth.Password.Username}).Info("MFA code is required")
+				restRespError(w, http.StatusUnauthorized, api.RESTErrMFARequired)
+				return
+			}
+
 			code := api.RESTErrUnauthorized
 			var ev share.TLogEvent = share.CLUSEvAuthLoginFailed
 			var msg string
@@ -2253,6 +2476,7 @@ func handlerAuthLogin(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 				RoleDomains: user.RoleDomains,
 			},
 		},
+		MFAEnrollRequired: login.mfaEnroll,
 	}
 
 	if cacheRancherCookie {
@@ -2385,6 +2609,23 @@ func handlerAuthLoginServer(w http.ResponseWriter, r *http.Request, ps httproute
 
 	server := ps.ByName("server")
 
+	// Rancher-issued tokens are validated by the Rancher server as the Rancher SSO session
+	if server == api.AuthServerRancher {
+		if localDev.Host.Platform != share.PlatformKubernetes || localDev.Host.Flavor != share.FlavorRancher {
+			log.Error("Not a Rancher cluster")
+			restRespError(w, http.StatusUnauthorized, api.RESTErrPlatformAuthDisabled)
+			return
+		}
+		if data.Token == nil || !rancherTokenRegex.MatchString(data.Token.Token) {
+			log.Error("Invalid Rancher token")
+			restRespError(w, http.StatusUnauthorized, api.RESTErrUnauthorized)
+			return
+		}
+		r.Header.Set(api.RESTRancherTokenHeader, data.Token.Token)
+		handlerAuthLogin(w, r, ps)
+		return
+	}
+
 	remote := data.ClientIP
 	if remote == "" {
 		remote = r.RemoteAddr
@@ -2398,6 +2639,7 @@ func handlerAuthLoginServer(w http.ResponseWriter, r *http.Request, ps httproute
 	var user *share.CLUSUser
 	var defaultPW bool
 	var localAuthed bool
+	var refreshToken string
 
 	if data.Password != nil {
 		if len(data.Password.Password) == 0 {
@@ -2429,7 +2671,11 @@ func handlerAuthLoginServer(w http.ResponseWriter, r *http.Request, ps httproute
 			user, err = remotePasswordAuth(cs, data.Password)
 		}
 
-		if err != nil {
+		if err == errMFARequired {
+			log.WithFields(log.Fields{"server": server, "user": data.Password.Username}).Info("MFA code is required")
+			restRespError(w, http.StatusUnauthorized, api.RESTErrMFARequired)
+			return
+		} else if err != nil {
 			log.WithFields(log.Fields{"server": server, "user": data.Password.Username, "blockedLogin": blockedForFailedLogin, "blockedPwd": blockedForExpiredPwd, "error": err}).Error("User login failed")
 			code := api.RESTErrUnauthorized
 			var ev share.TLogEvent = share.CLUSEvAuthLoginFailed
@@ -2490,7 +2736,14 @@ func handlerAuthLoginServer(w http.ResponseWriter, r *http.Request, ps httproute
 				return
 			}
 		} else if cs.OIDC != nil {
-			claims, err := remoteAuther.OIDCAuth(cs.OIDC, data.Token)
+			if data.Token.RefreshToken != "" && !cs.OIDC.RefreshLogin {
+				log.WithFields(log.Fields{"server": server}).Error("Login with refresh token is not enabled")
+				authLog(share.CLUSEvAuthLoginFailed, "", remote, "", nil, "")
+				restRespError(w, http.StatusUnauthorized, api.RESTErrUnauthorized)
+				return
+			}
+
+			claims, token, err := remoteAuther.OIDCAuth(cs.OIDC, data.Token)
 			if err != nil || claims == nil {
 				log.WithFields(log.Fields{"server": server, "error": err}).Error("User login failed")
 				authLog(share.CLUSEvAuthLoginFailed, "", remote, "", nil, "")
@@ -2513,6 +2766,9 @@ func handlerAuthLoginServer(w http.ResponseWriter, r *http.Request, ps httproute
 				restRespError(w, http.StatusUnauthorized, api.RESTErrUnauthorized)
 				return
 			}
+			if cs.OIDC.RefreshLogin {
+				refreshToken = token
+			}
 		} else {
 			log.WithFields(log.Fields{"server": server}).Error("Unsupported server type")
 			restRespError(w, http.StatusUnauthorized, api.RESTErrUnauthorized)
@@ -2560,6 +2816,8 @@ func handlerAuthLoginServer(w http.ResponseWriter, r *http.Request, ps httproute
 		},
 		PwdDaysUntilExpire:  pwdDaysUntilExpire,
 		PwdHoursUntilExpire: pwdHoursUntilExpire,
+		RefreshToken:        refreshToken,
+		MFAEnrollRequired:   login.mfaEnroll,
 	}
 	resp.Token.GlobalPermits, resp.Token.DomainPermits, err = access.GetDomainPermissions(user.Role, user.RoleDomains)
 	if err != nil {
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/neuvector/neuvector/controller/access"
 	"github.com/neuvector/neuvector/controller/api"
@@ -27,7 +29,8 @@ type samlUser struct {
 }
 
 type oidcUser struct {
-	claims map[string]interface{}
+	claims       map[string]interface{}
+	refreshToken string
 }
 
 type mockRemoteAuth struct {
@@ -48,7 +51,7 @@ func (a *mockRemoteAuth) addSAMLUser(token string, attrs map[string][]string) {
 }
 
 func (a *mockRemoteAuth) addOIDCUser(token string, claims map[string]interface{}) {
-	a.oidcUsers[token] = &oidcUser{claims: claims}
+	a.oidcUsers[token] = &oidcUser{claims: claims, refreshToken: "refresh-" + token}
 }
 
 func (a *mockRemoteAuth) LDAPAuth(ldap *share.CLUSServerLDAP, username, password string) (map[string]string, []string, error) {
@@ -73,12 +76,17 @@ func (a *mockRemoteAuth) OIDCDiscover(issuer string) (string, string, string, st
 	return "", "", "", "", nil
 }
 
-func (a *mockRemoteAuth) OIDCAuth(coidc *share.CLUSServerOIDC, tokenData *api.RESTAuthToken) (map[string]interface{}, error) {
-	if user, ok := a.oidcUsers[tokenData.Token]; ok {
-		return user.claims, nil
-	} else {
-		return nil, errors.New("Authentication failed")
+func (a *mockRemoteAuth) OIDCAuth(coidc *share.CLUSServerOIDC, tokenData *api.RESTAuthToken) (map[string]interface{}, string, error) {
+	if tokenData.RefreshToken != "" {
+		for _, user := range a.oidcUsers {
+			if user.refreshToken == tokenData.RefreshToken {
+				return user.claims, user.refreshToken, nil
+			}
+		}
+	} else if user, ok := a.oidcUsers[tokenData.Token]; ok {
+		return user.claims, user.refreshToken, nil
 	}
+	return nil, "", errors.New("Authentication failed")
 }
 
 func makeLocalUser(username, password, role string) *share.CLUSUser {
@@ -163,6 +171,81 @@ func TestLocalLogin(t *testing.T) {
 	postTest()
 }
 
+func TestSessionPolicy(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+
+	user := makeLocalUser("user", "pass", api.UserRoleReader)
+	clusHelper.CreateUser(user)
+
+	profile := share.CLUSPwdProfile{
+		Name: share.CLUSDefPwdProfileName,
+		SessionPolicies: []share.CLUSSessionPolicy{
+			share.CLUSSessionPolicy{Role: api.UserRoleReader, IdleTimeout: 60, MaxLifetime: 3600, MaxSessions: 2},
+		},
+	}
+	mc := &mockCache{}
+	mc.PutPwdProfiles(share.CLUSDefPwdProfileName, map[string]*share.CLUSPwdProfile{share.CLUSSysPwdProfileName: &profile})
+	cacher = mc
+
+	tokens := make([]string, 3)
+	for i := range tokens {
+		w := login("user", "pass")
+		if w.status != http.StatusOK {
+			t.Fatalf("Failed to login user: status=%v.", w.status)
+		}
+		tokens[i] = getLoginToken(w)
+		// Make sure the sessions can be ordered by login time
+		if s, ok := loginSessions[tokens[i]]; ok {
+			s.loginAt = s.loginAt.Add(time.Duration(i-len(tokens)) * time.Second)
+		}
+	}
+
+	// The oldest session is revoked
+	if _, ok := loginSessions[tokens[0]]; ok {
+		t.Errorf("The oldest session should be revoked")
+	}
+	if n := loginUserAccounting["user"]; n != 2 {
+		t.Errorf("Unexpected session count: %v", n)
+	}
+
+	// Idle timeout and lifetime are limited by the policy
+	s := loginSessions[tokens[2]]
+	if s == nil {
+		t.Fatalf("Failed to find the session")
+	}
+	if timeout := s.getIdleTimeout(); timeout != time.Minute {
+		t.Errorf("Unexpected idle timeout: %v", timeout)
+	}
+	if lifetime := s.eolAt.Sub(s.loginAt); lifetime > time.Hour+time.Minute {
+		t.Errorf("Token lifetime is not limited: %v", lifetime)
+	}
+	if w := restCallToken("GET", "/v1/selfuser", nil, tokens[2]); w.status != http.StatusOK {
+		t.Errorf("Failed to use the session: status=%v.", w.status)
+	}
+	s.loginAt = s.loginAt.Add(-time.Hour)
+	if w := restCallToken("GET", "/v1/selfuser", nil, tokens[2]); w.status != http.StatusRequestTimeout {
+		t.Errorf("Session should expire after max lifetime: status=%v.", w.status)
+	}
+
+	// Other roles are not limited
+	clusHelper.CreateUser(makeLocalUser("admin1", "pass", api.UserRoleAdmin))
+	w := login("admin1", "pass")
+	if s := loginSessions[getLoginToken(w)]; s == nil || s.getIdleTimeout() != getUserTimeout(common.DefaultIdleTimeout) {
+		t.Errorf("Unexpected session of the role without policy: %+v", s)
+	}
+
+	for _, token := range tokens {
+		logout(token)
+	}
+	logout(getLoginToken(w))
+
+	postTest()
+}
+
 func TestLDAPLogin(t *testing.T) {
 	preTest()
 
@@ -884,6 +967,82 @@ func TestOIDCLogin(t *testing.T) {
 	postTest()
 }
 
+func TestOIDCRefreshLogin(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	initKvAndCache(&mockCluster, nil, nil)
+
+	oidc := share.CLUSServer{
+		Name: "oidc1", Enable: true,
+		OIDC: &share.CLUSServerOIDC{
+			CLUSServerAuth: share.CLUSServerAuth{
+				DefaultRole: api.UserRoleReader,
+				GroupMappedRoles: []*share.GroupRoleMapping{
+					&share.GroupRoleMapping{
+						Group:       "group1",
+						GlobalRole:  api.UserRoleNone,
+						RoleDomains: map[string][]string{api.UserRoleAdmin: []string{"ns1"}},
+					},
+				},
+			},
+			Issuer: "issuer",
+		},
+	}
+	clusHelper.PutServerRev(&oidc, 0)
+
+	mockAuther := mockRemoteAuth{oidcUsers: make(map[string]*oidcUser)}
+	mockAuther.addOIDCUser("token", map[string]interface{}{
+		oidcPreferredNameKey: "joe@example.com", oidcGroupKey: []interface{}{"group1"},
+	})
+	remoteAuther = &mockAuther
+
+	// Refresh token is not returned or accepted if not enabled
+	w := loginServerToken("token", "oidc1")
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to login user: status=%v.", w.status)
+	}
+	var resp api.RESTTokenData
+	json.Unmarshal(w.body, &resp)
+	if resp.RefreshToken != "" {
+		t.Errorf("Refresh token should not be returned: token=%v.", resp.RefreshToken)
+	}
+	logout(resp.Token.Token)
+
+	if w = loginServerRefreshToken("refresh-token", "oidc1"); w.status != http.StatusUnauthorized {
+		t.Errorf("Login should fail, refresh login not enabled: status=%v.", w.status)
+	}
+
+	oidc.OIDC.RefreshLogin = true
+	w = loginServerToken("token", "oidc1")
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to login user: status=%v.", w.status)
+	}
+	resp = api.RESTTokenData{}
+	json.Unmarshal(w.body, &resp)
+	if resp.RefreshToken != "refresh-token" {
+		t.Errorf("Unexpected refresh token: token=%v.", resp.RefreshToken)
+	}
+	logout(resp.Token.Token)
+
+	// Group claim is mapped to the domain role
+	if w = loginServerRefreshToken(resp.RefreshToken, "oidc1"); w.status != http.StatusOK {
+		t.Fatalf("Failed to login user with refresh token: status=%v.", w.status)
+	}
+	resp = api.RESTTokenData{}
+	json.Unmarshal(w.body, &resp)
+	if resp.Token.Role != api.UserRoleNone || len(resp.Token.RoleDomains[api.UserRoleAdmin]) != 1 {
+		t.Errorf("Unexpected user role: role=%v domains=%+v.", resp.Token.Role, resp.Token.RoleDomains)
+	}
+	logout(resp.Token.Token)
+
+	if w = loginServerRefreshToken("invalid", "oidc1"); w.status != http.StatusUnauthorized {
+		t.Errorf("Login should fail with invalid refresh token: status=%v.", w.status)
+	}
+
+	postTest()
+}
+
 /* TODO: Shadow User
 func TestOIDCLoginShadowUser(t *testing.T) {
 	preTest()
@@ -1049,3 +1208,76 @@ func TestGroupMapping(t *testing.T) {
 
 	postTest()
 }
+
+func TestRancherTokenLogin(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+	cacher = &mockCache{}
+
+	token := "token-x7v2q:abcdefghjklmnpqrstvwxz2456789"
+	w := loginServerToken(token, api.AuthServerRancher)
+	if w.status != http.StatusUnauthorized {
+		t.Errorf("Rancher login should fail in non-Rancher cluster: status=%v", w.status)
+	}
+
+	localDev.Host.Platform = share.PlatformKubernetes
+	localDev.Host.Flavor = share.FlavorRancher
+	for _, bad := range []string{"", "abc", "token-x7v2q", "token-x7v2q:", "token-x7v2q:abc def", "Bearer token-x7v2q:abc"} {
+		w = loginServerToken(bad, api.AuthServerRancher)
+		if w.status != http.StatusUnauthorized {
+			t.Errorf("Login with malformed token should fail: token=%s status=%v", bad, w.status)
+		}
+	}
+
+	// platform auth is not enabled, the token is not sent to Rancher
+	var resp api.RESTError
+	w = loginServerToken(token, api.AuthServerRancher)
+	json.Unmarshal(w.body, &resp)
+	if w.status != http.StatusUnauthorized || resp.Code != api.RESTErrPlatformAuthDisabled {
+		t.Errorf("Login should fail when platform auth is disabled: status=%v code=%v", w.status, resp.Code)
+	}
+
+	if !isReservedServerName(api.AuthServerRancher) {
+		t.Errorf("Rancher server name should be reserved")
+	}
+
+	postTest()
+}
+
+func TestRancherProjectDomainRoles(t *testing.T) {
+	bindings := []api.ProjectRoleTemplateBinding{
+		{ProjectID: "c-m-abc:p-dev", RoleTemplateID: "project-member", UserID: "u-1"},
+		{ProjectID: "c-m-abc:p-dev", RoleTemplateID: "project-owner", GroupPrincipalID: "github_team://42"},
+		{ProjectID: "c-m-abc:p-qa", RoleTemplateID: "read-only", UserPrincipalID: "local://u-1"},
+		{ProjectID: "c-m-abc:p-ops", RoleTemplateID: "project-owner", UserID: "u-2"},
+		{ProjectID: "c-m-abc:p-web", RoleTemplateID: "create-ns", UserID: "u-1"},
+	}
+	projectRoles := map[string]string{
+		"project-owner":  api.UserRoleAdmin,
+		"project-member": api.UserRoleReader,
+		"read-only":      api.UserRoleReader,
+	}
+	domains := []*api.RESTDomain{
+		{Name: "dev-1", Labels: map[string]string{rancherProjectIDLabel: "p-dev"}},
+		{Name: "dev-2", Labels: map[string]string{rancherProjectIDLabel: "p-dev"}},
+		{Name: "qa", Labels: map[string]string{rancherProjectIDLabel: "p-qa"}},
+		{Name: "ops", Labels: map[string]string{rancherProjectIDLabel: "p-ops"}},
+		{Name: "web", Labels: map[string]string{rancherProjectIDLabel: "p-web"}},
+		{Name: "default"},
+	}
+
+	principals := utils.NewSet("local://u-1", "github_team://42")
+	domainRoles := rancherProjectDomainRoles(bindings, "u-1", principals, projectRoles, domains)
+	expect := map[string]string{"dev-1": api.UserRoleAdmin, "dev-2": api.UserRoleAdmin, "qa": api.UserRoleReader}
+	if !reflect.DeepEqual(domainRoles, expect) {
+		t.Errorf("Unexpected domain roles: expect=%v actual=%v", expect, domainRoles)
+	}
+
+	domainRoles = rancherProjectDomainRoles(bindings, "u-3", utils.NewSet("local://u-3"), projectRoles, domains)
+	if len(domainRoles) != 0 {
+		t.Errorf("Unexpected domain roles: %v", domainRoles)
+	}
+}
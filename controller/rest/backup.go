@@ -0,0 +1,566 @@
+package rest
+
+// Scheduled configuration backup. The exported configuration is encrypted with a key derived from the passphrase
+// and uploaded to the object storage. The lead controller runs the backup in the configured interval and keeps the
+// latest backups in the storage according to the retention.
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+const (
+	backupCheckPeriod      = time.Minute * 10
+	backupRetryInterval    = time.Hour
+	backupMagic            = "NVBACKUP1"
+	backupSaltLen          = 16
+	backupKeyIterations    = 100000
+	backupNameFormat       = "nv-config-20060102-150405.nvbak"
+	backupMinPassphraseLen = 8
+)
+
+var backupRunning uint32
+var regBackupPrefix *regexp.Regexp = regexp.MustCompile(`^[a-zA-Z0-9._/-]*$`)
+var regBackupName *regexp.Regexp = regexp.MustCompile(`^nv-config-[0-9]{8}-[0-9]{6}\.nvbak$`)
+
+var errBackupNotConfigured = errors.New("Backup storage is not configured")
+var errBackupRunning = errors.New("Another backup is running")
+
+func backupKey(passphrase string, salt []byte) []byte {
+	// AES-256 key
+	return pbkdf2.Key([]byte(passphrase), salt, backupKeyIterations, 32, sha256.New)
+}
+
+// archive: magic | salt | nonce | AES-256-GCM sealed data
+func encryptBackup(data []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, backupSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(backupKey(passphrase, salt))
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	archive := make([]byte, 0, len(backupMagic)+len(salt)+len(nonce)+len(data)+gcm.Overhead())
+	archive = append(archive, backupMagic...)
+	archive = append(archive, salt...)
+	archive = append(archive, nonce...)
+	return gcm.Seal(archive, nonce, data, []byte(backupMagic)), nil
+}
+
+func decryptBackup(archive []byte, passphrase string) ([]byte, error) {
+	if !bytes.HasPrefix(archive, []byte(backupMagic)) || len(archive) < len(backupMagic)+backupSaltLen {
+		return nil, errors.New("Invalid backup format")
+	}
+	archive = archive[len(backupMagic):]
+	block, err := aes.NewCipher(backupKey(passphrase, archive[:backupSaltLen]))
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	archive = archive[backupSaltLen:]
+	if len(archive) < gcm.NonceSize() {
+		return nil, errors.New("Invalid backup format")
+	}
+	data, err := gcm.Open(nil, archive[:gcm.NonceSize()], archive[gcm.NonceSize():], []byte(backupMagic))
+	if err != nil {
+		return nil, errors.New("Failed to decrypt the backup, passphrase may be incorrect")
+	}
+	return data, nil
+}
+
+// Backup names include the creation time, so they are sorted by name from the oldest to the newest
+func listBackups(storage backupStorage) ([]*backupObject, error) {
+	objs, err := storage.list()
+	if err != nil {
+		return nil, err
+	}
+	backups := make([]*backupObject, 0, len(objs))
+	for _, obj := range objs {
+		if regBackupName.MatchString(obj.name) {
+			backups = append(backups, obj)
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].name < backups[j].name })
+	return backups, nil
+}
+
+func pruneBackups(storage backupStorage, retention uint32) {
+	if retention == 0 {
+		return
+	}
+	backups, err := listBackups(storage)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to list backups")
+		return
+	}
+	for i := 0; i < len(backups)-int(retention); i++ {
+		if err := storage.remove(backups[i].name); err != nil {
+			log.WithFields(log.Fields{"name": backups[i].name, "error": err}).Error("Failed to remove backup")
+		} else {
+			log.WithFields(log.Fields{"name": backups[i].name}).Info("Removed backup")
+		}
+	}
+}
+
+func exportBackupConfig() ([]byte, error) {
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	bufw := bufio.NewWriter(gzw)
+	sections := utils.NewSet(api.ConfSectionAll)
+	if err := cfgHelper.Export(bufw, sections); err != nil {
+		return nil, err
+	}
+	if err := bufw.Flush(); err != nil {
+		return nil, err
+	}
+	if err := gzw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func runConfigBackup(cfg *share.CLUSBackupConfig) (string, error) {
+	if cfg.Storage.Type == "" {
+		return "", errBackupNotConfigured
+	}
+	if kv.IsImporting() {
+		return "", errors.New("Configuration is being imported")
+	}
+	if !atomic.CompareAndSwapUint32(&backupRunning, 0, 1) {
+		return "", errBackupRunning
+	}
+	defer atomic.StoreUint32(&backupRunning, 0)
+
+	now := time.Now().UTC()
+	name := now.Format(backupNameFormat)
+	status := clusHelper.GetBackupStatus()
+	status.LastRunAt = now
+
+	err := func() error {
+		storage, err := newBackupStorage(&cfg.Storage)
+		if err != nil {
+			return err
+		}
+		data, err := exportBackupConfig()
+		if err != nil {
+			return err
+		}
+		if data, err = encryptBackup(data, cfg.Passphrase); err != nil {
+			return err
+		}
+		if err = storage.upload(name, data); err != nil {
+			return err
+		}
+		pruneBackups(storage, cfg.Retention)
+		return nil
+	}()
+	if err == nil {
+		status.LastBackupAt = now
+		status.LastBackup = name
+		status.LastError = ""
+		log.WithFields(log.Fields{"name": name}).Info("Configuration backed up")
+	} else {
+		status.LastError = err.Error()
+		log.WithFields(log.Fields{"error": err}).Error("Failed to backup configuration")
+	}
+	clusHelper.PutBackupStatus(status)
+
+	return name, err
+}
+
+func scheduledConfigBackup() {
+	cfg, _ := clusHelper.GetBackupConfigRev()
+	if !cfg.Enabled || cfg.Interval == 0 || cfg.Storage.Type == "" {
+		return
+	}
+
+	status := clusHelper.GetBackupStatus()
+	since := time.Since(status.LastRunAt)
+	if since < time.Duration(cfg.Interval)*time.Hour && (status.LastError == "" || since < backupRetryInterval) {
+		return
+	}
+
+	clog := share.CLUSEventLog{
+		Event:          share.CLUSEvConfigBackup,
+		HostID:         localDev.Host.ID,
+		HostName:       localDev.Host.Name,
+		ControllerID:   localDev.Ctrler.ID,
+		ControllerName: localDev.Ctrler.Name,
+	}
+	if name, err := runConfigBackup(cfg); err == nil {
+		clog.Msg = fmt.Sprintf("Configuration is backed up to %s", name)
+	} else if err == errBackupRunning {
+		return
+	} else {
+		clog.Event = share.CLUSEvConfigBackupFail
+		clog.Msg = fmt.Sprintf("Failed to backup configuration: %s", err.Error())
+	}
+	clog.ReportedAt = time.Now().UTC()
+	evqueue.Append(&clog)
+}
+
+func ConfigBackupProc() {
+	cSig := make(chan os.Signal, 1)
+	signal.Notify(cSig, os.Interrupt, syscall.SIGTERM)
+	ticker := time.Tick(backupCheckPeriod)
+Loop:
+	for {
+		select {
+		case <-ticker:
+			if atomic.LoadUint32(&_isLeader) == 1 {
+				scheduledConfigBackup()
+			}
+		case <-cSig:
+			break Loop
+		}
+	}
+}
+
+// Masked secrets in the request keep the values in old config
+func parseBackupConfig(req *api.RESTBackupConfigConfig, old *share.CLUSBackupConfig) (*share.CLUSBackupConfig, error) {
+	cfg := *old
+	if req.Enabled != nil {
+		cfg.Enabled = *req.Enabled
+	}
+	if req.Interval != nil {
+		cfg.Interval = *req.Interval
+	}
+	if req.Retention != nil {
+		cfg.Retention = *req.Retention
+	}
+	if req.Passphrase != nil && *req.Passphrase != api.RESTMaskedValue {
+		cfg.Passphrase = *req.Passphrase
+	}
+	if s := req.Storage; s != nil {
+		cfg.Storage = share.CLUSBackupStorage{
+			Type:        s.Type,
+			Bucket:      strings.TrimSpace(s.Bucket),
+			Prefix:      strings.TrimSpace(s.Prefix),
+			Region:      strings.TrimSpace(s.Region),
+			Endpoint:    strings.TrimSpace(s.Endpoint),
+			AccessKey:   strings.TrimSpace(s.AccessKey),
+			SecretKey:   s.SecretKey,
+			AccountName: strings.TrimSpace(s.AccountName),
+			SASToken:    strings.TrimSpace(s.SASToken),
+		}
+		if cfg.Storage.SecretKey == api.RESTMaskedValue {
+			cfg.Storage.SecretKey = old.Storage.SecretKey
+		}
+		if cfg.Storage.SASToken == api.RESTMaskedValue {
+			cfg.Storage.SASToken = old.Storage.SASToken
+		}
+	}
+
+	switch cfg.Storage.Type {
+	case "":
+		if cfg.Enabled {
+			return nil, errBackupNotConfigured
+		}
+		return &cfg, nil
+	case share.BackupStorageS3, share.BackupStorageGCS:
+		if cfg.Storage.AccessKey == "" || cfg.Storage.SecretKey == "" {
+			return nil, errors.New("Access key and secret key are required")
+		}
+	case share.BackupStorageAzure:
+		if cfg.Storage.AccountName == "" && cfg.Storage.Endpoint == "" {
+			return nil, errors.New("Storage account name is required")
+		}
+		if cfg.Storage.SASToken == "" {
+			return nil, errors.New("SAS token is required")
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported storage type: %s", cfg.Storage.Type)
+	}
+	if cfg.Storage.Bucket == "" {
+		return nil, errors.New("Bucket or container name is required")
+	}
+	if !regBackupPrefix.MatchString(cfg.Storage.Prefix) || strings.HasPrefix(cfg.Storage.Prefix, "/") {
+		return nil, fmt.Errorf("Invalid prefix %s", cfg.Storage.Prefix)
+	}
+	if cfg.Storage.Endpoint != "" && !strings.HasPrefix(cfg.Storage.Endpoint, "https://") {
+		return nil, fmt.Errorf("Invalid endpoint %s, https is required", cfg.Storage.Endpoint)
+	}
+	if len(cfg.Passphrase) < backupMinPassphraseLen {
+		return nil, fmt.Errorf("Passphrase must have at least %d characters", backupMinPassphraseLen)
+	}
+	if cfg.Enabled && cfg.Interval == 0 {
+		return nil, errors.New("Backup interval is required")
+	}
+
+	return &cfg, nil
+}
+
+func handlerGetBackupConfig(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	cfg, _ := clusHelper.GetBackupConfigRev()
+	status := clusHelper.GetBackupStatus()
+	resp := api.RESTBackupConfigData{
+		Config: &api.RESTBackupConfig{
+			Enabled:    cfg.Enabled,
+			Interval:   cfg.Interval,
+			Retention:  cfg.Retention,
+			Passphrase: cfg.Passphrase,
+			Storage: api.RESTBackupStorage{
+				Type:        cfg.Storage.Type,
+				Bucket:      cfg.Storage.Bucket,
+				Prefix:      cfg.Storage.Prefix,
+				Region:      cfg.Storage.Region,
+				Endpoint:    cfg.Storage.Endpoint,
+				AccessKey:   cfg.Storage.AccessKey,
+				SecretKey:   cfg.Storage.SecretKey,
+				AccountName: cfg.Storage.AccountName,
+				SASToken:    cfg.Storage.SASToken,
+			},
+		},
+		Status: &api.RESTBackupStatus{
+			LastRunAt:     status.LastRunAt,
+			LastBackupAt:  status.LastBackupAt,
+			LastBackup:    status.LastBackup,
+			LastError:     status.LastError,
+			LastRestoreAt: status.LastRestoreAt,
+			LastRestored:  status.LastRestored,
+		},
+	}
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get configuration backup settings")
+}
+
+func handlerBackupConfig(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	var rconf api.RESTBackupConfigConfigData
+	body, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(body, &rconf); err != nil || rconf.Config == nil {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	old, rev := clusHelper.GetBackupConfigRev()
+	cfg, err := parseBackupConfig(rconf.Config, old)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Invalid backup config")
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+		return
+	}
+	if err := clusHelper.PutBackupConfigRev(cfg, rev); err != nil {
+		log.WithFields(log.Fields{"error": err, "rev": rev}).Error()
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+
+	restRespSuccess(w, r, nil, acc, login, &rconf, "Configure configuration backup")
+}
+
+func handlerBackupNow(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	cfg, _ := clusHelper.GetBackupConfigRev()
+	name, err := runConfigBackup(cfg)
+	if err != nil {
+		configLog(share.CLUSEvConfigBackupFail, login, fmt.Sprintf("Failed to backup configuration: %s", err.Error()))
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFailExport, err.Error())
+		return
+	}
+	configLog(share.CLUSEvConfigBackup, login, fmt.Sprintf("Configuration is backed up to %s", name))
+
+	resp := api.RESTBackupData{
+		Backup: &api.RESTBackup{Name: name, CreatedAt: clusHelper.GetBackupStatus().LastBackupAt},
+	}
+	restRespSuccess(w, r, &resp, acc, login, nil, "Backup configuration")
+}
+
+func handlerBackupList(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	cfg, _ := clusHelper.GetBackupConfigRev()
+	if cfg.Storage.Type == "" {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, errBackupNotConfigured.Error())
+		return
+	}
+	storage, err := newBackupStorage(&cfg.Storage)
+	if err != nil {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+		return
+	}
+	backups, err := listBackups(storage)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to list backups")
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFailReadCluster, err.Error())
+		return
+	}
+
+	resp := api.RESTBackupsData{Backups: make([]*api.RESTBackup, len(backups))}
+	for i, b := range backups {
+		// newest first
+		resp.Backups[len(backups)-1-i] = &api.RESTBackup{Name: b.name, Size: b.size, CreatedAt: b.createdAt}
+	}
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get configuration backup list")
+}
+
+// The backup is decrypted and restored thru the config import, whose status can be queried like config import
+func handlerBackupRestore(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.CanWriteCluster() {
+		restRespAccessDenied(w, login)
+		return
+	} else if acc.HasGlobalPermissions(share.PERM_SYSTEM_CONFIG, share.PERM_SYSTEM_CONFIG) {
+		fedRole, _ := cacher.GetFedMembershipRole(acc)
+		if fedRole == api.FedRoleMaster && !acc.IsFedAdmin() {
+			restRespAccessDenied(w, login)
+			return
+		}
+	}
+
+	var req api.RESTBackupRestoreReq
+	body, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(body, &req); err != nil || !regBackupName.MatchString(req.Name) {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+	// sections are passed to the import like the config import request
+	query := url.Values{}
+	if len(req.Sections) > 0 {
+		query.Set(api.QueryKeySection, strings.Join(req.Sections, ","))
+	}
+	r.URL.RawQuery = query.Encode()
+	sections, err := parseImportSections(r)
+	if err != nil {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+		return
+	}
+	if !req.DryRun && !checkImportPolicyChangeApproval(w, sections) {
+		return
+	}
+
+	cfg, _ := clusHelper.GetBackupConfigRev()
+	if cfg.Storage.Type == "" {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, errBackupNotConfigured.Error())
+		return
+	}
+	passphrase := req.Passphrase
+	if passphrase == "" {
+		passphrase = cfg.Passphrase
+	}
+
+	data, err := func() ([]byte, error) {
+		storage, err := newBackupStorage(&cfg.Storage)
+		if err != nil {
+			return nil, err
+		}
+		archive, err := storage.download(req.Name)
+		if err != nil {
+			return nil, err
+		}
+		return decryptBackup(archive, passphrase)
+	}()
+	if err != nil {
+		log.WithFields(log.Fields{"name": req.Name, "error": err}).Error("Failed to get backup")
+		if !req.DryRun {
+			configLog(share.CLUSEvImportFail, login, fmt.Sprintf("Failed to restore configuration from %s", req.Name))
+		}
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrFailImport, err.Error())
+		return
+	}
+
+	// feed the decrypted config to the import as a gzip upload
+	r.Body = ioutil.NopCloser(bytes.NewReader(data))
+	r.Header.Set("Content-Type", "application/x-gzip")
+	if req.DryRun {
+		configImportDiff(w, r, sections, acc, login)
+		return
+	}
+
+	status := clusHelper.GetBackupStatus()
+	status.LastRestoreAt = time.Now().UTC()
+	status.LastRestored = req.Name
+	clusHelper.PutBackupStatus(status)
+	configLog(share.CLUSEvConfigRestore, login, fmt.Sprintf("Restore configuration from %s", req.Name))
+
+	_importHandler(w, r, "", share.IMPORT_TYPE_CONFIG, share.PREFIX_IMPORT_CONFIG, acc, login)
+}
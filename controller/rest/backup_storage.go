@@ -0,0 +1,305 @@
+package rest
+
+// Object storage clients for configuration backup. S3 and GCS (thru its XML interoperability API with HMAC keys)
+// share the same client with AWS signature v4. Azure blob storage is accessed with SAS token.
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+
+	"github.com/neuvector/neuvector/share"
+)
+
+const backupStorageTimeout = time.Minute * 5
+const azureBlobAPIVersion = "2020-04-08"
+
+type backupObject struct {
+	name      string
+	size      int64
+	createdAt time.Time
+}
+
+type backupStorage interface {
+	upload(name string, data []byte) error
+	download(name string) ([]byte, error)
+	list() ([]*backupObject, error)
+	remove(name string) error
+}
+
+func newBackupStorage(cfg *share.CLUSBackupStorage) (backupStorage, error) {
+	switch cfg.Type {
+	case share.BackupStorageS3, share.BackupStorageGCS:
+		return newS3BackupStorage(cfg), nil
+	case share.BackupStorageAzure:
+		return newAzureBackupStorage(cfg), nil
+	}
+	return nil, fmt.Errorf("Unsupported storage type: %s", cfg.Type)
+}
+
+// The system https proxy is used when it's enabled
+func createBackupHttpClient() *http.Client {
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+
+	_sysProxyMutex.RLock()
+	proxy := _sysHttpsProxy
+	_sysProxyMutex.RUnlock()
+	if proxy.Enable && proxy.URL != "" {
+		if u, err := url.Parse(proxy.URL); err == nil {
+			if proxy.Username != "" {
+				u.User = url.UserPassword(proxy.Username, proxy.Password)
+			}
+			transport.Proxy = http.ProxyURL(u)
+		}
+	}
+
+	return &http.Client{Transport: transport, Timeout: backupStorageTimeout}
+}
+
+func sendBackupRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		msg := string(body)
+		if len(msg) > 256 {
+			msg = msg[:256]
+		}
+		return nil, fmt.Errorf("%s %s: %s, %s", req.Method, req.URL.Path, resp.Status, msg)
+	}
+	return body, nil
+}
+
+// -- S3 / GCS
+
+type s3BackupStorage struct {
+	client   *http.Client
+	signer   *v4.Signer
+	endpoint string
+	region   string
+	bucket   string
+	prefix   string
+}
+
+type s3ListBucketResult struct {
+	IsTruncated bool   `xml:"IsTruncated"`
+	NextMarker  string `xml:"NextMarker"`
+	Contents    []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+		Size         int64     `xml:"Size"`
+	} `xml:"Contents"`
+}
+
+func newS3BackupStorage(cfg *share.CLUSBackupStorage) *s3BackupStorage {
+	s := &s3BackupStorage{
+		client: createBackupHttpClient(),
+		signer: v4.NewSigner(credentials.NewStaticCredentials(cfg.AccessKey, cfg.SecretKey, ""), func(s *v4.Signer) {
+			s.DisableURIPathEscaping = true
+		}),
+		endpoint: strings.TrimRight(cfg.Endpoint, "/"),
+		region:   cfg.Region,
+		bucket:   cfg.Bucket,
+		prefix:   cfg.Prefix,
+	}
+	if cfg.Type == share.BackupStorageGCS {
+		if s.endpoint == "" {
+			s.endpoint = "https://storage.googleapis.com"
+		}
+		if s.region == "" {
+			s.region = "auto"
+		}
+	} else {
+		if s.region == "" {
+			s.region = "us-east-1"
+		}
+		if s.endpoint == "" {
+			s.endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", s.region)
+		}
+	}
+	return s
+}
+
+func (s *s3BackupStorage) do(method, name string, query url.Values, body []byte) ([]byte, error) {
+	u := fmt.Sprintf("%s/%s/", s.endpoint, s.bucket)
+	if name != "" {
+		u += (&url.URL{Path: s.prefix + name}).EscapedPath()
+	}
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.signer.Sign(req, bytes.NewReader(body), "s3", s.region, time.Now()); err != nil {
+		return nil, err
+	}
+	return sendBackupRequest(s.client, req)
+}
+
+func (s *s3BackupStorage) upload(name string, data []byte) error {
+	_, err := s.do("PUT", name, nil, data)
+	return err
+}
+
+func (s *s3BackupStorage) download(name string) ([]byte, error) {
+	return s.do("GET", name, nil, nil)
+}
+
+func (s *s3BackupStorage) remove(name string) error {
+	_, err := s.do("DELETE", name, nil, nil)
+	return err
+}
+
+func (s *s3BackupStorage) list() ([]*backupObject, error) {
+	var objs []*backupObject
+	var marker string
+	for {
+		query := url.Values{"prefix": []string{s.prefix}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		body, err := s.do("GET", "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result s3ListBucketResult
+		if err = xml.Unmarshal(body, &result); err != nil {
+			return nil, err
+		}
+		for _, c := range result.Contents {
+			name := strings.TrimPrefix(c.Key, s.prefix)
+			if name == "" || strings.Contains(name, "/") {
+				continue
+			}
+			objs = append(objs, &backupObject{name: name, size: c.Size, createdAt: c.LastModified})
+		}
+		if !result.IsTruncated || len(result.Contents) == 0 {
+			break
+		}
+		if marker = result.NextMarker; marker == "" {
+			marker = result.Contents[len(result.Contents)-1].Key
+		}
+	}
+	return objs, nil
+}
+
+// -- Azure blob
+
+type azureBackupStorage struct {
+	client  *http.Client
+	baseURL string
+	sas     string
+	prefix  string
+}
+
+type azureEnumerationResults struct {
+	Blobs struct {
+		Blob []struct {
+			Name       string `xml:"Name"`
+			Properties struct {
+				LastModified  string `xml:"Last-Modified"`
+				ContentLength int64  `xml:"Content-Length"`
+			} `xml:"Properties"`
+		} `xml:"Blob"`
+	} `xml:"Blobs"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+func newAzureBackupStorage(cfg *share.CLUSBackupStorage) *azureBackupStorage {
+	endpoint := strings.TrimRight(cfg.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", cfg.AccountName)
+	}
+	return &azureBackupStorage{
+		client:  createBackupHttpClient(),
+		baseURL: fmt.Sprintf("%s/%s", endpoint, cfg.Bucket),
+		sas:     strings.TrimPrefix(cfg.SASToken, "?"),
+		prefix:  cfg.Prefix,
+	}
+}
+
+func (s *azureBackupStorage) do(method, name string, query url.Values, body []byte) ([]byte, error) {
+	u := s.baseURL
+	if name != "" {
+		u += "/" + (&url.URL{Path: s.prefix + name}).EscapedPath()
+	}
+	u += "?" + s.sas
+	if len(query) > 0 {
+		u += "&" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-ms-version", azureBlobAPIVersion)
+	if method == "PUT" {
+		req.Header.Set("x-ms-blob-type", "BlockBlob")
+	}
+	return sendBackupRequest(s.client, req)
+}
+
+func (s *azureBackupStorage) upload(name string, data []byte) error {
+	_, err := s.do("PUT", name, nil, data)
+	return err
+}
+
+func (s *azureBackupStorage) download(name string) ([]byte, error) {
+	return s.do("GET", name, nil, nil)
+}
+
+func (s *azureBackupStorage) remove(name string) error {
+	_, err := s.do("DELETE", name, nil, nil)
+	return err
+}
+
+func (s *azureBackupStorage) list() ([]*backupObject, error) {
+	var objs []*backupObject
+	var marker string
+	for {
+		query := url.Values{"restype": []string{"container"}, "comp": []string{"list"}, "prefix": []string{s.prefix}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		body, err := s.do("GET", "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result azureEnumerationResults
+		if err = xml.Unmarshal(body, &result); err != nil {
+			return nil, err
+		}
+		for _, b := range result.Blobs.Blob {
+			name := strings.TrimPrefix(b.Name, s.prefix)
+			if name == "" || strings.Contains(name, "/") {
+				continue
+			}
+			obj := &backupObject{name: name, size: b.Properties.ContentLength}
+			obj.createdAt, _ = time.Parse(time.RFC1123, b.Properties.LastModified)
+			objs = append(objs, obj)
+		}
+		if marker = result.NextMarker; marker == "" {
+			break
+		}
+	}
+	return objs, nil
+}
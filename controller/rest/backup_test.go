@@ -0,0 +1,135 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+type mockBackupStorage struct {
+	objects map[string][]byte
+}
+
+func (s *mockBackupStorage) upload(name string, data []byte) error {
+	s.objects[name] = data
+	return nil
+}
+
+func (s *mockBackupStorage) download(name string) ([]byte, error) {
+	if data, ok := s.objects[name]; ok {
+		return data, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (s *mockBackupStorage) list() ([]*backupObject, error) {
+	objs := make([]*backupObject, 0, len(s.objects))
+	for name, data := range s.objects {
+		objs = append(objs, &backupObject{name: name, size: int64(len(data))})
+	}
+	return objs, nil
+}
+
+func (s *mockBackupStorage) remove(name string) error {
+	delete(s.objects, name)
+	return nil
+}
+
+func TestBackupEncryption(t *testing.T) {
+	data := []byte("config data")
+	archive, err := encryptBackup(data, "passphrase1")
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %s", err)
+	}
+	if bytes.Contains(archive, data) {
+		t.Errorf("Data is not encrypted")
+	}
+	if plain, err := decryptBackup(archive, "passphrase1"); err != nil || !bytes.Equal(plain, data) {
+		t.Errorf("Failed to decrypt: %s, %v", plain, err)
+	}
+	if _, err := decryptBackup(archive, "passphrase2"); err == nil {
+		t.Errorf("Decryption with wrong passphrase should fail")
+	}
+	if _, err := decryptBackup(data, "passphrase1"); err == nil {
+		t.Errorf("Decryption of invalid archive should fail")
+	}
+}
+
+func TestBackupPrune(t *testing.T) {
+	storage := &mockBackupStorage{objects: map[string][]byte{
+		"nv-config-20230101-000000.nvbak": nil,
+		"nv-config-20230103-000000.nvbak": nil,
+		"nv-config-20230102-000000.nvbak": nil,
+		"other.txt":                       nil,
+	}}
+	pruneBackups(storage, 2)
+
+	var names []string
+	for name := range storage.objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	expect := []string{"nv-config-20230102-000000.nvbak", "nv-config-20230103-000000.nvbak", "other.txt"}
+	if len(names) != len(expect) || names[0] != expect[0] || names[1] != expect[1] || names[2] != expect[2] {
+		t.Errorf("Unexpected backups after prune: %v", names)
+	}
+}
+
+func TestParseBackupConfig(t *testing.T) {
+	enabled := true
+	interval := uint32(24)
+	masked := api.RESTMaskedValue
+	old := &share.CLUSBackupConfig{
+		Passphrase: "old passphrase",
+		Storage:    share.CLUSBackupStorage{Type: share.BackupStorageS3, Bucket: "b", AccessKey: "ak", SecretKey: "old secret"},
+	}
+
+	req := &api.RESTBackupConfigConfig{
+		Enabled:    &enabled,
+		Interval:   &interval,
+		Passphrase: &masked,
+		Storage:    &api.RESTBackupStorage{Type: share.BackupStorageS3, Bucket: "b", Prefix: "nv/", AccessKey: "ak", SecretKey: masked},
+	}
+	cfg, err := parseBackupConfig(req, old)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %s", err)
+	}
+	if !cfg.Enabled || cfg.Interval != 24 || cfg.Passphrase != "old passphrase" || cfg.Storage.SecretKey != "old secret" || cfg.Storage.Prefix != "nv/" {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+
+	invalids := []*api.RESTBackupStorage{
+		&api.RESTBackupStorage{Type: "ftp", Bucket: "b"},
+		&api.RESTBackupStorage{Type: share.BackupStorageGCS, Bucket: "b", AccessKey: "ak"},
+		&api.RESTBackupStorage{Type: share.BackupStorageAzure, Bucket: "b", AccountName: "account"},
+		&api.RESTBackupStorage{Type: share.BackupStorageS3, Bucket: "b", AccessKey: "ak", SecretKey: "sk", Prefix: "../*"},
+		&api.RESTBackupStorage{Type: share.BackupStorageS3, Bucket: "b", AccessKey: "ak", SecretKey: "sk", Endpoint: "http://minio"},
+	}
+	for _, s := range invalids {
+		if _, err := parseBackupConfig(&api.RESTBackupConfigConfig{Storage: s}, old); err == nil {
+			t.Errorf("Config should be invalid: %+v", s)
+		}
+	}
+	short := "short"
+	if _, err := parseBackupConfig(&api.RESTBackupConfigConfig{Passphrase: &short}, old); err == nil {
+		t.Errorf("Short passphrase should be rejected")
+	}
+}
+
+func TestBackupKey(t *testing.T) {
+	key := backupKey("passphrase", []byte("salt"))
+	if len(key) != 32 {
+		t.Errorf("Unexpected key length: %d", len(key))
+	}
+	if !bytes.Equal(key, backupKey("passphrase", []byte("salt"))) {
+		t.Errorf("Key should be the same for the same passphrase and salt")
+	}
+	if bytes.Equal(key, backupKey("passphrase", []byte("pepper"))) || bytes.Equal(key, backupKey("password", []byte("salt"))) {
+		t.Errorf("Key should be different for a different passphrase or salt: %s", hex.EncodeToString(key))
+	}
+}
@@ -57,11 +57,15 @@ func bench2REST(bench share.BenchType, item *share.CLUSBenchItem, cpf *complianc
 
 	switch bench {
 	case share.BenchCustomHost:
-		r.Profile = "Level 1"
+		if r.Profile == "" {
+			r.Profile = share.BenchProfileL1
+		}
 		r.Category = api.BenchCategoryCustom
 		r.Type = api.BenchTypeHost
 	case share.BenchCustomContainer:
-		r.Profile = "Level 1"
+		if r.Profile == "" {
+			r.Profile = share.BenchProfileL1
+		}
 		r.Category = api.BenchCategoryCustom
 		r.Type = api.BenchTypeContainer
 	case share.BenchContainerSecret:
@@ -87,6 +91,12 @@ func bench2REST(bench share.BenchType, item *share.CLUSBenchItem, cpf *complianc
 		r.Description = fmt.Sprintf("%s - %s", r.Description, r.Message[0])
 	}
 
+	if item.Stig != nil {
+		r.Stig = &api.RESTBenchStig{
+			VulnID: item.Stig.VulnID, RuleID: item.Stig.RuleID, StigID: item.Stig.StigID, Severity: item.Stig.Severity,
+		}
+	}
+
 	// add tags
 	if tags, ok := cpf.filter[r.TestNum]; ok {
 		r.Tags = tags
@@ -273,6 +283,47 @@ func handlerKubeBench(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 	}
 }
 
+const (
+	minCustomCheckSchedule = 5
+	maxCustomCheckSchedule = 7 * 24 * 60
+)
+
+func isCustomCheckProfileValid(profile string) bool {
+	switch profile {
+	case "", share.BenchProfileL1, share.BenchProfileL2:
+		return true
+	}
+	return false
+}
+
+func customCheck2Cluster(script *api.RESTCustomCheck) *share.CLUSCustomCheck {
+	return &share.CLUSCustomCheck{
+		Name:        script.Name,
+		Script:      script.Script,
+		Description: script.Description,
+		Remediation: script.Remediation,
+		Profile:     script.Profile,
+		Scored:      script.Scored,
+	}
+}
+
+func customChecks2REST(group string, conf *share.CLUSCustomCheckGroup) *api.RESTCustomChecks {
+	schedule := conf.Schedule
+	config := &api.RESTCustomChecks{Group: group, Schedule: &schedule}
+	for _, scr := range conf.Scripts {
+		scp := &api.RESTCustomCheck{
+			Name:        scr.Name,
+			Script:      scr.Script,
+			Description: scr.Description,
+			Remediation: scr.Remediation,
+			Profile:     scr.Profile,
+			Scored:      scr.Scored,
+		}
+		config.Scripts = append(config.Scripts, scp)
+	}
+	return config
+}
+
 func handlerCustomCheckConfig(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
 	defer r.Body.Close()
@@ -358,11 +409,11 @@ func handlerCustomCheckConfig(w http.ResponseWriter, r *http.Request, ps httprou
 				restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, "Invalid characters in the script name")
 				return
 			}
-			scp := &share.CLUSCustomCheck{
-				Name:   script.Name,
-				Script: script.Script,
+			if !isCustomCheckProfileValid(script.Profile) {
+				restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, "Invalid script profile")
+				return
 			}
-			oldConfig.Scripts = append(oldConfig.Scripts, scp)
+			oldConfig.Scripts = append(oldConfig.Scripts, customCheck2Cluster(script))
 		}
 	}
 	// update
@@ -371,7 +422,11 @@ func handlerCustomCheckConfig(w http.ResponseWriter, r *http.Request, ps httprou
 			found := false
 			for i, scr := range oldConfig.Scripts {
 				if script.Name == scr.Name {
-					oldConfig.Scripts[i].Script = script.Script
+					if !isCustomCheckProfileValid(script.Profile) {
+						restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, "Invalid script profile")
+						return
+					}
+					oldConfig.Scripts[i] = customCheck2Cluster(script)
 					found = true
 					break
 				}
@@ -382,6 +437,18 @@ func handlerCustomCheckConfig(w http.ResponseWriter, r *http.Request, ps httprou
 			}
 		}
 	}
+	// schedule
+	for _, checks := range []*api.RESTCustomChecks{config.Add, config.Update} {
+		if checks != nil && checks.Schedule != nil {
+			if *checks.Schedule != 0 &&
+				(*checks.Schedule < minCustomCheckSchedule || *checks.Schedule > maxCustomCheckSchedule) {
+				e := fmt.Sprintf("Schedule must be between %d and %d minutes", minCustomCheckSchedule, maxCustomCheckSchedule)
+				restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+				return
+			}
+			oldConfig.Schedule = *checks.Schedule
+		}
+	}
 	// Write access rule
 	if err := clusHelper.PutCustomCheckConfig(group, oldConfig, rev); err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("Write cluster fail")
@@ -412,17 +479,10 @@ func handlerCustomCheckShow(w http.ResponseWriter, r *http.Request, ps httproute
 
 	oldConfig, _ := clusHelper.GetCustomCheckConfig(group)
 
-	config := api.RESTCustomChecks{Group: group}
-	if oldConfig != nil {
-		for _, script := range oldConfig.Scripts {
-			scp := &api.RESTCustomCheck{
-				Name:   script.Name,
-				Script: script.Script,
-			}
-			config.Scripts = append(config.Scripts, scp)
-		}
+	if oldConfig == nil {
+		oldConfig = &share.CLUSCustomCheckGroup{}
 	}
-	resp := api.RESTCustomCheckData{Config: &config}
+	resp := api.RESTCustomCheckData{Config: customChecks2REST(group, oldConfig)}
 	restRespSuccess(w, r, &resp, acc, login, nil, "Get bench script config")
 }
 
@@ -442,15 +502,7 @@ func handlerCustomCheckList(w http.ResponseWriter, r *http.Request, ps httproute
 		if !cacher.AuthorizeCustomCheck(group, acc) {
 			continue
 		}
-		config := api.RESTCustomChecks{Group: group}
-		for _, scr := range script.Scripts {
-			scp := &api.RESTCustomCheck{
-				Name:   scr.Name,
-				Script: scr.Script,
-			}
-			config.Scripts = append(config.Scripts, scp)
-		}
-		configs = append(configs, &config)
+		configs = append(configs, customChecks2REST(group, script))
 	}
 	sort.Slice(configs, func(i, j int) bool { return configs[i].Group < configs[j].Group })
 	resp := api.RESTCustomCheckListData{Configs: configs}
@@ -571,6 +623,7 @@ func handlerHostCompliance(w http.ResponseWriter, r *http.Request, ps httprouter
 	var ts int64
 	var runAt string
 	var dockerVer, kubeVer string
+	var kubeStig *api.RESTBenchStigInfo
 	items := make([]*api.RESTBenchItem, 0)
 
 	if rpt, _, _ := getCISReportFromCluster(share.BenchCustomHost, id, cpf, acc); rpt != nil {
@@ -590,6 +643,7 @@ func handlerHostCompliance(w http.ResponseWriter, r *http.Request, ps httprouter
 	}
 	if rpt, _, _ := getKubeCISReportFromCluster(id, cpf, acc); rpt != nil {
 		kubeVer = rpt.Version
+		kubeStig = rpt.Stig
 		items = append(items, rpt.Items...)
 		if rpt.RunAtTimeStamp > ts {
 			ts = rpt.RunAtTimeStamp
@@ -600,6 +654,7 @@ func handlerHostCompliance(w http.ResponseWriter, r *http.Request, ps httprouter
 	items = sortBenchItems(items)
 	data := api.RESTComplianceData{
 		RunAtTimeStamp: ts, RunAt: runAt, KubeVersion: kubeVer, DockerVersion: dockerVer, Items: items,
+		KubeStig: kubeStig,
 	}
 	restRespSuccess(w, r, &data, acc, login, nil, "Get host compliance report")
 }
@@ -643,6 +698,11 @@ func _getCISReportFromCluster(bench share.BenchType, id string, readData bool, c
 		Version:        r.Version,
 		Items:          make([]*api.RESTBenchItem, 0),
 	}
+	if r.Stig != nil {
+		rpt.Stig = &api.RESTBenchStigInfo{
+			StigID: r.Stig.StigID, Title: r.Stig.Title, Version: r.Stig.Version, Release: r.Stig.Release,
+		}
+	}
 
 	// Add check tags
 	for _, item := range r.Items {
@@ -715,6 +775,11 @@ func decodeCISReport(bench share.BenchType, value []byte, cpf *complianceProfile
 		Version:        r.Version,
 		Items:          make([]*api.RESTBenchItem, 0),
 	}
+	if r.Stig != nil {
+		rpt.Stig = &api.RESTBenchStigInfo{
+			StigID: r.Stig.StigID, Title: r.Stig.Title, Version: r.Stig.Version, Release: r.Stig.Release,
+		}
+	}
 
 	// Add check tags
 	for _, item := range r.Items {
@@ -749,6 +814,7 @@ func addCompAsset(all map[string]*compAsset, comp *api.RESTBenchItem) *compAsset
 				Remediation: comp.Remediation,
 				Group:       comp.Group,
 				Tags:        comp.Tags,
+				Controls:    comp.Controls,
 			},
 			wls:       utils.NewSet(),
 			nodes:     utils.NewSet(),
@@ -765,19 +831,8 @@ func addCompAsset(all map[string]*compAsset, comp *api.RESTBenchItem) *compAsset
 	return ca
 }
 
-func handlerAssetCompliance(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
-	defer r.Body.Close()
-
-	acc, login := getAccessControl(w, r, "")
-	if acc == nil {
-		return
-	} else if !acc.HasRequiredPermissions() {
-		resp := &api.RESTComplianceAssetData{Compliances: make([]*api.RESTComplianceAsset, 0)}
-		restRespSuccess(w, r, resp, acc, login, nil, "Get compliance asset report")
-		return
-	}
-
+// The failed checks of the workloads, nodes and images that the caller can access
+func getComplianceAssets(acc *access.AccessControl) *api.RESTComplianceAssetData {
 	cpf := &complianceProfileFilter{filter: make(map[string][]string)}
 	if cp, filter, err := cacher.GetComplianceProfile(share.DefaultComplianceProfileName, access.NewReaderAccessControl()); err != nil {
 		log.WithFields(log.Fields{"profile": share.DefaultComplianceProfileName}).Error("Compliance profile not found")
@@ -968,6 +1023,24 @@ func handlerAssetCompliance(w http.ResponseWriter, r *http.Request, ps httproute
 	resp.KubeVersion = getNewestVersion(kubeVers)
 	resp.DockerVersion = getNewestVersion(dockerVers)
 
+	return &resp
+}
+
+func handlerAssetCompliance(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.HasRequiredPermissions() {
+		resp := &api.RESTComplianceAssetData{Compliances: make([]*api.RESTComplianceAsset, 0)}
+		restRespSuccess(w, r, resp, acc, login, nil, "Get compliance asset report")
+		return
+	}
+
+	resp := getComplianceAssets(acc)
+
 	// remove id from RESTIDName to reduce data size.
 	for _, wls := range resp.Workloads {
 		for i, _ := range wls {
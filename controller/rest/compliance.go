@@ -79,6 +79,15 @@ func filterComplianceChecks(items []*api.RESTBenchItem, cpf *complianceProfileFi
 		domain = api.DomainNodes
 	}
 
+	// Remove the checks whose risks are accepted in the domain
+	accepted := make([]*api.RESTBenchItem, 0, len(items))
+	for _, item := range items {
+		if !cacher.IsComplianceRiskAccepted(item.TestNum, domain) {
+			accepted = append(accepted, item)
+		}
+	}
+	items = accepted
+
 	tags, _ := cacher.GetDomainEffectiveTags(domain, access.NewReaderAccessControl())
 	if len(tags) > 0 {
 		// namespace tagged
@@ -151,9 +160,21 @@ func handlerComplianceProfileShow(w http.ResponseWriter, r *http.Request, ps htt
 	restRespSuccess(w, r, &resp, acc, login, nil, "Get compliance profile detail")
 }
 
+// The custom check results are reported with the script name as the test number
+func isCustomCheckName(name string) bool {
+	for _, conf := range clusHelper.GetAllCustomCheckConfig() {
+		for _, scr := range conf.Scripts {
+			if scr.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func configComplianceProfileEntry(ccp *share.CLUSComplianceProfile, re *api.RESTComplianceProfileEntry) error {
 	_, metaMap := scanUtils.GetComplianceMeta()
-	if _, ok := metaMap[re.TestNum]; !ok {
+	if _, ok := metaMap[re.TestNum]; !ok && !isCustomCheckName(re.TestNum) {
 		return errors.New("Unknonwn compliance ID")
 	}
 
@@ -169,12 +190,18 @@ func configComplianceProfileEntry(ccp *share.CLUSComplianceProfile, re *api.REST
 	}
 	tags := tagSet.ToStringSlice()
 	sort.Strings(tags)
-	ccp.Entries[re.TestNum] = share.CLUSComplianceProfileEntry{TestNum: re.TestNum, Tags: tags}
+	if ce, ok := ccp.Entries[re.TestNum]; ok && ce.CfgType == share.GroundCfg {
+		return fmt.Errorf("Entry %s is defined by CRD and cannot be configured", re.TestNum)
+	}
+	ccp.Entries[re.TestNum] = share.CLUSComplianceProfileEntry{TestNum: re.TestNum, Tags: tags, CfgType: share.UserCreated}
 	return nil
 }
 
 func configComplianceProfile(ccp *share.CLUSComplianceProfile, rcp *api.RESTComplianceProfileConfig) error {
 	if rcp.DisableSystem != nil {
+		if ccp.UserDisableSystem != nil && ccp.DisableSystem != *rcp.DisableSystem {
+			return errors.New("disable_system is defined by CRD and cannot be configured")
+		}
 		ccp.DisableSystem = *rcp.DisableSystem
 	}
 
@@ -233,8 +260,12 @@ func handlerComplianceProfileConfig(w http.ResponseWriter, r *http.Request, ps h
 			return
 		}
 
-		// clean up current entries
-		ccp.Entries = make(map[string]share.CLUSComplianceProfileEntry)
+		// clean up current entries, except those defined by crd
+		for testNum, ce := range ccp.Entries {
+			if ce.CfgType != share.GroundCfg {
+				delete(ccp.Entries, testNum)
+			}
+		}
 		if err := configComplianceProfile(ccp, rcp); err != nil {
 			log.WithFields(log.Fields{"error": err}).Error("Failed to configure compliance profile")
 			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
@@ -359,6 +390,12 @@ func handlerComplianceProfileEntryDelete(w http.ResponseWriter, r *http.Request,
 			return
 		}
 
+		if ce, ok := ccp.Entries[testNum]; ok && ce.CfgType == share.GroundCfg {
+			e := "Entry defined by CRD cannot be deleted"
+			log.WithFields(log.Fields{"testNum": testNum}).Error(e)
+			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrOpNotAllowed, e)
+			return
+		}
 		delete(ccp.Entries, testNum)
 
 		if !acc.Authorize(ccp, nil) {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/neuvector/neuvector/controller/api"
 	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
 )
 
 func TestComplianceProfileConfig(t *testing.T) {
@@ -264,3 +265,122 @@ func TestComplianceProfileNegative(t *testing.T) {
 
 	postTest()
 }
+
+func TestComplianceProfileCrdEntry(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+	cacher = &mockCache{
+		cps: map[string]*api.RESTComplianceProfile{
+			"default": &api.RESTComplianceProfile{
+				Name:          "default",
+				DisableSystem: false,
+				Entries:       []api.RESTComplianceProfileEntry{},
+			},
+		},
+	}
+
+	cfg := api.RESTComplianceProfileConfig{
+		Name: "default",
+		Entries: &[]*api.RESTComplianceProfileEntry{
+			&api.RESTComplianceProfileEntry{TestNum: "D.1.1.1", Tags: []string{"PCI"}},
+			&api.RESTComplianceProfileEntry{TestNum: "D.1.2.1", Tags: []string{"NIST"}},
+		},
+	}
+	data := api.RESTComplianceProfileConfigData{Config: &cfg}
+	body, _ := json.Marshal(data)
+
+	w := restCall("PATCH", "/v1/compliance/profile/default", body, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Errorf("Configure compliance profile failed: %v", w.status)
+	}
+
+	// Crd conflicting with the user entry is rejected
+	var crdHandler nvCrdHandler
+	crdHandler.Init(share.CLUSLockPolicyKey)
+	disable := true
+	crdCfg := api.RESTComplianceProfileConfig{
+		Name:          "default",
+		DisableSystem: &disable,
+		Entries: &[]*api.RESTComplianceProfileEntry{
+			&api.RESTComplianceProfileEntry{TestNum: "D.1.1.1", Tags: []string{"GDPR", "HIPAA"}},
+		},
+	}
+	if err := crdHandler.crdHandleCompProfileEntries(&crdCfg); err == nil {
+		t.Errorf("Crd compliance profile conflicting with user entry should fail")
+	}
+
+	cp, _, _ := mockCluster.GetComplianceProfile("default", nil)
+	if e, ok := cp.Entries["D.1.1.1"]; !ok || e.CfgType == share.GroundCfg || len(e.Tags) != 1 || cp.DisableSystem {
+		t.Errorf("User compliance profile entry is changed: %+v %+v", cp, e)
+	}
+
+	crdCfg.Entries = &[]*api.RESTComplianceProfileEntry{
+		&api.RESTComplianceProfileEntry{TestNum: "D.1.1.10", Tags: []string{"GDPR", "HIPAA"}},
+	}
+	if err := crdHandler.crdHandleCompProfileEntries(&crdCfg); err != nil {
+		t.Errorf("Failed to apply crd compliance profile: %v", err)
+	}
+
+	cp, _, _ = mockCluster.GetComplianceProfile("default", nil)
+	if e, ok := cp.Entries["D.1.1.10"]; !ok || e.CfgType != share.GroundCfg || len(e.Tags) != 2 || !cp.DisableSystem {
+		t.Errorf("Crd compliance profile entry is not configured correctly: %+v %+v", cp, e)
+	}
+
+	// Entry defined by crd cannot be modified or deleted
+	e := api.RESTComplianceProfileEntry{TestNum: "D.1.1.10", Tags: []string{"NIST"}}
+	edata := api.RESTComplianceProfileEntryConfigData{Config: &e}
+	body, _ = json.Marshal(edata)
+
+	w = restCall("PATCH", "/v1/compliance/profile/default/entry/D.1.1.10", body, api.UserRoleAdmin)
+	if w.status != http.StatusBadRequest {
+		t.Errorf("Configure crd compliance profile entry should fail: %v", w.status)
+	}
+	w = restCall("DELETE", "/v1/compliance/profile/default/entry/D.1.1.10", nil, api.UserRoleAdmin)
+	if w.status != http.StatusBadRequest {
+		t.Errorf("Delete crd compliance profile entry should fail: %v", w.status)
+	}
+
+	// disable_system defined by crd cannot be changed
+	enable := false
+	cfg = api.RESTComplianceProfileConfig{Name: "default", DisableSystem: &enable}
+	data = api.RESTComplianceProfileConfigData{Config: &cfg}
+	body, _ = json.Marshal(data)
+
+	w = restCall("PATCH", "/v1/compliance/profile/default", body, api.UserRoleAdmin)
+	if w.status != http.StatusBadRequest {
+		t.Errorf("Configure crd disable_system should fail: %v", w.status)
+	}
+
+	// Entry defined by crd is kept when the profile is configured
+	cfg = api.RESTComplianceProfileConfig{
+		Name:    "default",
+		Entries: &[]*api.RESTComplianceProfileEntry{},
+	}
+	data = api.RESTComplianceProfileConfigData{Config: &cfg}
+	body, _ = json.Marshal(data)
+
+	w = restCall("PATCH", "/v1/compliance/profile/default", body, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Errorf("Configure compliance profile failed: %v", w.status)
+	}
+
+	cp, _, _ = mockCluster.GetComplianceProfile("default", nil)
+	if _, ok := cp.Entries["D.1.1.10"]; !ok || len(cp.Entries) != 1 {
+		t.Errorf("Compliance profile entries are not configured correctly: %v", cp.Entries)
+	}
+
+	// Entries and disable_system defined by crd are removed with the crd
+	if err := crdHandler.crdHandleCompProfileEntries(nil); err != nil {
+		t.Errorf("Failed to remove crd compliance profile: %v", err)
+	}
+
+	cp, _, _ = mockCluster.GetComplianceProfile("default", nil)
+	if len(cp.Entries) != 0 || cp.DisableSystem || cp.UserDisableSystem != nil {
+		t.Errorf("Crd compliance profile is not removed: %+v", cp)
+	}
+
+	postTest()
+}
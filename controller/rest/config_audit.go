@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+// The object before and after a change is recorded in the config audit. It's read with the GET API of the
+// same uri as the user who makes the change, and masked like the support data. The cache is updated from the
+// cluster after the change is written, so the object after the change is read when the cache applies the next
+// config update, until it's different from the one before the change. The REST write event is logged in the
+// request, so the audits are in the order of the changes. Only the changes to an object uri, i.e.
+// PATCH/PUT/DELETE, are recorded.
+
+const restAuditValueMax int = 4096
+const restAuditAfterTimeout = time.Duration(time.Second * 2)
+
+type restAuditCtxKey struct{}
+
+// REST write event of the request, which is logged after the object is read again
+type restAuditEvent struct {
+	ev *share.CLUSEventLog
+}
+
+type configAuditHandler struct {
+	handler http.Handler
+	router  *httprouter.Router
+}
+
+func (h configAuditHandler) readObject(r *http.Request) []byte {
+	u := *r.URL
+	query := u.Query()
+	query.Set(api.SupportFlag, "true")
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil
+	}
+	req.Header = r.Header.Clone()
+	req.Header.Del("Accept-Encoding")
+	req.Header.Del("Content-Type")
+	req.RemoteAddr = r.RemoteAddr
+
+	w := httptest.NewRecorder()
+	h.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		return nil
+	}
+	return w.Body.Bytes()
+}
+
+func restAuditValue(value []byte) string {
+	if len(value) > restAuditValueMax {
+		value = value[:restAuditValueMax]
+	}
+	return string(value)
+}
+
+func (h configAuditHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPatch, http.MethodPut, http.MethodDelete:
+	default:
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	if handle, _, _ := h.router.Lookup(http.MethodGet, r.URL.Path); handle == nil {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	before := h.readObject(r)
+	// Get the notification before the change is written, so the update of the change is not missed
+	updated := cacher.ConfigUpdateNotify()
+	audit := &restAuditEvent{}
+	h.handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), restAuditCtxKey{}, audit)))
+	if audit.ev == nil {
+		return
+	}
+
+	var after []byte
+	timer := time.NewTimer(restAuditAfterTimeout)
+	defer timer.Stop()
+wait:
+	for {
+		select {
+		case <-updated:
+			// the update can be of another object, then wait for the next one
+			updated = cacher.ConfigUpdateNotify()
+			if after = h.readObject(r); !bytes.Equal(before, after) {
+				break wait
+			}
+		case <-timer.C:
+			after = h.readObject(r)
+			break wait
+		}
+	}
+
+	audit.ev.RESTBefore = restAuditValue(before)
+	audit.ev.RESTAfter = restAuditValue(after)
+	evqueue.Append(audit.ev)
+}
+
+// Return true if the REST write event is logged by configAuditHandler
+func restAuditEventLog(r *http.Request, clog *share.CLUSEventLog) bool {
+	if clog.Event != share.CLUSEvRESTWrite {
+		return false
+	}
+	if audit, ok := r.Context().Value(restAuditCtxKey{}).(*restAuditEvent); ok && audit.ev == nil {
+		audit.ev = clog
+		return true
+	}
+	return false
+}
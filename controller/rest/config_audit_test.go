@@ -0,0 +1,98 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/cache"
+	"github.com/neuvector/neuvector/share"
+)
+
+type mockAuditQueue struct {
+	events chan *share.CLUSEventLog
+}
+
+func (q *mockAuditQueue) Append(obj interface{}) error {
+	q.events <- obj.(*share.CLUSEventLog)
+	return nil
+}
+
+func (q *mockAuditQueue) Flush() error {
+	return nil
+}
+
+type mockConfigUpdateCache struct {
+	cache.CacheInterface
+	updated chan struct{}
+}
+
+func (m *mockConfigUpdateCache) ConfigUpdateNotify() <-chan struct{} {
+	return m.updated
+}
+
+func TestConfigAuditHandler(t *testing.T) {
+	preTest()
+
+	queue := &mockAuditQueue{events: make(chan *share.CLUSEventLog, 4)}
+	evqueue = queue
+	mc := &mockConfigUpdateCache{updated: make(chan struct{})}
+	cacher = mc
+
+	role := "reader"
+	r := httprouter.New()
+	r.GET("/v1/user/:fullname", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if r.URL.Query().Get(api.SupportFlag) != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"user":{"fullname":"%s","role":"%s"}}`, ps.ByName("fullname"), role)
+	})
+	write := func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		// the cache is updated after the request
+		updated := mc.updated
+		go func() {
+			role = "admin"
+			close(updated)
+		}()
+		clog := share.CLUSEventLog{Event: share.CLUSEvRESTWrite, RESTMethod: r.Method, RESTRequest: r.URL.String()}
+		if !restAuditEventLog(r, &clog) {
+			evqueue.Append(&clog)
+		}
+	}
+	r.PATCH("/v1/user/:fullname", write)
+	r.POST("/v1/user", write)
+	h := configAuditHandler{handler: r, router: r}
+
+	// the change is logged when the request is done
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPatch, "/v1/user/u1", nil))
+	select {
+	case ev := <-queue.events:
+		if ev.RESTBefore != `{"user":{"fullname":"u1","role":"reader"}}` {
+			t.Errorf("Unexpected object before the change: %s", ev.RESTBefore)
+		}
+		if ev.RESTAfter != `{"user":{"fullname":"u1","role":"admin"}}` {
+			t.Errorf("Unexpected object after the change: %s", ev.RESTAfter)
+		}
+	default:
+		t.Errorf("Config change is not logged")
+	}
+
+	// no object uri to read
+	mc.updated = make(chan struct{})
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v1/user", nil))
+	select {
+	case ev := <-queue.events:
+		if ev.RESTBefore != "" || ev.RESTAfter != "" {
+			t.Errorf("Unexpected object values: before=%s, after=%s", ev.RESTBefore, ev.RESTAfter)
+		}
+	default:
+		t.Errorf("Config change is not logged")
+	}
+
+	postTest()
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"strings"
 	"time"
 
@@ -280,12 +281,21 @@ func handlesystemcfg(yaml_data []byte, load bool, skip *bool, context *configMap
 			SingleCVEPerSyslog:        rc.SingleCVEPerSyslog,
 			SyslogCVEInLayers:         rc.SyslogCVEInLayers,
 			SyslogServerCert:          rc.SyslogServerCert,
+			SyslogClientCert:          rc.SyslogClientCert,
+			SyslogClientKey:           rc.SyslogClientKey,
+			SyslogStructuredData:      rc.SyslogStructuredData,
+			SyslogCategoryMaps:        rc.SyslogCategoryMaps,
 			AuthOrder:                 rc.AuthOrder,
 			AuthByPlatform:            rc.AuthByPlatform,
 			RancherEP:                 rc.RancherEP,
+			RancherProjectRoles:       rc.RancherProjectRoles,
 			WebhookEnable:             rc.WebhookEnable,
 			WebhookUrl:                rc.WebhookUrl,
 			Webhooks:                  rc.Webhooks,
+			FlowExport:                rc.FlowExport,
+			LogExporters:              rc.LogExporters,
+			SMTP:                      rc.SMTP,
+			LogCorrelation:            rc.LogCorrelation,
 			ClusterName:               rc.ClusterName,
 			ControllerDebug:           rc.ControllerDebug,
 			MonitorServiceMesh:        rc.MonitorServiceMesh,
@@ -298,12 +308,19 @@ func handlesystemcfg(yaml_data []byte, load bool, skip *bool, context *configMap
 			XffEnabled:                rc.XffEnabled,
 			ScannerAutoscale:          rc.ScannerAutoscale,
 			NoTelemetryReport:         rc.NoTelemetryReport,
+			KubeCISProfile:            rc.KubeCISProfile,
+			BenchMode:                 rc.BenchMode,
+			PolicyChangeApproval:      rc.PolicyChangeApproval,
 		},
 		NetConfig: &api.RESTSysNetConfigConfig{
 			NetServiceStatus:     rc.NetServiceStatus,
 			NetServicePolicyMode: rc.NetServicePolicyMode,
 			DisableNetPolicy:     rc.DisableNetPolicy,
 			DetectUnmanagedWl:    rc.DetectUnmanagedWl,
+			HostNetPolicyStatus:  rc.HostNetPolicyStatus,
+			HostNetPolicyMode:    rc.HostNetPolicyMode,
+			SynFloodThreshold:    rc.SynFloodThreshold,
+			PortScanThreshold:    rc.PortScanThreshold,
 		},
 		AtmoConfig: &api.RESTSysAtmoConfigConfig{
 			ModeAutoD2M:         rc.ModeAutoD2M,
@@ -315,8 +332,19 @@ func handlesystemcfg(yaml_data []byte, load bool, skip *bool, context *configMap
 
 	acc := access.NewAdminAccessControl()
 	_, err = configSystemConfig(nil, acc, nil, "configmap", share.ScopeLocal, context.platform, &rconf)
-	if err == nil && rc.ScanConfig != nil && rc.ScanConfig.AutoScan != nil {
-		cconf := &share.CLUSScanConfig{AutoScan: *rc.ScanConfig.AutoScan}
+	if err == nil && rc.ScanConfig != nil && (rc.ScanConfig.AutoScan != nil || rc.ScanConfig.RegistryMirrors != nil) {
+		cconf, _ := clusHelper.GetScanConfigRev(acc)
+		if cconf == nil {
+			cconf = &share.CLUSScanConfig{}
+		}
+		if rc.ScanConfig.AutoScan != nil {
+			cconf.AutoScan = *rc.ScanConfig.AutoScan
+		}
+		if rc.ScanConfig.RegistryMirrors != nil {
+			if cconf.RegistryMirrors, err = restRegistryMirrors2Clus(*rc.ScanConfig.RegistryMirrors); err != nil {
+				return err
+			}
+		}
 		value, _ := json.Marshal(cconf)
 		err = cluster.Put(share.CLUSConfigScanKey, value)
 	}
@@ -446,12 +474,15 @@ func updateAdminPass(ruser *api.RESTUser, acc *access.AccessControl) {
 							} else {
 								profile.SessionTimeout = rprofile.SessionTimeout
 							}
+							profile.MFARequiredRoles = rprofile.MFARequiredRoles
+							profile.SessionPolicies = sessionPolicies2CLUS(rprofile.SessionPolicies)
 							if profile.MinLen <= 0 || profile.MinUpperCount < 0 || profile.MinLowerCount < 0 || profile.MinDigitCount < 0 || profile.MinSpecialCount < 0 ||
 								(profile.EnablePwdExpiration && profile.PwdExpireAfterDays <= 0) ||
 								(profile.EnablePwdHistory && profile.PwdHistoryCount <= 0) ||
 								(profile.EnableBlockAfterFailedLogin && (profile.BlockAfterFailedCount <= 0 || profile.BlockMinutes <= 0)) ||
 								(profile.MinLen < (profile.MinUpperCount + profile.MinLowerCount + profile.MinDigitCount + profile.MinSpecialCount)) ||
-								(profile.SessionTimeout > api.UserIdleTimeoutMax || profile.SessionTimeout < api.UserIdleTimeoutMin) {
+								(profile.SessionTimeout > api.UserIdleTimeoutMax || profile.SessionTimeout < api.UserIdleTimeoutMin) ||
+								!isValidMFARequiredRoles(profile.MFARequiredRoles) || !isValidSessionPolicies(profile.SessionPolicies) {
 								log.WithFields(log.Fields{"profile": profile}).Error("invalid value")
 								profile = share.CLUSPwdProfile{}
 							}
@@ -465,7 +496,7 @@ func updateAdminPass(ruser *api.RESTUser, acc *access.AccessControl) {
 		}
 	}
 	empty := share.CLUSPwdProfile{}
-	if profile == empty {
+	if reflect.DeepEqual(profile, empty) {
 		if pprofile, _, _ := clusHelper.GetPwdProfileRev(share.CLUSDefPwdProfileName, acc); pprofile != nil {
 			profile = *pprofile
 		}
@@ -594,12 +625,15 @@ func handlepwdprofilecfg(yaml_data []byte, load bool, skip *bool, context *confi
 		} else {
 			profile.SessionTimeout = rprofile.SessionTimeout
 		}
+		profile.MFARequiredRoles = rprofile.MFARequiredRoles
+		profile.SessionPolicies = sessionPolicies2CLUS(rprofile.SessionPolicies)
 		if profile.MinLen <= 0 || profile.MinUpperCount < 0 || profile.MinLowerCount < 0 || profile.MinDigitCount < 0 || profile.MinSpecialCount < 0 ||
 			(profile.EnablePwdExpiration && profile.PwdExpireAfterDays <= 0) ||
 			(profile.EnablePwdHistory && profile.PwdHistoryCount <= 0) ||
 			(profile.EnableBlockAfterFailedLogin && (profile.BlockAfterFailedCount <= 0 || profile.BlockMinutes <= 0)) ||
 			(profile.MinLen < (profile.MinUpperCount + profile.MinLowerCount + profile.MinDigitCount + profile.MinSpecialCount)) ||
-			(profile.SessionTimeout > api.UserIdleTimeoutMax || profile.SessionTimeout < api.UserIdleTimeoutMin) {
+			(profile.SessionTimeout > api.UserIdleTimeoutMax || profile.SessionTimeout < api.UserIdleTimeoutMin) ||
+			!isValidMFARequiredRoles(profile.MFARequiredRoles) || !isValidSessionPolicies(profile.SessionPolicies) {
 			log.WithFields(log.Fields{"rprofile": *rprofile}).Error("invalid value")
 			continue
 		}
@@ -6,6 +6,7 @@ import (
 	"github.com/neuvector/neuvector/controller/cache"
 	"github.com/neuvector/neuvector/controller/kv"
 	"github.com/neuvector/neuvector/share"
+	"reflect"
 	"testing"
 )
 
@@ -262,7 +263,7 @@ func TestPwdProfileCfg(t *testing.T) {
 		if s == nil {
 			t.Errorf("Failed to get %s config\n", allExpected[idx].Name)
 		} else {
-			if *expected != *s {
+			if !reflect.DeepEqual(*expected, *s) {
 				t.Errorf("[%d] Got %v but expect %v\n", idx, *s, *expected)
 			}
 		}
@@ -315,7 +316,7 @@ func TestPwdProfileCfg(t *testing.T) {
 		if s == nil {
 			t.Errorf("(2) Failed to get %s config\n", expected.Name)
 		} else {
-			if expected != *s {
+			if !reflect.DeepEqual(expected, *s) {
 				t.Errorf("(2) Got %v but expect %v\n", *s, expected)
 			}
 		}
@@ -0,0 +1,242 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/neuvector/k8s"
+	apiextv1 "github.com/neuvector/k8s/apis/apiextensions/v1"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/common"
+	admission "github.com/neuvector/neuvector/controller/nvk8sapi/nvvalidatewebhookcfg"
+	"github.com/neuvector/neuvector/controller/resource"
+	"github.com/neuvector/neuvector/share/global"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+const (
+	crdConversionStrategyNone    = "None"
+	crdConversionStrategyWebhook = "Webhook"
+	crdConversionSuccess         = "Success"
+	crdConversionFailure         = "Failure"
+)
+
+// convert the unmarshalled object of a kind from one version to another. apiVersion is updated by the caller
+type crdConvertFunc func(obj map[string]interface{}) error
+
+// neuvector crds that are served in multiple versions and converted by the crd webhook server
+var crdConvertibleNames utils.Set = utils.NewSet(
+	resource.NvSecurityRuleName,
+	resource.NvClusterSecurityRuleName,
+	resource.NvAdmCtrlSecurityRuleName,
+	resource.NvDlpSecurityRuleName,
+	resource.NvWafSecurityRuleName,
+	resource.NvVulnProfileName,
+	resource.NvCompProfileName,
+)
+
+// key is "{kind}/{from version}/{to version}". When a schema change is not backward compatible, add the converters
+// in both directions here. Versions without a converter share the same schema and only apiVersion is changed.
+var crdConverters map[string]crdConvertFunc = map[string]crdConvertFunc{}
+
+func crdConverterKey(kind, from, to string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, from, to)
+}
+
+func isCrdVersionServed(version string) bool {
+	if version == resource.NvSecurityRuleVersion {
+		return true
+	}
+	for _, v := range resource.NvCrdServedOldVersions {
+		if version == v {
+			return true
+		}
+	}
+	return false
+}
+
+func splitCrdApiVersion(apiVersion string) (string, string, error) {
+	ss := strings.Split(apiVersion, "/")
+	if len(ss) != 2 || ss[0] == "" || ss[1] == "" {
+		return "", "", fmt.Errorf("invalid apiVersion %s", apiVersion)
+	}
+	return ss[0], ss[1], nil
+}
+
+func convertCrdObject(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	toGroup, toVersion, err := splitCrdApiVersion(desiredAPIVersion)
+	if err != nil {
+		return nil, err
+	} else if !isCrdVersionServed(toVersion) {
+		return nil, fmt.Errorf("unsupported desired version %s", toVersion)
+	}
+
+	var obj map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil {
+		return nil, err
+	}
+
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+	fromGroup, fromVersion, err := splitCrdApiVersion(apiVersion)
+	if err != nil {
+		return nil, err
+	} else if fromGroup != toGroup || (toGroup != common.OEMSecurityRuleGroup && toGroup != common.OEMClusterSecurityRuleGroup) {
+		return nil, fmt.Errorf("unsupported conversion from %s to %s", apiVersion, desiredAPIVersion)
+	} else if !isCrdVersionServed(fromVersion) {
+		return nil, fmt.Errorf("unsupported version %s", fromVersion)
+	}
+
+	if fromVersion == toVersion {
+		return raw, nil
+	}
+	if convert, ok := crdConverters[crdConverterKey(kind, fromVersion, toVersion)]; ok {
+		if err := convert(obj); err != nil {
+			return nil, err
+		}
+	}
+	obj["apiVersion"] = desiredAPIVersion
+
+	return json.Marshal(obj)
+}
+
+func convertCrdReview(review *resource.K8sConversionReview) *resource.K8sConversionResponse {
+	req := review.Request
+	resp := &resource.K8sConversionResponse{
+		UID:              req.UID,
+		ConvertedObjects: make([]json.RawMessage, 0, len(req.Objects)),
+		Result:           resource.K8sConversionResult{Status: crdConversionSuccess},
+	}
+	for _, raw := range req.Objects {
+		converted, err := convertCrdObject(raw, req.DesiredAPIVersion)
+		if err != nil {
+			log.WithFields(log.Fields{"uid": req.UID, "desired": req.DesiredAPIVersion, "error": err}).Error()
+			resp.ConvertedObjects = nil
+			resp.Result = resource.K8sConversionResult{Status: crdConversionFailure, Message: err.Error()}
+			break
+		}
+		resp.ConvertedObjects = append(resp.ConvertedObjects, converted)
+	}
+
+	return resp
+}
+
+// Serve method for crd conversion webhook
+func (whsvr *WebhookServer) crdconvert(w http.ResponseWriter, r *http.Request) {
+
+	var body []byte
+	if r.Body != nil {
+		if data, err := ioutil.ReadAll(r.Body); err == nil {
+			body = data
+		}
+	}
+	if len(body) == 0 {
+		log.Error("empty body")
+		http.Error(w, "empty body", http.StatusBadRequest)
+		return
+	}
+
+	// verify the content type is accurate
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "application/json" {
+		log.WithFields(log.Fields{"contentType": contentType}).Error("unexpectd header")
+		http.Error(w, "invalid Content-Type, expect `application/json`", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var review resource.K8sConversionReview
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		log.WithFields(log.Fields{"error": err}).Error("can't decode body")
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	if whsvr.dumpRequestObj {
+		log.WithFields(log.Fields{"ConversionReview": string(body)}).Debug()
+	}
+
+	resp := resource.K8sConversionReview{
+		APIVersion: review.APIVersion,
+		Kind:       resource.K8sKindConversionReview,
+		Response:   convertCrdReview(&review),
+	}
+	if resp.APIVersion == "" {
+		resp.APIVersion = resource.ApiextensionsK8sIoV1
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("can't encode response")
+		http.Error(w, fmt.Sprintf("could not encode response: %v", err), http.StatusInternalServerError)
+	} else if _, err := w.Write(data); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("can't write response")
+		http.Error(w, fmt.Sprintf("could not write response: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// Return the conversion setting & old versions to serve for a neuvector crd. Before the crd webhook server's caBundle
+// is available, the "None" strategy is used because all served versions share the same schema for now.
+func GetCrdConversion(crdMetaName string) (*apiextv1.CustomResourceConversion, []string) {
+	if !crdConvertibleNames.Contains(crdMetaName) {
+		return nil, nil
+	}
+
+	conversion := &apiextv1.CustomResourceConversion{}
+	if caBundle := admission.GetCABundle(resource.NvCrdSvcName); len(caBundle) > 0 {
+		strategy := crdConversionStrategyWebhook
+		namespace := resource.NvAdmSvcNamespace
+		name := resource.NvCrdSvcName
+		path := admission.UriCrdConvert
+		conversion.Strategy = &strategy
+		conversion.Webhook = &apiextv1.WebhookConversion{
+			ClientConfig: &apiextv1.WebhookClientConfig{
+				Service: &apiextv1.ServiceReference{
+					Namespace: &namespace,
+					Name:      &name,
+					Path:      &path,
+				},
+				CaBundle: caBundle,
+			},
+			ConversionReviewVersions: []string{"v1"},
+		}
+	} else {
+		strategy := crdConversionStrategyNone
+		conversion.Strategy = &strategy
+	}
+
+	return conversion, resource.NvCrdServedOldVersions
+}
+
+// Called by the lead controller when the crd webhook server's certificate is changed
+func syncCrdConversion() {
+	for name := range crdConvertibleNames.Iter() {
+		crdName := name.(string)
+		obj, err := global.ORCH.GetResource(resource.RscTypeCrd, k8s.AllNamespaces, crdName)
+		if err != nil {
+			continue
+		}
+		crdRes, ok := obj.(*apiextv1.CustomResourceDefinition)
+		if !ok || crdRes.Spec == nil || crdRes.Spec.Conversion == nil {
+			continue
+		}
+		conversion, _ := GetCrdConversion(crdName)
+		if conversion == nil || conversion.GetStrategy() != crdConversionStrategyWebhook {
+			continue
+		}
+		if cur := crdRes.Spec.Conversion; cur.GetStrategy() == crdConversionStrategyWebhook && cur.Webhook != nil &&
+			cur.Webhook.ClientConfig != nil && bytes.Equal(cur.Webhook.ClientConfig.CaBundle, conversion.Webhook.ClientConfig.CaBundle) {
+			continue
+		}
+		crdRes.Spec.Conversion = conversion
+		if err := global.ORCH.UpdateResource(resource.RscTypeCrd, crdRes); err != nil {
+			log.WithFields(log.Fields{"crd": crdName, "error": err}).Error("failed to update crd conversion")
+		} else {
+			log.WithFields(log.Fields{"crd": crdName}).Info("updated crd conversion")
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/resource"
+)
+
+func TestConvertCrdObject(t *testing.T) {
+	raw := []byte(`{"apiVersion":"neuvector.com/v1alpha1","kind":"NvSecurityRule","metadata":{"name":"ubuntu","namespace":"default","generation":3},"spec":{"target":{"policymode":"Protect"}}}`)
+
+	converted, err := convertCrdObject(raw, "neuvector.com/v1")
+	if err != nil {
+		t.Errorf("Unexpected conversion error: %v", err)
+	} else {
+		var obj map[string]interface{}
+		json.Unmarshal(converted, &obj)
+		if obj["apiVersion"] != "neuvector.com/v1" {
+			t.Errorf("Unexpected apiVersion: %v", obj["apiVersion"])
+		}
+		if meta, ok := obj["metadata"].(map[string]interface{}); !ok || meta["generation"] != float64(3) {
+			t.Errorf("Unexpected metadata: %v", obj["metadata"])
+		}
+		if spec, ok := obj["spec"].(map[string]interface{}); !ok || spec["target"] == nil {
+			t.Errorf("Unexpected spec: %v", obj["spec"])
+		}
+	}
+
+	for _, desired := range []string{"neuvector.com/v2", "other.com/v1", "v1"} {
+		if _, err := convertCrdObject(raw, desired); err == nil {
+			t.Errorf("Conversion to %s should fail", desired)
+		}
+	}
+
+	review := &resource.K8sConversionReview{
+		APIVersion: resource.ApiextensionsK8sIoV1,
+		Kind:       resource.K8sKindConversionReview,
+		Request: &resource.K8sConversionRequest{
+			UID:               "1234",
+			DesiredAPIVersion: "neuvector.com/v1alpha1",
+			Objects:           []json.RawMessage{raw, []byte(`{"apiVersion":"neuvector.com/v1","kind":"NvDlpSecurityRule"}`)},
+		},
+	}
+	resp := convertCrdReview(review)
+	if resp.UID != "1234" || resp.Result.Status != crdConversionSuccess || len(resp.ConvertedObjects) != 2 {
+		t.Errorf("Unexpected conversion response: %+v", resp)
+	}
+
+	review.Request.Objects = append(review.Request.Objects, []byte(`{"apiVersion":"other.com/v1","kind":"Other"}`))
+	resp = convertCrdReview(review)
+	if resp.Result.Status != crdConversionFailure || len(resp.ConvertedObjects) != 0 {
+		t.Errorf("Unexpected conversion response: %+v", resp)
+	}
+}
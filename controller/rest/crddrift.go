@@ -0,0 +1,420 @@
+package rest
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	admission "github.com/neuvector/neuvector/controller/nvk8sapi/nvvalidatewebhookcfg"
+	"github.com/neuvector/neuvector/controller/resource"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/global"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+type crdDriftKindInfo struct {
+	rscType   string
+	kvCrdKind string
+	lockKey   string
+}
+
+// crd kinds whose declared content is compared with the live config periodically
+var crdDriftKinds map[string]crdDriftKindInfo = map[string]crdDriftKindInfo{
+	resource.NvSecurityRuleKind:        {resource.RscTypeCrdSecurityRule, resource.NvSecurityRuleKind, share.CLUSLockPolicyKey},
+	resource.NvClusterSecurityRuleKind: {resource.RscTypeCrdClusterSecurityRule, resource.NvSecurityRuleKind, share.CLUSLockPolicyKey},
+	resource.NvAdmCtrlSecurityRuleKind: {resource.RscTypeCrdAdmCtrlSecurityRule, resource.NvAdmCtrlSecurityRuleKind, share.CLUSLockAdmCtrlKey},
+	resource.NvDlpSecurityRuleKind:     {resource.RscTypeCrdDlpSecurityRule, resource.NvDlpSecurityRuleKind, share.CLUSLockPolicyKey},
+	resource.NvWafSecurityRuleKind:     {resource.RscTypeCrdWafSecurityRule, resource.NvWafSecurityRuleKind, share.CLUSLockPolicyKey},
+	resource.NvVulnProfileKind:         {resource.RscTypeCrdVulnProfile, resource.NvVulnProfileKind, share.CLUSLockPolicyKey},
+	resource.NvCompProfileKind:         {resource.RscTypeCrdCompProfile, resource.NvCompProfileKind, share.CLUSLockPolicyKey},
+}
+
+// Compare what a crd record declares with the config in kv. Return the out-of-band modifications found
+func crdRecordDrift(kind string, record *share.CLUSCrdSecurityRule, acc *access.AccessControl) []string {
+	var drifts []string
+
+	switch kind {
+	case resource.NvSecurityRuleKind, resource.NvClusterSecurityRuleKind:
+		for _, name := range record.Groups {
+			if cg, _, _ := clusHelper.GetGroup(name, acc); cg == nil {
+				drifts = append(drifts, fmt.Sprintf("group %s is removed", name))
+			} else if cg.CfgType != share.GroundCfg && cg.CfgType != share.FederalCfg {
+				drifts = append(drifts, fmt.Sprintf("group %s is not crd type", name))
+			}
+		}
+		for _, id := range record.Rules {
+			if r, _ := clusHelper.GetPolicyRule(id); r == nil {
+				drifts = append(drifts, fmt.Sprintf("network rule %d is removed", id))
+			} else if r.CfgType != share.GroundCfg {
+				drifts = append(drifts, fmt.Sprintf("network rule %d is not crd type", id))
+			}
+		}
+		if record.ProfileName != "" && len(record.ProcessRules) > 0 {
+			procs := make(map[string]bool)
+			if pp := clusHelper.GetProcessProfile(record.ProfileName); pp != nil {
+				for _, proc := range pp.Process {
+					if proc.CfgType == share.GroundCfg {
+						procs[fmt.Sprintf("%s::%s::%s", proc.Name, proc.Path, proc.Action)] = true
+					}
+				}
+			}
+			for _, pr := range record.ProcessRules {
+				if !procs[fmt.Sprintf("%s::%s::%s", pr.Name, pr.Path, pr.Action)] {
+					drifts = append(drifts, fmt.Sprintf("process rule %s(%s) of group %s is modified", pr.Name, pr.Path, record.ProfileName))
+				}
+			}
+		}
+		if record.ProfileName != "" && len(record.FileRules) > 0 {
+			files := make(map[string]bool)
+			if mon, _ := clusHelper.GetFileMonitorProfile(record.ProfileName); mon != nil {
+				for _, flt := range mon.FiltersCRD {
+					files[fmt.Sprintf("%s::%v::%s", flt.Filter, flt.Recursive, flt.Behavior)] = true
+				}
+			}
+			for _, fr := range record.FileRules {
+				if !files[fmt.Sprintf("%s::%v::%s", fr.Filter, fr.Recursive, fr.Behavior)] {
+					drifts = append(drifts, fmt.Sprintf("file rule %s of group %s is modified", fr.Filter, record.ProfileName))
+				}
+			}
+		}
+	case resource.NvAdmCtrlSecurityRuleKind:
+		for name, id := range record.AdmCtrlRules {
+			ruleType := strings.Split(name, "-")[0] // name is in the format "{ruleType}-{hash}"
+			if r := clusHelper.GetAdmissionRule(admission.NvAdmValidateType, ruleType, id); r == nil {
+				drifts = append(drifts, fmt.Sprintf("admission control %s rule %d is removed", ruleType, id))
+			} else if r.CfgType != share.GroundCfg {
+				drifts = append(drifts, fmt.Sprintf("admission control %s rule %d is not crd type", ruleType, id))
+			}
+		}
+	case resource.NvDlpSecurityRuleKind:
+		if record.DlpSensor != "" {
+			if sensor := clusHelper.GetDlpSensor(record.DlpSensor); sensor == nil {
+				drifts = append(drifts, fmt.Sprintf("dlp sensor %s is removed", record.DlpSensor))
+			} else if sensor.CfgType != share.GroundCfg {
+				drifts = append(drifts, fmt.Sprintf("dlp sensor %s is not crd type", record.DlpSensor))
+			}
+		}
+	case resource.NvWafSecurityRuleKind:
+		if record.WafSensor != "" {
+			if sensor := clusHelper.GetWafSensor(record.WafSensor); sensor == nil {
+				drifts = append(drifts, fmt.Sprintf("waf sensor %s is removed", record.WafSensor))
+			} else if sensor.CfgType != share.GroundCfg {
+				drifts = append(drifts, fmt.Sprintf("waf sensor %s is not crd type", record.WafSensor))
+			}
+		}
+	}
+
+	return drifts
+}
+
+// Compare the parsed spec of a crd object with the config in kv. Return the out-of-band modifications found
+func crdSpecDrift(kind string, record *share.CLUSCrdSecurityRule, crdCfgRet *resource.NvSecurityParse, acc *access.AccessControl) []string {
+	var drifts []string
+
+	switch kind {
+	case resource.NvSecurityRuleKind, resource.NvClusterSecurityRuleKind:
+		for _, group := range crdCfgRet.GroupCfgs {
+			// reserved and nv.ip.xxx groups don't have criteria defined by crd
+			if group.Name == api.LearnedExternal || group.Name == api.AllHostGroup ||
+				strings.HasPrefix(group.Name, api.LearnedSvcGroupPrefix) || group.Criteria == nil {
+				continue
+			}
+			// removed group or group of other type is reported by crdRecordDrift
+			cg, _, _ := clusHelper.GetGroup(group.Name, acc)
+			if cg == nil || cg.CfgType != share.GroundCfg {
+				continue
+			}
+			declared := make([]string, 0, len(*group.Criteria))
+			for _, ct := range *group.Criteria {
+				declared = append(declared, fmt.Sprintf("%s::%s::%s", ct.Key, ct.Value, ct.Op))
+			}
+			criteria := make([]string, 0, len(cg.Criteria))
+			for _, ct := range cg.Criteria {
+				criteria = append(criteria, fmt.Sprintf("%s::%s::%s", ct.Key, ct.Value, ct.Op))
+			}
+			if cg.Comment != group.Comment || !utils.CompareSliceWithoutOrder(declared, criteria) {
+				drifts = append(drifts, fmt.Sprintf("group %s is modified", group.Name))
+			}
+		}
+		for _, ruleConf := range crdCfgRet.RuleCfgs {
+			if ruleConf.Comment == nil {
+				continue
+			}
+			id, ok := record.Rules[*ruleConf.Comment]
+			if !ok {
+				drifts = append(drifts, fmt.Sprintf("network rule %s is removed", *ruleConf.Comment))
+				continue
+			}
+			r, _ := clusHelper.GetPolicyRule(id)
+			if r == nil || r.CfgType != share.GroundCfg {
+				continue
+			}
+			if (ruleConf.From != nil && r.From != *ruleConf.From) || (ruleConf.To != nil && r.To != *ruleConf.To) ||
+				(ruleConf.Ports != nil && r.Ports != *ruleConf.Ports) || (ruleConf.Action != nil && r.Action != *ruleConf.Action) ||
+				(ruleConf.Applications != nil && !utils.CompareSliceWithoutOrder(r.Applications, appNames2IDs(*ruleConf.Applications))) {
+				drifts = append(drifts, fmt.Sprintf("network rule %d is modified", id))
+			}
+		}
+	case resource.NvDlpSecurityRuleKind:
+		if sensorCfg := crdCfgRet.DlpSensorCfg; sensorCfg != nil && sensorCfg.Rules != nil && crdDlpSensorModified(sensorCfg) {
+			drifts = append(drifts, fmt.Sprintf("dlp sensor %s is modified", sensorCfg.Name))
+		}
+	case resource.NvWafSecurityRuleKind:
+		// rules converted from modsec are not compared
+		if sensorCfg := crdCfgRet.WafSensorCfg; sensorCfg != nil && sensorCfg.Rules != nil && sensorCfg.ModSec == nil && crdWafSensorModified(sensorCfg) {
+			drifts = append(drifts, fmt.Sprintf("waf sensor %s is modified", sensorCfg.Name))
+		}
+	case resource.NvVulnProfileKind:
+		if crdCfgRet.VulnProfileCfg == nil || crdCfgRet.VulnProfileCfg.Entries == nil {
+			break
+		}
+		cvp, _, _ := clusHelper.GetVulnerabilityProfile(share.DefaultVulnerabilityProfileName, acc)
+		if cvp == nil {
+			break
+		}
+		crdEntries := make(map[string]*share.CLUSVulnerabilityProfileEntry)
+		for _, ce := range cvp.Entries {
+			if ce.CfgType == share.GroundCfg {
+				crdEntries[ce.Name] = ce
+			}
+		}
+		for _, re := range *crdCfgRet.VulnProfileCfg.Entries {
+			declared, err := checkVulnerabilityProfileEntry(re)
+			if err != nil {
+				continue
+			}
+			if ce, ok := crdEntries[declared.Name]; !ok {
+				drifts = append(drifts, fmt.Sprintf("vulnerability profile entry %s is removed", declared.Name))
+			} else if ce.Comment != declared.Comment || ce.Days != declared.Days ||
+				!utils.CompareSliceWithoutOrder(ce.Domains, declared.Domains) || !utils.CompareSliceWithoutOrder(ce.Images, declared.Images) {
+				drifts = append(drifts, fmt.Sprintf("vulnerability profile entry %s is modified", declared.Name))
+			}
+			delete(crdEntries, declared.Name)
+		}
+		for name := range crdEntries {
+			drifts = append(drifts, fmt.Sprintf("vulnerability profile entry %s is added", name))
+		}
+	case resource.NvCompProfileKind:
+		cfg := crdCfgRet.CompProfileCfg
+		if cfg == nil {
+			break
+		}
+		ccp, _, _ := clusHelper.GetComplianceProfile(share.DefaultComplianceProfileName, acc)
+		if ccp == nil {
+			break
+		}
+		if cfg.DisableSystem != nil && ccp.DisableSystem != *cfg.DisableSystem {
+			drifts = append(drifts, "compliance profile disable_system is modified")
+		}
+		// normalize the declared entries with a scratch profile
+		declared := &share.CLUSComplianceProfile{Entries: make(map[string]share.CLUSComplianceProfileEntry)}
+		if cfg.Entries != nil {
+			for _, re := range *cfg.Entries {
+				configComplianceProfileEntry(declared, re)
+			}
+		}
+		for testNum, ce := range ccp.Entries {
+			if ce.CfgType != share.GroundCfg {
+				continue
+			}
+			if de, ok := declared.Entries[testNum]; !ok {
+				drifts = append(drifts, fmt.Sprintf("compliance profile entry %s is added", testNum))
+			} else if !utils.CompareSliceWithoutOrder(ce.Tags, de.Tags) {
+				drifts = append(drifts, fmt.Sprintf("compliance profile entry %s is modified", testNum))
+			}
+			delete(declared.Entries, testNum)
+		}
+		for testNum := range declared.Entries {
+			drifts = append(drifts, fmt.Sprintf("compliance profile entry %s is removed", testNum))
+		}
+	}
+
+	return drifts
+}
+
+// Return true if the comment or rules of a dlp sensor in kv are different from what the crd declares
+func crdDlpSensorModified(sensorCfg *api.RESTDlpSensorConfig) bool {
+	// removed sensor is reported by crdRecordDrift
+	sensor := clusHelper.GetDlpSensor(sensorCfg.Name)
+	if sensor == nil {
+		return false
+	}
+	var comment string
+	if sensorCfg.Comment != nil {
+		comment = *sensorCfg.Comment
+	}
+	if sensor.Comment != comment || len(sensor.RuleListNames) != len(*sensorCfg.Rules) {
+		return true
+	}
+
+	// the rule patterns are saved in the default sensor
+	defSensor := clusHelper.GetDlpSensor(share.CLUSDlpDefaultSensor)
+	for _, rule := range *sensorCfg.Rules {
+		name := common.GetInternalDlpRuleName(rule.Name, sensorCfg.Name)
+		if _, ok := sensor.RuleListNames[name]; !ok || defSensor == nil || defSensor.RuleList[name] == nil {
+			return true
+		}
+		declared := make([]string, 0, len(rule.Patterns))
+		for _, pt := range rule.Patterns {
+			declared = append(declared, fmt.Sprintf("%s::%s::%s::%s", pt.Key, pt.Value, pt.Op, pt.Context))
+		}
+		patterns := make([]string, 0, len(defSensor.RuleList[name].Patterns))
+		for _, pt := range defSensor.RuleList[name].Patterns {
+			patterns = append(patterns, fmt.Sprintf("%s::%s::%s::%s", pt.Key, pt.Value, pt.Op, pt.Context))
+		}
+		if !utils.CompareSliceWithoutOrder(declared, patterns) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Return true if the comment or rules of a waf sensor in kv are different from what the crd declares
+func crdWafSensorModified(sensorCfg *api.RESTWafSensorConfig) bool {
+	// removed sensor is reported by crdRecordDrift
+	sensor := clusHelper.GetWafSensor(sensorCfg.Name)
+	if sensor == nil {
+		return false
+	}
+	var comment string
+	if sensorCfg.Comment != nil {
+		comment = *sensorCfg.Comment
+	}
+	if sensor.Comment != comment || len(sensor.RuleListNames) != len(*sensorCfg.Rules) {
+		return true
+	}
+
+	// the rule patterns are saved in the default sensor
+	defSensor := clusHelper.GetWafSensor(share.CLUSWafDefaultSensor)
+	for _, rule := range *sensorCfg.Rules {
+		name := common.GetInternalWafRuleName(rule.Name, sensorCfg.Name)
+		if _, ok := sensor.RuleListNames[name]; !ok || defSensor == nil || defSensor.RuleList[name] == nil {
+			return true
+		}
+		declared := make([]string, 0, len(rule.Patterns))
+		for _, pt := range rule.Patterns {
+			declared = append(declared, fmt.Sprintf("%s::%s::%s::%s", pt.Key, pt.Value, pt.Op, pt.Context))
+		}
+		patterns := make([]string, 0, len(defSensor.RuleList[name].Patterns))
+		for _, pt := range defSensor.RuleList[name].Patterns {
+			patterns = append(patterns, fmt.Sprintf("%s::%s::%s::%s", pt.Key, pt.Value, pt.Op, pt.Context))
+		}
+		if !utils.CompareSliceWithoutOrder(declared, patterns) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parse a crd object in k8s like what's done when it's applied. Objects that fail the parsing are not compared
+func crdParseDriftObject(h *nvCrdHandler, obj interface{}) (*resource.NvSecurityParse, string, bool) {
+	var crdCfgRet *resource.NvSecurityParse
+	var errCount int
+	var recordName string
+
+	switch r := obj.(type) {
+	case *resource.NvSecurityRule:
+		crdCfgRet, errCount, _, recordName = h.parseCurCrdGfwContent(r, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
+	case *resource.NvClusterSecurityRule:
+		gfwrule := resource.NvSecurityRule(*r)
+		crdCfgRet, errCount, _, recordName = h.parseCurCrdGfwContent(&gfwrule, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
+	case *resource.NvAdmCtrlSecurityRule:
+		crdCfgRet, errCount, _, recordName = h.parseCurCrdAdmCtrlContent(r, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
+	case *resource.NvDlpSecurityRule:
+		crdCfgRet, errCount, _, recordName = h.parseCurCrdDlpContent(r, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
+	case *resource.NvWafSecurityRule:
+		crdCfgRet, errCount, _, recordName = h.parseCurCrdWafContent(r, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
+	case *resource.NvVulnProfileSecurityRule:
+		crdCfgRet, errCount, _, recordName = h.parseCurCrdVulnProfileContent(r, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
+	case *resource.NvCompProfileSecurityRule:
+		crdCfgRet, errCount, _, recordName = h.parseCurCrdCompProfileContent(r, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
+	default:
+		return nil, "", false
+	}
+
+	return crdCfgRet, recordName, errCount == 0 && crdCfgRet != nil
+}
+
+// Return the out-of-band modifications found for each crd kind. The map value is sorted
+func crdCollectDrift() map[string][]string {
+	acc := access.NewAdminAccessControl()
+	kindDrifts := make(map[string][]string)
+	for kind, info := range crdDriftKinds {
+		objs, err := global.ORCH.ListResource(info.rscType)
+		if err != nil {
+			log.WithFields(log.Fields{"rscType": info.rscType, "err": err}).Error()
+			continue
+		}
+
+		// parsing doesn't change the config so the lock is not acquired
+		var crdHandler nvCrdHandler
+		crdHandler.Init(info.lockKey)
+		for _, obj := range objs {
+			crdCfgRet, recordName, ok := crdParseDriftObject(&crdHandler, obj)
+			if !ok {
+				continue
+			}
+			var drifts []string
+			if record := clusHelper.GetCrdSecurityRuleRecord(info.kvCrdKind, recordName); record == nil {
+				drifts = append(drifts, "crd record is removed")
+			} else {
+				drifts = append(crdRecordDrift(kind, record, acc), crdSpecDrift(kind, record, crdCfgRet, acc)...)
+			}
+			for _, drift := range drifts {
+				kindDrifts[kind] = append(kindDrifts[kind], fmt.Sprintf("%s: %s", recordName, drift))
+			}
+		}
+	}
+	for _, drifts := range kindDrifts {
+		sort.Strings(drifts)
+	}
+
+	return kindDrifts
+}
+
+// Repair the crd config in kv by re-applying the crd resources in k8s, like what's done when the controller starts
+func checkCrdDrift() {
+	// crd requests not processed yet are not drift
+	if crdEventQueue := clusHelper.GetCrdEventQueue(); crdEventQueue != nil && len(crdEventQueue.CrdEventRecord) > 0 {
+		return
+	}
+
+	for kind, drifts := range crdCollectDrift() {
+		info := crdDriftKinds[kind]
+		log.WithFields(log.Fields{"kind": kind, "drifts": drifts}).Info("repair crd drift")
+		if err := CrossCheckCrd(kind, info.rscType, info.kvCrdKind, info.lockKey, false); err == nil {
+			e := fmt.Sprintf("CustomResourceDefinition %s drift detected and repaired", kind)
+			k8sResourceLog(share.CLUSEvCrdDriftRepaired, e, drifts)
+		}
+	}
+}
+
+// The lead controller periodically compares the config declared by crd resources with the config in kv
+func CrdDriftProc() {
+	cSig := make(chan os.Signal, 1)
+	signal.Notify(cSig, os.Interrupt, syscall.SIGTERM)
+	ticker := time.Tick(crdDriftCheckPeriod)
+Loop:
+	for {
+		select {
+		case <-ticker:
+			if atomic.LoadUint32(&_isLeader) == 1 {
+				checkCrdDrift()
+			}
+		case <-cSig:
+			break Loop
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package rest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/controller/resource"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestCrdRecordDrift(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(
+		[]*share.CLUSPolicyRule{
+			&share.CLUSPolicyRule{ID: 1001, From: "g1", To: "g2", CfgType: share.GroundCfg},
+			&share.CLUSPolicyRule{ID: 1002, From: "g2", To: "g1", CfgType: share.UserCreated},
+		},
+		[]*share.CLUSGroup{
+			&share.CLUSGroup{Name: "g1", CfgType: share.GroundCfg},
+			&share.CLUSGroup{Name: "g2", CfgType: share.UserCreated},
+		},
+	)
+	clusHelper = &mockCluster
+	acc := access.NewAdminAccessControl()
+
+	record := &share.CLUSCrdSecurityRule{
+		Name:   "NvSecurityRule-default-g1",
+		Groups: []string{"g1"},
+		Rules:  map[string]uint32{"r1": 1001},
+	}
+	if drifts := crdRecordDrift(resource.NvSecurityRuleKind, record, acc); len(drifts) != 0 {
+		t.Errorf("Unexpected drifts: %+v", drifts)
+	}
+
+	record.Groups = []string{"g1", "g2", "g3"}
+	record.Rules = map[string]uint32{"r1": 1001, "r2": 1002, "r3": 1003}
+	record.ProfileName = "g1"
+	record.ProcessRules = []share.CLUSCrdProcessRule{
+		share.CLUSCrdProcessRule{Name: "bash", Path: "/usr/bin/bash", Action: share.PolicyActionAllow},
+	}
+	drifts := crdRecordDrift(resource.NvSecurityRuleKind, record, acc)
+	expects := []string{"group g2 is not crd type", "group g3 is removed", "network rule 1002 is not crd type",
+		"network rule 1003 is removed", "process rule bash(/usr/bin/bash) of group g1 is modified"}
+	if len(drifts) != len(expects) {
+		t.Errorf("Unexpected drifts: %+v", drifts)
+	} else {
+		all := strings.Join(drifts, "\n")
+		for _, e := range expects {
+			if !strings.Contains(all, e) {
+				t.Errorf("Drift not found: %s, drifts=%+v", e, drifts)
+			}
+		}
+	}
+
+	postTest()
+}
+
+func TestCrdSpecDrift(t *testing.T) {
+	preTest()
+
+	var mockCluster vulProfileMockCluster
+	mockCluster.Init(
+		[]*share.CLUSPolicyRule{
+			&share.CLUSPolicyRule{ID: 1001, From: "g1", To: "g2", Ports: "tcp/80", Action: share.PolicyActionAllow, CfgType: share.GroundCfg},
+		},
+		[]*share.CLUSGroup{
+			&share.CLUSGroup{Name: "g1", CfgType: share.GroundCfg, Criteria: []share.CLUSCriteriaEntry{
+				{Key: "service", Value: "nginx", Op: share.CriteriaOpEqual},
+			}},
+		},
+	)
+	mockCluster.vp = &share.CLUSVulnerabilityProfile{
+		Name: share.DefaultVulnerabilityProfileName,
+		Entries: []*share.CLUSVulnerabilityProfileEntry{
+			{ID: 1, Name: "CVE-2021-0001", Comment: "crd", Domains: []string{}, Images: []string{}, CfgType: share.GroundCfg},
+			{ID: 2, Name: "CVE-2021-0002", Comment: "user", Domains: []string{}, Images: []string{}, CfgType: share.UserCreated},
+		},
+	}
+	mockCluster.PutComplianceProfile(&share.CLUSComplianceProfile{
+		Name: share.DefaultComplianceProfileName,
+		Entries: map[string]share.CLUSComplianceProfileEntry{
+			"D.1.1.1": {TestNum: "D.1.1.1", Tags: []string{"HIPAA", "PCI"}, CfgType: share.GroundCfg},
+			"D.1.2.1": {TestNum: "D.1.2.1", Tags: []string{"PCI"}, CfgType: share.UserCreated},
+		},
+	}, 0)
+	clusHelper = &mockCluster
+	acc := access.NewAdminAccessControl()
+
+	// network rules and groups
+	record := &share.CLUSCrdSecurityRule{
+		Name:   "NvSecurityRule-default-g1",
+		Groups: []string{"g1"},
+		Rules:  map[string]uint32{"r1": 1001},
+	}
+	from, to, ports, action, comment := "g1", "g2", "tcp/80", share.PolicyActionAllow, "r1"
+	criteria := []api.RESTCriteriaEntry{{Key: "service", Value: "nginx", Op: share.CriteriaOpEqual}}
+	crdCfgRet := &resource.NvSecurityParse{
+		GroupCfgs: []api.RESTCrdGroupConfig{{Name: "g1", Criteria: &criteria}},
+		RuleCfgs:  []api.RESTPolicyRuleConfig{{From: &from, To: &to, Ports: &ports, Action: &action, Comment: &comment}},
+	}
+	if drifts := crdSpecDrift(resource.NvSecurityRuleKind, record, crdCfgRet, acc); len(drifts) != 0 {
+		t.Errorf("Unexpected drifts: %+v", drifts)
+	}
+
+	ports = "tcp/8080"
+	criteria[0].Value = "redis"
+	drifts := crdSpecDrift(resource.NvSecurityRuleKind, record, crdCfgRet, acc)
+	if len(drifts) != 2 || drifts[0] != "group g1 is modified" || drifts[1] != "network rule 1001 is modified" {
+		t.Errorf("Unexpected drifts: %+v", drifts)
+	}
+
+	// vulnerability profile entries defined by crd, the user entry is not compared
+	record = &share.CLUSCrdSecurityRule{Name: "NvVulnerabilityProfile-default-default"}
+	crdCfgRet = &resource.NvSecurityParse{
+		VulnProfileCfg: &api.RESTVulnerabilityProfileConfig{
+			Name:    share.DefaultVulnerabilityProfileName,
+			Entries: &[]*api.RESTVulnerabilityProfileEntry{{Name: "CVE-2021-0001", Comment: "crd"}},
+		},
+	}
+	if drifts := crdSpecDrift(resource.NvVulnProfileKind, record, crdCfgRet, acc); len(drifts) != 0 {
+		t.Errorf("Unexpected drifts: %+v", drifts)
+	}
+
+	mockCluster.vp.Entries[0].Comment = "modified"
+	mockCluster.vp.Entries = append(mockCluster.vp.Entries, &share.CLUSVulnerabilityProfileEntry{ID: 3, Name: "CVE-2021-0003", CfgType: share.GroundCfg})
+	(*crdCfgRet.VulnProfileCfg.Entries) = append(*crdCfgRet.VulnProfileCfg.Entries, &api.RESTVulnerabilityProfileEntry{Name: "CVE-2021-0004"})
+	drifts = crdSpecDrift(resource.NvVulnProfileKind, record, crdCfgRet, acc)
+	expects := []string{"vulnerability profile entry CVE-2021-0001 is modified", "vulnerability profile entry CVE-2021-0003 is added",
+		"vulnerability profile entry CVE-2021-0004 is removed"}
+	if all := strings.Join(drifts, "\n"); len(drifts) != len(expects) {
+		t.Errorf("Unexpected drifts: %+v", drifts)
+	} else {
+		for _, e := range expects {
+			if !strings.Contains(all, e) {
+				t.Errorf("Drift not found: %s, drifts=%+v", e, drifts)
+			}
+		}
+	}
+
+	// compliance profile entries defined by crd, the tags are compared without order
+	disableSystem := false
+	record = &share.CLUSCrdSecurityRule{Name: "NvComplianceProfile-default-default"}
+	crdCfgRet = &resource.NvSecurityParse{
+		CompProfileCfg: &api.RESTComplianceProfileConfig{
+			Name:          share.DefaultComplianceProfileName,
+			DisableSystem: &disableSystem,
+			Entries:       &[]*api.RESTComplianceProfileEntry{{TestNum: "D.1.1.1", Tags: []string{"PCI", "HIPAA"}}},
+		},
+	}
+	if drifts := crdSpecDrift(resource.NvCompProfileKind, record, crdCfgRet, acc); len(drifts) != 0 {
+		t.Errorf("Unexpected drifts: %+v", drifts)
+	}
+
+	disableSystem = true
+	(*crdCfgRet.CompProfileCfg.Entries)[0].Tags = []string{"PCI"}
+	drifts = crdSpecDrift(resource.NvCompProfileKind, record, crdCfgRet, acc)
+	expects = []string{"compliance profile disable_system is modified", "compliance profile entry D.1.1.1 is modified"}
+	if all := strings.Join(drifts, "\n"); len(drifts) != len(expects) {
+		t.Errorf("Unexpected drifts: %+v", drifts)
+	} else {
+		for _, e := range expects {
+			if !strings.Contains(all, e) {
+				t.Errorf("Drift not found: %s, drifts=%+v", e, drifts)
+			}
+		}
+	}
+
+	postTest()
+}
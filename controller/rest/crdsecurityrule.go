@@ -160,11 +160,19 @@ func (h *nvCrdHandler) crdDelAll(k8sKind, kvCrdKind string, recordList map[strin
 			h.crdHandleGroupRecordDel(gw, gw.Groups, false)
 			h.crdDeleteRecordEx(resource.NvSecurityRuleKind, recordName, gw.ProfileName)
 		case resource.NvDlpSecurityRuleKind:
+			h.crdHandleDlpSensorGroups(gw.DlpSensor, nil, gw.DlpSensorGroups)
 			deleteDlpSensor(nil, gw.DlpSensor, share.ReviewTypeCRD, true, h.acc, nil)
 			h.crdDeleteRecord(k8sKind, recordName)
 		case resource.NvWafSecurityRuleKind:
+			h.crdHandleWafSensorGroups(gw.WafSensor, nil, gw.WafSensorGroups)
 			deleteWafSensor(nil, gw.WafSensor, share.ReviewTypeCRD, true, h.acc, nil)
 			h.crdDeleteRecord(k8sKind, recordName)
+		case resource.NvVulnProfileKind:
+			h.crdHandleVulnProfileEntries(nil)
+			h.crdDeleteRecord(k8sKind, recordName)
+		case resource.NvCompProfileKind:
+			h.crdHandleCompProfileEntries(nil)
+			h.crdDeleteRecord(k8sKind, recordName)
 		}
 	}
 
@@ -1062,7 +1070,12 @@ func (h *nvCrdHandler) crdHandleAdmCtrlRules(scope string, allAdmCtrlRules map[s
 					}
 				} else {
 					// it's non-default rule
-					cr.Criteria, _ = cache.AdmCriteria2CLUS(ruleConf.Criteria)
+					var err error
+					if cr.Criteria, err = cache.AdmCriteria2CLUS(ruleConf.Criteria); err == nil {
+						// same as rest api, it fills the default sub-criteria of some criteria, like cveScoreCount
+						options := nvsysadmission.GetAdmRuleTypeOptions(ruleType)
+						validateAdmCtrlCriteria(cr.Criteria, options.K8sOptions.RuleOptions, ruleType)
+					}
 					cr.Comment = ruleConf.Comment
 				}
 				cr.RuleMode = ruleConf.RuleMode
@@ -1222,6 +1235,67 @@ func (h *nvCrdHandler) crdHandleDlpGroup(name string, dlpGroupCfg *api.RESTCrdDl
 	return nil
 }
 
+// Apply a dlp sensor to the groups in bindings(group name -> action) and remove it from the groups that are in oldGroups but not in bindings.
+// Other sensors of the groups are kept. Return the groups that the sensor is applied to. caller must own CLUSLockPolicyKey lock
+// The dlp sensors of a group targeted by NvSecurityRule are defined by that NvSecurityRule only, so such groups are not changed here.
+func (h *nvCrdHandler) crdHandleDlpSensorGroups(sensor string, bindings map[string]string, oldGroups []string) []string {
+	groups := make([]string, 0, len(bindings))
+	for _, name := range oldGroups {
+		if _, ok := bindings[name]; ok {
+			continue
+		}
+		if dlpGroup := clusHelper.GetDlpGroup(name); dlpGroup != nil && dlpGroup.CfgType != share.GroundCfg {
+			list := make([]*share.CLUSDlpSetting, 0, len(dlpGroup.Sensors))
+			for _, cs := range dlpGroup.Sensors {
+				if cs.Name != sensor {
+					list = append(list, cs)
+				}
+			}
+			if len(list) != len(dlpGroup.Sensors) {
+				dlpGroup.Sensors = list
+				clusHelper.PutDlpGroup(dlpGroup, false)
+			}
+		}
+	}
+	for name, action := range bindings {
+		dlpGroup := clusHelper.GetDlpGroup(name)
+		if dlpGroup == nil {
+			log.WithFields(log.Fields{"group": name, "sensor": sensor}).Error("Dlp group doesn't exist")
+			continue
+		} else if dlpGroup.CfgType == share.GroundCfg {
+			log.WithFields(log.Fields{"group": name, "sensor": sensor}).Info("Dlp group is defined by NvSecurityRule")
+			continue
+		}
+		cs := &share.CLUSDlpSetting{Name: sensor, Action: action}
+		if ret, ok := common.MergeDlpSensors(dlpGroup.Sensors, cs); ok {
+			dlpGroup.Sensors = ret
+			clusHelper.PutDlpGroup(dlpGroup, false)
+		}
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+
+	return groups
+}
+
+// NvSecurityRule takes precedence over NvDlpSecurityRule for the dlp sensors of its target group. When NvSecurityRule takes over
+// a group, the group is removed from the bindings of NvDlpSecurityRule records. caller must own CLUSLockPolicyKey lock
+func (h *nvCrdHandler) crdReleaseDlpSensorGroup(name string) {
+	if dlpGroup := clusHelper.GetDlpGroup(name); dlpGroup == nil || dlpGroup.CfgType == share.GroundCfg {
+		return
+	}
+	for recordName, crdRecord := range clusHelper.GetCrdSecurityRuleRecordList(resource.NvDlpSecurityRuleKind) {
+		for i, g := range crdRecord.DlpSensorGroups {
+			if g == name {
+				crdRecord.DlpSensorGroups = append(crdRecord.DlpSensorGroups[:i], crdRecord.DlpSensorGroups[i+1:]...)
+				clusHelper.PutCrdSecurityRuleRecord(resource.NvDlpSecurityRuleKind, recordName, crdRecord)
+				log.WithFields(log.Fields{"group": name, "sensor": crdRecord.DlpSensor}).Info("Dlp sensor binding is replaced by NvSecurityRule")
+				break
+			}
+		}
+	}
+}
+
 // caller must own CLUSLockPolicyKey lock
 func (h *nvCrdHandler) crdHandleDlpSensor(scope string, dlpSensorConf *api.RESTDlpSensorConfig,
 	cacheRecord *share.CLUSCrdSecurityRule, reviewType share.TReviewType) error {
@@ -1290,6 +1364,67 @@ func (h *nvCrdHandler) crdHandleWafGroup(name string, wafGroupCfg *api.RESTCrdWa
 	return nil
 }
 
+// Apply a waf sensor to the groups in bindings(group name -> action) and remove it from the groups that are in oldGroups but not in bindings.
+// Other sensors of the groups are kept. Return the groups that the sensor is applied to. caller must own CLUSLockPolicyKey lock
+// The waf sensors of a group targeted by NvSecurityRule are defined by that NvSecurityRule only, so such groups are not changed here.
+func (h *nvCrdHandler) crdHandleWafSensorGroups(sensor string, bindings map[string]string, oldGroups []string) []string {
+	groups := make([]string, 0, len(bindings))
+	for _, name := range oldGroups {
+		if _, ok := bindings[name]; ok {
+			continue
+		}
+		if wafGroup := clusHelper.GetWafGroup(name); wafGroup != nil && wafGroup.CfgType != share.GroundCfg {
+			list := make([]*share.CLUSWafSetting, 0, len(wafGroup.Sensors))
+			for _, cs := range wafGroup.Sensors {
+				if cs.Name != sensor {
+					list = append(list, cs)
+				}
+			}
+			if len(list) != len(wafGroup.Sensors) {
+				wafGroup.Sensors = list
+				clusHelper.PutWafGroup(wafGroup, false)
+			}
+		}
+	}
+	for name, action := range bindings {
+		wafGroup := clusHelper.GetWafGroup(name)
+		if wafGroup == nil {
+			log.WithFields(log.Fields{"group": name, "sensor": sensor}).Error("Waf group doesn't exist")
+			continue
+		} else if wafGroup.CfgType == share.GroundCfg {
+			log.WithFields(log.Fields{"group": name, "sensor": sensor}).Info("Waf group is defined by NvSecurityRule")
+			continue
+		}
+		cs := &share.CLUSWafSetting{Name: sensor, Action: action}
+		if ret, ok := common.MergeWafSensors(wafGroup.Sensors, cs); ok {
+			wafGroup.Sensors = ret
+			clusHelper.PutWafGroup(wafGroup, false)
+		}
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+
+	return groups
+}
+
+// NvSecurityRule takes precedence over NvWafSecurityRule for the waf sensors of its target group. When NvSecurityRule takes over
+// a group, the group is removed from the bindings of NvWafSecurityRule records. caller must own CLUSLockPolicyKey lock
+func (h *nvCrdHandler) crdReleaseWafSensorGroup(name string) {
+	if wafGroup := clusHelper.GetWafGroup(name); wafGroup == nil || wafGroup.CfgType == share.GroundCfg {
+		return
+	}
+	for recordName, crdRecord := range clusHelper.GetCrdSecurityRuleRecordList(resource.NvWafSecurityRuleKind) {
+		for i, g := range crdRecord.WafSensorGroups {
+			if g == name {
+				crdRecord.WafSensorGroups = append(crdRecord.WafSensorGroups[:i], crdRecord.WafSensorGroups[i+1:]...)
+				clusHelper.PutCrdSecurityRuleRecord(resource.NvWafSecurityRuleKind, recordName, crdRecord)
+				log.WithFields(log.Fields{"group": name, "sensor": crdRecord.WafSensor}).Info("Waf sensor binding is replaced by NvSecurityRule")
+				break
+			}
+		}
+	}
+}
+
 // caller must own CLUSLockPolicyKey lock
 func (h *nvCrdHandler) crdHandleWafSensor(scope string, wafSensorConf *api.RESTWafSensorConfig,
 	cacheRecord *share.CLUSCrdSecurityRule, reviewType share.TReviewType) error {
@@ -2290,6 +2425,35 @@ func (h *nvCrdHandler) parseCurCrdDlpContent(dlpSecRule *resource.NvDlpSecurityR
 		}
 	}
 
+	if len(dlpSecRule.Spec.Groups) > 0 {
+		crdCfgRet.DlpSensorGroups = make(map[string]string, len(dlpSecRule.Spec.Groups))
+		for _, binding := range dlpSecRule.Spec.Groups {
+			if binding == nil {
+				continue
+			}
+			if binding.Action != share.PolicyActionAllow && binding.Action != share.PolicyActionDeny {
+				errMsg := fmt.Sprintf("%s file format error:  unsupported action %s for group %s", reviewTypeDisplay, binding.Action, binding.Name)
+				return nil, 1, errMsg, recordName
+			}
+			if _, ok := crdCfgRet.DlpSensorGroups[binding.Name]; ok {
+				errMsg := fmt.Sprintf("%s file format error:  duplicate group %s", reviewTypeDisplay, binding.Name)
+				return nil, 1, errMsg, recordName
+			}
+			if g, _, _ := clusHelper.GetGroup(binding.Name, access.NewReaderAccessControl()); g == nil {
+				errMsg := fmt.Sprintf("%s file format error:  group %s does not exist", reviewTypeDisplay, binding.Name)
+				return nil, 1, errMsg, recordName
+			} else if g.Kind != share.GroupKindContainer {
+				errMsg := fmt.Sprintf("%s file format error:  group %s cannot have DLP policy", reviewTypeDisplay, binding.Name)
+				return nil, 1, errMsg, recordName
+			}
+			if dlpGroup := clusHelper.GetDlpGroup(binding.Name); dlpGroup != nil && dlpGroup.CfgType == share.GroundCfg {
+				errMsg := fmt.Sprintf("%s file format error:  DLP policy of group %s is defined by NvSecurityRule", reviewTypeDisplay, binding.Name)
+				return nil, 1, errMsg, recordName
+			}
+			crdCfgRet.DlpSensorGroups[binding.Name] = binding.Action
+		}
+	}
+
 	return crdCfgRet, errCount, buffer.String(), recordName
 }
 
@@ -2361,9 +2525,141 @@ func (h *nvCrdHandler) parseCurCrdWafContent(wafSecRule *resource.NvWafSecurityR
 		}
 	}
 
+	if len(wafSecRule.Spec.Groups) > 0 {
+		crdCfgRet.WafSensorGroups = make(map[string]string, len(wafSecRule.Spec.Groups))
+		for _, binding := range wafSecRule.Spec.Groups {
+			if binding == nil {
+				continue
+			}
+			if binding.Action != share.PolicyActionAllow && binding.Action != share.PolicyActionDeny {
+				errMsg := fmt.Sprintf("%s file format error:  unsupported action %s for group %s", reviewTypeDisplay, binding.Action, binding.Name)
+				return nil, 1, errMsg, recordName
+			}
+			if _, ok := crdCfgRet.WafSensorGroups[binding.Name]; ok {
+				errMsg := fmt.Sprintf("%s file format error:  duplicate group %s", reviewTypeDisplay, binding.Name)
+				return nil, 1, errMsg, recordName
+			}
+			if g, _, _ := clusHelper.GetGroup(binding.Name, access.NewReaderAccessControl()); g == nil {
+				errMsg := fmt.Sprintf("%s file format error:  group %s does not exist", reviewTypeDisplay, binding.Name)
+				return nil, 1, errMsg, recordName
+			} else if g.Kind != share.GroupKindContainer {
+				errMsg := fmt.Sprintf("%s file format error:  group %s cannot have WAF policy", reviewTypeDisplay, binding.Name)
+				return nil, 1, errMsg, recordName
+			}
+			if wafGroup := clusHelper.GetWafGroup(binding.Name); wafGroup != nil && wafGroup.CfgType == share.GroundCfg {
+				errMsg := fmt.Sprintf("%s file format error:  WAF policy of group %s is defined by NvSecurityRule", reviewTypeDisplay, binding.Name)
+				return nil, 1, errMsg, recordName
+			}
+			crdCfgRet.WafSensorGroups[binding.Name] = binding.Action
+		}
+	}
+
 	return crdCfgRet, errCount, buffer.String(), recordName
 }
 
+// for CRD vulnerability profile import
+func (h *nvCrdHandler) parseCurCrdVulnProfileContent(vpSecRule *resource.NvVulnProfileSecurityRule, reviewType share.TReviewType,
+	reviewTypeDisplay string) (*resource.NvSecurityParse, int, string, string) {
+
+	if vpSecRule != nil && vpSecRule.Metadata != nil {
+		h.crUid = vpSecRule.Metadata.GetUid()
+	}
+	if vpSecRule == nil || vpSecRule.Metadata == nil || vpSecRule.Metadata.Name == nil {
+		errMsg := fmt.Sprintf("%s file format error:  validation error", reviewTypeDisplay)
+		return nil, 1, errMsg, ""
+	}
+
+	name := *vpSecRule.Metadata.Name
+	recordName := fmt.Sprintf("%s-default-%s", *vpSecRule.Kind, name)
+	if name != share.DefaultVulnerabilityProfileName {
+		errMsg := fmt.Sprintf("%s file format error:  only the %s vulnerability profile is allowed", reviewTypeDisplay, share.DefaultVulnerabilityProfileName)
+		return nil, 1, errMsg, recordName
+	}
+
+	names := utils.NewSet()
+	entries := make([]*api.RESTVulnerabilityProfileEntry, 0, len(vpSecRule.Spec.Entries))
+	for _, e := range vpSecRule.Spec.Entries {
+		if e == nil {
+			continue
+		}
+		re := &api.RESTVulnerabilityProfileEntry{
+			Name:    e.Name,
+			Comment: e.Comment,
+			Days:    e.Days,
+			Domains: e.Domains,
+			Images:  e.Images,
+		}
+		if _, err := checkVulnerabilityProfileEntry(re); err != nil {
+			errMsg := fmt.Sprintf("%s file format error:  entry %s: %s", reviewTypeDisplay, e.Name, err.Error())
+			return nil, 1, errMsg, recordName
+		}
+		if names.Contains(e.Name) {
+			errMsg := fmt.Sprintf("%s file format error:  duplicate entry %s", reviewTypeDisplay, e.Name)
+			return nil, 1, errMsg, recordName
+		}
+		names.Add(e.Name)
+		entries = append(entries, re)
+	}
+
+	crdCfgRet := &resource.NvSecurityParse{
+		VulnProfileCfg: &api.RESTVulnerabilityProfileConfig{
+			Name:    name,
+			Entries: &entries,
+		},
+	}
+
+	return crdCfgRet, 0, "", recordName
+}
+
+// for CRD compliance profile import
+func (h *nvCrdHandler) parseCurCrdCompProfileContent(cpSecRule *resource.NvCompProfileSecurityRule, reviewType share.TReviewType,
+	reviewTypeDisplay string) (*resource.NvSecurityParse, int, string, string) {
+
+	if cpSecRule != nil && cpSecRule.Metadata != nil {
+		h.crUid = cpSecRule.Metadata.GetUid()
+	}
+	if cpSecRule == nil || cpSecRule.Metadata == nil || cpSecRule.Metadata.Name == nil {
+		errMsg := fmt.Sprintf("%s file format error:  validation error", reviewTypeDisplay)
+		return nil, 1, errMsg, ""
+	}
+
+	name := *cpSecRule.Metadata.Name
+	recordName := fmt.Sprintf("%s-default-%s", *cpSecRule.Kind, name)
+	if name != share.DefaultComplianceProfileName {
+		errMsg := fmt.Sprintf("%s file format error:  only the %s compliance profile is allowed", reviewTypeDisplay, share.DefaultComplianceProfileName)
+		return nil, 1, errMsg, recordName
+	}
+
+	// validate the entries with a scratch profile
+	ccp := &share.CLUSComplianceProfile{Entries: make(map[string]share.CLUSComplianceProfileEntry)}
+	entries := make([]*api.RESTComplianceProfileEntry, 0, len(cpSecRule.Spec.Entries))
+	for _, e := range cpSecRule.Spec.Entries {
+		if e == nil {
+			continue
+		}
+		if _, ok := ccp.Entries[e.TestNum]; ok {
+			errMsg := fmt.Sprintf("%s file format error:  duplicate entry %s", reviewTypeDisplay, e.TestNum)
+			return nil, 1, errMsg, recordName
+		}
+		re := &api.RESTComplianceProfileEntry{TestNum: e.TestNum, Tags: e.Tags}
+		if err := configComplianceProfileEntry(ccp, re); err != nil {
+			errMsg := fmt.Sprintf("%s file format error:  entry %s: %s", reviewTypeDisplay, e.TestNum, err.Error())
+			return nil, 1, errMsg, recordName
+		}
+		entries = append(entries, re)
+	}
+
+	crdCfgRet := &resource.NvSecurityParse{
+		CompProfileCfg: &api.RESTComplianceProfileConfig{
+			Name:          name,
+			DisableSystem: cpSecRule.Spec.DisableSystem,
+			Entries:       &entries,
+		},
+	}
+
+	return crdCfgRet, 0, "", recordName
+}
+
 // Process the group and network rule list get from the crd. caller must own CLUSLockPolicyKey lock
 func (h *nvCrdHandler) crdGFwRuleProcessRecord(crdCfgRet *resource.NvSecurityParse, kind, recordName string) (string, string) {
 	crdRecord := clusHelper.GetCrdSecurityRuleRecord(kind, recordName)
@@ -2411,6 +2707,8 @@ func (h *nvCrdHandler) crdGFwRuleProcessRecord(crdCfgRet *resource.NvSecurityPar
 		if crdCfgRet.WafGroupCfg == nil {
 			crdCfgRet.WafGroupCfg = &api.RESTCrdWafGroupConfig{RepSensors: make([]api.RESTCrdWafGroupSetting, 0)}
 		}
+		h.crdReleaseDlpSensorGroup(crdCfgRet.TargetName)
+		h.crdReleaseWafSensorGroup(crdCfgRet.TargetName)
 		crdRecord.DlpGroupSensors = h.crdHandleDlpGroup(crdCfgRet.TargetName, crdCfgRet.DlpGroupCfg, share.GroundCfg)
 		crdRecord.WafGroupSensors = h.crdHandleWafGroup(crdCfgRet.TargetName, crdCfgRet.WafGroupCfg, share.GroundCfg)
 	}
@@ -2475,11 +2773,13 @@ func (h *nvCrdHandler) crdDlpSensorRecord(crdCfgRet *resource.NvSecurityParse, k
 	log.WithFields(log.Fields{"name": recordName}).Debug()
 	// handle dlp part of crd (dlp sensor definition, not per-group's sensors association)
 	h.crdHandleDlpSensor(share.ScopeLocal, crdCfgRet.DlpSensorCfg, crdRecord, share.ReviewTypeCRD)
+	// handle the groups that this dlp sensor is applied to
+	crdRecord.DlpSensorGroups = h.crdHandleDlpSensorGroups(crdCfgRet.DlpSensorCfg.Name, crdCfgRet.DlpSensorGroups, crdRecord.DlpSensorGroups)
 	clusHelper.PutCrdSecurityRuleRecord(kind, recordName, crdRecord)
 }
 
 // Process WAF sensor get from the crd. caller must own CLUSLockPolicyKey lock
-func (h *nvCrdHandler) crdWafSensorRecord(crdCfgRet *resource.NvSecurityParse, kind, recordName string) {
+func (h *nvCrdHandler) crdWafSensorRecord(crdCfgRet *resource.NvSecurityParse, kind, recordName string) error {
 	crdRecord := clusHelper.GetCrdSecurityRuleRecord(kind, recordName)
 	if crdRecord == nil {
 		crdRecord = &share.CLUSCrdSecurityRule{
@@ -2491,8 +2791,255 @@ func (h *nvCrdHandler) crdWafSensorRecord(crdCfgRet *resource.NvSecurityParse, k
 
 	log.WithFields(log.Fields{"name": recordName}).Debug()
 	// handle waf part of crd (waf sensor definition, not per-group's sensors association)
-	h.crdHandleWafSensor(share.ScopeLocal, crdCfgRet.WafSensorCfg, crdRecord, share.ReviewTypeCRD)
+	if err := h.crdHandleWafSensor(share.ScopeLocal, crdCfgRet.WafSensorCfg, crdRecord, share.ReviewTypeCRD); err != nil {
+		return err
+	}
+	// handle the groups that this waf sensor is applied to
+	crdRecord.WafSensorGroups = h.crdHandleWafSensorGroups(crdCfgRet.WafSensorCfg.Name, crdCfgRet.WafSensorGroups, crdRecord.WafSensorGroups)
 	clusHelper.PutCrdSecurityRuleRecord(kind, recordName, crdRecord)
+
+	return nil
+}
+
+// A crd entry cannot have the same name as a user created entry, the crd is rejected so the user created entry is not lost.
+// When rvp is nil, all entries defined by crd are removed.
+func (h *nvCrdHandler) crdHandleVulnProfileEntries(rvp *api.RESTVulnerabilityProfileConfig) error {
+	crdEntries := make([]*api.RESTVulnerabilityProfileEntry, 0)
+	if rvp != nil && rvp.Entries != nil {
+		crdEntries = *rvp.Entries
+	}
+	crdNames := utils.NewSet()
+	for _, re := range crdEntries {
+		crdNames.Add(re.Name)
+	}
+
+	retry := 0
+	for retry < retryClusterMax {
+		cvp, rev, err := clusHelper.GetVulnerabilityProfile(share.DefaultVulnerabilityProfileName, h.acc)
+		if cvp == nil {
+			return err
+		}
+
+		ids := utils.NewSet()
+		oldIDs := make(map[string]uint32)
+		entries := make([]*share.CLUSVulnerabilityProfileEntry, 0, len(cvp.Entries)+len(crdEntries))
+		for _, ce := range cvp.Entries {
+			ids.Add(ce.ID)
+			if ce.CfgType == share.GroundCfg {
+				oldIDs[ce.Name] = ce.ID
+			} else if crdNames.Contains(ce.Name) {
+				return fmt.Errorf("Entry %s is already defined by REST API", ce.Name)
+			} else {
+				entries = append(entries, ce)
+			}
+		}
+		for _, re := range crdEntries {
+			ce, err := checkVulnerabilityProfileEntry(re)
+			if err != nil {
+				return err
+			}
+			if id, ok := oldIDs[ce.Name]; ok {
+				ce.ID = id
+			} else if ce.ID = getAvailableRuleID(ruleTypeVulProf, ids, share.GroundCfg); ce.ID == 0 {
+				return errors.New("Failed to locate available entry ID")
+			}
+			ids.Add(ce.ID)
+			ce.CfgType = share.GroundCfg
+			entries = append(entries, ce)
+		}
+		cvp.Entries = entries
+
+		if err := clusHelper.PutVulnerabilityProfile(cvp, rev); err != nil {
+			log.WithFields(log.Fields{"error": err, "rev": rev}).Error()
+			retry++
+		} else {
+			break
+		}
+	}
+	if retry >= retryClusterMax {
+		return errors.New("Failed to write to the cluster")
+	}
+
+	return nil
+}
+
+// A crd entry cannot have the same test number as a user created entry, the crd is rejected so the user created entry is not lost.
+// When rcp is nil, all entries defined by crd are removed and the disable_system value configured by REST API is restored.
+func (h *nvCrdHandler) crdHandleCompProfileEntries(rcp *api.RESTComplianceProfileConfig) error {
+	retry := 0
+	for retry < retryClusterMax {
+		ccp, rev, err := clusHelper.GetComplianceProfile(share.DefaultComplianceProfileName, h.acc)
+		if ccp == nil {
+			return err
+		}
+
+		for testNum, ce := range ccp.Entries {
+			if ce.CfgType == share.GroundCfg {
+				delete(ccp.Entries, testNum)
+			}
+		}
+		if ccp.UserDisableSystem != nil {
+			ccp.DisableSystem = *ccp.UserDisableSystem
+			ccp.UserDisableSystem = nil
+		}
+		if rcp != nil {
+			if rcp.DisableSystem != nil {
+				userDisableSystem := ccp.DisableSystem
+				ccp.UserDisableSystem = &userDisableSystem
+				ccp.DisableSystem = *rcp.DisableSystem
+			}
+			if rcp.Entries != nil {
+				for _, re := range *rcp.Entries {
+					if _, ok := ccp.Entries[re.TestNum]; ok {
+						return fmt.Errorf("Entry %s is already defined by REST API", re.TestNum)
+					}
+					if err := configComplianceProfileEntry(ccp, re); err != nil {
+						return err
+					}
+					ce := ccp.Entries[re.TestNum]
+					ce.CfgType = share.GroundCfg
+					ccp.Entries[re.TestNum] = ce
+				}
+			}
+		}
+
+		if err := clusHelper.PutComplianceProfile(ccp, rev); err != nil {
+			log.WithFields(log.Fields{"error": err, "rev": rev}).Error()
+			retry++
+		} else {
+			break
+		}
+	}
+	if retry >= retryClusterMax {
+		return errors.New("Failed to write to the cluster")
+	}
+
+	return nil
+}
+
+// Process vulnerability/compliance profile get from the crd. caller must own CLUSLockPolicyKey lock
+func (h *nvCrdHandler) crdProfileRecord(crdCfgRet *resource.NvSecurityParse, kind, recordName string) error {
+	var err error
+	switch kind {
+	case resource.NvVulnProfileKind:
+		err = h.crdHandleVulnProfileEntries(crdCfgRet.VulnProfileCfg)
+	case resource.NvCompProfileKind:
+		err = h.crdHandleCompProfileEntries(crdCfgRet.CompProfileCfg)
+	}
+	if err != nil {
+		return err
+	}
+
+	crdRecord := clusHelper.GetCrdSecurityRuleRecord(kind, recordName)
+	if crdRecord == nil {
+		crdRecord = &share.CLUSCrdSecurityRule{
+			Name: recordName,
+			Uid:  h.crUid,
+		}
+	}
+	log.WithFields(log.Fields{"name": recordName}).Debug()
+	clusHelper.PutCrdSecurityRuleRecord(kind, recordName, crdRecord)
+
+	return nil
+}
+
+// Update the condition with the same type. The transition time is kept if the condition status is not changed
+func setCrdCondition(status *resource.NvSecurityRuleStatus, condType, condStatus, reason, msg, now string) {
+	cond := &resource.NvCrdCondition{
+		Type:               condType,
+		Status:             condStatus,
+		Reason:             reason,
+		Message:            msg,
+		LastTransitionTime: now,
+	}
+	for i, c := range status.Conditions {
+		if c.Type == condType {
+			if c.Status == condStatus {
+				cond.LastTransitionTime = c.LastTransitionTime
+			}
+			status.Conditions[i] = cond
+			return
+		}
+	}
+	status.Conditions = append(status.Conditions, cond)
+}
+
+// inEffect is true when a previously accepted spec of the object is still in effect after the current spec is rejected
+func setCrdSecRuleConditions(status *resource.NvSecurityRuleStatus, errMsg, warning string, inEffect bool, now string) {
+	if errMsg != "" {
+		setCrdCondition(status, resource.NvCrdConditionAccepted, resource.NvCrdConditionFalse, "ValidationFailed", errMsg, now)
+		if inEffect {
+			setCrdCondition(status, resource.NvCrdConditionSynced, resource.NvCrdConditionTrue, "PreviousSpecInEffect",
+				"The last accepted spec is still in effect", now)
+		} else {
+			setCrdCondition(status, resource.NvCrdConditionSynced, resource.NvCrdConditionFalse, "Rejected", "", now)
+		}
+		setCrdCondition(status, resource.NvCrdConditionError, resource.NvCrdConditionTrue, "ValidationFailed", errMsg, now)
+	} else {
+		setCrdCondition(status, resource.NvCrdConditionAccepted, resource.NvCrdConditionTrue, "Validated", "", now)
+		setCrdCondition(status, resource.NvCrdConditionSynced, resource.NvCrdConditionTrue, "Applied", "", now)
+		if warning != "" {
+			setCrdCondition(status, resource.NvCrdConditionError, resource.NvCrdConditionTrue, "Warning", warning, now)
+		} else {
+			setCrdCondition(status, resource.NvCrdConditionError, resource.NvCrdConditionFalse, "NoError", "", now)
+		}
+	}
+}
+
+// Set the conditions in the status of a NvSecurityRule/NvClusterSecurityRule/NvWafSecurityRule object. Return false for other objects
+func setCrdObjectStatus(obj interface{}, errMsg, warning string, inEffect bool, now string) bool {
+	var metadata *cmetav1.ObjectMeta
+	var status **resource.NvSecurityRuleStatus
+	switch r := obj.(type) {
+	case *resource.NvSecurityRule:
+		metadata, status = r.Metadata, &r.Status
+	case *resource.NvClusterSecurityRule:
+		metadata, status = r.Metadata, &r.Status
+	case *resource.NvWafSecurityRule:
+		metadata, status = r.Metadata, &r.Status
+	default:
+		return false
+	}
+	if *status == nil {
+		*status = &resource.NvSecurityRuleStatus{}
+	}
+
+	setCrdSecRuleConditions(*status, errMsg, warning, inEffect, now)
+	(*status).ObservedGeneration = metadata.GetGeneration()
+	return true
+}
+
+// Report the processing result in the status subresource of the NvSecurityRule/NvClusterSecurityRule/NvWafSecurityRule object
+func (h *nvCrdHandler) crdUpdateSecRuleStatus(kind, ns, name, errMsg, warning string) {
+	var rscType, kvCrdKind, recordName string
+	switch kind {
+	case resource.NvSecurityRuleKind:
+		rscType, kvCrdKind = resource.RscTypeCrdSecurityRule, resource.NvSecurityRuleKind
+		recordName = fmt.Sprintf("%s-%s-%s", kind, ns, name)
+	case resource.NvClusterSecurityRuleKind:
+		rscType, kvCrdKind = resource.RscTypeCrdClusterSecurityRule, resource.NvSecurityRuleKind
+		recordName = fmt.Sprintf("%s-default-%s", kind, name)
+		ns = ""
+	case resource.NvWafSecurityRuleKind:
+		rscType, kvCrdKind = resource.RscTypeCrdWafSecurityRule, resource.NvWafSecurityRuleKind
+		recordName = fmt.Sprintf("%s-default-%s", kind, name)
+		ns = ""
+	default:
+		return
+	}
+	obj, err := global.ORCH.GetResource(rscType, ns, name)
+	if err != nil {
+		log.WithFields(log.Fields{"kind": kind, "name": name, "error": err}).Error("Failed to get crd")
+		return
+	}
+
+	inEffect := clusHelper.GetCrdSecurityRuleRecord(kvCrdKind, recordName) != nil
+	if !setCrdObjectStatus(obj, errMsg, warning, inEffect, time.Now().UTC().Format(time.RFC3339)) {
+		return
+	}
+	if err := global.ORCH.UpdateResource(rscType, obj); err != nil {
+		log.WithFields(log.Fields{"kind": kind, "name": name, "error": err}).Error("Failed to update crd status")
+	}
 }
 
 // for CRD only
@@ -2503,6 +3050,8 @@ func (h *nvCrdHandler) parseCrdContent(raw []byte) (*resource.NvSecurityParse, i
 	var admCtrlSecRule resource.NvAdmCtrlSecurityRule
 	var dlpSecRule resource.NvDlpSecurityRule
 	var wafSecRule resource.NvWafSecurityRule
+	var vpSecRule resource.NvVulnProfileSecurityRule
+	var cpSecRule resource.NvCompProfileSecurityRule
 	var buffer bytes.Buffer
 	var errMsg, recordName string
 	var kind string
@@ -2522,6 +3071,10 @@ func (h *nvCrdHandler) parseCrdContent(raw []byte) (*resource.NvSecurityParse, i
 				err = json.Unmarshal(raw, &dlpSecRule)
 			case resource.NvWafSecurityRuleKind:
 				err = json.Unmarshal(raw, &wafSecRule)
+			case resource.NvVulnProfileKind:
+				err = json.Unmarshal(raw, &vpSecRule)
+			case resource.NvCompProfileKind:
+				err = json.Unmarshal(raw, &cpSecRule)
 			default:
 				err = errors.New("unsupported Kubernetese resource kind")
 			}
@@ -2544,6 +3097,10 @@ func (h *nvCrdHandler) parseCrdContent(raw []byte) (*resource.NvSecurityParse, i
 			crdCfgRet, errCount, errMsg, recordName = h.parseCurCrdDlpContent(&dlpSecRule, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
 		case resource.NvWafSecurityRuleKind:
 			crdCfgRet, errCount, errMsg, recordName = h.parseCurCrdWafContent(&wafSecRule, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
+		case resource.NvVulnProfileKind:
+			crdCfgRet, errCount, errMsg, recordName = h.parseCurCrdVulnProfileContent(&vpSecRule, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
+		case resource.NvCompProfileKind:
+			crdCfgRet, errCount, errMsg, recordName = h.parseCurCrdCompProfileContent(&cpSecRule, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
 		}
 	}
 
@@ -2551,17 +3108,28 @@ func (h *nvCrdHandler) parseCrdContent(raw []byte) (*resource.NvSecurityParse, i
 		var err1 error
 		log.Printf("CRD validate fail : %s\n", errMsg)
 		switch kind {
+		// with crdKeepRejected, the error is reported in the status of the crd object instead
 		case resource.NvSecurityRuleKind:
-			err1 = global.ORCH.DeleteResource(resource.RscTypeCrdSecurityRule, &gfwrule)
+			if !crdKeepRejected || gfwrule.Metadata == nil || gfwrule.Metadata.GetName() == "" {
+				err1 = global.ORCH.DeleteResource(resource.RscTypeCrdSecurityRule, &gfwrule)
+			}
 		case resource.NvClusterSecurityRuleKind:
-			r := resource.NvClusterSecurityRule(gfwrule)
-			err1 = global.ORCH.DeleteResource(resource.RscTypeCrdClusterSecurityRule, &r)
+			if !crdKeepRejected || gfwrule.Metadata == nil || gfwrule.Metadata.GetName() == "" {
+				r := resource.NvClusterSecurityRule(gfwrule)
+				err1 = global.ORCH.DeleteResource(resource.RscTypeCrdClusterSecurityRule, &r)
+			}
 		case resource.NvAdmCtrlSecurityRuleKind:
 			err1 = global.ORCH.DeleteResource(resource.RscTypeCrdAdmCtrlSecurityRule, &admCtrlSecRule)
 		case resource.NvDlpSecurityRuleKind:
 			err1 = global.ORCH.DeleteResource(resource.RscTypeCrdDlpSecurityRule, &dlpSecRule)
 		case resource.NvWafSecurityRuleKind:
-			err1 = global.ORCH.DeleteResource(resource.RscTypeCrdWafSecurityRule, &wafSecRule)
+			if !crdKeepRejected || wafSecRule.Metadata == nil || wafSecRule.Metadata.GetName() == "" {
+				err1 = global.ORCH.DeleteResource(resource.RscTypeCrdWafSecurityRule, &wafSecRule)
+			}
+		case resource.NvVulnProfileKind:
+			err1 = global.ORCH.DeleteResource(resource.RscTypeCrdVulnProfile, &vpSecRule)
+		case resource.NvCompProfileKind:
+			err1 = global.ORCH.DeleteResource(resource.RscTypeCrdCompProfile, &cpSecRule)
 		}
 		if err1 != nil {
 			log.WithFields(log.Fields{"error": err1}).Error(recordName)
@@ -2605,11 +3173,19 @@ func (h *nvCrdHandler) crdSecRuleHandler(req *admissionv1beta1.AdmissionRequest)
 				setAdmCtrlStateInCluster(nil, nil, nil, nil, nil, share.UserCreated)
 				h.crdDeleteRecord(req.Kind.Kind, recordName)
 			case resource.NvDlpSecurityRuleKind:
+				h.crdHandleDlpSensorGroups(crdRecord.DlpSensor, nil, crdRecord.DlpSensorGroups)
 				deleteDlpSensor(nil, crdRecord.DlpSensor, share.ReviewTypeCRD, true, h.acc, nil)
 				h.crdDeleteRecord(req.Kind.Kind, recordName)
 			case resource.NvWafSecurityRuleKind:
+				h.crdHandleWafSensorGroups(crdRecord.WafSensor, nil, crdRecord.WafSensorGroups)
 				deleteWafSensor(nil, crdRecord.WafSensor, share.ReviewTypeCRD, true, h.acc, nil)
 				h.crdDeleteRecord(req.Kind.Kind, recordName)
+			case resource.NvVulnProfileKind:
+				h.crdHandleVulnProfileEntries(nil)
+				h.crdDeleteRecord(req.Kind.Kind, recordName)
+			case resource.NvCompProfileKind:
+				h.crdHandleCompProfileEntries(nil)
+				h.crdDeleteRecord(req.Kind.Kind, recordName)
 			case resource.NvSecurityRuleKind, resource.NvClusterSecurityRuleKind:
 				h.crdDeleteNetworkRules(crdRecord.Rules)
 				h.crdHandleGroupRecordDel(crdRecord, crdRecord.Groups, false)
@@ -2646,12 +3222,33 @@ func (h *nvCrdHandler) crdSecRuleHandler(req *admissionv1beta1.AdmissionRequest)
 			case resource.NvDlpSecurityRuleKind:
 				h.crdDlpSensorRecord(crdCfgRet, kind, recordName)
 			case resource.NvWafSecurityRuleKind:
-				h.crdWafSensorRecord(crdCfgRet, kind, recordName)
+				if e := h.crdWafSensorRecord(crdCfgRet, kind, recordName); e != nil {
+					errCount = 1
+					err = e.Error()
+				}
+			case resource.NvVulnProfileKind, resource.NvCompProfileKind:
+				if e := h.crdProfileRecord(crdCfgRet, kind, recordName); e != nil {
+					errCount = 1
+					err = e.Error()
+				}
 			default:
 				errCount = 1
 				err = "unsupported Kubernetese resource kind"
 			}
 		}
+		// with crdKeepRejected, rejected objects are kept and the error is reported in their status
+		var statusReported bool
+		if req.Name != "" && (errCount == 0 || crdKeepRejected) {
+			switch kind {
+			case resource.NvSecurityRuleKind, resource.NvClusterSecurityRuleKind, resource.NvWafSecurityRuleKind:
+				if errCount > 0 {
+					h.crdUpdateSecRuleStatus(kind, req.Namespace, req.Name, err, "")
+				} else {
+					h.crdUpdateSecRuleStatus(kind, req.Namespace, req.Name, "", crWarning)
+				}
+				statusReported = true
+			}
+		}
 
 		detail := []string{fmt.Sprintf("%s %s", req.Operation, h.crUid)}
 		if crInfo != "" {
@@ -2663,6 +3260,9 @@ func (h *nvCrdHandler) crdSecRuleHandler(req *admissionv1beta1.AdmissionRequest)
 		}
 		if errCount > 0 {
 			msg := fmt.Sprintf("CRD %s Removed", recordName)
+			if statusReported {
+				msg = fmt.Sprintf("CRD %s Rejected", recordName)
+			}
 			detail = append(detail, fmt.Sprintf("Error: %s", err))
 			k8sResourceLog(share.CLUSEvCrdErrDetected, msg, detail)
 			log.WithFields(log.Fields{"crdName": recordName, "op": req.Operation}).Error("Failed to add CRD")
@@ -2729,13 +3329,104 @@ func exportAttachRule(rule *api.RESTPolicyRule, useFrom bool, acc *access.Access
 	return &detail
 }
 
+// export a group with its network/process/file rules and dlp/waf settings as NvSecurityRule/NvClusterSecurityRule.
+// policyIDs, inCount & eCount are shared by all groups in the same export so that a network rule is exported only once
+func exportGroupSecurityRule(gname, policyMode string, policyIDs utils.Set, inCount, eCount *int, acc *access.AccessControl) *resource.NvSecurityRule {
+	skip, group := isExportSkipGroupName(gname, acc)
+	if skip {
+		log.WithFields(log.Fields{"name": gname}).Debug("Skip special group export")
+		return nil
+	}
+
+	if group == nil || group.CfgType == api.CfgTypeFederal {
+		return nil
+	}
+
+	tgroup := group2RESTConfig(group)
+	kindName := utils.Dns1123NameChg(strings.ToLower(gname))
+	targetNs := ""
+	targetKind := resource.NvClusterSecurityRuleKind
+	apiversion := fmt.Sprintf("%s/%s", common.OEMClusterSecurityRuleGroup, resource.NvClusterSecurityRuleVersion)
+	for _, ct := range group.Criteria {
+		if ct.Key == share.CriteriaKeyDomain {
+			targetNs = ct.Value
+			targetKind = resource.NvSecurityRuleKind
+			apiversion = fmt.Sprintf("%s/%s", common.OEMSecurityRuleGroup, resource.NvSecurityRuleVersion)
+
+		}
+	}
+
+	resptmp := resource.NvSecurityRule{
+		//Kind: &resource.NvSecurityRuleKind,
+		Kind: &targetKind,
+		Metadata: &cmetav1.ObjectMeta{
+			Name:      &kindName,
+			Namespace: &targetNs,
+		},
+		ApiVersion: &apiversion,
+		Spec: resource.NvSecurityRuleSpec{
+			Target: resource.NvSecurityTarget{
+				Selector: *tgroup,
+			},
+			IngressRule: make([]resource.NvSecurityRuleDetail, 0),
+			EgressRule:  make([]resource.NvSecurityRuleDetail, 0),
+			ProcessRule: make([]resource.NvSecurityProcessRule, 0),
+			FileRule:    make([]resource.NvSecurityFileRule, 0),
+		},
+	}
+	// If Learned group add the policy mode in crd
+	if utils.DoesGroupHavePolicyMode(gname) {
+		if policyMode != "" {
+			resptmp.Spec.Target.PolicyMode = &policyMode
+		} else {
+			resptmp.Spec.Target.PolicyMode = &group.PolicyMode
+		}
+	} else {
+		resptmp.Spec.Target.PolicyMode = func() *string { b := share.PolicyModeUnavailable; return &b }()
+	}
+
+	// export process and file profiles
+	exportProcessRule(gname, &(resptmp.Spec), acc)
+	if gname != api.AllHostGroup { // TODO: skip file for now
+		exportFileRule(gname, &(resptmp.Spec), acc)
+	}
+
+	// export group's dlp/waf data
+	if group.Kind == share.GroupKindContainer {
+		exportDlpWafGroup(gname, &resptmp, acc)
+	}
+
+	for _, idx := range group.PolicyRules {
+		if policyIDs.Contains(idx) {
+			continue
+		}
+		policyIDs.Add(idx)
+		rule, _ := cacher.GetPolicyRule(idx, acc)
+		if rule != nil {
+			if rule.To == gname {
+				detail := exportAttachRule(rule, true, acc, *inCount)
+				if detail != nil {
+					resptmp.Spec.IngressRule = append(resptmp.Spec.IngressRule, *detail)
+					*inCount = *inCount + 1
+				}
+			} else {
+				detail := exportAttachRule(rule, false, acc, *eCount)
+				if detail != nil {
+					resptmp.Spec.EgressRule = append(resptmp.Spec.EgressRule, *detail)
+					*eCount = *eCount + 1
+				}
+			}
+		}
+	}
+
+	return &resptmp
+}
+
 func handlerGroupCfgExport(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
 	defer r.Body.Close()
 	var data []byte
 	var inCount, eCount int
-	var group *api.RESTGroup
-	var skip bool
 
 	policy_ids := utils.NewSet()
 	acc, _ := getAccessControl(w, r, access.AccessOPRead) // handlerGroupCfgExport() is used for both GET/POST so we force the op to be AccessOPRead for access control
@@ -2768,107 +3459,151 @@ func handlerGroupCfgExport(w http.ResponseWriter, r *http.Request, ps httprouter
 	}
 	defer clusHelper.ReleaseLock(lock)
 	for _, gname := range rconf.Groups {
-
-		if skip, group = isExportSkipGroupName(gname, acc); skip {
-			e := "Skip special group export"
-			log.WithFields(log.Fields{"name": gname}).Error(e)
-			continue
+		if resptmp := exportGroupSecurityRule(gname, rconf.PolicyMode, policy_ids, &inCount, &eCount, acc); resptmp != nil {
+			resp.Items = append(resp.Items, resptmp)
 		}
+	}
+	// for all the group in the From/To , if learned group we also need export it's policymode
+	// We don't know the default policy mode in other system so in current system just export
 
-		if group == nil || group.CfgType == api.CfgTypeFederal {
-			continue
-		}
+	// tell the browser the returned content should be downloaded
+	w.Header().Set("Content-Disposition", "Attachment; filename="+filename)
+	w.Header().Set("Content-Encoding", "gzip")
+	w.WriteHeader(http.StatusOK)
+	json_data, _ := json.MarshalIndent(resp, "", "  ")
+	data, _ = yaml.JSONToYAML(json_data)
+	data = utils.GzipBytes(data)
+	w.Write(data)
+}
 
-		tgroup := group2RESTConfig(group)
-		kindName := utils.Dns1123NameChg(strings.ToLower(gname))
-		targetNs := ""
-		targetKind := resource.NvClusterSecurityRuleKind
-		apiversion := fmt.Sprintf("%s/%s", common.OEMClusterSecurityRuleGroup, resource.NvClusterSecurityRuleVersion)
-		for _, ct := range group.Criteria {
-			if ct.Key == share.CriteriaKeyDomain {
-				targetNs = ct.Value
-				targetKind = resource.NvSecurityRuleKind
-				apiversion = fmt.Sprintf("%s/%s", common.OEMSecurityRuleGroup, resource.NvSecurityRuleVersion)
+// Export the running config of groups as crd yaml documents that can be applied to k8s directly:
+// 1. NvSecurityRule/NvClusterSecurityRule for each group with its network/process/file rules and dlp/waf settings
+// 2. NvDlpSecurityRule/NvWafSecurityRule for the non-predefined sensors used by the exported groups
+// 3. NvAdmissionControlSecurityRule for the local admission control config & rules
+func handlerCrdConfigExport(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
 
+	var groups []string
+	query := restParseQuery(r)
+	if value, _ := query.pairs[api.QueryKeyGroups]; value != "" {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				groups = append(groups, name)
 			}
 		}
+	} else {
+		for _, gs := range cacher.GetAllGroups(share.ScopeLocal, "", false, acc) {
+			for _, g := range gs {
+				groups = append(groups, g.Name)
+			}
+		}
+		sort.Strings(groups)
+	}
 
-		resptmp := resource.NvSecurityRule{
-			//Kind: &resource.NvSecurityRuleKind,
-			Kind: &targetKind,
-			Metadata: &cmetav1.ObjectMeta{
-				Name:      &kindName,
-				Namespace: &targetNs,
-			},
-			ApiVersion: &apiversion,
-			Spec: resource.NvSecurityRuleSpec{
-				Target: resource.NvSecurityTarget{
-					Selector: *tgroup,
-				},
-				IngressRule: make([]resource.NvSecurityRuleDetail, 0),
-				EgressRule:  make([]resource.NvSecurityRuleDetail, 0),
-				ProcessRule: make([]resource.NvSecurityProcessRule, 0),
-				FileRule:    make([]resource.NvSecurityFileRule, 0),
-			},
+	docs := make([]interface{}, 0, len(groups)+4)
+	lock, err := lockClusKey(w, share.CLUSLockPolicyKey)
+	if err != nil {
+		return
+	}
+
+	var inCount, eCount int
+	policyIDs := utils.NewSet()
+	dlpSensors := utils.NewSet()
+	wafSensors := utils.NewSet()
+	for _, gname := range groups {
+		if secRule := exportGroupSecurityRule(gname, "", policyIDs, &inCount, &eCount, acc); secRule != nil {
+			if secRule.Spec.DlpGroup != nil {
+				for _, s := range secRule.Spec.DlpGroup.Settings {
+					dlpSensors.Add(s.Name)
+				}
+			}
+			if secRule.Spec.WafGroup != nil {
+				for _, s := range secRule.Spec.WafGroup.Settings {
+					wafSensors.Add(s.Name)
+				}
+			}
+			docs = append(docs, secRule)
 		}
-		// If Learned group add the policy mode in crd
-		if utils.DoesGroupHavePolicyMode(gname) {
-			if rconf.PolicyMode != "" {
-				resptmp.Spec.Target.PolicyMode = &rconf.PolicyMode
-			} else {
-				resptmp.Spec.Target.PolicyMode = &group.PolicyMode
+	}
+	// the sensors' group settings are already in the exported NvSecurityRule objects
+	if dlpSensors.Cardinality() > 0 && acc.Authorize(&share.CLUSDlpSensor{}, nil) {
+		defSensor := clusHelper.GetDlpSensor(share.CLUSDlpDefaultSensor)
+		for _, name := range dlpSensors.ToStringSlice() {
+			if sensor := clusHelper.GetDlpSensor(name); sensor != nil && !sensor.Predefine && defSensor != nil {
+				docs = append(docs, exportDlpSecurityRule(sensor, defSensor, false, acc))
 			}
-		} else {
-			resptmp.Spec.Target.PolicyMode = func() *string { b := share.PolicyModeUnavailable; return &b }()
 		}
-
-		// export process and file profiles
-		exportProcessRule(gname, &(resptmp.Spec), acc)
-		if gname != api.AllHostGroup { // TODO: skip file for now
-			exportFileRule(gname, &(resptmp.Spec), acc)
+	}
+	if wafSensors.Cardinality() > 0 && acc.Authorize(&share.CLUSWafSensor{}, nil) {
+		defSensor := clusHelper.GetWafSensor(share.CLUSWafDefaultSensor)
+		for _, name := range wafSensors.ToStringSlice() {
+			if sensor := clusHelper.GetWafSensor(name); sensor != nil && !sensor.Predefine && defSensor != nil {
+				docs = append(docs, exportWafSecurityRule(sensor, defSensor, false, acc))
+			}
 		}
+	}
+	clusHelper.ReleaseLock(lock)
 
-		// export group's dlp/waf data
-		if group.Kind == share.GroupKindContainer {
-			exportDlpWafGroup(gname, &resptmp, acc)
+	if k8sPlatform {
+		if lock, err = lockClusKey(w, share.CLUSLockAdmCtrlKey); err != nil {
+			return
 		}
-
-		for _, idx := range group.PolicyRules {
-			if policy_ids.Contains(idx) {
-				continue
-			}
-			policy_ids.Add(idx)
-			rule, _ := cacher.GetPolicyRule(idx, acc)
-			if rule != nil {
-				if rule.To == gname {
-					detail := exportAttachRule(rule, true, acc, inCount)
-					if detail != nil {
-						resptmp.Spec.IngressRule = append(resptmp.Spec.IngressRule, *detail)
-						inCount = inCount + 1
-					}
-				} else {
-					detail := exportAttachRule(rule, false, acc, eCount)
-					if detail != nil {
-						resptmp.Spec.EgressRule = append(resptmp.Spec.EgressRule, *detail)
-						eCount = eCount + 1
-					}
+		var rules []*resource.NvSecurityAdmCtrlRule
+		for _, ruleType := range []string{api.ValidatingExceptRuleType, api.ValidatingDenyRuleType} {
+			for _, rule := range cacher.GetAdmissionRules(admission.NvAdmValidateType, ruleType, acc) {
+				if rule.CfgType != api.CfgTypeFederal {
+					rules = append(rules, exportAdmCtrlRule(rule))
 				}
 			}
 		}
+		cfg, err := exportAdmCtrlConfig(acc)
+		clusHelper.ReleaseLock(lock)
+		if err == nil || len(rules) > 0 {
+			apiversion := fmt.Sprintf("%s/%s", common.OEMSecurityRuleGroup, resource.NvAdmCtrlSecurityRuleVersion)
+			kind := resource.NvAdmCtrlSecurityRuleKind
+			name := share.ScopeLocal
+			docs = append(docs, &resource.NvAdmCtrlSecurityRule{
+				ApiVersion: &apiversion,
+				Kind:       &kind,
+				Metadata: &cmetav1.ObjectMeta{
+					Name: &name,
+				},
+				Spec: resource.NvSecurityAdmCtrlSpec{
+					Config: cfg,
+					Rules:  rules,
+				},
+			})
+		}
+	}
 
-		resp.Items = append(resp.Items, &resptmp)
+	var buffer bytes.Buffer
+	for idx, doc := range docs {
+		json_data, _ := json.MarshalIndent(doc, "", "  ")
+		data, err := yaml.JSONToYAML(json_data)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Failed to convert to yaml")
+			restRespError(w, http.StatusInternalServerError, api.RESTErrFailExport)
+			return
+		}
+		if idx > 0 {
+			buffer.WriteString("---\n")
+		}
+		buffer.Write(data)
 	}
-	// for all the group in the From/To , if learned group we also need export it's policymode
-	// We don't know the default policy mode in other system so in current system just export
+	log.WithFields(log.Fields{"login": login.fullname, "groups": len(groups), "docs": len(docs)}).Debug("Export crd config")
 
 	// tell the browser the returned content should be downloaded
+	filename := "cfgCrdExport.yaml"
 	w.Header().Set("Content-Disposition", "Attachment; filename="+filename)
 	w.Header().Set("Content-Encoding", "gzip")
 	w.WriteHeader(http.StatusOK)
-	json_data, _ := json.MarshalIndent(resp, "", "  ")
-	data, _ = yaml.JSONToYAML(json_data)
-	data = utils.GzipBytes(data)
-	w.Write(data)
+	w.Write(utils.GzipBytes(buffer.Bytes()))
 }
 
 func (h *nvCrdHandler) crdDeleteRecord(kind, recordName string) {
@@ -3260,6 +3995,11 @@ func CrossCheckCrd(kind, rscType, kvCrdKind, lockKey string, kvOnly bool) error
 		crdHandler.crdUpdateDlpSensors()
 	case resource.NvWafSecurityRuleKind:
 		crdHandler.crdUpdateWafSensors()
+	case resource.NvVulnProfileKind:
+		// entries defined by crd are rebuilt from the crd objects in k8s
+		crdHandler.crdHandleVulnProfileEntries(nil)
+	case resource.NvCompProfileKind:
+		crdHandler.crdHandleCompProfileEntries(nil)
 	}
 	crdHandler.ReleaseLock()
 
@@ -3278,12 +4018,20 @@ func CrossCheckCrd(kind, rscType, kvCrdKind, lockKey string, kvOnly bool) error
 			crdCfgRet, errCount, err, recordname = crdHandler.parseCurCrdGfwContent(r, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
 			if errCount > 0 {
 				log.WithFields(log.Fields{"error": err}).Error()
-				e := fmt.Sprintf("%s deleted due to error: %s", metadataName, err)
-				deleted = append(deleted, e)
-				global.ORCH.DeleteResource(resource.RscTypeCrdSecurityRule, r)
+				if !crdKeepRejected || r.Metadata.GetName() == "" {
+					e := fmt.Sprintf("%s deleted due to error: %s", metadataName, err)
+					deleted = append(deleted, e)
+					global.ORCH.DeleteResource(resource.RscTypeCrdSecurityRule, r)
+				} else {
+					// the last accepted spec of the rejected object is kept
+					delete(recordList, fmt.Sprintf("%s-%s-%s", kind, r.Metadata.GetNamespace(), r.Metadata.GetName()))
+					crdHandler.crdUpdateSecRuleStatus(kind, r.Metadata.GetNamespace(), r.Metadata.GetName(), err, "")
+				}
 			} else {
+				var crWarning string
 				delete(recordList, recordname)
-				crInfo, _ = crdHandler.crdGFwRuleProcessRecord(crdCfgRet, resource.NvSecurityRuleKind, recordname)
+				crInfo, crWarning = crdHandler.crdGFwRuleProcessRecord(crdCfgRet, resource.NvSecurityRuleKind, recordname)
+				crdHandler.crdUpdateSecRuleStatus(kind, r.Metadata.GetNamespace(), r.Metadata.GetName(), "", crWarning)
 				e := fmt.Sprintf("%s (%s)", metadataName, crInfo)
 				imported = append(imported, e)
 			}
@@ -3307,19 +4055,34 @@ func CrossCheckCrd(kind, rscType, kvCrdKind, lockKey string, kvOnly bool) error
 				r := obj.(*resource.NvWafSecurityRule)
 				metadataName = *r.Metadata.Name
 				crdCfgRet, errCount, err, recordname = crdHandler.parseCurCrdWafContent(r, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
+			case resource.NvVulnProfileKind:
+				r := obj.(*resource.NvVulnProfileSecurityRule)
+				metadataName = *r.Metadata.Name
+				crdCfgRet, errCount, err, recordname = crdHandler.parseCurCrdVulnProfileContent(r, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
+			case resource.NvCompProfileKind:
+				r := obj.(*resource.NvCompProfileSecurityRule)
+				metadataName = *r.Metadata.Name
+				crdCfgRet, errCount, err, recordname = crdHandler.parseCurCrdCompProfileContent(r, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
 			}
 			if errCount > 0 {
-				if kind == resource.NvSecurityRuleKind || kind == resource.NvClusterSecurityRuleKind || metadataName == "" {
+				if kind == resource.NvVulnProfileKind || kind == resource.NvCompProfileKind || metadataName == "" ||
+					((kind == resource.NvClusterSecurityRuleKind || kind == resource.NvWafSecurityRuleKind) && !crdKeepRejected) {
 					// for admission control crd resource, if metaname is empty, delete the crd resource
 					log.WithFields(log.Fields{"error": err, "name": metadataName}).Error()
 					e := fmt.Sprintf("%s deleted due to error: %s", metadataName, err)
 					deleted = append(deleted, e)
 					global.ORCH.DeleteResource(rscType, obj)
+				} else if kind == resource.NvClusterSecurityRuleKind || kind == resource.NvWafSecurityRuleKind {
+					// the last accepted spec of the rejected object is kept
+					delete(recordList, fmt.Sprintf("%s-default-%s", kind, metadataName))
+					crdHandler.crdUpdateSecRuleStatus(kind, "", metadataName, err, "")
 				}
 			} else {
 				switch kind {
 				case resource.NvClusterSecurityRuleKind:
-					crInfo, _ = crdHandler.crdGFwRuleProcessRecord(crdCfgRet, resource.NvSecurityRuleKind, recordname)
+					var crWarning string
+					crInfo, crWarning = crdHandler.crdGFwRuleProcessRecord(crdCfgRet, resource.NvSecurityRuleKind, recordname)
+					crdHandler.crdUpdateSecRuleStatus(kind, "", metadataName, "", crWarning)
 				case resource.NvAdmCtrlSecurityRuleKind:
 					if crdCfgRet != nil { // for NvAdmissionControlSecurityRule resource objects with metadata name other than "local", ignore them
 						crInfo, _ = crdHandler.crdAdmCtrlRuleRecord(crdCfgRet, kind, recordname)
@@ -3327,7 +4090,15 @@ func CrossCheckCrd(kind, rscType, kvCrdKind, lockKey string, kvOnly bool) error
 				case resource.NvDlpSecurityRuleKind:
 					crdHandler.crdDlpSensorRecord(crdCfgRet, kind, recordname)
 				case resource.NvWafSecurityRuleKind:
-					crdHandler.crdWafSensorRecord(crdCfgRet, kind, recordname)
+					var errMsg string
+					if err := crdHandler.crdWafSensorRecord(crdCfgRet, kind, recordname); err != nil {
+						errMsg = err.Error()
+					}
+					crdHandler.crdUpdateSecRuleStatus(kind, "", metadataName, errMsg, "")
+				case resource.NvVulnProfileKind, resource.NvCompProfileKind:
+					if err := crdHandler.crdProfileRecord(crdCfgRet, kind, recordname); err != nil {
+						log.WithFields(log.Fields{"error": err, "name": metadataName}).Error()
+					}
 				}
 				e := fmt.Sprintf("%s (%s)", metadataName, crInfo)
 				imported = append(imported, e)
@@ -2,10 +2,16 @@ package rest
 
 import (
 	"encoding/json"
+	"net/http"
+	"strings"
 	"testing"
 
+	metav1 "github.com/neuvector/k8s/apis/meta/v1"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
 	"github.com/neuvector/neuvector/controller/resource"
 	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
 	"sigs.k8s.io/yaml"
 )
 
@@ -276,5 +282,470 @@ func TestParseCrdSecurityRule(t *testing.T) {
 		}
 	}
 
+	//--- cve score, psp & custom criteria
+	{
+		errCountExpected := []int{0, 1, 1}
+		raw_strings := []string{
+			"apiVersion: neuvector.com/v1\nkind: NvAdmissionControlSecurityRule\nmetadata:\n  name: local\nspec:\n  rules:\n  - action: deny\n    criteria:\n    - name: cveScoreCount\n      op: \">=\"\n      value: \"7\"\n    - name: cveHighCount\n      op: \">=\"\n      value: \"5\"\n      sub_criteria:\n      - name: publishDays\n        op: \">=\"\n        value: \"30\"\n  - action: deny\n    criteria:\n    - name: pspCompliance\n      op: =\n      value: \"true\"\n  - action: deny\n    rule_mode: monitor\n    criteria:\n    - name: item.spec.hostAliases\n      op: exist\n      type: customPath\n      template_kind: pod\n      path: item.spec.hostAliases\n      value_type: key\n",
+			"apiVersion: neuvector.com/v1\nkind: NvAdmissionControlSecurityRule\nmetadata:\n  name: local\nspec:\n  rules:\n  - action: allow\n    criteria:\n    - name: item.spec.hostAliases\n      op: exist\n      type: customPath\n      template_kind: pod\n      path: item.spec.hostAliases\n      value_type: key\n",
+			"apiVersion: neuvector.com/v1\nkind: NvAdmissionControlSecurityRule\nmetadata:\n  name: local\nspec:\n  rules:\n  - action: deny\n    criteria:\n    - name: cveScoreCount\n      op: \">=\"\n      value: high\n",
+		}
+		for idx, raw_string := range raw_strings {
+			json_data, err := yaml.YAMLToJSON([]byte(raw_string))
+			if err != nil {
+				t.Errorf("[admission criteria: %d] yaml error\n %v", idx, err)
+			} else {
+				var admCtrlSecRule resource.NvAdmCtrlSecurityRule
+				if err = json.Unmarshal(json_data, &admCtrlSecRule); err != nil {
+					t.Errorf("[admission criteria: %d] unmarshal error\n %v", idx, err)
+				} else {
+					parsed, errCount, err, _ := crdHandler.parseCurCrdAdmCtrlContent(&admCtrlSecRule, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
+					if errCount != errCountExpected[idx] {
+						t.Errorf("[admission criteria: %d] %d parse error, %d parse error expected\n %v", idx, errCount, errCountExpected[idx], err)
+					} else if errCount == 0 && (parsed == nil || len(parsed.AdmCtrlRulesCfg[api.ValidatingDenyRuleType]) != 3) {
+						t.Errorf("[admission criteria: %d] unexpected parsed rules: %+v", idx, parsed)
+					}
+				}
+			}
+		}
+	}
+
 	postTest()
 }
+
+func TestCrdSecRuleConditions(t *testing.T) {
+	getCond := func(status *resource.NvSecurityRuleStatus, condType string) *resource.NvCrdCondition {
+		for _, c := range status.Conditions {
+			if c.Type == condType {
+				return c
+			}
+		}
+		return nil
+	}
+
+	status := &resource.NvSecurityRuleStatus{}
+
+	// accepted
+	setCrdSecRuleConditions(status, "", "", false, "t1")
+	if len(status.Conditions) != 3 {
+		t.Errorf("Unexpected conditions: %+v", status.Conditions)
+	}
+	if c := getCond(status, resource.NvCrdConditionAccepted); c == nil || c.Status != resource.NvCrdConditionTrue {
+		t.Errorf("Unexpected accepted condition: %+v", c)
+	}
+	if c := getCond(status, resource.NvCrdConditionSynced); c == nil || c.Status != resource.NvCrdConditionTrue {
+		t.Errorf("Unexpected synced condition: %+v", c)
+	}
+	if c := getCond(status, resource.NvCrdConditionError); c == nil || c.Status != resource.NvCrdConditionFalse {
+		t.Errorf("Unexpected error condition: %+v", c)
+	}
+
+	// rejected while the last accepted spec is still in effect
+	setCrdSecRuleConditions(status, "invalid rule", "", true, "t2")
+	if len(status.Conditions) != 3 {
+		t.Errorf("Unexpected conditions: %+v", status.Conditions)
+	}
+	if c := getCond(status, resource.NvCrdConditionAccepted); c == nil || c.Status != resource.NvCrdConditionFalse || c.LastTransitionTime != "t2" {
+		t.Errorf("Unexpected accepted condition: %+v", c)
+	}
+	if c := getCond(status, resource.NvCrdConditionSynced); c == nil || c.Status != resource.NvCrdConditionTrue || c.LastTransitionTime != "t1" {
+		t.Errorf("Unexpected synced condition: %+v", c)
+	}
+	if c := getCond(status, resource.NvCrdConditionError); c == nil || c.Status != resource.NvCrdConditionTrue || c.Message != "invalid rule" {
+		t.Errorf("Unexpected error condition: %+v", c)
+	}
+
+	// rejected
+	status = &resource.NvSecurityRuleStatus{}
+	setCrdSecRuleConditions(status, "invalid rule", "", false, "t3")
+	if c := getCond(status, resource.NvCrdConditionSynced); c == nil || c.Status != resource.NvCrdConditionFalse {
+		t.Errorf("Unexpected synced condition: %+v", c)
+	}
+
+	// accepted with warning
+	setCrdSecRuleConditions(status, "", "uid mismatch", false, "t4")
+	if c := getCond(status, resource.NvCrdConditionSynced); c == nil || c.Status != resource.NvCrdConditionTrue {
+		t.Errorf("Unexpected synced condition: %+v", c)
+	}
+	if c := getCond(status, resource.NvCrdConditionError); c == nil || c.Status != resource.NvCrdConditionTrue || c.Message != "uid mismatch" {
+		t.Errorf("Unexpected error condition: %+v", c)
+	}
+}
+
+func TestCrdConfigExport(t *testing.T) {
+	preTest()
+	initNetPolicyExportTest()
+
+	w := restCall("GET", "/v1/file/config/crd?groups=nv.web.demo,nv.db.demo", nil, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Fatalf("Export crd config: status=%d", w.status)
+	}
+
+	docs := strings.Split(string(utils.GunzipBytes(w.body)), "---\n")
+	if len(docs) != 2 {
+		t.Fatalf("Unexpected document count: %d", len(docs))
+	}
+	var ingress, egress int
+	for idx, doc := range docs {
+		var secRule resource.NvSecurityRule
+		if err := yaml.Unmarshal([]byte(doc), &secRule); err != nil {
+			t.Fatalf("Failed to parse exported document %d: %v", idx, err)
+		}
+		if secRule.Kind == nil || *secRule.Kind != resource.NvSecurityRuleKind ||
+			secRule.Metadata == nil || secRule.Metadata.GetNamespace() != "demo" {
+			t.Errorf("Unexpected document %d: %s", idx, doc)
+		}
+		ingress += len(secRule.Spec.IngressRule)
+		egress += len(secRule.Spec.EgressRule)
+	}
+	// each network rule is exported only once even if it's shared by the exported groups
+	if ingress != 1 || egress != 3 {
+		t.Errorf("Unexpected network rules: ingress=%d, egress=%d", ingress, egress)
+	}
+
+	postTest()
+}
+
+type dlpGroupMockCluster struct {
+	kv.MockCluster
+	dlpGroups  map[string]*share.CLUSDlpGroup
+	crdRecords map[string]*share.CLUSCrdSecurityRule
+}
+
+func (m *dlpGroupMockCluster) GetDlpGroup(name string) *share.CLUSDlpGroup {
+	if g, ok := m.dlpGroups[name]; ok {
+		clone := *g
+		clone.Sensors = make([]*share.CLUSDlpSetting, len(g.Sensors))
+		for i, s := range g.Sensors {
+			cs := *s
+			clone.Sensors[i] = &cs
+		}
+		return &clone
+	}
+	return nil
+}
+
+func (m *dlpGroupMockCluster) PutDlpGroup(group *share.CLUSDlpGroup, create bool) error {
+	m.dlpGroups[group.Name] = group
+	return nil
+}
+
+func (m *dlpGroupMockCluster) GetCrdSecurityRuleRecordList(crdKind string) map[string]*share.CLUSCrdSecurityRule {
+	return m.crdRecords
+}
+
+func (m *dlpGroupMockCluster) PutCrdSecurityRuleRecord(crdKind, crdName string, rules *share.CLUSCrdSecurityRule) error {
+	m.crdRecords[crdName] = rules
+	return nil
+}
+
+func dlpGroupSensors(g *share.CLUSDlpGroup) string {
+	list := make([]string, len(g.Sensors))
+	for i, s := range g.Sensors {
+		list[i] = s.Name + ":" + s.Action
+	}
+	return strings.Join(list, ",")
+}
+
+func TestCrdDlpSensorGroups(t *testing.T) {
+	preTest()
+
+	var mockCluster dlpGroupMockCluster
+	mockCluster.Init(nil, []*share.CLUSGroup{
+		{Name: "g1", Kind: share.GroupKindContainer, CfgType: share.UserCreated},
+		{Name: "g2", Kind: share.GroupKindContainer, CfgType: share.GroundCfg},
+		{Name: "ext", Kind: share.GroupKindAddress, CfgType: share.UserCreated},
+	})
+	mockCluster.dlpGroups = map[string]*share.CLUSDlpGroup{
+		"g1": {Name: "g1", CfgType: share.UserCreated, Sensors: []*share.CLUSDlpSetting{{Name: "s0", Action: share.PolicyActionAllow}}},
+		"g2": {Name: "g2", CfgType: share.GroundCfg, Sensors: []*share.CLUSDlpSetting{{Name: "s1", Action: share.PolicyActionAllow}}},
+	}
+	mockCluster.crdRecords = make(map[string]*share.CLUSCrdSecurityRule)
+	clusHelper = &mockCluster
+
+	var crdHandler nvCrdHandler
+	crdHandler.Init(share.CLUSLockPolicyKey)
+
+	// group bindings validation
+	kind := resource.NvDlpSecurityRuleKind
+	name := "s1"
+	tests := []struct {
+		groups []*resource.NvSecurityDlpGroupBinding
+		errMsg string
+	}{
+		{groups: []*resource.NvSecurityDlpGroupBinding{{Name: "g1", Action: share.PolicyActionDeny}}},
+		{groups: []*resource.NvSecurityDlpGroupBinding{{Name: "g1", Action: "learn"}}, errMsg: "unsupported action"},
+		{groups: []*resource.NvSecurityDlpGroupBinding{{Name: "g1", Action: share.PolicyActionDeny}, {Name: "g1", Action: share.PolicyActionAllow}}, errMsg: "duplicate group"},
+		{groups: []*resource.NvSecurityDlpGroupBinding{{Name: "g3", Action: share.PolicyActionDeny}}, errMsg: "does not exist"},
+		{groups: []*resource.NvSecurityDlpGroupBinding{{Name: "ext", Action: share.PolicyActionDeny}}, errMsg: "cannot have DLP policy"},
+		{groups: []*resource.NvSecurityDlpGroupBinding{{Name: "g2", Action: share.PolicyActionDeny}}, errMsg: "defined by NvSecurityRule"},
+	}
+	for i, test := range tests {
+		dlpSecRule := resource.NvDlpSecurityRule{
+			Kind:     &kind,
+			Metadata: &metav1.ObjectMeta{Name: &name},
+			Spec:     resource.NvSecurityDlpSpec{Groups: test.groups},
+		}
+		parsed, errCount, errMsg, _ := crdHandler.parseCurCrdDlpContent(&dlpSecRule, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
+		if test.errMsg == "" {
+			if errCount > 0 || parsed.DlpSensorGroups["g1"] != share.PolicyActionDeny {
+				t.Errorf("%d: Unexpected parse result: err=%v groups=%+v\n", i, errMsg, parsed)
+			}
+		} else if errCount == 0 || !strings.Contains(errMsg, test.errMsg) {
+			t.Errorf("%d: Expected error %v, got %v\n", i, test.errMsg, errMsg)
+		}
+	}
+
+	// apply the sensor to the groups, other sensors of the groups are kept
+	groups := crdHandler.crdHandleDlpSensorGroups("s1", map[string]string{"g1": share.PolicyActionDeny, "g3": share.PolicyActionDeny}, nil)
+	if len(groups) != 1 || groups[0] != "g1" {
+		t.Errorf("Unexpected bound groups: %+v\n", groups)
+	}
+	if s := dlpGroupSensors(mockCluster.dlpGroups["g1"]); s != "s0:allow,s1:deny" {
+		t.Errorf("Unexpected sensors of g1: %v\n", s)
+	}
+
+	// change the action
+	crdHandler.crdHandleDlpSensorGroups("s1", map[string]string{"g1": share.PolicyActionAllow}, groups)
+	if s := dlpGroupSensors(mockCluster.dlpGroups["g1"]); s != "s0:allow,s1:allow" {
+		t.Errorf("Unexpected sensors of g1: %v\n", s)
+	}
+
+	// remove the binding
+	groups = crdHandler.crdHandleDlpSensorGroups("s1", nil, groups)
+	if len(groups) != 0 {
+		t.Errorf("Unexpected bound groups: %+v\n", groups)
+	}
+	if s := dlpGroupSensors(mockCluster.dlpGroups["g1"]); s != "s0:allow" {
+		t.Errorf("Unexpected sensors of g1: %v\n", s)
+	}
+
+	// the group defined by NvSecurityRule is not changed by NvDlpSecurityRule
+	groups = crdHandler.crdHandleDlpSensorGroups("s1", map[string]string{"g2": share.PolicyActionDeny}, nil)
+	if len(groups) != 0 {
+		t.Errorf("Unexpected bound groups: %+v\n", groups)
+	}
+	crdHandler.crdHandleDlpSensorGroups("s1", nil, []string{"g2"})
+	if s := dlpGroupSensors(mockCluster.dlpGroups["g2"]); s != "s1:allow" {
+		t.Errorf("Unexpected sensors of g2: %v\n", s)
+	}
+
+	// NvSecurityRule takes over the group bound by NvDlpSecurityRule
+	recordName := "NvDlpSecurityRule-default-s1"
+	mockCluster.crdRecords[recordName] = &share.CLUSCrdSecurityRule{
+		Name:            recordName,
+		DlpSensor:       "s1",
+		DlpSensorGroups: crdHandler.crdHandleDlpSensorGroups("s1", map[string]string{"g1": share.PolicyActionDeny}, nil),
+	}
+	crdHandler.crdReleaseDlpSensorGroup("g1")
+	crdHandler.crdHandleDlpGroup("g1", &api.RESTCrdDlpGroupConfig{
+		RepSensors: []api.RESTCrdDlpGroupSetting{{Name: "s2", Action: share.PolicyActionDeny}},
+	}, share.GroundCfg)
+	if r := mockCluster.crdRecords[recordName]; len(r.DlpSensorGroups) != 0 {
+		t.Errorf("Group binding is not released: %+v\n", r.DlpSensorGroups)
+	}
+	if g := mockCluster.dlpGroups["g1"]; g.CfgType != share.GroundCfg || dlpGroupSensors(g) != "s2:deny" {
+		t.Errorf("Unexpected dlp group defined by NvSecurityRule: %+v %v\n", g, dlpGroupSensors(g))
+	}
+
+	// deleting NvDlpSecurityRule doesn't change the group owned by NvSecurityRule
+	crdHandler.crdHandleDlpSensorGroups("s1", nil, mockCluster.crdRecords[recordName].DlpSensorGroups)
+	if s := dlpGroupSensors(mockCluster.dlpGroups["g1"]); s != "s2:deny" {
+		t.Errorf("Unexpected sensors of g1: %v\n", s)
+	}
+
+	postTest()
+}
+
+type wafGroupMockCluster struct {
+	kv.MockCluster
+	wafGroups  map[string]*share.CLUSWafGroup
+	crdRecords map[string]*share.CLUSCrdSecurityRule
+}
+
+func (m *wafGroupMockCluster) GetWafGroup(name string) *share.CLUSWafGroup {
+	if g, ok := m.wafGroups[name]; ok {
+		clone := *g
+		clone.Sensors = make([]*share.CLUSWafSetting, len(g.Sensors))
+		for i, s := range g.Sensors {
+			cs := *s
+			clone.Sensors[i] = &cs
+		}
+		return &clone
+	}
+	return nil
+}
+
+func (m *wafGroupMockCluster) PutWafGroup(group *share.CLUSWafGroup, create bool) error {
+	m.wafGroups[group.Name] = group
+	return nil
+}
+
+func (m *wafGroupMockCluster) GetCrdSecurityRuleRecordList(crdKind string) map[string]*share.CLUSCrdSecurityRule {
+	return m.crdRecords
+}
+
+func (m *wafGroupMockCluster) PutCrdSecurityRuleRecord(crdKind, crdName string, rules *share.CLUSCrdSecurityRule) error {
+	m.crdRecords[crdName] = rules
+	return nil
+}
+
+func wafGroupSensors(g *share.CLUSWafGroup) string {
+	list := make([]string, len(g.Sensors))
+	for i, s := range g.Sensors {
+		list[i] = s.Name + ":" + s.Action
+	}
+	return strings.Join(list, ",")
+}
+
+func TestCrdWafSensorGroups(t *testing.T) {
+	preTest()
+
+	var mockCluster wafGroupMockCluster
+	mockCluster.Init(nil, []*share.CLUSGroup{
+		{Name: "g1", Kind: share.GroupKindContainer, CfgType: share.UserCreated},
+		{Name: "g2", Kind: share.GroupKindContainer, CfgType: share.GroundCfg},
+		{Name: "ext", Kind: share.GroupKindAddress, CfgType: share.UserCreated},
+	})
+	mockCluster.wafGroups = map[string]*share.CLUSWafGroup{
+		"g1": {Name: "g1", CfgType: share.UserCreated, Sensors: []*share.CLUSWafSetting{{Name: "s0", Action: share.PolicyActionAllow}}},
+		"g2": {Name: "g2", CfgType: share.GroundCfg, Sensors: []*share.CLUSWafSetting{{Name: "s1", Action: share.PolicyActionAllow}}},
+	}
+	mockCluster.crdRecords = make(map[string]*share.CLUSCrdSecurityRule)
+	clusHelper = &mockCluster
+
+	var crdHandler nvCrdHandler
+	crdHandler.Init(share.CLUSLockPolicyKey)
+
+	// group bindings validation
+	kind := resource.NvWafSecurityRuleKind
+	name := "s1"
+	tests := []struct {
+		groups []*resource.NvSecurityWafGroupBinding
+		errMsg string
+	}{
+		{groups: []*resource.NvSecurityWafGroupBinding{{Name: "g1", Action: share.PolicyActionDeny}}},
+		{groups: []*resource.NvSecurityWafGroupBinding{{Name: "g1", Action: "learn"}}, errMsg: "unsupported action"},
+		{groups: []*resource.NvSecurityWafGroupBinding{{Name: "g1", Action: share.PolicyActionDeny}, {Name: "g1", Action: share.PolicyActionAllow}}, errMsg: "duplicate group"},
+		{groups: []*resource.NvSecurityWafGroupBinding{{Name: "g3", Action: share.PolicyActionDeny}}, errMsg: "does not exist"},
+		{groups: []*resource.NvSecurityWafGroupBinding{{Name: "ext", Action: share.PolicyActionDeny}}, errMsg: "cannot have WAF policy"},
+		{groups: []*resource.NvSecurityWafGroupBinding{{Name: "g2", Action: share.PolicyActionDeny}}, errMsg: "defined by NvSecurityRule"},
+	}
+	for i, test := range tests {
+		wafSecRule := resource.NvWafSecurityRule{
+			Kind:     &kind,
+			Metadata: &metav1.ObjectMeta{Name: &name},
+			Spec:     resource.NvSecurityWafSpec{Groups: test.groups},
+		}
+		parsed, errCount, errMsg, _ := crdHandler.parseCurCrdWafContent(&wafSecRule, share.ReviewTypeCRD, share.ReviewTypeDisplayCRD)
+		if test.errMsg == "" {
+			if errCount > 0 || parsed.WafSensorGroups["g1"] != share.PolicyActionDeny {
+				t.Errorf("%d: Unexpected parse result: err=%v groups=%+v\n", i, errMsg, parsed)
+			}
+		} else if errCount == 0 || !strings.Contains(errMsg, test.errMsg) {
+			t.Errorf("%d: Expected error %v, got %v\n", i, test.errMsg, errMsg)
+		}
+	}
+
+	// apply the sensor to the groups, other sensors of the groups are kept
+	groups := crdHandler.crdHandleWafSensorGroups("s1", map[string]string{"g1": share.PolicyActionDeny, "g3": share.PolicyActionDeny}, nil)
+	if len(groups) != 1 || groups[0] != "g1" {
+		t.Errorf("Unexpected bound groups: %+v\n", groups)
+	}
+	if s := wafGroupSensors(mockCluster.wafGroups["g1"]); s != "s0:allow,s1:deny" {
+		t.Errorf("Unexpected sensors of g1: %v\n", s)
+	}
+
+	// change the action
+	crdHandler.crdHandleWafSensorGroups("s1", map[string]string{"g1": share.PolicyActionAllow}, groups)
+	if s := wafGroupSensors(mockCluster.wafGroups["g1"]); s != "s0:allow,s1:allow" {
+		t.Errorf("Unexpected sensors of g1: %v\n", s)
+	}
+
+	// remove the binding
+	groups = crdHandler.crdHandleWafSensorGroups("s1", nil, groups)
+	if len(groups) != 0 {
+		t.Errorf("Unexpected bound groups: %+v\n", groups)
+	}
+	if s := wafGroupSensors(mockCluster.wafGroups["g1"]); s != "s0:allow" {
+		t.Errorf("Unexpected sensors of g1: %v\n", s)
+	}
+
+	// the group defined by NvSecurityRule is not changed by NvWafSecurityRule
+	groups = crdHandler.crdHandleWafSensorGroups("s1", map[string]string{"g2": share.PolicyActionDeny}, nil)
+	if len(groups) != 0 {
+		t.Errorf("Unexpected bound groups: %+v\n", groups)
+	}
+	crdHandler.crdHandleWafSensorGroups("s1", nil, []string{"g2"})
+	if s := wafGroupSensors(mockCluster.wafGroups["g2"]); s != "s1:allow" {
+		t.Errorf("Unexpected sensors of g2: %v\n", s)
+	}
+
+	// NvSecurityRule takes over the group bound by NvWafSecurityRule
+	recordName := "NvWafSecurityRule-default-s1"
+	mockCluster.crdRecords[recordName] = &share.CLUSCrdSecurityRule{
+		Name:            recordName,
+		WafSensor:       "s1",
+		WafSensorGroups: crdHandler.crdHandleWafSensorGroups("s1", map[string]string{"g1": share.PolicyActionDeny}, nil),
+	}
+	crdHandler.crdReleaseWafSensorGroup("g1")
+	crdHandler.crdHandleWafGroup("g1", &api.RESTCrdWafGroupConfig{
+		RepSensors: []api.RESTCrdWafGroupSetting{{Name: "s2", Action: share.PolicyActionDeny}},
+	}, share.GroundCfg)
+	if r := mockCluster.crdRecords[recordName]; len(r.WafSensorGroups) != 0 {
+		t.Errorf("Group binding is not released: %+v\n", r.WafSensorGroups)
+	}
+	if g := mockCluster.wafGroups["g1"]; g.CfgType != share.GroundCfg || wafGroupSensors(g) != "s2:deny" {
+		t.Errorf("Unexpected waf group defined by NvSecurityRule: %+v %v\n", g, wafGroupSensors(g))
+	}
+
+	// deleting NvWafSecurityRule doesn't change the group owned by NvSecurityRule
+	crdHandler.crdHandleWafSensorGroups("s1", nil, mockCluster.crdRecords[recordName].WafSensorGroups)
+	if s := wafGroupSensors(mockCluster.wafGroups["g1"]); s != "s2:deny" {
+		t.Errorf("Unexpected sensors of g1: %v\n", s)
+	}
+
+	postTest()
+}
+
+func TestCrdWafStatus(t *testing.T) {
+	kind := resource.NvWafSecurityRuleKind
+	name := "s1"
+	wafSecRule := &resource.NvWafSecurityRule{
+		Kind:     &kind,
+		Metadata: &metav1.ObjectMeta{Name: &name, Generation: func() *int64 { g := int64(2); return &g }()},
+	}
+
+	// the status of waf crd object has the same conditions as NvSecurityRule
+	if !setCrdObjectStatus(wafSecRule, "", "", false, "t1") {
+		t.Fatalf("Status of waf crd object is not set\n")
+	}
+	if status := wafSecRule.Status; status == nil || status.ObservedGeneration != 2 || len(status.Conditions) != 3 {
+		t.Errorf("Unexpected status: %+v\n", status)
+	}
+
+	// rejected while the last accepted spec is still in effect
+	*wafSecRule.Metadata.Generation = 3
+	setCrdObjectStatus(wafSecRule, "invalid sensor", "", true, "t2")
+	if wafSecRule.Status.ObservedGeneration != 3 {
+		t.Errorf("Unexpected observed generation: %+v\n", wafSecRule.Status.ObservedGeneration)
+	}
+	for _, c := range wafSecRule.Status.Conditions {
+		switch c.Type {
+		case resource.NvCrdConditionAccepted:
+			if c.Status != resource.NvCrdConditionFalse || c.Message != "invalid sensor" {
+				t.Errorf("Unexpected accepted condition: %+v\n", c)
+			}
+		case resource.NvCrdConditionSynced:
+			if c.Status != resource.NvCrdConditionTrue || c.LastTransitionTime != "t1" {
+				t.Errorf("Unexpected synced condition: %+v\n", c)
+			}
+		}
+	}
+
+	// other crd objects don't have the status
+	dlpKind := resource.NvDlpSecurityRuleKind
+	if setCrdObjectStatus(&resource.NvDlpSecurityRule{Kind: &dlpKind, Metadata: &metav1.ObjectMeta{Name: &name}}, "", "", false, "t3") {
+		t.Errorf("Status of dlp crd object should not be set\n")
+	}
+}
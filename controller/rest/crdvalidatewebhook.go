@@ -24,7 +24,7 @@ func (h *nvCrdHandler) crdvalidate(ar *admissionv1beta1.AdmissionReview) {
 
 	switch req.Kind.Kind {
 	case resource.NvSecurityRuleKind, resource.NvClusterSecurityRuleKind, resource.NvAdmCtrlSecurityRuleKind,
-		resource.NvDlpSecurityRuleKind, resource.NvWafSecurityRuleKind:
+		resource.NvDlpSecurityRuleKind, resource.NvWafSecurityRuleKind, resource.NvVulnProfileKind, resource.NvCompProfileKind:
 		h.crdSecRuleHandler(req)
 	}
 	return
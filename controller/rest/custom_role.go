@@ -57,6 +57,17 @@ func restPermissionsToCLUS(roleName string, restPermissions []*api.RESTRolePermi
 					return nil, fmt.Errorf("invalid write permission %s", p.ID)
 				}
 			}
+			if len(p.Domains) > 0 {
+				if (option.SupportScope & access.CONST_PERM_SUPPORT_DOMAIN) == 0 {
+					return nil, fmt.Errorf("permission %s cannot be restricted to namespaces", p.ID)
+				}
+				for _, domain := range p.Domains {
+					if !isDomainNameValid(domain) {
+						return nil, fmt.Errorf("invalid namespace %s for permission %s", domain, p.ID)
+					}
+				}
+				permission.Domains = p.Domains
+			}
 		} else {
 			return nil, fmt.Errorf("invalid permission %s", p.ID)
 		}
@@ -3,6 +3,7 @@ package rest
 import (
 	"encoding/json"
 	"net/http"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -142,7 +143,7 @@ func TestRoleCreateDelete(t *testing.T) {
 	for _, crole := range resp.Roles {
 		if crole.Name == data.Config.Name {
 			foundRole = true
-			if crole.Comment != data.Config.Comment || crole.Reserved || len(crole.Permissions) != 1 || *crole.Permissions[0] != *data.Config.Permissions[0] {
+			if crole.Comment != data.Config.Comment || crole.Reserved || len(crole.Permissions) != 1 || !reflect.DeepEqual(crole.Permissions[0], data.Config.Permissions[0]) {
 				t.Fatalf("Incorrect role by REST: role=%v", crole)
 			}
 		}
@@ -539,7 +540,7 @@ func TestRoleConfig(t *testing.T) {
 	verifyCustomRole("12", &data, accAdmin, t)
 
 	// Config role with valid modify-only permission
-	modifyOnlyPermissionIDs := []string{"ci_scan"}
+	modifyOnlyPermissionIDs := []string{"ci_scan", "risk_approval", "policy_approval"}
 	for _, modifyOnlyPermitID := range modifyOnlyPermissionIDs {
 		data.Config.Permissions = []*api.RESTRolePermission{
 			&api.RESTRolePermission{ID: modifyOnlyPermitID, Write: true},
@@ -863,3 +864,87 @@ func TestUserWithCustomRole(t *testing.T) {
 
 	postTest()
 }
+
+func TestRoleWithDomainScopes(t *testing.T) {
+	preTest()
+
+	groups := []*share.CLUSGroup{
+		&share.CLUSGroup{Name: "g1", Domain: "ns1", CfgType: share.UserCreated},
+		&share.CLUSGroup{Name: "g2", Domain: "ns2", CfgType: share.UserCreated},
+		&share.CLUSGroup{Name: "g3", Domain: "team-a", CfgType: share.UserCreated},
+	}
+
+	var mockCluster kv.MockCluster
+	initKvAndCache(&mockCluster, nil, groups)
+	cache.MockCacheInit()
+	clusHelper.SetCacheMockCallback(share.CLUSConfigUserRoleStore, cache.MockUserRoleConfigUpdate)
+
+	data := api.RESTUserRoleConfigData{
+		Config: &api.RESTUserRoleConfig{
+			Name:    "team-policy",
+			Comment: "edit policies of team namespaces",
+			Permissions: []*api.RESTRolePermission{
+				&api.RESTRolePermission{ID: share.PERMS_RUNTIME_POLICIES_ID, Read: true, Write: true, Domains: []string{"ns1", "team-*"}},
+				&api.RESTRolePermission{ID: share.PERM_REG_SCAN_ID, Read: true},
+			},
+		}}
+	body, _ := json.Marshal(data)
+	w := restCall("POST", "/v1/user_role", body, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to create role: status=%v.", w.status)
+	}
+	verifyCustomRole("31", &data, access.NewAdminAccessControl(), t)
+
+	var resp api.RESTUserRoleData
+	w = restCall("GET", "/v1/user_role/"+data.Config.Name, nil, api.UserRoleAdmin)
+	json.Unmarshal(w.body, &resp)
+	for _, p := range resp.Role.Permissions {
+		if p.ID == share.PERMS_RUNTIME_POLICIES_ID && len(p.Domains) != 2 {
+			t.Errorf("Unexpected domains of permission: %+v", p)
+		} else if p.ID == share.PERM_REG_SCAN_ID && len(p.Domains) != 0 {
+			t.Errorf("Unexpected domains of permission: %+v", p)
+		}
+	}
+
+	// Only the groups in the scope domains are visible
+	var groupsResp api.RESTGroupsData
+	w = restCall("GET", "/v1/group", nil, data.Config.Name)
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to list groups: status=%v.", w.status)
+	}
+	json.Unmarshal(w.body, &groupsResp)
+	if len(groupsResp.Groups) != 2 {
+		t.Errorf("Unexpected groups: %+v", groupsResp.Groups)
+	}
+	for _, g := range groupsResp.Groups {
+		if g.Name == "g2" {
+			t.Errorf("Group out of the scope is visible: %+v", g)
+		}
+	}
+
+	// Permissions only supported globally cannot be restricted
+	data.Config.Permissions = []*api.RESTRolePermission{
+		&api.RESTRolePermission{ID: share.PERM_SYSTEM_CONFIG_ID, Read: true, Domains: []string{"ns1"}},
+	}
+	body, _ = json.Marshal(data)
+	w = restCall("PATCH", "/v1/user_role/"+data.Config.Name, body, api.UserRoleAdmin)
+	if w.status != http.StatusBadRequest {
+		t.Errorf("Surprised to restrict global permission to namespaces: status=%v.", w.status)
+	}
+
+	data.Config.Permissions = []*api.RESTRolePermission{
+		&api.RESTRolePermission{ID: share.PERMS_RUNTIME_POLICIES_ID, Read: true, Domains: []string{"NS_1"}},
+	}
+	body, _ = json.Marshal(data)
+	w = restCall("PATCH", "/v1/user_role/"+data.Config.Name, body, api.UserRoleAdmin)
+	if w.status != http.StatusBadRequest {
+		t.Errorf("Surprised to restrict permission to invalid namespace: status=%v.", w.status)
+	}
+
+	w = restCall("DELETE", "/v1/user_role/"+data.Config.Name, nil, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Errorf("Failed to delete role: status=%v.", w.status)
+	}
+
+	postTest()
+}
@@ -0,0 +1,88 @@
+package rest
+
+// Read-only mode when the cluster kv store loses quorum. The cache is still served, but the requests that
+// change configurations are rejected until the kv is recovered and the cache is re-synced.
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+const kvDegradedRetryAfter = "30"
+
+var _kvDegradedSince int64 // unix time in nano-seconds, 0 if kv is available
+
+// POST requests that don't write configurations
+var kvReadOnlyPaths utils.Set = utils.NewSet(
+	"/v1/auth",
+	"/v1/graphql",
+	"/v1/file/group",
+	"/v1/file/admission",
+	"/v1/file/dlp",
+	"/v1/file/waf",
+	"/v1/file/network_policy",
+	"/v1/csp/file/support",
+)
+
+var kvReadOnlyPrefixes []string = []string{
+	"/v1/auth/",
+	"/v1/token_auth_server/",
+}
+
+func KvQuorumNotify(lost bool) {
+	if lost {
+		if atomic.CompareAndSwapInt64(&_kvDegradedSince, 0, time.Now().UnixNano()) {
+			log.Warn("kv quorum is lost, enter read-only mode")
+		}
+	} else if atomic.SwapInt64(&_kvDegradedSince, 0) != 0 {
+		log.Info("kv is recovered, leave read-only mode")
+	}
+}
+
+func getKvDegradedSince() (time.Time, bool) {
+	since := atomic.LoadInt64(&_kvDegradedSince)
+	if since == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, since), true
+}
+
+func isKvReadOnlyRequest(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	// login, logout and requests that only query the cache
+	if kvReadOnlyPaths.Contains(r.URL.Path) {
+		return true
+	}
+	for _, prefix := range kvReadOnlyPrefixes {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+type kvDegradedHandler struct {
+	handler http.Handler
+}
+
+func (h kvDegradedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, degraded := getKvDegradedSince(); degraded {
+		w.Header().Set(api.RESTDegradedHeader, api.DegradedKvQuorumLost)
+		if !isKvReadOnlyRequest(r) {
+			w.Header().Set("Retry-After", kvDegradedRetryAfter)
+			restRespErrorMessage(w, http.StatusServiceUnavailable, api.RESTErrKvUnavailable, "")
+			return
+		}
+	}
+	h.handler.ServeHTTP(w, r)
+}
@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+)
+
+func TestKvDegradedHandler(t *testing.T) {
+	preTest()
+	defer KvQuorumNotify(false)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := kvDegradedHandler{handler: next}
+
+	cases := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{"GET", "/v1/workload", http.StatusOK},
+		{"POST", "/v1/auth", http.StatusOK},
+		{"DELETE", "/v1/auth", http.StatusOK},
+		{"PATCH", "/v1/auth/saml", http.StatusOK},
+		{"POST", "/v1/file/group", http.StatusOK},
+		{"POST", "/v1/group", http.StatusServiceUnavailable},
+		{"PATCH", "/v1/system/config", http.StatusServiceUnavailable},
+		{"DELETE", "/v1/user/admin", http.StatusServiceUnavailable},
+	}
+
+	// Nothing is blocked when kv is available
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(c.method, c.path, nil))
+		if w.Code != http.StatusOK || w.Header().Get(api.RESTDegradedHeader) != "" {
+			t.Errorf("%s %s: unexpected response when kv is available: %d", c.method, c.path, w.Code)
+		}
+	}
+
+	KvQuorumNotify(true)
+	if _, degraded := getKvDegradedSince(); !degraded {
+		t.Errorf("Degraded mode should be entered")
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(c.method, c.path, nil))
+		if w.Code != c.status {
+			t.Errorf("%s %s: status=%d, expect=%d", c.method, c.path, w.Code, c.status)
+		}
+		if w.Header().Get(api.RESTDegradedHeader) != api.DegradedKvQuorumLost {
+			t.Errorf("%s %s: degraded header is not set", c.method, c.path)
+		}
+		if c.status == http.StatusServiceUnavailable && w.Header().Get("Retry-After") == "" {
+			t.Errorf("%s %s: Retry-After header is not set", c.method, c.path)
+		}
+	}
+
+	KvQuorumNotify(false)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/v1/group", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Request should be allowed after kv is recovered: %d", w.Code)
+	}
+
+	postTest()
+}
@@ -0,0 +1,136 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+// Library of the validated dlp patterns. The rules of a pack are added to a sensor as its own rules when the
+// pack is selected, so they can be edited like the other rules. The library is shipped with the controller;
+// when a pack is selected again, its rules replace the ones of the same names in the sensor, so the sensor
+// is refreshed to the patterns of the running version. The patterns don't use the characters that are taken
+// as wildcards by the rule validation, such as a space followed by "?".
+
+func dlpPackRule(name, pattern, checksum string) *api.RESTDlpRule {
+	rule := &api.RESTDlpRule{
+		Name:     name,
+		Patterns: []api.RESTDlpCriteriaEntry{{Key: share.DlpRuleKeyPattern, Value: pattern, Op: share.CriteriaOpRegex}},
+		CfgType:  api.CfgSystemDefined,
+	}
+	if checksum != "" {
+		rule.Patterns = append(rule.Patterns, api.RESTDlpCriteriaEntry{Key: share.DlpRuleKeyChecksum, Value: checksum, Op: share.CriteriaOpEqual})
+	}
+	return rule
+}
+
+var dlpPacks = []*api.RESTDlpPack{
+	&api.RESTDlpPack{
+		Name:    "us",
+		Comment: "United States social security number, individual taxpayer identification number and medicare beneficiary identifier",
+		Rules: []*api.RESTDlpRule{
+			dlpPackRule("us_ssn", `\b(?!\b(\d)\1+-?(\d)\1+-?(\d)\1+\b)(?!123-?45-?6789|219-?09-?9999|078-?05-?1120)(?!666|000|9\d{2})\d{3}-?(?!00)\d{2}-?(?!0{4})\d{4}\b`, ""),
+			dlpPackRule("us_itin", `\b9\d{2}[ -]?(5\d|6[0-5]|7\d|8[0-8]|9[0-2]|9[4-9])[ -]?\d{4}\b`, ""),
+			dlpPackRule("us_mbi", `\b[1-9][AC-HJKMNP-RT-Y][AC-HJKMNP-RT-Y0-9]\d-?[AC-HJKMNP-RT-Y][AC-HJKMNP-RT-Y0-9]\d-?[AC-HJKMNP-RT-Y]{2}\d{2}\b`, ""),
+		},
+	},
+	&api.RESTDlpPack{
+		Name:    "uk",
+		Comment: "United Kingdom national insurance number and nhs number",
+		Rules: []*api.RESTDlpRule{
+			dlpPackRule("uk_nino", `\b(?!BG|GB|NK|KN|TN|NT|ZZ)[A-CEGHJ-PR-TW-Z][A-CEGHJ-NPR-TW-Z][ ]?\d{2}[ ]?\d{2}[ ]?\d{2}[ ]?[A-D]\b`, ""),
+			dlpPackRule("uk_nhs", `\b\d{3}[ -]?\d{3}[ -]?\d{4}\b`, share.DlpChecksumNhs),
+		},
+	},
+	&api.RESTDlpPack{
+		Name:    "ca",
+		Comment: "Canada social insurance number",
+		Rules: []*api.RESTDlpRule{
+			dlpPackRule("ca_sin", `\b[1-79]\d{2}[ -]?\d{3}[ -]?\d{3}\b`, share.DlpChecksumLuhn),
+		},
+	},
+	&api.RESTDlpPack{
+		Name:    "in",
+		Comment: "India aadhaar number and permanent account number",
+		Rules: []*api.RESTDlpRule{
+			dlpPackRule("in_aadhaar", `\b[2-9]\d{3}[ -]?\d{4}[ -]?\d{4}\b`, share.DlpChecksumVerhoeff),
+			dlpPackRule("in_pan", `\b[A-Z]{3}[ABCFGHJLPT][A-Z]\d{4}[A-Z]\b`, ""),
+		},
+	},
+	&api.RESTDlpPack{
+		Name:    "intl",
+		Comment: "International bank account number and machine readable zone of passports",
+		Rules: []*api.RESTDlpRule{
+			dlpPackRule("iban", `\b[A-Z]{2}\d{2}(?:[ ]?[A-Z0-9]{4}){2,7}(?:[ ]?[A-Z0-9]{1,3})?\b`, share.DlpChecksumIban),
+			dlpPackRule("passport_mrz", `\b[A-Z0-9<]{9}\d[A-Z<]{3}\d{6}\d[MFX<]\d{6}\d[A-Z0-9<]{14}[0-9<]\d\b`, share.DlpChecksumMrz),
+		},
+	},
+}
+
+func getDlpPack(name string) *api.RESTDlpPack {
+	for _, pack := range dlpPacks {
+		if pack.Name == name {
+			return pack
+		}
+	}
+	return nil
+}
+
+// Add the rules of the selected packs to the replace list if it's used, otherwise to the change list
+func applyDlpPacks(conf *api.RESTDlpSensorConfig) error {
+	if conf.Packs == nil {
+		return nil
+	}
+
+	rules := conf.Rules
+	if rules == nil {
+		if conf.RuleChgList == nil {
+			list := make([]api.RESTDlpRule, 0)
+			conf.RuleChgList = &list
+		}
+		rules = conf.RuleChgList
+	}
+
+	for _, name := range *conf.Packs {
+		pack := getDlpPack(name)
+		if pack == nil {
+			log.WithFields(log.Fields{"pack": name}).Error("Unknown dlp pack")
+			return fmt.Errorf("unknown dlp pack (%s)", name)
+		}
+	NEXT_RULE:
+		for _, pr := range pack.Rules {
+			rule := *pr
+			rule.Patterns = append([]api.RESTDlpCriteriaEntry(nil), pr.Patterns...)
+			rule.CfgType = api.CfgTypeUserCreated
+			for i := range *rules {
+				if (*rules)[i].Name == rule.Name {
+					(*rules)[i] = rule
+					continue NEXT_RULE
+				}
+			}
+			*rules = append(*rules, rule)
+		}
+	}
+	return nil
+}
+
+func handlerDlpPackList(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSDlpSensor{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	resp := api.RESTDlpPacksData{Packs: dlpPacks}
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get dlp pack list")
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestDlpPackPatterns(t *testing.T) {
+	for _, pack := range dlpPacks {
+		for _, rule := range pack.Rules {
+			if !isObjectNameValid(rule.Name) {
+				t.Errorf("Invalid rule name: pack=%s, rule=%s", pack.Name, rule.Name)
+			}
+			for _, pt := range rule.Patterns {
+				if pt.Key == share.DlpRuleKeyPattern && wildCardToRegexp(pt.Value) != pt.Value {
+					t.Errorf("Pattern is changed by wildcard conversion: pack=%s, rule=%s", pack.Name, rule.Name)
+				} else if pt.Key == share.DlpRuleKeyChecksum && !dlpChecksums.Contains(pt.Value) {
+					t.Errorf("Unknown checksum: pack=%s, rule=%s, checksum=%s", pack.Name, rule.Name, pt.Value)
+				}
+			}
+		}
+	}
+}
+
+func TestApplyDlpPacks(t *testing.T) {
+	// Pack rules are added to the change list, the rule of the same name is replaced
+	packs := []string{"uk"}
+	chgs := []api.RESTDlpRule{
+		api.RESTDlpRule{Name: "uk_nhs", Patterns: []api.RESTDlpCriteriaEntry{api.RESTDlpCriteriaEntry{Key: share.DlpRuleKeyPattern, Value: "old"}}},
+		api.RESTDlpRule{Name: "rule1"},
+	}
+	conf := &api.RESTDlpSensorConfig{Name: "sensor1", Packs: &packs, RuleChgList: &chgs}
+	if err := applyDlpPacks(conf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if conf.Rules != nil || len(*conf.RuleChgList) != 3 {
+		t.Fatalf("Unexpected rules: %+v", *conf.RuleChgList)
+	}
+	if r := (*conf.RuleChgList)[0]; r.Name != "uk_nhs" || len(r.Patterns) != 2 || r.Patterns[1].Value != share.DlpChecksumNhs {
+		t.Errorf("Rule is not replaced: %+v", r)
+	}
+	if r := (*conf.RuleChgList)[2]; r.Name != "uk_nino" || r.CfgType != api.CfgTypeUserCreated {
+		t.Errorf("Unexpected added rule: %+v", r)
+	}
+
+	// Pack rules are added to the replace list if it's used
+	packs = []string{"ca", "in"}
+	rules := make([]api.RESTDlpRule, 0)
+	conf = &api.RESTDlpSensorConfig{Name: "sensor1", Packs: &packs, Rules: &rules}
+	if err := applyDlpPacks(conf); err != nil || conf.RuleChgList != nil || len(*conf.Rules) != 3 {
+		t.Errorf("Unexpected rules: err=%v, rules=%+v", err, *conf.Rules)
+	}
+
+	// The library is not modified
+	(*conf.Rules)[0].Patterns[0].Value = "changed"
+	if getDlpPack("ca").Rules[0].Patterns[0].Value == "changed" {
+		t.Errorf("Pack rule should not be modified")
+	}
+
+	packs = []string{"xx"}
+	conf = &api.RESTDlpSensorConfig{Name: "sensor1", Packs: &packs}
+	if err := applyDlpPacks(conf); err == nil {
+		t.Errorf("Unknown pack should fail")
+	}
+}
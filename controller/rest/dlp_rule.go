@@ -272,6 +272,9 @@ func handlerDlpGroupShow(w http.ResponseWriter, r *http.Request, ps httprouter.P
 
 var regPattern *regexp.Regexp = regexp.MustCompile(`^\.*\*$`)
 
+var dlpChecksums utils.Set = utils.NewSet(share.DlpChecksumLuhn, share.DlpChecksumIban, share.DlpChecksumVerhoeff,
+	share.DlpChecksumNhs, share.DlpChecksumMrz)
+
 func wildCardToRegexp(pattern string) string {
 	/* Match one or many simple wildcard characters "?" or one "*" with
 	   previous token being a letter, number, whitespace or the start of the pattern.
@@ -333,7 +336,35 @@ func validateDlpRuleConfig(list []api.RESTDlpRule) error {
 			return fmt.Errorf("dlp rule %s: must have no more than %d patterns", rule.Name, api.DlpRulePatternMaxNum)
 		}
 		total_len := 0
+		checksum := ""
+		hasRegex := false
 		for i, pt := range rule.Patterns {
+			if pt.Key == share.DlpRuleKeyChecksum {
+				if !dlpChecksums.Contains(pt.Value) || pt.Op != share.CriteriaOpEqual {
+					log.WithFields(log.Fields{"value": pt.Value, "op": pt.Op}).Error("Invalid checksum")
+					return fmt.Errorf("dlp rule %s: invalid checksum (%s)", rule.Name, pt.Value)
+				}
+				if checksum != "" {
+					log.WithFields(log.Fields{"name": rule.Name}).Error("Dlp rule has multiple checksums")
+					return fmt.Errorf("dlp rule %s: dlp rule can have only one checksum", rule.Name)
+				}
+				checksum = pt.Value
+				continue
+			}
+			if pt.Key == share.DlpRuleKeyFileType {
+				if _, ok := share.DlpFileTypeMagic[pt.Value]; !ok || pt.Op != share.CriteriaOpEqual {
+					log.WithFields(log.Fields{"value": pt.Value, "op": pt.Op}).Error("Invalid file type")
+					return fmt.Errorf("dlp rule %s: invalid file type (%s)", rule.Name, pt.Value)
+				}
+				if pt.Context != "" && pt.Context != share.DlpPatternContextBODY {
+					log.WithFields(log.Fields{"context": pt.Context}).Error("Invalid file type context")
+					return fmt.Errorf("dlp rule %s: file type can only be detected in body", rule.Name)
+				}
+				continue
+			}
+			if pt.Op == share.CriteriaOpRegex {
+				hasRegex = true
+			}
 			if pt.Op == share.CriteriaOpRegex || pt.Op == share.CriteriaOpNotRegex {
 				if len(pt.Value) > api.DlpRulePatternMaxLen {
 					log.WithFields(log.Fields{"pattern": pt.Value, "pattern_len": len(pt.Value)}).Error("Invalid pattern length")
@@ -348,7 +379,8 @@ func validateDlpRuleConfig(list []api.RESTDlpRule) error {
 					pt.Context != share.DlpPatternContextURI &&
 					pt.Context != share.DlpPatternContextHEAD &&
 					pt.Context != share.DlpPatternContextBODY &&
-					pt.Context != share.DlpPatternContextPACKET {
+					pt.Context != share.DlpPatternContextPACKET &&
+					pt.Context != share.DlpPatternContextFILE {
 					log.WithFields(log.Fields{"context": pt.Context}).Error("Invalid pattern context")
 					return fmt.Errorf("dlp rule %s: invalid pattern context (%s)", rule.Name, pt.Context)
 				}
@@ -364,6 +396,10 @@ func validateDlpRuleConfig(list []api.RESTDlpRule) error {
 				}
 			}
 		}
+		if checksum != "" && !hasRegex {
+			log.WithFields(log.Fields{"name": rule.Name}).Error("Dlp rule checksum without pattern")
+			return fmt.Errorf("dlp rule %s: checksum requires a regex pattern", rule.Name)
+		}
 	}
 	return nil
 }
@@ -584,6 +620,10 @@ func handlerDlpSensorCreate(w http.ResponseWriter, r *http.Request, ps httproute
 		rules := make([]api.RESTDlpRule, 0)
 		conf.Rules = &rules
 	}
+	if err := applyDlpPacks(conf); err != nil {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+		return
+	}
 	if err := validateDlpRuleConfig(*conf.Rules); err != nil {
 		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
 		return
@@ -972,6 +1012,11 @@ func handlerDlpSensorConfig(w http.ResponseWriter, r *http.Request, ps httproute
 		}
 	}
 
+	if err := applyDlpPacks(conf); err != nil {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+		return
+	}
+
 	if conf.Rules != nil {
 		if err := validateDlpRuleConfig(*conf.Rules); err != nil {
 			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
@@ -1582,6 +1627,68 @@ func handlerDebugDlpRuleMac(w http.ResponseWriter, r *http.Request, ps httproute
 	}
 }
 
+// export a non-predefined dlp sensor as NvDlpSecurityRule. When withGroups is true, the groups that the sensor is applied to are exported as well
+func exportDlpSecurityRule(sensor, defSensor *share.CLUSDlpSensor, withGroups bool, acc *access.AccessControl) *resource.NvDlpSecurityRule {
+	apiversion := fmt.Sprintf("%s/%s", common.OEMClusterSecurityRuleGroup, resource.NvDlpSecurityRuleVersion)
+
+	ruleList := make([]*resource.NvSecurityDlpRule, 0, len(sensor.RuleListNames))
+	for rName, _ := range sensor.RuleListNames {
+		if r, ok := defSensor.RuleList[rName]; ok {
+			patterns := make([]api.RESTDlpCriteriaEntry, len(r.Patterns))
+			for idx, p := range r.Patterns {
+				patterns[idx] = api.RESTDlpCriteriaEntry{
+					Key:     p.Key,
+					Value:   p.Value,
+					Op:      p.Op,
+					Context: p.Context,
+				}
+			}
+			if ss := strings.Split(rName, common.DLPRuleTag); len(ss) > 1 {
+				r.Name = ss[1] // use simple name for exported sensor's rules
+				rule := &resource.NvSecurityDlpRule{
+					Name:     &r.Name,
+					Patterns: patterns,
+				}
+				ruleList = append(ruleList, rule)
+			}
+		}
+	}
+	// export the groups that the sensor is applied to. Otherwise the groups' settings are exported in their own NvSecurityRule objects
+	var groups []*resource.NvSecurityDlpGroupBinding
+	if withGroups {
+		if cs, _ := cacher.GetDlpSensor(sensor.Name, acc); cs != nil {
+			for _, g := range cs.GroupList {
+				if dlpGroup, _ := cacher.GetDlpGroup(g, acc); dlpGroup != nil {
+					for _, s := range dlpGroup.Sensors {
+						if s.Name == sensor.Name {
+							groups = append(groups, &resource.NvSecurityDlpGroupBinding{Name: g, Action: s.Action})
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+
+	kind := resource.NvDlpSecurityRuleKind
+	resptmp := resource.NvDlpSecurityRule{
+		Kind: &kind,
+		Metadata: &cmetav1.ObjectMeta{
+			Name: &sensor.Name,
+		},
+		ApiVersion: &apiversion,
+		Spec: resource.NvSecurityDlpSpec{
+			Sensor: &resource.NvSecurityDlpSensor{
+				Name:     sensor.Name,
+				RuleList: ruleList,
+				Comment:  &sensor.Comment,
+			},
+			Groups: groups,
+		},
+	}
+	return &resptmp
+}
+
 func handlerDlpExport(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
 	defer r.Body.Close()
@@ -1620,7 +1727,6 @@ func handlerDlpExport(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 	}
 	defer clusHelper.ReleaseLock(lock)
 
-	apiversion := fmt.Sprintf("%s/%s", common.OEMClusterSecurityRuleGroup, resource.NvDlpSecurityRuleVersion)
 	defSensor := clusHelper.GetDlpSensor(share.CLUSDlpDefaultSensor)
 	// export selected dlp sensors
 	for _, name := range rconf.Names {
@@ -1635,44 +1741,7 @@ func handlerDlpExport(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 			continue
 		}
 
-		ruleList := make([]*resource.NvSecurityDlpRule, 0, len(sensor.RuleListNames))
-		for rName, _ := range sensor.RuleListNames {
-			if r, ok := defSensor.RuleList[rName]; ok {
-				patterns := make([]api.RESTDlpCriteriaEntry, len(r.Patterns))
-				for idx, p := range r.Patterns {
-					patterns[idx] = api.RESTDlpCriteriaEntry{
-						Key:     p.Key,
-						Value:   p.Value,
-						Op:      p.Op,
-						Context: p.Context,
-					}
-				}
-				if ss := strings.Split(rName, common.DLPRuleTag); len(ss) > 1 {
-					r.Name = ss[1] // use simple name for exported sensor's rules
-					rule := &resource.NvSecurityDlpRule{
-						Name:     &r.Name,
-						Patterns: patterns,
-					}
-					ruleList = append(ruleList, rule)
-				}
-			}
-		}
-		kind := resource.NvDlpSecurityRuleKind
-		resptmp := resource.NvDlpSecurityRule{
-			Kind: &kind,
-			Metadata: &cmetav1.ObjectMeta{
-				Name: &sensor.Name,
-			},
-			ApiVersion: &apiversion,
-			Spec: resource.NvSecurityDlpSpec{
-				Sensor: &resource.NvSecurityDlpSensor{
-					Name:     sensor.Name,
-					RuleList: ruleList,
-					Comment:  &sensor.Comment,
-				},
-			},
-		}
-		resp.Items = append(resp.Items, &resptmp)
+		resp.Items = append(resp.Items, exportDlpSecurityRule(sensor, defSensor, true, acc))
 	}
 
 	// tell the browser the returned content should be downloaded
@@ -1780,6 +1849,7 @@ func importDlp(scope string, loginDomainRoles access.DomainRole, importTask shar
 						importTask.Status = err.Error()
 						break
 					}
+					crdHandler.crdHandleDlpSensorGroups(parsedCfg.DlpSensorCfg.Name, parsedCfg.DlpSensorGroups, nil)
 					progress += inc
 					importTask.Percentage = int(progress)
 					clusHelper.PutImportTask(&importTask)
@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestDlpRuleFileType(t *testing.T) {
+	cases := []struct {
+		pattern api.RESTDlpCriteriaEntry
+		valid   bool
+	}{
+		{api.RESTDlpCriteriaEntry{Key: share.DlpRuleKeyFileType, Value: share.DlpFileTypePDF, Op: share.CriteriaOpEqual}, true},
+		{api.RESTDlpCriteriaEntry{Key: share.DlpRuleKeyFileType, Value: share.DlpFileTypeOffice, Op: share.CriteriaOpEqual, Context: share.DlpPatternContextBODY}, true},
+		{api.RESTDlpCriteriaEntry{Key: share.DlpRuleKeyFileType, Value: "exe", Op: share.CriteriaOpEqual}, false},
+		{api.RESTDlpCriteriaEntry{Key: share.DlpRuleKeyFileType, Value: share.DlpFileTypeZip, Op: share.CriteriaOpRegex}, false},
+		{api.RESTDlpCriteriaEntry{Key: share.DlpRuleKeyFileType, Value: share.DlpFileTypeZip, Op: share.CriteriaOpEqual, Context: share.DlpPatternContextHEAD}, false},
+	}
+
+	for i, c := range cases {
+		rules := []api.RESTDlpRule{api.RESTDlpRule{Name: "rule1", Patterns: []api.RESTDlpCriteriaEntry{c.pattern}}}
+		if err := validateDlpRuleConfig(rules); (err == nil) != c.valid {
+			t.Errorf("Unexpected validation: case=%d, pattern=%+v, err=%v", i, c.pattern, err)
+		}
+	}
+}
+
+func TestDlpRuleChecksum(t *testing.T) {
+	cases := [][]api.RESTDlpCriteriaEntry{
+		[]api.RESTDlpCriteriaEntry{
+			api.RESTDlpCriteriaEntry{Key: share.DlpRuleKeyChecksum, Value: "crc32", Op: share.CriteriaOpEqual},
+		},
+		[]api.RESTDlpCriteriaEntry{
+			api.RESTDlpCriteriaEntry{Key: share.DlpRuleKeyChecksum, Value: share.DlpChecksumLuhn, Op: share.CriteriaOpRegex},
+		},
+		[]api.RESTDlpCriteriaEntry{
+			api.RESTDlpCriteriaEntry{Key: share.DlpRuleKeyChecksum, Value: share.DlpChecksumLuhn, Op: share.CriteriaOpEqual},
+			api.RESTDlpCriteriaEntry{Key: share.DlpRuleKeyChecksum, Value: share.DlpChecksumIban, Op: share.CriteriaOpEqual},
+		},
+		[]api.RESTDlpCriteriaEntry{
+			api.RESTDlpCriteriaEntry{Key: share.DlpRuleKeyChecksum, Value: share.DlpChecksumLuhn, Op: share.CriteriaOpEqual},
+			api.RESTDlpCriteriaEntry{Key: share.DlpRuleKeyFileType, Value: share.DlpFileTypePDF, Op: share.CriteriaOpEqual},
+		},
+	}
+
+	for i, patterns := range cases {
+		rules := []api.RESTDlpRule{api.RESTDlpRule{Name: "rule1", Patterns: patterns}}
+		if err := validateDlpRuleConfig(rules); err == nil {
+			t.Errorf("Invalid checksum should fail: case=%d, patterns=%+v", i, patterns)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
 	"github.com/neuvector/neuvector/share/utils"
 )
 
@@ -161,6 +162,16 @@ func handlerDomainEntryConfig(w http.ResponseWriter, r *http.Request, ps httprou
 		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
 		return
 	}
+	if rd.NewServicePolicyMode != nil {
+		switch *rd.NewServicePolicyMode {
+		case "", share.PolicyModeLearn, share.PolicyModeEvaluate, share.PolicyModeEnforce:
+		default:
+			e := "Invalid new service policy mode"
+			log.WithFields(log.Fields{"new_service_policy_mode": *rd.NewServicePolicyMode}).Error(e)
+			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+			return
+		}
+	}
 
 	retry := 0
 	for retry < retryClusterMax {
@@ -178,6 +189,15 @@ func handlerDomainEntryConfig(w http.ResponseWriter, r *http.Request, ps httprou
 				cd.Tags = tags
 			}
 		}
+		if rd.NewServicePolicyMode != nil {
+			if cd.Dummy {
+				e := "New service policy mode cannot be set for the namespace"
+				log.WithFields(log.Fields{"domain": name}).Error(e)
+				restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+				return
+			}
+			cd.NewServicePolicyMode = *rd.NewServicePolicyMode
+		}
 
 		if !acc.Authorize(cd, nil) {
 			restRespAccessDenied(w, login)
@@ -0,0 +1,177 @@
+package rest
+
+// Conversation map across the clusters in federation. Each cluster reports the flows between its workloads and the
+// endpoints outside the cluster in fed report. Master cluster matches the egress flows of a cluster with the ingress
+// flows of the cluster that owns the destination ip.
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+const fedConverFlowMax = 2000 // max flows in the report of a cluster
+
+// keep the flows with most traffic
+func trimFedConverFlows(flows []*share.CLUSFedConverFlow, max int) []*share.CLUSFedConverFlow {
+	sort.Slice(flows, func(i, j int) bool {
+		if flows[i].Bytes != flows[j].Bytes {
+			return flows[i].Bytes > flows[j].Bytes
+		}
+		return flows[i].LastSeen > flows[j].LastSeen
+	})
+	if len(flows) > max {
+		flows = flows[:max]
+	}
+	return flows
+}
+
+func fedConverEndpointID(clusterID, service string) string {
+	return fmt.Sprintf("%s/%s", clusterID, service)
+}
+
+func matchFedConverFlows(reports []*share.CLUSFedClusterReport) *api.RESTFedConversationData {
+	// the cluster that a host ip belongs to
+	owners := make(map[string]*share.CLUSFedClusterReport)
+	for _, report := range reports {
+		for _, ip := range report.Addresses {
+			if _, ok := owners[ip]; !ok {
+				owners[ip] = report
+			}
+		}
+	}
+
+	type fedConverEdge struct {
+		conver *api.RESTFedConversation
+		ports  utils.Set
+		apps   utils.Set
+		last   uint32
+	}
+	endpoints := make(map[string]*api.RESTFedConverEndpoint)
+	edges := make(map[string]*fedConverEdge)
+
+	addEndpoint := func(report *share.CLUSFedClusterReport, service, domain string) string {
+		id := fedConverEndpointID(report.ID, service)
+		if _, ok := endpoints[id]; !ok {
+			endpoints[id] = &api.RESTFedConverEndpoint{
+				ID:          id,
+				ClusterID:   report.ID,
+				ClusterName: report.Name,
+				Service:     service,
+				Domain:      domain,
+			}
+		}
+		return id
+	}
+	addEdge := func(from, to string, flow *share.CLUSFedConverFlow, confirmed bool) {
+		key := fmt.Sprintf("%s->%s", from, to)
+		edge, ok := edges[key]
+		if !ok {
+			edge = &fedConverEdge{
+				conver: &api.RESTFedConversation{From: from, To: to},
+				ports:  utils.NewSet(),
+				apps:   utils.NewSet(),
+			}
+			edges[key] = edge
+		}
+		edge.ports.Add(utils.GetPortLink(flow.IPProto, flow.Port))
+		if flow.Application != "" {
+			edge.apps.Add(flow.Application)
+		}
+		edge.conver.Bytes += flow.Bytes
+		edge.conver.Sessions += flow.Sessions
+		edge.conver.Confirmed = edge.conver.Confirmed || confirmed
+		if flow.LastSeen > edge.last {
+			edge.last = flow.LastSeen
+		}
+	}
+
+	matched := utils.NewSet() // ingress flows that match egress flows
+	for _, src := range reports {
+		for _, egress := range src.Flows {
+			if egress.Ingress {
+				continue
+			}
+			dst, ok := owners[egress.IP]
+			if !ok || dst == src {
+				continue
+			}
+			from := addEndpoint(src, egress.Service, egress.Domain)
+			found := false
+			for _, ingress := range dst.Flows {
+				if !ingress.Ingress || ingress.IPProto != egress.IPProto ||
+					(ingress.Port != egress.Port && ingress.MappedPort != egress.Port) {
+					continue
+				}
+				if owner, ok := owners[ingress.IP]; !ok || owner != src {
+					continue
+				}
+				matched.Add(ingress)
+				addEdge(from, addEndpoint(dst, ingress.Service, ingress.Domain), egress, true)
+				found = true
+			}
+			if !found {
+				// the service that receives the traffic is not known
+				addEdge(from, addEndpoint(dst, "", ""), egress, false)
+			}
+		}
+	}
+	for _, dst := range reports {
+		for _, ingress := range dst.Flows {
+			if !ingress.Ingress || matched.Contains(ingress) {
+				continue
+			}
+			src, ok := owners[ingress.IP]
+			if !ok || src == dst {
+				continue
+			}
+			addEdge(addEndpoint(src, "", ""), addEndpoint(dst, ingress.Service, ingress.Domain), ingress, false)
+		}
+	}
+
+	data := &api.RESTFedConversationData{
+		Endpoints:     make([]*api.RESTFedConverEndpoint, 0, len(endpoints)),
+		Conversations: make([]*api.RESTFedConversation, 0, len(edges)),
+	}
+	for _, ep := range endpoints {
+		data.Endpoints = append(data.Endpoints, ep)
+	}
+	sort.Slice(data.Endpoints, func(i, j int) bool { return data.Endpoints[i].ID < data.Endpoints[j].ID })
+	for _, edge := range edges {
+		edge.conver.Ports = edge.ports.ToStringSlice()
+		edge.conver.Applications = edge.apps.ToStringSlice()
+		edge.conver.LastSeenAt = api.RESTTimeString(time.Unix(int64(edge.last), 0).UTC())
+		sort.Strings(edge.conver.Ports)
+		sort.Strings(edge.conver.Applications)
+		data.Conversations = append(data.Conversations, edge.conver)
+	}
+	sort.Slice(data.Conversations, func(i, j int) bool {
+		if data.Conversations[i].From != data.Conversations[j].From {
+			return data.Conversations[i].From < data.Conversations[j].From
+		}
+		return data.Conversations[i].To < data.Conversations[j].To
+	})
+
+	return data
+}
+
+func handlerGetFedConversation(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := isFedOpAllowed(api.FedRoleMaster, _fedReaderRequired, w, r)
+	if acc == nil || login == nil {
+		return
+	}
+
+	resp := matchFedConverFlows(getFedMemberReports(acc))
+	restRespSuccess(w, r, resp, acc, login, nil, "Get federation conversation map")
+}
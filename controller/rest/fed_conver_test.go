@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestFedConverFlowMatch(t *testing.T) {
+	reports := []*share.CLUSFedClusterReport{
+		&share.CLUSFedClusterReport{
+			ID:        "c1",
+			Name:      "cluster1",
+			Addresses: []string{"10.1.0.1", "10.1.0.2"},
+			Flows: []*share.CLUSFedConverFlow{
+				// to nodeport of cluster2
+				&share.CLUSFedConverFlow{Service: "nv.web.demo", Domain: "demo", IP: "10.2.0.1", Port: 30080, IPProto: 6, Bytes: 100, Sessions: 2, LastSeen: 1000},
+				// to unknown service of cluster2
+				&share.CLUSFedConverFlow{Service: "nv.web.demo", Domain: "demo", IP: "10.2.0.1", Port: 30443, IPProto: 6, Bytes: 10, Sessions: 1, LastSeen: 900},
+				// to internet
+				&share.CLUSFedConverFlow{Service: "nv.web.demo", Domain: "demo", IP: "8.8.8.8", Port: 53, IPProto: 17, Bytes: 10, Sessions: 1},
+			},
+		},
+		&share.CLUSFedClusterReport{
+			ID:        "c2",
+			Name:      "cluster2",
+			Addresses: []string{"10.2.0.1"},
+			Flows: []*share.CLUSFedConverFlow{
+				&share.CLUSFedConverFlow{Ingress: true, Service: "nv.api.demo", Domain: "demo", IP: "10.1.0.2", Port: 8080, MappedPort: 30080, IPProto: 6, Bytes: 100, Sessions: 2},
+				// from cluster1, no egress flow is reported by cluster1
+				&share.CLUSFedConverFlow{Ingress: true, Service: "nv.db.demo", Domain: "demo", IP: "10.1.0.1", Port: 5432, IPProto: 6, Bytes: 50, Sessions: 1},
+				// from outside of federation
+				&share.CLUSFedConverFlow{Ingress: true, Service: "nv.api.demo", Domain: "demo", IP: "1.2.3.4", Port: 8080, IPProto: 6, Bytes: 1, Sessions: 1},
+			},
+		},
+	}
+
+	data := matchFedConverFlows(reports)
+
+	var ids []string
+	for _, ep := range data.Endpoints {
+		ids = append(ids, ep.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"c1/", "c1/nv.web.demo", "c2/", "c2/nv.api.demo", "c2/nv.db.demo"}) {
+		t.Errorf("Unexpected endpoints: %v", ids)
+	}
+
+	if len(data.Conversations) != 3 {
+		t.Fatalf("Unexpected number of conversations: %d", len(data.Conversations))
+	}
+	c := data.Conversations[0]
+	if c.From != "c1/" || c.To != "c2/nv.db.demo" || c.Confirmed || c.Bytes != 50 {
+		t.Errorf("Unexpected conversation: %+v", c)
+	}
+	c = data.Conversations[1]
+	if c.From != "c1/nv.web.demo" || c.To != "c2/" || c.Confirmed || !reflect.DeepEqual(c.Ports, []string{"tcp/30443"}) {
+		t.Errorf("Unexpected conversation: %+v", c)
+	}
+	c = data.Conversations[2]
+	if c.From != "c1/nv.web.demo" || c.To != "c2/nv.api.demo" || !c.Confirmed || c.Bytes != 100 || c.Sessions != 2 {
+		t.Errorf("Unexpected conversation: %+v", c)
+	}
+}
+
+func TestFedConverFlowTrim(t *testing.T) {
+	flows := []*share.CLUSFedConverFlow{
+		&share.CLUSFedConverFlow{IP: "1.1.1.1", Bytes: 10},
+		&share.CLUSFedConverFlow{IP: "2.2.2.2", Bytes: 30},
+		&share.CLUSFedConverFlow{IP: "3.3.3.3", Bytes: 20},
+	}
+	flows = trimFedConverFlows(flows, 2)
+	if len(flows) != 2 || flows[0].IP != "2.2.2.2" || flows[1].IP != "3.3.3.3" {
+		t.Errorf("Flows with most traffic should be kept: %+v", flows)
+	}
+}
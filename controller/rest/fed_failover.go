@@ -0,0 +1,453 @@
+package rest
+
+// Failover of the federation to a standby joint cluster when the master cluster is lost.
+// The master cluster gives a fed config snapshot to the designated standby joint cluster in polling responses.
+// When the standby cluster takes over the federation, it re-keys the other joint clusters with the secret/keys in the snapshot.
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/cache"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/cluster"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+type failoverResult struct {
+	cluster *share.CLUSFedJointClusterInfo // nil if the joint cluster cannot be re-keyed
+	name    string
+}
+
+func isMasterUnreachable(lastHeartbeat, now time.Time, pollInterval uint32) bool {
+	if pollInterval == 0 {
+		pollInterval = 1
+	}
+	return now.Sub(lastHeartbeat) > time.Minute*time.Duration(pollInterval*masterUnreachablePolls)
+}
+
+// called by joint cluster after it polls master cluster successfully
+func updateStandbySnapshot(standbyID string, snapshot *share.CLUSFedStandbySnapshot) {
+	m := clusHelper.GetFedMembership()
+	if m == nil || m.FedRole != api.FedRoleJoint {
+		return
+	}
+	if standbyID != m.JointCluster.ID {
+		snapshot = nil
+	}
+	if m.StandbyID == standbyID && reflect.DeepEqual(m.Standby, snapshot) {
+		return
+	}
+
+	lock, err := lockClusKey(nil, share.CLUSLockFedKey)
+	if err != nil {
+		return
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	if m = clusHelper.GetFedMembership(); m != nil && m.FedRole == api.FedRoleJoint {
+		if m.StandbyID != standbyID {
+			log.WithFields(log.Fields{"standby": standbyID, "self": standbyID == m.JointCluster.ID}).Info("standby primary cluster changed")
+		}
+		m.StandbyID = standbyID
+		m.Standby = snapshot
+		if err := clusHelper.PutFedMembership(m); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Failed to update standby snapshot")
+		}
+	}
+}
+
+// called by master cluster for the polling request from the standby joint cluster
+func getStandbySnapshot(standbyID string, acc *access.AccessControl) *share.CLUSFedStandbySnapshot {
+	snapshot := &share.CLUSFedStandbySnapshot{
+		MasterName:    cacher.GetSystemConfigClusterName(acc),
+		PingInterval:  atomic.LoadUint32(&_fedPingInterval),
+		PollInterval:  atomic.LoadUint32(&_fedPollInterval),
+		JointClusters: make([]share.CLUSFedJointClusterInfo, 0),
+	}
+	for id := range cacher.GetFedJoinedClusterIdMap(acc) {
+		if id == standbyID {
+			continue
+		}
+		if c := cacher.GetFedJoinedCluster(id, acc); c.ID == id {
+			snapshot.JointClusters = append(snapshot.JointClusters, c)
+		}
+	}
+	sort.Slice(snapshot.JointClusters, func(i, j int) bool { return snapshot.JointClusters[i].ID < snapshot.JointClusters[j].ID })
+
+	return snapshot
+}
+
+func handlerConfigFedStandby(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	var err error
+	var lock cluster.LockInterface
+	if lock, err = lockClusKey(w, share.CLUSLockFedKey); err != nil {
+		return
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	acc, login := isFedOpAllowed(api.FedRoleMaster, _fedAdminRequired, w, r)
+	if acc == nil || login == nil {
+		return
+	}
+
+	var req api.RESTFedStandbyReq
+	body, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(body, &req); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	var msg string
+	if req.ID != "" {
+		joinedCluster := cacher.GetFedJoinedCluster(req.ID, acc)
+		if joinedCluster.ID != req.ID {
+			restRespErrorMessage(w, http.StatusNotFound, api.RESTErrObjectNotFound, "Managed cluster not found")
+			return
+		}
+		msg = fmt.Sprintf("Set cluster %s as standby primary cluster", joinedCluster.Name)
+	} else {
+		msg = "Unset standby primary cluster"
+	}
+
+	m := clusHelper.GetFedMembership()
+	if m == nil || m.FedRole != api.FedRoleMaster {
+		restRespError(w, http.StatusBadRequest, api.RESTErrOpNotAllowed)
+		return
+	}
+	m.StandbyID = req.ID
+	if err := clusHelper.PutFedMembership(m); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error()
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+
+	restRespSuccess(w, r, nil, acc, login, nil, msg)
+}
+
+// share.CLUSLockFedKey lock is owned by caller
+func failoverJointCluster(old share.CLUSFedJointClusterInfo, reqTo api.RESTFedFailoverReqInternal,
+	ch chan<- failoverResult, acc *access.AccessControl) {
+
+	result := failoverResult{name: old.Name}
+	defer func() { ch <- result }()
+
+	// the old key is for signing the token that the joint cluster can validate
+	if err := setJointKeysInCache(api.FedRoleMaster, &old); err != nil {
+		log.WithFields(log.Fields{"id": old.ID, "err": err}).Error("invalid joint keys")
+		return
+	}
+
+	var err error
+	var privKeyData, certData []byte
+	_, privKeyPath, certPath := kv.GetFedTlsKeyCertPath("", old.ID)
+	os.Remove(privKeyPath)
+	os.Remove(certPath)
+	if kv.GenTlsKeyCert(old.ID, privKeyPath, certPath, x509.ExtKeyUsageClientAuth) {
+		if privKeyData, err = ioutil.ReadFile(privKeyPath); err == nil {
+			certData, err = ioutil.ReadFile(certPath)
+		}
+	}
+	c := old
+	c.Secret, _ = utils.GetGuid()
+	if privKeyData == nil || certData == nil || c.Secret == "" {
+		log.WithFields(log.Fields{"id": old.ID, "err": err}).Error("failed to generate keys")
+		_setFedJointPrivateKey(old.ID, nil)
+		return
+	}
+	c.ClientKey = base64.StdEncoding.EncodeToString(privKeyData)
+	c.ClientCert = base64.StdEncoding.EncodeToString(certData)
+
+	reqTo.Token = jwtGenFedPingToken(api.FedRoleMaster, old.ID, old.Secret, nil)
+	reqTo.Secret = c.Secret
+	reqTo.ClientKey = c.ClientKey
+	reqTo.ClientCert = c.ClientCert
+	bodyTo, _ := json.Marshal(&reqTo)
+	_, statusCode, _, proxyUsed, err := sendReqToJointCluster(old.RestInfo, old.ID, "", http.MethodPost,
+		"v1/fed/failover_internal", jsonContentType, _tagFailover, "", bodyTo, false, _notForward, false, true, acc)
+	if err != nil || statusCode != http.StatusOK {
+		log.WithFields(log.Fields{"id": old.ID, "statusCode": statusCode, "err": err}).Error("failed to re-key")
+		os.Remove(privKeyPath)
+		os.Remove(certPath)
+		_setFedJointPrivateKey(old.ID, nil)
+		return
+	}
+	c.ProxyRequired = proxyUsed
+	result.cluster = &c
+}
+
+// called on the standby joint cluster for taking over the federation
+func handlerFedFailover(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	var err error
+	var lock cluster.LockInterface
+	if lock, err = lockClusKey(w, share.CLUSLockFedKey); err != nil {
+		return
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	// local users & rancher users who have admin permission can take over the federation
+	acc, login := isFedOpAllowed(api.FedRoleJoint, _localAdminRequired, w, r)
+	if acc == nil || login == nil {
+		return
+	}
+
+	var req api.RESTFedFailoverReq
+	body, _ := ioutil.ReadAll(r.Body)
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Request error")
+			restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+			return
+		}
+	}
+
+	m := clusHelper.GetFedMembership()
+	if m == nil || m.FedRole != api.FedRoleJoint || m.StandbyID == "" || m.StandbyID != m.JointCluster.ID || m.Standby == nil {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrOpNotAllowed, "This cluster is not the standby primary cluster")
+		return
+	}
+	if !req.Force {
+		if cached := cacher.GetFedJoinedClusterStatus(m.MasterCluster.ID, acc); cached.Status != _fedMasterUnreachable {
+			restRespErrorMessage(w, http.StatusPreconditionFailed, api.RESTErrOpNotAllowed, "Primary cluster is not unreachable")
+			return
+		}
+	}
+
+	var masterID string
+	var caCertData []byte
+	for ok := true; ok; ok = false {
+		if masterID, err = utils.GetGuid(); err == nil {
+			var caCertPath string
+			if caCertPath, err = kv.GetFedCaCertPath(masterID); err == nil {
+				if caCertData, err = ioutil.ReadFile(caCertPath); err == nil {
+					break
+				}
+			}
+		}
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFedOperationFailed, err.Error())
+		return
+	}
+
+	snapshot := m.Standby
+	oldMaster := m.MasterCluster
+	restInfo := m.LocalRestInfo
+	if restInfo.Server == "" {
+		restInfo = m.JointCluster.RestInfo
+	}
+	reqTo := api.RESTFedFailoverReqInternal{
+		StandbyID:    m.JointCluster.ID,
+		FedKvVersion: kv.GetFedKvVer(),
+		PollInterval: snapshot.PollInterval,
+		CACert:       base64.StdEncoding.EncodeToString(caCertData),
+		MasterCluster: &api.RESTFedMasterClusterInfo{
+			Name:     cacher.GetSystemConfigClusterName(acc),
+			ID:       masterID,
+			RestInfo: restInfo,
+		},
+	}
+
+	resp := api.RESTFedFailoverResp{
+		MasterCluster: *reqTo.MasterCluster,
+		Rekeyed:       make([]string, 0, len(snapshot.JointClusters)),
+		Failed:        make([]string, 0),
+	}
+	list := share.CLUSFedJoinedClusterList{IDs: make([]string, 0, len(snapshot.JointClusters))}
+	ch := make(chan failoverResult)
+	for _, c := range snapshot.JointClusters {
+		go failoverJointCluster(c, reqTo, ch, acc)
+	}
+	for j := 0; j < len(snapshot.JointClusters); j++ {
+		result := <-ch
+		if result.cluster == nil {
+			resp.Failed = append(resp.Failed, result.name)
+			continue
+		}
+		if err := clusHelper.PutFedJointCluster(result.cluster); err != nil {
+			log.WithFields(log.Fields{"id": result.cluster.ID, "error": err}).Error()
+			resp.Failed = append(resp.Failed, result.name)
+			continue
+		}
+		setJointKeysInCache(api.FedRoleMaster, result.cluster)
+		updateClusterState(result.cluster.ID, "", _fedClusterJoined, nil, acc)
+		list.IDs = append(list.IDs, result.cluster.ID)
+		resp.Rekeyed = append(resp.Rekeyed, result.name)
+	}
+	clusHelper.PutFedJointClusterList(&list)
+
+	if snapshot.PingInterval > 0 {
+		atomic.StoreUint32(&_fedPingInterval, snapshot.PingInterval)
+	}
+	if snapshot.PollInterval > 0 {
+		atomic.StoreUint32(&_fedPollInterval, snapshot.PollInterval)
+	}
+	secret, _ := utils.GetGuid()
+	mNew := share.CLUSFedMembership{
+		FedRole:       api.FedRoleMaster,
+		PingInterval:  snapshot.PingInterval,
+		PollInterval:  snapshot.PollInterval,
+		LocalRestInfo: restInfo,
+		MasterCluster: share.CLUSFedMasterClusterInfo{
+			ID:       masterID,
+			Secret:   secret,
+			User:     login.fullname,
+			RestInfo: restInfo,
+		},
+		UseProxy: m.UseProxy,
+		FedProxy: m.FedProxy,
+	}
+	if err := clusHelper.PutFedMembership(&mNew); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to take over federation")
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFedOperationFailed, err.Error())
+		return
+	}
+
+	// the fed rules pulled from the old master cluster are kept with their revisions
+	clusHelper.ConfigFedRole(common.DefaultAdminUser, api.UserRoleFedAdmin, acc)
+	if login.fullname != common.DefaultAdminUser {
+		clusHelper.ConfigFedRole(login.fullname, api.UserRoleFedAdmin, acc)
+	}
+	kv.CreateDefaultFedGroups()
+
+	if oldCaCertPath, _, _ := kv.GetFedTlsKeyCertPath(oldMaster.ID, ""); oldCaCertPath != "" {
+		os.Remove(oldCaCertPath)
+	}
+	_setFedJointPrivateKey(m.JointCluster.ID, nil)
+
+	msg := fmt.Sprintf("Take over federation from primary cluster %s", snapshot.MasterName)
+	if len(resp.Failed) > 0 {
+		msg = fmt.Sprintf("%s. Unreachable managed clusters need to join federation again: %v", msg, resp.Failed)
+	}
+	cacheFedEvent(share.CLUSEvFedPromote, msg, login.fullname, login.remote, login.id, login.domainRoles)
+
+	accFedAdmin := access.NewFedAdminAccessControl()
+	user, _, _ := clusHelper.GetUserRev(common.DefaultAdminUser, accFedAdmin)
+	if user != nil {
+		kickLoginSessions(user)
+	}
+	if login.fullname != common.DefaultAdminUser || login.server != "" {
+		if user, _, _ := clusHelper.GetUserRev(login.fullname, accFedAdmin); user != nil {
+			kickLoginSessions(user)
+		}
+	}
+
+	cache.ConfigCspUsages(false, false, api.FedRoleMaster, masterID)
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Take over federation")
+}
+
+// called from the standby cluster that takes over the federation to joint cluster
+func handlerFedFailoverInternal(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	var err error
+	var lock cluster.LockInterface
+	if lock, err = lockClusKey(w, share.CLUSLockFedKey); err != nil {
+		return
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	accReadAll := access.NewReaderAccessControl()
+	if !isNoAuthFedOpAllowed(api.FedRoleJoint, w, r, accReadAll) {
+		return
+	}
+
+	var req api.RESTFedFailoverReqInternal
+	body, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(body, &req); err != nil || req.MasterCluster == nil || req.MasterCluster.ID == "" ||
+		req.Secret == "" || req.ClientKey == "" || req.ClientCert == "" {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	m := clusHelper.GetFedMembership()
+	if m == nil || m.FedRole != api.FedRoleJoint {
+		restRespError(w, http.StatusBadRequest, api.RESTErrOpNotAllowed)
+		return
+	}
+	// the token is signed with the key that only the master cluster & standby cluster have
+	if _, err := jwtValidateToken(req.Token, m.JointCluster.Secret, nil); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrFedOperationFailed, err.Error())
+		return
+	}
+	if m.StandbyID == "" || req.StandbyID != m.StandbyID {
+		log.WithFields(log.Fields{"standby": m.StandbyID, "req": req.StandbyID}).Error("Not from standby primary cluster")
+		restRespError(w, http.StatusBadRequest, api.RESTErrOpNotAllowed)
+		return
+	}
+	if met, result, err := kv.CheckFedKvVersion("joint", req.FedKvVersion); !met {
+		log.WithFields(log.Fields{"err": err, "result": result}).Error()
+		restRespError(w, http.StatusUpgradeRequired, result)
+		return
+	}
+
+	var caCert []byte
+	caCertPath, _, _ := kv.GetFedTlsKeyCertPath(req.MasterCluster.ID, "")
+	if caCert, err = base64.StdEncoding.DecodeString(req.CACert); err == nil {
+		err = ioutil.WriteFile(caCertPath, caCert, 0600)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to write ca cert")
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFedOperationFailed, err.Error())
+		return
+	}
+
+	oldMaster := m.MasterCluster
+	if req.PollInterval > 0 {
+		m.PollInterval = req.PollInterval
+	}
+	m.MasterCluster = share.CLUSFedMasterClusterInfo{
+		Name:     req.MasterCluster.Name,
+		ID:       req.MasterCluster.ID,
+		CACert:   req.CACert,
+		RestInfo: req.MasterCluster.RestInfo,
+	}
+	m.JointCluster.Secret = req.Secret
+	m.JointCluster.ClientKey = req.ClientKey
+	m.JointCluster.ClientCert = req.ClientCert
+	m.StandbyID = ""
+	m.Standby = nil
+	if err := clusHelper.PutFedMembership(m); err != nil {
+		os.Remove(caCertPath)
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFedOperationFailed, err.Error())
+		return
+	}
+	if oldCaCertPath, _, _ := kv.GetFedTlsKeyCertPath(oldMaster.ID, ""); oldCaCertPath != "" {
+		os.Remove(oldCaCertPath)
+	}
+	// sessions from the old master cluster are not valid anymore
+	delAllFedSessionTokens()
+	setJointKeysInCache(api.FedRoleJoint, &m.JointCluster)
+	_lastMasterHeartbeatTime = time.Now()
+
+	updateClusterState(req.MasterCluster.ID, req.MasterCluster.ID, _fedClusterConnected, nil, accReadAll)
+	msg := fmt.Sprintf("Primary cluster is changed from %s to %s by failover", oldMaster.Name, req.MasterCluster.Name)
+	cacheFedEvent(share.CLUSEvFedJoin, msg, "", "", "", nil)
+
+	restRespSuccess(w, r, nil, nil, nil, nil, "Switch primary cluster by failover")
+}
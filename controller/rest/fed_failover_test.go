@@ -0,0 +1,23 @@
+package rest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMasterUnreachable(t *testing.T) {
+	now := time.Now()
+
+	if isMasterUnreachable(now.Add(-time.Minute*2), now, 1) {
+		t.Errorf("Master should be reachable within %d polling intervals", masterUnreachablePolls)
+	}
+	if !isMasterUnreachable(now.Add(-time.Minute*4), now, 1) {
+		t.Errorf("Master should be unreachable after %d polling intervals", masterUnreachablePolls)
+	}
+	if isMasterUnreachable(now.Add(-time.Minute*10), now, 5) {
+		t.Errorf("Polling interval is not respected")
+	}
+	if !isMasterUnreachable(now.Add(-time.Minute*4), now, 0) {
+		t.Errorf("Zero polling interval should be treated as 1 minute")
+	}
+}
@@ -0,0 +1,181 @@
+package rest
+
+// Egress proxy, client cert and pinned remote certs for the federation requests sent by local cluster.
+// When it's configured, federation requests never fall back to direct connection or system proxy.
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+var _fedProxyClient *tNvHttpClient
+var _fedProxyCfg *share.CLUSFedProxyConfig // the config that _fedProxyClient is created from. protected by _httpClientMutex
+
+func normalizeCertFingerprint(fp string) string {
+	return strings.ToLower(strings.Replace(strings.TrimSpace(fp), ":", "", -1))
+}
+
+func isValidCertFingerprint(fp string) bool {
+	fp = normalizeCertFingerprint(fp)
+	if len(fp) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(fp)
+	return err == nil
+}
+
+// The leaf cert of the remote server(and of the proxy if its url is https) must match one of the pinned fingerprints
+func verifyPinnedCert(pins []string) func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	pinned := make(map[string]bool, len(pins))
+	for _, pin := range pins {
+		pinned[normalizeCertFingerprint(pin)] = true
+	}
+	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("No remote certificate")
+		}
+		sum := sha256.Sum256(rawCerts[0])
+		if fp := hex.EncodeToString(sum[:]); !pinned[fp] {
+			return fmt.Errorf("Remote certificate %s is not pinned", fp)
+		}
+		return nil
+	}
+}
+
+func createFedProxyHttpClient(cfg *share.CLUSFedProxyConfig, timeout time.Duration) (*tNvHttpClient, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: true,
+	}
+	if cfg.ClientCert != "" {
+		cert, err := tls.X509KeyPair([]byte(cfg.ClientCert), []byte(cfg.ClientKey))
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if len(cfg.PinnedCerts) > 0 {
+		tlsConfig.VerifyPeerCertificate = verifyPinnedCert(cfg.PinnedCerts)
+	}
+
+	// refer to createHttpClient()
+	transport := &http.Transport{
+		Proxy:              getProxyURL,
+		TLSClientConfig:    tlsConfig,
+		MaxIdleConns:       100,
+		IdleConnTimeout:    90 * time.Second,
+		DisableCompression: true,
+	}
+	var basicAuth string
+	if cfg.URL != "" && cfg.Username != "" {
+		auth := fmt.Sprintf("%s:%s", cfg.Username, cfg.Password)
+		basicAuth = "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
+		transport.ProxyConnectHeader = http.Header{}
+		transport.ProxyConnectHeader.Add("Proxy-Authorization", basicAuth)
+	}
+	httpClient := &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}
+	if jar, err := cookiejar.New(nil); err == nil {
+		httpClient.Jar = jar
+	}
+
+	return &tNvHttpClient{
+		httpClient:  httpClient,
+		proxyUrlStr: cfg.URL,
+		basicAuth:   basicAuth,
+	}, nil
+}
+
+// It returns (nil, nil) when there is no fed proxy config.
+// The http client is re-created when the config is changed
+func getFedProxyHttpClient(acc *access.AccessControl) (*tNvHttpClient, error) {
+	cfg := cacher.GetFedProxyConfig(acc)
+
+	_httpClientMutex.Lock()
+	defer _httpClientMutex.Unlock()
+
+	if cfg == nil {
+		if _fedProxyClient != nil {
+			_fedProxyClient.httpClient.CloseIdleConnections()
+			_fedProxyClient = nil
+			_fedProxyCfg = nil
+		}
+		return nil, nil
+	}
+
+	if _fedProxyClient == nil || !reflect.DeepEqual(cfg, _fedProxyCfg) {
+		nvHttpClient, err := createFedProxyHttpClient(cfg, clusterAuthTimeout)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Failed to create http client for fed proxy")
+			return nil, err
+		}
+		if _fedProxyClient != nil {
+			_fedProxyClient.httpClient.CloseIdleConnections()
+		}
+		_fedProxyClient = nvHttpClient
+		_fedProxyCfg = cfg
+	}
+
+	return _fedProxyClient, nil
+}
+
+// Masked password/key in the request keep the values in old config.
+// It returns nil config when the request removes fed proxy config
+func parseFedProxyConfig(req *api.RESTFedProxyConfig, old *share.CLUSFedProxyConfig) (*share.CLUSFedProxyConfig, error) {
+	cfg := &share.CLUSFedProxyConfig{
+		URL:         strings.TrimSpace(req.URL),
+		Username:    req.Username,
+		Password:    req.Password,
+		ClientCert:  strings.TrimSpace(req.ClientCert),
+		ClientKey:   strings.TrimSpace(req.ClientKey),
+		PinnedCerts: make([]string, 0, len(req.PinnedCerts)),
+	}
+	if old != nil {
+		if cfg.Password == api.RESTMaskedValue {
+			cfg.Password = old.Password
+		}
+		if cfg.ClientKey == api.RESTMaskedValue {
+			cfg.ClientKey = old.ClientKey
+		}
+	}
+	if cfg.URL == "" && cfg.ClientCert == "" && len(req.PinnedCerts) == 0 {
+		return nil, nil
+	}
+
+	if cfg.URL != "" {
+		if u, err := url.ParseRequestURI(cfg.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("Invalid proxy url %s", cfg.URL)
+		}
+	}
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if _, err := tls.X509KeyPair([]byte(cfg.ClientCert), []byte(cfg.ClientKey)); err != nil {
+			return nil, fmt.Errorf("Invalid client certificate or key: %s", err.Error())
+		}
+	}
+	for _, pin := range req.PinnedCerts {
+		if !isValidCertFingerprint(pin) {
+			return nil, fmt.Errorf("Invalid certificate fingerprint %s", pin)
+		}
+		cfg.PinnedCerts = append(cfg.PinnedCerts, normalizeCertFingerprint(pin))
+	}
+
+	return cfg, nil
+}
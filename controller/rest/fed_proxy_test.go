@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestFedProxyCertPinning(t *testing.T) {
+	cert := []byte("remote cert")
+	sum := sha256.Sum256(cert)
+	fp := hex.EncodeToString(sum[:])
+
+	// fingerprint in "AB:CD:..." format
+	var pin string
+	for i := 0; i < len(fp); i += 2 {
+		if i > 0 {
+			pin += ":"
+		}
+		pin += strings.ToUpper(fp[i : i+2])
+	}
+
+	verify := verifyPinnedCert([]string{strings.Repeat("0", 64), pin})
+	if err := verify([][]byte{cert}, nil); err != nil {
+		t.Errorf("Pinned certificate should be accepted: %s", err)
+	}
+	if err := verify([][]byte{[]byte("other cert")}, nil); err == nil {
+		t.Errorf("Certificate that is not pinned should be rejected")
+	}
+	if err := verify(nil, nil); err == nil {
+		t.Errorf("Missing certificate should be rejected")
+	}
+}
+
+func TestParseFedProxyConfig(t *testing.T) {
+	pin := strings.Repeat("ab", 32)
+	old := &share.CLUSFedProxyConfig{URL: "http://10.1.1.1:3128", Username: "user", Password: "secret"}
+
+	req := &api.RESTFedProxyConfig{
+		URL:         " https://proxy.local:3128 ",
+		Username:    "user",
+		Password:    api.RESTMaskedValue,
+		PinnedCerts: []string{strings.ToUpper(pin)},
+	}
+	cfg, err := parseFedProxyConfig(req, old)
+	if err != nil || cfg == nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+	if cfg.URL != "https://proxy.local:3128" || cfg.Password != "secret" || len(cfg.PinnedCerts) != 1 || cfg.PinnedCerts[0] != pin {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+
+	if cfg, err = parseFedProxyConfig(&api.RESTFedProxyConfig{}, old); err != nil || cfg != nil {
+		t.Errorf("Empty config should remove fed proxy: %+v, %v", cfg, err)
+	}
+
+	invalids := []*api.RESTFedProxyConfig{
+		&api.RESTFedProxyConfig{URL: "socks5://proxy.local:1080"},
+		&api.RESTFedProxyConfig{URL: "proxy.local:3128"},
+		&api.RESTFedProxyConfig{PinnedCerts: []string{"abcd"}},
+		&api.RESTFedProxyConfig{PinnedCerts: []string{strings.Repeat("xy", 32)}},
+		&api.RESTFedProxyConfig{ClientCert: "invalid cert", ClientKey: "invalid key"},
+	}
+	for _, req := range invalids {
+		if _, err := parseFedProxyConfig(req, nil); err == nil {
+			t.Errorf("Config should be invalid: %+v", req)
+		}
+	}
+}
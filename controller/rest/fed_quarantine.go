@@ -0,0 +1,218 @@
+package rest
+
+// Federal quarantine list. Workloads running the listed images are quarantined in all clusters of the federation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func isValidImageDigest(digest string) bool {
+	digest = utils.NormalizeImageDigest(digest)
+	if digest == "" {
+		return false
+	}
+	for _, c := range digest {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Images that are already in the list are ignored. Returns true if any image is added
+func addFedQuarantineImages(images []*share.CLUSFedQuarantineImage) bool {
+	lock, err := clusHelper.AcquireLock(share.CLUSLockPolicyKey, clusterLockWait)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to acquire cluster lock")
+		return false
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	existing, rev := clusHelper.GetResponseQuarantineImages(share.FedPolicyName)
+	digests := utils.NewSet()
+	for _, img := range existing {
+		digests.Add(utils.NormalizeImageDigest(img.Digest))
+	}
+
+	added := false
+	for _, img := range images {
+		if !isValidImageDigest(img.Digest) {
+			log.WithFields(log.Fields{"digest": img.Digest}).Error("Invalid image digest")
+			continue
+		}
+		if digest := utils.NormalizeImageDigest(img.Digest); !digests.Contains(digest) {
+			digests.Add(digest)
+			existing = append(existing, img)
+			added = true
+		}
+	}
+	if added {
+		if err := clusHelper.PutResponseQuarantineImagesRev(share.FedPolicyName, existing, rev); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Failed to write fed quarantine images")
+			return false
+		}
+	}
+
+	return added
+}
+
+// Called by the lead controller of master cluster periodically for the images that triggered fed-quarantine action locally
+func applyPendingFedQuarantineImages() {
+	if cacher.GetFedMembershipRoleNoAuth() != api.FedRoleMaster {
+		return
+	}
+
+	images := cacher.GetPendingFedQuarantineImages()
+	if len(images) == 0 {
+		return
+	}
+
+	acc := access.NewReaderAccessControl()
+	name := cacher.GetSystemConfigClusterName(acc)
+	for _, img := range images {
+		img.Cluster = name
+	}
+	if addFedQuarantineImages(images) {
+		updateFedRulesRevision([]string{share.FedResponseRulesType}, acc, nil)
+	}
+}
+
+func handlerGetFedQuarantineImages(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := isFedOpAllowed(api.FedRoleMaster, _fedReaderRequired, w, r)
+	if acc == nil || login == nil {
+		return
+	}
+
+	images := cacher.GetFedQuarantineImagesCache()
+	resp := api.RESTFedQuarantineImagesData{Images: make([]*api.RESTFedQuarantineImage, 0, len(images))}
+	for _, img := range images {
+		resp.Images = append(resp.Images, &api.RESTFedQuarantineImage{
+			Digest:      img.Digest,
+			Name:        img.Name,
+			Comment:     img.Comment,
+			User:        img.User,
+			Cluster:     img.Cluster,
+			CreatedAtTS: img.CreatedAt.Unix(),
+			CreatedAt:   api.RESTTimeString(img.CreatedAt),
+		})
+	}
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get federal quarantine images")
+}
+
+func handlerAddFedQuarantineImage(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := isFedOpAllowed(api.FedRoleMaster, _fedAdminRequired, w, r)
+	if acc == nil || login == nil {
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+
+	var rconf api.RESTFedQuarantineImageConfigData
+	err := json.Unmarshal(body, &rconf)
+	if err != nil || rconf.Config == nil {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	digest := strings.TrimSpace(rconf.Config.Digest)
+	if !isValidImageDigest(digest) {
+		e := "Invalid image digest"
+		log.WithFields(log.Fields{"digest": digest}).Error(e)
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+		return
+	}
+
+	img := &share.CLUSFedQuarantineImage{
+		Digest:    digest,
+		User:      login.fullname,
+		CreatedAt: time.Now().UTC(),
+	}
+	if rconf.Config.Name != nil {
+		img.Name = *rconf.Config.Name
+	}
+	if rconf.Config.Comment != nil {
+		img.Comment = *rconf.Config.Comment
+	}
+
+	if existing, _ := clusHelper.GetResponseQuarantineImages(share.FedPolicyName); len(existing) > 0 {
+		for _, quar := range existing {
+			if utils.NormalizeImageDigest(quar.Digest) == utils.NormalizeImageDigest(digest) {
+				e := fmt.Sprintf("Image %s is already in the list", digest)
+				log.Error(e)
+				restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrDuplicateName, e)
+				return
+			}
+		}
+	}
+
+	if !addFedQuarantineImages([]*share.CLUSFedQuarantineImage{img}) {
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+	updateFedRulesRevision([]string{share.FedResponseRulesType}, acc, login)
+
+	restRespSuccess(w, r, nil, acc, login, &rconf, fmt.Sprintf("Add image %s to federal quarantine list", digest))
+}
+
+// Workloads that have been quarantined are not released
+func handlerDeleteFedQuarantineImage(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := isFedOpAllowed(api.FedRoleMaster, _fedAdminRequired, w, r)
+	if acc == nil || login == nil {
+		return
+	}
+
+	digest := utils.NormalizeImageDigest(ps.ByName("digest"))
+
+	lock, err := clusHelper.AcquireLock(share.CLUSLockPolicyKey, clusterLockWait)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to acquire cluster lock")
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFailLockCluster, err.Error())
+		return
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	existing, rev := clusHelper.GetResponseQuarantineImages(share.FedPolicyName)
+	images := make([]*share.CLUSFedQuarantineImage, 0, len(existing))
+	for _, img := range existing {
+		if utils.NormalizeImageDigest(img.Digest) != digest {
+			images = append(images, img)
+		}
+	}
+	if len(images) == len(existing) {
+		restRespError(w, http.StatusNotFound, api.RESTErrObjectNotFound)
+		return
+	}
+
+	if err := clusHelper.PutResponseQuarantineImagesRev(share.FedPolicyName, images, rev); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error()
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+	updateFedRulesRevision([]string{share.FedResponseRulesType}, acc, login)
+
+	restRespSuccess(w, r, nil, acc, login, nil, fmt.Sprintf("Delete image %s from federal quarantine list", digest))
+}
@@ -0,0 +1,62 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestImageDigestValidation(t *testing.T) {
+	valids := []string{
+		"sha256:4cda95efb0e4e4ad6ec4ec7a66e3ff28e25b6fa7b7b1c37f4c1dfcdb2c8a5d0a",
+		"4cda95efb0e4e4ad6ec4ec7a66e3ff28e25b6fa7b7b1c37f4c1dfcdb2c8a5d0a",
+		"SHA256:4CDA95EFB0E4",
+	}
+	for _, d := range valids {
+		if !isValidImageDigest(d) {
+			t.Errorf("Image digest should be valid: %s", d)
+		}
+	}
+
+	invalids := []string{"", "sha256:", "nginx:latest", "sha256:4cda95ef/b0e4"}
+	for _, d := range invalids {
+		if isValidImageDigest(d) {
+			t.Errorf("Image digest should be invalid: %s", d)
+		}
+	}
+}
+
+func TestAddFedQuarantineImages(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+
+	images := []*share.CLUSFedQuarantineImage{
+		&share.CLUSFedQuarantineImage{Digest: "sha256:4cda95efb0e4"},
+		&share.CLUSFedQuarantineImage{Digest: "invalid/digest"},
+	}
+	if !addFedQuarantineImages(images) {
+		t.Errorf("Failed to add image")
+	}
+
+	// Same image with different digest format is not added again
+	images = []*share.CLUSFedQuarantineImage{&share.CLUSFedQuarantineImage{Digest: "4CDA95EFB0E4"}}
+	if addFedQuarantineImages(images) {
+		t.Errorf("Duplicate image should not be added")
+	}
+
+	images = []*share.CLUSFedQuarantineImage{&share.CLUSFedQuarantineImage{Digest: "sha256:8d9a7c1d4f23"}}
+	if !addFedQuarantineImages(images) {
+		t.Errorf("Failed to add image")
+	}
+
+	if list, _ := clusHelper.GetResponseQuarantineImages(share.FedPolicyName); len(list) != 2 ||
+		list[0].Digest != "sha256:4cda95efb0e4" || list[1].Digest != "sha256:8d9a7c1d4f23" {
+		t.Errorf("Unexpected fed quarantine images: %+v", list)
+	}
+
+	postTest()
+}
@@ -0,0 +1,494 @@
+package rest
+
+// Summarized vulnerability & compliance results of all clusters in federation, collected by master cluster
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+const fedReportInterval = time.Hour
+const fedReportTrendMax = 24 * 7 // one week of hourly collections
+
+var _fedReportOngoing uint32
+var _fedReportCollectedAt time.Time // only accessed by whoever owns _fedReportOngoing
+
+func addFedComplianceFailures(all map[string]*compAsset, rpt *api.RESTBenchReport, id string, isNode bool) {
+	if rpt == nil {
+		return
+	}
+	for _, item := range rpt.Items {
+		if item.Level != "PASS" && item.Level != "NOTE" {
+			ca := addCompAsset(all, item)
+			if isNode {
+				ca.nodes.Add(id)
+			} else {
+				ca.wls.Add(id)
+			}
+		}
+	}
+}
+
+// build the summarized scan/benchmark results of the local cluster
+func buildFedClusterReport(id, name string) *share.CLUSFedClusterReport {
+	acc := access.NewReaderAccessControl()
+	report := &share.CLUSFedClusterReport{
+		ID:          id,
+		Name:        name,
+		CollectedAt: time.Now().UTC(),
+	}
+
+	// remove duplicate results for the same image
+	images := make(map[string]*share.CLUSFedImageVulSummary)
+	addImage := func(imageID, imageName, digest string, high, med int) {
+		img, ok := images[imageID]
+		if !ok {
+			img = &share.CLUSFedImageVulSummary{ImageID: imageID, Names: make([]string, 0, 1)}
+			images[imageID] = img
+		}
+		if img.Digest == "" {
+			img.Digest = digest
+		}
+		if imageName != "" {
+			found := false
+			for _, n := range img.Names {
+				if n == imageName {
+					found = true
+					break
+				}
+			}
+			if !found {
+				img.Names = append(img.Names, imageName)
+			}
+		}
+		if high > img.HighVuls {
+			img.HighVuls = high
+		}
+		if med > img.MedVuls {
+			img.MedVuls = med
+		}
+	}
+
+	for _, wl := range cacher.GetAllWorkloadsBrief("", acc) {
+		if wl.ScanSummary == nil || wl.ScanSummary.Status != api.ScanStatusFinished || wl.ImageID == "" {
+			continue
+		}
+		addImage(wl.ImageID, wl.Image, "", wl.ScanSummary.HighVuls, wl.ScanSummary.MedVuls)
+	}
+
+	vpf := cacher.GetVulnerabilityProfileInterface(share.DefaultVulnerabilityProfileName)
+	for _, reg := range scanner.GetAllRegistrySummary(share.ScopeLocal, acc) {
+		for _, img := range scanner.GetRegistryImageSummary(reg.Name, vpf, acc) {
+			if img.Status != api.ScanStatusFinished || img.ImageID == "" {
+				continue
+			}
+			addImage(img.ImageID, img.Repository+":"+img.Tag, img.Digest, img.HighVuls, img.MedVuls)
+		}
+	}
+
+	report.Images = make([]*share.CLUSFedImageVulSummary, 0, len(images))
+	for _, img := range images {
+		sort.Strings(img.Names)
+		report.Images = append(report.Images, img)
+	}
+	sort.Slice(report.Images, func(i, j int) bool { return report.Images[i].ImageID < report.Images[j].ImageID })
+
+	cpf := &complianceProfileFilter{filter: make(map[string][]string)}
+	if cp, filter, err := cacher.GetComplianceProfile(share.DefaultComplianceProfileName, acc); err != nil {
+		log.WithFields(log.Fields{"profile": share.DefaultComplianceProfileName}).Error("Compliance profile not found")
+	} else {
+		cpf = &complianceProfileFilter{disableSystem: cp.DisableSystem, filter: filter}
+	}
+
+	all := make(map[string]*compAsset)
+	for _, pod := range cacher.GetAllWorkloadsRisk(acc) {
+		// Skip pod in kubernetes; if no child, use the parent (native docker)
+		if len(pod.Children) == 0 {
+			pod.Children = append(pod.Children, pod)
+		}
+		for _, wl := range pod.Children {
+			cpf.object = wl
+			addFedComplianceFailures(all, decodeCISReport(share.BenchCustomContainer, wl.CustomBenchValue, cpf), wl.ID, false)
+			addFedComplianceFailures(all, decodeCISReport(share.BenchContainer, wl.DockerBenchValue, cpf), wl.ID, false)
+			addFedComplianceFailures(all, decodeCISReport(share.BenchContainerSecret, wl.SecretBenchValue, cpf), wl.ID, false)
+			addFedComplianceFailures(all, decodeCISReport(share.BenchContainerSetID, wl.SetidBenchValue, cpf), wl.ID, false)
+		}
+	}
+	for _, n := range cacher.GetAllHostsRisk(acc) {
+		cpf.object = n
+		addFedComplianceFailures(all, decodeCISReport(share.BenchCustomHost, n.CustomBenchValue, cpf), n.ID, true)
+		addFedComplianceFailures(all, decodeCISReport(share.BenchDockerHost, n.DockerBenchValue, cpf), n.ID, true)
+		addFedComplianceFailures(all, decodeCISReport(share.BenchKubeMaster, n.MasterBenchValue, cpf), n.ID, true)
+		addFedComplianceFailures(all, decodeCISReport(share.BenchKubeWorker, n.WorkerBenchValue, cpf), n.ID, true)
+	}
+
+	report.Compliances = make([]*share.CLUSFedComplianceSummary, 0, len(all))
+	for _, ca := range all {
+		report.Compliances = append(report.Compliances, &share.CLUSFedComplianceSummary{
+			Name:        ca.asset.Name,
+			Category:    ca.asset.Category,
+			Level:       ca.asset.Level,
+			Description: ca.asset.Description,
+			Workloads:   ca.wls.Cardinality(),
+			Nodes:       ca.nodes.Cardinality(),
+		})
+	}
+	sort.Slice(report.Compliances, func(i, j int) bool { return report.Compliances[i].Name < report.Compliances[j].Name })
+
+	flows, addrs := cacher.GetFedConverFlows()
+	report.Flows = trimFedConverFlows(flows, fedConverFlowMax)
+	report.Addresses = addrs
+
+	return report
+}
+
+// called from master cluster to joint cluster
+func handlerFedReportInternal(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	defer r.Body.Close()
+
+	if fedRole := cacher.GetFedMembershipRoleNoAuth(); fedRole == api.FedRoleJoint {
+		var req api.RESTFedPingReq
+		var resp api.RESTFedReportInternalResp
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &req); err == nil {
+			accReadAll := access.NewReaderAccessControl()
+			if jointCluster := cacher.GetFedLocalJointCluster(accReadAll); jointCluster.ID != "" {
+				if _, err := jwtValidateToken(req.Token, jointCluster.Secret, nil); err == nil {
+					if met, result, _ := kv.CheckFedKvVersion("joint", req.FedKvVersion); !met {
+						resp.Result = result
+					} else {
+						resp.Report = buildFedClusterReport(jointCluster.ID, cacher.GetSystemConfigClusterName(accReadAll))
+					}
+					restRespSuccess(w, r, &resp, nil, nil, nil, "")
+					return
+				} else {
+					log.WithFields(log.Fields{"err": err}).Debug("validate")
+				}
+			} else {
+				log.Debug("empty cluster id")
+			}
+		} else {
+			log.WithFields(log.Fields{"err": err}).Debug("Unmarshal error")
+		}
+	} else {
+		log.WithFields(log.Fields{"fedRole": fedRole}).Debug("unexpected fedRole")
+	}
+	restRespError(w, http.StatusGone, api.RESTErrInvalidRequest)
+}
+
+func getJointClusterReport(jointCluster share.CLUSFedJointClusterInfo, ch chan<- *share.CLUSFedClusterReport, acc *access.AccessControl) {
+	var report *share.CLUSFedClusterReport
+
+	reqTo := api.RESTFedPingReq{
+		Token:        jwtGenFedPingToken(api.FedRoleMaster, jointCluster.ID, jointCluster.Secret, nil),
+		FedKvVersion: kv.GetFedKvVer(),
+	}
+	bodyTo, _ := json.Marshal(&reqTo)
+	_, statusCode, data, proxyUsed, err := sendReqToJointCluster(jointCluster.RestInfo, jointCluster.ID, "", http.MethodPost,
+		"v1/fed/report_internal", jsonContentType, _tagFedReport, "", bodyTo, false, _notForward, false, true, acc)
+	if err == nil {
+		if statusCode == http.StatusOK {
+			var resp api.RESTFedReportInternalResp
+			if err = json.Unmarshal(data, &resp); err == nil && resp.Report != nil {
+				report = resp.Report
+				// do not trust the identity reported by joint cluster
+				report.ID = jointCluster.ID
+				report.Name = jointCluster.Name
+			} else {
+				log.WithFields(log.Fields{"id": jointCluster.ID, "result": resp.Result, "err": err}).Error("no report")
+			}
+		} else {
+			log.WithFields(log.Fields{"statusCode": statusCode, "id": jointCluster.ID, "proxyUsed": proxyUsed}).Error("unexpected")
+		}
+	}
+	ch <- report
+}
+
+// Called by the lead controller of master cluster periodically
+func collectFedReports() {
+	if !atomic.CompareAndSwapUint32(&_fedReportOngoing, 0, 1) {
+		return
+	}
+	defer atomic.StoreUint32(&_fedReportOngoing, 0)
+
+	if cacher.GetFedMembershipRoleNoAuth() != api.FedRoleMaster || time.Since(_fedReportCollectedAt) < fedReportInterval {
+		return
+	}
+	_fedReportCollectedAt = time.Now()
+
+	acc := access.NewReaderAccessControl()
+	masterCluster := cacher.GetFedMasterCluster(acc)
+	if masterCluster.ID == "" {
+		return
+	}
+	if err := clusHelper.PutFedClusterReport(buildFedClusterReport(masterCluster.ID, masterCluster.Name)); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to save report")
+	}
+
+	ch := make(chan *share.CLUSFedClusterReport)
+	ids := cacher.GetFedJoinedClusterIdMap(acc)
+	count := 0
+	for id, disabled := range ids {
+		if !disabled {
+			jointCluster := cacher.GetFedJoinedCluster(id, acc)
+			if jointCluster.ID == id {
+				count++
+				go getJointClusterReport(jointCluster, ch, acc)
+			}
+		}
+	}
+	for j := 0; j < count; j++ {
+		if report := <-ch; report != nil {
+			if err := clusHelper.PutFedClusterReport(report); err != nil {
+				log.WithFields(log.Fields{"id": report.ID, "error": err}).Error("Failed to save report")
+			}
+		}
+	}
+
+	// reports of the clusters that are not in federation anymore are removed
+	reports := make([]*share.CLUSFedClusterReport, 0, len(ids)+1)
+	for id, report := range clusHelper.GetAllFedClusterReports() {
+		if _, ok := ids[id]; ok || id == masterCluster.ID {
+			reports = append(reports, report)
+		} else {
+			clusHelper.DeleteFedClusterReport(id)
+		}
+	}
+
+	trend := clusHelper.GetFedReportTrend()
+	trend.Points = appendFedReportTrend(trend.Points, fedReportTrendPoint(reports, time.Now().UTC()), fedReportTrendMax)
+	if err := clusHelper.PutFedReportTrend(trend); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to save report trend")
+	}
+}
+
+func appendFedReportTrend(points []*share.CLUSFedReportTrendPoint, point *share.CLUSFedReportTrendPoint, max int) []*share.CLUSFedReportTrendPoint {
+	points = append(points, point)
+	if len(points) > max {
+		points = points[len(points)-max:]
+	}
+	return points
+}
+
+func fedReportTrendPoint(reports []*share.CLUSFedClusterReport, now time.Time) *share.CLUSFedReportTrendPoint {
+	point := &share.CLUSFedReportTrendPoint{CollectedAt: now, Clusters: len(reports)}
+	for _, img := range consolidateFedVulReports(reports) {
+		point.Images++
+		point.HighVuls += img.HighVuls
+		point.MedVuls += img.MedVuls
+	}
+	for _, c := range consolidateFedComplianceReports(reports) {
+		point.ComplianceFailures += c.Workloads + c.Nodes
+	}
+	return point
+}
+
+// The same image could be pulled from different registries in different clusters, so images are
+// identified by the manifest digest when any cluster knows it. Otherwise the image id is used.
+func consolidateFedVulReports(reports []*share.CLUSFedClusterReport) []*api.RESTFedImageVulReport {
+	id2digest := make(map[string]string)
+	for _, report := range reports {
+		for _, img := range report.Images {
+			if img.Digest != "" {
+				id2digest[img.ImageID] = img.Digest
+			}
+		}
+	}
+
+	type fedImage struct {
+		vul                      *api.RESTFedImageVulReport
+		imageIDs, names, cluster utils.Set
+	}
+	all := make(map[string]*fedImage)
+	for _, report := range reports {
+		for _, img := range report.Images {
+			key := img.Digest
+			if key == "" {
+				if key = id2digest[img.ImageID]; key == "" {
+					key = img.ImageID
+				}
+			}
+			fi, ok := all[key]
+			if !ok {
+				fi = &fedImage{
+					vul:      &api.RESTFedImageVulReport{Digest: key},
+					imageIDs: utils.NewSet(),
+					names:    utils.NewSet(),
+					cluster:  utils.NewSet(),
+				}
+				all[key] = fi
+			}
+			fi.imageIDs.Add(img.ImageID)
+			for _, n := range img.Names {
+				fi.names.Add(n)
+			}
+			fi.cluster.Add(report.Name)
+			if img.HighVuls > fi.vul.HighVuls {
+				fi.vul.HighVuls = img.HighVuls
+			}
+			if img.MedVuls > fi.vul.MedVuls {
+				fi.vul.MedVuls = img.MedVuls
+			}
+		}
+	}
+
+	list := make([]*api.RESTFedImageVulReport, 0, len(all))
+	for _, fi := range all {
+		fi.vul.ImageIDs = fi.imageIDs.ToStringSlice()
+		fi.vul.Names = fi.names.ToStringSlice()
+		fi.vul.Clusters = fi.cluster.ToStringSlice()
+		sort.Strings(fi.vul.ImageIDs)
+		sort.Strings(fi.vul.Names)
+		sort.Strings(fi.vul.Clusters)
+		list = append(list, fi.vul)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].HighVuls != list[j].HighVuls {
+			return list[i].HighVuls > list[j].HighVuls
+		} else if list[i].MedVuls != list[j].MedVuls {
+			return list[i].MedVuls > list[j].MedVuls
+		}
+		return list[i].Digest < list[j].Digest
+	})
+	return list
+}
+
+func consolidateFedComplianceReports(reports []*share.CLUSFedClusterReport) []*api.RESTFedComplianceReport {
+	all := make(map[string]*api.RESTFedComplianceReport)
+	for _, report := range reports {
+		for _, c := range report.Compliances {
+			comp, ok := all[c.Name]
+			if !ok {
+				comp = &api.RESTFedComplianceReport{
+					Name:        c.Name,
+					Category:    c.Category,
+					Level:       c.Level,
+					Description: c.Description,
+					Clusters:    make([]string, 0),
+				}
+				all[c.Name] = comp
+			}
+			comp.Clusters = append(comp.Clusters, report.Name)
+			comp.Workloads += c.Workloads
+			comp.Nodes += c.Nodes
+		}
+	}
+
+	list := make([]*api.RESTFedComplianceReport, 0, len(all))
+	for _, comp := range all {
+		sort.Strings(comp.Clusters)
+		list = append(list, comp)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// only reports of the clusters that are still in federation are returned
+func getFedMemberReports(acc *access.AccessControl) []*share.CLUSFedClusterReport {
+	masterCluster := cacher.GetFedMasterCluster(acc)
+	ids := cacher.GetFedJoinedClusterIdMap(acc)
+	reports := make([]*share.CLUSFedClusterReport, 0, len(ids)+1)
+	for id, report := range clusHelper.GetAllFedClusterReports() {
+		if _, ok := ids[id]; ok || id == masterCluster.ID {
+			reports = append(reports, report)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Name < reports[j].Name })
+	return reports
+}
+
+func handlerGetFedReport(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := isFedOpAllowed(api.FedRoleMaster, _fedReaderRequired, w, r)
+	if acc == nil || login == nil {
+		return
+	}
+
+	reports := getFedMemberReports(acc)
+	resp := api.RESTFedReportData{Clusters: make([]*api.RESTFedClusterReportSummary, 0, len(reports))}
+	for _, report := range reports {
+		s := &api.RESTFedClusterReportSummary{
+			ID:                   report.ID,
+			Name:                 report.Name,
+			CollectedAtTimeStamp: report.CollectedAt.Unix(),
+			CollectedAt:          api.RESTTimeString(report.CollectedAt),
+			Images:               len(report.Images),
+		}
+		for _, img := range report.Images {
+			s.HighVuls += img.HighVuls
+			s.MedVuls += img.MedVuls
+		}
+		for _, c := range report.Compliances {
+			s.ComplianceFailures += c.Workloads + c.Nodes
+		}
+		resp.Clusters = append(resp.Clusters, s)
+	}
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get federation report")
+}
+
+func handlerGetFedVulReport(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := isFedOpAllowed(api.FedRoleMaster, _fedReaderRequired, w, r)
+	if acc == nil || login == nil {
+		return
+	}
+
+	resp := api.RESTFedVulReportData{Images: consolidateFedVulReports(getFedMemberReports(acc))}
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get federation vulnerability report")
+}
+
+func handlerGetFedComplianceReport(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := isFedOpAllowed(api.FedRoleMaster, _fedReaderRequired, w, r)
+	if acc == nil || login == nil {
+		return
+	}
+
+	resp := api.RESTFedComplianceReportData{Compliances: consolidateFedComplianceReports(getFedMemberReports(acc))}
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get federation compliance report")
+}
+
+func handlerGetFedReportTrend(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := isFedOpAllowed(api.FedRoleMaster, _fedReaderRequired, w, r)
+	if acc == nil || login == nil {
+		return
+	}
+
+	trend := clusHelper.GetFedReportTrend()
+	resp := api.RESTFedReportTrendData{Trend: make([]*api.RESTFedReportTrendPoint, 0, len(trend.Points))}
+	for _, p := range trend.Points {
+		resp.Trend = append(resp.Trend, &api.RESTFedReportTrendPoint{
+			CollectedAtTimeStamp: p.CollectedAt.Unix(),
+			Clusters:             p.Clusters,
+			Images:               p.Images,
+			HighVuls:             p.HighVuls,
+			MedVuls:              p.MedVuls,
+			ComplianceFailures:   p.ComplianceFailures,
+		})
+	}
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get federation report trend")
+}
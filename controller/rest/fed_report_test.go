@@ -0,0 +1,103 @@
+package rest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestFedVulReportConsolidation(t *testing.T) {
+	reports := []*share.CLUSFedClusterReport{
+		&share.CLUSFedClusterReport{
+			ID:   "c1",
+			Name: "cluster1",
+			Images: []*share.CLUSFedImageVulSummary{
+				&share.CLUSFedImageVulSummary{ImageID: "id1", Digest: "sha256:d1", Names: []string{"reg1/nginx:1.0"}, HighVuls: 2, MedVuls: 5},
+				&share.CLUSFedImageVulSummary{ImageID: "id2", Names: []string{"redis:6"}, HighVuls: 1, MedVuls: 1},
+			},
+		},
+		&share.CLUSFedClusterReport{
+			ID:   "c2",
+			Name: "cluster2",
+			Images: []*share.CLUSFedImageVulSummary{
+				// same image without digest, known by its image id
+				&share.CLUSFedImageVulSummary{ImageID: "id1", Names: []string{"nginx:1.0"}, HighVuls: 3, MedVuls: 4},
+				// same image with a different image id, known by its digest
+				&share.CLUSFedImageVulSummary{ImageID: "id3", Digest: "sha256:d1", Names: []string{"reg2/nginx:1.0"}, HighVuls: 2, MedVuls: 5},
+				&share.CLUSFedImageVulSummary{ImageID: "id2", Names: []string{"redis:6"}, HighVuls: 1, MedVuls: 1},
+			},
+		},
+	}
+
+	images := consolidateFedVulReports(reports)
+	if len(images) != 2 {
+		t.Fatalf("Unexpected number of images: %d", len(images))
+	}
+
+	img := images[0]
+	if img.Digest != "sha256:d1" || img.HighVuls != 3 || img.MedVuls != 5 {
+		t.Errorf("Unexpected image: %+v", img)
+	}
+	if !reflect.DeepEqual(img.ImageIDs, []string{"id1", "id3"}) ||
+		!reflect.DeepEqual(img.Names, []string{"nginx:1.0", "reg1/nginx:1.0", "reg2/nginx:1.0"}) ||
+		!reflect.DeepEqual(img.Clusters, []string{"cluster1", "cluster2"}) {
+		t.Errorf("Unexpected image: %+v", img)
+	}
+
+	img = images[1]
+	if img.Digest != "id2" || !reflect.DeepEqual(img.Clusters, []string{"cluster1", "cluster2"}) {
+		t.Errorf("Unexpected image: %+v", img)
+	}
+}
+
+func TestFedComplianceReportConsolidation(t *testing.T) {
+	reports := []*share.CLUSFedClusterReport{
+		&share.CLUSFedClusterReport{
+			ID:   "c1",
+			Name: "cluster1",
+			Compliances: []*share.CLUSFedComplianceSummary{
+				&share.CLUSFedComplianceSummary{Name: "D.1.1", Level: "WARN", Nodes: 2},
+				&share.CLUSFedComplianceSummary{Name: "D.4.1", Level: "WARN", Workloads: 3},
+			},
+		},
+		&share.CLUSFedClusterReport{
+			ID:   "c2",
+			Name: "cluster2",
+			Compliances: []*share.CLUSFedComplianceSummary{
+				&share.CLUSFedComplianceSummary{Name: "D.4.1", Level: "WARN", Workloads: 4},
+			},
+		},
+	}
+
+	comps := consolidateFedComplianceReports(reports)
+	if len(comps) != 2 {
+		t.Fatalf("Unexpected number of compliance checks: %d", len(comps))
+	}
+	if comps[0].Name != "D.1.1" || comps[0].Nodes != 2 || !reflect.DeepEqual(comps[0].Clusters, []string{"cluster1"}) {
+		t.Errorf("Unexpected compliance check: %+v", comps[0])
+	}
+	if comps[1].Name != "D.4.1" || comps[1].Workloads != 7 || !reflect.DeepEqual(comps[1].Clusters, []string{"cluster1", "cluster2"}) {
+		t.Errorf("Unexpected compliance check: %+v", comps[1])
+	}
+
+	point := fedReportTrendPoint(reports, time.Now())
+	if point.Clusters != 2 || point.ComplianceFailures != 9 {
+		t.Errorf("Unexpected trend point: %+v", point)
+	}
+}
+
+func TestFedReportTrendMax(t *testing.T) {
+	var points []*share.CLUSFedReportTrendPoint
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		points = appendFedReportTrend(points, &share.CLUSFedReportTrendPoint{CollectedAt: now.Add(time.Hour * time.Duration(i))}, 3)
+	}
+	if len(points) != 3 {
+		t.Fatalf("Unexpected number of trend points: %d", len(points))
+	}
+	if !points[0].CollectedAt.Equal(now.Add(time.Hour*2)) || !points[2].CollectedAt.Equal(now.Add(time.Hour*4)) {
+		t.Errorf("Oldest trend points should be removed")
+	}
+}
@@ -0,0 +1,103 @@
+package rest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// fed rules types whose deployment is affected by the selector of each object type
+var fedSyncObjRuleTypes map[string][]string = map[string][]string{
+	share.FedSyncObjGroup: []string{share.FedGroupType, share.FedNetworkRulesType, share.FedResponseRulesType,
+		share.FedFileMonitorProfilesType, share.FedProcessProfilesType},
+	share.FedSyncObjNetworkRule:   []string{share.FedNetworkRulesType},
+	share.FedSyncObjResponseRule:  []string{share.FedResponseRulesType},
+	share.FedSyncObjAdmissionRule: []string{share.FedAdmCtrlExceptRulesType, share.FedAdmCtrlDenyRulesType},
+}
+
+func parseFedSyncObjects(objType string, names []string) ([]string, error) {
+	set := utils.NewSet()
+	for _, name := range names {
+		if objType == share.FedSyncObjGroup {
+			if !strings.HasPrefix(name, api.FederalGroupPrefix) {
+				return nil, fmt.Errorf("Group %s is not a federal group", name)
+			}
+		} else if _, err := strconv.ParseUint(name, 10, 32); err != nil {
+			return nil, fmt.Errorf("Invalid %s id %s", objType, name)
+		}
+		set.Add(name)
+	}
+	if set.Cardinality() == 0 {
+		return nil, nil
+	}
+	list := set.ToStringSlice()
+	sort.Strings(list)
+	return list, nil
+}
+
+// The selectors without any object are removed
+func parseFedSyncSelectors(selectors map[string]*api.RESTFedSyncSelector) (map[string]*share.CLUSFedSyncSelector, error) {
+	var err error
+	var cfg map[string]*share.CLUSFedSyncSelector
+	for objType, sel := range selectors {
+		if _, ok := fedSyncObjRuleTypes[objType]; !ok {
+			return nil, fmt.Errorf("Unsupported object type %s", objType)
+		}
+		if sel == nil {
+			continue
+		}
+		var s share.CLUSFedSyncSelector
+		if s.Include, err = parseFedSyncObjects(objType, sel.Include); err != nil {
+			return nil, err
+		}
+		if s.Exclude, err = parseFedSyncObjects(objType, sel.Exclude); err != nil {
+			return nil, err
+		}
+		if len(s.Include) > 0 || len(s.Exclude) > 0 {
+			if cfg == nil {
+				cfg = make(map[string]*share.CLUSFedSyncSelector)
+			}
+			cfg[objType] = &s
+		}
+	}
+	return cfg, nil
+}
+
+// Return the fed rules types that need to be deployed again after the selectors are changed
+func getFedSyncChangedRuleTypes(oldCfg, newCfg map[string]*share.CLUSFedSyncSelector) []string {
+	ruleTypes := utils.NewSet()
+	for objType, types := range fedSyncObjRuleTypes {
+		if !reflect.DeepEqual(oldCfg[objType], newCfg[objType]) {
+			for _, t := range types {
+				ruleTypes.Add(t)
+			}
+		}
+	}
+	list := ruleTypes.ToStringSlice()
+	sort.Strings(list)
+	return list
+}
+
+func fedSyncSelectors2REST(cfg map[string]*share.CLUSFedSyncSelector) map[string]*api.RESTFedSyncSelector {
+	if len(cfg) == 0 {
+		return nil
+	}
+	selectors := make(map[string]*api.RESTFedSyncSelector, len(cfg))
+	for objType, s := range cfg {
+		sel := &api.RESTFedSyncSelector{Include: s.Include, Exclude: s.Exclude}
+		if sel.Include == nil {
+			sel.Include = make([]string, 0)
+		}
+		if sel.Exclude == nil {
+			sel.Exclude = make([]string, 0)
+		}
+		selectors[objType] = sel
+	}
+	return selectors
+}
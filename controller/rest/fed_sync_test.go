@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestParseFedSyncSelectors(t *testing.T) {
+	invalid := []map[string]*api.RESTFedSyncSelector{
+		{"dlp_sensor": &api.RESTFedSyncSelector{Include: []string{"fed.s1"}}},
+		{share.FedSyncObjGroup: &api.RESTFedSyncSelector{Include: []string{"g1"}}},
+		{share.FedSyncObjNetworkRule: &api.RESTFedSyncSelector{Exclude: []string{"rule1"}}},
+	}
+	for i, selectors := range invalid {
+		if _, err := parseFedSyncSelectors(selectors); err == nil {
+			t.Errorf("Invalid selectors should fail: case=%d", i)
+		}
+	}
+
+	cfg, err := parseFedSyncSelectors(map[string]*api.RESTFedSyncSelector{
+		share.FedSyncObjGroup:       &api.RESTFedSyncSelector{Include: []string{"fed.g2", "fed.g1", "fed.g2"}},
+		share.FedSyncObjNetworkRule: &api.RESTFedSyncSelector{Include: []string{}, Exclude: []string{}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expect := map[string]*share.CLUSFedSyncSelector{share.FedSyncObjGroup: &share.CLUSFedSyncSelector{Include: []string{"fed.g1", "fed.g2"}}}
+	if !reflect.DeepEqual(cfg, expect) {
+		t.Errorf("Unexpected selectors: %+v", cfg)
+	}
+}
+
+func TestFedSyncChangedRuleTypes(t *testing.T) {
+	oldCfg := map[string]*share.CLUSFedSyncSelector{
+		share.FedSyncObjNetworkRule:   &share.CLUSFedSyncSelector{Exclude: []string{"100001"}},
+		share.FedSyncObjAdmissionRule: &share.CLUSFedSyncSelector{Exclude: []string{"100001"}},
+	}
+	newCfg := map[string]*share.CLUSFedSyncSelector{
+		share.FedSyncObjNetworkRule: &share.CLUSFedSyncSelector{Exclude: []string{"100001"}},
+	}
+
+	types := getFedSyncChangedRuleTypes(oldCfg, newCfg)
+	if !reflect.DeepEqual(types, []string{share.FedAdmCtrlDenyRulesType, share.FedAdmCtrlExceptRulesType}) {
+		t.Errorf("Unexpected rule types: %+v", types)
+	}
+	if types := getFedSyncChangedRuleTypes(newCfg, newCfg); len(types) != 0 {
+		t.Errorf("Unexpected rule types: %+v", types)
+	}
+}
@@ -87,6 +87,8 @@ const (
 	_tagLeaveFed           = "leave"
 	_tagDismissFed         = "dismiss"
 	_tagFedForward         = "forward"
+	_tagFailover           = "failover"
+	_tagFedReport          = "report"
 
 	_headerProxy = "X-NV-Proxy"
 )
@@ -111,12 +113,14 @@ const (
 	_fedClusterPinging        = 208  // do not change
 	_fedClusterSyncing        = 209  // do not change
 	_fedClusterJoinPending    = 210  // do not change
+	_fedMasterUnreachable     = 211  // do not change
 	_fedClusterNetworkError   = 300  // do not change. this state is not visible on UI
 	_fedClusterImporting      = 301  // do not change. this state is not visible on UI
 )
 
 const clusterAuthTimeout = time.Duration(10 * time.Second)
 const restForInstantPing = time.Duration(8 * time.Second)
+const masterUnreachablePolls = 3 // joint cluster reports master cluster unreachable after this many polling intervals without a successful polling
 
 const jsonContentType = "application/json"
 
@@ -126,6 +130,7 @@ const (
 	const_no_proxy = iota
 	const_https_proxy
 	const_http_proxy
+	const_fed_proxy // proxy in fed proxy config. it's not in _nvHttpClients
 )
 
 var _isLeader uint32
@@ -141,6 +146,7 @@ var _fedPollingTimer *time.Timer = time.NewTimer(time.Minute * time.Duration(_fe
 var _fedPingInterval uint32 = 1                                                                 // in minutes
 var _fedPingTimer *time.Timer = time.NewTimer(time.Minute * time.Duration(_fedPingInterval))    // for master cluster to ping master clusters
 var _lastFedMemberPingTime time.Time = time.Now()
+var _lastMasterHeartbeatTime time.Time = time.Now() // for joint cluster: last time it polled master cluster successfully
 var _masterClusterIP string
 
 var _sysHttpsProxy share.CLUSProxy
@@ -173,6 +179,7 @@ var _clusterStatusMap = map[int]string{
 	_fedClusterPinging:        api.FedStatusClusterPinging,
 	_fedClusterSyncing:        api.FedStatusClusterSyncing,
 	_fedClusterJoinPending:    api.FedStatusClusterPending,
+	_fedMasterUnreachable:     api.FedStatusMasterUnreachable,
 }
 
 var ibmSACfg share.CLUSIBMSAConfig
@@ -507,6 +514,14 @@ func sendRestRequest(idTarget string, method, urlStr, token, cntType, jointTicke
 			useProxy = const_http_proxy
 		}
 	} else {
+		// for communication with master, fed proxy is the only choice when it's configured
+		if nvHttpClient, errFed := getFedProxyHttpClient(acc); errFed != nil {
+			return nil, 0, false, errFed
+		} else if nvHttpClient != nil {
+			data, statusCode, err = sendRestReqInternal(nvHttpClient, method, urlStr, token, cntType,
+				jointTicket, jointID, const_fed_proxy, cookie, body, logError)
+			return data, statusCode, nvHttpClient.proxyUrlStr != "", err
+		}
 		if specificProxy != nil {
 			useProxy = *specificProxy
 		} else {
@@ -725,14 +740,29 @@ func sendReqToJointCluster(rc share.CLUSRestServerInfo, clusterID, token, method
 	var scanRepository bool
 	var err error
 
-	_, useProxy := cacher.GetFedLocalRestInfo(acc)
-	proxyOptions := getProxyOptions(clusterID, useProxy)
-
 	if method == http.MethodPost && request == "/v1/scan/repository" {
 		scanRepository = true
 	}
 
 	urlStr := fmt.Sprintf("https://%s:%d/%s", rc.Server, rc.Port, request)
+
+	// fed proxy is the only choice when it's configured
+	if nvHttpClient, errFed := getFedProxyHttpClient(acc); errFed != nil {
+		return nil, 0, nil, false, errFed
+	} else if nvHttpClient != nil {
+		if scanRepository {
+			nvHttpClient.httpClient.Timeout = repoScanLingeringDuration + time.Duration(30*time.Second)
+		}
+		headers, statusCode, data, err = sendReqToJointClusterInternal(nvHttpClient, method, urlStr, token, contentType, tag, txnID,
+			const_fed_proxy, body, gzipped, forward, remoteExport, logError)
+		if scanRepository {
+			nvHttpClient.httpClient.Timeout = clusterAuthTimeout
+		}
+		return headers, statusCode, data, nvHttpClient.proxyUrlStr != "", err
+	}
+
+	_, useProxy := cacher.GetFedLocalRestInfo(acc)
+	proxyOptions := getProxyOptions(clusterID, useProxy)
 	for _, proxyOption := range proxyOptions {
 		var nvHttpClient *tNvHttpClient
 
@@ -1001,7 +1031,7 @@ func cleanFedRules() {
 		Rules:     make(map[uint32]*share.CLUSResponseRule),
 		RuleHeads: make([]*share.CLUSRuleHead, 0),
 	}
-	replaceFedResponseRules(resRulesData.Rules, resRulesData.RuleHeads)
+	replaceFedResponseRules(resRulesData.Rules, resRulesData.RuleHeads, make([]*share.CLUSFedQuarantineImage, 0))
 
 	deleteFedGroupPolicy()
 
@@ -1160,7 +1190,10 @@ func updateFedRulesRevision(ruleTypes []string, acc *access.AccessControl, login
 			}
 		}
 	}
-	go notifyDeployFedRules(acc, login)
+	// without login session, managed clusters get the change in their next polling
+	if login != nil {
+		go notifyDeployFedRules(acc, login)
+	}
 }
 
 func pingJointCluster(tag, urlStr string, jointCluster share.CLUSFedJointClusterInfo, ch chan<- cmdResponse, acc *access.AccessControl) (int, bool, error) {
@@ -1333,6 +1366,9 @@ func handlerGetFedMember(w http.ResponseWriter, r *http.Request, ps httprouter.P
 
 	fedCfg := cacher.GetFedSettings()
 	org.DeployRepoScanData = fedCfg.DeployRepoScanData
+	if org.FedRole == api.FedRoleMaster {
+		org.SyncSelectors = fedSyncSelectors2REST(fedCfg.SyncSelectors)
+	}
 
 	restRespSuccess(w, r, org, acc, login, nil, "Get federation config")
 }
@@ -1369,20 +1405,38 @@ func handlerConfigLocalCluster(w http.ResponseWriter, r *http.Request, ps httpro
 	}
 	defer clusHelper.ReleaseLock(lock)
 
-	if reqData.DeployRepoScanData != nil {
+	if reqData.DeployRepoScanData != nil || reqData.SyncSelectors != nil {
 		if fedRole == api.FedRoleJoint {
 			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrFedOperationFailed,
 				"Options for scan data deployment can only be configured on primary cluster")
 			return
 		}
+		var ruleTypes []string
 		fedCfg := clusHelper.GetFedSettings()
 		newCfg := fedCfg
 		if reqData.DeployRepoScanData != nil {
 			newCfg.DeployRepoScanData = *reqData.DeployRepoScanData
 		}
-		if newCfg != fedCfg {
+		if reqData.SyncSelectors != nil {
+			if fedRole != api.FedRoleMaster {
+				restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrFedOperationFailed,
+					"Sync selectors can only be configured on primary cluster")
+				return
+			}
+			if newCfg.SyncSelectors, err = parseFedSyncSelectors(*reqData.SyncSelectors); err != nil {
+				log.WithFields(log.Fields{"error": err}).Error("Request error")
+				restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+				return
+			}
+			ruleTypes = getFedSyncChangedRuleTypes(fedCfg.SyncSelectors, newCfg.SyncSelectors)
+		}
+		if newCfg.DeployRepoScanData != fedCfg.DeployRepoScanData || len(ruleTypes) > 0 {
 			clusHelper.PutFedSettings(nil, newCfg)
 		}
+		if len(ruleTypes) > 0 {
+			// managed clusters get the fed rules again with the new selectors
+			updateFedRulesRevision(ruleTypes, acc, login)
+		}
 	}
 
 	if fedRole == api.FedRoleMaster {
@@ -1411,11 +1465,20 @@ func handlerConfigLocalCluster(w http.ResponseWriter, r *http.Request, ps httpro
 		}
 	}
 
-	if reqData.RestInfo != nil || reqData.UseProxy != nil {
+	if reqData.RestInfo != nil || reqData.UseProxy != nil || reqData.FedProxy != nil {
 		if m := clusHelper.GetFedMembership(); m != nil {
 			if reqData.UseProxy != nil {
 				m.UseProxy = *reqData.UseProxy
 			}
+			if reqData.FedProxy != nil {
+				fedProxy, err := parseFedProxyConfig(reqData.FedProxy, m.FedProxy)
+				if err != nil {
+					log.WithFields(log.Fields{"error": err}).Error("Request error")
+					restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+					return
+				}
+				m.FedProxy = fedProxy
+			}
 			if reqData.RestInfo != nil {
 				switch m.FedRole {
 				case api.FedRoleNone:
@@ -1560,6 +1623,7 @@ func handlerPromoteToMaster(w http.ResponseWriter, r *http.Request, ps httproute
 			RestInfo: restInfo,
 		},
 		UseProxy: useProxy,
+		FedProxy: cacher.GetFedProxyConfig(acc),
 	}
 
 	if clusHelper.PutFedMembership(&m) != nil {
@@ -1666,6 +1730,7 @@ func handlerDemoteFromMaster(w http.ResponseWriter, r *http.Request, ps httprout
 	evqueue.Flush()
 	revertFedRoles(acc)
 	cleanFedRules()
+	clusHelper.DeleteFedReports()
 
 	cache.ConfigCspUsages(false, false, api.FedRoleNone, "")
 
@@ -1734,6 +1799,7 @@ func handlerJoinFed(w http.ResponseWriter, r *http.Request, ps httprouter.Params
 	var specificProxy int8
 	var joinToken joinToken
 	var msgProxy string
+	var fedProxy *share.CLUSFedProxyConfig
 	body, _ := ioutil.ReadAll(r.Body)
 	if err := json.Unmarshal(body, &req); err != nil || (req.UseProxy != nil && *req.UseProxy != "" && *req.UseProxy != "https") {
 		log.WithFields(log.Fields{"error": err}).Error("Request error")
@@ -1756,6 +1822,18 @@ func handlerJoinFed(w http.ResponseWriter, r *http.Request, ps httprouter.Params
 			msgProxy = "(use proxy)"
 			specificProxy = const_https_proxy
 		}
+
+		fedProxy = cacher.GetFedProxyConfig(acc)
+		if req.FedProxy != nil {
+			if fedProxy, err = parseFedProxyConfig(req.FedProxy, fedProxy); err != nil {
+				log.WithFields(log.Fields{"error": err}).Error("Request error")
+				restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+				return
+			}
+		}
+		if fedProxy != nil && fedProxy.URL != "" {
+			msgProxy = "(use fed proxy)"
+		}
 	}
 	//log.WithFields(log.Fields{"useProxy": useProxy}).Debug()
 
@@ -1817,7 +1895,17 @@ func handlerJoinFed(w http.ResponseWriter, r *http.Request, ps httprouter.Params
 	var proxyUsed bool
 	// call master cluster for joining federation
 	urlStr := fmt.Sprintf("https://%s:%d/v1/fed/join_internal", req.Server, req.Port)
-	data, statusCode, proxyUsed, err = sendRestRequest("", http.MethodPost, urlStr, "", "", "", "", nil, bodyTo, true, &specificProxy, acc)
+	if fedProxy != nil {
+		// fed proxy config in the request is not saved yet
+		var nvHttpClient *tNvHttpClient
+		if nvHttpClient, err = createFedProxyHttpClient(fedProxy, clusterAuthTimeout); err == nil {
+			data, statusCode, err = sendRestReqInternal(nvHttpClient, http.MethodPost, urlStr, "", "", "", "", const_fed_proxy, nil, bodyTo, true)
+			proxyUsed = fedProxy.URL != ""
+			nvHttpClient.httpClient.CloseIdleConnections()
+		}
+	} else {
+		data, statusCode, proxyUsed, err = sendRestRequest("", http.MethodPost, urlStr, "", "", "", "", nil, bodyTo, true, &specificProxy, acc)
+	}
 	if err == nil {
 		respTo := api.RESTFedJoinRespInternal{}
 		if err = json.Unmarshal(data, &respTo); err == nil {
@@ -1861,6 +1949,7 @@ func handlerJoinFed(w http.ResponseWriter, r *http.Request, ps httprouter.Params
 					User:       login.fullname,
 				},
 				UseProxy: useProxy,
+				FedProxy: fedProxy,
 			}
 			if err = clusHelper.PutFedMembership(&m); err == nil {
 				clusHelper.PutFedScanRevisions(&share.CLUSFedScanRevisions{ScannedRegRevs: make(map[string]uint64)}, nil)
@@ -2312,6 +2401,10 @@ func removeFromFederation(joinedCluster *share.CLUSFedJointClusterInfo, acc *acc
 				clusterIDs[i] = clusterIDs[len(clusterIDs)-1]
 				list.IDs = clusterIDs[:len(clusterIDs)-1]
 				if err := clusHelper.PutFedJointClusterList(list); err == nil {
+					if m := clusHelper.GetFedMembership(); m != nil && m.StandbyID == id {
+						m.StandbyID = ""
+						clusHelper.PutFedMembership(m)
+					}
 					clusHelper.DeleteFedJointCluster(id)
 					_, clientKeyPath, clientCertPath := kv.GetFedTlsKeyCertPath("", id)
 					os.Remove(clientKeyPath)
@@ -2448,7 +2541,8 @@ func workFedRules(fedSettings *api.RESTFedRulesSettings, fedRevs map[string]uint
 					}
 				case share.FedResponseRulesType:
 					if fedSettings.ResponseRulesData.Rules != nil && fedSettings.ResponseRulesData.RuleHeads != nil {
-						applied = replaceFedResponseRules(fedSettings.ResponseRulesData.Rules, fedSettings.ResponseRulesData.RuleHeads)
+						applied = replaceFedResponseRules(fedSettings.ResponseRulesData.Rules, fedSettings.ResponseRulesData.RuleHeads,
+							fedSettings.ResponseRulesData.QuarImages)
 					}
 				case share.FedGroupType:
 					applied = replaceFedGroups(fedSettings.GroupsData.Groups, acc)
@@ -2591,6 +2685,7 @@ func pollFedRules(forcePulling bool, tryTimes int) bool {
 		reqTo.ID = jointCluster.ID
 		reqTo.JointTicket = jwtGenFedTicket(jointCluster.Secret, jwtFedJointTicketLife)
 		reqTo.Revisions = cacher.GetAllFedRulesRevisions()
+		reqTo.QuarImages = cacher.GetPendingFedQuarantineImages()
 		if forcePulling {
 			for ruleType, _ := range reqTo.Revisions {
 				reqTo.Revisions[ruleType] = 0
@@ -2620,6 +2715,8 @@ func pollFedRules(forcePulling bool, tryTimes int) bool {
 					atomic.StoreUint32(&_fedPollInterval, respTo.PollInterval)
 				}
 				if respTo.Result == _fedSuccess { // success
+					_lastMasterHeartbeatTime = time.Now()
+					updateStandbySnapshot(respTo.StandbyID, respTo.Standby)
 					updateClusterState(jointCluster.ID, "", _fedClusterJoined, nil, accReadAll)
 					var cspUsage share.CLUSClusterCspUsage
 					cspUsage.CspType, _ = common.GetMappedCspType(&respTo.CspType, nil)
@@ -2660,6 +2757,7 @@ func pollFedRules(forcePulling bool, tryTimes int) bool {
 				} else if respTo.Result == _fedJointUpgradeRequired {
 					updateClusterState(jointCluster.ID, "", _fedJointUpgradeRequired, nil, accReadAll)
 				} else if respTo.Result == _fedClusterImporting {
+					_lastMasterHeartbeatTime = time.Now()
 					status = _fedSuccess
 				}
 			}
@@ -2680,6 +2778,12 @@ func pollFedRules(forcePulling bool, tryTimes int) bool {
 				}
 			}
 		}
+		if status == _fedClusterDisconnected && isMasterUnreachable(_lastMasterHeartbeatTime, time.Now(), atomic.LoadUint32(&_fedPollInterval)) {
+			if cached := cacher.GetFedJoinedClusterStatus(masterCluster.ID, accReadAll); cached.Status != _fedMasterUnreachable {
+				log.WithFields(log.Fields{"master": masterCluster.Name, "lastHeartbeat": _lastMasterHeartbeatTime}).Warn("Primary cluster is unreachable")
+			}
+			status = _fedMasterUnreachable
+		}
 		updateClusterState(masterCluster.ID, masterCluster.ID, status, nil, accReadAll)
 	}
 	return doPoll
@@ -2917,6 +3021,12 @@ func handlerPollFedRulesInternal(w http.ResponseWriter, r *http.Request, ps http
 		PollInterval:       atomic.LoadUint32(&_fedPollInterval),
 		DeployRepoScanData: fedCfg.DeployRepoScanData,
 	}
+	if m := clusHelper.GetFedMembership(); m != nil && m.StandbyID != "" {
+		resp.StandbyID = m.StandbyID
+		if req.ID == m.StandbyID {
+			resp.Standby = getStandbySnapshot(m.StandbyID, accReadAll)
+		}
+	}
 	_, resp.CspType = common.GetMappedCspType(nil, &cctx.CspType) // master cluster's billing csp type
 	if kv.IsImporting() {
 		// do not give out master's fed policies when master cluster is importing config
@@ -2944,6 +3054,15 @@ func handlerPollFedRulesInternal(w http.ResponseWriter, r *http.Request, ps http
 			status = result
 		} else {
 			// return fed registry/repo scan data revisions to managed clusters
+			if len(req.QuarImages) > 0 {
+				for _, img := range req.QuarImages {
+					img.Cluster = jointCluster.Name
+					img.User = ""
+				}
+				if addFedQuarantineImages(req.QuarImages) {
+					updateFedRulesRevision([]string{share.FedResponseRulesType}, accReadAll, nil)
+				}
+			}
 			resp.ScanDataRevs, _ = cacher.GetFedScanDataRevisions(true, fedCfg.DeployRepoScanData)
 			resp.Settings, resp.Revisions, _ = cacher.GetFedRules(req.Revisions, accReadAll)
 			if len(resp.Revisions) > 0 {
@@ -125,6 +125,11 @@ func replaceFedFileMonitorProfiles(profiles []*share.CLUSFileMonitorProfile, acc
 	return true
 }
 
+// "nodes" monitors the files of the host services, which are watched by the enforcers in monitor mode
+func isValidKindFileMonitorProfile(group, kind string) bool {
+	return kind == share.GroupKindContainer || (kind == share.GroupKindNode && utils.IsGroupNodes(group))
+}
+
 func handlerFileMonitorConfig(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
 	defer r.Body.Close()
@@ -167,8 +172,7 @@ func handlerFileMonitorConfig(w http.ResponseWriter, r *http.Request, ps httprou
 		return
 	}
 
-	if grp.Kind != share.GroupKindContainer {
-		// "nodes" : share.GroupKindNode
+	if !isValidKindFileMonitorProfile(group, grp.Kind) {
 		log.WithFields(log.Fields{"group": group, "kind": grp.Kind}).Error("Get profile failed!")
 		restRespError(w, http.StatusBadRequest, api.RESTErrObjectNotFound)
 		return
@@ -248,6 +252,7 @@ func handlerFileMonitorConfig(w http.ResponseWriter, r *http.Request, ps httprou
 				Regex:       regex,
 				Recursive:   filter.Recursive,
 				CustomerAdd: true,
+				Baseline:    filter.Baseline,
 			}
 			if fileAccessOptionSet.Contains(filter.Behavior) {
 				flt.Behavior = filter.Behavior
@@ -313,6 +318,10 @@ func handlerFileMonitorConfig(w http.ResponseWriter, r *http.Request, ps httprou
 						profChanged = true
 						profConf.Filters[i].Behavior = frule.Behavior
 					}
+					if filter.Baseline != cfilter.Baseline {
+						profChanged = true
+						profConf.Filters[i].Baseline = filter.Baseline
+					}
 					break
 				}
 			}
@@ -401,8 +410,7 @@ func handlerFileMonitorShow(w http.ResponseWriter, r *http.Request, ps httproute
 		return
 	}
 
-	if grp.Kind != share.GroupKindContainer {
-		// "nodes" : share.GroupKindNode
+	if !isValidKindFileMonitorProfile(name, grp.Kind) {
 		log.WithFields(log.Fields{"group": name, "kind": grp.Kind}).Error("Get profile failed!")
 		restRespError(w, http.StatusBadRequest, api.RESTErrObjectNotFound)
 		return
@@ -135,4 +135,31 @@ func TestFileRuleShow(t *testing.T) {
 		}
 	}
 	postTest()
-}
\ No newline at end of file
+}
+
+func TestFileBaselineResetInvalid(t *testing.T) {
+	preTest()
+
+	// Relative path
+	{
+		data := api.RESTFileBaselineResetData{Reset: &api.RESTFileBaselineReset{Paths: []string{"etc/passwd"}}}
+		body, _ := json.Marshal(data)
+		w := restCall("POST", "/v1/workload/wl1/file_baseline", body, api.UserRoleAdmin)
+		if w.status != http.StatusBadRequest {
+			t.Errorf("Reset a relative path: Status is OK")
+			t.Logf("  Expect status: %+v\n", http.StatusBadRequest)
+			t.Logf("  Actual status: %+v\n", w.status)
+		}
+	}
+
+	// Malformed request
+	{
+		w := restCall("POST", "/v1/workload/wl1/file_baseline", []byte("{\"reset\": []}"), api.UserRoleAdmin)
+		if w.status != http.StatusBadRequest {
+			t.Errorf("Reset with a malformed request: Status is OK")
+			t.Logf("  Expect status: %+v\n", http.StatusBadRequest)
+			t.Logf("  Actual status: %+v\n", w.status)
+		}
+	}
+	postTest()
+}
@@ -0,0 +1,529 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+)
+
+// The GraphQL endpoint serves the network activity map and asset inventory in one round trip. Only a subset of
+// the query language is supported: fields, aliases, nested selections and literal arguments. Variables,
+// fragments, directives and mutations are not supported.
+
+const gqlMaxDepth int = 8
+
+const (
+	gqlTypeQuery         = "Query"
+	gqlTypeWorkload      = "Workload"
+	gqlTypeGroup         = "Group"
+	gqlTypeConversation  = "Conversation"
+	gqlTypeEndpoint      = "Endpoint"
+	gqlTypeVulnerability = "Vulnerability"
+)
+
+var errGqlAccessDenied = errors.New("Access denied")
+
+type gqlField struct {
+	alias  string
+	name   string
+	args   map[string]string
+	fields []*gqlField // selection set, nil for a leaf field
+}
+
+func (f *gqlField) arg(name string) string {
+	return f.args[name]
+}
+
+// --
+
+type gqlParser struct {
+	src string
+	pos int
+}
+
+func parseGraphQL(query string) ([]*gqlField, error) {
+	p := &gqlParser{src: query}
+	if p.peek() != '{' {
+		if op, err := p.name(); err != nil {
+			return nil, err
+		} else if op != "query" {
+			return nil, fmt.Errorf("Operation %s is not supported", op)
+		}
+		if c := p.peek(); c != '{' && c != '(' {
+			// operation name
+			if _, err := p.name(); err != nil {
+				return nil, err
+			}
+		}
+		if p.peek() == '(' {
+			return nil, errors.New("Variables are not supported")
+		}
+	}
+
+	fields, err := p.selectionSet(1)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != 0 {
+		return nil, fmt.Errorf("Unexpected '%c' at position %d, only one operation is supported", p.src[p.pos], p.pos)
+	}
+	return fields, nil
+}
+
+// Skip ignored tokens and return the next character, 0 at the end
+func (p *gqlParser) peek() byte {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; c {
+		case ' ', '\t', '\n', '\r', ',':
+			p.pos++
+		case '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return c
+		}
+	}
+	return 0
+}
+
+func (p *gqlParser) expect(c byte) error {
+	if p.peek() != c {
+		return fmt.Errorf("Expected '%c' at position %d", c, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+func isGqlNameChar(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
+
+func (p *gqlParser) name() (string, error) {
+	p.peek()
+	start := p.pos
+	for p.pos < len(p.src) && isGqlNameChar(p.src[p.pos], p.pos == start) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", fmt.Errorf("Expected name at position %d", p.pos)
+	}
+	return p.src[start:p.pos], nil
+}
+
+// Return the string value of a literal argument
+func (p *gqlParser) value() (string, error) {
+	switch c := p.peek(); {
+	case c == '"':
+		var buf bytes.Buffer
+		for p.pos++; p.pos < len(p.src); p.pos++ {
+			switch c = p.src[p.pos]; c {
+			case '"':
+				p.pos++
+				return buf.String(), nil
+			case '\\':
+				if p.pos++; p.pos < len(p.src) {
+					buf.WriteByte(p.src[p.pos])
+				}
+			case '\n':
+				return "", fmt.Errorf("Unterminated string at position %d", p.pos)
+			default:
+				buf.WriteByte(c)
+			}
+		}
+		return "", errors.New("Unterminated string")
+	case c == '-' || (c >= '0' && c <= '9') || isGqlNameChar(c, true):
+		start := p.pos
+		for p.pos < len(p.src) && (isGqlNameChar(p.src[p.pos], false) || strings.IndexByte("-+.", p.src[p.pos]) >= 0) {
+			p.pos++
+		}
+		return p.src[start:p.pos], nil
+	case c == '$':
+		return "", errors.New("Variables are not supported")
+	default:
+		return "", fmt.Errorf("Unexpected value at position %d", p.pos)
+	}
+}
+
+func (p *gqlParser) selectionSet(depth int) ([]*gqlField, error) {
+	if depth > gqlMaxDepth {
+		return nil, fmt.Errorf("Query depth is over %d", gqlMaxDepth)
+	}
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+
+	fields := make([]*gqlField, 0)
+	for {
+		switch p.peek() {
+		case '}':
+			p.pos++
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("Empty selection at position %d", p.pos)
+			}
+			return fields, nil
+		case 0:
+			return nil, errors.New("Unexpected end of query")
+		case '.':
+			return nil, errors.New("Fragments are not supported")
+		}
+
+		f, err := p.field(depth)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+}
+
+func (p *gqlParser) field(depth int) (*gqlField, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	f := &gqlField{alias: name, name: name}
+	if p.peek() == ':' {
+		p.pos++
+		if f.name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek() == '(' {
+		p.pos++
+		f.args = make(map[string]string)
+		for p.peek() != ')' {
+			arg, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect(':'); err != nil {
+				return nil, err
+			}
+			if f.args[arg], err = p.value(); err != nil {
+				return nil, err
+			}
+		}
+		p.pos++
+	}
+	if p.peek() == '@' {
+		return nil, errors.New("Directives are not supported")
+	}
+	if p.peek() == '{' {
+		if f.fields, err = p.selectionSet(depth + 1); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// --
+
+type gqlExecutor struct {
+	login     *loginSession
+	accs      map[string]*access.AccessControl // key is the uri serving the same data in REST API
+	endpoints map[string]*api.RESTConversationEndpoint
+}
+
+// Objects are authorized the same way as they are in REST API
+func (e *gqlExecutor) access(uri string) *access.AccessControl {
+	if acc, ok := e.accs[uri]; ok {
+		return acc
+	}
+
+	r, _ := http.NewRequest(http.MethodGet, uri, nil)
+	acc := access.NewAccessControl(r, access.AccessOPRead, e.login.domainRoles)
+	if e.login.loginType == loginTypeApikey && !acc.IsAllowedByApikeyScopes(e.login.apikeyScopes) {
+		acc = nil
+	}
+	e.accs[uri] = acc
+	return acc
+}
+
+func (e *gqlExecutor) resolveRoot(f *gqlField) (interface{}, error) {
+	var typ, uri string
+	switch f.name {
+	case "workloads":
+		typ, uri = gqlTypeWorkload, "/v1/workload"
+	case "groups":
+		typ, uri = gqlTypeGroup, "/v1/group"
+	case "conversations":
+		typ, uri = gqlTypeConversation, "/v1/conversation"
+	case "endpoints":
+		typ, uri = gqlTypeEndpoint, "/v1/conversation_endpoint"
+	case "vulnerabilities":
+		if f.arg("workload") == "" {
+			return nil, errors.New("Argument \"workload\" is required")
+		}
+		typ, uri = gqlTypeVulnerability, "/v1/scan/workload/"+f.arg("workload")
+	default:
+		return nil, fmt.Errorf("Cannot query field \"%s\" on type \"%s\"", f.name, gqlTypeQuery)
+	}
+	if f.fields == nil {
+		return nil, fmt.Errorf("Field \"%s\" of type \"[%s]\" must have a selection of subfields", f.name, typ)
+	}
+
+	acc := e.access(uri)
+	if acc == nil {
+		return nil, errGqlAccessDenied
+	}
+
+	list := make([]interface{}, 0)
+	switch f.name {
+	case "workloads":
+		for _, wl := range cacher.GetAllWorkloadsDetail("", acc) {
+			if (f.arg("id") == "" || wl.ID == f.arg("id")) && (f.arg("domain") == "" || wl.Domain == f.arg("domain")) {
+				list = append(list, wl)
+			}
+		}
+	case "groups":
+		for _, groups := range cacher.GetAllGroups("", "", false, acc) {
+			for _, g := range groups {
+				if (f.arg("name") == "" || g.Name == f.arg("name")) && (f.arg("domain") == "" || g.Domain == f.arg("domain")) {
+					list = append(list, g)
+				}
+			}
+		}
+	case "conversations":
+		if group := f.arg("group"); group != "" {
+			if exist, _ := cacher.DoesGroupExist(group, acc); !exist {
+				return nil, fmt.Errorf("Group %s not found", group)
+			}
+		}
+		convers, _ := cacher.GetAllApplicationConvers(f.arg("group"), f.arg("domain"), acc)
+		for _, conver := range convers {
+			list = append(list, conver)
+		}
+	case "endpoints":
+		for _, ep := range cacher.GetAllConverEndpoints("", acc) {
+			list = append(list, ep)
+		}
+	case "vulnerabilities":
+		vuls, err := e.workloadVuls(f.arg("workload"))
+		if err != nil {
+			return nil, err
+		}
+		list = vuls
+	}
+
+	return e.objects(typ, list, f.fields)
+}
+
+func (e *gqlExecutor) workloadVuls(id string) ([]interface{}, error) {
+	acc := e.access("/v1/scan/workload/" + id)
+	if acc == nil {
+		return nil, errGqlAccessDenied
+	} else if err := cacher.CanAccessWorkload(id, acc); err != nil {
+		return nil, err
+	}
+
+	list := make([]interface{}, 0)
+	vuls, _, _ := cacher.GetVulnerabilityReport(id, "")
+	for _, v := range vuls {
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+// Fields that are resolved from other objects. Without a selection, the field's own value is returned
+func (e *gqlExecutor) resolveNested(typ string, obj interface{}, f *gqlField) (interface{}, bool, error) {
+	switch typ {
+	case gqlTypeWorkload:
+		wl := obj.(*api.RESTWorkloadDetail)
+		switch {
+		case f.name == "vulnerabilities":
+			if f.fields == nil {
+				return nil, true, fmt.Errorf("Field \"%s\" of type \"[%s]\" must have a selection of subfields", f.name, gqlTypeVulnerability)
+			}
+			vuls, err := e.workloadVuls(wl.ID)
+			if err != nil {
+				return nil, true, err
+			}
+			list, err := e.objects(gqlTypeVulnerability, vuls, f.fields)
+			return list, true, err
+		case f.name == "groups" && f.fields != nil:
+			list := make([]interface{}, 0, len(wl.Groups))
+			if acc := e.access("/v1/group"); acc != nil {
+				for _, name := range wl.Groups {
+					if g, err := cacher.GetGroup(name, "", false, acc); err == nil {
+						list = append(list, g)
+					}
+				}
+			}
+			groups, err := e.objects(gqlTypeGroup, list, f.fields)
+			return groups, true, err
+		}
+	case gqlTypeGroup:
+		if g := obj.(*api.RESTGroup); f.name == "members" && f.fields != nil {
+			list := make([]interface{}, 0, len(g.Members))
+			if acc := e.access("/v1/workload"); acc != nil {
+				for _, m := range g.Members {
+					if wl, err := cacher.GetWorkloadDetail(m.ID, "", acc); err == nil {
+						list = append(list, wl)
+					}
+				}
+			}
+			members, err := e.objects(gqlTypeWorkload, list, f.fields)
+			return members, true, err
+		}
+	case gqlTypeConversation:
+		if conver := obj.(*api.RESTConversationCompact); (f.name == "from" || f.name == "to") && f.fields != nil {
+			if e.endpoints == nil {
+				e.endpoints = make(map[string]*api.RESTConversationEndpoint)
+				if acc := e.access("/v1/conversation_endpoint"); acc != nil {
+					for _, ep := range cacher.GetAllConverEndpoints("", acc) {
+						e.endpoints[ep.ID] = ep
+					}
+				}
+			}
+			id := conver.From
+			if f.name == "to" {
+				id = conver.To
+			}
+			if ep, ok := e.endpoints[id]; ok {
+				m, err := e.object(gqlTypeEndpoint, ep, f.fields)
+				return m, true, err
+			}
+			return nil, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (e *gqlExecutor) objects(typ string, list []interface{}, sel []*gqlField) ([]interface{}, error) {
+	results := make([]interface{}, len(list))
+	for i, obj := range list {
+		m, err := e.object(typ, obj, sel)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = m
+	}
+	return results, nil
+}
+
+func (e *gqlExecutor) object(typ string, obj interface{}, sel []*gqlField) (map[string]interface{}, error) {
+	var fields map[string]interface{}
+	if err := gqlToValue(obj, &fields); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{}, len(sel))
+	for _, f := range sel {
+		if v, ok, err := e.resolveNested(typ, obj, f); ok {
+			if err != nil {
+				return nil, err
+			}
+			result[f.alias] = v
+			continue
+		}
+
+		v, ok := fields[f.name]
+		if !ok {
+			return nil, fmt.Errorf("Cannot query field \"%s\" on type \"%s\"", f.name, typ)
+		}
+		if f.fields != nil {
+			var err error
+			if v, err = gqlSelect(v, f); err != nil {
+				return nil, err
+			}
+		}
+		result[f.alias] = v
+	}
+	return result, nil
+}
+
+// Apply the selection to the json value of a field that is not resolved from other objects
+func gqlSelect(v interface{}, f *gqlField) (interface{}, error) {
+	switch value := v.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		results := make([]interface{}, len(value))
+		for i, elem := range value {
+			r, err := gqlSelect(elem, f)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = r
+		}
+		return results, nil
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(f.fields))
+		for _, sub := range f.fields {
+			sv, ok := value[sub.name]
+			if !ok {
+				return nil, fmt.Errorf("Cannot query field \"%s\" on field \"%s\"", sub.name, f.name)
+			}
+			if sub.fields != nil {
+				var err error
+				if sv, err = gqlSelect(sv, sub); err != nil {
+					return nil, err
+				}
+			}
+			result[sub.alias] = sv
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("Field \"%s\" must not have a selection since it has no subfields", f.name)
+	}
+}
+
+// Objects are served with the same json tags as in REST API
+func gqlToValue(obj interface{}, v interface{}) error {
+	var m common.EmptyMarshaller
+	data, err := m.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func handlerGraphQL(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, access.AccessOPRead)
+	if acc == nil {
+		return
+	}
+
+	var rconf api.RESTGraphQLQuery
+	body, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(body, &rconf); err != nil || rconf.Query == "" {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	fields, err := parseGraphQL(rconf.Query)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Invalid query")
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+		return
+	}
+
+	e := &gqlExecutor{login: login, accs: make(map[string]*access.AccessControl)}
+	resp := api.RESTGraphQLData{Data: make(map[string]interface{}, len(fields))}
+	for _, f := range fields {
+		v, err := e.resolveRoot(f)
+		if err != nil {
+			resp.Errors = append(resp.Errors, &api.RESTGraphQLError{Message: err.Error(), Path: []string{f.alias}})
+		}
+		resp.Data[f.alias] = v
+	}
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "")
+}
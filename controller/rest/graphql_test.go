@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestGraphQLParse(t *testing.T) {
+	preTest()
+
+	query := `query activity {
+		# comment
+		workloads(domain: "ns1") { id name svc: service groups { name } }
+		conversations { from { id } to { id } bytes }
+	}`
+	fields, err := parseGraphQL(query)
+	if err != nil {
+		t.Fatalf("Failed to parse query: %v", err)
+	}
+	if len(fields) != 2 || fields[0].name != "workloads" || fields[0].arg("domain") != "ns1" || len(fields[0].fields) != 4 {
+		t.Errorf("Unexpected fields: %+v", fields)
+	}
+	if f := fields[0].fields[2]; f.alias != "svc" || f.name != "service" || f.fields != nil {
+		t.Errorf("Unexpected alias field: %+v", f)
+	}
+	if f := fields[1].fields[0]; f.name != "from" || len(f.fields) != 1 {
+		t.Errorf("Unexpected nested field: %+v", f)
+	}
+
+	invalid := []string{
+		`{ workloads { id }`,
+		`{ workloads { } }`,
+		`mutation { groups { name } }`,
+		`query q($d: String) { workloads(domain: $d) { id } }`,
+		`{ workloads { ...wl } }`,
+		`{ groups { name } } { workloads { id } }`,
+		`{ a { b { c { d { e { f { g { h { i } } } } } } } } }`,
+	}
+	for _, q := range invalid {
+		if _, err := parseGraphQL(q); err == nil {
+			t.Errorf("Invalid query is parsed: %s", q)
+		}
+	}
+
+	postTest()
+}
+
+func TestGraphQLGroups(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	initKvAndCache(&mockCluster, nil, []*share.CLUSGroup{
+		&share.CLUSGroup{Name: "g1", Domain: "ns1", CfgType: share.UserCreated},
+		&share.CLUSGroup{Name: "g2", Domain: "ns2", CfgType: share.UserCreated},
+	})
+
+	query := func(q string, roles map[string][]string) (int, *api.RESTGraphQLData) {
+		body, _ := json.Marshal(&api.RESTGraphQLQuery{Query: q})
+		var w *mockResponseWriter
+		if roles == nil {
+			w = restCall("POST", "/v1/graphql", body, api.UserRoleReader)
+		} else {
+			w = restCallWithRole("POST", "/v1/graphql", body, api.UserRoleNone, roles)
+		}
+		var resp api.RESTGraphQLData
+		json.Unmarshal(w.body, &resp)
+		return w.status, &resp
+	}
+
+	status, resp := query(`{ groups(name: "g1") { name ns: domain } }`, nil)
+	if status != http.StatusOK || len(resp.Errors) != 0 {
+		t.Fatalf("Failed to query groups: status=%v, errors=%+v", status, resp.Errors)
+	}
+	groups, _ := resp.Data["groups"].([]interface{})
+	if len(groups) != 1 {
+		t.Fatalf("Unexpected groups: %+v", resp.Data)
+	}
+	if g := groups[0].(map[string]interface{}); len(g) != 2 || g["name"] != "g1" || g["ns"] != "ns1" {
+		t.Errorf("Unexpected group: %+v", g)
+	}
+
+	// Groups are authorized like in REST API
+	status, resp = query(`{ groups { name } }`, map[string][]string{api.UserRoleReader: []string{"ns2"}})
+	if groups, _ = resp.Data["groups"].([]interface{}); status != http.StatusOK || len(groups) != 1 {
+		t.Errorf("Unexpected groups for namespace user: status=%v, data=%+v", status, resp.Data)
+	}
+
+	// Field errors are reported without failing other fields
+	status, resp = query(`{ groups { name } hosts { id } }`, nil)
+	if status != http.StatusOK || len(resp.Errors) != 1 || resp.Errors[0].Path[0] != "hosts" || resp.Data["groups"] == nil {
+		t.Errorf("Unexpected response for unknown field: status=%v, resp=%+v", status, resp)
+	}
+	status, resp = query(`{ groups { name { first } } }`, nil)
+	if status != http.StatusOK || len(resp.Errors) != 1 {
+		t.Errorf("Unexpected response for selection on scalar: status=%v, resp=%+v", status, resp)
+	}
+
+	if status, _ = query(`{ groups { name }`, nil); status != http.StatusBadRequest {
+		t.Errorf("Unexpected status for invalid query: %v", status)
+	}
+
+	postTest()
+}
@@ -140,6 +140,20 @@ func handlerGroupBrief(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 	restRespSuccess(w, r, &resp, acc, login, nil, "Get group brief list")
 }
 
+// Groups waiting to be promoted by the auto mode, with the elapsed and required time of the good probes
+func handlerGroupAutoModeList(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	resp := api.RESTAutoModeTransitionsData{Transitions: cacher.GetAutoModeTransitions(acc)}
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get auto mode transitions")
+}
+
 func handlerGroupList(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	query := restParseQuery(r)
 	if query.brief {
@@ -445,9 +459,59 @@ func validateGroupConfig(rg *api.RESTGroupConfig, create bool) (int, string) {
 			}
 		}
 	}
+	if rg.Template != nil && *rg.Template && (isReservedGroupName(rg.Name) || utils.IsGroupLearned(rg.Name)) {
+		e := "Reserved or learned group cannot be a template"
+		log.WithFields(log.Fields{"name": rg.Name}).Error(e)
+		return api.RESTErrInvalidRequest, e
+	}
+	if am := rg.AutoMode; am != nil {
+		if !utils.DoesGroupHavePolicyMode(rg.Name) || rg.Name == api.AllHostGroup {
+			e := "Auto mode is not supported by the group"
+			log.WithFields(log.Fields{"name": rg.Name}).Error(e)
+			return api.RESTErrInvalidRequest, e
+		}
+		if (am.ModeAutoD2MDuration != nil && *am.ModeAutoD2MDuration < 0) ||
+			(am.ModeAutoM2PDuration != nil && *am.ModeAutoM2PDuration < 0) {
+			e := "Invalid auto mode duration"
+			log.WithFields(log.Fields{"name": rg.Name}).Error(e)
+			return api.RESTErrInvalidRequest, e
+		}
+	}
 	return 0, ""
 }
 
+// Template group is inherited by the workloads it selects, address group has no member to inherit its rules
+func validateGroupTemplate(cg *share.CLUSGroup) (int, string) {
+	if cg.Template && cg.Kind == share.GroupKindAddress {
+		e := "Address group cannot be a template"
+		log.WithFields(log.Fields{"name": cg.Name}).Error(e)
+		return api.RESTErrInvalidRequest, e
+	}
+	return 0, ""
+}
+
+// The group rate limit is shared by the allow rules to the group, 0 removes the limit
+func setGroupRateLimit(cg *share.CLUSGroup, rg *api.RESTGroupConfig) (int, string) {
+	if rg.RateLimit != nil {
+		cg.RateLimit = *rg.RateLimit
+	}
+	if rg.RateLimitUnit != nil {
+		cg.RateLimitUnit = *rg.RateLimitUnit
+	}
+	if cg.RateLimit == 0 {
+		cg.RateLimitUnit = ""
+	} else if cg.RateLimitUnit != share.PolicyRateUnitBytes && cg.RateLimitUnit != share.PolicyRateUnitConnections {
+		e := "Invalid rate limit unit"
+		log.WithFields(log.Fields{"name": cg.Name, "unit": cg.RateLimitUnit}).Error(e)
+		return api.RESTErrInvalidRequest, e
+	}
+	return 0, ""
+}
+
+// Criteria keys other than these are the labels of the workload or namespace
+var groupObjectCriteriaKeys = utils.NewSet(share.CriteriaKeyImage, share.CriteriaKeyHost, share.CriteriaKeyWorkload,
+	share.CriteriaKeyService, share.CriteriaKeyAddress, share.CriteriaKeyDomain, share.CriteriaKeyNamespace, share.CriteriaKeyOwner)
+
 func validateGroupConfigCriteria(rg *api.RESTGroupConfig, acc *access.AccessControl) (int, string, bool) {
 	var hasAddrCT, hasObjCT bool
 	for _, ct := range *rg.Criteria {
@@ -457,7 +521,8 @@ func validateGroupConfigCriteria(rg *api.RESTGroupConfig, acc *access.AccessCont
 			return api.RESTErrInvalidRequest, e, hasAddrCT
 		}
 
-		if ct.Op != share.CriteriaOpEqual && ct.Op != share.CriteriaOpNotEqual && ct.Value == "" {
+		if ct.Op != share.CriteriaOpEqual && ct.Op != share.CriteriaOpNotEqual &&
+			ct.Op != share.CriteriaOpExist && ct.Op != share.CriteriaOpNotExist && ct.Value == "" {
 			e := fmt.Sprintf("Empty criteria value is only allowed for exact match (key: %s)", ct.Key)
 			log.WithFields(log.Fields{"key": ct.Key, "value": ct.Value}).Error(e)
 			return api.RESTErrInvalidRequest, e, hasAddrCT
@@ -470,11 +535,18 @@ func validateGroupConfigCriteria(rg *api.RESTGroupConfig, acc *access.AccessCont
 		}
 		if ct.Op != share.CriteriaOpEqual && ct.Op != share.CriteriaOpContains &&
 			ct.Op != share.CriteriaOpPrefix && ct.Op != share.CriteriaOpRegex &&
-			ct.Op != share.CriteriaOpNotEqual && ct.Op != share.CriteriaOpNotRegex {
+			ct.Op != share.CriteriaOpNotEqual && ct.Op != share.CriteriaOpNotRegex &&
+			ct.Op != share.CriteriaOpIn && ct.Op != share.CriteriaOpNotIn &&
+			ct.Op != share.CriteriaOpExist && ct.Op != share.CriteriaOpNotExist {
 			e := fmt.Sprintf("Invalid operation in criteria (key: %s, op: %s)", ct.Key, ct.Op)
 			log.WithFields(log.Fields{"key": ct.Key}).Error(e)
 			return api.RESTErrInvalidRequest, e, hasAddrCT
 		}
+		if (ct.Op == share.CriteriaOpExist || ct.Op == share.CriteriaOpNotExist) && groupObjectCriteriaKeys.Contains(ct.Key) {
+			e := fmt.Sprintf("Existence operation is only supported for label criteria (key: %s, op: %s)", ct.Key, ct.Op)
+			log.WithFields(log.Fields{"key": ct.Key}).Error(e)
+			return api.RESTErrInvalidRequest, e, hasAddrCT
+		}
 
 		kovStr := fmt.Sprintf("(key: %s, op: %s, value: %s)", ct.Key, ct.Op, ct.Value)
 		if ct.Op == share.CriteriaOpRegex || ct.Op == share.CriteriaOpNotRegex {
@@ -609,6 +681,17 @@ func handlerGroupCreate(w http.ResponseWriter, r *http.Request, ps httprouter.Pa
 	if rg.Comment != nil {
 		cg.Comment = *rg.Comment
 	}
+	if rg.Template != nil {
+		cg.Template = *rg.Template
+	}
+	if err, msg := setGroupRateLimit(&cg, rg); err > 0 {
+		restRespErrorMessage(w, http.StatusBadRequest, err, msg)
+		return
+	}
+	if err, msg := validateGroupTemplate(&cg); err > 0 {
+		restRespErrorMessage(w, http.StatusBadRequest, err, msg)
+		return
+	}
 
 	// Write group definition into key-value store. Make sure group doesn't exist.
 	if err := clusHelper.PutGroup(&cg, true); err != nil {
@@ -738,6 +821,37 @@ func handlerGroupConfig(w http.ResponseWriter, r *http.Request, ps httprouter.Pa
 	if rg.Comment != nil {
 		cg.Comment = *rg.Comment
 	}
+	if rg.Template != nil {
+		cg.Template = *rg.Template
+	}
+	if am := rg.AutoMode; am != nil {
+		if cg.AutoMode == nil {
+			cg.AutoMode = &share.CLUSGroupAutoMode{}
+		}
+		if am.Hold != nil {
+			cg.AutoMode.Hold = *am.Hold
+		}
+		if am.ModeAutoD2MDuration != nil {
+			cg.AutoMode.ModeAutoD2MDuration = *am.ModeAutoD2MDuration
+		}
+		if am.ModeAutoM2PDuration != nil {
+			cg.AutoMode.ModeAutoM2PDuration = *am.ModeAutoM2PDuration
+		}
+		if am.NoLearnDays != nil {
+			cg.AutoMode.NoLearnDays = *am.NoLearnDays
+		}
+		if *cg.AutoMode == (share.CLUSGroupAutoMode{}) {
+			cg.AutoMode = nil
+		}
+	}
+	if err, msg := setGroupRateLimit(cg, rg); err > 0 {
+		restRespErrorMessage(w, http.StatusBadRequest, err, msg)
+		return
+	}
+	if err, msg := validateGroupTemplate(cg); err > 0 {
+		restRespErrorMessage(w, http.StatusBadRequest, err, msg)
+		return
+	}
 
 	if !acc.Authorize(cg, nil) {
 		restRespAccessDenied(w, login)
@@ -841,6 +955,11 @@ func handlerGroupDelete(w http.ResponseWriter, r *http.Request, ps httprouter.Pa
 				return
 			}
 		}
+
+		// The network rules of the group are deleted with it
+		if cached.CfgType != api.CfgTypeFederal && len(cached.PolicyRules) > 0 && !checkPolicyChangeApproval(w, share.ScopeLocal) {
+			return
+		}
 	}
 
 	lock, err := clusHelper.AcquireLock(share.CLUSLockPolicyKey, clusterLockWait)
@@ -911,6 +1030,214 @@ func handlerGroupDelete(w http.ResponseWriter, r *http.Request, ps httprouter.Pa
 	restRespSuccess(w, r, nil, acc, login, nil, "Delete group")
 }
 
+// Validate one operation of a batch request. It returns the group to write for create/update operations
+func batchGroupOp(w http.ResponseWriter, i int, op *api.RESTGroupBatchOp, opNames utils.Set, acc *access.AccessControl, login *loginSession) (*share.CLUSGroup, error) {
+	badRequest := func(code int, msg string) error {
+		e := fmt.Errorf("Operation %d: %s", i, msg)
+		log.Error(e)
+		restRespErrorMessage(w, http.StatusBadRequest, code, e.Error())
+		return e
+	}
+
+	name := op.Name
+	if op.Op != api.BatchOpDelete {
+		if op.Config == nil {
+			return nil, badRequest(api.RESTErrInvalidRequest, "Missing group config")
+		}
+		name = op.Config.Name
+	}
+	if opNames.Contains(name) {
+		return nil, badRequest(api.RESTErrInvalidRequest, fmt.Sprintf("Group %s is in multiple operations", name))
+	}
+	opNames.Add(name)
+
+	accReadAll := access.NewReaderAccessControl()
+	switch op.Op {
+	case api.BatchOpCreate:
+		rg := op.Config
+		if rg.CfgType != "" && rg.CfgType != api.CfgTypeUserCreated {
+			return nil, badRequest(api.RESTErrOpNotAllowed, "Only user created groups can be created in batch")
+		}
+		cg := &share.CLUSGroup{
+			Name:           rg.Name,
+			CreaterDomains: acc.GetAdminDomains(share.PERMS_RUNTIME_POLICIES),
+			CfgType:        share.UserCreated,
+			Kind:           share.GroupKindContainer,
+		}
+		if !acc.Authorize(cg, nil) {
+			restRespAccessDenied(w, login)
+			return nil, common.ErrObjectAccessDenied
+		}
+		if code, msg := validateGroupConfig(rg, true); code > 0 {
+			return nil, badRequest(code, msg)
+		}
+		if rg.Criteria == nil || len(*rg.Criteria) == 0 {
+			return nil, badRequest(api.RESTErrInvalidRequest, "Group must have criteria")
+		} else if code, msg, _ := validateGroupConfigCriteria(rg, acc); code > 0 {
+			return nil, badRequest(code, msg)
+		}
+		if existing, _, _ := clusHelper.GetGroup(rg.Name, accReadAll); existing != nil {
+			return nil, badRequest(api.RESTErrDuplicateName, "Group already exists")
+		}
+		for _, ct := range *rg.Criteria {
+			cg.Criteria = append(cg.Criteria, share.CLUSCriteriaEntry{Key: ct.Key, Value: ct.Value, Op: ct.Op})
+			if ct.Key == share.CriteriaKeyAddress {
+				cg.Kind = share.GroupKindAddress
+			}
+		}
+		if rg.Comment != nil {
+			cg.Comment = *rg.Comment
+		}
+		return cg, nil
+	case api.BatchOpUpdate, api.BatchOpDelete:
+		cached, err := cacher.GetGroup(name, "", false, acc)
+		if cached == nil {
+			restRespNotFoundLogAccessDenied(w, login, err)
+			return nil, err
+		} else if cached.CfgType == api.CfgTypeGround || cached.CfgType == api.CfgTypeFederal {
+			return nil, badRequest(api.RESTErrOpNotAllowed, fmt.Sprintf("Group %s cannot be changed in batch", name))
+		}
+		for _, idx := range cached.PolicyRules {
+			if isSecurityPolicyID(idx) {
+				return nil, badRequest(api.RESTErrInvalidRequest, fmt.Sprintf("Group %s is referenced by SecurityRule", name))
+			}
+		}
+		if op.Op == api.BatchOpDelete {
+			if cached.Reserved || cached.Kind == share.GroupKindIPService {
+				return nil, badRequest(api.RESTErrInvalidRequest, fmt.Sprintf("Group %s cannot be deleted", name))
+			} else if cached.CfgType == api.CfgTypeLearned && len(cached.Members) > 0 {
+				return nil, badRequest(api.RESTErrInvalidRequest, "Learned group with members cannot be deleted")
+			}
+		} else {
+			rg := op.Config
+			if code, msg := validateGroupConfig(rg, false); code > 0 {
+				return nil, badRequest(code, msg)
+			}
+			if rg.Criteria != nil {
+				if len(*rg.Criteria) == 0 {
+					return nil, badRequest(api.RESTErrInvalidRequest, "Group must have criteria")
+				} else if code, msg, _ := validateGroupConfigCriteria(rg, acc); code > 0 {
+					return nil, badRequest(code, msg)
+				}
+			}
+		}
+
+		cg, _, _ := clusHelper.GetGroup(name, acc)
+		if cg == nil {
+			e := fmt.Errorf("Operation %d: Group %s doesn't exist", i, name)
+			log.Error(e)
+			restRespErrorMessage(w, http.StatusNotFound, api.RESTErrObjectNotFound, e.Error())
+			return nil, e
+		}
+		if op.Op == api.BatchOpUpdate {
+			// Unlike configuring one group, omitted criteria means it's not modified
+			if rg := op.Config; rg.Criteria != nil {
+				cg.Criteria = nil
+				cg.Kind = share.GroupKindContainer
+				for _, ct := range *rg.Criteria {
+					cg.Criteria = append(cg.Criteria, share.CLUSCriteriaEntry{Key: ct.Key, Value: ct.Value, Op: ct.Op})
+					if ct.Key == share.CriteriaKeyAddress {
+						cg.Kind = share.GroupKindAddress
+					}
+				}
+			}
+			if op.Config.Comment != nil {
+				cg.Comment = *op.Config.Comment
+			}
+		}
+		if !acc.Authorize(cg, nil) {
+			restRespAccessDenied(w, login)
+			return nil, common.ErrObjectAccessDenied
+		}
+		if op.Op == api.BatchOpDelete {
+			return nil, nil
+		}
+		return cg, nil
+	default:
+		return nil, badRequest(api.RESTErrInvalidRequest, fmt.Sprintf("Unsupported operation %s", op.Op))
+	}
+}
+
+// Create/update/delete local groups with one kv transaction, so policy is recalculated once for the whole batch.
+// Network and response rules referencing the deleted groups are deleted in the same transaction
+func handlerGroupBatch(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.HasRequiredPermissions() {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	// Read request
+	body, _ := ioutil.ReadAll(r.Body)
+	var rconf api.RESTGroupBatchData
+	err := json.Unmarshal(body, &rconf)
+	if err != nil || len(rconf.Ops) == 0 {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	lock, err := clusHelper.AcquireLock(share.CLUSLockPolicyKey, clusterLockWait)
+	if err != nil {
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFailLockCluster, err.Error())
+		return
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	// Validate all operations before writing anything
+	opNames := utils.NewSet()
+	writes := make([]*share.CLUSGroup, 0, len(rconf.Ops))
+	dels := make([]string, 0)
+	for i, op := range rconf.Ops {
+		if op == nil {
+			continue
+		}
+		cg, err := batchGroupOp(w, i, op, opNames, acc, login)
+		if err != nil {
+			return
+		}
+		if op.Op == api.BatchOpDelete {
+			dels = append(dels, op.Name)
+		} else {
+			writes = append(writes, cg)
+		}
+	}
+
+	txn := cluster.Transact()
+	defer txn.Close()
+
+	for _, cg := range writes {
+		clusHelper.PutGroupTxn(txn, cg)
+	}
+	if len(dels) > 0 {
+		kv.DeletePolicyByGroupsTxn(txn, dels)
+		kv.DeleteResponseRuleByGroupsTxn(txn, dels)
+		for _, name := range dels {
+			clusHelper.DeleteGroupTxn(txn, name)
+		}
+	}
+
+	if txn.Size() > cluster.KVTransactEntriesMax {
+		e := "Too many operations to apply atomically"
+		log.WithFields(log.Fields{"ops": len(rconf.Ops), "entries": txn.Size()}).Error(e)
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+		return
+	}
+	if ok, err := txn.Apply(); err != nil || !ok {
+		e := "Atomic write to the cluster failed"
+		log.WithFields(log.Fields{"ok": ok, "error": err}).Error(e)
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster, e)
+		return
+	}
+
+	restRespSuccess(w, r, nil, acc, login, &rconf, "Batch configure groups")
+}
+
 // This allows user to create a service and its process/file/network profile before
 // starting the containers in protect mode.
 func handlerServiceCreate(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
@@ -1655,6 +1982,10 @@ func handlerGroupCfgImport(w http.ResponseWriter, r *http.Request, ps httprouter
 		restRespAccessDenied(w, login)
 		return
 	}
+	// The network rules of the imported groups are replaced
+	if tid == "" && !checkPolicyChangeApproval(w, share.ScopeLocal) {
+		return
+	}
 
 	_importHandler(w, r, tid, share.IMPORT_TYPE_GROUP_POLICY, share.PREFIX_IMPORT_GROUP_POLICY, acc, login)
 }
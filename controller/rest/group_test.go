@@ -131,6 +131,92 @@ func TestGroupCreate(t *testing.T) {
 	postTest()
 }
 
+func TestGroupConfigRateLimit(t *testing.T) {
+	preTest()
+
+	accAdmin := access.NewAdminAccessControl()
+	criteria := []api.RESTCriteriaEntry{{Key: "image", Value: "redis", Op: share.CriteriaOpEqual}}
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(
+		[]*share.CLUSPolicyRule{},
+		[]*share.CLUSGroup{
+			&share.CLUSGroup{Name: "g1", CfgType: share.UserCreated,
+				Criteria: []share.CLUSCriteriaEntry{
+					share.CLUSCriteriaEntry{Key: "image", Value: "redis", Op: share.CriteriaOpEqual},
+				},
+			},
+		},
+	)
+	clusHelper = &mockCluster
+
+	mc := mockCache{
+		rules:  make(map[uint32]*api.RESTPolicyRule, 0),
+		groups: make(map[string]*api.RESTGroup, 0),
+	}
+	mc.groups["g1"] = &api.RESTGroup{
+		RESTGroupBrief: api.RESTGroupBrief{Name: "g1", CfgType: api.CfgTypeUserCreated},
+	}
+	cacher = &mc
+
+	// Set rate limit
+	{
+		rate := uint32(1000)
+		unit := share.PolicyRateUnitBytes
+		conf := api.RESTGroupConfig{Name: "g1", Criteria: &criteria, RateLimit: &rate, RateLimitUnit: &unit}
+		body, _ := json.Marshal(api.RESTGroupConfigData{Config: &conf})
+
+		w := restCall("PATCH", "/v1/group/g1", body, api.UserRoleAdmin)
+		if w.status != http.StatusOK {
+			t.Errorf("Set group rate limit: Status %v is not OK.", w.status)
+		}
+		if cg, _, _ := clusHelper.GetGroup("g1", accAdmin); cg == nil || cg.RateLimit != rate || cg.RateLimitUnit != unit {
+			t.Errorf("Set group rate limit: Fail! %+v", cg)
+		}
+	}
+
+	// Limit without unit is rejected
+	{
+		var mockCluster kv.MockCluster
+		mockCluster.Init([]*share.CLUSPolicyRule{}, []*share.CLUSGroup{})
+		clusHelper = &mockCluster
+
+		rate := uint32(1000)
+		conf := api.RESTGroupConfig{Name: "g2", Criteria: &criteria, RateLimit: &rate}
+		body, _ := json.Marshal(api.RESTGroupConfigData{Config: &conf})
+
+		w := restCall("POST", "/v1/group", body, api.UserRoleAdmin)
+		if w.status != http.StatusBadRequest {
+			t.Errorf("Group rate limit without unit: Status %v is not bad request.", w.status)
+		}
+	}
+
+	// Remove rate limit
+	{
+		mockCluster.Init(
+			[]*share.CLUSPolicyRule{},
+			[]*share.CLUSGroup{
+				&share.CLUSGroup{Name: "g1", CfgType: share.UserCreated, RateLimit: 1000, RateLimitUnit: share.PolicyRateUnitBytes},
+			},
+		)
+		clusHelper = &mockCluster
+
+		rate := uint32(0)
+		conf := api.RESTGroupConfig{Name: "g1", Criteria: &criteria, RateLimit: &rate}
+		body, _ := json.Marshal(api.RESTGroupConfigData{Config: &conf})
+
+		w := restCall("PATCH", "/v1/group/g1", body, api.UserRoleAdmin)
+		if w.status != http.StatusOK {
+			t.Errorf("Remove group rate limit: Status %v is not OK.", w.status)
+		}
+		if cg, _, _ := clusHelper.GetGroup("g1", accAdmin); cg == nil || cg.RateLimit != 0 || cg.RateLimitUnit != "" {
+			t.Errorf("Remove group rate limit: Fail! %+v", cg)
+		}
+	}
+
+	postTest()
+}
+
 func TestGroupCreateNegative(t *testing.T) {
 	preTest()
 
@@ -342,3 +428,59 @@ func TestGroupDelete(t *testing.T) {
 
 	postTest()
 }
+
+func TestGroupBatch(t *testing.T) {
+	preTest()
+
+	rule := share.CLUSPolicyRule{
+		ID: 100, From: "g1", To: "g2", Action: share.PolicyActionAllow,
+		Ports: api.PolicyPortAny, Applications: []uint32{}, CfgType: share.UserCreated,
+	}
+	ct := share.CLUSCriteriaEntry{Key: "image", Value: "redis", Op: share.CriteriaOpEqual}
+	var mockCluster kv.MockCluster
+	initKvAndCache(&mockCluster, []*share.CLUSPolicyRule{&rule}, []*share.CLUSGroup{
+		&share.CLUSGroup{Name: "g1", CfgType: share.UserCreated, Criteria: []share.CLUSCriteriaEntry{ct}},
+		&share.CLUSGroup{Name: "g2", CfgType: share.UserCreated, Criteria: []share.CLUSCriteriaEntry{ct}},
+	})
+
+	comment := "batch"
+	criteria := []api.RESTCriteriaEntry{api.RESTCriteriaEntry{Key: "address", Value: "1.2.3.4", Op: share.CriteriaOpEqual}}
+	data := api.RESTGroupBatchData{Ops: []*api.RESTGroupBatchOp{
+		&api.RESTGroupBatchOp{Op: api.BatchOpCreate, Config: &api.RESTGroupConfig{Name: "g3", Criteria: &criteria}},
+		&api.RESTGroupBatchOp{Op: api.BatchOpUpdate, Config: &api.RESTGroupConfig{Name: "g1", Comment: &comment}},
+		&api.RESTGroupBatchOp{Op: api.BatchOpCreate, Config: &api.RESTGroupConfig{Name: "g2", Criteria: &criteria}},
+	}}
+
+	// Creating an existing group fails the whole batch
+	body, _ := json.Marshal(data)
+	w := restCall("PATCH", "/v1/groups/batch", body, api.UserRoleAdmin)
+	if w.status != http.StatusBadRequest {
+		t.Errorf("Batch with existing group: unexpected status %v", w.status)
+	}
+	if cg, _, _ := clusHelper.GetGroup("g3", access.NewReaderAccessControl()); cg != nil {
+		t.Errorf("Group should not be created when batch fails")
+	}
+
+	data.Ops[2] = &api.RESTGroupBatchOp{Op: api.BatchOpDelete, Name: "g2"}
+	body, _ = json.Marshal(data)
+	w = restCall("PATCH", "/v1/groups/batch", body, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Fatalf("Batch groups: unexpected status %v", w.status)
+	}
+
+	accReadAll := access.NewReaderAccessControl()
+	if cg, _, _ := clusHelper.GetGroup("g3", accReadAll); cg == nil || cg.Kind != share.GroupKindAddress {
+		t.Errorf("Group is not created: %+v", cg)
+	}
+	if cg, _, _ := clusHelper.GetGroup("g1", accReadAll); cg == nil || cg.Comment != comment || len(cg.Criteria) != 1 {
+		t.Errorf("Group is not modified: %+v", cg)
+	}
+	if cg, _, _ := clusHelper.GetGroup("g2", accReadAll); cg != nil {
+		t.Errorf("Group is not deleted: %+v", cg)
+	}
+	if r, _ := clusHelper.GetPolicyRule(rule.ID); r != nil {
+		t.Errorf("Rule referencing the deleted group is not deleted: %+v", r)
+	}
+
+	postTest()
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/julienschmidt/httprouter"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/neuvector/neuvector/controller/api"
 	"github.com/neuvector/neuvector/share"
 )
 
@@ -24,3 +25,35 @@ func handlerInternalSystem(w http.ResponseWriter, r *http.Request, ps httprouter
 
 	restRespSuccess(w, r, resp, acc, login, nil, "Get internal system data")
 }
+
+// kv store size is collected by the lead controller in the kv prune cycle
+func handlerInternalKvStats(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	stats := clusHelper.GetKvStoreStats()
+	resp := api.RESTKvStoreStatsData{
+		Stats: &api.RESTKvStoreStats{
+			UpdatedAt:     stats.UpdatedAt,
+			Keys:          stats.Keys,
+			Size:          stats.Size,
+			SizeLimit:     stats.SizeLimit,
+			Prefixes:      make([]*api.RESTKvPrefixStats, len(stats.Prefixes)),
+			LastCompactAt: stats.LastCompactAt,
+			CompactedKeys: stats.CompactedKeys,
+		},
+	}
+	for i, ps := range stats.Prefixes {
+		resp.Stats.Prefixes[i] = &api.RESTKvPrefixStats{Prefix: ps.Prefix, Keys: ps.Keys, Size: ps.Size}
+	}
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get kv store stats")
+}
@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+)
+
+func kvKeyring2REST(keyring *share.CLUSKvKeyring) *api.RESTKvEncryption {
+	if keyring == nil {
+		return &api.RESTKvEncryption{DataKeys: make([]*api.RESTKvDataKey, 0)}
+	}
+	r := &api.RESTKvEncryption{
+		Enabled:   kv.IsKvEncryptionEnabled(),
+		Provider:  keyring.Provider,
+		KeyID:     keyring.KeyID,
+		ActiveKey: keyring.ActiveKey,
+		DataKeys:  make([]*api.RESTKvDataKey, len(keyring.DataKeys)),
+		RotatedAt: keyring.RotatedAt,
+	}
+	for i, dk := range keyring.DataKeys {
+		r.DataKeys[i] = &api.RESTKvDataKey{ID: dk.ID, CreatedAt: dk.CreatedAt}
+	}
+	return r
+}
+
+func handlerGetKvEncryption(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	resp := api.RESTKvEncryptionData{Encryption: kvKeyring2REST(kv.GetKvKeyring())}
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get kv encryption")
+}
+
+func handlerKvEncryptionRotate(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	keyring, count, err := kv.RotateKvEncryptionKey()
+	if err == kv.ErrKvEncryptionDisabled {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrOpNotAllowed, err.Error())
+		return
+	} else if err != nil {
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster, err.Error())
+		return
+	}
+	configLog(share.CLUSEvKvKeyRotated, login, fmt.Sprintf("Data key of kv encryption is rotated to %s, %d values re-encrypted", keyring.ActiveKey, count))
+
+	resp := api.RESTKvEncryptionData{Encryption: kvKeyring2REST(keyring), Reencrypted: count}
+	restRespSuccess(w, r, &resp, acc, login, nil, "Rotate kv encryption key")
+}
@@ -4,14 +4,23 @@ package rest
 import "C"
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/neuvector/neuvector/controller/access"
 	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/cache"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
 )
 
 func filterAndSort(data []interface{}, query *restQuery) []interface{} {
@@ -425,3 +434,246 @@ func handlerAuditList(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 
 	restRespSuccess(w, r, &resp, acc, login, nil, "Get audit list")
 }
+
+// Config audits are returned in the order they were recorded. Use f_seq=gt,{seq} to export the records after the last exported one
+func handlerConfigAuditList(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.HasGlobalPermissions(share.PERM_EVENTS, 0) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	query := restParseQuery(r)
+	query.sorts = append(query.sorts, restFieldSort{tag: "seq", asc: true})
+
+	audits, verified := cacher.GetConfigAudits(acc)
+
+	var data []interface{} = make([]interface{}, len(audits))
+	for i, d := range audits {
+		data[i] = d
+	}
+	data = filterAndSort(data, query)
+
+	resp := api.RESTConfigAuditsData{Audits: make([]*api.ConfigAudit, len(data)), Verified: verified}
+	for i, d := range data {
+		resp.Audits[i] = d.(*api.ConfigAudit)
+	}
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get config audit list")
+}
+
+const logStreamKeepAlive = time.Duration(time.Second * 30)
+
+var logStreamTypes utils.Set = utils.NewSet(
+	api.LogTypeActivity,
+	api.LogTypeEvent,
+	api.LogTypeThreat,
+	api.LogTypeIncident,
+	api.LogTypeViolation,
+	api.LogTypeAudit,
+)
+
+// Return the log to push if the caller is allowed to see it
+func authorizeStreamLog(entry *cache.LogStreamEntry, login *loginSession, acc *access.AccessControl,
+	users map[string]*share.CLUSUser) interface{} {
+
+	userGetter := func(u string) share.AccessObject {
+		if user, ok := users[u]; ok {
+			return user
+		} else {
+			return nil
+		}
+	}
+
+	switch rlog := entry.Log.(type) {
+	case *api.Event:
+		if !acc.Authorize(rlog, userGetter) {
+			// every user is allowed to see his/her own events
+			if entry.Type != api.LogTypeEvent || rlog.User != login.fullname {
+				return nil
+			}
+		}
+		return rlog
+	case *api.Threat:
+		if !acc.Authorize(rlog, nil) {
+			return nil
+		}
+		// Not to send packet for threat stream
+		t := *rlog
+		t.Packet = ""
+		return &t
+	case *api.Incident, *api.Violation, *api.Audit:
+		if !acc.Authorize(rlog.(share.AccessObject), nil) {
+			return nil
+		}
+		return rlog
+	}
+	return nil
+}
+
+// Push new logs to the caller as server-sent events until the caller disconnects or the login session ends
+func handlerLogStream(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		restRespError(w, http.StatusInternalServerError, api.RESTErrInvalidRequest)
+		return
+	}
+
+	types := utils.NewSet()
+	if value := r.URL.Query().Get(api.QueryKeyTypes); value != "" {
+		for _, t := range strings.Split(value, ",") {
+			if t = strings.TrimSpace(t); !logStreamTypes.Contains(t) {
+				e := fmt.Sprintf("Unsupported log type %s", t)
+				log.WithFields(log.Fields{"types": value}).Error(e)
+				restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+				return
+			}
+			types.Add(t)
+		}
+	} else {
+		types = logStreamTypes.Clone()
+	}
+
+	id, ch := cacher.SubscribeLogStream(types)
+	defer cacher.UnsubscribeLogStream(id)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	log.WithFields(log.Fields{"id": id, "user": login.fullname, "types": types}).Info("Log stream started")
+	defer log.WithFields(log.Fields{"id": id, "user": login.fullname}).Info("Log stream stopped")
+
+	users := clusHelper.GetAllUsers(acc)
+	ticker := time.NewTicker(logStreamKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if !isLoginSessionAlive(r, login) {
+				return
+			}
+			users = clusHelper.GetAllUsers(acc)
+			if _, err := w.Write([]byte(": keepalive\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+		case entry := <-ch:
+			rlog := authorizeStreamLog(entry, login, acc, users)
+			if rlog == nil {
+				continue
+			}
+			data, err := json.Marshal(rlog)
+			if err != nil {
+				log.WithFields(log.Fields{"type": entry.Type, "error": err}).Error()
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", entry.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func parseUnixTimeQuery(r *http.Request, key string) (time.Time, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	sec, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || sec < 0 {
+		return time.Time{}, fmt.Errorf("Invalid %s time %s", key, value)
+	}
+	return time.Unix(sec, 0), nil
+}
+
+// Export the persisted logs of a type as newline-delimited json, oldest first
+func handlerLogExport(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	query := r.URL.Query()
+	logType := query.Get(api.QueryKeyLogType)
+	if !logStreamTypes.Contains(logType) {
+		e := fmt.Sprintf("Unsupported log type %s", logType)
+		log.WithFields(log.Fields{"type": logType}).Error(e)
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+		return
+	}
+
+	start, err := parseUnixTimeQuery(r, api.QueryKeyStart)
+	if err != nil {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+		return
+	}
+	end, err := parseUnixTimeQuery(r, api.QueryKeyEnd)
+	if err != nil {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+		return
+	}
+	var limit int
+	if value := query.Get(api.QueryKeyLimit); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, fmt.Sprintf("Invalid limit %s", value))
+			return
+		}
+	}
+
+	exportStoredLogs(w, login, acc, logType, start, end, limit)
+}
+
+func exportStoredLogs(w http.ResponseWriter, login *loginSession, acc *access.AccessControl,
+	logType string, start, end time.Time, limit int) {
+
+	entries, err := cacher.GetStoredLogs(logType, start, end, limit)
+	if err == common.ErrUnsupported {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrOpNotAllowed, "Log store is not enabled")
+		return
+	} else if err != nil {
+		log.WithFields(log.Fields{"type": logType, "error": err}).Error("Failed to read stored logs")
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailReadCluster)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.ndjson", logType))
+	w.WriteHeader(http.StatusOK)
+
+	users := clusHelper.GetAllUsers(acc)
+	for _, entry := range entries {
+		rlog := authorizeStreamLog(entry, login, acc, users)
+		if rlog == nil {
+			continue
+		}
+		data, err := json.Marshal(rlog)
+		if err != nil {
+			continue
+		}
+		if _, err := w.Write(append(data, '\n')); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package rest
+
+// Generate the mandatory access control profiles, AppArmor and SELinux, of a group
+// from its learned process profile and file monitor profile
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+// convert a process profile path into an AppArmor glob, the ending "/*" is a recursive directory
+func appArmorProcessGlob(path string) string {
+	if path == "" || path == "*" || path == "/*" {
+		return "/**"
+	}
+	path = strings.Replace(path, "/*/", "/**/", 1)
+	if strings.HasSuffix(path, "/*") {
+		return path + "*"
+	}
+	return path
+}
+
+// convert a file monitor filter into an AppArmor glob
+func appArmorFilterGlob(filter string, recursive bool) string {
+	if !recursive {
+		return filter
+	}
+	dir, base := filepath.Split(filter)
+	if strings.HasPrefix(base, "*") { // "/etc/*" => "/etc/**", "/var/log/*.log" => "/var/log/**.log"
+		return dir + "*" + base
+	}
+	return filter + "{,/**}"
+}
+
+// convert a path glob into the regular expression of a SELinux file context
+func selinuxPathRegex(path string, recursive bool) string {
+	conv := func(p string) string {
+		p = strings.Replace(p, ".", `\.`, -1)
+		p = strings.Replace(p, "*", "[^/]*", -1)
+		return strings.Replace(p, "/[^/]*/", "/.*/", 1)
+	}
+
+	if recursive {
+		dir, base := filepath.Split(path)
+		if strings.HasPrefix(base, "*") { // any file under the directory
+			return conv(dir) + ".*" + conv(base[1:])
+		}
+		return conv(path) + "(/.*)?"
+	}
+	return conv(path)
+}
+
+func macProfileName(group string) string {
+	if !strings.HasPrefix(group, api.LearnedGroupPrefix) {
+		group = api.LearnedGroupPrefix + group
+	}
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '.' {
+			return r
+		}
+		return '_'
+	}, group)
+}
+
+func buildAppArmorProfile(group string, procs []*api.RESTProcessProfileEntry, filters []*api.RESTFileMonitorFilter) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# AppArmor profile generated from the learned behavior of group %s\n", group))
+	sb.WriteString("#include <tunables/global>\n\n")
+	sb.WriteString(fmt.Sprintf("profile %s flags=(attach_disconnected,mediate_deleted) {\n", macProfileName(group)))
+	sb.WriteString("  #include <abstractions/base>\n\n")
+	sb.WriteString("  network,\n  capability,\n  signal (receive) peer=unconfined,\n\n")
+	sb.WriteString("  # files: everything but execution\n")
+	sb.WriteString("  /** rwlkm,\n")
+	sb.WriteString("  deny @{PROC}/sys/kernel/** w,\n")
+	sb.WriteString("  deny /sys/[^f]*/** w,\n\n")
+
+	allows := make([]string, 0)
+	denies := make([]string, 0)
+	for _, proc := range procs {
+		glob := appArmorProcessGlob(proc.Path)
+		switch proc.Action {
+		case share.PolicyActionAllow:
+			allows = append(allows, fmt.Sprintf("  %s ix, # %s\n", glob, proc.Name))
+		case share.PolicyActionDeny:
+			denies = append(denies, fmt.Sprintf("  deny %s x, # %s\n", glob, proc.Name))
+		}
+	}
+	sort.Strings(allows)
+	sort.Strings(denies)
+	sb.WriteString("  # processes\n")
+	for _, line := range allows {
+		sb.WriteString(line)
+	}
+	for _, line := range denies {
+		sb.WriteString(line)
+	}
+
+	if len(filters) > 0 {
+		sb.WriteString("\n  # monitored files\n")
+		for _, ff := range filters {
+			glob := appArmorFilterGlob(ff.Filter, ff.Recursive)
+			if ff.Behavior == share.FileAccessBehaviorBlock && len(ff.Apps) == 0 {
+				sb.WriteString(fmt.Sprintf("  deny %s w,\n", glob))
+			} else if len(ff.Apps) > 0 {
+				sb.WriteString(fmt.Sprintf("  audit %s w, # %s\n", glob, strings.Join(ff.Apps, ", ")))
+			} else {
+				sb.WriteString(fmt.Sprintf("  audit %s w,\n", glob))
+			}
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func buildSELinuxPolicy(group string, procs []*api.RESTProcessProfileEntry, filters []*api.RESTFileMonitorFilter) string {
+	var sb strings.Builder
+
+	module := strings.Replace(strings.Replace(macProfileName(group), ".", "_", -1), "-", "_", -1)
+	domain := module + "_t"
+	exec := module + "_exec_t"
+	data := module + "_file_t"
+
+	sb.WriteString(fmt.Sprintf("# SELinux type enforcement suggestions generated from the learned behavior of group %s\n", group))
+	sb.WriteString("# review the rules and the file contexts before loading the module\n")
+	sb.WriteString(fmt.Sprintf("policy_module(%s, 1.0)\n\n", module))
+	sb.WriteString("gen_require(`\n\ttype container_t;\n\ttype container_file_t;\n')\n\n")
+	sb.WriteString(fmt.Sprintf("type %s;\ndomain_type(%s)\ntypeattribute %s container_domain;\n\n", domain, domain, domain))
+	sb.WriteString(fmt.Sprintf("type %s;\nfiles_type(%s)\n", exec, exec))
+	sb.WriteString(fmt.Sprintf("type %s;\nfiles_type(%s)\n\n", data, data))
+
+	sb.WriteString("# learned processes\n")
+	sb.WriteString(fmt.Sprintf("allow %s container_file_t:file { getattr open read map };\n", domain))
+	sb.WriteString(fmt.Sprintf("allow %s %s:file { getattr open read map execute execute_no_trans };\n", domain, exec))
+	fcs := make([]string, 0)
+	for _, proc := range procs {
+		if proc.Path == "" || proc.Path == "*" || proc.Path == "/*" {
+			sb.WriteString(fmt.Sprintf("# %s: %s from any path, no file context\n", proc.Action, proc.Name))
+			continue
+		}
+		switch proc.Action {
+		case share.PolicyActionAllow:
+			fcs = append(fcs, fmt.Sprintf("%s\t--\tgen_context(system_u:object_r:%s,s0)", selinuxPathRegex(proc.Path, strings.HasSuffix(proc.Path, "/*")), exec))
+		case share.PolicyActionDeny:
+			sb.WriteString(fmt.Sprintf("# deny: %s, keep it out of %s\n", proc.Path, exec))
+		}
+	}
+
+	if len(filters) > 0 {
+		sb.WriteString("\n# monitored files\n")
+		sb.WriteString(fmt.Sprintf("allow %s %s:file { getattr open read map };\n", domain, data))
+		var writable bool
+		for _, ff := range filters {
+			if ff.Behavior == share.FileAccessBehaviorBlock && len(ff.Apps) == 0 {
+				sb.WriteString(fmt.Sprintf("# read only: %s\n", ff.Filter))
+			} else {
+				writable = true
+			}
+			fcs = append(fcs, fmt.Sprintf("%s\t\tgen_context(system_u:object_r:%s,s0)", selinuxPathRegex(ff.Filter, ff.Recursive), data))
+		}
+		if writable {
+			sb.WriteString(fmt.Sprintf("auditallow %s %s:file { write append };\n", domain, data))
+		}
+	}
+
+	sort.Strings(fcs)
+	sb.WriteString(fmt.Sprintf("\n# file contexts (%s.fc)\n", module))
+	for _, fc := range fcs {
+		sb.WriteString("# " + fc + "\n")
+	}
+	return sb.String()
+}
+
+func handlerGroupProfilesExport(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	query := restParseQuery(r)
+	profileType, ok := query.pairs[api.QueryKeyProfileType]
+	if !ok {
+		profileType = api.QueryValueProfileAppArmor
+	}
+	if profileType != api.QueryValueProfileAppArmor && profileType != api.QueryValueProfileSELinux {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, fmt.Sprintf("Unsupported profile type: %s", profileType))
+		return
+	}
+
+	group := ps.ByName("name")
+	if grp, err := cacher.GetGroupBrief(group, false, acc); err == nil {
+		if !isValidKindProcessProfile(grp.Kind) {
+			log.WithFields(log.Fields{"group": group, "kind": grp.Kind}).Error("Invalid group kind")
+			restRespError(w, http.StatusBadRequest, api.RESTErrObjectNotFound)
+			return
+		}
+	} else {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+
+	profile, err := cacher.GetProcessProfile(group, acc)
+	if profile == nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+
+	var filters []*api.RESTFileMonitorFilter
+	if mon, err := cacher.GetFileMonitorProfile(group, acc, true); err == nil {
+		filters = mon.Filters
+	}
+
+	var data, filename string
+	if profileType == api.QueryValueProfileSELinux {
+		data = buildSELinuxPolicy(group, profile.ProcessList, filters)
+		filename = macProfileName(group) + ".te"
+	} else {
+		data = buildAppArmorProfile(group, profile.ProcessList, filters)
+		filename = macProfileName(group)
+	}
+
+	// tell the browser the returned content should be downloaded
+	w.Header().Set("Content-Disposition", "Attachment; filename="+filename)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(data))
+}
@@ -0,0 +1,120 @@
+package rest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestMacProfilePath(t *testing.T) {
+	procs := map[string]string{
+		"":                 "/**",
+		"*":                "/**",
+		"/usr/bin/nginx":   "/usr/bin/nginx",
+		"/usr/bin/*":       "/usr/bin/**",
+		"/usr/*/nginx":     "/usr/**/nginx",
+		"/opt/app/bin/run": "/opt/app/bin/run",
+	}
+	for path, glob := range procs {
+		if g := appArmorProcessGlob(path); g != glob {
+			t.Errorf("process %v: expect %v, actual %v\n", path, glob, g)
+		}
+	}
+
+	type filterCase struct {
+		filter    string
+		recursive bool
+		glob      string
+		regex     string
+	}
+	filters := []filterCase{
+		{"/etc/passwd", false, "/etc/passwd", `/etc/passwd`},
+		{"/etc/*", false, "/etc/*", `/etc/[^/]*`},
+		{"/etc/*", true, "/etc/**", `/etc/.*`},
+		{"/var/log/*.log", true, "/var/log/**.log", `/var/log/.*\.log`},
+		{"/etc/ssl", true, "/etc/ssl{,/**}", `/etc/ssl(/.*)?`},
+	}
+	for _, c := range filters {
+		if g := appArmorFilterGlob(c.filter, c.recursive); g != c.glob {
+			t.Errorf("filter %v: expect %v, actual %v\n", c.filter, c.glob, g)
+		}
+		if re := selinuxPathRegex(c.filter, c.recursive); re != c.regex {
+			t.Errorf("filter %v: expect %v, actual %v\n", c.filter, c.regex, re)
+		}
+	}
+}
+
+func TestGroupProfilesExport(t *testing.T) {
+	preTest()
+
+	mc := mockCache{
+		groups:   make(map[string]*api.RESTGroup),
+		profiles: make(map[string][]*api.RESTProcessProfileEntry),
+		filters:  make(map[string][]*api.RESTFileMonitorFilter),
+	}
+
+	mc.groups["nv.web.default"] = &api.RESTGroup{
+		RESTGroupBrief: api.RESTGroupBrief{
+			Name: "nv.web.default",
+			Kind: share.GroupKindContainer,
+		},
+	}
+	mc.profiles["nv.web.default"] = []*api.RESTProcessProfileEntry{
+		{Name: "nginx", Path: "/usr/sbin/nginx", Action: share.PolicyActionAllow},
+		{Name: "curl", Path: "/usr/bin/curl", Action: share.PolicyActionDeny},
+	}
+	mc.filters["nv.web.default"] = []*api.RESTFileMonitorFilter{
+		{Filter: "/etc/nginx/*", Recursive: true, Behavior: share.FileAccessBehaviorBlock, Apps: []string{"nginx"}},
+		{Filter: "/etc/shadow", Behavior: share.FileAccessBehaviorBlock},
+	}
+	cacher = &mc
+
+	// AppArmor, by default
+	{
+		w := restCall("GET", "/v1/group/nv.web.default/profiles/export", nil, api.UserRoleAdmin)
+		if w.status != http.StatusOK {
+			t.Errorf("Export AppArmor profile failed: status=%v\n", w.status)
+		}
+		body := string(w.body)
+		for _, rule := range []string{"profile nv.web.default ", "/usr/sbin/nginx ix,", "deny /usr/bin/curl x,", "audit /etc/nginx/** w,", "deny /etc/shadow w,"} {
+			if !strings.Contains(body, rule) {
+				t.Errorf("Missing AppArmor rule: %v\n%v\n", rule, body)
+			}
+		}
+	}
+
+	// SELinux
+	{
+		w := restCall("GET", "/v1/group/nv.web.default/profiles/export?type=selinux", nil, api.UserRoleAdmin)
+		if w.status != http.StatusOK {
+			t.Errorf("Export SELinux policy failed: status=%v\n", w.status)
+		}
+		body := string(w.body)
+		for _, rule := range []string{"policy_module(nv_web_default, 1.0)", `/usr/sbin/nginx	--	gen_context(system_u:object_r:nv_web_default_exec_t,s0)`, "auditallow nv_web_default_t nv_web_default_file_t:file"} {
+			if !strings.Contains(body, rule) {
+				t.Errorf("Missing SELinux rule: %v\n%v\n", rule, body)
+			}
+		}
+	}
+
+	// Unsupported type
+	{
+		w := restCall("GET", "/v1/group/nv.web.default/profiles/export?type=smack", nil, api.UserRoleAdmin)
+		if w.status != http.StatusBadRequest {
+			t.Errorf("Export unsupported profile type: status=%v\n", w.status)
+		}
+	}
+
+	// Non-existing group
+	{
+		w := restCall("GET", "/v1/group/nv.nothing/profiles/export", nil, api.UserRoleAdmin)
+		if w.status != http.StatusNotFound {
+			t.Errorf("Export non-existing group: status=%v\n", w.status)
+		}
+	}
+
+	postTest()
+}
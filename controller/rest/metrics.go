@@ -0,0 +1,204 @@
+package rest
+
+// Prometheus exporter of the security posture. The metrics are served in the text exposition format on a
+// dedicated port, so they can be scraped without a REST API session.
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+const metricsPath = "/metrics"
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var _metricsPort uint
+
+type metricsWriter struct {
+	buf bytes.Buffer
+}
+
+var metricsLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func (w *metricsWriter) family(name, typ, help string) {
+	fmt.Fprintf(&w.buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// Labels are given as key and value pairs
+func (w *metricsWriter) sample(name string, value float64, labels ...string) {
+	w.buf.WriteString(name)
+	if len(labels) > 0 {
+		w.buf.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			fmt.Fprintf(&w.buf, `%s="%s"`, labels[i], metricsLabelEscaper.Replace(labels[i+1]))
+		}
+		w.buf.WriteByte('}')
+	}
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	w.buf.WriteByte('\n')
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeSecurityMetrics(w *metricsWriter, acc *access.AccessControl) {
+	m := cacher.GetSecurityMetrics(acc)
+
+	w.family("neuvector_workload_vulnerabilities", "gauge", "Vulnerabilities of the running containers by namespace and severity")
+	namespaces := make([]string, 0, len(m.WorkloadVuls))
+	for ns := range m.WorkloadVuls {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	for _, ns := range namespaces {
+		vuls := m.WorkloadVuls[ns]
+		for _, sev := range sortedKeys(vuls) {
+			w.sample("neuvector_workload_vulnerabilities", float64(vuls[sev]), "namespace", ns, "severity", sev)
+		}
+	}
+
+	w.family("neuvector_host_vulnerabilities", "gauge", "Vulnerabilities of the nodes by severity")
+	for _, sev := range sortedKeys(m.HostVuls) {
+		w.sample("neuvector_host_vulnerabilities", float64(m.HostVuls[sev]), "severity", sev)
+	}
+
+	w.family("neuvector_policy_mode_pods", "gauge", "Running pods by the policy mode of their groups")
+	for _, mode := range sortedKeys(m.PolicyModes) {
+		w.sample("neuvector_policy_mode_pods", float64(m.PolicyModes[mode]), "mode", mode)
+	}
+
+	w.family("neuvector_violations_total", "counter", "Network violations since the controller started")
+	w.sample("neuvector_violations_total", float64(m.Violations))
+	w.family("neuvector_threats_total", "counter", "Threats since the controller started")
+	w.sample("neuvector_threats_total", float64(m.Threats))
+	w.family("neuvector_incidents_total", "counter", "Security incidents since the controller started")
+	w.sample("neuvector_incidents_total", float64(m.Incidents))
+	w.family("neuvector_admission_denials_total", "counter", "Denied admission requests since the controller started")
+	w.sample("neuvector_admission_denials_total", float64(m.AdmDenials))
+}
+
+func writeScanMetrics(w *metricsWriter, acc *access.AccessControl) {
+	scanners, _, _ := cacher.GetScannerCount(acc)
+	w.family("neuvector_scanners", "gauge", "Available scanners")
+	w.sample("neuvector_scanners", float64(scanners))
+
+	if status, err := cacher.GetScanStatus(acc); err == nil {
+		w.family("neuvector_scan_backlog", "gauge", "Workload and node scans waiting for or being processed by the scanners")
+		w.sample("neuvector_scan_backlog", float64(status.Scheduled), "state", "scheduled")
+		w.sample("neuvector_scan_backlog", float64(status.Scanning), "state", "scanning")
+	}
+}
+
+func writeFedMetrics(w *metricsWriter, acc *access.AccessControl) {
+	switch cacher.GetFedMembershipRoleNoAuth() {
+	case api.FedRoleMaster:
+		ids := cacher.GetFedJoinedClusterIdMap(acc)
+		w.family("neuvector_fed_cluster_synced", "gauge", "Whether the managed cluster is in sync with the primary cluster")
+		w.family("neuvector_fed_cluster_status", "gauge", "Status of the managed cluster")
+		names := make(map[string]string, len(ids))
+		for id := range ids {
+			names[id] = cacher.GetFedJoinedCluster(id, acc).Name
+		}
+		for _, id := range sortedIDs(ids) {
+			status := cacher.GetFedJoinedClusterStatus(id, acc).Status
+			var synced float64
+			if status == _fedClusterSynced {
+				synced = 1
+			}
+			w.sample("neuvector_fed_cluster_synced", synced, "cluster", names[id])
+			w.sample("neuvector_fed_cluster_status", 1, "cluster", names[id], "status", fedStatusString(status))
+		}
+	case api.FedRoleJoint:
+		master := cacher.GetFedMasterCluster(acc)
+		status := cacher.GetFedJoinedClusterStatus(master.ID, acc).Status
+		var connected float64
+		if status == _fedClusterConnected {
+			connected = 1
+		}
+		w.family("neuvector_fed_primary_connected", "gauge", "Whether the primary cluster is connected")
+		w.sample("neuvector_fed_primary_connected", connected, "cluster", master.Name)
+		w.family("neuvector_fed_primary_last_sync_seconds", "gauge", "Seconds since the last successful polling of the primary cluster")
+		w.sample("neuvector_fed_primary_last_sync_seconds", time.Since(_lastMasterHeartbeatTime).Seconds(), "cluster", master.Name)
+	}
+}
+
+func sortedIDs(ids map[string]bool) []string {
+	list := make([]string, 0, len(ids))
+	for id := range ids {
+		list = append(list, id)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func fedStatusString(status int) string {
+	if s, ok := _clusterStatusMap[status]; ok && s != "" {
+		return s
+	}
+	return "unknown"
+}
+
+func handlerMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != metricsPath {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	acc := access.NewReaderAccessControl()
+	var mw metricsWriter
+	writeSecurityMetrics(&mw, acc)
+	writeScanMetrics(&mw, acc)
+	writeFedMetrics(&mw, acc)
+
+	w.Header().Set("Content-Type", metricsContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(mw.buf.Bytes())
+}
+
+func startMetricsServer(port uint) {
+	log.WithFields(log.Fields{"port": port}).Info("Start metrics server")
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: http.HandlerFunc(handlerMetrics),
+		TLSConfig: &tls.Config{
+			MinVersion:               tls.VersionTLS11,
+			PreferServerCipherSuites: true,
+			CipherSuites:             utils.GetSupportedTLSCipherSuites(),
+		},
+		TLSNextProto: make(map[string]func(*http.Server, *tls.Conn, http.Handler), 0), // disable http/2
+	}
+	for {
+		if err := server.ListenAndServeTLS(defaultSSLCertFile, defaultSSLKeyFile); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Fail to start metrics server")
+			time.Sleep(time.Second * 5)
+		} else {
+			break
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+)
+
+type metricsMockCache struct {
+	mockCache
+}
+
+func (m *metricsMockCache) GetSecurityMetrics(acc *access.AccessControl) *common.SecurityMetrics {
+	return &common.SecurityMetrics{
+		WorkloadVuls: map[string]map[string]int{
+			"prod":     {share.VulnSeverityHigh: 3, share.VulnSeverityMedium: 5},
+			`de"fault`: {share.VulnSeverityHigh: 1},
+		},
+		HostVuls:    map[string]int{share.VulnSeverityHigh: 2},
+		PolicyModes: map[string]int{share.PolicyModeEnforce: 4, share.PolicyModeLearn: 1},
+		Violations:  7,
+		AdmDenials:  2,
+	}
+}
+
+func (m *metricsMockCache) GetScannerCount(acc *access.AccessControl) (int, string, string) {
+	return 2, "", ""
+}
+
+func (m *metricsMockCache) GetScanStatus(acc *access.AccessControl) (*api.RESTScanStatus, error) {
+	return &api.RESTScanStatus{Scheduled: 10, Scanning: 2, Scanned: 30}, nil
+}
+
+func (m *metricsMockCache) GetFedJoinedClusterIdMap(acc *access.AccessControl) map[string]bool {
+	return map[string]bool{"id1": false, "id2": false}
+}
+
+func (m *metricsMockCache) GetFedJoinedCluster(id string, acc *access.AccessControl) share.CLUSFedJointClusterInfo {
+	return share.CLUSFedJointClusterInfo{ID: id, Name: "cluster-" + id}
+}
+
+func (m *metricsMockCache) GetFedJoinedClusterStatus(id string, acc *access.AccessControl) share.CLUSFedClusterStatus {
+	if id == "id1" {
+		return share.CLUSFedClusterStatus{Status: _fedClusterSynced}
+	}
+	return share.CLUSFedClusterStatus{Status: _fedClusterDisconnected}
+}
+
+func TestMetrics(t *testing.T) {
+	preTest()
+
+	cacher = &metricsMockCache{}
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, metricsPath, nil)
+	handlerMetrics(w, r)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != metricsContentType {
+		t.Fatalf("Unexpected response: status=%v header=%+v", w.Code, w.Header())
+	}
+
+	body := w.Body.String()
+	for _, line := range []string{
+		"# TYPE neuvector_workload_vulnerabilities gauge",
+		`neuvector_workload_vulnerabilities{namespace="de\"fault",severity="High"} 1`,
+		`neuvector_workload_vulnerabilities{namespace="prod",severity="Medium"} 5`,
+		`neuvector_host_vulnerabilities{severity="High"} 2`,
+		`neuvector_policy_mode_pods{mode="Protect"} 4`,
+		"# TYPE neuvector_violations_total counter",
+		"neuvector_violations_total 7",
+		"neuvector_threats_total 0",
+		"neuvector_admission_denials_total 2",
+		"neuvector_scanners 2",
+		`neuvector_scan_backlog{state="scheduled"} 10`,
+		`neuvector_fed_cluster_synced{cluster="cluster-id1"} 1`,
+		`neuvector_fed_cluster_synced{cluster="cluster-id2"} 0`,
+		`neuvector_fed_cluster_status{cluster="cluster-id2",status="disconnected"} 1`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("Missing metric: %s\n%s", line, body)
+		}
+	}
+
+	// namespaces are sorted
+	if strings.Index(body, `namespace="de\"fault"`) > strings.Index(body, `namespace="prod"`) {
+		t.Errorf("Metrics are not sorted:\n%s", body)
+	}
+
+	w = httptest.NewRecorder()
+	r, _ = http.NewRequest(http.MethodGet, "/v1/metrics", nil)
+	handlerMetrics(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Unexpected status: %v", w.Code)
+	}
+
+	postTest()
+}
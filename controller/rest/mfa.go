@@ -0,0 +1,358 @@
+package rest
+
+// TOTP (RFC 6238) based multi-factor authentication for local users
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+const (
+	mfaSecretSize      = 20 // 160 bits, as recommended by RFC 4226
+	mfaTOTPPeriod      = 30
+	mfaTOTPDigits      = 6
+	mfaTOTPSkew        = 1 // accept codes of the adjacent time steps for clock drift
+	mfaBackupCodeCount = 10
+	mfaIssuer          = "NeuVector"
+)
+
+var errMFARequired = errors.New("MFA code is required")
+
+var mfaBase32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func totpCode(secret []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", mfaTOTPDigits, code%1000000)
+}
+
+// Return the time step that the code matches, or -1
+func totpVerify(secret []byte, code string, now time.Time) int64 {
+	step := now.Unix() / mfaTOTPPeriod
+	for i := int64(-mfaTOTPSkew); i <= mfaTOTPSkew; i++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(secret, step+i)), []byte(code)) == 1 {
+			return step + i
+		}
+	}
+	return -1
+}
+
+func getUserMFASecret(user *share.CLUSUser) []byte {
+	if user.MFASecret == "" {
+		return nil
+	}
+	secret, err := mfaBase32.DecodeString(common.DecryptCloaked(user.MFASecret))
+	if err != nil {
+		log.WithFields(log.Fields{"user": user.Fullname, "error": err}).Error("Invalid MFA secret")
+		return nil
+	}
+	return secret
+}
+
+// Verify the TOTP code or one of the backup codes. The user is modified so the same code cannot be used again,
+// caller should write the user to the cluster.
+func verifyUserMFACode(user *share.CLUSUser, code string, now time.Time) bool {
+	code = strings.Replace(code, " ", "", -1)
+	if secret := getUserMFASecret(user); secret != nil {
+		if step := totpVerify(secret, code, now); step > user.MFALastStep {
+			user.MFALastStep = step
+			return true
+		}
+	}
+
+	hash := utils.HashPassword(code)
+	for i, h := range user.MFABackupCodes {
+		if h == hash {
+			user.MFABackupCodes = append(user.MFABackupCodes[:i], user.MFABackupCodes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func isMFARequiredRole(role string) bool {
+	profile, _ := cacher.GetPwdProfile(share.CLUSSysPwdProfileName)
+	for _, r := range profile.MFARequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Local user who hasn't enrolled MFA but is required to by the password profile
+func isMFAEnrollRequired(user *share.CLUSUser) bool {
+	if user.Server != "" || user.MFAEnabled {
+		return false
+	}
+	return isMFARequiredRole(user.Role)
+}
+
+// The session of the user who must enroll MFA can only be used for the enrollment
+func isMFAEnrollRequest(r *http.Request, login *loginSession) bool {
+	switch r.URL.Path {
+	case "/v1/auth", "/v1/selfuser":
+		return true
+	}
+	path := fmt.Sprintf("/v1/user/%s/mfa", login.fullname)
+	return r.Method == http.MethodPost && (r.URL.Path == path || r.URL.Path == path+"/verify")
+}
+
+func getMFAUser(w http.ResponseWriter, ps httprouter.Params, login *loginSession) *share.CLUSUser {
+	fullname, _ := url.PathUnescape(ps.ByName("fullname"))
+	user, _, _ := clusHelper.GetUserRev(fullname, access.NewReaderAccessControl())
+	if user == nil || !compareUserWithLogin(user, login) || login.loginType == loginTypeApikey {
+		restRespAccessDenied(w, login)
+		return nil
+	}
+	if user.Server != "" {
+		e := "MFA is only supported for local users"
+		log.WithFields(log.Fields{"user": fullname}).Error(e)
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+		return nil
+	}
+	return user
+}
+
+// Write the user modified by the callback. The callback returns false to abort.
+func updateMFAUser(w http.ResponseWriter, fullname string, update func(user *share.CLUSUser) bool) bool {
+	lock, err := lockClusKey(w, share.CLUSLockUserKey)
+	if err != nil {
+		return false
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	retry := 0
+	for retry < retryClusterMax {
+		user, rev, _ := clusHelper.GetUserRev(fullname, access.NewReaderAccessControl())
+		if user == nil {
+			restRespError(w, http.StatusNotFound, api.RESTErrObjectNotFound)
+			return false
+		}
+		if !update(user) {
+			return false
+		}
+		if err := clusHelper.PutUserRev(user, rev); err != nil {
+			log.WithFields(log.Fields{"error": err, "rev": rev}).Error()
+			retry++
+		} else {
+			return true
+		}
+	}
+
+	restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster, "Failed to write to the cluster")
+	return false
+}
+
+func handlerUserMFAEnroll(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	user := getMFAUser(w, ps, login)
+	if user == nil {
+		return
+	}
+
+	b := make([]byte, mfaSecretSize)
+	if _, err := rand.Read(b); err != nil {
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+	secret := mfaBase32.EncodeToString(b)
+
+	// Enrollment is pending until the secret is verified
+	ok := updateMFAUser(w, user.Fullname, func(user *share.CLUSUser) bool {
+		if user.MFAEnabled {
+			e := "MFA is already enabled"
+			log.WithFields(log.Fields{"user": user.Fullname}).Error(e)
+			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+			return false
+		}
+		encrypted, err := common.EncryptCloaked(secret)
+		if err != nil {
+			log.WithFields(log.Fields{"user": user.Fullname, "error": err}).Error("Failed to encrypt MFA secret")
+			restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+			return false
+		}
+		user.MFASecret = encrypted
+		user.MFABackupCodes = nil
+		user.MFALastStep = 0
+		return true
+	})
+	if !ok {
+		return
+	}
+
+	label := url.PathEscape(fmt.Sprintf("%s:%s", mfaIssuer, user.Fullname))
+	resp := api.RESTUserMFAEnrollData{MFA: &api.RESTUserMFAEnroll{
+		Secret: secret,
+		URI: fmt.Sprintf("otpauth://totp/%s?secret=%s&issuer=%s&algorithm=SHA1&digits=%d&period=%d",
+			label, secret, url.QueryEscape(mfaIssuer), mfaTOTPDigits, mfaTOTPPeriod),
+	}}
+	restRespSuccess(w, r, &resp, acc, login, nil, "Enroll user MFA")
+}
+
+func handlerUserMFAVerify(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	user := getMFAUser(w, ps, login)
+	if user == nil {
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+
+	var rconf api.RESTUserMFAVerifyData
+	err := json.Unmarshal(body, &rconf)
+	if err != nil || rconf.Config == nil || rconf.Config.Code == "" {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	codes := make([]string, mfaBackupCodeCount)
+	hashes := make([]string, mfaBackupCodeCount)
+	for i := range codes {
+		codes[i] = utils.GetRandomID(5, "")
+		hashes[i] = utils.HashPassword(codes[i])
+	}
+
+	ok := updateMFAUser(w, user.Fullname, func(user *share.CLUSUser) bool {
+		var e string
+		if user.MFAEnabled {
+			e = "MFA is already enabled"
+		} else if secret := getUserMFASecret(user); secret == nil {
+			e = "MFA is not enrolled"
+		} else if step := totpVerify(secret, strings.Replace(rconf.Config.Code, " ", "", -1), time.Now()); step < 0 {
+			e = "Invalid MFA code"
+		} else {
+			user.MFAEnabled = true
+			user.MFALastStep = step
+			user.MFABackupCodes = hashes
+			return true
+		}
+		log.WithFields(log.Fields{"user": user.Fullname}).Error(e)
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+		return false
+	})
+	if !ok {
+		return
+	}
+
+	// Sessions that were restricted to the enrollment are not upgraded. User must login again with the MFA code.
+	resp := api.RESTUserMFABackupCodesData{BackupCodes: codes}
+	restRespSuccess(w, r, &resp, acc, login, nil, "Enable user MFA")
+}
+
+func handlerUserMFADelete(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	fullname, _ := url.PathUnescape(ps.ByName("fullname"))
+	user, _, err := clusHelper.GetUserRev(fullname, access.NewReaderAccessControl())
+	if user == nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+
+	// Users can disable their own MFA if it's not required. Otherwise MFA can only be reset by users who can modify the user.
+	self := compareUserWithLogin(user, login)
+	if self {
+		if isMFARequiredRole(user.Role) {
+			e := "MFA is required for the user role"
+			log.WithFields(log.Fields{"user": fullname}).Error(e)
+			restRespErrorMessage(w, http.StatusForbidden, api.RESTErrOpNotAllowed, e)
+			return
+		}
+	} else if !acc.AuthorizeOwn(user, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	// A stolen session cannot remove the MFA. The current code of the login user is required if the login user
+	// has enabled MFA, which is the user self, or the admin who resets the MFA of another user.
+	body, _ := ioutil.ReadAll(r.Body)
+	var rconf api.RESTUserMFAVerifyData
+	var code string
+	if json.Unmarshal(body, &rconf) == nil && rconf.Config != nil {
+		code = rconf.Config.Code
+	}
+	verifyCode := func(user *share.CLUSUser) bool {
+		if !user.MFAEnabled || (code != "" && verifyUserMFACode(user, code, time.Now())) {
+			return true
+		}
+		e := "Invalid MFA code"
+		if code == "" {
+			e = errMFARequired.Error()
+		}
+		log.WithFields(log.Fields{"user": user.Fullname}).Error(e)
+		restRespErrorMessage(w, http.StatusForbidden, api.RESTErrMFARequired, e)
+		return false
+	}
+
+	if !self && login.loginType != loginTypeApikey {
+		caller, _, _ := clusHelper.GetUserRev(login.fullname, access.NewReaderAccessControl())
+		if caller != nil && caller.Server == "" && caller.MFAEnabled && !updateMFAUser(w, caller.Fullname, verifyCode) {
+			return
+		}
+	}
+
+	ok := updateMFAUser(w, fullname, func(user *share.CLUSUser) bool {
+		if self && !verifyCode(user) {
+			return false
+		}
+		user.MFASecret = ""
+		user.MFAEnabled = false
+		user.MFABackupCodes = nil
+		user.MFALastStep = 0
+		return true
+	})
+	if !ok {
+		return
+	}
+
+	restRespSuccess(w, r, nil, acc, login, nil, "Reset user MFA")
+}
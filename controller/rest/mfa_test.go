@@ -0,0 +1,254 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func getRESTErrorCode(w *mockResponseWriter) int {
+	var data api.RESTError
+	json.Unmarshal(w.body, &data)
+	return data.Code
+}
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 SHA1 test vectors, truncated to 6 digits
+	secret := []byte("12345678901234567890")
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for ts, code := range vectors {
+		if c := totpCode(secret, ts/mfaTOTPPeriod); c != code {
+			t.Errorf("Wrong TOTP code: time=%v code=%v expect=%v", ts, c, code)
+		}
+		if step := totpVerify(secret, code, time.Unix(ts+mfaTOTPPeriod, 0)); step != ts/mfaTOTPPeriod {
+			t.Errorf("Failed to verify code of the previous step: time=%v step=%v", ts, step)
+		}
+		if step := totpVerify(secret, code, time.Unix(ts+mfaTOTPPeriod*2, 0)); step != -1 {
+			t.Errorf("Code should be expired: time=%v step=%v", ts, step)
+		}
+	}
+}
+
+func TestMFALogin(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+
+	user := makeLocalUser("user", "pass", api.UserRoleReader)
+	clusHelper.CreateUser(user)
+
+	cacher = &mockCache{}
+
+	key1, _ := common.NewKvDataKey()
+	common.SetKvDataKeys("key1", map[string][]byte{"key1": key1})
+	defer common.SetKvDataKeys("", nil)
+
+	w := login("user", "pass")
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to login user: status=%v.", w.status)
+	}
+	token := getLoginToken(w)
+
+	// Enroll
+	w = restCallToken("POST", "/v1/user/user/mfa", nil, token)
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to enroll MFA: status=%v.", w.status)
+	}
+	var enroll api.RESTUserMFAEnrollData
+	json.Unmarshal(w.body, &enroll)
+	secret, _ := mfaBase32.DecodeString(enroll.MFA.Secret)
+	if u, _, _ := clusHelper.GetUserRev("user", access.NewReaderAccessControl()); u == nil || !common.IsKvEnvelopeEncrypted(u.MFASecret) {
+		t.Errorf("MFA secret is not encrypted by the data key")
+	}
+
+	// MFA is not enabled until the code is verified
+	w = login("user", "pass")
+	if w.status != http.StatusOK {
+		t.Errorf("Login should succeed before MFA is verified: status=%v.", w.status)
+	}
+	logout(getLoginToken(w))
+
+	step := time.Now().Unix() / mfaTOTPPeriod
+	data := api.RESTUserMFAVerifyData{Config: &api.RESTUserMFAVerify{Code: "abcdef"}}
+	body, _ := json.Marshal(&data)
+	w = restCallToken("POST", "/v1/user/user/mfa/verify", body, token)
+	if w.status != http.StatusBadRequest {
+		t.Errorf("Verify with wrong code should fail: status=%v.", w.status)
+	}
+
+	data.Config.Code = totpCode(secret, step)
+	body, _ = json.Marshal(&data)
+	w = restCallToken("POST", "/v1/user/user/mfa/verify", body, token)
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to verify MFA: status=%v.", w.status)
+	}
+	var backup api.RESTUserMFABackupCodesData
+	json.Unmarshal(w.body, &backup)
+	if len(backup.BackupCodes) != mfaBackupCodeCount {
+		t.Errorf("Unexpected backup codes: %+v", backup.BackupCodes)
+	}
+	logout(token)
+
+	// Login without code
+	w = login("user", "pass")
+	if w.status != http.StatusUnauthorized || getRESTErrorCode(w) != api.RESTErrMFARequired {
+		t.Errorf("Login should ask for MFA code: status=%v code=%v.", w.status, getRESTErrorCode(w))
+	}
+
+	// Code used for verification cannot be used again
+	w = loginMFA("user", "pass", totpCode(secret, step))
+	if w.status != http.StatusUnauthorized {
+		t.Errorf("Login with used code should fail: status=%v.", w.status)
+	}
+
+	w = loginMFA("user", "pass", totpCode(secret, step+1))
+	if w.status != http.StatusOK {
+		t.Errorf("Failed to login with MFA code: status=%v.", w.status)
+	}
+	logout(getLoginToken(w))
+
+	// Backup code can only be used once
+	w = loginMFA("user", "pass", backup.BackupCodes[0])
+	if w.status != http.StatusOK {
+		t.Errorf("Failed to login with backup code: status=%v.", w.status)
+	}
+	token = getLoginToken(w)
+	w = loginMFA("user", "pass", backup.BackupCodes[0])
+	if w.status != http.StatusUnauthorized {
+		t.Errorf("Login with used backup code should fail: status=%v.", w.status)
+	}
+
+	// Disable requires a current code
+	w = restCallToken("DELETE", "/v1/user/user/mfa", nil, token)
+	if w.status != http.StatusForbidden || getRESTErrorCode(w) != api.RESTErrMFARequired {
+		t.Errorf("Disable without code should fail: status=%v code=%v.", w.status, getRESTErrorCode(w))
+	}
+	data.Config.Code = backup.BackupCodes[0]
+	body, _ = json.Marshal(&data)
+	w = restCallToken("DELETE", "/v1/user/user/mfa", body, token)
+	if w.status != http.StatusForbidden {
+		t.Errorf("Disable with used code should fail: status=%v.", w.status)
+	}
+	data.Config.Code = backup.BackupCodes[1]
+	body, _ = json.Marshal(&data)
+	w = restCallToken("DELETE", "/v1/user/user/mfa", body, token)
+	if w.status != http.StatusOK {
+		t.Errorf("Failed to disable MFA: status=%v.", w.status)
+	}
+	logout(token)
+
+	w = login("user", "pass")
+	if w.status != http.StatusOK {
+		t.Errorf("Failed to login after MFA is disabled: status=%v.", w.status)
+	}
+	logout(getLoginToken(w))
+
+	// Admin who enabled MFA resets the MFA of another user with the own code
+	mfaSecret, _ := common.EncryptCloaked(enroll.MFA.Secret)
+	clusHelper.PutUserRev(&share.CLUSUser{Fullname: "user", Username: "user", PasswordHash: user.PasswordHash,
+		Role: api.UserRoleReader, MFAEnabled: true, MFASecret: mfaSecret}, 0)
+	admin := makeLocalUser("admin1", "pass", api.UserRoleAdmin)
+	admin.MFAEnabled = true
+	admin.MFASecret = mfaSecret
+	admin.MFABackupCodes = []string{utils.HashPassword("backup1")}
+	clusHelper.CreateUser(admin)
+	w = loginMFA("admin1", "pass", totpCode(secret, step))
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to login admin: status=%v.", w.status)
+	}
+	token = getLoginToken(w)
+	w = restCallToken("DELETE", "/v1/user/user/mfa", nil, token)
+	if w.status != http.StatusForbidden || getRESTErrorCode(w) != api.RESTErrMFARequired {
+		t.Errorf("Reset without admin code should fail: status=%v code=%v.", w.status, getRESTErrorCode(w))
+	}
+	data.Config.Code = "backup1"
+	body, _ = json.Marshal(&data)
+	w = restCallToken("DELETE", "/v1/user/user/mfa", body, token)
+	if w.status != http.StatusOK {
+		t.Errorf("Failed to reset MFA by admin: status=%v.", w.status)
+	}
+	if u, _, _ := clusHelper.GetUserRev("user", access.NewReaderAccessControl()); u == nil || u.MFAEnabled {
+		t.Errorf("MFA is not reset: %+v", u)
+	}
+	logout(token)
+
+	postTest()
+}
+
+func TestMFAEnrollRequired(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+
+	user := makeLocalUser("user", "pass", api.UserRoleReader)
+	clusHelper.CreateUser(user)
+
+	profile := share.CLUSPwdProfile{Name: share.CLUSDefPwdProfileName, MFARequiredRoles: []string{api.UserRoleReader}}
+	mc := &mockCache{}
+	mc.PutPwdProfiles(share.CLUSDefPwdProfileName, map[string]*share.CLUSPwdProfile{share.CLUSSysPwdProfileName: &profile})
+	cacher = mc
+
+	w := login("user", "pass")
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to login user: status=%v.", w.status)
+	}
+	var data api.RESTTokenData
+	json.Unmarshal(w.body, &data)
+	if !data.MFAEnrollRequired {
+		t.Errorf("MFA enrollment should be required")
+	}
+	token := getLoginToken(w)
+
+	// Session can only be used for enrollment
+	w = restCallToken("GET", "/v1/user", nil, token)
+	if w.status != http.StatusForbidden || getRESTErrorCode(w) != api.RESTErrMFAEnrollRequired {
+		t.Errorf("Request should be denied before MFA is enrolled: status=%v code=%v.", w.status, getRESTErrorCode(w))
+	}
+	w = restCallToken("POST", "/v1/user/user/mfa", nil, token)
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to enroll MFA: status=%v.", w.status)
+	}
+
+	// User cannot disable required MFA by self, but admin can reset it
+	w = restCallToken("DELETE", "/v1/user/user/mfa", nil, token)
+	if w.status != http.StatusForbidden {
+		t.Errorf("MFA required for the role should not be disabled by self: status=%v.", w.status)
+	}
+	w = restCall("DELETE", "/v1/user/user/mfa", nil, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Errorf("Failed to reset MFA by admin: status=%v.", w.status)
+	}
+	if u, _, _ := clusHelper.GetUserRev("user", access.NewReaderAccessControl()); u == nil || u.MFASecret != "" {
+		t.Errorf("MFA is not reset: %+v", u)
+	}
+	logout(token)
+
+	// Users whose role doesn't require MFA are not affected
+	clusHelper.CreateUser(makeLocalUser("admin1", "pass", api.UserRoleAdmin))
+	w = login("admin1", "pass")
+	var adminData api.RESTTokenData
+	json.Unmarshal(w.body, &adminData)
+	if w.status != http.StatusOK || adminData.MFAEnrollRequired {
+		t.Errorf("Unexpected login result: status=%v enroll=%v.", w.status, adminData.MFAEnrollRequired)
+	}
+	logout(getLoginToken(w))
+
+	postTest()
+}
@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+)
+
+// The MITRE ATT&CK techniques covered by the built-in detections, and their hits in the cached threats and incidents
+func handlerAttackMatrix(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	techMap := make(map[string]*api.RESTMitreTechniqueReport, len(share.CLUSMitreTechniques))
+	lastHits := make(map[string]int64)
+	addDetection := func(techniques []string, name string) {
+		for _, id := range techniques {
+			tech, ok := techMap[id]
+			if !ok {
+				tech = &api.RESTMitreTechniqueReport{ID: id, Name: share.CLUSMitreTechniques[id].Name, Detections: make([]string, 0)}
+				techMap[id] = tech
+			}
+			tech.Detections = append(tech.Detections, name)
+		}
+	}
+	addHit := func(techniques []string, ts int64) {
+		for _, id := range techniques {
+			if tech, ok := techMap[id]; ok {
+				tech.Hits++
+				if ts > lastHits[id] {
+					lastHits[id] = ts
+				}
+			}
+		}
+	}
+
+	for _, info := range common.LogThreatMap {
+		addDetection(info.Techniques, info.Name)
+	}
+	for id, techniques := range share.CLUSIncidentMitreMap {
+		if info, ok := common.LogIncidentMap[id]; ok {
+			addDetection(techniques, info.Name)
+		}
+	}
+
+	var hits int
+	for _, t := range cacher.GetThreats(acc) {
+		if len(t.Mitre) > 0 {
+			addHit(t.Mitre, t.ReportedTimeStamp)
+			hits++
+		}
+	}
+	for _, incd := range cacher.GetIncidents(acc) {
+		if len(incd.Mitre) > 0 {
+			addHit(incd.Mitre, incd.ReportedTimeStamp)
+			hits++
+		}
+	}
+
+	resp := api.RESTAttackMatrixData{
+		Tactics:    make([]*api.RESTMitreTacticReport, 0, len(share.CLUSMitreTactics)),
+		Techniques: len(techMap),
+		Hits:       hits,
+	}
+	for _, tactic := range share.CLUSMitreTactics {
+		report := &api.RESTMitreTacticReport{ID: tactic.ID, Name: tactic.Name, Techniques: make([]*api.RESTMitreTechniqueReport, 0)}
+		for id, tech := range techMap {
+			if share.CLUSMitreTechniques[id].Tactic == tactic.ID {
+				if ts, ok := lastHits[id]; ok {
+					tech.LastHitAt = api.RESTTimeString(time.Unix(ts, 0))
+				}
+				sort.Strings(tech.Detections)
+				report.Techniques = append(report.Techniques, tech)
+			}
+		}
+		sort.Slice(report.Techniques, func(i, j int) bool { return report.Techniques[i].ID < report.Techniques[j].ID })
+		resp.Tactics = append(resp.Tactics, report)
+	}
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get MITRE ATT&CK matrix")
+}
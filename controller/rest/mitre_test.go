@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+)
+
+type mitreMockCache struct {
+	mockCache
+}
+
+func (m *mitreMockCache) GetThreats(acc *access.AccessControl) []*api.Threat {
+	return []*api.Threat{
+		{LogCommon: api.LogCommon{ReportedTimeStamp: 100}, Mitre: []string{share.MitreTechExploitPublicApp}},
+		{LogCommon: api.LogCommon{ReportedTimeStamp: 200}, Mitre: []string{share.MitreTechExploitPublicApp}},
+		{LogCommon: api.LogCommon{ReportedTimeStamp: 300}}, // DLP
+	}
+}
+
+func (m *mitreMockCache) GetIncidents(acc *access.AccessControl) []*api.Incident {
+	return []*api.Incident{
+		{LogCommon: api.LogCommon{ReportedTimeStamp: 150}, Mitre: []string{share.MitreTechKernelModules, share.MitreTechEscapeToHost}},
+	}
+}
+
+func TestAttackMatrix(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+	cacher = &mitreMockCache{}
+	router.GET("/v1/report/attack-matrix", handlerAttackMatrix)
+
+	w := restCall("GET", "/v1/report/attack-matrix", nil, api.UserRoleReader)
+	if w.status != 200 {
+		t.Fatalf("Unexpected status: %v", w.status)
+	}
+	var resp api.RESTAttackMatrixData
+	json.Unmarshal(w.body, &resp)
+
+	if len(resp.Tactics) != len(share.CLUSMitreTactics) || resp.Hits != 3 || resp.Techniques != len(share.CLUSMitreTechniques) {
+		t.Fatalf("Unexpected matrix: tactics=%v hits=%v techniques=%v", len(resp.Tactics), resp.Hits, resp.Techniques)
+	}
+
+	techs := make(map[string]*api.RESTMitreTechniqueReport)
+	for _, tactic := range resp.Tactics {
+		for _, tech := range tactic.Techniques {
+			if share.CLUSMitreTechniques[tech.ID].Tactic != tactic.ID {
+				t.Errorf("Technique %v is in wrong tactic %v", tech.ID, tactic.ID)
+			}
+			techs[tech.ID] = tech
+		}
+	}
+	if tech := techs[share.MitreTechExploitPublicApp]; tech == nil || tech.Hits != 2 || tech.LastHitAt == "" || len(tech.Detections) == 0 {
+		t.Errorf("Unexpected technique: %+v", tech)
+	}
+	if tech := techs[share.MitreTechEscapeToHost]; tech == nil || tech.Hits != 1 || len(tech.Detections) != 2 {
+		t.Errorf("Unexpected technique: %+v", tech)
+	}
+	if tech := techs[share.MitreTechBruteForce]; tech == nil || tech.Hits != 0 || tech.LastHitAt != "" {
+		t.Errorf("Unexpected technique: %+v", tech)
+	}
+
+	postTest()
+}
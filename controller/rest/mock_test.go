@@ -281,6 +281,10 @@ func (m *mockCache) GetGroupBrief(name string, withCap bool, acc *access.AccessC
 	return nil, common.ErrObjectNotFound
 }
 
+func (m *mockCache) AuthorizeCustomCheck(name string, acc *access.AccessControl) bool {
+	return acc.Authorize(&share.CLUSGroup{Name: name}, nil)
+}
+
 func (m *mockCache) GetGroup(name string, view string, withCap bool, acc *access.AccessControl) (*api.RESTGroup, error) {
 	if g, ok := m.groups[name]; ok {
 		return g, nil
@@ -290,6 +294,20 @@ func (m *mockCache) GetGroup(name string, view string, withCap bool, acc *access
 	return nil, common.ErrObjectNotFound
 }
 
+func (m *mockCache) GetGroupCount(scope string, acc *access.AccessControl) int {
+	return len(m.groups)
+}
+
+func (m *mockCache) GetAllGroups(scope, view string, withCap bool, acc *access.AccessControl) [][]*api.RESTGroup {
+	groups := make([]*api.RESTGroup, 0, len(m.groups))
+	for _, g := range m.groups {
+		if acc.Authorize(m.Group2CLUS(g), nil) {
+			groups = append(groups, g)
+		}
+	}
+	return [][]*api.RESTGroup{groups}
+}
+
 func (m *mockCache) GetAllHosts(acc *access.AccessControl) []*api.RESTHost {
 	return nil
 }
@@ -523,10 +541,14 @@ func initTest() {
 	router.POST("/v1/user", handlerUserCreate)
 	router.PATCH("/v1/user/:fullname", handlerUserConfig)
 	router.DELETE("/v1/user/:fullname", handlerUserDelete)
+	router.POST("/v1/user/:fullname/mfa", handlerUserMFAEnroll)
+	router.POST("/v1/user/:fullname/mfa/verify", handlerUserMFAVerify)
+	router.DELETE("/v1/user/:fullname/mfa", handlerUserMFADelete)
 
 	router.POST("/v1/auth", handlerAuthLogin)
 	router.POST("/v1/auth/:server", handlerAuthLoginServer)
 	router.DELETE("/v1/auth", handlerAuthLogout)
+	router.POST("/v1/recovery_auth", handlerRecoveryAuth)
 	router.POST("/v1/fed_auth", handlerFedAuthLogin)
 	router.DELETE("/v1/fed_auth", handlerFedAuthLogout)
 
@@ -550,19 +572,45 @@ func initTest() {
 	router.PATCH("/v1/compliance/profile/:name", handlerComplianceProfileConfig)
 	router.PATCH("/v1/compliance/profile/:name/entry/:check", handlerComplianceProfileEntryConfig)
 	router.DELETE("/v1/compliance/profile/:name/entry/:check", handlerComplianceProfileEntryDelete)
+	router.GET("/v1/custom_check/:group", handlerCustomCheckShow)
+	router.PATCH("/v1/custom_check/:group", handlerCustomCheckConfig)
+
+	router.GET("/v1/compliance/acceptance/:id", handlerRiskAcceptanceShow)
+	router.GET("/v1/vulnerability/acceptance", handlerRiskAcceptanceList)
+	router.POST("/v1/vulnerability/acceptance", handlerRiskAcceptanceCreate)
+	router.POST("/v1/vulnerability/acceptance/:id/approve", handlerRiskAcceptanceApprove)
+	router.DELETE("/v1/vulnerability/acceptance/:id", handlerRiskAcceptanceDelete)
 
 	router.GET("/v1/policy/rule", handlerPolicyRuleList)
 	router.GET("/v1/policy/rule/:id", handlerPolicyRuleShow)
 	router.PATCH("/v1/policy/rule", handlerPolicyRuleAction)
 	router.PATCH("/v1/policy/rule/:id", handlerPolicyRuleConfig)
+	router.PATCH("/v1/policy/rules/batch", handlerPolicyRuleBatch)
+	router.GET("/v1/policy/changes/:id", handlerPolicyChangeShow)
+	router.POST("/v1/policy/changes", handlerPolicyChangeSubmit)
+	router.POST("/v1/policy/changes/:id/approve", handlerPolicyChangeApprove)
+	router.POST("/v1/policy/changes/:id/reject", handlerPolicyChangeReject)
+	router.DELETE("/v1/policy/changes/:id", handlerPolicyChangeDelete)
+	router.POST("/v1/policy/simulate", handlerPolicySimulate)
+	router.POST("/v1/file/network_policy", handlerNetPolicyExport)
+	router.POST("/v1/file/group/config", handlerGroupCfgImport)
+	router.POST("/v1/file/config", handlerConfigImport)
+	router.POST("/v1/system/backup/restore", handlerBackupRestore)
+	router.GET("/v1/file/config/crd", handlerCrdConfigExport)
 
 	router.POST("/v1/service", handlerServiceCreate)
 	router.GET("/v1/service/:name", handlerServiceShow)
+	router.GET("/v1/group", handlerGroupList)
 	router.POST("/v1/group", handlerGroupCreate)
 	router.PATCH("/v1/group/:name", handlerGroupConfig)
+	router.PATCH("/v1/groups/batch", handlerGroupBatch)
 	router.DELETE("/v1/group/:name", handlerGroupDelete)
+	router.GET("/v1/group/:name/profiles/export", handlerGroupProfilesExport)
+
+	router.POST("/v1/threat/signature/bundle", handlerThreatSigImport)
 
 	router.GET("/v1/file_monitor/:name", handlerFileMonitorShow)
+	router.POST("/v1/workload/:id/file_baseline", handlerWorkloadFileBaselineReset)
 	router.GET("/v1/process_profile/:name", handlerProcessProfileShow)
 	router.PATCH("/v1/process_profile/:name", handlerProcessProfileConfig)
 
@@ -602,6 +650,7 @@ func initTest() {
 	router.DELETE("/v1/conversation_endpoint/:id", handlerConverEndpointDelete) // API not exposed
 	router.DELETE("/v1/conversation", handlerConverDeleteAll)                   // API not exposed
 	router.DELETE("/v1/session", handlerSessionDelete)
+	router.POST("/v1/graphql", handlerGraphQL)
 
 	// only for custom role unittest
 	router.GET("/v1/scan/config", handlerScanConfigGet)
@@ -717,6 +766,15 @@ func login(username, password string) *mockResponseWriter {
 	return w
 }
 
+func loginMFA(username, password, code string) *mockResponseWriter {
+	w := new(mockResponseWriter)
+	data := api.RESTAuthData{Password: &api.RESTAuthPassword{Username: username, Password: password, MFACode: code}}
+	body, _ := json.Marshal(data)
+	r, _ := http.NewRequest("POST", "/v1/auth", bytes.NewBuffer(body))
+	router.ServeHTTP(w, r)
+	return w
+}
+
 func loginServerPassword(username, password, server string) *mockResponseWriter {
 	w := new(mockResponseWriter)
 	data := api.RESTAuthData{Password: &api.RESTAuthPassword{Username: username, Password: password}}
@@ -735,6 +793,15 @@ func loginServerToken(token, server string) *mockResponseWriter {
 	return w
 }
 
+func loginServerRefreshToken(refreshToken, server string) *mockResponseWriter {
+	w := new(mockResponseWriter)
+	data := api.RESTAuthData{Token: &api.RESTAuthToken{RefreshToken: refreshToken}}
+	body, _ := json.Marshal(data)
+	r, _ := http.NewRequest("POST", "/v1/auth/"+server, bytes.NewBuffer(body))
+	router.ServeHTTP(w, r)
+	return w
+}
+
 func logout(token string) *mockResponseWriter {
 	w := new(mockResponseWriter)
 	r, _ := http.NewRequest("DELETE", "/v1/auth", bytes.NewBuffer([]byte{}))
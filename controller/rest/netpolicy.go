@@ -0,0 +1,674 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// Translate network rules of NeuVector groups to platform network policies, so the rules can be
+// enforced by CNI where enforcers are not deployed. Only allow rules are exported as native network
+// policies are whitelist. Application (L7) match of the rules is not exported.
+
+const k8sNamespaceLabel = "kubernetes.io/metadata.name"
+
+// Container labels that are set by runtime or controllers and cannot be used as pod selector
+var netPolicyIgnoredLabelPrefixes = []string{
+	"io.kubernetes.", "io.cri-containerd.", "io.openshift.", "annotation.", "org.opencontainers.",
+	"com.docker.", "maintainer", "pod-template-hash", "controller-revision-hash", "pod-template-generation",
+	"statefulset.kubernetes.io/pod-name",
+}
+
+// Endpoint selection of a group
+type netPolicySelector struct {
+	namespace string
+	labels    map[string]string
+	cluster   bool // all containers
+	world     bool // external
+	cidrs     []string
+	fqdns     []string
+}
+
+type netPolicyPort struct {
+	protocol string
+	port     uint16
+	endPort  uint16
+}
+
+type netPolicyPeerRule struct {
+	peer  *netPolicySelector
+	ports []netPolicyPort // nil: any
+}
+
+type netPolicyGroup struct {
+	name     string
+	selector *netPolicySelector
+	ingress  []*netPolicyPeerRule
+	egress   []*netPolicyPeerRule
+}
+
+// -- kubernetes networking.k8s.io/v1
+
+type k8sLabelSelector struct {
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+type k8sIPBlock struct {
+	CIDR string `json:"cidr"`
+}
+
+type k8sNetPolicyPeer struct {
+	PodSelector       *k8sLabelSelector `json:"podSelector,omitempty"`
+	NamespaceSelector *k8sLabelSelector `json:"namespaceSelector,omitempty"`
+	IPBlock           *k8sIPBlock       `json:"ipBlock,omitempty"`
+}
+
+type k8sNetPolicyPort struct {
+	Protocol string  `json:"protocol,omitempty"`
+	Port     *uint16 `json:"port,omitempty"`
+	EndPort  *uint16 `json:"endPort,omitempty"`
+}
+
+type k8sNetPolicyIngressRule struct {
+	From  []k8sNetPolicyPeer `json:"from"`
+	Ports []k8sNetPolicyPort `json:"ports,omitempty"`
+}
+
+type k8sNetPolicyEgressRule struct {
+	To    []k8sNetPolicyPeer `json:"to"`
+	Ports []k8sNetPolicyPort `json:"ports,omitempty"`
+}
+
+type k8sNetPolicySpec struct {
+	PodSelector k8sLabelSelector          `json:"podSelector"`
+	PolicyTypes []string                  `json:"policyTypes"`
+	Ingress     []k8sNetPolicyIngressRule `json:"ingress"`
+	Egress      []k8sNetPolicyEgressRule  `json:"egress"`
+}
+
+// -- cilium cilium.io/v2
+
+type ciliumPort struct {
+	Port     string `json:"port"`
+	EndPort  uint16 `json:"endPort,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+}
+
+type ciliumPortRule struct {
+	Ports []ciliumPort `json:"ports"`
+}
+
+type ciliumFQDN struct {
+	MatchName string `json:"matchName"`
+}
+
+type ciliumIngressRule struct {
+	FromEndpoints []k8sLabelSelector `json:"fromEndpoints,omitempty"`
+	FromEntities  []string           `json:"fromEntities,omitempty"`
+	FromCIDR      []string           `json:"fromCIDR,omitempty"`
+	ToPorts       []ciliumPortRule   `json:"toPorts,omitempty"`
+}
+
+type ciliumEgressRule struct {
+	ToEndpoints []k8sLabelSelector `json:"toEndpoints,omitempty"`
+	ToEntities  []string           `json:"toEntities,omitempty"`
+	ToCIDR      []string           `json:"toCIDR,omitempty"`
+	ToFQDNs     []ciliumFQDN       `json:"toFQDNs,omitempty"`
+	ToPorts     []ciliumPortRule   `json:"toPorts,omitempty"`
+}
+
+type ciliumNetPolicySpec struct {
+	EndpointSelector k8sLabelSelector    `json:"endpointSelector"`
+	Ingress          []ciliumIngressRule `json:"ingress"`
+	Egress           []ciliumEgressRule  `json:"egress"`
+}
+
+// -- calico projectcalico.org/v3
+
+type calicoEntityRule struct {
+	Selector          string        `json:"selector,omitempty"`
+	NamespaceSelector string        `json:"namespaceSelector,omitempty"`
+	Nets              []string      `json:"nets,omitempty"`
+	Ports             []interface{} `json:"ports,omitempty"`
+}
+
+type calicoRule struct {
+	Action      string            `json:"action"`
+	Protocol    string            `json:"protocol,omitempty"`
+	Source      *calicoEntityRule `json:"source,omitempty"`
+	Destination *calicoEntityRule `json:"destination,omitempty"`
+}
+
+type calicoNetPolicySpec struct {
+	Selector string       `json:"selector"`
+	Types    []string     `json:"types"`
+	Ingress  []calicoRule `json:"ingress"`
+	Egress   []calicoRule `json:"egress"`
+}
+
+type netPolicyMeta struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+type netPolicyObject struct {
+	ApiVersion string        `json:"apiVersion"`
+	Kind       string        `json:"kind"`
+	Metadata   netPolicyMeta `json:"metadata"`
+	Spec       interface{}   `json:"spec"`
+}
+
+type netPolicyList struct {
+	ApiVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Items      []*netPolicyObject `json:"items"`
+}
+
+func isNetPolicyIgnoredLabel(key string) bool {
+	for _, prefix := range netPolicyIgnoredLabelPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Use the labels that all members have in common to select the pods of a learned group
+func getNetPolicyMemberLabels(group *api.RESTGroup, acc *access.AccessControl) map[string]string {
+	var labels map[string]string
+	for _, m := range group.Members {
+		wl, err := cacher.GetWorkload(m.ID, "", acc)
+		if err != nil {
+			continue
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+			for k, v := range wl.Labels {
+				if !isNetPolicyIgnoredLabel(k) {
+					labels[k] = v
+				}
+			}
+		} else {
+			for k, v := range labels {
+				if wv, ok := wl.Labels[k]; !ok || wv != v {
+					delete(labels, k)
+				}
+			}
+		}
+	}
+	return labels
+}
+
+func getNetPolicySelector(group *api.RESTGroup, acc *access.AccessControl) *netPolicySelector {
+	switch group.Name {
+	case api.LearnedExternal:
+		return &netPolicySelector{world: true}
+	case api.AllContainerGroup:
+		return &netPolicySelector{cluster: true}
+	case api.AllHostGroup:
+		return nil
+	}
+
+	sel := &netPolicySelector{labels: make(map[string]string)}
+	var service bool
+	for _, ct := range group.Criteria {
+		switch ct.Key {
+		case share.CriteriaKeyDomain, share.CriteriaKeyNamespace:
+			if ct.Op != share.CriteriaOpEqual {
+				return nil
+			}
+			sel.namespace = ct.Value
+		case share.CriteriaKeyAddress:
+			if _, ipnet, err := net.ParseCIDR(ct.Value); err == nil {
+				sel.cidrs = append(sel.cidrs, ipnet.String())
+			} else if ip := net.ParseIP(ct.Value); ip != nil {
+				sel.cidrs = append(sel.cidrs, utils.IPNet2Subnet(&net.IPNet{IP: ip, Mask: utils.IPHostMask(ip)}).String())
+			} else if !strings.Contains(ct.Value, "-") {
+				sel.fqdns = append(sel.fqdns, ct.Value)
+			}
+		case share.CriteriaKeyService:
+			service = true
+		case share.CriteriaKeyImage, share.CriteriaKeyHost, share.CriteriaKeyWorkload:
+			// cannot be expressed by pod selector
+			return nil
+		default:
+			if ct.Op != share.CriteriaOpEqual {
+				return nil
+			}
+			sel.labels[ct.Key] = ct.Value
+		}
+	}
+
+	if len(sel.cidrs) > 0 || len(sel.fqdns) > 0 {
+		sel.labels = nil
+		sel.namespace = ""
+		return sel
+	}
+
+	if sel.namespace == "" {
+		sel.namespace = group.Domain
+	}
+	if service && len(sel.labels) == 0 {
+		sel.labels = getNetPolicyMemberLabels(group, acc)
+	}
+	if sel.namespace == "" || len(sel.labels) == 0 {
+		return nil
+	}
+	return sel
+}
+
+// Parse rule ports into protocol port ranges. Return nil for any port, or error if any
+// of the ports cannot be expressed by network policy.
+func getNetPolicyPorts(ports string) ([]netPolicyPort, error) {
+	if ports == "" || ports == api.PolicyPortAny {
+		return nil, nil
+	}
+
+	list := make([]netPolicyPort, 0)
+	for _, pp := range strings.Split(ports, ",") {
+		proto, low, high, err := utils.ParsePortRangeLink(strings.TrimSpace(pp))
+		if err != nil {
+			return nil, err
+		}
+		switch proto {
+		case syscall.IPPROTO_TCP:
+			list = append(list, netPolicyPort{protocol: "TCP", port: low, endPort: high})
+		case syscall.IPPROTO_UDP:
+			list = append(list, netPolicyPort{protocol: "UDP", port: low, endPort: high})
+		case 0:
+			if low == 0 && high == 0xffff {
+				return nil, nil
+			}
+			list = append(list, netPolicyPort{protocol: "TCP", port: low, endPort: high})
+			list = append(list, netPolicyPort{protocol: "UDP", port: low, endPort: high})
+		default:
+			return nil, fmt.Errorf("Unsupported protocol: %s", pp)
+		}
+	}
+	return list, nil
+}
+
+func buildNetPolicyGroup(gname string, learnedOnly bool, acc *access.AccessControl) *netPolicyGroup {
+	group, err := cacher.GetGroup(gname, "", false, acc)
+	if err != nil {
+		log.WithFields(log.Fields{"name": gname, "error": err}).Error("Failed to get group")
+		return nil
+	}
+	if group.Kind != "" && group.Kind != share.GroupKindContainer {
+		return nil
+	}
+	sel := getNetPolicySelector(group, acc)
+	if sel == nil || sel.labels == nil {
+		log.WithFields(log.Fields{"name": gname}).Info("Group cannot be selected by network policy")
+		return nil
+	}
+
+	npg := &netPolicyGroup{name: gname, selector: sel}
+	peers := make(map[string]*netPolicySelector)
+	getPeer := func(name string) *netPolicySelector {
+		if peer, ok := peers[name]; ok {
+			return peer
+		}
+		var peer *netPolicySelector
+		if g, err := cacher.GetGroup(name, "", false, acc); err == nil {
+			peer = getNetPolicySelector(g, acc)
+		}
+		peers[name] = peer
+		return peer
+	}
+
+	for _, id := range group.PolicyRules {
+		rule, err := cacher.GetPolicyRule(id, acc)
+		if err != nil || rule.Disable || rule.Action != share.PolicyActionAllow {
+			continue
+		}
+		if learnedOnly && rule.CfgType != api.CfgTypeLearned {
+			continue
+		}
+		ports, err := getNetPolicyPorts(rule.Ports)
+		if err != nil {
+			log.WithFields(log.Fields{"rule": rule.ID, "error": err}).Info("Skip rule")
+			continue
+		}
+		if rule.To == gname {
+			if peer := getPeer(rule.From); peer != nil {
+				npg.ingress = append(npg.ingress, &netPolicyPeerRule{peer: peer, ports: ports})
+			}
+		}
+		if rule.From == gname {
+			if peer := getPeer(rule.To); peer != nil {
+				npg.egress = append(npg.egress, &netPolicyPeerRule{peer: peer, ports: ports})
+			}
+		}
+	}
+	return npg
+}
+
+func netPolicyMetadata(npg *netPolicyGroup) netPolicyMeta {
+	// named after the group as in group export
+	name := utils.Dns1123NameChg(strings.ToLower(npg.name))
+	return netPolicyMeta{
+		Name:      name,
+		Namespace: npg.selector.namespace,
+		Labels:    map[string]string{"neuvector.com/group": name},
+	}
+}
+
+// -- kubernetes
+
+func k8sNetPolicyPeers(peer *netPolicySelector) []k8sNetPolicyPeer {
+	peers := make([]k8sNetPolicyPeer, 0)
+	if peer.world {
+		peers = append(peers, k8sNetPolicyPeer{IPBlock: &k8sIPBlock{CIDR: "0.0.0.0/0"}},
+			k8sNetPolicyPeer{IPBlock: &k8sIPBlock{CIDR: "::/0"}})
+	} else if peer.cluster {
+		peers = append(peers, k8sNetPolicyPeer{PodSelector: &k8sLabelSelector{}, NamespaceSelector: &k8sLabelSelector{}})
+	} else if len(peer.cidrs) > 0 {
+		for _, cidr := range peer.cidrs {
+			peers = append(peers, k8sNetPolicyPeer{IPBlock: &k8sIPBlock{CIDR: cidr}})
+		}
+	} else if peer.labels != nil {
+		peers = append(peers, k8sNetPolicyPeer{
+			PodSelector:       &k8sLabelSelector{MatchLabels: peer.labels},
+			NamespaceSelector: &k8sLabelSelector{MatchLabels: map[string]string{k8sNamespaceLabel: peer.namespace}},
+		})
+	}
+	return peers
+}
+
+func k8sNetPolicyPorts(ports []netPolicyPort) []k8sNetPolicyPort {
+	if ports == nil {
+		return nil
+	}
+	list := make([]k8sNetPolicyPort, len(ports))
+	for i, p := range ports {
+		port := p.port
+		list[i] = k8sNetPolicyPort{Protocol: p.protocol, Port: &port}
+		if p.endPort > p.port {
+			endPort := p.endPort
+			list[i].EndPort = &endPort
+		}
+	}
+	return list
+}
+
+func k8sNetPolicy(npg *netPolicyGroup) *netPolicyObject {
+	spec := k8sNetPolicySpec{
+		PodSelector: k8sLabelSelector{MatchLabels: npg.selector.labels},
+		PolicyTypes: []string{"Ingress", "Egress"},
+		Ingress:     make([]k8sNetPolicyIngressRule, 0),
+		Egress:      make([]k8sNetPolicyEgressRule, 0),
+	}
+	for _, r := range npg.ingress {
+		// fqdn is not supported
+		if peers := k8sNetPolicyPeers(r.peer); len(peers) > 0 {
+			spec.Ingress = append(spec.Ingress, k8sNetPolicyIngressRule{From: peers, Ports: k8sNetPolicyPorts(r.ports)})
+		}
+	}
+	for _, r := range npg.egress {
+		if peers := k8sNetPolicyPeers(r.peer); len(peers) > 0 {
+			spec.Egress = append(spec.Egress, k8sNetPolicyEgressRule{To: peers, Ports: k8sNetPolicyPorts(r.ports)})
+		}
+	}
+	return &netPolicyObject{
+		ApiVersion: "networking.k8s.io/v1",
+		Kind:       "NetworkPolicy",
+		Metadata:   netPolicyMetadata(npg),
+		Spec:       spec,
+	}
+}
+
+// -- cilium
+
+func ciliumEndpointSelector(peer *netPolicySelector) k8sLabelSelector {
+	labels := make(map[string]string, len(peer.labels)+1)
+	for k, v := range peer.labels {
+		labels[k] = v
+	}
+	labels["k8s:io.kubernetes.pod.namespace"] = peer.namespace
+	return k8sLabelSelector{MatchLabels: labels}
+}
+
+func ciliumPorts(ports []netPolicyPort) []ciliumPortRule {
+	if ports == nil {
+		return nil
+	}
+	list := make([]ciliumPort, len(ports))
+	for i, p := range ports {
+		list[i] = ciliumPort{Port: fmt.Sprintf("%d", p.port), Protocol: p.protocol}
+		if p.endPort > p.port {
+			list[i].EndPort = p.endPort
+		}
+	}
+	return []ciliumPortRule{{Ports: list}}
+}
+
+func ciliumNetPolicy(npg *netPolicyGroup) *netPolicyObject {
+	spec := ciliumNetPolicySpec{
+		EndpointSelector: k8sLabelSelector{MatchLabels: npg.selector.labels},
+		Ingress:          make([]ciliumIngressRule, 0),
+		Egress:           make([]ciliumEgressRule, 0),
+	}
+	for _, r := range npg.ingress {
+		rule := ciliumIngressRule{ToPorts: ciliumPorts(r.ports)}
+		if r.peer.world {
+			rule.FromEntities = []string{"world"}
+		} else if r.peer.cluster {
+			rule.FromEntities = []string{"cluster"}
+		} else if len(r.peer.cidrs) > 0 {
+			rule.FromCIDR = r.peer.cidrs
+		} else if r.peer.labels != nil {
+			rule.FromEndpoints = []k8sLabelSelector{ciliumEndpointSelector(r.peer)}
+		} else {
+			continue
+		}
+		spec.Ingress = append(spec.Ingress, rule)
+	}
+	for _, r := range npg.egress {
+		rule := ciliumEgressRule{ToPorts: ciliumPorts(r.ports)}
+		if r.peer.world {
+			rule.ToEntities = []string{"world"}
+		} else if r.peer.cluster {
+			rule.ToEntities = []string{"cluster"}
+		} else if len(r.peer.cidrs) > 0 || len(r.peer.fqdns) > 0 {
+			rule.ToCIDR = r.peer.cidrs
+			for _, fqdn := range r.peer.fqdns {
+				rule.ToFQDNs = append(rule.ToFQDNs, ciliumFQDN{MatchName: fqdn})
+			}
+		} else if r.peer.labels != nil {
+			rule.ToEndpoints = []k8sLabelSelector{ciliumEndpointSelector(r.peer)}
+		} else {
+			continue
+		}
+		spec.Egress = append(spec.Egress, rule)
+	}
+	return &netPolicyObject{
+		ApiVersion: "cilium.io/v2",
+		Kind:       "CiliumNetworkPolicy",
+		Metadata:   netPolicyMetadata(npg),
+		Spec:       spec,
+	}
+}
+
+// -- calico
+
+func calicoSelector(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	exprs := make([]string, len(keys))
+	for i, k := range keys {
+		exprs[i] = fmt.Sprintf("%s == '%s'", k, labels[k])
+	}
+	return strings.Join(exprs, " && ")
+}
+
+func calicoEntity(peer *netPolicySelector) *calicoEntityRule {
+	if peer.world {
+		return &calicoEntityRule{Nets: []string{"0.0.0.0/0", "::/0"}}
+	} else if peer.cluster {
+		return &calicoEntityRule{Selector: "all()", NamespaceSelector: "all()"}
+	} else if len(peer.cidrs) > 0 {
+		return &calicoEntityRule{Nets: peer.cidrs}
+	} else if peer.labels != nil {
+		return &calicoEntityRule{
+			Selector:          calicoSelector(peer.labels),
+			NamespaceSelector: fmt.Sprintf("projectcalico.org/name == '%s'", peer.namespace),
+		}
+	}
+	return nil
+}
+
+// Calico rule has one protocol, so a rule is created for each protocol of the ports
+func calicoRules(peer, dst *calicoEntityRule, ingress bool, ports []netPolicyPort) []calicoRule {
+	var protos []string
+	protoPorts := make(map[string][]interface{})
+	for _, p := range ports {
+		if _, ok := protoPorts[p.protocol]; !ok {
+			protos = append(protos, p.protocol)
+		}
+		if p.endPort > p.port {
+			protoPorts[p.protocol] = append(protoPorts[p.protocol], fmt.Sprintf("%d:%d", p.port, p.endPort))
+		} else {
+			protoPorts[p.protocol] = append(protoPorts[p.protocol], p.port)
+		}
+	}
+
+	newRule := func(proto string, pp []interface{}) calicoRule {
+		rule := calicoRule{Action: "Allow", Protocol: proto}
+		if ingress {
+			rule.Source = peer
+			rule.Destination = &calicoEntityRule{Ports: pp}
+		} else {
+			d := *dst
+			d.Ports = pp
+			rule.Destination = &d
+		}
+		return rule
+	}
+
+	if ports == nil {
+		return []calicoRule{newRule("", nil)}
+	}
+	rules := make([]calicoRule, 0, len(protos))
+	for _, proto := range protos {
+		rules = append(rules, newRule(proto, protoPorts[proto]))
+	}
+	return rules
+}
+
+func calicoNetPolicy(npg *netPolicyGroup) *netPolicyObject {
+	spec := calicoNetPolicySpec{
+		Selector: calicoSelector(npg.selector.labels),
+		Types:    []string{"Ingress", "Egress"},
+		Ingress:  make([]calicoRule, 0),
+		Egress:   make([]calicoRule, 0),
+	}
+	for _, r := range npg.ingress {
+		if peer := calicoEntity(r.peer); peer != nil {
+			spec.Ingress = append(spec.Ingress, calicoRules(peer, nil, true, r.ports)...)
+		}
+	}
+	for _, r := range npg.egress {
+		if peer := calicoEntity(r.peer); peer != nil {
+			spec.Egress = append(spec.Egress, calicoRules(nil, peer, false, r.ports)...)
+		}
+	}
+	return &netPolicyObject{
+		ApiVersion: "projectcalico.org/v3",
+		Kind:       "NetworkPolicy",
+		Metadata:   netPolicyMetadata(npg),
+		Spec:       spec,
+	}
+}
+
+func handlerNetPolicyExport(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, access.AccessOPRead)
+	if acc == nil {
+		return
+	}
+
+	if !acc.Authorize(&share.CLUSPolicyRule{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	var rconf api.RESTNetPolicyExport
+	body, _ := ioutil.ReadAll(r.Body)
+	err := json.Unmarshal(body, &rconf)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	var convert func(npg *netPolicyGroup) *netPolicyObject
+	switch rconf.Format {
+	case "", api.NetPolicyExportKubernetes:
+		convert = k8sNetPolicy
+	case api.NetPolicyExportCilium:
+		convert = ciliumNetPolicy
+	case api.NetPolicyExportCalico:
+		convert = calicoNetPolicy
+	default:
+		e := "Unsupported network policy format"
+		log.WithFields(log.Fields{"format": rconf.Format}).Error(e)
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+		return
+	}
+
+	groups := rconf.Groups
+	if len(groups) == 0 {
+		if rconf.Namespace == "" {
+			e := "Either groups or namespace must be specified"
+			log.Error(e)
+			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+			return
+		}
+		for _, list := range cacher.GetAllGroups(share.ScopeLocal, "", false, acc) {
+			for _, g := range list {
+				if g.Domain == rconf.Namespace && g.Kind == share.GroupKindContainer {
+					groups = append(groups, g.Name)
+				}
+			}
+		}
+		sort.Strings(groups)
+	}
+
+	resp := netPolicyList{ApiVersion: "v1", Kind: "List", Items: make([]*netPolicyObject, 0, len(groups))}
+	for _, gname := range groups {
+		if npg := buildNetPolicyGroup(gname, rconf.LearnedOnly, acc); npg != nil {
+			resp.Items = append(resp.Items, convert(npg))
+		}
+	}
+
+	// tell the browser the returned content should be downloaded
+	w.Header().Set("Content-Disposition", "Attachment; filename=networkPolicy.yaml")
+	w.Header().Set("Content-Encoding", "gzip")
+	w.WriteHeader(http.StatusOK)
+	json_data, _ := json.MarshalIndent(resp, "", "  ")
+	data, _ := yaml.JSONToYAML(json_data)
+	data = utils.GzipBytes(data)
+	w.Write(data)
+}
@@ -0,0 +1,154 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func initNetPolicyExportTest() {
+	rule1 := share.CLUSPolicyRule{
+		ID: 10001, From: "nv.web.demo", To: "nv.db.demo", Action: share.PolicyActionAllow,
+		Ports: "tcp/5432", Applications: []uint32{}, CfgType: share.Learned,
+	}
+	rule2 := share.CLUSPolicyRule{
+		ID: 10002, From: api.LearnedExternal, To: "nv.web.demo", Action: share.PolicyActionAllow,
+		Ports: "tcp/80,8000-8080", Applications: []uint32{}, CfgType: share.Learned,
+	}
+	rule3 := share.CLUSPolicyRule{
+		ID: 1, From: "nv.web.demo", To: "nv.db.demo", Action: share.PolicyActionDeny,
+		Ports: api.PolicyPortAny, Applications: []uint32{}, CfgType: share.UserCreated,
+	}
+	rule4 := share.CLUSPolicyRule{
+		ID: 2, From: "nv.web.demo", To: "vendor", Action: share.PolicyActionAllow,
+		Ports: "tcp/443", Applications: []uint32{}, CfgType: share.UserCreated,
+	}
+	initRules := []*share.CLUSPolicyRule{&rule1, &rule2, &rule3, &rule4}
+	initGroups := []*share.CLUSGroup{
+		&share.CLUSGroup{Name: "nv.web.demo", CfgType: share.Learned},
+		&share.CLUSGroup{Name: "nv.db.demo", CfgType: share.Learned},
+		&share.CLUSGroup{Name: api.LearnedExternal, CfgType: share.Learned},
+		&share.CLUSGroup{Name: "vendor", CfgType: share.UserCreated},
+	}
+	var mockCluster kv.MockCluster
+	initKvAndCache(&mockCluster, initRules, initGroups)
+
+	mc := cacher.(*mockCache)
+	mc.groups["nv.web.demo"].Criteria = []api.RESTCriteriaEntry{
+		{Key: "app", Value: "web", Op: share.CriteriaOpEqual},
+		{Key: share.CriteriaKeyDomain, Value: "demo", Op: share.CriteriaOpEqual},
+	}
+	mc.groups["nv.web.demo"].PolicyRules = []uint32{1, 2, 10001, 10002}
+	mc.groups["nv.db.demo"].Criteria = []api.RESTCriteriaEntry{
+		{Key: "app", Value: "db", Op: share.CriteriaOpEqual},
+		{Key: share.CriteriaKeyDomain, Value: "demo", Op: share.CriteriaOpEqual},
+	}
+	mc.groups["nv.db.demo"].PolicyRules = []uint32{1, 10001}
+	mc.groups["vendor"].Criteria = []api.RESTCriteriaEntry{
+		{Key: share.CriteriaKeyAddress, Value: "api.vendor.com", Op: share.CriteriaOpEqual},
+		{Key: share.CriteriaKeyAddress, Value: "10.1.1.1", Op: share.CriteriaOpEqual},
+	}
+}
+
+func netPolicyExport(t *testing.T, rconf *api.RESTNetPolicyExport) *netPolicyList {
+	body, _ := json.Marshal(rconf)
+	w := restCall("POST", "/v1/file/network_policy", body, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Fatalf("Export network policy: status=%d", w.status)
+	}
+
+	var resp netPolicyList
+	if err := yaml.Unmarshal(utils.GunzipBytes(w.body), &resp); err != nil {
+		t.Fatalf("Failed to parse exported policy: %v", err)
+	}
+	return &resp
+}
+
+func TestNetPolicyExportKubernetes(t *testing.T) {
+	preTest()
+	initNetPolicyExportTest()
+
+	resp := netPolicyExport(t, &api.RESTNetPolicyExport{Groups: []string{"nv.web.demo", "nv.db.demo"}})
+	if len(resp.Items) != 2 {
+		t.Fatalf("Unexpected policy count: %d", len(resp.Items))
+	}
+
+	var web, db k8sNetPolicySpec
+	value, _ := json.Marshal(resp.Items[0].Spec)
+	json.Unmarshal(value, &web)
+	value, _ = json.Marshal(resp.Items[1].Spec)
+	json.Unmarshal(value, &db)
+
+	if resp.Items[0].Metadata.Name != "nv.web.demo" || resp.Items[0].Metadata.Namespace != "demo" {
+		t.Errorf("Unexpected metadata: %+v", resp.Items[0].Metadata)
+	}
+	if web.PodSelector.MatchLabels["app"] != "web" {
+		t.Errorf("Unexpected pod selector: %+v", web.PodSelector)
+	}
+	// from external: tcp/80, tcp and udp 8000-8080
+	if len(web.Ingress) != 1 || len(web.Ingress[0].From) != 2 || web.Ingress[0].From[0].IPBlock == nil ||
+		len(web.Ingress[0].Ports) != 3 || *web.Ingress[0].Ports[1].EndPort != 8080 {
+		t.Errorf("Unexpected ingress: %+v", web.Ingress)
+	}
+	// to db, vendor ip. Deny rule and fqdn are not exported
+	if len(web.Egress) != 2 || web.Egress[0].To[0].IPBlock == nil || web.Egress[0].To[0].IPBlock.CIDR != "10.1.1.1/32" {
+		t.Errorf("Unexpected egress: %+v", web.Egress)
+	}
+	if len(db.Ingress) != 1 || db.Ingress[0].From[0].PodSelector.MatchLabels["app"] != "web" ||
+		db.Ingress[0].From[0].NamespaceSelector.MatchLabels[k8sNamespaceLabel] != "demo" ||
+		*db.Ingress[0].Ports[0].Port != 5432 {
+		t.Errorf("Unexpected ingress: %+v", db.Ingress)
+	}
+	if len(db.Egress) != 0 {
+		t.Errorf("Unexpected egress: %+v", db.Egress)
+	}
+
+	resp = netPolicyExport(t, &api.RESTNetPolicyExport{Groups: []string{"nv.web.demo"}, LearnedOnly: true})
+	value, _ = json.Marshal(resp.Items[0].Spec)
+	json.Unmarshal(value, &web)
+	if len(web.Egress) != 1 {
+		t.Errorf("Unexpected egress of learned rules: %+v", web.Egress)
+	}
+
+	postTest()
+}
+
+func TestNetPolicyExportCiliumCalico(t *testing.T) {
+	preTest()
+	initNetPolicyExportTest()
+
+	resp := netPolicyExport(t, &api.RESTNetPolicyExport{Groups: []string{"nv.web.demo"}, Format: api.NetPolicyExportCilium})
+	var cilium ciliumNetPolicySpec
+	value, _ := json.Marshal(resp.Items[0].Spec)
+	json.Unmarshal(value, &cilium)
+	if resp.Items[0].Kind != "CiliumNetworkPolicy" || len(cilium.Egress) != 2 ||
+		len(cilium.Egress[0].ToFQDNs) != 1 || cilium.Egress[0].ToFQDNs[0].MatchName != "api.vendor.com" {
+		t.Errorf("Unexpected cilium egress: %+v", cilium.Egress)
+	}
+	if len(cilium.Ingress) != 1 || cilium.Ingress[0].FromEntities[0] != "world" {
+		t.Errorf("Unexpected cilium ingress: %+v", cilium.Ingress)
+	}
+
+	resp = netPolicyExport(t, &api.RESTNetPolicyExport{Groups: []string{"nv.db.demo"}, Format: api.NetPolicyExportCalico})
+	var calico calicoNetPolicySpec
+	value, _ = json.Marshal(resp.Items[0].Spec)
+	json.Unmarshal(value, &calico)
+	if calico.Selector != "app == 'db'" || len(calico.Ingress) != 1 || calico.Ingress[0].Protocol != "TCP" ||
+		calico.Ingress[0].Source.Selector != "app == 'web'" {
+		t.Errorf("Unexpected calico policy: %+v", calico)
+	}
+
+	body, _ := json.Marshal(&api.RESTNetPolicyExport{Groups: []string{"nv.db.demo"}, Format: "istio"})
+	if w := restCall("POST", "/v1/file/network_policy", body, api.UserRoleAdmin); w.status != http.StatusBadRequest {
+		t.Errorf("Unsupported format: status=%d", w.status)
+	}
+
+	postTest()
+}
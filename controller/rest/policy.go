@@ -120,6 +120,76 @@ func handlerPolicyRuleList(w http.ResponseWriter, r *http.Request, ps httprouter
 	restRespSuccess(w, r, &resp, acc, login, nil, "Get policy rule list")
 }
 
+const defaultUnusedRuleDays = 30
+
+func validateRuleHitType(w http.ResponseWriter, query *restQuery) (string, bool) {
+	ruleType, _ := query.pairs[api.QueryKeyRuleType]
+	switch ruleType {
+	case "", api.RuleHitTypeNetwork, api.RuleHitTypeProcess, api.RuleHitTypeAdmission:
+		return ruleType, true
+	}
+	e := "Invalid rule type"
+	log.WithFields(log.Fields{"type": ruleType}).Error(e)
+	restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+	return "", false
+}
+
+func handlerRuleHitList(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	query := restParseQuery(r)
+	ruleType, ok := validateRuleHitType(w, query)
+	if !ok {
+		return
+	}
+
+	var resp api.RESTRuleHitsData
+	resp.CountingSince, resp.Rules = cacher.GetRuleHits(ruleType, 0, acc)
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get rule hits")
+}
+
+// Rules that are not hit in the last days. The result is not complete if the controller has not been counting
+// the hits for the whole period.
+func handlerUnusedRuleList(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	query := restParseQuery(r)
+	ruleType, ok := validateRuleHitType(w, query)
+	if !ok {
+		return
+	}
+
+	days := defaultUnusedRuleDays
+	if value, ok := query.pairs[api.QueryKeyDays]; ok {
+		if d, err := strconv.Atoi(value); err != nil || d <= 0 || d > 365 {
+			e := "Invalid number of days"
+			log.WithFields(log.Fields{"days": value}).Error(e)
+			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+			return
+		} else {
+			days = d
+		}
+	}
+
+	idleSince := time.Now().Add(-time.Duration(days) * time.Hour * 24).Unix()
+	resp := api.RESTUnusedRulesData{Days: uint32(days)}
+	resp.CountingSince, resp.Rules = cacher.GetRuleHits(ruleType, idleSince, acc)
+	resp.Complete = resp.CountingSince <= idleSince
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get unused rules")
+}
+
 func handlerPolicyRuleShow(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
 	defer r.Body.Close()
@@ -406,6 +476,29 @@ func validateGroupForPolicy(ruleCfgType share.TCfgType, group string, groupMap m
 // Validate a cluster policy rule; fill in host id information if the group is a managed host
 func validateClusterPolicyRule(r *share.CLUSPolicyRule,
 	groupMap map[string]*share.CLUSGroup, hosts []*api.RESTHost) error {
+	if r.RateLimit > 0 {
+		if r.Action != share.PolicyActionAllow {
+			err := errors.New("Rate limit only applies to allow rule")
+			log.WithFields(log.Fields{"id": r.ID, "action": r.Action}).Error(err.Error())
+			return err
+		} else if r.RateLimitUnit != share.PolicyRateUnitBytes && r.RateLimitUnit != share.PolicyRateUnitConnections {
+			err := errors.New("Invalid rate limit unit")
+			log.WithFields(log.Fields{"id": r.ID, "unit": r.RateLimitUnit}).Error(err.Error())
+			return err
+		}
+	}
+	if r.ExpireAt != 0 {
+		// Expired rules are disabled by the lead controller, which doesn't own the federal, CRD and learned rules
+		if r.CfgType == share.FederalCfg || r.CfgType == share.GroundCfg || r.CfgType == share.Learned {
+			err := errors.New("Expiration time is not supported by federal, CRD or learned rule")
+			log.WithFields(log.Fields{"id": r.ID, "cfgType": r.CfgType}).Error(err.Error())
+			return err
+		} else if r.ExpireAt < 0 || (!r.Disable && r.ExpireAt <= time.Now().Unix()) {
+			err := errors.New("Expiration time must be in the future")
+			log.WithFields(log.Fields{"id": r.ID, "expire": r.ExpireAt}).Error(err.Error())
+			return err
+		}
+	}
 	if groupMap != nil {
 		var fromSpecial, toSpecial bool
 		var err error
@@ -466,6 +559,13 @@ func validateRestPolicyRuleConfig(r *api.RESTPolicyRuleConfig) error {
 		}
 	}
 
+	if r.RateLimitUnit != nil {
+		if *r.RateLimitUnit != share.PolicyRateUnitBytes && *r.RateLimitUnit != share.PolicyRateUnitConnections {
+			log.WithFields(log.Fields{"id": r.ID, "unit": *r.RateLimitUnit}).Error("Invalid rate limit unit")
+			return errors.New("Invalid rate limit unit")
+		}
+	}
+
 	if r.Priority != 0 {
 		if r.Priority < 0 || r.Priority > 100 {
 			log.WithFields(log.Fields{"id": r.ID, "Priority": r.Priority}).Error("Prioty out of range [0-100]")
@@ -490,6 +590,9 @@ func validateRestPolicyRule(r *api.RESTPolicyRule) error {
 		Ports:        &r.Ports,
 		Applications: &r.Applications,
 	}
+	if r.RateLimit > 0 {
+		rc.RateLimitUnit = &r.RateLimitUnit
+	}
 	return validateRestPolicyRuleConfig(rc)
 }
 
@@ -504,6 +607,11 @@ func policyRule2Cluster(r *api.RESTPolicyRule) *share.CLUSPolicyRule {
 		Action:       r.Action,
 		Disable:      r.Disable,
 	}
+	if r.RateLimit > 0 {
+		rule.RateLimit = r.RateLimit
+		rule.RateLimitUnit = r.RateLimitUnit
+	}
+	rule.ExpireAt = r.ExpireTS
 	rule.CfgType, _ = cfgTypeMapping[r.CfgType]
 	return rule
 }
@@ -517,6 +625,43 @@ func policyRuleConf2Cluster(r *api.RESTPolicyRuleConfig) *share.CLUSPolicyRule {
 	}
 }
 
+// Modified fields in rc are applied to cr
+func applyPolicyRuleConfig(cr *share.CLUSPolicyRule, rc *api.RESTPolicyRuleConfig) {
+	if rc.From != nil {
+		cr.From = *rc.From
+	}
+	if rc.To != nil {
+		cr.To = *rc.To
+	}
+	if rc.Ports != nil {
+		cr.Ports = *rc.Ports
+	}
+	if rc.Applications != nil {
+		cr.Applications = appNames2IDs(*rc.Applications)
+	}
+	if rc.Action != nil {
+		cr.Action = *rc.Action
+	}
+	if rc.Comment != nil {
+		cr.Comment = *rc.Comment
+	}
+	if rc.Disable != nil {
+		cr.Disable = *rc.Disable
+	}
+	if rc.RateLimit != nil {
+		cr.RateLimit = *rc.RateLimit
+	}
+	if rc.RateLimitUnit != nil {
+		cr.RateLimitUnit = *rc.RateLimitUnit
+	}
+	if cr.RateLimit == 0 {
+		cr.RateLimitUnit = ""
+	}
+	if rc.ExpireTS != nil {
+		cr.ExpireAt = *rc.ExpireTS
+	}
+}
+
 func deletePolicyRules(txn *cluster.ClusterTransact, dels utils.Set) {
 	for id := range dels.Iter() {
 		clusHelper.DeletePolicyRuleTxn(txn, id.(uint32))
@@ -1457,6 +1602,9 @@ func handlerPolicyRuleAction(w http.ResponseWriter, r *http.Request, ps httprout
 		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
 		return
 	}
+	if !checkPolicyChangeApproval(w, scope) {
+		return
+	}
 
 	dataChanged := false
 	if rconf.Move != nil {
@@ -1544,9 +1692,11 @@ func handlerPolicyRuleConfig(w http.ResponseWriter, r *http.Request, ps httprout
 		return
 	}
 
-	if _, err := cacher.GetPolicyRuleCache(rconf.Config.ID, acc); err != nil {
+	if crule, err := cacher.GetPolicyRuleCache(rconf.Config.ID, acc); err != nil {
 		restRespNotFoundLogAccessDenied(w, login, err)
 		return
+	} else if crule.CfgType != share.FederalCfg && !checkPolicyChangeApproval(w, share.ScopeLocal) {
+		return
 	}
 
 	lock, err := clusHelper.AcquireLock(share.CLUSLockPolicyKey, clusterLockWait)
@@ -1605,27 +1755,7 @@ func handlerPolicyRuleConfig(w http.ResponseWriter, r *http.Request, ps httprout
 		return
 	}
 
-	if rc.From != nil {
-		cconf.From = *rc.From
-	}
-	if rc.To != nil {
-		cconf.To = *rc.To
-	}
-	if rc.Ports != nil {
-		cconf.Ports = *rc.Ports
-	}
-	if rc.Applications != nil {
-		cconf.Applications = appNames2IDs(*rc.Applications)
-	}
-	if rc.Action != nil {
-		cconf.Action = *rc.Action
-	}
-	if rc.Comment != nil {
-		cconf.Comment = *rc.Comment
-	}
-	if rc.Disable != nil {
-		cconf.Disable = *rc.Disable
-	}
+	applyPolicyRuleConfig(cconf, rc)
 
 	accReadAll := access.NewReaderAccessControl()
 	groups := clusHelper.GetAllGroups(scope, accReadAll)
@@ -1777,6 +1907,9 @@ func handlerPolicyRuleDelete(w http.ResponseWriter, r *http.Request, ps httprout
 			scope = share.ScopeFed
 		}
 	}
+	if !checkPolicyChangeApproval(w, scope) {
+		return
+	}
 
 	// No need to authorize again as it's done in the GetPolicyRuleCache()
 	if deleted, err := deletePolicyRule(scope, w, r, []uint32{uint32(id)}, acc); err == nil {
@@ -1808,6 +1941,9 @@ func handlerPolicyRuleDeleteAll(w http.ResponseWriter, r *http.Request, ps httpr
 		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
 		return
 	}
+	if !checkPolicyChangeApproval(w, delScope) {
+		return
+	}
 
 	// get all rules that I can modify. If I'm fedAdmin, all rules are returned. If I'm admin, only local rules are returned
 	crules := cacher.GetAllPolicyRulesCache(acc)
@@ -1888,6 +2024,255 @@ func handlerPolicyRuleDeleteAll(w http.ResponseWriter, r *http.Request, ps httpr
 	restRespSuccess(w, r, nil, acc, login, nil, "Delete all policy rules")
 }
 
+// Validate one operation of a batch request. It returns the rule to write for create/update operations
+func batchPolicyRuleOp(w http.ResponseWriter, i int, op *api.RESTPolicyRuleBatchOp, ids, opIDs utils.Set, groups map[string]*share.CLUSGroup,
+	hosts []*api.RESTHost, acc *access.AccessControl, login *loginSession) (*share.CLUSPolicyRule, error) {
+
+	badRequest := func(code int, err error) error {
+		e := fmt.Errorf("Operation %d: %s", i, err.Error())
+		log.Error(e)
+		restRespErrorMessage(w, http.StatusBadRequest, code, e.Error())
+		return e
+	}
+	getGroup := func(g string) share.AccessObject {
+		if cg, ok := groups[g]; ok {
+			return cg
+		} else {
+			return nil
+		}
+	}
+
+	var id uint32
+	switch op.Op {
+	case api.BatchOpCreate:
+		if op.Rule != nil {
+			id = op.Rule.ID
+		}
+	case api.BatchOpUpdate:
+		if op.Config != nil {
+			id = op.Config.ID
+		}
+	case api.BatchOpDelete:
+		id = op.ID
+	}
+	if id != api.PolicyAutoID {
+		if opIDs.Contains(id) {
+			return nil, badRequest(api.RESTErrInvalidRequest, fmt.Errorf("Rule %d is in multiple operations", id))
+		}
+		opIDs.Add(id)
+	}
+
+	now := time.Now().UTC()
+	switch op.Op {
+	case api.BatchOpCreate:
+		rr := op.Rule
+		if rr == nil {
+			return nil, badRequest(api.RESTErrInvalidRequest, errors.New("Missing rule"))
+		} else if rr.CfgType == "" {
+			rr.CfgType = api.CfgTypeUserCreated
+		}
+		if rr.CfgType != api.CfgTypeUserCreated {
+			return nil, badRequest(api.RESTErrOpNotAllowed, errors.New("Only user created rules can be created in batch"))
+		}
+		if ids.Contains(rr.ID) {
+			return nil, badRequest(api.RESTErrInvalidRequest, errors.New("Duplicate rule ID"))
+		}
+		if e := isLocalReservedId(rr.ID); e != nil {
+			return nil, badRequest(api.RESTErrInvalidRequest, e)
+		}
+		if rr.ID == api.PolicyAutoID {
+			if rr.ID = common.GetAvailablePolicyID(ids, share.UserCreated); rr.ID == 0 {
+				return nil, badRequest(api.RESTErrInvalidRequest, errors.New("Failed to locate available rule ID"))
+			}
+			opIDs.Add(rr.ID)
+		}
+		if err := validateRestPolicyRule(rr); err != nil {
+			return nil, badRequest(api.RESTErrInvalidRequest, err)
+		}
+
+		cr := policyRule2Cluster(rr)
+		cr.CreatedAt = now
+		cr.LastModAt = now
+		if err := validateClusterPolicyRule(cr, groups, hosts); err != nil {
+			return nil, badRequest(api.RESTErrInvalidRequest, err)
+		}
+		if !acc.Authorize(cr, getGroup) {
+			restRespAccessDenied(w, login)
+			return nil, common.ErrObjectAccessDenied
+		}
+		ids.Add(cr.ID)
+		return cr, nil
+	case api.BatchOpUpdate:
+		rc := op.Config
+		if rc == nil {
+			return nil, badRequest(api.RESTErrInvalidRequest, errors.New("Missing rule config"))
+		}
+		if isSecurityPolicyID(rc.ID) || isFedPolicyID(rc.ID) {
+			return nil, badRequest(api.RESTErrOpNotAllowed, fmt.Errorf("Rule %d can't be modified in batch", rc.ID))
+		}
+		if e := isLocalReservedId(rc.ID); e != nil {
+			return nil, badRequest(api.RESTErrInvalidRequest, e)
+		}
+		cr, _ := clusHelper.GetPolicyRule(rc.ID)
+		if cr == nil || !ids.Contains(rc.ID) {
+			e := fmt.Errorf("Operation %d: Policy rule %d doesn't exist", i, rc.ID)
+			log.Error(e)
+			restRespErrorMessage(w, http.StatusNotFound, api.RESTErrObjectNotFound, e.Error())
+			return nil, e
+		} else if cr.CfgType == share.Learned {
+			return nil, badRequest(api.RESTErrInvalidRequest, errors.New("Learned policy rule cannot be modified"))
+		}
+		if err := validateRestPolicyRuleConfig(rc); err != nil {
+			return nil, badRequest(api.RESTErrInvalidRequest, err)
+		}
+
+		applyPolicyRuleConfig(cr, rc)
+		cr.LastModAt = now
+		if err := validateClusterPolicyRule(cr, groups, hosts); err != nil {
+			return nil, badRequest(api.RESTErrInvalidRequest, err)
+		}
+		if !acc.Authorize(cr, getGroup) {
+			restRespAccessDenied(w, login)
+			return nil, common.ErrObjectAccessDenied
+		}
+		return cr, nil
+	case api.BatchOpDelete:
+		if isSecurityPolicyID(op.ID) || isFedPolicyID(op.ID) {
+			return nil, badRequest(api.RESTErrOpNotAllowed, fmt.Errorf("Rule %d can't be deleted in batch", op.ID))
+		}
+		// No need to authorize again as it's done in the GetPolicyRuleCache()
+		if _, err := cacher.GetPolicyRuleCache(op.ID, acc); err != nil || !ids.Contains(op.ID) {
+			if err == nil {
+				err = common.ErrObjectNotFound
+			}
+			restRespNotFoundLogAccessDenied(w, login, err)
+			return nil, err
+		}
+		// keep the id in ids so that it's not re-used by the new rules in the same batch
+		return nil, nil
+	default:
+		return nil, badRequest(api.RESTErrInvalidRequest, fmt.Errorf("Unsupported operation %s", op.Op))
+	}
+}
+
+// Create/update/delete local network rules with one kv transaction, so policy is recalculated once for the whole batch
+func handlerPolicyRuleBatch(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.HasRequiredPermissions() {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	// Read request
+	body, _ := ioutil.ReadAll(r.Body)
+	var rconf api.RESTPolicyRuleBatchData
+	err := json.Unmarshal(body, &rconf)
+	if err != nil || len(rconf.Ops) == 0 {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+	if !checkPolicyChangeApproval(w, share.ScopeLocal) {
+		return
+	}
+
+	lock, err := clusHelper.AcquireLock(share.CLUSLockPolicyKey, clusterLockWait)
+	if err != nil {
+		e := "Failed to acquire cluster lock"
+		log.WithFields(log.Fields{"error": err}).Error(e)
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFailLockCluster, e)
+		return
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	// Read ID list from cluster
+	crhs := clusHelper.GetPolicyRuleList()
+	ids := utils.NewSet()
+	for _, crh := range crhs {
+		ids.Add(crh.ID)
+	}
+
+	accReadAll := access.NewReaderAccessControl()
+	groups := clusHelper.GetAllGroups(share.ScopeLocal, accReadAll)
+	hosts := cacher.GetAllHosts(accReadAll)
+
+	// Validate all operations before writing anything
+	opIDs := utils.NewSet()
+	writes := make([]*share.CLUSPolicyRule, 0, len(rconf.Ops))
+	news := make([]*share.CLUSRuleHead, 0)
+	dels := utils.NewSet()
+	resp := api.RESTPolicyRuleBatchCreatedData{IDs: make([]uint32, 0)}
+	for i, op := range rconf.Ops {
+		if op == nil {
+			continue
+		}
+		cr, err := batchPolicyRuleOp(w, i, op, ids, opIDs, groups, hosts, acc, login)
+		if err != nil {
+			return
+		}
+		switch op.Op {
+		case api.BatchOpCreate:
+			news = append(news, &share.CLUSRuleHead{ID: cr.ID, CfgType: share.UserCreated})
+			resp.IDs = append(resp.IDs, cr.ID)
+			writes = append(writes, cr)
+		case api.BatchOpUpdate:
+			writes = append(writes, cr)
+		case api.BatchOpDelete:
+			dels.Add(op.ID)
+		}
+	}
+
+	txn := cluster.Transact()
+	defer txn.Close()
+
+	if err := applyPolicyRuleBatch(w, txn, crhs, writes, news, dels); err != nil {
+		return
+	}
+
+	restRespSuccess(w, r, &resp, acc, login, &rconf, "Batch configure policy rules")
+}
+
+// Write the rules and the rule list with one kv transaction. New rules are appended to the end of the rule list.
+// The caller can add other entries to the transaction before calling it.
+func applyPolicyRuleBatch(w http.ResponseWriter, txn *cluster.ClusterTransact, crhs []*share.CLUSRuleHead,
+	writes []*share.CLUSPolicyRule, news []*share.CLUSRuleHead, dels utils.Set) error {
+
+	writePolicyRules(txn, writes)
+	deletePolicyRules(txn, dels)
+	if len(news) > 0 || dels.Cardinality() > 0 {
+		crhsNew := make([]*share.CLUSRuleHead, 0, len(crhs)+len(news))
+		for _, crh := range crhs {
+			if !dels.Contains(crh.ID) {
+				crhsNew = append(crhsNew, crh)
+			}
+		}
+		clusHelper.PutPolicyRuleListTxn(txn, append(crhsNew, news...))
+	}
+
+	if txn.Size() > cluster.KVTransactEntriesMax {
+		e := "Too many operations to apply atomically"
+		log.WithFields(log.Fields{"writes": len(writes), "deletes": dels.Cardinality(), "entries": txn.Size()}).Error(e)
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+		return errors.New(e)
+	}
+	if ok, err := txn.Apply(); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("")
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return err
+	} else if !ok {
+		e := "Atomic write to the cluster failed"
+		log.Error(e)
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster, e)
+		return errors.New(e)
+	}
+	return nil
+}
+
 func derivedPolicy2Rest(r *share.CLUSDerivedPolicyRule) []*api.RESTDerivedPolicyRule {
 	p := &api.RESTDerivedPolicyRule{
 		ID:          r.ID,
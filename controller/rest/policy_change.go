@@ -0,0 +1,493 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/cluster"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// The changes of the local network rules that are staged for approval. When the policy change approval is enabled
+// in the system config, the local network rules cannot be modified directly. A user submits the changes in the
+// format of the batch API, then another user with the policy approval permission approves them, which applies all
+// the changes in one transaction, or rejects them. The federal rules and CRD rules are not covered.
+
+func isPolicyChangeApprovalEnabled() bool {
+	return cacher.GetSystemConfig(access.NewReaderAccessControl()).PolicyChangeApproval
+}
+
+// Reject the direct modification of the local network rules when the changes must be approved
+func checkPolicyChangeApproval(w http.ResponseWriter, scope string) bool {
+	if scope == share.ScopeLocal && isPolicyChangeApprovalEnabled() {
+		e := "Network rule changes must be submitted for approval"
+		log.Error(e)
+		restRespErrorMessage(w, http.StatusForbidden, api.RESTErrOpNotAllowed, e)
+		return false
+	}
+	return true
+}
+
+// The policy section of the imported configuration replaces the local network rules. No section means all.
+func checkImportPolicyChangeApproval(w http.ResponseWriter, sections []string) bool {
+	if len(sections) > 0 && !utils.NewSetFromSliceKind(sections).Contains(api.ConfSectionPolicy) {
+		return true
+	}
+	return checkPolicyChangeApproval(w, share.ScopeLocal)
+}
+
+// Return the modified fields of a rule, in the names of the REST API
+func policyRuleChanges(before, after *share.CLUSPolicyRule) []string {
+	changes := make([]string, 0)
+	if before.Comment != after.Comment {
+		changes = append(changes, "comment")
+	}
+	if before.From != after.From {
+		changes = append(changes, "from")
+	}
+	if before.To != after.To {
+		changes = append(changes, "to")
+	}
+	if before.Ports != after.Ports {
+		changes = append(changes, "ports")
+	}
+	if before.Action != after.Action {
+		changes = append(changes, "action")
+	}
+	if !reflect.DeepEqual(before.Applications, after.Applications) {
+		changes = append(changes, "applications")
+	}
+	if before.Disable != after.Disable {
+		changes = append(changes, "disable")
+	}
+	if before.RateLimit != after.RateLimit || before.RateLimitUnit != after.RateLimitUnit {
+		changes = append(changes, "rate_limit")
+	}
+	if before.ExpireAt != after.ExpireAt {
+		changes = append(changes, "expire_timestamp")
+	}
+	return changes
+}
+
+// The rule of an update or delete operation must not be modified after the change is submitted. For create
+// operation with the rule ID, the ID must not be used.
+func isPolicyChangeOpConflict(op *share.CLUSPolicyChangeOp, cur *share.CLUSPolicyRule) bool {
+	if op.Op == api.BatchOpCreate {
+		return op.ID != 0 && cur != nil
+	}
+	return cur == nil || op.Base == nil || !cur.LastModAt.Equal(op.Base.LastModAt)
+}
+
+func policyChange2REST(pc *share.CLUSPolicyChange) *api.RESTPolicyChange {
+	rpc := &api.RESTPolicyChange{
+		ID:            pc.ID,
+		Comment:       pc.Comment,
+		Status:        pc.Status,
+		SubmittedBy:   pc.SubmittedBy,
+		SubmittedAt:   pc.SubmittedAt,
+		ReviewedBy:    pc.ReviewedBy,
+		ReviewedAt:    pc.ReviewedAt,
+		ReviewComment: pc.ReviewComment,
+		CreatedIDs:    pc.CreatedIDs,
+		Ops:           make([]*api.RESTPolicyChangeOp, 0, len(pc.Ops)),
+	}
+	if rpc.CreatedIDs == nil {
+		rpc.CreatedIDs = make([]uint32, 0)
+	}
+	for _, op := range pc.Ops {
+		rop := &api.RESTPolicyChangeOp{Op: op.Op, ID: op.ID, Changes: make([]string, 0)}
+		if op.Base != nil {
+			rop.Before = cacher.PolicyRule2REST(op.Base)
+		}
+		if op.Rule != nil {
+			rop.After = cacher.PolicyRule2REST(op.Rule)
+		}
+		if op.Op == api.BatchOpUpdate && op.Base != nil && op.Rule != nil {
+			rop.Changes = policyRuleChanges(op.Base, op.Rule)
+		}
+		// Only the pending changes can conflict with the current rules
+		if pc.Status == share.PolicyChangePending && op.ID != 0 {
+			cur, _ := clusHelper.GetPolicyRule(op.ID)
+			rop.Conflict = isPolicyChangeOpConflict(op, cur)
+		}
+		rpc.Ops = append(rpc.Ops, rop)
+	}
+	return rpc
+}
+
+// The change must be reviewed by a user other than the submitter
+func reviewPolicyChange(pc *share.CLUSPolicyChange, reviewer string, approve bool, comment string, now time.Time) error {
+	if pc.Status != share.PolicyChangePending {
+		return fmt.Errorf("Change is already %s", pc.Status)
+	}
+	if reviewer == pc.SubmittedBy {
+		return errors.New("Change cannot be reviewed by the submitter")
+	}
+
+	if approve {
+		pc.Status = share.PolicyChangeApproved
+	} else {
+		pc.Status = share.PolicyChangeRejected
+	}
+	pc.ReviewedBy = reviewer
+	pc.ReviewedAt = now
+	pc.ReviewComment = comment
+	return nil
+}
+
+// Only the pending change can be withdrawn, by the submitter or an admin. The reviewed changes are kept as records.
+func deletePolicyChangeCheck(pc *share.CLUSPolicyChange, user string, admin bool) error {
+	if pc.Status != share.PolicyChangePending {
+		return fmt.Errorf("Change is already %s", pc.Status)
+	}
+	if user != pc.SubmittedBy && !admin {
+		return errors.New("Change can only be deleted by the submitter or an admin")
+	}
+	return nil
+}
+
+// Validate the operations against the current rules and groups and return the writes of the batch. The rules
+// are validated again as the groups can be removed after the change is submitted. policy lock is owned by caller.
+func preparePolicyChange(pc *share.CLUSPolicyChange, crhs []*share.CLUSRuleHead, now time.Time) (
+	[]*share.CLUSPolicyRule, []*share.CLUSRuleHead, utils.Set, []uint32, error) {
+
+	ids := utils.NewSet()
+	for _, crh := range crhs {
+		ids.Add(crh.ID)
+	}
+
+	accReadAll := access.NewReaderAccessControl()
+	groups := clusHelper.GetAllGroups(share.ScopeLocal, accReadAll)
+	hosts := cacher.GetAllHosts(accReadAll)
+
+	writes := make([]*share.CLUSPolicyRule, 0, len(pc.Ops))
+	news := make([]*share.CLUSRuleHead, 0)
+	dels := utils.NewSet()
+	created := make([]uint32, 0)
+	for i, op := range pc.Ops {
+		var cur *share.CLUSPolicyRule
+		if op.ID != 0 {
+			cur, _ = clusHelper.GetPolicyRule(op.ID)
+		}
+		if isPolicyChangeOpConflict(op, cur) {
+			return nil, nil, nil, nil, fmt.Errorf("Operation %d: Rule %d is changed after the change is submitted", i, op.ID)
+		}
+
+		switch op.Op {
+		case api.BatchOpCreate, api.BatchOpUpdate:
+			cr := *op.Rule
+			if op.Op == api.BatchOpCreate {
+				if cr.ID == 0 {
+					if cr.ID = common.GetAvailablePolicyID(ids, share.UserCreated); cr.ID == 0 {
+						return nil, nil, nil, nil, errors.New("Failed to locate available rule ID")
+					}
+				}
+				cr.CreatedAt = now
+				ids.Add(cr.ID)
+				news = append(news, &share.CLUSRuleHead{ID: cr.ID, CfgType: share.UserCreated})
+				created = append(created, cr.ID)
+			} else if !ids.Contains(cr.ID) {
+				return nil, nil, nil, nil, fmt.Errorf("Operation %d: Rule %d is not in the rule list", i, cr.ID)
+			}
+			cr.LastModAt = now
+			if err := validateClusterPolicyRule(&cr, groups, hosts); err != nil {
+				return nil, nil, nil, nil, fmt.Errorf("Operation %d: %s", i, err.Error())
+			}
+			writes = append(writes, &cr)
+		case api.BatchOpDelete:
+			dels.Add(op.ID)
+		}
+	}
+	return writes, news, dels, created, nil
+}
+
+// The operations are validated in the same way as the batch API, without writing the rules. The ID of the rule
+// to be created is 0 if it is assigned automatically.
+func policyChangeOps(w http.ResponseWriter, rops []*api.RESTPolicyRuleBatchOp, acc *access.AccessControl,
+	login *loginSession) ([]*share.CLUSPolicyChangeOp, error) {
+
+	crhs := clusHelper.GetPolicyRuleList()
+	ids := utils.NewSet()
+	for _, crh := range crhs {
+		ids.Add(crh.ID)
+	}
+
+	accReadAll := access.NewReaderAccessControl()
+	groups := clusHelper.GetAllGroups(share.ScopeLocal, accReadAll)
+	hosts := cacher.GetAllHosts(accReadAll)
+
+	opIDs := utils.NewSet()
+	ops := make([]*share.CLUSPolicyChangeOp, 0, len(rops))
+	for i, op := range rops {
+		if op == nil {
+			continue
+		}
+		autoID := op.Op == api.BatchOpCreate && op.Rule != nil && op.Rule.ID == api.PolicyAutoID
+		cr, err := batchPolicyRuleOp(w, i, op, ids, opIDs, groups, hosts, acc, login)
+		if err != nil {
+			return nil, err
+		}
+		switch op.Op {
+		case api.BatchOpCreate:
+			if autoID {
+				cr.ID = 0
+			}
+			ops = append(ops, &share.CLUSPolicyChangeOp{Op: op.Op, ID: cr.ID, Rule: cr})
+		case api.BatchOpUpdate:
+			base, _ := clusHelper.GetPolicyRule(cr.ID)
+			ops = append(ops, &share.CLUSPolicyChangeOp{Op: op.Op, ID: cr.ID, Rule: cr, Base: base})
+		case api.BatchOpDelete:
+			base, _ := clusHelper.GetPolicyRule(op.ID)
+			ops = append(ops, &share.CLUSPolicyChangeOp{Op: op.Op, ID: op.ID, Base: base})
+		}
+	}
+	return ops, nil
+}
+
+func handlerPolicyChangeList(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	list := clusHelper.GetAllPolicyChanges()
+	resp := api.RESTPolicyChangesData{Changes: make([]*api.RESTPolicyChange, 0, len(list))}
+	for _, pc := range list {
+		if acc.Authorize(pc, nil) {
+			resp.Changes = append(resp.Changes, policyChange2REST(pc))
+		}
+	}
+	sort.Slice(resp.Changes, func(i, j int) bool {
+		return resp.Changes[i].SubmittedAt.After(resp.Changes[j].SubmittedAt)
+	})
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get policy change list")
+}
+
+func handlerPolicyChangeShow(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	pc, _, err := clusHelper.GetPolicyChangeRev(ps.ByName("id"))
+	if err != nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	} else if !acc.Authorize(pc, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	resp := api.RESTPolicyChangeData{Change: policyChange2REST(pc)}
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get policy change")
+}
+
+func handlerPolicyChangeSubmit(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.HasRequiredPermissions() {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	var rconf api.RESTPolicyChangeConfigData
+	body, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(body, &rconf); err != nil || rconf.Config == nil || len(rconf.Config.Ops) == 0 {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	ops, err := policyChangeOps(w, rconf.Config.Ops, acc, login)
+	if err != nil {
+		return
+	}
+
+	id, err := utils.GetGuid()
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error()
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+	pc := &share.CLUSPolicyChange{
+		ID:          id,
+		Comment:     rconf.Config.Comment,
+		Ops:         ops,
+		Status:      share.PolicyChangePending,
+		SubmittedBy: login.fullname,
+		SubmittedAt: time.Now().UTC(),
+	}
+	if err := clusHelper.PutPolicyChangeRev(pc, 0); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error()
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+
+	msg := fmt.Sprintf("Change of %d network rule operations is submitted for approval", len(ops))
+	configLog(share.CLUSEvPolicyChangeSubmitted, login, msg)
+
+	resp := api.RESTPolicyChangeData{Change: policyChange2REST(pc)}
+	restRespSuccess(w, r, &resp, acc, login, &rconf, msg)
+}
+
+func handlerPolicyChangeDelete(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	lock, err := clusHelper.AcquireLock(share.CLUSLockPolicyKey, clusterLockWait)
+	if err != nil {
+		e := "Failed to acquire cluster lock"
+		log.WithFields(log.Fields{"error": err}).Error(e)
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFailLockCluster, e)
+		return
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	id := ps.ByName("id")
+	pc, _, err := clusHelper.GetPolicyChangeRev(id)
+	if err != nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	} else if !acc.Authorize(pc, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+	if err := deletePolicyChangeCheck(pc, login.fullname, acc.CanWriteCluster()); err != nil {
+		log.WithFields(log.Fields{"id": id, "error": err}).Error("Failed to delete policy change")
+		restRespErrorMessage(w, http.StatusForbidden, api.RESTErrOpNotAllowed, err.Error())
+		return
+	}
+	if err := clusHelper.DeletePolicyChange(id); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error()
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+
+	restRespSuccess(w, r, nil, acc, login, nil, fmt.Sprintf("Delete policy change %s", id))
+}
+
+// The change is approved and applied in one transaction, so its status is always consistent with the rules
+func policyChangeReview(w http.ResponseWriter, r *http.Request, ps httprouter.Params, approve bool) {
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	var rconf api.RESTPolicyChangeReviewData
+	body, _ := ioutil.ReadAll(r.Body)
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &rconf); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Request error")
+			restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+			return
+		}
+	}
+	var comment string
+	if rconf.Review != nil {
+		comment = rconf.Review.Comment
+	}
+
+	lock, err := clusHelper.AcquireLock(share.CLUSLockPolicyKey, clusterLockWait)
+	if err != nil {
+		e := "Failed to acquire cluster lock"
+		log.WithFields(log.Fields{"error": err}).Error(e)
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFailLockCluster, e)
+		return
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	id := ps.ByName("id")
+	pc, rev, err := clusHelper.GetPolicyChangeRev(id)
+	if err != nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	} else if !acc.Authorize(pc, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	now := time.Now().UTC()
+	if err := reviewPolicyChange(pc, login.fullname, approve, comment, now); err != nil {
+		log.WithFields(log.Fields{"id": id, "error": err}).Error("Failed to review policy change")
+		restRespErrorMessage(w, http.StatusForbidden, api.RESTErrOpNotAllowed, err.Error())
+		return
+	}
+
+	txn := cluster.Transact()
+	defer txn.Close()
+
+	var msg string
+	if approve {
+		crhs := clusHelper.GetPolicyRuleList()
+		writes, news, dels, created, err := preparePolicyChange(pc, crhs, now)
+		if err != nil {
+			log.WithFields(log.Fields{"id": id, "error": err}).Error("Failed to approve policy change")
+			restRespErrorMessage(w, http.StatusConflict, api.RESTErrInvalidRequest, err.Error())
+			return
+		}
+		pc.CreatedIDs = created
+		clusHelper.PutPolicyChangeTxn(txn, pc, rev)
+		if err := applyPolicyRuleBatch(w, txn, crhs, writes, news, dels); err != nil {
+			return
+		}
+
+		msg = fmt.Sprintf("Change of %d network rule operations submitted by %s is approved", len(pc.Ops), pc.SubmittedBy)
+		configLog(share.CLUSEvPolicyChangeApproved, login, msg)
+	} else {
+		clusHelper.PutPolicyChangeTxn(txn, pc, rev)
+		if ok, err := txn.Apply(); err != nil || !ok {
+			log.WithFields(log.Fields{"id": id, "error": err, "rev": rev}).Error()
+			restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+			return
+		}
+
+		msg = fmt.Sprintf("Change of %d network rule operations submitted by %s is rejected", len(pc.Ops), pc.SubmittedBy)
+		configLog(share.CLUSEvPolicyChangeRejected, login, msg)
+	}
+
+	resp := api.RESTPolicyChangeData{Change: policyChange2REST(pc)}
+	restRespSuccess(w, r, &resp, acc, login, &rconf, msg)
+}
+
+func handlerPolicyChangeApprove(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	policyChangeReview(w, r, ps, true)
+}
+
+func handlerPolicyChangeReject(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	policyChangeReview(w, r, ps, false)
+}
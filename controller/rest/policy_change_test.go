@@ -0,0 +1,252 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestPolicyRuleChanges(t *testing.T) {
+	before := &share.CLUSPolicyRule{ID: 100, From: "g1", To: "g2", Ports: "any", Action: share.PolicyActionAllow, Applications: []uint32{}}
+	after := *before
+	if changes := policyRuleChanges(before, &after); len(changes) != 0 {
+		t.Errorf("Unexpected changes: %+v", changes)
+	}
+	after.Action = share.PolicyActionDeny
+	after.Ports = "tcp/80"
+	if changes := policyRuleChanges(before, &after); len(changes) != 2 || changes[0] != "ports" || changes[1] != "action" {
+		t.Errorf("Unexpected changes: %+v", changes)
+	}
+
+	now := time.Now().UTC()
+	pc := &share.CLUSPolicyChange{Status: share.PolicyChangePending, SubmittedBy: "alice"}
+	if err := reviewPolicyChange(pc, "alice", true, "", now); err == nil {
+		t.Errorf("Submitter should not review the change")
+	}
+	if err := reviewPolicyChange(pc, "bob", false, "Too broad", now); err != nil || pc.Status != share.PolicyChangeRejected || pc.ReviewedBy != "bob" {
+		t.Errorf("Failed to reject change: %v, %+v", err, pc)
+	}
+	if err := reviewPolicyChange(pc, "carol", true, "", now); err == nil {
+		t.Errorf("Rejected change should not be reviewed again")
+	}
+}
+
+func TestPolicyChangeWorkflow(t *testing.T) {
+	preTest()
+
+	rule1 := share.CLUSPolicyRule{
+		ID: 100, From: "g1", To: "g2", Action: share.PolicyActionAllow,
+		Ports: api.PolicyPortAny, Applications: []uint32{}, CfgType: share.UserCreated,
+	}
+	rule2 := share.CLUSPolicyRule{
+		ID: 101, From: "g2", To: "g1", Action: share.PolicyActionAllow,
+		Ports: api.PolicyPortAny, Applications: []uint32{}, CfgType: share.UserCreated,
+	}
+	groups := []*share.CLUSGroup{
+		&share.CLUSGroup{Name: "g1", CfgType: share.UserCreated},
+		&share.CLUSGroup{Name: "g2", CfgType: share.UserCreated},
+	}
+
+	var mockCluster kv.MockCluster
+	initKvAndCache(&mockCluster, []*share.CLUSPolicyRule{&rule1, &rule2}, groups)
+	cacher.(*mockCache).systemConfig.PolicyChangeApproval = true
+
+	deny := share.PolicyActionDeny
+	ops := []*api.RESTPolicyRuleBatchOp{
+		&api.RESTPolicyRuleBatchOp{Op: api.BatchOpUpdate, Config: &api.RESTPolicyRuleConfig{ID: 100, Action: &deny}},
+		&api.RESTPolicyRuleBatchOp{Op: api.BatchOpDelete, ID: 101},
+		&api.RESTPolicyRuleBatchOp{Op: api.BatchOpCreate, Rule: &api.RESTPolicyRule{
+			From: "g2", To: "g1", Action: share.PolicyActionDeny, Ports: api.PolicyPortAny,
+		}},
+	}
+
+	// Rules cannot be modified directly
+	body, _ := json.Marshal(api.RESTPolicyRuleBatchData{Ops: ops})
+	w := restCall("PATCH", "/v1/policy/rules/batch", body, api.UserRoleAdmin)
+	if w.status != http.StatusForbidden {
+		t.Errorf("Rules should not be modified directly: status=%v", w.status)
+	}
+	w = restCall("POST", "/v1/file/group/config", []byte("{}"), api.UserRoleAdmin)
+	if w.status != http.StatusForbidden {
+		t.Errorf("Rules should not be modified by group import: status=%v", w.status)
+	}
+
+	body, _ = json.Marshal(api.RESTPolicyChangeConfigData{Config: &api.RESTPolicyChangeConfig{Comment: "Block g2", Ops: ops}})
+	w = restCall("POST", "/v1/policy/changes", body, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to submit change: status=%v", w.status)
+	}
+	var resp api.RESTPolicyChangeData
+	json.Unmarshal(w.body, &resp)
+	id := resp.Change.ID
+	if resp.Change.Status != share.PolicyChangePending || resp.Change.SubmittedBy != "admin" || len(resp.Change.Ops) != 3 {
+		t.Fatalf("Unexpected change: %+v", resp.Change)
+	}
+	if op := resp.Change.Ops[0]; op.Before.Action != share.PolicyActionAllow || op.After.Action != share.PolicyActionDeny ||
+		len(op.Changes) != 1 || op.Changes[0] != "action" || op.Conflict {
+		t.Errorf("Unexpected update: %+v", op)
+	}
+	if op := resp.Change.Ops[2]; op.ID != 0 || op.Before != nil || op.After.From != "g2" {
+		t.Errorf("Unexpected create: %+v", op)
+	}
+	if r, _ := clusHelper.GetPolicyRule(100); r.Action != share.PolicyActionAllow {
+		t.Errorf("Rule should not be modified before approval: %+v", r)
+	}
+
+	// the submitter cannot approve it
+	w = restCall("POST", "/v1/policy/changes/"+id+"/approve", nil, api.UserRoleAdmin)
+	if w.status != http.StatusForbidden {
+		t.Errorf("Submitter should not approve change: status=%v", w.status)
+	}
+
+	pc, _, _ := clusHelper.GetPolicyChangeRev(id)
+	pc.SubmittedBy = "alice"
+	clusHelper.PutPolicyChangeRev(pc, 0)
+
+	w = restCall("POST", "/v1/policy/changes/"+id+"/approve", nil, api.UserRoleReader)
+	if w.status != http.StatusForbidden {
+		t.Errorf("Reader should not approve change: status=%v", w.status)
+	}
+	w = restCall("POST", "/v1/policy/changes/"+id+"/approve", nil, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to approve change: status=%v", w.status)
+	}
+	json.Unmarshal(w.body, &resp)
+	if resp.Change.Status != share.PolicyChangeApproved || resp.Change.ReviewedBy != "admin" || len(resp.Change.CreatedIDs) != 1 {
+		t.Fatalf("Unexpected change: %+v", resp.Change)
+	}
+	if r, _ := clusHelper.GetPolicyRule(100); r.Action != share.PolicyActionDeny {
+		t.Errorf("Rule is not modified: %+v", r)
+	}
+	if r, _ := clusHelper.GetPolicyRule(101); r != nil {
+		t.Errorf("Rule is not deleted: %+v", r)
+	}
+	if r, _ := clusHelper.GetPolicyRule(resp.Change.CreatedIDs[0]); r == nil || r.From != "g2" || r.CfgType != share.UserCreated {
+		t.Errorf("Rule is not created: %+v", r)
+	}
+
+	// A change cannot be approved if the rule is modified after it's submitted
+	allow := share.PolicyActionAllow
+	body, _ = json.Marshal(api.RESTPolicyChangeConfigData{Config: &api.RESTPolicyChangeConfig{Ops: []*api.RESTPolicyRuleBatchOp{
+		&api.RESTPolicyRuleBatchOp{Op: api.BatchOpUpdate, Config: &api.RESTPolicyRuleConfig{ID: 100, Action: &allow}},
+	}}})
+	w = restCall("POST", "/v1/policy/changes", body, api.UserRoleAdmin)
+	json.Unmarshal(w.body, &resp)
+	id = resp.Change.ID
+
+	pc, _, _ = clusHelper.GetPolicyChangeRev(id)
+	pc.SubmittedBy = "alice"
+	clusHelper.PutPolicyChangeRev(pc, 0)
+	r, _ := clusHelper.GetPolicyRule(100)
+	r.LastModAt = r.LastModAt.Add(time.Second)
+	clusHelper.PutPolicyRule(r)
+
+	w = restCall("GET", "/v1/policy/changes/"+id, nil, api.UserRoleReader)
+	json.Unmarshal(w.body, &resp)
+	if w.status != http.StatusOK || !resp.Change.Ops[0].Conflict {
+		t.Errorf("Change should conflict: status=%v, %+v", w.status, resp.Change.Ops[0])
+	}
+	w = restCall("POST", "/v1/policy/changes/"+id+"/approve", nil, api.UserRoleAdmin)
+	if w.status != http.StatusConflict {
+		t.Errorf("Conflicting change should not be approved: status=%v", w.status)
+	}
+	w = restCall("POST", "/v1/policy/changes/"+id+"/reject", nil, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Errorf("Failed to reject change: status=%v", w.status)
+	}
+	if r, _ := clusHelper.GetPolicyRule(100); r.Action != share.PolicyActionDeny {
+		t.Errorf("Rule should not be modified by the rejected change: %+v", r)
+	}
+
+	// Reviewed changes are kept, and the pending change can be withdrawn
+	w = restCall("DELETE", "/v1/policy/changes/"+id, nil, api.UserRoleAdmin)
+	if pc, _, _ := clusHelper.GetPolicyChangeRev(id); w.status != http.StatusForbidden || pc == nil {
+		t.Errorf("Reviewed change should not be deleted: status=%v", w.status)
+	}
+	w = restCall("POST", "/v1/policy/changes", body, api.UserRoleAdmin)
+	json.Unmarshal(w.body, &resp)
+	id = resp.Change.ID
+	w = restCall("DELETE", "/v1/policy/changes/"+id, nil, api.UserRoleAdmin)
+	if pc, _, _ := clusHelper.GetPolicyChangeRev(id); w.status != http.StatusOK || pc != nil {
+		t.Errorf("Failed to delete pending change: status=%v", w.status)
+	}
+
+	postTest()
+}
+
+func TestPolicyChangeDeleteCheck(t *testing.T) {
+	pc := &share.CLUSPolicyChange{Status: share.PolicyChangePending, SubmittedBy: "alice"}
+	if err := deletePolicyChangeCheck(pc, "alice", false); err != nil {
+		t.Errorf("Submitter should delete pending change: %v", err)
+	}
+	if err := deletePolicyChangeCheck(pc, "bob", true); err != nil {
+		t.Errorf("Admin should delete pending change: %v", err)
+	}
+	if err := deletePolicyChangeCheck(pc, "bob", false); err == nil {
+		t.Errorf("Other users should not delete pending change")
+	}
+	for _, status := range []string{share.PolicyChangeApproved, share.PolicyChangeRejected} {
+		pc.Status = status
+		if err := deletePolicyChangeCheck(pc, "alice", true); err == nil {
+			t.Errorf("Change should not be deleted: status=%s", status)
+		}
+	}
+}
+
+func TestPolicyChangeApprovalPaths(t *testing.T) {
+	preTest()
+
+	rule1 := share.CLUSPolicyRule{
+		ID: 100, From: "g1", To: "g2", Action: share.PolicyActionAllow,
+		Ports: api.PolicyPortAny, Applications: []uint32{}, CfgType: share.UserCreated,
+	}
+	groups := []*share.CLUSGroup{
+		&share.CLUSGroup{Name: "g1", CfgType: share.UserCreated},
+		&share.CLUSGroup{Name: "g2", CfgType: share.UserCreated},
+	}
+
+	var mockCluster kv.MockCluster
+	initKvAndCache(&mockCluster, []*share.CLUSPolicyRule{&rule1}, groups)
+	cacher.(*mockCache).systemConfig.PolicyChangeApproval = true
+	cacher.(*mockCache).groups["g1"].PolicyRules = []uint32{100}
+
+	// Group with network rules
+	w := restCall("DELETE", "/v1/group/g1", nil, api.UserRoleAdmin)
+	if g, _, _ := clusHelper.GetGroup("g1", access.NewReaderAccessControl()); getRESTErrorCode(w) != api.RESTErrOpNotAllowed || g == nil {
+		t.Errorf("Group with network rules should not be deleted: status=%v", w.status)
+	}
+	if r, _ := clusHelper.GetPolicyRule(100); r == nil {
+		t.Errorf("Rule should not be deleted with the group")
+	}
+
+	// Config import with the policy section
+	for _, url := range []string{"/v1/file/config", "/v1/file/config?section=policy", "/v1/file/config?section=user,policy"} {
+		w = restCallFed("POST", url, []byte("{}"), api.UserRoleFedAdmin, api.FedRoleMaster)
+		if getRESTErrorCode(w) != api.RESTErrOpNotAllowed {
+			t.Errorf("Policy section should not be imported: url=%s, status=%v", url, w.status)
+		}
+	}
+
+	// Backup restore with the policy section
+	for _, sections := range [][]string{nil, []string{api.ConfSectionPolicy}} {
+		body, _ := json.Marshal(&api.RESTBackupRestoreReq{Name: "nv-config-20200101-000000.nvbak", Sections: sections})
+		w = restCallFed("POST", "/v1/system/backup/restore", body, api.UserRoleFedAdmin, api.FedRoleMaster)
+		if getRESTErrorCode(w) != api.RESTErrOpNotAllowed {
+			t.Errorf("Policy section should not be restored: sections=%v, status=%v", sections, w.status)
+		}
+	}
+	// Dry run compares the backup only
+	body, _ := json.Marshal(&api.RESTBackupRestoreReq{Name: "nv-config-20200101-000000.nvbak", DryRun: true})
+	w = restCallFed("POST", "/v1/system/backup/restore", body, api.UserRoleFedAdmin, api.FedRoleMaster)
+	if getRESTErrorCode(w) == api.RESTErrOpNotAllowed {
+		t.Errorf("Dry run should not be rejected: status=%v", w.status)
+	}
+
+	postTest()
+}
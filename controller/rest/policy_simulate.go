@@ -0,0 +1,122 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// Apply the operations to the rules in order. The created rules are appended to the end of the rules, in the same
+// way as the batch API.
+func simulatePolicyChangeOps(rules []*share.CLUSPolicyRule, ops []*share.CLUSPolicyChangeOp) []*share.CLUSPolicyRule {
+	ids := utils.NewSet()
+	for _, rule := range rules {
+		ids.Add(rule.ID)
+	}
+
+	updates := make(map[uint32]*share.CLUSPolicyRule)
+	dels := utils.NewSet()
+	news := make([]*share.CLUSPolicyRule, 0)
+	for _, op := range ops {
+		switch op.Op {
+		case api.BatchOpCreate:
+			cr := *op.Rule
+			if cr.ID == 0 {
+				cr.ID = common.GetAvailablePolicyID(ids, share.UserCreated)
+			}
+			ids.Add(cr.ID)
+			news = append(news, &cr)
+		case api.BatchOpUpdate:
+			updates[op.ID] = op.Rule
+		case api.BatchOpDelete:
+			dels.Add(op.ID)
+		}
+	}
+
+	list := make([]*share.CLUSPolicyRule, 0, len(rules)+len(news))
+	for _, rule := range rules {
+		if dels.Contains(rule.ID) {
+			continue
+		} else if cr, ok := updates[rule.ID]; ok {
+			list = append(list, cr)
+		} else {
+			list = append(list, rule)
+		}
+	}
+	return append(list, news...)
+}
+
+func handlerPolicySimulate(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, access.AccessOPRead)
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSPolicyRule{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	var rconf api.RESTPolicySimulationConfigData
+	body, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(body, &rconf); err != nil || rconf.Config == nil {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+	rc := rconf.Config
+	if len(rc.Ops) > 0 && rc.ChangeID != "" {
+		e := "Operations and policy change cannot be simulated together"
+		log.Error(e)
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+		return
+	}
+
+	var ops []*share.CLUSPolicyChangeOp
+	if rc.ChangeID != "" {
+		pc, _, err := clusHelper.GetPolicyChangeRev(rc.ChangeID)
+		if err != nil {
+			restRespNotFoundLogAccessDenied(w, login, err)
+			return
+		} else if !acc.Authorize(pc, nil) {
+			restRespAccessDenied(w, login)
+			return
+		} else if pc.Status != share.PolicyChangePending {
+			e := fmt.Sprintf("Change is already %s", pc.Status)
+			log.WithFields(log.Fields{"id": rc.ChangeID}).Error(e)
+			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+			return
+		}
+		ops = pc.Ops
+	} else if len(rc.Ops) > 0 {
+		var err error
+		if ops, err = policyChangeOps(w, rc.Ops, acc, login); err != nil {
+			return
+		}
+	}
+
+	for _, name := range rc.Groups {
+		if _, err := cacher.GetGroupCache(name, acc); err != nil {
+			restRespNotFoundLogAccessDenied(w, login, err)
+			return
+		}
+	}
+
+	// All rules are matched, including the ones that the user cannot see
+	rules := simulatePolicyChangeOps(cacher.GetAllPolicyRulesCache(access.NewReaderAccessControl()), ops)
+	evaluated, denied := cacher.SimulatePolicy(rules, rc.Groups, rc.SinceTS, acc)
+
+	resp := api.RESTPolicySimulationData{Evaluated: evaluated, Denied: denied}
+	restRespSuccess(w, r, &resp, acc, login, nil, "Simulate network rules")
+}
@@ -0,0 +1,32 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestSimulatePolicyChangeOps(t *testing.T) {
+	rules := []*share.CLUSPolicyRule{
+		&share.CLUSPolicyRule{ID: 100, From: "g1", To: "g2", Action: share.PolicyActionAllow},
+		&share.CLUSPolicyRule{ID: 101, From: "g2", To: "g1", Action: share.PolicyActionAllow},
+		&share.CLUSPolicyRule{ID: 102, From: "g1", To: "g3", Action: share.PolicyActionAllow},
+	}
+	ops := []*share.CLUSPolicyChangeOp{
+		&share.CLUSPolicyChangeOp{Op: api.BatchOpCreate, Rule: &share.CLUSPolicyRule{From: "g3", To: "g1", Action: share.PolicyActionDeny}},
+		&share.CLUSPolicyChangeOp{Op: api.BatchOpUpdate, ID: 101, Rule: &share.CLUSPolicyRule{ID: 101, From: "g2", To: "g1", Action: share.PolicyActionDeny}},
+		&share.CLUSPolicyChangeOp{Op: api.BatchOpDelete, ID: 102},
+	}
+
+	list := simulatePolicyChangeOps(rules, ops)
+	if len(list) != 3 || list[0].ID != 100 || list[1].ID != 101 || list[1].Action != share.PolicyActionDeny {
+		t.Fatalf("Unexpected rules: %+v", list)
+	}
+	if r := list[2]; r.ID == 0 || r.ID == 100 || r.ID == 101 || r.From != "g3" {
+		t.Errorf("Unexpected created rule: %+v", r)
+	}
+	if rules[1].Action != share.PolicyActionAllow || ops[0].Rule.ID != 0 {
+		t.Errorf("Current rules and operations should not be modified")
+	}
+}
@@ -338,6 +338,103 @@ func TestPolicyRuleConfig(t *testing.T) {
 	postTest()
 }
 
+func TestPolicyRuleConfigRateLimit(t *testing.T) {
+	preTest()
+
+	// Initial data
+	rule1 := share.CLUSPolicyRule{
+		ID: 10, From: "g1", To: "g1", Action: share.PolicyActionAllow,
+		Ports:        api.PolicyPortAny,
+		Applications: []uint32{},
+		CfgType:      share.UserCreated,
+	}
+	mc := mockCache{rules: make(map[uint32]*api.RESTPolicyRule, 0)}
+	mc.rules[rule1.ID] = mc.PolicyRule2REST(&rule1)
+	cacher = &mc
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(
+		[]*share.CLUSPolicyRule{&rule1},
+		[]*share.CLUSGroup{
+			&share.CLUSGroup{Name: "g1", CfgType: share.UserCreated},
+		},
+	)
+	clusHelper = &mockCluster
+
+	// Set rate limit
+	{
+		rate := uint32(1000)
+		unit := share.PolicyRateUnitConnections
+		conf := api.RESTPolicyRuleConfig{ID: 10, RateLimit: &rate, RateLimitUnit: &unit}
+		data := api.RESTPolicyRuleConfigData{Config: &conf}
+		body, _ := json.Marshal(data)
+
+		restCall("PATCH", "/v1/policy/rule/10", body, api.UserRoleAdmin)
+
+		rule1.RateLimit = rate
+		rule1.RateLimitUnit = unit
+		nrule1, _ := clusHelper.GetPolicyRule(10)
+		if !compareCLUSRules(&rule1, nrule1) {
+			t.Errorf("Set rate limit: Fail!")
+			t.Logf("  Expect: %+v\n", rule1)
+			t.Logf("  Actual: %+v\n", *nrule1)
+		}
+	}
+
+	// Remove rate limit
+	{
+		rate := uint32(0)
+		conf := api.RESTPolicyRuleConfig{ID: 10, RateLimit: &rate}
+		data := api.RESTPolicyRuleConfigData{Config: &conf}
+		body, _ := json.Marshal(data)
+
+		restCall("PATCH", "/v1/policy/rule/10", body, api.UserRoleAdmin)
+
+		rule1.RateLimit = 0
+		rule1.RateLimitUnit = ""
+		nrule1, _ := clusHelper.GetPolicyRule(10)
+		if !compareCLUSRules(&rule1, nrule1) {
+			t.Errorf("Remove rate limit: Fail!")
+			t.Logf("  Expect: %+v\n", rule1)
+			t.Logf("  Actual: %+v\n", *nrule1)
+		}
+	}
+
+	// Invalid unit
+	{
+		unit := "packets"
+		conf := api.RESTPolicyRuleConfig{ID: 10, RateLimitUnit: &unit}
+		data := api.RESTPolicyRuleConfigData{Config: &conf}
+		body, _ := json.Marshal(data)
+
+		w := restCall("PATCH", "/v1/policy/rule/10", body, api.UserRoleAdmin)
+		if w.status != http.StatusBadRequest {
+			t.Errorf("Invalid rate limit unit: Incorrect status.")
+			t.Logf("  Expect status: %+v\n", http.StatusBadRequest)
+			t.Logf("  Actual status: %+v\n", w.status)
+		}
+	}
+
+	// Rate limit on deny rule
+	{
+		action := share.PolicyActionDeny
+		rate := uint32(1000)
+		unit := share.PolicyRateUnitBytes
+		conf := api.RESTPolicyRuleConfig{ID: 10, Action: &action, RateLimit: &rate, RateLimitUnit: &unit}
+		data := api.RESTPolicyRuleConfigData{Config: &conf}
+		body, _ := json.Marshal(data)
+
+		w := restCall("PATCH", "/v1/policy/rule/10", body, api.UserRoleAdmin)
+		if w.status != http.StatusBadRequest {
+			t.Errorf("Rate limit on deny rule: Incorrect status.")
+			t.Logf("  Expect status: %+v\n", http.StatusBadRequest)
+			t.Logf("  Actual status: %+v\n", w.status)
+		}
+	}
+
+	postTest()
+}
+
 func TestPolicyRuleConfigGroup(t *testing.T) {
 	preTest()
 
@@ -3653,3 +3750,93 @@ func TestPolicyRuleReplaceRuleDuplicateID(t *testing.T) {
 
 	postTest()
 }
+
+func TestPolicyRuleBatch(t *testing.T) {
+	preTest()
+
+	rule1 := share.CLUSPolicyRule{
+		ID: 10001, From: "g1", To: "g2", Action: share.PolicyActionAllow,
+		Ports: api.PolicyPortAny, Applications: []uint32{}, CfgType: share.Learned,
+	}
+	rule2 := share.CLUSPolicyRule{
+		ID: 100, From: "g1", To: "g2", Action: share.PolicyActionAllow,
+		Ports: api.PolicyPortAny, Applications: []uint32{}, CfgType: share.UserCreated,
+	}
+	rule3 := share.CLUSPolicyRule{
+		ID: 101, From: "g2", To: "g1", Action: share.PolicyActionAllow,
+		Ports: api.PolicyPortAny, Applications: []uint32{}, CfgType: share.UserCreated,
+	}
+	groups := []*share.CLUSGroup{
+		&share.CLUSGroup{Name: "g1", CfgType: share.UserCreated},
+		&share.CLUSGroup{Name: "g2", CfgType: share.UserCreated},
+	}
+
+	var mockCluster kv.MockCluster
+	initKvAndCache(&mockCluster, []*share.CLUSPolicyRule{&rule1, &rule2, &rule3}, groups)
+
+	// One of the operations fails, nothing should be written
+	deny := share.PolicyActionDeny
+	data := api.RESTPolicyRuleBatchData{Ops: []*api.RESTPolicyRuleBatchOp{
+		&api.RESTPolicyRuleBatchOp{Op: api.BatchOpDelete, ID: 999},
+		&api.RESTPolicyRuleBatchOp{Op: api.BatchOpUpdate, Config: &api.RESTPolicyRuleConfig{ID: 100, Action: &deny}},
+	}}
+	body, _ := json.Marshal(data)
+	w := restCall("PATCH", "/v1/policy/rules/batch", body, api.UserRoleAdmin)
+	if w.status != http.StatusNotFound {
+		t.Errorf("Batch with missing rule: unexpected status %v", w.status)
+	}
+	if r, _ := clusHelper.GetPolicyRule(100); r.Action != share.PolicyActionAllow {
+		t.Errorf("Rule should not be modified when batch fails: %+v", r)
+	}
+
+	// The same rule in multiple operations
+	data.Ops[0] = &api.RESTPolicyRuleBatchOp{Op: api.BatchOpDelete, ID: 100}
+	body, _ = json.Marshal(data)
+	w = restCall("PATCH", "/v1/policy/rules/batch", body, api.UserRoleAdmin)
+	if w.status != http.StatusBadRequest {
+		t.Errorf("Batch with duplicate rule: unexpected status %v", w.status)
+	}
+
+	// Learned rules cannot be modified
+	data.Ops[0] = &api.RESTPolicyRuleBatchOp{Op: api.BatchOpUpdate, Config: &api.RESTPolicyRuleConfig{ID: 10001, Action: &deny}}
+	body, _ = json.Marshal(data)
+	w = restCall("PATCH", "/v1/policy/rules/batch", body, api.UserRoleAdmin)
+	if w.status != http.StatusBadRequest {
+		t.Errorf("Batch with learned rule: unexpected status %v", w.status)
+	}
+
+	data.Ops[0] = &api.RESTPolicyRuleBatchOp{Op: api.BatchOpDelete, ID: 101}
+	data.Ops = append(data.Ops, &api.RESTPolicyRuleBatchOp{Op: api.BatchOpCreate, Rule: &api.RESTPolicyRule{
+		From: "g2", To: "g1", Action: share.PolicyActionDeny, Ports: api.PolicyPortAny,
+	}})
+	body, _ = json.Marshal(data)
+	w = restCall("PATCH", "/v1/policy/rules/batch", body, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Fatalf("Batch policy rules: unexpected status %v", w.status)
+	}
+
+	var resp api.RESTPolicyRuleBatchCreatedData
+	json.Unmarshal(w.body, &resp)
+	if len(resp.IDs) != 1 || resp.IDs[0] == 0 {
+		t.Fatalf("Unexpected created rules: %+v", resp.IDs)
+	}
+	if r, _ := clusHelper.GetPolicyRule(100); r.Action != share.PolicyActionDeny {
+		t.Errorf("Rule is not modified: %+v", r)
+	}
+	if r, _ := clusHelper.GetPolicyRule(101); r != nil {
+		t.Errorf("Rule is not deleted: %+v", r)
+	}
+	if r, _ := clusHelper.GetPolicyRule(resp.IDs[0]); r == nil || r.From != "g2" || r.CfgType != share.UserCreated {
+		t.Errorf("Rule is not created: %+v", r)
+	}
+	expcrhs := []*share.CLUSRuleHead{
+		&share.CLUSRuleHead{ID: rule1.ID, CfgType: rule1.CfgType},
+		&share.CLUSRuleHead{ID: rule2.ID, CfgType: rule2.CfgType},
+		&share.CLUSRuleHead{ID: resp.IDs[0], CfgType: share.UserCreated},
+	}
+	if crhs := clusHelper.GetPolicyRuleList(); !reflect.DeepEqual(expcrhs, crhs) {
+		t.Errorf("Unexpected rule heads: %+v", crhs)
+	}
+
+	postTest()
+}
@@ -7,11 +7,13 @@ import (
 	"net/http"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/julienschmidt/httprouter"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/neuvector/neuvector/controller/api"
@@ -167,7 +169,78 @@ func handlerProcessProfileShow(w http.ResponseWriter, r *http.Request, ps httpro
 	restRespSuccess(w, r, &resp, acc, login, nil, "Get process profile detail")
 }
 
-func validateProcessProfileConfig(list []api.RESTProcessProfileEntryConfig) error {
+// system calls needed by the runtime to start a container and by the dynamic loader, they are
+// hardly caught by sampling so they are always allowed in the generated seccomp profile
+var seccompBaseSyscalls []string = []string{
+	"access", "arch_prctl", "brk", "capget", "capset", "chdir", "close", "dup2", "dup3", "execve",
+	"exit", "exit_group", "fchdir", "fcntl", "fstat", "futex", "getcwd", "getdents64", "getegid", "geteuid",
+	"getgid", "getpid", "getppid", "getrandom", "gettid", "getuid", "lseek", "madvise", "mmap", "mprotect",
+	"munmap", "newfstatat", "open", "openat", "prctl", "pread64", "prlimit64", "read", "readlink", "rseq",
+	"rt_sigaction", "rt_sigprocmask", "rt_sigreturn", "sched_getaffinity", "set_robust_list", "set_tid_address",
+	"setgid", "setgroups", "setuid", "sigaltstack", "stat", "statx", "tgkill", "uname", "write",
+}
+
+// System calls are sampled, not traced, so the learned list is never complete. The profile is
+// advisory: calls outside of the list are logged by the kernel instead of being denied.
+// Only x86_64 enforcers resolve system call names.
+func buildSeccompProfile(learned []string) *specs.LinuxSeccomp {
+	calls := make([]string, len(seccompBaseSyscalls))
+	copy(calls, seccompBaseSyscalls)
+	sort.Strings(calls)
+	calls, _ = common.MergeSyscalls(calls, learned)
+
+	return &specs.LinuxSeccomp{
+		DefaultAction: specs.ActLog,
+		Architectures: []specs.Arch{specs.ArchX86_64, specs.ArchX86, specs.ArchX32},
+		Syscalls: []specs.LinuxSyscall{
+			{Names: calls, Action: specs.ActAllow},
+		},
+	}
+}
+
+func handlerProcessProfileSeccomp(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	group := ps.ByName("name")
+	if grp, err := cacher.GetGroupBrief(group, false, acc); err == nil {
+		if !isValidKindProcessProfile(grp.Kind) {
+			log.WithFields(log.Fields{"group": group, "kind": grp.Kind}).Error("Get profile failed!")
+			restRespError(w, http.StatusBadRequest, api.RESTErrObjectNotFound)
+			return
+		}
+	} else {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+
+	profile, err := cacher.GetProcessProfile(group, acc)
+	if profile == nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+
+	if len(profile.Syscalls) == 0 {
+		log.WithFields(log.Fields{"group": group}).Error("No learned system call")
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, "No system call has been learned for the group")
+		return
+	}
+
+	// tell the browser the returned content should be downloaded
+	data, _ := json.MarshalIndent(buildSeccompProfile(profile.Syscalls), "", "  ")
+	w.Header().Set("Content-Disposition", "Attachment; filename="+fmt.Sprintf("seccomp-%s.json", group))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+var systemdUnitRegex = regexp.MustCompile(`^[a-zA-Z0-9:_.@\\-]+\.(service|scope)$`)
+
+func validateProcessProfileConfig(group string, list []api.RESTProcessProfileEntryConfig) error {
 	for i, proc := range list {
 		if proc.Action != share.PolicyActionAllow && proc.Action != share.PolicyActionDeny {
 			log.WithFields(log.Fields{"proc": proc}).Error("Action not supported!")
@@ -202,6 +275,48 @@ func validateProcessProfileConfig(list []api.RESTProcessProfileEntryConfig) erro
 			return fmt.Errorf("Invalid entry: deny all processes[ %s: %s]", proc.Name, proc.Path)
 		}
 
+		// optional argument constraint
+		switch proc.ArgsMatch {
+		case "":
+			if proc.Args != "" {
+				log.WithFields(log.Fields{"Name": proc.Name, "Args": proc.Args}).Error("PROC: missing args match")
+				return fmt.Errorf("process %s: %s, args_match is required with args", proc.Name, proc.Path)
+			}
+		case share.ProcArgsMatchExact, share.ProcArgsMatchPrefix:
+		case share.ProcArgsMatchRegex:
+			if _, err := regexp.Compile(proc.Args); err != nil {
+				log.WithFields(log.Fields{"Name": proc.Name, "Args": proc.Args, "error": err}).Error("PROC: invalid args regex")
+				return fmt.Errorf("process %s: %s, invalid args regex: %s", proc.Name, proc.Path, err.Error())
+			}
+		default:
+			log.WithFields(log.Fields{"Name": proc.Name, "ArgsMatch": proc.ArgsMatch}).Error("PROC: unsupported args match")
+			return fmt.Errorf("process %s: %s, args_match %s is not supported", proc.Name, proc.Path, proc.ArgsMatch)
+		}
+
+		// optional host service constraint
+		if proc.Unit != "" {
+			if !utils.IsGroupNodes(group) {
+				log.WithFields(log.Fields{"Name": proc.Name, "Unit": proc.Unit, "group": group}).Error("PROC: unit on non-host group")
+				return fmt.Errorf("process %s: %s, unit is only supported by the nodes group", proc.Name, proc.Path)
+			}
+			if !systemdUnitRegex.MatchString(proc.Unit) {
+				log.WithFields(log.Fields{"Name": proc.Name, "Unit": proc.Unit}).Error("PROC: invalid unit")
+				return fmt.Errorf("process %s: %s, invalid systemd unit %s", proc.Name, proc.Path, proc.Unit)
+			}
+		}
+
+		// optional expiration time, the federal rules are not owned by the lead controller of the cluster
+		if proc.ExpireTS != 0 {
+			if strings.HasPrefix(group, api.FederalGroupPrefix) {
+				log.WithFields(log.Fields{"Name": proc.Name, "group": group}).Error("PROC: expiration on federal group")
+				return fmt.Errorf("process %s: %s, expiration time is not supported by federal rule", proc.Name, proc.Path)
+			}
+			if proc.ExpireTS <= time.Now().Unix() {
+				log.WithFields(log.Fields{"Name": proc.Name, "expire": proc.ExpireTS}).Error("PROC: expiration in the past")
+				return fmt.Errorf("process %s: %s, expiration time must be in the future", proc.Name, proc.Path)
+			}
+		}
+
 		// update
 		list[i].Name = proc.Name
 		list[i].Path = proc.Path
@@ -209,6 +324,52 @@ func validateProcessProfileConfig(list []api.RESTProcessProfileEntryConfig) erro
 	return nil
 }
 
+// The exception paths are absolute, the root directory is not accepted to avoid disabling the zero-drift
+func validateZeroDriftConfig(group string, conf *api.RESTZeroDriftConfig) error {
+	if !utils.DoesGroupHavePolicyMode(group) || utils.IsGroupNodes(group) {
+		log.WithFields(log.Fields{"group": group}).Error("PROC: zero-drift on invalid group")
+		return fmt.Errorf("zero-drift exceptions are not supported by group %s", group)
+	}
+
+	if conf.Paths != nil {
+		paths := make([]string, 0, len(*conf.Paths))
+		for _, path := range *conf.Paths {
+			path = strings.TrimSpace(path)
+			bDir := strings.HasSuffix(path, "/*")
+			if bDir {
+				path = strings.TrimSuffix(path, "/*")
+			}
+			if !strings.HasPrefix(path, "/") || strings.Contains(path, "*") {
+				log.WithFields(log.Fields{"path": path}).Error("PROC: illegal zero-drift path")
+				return fmt.Errorf("zero-drift exception path %s is not supported", path)
+			}
+			if path = filepath.Clean(path); path == "/" {
+				log.WithFields(log.Fields{"path": path}).Error("PROC: illegal zero-drift path")
+				return fmt.Errorf("zero-drift exception path %s is not supported", path)
+			}
+			if bDir {
+				path += "/*"
+			}
+			paths = append(paths, path)
+		}
+		*conf.Paths = paths
+	}
+
+	if conf.Names != nil {
+		names := make([]string, 0, len(*conf.Names))
+		for _, name := range *conf.Names {
+			name = strings.TrimSpace(name)
+			if name == "" || strings.ContainsAny(name, "/*") {
+				log.WithFields(log.Fields{"name": name}).Error("PROC: illegal zero-drift name")
+				return fmt.Errorf("zero-drift exception name %s is not supported", name)
+			}
+			names = append(names, name)
+		}
+		*conf.Names = names
+	}
+	return nil
+}
+
 func handlerProcessProfileConfig(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
 	defer r.Body.Close()
@@ -234,7 +395,13 @@ func handlerProcessProfileConfig(w http.ResponseWriter, r *http.Request, ps http
 	conf := rconf.Config
 	log.WithFields(log.Fields{"conf": conf}).Debug("")
 	if conf.ProcessChgList != nil {
-		if err := validateProcessProfileConfig(*conf.ProcessChgList); err != nil {
+		if err := validateProcessProfileConfig(group, *conf.ProcessChgList); err != nil {
+			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+			return
+		}
+	}
+	if conf.ZeroDrift != nil {
+		if err := validateZeroDriftConfig(group, conf.ZeroDrift); err != nil {
 			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
 			return
 		}
@@ -307,6 +474,24 @@ func handlerProcessProfileConfig(w http.ResponseWriter, r *http.Request, ps http
 		}
 	}
 
+	if zd := conf.ZeroDrift; zd != nil {
+		if profile.ZeroDrift == nil {
+			profile.ZeroDrift = &share.CLUSZeroDrift{}
+		}
+		if zd.ReportOnly != nil {
+			profile.ZeroDrift.ReportOnly = *zd.ReportOnly
+		}
+		if zd.Paths != nil {
+			profile.ZeroDrift.Paths = *zd.Paths
+		}
+		if zd.Names != nil {
+			profile.ZeroDrift.Names = *zd.Names
+		}
+		if !profile.ZeroDrift.ReportOnly && len(profile.ZeroDrift.Paths) == 0 && len(profile.ZeroDrift.Names) == 0 {
+			profile.ZeroDrift = nil
+		}
+	}
+
 	// new/modified entry as UserCreated type
 	var rule_cfg share.TCfgType = share.UserCreated
 	if strings.HasPrefix(group, api.FederalGroupPrefix) {
@@ -321,15 +506,18 @@ func handlerProcessProfileConfig(w http.ResponseWriter, r *http.Request, ps http
 		for _, proc := range *conf.ProcessDelList {
 			var found bool = false
 			p := &share.CLUSProcessProfileEntry{
-				Name:    proc.Name,
-				Path:    proc.Path,
-				Action:  proc.Action,
-				CfgType: rule_cfg,
+				Name:      proc.Name,
+				Path:      proc.Path,
+				Action:    proc.Action,
+				CfgType:   rule_cfg,
+				Args:      proc.Args,
+				ArgsMatch: proc.ArgsMatch,
+				Unit:      proc.Unit,
 			}
 
 			idx, found := common.FindProcessInProfile(profile.Process, p)
 			if found {
-				key := proc.Name + ":" + proc.Path
+				key := proc.Name + ":" + proc.Path + ":" + proc.ArgsMatch + ":" + proc.Args + ":" + proc.Unit
 				deleted[key] = profile.Process[idx]
 			} else {
 				log.WithFields(log.Fields{"group": group, "rule": p}).Error("Cannot find rule")
@@ -340,7 +528,7 @@ func handlerProcessProfileConfig(w http.ResponseWriter, r *http.Request, ps http
 
 		list := make([]*share.CLUSProcessProfileEntry, 0)
 		for _, p := range profile.Process {
-			key := p.Name + ":" + p.Path
+			key := p.Name + ":" + p.Path + ":" + p.ArgsMatch + ":" + p.Args + ":" + p.Unit
 			if d, ok := deleted[key]; ok && (d.CfgType == p.CfgType) {
 				// meet all comparing criteria
 				continue
@@ -354,7 +542,7 @@ func handlerProcessProfileConfig(w http.ResponseWriter, r *http.Request, ps http
 	if conf.ProcessChgList != nil {
 		for _, proc := range *conf.ProcessChgList {
 			var created time.Time
-			key := proc.Name + ":" + proc.Path
+			key := proc.Name + ":" + proc.Path + ":" + proc.ArgsMatch + ":" + proc.Args + ":" + proc.Unit
 			if d, ok := deleted[key]; ok {
 				log.WithFields(log.Fields{"rule": d}).Debug("precedent")
 				created = d.CreatedAt
@@ -367,6 +555,10 @@ func handlerProcessProfileConfig(w http.ResponseWriter, r *http.Request, ps http
 				Uuid:            ruleid.NewUuid(),
 				CreatedAt:       created,
 				AllowFileUpdate: proc.AllowFileUpdate,
+				Args:            proc.Args,
+				ArgsMatch:       proc.ArgsMatch,
+				Unit:            proc.Unit,
+				ExpireAt:        proc.ExpireTS,
 			}
 			if ret, ok := common.MergeProcess(profile.Process, &p, true); ok {
 				profile.Process = ret
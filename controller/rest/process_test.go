@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"reflect"
+	"sort"
 	"testing"
 
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+
 	"github.com/neuvector/neuvector/controller/api"
 	"github.com/neuvector/neuvector/share"
 )
@@ -345,3 +348,25 @@ func TestProcessProfileConfigDelete(t *testing.T) {
 
 	postTest()
 }
+
+func TestProcessProfileSeccomp(t *testing.T) {
+	learned := []string{"accept4", "epoll_wait", "read"}
+	sp := buildSeccompProfile(learned)
+	if sp.DefaultAction != specs.ActLog || len(sp.Syscalls) != 1 || sp.Syscalls[0].Action != specs.ActAllow {
+		t.Errorf("Unexpected seccomp profile: %+v\n", sp)
+		return
+	}
+
+	names := sp.Syscalls[0].Names
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("System calls are not sorted: %+v\n", names)
+	}
+	for _, name := range append(learned, "execve") {
+		if i := sort.SearchStrings(names, name); i == len(names) || names[i] != name {
+			t.Errorf("Missing system call: %v\n", name)
+		}
+	}
+	if len(names) != len(seccompBaseSyscalls)+2 { // "read" is a base one
+		t.Errorf("Duplicate system calls: %+v\n", names)
+	}
+}
@@ -4,15 +4,18 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"reflect"
 	"time"
 
 	"github.com/julienschmidt/httprouter"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/neuvector/neuvector/controller/access"
 	"github.com/neuvector/neuvector/controller/api"
 	"github.com/neuvector/neuvector/controller/common"
 	"github.com/neuvector/neuvector/share"
 	"github.com/neuvector/neuvector/share/cluster"
+	"github.com/neuvector/neuvector/share/utils"
 )
 
 const _maxPwdHistoryCount = 32
@@ -20,6 +23,54 @@ const _pwdValidPerDayUnit = 1440
 
 var _pwdValidUnit time.Duration = _pwdValidPerDayUnit // default: per day
 
+func isValidMFARequiredRoles(roles []string) bool {
+	for _, role := range roles {
+		if role == api.UserRoleNone || !access.IsValidRole(role, access.CONST_VISIBLE_USER_ROLE) {
+			return false
+		}
+	}
+	return true
+}
+
+func isValidSessionPolicies(policies []share.CLUSSessionPolicy) bool {
+	roles := utils.NewSet()
+	for _, p := range policies {
+		if p.Role == api.UserRoleNone || !access.IsValidRole(p.Role, access.CONST_VISIBLE_USER_ROLE) || roles.Contains(p.Role) {
+			return false
+		}
+		if p.IdleTimeout != 0 && (p.IdleTimeout > api.UserIdleTimeoutMax || p.IdleTimeout < api.UserIdleTimeoutMin) {
+			return false
+		}
+		if p.MaxLifetime != 0 && p.MaxLifetime < api.UserIdleTimeoutMin {
+			return false
+		}
+		if p.MaxSessions < 0 || p.MaxSessions > MaxPerDomainLoginUsers {
+			return false
+		}
+		roles.Add(p.Role)
+	}
+	return true
+}
+
+func sessionPolicies2CLUS(rpolicies []api.RESTSessionPolicy) []share.CLUSSessionPolicy {
+	if len(rpolicies) == 0 {
+		return nil
+	}
+	policies := make([]share.CLUSSessionPolicy, len(rpolicies))
+	for i, p := range rpolicies {
+		policies[i] = share.CLUSSessionPolicy{Role: p.Role, IdleTimeout: p.IdleTimeout, MaxLifetime: p.MaxLifetime, MaxSessions: p.MaxSessions}
+	}
+	return policies
+}
+
+func sessionPolicies2REST(policies []share.CLUSSessionPolicy) []api.RESTSessionPolicy {
+	rpolicies := make([]api.RESTSessionPolicy, len(policies))
+	for i, p := range policies {
+		rpolicies[i] = api.RESTSessionPolicy{Role: p.Role, IdleTimeout: p.IdleTimeout, MaxLifetime: p.MaxLifetime, MaxSessions: p.MaxSessions}
+	}
+	return rpolicies
+}
+
 func handlerPwdProfileCreate(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
 	defer r.Body.Close()
@@ -52,7 +103,8 @@ func handlerPwdProfileCreate(w http.ResponseWriter, r *http.Request, ps httprout
 		(rprofile.EnablePwdHistory && rprofile.PwdHistoryCount <= 0) ||
 		(rprofile.EnableBlockAfterFailedLogin && (rprofile.BlockAfterFailedCount <= 0 || rprofile.BlockMinutes <= 0)) ||
 		(rprofile.MinLen < (rprofile.MinUpperCount + rprofile.MinLowerCount + rprofile.MinDigitCount + rprofile.MinSpecialCount)) ||
-		(rprofile.SessionTimeout > api.UserIdleTimeoutMax || rprofile.SessionTimeout < api.UserIdleTimeoutMin) {
+		(rprofile.SessionTimeout > api.UserIdleTimeoutMax || rprofile.SessionTimeout < api.UserIdleTimeoutMin) ||
+		!isValidMFARequiredRoles(rprofile.MFARequiredRoles) || !isValidSessionPolicies(sessionPolicies2CLUS(rprofile.SessionPolicies)) {
 		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, "invalid value")
 		return
 	}
@@ -73,6 +125,8 @@ func handlerPwdProfileCreate(w http.ResponseWriter, r *http.Request, ps httprout
 		BlockAfterFailedCount:       rprofile.BlockAfterFailedCount,
 		BlockMinutes:                rprofile.BlockMinutes,
 		SessionTimeout:              rprofile.SessionTimeout,
+		MFARequiredRoles:            rprofile.MFARequiredRoles,
+		SessionPolicies:             sessionPolicies2CLUS(rprofile.SessionPolicies),
 	}
 	if profile.PwdHistoryCount > _maxPwdHistoryCount {
 		profile.PwdHistoryCount = _maxPwdHistoryCount
@@ -144,6 +198,8 @@ func handlerPwdProfileShow(w http.ResponseWriter, r *http.Request, ps httprouter
 			BlockAfterFailedCount:       &profile.BlockAfterFailedCount,
 			BlockMinutes:                &profile.BlockMinutes,
 			SessionTimeout:              &profile.SessionTimeout,
+			MFARequiredRoles:            profile.MFARequiredRoles,
+			SessionPolicies:             sessionPolicies2REST(profile.SessionPolicies),
 		}
 	} else {
 		resp.PwdProfile = &api.RESTPwdProfileConditional{
@@ -194,6 +250,8 @@ func handlerPwdProfileList(w http.ResponseWriter, r *http.Request, ps httprouter
 			BlockAfterFailedCount:       profile.BlockAfterFailedCount,
 			BlockMinutes:                profile.BlockMinutes,
 			SessionTimeout:              profile.SessionTimeout,
+			MFARequiredRoles:            profile.MFARequiredRoles,
+			SessionPolicies:             sessionPolicies2REST(profile.SessionPolicies),
 		})
 	}
 
@@ -288,6 +346,12 @@ func handlerPwdProfileConfig(w http.ResponseWriter, r *http.Request, ps httprout
 			} else if profile.SessionTimeout == 0 {
 				profile.SessionTimeout = common.DefIdleTimeoutInternal
 			}
+			if rprofile.MFARequiredRoles != nil {
+				profile.MFARequiredRoles = *rprofile.MFARequiredRoles
+			}
+			if rprofile.SessionPolicies != nil {
+				profile.SessionPolicies = sessionPolicies2CLUS(*rprofile.SessionPolicies)
+			}
 			if profile.PwdHistoryCount > _maxPwdHistoryCount {
 				profile.PwdHistoryCount = _maxPwdHistoryCount
 			}
@@ -296,7 +360,8 @@ func handlerPwdProfileConfig(w http.ResponseWriter, r *http.Request, ps httprout
 				(profile.EnablePwdHistory && profile.PwdHistoryCount <= 0) ||
 				(profile.EnableBlockAfterFailedLogin && (profile.BlockAfterFailedCount <= 0 || profile.BlockMinutes <= 0)) ||
 				(profile.MinLen < (profile.MinUpperCount + profile.MinLowerCount + profile.MinDigitCount + profile.MinSpecialCount)) ||
-				(profile.SessionTimeout > api.UserIdleTimeoutMax || profile.SessionTimeout < api.UserIdleTimeoutMin) {
+				(profile.SessionTimeout > api.UserIdleTimeoutMax || profile.SessionTimeout < api.UserIdleTimeoutMin) ||
+				!isValidMFARequiredRoles(profile.MFARequiredRoles) || !isValidSessionPolicies(profile.SessionPolicies) {
 				restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, "invalid value")
 				return
 			}
@@ -318,7 +383,7 @@ func handlerPwdProfileConfig(w http.ResponseWriter, r *http.Request, ps httprout
 					}
 				}
 			}*/
-			if oldProfile != *profile {
+			if !reflect.DeepEqual(oldProfile, *profile) {
 				if err := clusHelper.PutPwdProfileRev(profile, rev); err != nil {
 					kvProfileUpdateOK = false
 				} else if (newActivePwdProfileName == oldActivePwdProfileName) && (oldActivePwdProfileName == rprofile.Name) {
@@ -0,0 +1,163 @@
+package rest
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+)
+
+const rateLimitPrunePeriod = time.Minute
+
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+	limited  bool // whether the last request was limited, to avoid logging every limited request
+}
+
+// Token bucket rate limiter. Each client (login user, apikey or source ip) has its own bucket
+type restRateLimiter struct {
+	mutex     sync.Mutex
+	rate      uint // tokens added per second
+	burst     uint // bucket size
+	buckets   map[string]*tokenBucket
+	lastPrune time.Time
+	allowed   uint64
+	limited   uint64
+}
+
+var restLimiter *restRateLimiter
+
+func newRestRateLimiter(rate, burst uint) *restRateLimiter {
+	if burst < rate {
+		burst = rate
+	}
+	return &restRateLimiter{
+		rate:      rate,
+		burst:     burst,
+		buckets:   make(map[string]*tokenBucket),
+		lastPrune: time.Now(),
+	}
+}
+
+// Return whether the request from the client can be served. If not, also return how long the client should wait
+func (l *restRateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if now.Sub(l.lastPrune) >= rateLimitPrunePeriod {
+		l.prune(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(l.burst), lastSeen: now}
+		l.buckets[key] = b
+	} else if elapsed := now.Sub(b.lastSeen); elapsed > 0 {
+		b.tokens = math.Min(float64(l.burst), b.tokens+elapsed.Seconds()*float64(l.rate))
+		b.lastSeen = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		b.limited = false
+		l.allowed++
+		return true, 0
+	}
+
+	if !b.limited {
+		b.limited = true
+		log.WithFields(log.Fields{"client": key}).Info("REST API rate limit reached")
+	}
+	l.limited++
+	wait := time.Duration((1 - b.tokens) / float64(l.rate) * float64(time.Second))
+	return false, wait
+}
+
+// Remove the buckets that have been refilled, as they are the same as new buckets. with mutex locked when calling this
+func (l *restRateLimiter) prune(now time.Time) {
+	refill := time.Duration(float64(l.burst) / float64(l.rate) * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastPrune = now
+}
+
+func (l *restRateLimiter) stats() *api.RESTRateLimitStats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return &api.RESTRateLimitStats{
+		Enabled:         true,
+		Rate:            l.rate,
+		Burst:           l.burst,
+		ActiveClients:   len(l.buckets),
+		AllowedRequests: l.allowed,
+		LimitedRequests: l.limited,
+	}
+}
+
+// Requests are counted against the login user if the token is known, the apikey name, or the source ip otherwise
+func restReqClientKey(r *http.Request) string {
+	// Requests are counted by the credential only when it's a valid session or apikey, so random
+	// tokens or apikeys are counted by the client ip and don't get a new bucket each time.
+	if token, ok := r.Header[api.RESTTokenHeader]; ok && len(token) == 1 {
+		userMutex.RLock()
+		login, ok := loginSessions[token[0]]
+		userMutex.RUnlock()
+		if ok && login != nil {
+			return "user:" + login.fullname
+		}
+	} else if apikey, ok := r.Header[api.RESTAPIKeyHeader]; ok && len(apikey) == 1 {
+		if apikeyAccount, _ := verifyApikey(apikey[0]); apikeyAccount != nil {
+			return "apikey:" + apikeyAccount.Name
+		}
+	}
+
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return "ip:" + host
+	}
+	return "ip:" + r.RemoteAddr
+}
+
+type rateLimitHandler struct {
+	handler http.Handler
+	limiter *restRateLimiter
+}
+
+func (h rateLimitHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if ok, wait := h.limiter.allow(restReqClientKey(r), time.Now()); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		restRespErrorMessage(w, http.StatusTooManyRequests, api.RESTErrTooManyRequests, "")
+		return
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+func handlerDebugRateLimit(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !authDebugCaller(w, acc, login) {
+		return
+	}
+
+	resp := api.RESTRateLimitStatsData{Stats: &api.RESTRateLimitStats{}}
+	if restLimiter != nil {
+		resp.Stats = restLimiter.stats()
+	}
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get REST API rate limit stats")
+}
@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	l := newRestRateLimiter(2, 4)
+	now := time.Now()
+
+	for i := 0; i < 4; i++ {
+		if ok, _ := l.allow("user:admin", now); !ok {
+			t.Errorf("Request %d should be allowed in burst", i)
+		}
+	}
+	if ok, wait := l.allow("user:admin", now); ok {
+		t.Errorf("Request should be limited after burst")
+	} else if wait != time.Millisecond*500 {
+		t.Errorf("Unexpected wait time: %v", wait)
+	}
+
+	// Other clients are not affected
+	if ok, _ := l.allow("ip:10.1.1.1", now); !ok {
+		t.Errorf("Request from another client should be allowed")
+	}
+
+	// Refilled at the rate
+	now = now.Add(time.Millisecond * 500)
+	if ok, _ := l.allow("user:admin", now); !ok {
+		t.Errorf("Request should be allowed after refill")
+	}
+	if ok, _ := l.allow("user:admin", now); ok {
+		t.Errorf("Request should be limited before next refill")
+	}
+
+	stats := l.stats()
+	if stats.AllowedRequests != 6 || stats.LimitedRequests != 2 || stats.ActiveClients != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	// Refilled buckets are pruned
+	now = now.Add(rateLimitPrunePeriod)
+	l.allow("ip:10.1.1.2", now)
+	if stats = l.stats(); stats.ActiveClients != 1 {
+		t.Errorf("Unexpected active clients after prune: %+v", stats)
+	}
+}
+
+func TestRateLimitHandler(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+	mockCluster.CreateApikey(&share.CLUSApikey{Name: "key1", SecretKeyHash: utils.HashPassword("secret")})
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := rateLimitHandler{handler: next, limiter: newRestRateLimiter(1, 1)}
+
+	req, _ := http.NewRequest("GET", "https://10.1.1.1/v1/group", nil)
+	req.RemoteAddr = "10.1.1.1:12345"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected status: %v", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Unexpected status: %v", w.Code)
+	} else if w.Header().Get("Retry-After") != "1" {
+		t.Errorf("Unexpected Retry-After: %v", w.Header().Get("Retry-After"))
+	}
+
+	// Unknown token or invalid apikey is counted by its source ip
+	for _, hdr := range []string{api.RESTTokenHeader, api.RESTAPIKeyHeader} {
+		req.Header = http.Header{}
+		req.Header.Set(hdr, "random:value")
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusTooManyRequests {
+			t.Errorf("Unexpected status with %s: %v", hdr, w.Code)
+		}
+	}
+
+	// Valid apikey is counted separately from its source ip
+	req.Header = http.Header{}
+	req.Header.Set(api.RESTAPIKeyHeader, "key1:secret")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected status: %v", w.Code)
+	}
+
+	postTest()
+}
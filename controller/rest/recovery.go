@@ -0,0 +1,141 @@
+package rest
+
+// Break-glass recovery of the local admin account with the one-time token minted by the controller CLI
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func handlerRecoveryAuth(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	body, _ := ioutil.ReadAll(r.Body)
+
+	var data api.RESTRecoveryAuthData
+	err := json.Unmarshal(body, &data)
+	if err != nil || data.Recovery == nil || data.Recovery.Token == "" || data.Recovery.NewPassword == "" {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	remote := r.RemoteAddr
+	if i := strings.Index(remote, ":"); i > 0 {
+		remote = remote[:i]
+	}
+
+	lock, err := lockClusKey(w, share.CLUSLockUserKey)
+	if err != nil {
+		return
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	rt, _ := clusHelper.GetRecoveryToken()
+	if rt == nil || time.Now().UTC().After(rt.ExpireAt) ||
+		subtle.ConstantTimeCompare([]byte(utils.HashPassword(data.Recovery.Token)), []byte(rt.TokenHash)) != 1 {
+		log.WithFields(log.Fields{"remote": remote}).Error("Invalid or expired recovery token")
+		restRespError(w, http.StatusUnauthorized, api.RESTErrUnauthorized)
+		return
+	}
+
+	// The token can only be used once, even if the password is rejected
+	if err := clusHelper.DeleteRecoveryToken(); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to delete recovery token")
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+
+	// only the built-in admin account is recovered, other accounts are recovered by the admin
+	if rt.Username != common.DefaultAdminUser {
+		e := "Only the built-in admin account can be recovered"
+		log.WithFields(log.Fields{"user": rt.Username}).Error(e)
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrOpNotAllowed, e)
+		return
+	}
+
+	acc := access.NewAdminAccessControl()
+	user, rev, _ := clusHelper.GetUserRev(rt.Username, acc)
+	if user != nil && user.Server != "" {
+		e := "Cannot recover remote user"
+		log.WithFields(log.Fields{"user": rt.Username}).Error(e)
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrOpNotAllowed, e)
+		return
+	}
+
+	var pwdHash string
+	var pwdHashHistory []string
+	if user != nil {
+		pwdHash = user.PasswordHash
+		pwdHashHistory = user.PwdHashHistory
+	}
+	if weak, _, profileBasic, e := isWeakPassword(data.Recovery.NewPassword, pwdHash, pwdHashHistory, nil); weak {
+		log.WithFields(log.Fields{"user": rt.Username}).Error(e)
+		restRespErrorMessageEx(w, http.StatusBadRequest, api.RESTErrWeakPassword, e, profileBasic)
+		return
+	}
+
+	create := user == nil
+	if create {
+		user = &share.CLUSUser{
+			Fullname:    rt.Username,
+			Username:    rt.Username,
+			Timeout:     common.DefaultIdleTimeout,
+			RoleDomains: make(map[string][]string),
+			Locale:      common.OEMDefaultUserLocale,
+		}
+	}
+	// the built-in admin account could be demoted by whoever locked it out
+	oldRole := user.Role
+	if user.Role != api.UserRoleFedAdmin {
+		user.Role = api.UserRoleAdmin
+	}
+	user.RoleDomains = make(map[string][]string)
+	user.PasswordHash = utils.HashPassword(data.Recovery.NewPassword)
+	user.PwdHashHistory = nil
+	user.PwdResetTime = time.Now().UTC()
+	user.FailedLoginCount = 0
+	user.BlockLoginSince = time.Time{}
+	user.MFASecret = ""
+	user.MFAEnabled = false
+	user.MFABackupCodes = nil
+	user.MFALastStep = 0
+
+	if create {
+		err = clusHelper.CreateUser(user)
+	} else {
+		err = clusHelper.PutUserRev(user, rev)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "user": rt.Username}).Error()
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+
+	// Existing sessions of the user might be owned by whoever locked the admin out
+	kickLoginSessions(user)
+
+	msg := fmt.Sprintf("User %s is recovered with the recovery token", rt.Username)
+	if !create && oldRole != user.Role {
+		msg = fmt.Sprintf("%s, role is changed from %s to %s", msg, oldRole, user.Role)
+		log.WithFields(log.Fields{"user": rt.Username, "old": oldRole, "new": user.Role}).Warn("Role is changed by recovery")
+	}
+	authLog(share.CLUSEvAuthAccountRecovered, rt.Username, remote, "", map[string]string{"": user.Role}, msg)
+
+	restRespSuccess(w, r, nil, nil, nil, nil, "")
+}
@@ -0,0 +1,113 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func recoverAuth(token, password string) *mockResponseWriter {
+	w := new(mockResponseWriter)
+	data := api.RESTRecoveryAuthData{Recovery: &api.RESTRecoveryAuth{Token: token, NewPassword: password}}
+	body, _ := json.Marshal(&data)
+	r, _ := http.NewRequest("POST", "/v1/recovery_auth", bytes.NewBuffer(body))
+	router.ServeHTTP(w, r)
+	return w
+}
+
+func TestRecoveryAuth(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+
+	user := makeLocalUser("admin", "pass", api.UserRoleReader)
+	user.FailedLoginCount = 5
+	user.BlockLoginSince = time.Now().UTC()
+	user.MFAEnabled = true
+	clusHelper.CreateUser(user)
+
+	cacher = &mockCache{}
+
+	// No token is minted
+	w := recoverAuth("token", "newpass")
+	if w.status != http.StatusUnauthorized {
+		t.Errorf("Recovery without token should fail: status=%v.", w.status)
+	}
+
+	// Expired token
+	rt := share.CLUSRecoveryToken{Username: "admin", TokenHash: utils.HashPassword("token"), ExpireAt: time.Now().UTC().Add(-time.Minute)}
+	clusHelper.PutRecoveryToken(&rt)
+	w = recoverAuth("token", "newpass")
+	if w.status != http.StatusUnauthorized {
+		t.Errorf("Recovery with expired token should fail: status=%v.", w.status)
+	}
+
+	// Wrong token
+	rt.ExpireAt = time.Now().UTC().Add(time.Minute)
+	clusHelper.PutRecoveryToken(&rt)
+	w = recoverAuth("wrong", "newpass")
+	if w.status != http.StatusUnauthorized {
+		t.Errorf("Recovery with wrong token should fail: status=%v.", w.status)
+	}
+
+	w = recoverAuth("token", "newpass")
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to recover user: status=%v.", w.status)
+	}
+	u, _, _ := clusHelper.GetUserRev("admin", access.NewReaderAccessControl())
+	if u == nil || u.Role != api.UserRoleAdmin || u.FailedLoginCount != 0 || u.MFAEnabled ||
+		u.PasswordHash != utils.HashPassword("newpass") {
+		t.Errorf("User is not recovered: %+v", u)
+	}
+
+	// Token can only be used once
+	w = recoverAuth("token", "newpass2")
+	if w.status != http.StatusUnauthorized {
+		t.Errorf("Recovery token should not be used again: status=%v.", w.status)
+	}
+
+	w = login("admin", "newpass")
+	if w.status != http.StatusOK {
+		t.Errorf("Failed to login recovered user: status=%v.", w.status)
+	}
+	logout(getLoginToken(w))
+
+	// Only the built-in admin account can be recovered
+	clusHelper.CreateUser(makeLocalUser("admin2", "pass", api.UserRoleReader))
+	rt.Username = "admin2"
+	clusHelper.PutRecoveryToken(&rt)
+	w = recoverAuth("token", "newpass")
+	if w.status != http.StatusBadRequest {
+		t.Errorf("Recovery of other user should fail: status=%v.", w.status)
+	}
+	if u, _, _ := clusHelper.GetUserRev("admin2", access.NewReaderAccessControl()); u == nil || u.Role != api.UserRoleReader {
+		t.Errorf("User should not be changed: %+v", u)
+	}
+	if rt, _ := clusHelper.GetRecoveryToken(); rt != nil {
+		t.Errorf("Recovery token should be deleted: %+v", rt)
+	}
+
+	// The built-in admin account is created if it doesn't exist
+	clusHelper.DeleteUser("admin")
+	rt.Username = "admin"
+	clusHelper.PutRecoveryToken(&rt)
+	w = recoverAuth("token", "newpass")
+	if w.status != http.StatusOK {
+		t.Errorf("Failed to recover non-existing user: status=%v.", w.status)
+	}
+	if u, _, _ := clusHelper.GetUserRev("admin", access.NewReaderAccessControl()); u == nil || u.Role != api.UserRoleAdmin {
+		t.Errorf("User is not created: %+v", u)
+	}
+
+	postTest()
+}
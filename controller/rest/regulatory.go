@@ -0,0 +1,200 @@
+package rest
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/controller/nvk8sapi/nvvalidatewebhookcfg"
+	"github.com/neuvector/neuvector/share"
+	scanUtils "github.com/neuvector/neuvector/share/scan"
+)
+
+const regulatoryViolationDetection = "Network.Violation"
+
+type regulatoryFindings struct {
+	compliances []*api.RESTComplianceAsset
+	admRules    []*api.RESTAdmissionRule
+	threats     []*api.Threat
+	incidents   []*api.Incident
+	violations  []*api.Violation
+}
+
+func getRegulatoryFindings(acc *access.AccessControl) *regulatoryFindings {
+	f := &regulatoryFindings{
+		compliances: getComplianceAssets(acc).Compliances,
+		threats:     cacher.GetThreats(acc),
+		incidents:   cacher.GetIncidents(acc),
+		violations:  cacher.GetViolations(acc),
+	}
+	for _, ruleType := range []string{api.ValidatingDenyRuleType, share.FedAdmCtrlDenyRulesType} {
+		f.admRules = append(f.admRules, cacher.GetAdmissionRules(admission.NvAdmValidateType, ruleType, acc)...)
+	}
+	return f
+}
+
+// The posture of the controls of the framework
+func regulatoryPosture(framework string, f *regulatoryFindings) []*api.RESTRegulatoryControlReport {
+	controls := share.RegulatoryFrameworkControls(framework)
+	reports := make(map[string]*api.RESTRegulatoryControlReport, len(controls))
+	list := make([]*api.RESTRegulatoryControlReport, 0, len(controls))
+	for _, c := range controls {
+		r := &api.RESTRegulatoryControlReport{
+			ID: c.ID, Name: c.Name, FailedChecks: make([]string, 0), Detections: make([]string, 0),
+		}
+		reports[c.Ref()] = r
+		list = append(list, r)
+	}
+
+	metas, _ := scanUtils.GetComplianceMeta()
+	for _, m := range metas {
+		for _, ref := range m.Controls {
+			if r, ok := reports[ref]; ok {
+				r.Checks++
+			}
+		}
+	}
+	for _, c := range f.compliances {
+		for _, ref := range c.Controls {
+			if r, ok := reports[ref]; ok {
+				r.FailedChecks = append(r.FailedChecks, c.Name)
+			}
+		}
+	}
+	for _, rule := range f.admRules {
+		if rule.Disable {
+			continue
+		}
+		for _, ref := range rule.Controls {
+			if r, ok := reports[ref]; ok {
+				r.AdmissionRules++
+			}
+		}
+	}
+
+	addDetection := func(refs []string, name string) {
+		for _, ref := range refs {
+			if r, ok := reports[ref]; ok {
+				r.Detections = append(r.Detections, name)
+			}
+		}
+	}
+	threatControls := share.RegulatoryControls(share.CLUSThreatRegulatoryAreas)
+	for _, info := range common.LogThreatMap {
+		addDetection(threatControls, info.Name)
+	}
+	for id, areas := range share.CLUSIncidentRegulatoryMap {
+		if info, ok := common.LogIncidentMap[id]; ok {
+			addDetection(share.RegulatoryControls(areas), info.Name)
+		}
+	}
+	addDetection(share.RegulatoryControls(share.CLUSViolationRegulatoryAreas), regulatoryViolationDetection)
+
+	lastHits := make(map[string]int64)
+	addHit := func(refs []string, ts int64) {
+		for _, ref := range refs {
+			if r, ok := reports[ref]; ok {
+				r.Hits++
+				if ts > lastHits[ref] {
+					lastHits[ref] = ts
+				}
+			}
+		}
+	}
+	for _, t := range f.threats {
+		addHit(t.Controls, t.ReportedTimeStamp)
+	}
+	for _, incd := range f.incidents {
+		addHit(incd.Controls, incd.ReportedTimeStamp)
+	}
+	for _, v := range f.violations {
+		addHit(v.Controls, v.ReportedTimeStamp)
+	}
+
+	for ref, r := range reports {
+		if ts, ok := lastHits[ref]; ok {
+			r.LastHitAt = api.RESTTimeString(time.Unix(ts, 0))
+		}
+		sort.Strings(r.FailedChecks)
+		sort.Strings(r.Detections)
+		if len(r.FailedChecks) > 0 {
+			r.Status = api.RegulatoryStatusFail
+		} else if r.Checks > 0 || r.AdmissionRules > 0 || len(r.Detections) > 0 {
+			r.Status = api.RegulatoryStatusPass
+		} else {
+			r.Status = api.RegulatoryStatusNotCovered
+		}
+	}
+	return list
+}
+
+func regulatorySummary(fw *share.CLUSRegulatoryFramework, controls []*api.RESTRegulatoryControlReport) *api.RESTRegulatoryFrameworkSummary {
+	s := &api.RESTRegulatoryFrameworkSummary{ID: fw.ID, Name: fw.Name, Controls: len(controls)}
+	for _, r := range controls {
+		switch r.Status {
+		case api.RegulatoryStatusPass:
+			s.PassedControls++
+		case api.RegulatoryStatusFail:
+			s.FailedControls++
+		default:
+			s.NotCoveredControls++
+		}
+		s.FailedChecks += len(r.FailedChecks)
+		s.Hits += r.Hits
+	}
+	return s
+}
+
+func handlerRegulatoryPostureList(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	f := getRegulatoryFindings(acc)
+	resp := api.RESTRegulatoryPostureData{
+		Frameworks: make([]*api.RESTRegulatoryFrameworkSummary, 0, len(share.CLUSRegulatoryFrameworks)),
+	}
+	for i := range share.CLUSRegulatoryFrameworks {
+		fw := &share.CLUSRegulatoryFrameworks[i]
+		resp.Frameworks = append(resp.Frameworks, regulatorySummary(fw, regulatoryPosture(fw.ID, f)))
+	}
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get regulatory posture")
+}
+
+func handlerRegulatoryPostureShow(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	var fw *share.CLUSRegulatoryFramework
+	for i := range share.CLUSRegulatoryFrameworks {
+		if share.CLUSRegulatoryFrameworks[i].ID == ps.ByName("framework") {
+			fw = &share.CLUSRegulatoryFrameworks[i]
+			break
+		}
+	}
+	if fw == nil {
+		restRespError(w, http.StatusNotFound, api.RESTErrObjectNotFound)
+		return
+	}
+
+	controls := regulatoryPosture(fw.ID, getRegulatoryFindings(acc))
+	resp := api.RESTRegulatoryFrameworkData{Framework: regulatorySummary(fw, controls), Controls: controls}
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get regulatory framework posture")
+}
@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+	scanUtils "github.com/neuvector/neuvector/share/scan"
+)
+
+func TestRegulatoryControls(t *testing.T) {
+	_, metaMap := scanUtils.GetComplianceMeta()
+	for num, ref := range map[string]string{
+		"D.1.1.3":  "PCI-DSS 10.2",        // audit
+		"K.1.1.1":  "NIST-800-53 AC-6",    // file mode
+		"K.2.1":    "HIPAA 164.312(e)(1)", // cert
+		"D.1.1.1":  "PCI-DSS 2.2",         // every check
+		"K.1.2.10": "NIST-800-53 CM-3",    // adm. ctrl.
+	} {
+		found := false
+		for _, c := range metaMap[num].Controls {
+			if c == ref {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Control %v not found in check %v: %+v", ref, num, metaMap[num].Controls)
+		}
+	}
+
+	rule := &share.CLUSAdmissionRule{Criteria: []*share.CLUSAdmRuleCriterion{
+		{Name: share.CriteriaKeyCVEHighCount}, {Name: share.CriteriaKeyNamespace},
+	}}
+	refs := share.RegulatoryControls(share.AdmissionRuleRegulatoryAreas(rule))
+	expect := []string{"HIPAA 164.308(a)(1)(ii)(A)", "HIPAA 164.308(a)(1)(ii)(D)", "NIST-800-53 CM-3", "NIST-800-53 RA-5",
+		"NIST-800-53 SI-2", "PCI-DSS 11.3", "PCI-DSS 6.3", "PCI-DSS 6.5"}
+	if len(refs) != len(expect) {
+		t.Fatalf("Unexpected controls: %+v", refs)
+	}
+	for i := range refs {
+		if refs[i] != expect[i] {
+			t.Errorf("Unexpected controls: %+v", refs)
+			break
+		}
+	}
+}
+
+func TestRegulatoryPosture(t *testing.T) {
+	_, metaMap := scanUtils.GetComplianceMeta()
+	f := &regulatoryFindings{
+		compliances: []*api.RESTComplianceAsset{
+			{Name: "D.1.1.3", Controls: metaMap["D.1.1.3"].Controls},
+		},
+		admRules: []*api.RESTAdmissionRule{
+			{Controls: []string{"PCI-DSS 6.3", "PCI-DSS 6.5"}},
+			{Controls: []string{"PCI-DSS 6.3"}, Disable: true},
+		},
+		incidents: []*api.Incident{
+			{LogCommon: api.LogCommon{ReportedTimeStamp: 100}, Controls: share.RegulatoryControls(share.CLUSIncidentRegulatoryMap[share.CLUSIncidContainerEscape])},
+		},
+		violations: []*api.Violation{
+			{LogCommon: api.LogCommon{ReportedTimeStamp: 200}, Controls: share.RegulatoryControls(share.CLUSViolationRegulatoryAreas)},
+			{LogCommon: api.LogCommon{ReportedTimeStamp: 300}, Controls: share.RegulatoryControls(share.CLUSViolationRegulatoryAreas)},
+		},
+	}
+
+	controls := regulatoryPosture(share.RegulatoryPCIDSS, f)
+	reports := make(map[string]*api.RESTRegulatoryControlReport)
+	for _, r := range controls {
+		reports[r.ID] = r
+	}
+	if r := reports["10.2"]; r == nil || r.Status != api.RegulatoryStatusFail || len(r.FailedChecks) != 1 || r.Checks == 0 {
+		t.Errorf("Unexpected control: %+v", r)
+	}
+	if r := reports["6.3"]; r == nil || r.Status != api.RegulatoryStatusPass || r.AdmissionRules != 1 {
+		t.Errorf("Unexpected control: %+v", r)
+	}
+	if r := reports["1.3"]; r == nil || r.Hits != 2 || r.LastHitAt == "" || len(r.Detections) == 0 {
+		t.Errorf("Unexpected control: %+v", r)
+	}
+	if r := reports["11.5"]; r == nil || r.Hits != 1 || len(r.Detections) == 0 {
+		t.Errorf("Unexpected control: %+v", r)
+	}
+
+	s := regulatorySummary(&share.CLUSRegulatoryFrameworks[0], controls)
+	if s.Controls != len(controls) || s.FailedControls != 2 || s.FailedChecks != 2 || s.Hits != 5 ||
+		s.PassedControls+s.FailedControls+s.NotCoveredControls != s.Controls {
+		t.Errorf("Unexpected summary: %+v", s)
+	}
+}
@@ -0,0 +1,841 @@
+package rest
+
+// Compliance and vulnerability reports. A report is generated in PDF or CSV by its schedule, on demand or in the
+// interval by the lead controller, and kept in the kv store for download. It's optionally emailed to the recipients
+// thru the SMTP server of the system config, and uploaded to the object storage of the configuration backup.
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/mail"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+const (
+	reportCheckPeriod      = time.Minute * 10
+	reportDefaultRetention = 5
+	reportMaxRetention     = 30
+	reportMaxPDFAssets     = 20 // assets listed for each entry of the PDF report
+	reportDefaultTitle     = "{{.Type}} Report - {{.OrgName}}"
+	reportUploadPrefix     = "reports/"
+	reportScheduledBy      = "schedule"
+)
+
+var reportRunning uint32
+var errReportRunning = errors.New("Another report is being generated")
+
+var reportSeverityRank = map[string]int{
+	strings.ToLower(share.VulnSeverityLow):      1,
+	strings.ToLower(share.VulnSeverityMedium):   2,
+	strings.ToLower(share.VulnSeverityHigh):     3,
+	strings.ToLower(share.VulnSeverityCritical): 4,
+}
+
+// Fields of the title template
+type reportTitleData struct {
+	OrgName string
+	Cluster string
+	Type    string
+	Scope   string
+	Date    string
+}
+
+// The first 2 columns of the rows are the name and the level of the entries
+type reportTable struct {
+	columns []string
+	rows    [][]string
+	summary []string
+}
+
+func reportContentType(format string) string {
+	if format == share.ReportFormatCSV {
+		return "text/csv"
+	}
+	return "application/pdf"
+}
+
+func reportScope(schedule *share.CLUSReportSchedule) string {
+	scope := "All namespaces"
+	if len(schedule.Domains) > 0 {
+		scope = fmt.Sprintf("Namespaces %s", strings.Join(schedule.Domains, ", "))
+	}
+	if len(schedule.Tags) > 0 {
+		scope += fmt.Sprintf("; Tags %s", strings.Join(schedule.Tags, ", "))
+	}
+	if schedule.Severity != "" {
+		scope += fmt.Sprintf("; Severity %s and above", schedule.Severity)
+	}
+	return scope
+}
+
+func reportTitle(title string, data *reportTitleData) (string, error) {
+	if title == "" {
+		title = reportDefaultTitle
+	}
+	tmpl, err := template.New("title").Option("missingkey=error").Parse(title)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// The display names of the assets, at most limit names are listed if limit is not 0
+func reportAssetNames(ids []string, idns map[string][]api.RESTIDName, limit int) string {
+	set := utils.NewSet()
+	for _, id := range ids {
+		if list, ok := idns[id]; ok && len(list) > 0 {
+			for _, idn := range list {
+				set.Add(idn.DisplayName)
+			}
+		} else {
+			set.Add(id)
+		}
+	}
+	names := set.ToStringSlice()
+	sort.Strings(names)
+	if limit > 0 && len(names) > limit {
+		return fmt.Sprintf("%s and %d more", strings.Join(names[:limit], ", "), len(names)-limit)
+	}
+	return strings.Join(names, ", ")
+}
+
+func reportLevelSummary(counts map[string]int) string {
+	levels := make([]string, 0, len(counts))
+	for level, count := range counts {
+		levels = append(levels, fmt.Sprintf("%s: %d", level, count))
+	}
+	sort.Strings(levels)
+	return strings.Join(levels, ", ")
+}
+
+func complianceReportTable(schedule *share.CLUSReportSchedule, data *api.RESTComplianceAssetData, limit int) *reportTable {
+	t := &reportTable{
+		columns: []string{"Name", "Level", "Category", "Type", "Profile", "Scored", "Description", "Remediation", "Tags",
+			"Workloads", "Nodes", "Images", "Platforms"},
+		rows: make([][]string, 0, len(data.Compliances)),
+	}
+	tags := utils.NewSetFromStringSlice(schedule.Tags)
+	counts := make(map[string]int)
+	for _, c := range data.Compliances {
+		if tags.Cardinality() > 0 && tags.Intersect(utils.NewSetFromStringSlice(c.Tags)).Cardinality() == 0 {
+			continue
+		}
+		counts[c.Level]++
+		t.rows = append(t.rows, []string{
+			c.Name, c.Level, c.Category, c.Type, c.Profile, strconv.FormatBool(c.Scored), c.Description, c.Remediation,
+			strings.Join(c.Tags, ", "),
+			reportAssetNames(c.Workloads, data.Workloads, limit),
+			reportAssetNames(c.Nodes, data.Nodes, limit),
+			reportAssetNames(c.Images, data.Images, limit),
+			reportAssetNames(c.Platforms, data.Platforms, limit),
+		})
+	}
+	t.summary = []string{
+		fmt.Sprintf("Kubernetes CIS version: %s", data.KubeVersion),
+		fmt.Sprintf("Docker CIS version: %s", data.DockerVersion),
+		fmt.Sprintf("Failed checks: %d (%s)", len(t.rows), reportLevelSummary(counts)),
+	}
+	return t
+}
+
+func vulnerabilityReportTable(schedule *share.CLUSReportSchedule, data *api.RESTVulnerabilityAssetData, limit int) *reportTable {
+	t := &reportTable{
+		columns: []string{"Name", "Severity", "Score", "Score V3", "Packages", "Description", "Link", "Published",
+			"Workloads", "Nodes", "Images", "Platforms"},
+		rows: make([][]string, 0, len(data.Vuls)),
+	}
+	minRank := reportSeverityRank[strings.ToLower(schedule.Severity)]
+	counts := make(map[string]int)
+	for _, v := range data.Vuls {
+		if reportSeverityRank[strings.ToLower(v.Severity)] < minRank {
+			continue
+		}
+		counts[v.Severity]++
+
+		pkgs := make([]string, 0, len(v.Packages))
+		for name, vers := range v.Packages {
+			for _, ver := range vers {
+				if ver.FixedVersion != "" {
+					pkgs = append(pkgs, fmt.Sprintf("%s %s (fixed in %s)", name, ver.PackageVersion, ver.FixedVersion))
+				} else {
+					pkgs = append(pkgs, fmt.Sprintf("%s %s", name, ver.PackageVersion))
+				}
+			}
+		}
+		sort.Strings(pkgs)
+		var published string
+		if v.PublishedTS > 0 {
+			published = time.Unix(v.PublishedTS, 0).UTC().Format("2006-01-02")
+		}
+		t.rows = append(t.rows, []string{
+			v.Name, v.Severity,
+			strconv.FormatFloat(float64(v.Score), 'f', 1, 32), strconv.FormatFloat(float64(v.ScoreV3), 'f', 1, 32),
+			strings.Join(pkgs, ", "), v.Description, v.Link, published,
+			reportAssetNames(v.Workloads, data.Workloads, limit),
+			reportAssetNames(v.Nodes, data.Nodes, limit),
+			reportAssetNames(v.Images, data.Images, limit),
+			reportAssetNames(v.Platforms, data.Platforms, limit),
+		})
+	}
+	t.summary = []string{fmt.Sprintf("Vulnerabilities: %d (%s)", len(t.rows), reportLevelSummary(counts))}
+	return t
+}
+
+func renderReportCSV(t *reportTable) []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(t.columns)
+	w.WriteAll(t.rows)
+	return buf.Bytes()
+}
+
+func renderReportPDF(title string, meta []string, t *reportTable, createdAt time.Time) []byte {
+	d := newPDFDoc(title)
+	d.heading(title, 16)
+	for _, line := range meta {
+		d.text(line, 0)
+	}
+	d.heading("Summary", 12)
+	for _, line := range t.summary {
+		d.text(line, 0)
+	}
+	d.heading("Details", 12)
+	if len(t.rows) == 0 {
+		d.text("No entries in the scope.", 0)
+	}
+	for _, row := range t.rows {
+		d.heading(fmt.Sprintf("%s [%s]", row[0], row[1]), 10)
+		for i := 2; i < len(row); i++ {
+			if row[i] != "" {
+				d.text(fmt.Sprintf("%s: %s", t.columns[i], row[i]), 10)
+			}
+		}
+	}
+	return d.bytes(createdAt)
+}
+
+func generateReport(schedule *share.CLUSReportSchedule, by string) (*share.CLUSReport, error) {
+	now := time.Now().UTC()
+	td := reportTitleData{
+		OrgName: schedule.OrgName,
+		Cluster: cacher.GetSystemConfig(access.NewReaderAccessControl()).ClusterName,
+		Scope:   reportScope(schedule),
+		Date:    now.Format("2006-01-02"),
+	}
+
+	var limit int
+	if schedule.Format == share.ReportFormatPDF {
+		limit = reportMaxPDFAssets
+	}
+	acc := access.NewDomainReaderAccessControl(schedule.Domains)
+	var t *reportTable
+	switch schedule.Type {
+	case share.ReportTypeCompliance:
+		td.Type = "Compliance"
+		t = complianceReportTable(schedule, getComplianceAssets(acc), limit)
+	case share.ReportTypeVulnerability:
+		td.Type = "Vulnerability"
+		t = vulnerabilityReportTable(schedule, getVulnerabilityAssets(acc), limit)
+	default:
+		return nil, fmt.Errorf("Unsupported report type %s", schedule.Type)
+	}
+
+	title, err := reportTitle(schedule.Title, &td)
+	if err != nil {
+		return nil, err
+	}
+	id, err := utils.GetGuid()
+	if err != nil {
+		return nil, err
+	}
+	report := &share.CLUSReport{
+		ID:          id,
+		Schedule:    schedule.Name,
+		Type:        schedule.Type,
+		Format:      schedule.Format,
+		Title:       title,
+		FileName:    fmt.Sprintf("%s-%s.%s", schedule.Name, now.Format("20060102-150405"), schedule.Format),
+		GeneratedAt: now,
+		GeneratedBy: by,
+		Entries:     len(t.rows),
+	}
+	if schedule.Format == share.ReportFormatCSV {
+		report.Data = renderReportCSV(t)
+	} else {
+		meta := []string{
+			fmt.Sprintf("Organization: %s", td.OrgName),
+			fmt.Sprintf("Cluster: %s", td.Cluster),
+			fmt.Sprintf("Scope: %s", td.Scope),
+			fmt.Sprintf("Generated at: %s", now.Format(time.RFC1123)),
+		}
+		report.Data = renderReportPDF(title, meta, t, now)
+	}
+	return report, nil
+}
+
+// Keep the latest reports of the schedule
+func pruneReports(name string, retention uint32) {
+	reports := make([]*share.CLUSReport, 0)
+	for _, report := range clusHelper.GetAllReports() {
+		if report.Schedule == name {
+			reports = append(reports, report)
+		}
+	}
+	if len(reports) <= int(retention) {
+		return
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].GeneratedAt.Before(reports[j].GeneratedAt) })
+	for _, report := range reports[:len(reports)-int(retention)] {
+		if err := clusHelper.DeleteReport(report.ID); err == nil {
+			log.WithFields(log.Fields{"id": report.ID, "schedule": name}).Info("Removed report")
+		}
+	}
+}
+
+func emailReport(schedule *share.CLUSReportSchedule, report *share.CLUSReport) error {
+	cfg, _ := clusHelper.GetSystemConfigRev(access.NewReaderAccessControl())
+	if cfg.SMTP.Server == "" || cfg.SMTP.From == "" {
+		return errors.New("SMTP server is not configured")
+	}
+	body := fmt.Sprintf("%s\n\nGenerated at: %s\nEntries: %d\n",
+		report.Title, report.GeneratedAt.Format(time.RFC1123), report.Entries)
+	att := &common.SMTPAttachment{Name: report.FileName, ContentType: reportContentType(report.Format), Data: report.Data}
+	return common.SendSMTPAttachment(&cfg.SMTP, schedule.Recipients, report.Title, []byte(body), att)
+}
+
+func uploadReport(report *share.CLUSReport) error {
+	cfg, _ := clusHelper.GetBackupConfigRev()
+	if cfg.Storage.Type == "" {
+		return errBackupNotConfigured
+	}
+	storage, err := newBackupStorage(&cfg.Storage)
+	if err != nil {
+		return err
+	}
+	return storage.upload(reportUploadPrefix+report.FileName, report.Data)
+}
+
+// Generate, store and deliver the report of the schedule. The report is returned if it's stored, even if the
+// delivery fails.
+func runReport(schedule *share.CLUSReportSchedule, by string) (*share.CLUSReport, error) {
+	if !atomic.CompareAndSwapUint32(&reportRunning, 0, 1) {
+		return nil, errReportRunning
+	}
+	defer atomic.StoreUint32(&reportRunning, 0)
+
+	report, err := generateReport(schedule, by)
+	if err == nil {
+		if err = clusHelper.PutReport(report); err != nil {
+			report = nil
+		}
+	}
+	if report != nil {
+		pruneReports(schedule.Name, schedule.Retention)
+		log.WithFields(log.Fields{"name": report.FileName, "entries": report.Entries}).Info("Report generated")
+
+		errs := make([]string, 0)
+		if len(schedule.Recipients) > 0 {
+			if err := emailReport(schedule, report); err != nil {
+				errs = append(errs, fmt.Sprintf("Failed to email report: %s", err.Error()))
+			}
+		}
+		if schedule.Upload {
+			if err := uploadReport(report); err != nil {
+				errs = append(errs, fmt.Sprintf("Failed to upload report: %s", err.Error()))
+			}
+		}
+		if len(errs) > 0 {
+			err = errors.New(strings.Join(errs, "; "))
+		}
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"schedule": schedule.Name, "error": err}).Error()
+	}
+
+	status := clusHelper.GetReportStatus()
+	s := &share.CLUSReportScheduleStatus{LastRunAt: time.Now().UTC()}
+	if err != nil {
+		s.LastError = err.Error()
+	}
+	status.Schedules[schedule.Name] = s
+	clusHelper.PutReportStatus(status)
+
+	return report, err
+}
+
+func reportEventMsg(schedule *share.CLUSReportSchedule, report *share.CLUSReport, err error) (share.TLogEvent, string) {
+	if report == nil {
+		return share.CLUSEvReportGenerateFail, fmt.Sprintf("Failed to generate report of %s: %s", schedule.Name, err.Error())
+	} else if err != nil {
+		return share.CLUSEvReportGenerateFail, fmt.Sprintf("Report %s is generated. %s", report.FileName, err.Error())
+	}
+	return share.CLUSEvReportGenerated, fmt.Sprintf("Report %s is generated", report.FileName)
+}
+
+func scheduledReports() {
+	status := clusHelper.GetReportStatus()
+	for _, schedule := range clusHelper.GetAllReportSchedules() {
+		if schedule.Interval == 0 {
+			continue
+		}
+		if s, ok := status.Schedules[schedule.Name]; ok && time.Since(s.LastRunAt) < time.Duration(schedule.Interval)*time.Hour {
+			continue
+		}
+
+		report, err := runReport(schedule, reportScheduledBy)
+		if err == errReportRunning {
+			return
+		}
+		clog := share.CLUSEventLog{
+			HostID:         localDev.Host.ID,
+			HostName:       localDev.Host.Name,
+			ControllerID:   localDev.Ctrler.ID,
+			ControllerName: localDev.Ctrler.Name,
+		}
+		clog.Event, clog.Msg = reportEventMsg(schedule, report, err)
+		clog.ReportedAt = time.Now().UTC()
+		evqueue.Append(&clog)
+	}
+}
+
+func ReportScheduleProc() {
+	cSig := make(chan os.Signal, 1)
+	signal.Notify(cSig, os.Interrupt, syscall.SIGTERM)
+	ticker := time.Tick(reportCheckPeriod)
+Loop:
+	for {
+		select {
+		case <-ticker:
+			if atomic.LoadUint32(&_isLeader) == 1 {
+				scheduledReports()
+			}
+		case <-cSig:
+			break Loop
+		}
+	}
+}
+
+func parseReportSchedule(rc *api.RESTReportScheduleConfig, old *share.CLUSReportSchedule) (*share.CLUSReportSchedule, error) {
+	schedule := *old
+	if rc.Comment != nil {
+		schedule.Comment = *rc.Comment
+	}
+	if rc.Type != nil {
+		schedule.Type = *rc.Type
+	}
+	if rc.Format != nil {
+		schedule.Format = *rc.Format
+	}
+	if rc.Interval != nil {
+		schedule.Interval = *rc.Interval
+	}
+	if rc.Retention != nil {
+		schedule.Retention = *rc.Retention
+	}
+	if rc.OrgName != nil {
+		schedule.OrgName = strings.TrimSpace(*rc.OrgName)
+	}
+	if rc.Title != nil {
+		schedule.Title = strings.TrimSpace(*rc.Title)
+	}
+	if rc.Domains != nil {
+		schedule.Domains = *rc.Domains
+	}
+	if rc.Tags != nil {
+		schedule.Tags = *rc.Tags
+	}
+	if rc.Severity != nil {
+		schedule.Severity = *rc.Severity
+	}
+	if rc.Recipients != nil {
+		schedule.Recipients = *rc.Recipients
+	}
+	if rc.Upload != nil {
+		schedule.Upload = *rc.Upload
+	}
+	if schedule.Format == "" {
+		schedule.Format = share.ReportFormatPDF
+	}
+	if schedule.Retention == 0 {
+		schedule.Retention = reportDefaultRetention
+	}
+
+	switch schedule.Type {
+	case share.ReportTypeCompliance:
+		if schedule.Severity != "" {
+			return nil, errors.New("Severity is only applied to the vulnerability report")
+		}
+	case share.ReportTypeVulnerability:
+		if len(schedule.Tags) > 0 {
+			return nil, errors.New("Tags are only applied to the compliance report")
+		}
+		if _, ok := reportSeverityRank[strings.ToLower(schedule.Severity)]; schedule.Severity != "" && !ok {
+			return nil, fmt.Errorf("Invalid severity %s", schedule.Severity)
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported report type %s", schedule.Type)
+	}
+	switch schedule.Format {
+	case share.ReportFormatPDF, share.ReportFormatCSV:
+	default:
+		return nil, fmt.Errorf("Unsupported report format %s", schedule.Format)
+	}
+	if schedule.Retention > reportMaxRetention {
+		return nil, fmt.Errorf("Retention cannot be more than %d", reportMaxRetention)
+	}
+	for _, domain := range schedule.Domains {
+		if !isObjectNameValid(domain) {
+			return nil, fmt.Errorf("Invalid namespace %s", domain)
+		}
+	}
+	for _, rcpt := range schedule.Recipients {
+		if _, err := mail.ParseAddress(rcpt); err != nil {
+			return nil, fmt.Errorf("Invalid recipient %s", rcpt)
+		}
+	}
+	if _, err := reportTitle(schedule.Title, &reportTitleData{}); err != nil {
+		return nil, fmt.Errorf("Invalid title: %s", err.Error())
+	}
+
+	return &schedule, nil
+}
+
+func reportSchedule2REST(schedule *share.CLUSReportSchedule, status *share.CLUSReportStatus) *api.RESTReportSchedule {
+	r := &api.RESTReportSchedule{
+		Name:       schedule.Name,
+		Comment:    schedule.Comment,
+		Type:       schedule.Type,
+		Format:     schedule.Format,
+		Interval:   schedule.Interval,
+		Retention:  schedule.Retention,
+		OrgName:    schedule.OrgName,
+		Title:      schedule.Title,
+		Domains:    schedule.Domains,
+		Tags:       schedule.Tags,
+		Severity:   schedule.Severity,
+		Recipients: schedule.Recipients,
+		Upload:     schedule.Upload,
+	}
+	if r.Domains == nil {
+		r.Domains = make([]string, 0)
+	}
+	if r.Tags == nil {
+		r.Tags = make([]string, 0)
+	}
+	if r.Recipients == nil {
+		r.Recipients = make([]string, 0)
+	}
+	if s, ok := status.Schedules[schedule.Name]; ok {
+		r.LastRunAt = s.LastRunAt
+		r.LastError = s.LastError
+	}
+	return r
+}
+
+func report2REST(report *share.CLUSReport) *api.RESTReport {
+	return &api.RESTReport{
+		ID:          report.ID,
+		Schedule:    report.Schedule,
+		Type:        report.Type,
+		Format:      report.Format,
+		Title:       report.Title,
+		FileName:    report.FileName,
+		Size:        len(report.Data),
+		Entries:     report.Entries,
+		GeneratedAt: report.GeneratedAt,
+		GeneratedBy: report.GeneratedBy,
+	}
+}
+
+func handlerReportScheduleList(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	schedules := clusHelper.GetAllReportSchedules()
+	status := clusHelper.GetReportStatus()
+	resp := api.RESTReportSchedulesData{Schedules: make([]*api.RESTReportSchedule, 0, len(schedules))}
+	for _, schedule := range schedules {
+		resp.Schedules = append(resp.Schedules, reportSchedule2REST(schedule, status))
+	}
+	sort.Slice(resp.Schedules, func(i, j int) bool { return resp.Schedules[i].Name < resp.Schedules[j].Name })
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get report schedule list")
+}
+
+func handlerReportScheduleShow(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	schedule, _, err := clusHelper.GetReportScheduleRev(ps.ByName("name"))
+	if err != nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+
+	resp := api.RESTReportScheduleData{Schedule: reportSchedule2REST(schedule, clusHelper.GetReportStatus())}
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get report schedule")
+}
+
+func handlerReportScheduleCreate(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	var rconf api.RESTReportScheduleConfigData
+	body, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(body, &rconf); err != nil || rconf.Config == nil {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+	if !isObjectNameValid(rconf.Config.Name) {
+		e := "Invalid characters in name"
+		log.WithFields(log.Fields{"name": rconf.Config.Name}).Error(e)
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidName, e)
+		return
+	}
+	if _, _, err := clusHelper.GetReportScheduleRev(rconf.Config.Name); err == nil {
+		e := "Report schedule already exists"
+		log.WithFields(log.Fields{"name": rconf.Config.Name}).Error(e)
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrDuplicateName, e)
+		return
+	}
+
+	schedule, err := parseReportSchedule(rconf.Config, &share.CLUSReportSchedule{Name: rconf.Config.Name})
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Invalid report schedule")
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+		return
+	}
+	if err := clusHelper.PutReportScheduleRev(schedule, 0); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error()
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+
+	restRespSuccess(w, r, nil, acc, login, &rconf, fmt.Sprintf("Create report schedule %s", schedule.Name))
+}
+
+func handlerReportScheduleConfig(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	name := ps.ByName("name")
+	var rconf api.RESTReportScheduleConfigData
+	body, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(body, &rconf); err != nil || rconf.Config == nil || rconf.Config.Name != name {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	old, rev, err := clusHelper.GetReportScheduleRev(name)
+	if err != nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+	schedule, err := parseReportSchedule(rconf.Config, old)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Invalid report schedule")
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+		return
+	}
+	if err := clusHelper.PutReportScheduleRev(schedule, rev); err != nil {
+		log.WithFields(log.Fields{"error": err, "rev": rev}).Error()
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+
+	restRespSuccess(w, r, nil, acc, login, &rconf, fmt.Sprintf("Configure report schedule %s", name))
+}
+
+// The generated reports of the schedule are removed as well
+func handlerReportScheduleDelete(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	name := ps.ByName("name")
+	if err := clusHelper.DeleteReportSchedule(name); err != nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+	pruneReports(name, 0)
+	status := clusHelper.GetReportStatus()
+	if _, ok := status.Schedules[name]; ok {
+		delete(status.Schedules, name)
+		clusHelper.PutReportStatus(status)
+	}
+
+	restRespSuccess(w, r, nil, acc, login, nil, fmt.Sprintf("Delete report schedule %s", name))
+}
+
+func handlerReportRun(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	schedule, _, err := clusHelper.GetReportScheduleRev(ps.ByName("name"))
+	if err != nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+
+	report, err := runReport(schedule, login.fullname)
+	if err == errReportRunning {
+		restRespErrorMessage(w, http.StatusConflict, api.RESTErrFailExport, err.Error())
+		return
+	}
+	ev, msg := reportEventMsg(schedule, report, err)
+	configLog(ev, login, msg)
+	if report == nil {
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFailExport, err.Error())
+		return
+	}
+
+	resp := api.RESTReportData{Report: report2REST(report)}
+	restRespSuccess(w, r, &resp, acc, login, nil, fmt.Sprintf("Generate report of %s", schedule.Name))
+}
+
+func handlerReportList(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	name := r.URL.Query().Get("schedule")
+	reports := clusHelper.GetAllReports()
+	resp := api.RESTReportsData{Reports: make([]*api.RESTReport, 0, len(reports))}
+	for _, report := range reports {
+		if name == "" || report.Schedule == name {
+			resp.Reports = append(resp.Reports, report2REST(report))
+		}
+	}
+	// newest first
+	sort.Slice(resp.Reports, func(i, j int) bool { return resp.Reports[i].GeneratedAt.After(resp.Reports[j].GeneratedAt) })
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get report list")
+}
+
+func handlerReportDownload(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	report, err := clusHelper.GetReport(ps.ByName("id"))
+	if err != nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+
+	// tell the browser the returned content should be downloaded
+	w.Header().Set("Content-Type", reportContentType(report.Format))
+	w.Header().Set("Content-Disposition", "Attachment; filename="+report.FileName)
+	w.WriteHeader(http.StatusOK)
+	w.Write(report.Data)
+}
+
+func handlerReportDelete(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSSystemConfig{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	id := ps.ByName("id")
+	if err := clusHelper.DeleteReport(id); err != nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+
+	restRespSuccess(w, r, nil, acc, login, nil, fmt.Sprintf("Delete report %s", id))
+}
@@ -0,0 +1,159 @@
+package rest
+
+// A minimal PDF writer of the text reports. The text is laid out in lines of the standard Helvetica fonts, so no
+// font is embedded. The characters out of the WinAnsi encoding are replaced with '?'.
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	pdfPageWidth   = 612 // letter size in points
+	pdfPageHeight  = 792
+	pdfMargin      = 50
+	pdfCharWidth   = 0.52 // average width of Helvetica characters relative to the font size
+	pdfLineSpacing = 1.3
+)
+
+type pdfDoc struct {
+	title string
+	pages []*bytes.Buffer // content stream of the pages
+	y     float64
+}
+
+func newPDFDoc(title string) *pdfDoc {
+	d := &pdfDoc{title: title}
+	d.newPage()
+	return d
+}
+
+func (d *pdfDoc) newPage() {
+	page := &bytes.Buffer{}
+	d.pages = append(d.pages, page)
+	d.y = pdfPageHeight - pdfMargin
+	// page number in the footer
+	fmt.Fprintf(page, "BT /F1 8 Tf %d %d Td (%d) Tj ET\n", pdfPageWidth/2, pdfMargin/2, len(d.pages))
+}
+
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString("    ")
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			// latin-1 characters are the same in WinAnsi encoding
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// Split the text into the lines that fit in the width
+func pdfWrap(text string, size, width float64) []string {
+	max := int(width / (size * pdfCharWidth))
+	lines := make([]string, 0)
+	for _, para := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			for len(word) > max {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				lines = append(lines, word[:max])
+				word = word[max:]
+			}
+			if line == "" {
+				line = word
+			} else if len(line)+1+len(word) <= max {
+				line += " " + word
+			} else {
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (d *pdfDoc) write(text string, size, indent float64, bold bool) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	height := size * pdfLineSpacing
+	for _, line := range pdfWrap(text, size, pdfPageWidth-2*pdfMargin-indent) {
+		if d.y-height < pdfMargin {
+			d.newPage()
+		}
+		d.y -= height
+		if line != "" {
+			fmt.Fprintf(d.pages[len(d.pages)-1], "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n",
+				font, size, pdfMargin+indent, d.y, pdfEscape(line))
+		}
+	}
+}
+
+func (d *pdfDoc) heading(text string, size float64) {
+	d.space(size / 2)
+	d.write(text, size, 0, true)
+}
+
+func (d *pdfDoc) text(text string, indent float64) {
+	d.write(text, 9, indent, false)
+}
+
+func (d *pdfDoc) space(height float64) {
+	if d.y -= height; d.y < pdfMargin {
+		d.newPage()
+	}
+}
+
+func (d *pdfDoc) bytes(createdAt time.Time) []byte {
+	var buf bytes.Buffer
+	offsets := make([]int, 0)
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	// 1: catalog, 2: pages, 3-4: fonts, 5: info, then the page and the content of each page
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+i*2)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (NeuVector) /CreationDate (D:%s) >>",
+		pdfEscape(d.title), createdAt.UTC().Format("20060102150405Z")))
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, firstPage+i*2+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
@@ -0,0 +1,138 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestParseReportSchedule(t *testing.T) {
+	rtype := share.ReportTypeVulnerability
+	severity := "High"
+	title := "{{.OrgName}} report of {{.Date}}"
+	domains := []string{"ns1", "ns2"}
+	schedule, err := parseReportSchedule(&api.RESTReportScheduleConfig{
+		Name: "weekly", Type: &rtype, Severity: &severity, Title: &title, Domains: &domains,
+	}, &share.CLUSReportSchedule{Name: "weekly"})
+	if err != nil {
+		t.Fatalf("Failed to parse schedule: %s", err)
+	}
+	if schedule.Format != share.ReportFormatPDF || schedule.Retention != reportDefaultRetention || len(schedule.Domains) != 2 {
+		t.Errorf("Unexpected schedule: %+v", schedule)
+	}
+
+	compliance := share.ReportTypeCompliance
+	csvFormat := share.ReportFormatCSV
+	invalid := "invalid"
+	retention := uint32(reportMaxRetention + 1)
+	badTitle := "{{.Unknown}}"
+	badDomains := []string{"ns 1"}
+	badRecipients := []string{"not an address"}
+	tags := []string{api.ComplianceTemplatePCI}
+	for _, rc := range []*api.RESTReportScheduleConfig{
+		&api.RESTReportScheduleConfig{Type: &invalid},
+		&api.RESTReportScheduleConfig{Type: &compliance, Format: &invalid},
+		&api.RESTReportScheduleConfig{Type: &compliance, Severity: &severity},
+		&api.RESTReportScheduleConfig{Type: &rtype, Tags: &tags},
+		&api.RESTReportScheduleConfig{Type: &rtype, Severity: &invalid},
+		&api.RESTReportScheduleConfig{Type: &compliance, Format: &csvFormat, Retention: &retention},
+		&api.RESTReportScheduleConfig{Type: &compliance, Title: &badTitle},
+		&api.RESTReportScheduleConfig{Type: &compliance, Domains: &badDomains},
+		&api.RESTReportScheduleConfig{Type: &compliance, Recipients: &badRecipients},
+	} {
+		if _, err := parseReportSchedule(rc, &share.CLUSReportSchedule{Name: "test"}); err == nil {
+			t.Errorf("Schedule should be invalid: %+v", rc)
+		}
+	}
+}
+
+func TestComplianceReport(t *testing.T) {
+	data := &api.RESTComplianceAssetData{
+		Compliances: []*api.RESTComplianceAsset{
+			&api.RESTComplianceAsset{Name: "D.4.1", Level: "WARN", Category: "docker", Description: "Create a user (test)",
+				Tags: []string{api.ComplianceTemplatePCI}, Workloads: []string{"w1", "w2"}},
+			&api.RESTComplianceAsset{Name: "K.1.1.1", Level: "INFO", Category: "kubernetes", Nodes: []string{"n1"}},
+		},
+		Workloads: map[string][]api.RESTIDName{
+			"w1": []api.RESTIDName{{DisplayName: "nginx"}},
+			"w2": []api.RESTIDName{{DisplayName: "redis"}},
+		},
+		Nodes: map[string][]api.RESTIDName{"n1": []api.RESTIDName{{DisplayName: "node1"}}},
+	}
+
+	tbl := complianceReportTable(&share.CLUSReportSchedule{}, data, 1)
+	if len(tbl.rows) != 2 || tbl.rows[0][9] != "nginx and 1 more" || tbl.rows[1][10] != "node1" {
+		t.Errorf("Unexpected rows: %+v", tbl.rows)
+	}
+	tbl = complianceReportTable(&share.CLUSReportSchedule{Tags: []string{api.ComplianceTemplatePCI}}, data, 0)
+	if len(tbl.rows) != 1 || tbl.rows[0][9] != "nginx, redis" || tbl.summary[2] != "Failed checks: 1 (WARN: 1)" {
+		t.Errorf("Unexpected rows: %+v, summary: %+v", tbl.rows, tbl.summary)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(renderReportCSV(tbl))).ReadAll()
+	if err != nil || len(records) != 2 || records[0][0] != "Name" || records[1][0] != "D.4.1" {
+		t.Errorf("Unexpected CSV: %+v, %v", records, err)
+	}
+
+	pdf := renderReportPDF("Compliance Report", []string{"Organization: NeuVector"}, tbl, time.Unix(0, 0))
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) ||
+		!bytes.Contains(pdf, []byte(`Create a user \(test\)`)) {
+		t.Errorf("Unexpected PDF: %s", pdf)
+	}
+	// the objects are at the offsets of the cross-reference table
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	if m == nil {
+		t.Fatalf("No startxref: %s", pdf)
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	if len(entries) == 0 {
+		t.Fatalf("No xref entries: %s", pdf[xref:])
+	}
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		if !bytes.HasPrefix(pdf[off:], []byte(fmt.Sprintf("%d 0 obj", i+1))) {
+			t.Errorf("Invalid offset of object %d: %d", i+1, off)
+		}
+	}
+}
+
+func TestVulnerabilityReport(t *testing.T) {
+	data := &api.RESTVulnerabilityAssetData{
+		Vuls: []*api.RESTVulnerabilityAsset{
+			&api.RESTVulnerabilityAsset{Name: "CVE-2023-0001", Severity: "High", ScoreV3: 7.5,
+				Packages: map[string][]api.RESTVulnPackageVersion{"openssl": {{PackageVersion: "1.1.1", FixedVersion: "1.1.1t"}}}},
+			&api.RESTVulnerabilityAsset{Name: "CVE-2023-0002", Severity: "Medium", ScoreV3: 5.0},
+		},
+	}
+	tbl := vulnerabilityReportTable(&share.CLUSReportSchedule{Severity: "high"}, data, 0)
+	if len(tbl.rows) != 1 || tbl.rows[0][3] != "7.5" || tbl.rows[0][4] != "openssl 1.1.1 (fixed in 1.1.1t)" {
+		t.Errorf("Unexpected rows: %+v", tbl.rows)
+	}
+}
+
+func TestReportPrune(t *testing.T) {
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+
+	now := time.Now()
+	for i, name := range []string{"s1", "s1", "s1", "s2"} {
+		clusHelper.PutReport(&share.CLUSReport{ID: fmt.Sprintf("r%d", i), Schedule: name, GeneratedAt: now.Add(time.Duration(i) * time.Minute)})
+	}
+	pruneReports("s1", 2)
+	if _, err := clusHelper.GetReport("r0"); err == nil {
+		t.Errorf("The oldest report should be removed")
+	}
+	if len(clusHelper.GetAllReports()) != 3 {
+		t.Errorf("Unexpected reports: %+v", clusHelper.GetAllReports())
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -22,6 +23,8 @@ import (
 	"github.com/neuvector/neuvector/share/utils"
 )
 
+const maxWebhookDedupKeyLen = 255
+
 var responseRuleOptions map[string]*api.RESTResponseRuleOptions
 var responseRuleOptionsForLocalUsers map[string]*api.RESTResponseRuleOptions
 
@@ -270,7 +273,7 @@ func handlerResponseRuleOptions(w http.ResponseWriter, r *http.Request, ps httpr
 }
 
 func isValidAction(act string) bool {
-	if act != share.EventActionQuarantine &&
+	if act != share.EventActionQuarantine && act != share.EventActionQuarantineSource && act != share.EventActionFedQuarantine &&
 		act != share.EventActionSuppressLog && act != share.EventActionWebhook {
 		return false
 	}
@@ -281,6 +284,13 @@ func validateResponseRule(r *api.RESTResponseRule, acc *access.AccessControl) er
 	if r.Event == "" {
 		return fmt.Errorf("Missing event for response rule")
 	}
+	if r.ExpireTS != 0 {
+		if r.CfgType == api.CfgTypeFederal || r.CfgType == api.CfgTypeGround {
+			return fmt.Errorf("Expiration time is not supported by federal or CRD response rule")
+		} else if r.ExpireTS < 0 || (!r.Disable && r.ExpireTS <= time.Now().Unix()) {
+			return fmt.Errorf("Expiration time must be in the future")
+		}
+	}
 
 	options := getResponeRuleOptions(acc)
 	if option, ok := options[r.Event]; !ok {
@@ -323,10 +333,23 @@ func validateResponseRule(r *api.RESTResponseRule, acc *access.AccessControl) er
 	}
 
 	var scWebhooks []share.CLUSWebhook
+	var hasWebhook, hasQuarantine bool
 	for _, act := range r.Actions {
 		if !isValidAction(act) {
 			return fmt.Errorf("Action %s is not supported", act)
 		}
+		if act == share.EventActionQuarantineSource && r.Event != share.EventRuntime {
+			return fmt.Errorf("Action %s is only supported for %s event", act, share.EventRuntime)
+		}
+		if act == share.EventActionFedQuarantine && r.CfgType != api.CfgTypeFederal {
+			return fmt.Errorf("Action %s is only supported for federal response rules", act)
+		}
+
+		if act == share.EventActionWebhook {
+			hasWebhook = true
+		} else if act == share.EventActionQuarantine || act == share.EventActionQuarantineSource || act == share.EventActionFedQuarantine {
+			hasQuarantine = true
+		}
 
 		// We specifically allow action to be webhook without specifying webhook name,
 		// because it is allowed in the pre-multi-webhook config.
@@ -358,6 +381,36 @@ func validateResponseRule(r *api.RESTResponseRule, acc *access.AccessControl) er
 		}
 	}
 
+	if len(r.QuarExceptions) > 0 {
+		if !hasQuarantine {
+			return fmt.Errorf("Quarantine exceptions require action %s, %s or %s", share.EventActionQuarantine,
+				share.EventActionQuarantineSource, share.EventActionFedQuarantine)
+		}
+		if err := validateQuarExceptions(r.QuarExceptions); err != nil {
+			return err
+		}
+	}
+
+	if r.WebhookTemplate != "" {
+		if !hasWebhook {
+			return fmt.Errorf("Webhook template requires action %s", share.EventActionWebhook)
+		}
+		if err := common.ValidateWebhookTemplate(r.WebhookTemplate); err != nil {
+			return err
+		}
+	}
+	if r.WebhookSeverity != "" || r.WebhookDedupKey != "" {
+		if !hasWebhook {
+			return fmt.Errorf("Webhook severity and dedup key require action %s", share.EventActionWebhook)
+		}
+		if r.WebhookSeverity != "" && !common.ValidatePagerDutySeverity(r.WebhookSeverity) {
+			return fmt.Errorf("Invalid webhook severity %s", r.WebhookSeverity)
+		}
+		if len(r.WebhookDedupKey) > maxWebhookDedupKeyLen {
+			return fmt.Errorf("Webhook dedup key is longer than %d characters", maxWebhookDedupKeyLen)
+		}
+	}
+
 	if r.Group != "" {
 		grp, _, _ := clusHelper.GetGroup(r.Group, acc)
 		if grp == nil {
@@ -379,21 +432,27 @@ func validateResponseRule(r *api.RESTResponseRule, acc *access.AccessControl) er
 
 func responseRule2Cluster(r *api.RESTResponseRule) *share.CLUSResponseRule {
 	ret := &share.CLUSResponseRule{
-		ID:         r.ID,
-		Event:      r.Event,
-		Comment:    r.Comment,
-		Group:      r.Group,
-		Conditions: r.Conditions, // Conditions []CLUSEventCondition `json:"conditions,omitempty"`
-		Actions:    r.Actions,    // Actions    []string             `json:"actions"`
-		Webhooks:   r.Webhooks,
-		Disable:    r.Disable,
+		ID:              r.ID,
+		Event:           r.Event,
+		Comment:         r.Comment,
+		Group:           r.Group,
+		Conditions:      r.Conditions, // Conditions []CLUSEventCondition `json:"conditions,omitempty"`
+		Actions:         r.Actions,    // Actions    []string             `json:"actions"`
+		Webhooks:        r.Webhooks,
+		WebhookTemplate: r.WebhookTemplate,
+		WebhookSeverity: r.WebhookSeverity,
+		WebhookDedupKey: r.WebhookDedupKey,
+		QuarExceptions:  r.QuarExceptions,
+		Disable:         r.Disable,
+		ExpireAt:        r.ExpireTS,
 	}
 	ret.CfgType, _ = cfgTypeMapping[r.CfgType]
 	return ret
 }
 
-// caller has been verified for federal admin access right
-func replaceFedResponseRules(rulesNew map[uint32]*share.CLUSResponseRule, rhsNew []*share.CLUSRuleHead) bool {
+// caller has been verified for federal admin access right. nil quarImagesNew means not to change the fed quarantine list
+func replaceFedResponseRules(rulesNew map[uint32]*share.CLUSResponseRule, rhsNew []*share.CLUSRuleHead,
+	quarImagesNew []*share.CLUSFedQuarantineImage) bool {
 	lock, err := clusHelper.AcquireLock(share.CLUSLockPolicyKey, clusterLockWait)
 	if err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("Failed to acquire cluster lock")
@@ -424,6 +483,11 @@ func replaceFedResponseRules(rulesNew map[uint32]*share.CLUSResponseRule, rhsNew
 		// overwrite rule headers list
 		clusHelper.PutResponseRuleListTxn(share.FedPolicyName, txn, rhsNew)
 	}
+	if quarImagesNew != nil {
+		if quarImagesExisting, _ := clusHelper.GetResponseQuarantineImages(share.FedPolicyName); !reflect.DeepEqual(quarImagesNew, quarImagesExisting) {
+			clusHelper.PutResponseQuarantineImagesTxn(share.FedPolicyName, txn, quarImagesNew)
+		}
+	}
 
 	if ok, err := txn.Apply(); err != nil || !ok {
 		log.WithFields(log.Fields{"ok": ok, "error": err}).Error("Atomic write to the cluster failed")
@@ -774,12 +838,27 @@ func handlerResponseRuleConfig(w http.ResponseWriter, r *http.Request, ps httpro
 	if rc.Webhooks != nil {
 		cconf.Webhooks = *rc.Webhooks
 	}
+	if rc.WebhookTemplate != nil {
+		cconf.WebhookTemplate = *rc.WebhookTemplate
+	}
+	if rc.WebhookSeverity != nil {
+		cconf.WebhookSeverity = *rc.WebhookSeverity
+	}
+	if rc.WebhookDedupKey != nil {
+		cconf.WebhookDedupKey = *rc.WebhookDedupKey
+	}
+	if rc.QuarExceptions != nil {
+		cconf.QuarExceptions = *rc.QuarExceptions
+	}
 	if rc.Comment != nil {
 		cconf.Comment = *rc.Comment
 	}
 	if rc.Disable != nil {
 		cconf.Disable = *rc.Disable
 	}
+	if rc.ExpireTS != nil {
+		cconf.ExpireAt = *rc.ExpireTS
+	}
 
 	rr := cacher.ResponseRule2REST(cconf)
 	if err := validateResponseRule(rr, acc); err != nil {
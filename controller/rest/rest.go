@@ -37,6 +37,7 @@ import (
 	"github.com/neuvector/neuvector/share/auth"
 	"github.com/neuvector/neuvector/share/cluster"
 	"github.com/neuvector/neuvector/share/global"
+	"github.com/neuvector/neuvector/share/otel"
 	"github.com/neuvector/neuvector/share/utils"
 )
 
@@ -72,6 +73,7 @@ const _maxTransacKeys = 64
 
 var _restPort uint
 var _fedPort uint
+var _apiGRPCPort uint
 var _fedServerChan chan bool
 
 var _licSigKeyEnv int
@@ -91,6 +93,7 @@ const defFedSSLKeyFile = "/etc/neuvector/certs/fed-ssl-cert.key"
 const restErrMessageDefault string = "Unknown error"
 
 const crdEventProcPeriod = time.Duration(time.Second * 10)
+const crdDriftCheckPeriod = time.Duration(time.Minute * 10)
 
 var restErrNeedAgentWorkloadFilter = errors.New("Enforcer or workload filter must be provided")
 var restErrNeedAgentFilter = errors.New("Enforcer filter must be provided")
@@ -100,6 +103,9 @@ var restErrAgentDisconnected error = errors.New("Enforcer is disconnected")
 
 var checkCrdSchemaFunc func(lead, create bool, cspType share.TCspType) []string
 
+// rejected NvSecurityRule/NvClusterSecurityRule/NvWafSecurityRule objects are deleted unless it is enabled by -crd_keep_rejected
+var crdKeepRejected bool
+
 var restErrMessage = []string{
 	api.RESTErrNotFound:              "URL not found",
 	api.RESTErrMethodNotAllowed:      "Method not allowed",
@@ -151,6 +157,10 @@ var restErrMessage = []string{
 	api.RESTErrPromoteFail:           "Failed to promote rules",
 	api.RESTErrPlatformAuthDisabled:  "Platform authentication is disabled",
 	api.RESTErrRancherUnauthorized:   "Rancher authentication failed",
+	api.RESTErrTooManyRequests:       "Too many requests",
+	api.RESTErrMFARequired:           "Multi-factor authentication code is required",
+	api.RESTErrMFAEnrollRequired:     "Multi-factor authentication must be enrolled",
+	api.RESTErrKvUnavailable:         "Cluster store is unavailable, configurations are read-only",
 }
 
 func restRespForward(w http.ResponseWriter, r *http.Request, statusCode int, headers map[string]string, data []byte, remoteExport, remoteRegScanTest bool) {
@@ -983,6 +993,9 @@ func restEventLog(r *http.Request, body []byte, login *loginSession, fields rest
 		}
 	}
 
+	if restAuditEventLog(r, &clog) {
+		return
+	}
 	evqueue.Append(&clog)
 }
 
@@ -1207,6 +1220,13 @@ func (w writer) Write(a []byte) (int, error) {
 	return w.writer.Write(a)
 }
 
+// for streaming responses
+func (w writer) Flush() {
+	if f, ok := w.writer.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 func (w writer) WriteHeader(statusCode int) {
 	url := w.req.URL.String()
 	if statusCode == http.StatusOK {
@@ -1233,6 +1253,53 @@ func (l restLogger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	l.handler.ServeHTTP(writer{r, w}, r)
 }
 
+// Get the route pattern of the request, such as /v1/workload/:id, so the requests of the same API are traced
+// and measured together.
+func restRoute(router *httprouter.Router) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		handle, ps, _ := router.Lookup(r.Method, r.URL.Path)
+		if handle == nil {
+			return "unknown"
+		}
+
+		// A parameter value can also be a static segment of the path, such as /v1/workload/stats/stats, so the
+		// position is confirmed by looking up the path again with a placeholder if there are multiple candidates
+		const placeholder = "\x00"
+		segs := strings.Split(r.URL.Path, "/")
+		start := 1
+		for j, p := range ps {
+			if strings.HasPrefix(p.Value, "/") {
+				// catch-all parameter is always the last one
+				i := len(segs) - strings.Count(p.Value, "/")
+				segs = append(segs[:i], "*"+p.Key)
+				break
+			}
+
+			pos := -1
+			for i := start; i < len(segs); i++ {
+				if segs[i] != p.Value {
+					continue
+				}
+				if pos == -1 {
+					pos = i
+				}
+				probe := append([]string(nil), segs...)
+				probe[i] = placeholder
+				if _, pps, _ := router.Lookup(r.Method, strings.Join(probe, "/")); len(pps) > j && pps[j].Value == placeholder {
+					pos = i
+					break
+				}
+			}
+			if pos == -1 {
+				return "unknown"
+			}
+			segs[pos] = ":" + p.Key
+			start = pos + 1
+		}
+		return strings.Join(segs, "/")
+	}
+}
+
 type Context struct {
 	LocalDev           *common.LocalDevice
 	EvQueue            cluster.ObjectQueueInterface
@@ -1250,6 +1317,12 @@ type Context struct {
 	CspType            share.TCspType
 	CspPauseInterval   uint // in minutes
 	CheckCrdSchemaFunc func(leader, create bool, cspType share.TCspType) []string
+	ThreatSigKeyFile   string // PEM encoded public key to verify threat signature bundles
+	RESTRateLimit      uint   // requests per second allowed for each client. 0 means no limit
+	RESTRateBurst      uint   // max requests allowed in a burst for each client
+	APIGRPCPort        uint   // external gRPC API port, 0 to disable
+	MetricsPort        uint   // Prometheus metrics port, 0 to disable
+	CrdKeepRejected    bool   // keep rejected security rule crd objects and report the error in their status
 }
 
 var cctx *Context
@@ -1270,14 +1343,21 @@ func InitContext(ctx *Context) {
 
 	_restPort = ctx.RESTPort
 	_fedPort = ctx.FedPort
+	_apiGRPCPort = ctx.APIGRPCPort
+	_metricsPort = ctx.MetricsPort
 	_fedServerChan = make(chan bool, 1)
 	crdEventProcTicker = time.NewTicker(crdEventProcPeriod)
 	checkCrdSchemaFunc = ctx.CheckCrdSchemaFunc
+	crdKeepRejected = ctx.CrdKeepRejected
 
 	if ctx.PwdValidUnit < _pwdValidPerDayUnit && ctx.PwdValidUnit > 0 {
 		_pwdValidUnit = time.Duration(ctx.PwdValidUnit)
 	}
 
+	if ctx.RESTRateLimit > 0 {
+		restLimiter = newRestRateLimiter(ctx.RESTRateLimit, ctx.RESTRateBurst)
+	}
+
 	_teleNeuvectorURL = ctx.TeleNeuvectorURL
 	_teleFreq = ctx.TeleFreq
 	if _teleFreq == 0 {
@@ -1311,6 +1391,7 @@ func StartRESTServer() {
 	r.PATCH("/v1/auth", handlerAuthRefresh)
 	r.DELETE("/v1/auth", handlerAuthLogout)
 	r.DELETE("/v1/fed_auth", handlerFedAuthLogout) // Skip API document
+	r.POST("/v1/recovery_auth", handlerRecoveryAuth)
 	r.GET("/v1/eula", handlerEULAShow)
 	r.POST("/v1/eula", handlerEULAConfig)
 	r.GET("/v1/user", handlerUserList)
@@ -1320,6 +1401,9 @@ func StartRESTServer() {
 	r.PATCH("/v1/user/:fullname", handlerUserConfig)
 	r.PATCH("/v1/user/:fullname/role/:role", handlerUserRoleDomainsConfig) // For CLI to modify one role
 	r.POST("/v1/user/:fullname/password", handlerUserPwdConfig)
+	r.POST("/v1/user/:fullname/mfa", handlerUserMFAEnroll)
+	r.POST("/v1/user/:fullname/mfa/verify", handlerUserMFAVerify)
+	r.DELETE("/v1/user/:fullname/mfa", handlerUserMFADelete)
 	r.DELETE("/v1/user/:fullname", handlerUserDelete)
 	r.GET("/v1/password_profile", handlerPwdProfileList)
 	r.GET("/v1/password_profile/:name", handlerPwdProfileShow)
@@ -1340,6 +1424,7 @@ func StartRESTServer() {
 	r.DELETE("/v1/server/:name", handlerServerDelete)
 	r.GET("/v1/file/config", handlerConfigExport)
 	r.POST("/v1/file/config", handlerConfigImport)
+	r.GET("/v1/file/config/crd", handlerCrdConfigExport) // export running config as crd yaml. supported 'groups' query parameter value: comma-separated group names(default: all groups)
 	r.GET("/v1/file/group", handlerGroupCfgExport)
 	r.POST("/v1/file/group", handlerGroupCfgExport)           // as client, GO's http.NewRequest(http.MethodGet) doesn't use body. This API is for multi-cluster purpose.
 	r.GET("/v1/file/group/config", handlerGetGroupCfgImport)  // get current running import task
@@ -1349,8 +1434,10 @@ func StartRESTServer() {
 	r.POST("/v1/file/dlp", handlerDlpExport)                  // supported 'scope' query parameter values: "local"(default).
 	r.POST("/v1/file/dlp/config", handlerDlpImport)           // for providing similar function as crd import but do not rely on crd webhook. besides, it's for replacement
 	r.POST("/v1/file/waf", handlerWafExport)                  // supported 'scope' query parameter values: "local"(default).
+	r.POST("/v1/file/network_policy", handlerNetPolicyExport) // export network rules of groups as kubernetes/cilium/calico network policy
 	r.POST("/v1/file/waf/config", handlerWafImport)           // for providing similar function as crd import but do not rely on crd webhook. besides, it's for replacement
 	r.GET("/v1/internal/system", handlerInternalSystem)       // skip API document
+	r.GET("/v1/internal/system/kv", handlerInternalKvStats)   // skip API document
 	r.GET("/v1/system/usage", handlerSystemUsage)             // skip API document
 	r.GET("/v1/system/summary", handlerSystemSummary)
 	r.GET("/v1/system/config", handlerSystemGetConfig)   // supported 'scope' query parameter values: ""(all, default)/"fed"/"local". no payload
@@ -1361,6 +1448,13 @@ func StartRESTServer() {
 	r.POST("/v1/system/config/webhook", handlerSystemWebhookCreate)
 	r.PATCH("/v1/system/config/webhook/:name", handlerSystemWebhookConfig)  // supported 'scope' query parameter values: "fed"/"local"(default).
 	r.DELETE("/v1/system/config/webhook/:name", handlerSystemWebhookDelete) // supported 'scope' query parameter values: "fed"/"local"(default).
+	r.GET("/v1/system/backup", handlerGetBackupConfig)
+	r.PATCH("/v1/system/backup", handlerBackupConfig)
+	r.POST("/v1/system/backup", handlerBackupNow)
+	r.GET("/v1/system/backup/archive", handlerBackupList)
+	r.POST("/v1/system/backup/restore", handlerBackupRestore)
+	r.GET("/v1/system/kv_encryption", handlerGetKvEncryption)
+	r.POST("/v1/system/kv_encryption/rotate", handlerKvEncryptionRotate)
 	r.POST("/v1/system/request", handlerSystemRequest)
 	r.GET("/v1/system/license", handlerLicenseShow)
 	r.POST("/v1/system/license/update", handlerLicenseUpdate)
@@ -1401,27 +1495,36 @@ func StartRESTServer() {
 	r.GET("/v1/workload/:id/process_history", handlerWorkloadProcessHistory)
 	r.GET("/v1/workload/:id/process_profile", handlerWorkloadProcessProfile)  // Skip API document, debug, possibly used by UI
 	r.GET("/v1/workload/:id/file_profile", handlerWorkloadFileMonitorProfile) // Skip API document, debug, possibly used by UI
+	r.GET("/v1/workload/:id/file_baseline", handlerWorkloadFileBaseline)
+	r.POST("/v1/workload/:id/file_baseline", handlerWorkloadFileBaselineReset)
+	r.POST("/v1/workload/:id/forensic", handlerWorkloadForensic)
 	// r.GET("/v1/workload/:id/logs", handlerWorkloadLogs) // debug
 	r.PATCH("/v1/workload/:id", handlerWorkloadConfig)
 	r.POST("/v1/workload/request/:id", handlerWorkloadRequest)
+	r.POST("/v1/workload/:id/pcap", handlerWorkloadPcap)
 	r.GET("/v1/workload/:id/compliance", handlerContainerCompliance)
-	r.GET("/v1/conversation_endpoint", handlerConverEndpointList)          // Skip API document
-	r.PATCH("/v1/conversation_endpoint/:id", handlerConverEndpointConfig)  // Skip API document
-	r.DELETE("/v1/conversation_endpoint/:id", handlerConverEndpointDelete) // Skip API document
-	r.GET("/v1/conversation", handlerConverList)                           // Skip API document
-	r.GET("/v1/conversation/:from/:to", handlerConverShow)                 // Skip API document
-	r.DELETE("/v1/conversation", handlerConverDeleteAll)                   // Skip API document
-	r.DELETE("/v1/conversation/:from/:to", handlerConverDelete)            // Skip API document
-	r.GET("/v1/group", handlerGroupList)                                   // supported 'scope' query parameter values: ""(all, default)/"fed"/"local". no payload
-	r.GET("/v1/group/:name", handlerGroupShow)                             // no payload
-	r.POST("/v1/group", handlerGroupCreate)                                //
-	r.PATCH("/v1/group/:name", handlerGroupConfig)                         //
-	r.DELETE("/v1/group/:name", handlerGroupDelete)                        // no payload
-	r.GET("/v1/process_profile", handlerProcessProfileList)                // supported 'scope' query parameter values: ""(all, default)/"fed"/"local". no payload
-	r.GET("/v1/process_profile/:name", handlerProcessProfileShow)          //
-	r.PATCH("/v1/process_profile/:name", handlerProcessProfileConfig)      //
-	r.GET("/v1/process_rules/:uuid", handlerProcRuleShow)                  //
-	r.GET("/v1/file_monitor", handlerFileMonitorList)                      // supported 'scope' query parameter values: ""(all, default)/"fed"/"local". no payload
+	r.GET("/v1/conversation_endpoint", handlerConverEndpointList)            // Skip API document
+	r.PATCH("/v1/conversation_endpoint/:id", handlerConverEndpointConfig)    // Skip API document
+	r.DELETE("/v1/conversation_endpoint/:id", handlerConverEndpointDelete)   // Skip API document
+	r.GET("/v1/conversation", handlerConverList)                             // Skip API document
+	r.GET("/v1/conversation/:from/:to", handlerConverShow)                   // Skip API document
+	r.DELETE("/v1/conversation", handlerConverDeleteAll)                     // Skip API document
+	r.DELETE("/v1/conversation/:from/:to", handlerConverDelete)              // Skip API document
+	r.POST("/v1/graphql", handlerGraphQL)                                    // workloads, groups, conversations and vulnerabilities
+	r.GET("/v1/group", handlerGroupList)                                     // supported 'scope' query parameter values: ""(all, default)/"fed"/"local". no payload
+	r.GET("/v1/group/:name", handlerGroupShow)                               // no payload
+	r.POST("/v1/group", handlerGroupCreate)                                  //
+	r.PATCH("/v1/group/:name", handlerGroupConfig)                           //
+	r.PATCH("/v1/groups/batch", handlerGroupBatch)                           // create/update/delete groups in one transaction
+	r.GET("/v1/groups/automode", handlerGroupAutoModeList)                   // groups pending the auto mode promotion
+	r.DELETE("/v1/group/:name", handlerGroupDelete)                          // no payload
+	r.GET("/v1/group/:name/profiles/export", handlerGroupProfilesExport)     // supported 'type' query parameter values: "apparmor"(default)/"selinux"
+	r.GET("/v1/process_profile", handlerProcessProfileList)                  // supported 'scope' query parameter values: ""(all, default)/"fed"/"local". no payload
+	r.GET("/v1/process_profile/:name", handlerProcessProfileShow)            //
+	r.PATCH("/v1/process_profile/:name", handlerProcessProfileConfig)        //
+	r.GET("/v1/process_profile/:name/seccomp", handlerProcessProfileSeccomp) // download
+	r.GET("/v1/process_rules/:uuid", handlerProcRuleShow)                    //
+	r.GET("/v1/file_monitor", handlerFileMonitorList)                        // supported 'scope' query parameter values: ""(all, default)/"fed"/"local". no payload
 	r.GET("/v1/file_monitor/:name", handlerFileMonitorShow)
 	r.PATCH("/v1/file_monitor/:name", handlerFileMonitorConfig)
 	r.GET("/v1/file_monitor_file", handlerFileMonitorFile) // debug
@@ -1435,6 +1538,7 @@ func StartRESTServer() {
 	r.PATCH("/v1/dlp/group/:name", handlerDlpGroupConfig)
 	r.GET("/v1/dlp/rule", handlerDlpRuleList)
 	r.GET("/v1/dlp/rule/:name", handlerDlpRuleShow)
+	r.GET("/v1/dlp/pack", handlerDlpPackList)
 	//r.POST("/v1/dlp/rule", handlerDlpRuleCreate)							  // before uncomment this line, check if access control needs to be adjusted in handlerDlpRuleCreate for required permissions
 	//r.PATCH("/v1/dlp/rule/:name", handlerDlpRuleConfig)					  // before uncomment this line, check if access control needs to be adjusted in handlerDlpRuleConfig for required permissions
 	//r.DELETE("/v1/dlp/rule/:name", handlerDlpRuleDelete)					  // before uncomment this line, check if access control needs to be adjusted in handlerDlpRuleDelete for required permissions
@@ -1448,6 +1552,10 @@ func StartRESTServer() {
 	r.PATCH("/v1/waf/group/:name", handlerWafGroupConfig)
 	r.GET("/v1/waf/rule", handlerWafRuleList)
 	r.GET("/v1/waf/rule/:name", handlerWafRuleShow)
+	r.POST("/v1/waf/modsec", handlerWafModSecConvert) // convert modsecurity rules without saving them
+	r.GET("/v1/threat/signature", handlerThreatSigList)
+	r.POST("/v1/threat/signature/bundle", handlerThreatSigImport) // import a signed bundle, or download it from the url in payload
+	r.PATCH("/v1/threat/signature/:id", handlerThreatSigConfig)
 	r.GET("/v1/policy/rule", handlerPolicyRuleList)                           // supported 'scope' query parameter values: ""(all, default)/"fed"/"local". no payload
 	r.GET("/v1/policy/rule/:id", handlerPolicyRuleShow)                       // no payload
 	r.PATCH("/v1/policy/rule", handlerPolicyRuleAction)                       // supported 'scope' query parameter values: "fed"/"local"(default).
@@ -1455,6 +1563,16 @@ func StartRESTServer() {
 	r.DELETE("/v1/policy/rule/:id", handlerPolicyRuleDelete)                  // no payload
 	r.DELETE("/v1/policy/rule", handlerPolicyRuleDeleteAll)                   // supported 'scope' query parameter values: "fed"/"local"(default). no payload
 	r.POST("/v1/policy/rules/promote", handlerPolicyRulesPromote)             // promote local/crd network policy rules to fed
+	r.PATCH("/v1/policy/rules/batch", handlerPolicyRuleBatch)                 // create/update/delete network rules in one transaction
+	r.GET("/v1/policy/rules/hits", handlerRuleHitList)                        // supported 'type' query parameter values: ""(all, default)/"network"/"process"/"admission"
+	r.GET("/v1/policy/rules/unused", handlerUnusedRuleList)                   // rules not hit in the last 'days' query parameter value(default: 30) and the 'type' query parameter
+	r.GET("/v1/policy/changes", handlerPolicyChangeList)                      // network rule changes submitted for approval
+	r.GET("/v1/policy/changes/:id", handlerPolicyChangeShow)                  // no payload
+	r.POST("/v1/policy/changes", handlerPolicyChangeSubmit)                   // same operations as the batch API
+	r.POST("/v1/policy/changes/:id/approve", handlerPolicyChangeApprove)      // apply the changes. the submitter cannot approve them
+	r.POST("/v1/policy/changes/:id/reject", handlerPolicyChangeReject)        //
+	r.DELETE("/v1/policy/changes/:id", handlerPolicyChangeDelete)             // no payload
+	r.POST("/v1/policy/simulate", handlerPolicySimulate)                      // report the recorded conversations that would be denied in Protect mode
 	r.GET("/v1/response/rule", handlerResponseRuleList)                       // supported 'scope' query parameter values: ""(all, default)/"fed"/"local". no payload
 	r.GET("/v1/response/rule/:id", handlerResponseRuleShow)                   // no payload
 	r.GET("/v1/response/workload_rules/:id", handlerResponseRuleShowWorkload) //
@@ -1493,11 +1611,23 @@ func StartRESTServer() {
 	r.POST("/v1/fed/leave", handlerLeaveFed)                                 // Skip API document, called by manager of joint cluster
 	r.DELETE("/v1/fed/cluster/:id", handlerRemoveJointCluster)               // Skip API document, called by manager of master cluster
 	r.POST("/v1/fed/deploy", handlerDeployFedRules)                          // Skip API document, called by manager of master cluster
+	r.POST("/v1/fed/standby", handlerConfigFedStandby)                       // Skip API document, called by manager of master cluster
+	r.POST("/v1/fed/failover", handlerFedFailover)                           // Skip API document, called by manager of the standby joint cluster
 	r.POST("/v1/fed/ping_internal", handlerPingJointInternal)                // Skip API document, called from master cluster to joint cluster
 	r.POST("/v1/fed/joint_test_internal", handlerTestJointInternal)          // Skip API document, called from master cluster to joint cluster
 	r.POST("/v1/fed/remove_internal", handlerJointKickedInternal)            // Skip API document, called from master cluster to joint cluster
 	r.POST("/v1/fed/command_internal", handlerFedCommandInternal)            // Skip API document, called from master cluster to joint cluster
+	r.POST("/v1/fed/failover_internal", handlerFedFailoverInternal)          // Skip API document, called from the standby cluster to joint cluster
 	r.GET("/v1/fed/view/:id", handlerGetJointClusterView)                    // Skip API document, called by manager of master cluster
+	r.POST("/v1/fed/report_internal", handlerFedReportInternal)              // Skip API document, called from master cluster to joint cluster
+	r.GET("/v1/fed/report", handlerGetFedReport)                             // Skip API document, called by manager of master cluster
+	r.GET("/v1/fed/report/vulnerability", handlerGetFedVulReport)            // Skip API document, called by manager of master cluster
+	r.GET("/v1/fed/report/compliance", handlerGetFedComplianceReport)        // Skip API document, called by manager of master cluster
+	r.GET("/v1/fed/report/trend", handlerGetFedReportTrend)                  // Skip API document, called by manager of master cluster
+	r.GET("/v1/fed/report/conversation", handlerGetFedConversation)          // Skip API document, called by manager of master cluster
+	r.GET("/v1/fed/quarantine", handlerGetFedQuarantineImages)               // Skip API document, called by manager of master cluster
+	r.POST("/v1/fed/quarantine", handlerAddFedQuarantineImage)               // Skip API document, called by manager of master cluster
+	r.DELETE("/v1/fed/quarantine/:digest", handlerDeleteFedQuarantineImage)  // Skip API document, called by manager of master cluster
 	r.GET("/v1/fed/cluster/:id/*request", handlerFedClusterForwardGet)       // Skip API document, called by manager of master cluster
 	r.POST("/v1/fed/cluster/:id/*request", handlerFedClusterForwardPost)     // Skip API document, called by manager of master cluster
 	r.PATCH("/v1/fed/cluster/:id/*request", handlerFedClusterForwardPatch)   // Skip API document, called by manager of master cluster
@@ -1514,6 +1644,21 @@ func StartRESTServer() {
 	r.GET("/v1/log/violation", handlerViolationList)
 	r.GET("/v1/log/violation/workload", handlerViolationWorkloads)
 	r.GET("/v1/log/audit", handlerAuditList)
+	r.GET("/v1/log/audit-config", handlerConfigAuditList)
+	r.GET("/v1/log/export", handlerLogExport)
+	r.GET("/v1/report/attack-matrix", handlerAttackMatrix)
+	r.GET("/v1/report/regulatory", handlerRegulatoryPostureList)
+	r.GET("/v1/report/regulatory/:framework", handlerRegulatoryPostureShow)
+	r.GET("/v1/report/schedule", handlerReportScheduleList)
+	r.GET("/v1/report/schedule/:name", handlerReportScheduleShow)
+	r.POST("/v1/report/schedule", handlerReportScheduleCreate)
+	r.PATCH("/v1/report/schedule/:name", handlerReportScheduleConfig)
+	r.DELETE("/v1/report/schedule/:name", handlerReportScheduleDelete)
+	r.POST("/v1/report/schedule/:name/run", handlerReportRun)
+	r.GET("/v1/report/generated", handlerReportList)
+	r.GET("/v1/report/generated/:id", handlerReportDownload)
+	r.DELETE("/v1/report/generated/:id", handlerReportDelete)
+	r.GET("/v1/stream/log", handlerLogStream) // supported 'types' query parameter value: comma-separated log types(default: all types). server-sent events
 	r.GET("/v1/scan/scanner", handlerScannerList)
 	r.PATCH("/v1/scan/config", handlerScanConfig)
 	r.GET("/v1/scan/config", handlerScanConfigGet)
@@ -1560,6 +1705,7 @@ func StartRESTServer() {
 	r.GET("/v1/bench/host/:id/docker", handlerDockerBench)
 	r.POST("/v1/bench/host/:id/docker", handlerDockerBenchRun)
 	r.GET("/v1/bench/host/:id/kubernetes", handlerKubeBench)
+	r.GET("/v1/bench/host/:id/kubernetes/ckl", handlerKubeBenchCKL)
 	r.POST("/v1/bench/host/:id/kubernetes", handlerKubeBenchRun)
 	r.GET("/v1/custom_check/:group", handlerCustomCheckShow)
 	r.GET("/v1/custom_check", handlerCustomCheckList)
@@ -1569,6 +1715,12 @@ func StartRESTServer() {
 	r.PATCH("/v1/compliance/profile/:name", handlerComplianceProfileConfig)
 	r.PATCH("/v1/compliance/profile/:name/entry/:check", handlerComplianceProfileEntryConfig)
 	r.DELETE("/v1/compliance/profile/:name/entry/:check", handlerComplianceProfileEntryDelete)
+	r.GET("/v1/compliance/acceptance", handlerRiskAcceptanceList)
+	r.GET("/v1/compliance/acceptance/:id", handlerRiskAcceptanceShow)
+	r.POST("/v1/compliance/acceptance", handlerRiskAcceptanceCreate)
+	r.POST("/v1/compliance/acceptance/:id/approve", handlerRiskAcceptanceApprove)
+	r.POST("/v1/compliance/acceptance/:id/reject", handlerRiskAcceptanceReject)
+	r.DELETE("/v1/compliance/acceptance/:id", handlerRiskAcceptanceDelete)
 
 	// vulnerability management
 	r.GET("/v1/vulnerability/profile", handlerVulnerabilityProfileList) // Only default is accepted, so not POST/DELETE
@@ -1577,6 +1729,12 @@ func StartRESTServer() {
 	r.POST("/v1/vulnerability/profile/:name/entry", handlerVulnerabilityProfileEntryCreate)
 	r.PATCH("/v1/vulnerability/profile/:name/entry/:id", handlerVulnerabilityProfileEntryConfig)
 	r.DELETE("/v1/vulnerability/profile/:name/entry/:id", handlerVulnerabilityProfileEntryDelete)
+	r.GET("/v1/vulnerability/acceptance", handlerRiskAcceptanceList)
+	r.GET("/v1/vulnerability/acceptance/:id", handlerRiskAcceptanceShow)
+	r.POST("/v1/vulnerability/acceptance", handlerRiskAcceptanceCreate)
+	r.POST("/v1/vulnerability/acceptance/:id/approve", handlerRiskAcceptanceApprove)
+	r.POST("/v1/vulnerability/acceptance/:id/reject", handlerRiskAcceptanceReject)
+	r.DELETE("/v1/vulnerability/acceptance/:id", handlerRiskAcceptanceDelete)
 
 	r.GET("/v1/sniffer", handlerSnifferList)
 	r.GET("/v1/sniffer/:id", handlerSnifferShow)
@@ -1600,6 +1758,7 @@ func StartRESTServer() {
 	r.GET("/v1/debug/dlp/rule", handlerDebugDlpRuleList)                       // debug
 	r.GET("/v1/debug/dlp/mac", handlerDebugDlpRuleMac)                         // debug
 	r.GET("/v1/debug/system/stats", handlerDebugSystemStats)                   // debug
+	r.GET("/v1/debug/rate_limit", handlerDebugRateLimit)                       // debug
 	r.POST("/v1/debug/controller/sync/:id", handlerDebugControllerSyncRequest) // debug
 	r.GET("/v1/debug/controller/sync", handlerDebugControllerSyncInfo)         // debug
 	r.GET("/v1/debug/workload/intercept", handlerDebugWorkloadIntcp)           // debug
@@ -1645,9 +1804,20 @@ func StartRESTServer() {
 		PreferServerCipherSuites: true,
 		CipherSuites:             utils.GetSupportedTLSCipherSuites(),
 	}
+	var handler http.Handler = kvDegradedHandler{handler: configAuditHandler{handler: r, router: r}}
+	if restLimiter != nil {
+		handler = rateLimitHandler{handler: handler, limiter: restLimiter}
+	}
+	handler = otel.HTTPHandler(handler, restRoute(r))
+	if _apiGRPCPort > 0 {
+		go startAPIGRPCServer(_apiGRPCPort, restLogger{handler})
+	}
+	if _metricsPort > 0 {
+		go startMetricsServer(_metricsPort)
+	}
 	server := &http.Server{
 		Addr:      addr,
-		Handler:   restLogger{r},
+		Handler:   restLogger{handler},
 		TLSConfig: config,
 		// ReadTimeout:  time.Duration(5) * time.Second,
 		// WriteTimeout: time.Duration(35) * time.Second,
@@ -1751,6 +1921,8 @@ Loop:
 		case <-_fedPingTimer.C:
 			if leader := atomic.LoadUint32(&_isLeader); leader == 1 {
 				go pingJointClusters()
+				go collectFedReports()
+				go applyPendingFedQuarantineImages()
 				_fedPingTimer.Reset(time.Minute * time.Duration(atomic.LoadUint32(&_fedPingInterval)))
 			} else {
 				_fedPingTimer.Stop()
@@ -630,3 +630,28 @@ func TestNewestVersion(t *testing.T) {
 		t.Errorf("Incorrect newest version: %v %s", vers, getNewestVersion(vers))
 	}
 }
+
+func TestRestRoute(t *testing.T) {
+	router := httprouter.New()
+	h := func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {}
+	router.GET("/v1/workload/:id", h)
+	router.GET("/v1/workload/:id/stats", h)
+	router.GET("/v1/host/:id/process_profile/:name", h)
+	router.GET("/v1/file/config/*path", h)
+	route := restRoute(router)
+
+	cases := map[string]string{
+		"/v1/workload/abc":                   "/v1/workload/:id",
+		"/v1/workload/stats/stats":           "/v1/workload/:id/stats",
+		"/v1/host/h1/process_profile/h1":     "/v1/host/:id/process_profile/:name",
+		"/v1/file/config/a/b":                "/v1/file/config/*path",
+		"/v1/workload":                       "unknown",
+		"/v1/host/workload/process_profile/": "unknown",
+	}
+	for path, expect := range cases {
+		r, _ := http.NewRequest(http.MethodGet, path, nil)
+		if got := route(r); got != expect {
+			t.Errorf("Unexpected route: path=%s expect=%s got=%s", path, expect, got)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+	scanUtils "github.com/neuvector/neuvector/share/scan"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// The accepted risks of vulnerabilities and compliance check failures. A user proposes an acceptance with the
+// justification and expiry, then another user with the risk approval permission approves or rejects it.
+
+const riskAcceptanceMaxExpireDays = 365
+
+// The acceptances of both types share the handlers, the type is decided by the URL
+func riskAcceptanceType(r *http.Request) string {
+	if strings.HasPrefix(r.URL.Path, "/v1/compliance/") {
+		return share.RiskAcceptanceCompliance
+	}
+	return share.RiskAcceptanceVulnerability
+}
+
+func riskAcceptance2REST(ra *share.CLUSRiskAcceptance, now time.Time) *api.RESTRiskAcceptance {
+	rra := &api.RESTRiskAcceptance{
+		ID:            ra.ID,
+		Type:          ra.Type,
+		Name:          ra.Name,
+		Domains:       ra.Domains,
+		Images:        ra.Images,
+		Justification: ra.Justification,
+		ExpireAt:      ra.ExpireAt,
+		Status:        ra.Status,
+		ProposedBy:    ra.ProposedBy,
+		ProposedAt:    ra.ProposedAt,
+		ReviewedBy:    ra.ReviewedBy,
+		ReviewedAt:    ra.ReviewedAt,
+		ReviewComment: ra.ReviewComment,
+	}
+	if rra.Domains == nil {
+		rra.Domains = make([]string, 0)
+	}
+	if rra.Images == nil {
+		rra.Images = make([]string, 0)
+	}
+	if ra.Status != share.RiskAcceptanceRejected && !now.Before(ra.ExpireAt) {
+		rra.Status = api.RiskAcceptanceExpired
+	}
+	return rra
+}
+
+func parseRiskAcceptance(riskType string, rc *api.RESTRiskAcceptanceConfig, now time.Time) (*share.CLUSRiskAcceptance, error) {
+	if rc.Name = strings.TrimSpace(rc.Name); rc.Name == "" {
+		return nil, errors.New("Empty name")
+	}
+	if strings.TrimSpace(rc.Justification) == "" {
+		return nil, errors.New("Justification is required")
+	}
+	if rc.ExpireDays == 0 || rc.ExpireDays > riskAcceptanceMaxExpireDays {
+		return nil, fmt.Errorf("Expiry must be between 1 and %d days", riskAcceptanceMaxExpireDays)
+	}
+
+	switch riskType {
+	case share.RiskAcceptanceVulnerability:
+		if rc.Name == api.VulnerabilityNameRecent || rc.Name == api.VulnerabilityNameRecentWithoutFix {
+			return nil, fmt.Errorf("Unsupported vulnerability name %s", rc.Name)
+		}
+	case share.RiskAcceptanceCompliance:
+		_, metaMap := scanUtils.GetComplianceMeta()
+		if _, ok := metaMap[rc.Name]; !ok {
+			return nil, fmt.Errorf("Unknown compliance check %s", rc.Name)
+		}
+		if len(rc.Images) > 0 {
+			return nil, errors.New("Images are not supported by compliance checks")
+		}
+	}
+
+	id, err := utils.GetGuid()
+	if err != nil {
+		return nil, err
+	}
+	return &share.CLUSRiskAcceptance{
+		ID:            id,
+		Type:          riskType,
+		Name:          rc.Name,
+		Domains:       rc.Domains,
+		Images:        rc.Images,
+		Justification: rc.Justification,
+		ExpireAt:      now.Add(time.Duration(rc.ExpireDays) * 24 * time.Hour),
+		Status:        share.RiskAcceptanceProposed,
+		ProposedAt:    now,
+	}, nil
+}
+
+// The acceptance must be reviewed by a user other than the proposer before it expires
+func reviewRiskAcceptance(ra *share.CLUSRiskAcceptance, reviewer string, approve bool, comment string, now time.Time) error {
+	if ra.Status != share.RiskAcceptanceProposed {
+		return fmt.Errorf("Acceptance is already %s", ra.Status)
+	}
+	if !now.Before(ra.ExpireAt) {
+		return errors.New("Acceptance is expired")
+	}
+	if reviewer == ra.ProposedBy {
+		return errors.New("Acceptance cannot be reviewed by the proposer")
+	}
+
+	if approve {
+		ra.Status = share.RiskAcceptanceApproved
+	} else {
+		ra.Status = share.RiskAcceptanceRejected
+	}
+	ra.ReviewedBy = reviewer
+	ra.ReviewedAt = now
+	ra.ReviewComment = comment
+	return nil
+}
+
+func handlerRiskAcceptanceList(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	now := time.Now()
+	list := clusHelper.GetAllRiskAcceptances(riskAcceptanceType(r))
+	resp := api.RESTRiskAcceptancesData{Acceptances: make([]*api.RESTRiskAcceptance, 0, len(list))}
+	for _, ra := range list {
+		if acc.Authorize(ra, nil) {
+			resp.Acceptances = append(resp.Acceptances, riskAcceptance2REST(ra, now))
+		}
+	}
+	sort.Slice(resp.Acceptances, func(i, j int) bool {
+		return resp.Acceptances[i].ProposedAt.After(resp.Acceptances[j].ProposedAt)
+	})
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get accepted risk list")
+}
+
+func handlerRiskAcceptanceShow(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	ra, _, err := clusHelper.GetRiskAcceptanceRev(riskAcceptanceType(r), ps.ByName("id"))
+	if err != nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	} else if !acc.Authorize(ra, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	resp := api.RESTRiskAcceptanceData{Acceptance: riskAcceptance2REST(ra, time.Now())}
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get accepted risk")
+}
+
+func handlerRiskAcceptanceCreate(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSRiskAcceptance{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	var rconf api.RESTRiskAcceptanceConfigData
+	body, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(body, &rconf); err != nil || rconf.Config == nil {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	ra, err := parseRiskAcceptance(riskAcceptanceType(r), rconf.Config, time.Now().UTC())
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Invalid accepted risk")
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+		return
+	}
+	ra.ProposedBy = login.fullname
+	if err := clusHelper.PutRiskAcceptanceRev(ra, 0); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error()
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+
+	msg := fmt.Sprintf("Acceptance of %s risk %s is proposed", ra.Type, ra.Name)
+	configLog(share.CLUSEvRiskAcceptanceProposed, login, msg)
+
+	resp := api.RESTRiskAcceptanceData{Acceptance: riskAcceptance2REST(ra, time.Now())}
+	restRespSuccess(w, r, &resp, acc, login, &rconf, msg)
+}
+
+func handlerRiskAcceptanceDelete(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	riskType := riskAcceptanceType(r)
+	id := ps.ByName("id")
+	ra, _, err := clusHelper.GetRiskAcceptanceRev(riskType, id)
+	if err != nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	} else if !acc.Authorize(ra, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+	if err := clusHelper.DeleteRiskAcceptance(riskType, id); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error()
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+
+	restRespSuccess(w, r, nil, acc, login, nil, fmt.Sprintf("Delete acceptance of %s risk %s", ra.Type, ra.Name))
+}
+
+func riskAcceptanceReview(w http.ResponseWriter, r *http.Request, ps httprouter.Params, approve bool) {
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	var rconf api.RESTRiskAcceptanceReviewData
+	body, _ := ioutil.ReadAll(r.Body)
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &rconf); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Request error")
+			restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+			return
+		}
+	}
+	var comment string
+	if rconf.Review != nil {
+		comment = rconf.Review.Comment
+	}
+
+	riskType := riskAcceptanceType(r)
+	id := ps.ByName("id")
+
+	var ra *share.CLUSRiskAcceptance
+	retry := 0
+	for retry < retryClusterMax {
+		var rev uint64
+		var err error
+		ra, rev, err = clusHelper.GetRiskAcceptanceRev(riskType, id)
+		if err != nil {
+			restRespNotFoundLogAccessDenied(w, login, err)
+			return
+		} else if !acc.Authorize(ra, nil) {
+			restRespAccessDenied(w, login)
+			return
+		}
+
+		if err := reviewRiskAcceptance(ra, login.fullname, approve, comment, time.Now().UTC()); err != nil {
+			log.WithFields(log.Fields{"id": id, "error": err}).Error("Failed to review accepted risk")
+			restRespErrorMessage(w, http.StatusForbidden, api.RESTErrOpNotAllowed, err.Error())
+			return
+		}
+
+		if err := clusHelper.PutRiskAcceptanceRev(ra, rev); err != nil {
+			log.WithFields(log.Fields{"id": id, "error": err, "rev": rev}).Error()
+			retry++
+		} else {
+			break
+		}
+	}
+	if retry >= retryClusterMax {
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+
+	var msg string
+	if approve {
+		msg = fmt.Sprintf("Acceptance of %s risk %s is approved", ra.Type, ra.Name)
+		configLog(share.CLUSEvRiskAcceptanceApproved, login, msg)
+	} else {
+		msg = fmt.Sprintf("Acceptance of %s risk %s is rejected", ra.Type, ra.Name)
+		configLog(share.CLUSEvRiskAcceptanceRejected, login, msg)
+	}
+
+	restRespSuccess(w, r, nil, acc, login, &rconf, msg)
+}
+
+func handlerRiskAcceptanceApprove(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	riskAcceptanceReview(w, r, ps, true)
+}
+
+func handlerRiskAcceptanceReject(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
+	defer r.Body.Close()
+
+	riskAcceptanceReview(w, r, ps, false)
+}
@@ -0,0 +1,128 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestParseRiskAcceptance(t *testing.T) {
+	now := time.Now().UTC()
+	ra, err := parseRiskAcceptance(share.RiskAcceptanceCompliance, &api.RESTRiskAcceptanceConfig{
+		Name: " K.1.1.1 ", Domains: []string{"ns1"}, Justification: "Managed by the cloud provider", ExpireDays: 30,
+	}, now)
+	if err != nil {
+		t.Fatalf("Failed to parse acceptance: %s", err)
+	}
+	if ra.Name != "K.1.1.1" || ra.Status != share.RiskAcceptanceProposed || !ra.ExpireAt.Equal(now.Add(30*24*time.Hour)) || ra.ID == "" {
+		t.Errorf("Unexpected acceptance: %+v", ra)
+	}
+
+	for _, c := range []struct {
+		riskType string
+		rc       *api.RESTRiskAcceptanceConfig
+	}{
+		{share.RiskAcceptanceVulnerability, &api.RESTRiskAcceptanceConfig{Name: "", Justification: "j", ExpireDays: 1}},
+		{share.RiskAcceptanceVulnerability, &api.RESTRiskAcceptanceConfig{Name: "CVE-2023-1234", ExpireDays: 1}},
+		{share.RiskAcceptanceVulnerability, &api.RESTRiskAcceptanceConfig{Name: "CVE-2023-1234", Justification: "j"}},
+		{share.RiskAcceptanceVulnerability, &api.RESTRiskAcceptanceConfig{Name: "CVE-2023-1234", Justification: "j", ExpireDays: 366}},
+		{share.RiskAcceptanceVulnerability, &api.RESTRiskAcceptanceConfig{Name: api.VulnerabilityNameRecent, Justification: "j", ExpireDays: 1}},
+		{share.RiskAcceptanceCompliance, &api.RESTRiskAcceptanceConfig{Name: "X.1.1", Justification: "j", ExpireDays: 1}},
+		{share.RiskAcceptanceCompliance, &api.RESTRiskAcceptanceConfig{Name: "K.1.1.1", Justification: "j", ExpireDays: 1, Images: []string{"nginx"}}},
+	} {
+		if _, err := parseRiskAcceptance(c.riskType, c.rc, now); err == nil {
+			t.Errorf("Acceptance should be invalid: %+v", c.rc)
+		}
+	}
+
+	ra.ProposedBy = "alice"
+	if err := reviewRiskAcceptance(ra, "alice", true, "", now); err == nil {
+		t.Errorf("Proposer should not review the acceptance")
+	}
+	if err := reviewRiskAcceptance(ra, "bob", true, "", now.Add(31*24*time.Hour)); err == nil {
+		t.Errorf("Expired acceptance should not be reviewed")
+	}
+	if err := reviewRiskAcceptance(ra, "bob", true, "ok", now); err != nil || ra.Status != share.RiskAcceptanceApproved || ra.ReviewedBy != "bob" {
+		t.Errorf("Failed to approve acceptance: %v, %+v", err, ra)
+	}
+	if err := reviewRiskAcceptance(ra, "carol", false, "", now); err == nil {
+		t.Errorf("Approved acceptance should not be reviewed again")
+	}
+	if rra := riskAcceptance2REST(ra, now.Add(31*24*time.Hour)); rra.Status != api.RiskAcceptanceExpired {
+		t.Errorf("Unexpected status: %s", rra.Status)
+	}
+}
+
+func TestRiskAcceptanceWorkflow(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+	cacher = &mockCache{}
+
+	data := api.RESTRiskAcceptanceConfigData{Config: &api.RESTRiskAcceptanceConfig{
+		Name: "CVE-2023-1234", Images: []string{"nginx:*"}, Justification: "Not exploitable", ExpireDays: 7,
+	}}
+	body, _ := json.Marshal(data)
+	w := restCall("POST", "/v1/vulnerability/acceptance", body, api.UserRoleReader)
+	if w.status != http.StatusForbidden {
+		t.Errorf("Reader should not propose acceptance: status=%v", w.status)
+	}
+	w = restCall("POST", "/v1/vulnerability/acceptance", body, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to propose acceptance: status=%v", w.status)
+	}
+	var resp api.RESTRiskAcceptanceData
+	json.Unmarshal(w.body, &resp)
+	id := resp.Acceptance.ID
+	if resp.Acceptance.Status != share.RiskAcceptanceProposed || resp.Acceptance.ProposedBy != "admin" {
+		t.Errorf("Unexpected acceptance: %+v", resp.Acceptance)
+	}
+
+	// not found in the other type
+	w = restCall("GET", "/v1/compliance/acceptance/"+id, nil, api.UserRoleAdmin)
+	if w.status != http.StatusNotFound {
+		t.Errorf("Acceptance should not be found: status=%v", w.status)
+	}
+
+	// the proposer cannot approve it
+	w = restCall("POST", "/v1/vulnerability/acceptance/"+id+"/approve", nil, api.UserRoleAdmin)
+	if w.status != http.StatusForbidden {
+		t.Errorf("Proposer should not approve acceptance: status=%v", w.status)
+	}
+
+	ra, _, _ := clusHelper.GetRiskAcceptanceRev(share.RiskAcceptanceVulnerability, id)
+	ra.ProposedBy = "alice"
+	clusHelper.PutRiskAcceptanceRev(ra, 0)
+
+	review, _ := json.Marshal(api.RESTRiskAcceptanceReviewData{Review: &api.RESTRiskAcceptanceReview{Comment: "Fix is planned"}})
+	w = restCall("POST", "/v1/vulnerability/acceptance/"+id+"/approve", review, api.UserRoleReader)
+	if w.status != http.StatusForbidden {
+		t.Errorf("Reader should not approve acceptance: status=%v", w.status)
+	}
+	w = restCall("POST", "/v1/vulnerability/acceptance/"+id+"/approve", review, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to approve acceptance: status=%v", w.status)
+	}
+
+	w = restCall("GET", "/v1/vulnerability/acceptance", nil, api.UserRoleReader)
+	var list api.RESTRiskAcceptancesData
+	json.Unmarshal(w.body, &list)
+	if w.status != http.StatusOK || len(list.Acceptances) != 1 || list.Acceptances[0].Status != share.RiskAcceptanceApproved ||
+		list.Acceptances[0].ReviewComment != "Fix is planned" {
+		t.Errorf("Unexpected acceptances: status=%v, %+v", w.status, list.Acceptances)
+	}
+
+	w = restCall("DELETE", "/v1/vulnerability/acceptance/"+id, nil, api.UserRoleAdmin)
+	if w.status != http.StatusOK || len(clusHelper.GetAllRiskAcceptances(share.RiskAcceptanceVulnerability)) != 0 {
+		t.Errorf("Failed to delete acceptance: status=%v", w.status)
+	}
+
+	postTest()
+}
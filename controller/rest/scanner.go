@@ -2,9 +2,11 @@ package rest
 
 import (
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"sort"
+	"strings"
 
 	"github.com/julienschmidt/httprouter"
 	log "github.com/sirupsen/logrus"
@@ -35,6 +37,20 @@ func handlerScannerList(w http.ResponseWriter, r *http.Request, ps httprouter.Pa
 	restRespSuccess(w, r, &resp, acc, login, nil, "Get scanner list")
 }
 
+func restRegistryMirrors2Clus(mirrors []*api.RESTRegistryMirror) ([]*share.CLUSRegistryMirror, error) {
+	list := make([]*share.CLUSRegistryMirror, 0, len(mirrors))
+	for _, m := range mirrors {
+		if m == nil || strings.TrimSpace(m.Mirror) == "" || strings.TrimSpace(m.Upstream) == "" {
+			return nil, errors.New("Registry mirror and upstream cannot be empty")
+		}
+		list = append(list, &share.CLUSRegistryMirror{
+			Mirror:   strings.TrimSpace(m.Mirror),
+			Upstream: strings.TrimSpace(m.Upstream),
+		})
+	}
+	return list, nil
+}
+
 func handlerScanConfig(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
 	defer r.Body.Close()
@@ -66,6 +82,17 @@ func handlerScanConfig(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 		return
 	}
 
+	// Registry mirrors are kept if not specified
+	if sconf.Config.RegistryMirrors == nil {
+		if cfg, _ := clusHelper.GetScanConfigRev(acc); cfg != nil {
+			cconf.RegistryMirrors = cfg.RegistryMirrors
+		}
+	} else if cconf.RegistryMirrors, err = restRegistryMirrors2Clus(sconf.Config.RegistryMirrors); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Invalid registry mirror")
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+		return
+	}
+
 	value, _ := json.Marshal(cconf)
 	if err := cluster.Put(share.CLUSConfigScanKey, value); err != nil {
 		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
@@ -653,18 +680,8 @@ func getAllVulnerabilities(acc *access.AccessControl) (map[string]*vulAsset, *ap
 	return all, &resp
 }
 
-func handlerAssetVulnerability(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
-	defer r.Body.Close()
-
-	acc, login := getAccessControl(w, r, "")
-	if acc == nil {
-		return
-	} else if !acc.HasRequiredPermissions() {
-		restRespAccessDenied(w, login)
-		return
-	}
-
+// The vulnerabilities with score of the workloads, nodes and images that the caller can access
+func getVulnerabilityAssets(acc *access.AccessControl) *api.RESTVulnerabilityAssetData {
 	all, resp := getAllVulnerabilities(acc)
 
 	list := make([]*api.RESTVulnerabilityAsset, 0, len(all))
@@ -702,6 +719,22 @@ func handlerAssetVulnerability(w http.ResponseWriter, r *http.Request, ps httpro
 	})
 
 	resp.Vuls = list
+	return resp
+}
+
+func handlerAssetVulnerability(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.HasRequiredPermissions() {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	resp := getVulnerabilityAssets(acc)
 
 	// remove id from RESTIDName to reduce data size.
 	for _, wls := range resp.Workloads {
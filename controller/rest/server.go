@@ -29,7 +29,7 @@ import (
 const DefaultLDAPServerPort uint16 = 389
 
 func isReservedServerName(name string) bool {
-	return name == api.AuthServerLocal || name == api.AuthServerPlatform
+	return name == api.AuthServerLocal || name == api.AuthServerPlatform || name == api.AuthServerRancher
 }
 
 func allowedServerCat() utils.Set {
@@ -122,6 +122,8 @@ func server2REST(cs *share.CLUSServer) *api.RESTServer {
 			UserInfoURL:      cs.OIDC.UserInfoURL,
 			Scopes:           cs.OIDC.Scopes,
 			GroupClaim:       cs.OIDC.GroupClaim,
+			UsePKCE:          cs.OIDC.UsePKCE,
+			RefreshLogin:     cs.OIDC.RefreshLogin,
 			Enable:           cs.Enable,
 			DefaultRole:      cs.OIDC.DefaultRole,
 			GroupMappedRoles: cs.OIDC.GroupMappedRoles,
@@ -774,7 +776,8 @@ func validateOIDCServer(cs *share.CLUSServer) error {
 		}
 	}
 
-	if len(coidc.Issuer) == 0 || len(coidc.ClientID) == 0 || len(coidc.ClientSecret) == 0 {
+	// Client secret is not required for public clients using PKCE
+	if len(coidc.Issuer) == 0 || len(coidc.ClientID) == 0 || (len(coidc.ClientSecret) == 0 && !coidc.UsePKCE) {
 		return errors.New("Parameters are missing in OpenID Connect settings")
 	}
 	if _, err := url.Parse(coidc.Issuer); err != nil {
@@ -889,6 +892,12 @@ func updateOIDCServer(cs *share.CLUSServer, oidc *api.RESTServerOIDCConfig, acc
 	if oidc.GroupClaim != nil {
 		coidc.GroupClaim = *oidc.GroupClaim
 	}
+	if oidc.UsePKCE != nil {
+		coidc.UsePKCE = *oidc.UsePKCE
+	}
+	if oidc.RefreshLogin != nil {
+		coidc.RefreshLogin = *oidc.RefreshLogin
+	}
 
 	return nil
 }
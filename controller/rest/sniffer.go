@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/textproto"
 	"sort"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	log "github.com/sirupsen/logrus"
@@ -25,6 +26,10 @@ const defaultPcapFileSizeInMB = 2 // 2MB
 const defaultPcapFileNumber = 5   // 10MB
 const maxPcapFileNumber = 50      // 100MB
 
+const defaultPcapCaptureDuration = 30 // seconds
+const maxPcapCaptureDuration = 300    // seconds
+const pcapCaptureStopGrace = 10       // seconds to wait for sniffer to stop after duration
+
 func parseSnifferStatus(status share.SnifferStatus) string {
 	switch status {
 	case share.SnifferStatus_Running:
@@ -218,13 +223,27 @@ func handlerSnifferStart(w http.ResponseWriter, r *http.Request, ps httprouter.P
 		return
 	}
 
-	args := proc.Sniffer
+	id, ok := startWorkloadSniffer(w, agentID, wlID, proc.Sniffer)
+	if !ok {
+		return
+	}
+
+	resp := api.RESTSnifferResultData{
+		Result: &api.RESTSnifferResult{
+			ID: id,
+		},
+	}
+	restRespSuccess(w, r, &resp, acc, login, &proc, "Start sniffer")
+}
+
+// Start a sniffer on the agent, error response is written if it fails
+func startWorkloadSniffer(w http.ResponseWriter, agentID, wlID string, args *api.RESTSnifferArgs) (string, bool) {
 	req := &share.CLUSSnifferRequest{WorkloadID: wlID, Cmd: share.SnifferCmd_StartSniffer}
 	if args.FileNumber != nil {
 		if *args.FileNumber > maxPcapFileNumber {
 			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrNotEnoughFilter,
 				fmt.Sprintf("Maximum pcap file count is %v", maxPcapFileNumber))
-			return
+			return "", false
 		}
 
 		req.FileNumber = *args.FileNumber
@@ -255,15 +274,9 @@ func handlerSnifferStart(w http.ResponseWriter, r *http.Request, ps httprouter.P
 			log.WithFields(log.Fields{"error": grpc.ErrorDesc(err)}).Error("Failed to start sniffer")
 			restRespError(w, http.StatusInternalServerError, api.RESTErrClusterRPCError)
 		}
-		return
+		return "", false
 	}
-
-	resp := api.RESTSnifferResultData{
-		Result: &api.RESTSnifferResult{
-			ID: res.ID,
-		},
-	}
-	restRespSuccess(w, r, &resp, acc, login, &proc, "Start sniffer")
+	return res.ID, true
 }
 
 func handlerSnifferStop(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
@@ -394,6 +407,10 @@ func handlerSnifferGetFile(w http.ResponseWriter, r *http.Request, ps httprouter
 		return
 	}
 
+	writePcapFile(w, id, pcap)
+}
+
+func writePcapFile(w http.ResponseWriter, id string, pcap []byte) {
 	mpw := multipart.NewWriter(w)
 	defer mpw.Close()
 
@@ -409,3 +426,120 @@ func handlerSnifferGetFile(w http.ResponseWriter, r *http.Request, ps httprouter
 		cfgw.Write(pcap)
 	}
 }
+
+// Capture the workload traffic for a bounded duration and return the pcap file.
+// The sniffer session is removed from the enforcer when the request completes.
+func handlerWorkloadPcap(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.HasRequiredPermissions() {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	wlID := ps.ByName("id")
+
+	var proc api.RESTSnifferArgsData
+	body, _ := ioutil.ReadAll(r.Body)
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &proc); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Request error")
+			restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+			return
+		}
+	}
+	if proc.Sniffer == nil {
+		proc.Sniffer = &api.RESTSnifferArgs{}
+	}
+
+	args := proc.Sniffer
+	if args.Duration == nil || *args.Duration == 0 {
+		duration := uint32(defaultPcapCaptureDuration)
+		args.Duration = &duration
+	} else if *args.Duration > maxPcapCaptureDuration {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest,
+			fmt.Sprintf("Maximum capture duration is %v seconds", maxPcapCaptureDuration))
+		return
+	}
+
+	filters := []restFieldFilter{{tag: api.FilterByWorkload, op: api.OPeq, value: wlID}}
+	agentID, _, err := getAgentWorkloadFromFilter(filters, acc)
+	if err != nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+
+	wl, err := cacher.GetWorkloadBrief(wlID, "", acc)
+	if wl == nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	} else if !acc.Authorize(&share.CLUSSnifferDummy{WorkloadDomain: wl.Domain}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	if !wl.CapSniff {
+		err := "Unable to capture the container traffic"
+		log.WithFields(log.Fields{"id": wlID}).Error(err)
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err)
+		return
+	}
+
+	id, ok := startWorkloadSniffer(w, agentID, wlID, args)
+	if !ok {
+		return
+	}
+	defer func() {
+		req := &share.CLUSSnifferRequest{ID: id, Cmd: share.SnifferCmd_RemoveSniffer}
+		if _, err := rpc.SnifferCmd(agentID, req); err != nil {
+			log.WithFields(log.Fields{"id": id, "error": grpc.ErrorDesc(err)}).Error("Failed to remove sniffer")
+		}
+	}()
+
+	if !waitSnifferStop(r, agentID, id, time.Duration(*args.Duration+pcapCaptureStopGrace)*time.Second) {
+		// client is gone or sniffer doesn't stop in time, stop it and return what has been captured
+		req := &share.CLUSSnifferRequest{ID: id, Cmd: share.SnifferCmd_StopSniffer}
+		rpc.SnifferCmd(agentID, req)
+		if r.Context().Err() != nil {
+			log.WithFields(log.Fields{"id": id}).Debug("Request canceled")
+			return
+		}
+	}
+
+	limit := defaultPcapFileSizeInMB * 1024 * 1024 * maxPcapFileNumber
+	pcap, err := rpc.GetSnifferPcap(agentID, id, limit)
+	if err != nil {
+		log.WithFields(log.Fields{"error": grpc.ErrorDesc(err)}).Error("Failed to download pcap file")
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrAgentError, "Failed to download pcap file")
+		return
+	}
+
+	log.WithFields(log.Fields{"id": id, "workload": wlID, "size": len(pcap)}).Debug("Workload capture done")
+	writePcapFile(w, id, pcap)
+}
+
+// Return true if the sniffer stops by itself before timeout
+func waitSnifferStop(r *http.Request, agentID, id string, timeout time.Duration) bool {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+
+	f := &share.CLUSSnifferFilter{ID: id}
+	for {
+		select {
+		case <-r.Context().Done():
+			return false
+		case <-deadline:
+			return false
+		case <-ticker.C:
+			if status, err := rpc.GetSniffers(agentID, f); err == nil && len(status) == 1 &&
+				status[0].Status != share.SnifferStatus_Running {
+				return true
+			}
+		}
+	}
+}
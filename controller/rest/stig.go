@@ -0,0 +1,191 @@
+package rest
+
+import (
+	"encoding/xml"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+// The STIG Viewer checklist (CKL) of the STIG benchmark report of a node
+
+const (
+	cklStatusOpen            = "Open"
+	cklStatusNotAFinding     = "NotAFinding"
+	cklStatusNotReviewed     = "Not_Reviewed"
+	cklDefaultClassification = "UNCLASSIFIED"
+)
+
+type cklChecklist struct {
+	XMLName xml.Name  `xml:"CHECKLIST"`
+	Asset   cklAsset  `xml:"ASSET"`
+	Stigs   []cklStig `xml:"STIGS>iSTIG"`
+}
+
+type cklAsset struct {
+	Role          string `xml:"ROLE"`
+	AssetType     string `xml:"ASSET_TYPE"`
+	HostName      string `xml:"HOST_NAME"`
+	HostIP        string `xml:"HOST_IP"`
+	HostMAC       string `xml:"HOST_MAC"`
+	HostFQDN      string `xml:"HOST_FQDN"`
+	TargetComment string `xml:"TARGET_COMMENT"`
+	TechArea      string `xml:"TECH_AREA"`
+	TargetKey     string `xml:"TARGET_KEY"`
+	WebOrDatabase bool   `xml:"WEB_OR_DATABASE"`
+	WebDBSite     string `xml:"WEB_DB_SITE"`
+	WebDBInstance string `xml:"WEB_DB_INSTANCE"`
+}
+
+type cklStig struct {
+	Info  []cklSIData `xml:"STIG_INFO>SI_DATA"`
+	Vulns []cklVuln   `xml:"VULN"`
+}
+
+type cklSIData struct {
+	Name string `xml:"SID_NAME"`
+	Data string `xml:"SID_DATA"`
+}
+
+type cklVuln struct {
+	Data           []cklStigData `xml:"STIG_DATA"`
+	Status         string        `xml:"STATUS"`
+	FindingDetails string        `xml:"FINDING_DETAILS"`
+	Comments       string        `xml:"COMMENTS"`
+	SeverityOver   string        `xml:"SEVERITY_OVERRIDE"`
+	SeverityJust   string        `xml:"SEVERITY_JUSTIFICATION"`
+}
+
+type cklStigData struct {
+	Attribute string `xml:"VULN_ATTRIBUTE"`
+	Data      string `xml:"ATTRIBUTE_DATA"`
+}
+
+func benchLevel2CKLStatus(level string) string {
+	switch level {
+	case share.BenchLevelWarn:
+		return cklStatusOpen
+	case share.BenchLevelPass:
+		return cklStatusNotAFinding
+	default:
+		return cklStatusNotReviewed
+	}
+}
+
+// Only the checks with the STIG rules are in the checklist, sorted by the vulnerability ID
+func stigReport2CKL(host *api.RESTHost, rpt *api.RESTBenchReport) ([]byte, error) {
+	asset := cklAsset{Role: "None", AssetType: "Computing", HostName: host.Name, TechArea: "Kubernetes"}
+	var names []string
+	for name := range host.Ifaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if addrs := host.Ifaces[name]; len(addrs) > 0 {
+			asset.HostIP = addrs[0].IP
+			break
+		}
+	}
+
+	stig := cklStig{
+		Info: []cklSIData{
+			{Name: "version", Data: rpt.Stig.Version},
+			{Name: "classification", Data: cklDefaultClassification},
+			{Name: "stigid", Data: rpt.Stig.StigID},
+			{Name: "releaseinfo", Data: rpt.Stig.Release},
+			{Name: "title", Data: rpt.Stig.Title},
+		},
+		Vulns: make([]cklVuln, 0, len(rpt.Items)),
+	}
+
+	items := make([]*api.RESTBenchItem, 0, len(rpt.Items))
+	for _, item := range rpt.Items {
+		if item.Stig != nil {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Stig.VulnID < items[j].Stig.VulnID })
+
+	for _, item := range items {
+		// The description has the first message appended, use the check header
+		title := item.Description
+		if len(item.Message) > 0 {
+			title = strings.TrimSuffix(title, " - "+item.Message[0])
+		}
+		stig.Vulns = append(stig.Vulns, cklVuln{
+			Data: []cklStigData{
+				{Attribute: "Vuln_Num", Data: item.Stig.VulnID},
+				{Attribute: "Severity", Data: item.Stig.Severity},
+				{Attribute: "Rule_ID", Data: item.Stig.RuleID},
+				{Attribute: "Rule_Ver", Data: item.Stig.StigID},
+				{Attribute: "Rule_Title", Data: title},
+				{Attribute: "Fix_Text", Data: strings.TrimSpace(item.Remediation)},
+				{Attribute: "STIGRef", Data: rpt.Stig.Title + " :: Version " + rpt.Stig.Version + ", " + rpt.Stig.Release},
+			},
+			Status:         benchLevel2CKLStatus(item.Level),
+			FindingDetails: strings.Join(item.Message, "\n"),
+		})
+	}
+
+	data, err := xml.MarshalIndent(&cklChecklist{Asset: asset, Stigs: []cklStig{stig}}, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+func handlerKubeBenchCKL(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.HasRequiredPermissions() {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	id := ps.ByName("id")
+
+	host, err := cacher.GetHost(id, acc)
+	if host == nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+
+	// The checklist has all the STIG checks, so the compliance profile is not applied
+	cpf := &complianceProfileFilter{filter: make(map[string][]string), object: host}
+	rpt, errCode, msg := getKubeCISReportFromCluster(id, cpf, acc)
+	if errCode != 0 {
+		if msg != "" {
+			restRespErrorMessage(w, http.StatusInternalServerError, errCode, msg)
+		} else {
+			restRespError(w, http.StatusInternalServerError, errCode)
+		}
+		return
+	}
+	if rpt == nil || rpt.Stig == nil {
+		restRespErrorMessage(w, http.StatusNotFound, api.RESTErrObjectNotFound, "STIG benchmark report not found")
+		return
+	}
+
+	data, err := stigReport2CKL(host, rpt)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to generate checklist")
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailExport)
+		return
+	}
+
+	// tell the browser the returned content should be downloaded
+	w.Header().Set("Content-Type", "application/xml")
+	w.Header().Set("Content-Disposition", "Attachment; filename="+host.Name+"_stig.ckl")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
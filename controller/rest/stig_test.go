@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestStigCKL(t *testing.T) {
+	host := &api.RESTHost{
+		Name:   "node1",
+		Ifaces: map[string][]*api.RESTIPAddr{"eth0": {{IP: "10.1.1.1"}}},
+	}
+	rpt := &api.RESTBenchReport{
+		Stig: &api.RESTBenchStigInfo{StigID: "Kubernetes_STIG", Title: "Kubernetes STIG", Version: "1", Release: "Release: 8"},
+		Items: []*api.RESTBenchItem{
+			{
+				RESTBenchCheck: api.RESTBenchCheck{TestNum: "V-242391", Description: "Kubelet anonymous auth - enabled"},
+				Level:          share.BenchLevelWarn,
+				Message:        []string{"enabled"},
+				Stig:           &api.RESTBenchStig{VulnID: "V-242391", StigID: "CNTR-K8-000370", Severity: share.BenchStigSeverityHigh},
+			},
+			{
+				RESTBenchCheck: api.RESTBenchCheck{TestNum: "V-242376", Description: "Controller manager TLS"},
+				Level:          share.BenchLevelPass,
+				Message:        []string{},
+				Stig:           &api.RESTBenchStig{VulnID: "V-242376", StigID: "CNTR-K8-000150", Severity: share.BenchStigSeverityMedium},
+			},
+			{
+				RESTBenchCheck: api.RESTBenchCheck{TestNum: "K.4.2.1", Description: "Not a STIG check"},
+				Level:          share.BenchLevelWarn,
+			},
+		},
+	}
+
+	data, err := stigReport2CKL(host, rpt)
+	if err != nil {
+		t.Fatalf("Failed to generate checklist: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte(xml.Header)) {
+		t.Errorf("No XML header: %s", data)
+	}
+
+	var ckl cklChecklist
+	if err = xml.Unmarshal(data, &ckl); err != nil {
+		t.Fatalf("Failed to parse checklist: %v", err)
+	}
+	if ckl.Asset.HostName != "node1" || ckl.Asset.HostIP != "10.1.1.1" || len(ckl.Stigs) != 1 {
+		t.Fatalf("Unexpected checklist: %+v", ckl)
+	}
+	vulns := ckl.Stigs[0].Vulns
+	if len(vulns) != 2 {
+		t.Fatalf("Unexpected vulns: %+v", vulns)
+	}
+	if vulns[0].Data[0].Data != "V-242376" || vulns[0].Status != cklStatusNotAFinding {
+		t.Errorf("Unexpected vuln: %+v", vulns[0])
+	}
+	if vulns[1].Data[0].Data != "V-242391" || vulns[1].Status != cklStatusOpen || vulns[1].FindingDetails != "enabled" {
+		t.Errorf("Unexpected vuln: %+v", vulns[1])
+	}
+	for _, d := range vulns[1].Data {
+		if d.Attribute == "Rule_Title" && d.Data != "Kubelet anonymous auth" {
+			t.Errorf("Unexpected rule title: %s", d.Data)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"bufio"
 	"compress/gzip"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
@@ -18,9 +19,11 @@ import (
 	"mime/multipart"
 	"net"
 	"net/http"
+	"net/mail"
 	"net/textproto"
 	"net/url"
 	"os"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -48,6 +51,8 @@ import (
 const hostSessionIDBase uint64 = 0x100000000
 const multipartConfigName = "configuration"
 const importBackupDir = "/etc/neuvector/"
+const minLogCorrelationWindow = 10
+const maxLogCorrelationWindow = 3600
 
 func parseWebUrl(l string) error {
 	u, err := url.Parse(l)
@@ -209,6 +214,10 @@ func handlerSystemSummary(w http.ResponseWriter, r *http.Request, ps httprouter.
 	sdb := scanUtils.GetScannerDB()
 	summary.CVEDBVersion = sdb.CVEDBVersion
 	summary.CVEDBCreateTime = sdb.CVEDBCreateTime
+	if since, degraded := getKvDegradedSince(); degraded {
+		summary.KvDegraded = true
+		summary.KvDegradedSince = since.UTC().Format(api.RESTTimeFomat)
+	}
 	resp := api.RESTSystemSummaryData{Summary: summary}
 
 	restRespSuccess(w, r, &resp, acc, login, nil, "Get system summary")
@@ -238,7 +247,10 @@ func handlerSystemGetConfigBase(apiVer string, w http.ResponseWriter, r *http.Re
 					Webhooks: make([]api.RESTWebhook, len(cconf.Webhooks)),
 				}
 				for i, wh := range cconf.Webhooks {
-					fedConf.Webhooks[i] = api.RESTWebhook{Name: wh.Name, Url: wh.Url, Enable: wh.Enable, Type: wh.Type, CfgType: api.CfgTypeFederal}
+					fedConf.Webhooks[i] = api.RESTWebhook{Name: wh.Name, Url: wh.Url, Enable: wh.Enable, Type: wh.Type, RoutingKey: wh.RoutingKey, CfgType: api.CfgTypeFederal}
+					for _, h := range wh.Headers {
+						fedConf.Webhooks[i].Headers = append(fedConf.Webhooks[i].Headers, api.RESTWebhookHeader{Name: h.Name, Value: h.Value})
+					}
 				}
 				sort.Slice(fedConf.Webhooks, func(i, j int) bool { return fedConf.Webhooks[i].Name < fedConf.Webhooks[j].Name })
 			}
@@ -290,33 +302,45 @@ func handlerSystemGetConfigBase(apiVer string, w http.ResponseWriter, r *http.Re
 						NewServiceProfileBaseline: rconf.NewServiceProfileBaseline,
 					},
 					Syslog: api.RESTSystemConfigSyslogV2{
-						SyslogServer:       rconf.SyslogServer,
-						SyslogIPProto:      rconf.SyslogIPProto,
-						SyslogPort:         rconf.SyslogPort,
-						SyslogLevel:        rconf.SyslogLevel,
-						SyslogEnable:       rconf.SyslogEnable,
-						SyslogCategories:   rconf.SyslogCategories,
-						SyslogInJSON:       rconf.SyslogInJSON,
-						SingleCVEPerSyslog: rconf.SingleCVEPerSyslog,
-						SyslogCVEInLayers:  rconf.SyslogCVEInLayers,
-						SyslogServerCert:   rconf.SyslogServerCert,
+						SyslogServer:         rconf.SyslogServer,
+						SyslogIPProto:        rconf.SyslogIPProto,
+						SyslogPort:           rconf.SyslogPort,
+						SyslogLevel:          rconf.SyslogLevel,
+						SyslogEnable:         rconf.SyslogEnable,
+						SyslogCategories:     rconf.SyslogCategories,
+						SyslogInJSON:         rconf.SyslogInJSON,
+						SingleCVEPerSyslog:   rconf.SingleCVEPerSyslog,
+						SyslogCVEInLayers:    rconf.SyslogCVEInLayers,
+						SyslogServerCert:     rconf.SyslogServerCert,
+						SyslogClientCert:     rconf.SyslogClientCert,
+						SyslogClientKey:      rconf.SyslogClientKey,
+						SyslogStructuredData: rconf.SyslogStructuredData,
+						SyslogCategoryMaps:   rconf.SyslogCategoryMaps,
 					},
 					Auth: api.RESTSystemConfigAuthV2{
-						AuthOrder:      rconf.AuthOrder,
-						AuthByPlatform: rconf.AuthByPlatform,
-						RancherEP:      rconf.RancherEP,
+						AuthOrder:           rconf.AuthOrder,
+						AuthByPlatform:      rconf.AuthByPlatform,
+						RancherEP:           rconf.RancherEP,
+						RancherProjectRoles: rconf.RancherProjectRoles,
 					},
 					Misc: api.RESTSystemConfigMiscV2{
-						InternalSubnets:    rconf.InternalSubnets,
-						UnusedGroupAging:   rconf.UnusedGroupAging,
-						ClusterName:        rconf.ClusterName,
-						ControllerDebug:    rconf.ControllerDebug,
-						MonitorServiceMesh: rconf.MonitorServiceMesh,
-						XffEnabled:         rconf.XffEnabled,
-						NoTelemetryReport:  rconf.NoTelemetryReport,
-						CspType:            rconf.CspType,
+						InternalSubnets:      rconf.InternalSubnets,
+						UnusedGroupAging:     rconf.UnusedGroupAging,
+						ClusterName:          rconf.ClusterName,
+						ControllerDebug:      rconf.ControllerDebug,
+						MonitorServiceMesh:   rconf.MonitorServiceMesh,
+						XffEnabled:           rconf.XffEnabled,
+						NoTelemetryReport:    rconf.NoTelemetryReport,
+						KubeCISProfile:       rconf.KubeCISProfile,
+						BenchMode:            rconf.BenchMode,
+						PolicyChangeApproval: rconf.PolicyChangeApproval,
+						CspType:              rconf.CspType,
 					},
-					Webhooks: rconf.Webhooks,
+					Webhooks:       rconf.Webhooks,
+					FlowExport:     rconf.FlowExport,
+					LogExporters:   rconf.LogExporters,
+					SMTP:           rconf.SMTP,
+					LogCorrelation: rconf.LogCorrelation,
 					Proxy: api.RESTSystemConfigProxyV2{
 						RegistryHttpProxyEnable:  rconf.RegistryHttpProxyEnable,
 						RegistryHttpsProxyEnable: rconf.RegistryHttpsProxyEnable,
@@ -334,6 +358,10 @@ func handlerSystemGetConfigBase(apiVer string, w http.ResponseWriter, r *http.Re
 						NetServicePolicyMode: rconf.NetServicePolicyMode,
 						DisableNetPolicy:     rconf.DisableNetPolicy,
 						DetectUnmanagedWl:    rconf.DetectUnmanagedWl,
+						HostNetPolicyStatus:  rconf.HostNetPolicyStatus,
+						HostNetPolicyMode:    rconf.HostNetPolicyMode,
+						SynFloodThreshold:    rconf.SynFloodThreshold,
+						PortScanThreshold:    rconf.PortScanThreshold,
 					},
 					ModeAuto: api.RESTSystemConfigModeAutoV2{
 						ModeAutoD2M:         rconf.ModeAutoD2M,
@@ -467,6 +495,7 @@ func handlerSystemRequest(w http.ResponseWriter, r *http.Request, ps httprouter.
 			}
 			cconf.Quarantine = false
 			cconf.QuarReason = ""
+			cconf.QuarExceptions = nil
 
 			value, _ = json.Marshal(&cconf)
 			if err = cluster.PutRev(key, value, rev); err != nil {
@@ -480,6 +509,8 @@ func handlerSystemRequest(w http.ResponseWriter, r *http.Request, ps httprouter.
 	restRespSuccess(w, r, nil, acc, login, &req, "System request")
 }
 
+var webhookHeaderRegex *regexp.Regexp = regexp.MustCompile("^[a-zA-Z0-9!#$%&'*+.^_`|~-]+$")
+
 func validateWebhook(h *api.RESTWebhook) (int, error) {
 	var msg string
 	hasFedPrefix := strings.HasPrefix(h.Name, api.FederalGroupPrefix)
@@ -511,13 +542,63 @@ func validateWebhook(h *api.RESTWebhook) (int, error) {
 		log.WithFields(log.Fields{"name": h.Name, "url": h.Url, "error": err}).Error("Invalid webhook URL")
 		return api.RESTErrInvalidRequest, errors.New("Invalid webhook URL")
 	}
-	if h.Type != "" && h.Type != api.WebhookTypeSlack && h.Type != api.WebhookTypeJSON && h.Type != api.WebhookTypeTeams {
+	if h.Type != "" && h.Type != api.WebhookTypeSlack && h.Type != api.WebhookTypeJSON && h.Type != api.WebhookTypeTeams &&
+		h.Type != api.WebhookTypePagerDuty {
 		log.WithFields(log.Fields{"name": h.Name, "type": h.Type}).Error("Invalid webhook type")
 		return api.RESTErrInvalidRequest, errors.New("Invalid webhook type")
 	}
+	names := utils.NewSet()
+	for _, hdr := range h.Headers {
+		name := http.CanonicalHeaderKey(hdr.Name)
+		if !webhookHeaderRegex.MatchString(hdr.Name) || name == "Content-Type" || name == "Content-Length" || name == "Host" {
+			log.WithFields(log.Fields{"name": h.Name, "header": hdr.Name}).Error("Invalid webhook header")
+			return api.RESTErrInvalidRequest, fmt.Errorf("Invalid webhook header %s", hdr.Name)
+		}
+		if names.Contains(name) {
+			log.WithFields(log.Fields{"name": h.Name, "header": hdr.Name}).Error("Duplicate webhook header")
+			return api.RESTErrInvalidRequest, fmt.Errorf("Duplicate webhook header %s", hdr.Name)
+		}
+		names.Add(name)
+	}
 	return 0, nil
 }
 
+// The header values are not returned to the clients, so the empty value keeps the existing one
+func webhookHeaders2Cluster(rhs []api.RESTWebhookHeader, olds []share.CLUSWebhookHeader) []share.CLUSWebhookHeader {
+	if len(rhs) == 0 {
+		return nil
+	}
+	headers := make([]share.CLUSWebhookHeader, len(rhs))
+	for i, rh := range rhs {
+		headers[i] = share.CLUSWebhookHeader{Name: http.CanonicalHeaderKey(rh.Name), Value: rh.Value, Cloaked: true}
+		if rh.Value == "" {
+			for _, old := range olds {
+				if old.Name == headers[i].Name {
+					headers[i].Value = old.Value
+					break
+				}
+			}
+		}
+	}
+	return headers
+}
+
+// The routing key is not returned to the clients either, so the empty or masked value keeps the existing one
+func webhookRoutingKey2Cluster(h *api.RESTWebhook, old string) (string, int, error) {
+	if h.Type != api.WebhookTypePagerDuty {
+		return "", 0, nil
+	}
+	key := h.RoutingKey
+	if key == "" || key == api.RESTMaskedValue {
+		key = old
+	}
+	if key == "" {
+		log.WithFields(log.Fields{"name": h.Name}).Error("Empty PagerDuty routing key")
+		return "", api.RESTErrInvalidRequest, errors.New("PagerDuty webhook requires a routing key")
+	}
+	return key, 0, nil
+}
+
 func configWebhooks(rcWebhookUrl *string, rcWebhooks *[]*api.RESTWebhook, cconfWebhooks []share.CLUSWebhook,
 	cfgType share.TCfgType, acc *access.AccessControl) ([]share.CLUSWebhook, int, error) {
 
@@ -559,8 +640,25 @@ func configWebhooks(rcWebhookUrl *string, rcWebhooks *[]*api.RESTWebhook, cconfW
 				return nil, code, err
 			}
 
+			var oldHeaders []share.CLUSWebhookHeader
+			var oldRoutingKey string
+			for _, old := range cconfWebhooks {
+				if old.Name == h.Name {
+					oldHeaders = old.Headers
+					oldRoutingKey = old.RoutingKey
+					break
+				}
+			}
+			routingKey, code, err := webhookRoutingKey2Cluster(h, oldRoutingKey)
+			if err != nil {
+				return nil, code, err
+			}
+
 			newWebhookNames.Add(h.Name)
-			newWebhooks = append(newWebhooks, share.CLUSWebhook{Name: h.Name, Url: h.Url, Enable: h.Enable, Type: h.Type, CfgType: cfgType})
+			newWebhooks = append(newWebhooks, share.CLUSWebhook{
+				Name: h.Name, Url: h.Url, Enable: h.Enable, Type: h.Type, Headers: webhookHeaders2Cluster(h.Headers, oldHeaders),
+				RoutingKey: routingKey, CfgType: cfgType,
+			})
 		}
 	}
 
@@ -596,6 +694,207 @@ func configWebhooks(rcWebhookUrl *string, rcWebhooks *[]*api.RESTWebhook, cconfW
 	return newWebhooks, 0, nil
 }
 
+// The exporters in the request replace the configured ones. A masked token or password keeps the value of the exporter with the same name.
+func configLogExporters(rcExporters []*api.RESTLogExporter, cconfExporters []share.CLUSLogExporter) ([]share.CLUSLogExporter, error) {
+	exporters := make([]share.CLUSLogExporter, 0, len(rcExporters))
+	names := utils.NewSet()
+	for _, rex := range rcExporters {
+		if rex.Name == "" || names.Contains(rex.Name) {
+			return nil, fmt.Errorf("Empty or duplicate log exporter name %s", rex.Name)
+		}
+		switch rex.Type {
+		case common.LogExporterSplunk, common.LogExporterElastic:
+			if err := parseWebUrl(rex.URL); err != nil {
+				return nil, fmt.Errorf("Invalid log exporter URL %s", rex.URL)
+			}
+		case common.LogExporterKafka:
+			if err := validateKafkaExporter(rex); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("Unsupported log exporter type %s", rex.Type)
+		}
+		for _, cat := range rex.Categories {
+			if !isLogExportCategory(cat) {
+				return nil, fmt.Errorf("Invalid log exporter category %s", cat)
+			}
+		}
+		if rex.CACert != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(rex.CACert)) {
+			return nil, fmt.Errorf("Invalid CA certificate of log exporter %s", rex.Name)
+		}
+
+		cex := share.CLUSLogExporter{
+			Name:       rex.Name,
+			Type:       rex.Type,
+			Enable:     rex.Enable,
+			URL:        rex.URL,
+			Token:      rex.Token,
+			Username:   rex.Username,
+			Password:   rex.Password,
+			Index:      rex.Index,
+			Categories: rex.Categories,
+			CACert:     rex.CACert,
+			SkipVerify: rex.SkipVerify,
+		}
+		if cex.Type == common.LogExporterKafka {
+			cex.Brokers = rex.Brokers
+			cex.Topic = rex.Topic
+			cex.TLS = rex.TLS
+			cex.SASLMechanism = rex.SASLMechanism
+			cex.PartitionKey = rex.PartitionKey
+			cex.Topics = make([]share.CLUSKafkaTopic, len(rex.Topics))
+			for i, t := range rex.Topics {
+				cex.Topics[i] = share.CLUSKafkaTopic{Category: t.Category, Topic: t.Topic}
+			}
+		}
+		for _, old := range cconfExporters {
+			if old.Name == cex.Name {
+				if cex.Token == api.RESTMaskedValue {
+					cex.Token = old.Token
+				}
+				if cex.Password == api.RESTMaskedValue {
+					cex.Password = old.Password
+				}
+				break
+			}
+		}
+		if cex.Type == common.LogExporterSplunk && cex.Token == "" {
+			return nil, fmt.Errorf("Token is required by log exporter %s", cex.Name)
+		}
+		names.Add(cex.Name)
+		exporters = append(exporters, cex)
+	}
+	return exporters, nil
+}
+
+var kafkaTopicRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,249}$`)
+
+func isLogExportCategory(cat string) bool {
+	switch cat {
+	case api.CategoryEvent, api.CategoryRuntime, api.CategoryAudit, api.CategoryConfigAudit:
+		return true
+	}
+	return false
+}
+
+func validateKafkaExporter(rex *api.RESTLogExporter) error {
+	if len(rex.Brokers) == 0 {
+		return fmt.Errorf("Kafka brokers are required by log exporter %s", rex.Name)
+	}
+	for _, broker := range rex.Brokers {
+		if host, port, err := net.SplitHostPort(broker); err != nil || host == "" || port == "" {
+			return fmt.Errorf("Invalid kafka broker %s", broker)
+		}
+	}
+	if !kafkaTopicRegex.MatchString(rex.Topic) {
+		return fmt.Errorf("Invalid kafka topic %s", rex.Topic)
+	}
+	for _, t := range rex.Topics {
+		if !isLogExportCategory(t.Category) {
+			return fmt.Errorf("Invalid kafka topic category %s", t.Category)
+		} else if !kafkaTopicRegex.MatchString(t.Topic) {
+			return fmt.Errorf("Invalid kafka topic %s", t.Topic)
+		}
+	}
+	switch rex.SASLMechanism {
+	case "":
+	case common.KafkaSASLPlain, common.KafkaSASLScram256, common.KafkaSASLScram512:
+		if rex.Username == "" {
+			return fmt.Errorf("Username is required by SASL %s", rex.SASLMechanism)
+		}
+	default:
+		return fmt.Errorf("Unsupported SASL mechanism %s", rex.SASLMechanism)
+	}
+	switch rex.PartitionKey {
+	case "", common.KafkaPartitionKeyCluster, common.KafkaPartitionKeyWorkload:
+	default:
+		return fmt.Errorf("Invalid kafka partition key %s", rex.PartitionKey)
+	}
+	return nil
+}
+
+func configFlowExport(rc *api.RESTFlowExport) (share.CLUSFlowExport, error) {
+	flow := share.CLUSFlowExport{Enable: rc.Enable, Collector: rc.Collector, Format: rc.Format}
+	if flow.Format == "" {
+		flow.Format = common.FlowExportIPFIX
+	}
+	if flow.Format != common.FlowExportIPFIX && flow.Format != common.FlowExportNetflowV9 {
+		return flow, fmt.Errorf("Unsupported flow export format %s", flow.Format)
+	}
+	if flow.Collector != "" {
+		if _, _, err := net.SplitHostPort(flow.Collector); err != nil {
+			return flow, fmt.Errorf("Invalid flow collector %s", flow.Collector)
+		}
+	} else if flow.Enable {
+		return flow, fmt.Errorf("Collector is required to enable flow export")
+	}
+	return flow, nil
+}
+
+// A masked password keeps the configured one
+func configSMTP(rc *api.RESTSMTPConfig, cconf *share.CLUSSMTPConfig) (share.CLUSSMTPConfig, error) {
+	smtp := share.CLUSSMTPConfig{
+		Enable:      rc.Enable,
+		Server:      rc.Server,
+		Port:        rc.Port,
+		Security:    rc.Security,
+		SkipVerify:  rc.SkipVerify,
+		Username:    rc.Username,
+		Password:    rc.Password,
+		From:        rc.From,
+		Routes:      make([]share.CLUSSMTPRoute, 0, len(rc.Routes)),
+		Digest:      rc.Digest,
+		DigestLevel: rc.DigestLevel,
+	}
+	if smtp.Password == api.RESTMaskedValue {
+		smtp.Password = cconf.Password
+	}
+
+	switch smtp.Security {
+	case "", common.SMTPSecurityStartTLS, common.SMTPSecurityTLS:
+	case common.SMTPSecurityNone:
+		if smtp.Username != "" {
+			return smtp, fmt.Errorf("SMTP authentication requires TLS")
+		}
+	default:
+		return smtp, fmt.Errorf("Unsupported SMTP security %s", smtp.Security)
+	}
+	if _, ok := common.SMTPDigestPeriod(smtp.Digest); !ok {
+		return smtp, fmt.Errorf("Unsupported SMTP digest %s", smtp.Digest)
+	}
+	if _, ok := common.LevelToPrio(smtp.DigestLevel); smtp.DigestLevel != "" && !ok {
+		return smtp, fmt.Errorf("Invalid SMTP digest level %s", smtp.DigestLevel)
+	}
+	if smtp.From != "" {
+		if _, err := mail.ParseAddress(smtp.From); err != nil {
+			return smtp, fmt.Errorf("Invalid SMTP sender %s", smtp.From)
+		}
+	}
+	for _, r := range rc.Routes {
+		if len(r.Recipients) == 0 || len(r.Categories) == 0 {
+			return smtp, fmt.Errorf("Recipients and categories are required by SMTP route")
+		}
+		for _, rcpt := range r.Recipients {
+			if _, err := mail.ParseAddress(rcpt); err != nil {
+				return smtp, fmt.Errorf("Invalid SMTP recipient %s", rcpt)
+			}
+		}
+		for _, cat := range r.Categories {
+			if !isLogExportCategory(cat) {
+				return smtp, fmt.Errorf("Invalid SMTP route category %s", cat)
+			}
+		}
+		if _, ok := common.LevelToPrio(r.Level); r.Level != "" && !ok {
+			return smtp, fmt.Errorf("Invalid SMTP route level %s", r.Level)
+		}
+		smtp.Routes = append(smtp.Routes, share.CLUSSMTPRoute{Recipients: r.Recipients, Categories: r.Categories, Level: r.Level})
+	}
+	if smtp.Enable && (smtp.Server == "" || smtp.From == "" || len(smtp.Routes) == 0) {
+		return smtp, fmt.Errorf("Server, sender and routes are required to enable SMTP notification")
+	}
+	return smtp, nil
+}
+
 func handlerSystemWebhookCreate(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
 	defer r.Body.Close()
@@ -628,6 +927,7 @@ func handlerSystemWebhookCreate(w http.ResponseWriter, r *http.Request, ps httpr
 		Url:     rwh.Url,
 		Enable:  rwh.Enable,
 		Type:    rwh.Type,
+		Headers: webhookHeaders2Cluster(rwh.Headers, nil),
 		CfgType: share.UserCreated,
 	}
 	if rwh.CfgType == api.CfgTypeFederal {
@@ -642,6 +942,12 @@ func handlerSystemWebhookCreate(w http.ResponseWriter, r *http.Request, ps httpr
 		restRespErrorMessage(w, http.StatusBadRequest, code, err.Error())
 		return
 	}
+	if key, code, err := webhookRoutingKey2Cluster(rwh, ""); err != nil {
+		restRespErrorMessage(w, http.StatusBadRequest, code, err.Error())
+		return
+	} else {
+		cwh.RoutingKey = key
+	}
 
 	// Acquire servr lock
 	lock, err := clusHelper.AcquireLock(share.CLUSLockServerKey, clusterLockWait)
@@ -794,7 +1100,15 @@ func handlerSystemWebhookConfig(w http.ResponseWriter, r *http.Request, ps httpr
 		var found bool
 		for i, _ := range cconf.Webhooks {
 			if cconf.Webhooks[i].Name == rwh.Name {
-				cconf.Webhooks[i] = share.CLUSWebhook{Name: rwh.Name, Url: rwh.Url, Enable: rwh.Enable, Type: rwh.Type}
+				routingKey, code, err := webhookRoutingKey2Cluster(rwh, cconf.Webhooks[i].RoutingKey)
+				if err != nil {
+					restRespErrorMessage(w, http.StatusBadRequest, code, err.Error())
+					return
+				}
+				cconf.Webhooks[i] = share.CLUSWebhook{
+					Name: rwh.Name, Url: rwh.Url, Enable: rwh.Enable, Type: rwh.Type, Headers: webhookHeaders2Cluster(rwh.Headers, cconf.Webhooks[i].Headers),
+					RoutingKey: routingKey,
+				}
 				found = true
 				break
 			}
@@ -1029,6 +1343,30 @@ func configSystemConfig(w http.ResponseWriter, acc *access.AccessControl, login
 			if nc.DetectUnmanagedWl != nil {
 				cconf.DetectUnmanagedWl = *nc.DetectUnmanagedWl
 			}
+
+			// network policy enforcement on host processes
+			if nc.HostNetPolicyStatus != nil {
+				cconf.HostNetPolicyStatus = *nc.HostNetPolicyStatus
+			}
+			if nc.HostNetPolicyMode != nil {
+				switch *nc.HostNetPolicyMode {
+				case share.PolicyModeLearn, share.PolicyModeEvaluate, share.PolicyModeEnforce:
+					cconf.HostNetPolicyMode = *nc.HostNetPolicyMode
+				default:
+					e := "Invalid host network policy mode"
+					log.WithFields(log.Fields{"host_net_policy_mode": *nc.HostNetPolicyMode}).Error(e)
+					restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+					return kick, errors.New(e)
+				}
+			}
+
+			// thresholds of the enforcer's flood and scan detection
+			if nc.SynFloodThreshold != nil {
+				cconf.SynFloodThreshold = *nc.SynFloodThreshold
+			}
+			if nc.PortScanThreshold != nil {
+				cconf.PortScanThreshold = *nc.PortScanThreshold
+			}
 		}
 
 		if scope == share.ScopeLocal && rconf.AtmoConfig != nil {
@@ -1125,7 +1463,7 @@ func configSystemConfig(w http.ResponseWriter, acc *access.AccessControl, login
 			if rc.SyslogCategories != nil {
 				for _, categories := range *rc.SyslogCategories {
 					if categories != api.CategoryEvent && categories != api.CategoryRuntime &&
-						categories != api.CategoryAudit {
+						categories != api.CategoryAudit && categories != api.CategoryConfigAudit {
 						e := "Invalid syslog Category"
 						log.WithFields(log.Fields{"category": categories}).Error(e)
 						restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
@@ -1182,6 +1520,39 @@ func configSystemConfig(w http.ResponseWriter, acc *access.AccessControl, login
 				}
 			}
 
+			// A masked client key keeps the key in the config
+			if rc.SyslogClientCert != nil {
+				cconf.SyslogClientCert = strings.TrimSpace(*rc.SyslogClientCert)
+			}
+			if rc.SyslogClientKey != nil && *rc.SyslogClientKey != api.RESTMaskedValue {
+				cconf.SyslogClientKey = strings.TrimSpace(*rc.SyslogClientKey)
+			}
+			if (rc.SyslogClientCert != nil || rc.SyslogClientKey != nil) && (cconf.SyslogClientCert != "" || cconf.SyslogClientKey != "") {
+				if _, certErr := tls.X509KeyPair([]byte(cconf.SyslogClientCert), []byte(cconf.SyslogClientKey)); certErr != nil {
+					e := "Invalid syslog client certificate or key"
+					log.WithFields(log.Fields{"error": certErr}).Error(e)
+					restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+					return kick, errors.New(e)
+				}
+			}
+
+			if rc.SyslogStructuredData != nil {
+				cconf.SyslogStructuredData = *rc.SyslogStructuredData
+			}
+
+			if rc.SyslogCategoryMaps != nil {
+				maps := make([]share.CLUSSyslogCategoryMap, 0, len(*rc.SyslogCategoryMaps))
+				for _, m := range *rc.SyslogCategoryMaps {
+					if err := validateSyslogCategoryMap(&m); err != nil {
+						log.WithFields(log.Fields{"map": m, "error": err}).Error()
+						restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+						return kick, err
+					}
+					maps = append(maps, share.CLUSSyslogCategoryMap{Category: m.Category, Facility: m.Facility, Level: m.Level})
+				}
+				cconf.SyslogCategoryMaps = maps
+			}
+
 			if rc.SyslogPort != nil {
 				if *rc.SyslogPort == 0 {
 					cconf.SyslogPort = api.SyslogDefaultUDPPort
@@ -1269,6 +1640,17 @@ func configSystemConfig(w http.ResponseWriter, acc *access.AccessControl, login
 					cconf.RancherEP = fmt.Sprintf("%s://%s", u.Scheme, u.Host)
 				}
 			}
+			if rc.RancherProjectRoles != nil {
+				for template, role := range *rc.RancherProjectRoles {
+					if template == "" || !access.IsValidRole(role, access.CONST_VISIBLE_DOMAIN_ROLE) {
+						e := "Invalid Rancher project role mapping"
+						log.WithFields(log.Fields{"template": template, "role": role}).Error(e)
+						restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+						return kick, errors.New(e)
+					}
+				}
+				cconf.RancherProjectRoles = *rc.RancherProjectRoles
+			}
 
 			/*
 				if rc.InternalSubnets != nil {
@@ -1295,6 +1677,46 @@ func configSystemConfig(w http.ResponseWriter, acc *access.AccessControl, login
 				}
 			}
 
+			if rc.FlowExport != nil {
+				if flow, err := configFlowExport(rc.FlowExport); err != nil {
+					log.WithFields(log.Fields{"error": err}).Error("Invalid flow export config")
+					restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+					return kick, err
+				} else {
+					cconf.FlowExport = flow
+				}
+			}
+
+			if rc.LogExporters != nil {
+				if exporters, err := configLogExporters(*rc.LogExporters, cconf.LogExporters); err != nil {
+					log.WithFields(log.Fields{"error": err}).Error("Invalid log exporter")
+					restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+					return kick, err
+				} else {
+					cconf.LogExporters = exporters
+				}
+			}
+
+			if rc.SMTP != nil {
+				if smtp, err := configSMTP(rc.SMTP, &cconf.SMTP); err != nil {
+					log.WithFields(log.Fields{"error": err}).Error("Invalid SMTP config")
+					restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+					return kick, err
+				} else {
+					cconf.SMTP = smtp
+				}
+			}
+
+			if rc.LogCorrelation != nil {
+				if win := rc.LogCorrelation.Window; win != 0 && (win < minLogCorrelationWindow || win > maxLogCorrelationWindow) {
+					e := fmt.Sprintf("Log correlation window must be between %d and %d seconds", minLogCorrelationWindow, maxLogCorrelationWindow)
+					log.WithFields(log.Fields{"window": win}).Error(e)
+					restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+					return kick, errors.New(e)
+				}
+				cconf.LogCorrelation = share.CLUSLogCorrelation{Enable: rc.LogCorrelation.Enable, Window: rc.LogCorrelation.Window}
+			}
+
 			// Controller debug
 			if rc.ControllerDebug != nil {
 				cconf.ControllerDebug = *rc.ControllerDebug
@@ -1436,6 +1858,35 @@ func configSystemConfig(w http.ResponseWriter, acc *access.AccessControl, login
 			if rc.NoTelemetryReport != nil {
 				cconf.NoTelemetryReport = *rc.NoTelemetryReport
 			}
+
+			// kubernetes CIS benchmark profile, the profiles are in the enforcer's bundle
+			if rc.KubeCISProfile != nil {
+				if *rc.KubeCISProfile != "" && !isObjectNameValid(*rc.KubeCISProfile) {
+					e := "Invalid kubernetes CIS profile name"
+					log.WithFields(log.Fields{"profile": *rc.KubeCISProfile}).Error(e)
+					restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+					return kick, errors.New(e)
+				}
+				cconf.KubeCISProfile = *rc.KubeCISProfile
+			}
+
+			// CIS or STIG benchmarks of the kubernetes nodes
+			if rc.BenchMode != nil {
+				switch *rc.BenchMode {
+				case "", share.BenchModeCIS, share.BenchModeSTIG:
+					cconf.BenchMode = *rc.BenchMode
+				default:
+					e := "Invalid benchmark mode"
+					log.WithFields(log.Fields{"mode": *rc.BenchMode}).Error(e)
+					restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+					return kick, errors.New(e)
+				}
+			}
+
+			// network rule changes must be approved by another user
+			if rc.PolicyChangeApproval != nil {
+				cconf.PolicyChangeApproval = *rc.PolicyChangeApproval
+			}
 		} else if scope == share.ScopeFed && rconf.FedConfig != nil {
 			// webhook for fed system config
 			if rconf.FedConfig.Webhooks != nil {
@@ -1510,11 +1961,16 @@ func handlerSystemConfigBase(apiVer string, w http.ResponseWriter, r *http.Reque
 				config.SingleCVEPerSyslog = configV2.SyslogCfg.SingleCVEPerSyslog
 				config.SyslogCVEInLayers = configV2.SyslogCfg.SyslogCVEInLayers
 				config.SyslogServerCert = configV2.SyslogCfg.SyslogServerCert
+				config.SyslogClientCert = configV2.SyslogCfg.SyslogClientCert
+				config.SyslogClientKey = configV2.SyslogCfg.SyslogClientKey
+				config.SyslogStructuredData = configV2.SyslogCfg.SyslogStructuredData
+				config.SyslogCategoryMaps = configV2.SyslogCfg.SyslogCategoryMaps
 			}
 			if configV2.AuthCfg != nil {
 				config.AuthOrder = configV2.AuthCfg.AuthOrder
 				config.AuthByPlatform = configV2.AuthCfg.AuthByPlatform
 				config.RancherEP = configV2.AuthCfg.RancherEP
+				config.RancherProjectRoles = configV2.AuthCfg.RancherProjectRoles
 			}
 			if configV2.ProxyCfg != nil {
 				config.RegistryHttpProxyEnable = configV2.ProxyCfg.RegistryHttpProxyEnable
@@ -1525,6 +1981,18 @@ func handlerSystemConfigBase(apiVer string, w http.ResponseWriter, r *http.Reque
 			if configV2.Webhooks != nil {
 				config.Webhooks = configV2.Webhooks
 			}
+			if configV2.FlowExport != nil {
+				config.FlowExport = configV2.FlowExport
+			}
+			if configV2.LogExporters != nil {
+				config.LogExporters = configV2.LogExporters
+			}
+			if configV2.SMTP != nil {
+				config.SMTP = configV2.SMTP
+			}
+			if configV2.LogCorrelation != nil {
+				config.LogCorrelation = configV2.LogCorrelation
+			}
 			if configV2.IbmsaCfg != nil {
 				config.IBMSAEpEnabled = configV2.IbmsaCfg.IBMSAEpEnabled
 				config.IBMSAEpDashboardURL = configV2.IbmsaCfg.IBMSAEpDashboardURL
@@ -1536,6 +2004,9 @@ func handlerSystemConfigBase(apiVer string, w http.ResponseWriter, r *http.Reque
 				config.MonitorServiceMesh = configV2.MiscCfg.MonitorServiceMesh
 				config.XffEnabled = configV2.MiscCfg.XffEnabled
 				config.NoTelemetryReport = configV2.MiscCfg.NoTelemetryReport
+				config.KubeCISProfile = configV2.MiscCfg.KubeCISProfile
+				config.BenchMode = configV2.MiscCfg.BenchMode
+				config.PolicyChangeApproval = configV2.MiscCfg.PolicyChangeApproval
 			}
 			config.ScannerAutoscale = configV2.ScannerAutoscale
 			rconf.Config = config
@@ -1785,6 +2256,8 @@ func meter2REST(m *share.CLUSMeter) *api.RESTMeter {
 		mType = api.MeterTypeIPSrcSessionLimit
 	case C.METER_ID_TCP_NODATA:
 		mType = api.MeterTypeTCPNoData
+	case C.METER_ID_PORT_SCAN:
+		mType = api.MeterTypePortScan
 	}
 
 	return &api.RESTMeter{
@@ -2052,6 +2525,83 @@ func rawImportRead(r *http.Request, tmpfile *os.File) (int, error) {
 	return lines, nil
 }
 
+func readConfigImportFile(r *http.Request, tmpfile *os.File) (int, error) {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Error in parsing media type")
+		tmpfile.Close()
+		return 0, err
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		return multipartImportRead(r, params, tmpfile)
+	}
+	return rawImportRead(r, tmpfile)
+}
+
+// Sections of the config file to import. Empty means all sections in the file
+func parseImportSections(r *http.Request) ([]string, error) {
+	query := restParseQuery(r)
+	value, ok := query.pairs[api.QueryKeySection]
+	if !ok || value == "" {
+		return nil, nil
+	}
+	sections := make([]string, 0)
+	for _, sec := range strings.Split(value, ",") {
+		switch sec {
+		case api.ConfSectionAll:
+			return nil, nil
+		case api.ConfSectionUser, api.ConfSectionPolicy, api.ConfSectionConfig:
+			sections = append(sections, sec)
+		default:
+			return nil, fmt.Errorf("Unsupported configuration section %s", sec)
+		}
+	}
+	return sections, nil
+}
+
+// The uploaded config file is compared with the current config, nothing is imported
+func configImportDiff(w http.ResponseWriter, r *http.Request, sections []string, acc *access.AccessControl, login *loginSession) {
+	if kv.IsImporting() {
+		restRespErrorMessage(w, http.StatusConflict, api.RESTErrFailImport, "Another import is ongoing")
+		return
+	}
+
+	tmpfile, err := ioutil.TempFile(importBackupDir, share.PREFIX_IMPORT_CONFIG)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to create temp file")
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFailImport, err.Error())
+		return
+	}
+	defer os.Remove(tmpfile.Name())
+
+	var entries []*kv.ConfigDiffEntry
+	if _, err = readConfigImportFile(r, tmpfile); err == nil {
+		entries, err = cfgHelper.Diff(tmpfile.Name(), sections)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to compare configuration")
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrFailImport, err.Error())
+		return
+	}
+
+	resp := api.RESTConfigDiffData{
+		Diff: &api.RESTConfigDiff{Entries: make([]*api.RESTConfigDiffEntry, len(entries))},
+	}
+	for i, e := range entries {
+		switch e.Action {
+		case api.ConfigDiffCreate:
+			resp.Diff.Created++
+		case api.ConfigDiffModify:
+			resp.Diff.Modified++
+		case api.ConfigDiffDelete:
+			resp.Diff.Deleted++
+		}
+		resp.Diff.Entries[i] = &api.RESTConfigDiffEntry{Section: e.Section, Endpoint: e.Endpoint, Key: e.Key, Action: e.Action}
+	}
+
+	restRespSuccess(w, r, &resp, acc, login, nil, "Compare configuration to import")
+}
+
 func multipartImportRead(r *http.Request, params map[string]string, tmpfile *os.File) (int, error) {
 	log.WithFields(log.Fields{"params": params}).Info()
 
@@ -2159,13 +2709,13 @@ func _importHandler(w http.ResponseWriter, r *http.Request, tid, importType, tem
 		return
 	}
 
-	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
-	if err != nil {
+	if _, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil {
 		log.WithFields(log.Fields{"error": err, "importType": importType}).Error("Error in parsing media type")
 		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFailImport, err.Error())
 		return
 	}
 
+	var err error
 	var tmpfile *os.File
 	if tmpfile, err = ioutil.TempFile(importBackupDir, tempFilePrefix); err == nil {
 		importTask := share.CLUSImportTask{
@@ -2180,15 +2730,14 @@ func _importHandler(w http.ResponseWriter, r *http.Request, tid, importType, tem
 			CallerRemote:   login.remote,
 			CallerID:       login.id,
 		}
+		if importType == share.IMPORT_TYPE_CONFIG {
+			importTask.Sections, _ = parseImportSections(r)
+		}
 		clusHelper.PutImportTask(&importTask)
 
 		lines := 0
 		if importType == share.IMPORT_TYPE_CONFIG {
-			if strings.HasPrefix(mediaType, "multipart/") {
-				lines, err = multipartImportRead(r, params, tmpfile)
-			} else {
-				lines, err = rawImportRead(r, tmpfile)
-			}
+			lines, err = readConfigImportFile(r, tmpfile)
 		} else {
 			body, _ := ioutil.ReadAll(r.Body)
 			json_data, err := yaml.YAMLToJSON(body)
@@ -2292,6 +2841,21 @@ func handlerConfigImport(w http.ResponseWriter, r *http.Request, ps httprouter.P
 		}
 	}
 
+	if tid == "" {
+		sections, err := parseImportSections(r)
+		if err != nil {
+			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+			return
+		}
+		if dryRun, _ := strconv.ParseBool(r.URL.Query().Get(api.DryRunFlag)); dryRun {
+			configImportDiff(w, r, sections, acc, login)
+			return
+		}
+		if !checkImportPolicyChangeApproval(w, sections) {
+			return
+		}
+	}
+
 	_importHandler(w, r, tid, share.IMPORT_TYPE_CONFIG, share.PREFIX_IMPORT_CONFIG, acc, login)
 }
 
@@ -2370,6 +2934,18 @@ func postImportOp(err error, importTask share.CLUSImportTask, loginDomainRoles a
 				LockKey:       share.CLUSLockPolicyKey,
 				KvCrdKind:     resource.NvWafSecurityRuleKind,
 			},
+			&resource.NvCrdInfo{
+				RscType:       resource.RscTypeCrdVulnProfile,
+				SpecNamesKind: resource.NvVulnProfileKind,
+				LockKey:       share.CLUSLockPolicyKey,
+				KvCrdKind:     resource.NvVulnProfileKind,
+			},
+			&resource.NvCrdInfo{
+				RscType:       resource.RscTypeCrdCompProfile,
+				SpecNamesKind: resource.NvCompProfileKind,
+				LockKey:       share.CLUSLockPolicyKey,
+				KvCrdKind:     resource.NvCompProfileKind,
+			},
 		}
 		for _, crdInfo := range nvCrdInfo {
 			CrossCheckCrd(crdInfo.SpecNamesKind, crdInfo.RscType, crdInfo.KvCrdKind, crdInfo.LockKey, true)
@@ -2421,6 +2997,25 @@ func replaceFedSystemConfig(newCfg *share.CLUSSystemConfig) bool {
 	return true
 }
 
+func validateSyslogCategoryMap(m *api.RESTSyslogCategoryMap) error {
+	switch m.Category {
+	case api.CategoryEvent, api.CategoryRuntime, api.CategoryAudit, api.CategoryConfigAudit:
+	default:
+		return fmt.Errorf("Invalid syslog category %s", m.Category)
+	}
+	if m.Facility != "" {
+		if _, ok := common.FacilityToPrio(m.Facility); !ok {
+			return fmt.Errorf("Invalid syslog facility %s", m.Facility)
+		}
+	}
+	if m.Level != "" {
+		if _, ok := common.LevelToPrio(m.Level); !ok {
+			return fmt.Errorf("Invalid syslog level %s", m.Level)
+		}
+	}
+	return nil
+}
+
 func validateCertificate(certificate string) error {
 	block, _ := pem.Decode([]byte(certificate))
 	if block == nil {
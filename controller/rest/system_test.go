@@ -107,3 +107,46 @@ func TestClusterName(t *testing.T) {
 	}
 	postTest()
 }
+
+func TestThreatThreshold(t *testing.T) {
+	preTest()
+
+	accAdmin := access.NewAdminAccessControl()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+
+	cacher = &mockCache{
+		systemConfig: api.RESTSystemConfig{},
+	}
+
+	synFlood := uint32(500)
+	portScan := uint32(20)
+	nc := api.RESTSysNetConfigConfig{SynFloodThreshold: &synFlood, PortScanThreshold: &portScan}
+	data := api.RESTSystemConfigConfigData{NetConfig: &nc}
+	body, _ := json.Marshal(data)
+	w := restCall("PATCH", "/v1/system/config", body, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Errorf("Fail to set threat thresholds: status=%v.", w.status)
+	}
+
+	cfg, _ := clusHelper.GetSystemConfigRev(accAdmin)
+	if cfg.SynFloodThreshold != synFlood || cfg.PortScanThreshold != portScan {
+		t.Errorf("Threat thresholds are not set: syn_flood=%v port_scan=%v", cfg.SynFloodThreshold, cfg.PortScanThreshold)
+	}
+
+	// Disable port scan detection only
+	portScan = 0
+	nc = api.RESTSysNetConfigConfig{PortScanThreshold: &portScan}
+	data = api.RESTSystemConfigConfigData{NetConfig: &nc}
+	body, _ = json.Marshal(data)
+	restCall("PATCH", "/v1/system/config", body, api.UserRoleAdmin)
+
+	cfg, _ = clusHelper.GetSystemConfigRev(accAdmin)
+	if cfg.SynFloodThreshold != synFlood || cfg.PortScanThreshold != 0 {
+		t.Errorf("Port scan detection is not disabled: syn_flood=%v port_scan=%v", cfg.SynFloodThreshold, cfg.PortScanThreshold)
+	}
+
+	postTest()
+}
@@ -0,0 +1,439 @@
+package rest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/common"
+	"github.com/neuvector/neuvector/share"
+)
+
+const threatSigDownloadTimeout = time.Duration(time.Second * 30)
+const threatSigPackageMaxSize = 16 * 1024 * 1024
+
+var errThreatSigURL = errors.New("Only https URL of an external server is allowed")
+
+var threatSigInternalNets = func() []*net.IPNet {
+	cidrs := []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7"}
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipnet, _ := net.ParseCIDR(cidr)
+		nets = append(nets, ipnet)
+	}
+	return nets
+}()
+
+var threatSigFqdnRegex = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?\.)+[a-zA-Z]{2,}$`)
+
+var threatSigSeverities = map[string]bool{
+	api.SeverityInfo:     true,
+	api.SeverityLow:      true,
+	api.SeverityMedium:   true,
+	api.SeverityHigh:     true,
+	api.SeverityCritical: true,
+}
+
+func loadThreatSigPublicKey(path string) (*ecdsa.PublicKey, error) {
+	if path == "" {
+		return nil, errors.New("Threat signature public key is not configured")
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("Invalid threat signature public key")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if key, ok := pub.(*ecdsa.PublicKey); ok {
+		return key, nil
+	}
+	return nil, errors.New("Threat signature public key is not an ECDSA key")
+}
+
+// Verify the package signature and return the bundle inside
+func verifyThreatSigPackage(pkg *api.RESTThreatSignaturePackage, key *ecdsa.PublicKey) (*api.RESTThreatSignatureBundle, error) {
+	data, err := base64.StdEncoding.DecodeString(pkg.Bundle)
+	if err != nil {
+		return nil, errors.New("Invalid bundle encoding")
+	}
+	sig, err := base64.StdEncoding.DecodeString(pkg.Signature)
+	if err != nil {
+		return nil, errors.New("Invalid signature encoding")
+	}
+	var rs struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(sig, &rs); err != nil {
+		return nil, errors.New("Invalid signature format")
+	}
+	h := sha256.Sum256(data)
+	if !ecdsa.Verify(key, h[:], rs.R, rs.S) {
+		return nil, errors.New("Bundle signature verification failed")
+	}
+
+	var bundle api.RESTThreatSignatureBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, errors.New("Invalid bundle format")
+	}
+	return &bundle, nil
+}
+
+// The download url is provided by the user. Only the https servers that are not in the internal networks are allowed,
+// so it cannot be used to reach the services inside the cluster
+func isThreatSigInternalIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsUnspecified() || ip.IsMulticast() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+		return true
+	}
+	for _, ipnet := range threatSigInternalNets {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkThreatSigURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return errThreatSigURL
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if isThreatSigInternalIP(ip) {
+			return errThreatSigURL
+		}
+	} else if ips, err := net.LookupIP(host); err == nil {
+		for _, ip := range ips {
+			if isThreatSigInternalIP(ip) {
+				return errThreatSigURL
+			}
+		}
+	}
+	return nil
+}
+
+func downloadThreatSigPackage(rawURL string) (*api.RESTThreatSignaturePackage, error) {
+	if err := checkThreatSigURL(rawURL); err != nil {
+		return nil, err
+	}
+
+	client, proxyUrlStr, _ := createHttpClient(const_https_proxy, threatSigDownloadTimeout)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse // not to follow the redirects to other servers
+	}
+	if transport, ok := client.Transport.(*http.Transport); ok && proxyUrlStr == "" {
+		// the resolved address is checked again when connecting to the server directly
+		dialer := &net.Dialer{Timeout: threatSigDownloadTimeout}
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, addr)
+			if err == nil {
+				if tcpAddr, ok := conn.RemoteAddr().(*net.TCPAddr); ok && isThreatSigInternalIP(tcpAddr.IP) {
+					conn.Close()
+					return nil, errThreatSigURL
+				}
+			}
+			return conn, err
+		}
+	}
+
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(_headerProxy, proxyUrlStr)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to download the package: %s", resp.Status)
+	}
+	if resp.ContentLength > threatSigPackageMaxSize {
+		return nil, errors.New("Package is too large")
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, threatSigPackageMaxSize+1))
+	if err != nil {
+		return nil, err
+	} else if len(body) > threatSigPackageMaxSize {
+		return nil, errors.New("Package is too large")
+	}
+	var pkg api.RESTThreatSignaturePackage
+	if err := json.Unmarshal(body, &pkg); err != nil {
+		return nil, errors.New("Invalid package format")
+	}
+	return &pkg, nil
+}
+
+// A mining pool is an ip, a cidr or a fqdn, which can start with a wildcard
+func isValidMiningPool(pool string) bool {
+	if net.ParseIP(pool) != nil {
+		return true
+	}
+	if _, _, err := net.ParseCIDR(pool); err == nil {
+		return true
+	}
+	return threatSigFqdnRegex.MatchString(strings.TrimPrefix(pool, "*."))
+}
+
+func validateThreatSigBundle(bundle *api.RESTThreatSignatureBundle) error {
+	ids := make(map[uint32]bool, len(bundle.Signatures))
+	rules := make([]api.RESTWafRule, 0, len(bundle.Signatures))
+	for _, sig := range bundle.Signatures {
+		if sig.ID < api.MinThreatSigID || sig.ID >= api.MaxThreatSigID {
+			return fmt.Errorf("threat signature %s: id %d out of range", sig.Name, sig.ID)
+		}
+		if ids[sig.ID] {
+			return fmt.Errorf("threat signature %s: duplicate id %d", sig.Name, sig.ID)
+		}
+		ids[sig.ID] = true
+		if !threatSigSeverities[sig.Severity] {
+			return fmt.Errorf("threat signature %s: invalid severity %s", sig.Name, sig.Severity)
+		}
+		if sig.Action != share.DlpRuleActionAllow && sig.Action != share.DlpRuleActionDrop {
+			return fmt.Errorf("threat signature %s: invalid action %s", sig.Name, sig.Action)
+		}
+		rules = append(rules, api.RESTWafRule{Name: sig.Name, ID: sig.ID, Patterns: sig.Patterns})
+	}
+	for _, pool := range bundle.MiningPools {
+		if !isValidMiningPool(pool) {
+			return fmt.Errorf("invalid mining pool %s", pool)
+		}
+	}
+	// Signatures are matched by the same engine as waf rules
+	return validateWafRuleConfig(rules)
+}
+
+// A bundle can only be upgraded, so an older bundle signed by the same key cannot roll back the signatures
+func checkThreatSigVersion(bundle *api.RESTThreatSignatureBundle, cbundle *share.CLUSThreatSignatureBundle) error {
+	ver, err := version.NewVersion(bundle.Version)
+	if err != nil {
+		return fmt.Errorf("invalid bundle version %s", bundle.Version)
+	}
+	if cbundle == nil {
+		return nil
+	}
+	cver, err := version.NewVersion(cbundle.Version)
+	if err != nil {
+		log.WithFields(log.Fields{"version": cbundle.Version}).Error("Invalid version of the current bundle")
+		return nil
+	}
+	if !ver.GreaterThan(cver) {
+		return fmt.Errorf("bundle version %s is not newer than the current version %s", bundle.Version, cbundle.Version)
+	}
+	return nil
+}
+
+func handlerThreatSigList(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	data, err := cacher.GetThreatSignatures(acc)
+	if data == nil {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+	restRespSuccess(w, r, data, acc, login, nil, "Get threat signature list")
+}
+
+func handlerThreatSigImport(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSThreatSignatureBundle{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	var rdata api.RESTThreatSignatureImportData
+	err := json.Unmarshal(body, &rdata)
+	if err != nil || (rdata.Package == nil && rdata.URL == "") {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	key, err := loadThreatSigPublicKey(cctx.ThreatSigKeyFile)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to load public key")
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrFailImport, err.Error())
+		return
+	}
+
+	pkg := rdata.Package
+	if pkg == nil {
+		if pkg, err = downloadThreatSigPackage(rdata.URL); err != nil {
+			log.WithFields(log.Fields{"url": rdata.URL, "error": err}).Error("Failed to download")
+			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrFailImport, err.Error())
+			return
+		}
+	}
+
+	bundle, err := verifyThreatSigPackage(pkg, key)
+	if err == nil {
+		err = validateThreatSigBundle(bundle)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Invalid threat signature bundle")
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrFailImport, err.Error())
+		return
+	}
+
+	lock, err := lockClusKey(w, share.CLUSLockPolicyKey)
+	if err != nil {
+		return
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	// Signatures disabled by the user stay disabled when the bundle is updated
+	disabled := make(map[uint32]bool)
+	cbundle, rev := clusHelper.GetThreatSignatureBundleRev(acc)
+	if err := checkThreatSigVersion(bundle, cbundle); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Invalid threat signature bundle")
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrFailImport, err.Error())
+		return
+	}
+	if cbundle != nil {
+		for _, sig := range cbundle.Signatures {
+			if sig.Disable {
+				disabled[sig.ID] = true
+			}
+		}
+	}
+
+	nbundle := share.CLUSThreatSignatureBundle{
+		Version:     bundle.Version,
+		ImportedAt:  time.Now().UTC(),
+		Signatures:  make([]*share.CLUSThreatSignature, len(bundle.Signatures)),
+		MiningPools: bundle.MiningPools,
+	}
+	for i, sig := range bundle.Signatures {
+		csig := &share.CLUSThreatSignature{
+			ID:       sig.ID,
+			Name:     sig.Name,
+			Comment:  sig.Comment,
+			Severity: sig.Severity,
+			Action:   sig.Action,
+			Patterns: make([]share.CLUSWafCriteriaEntry, len(sig.Patterns)),
+			Disable:  disabled[sig.ID],
+		}
+		for j, pt := range sig.Patterns {
+			csig.Patterns[j] = share.CLUSWafCriteriaEntry{Key: pt.Key, Value: pt.Value, Op: pt.Op, Context: pt.Context}
+		}
+		nbundle.Signatures[i] = csig
+	}
+
+	if err := clusHelper.PutThreatSignatureBundleRev(&nbundle, rev); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error()
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+
+	msg := fmt.Sprintf("Import threat signature bundle %s", bundle.Version)
+	restRespSuccess(w, r, nil, acc, login, nil, msg)
+}
+
+func handlerThreatSigConfig(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSThreatSignatureBundle{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	id, err := strconv.ParseUint(ps.ByName("id"), 10, 32)
+	if err != nil {
+		log.WithFields(log.Fields{"id": ps.ByName("id")}).Error("Invalid ID")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	var rconf api.RESTThreatSignatureConfigData
+	err = json.Unmarshal(body, &rconf)
+	if err != nil || rconf.Config == nil {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	lock, err := lockClusKey(w, share.CLUSLockPolicyKey)
+	if err != nil {
+		return
+	}
+	defer clusHelper.ReleaseLock(lock)
+
+	bundle, rev := clusHelper.GetThreatSignatureBundleRev(acc)
+	if bundle == nil {
+		// the bundle type is authorized above, so it hasn't been imported yet
+		restRespNotFoundLogAccessDenied(w, login, common.ErrObjectNotFound)
+		return
+	}
+
+	var sig *share.CLUSThreatSignature
+	for _, s := range bundle.Signatures {
+		if s.ID == uint32(id) {
+			sig = s
+			break
+		}
+	}
+	if sig == nil {
+		restRespErrorMessage(w, http.StatusNotFound, api.RESTErrObjectNotFound, "Threat signature not found")
+		return
+	}
+
+	if rconf.Config.Enable != nil {
+		sig.Disable = !*rconf.Config.Enable
+	}
+
+	if err := clusHelper.PutThreatSignatureBundleRev(bundle, rev); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error()
+		restRespError(w, http.StatusInternalServerError, api.RESTErrFailWriteCluster)
+		return
+	}
+
+	restRespSuccess(w, r, nil, acc, login, &rconf, "Configure threat signature")
+}
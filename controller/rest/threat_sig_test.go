@@ -0,0 +1,197 @@
+package rest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+)
+
+func signThreatSigBundle(t *testing.T, key *ecdsa.PrivateKey, bundle *api.RESTThreatSignatureBundle) *api.RESTThreatSignaturePackage {
+	data, _ := json.Marshal(bundle)
+	h := sha256.Sum256(data)
+	r, s, err := ecdsa.Sign(rand.Reader, key, h[:])
+	if err != nil {
+		t.Fatalf("Failed to sign bundle: %v", err)
+	}
+	sig, _ := asn1.Marshal(struct{ R, S interface{} }{r, s})
+	return &api.RESTThreatSignaturePackage{
+		Bundle:    base64.StdEncoding.EncodeToString(data),
+		Signature: base64.StdEncoding.EncodeToString(sig),
+	}
+}
+
+func TestThreatSigPackageVerify(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	bundle := &api.RESTThreatSignatureBundle{
+		Version: "2022.01",
+		Signatures: []*api.RESTThreatSignature{
+			&api.RESTThreatSignature{
+				ID: api.MinThreatSigID + 1, Name: "Log4j.JNDI", Severity: api.SeverityCritical, Action: share.DlpRuleActionDrop,
+				Patterns: []api.RESTWafCriteriaEntry{
+					api.RESTWafCriteriaEntry{Key: "pattern", Op: share.CriteriaOpRegex, Value: "\\$\\{jndi:", Context: share.DlpPatternContextHEAD},
+				},
+			},
+		},
+	}
+	pkg := signThreatSigBundle(t, key, bundle)
+
+	if b, err := verifyThreatSigPackage(pkg, &key.PublicKey); err != nil {
+		t.Errorf("Failed to verify signed bundle: %v", err)
+	} else if b.Version != bundle.Version || len(b.Signatures) != 1 || b.Signatures[0].ID != bundle.Signatures[0].ID {
+		t.Errorf("Unexpected bundle: %+v", b)
+	}
+
+	if _, err := verifyThreatSigPackage(pkg, &other.PublicKey); err == nil {
+		t.Errorf("Bundle should not be verified by another key")
+	}
+
+	// Tamper the bundle content
+	tampered := *pkg
+	bundle.Signatures[0].Action = share.DlpRuleActionAllow
+	data, _ := json.Marshal(bundle)
+	tampered.Bundle = base64.StdEncoding.EncodeToString(data)
+	if _, err := verifyThreatSigPackage(&tampered, &key.PublicKey); err == nil {
+		t.Errorf("Tampered bundle should not be verified")
+	}
+}
+
+func TestThreatSigBundleValidate(t *testing.T) {
+	newSig := func(id uint32) *api.RESTThreatSignature {
+		return &api.RESTThreatSignature{
+			ID: id, Name: "sig", Severity: api.SeverityHigh, Action: share.DlpRuleActionDrop,
+			Patterns: []api.RESTWafCriteriaEntry{
+				api.RESTWafCriteriaEntry{Key: "pattern", Op: share.CriteriaOpRegex, Value: "abc"},
+			},
+		}
+	}
+
+	bundle := &api.RESTThreatSignatureBundle{Signatures: []*api.RESTThreatSignature{newSig(api.MinWafRuleID)}}
+	if err := validateThreatSigBundle(bundle); err == nil {
+		t.Errorf("ID out of range should fail")
+	}
+
+	bundle = &api.RESTThreatSignatureBundle{Signatures: []*api.RESTThreatSignature{newSig(api.MinThreatSigID), newSig(api.MinThreatSigID)}}
+	if err := validateThreatSigBundle(bundle); err == nil {
+		t.Errorf("Duplicate ID should fail")
+	}
+
+	sig := newSig(api.MinThreatSigID)
+	sig.Severity = "urgent"
+	bundle = &api.RESTThreatSignatureBundle{Signatures: []*api.RESTThreatSignature{sig}}
+	if err := validateThreatSigBundle(bundle); err == nil {
+		t.Errorf("Invalid severity should fail")
+	}
+
+	sig = newSig(api.MinThreatSigID)
+	sig.Patterns = nil
+	bundle = &api.RESTThreatSignatureBundle{Signatures: []*api.RESTThreatSignature{sig}}
+	if err := validateThreatSigBundle(bundle); err == nil {
+		t.Errorf("Signature without pattern should fail")
+	}
+
+	bundle = &api.RESTThreatSignatureBundle{
+		Signatures:  []*api.RESTThreatSignature{newSig(api.MinThreatSigID)},
+		MiningPools: []string{"1.2.3.4", "10.1.0.0/16", "pool.minexmr.com", "*.nanopool.org"},
+	}
+	if err := validateThreatSigBundle(bundle); err != nil {
+		t.Errorf("Valid mining pools should pass: %v", err)
+	}
+
+	bundle.MiningPools = []string{"pool minexmr"}
+	if err := validateThreatSigBundle(bundle); err == nil {
+		t.Errorf("Invalid mining pool should fail")
+	}
+}
+
+func TestThreatSigImportVersion(t *testing.T) {
+	preTest()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+	cacher = &mockCache{}
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	f, _ := ioutil.TempFile("", "threat_sig_key")
+	defer os.Remove(f.Name())
+	pem.Encode(f, &pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	f.Close()
+	cctx.ThreatSigKeyFile = f.Name()
+	defer func() { cctx.ThreatSigKeyFile = "" }()
+
+	importBundle := func(ver string) int {
+		bundle := &api.RESTThreatSignatureBundle{
+			Version: ver,
+			Signatures: []*api.RESTThreatSignature{
+				&api.RESTThreatSignature{
+					ID: api.MinThreatSigID, Name: "sig", Severity: api.SeverityHigh, Action: share.DlpRuleActionDrop,
+					Patterns: []api.RESTWafCriteriaEntry{
+						api.RESTWafCriteriaEntry{Key: "pattern", Op: share.CriteriaOpRegex, Value: "abc"},
+					},
+				},
+			},
+		}
+		body, _ := json.Marshal(&api.RESTThreatSignatureImportData{Package: signThreatSigBundle(t, key, bundle)})
+		return restCall("POST", "/v1/threat/signature/bundle", body, api.UserRoleAdmin).status
+	}
+
+	if status := importBundle("2022.01"); status != http.StatusOK {
+		t.Errorf("Failed to import bundle: status=%v", status)
+	}
+	if status := importBundle("2022.02"); status != http.StatusOK {
+		t.Errorf("Failed to import newer bundle: status=%v", status)
+	}
+
+	// replayed or older bundles cannot roll back the signatures
+	for _, ver := range []string{"2022.02", "2022.01", "", "latest"} {
+		if status := importBundle(ver); status != http.StatusBadRequest {
+			t.Errorf("Bundle version %s should be rejected: status=%v", ver, status)
+		}
+	}
+	if b, _ := clusHelper.GetThreatSignatureBundleRev(nil); b == nil || b.Version != "2022.02" {
+		t.Errorf("Unexpected bundle: %+v", b)
+	}
+
+	postTest()
+}
+
+func TestThreatSigDownloadURL(t *testing.T) {
+	for _, u := range []string{
+		"http://example.com/bundle.json",
+		"ftp://example.com/bundle.json",
+		"https://127.0.0.1/bundle.json",
+		"https://localhost:10443/bundle.json",
+		"https://169.254.169.254/latest/meta-data",
+		"https://10.1.2.3/bundle.json",
+		"https://[::1]/bundle.json",
+		"https:///bundle.json",
+	} {
+		if err := checkThreatSigURL(u); err == nil {
+			t.Errorf("URL %s should be rejected", u)
+		}
+		if _, err := downloadThreatSigPackage(u); err != errThreatSigURL {
+			t.Errorf("Download from %s should be rejected: %v", u, err)
+		}
+	}
+
+	if err := checkThreatSigURL("https://8.8.8.8/bundle.json"); err != nil {
+		t.Errorf("Public https URL should be allowed: %v", err)
+	}
+}
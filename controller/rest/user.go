@@ -231,6 +231,7 @@ func user2REST(user *share.CLUSUser) *api.RESTUser {
 		LastLoginTimeStamp: user.LastLoginAt.Unix(),
 		LastLoginAt:        api.RESTTimeString(user.LastLoginAt),
 		LoginCount:         user.LoginCount,
+		MFAEnabled:         user.MFAEnabled,
 	}
 }
 
@@ -1204,7 +1205,23 @@ func handlerApikeyCreate(w http.ResponseWriter, r *http.Request, ps httprouter.P
 		return
 	}
 
-	if access.ContainsNonSupportRole(rapikey.Role) {
+	scopes := utils.NewSet()
+	for _, scope := range rapikey.Scopes {
+		if !access.IsValidApikeyScope(scope) {
+			e := fmt.Sprintf("Invalid API key scope %s", scope)
+			log.WithFields(log.Fields{"login": login.fullname, "create": rapikey.Name}).Error(e)
+			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+			return
+		}
+		scopes.Add(scope)
+	}
+
+	// An API key can have fedAdmin role only when it's explicitly limited to the fed:admin scope
+	if scopes.Contains(api.ApikeyScopeFedAdmin) != (rapikey.Role == api.UserRoleFedAdmin) {
+		e := fmt.Sprintf("API key scope %s is only for role %s", api.ApikeyScopeFedAdmin, api.UserRoleFedAdmin)
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+		return
+	} else if rapikey.Role != api.UserRoleFedAdmin && access.ContainsNonSupportRole(rapikey.Role) {
 		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, "API key cannot bind to nonsupport roles")
 		return
 	}
@@ -1228,6 +1245,10 @@ func handlerApikeyCreate(w http.ResponseWriter, r *http.Request, ps httprouter.P
 		CreatedByEntity:  login.fullname,
 		SecretKeyHash:    utils.HashPassword(secretKey),
 	}
+	if scopes.Cardinality() > 0 {
+		apikey.Scopes = scopes.ToStringSlice()
+		sort.Strings(apikey.Scopes)
+	}
 	if !acc.AuthorizeOwn(&apikey, nil) {
 		log.WithFields(log.Fields{"login": login.fullname, "apikey": rapikey.Name}).Error(common.ErrObjectAccessDenied.Error())
 		restRespAccessDenied(w, login)
@@ -1382,6 +1403,8 @@ func apikey2REST(apikey *share.CLUSApikey) *api.RESTApikey {
 		ExpirationTimestamp: apikey.ExpirationTimestamp,
 		CreatedTimestamp:    apikey.CreatedTimestamp,
 		CreatedByEntity:     apikey.CreatedByEntity,
+		Scopes:              apikey.Scopes,
+		LastUsedTimestamp:   apikey.LastUsedTimestamp,
 	}
 }
 
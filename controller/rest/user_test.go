@@ -638,3 +638,61 @@ func TestApikeyCreateDelete(t *testing.T) {
 
 	postTest()
 }
+
+func TestApikeyCreateScopes(t *testing.T) {
+	preTest()
+
+	accAdmin := access.NewAdminAccessControl()
+
+	var mockCluster kv.MockCluster
+	mockCluster.Init(nil, nil)
+	clusHelper = &mockCluster
+	cacher = &mockCache{}
+
+	data := api.RESTApikeyCreationData{Apikey: &api.RESTApikeyCreation{
+		ExpirationType: "never",
+		Name:           "token-scope",
+		Role:           api.UserRoleReader,
+		Scopes:         []string{api.ApikeyScopeScanRead, "scan:write"},
+	}}
+	body, _ := json.Marshal(data)
+	w := restCall("POST", "/v1/api_key", body, api.UserRoleAdmin)
+	if w.status != http.StatusBadRequest {
+		t.Errorf("Invalid apikey scope should be rejected: status=%v.", w.status)
+	}
+
+	// fed:admin scope is only for fedAdmin role
+	data.Apikey.Scopes = []string{api.ApikeyScopeFedAdmin}
+	body, _ = json.Marshal(data)
+	w = restCall("POST", "/v1/api_key", body, api.UserRoleAdmin)
+	if w.status != http.StatusBadRequest {
+		t.Errorf("fed:admin scope for reader role should be rejected: status=%v.", w.status)
+	}
+
+	data.Apikey.Scopes = []string{api.ApikeyScopeScanRead, api.ApikeyScopeScanRead}
+	body, _ = json.Marshal(data)
+	w = restCall("POST", "/v1/api_key", body, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to create apikey: status=%v.", w.status)
+	}
+
+	apikey, _, _ := clusHelper.GetApikeyRev("token-scope", accAdmin)
+	if apikey == nil {
+		t.Fatalf("Failed to locate apikey in cluster")
+	}
+	if len(apikey.Scopes) != 1 || apikey.Scopes[0] != api.ApikeyScopeScanRead {
+		t.Errorf("Incorrect apikey scopes in cluster: scopes=%v", apikey.Scopes)
+	}
+
+	var resp api.RESTApikeyData
+	w = restCall("GET", "/v1/api_key/token-scope", nil, api.UserRoleAdmin)
+	if w.status != http.StatusOK {
+		t.Fatalf("Failed to get apikey: status=%v.", w.status)
+	}
+	json.Unmarshal(w.body, &resp)
+	if resp.Apikey == nil || len(resp.Apikey.Scopes) != 1 || resp.Apikey.LastUsedTimestamp != 0 {
+		t.Errorf("Incorrect apikey in rest: apikey=%+v", resp.Apikey)
+	}
+
+	postTest()
+}
@@ -141,6 +141,16 @@ func checkVulnerabilityProfileEntry(e *api.RESTVulnerabilityProfileEntry) (*shar
 	return &ce, nil
 }
 
+// Entries defined by crd cannot be modified by rest api, and entry with the same name cannot be added.
+func checkCrdVulnerabilityProfileEntry(cvp *share.CLUSVulnerabilityProfile, re *api.RESTVulnerabilityProfileEntry) error {
+	for _, ce := range cvp.Entries {
+		if ce.CfgType == share.GroundCfg && (ce.ID == re.ID || ce.Name == re.Name) {
+			return fmt.Errorf("Entry %s is defined by CRD and cannot be configured", ce.Name)
+		}
+	}
+	return nil
+}
+
 func configVulnerabilityProfileEntry(cvp *share.CLUSVulnerabilityProfile, re *api.RESTVulnerabilityProfileEntry, create bool) error {
 	ids := utils.NewSet()
 	for _, ce := range cvp.Entries {
@@ -151,6 +161,9 @@ func configVulnerabilityProfileEntry(cvp *share.CLUSVulnerabilityProfile, re *ap
 	if err != nil {
 		return err
 	}
+	if err := checkCrdVulnerabilityProfileEntry(cvp, re); err != nil {
+		return err
+	}
 
 	if create {
 		if re.ID == 0 {
@@ -183,13 +196,25 @@ func configVulnerabilityProfileEntry(cvp *share.CLUSVulnerabilityProfile, re *ap
 	return nil
 }
 
-func configVulnerabilityProfile(rvp *api.RESTVulnerabilityProfileConfig) (*share.CLUSVulnerabilityProfile, error) {
+// Entries defined by crd are kept
+func configVulnerabilityProfile(old *share.CLUSVulnerabilityProfile, rvp *api.RESTVulnerabilityProfileConfig) (*share.CLUSVulnerabilityProfile, error) {
 	cvp := &share.CLUSVulnerabilityProfile{Name: rvp.Name, Entries: make([]*share.CLUSVulnerabilityProfileEntry, 0)}
+	for _, ce := range old.Entries {
+		if ce.CfgType == share.GroundCfg {
+			cvp.Entries = append(cvp.Entries, ce)
+		}
+	}
 
 	if rvp.Entries != nil {
 		ids := utils.NewSet()
+		for _, ce := range cvp.Entries {
+			ids.Add(ce.ID)
+		}
 		for _, re := range *rvp.Entries {
 			if ids.Contains(re.ID) {
+				if err := checkCrdVulnerabilityProfileEntry(cvp, re); err != nil {
+					return nil, err
+				}
 				return nil, errors.New("Duplicate entry ID")
 			}
 			if re.ID != 0 {
@@ -201,6 +226,9 @@ func configVulnerabilityProfile(rvp *api.RESTVulnerabilityProfileConfig) (*share
 			if err != nil {
 				return nil, err
 			}
+			if err := checkCrdVulnerabilityProfileEntry(cvp, re); err != nil {
+				return nil, err
+			}
 
 			if re.ID == 0 {
 				re.ID = getAvailableRuleID(ruleTypeVulProf, ids, share.UserCreated)
@@ -264,7 +292,7 @@ func handlerVulnerabilityProfileConfig(w http.ResponseWriter, r *http.Request, p
 		}
 
 		// clean up current entries
-		if cvp, err = configVulnerabilityProfile(rvp); err != nil {
+		if cvp, err = configVulnerabilityProfile(cvp, rvp); err != nil {
 			log.WithFields(log.Fields{"error": err}).Error("Failed to configure vulnerability profile")
 			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
 			return
@@ -471,6 +499,12 @@ func handlerVulnerabilityProfileEntryDelete(w http.ResponseWriter, r *http.Reque
 		var deled bool
 		for i, ce := range cvp.Entries {
 			if ce.ID == uint32(id) {
+				if ce.CfgType == share.GroundCfg {
+					e := "Entry defined by CRD cannot be deleted"
+					log.WithFields(log.Fields{"name": name, "id": id}).Error(e)
+					restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrOpNotAllowed, e)
+					return
+				}
 				copy(cvp.Entries[i:], cvp.Entries[i+1:])
 				cvp.Entries[len(cvp.Entries)-1] = nil
 				cvp.Entries = cvp.Entries[:len(cvp.Entries)-1]
@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/controller/kv"
+	"github.com/neuvector/neuvector/share"
+)
+
+type vulProfileMockCluster struct {
+	kv.MockCluster
+	vp *share.CLUSVulnerabilityProfile
+}
+
+func (m *vulProfileMockCluster) GetVulnerabilityProfile(name string, acc *access.AccessControl) (*share.CLUSVulnerabilityProfile, uint64, error) {
+	clone := *m.vp
+	clone.Entries = append([]*share.CLUSVulnerabilityProfileEntry{}, m.vp.Entries...)
+	return &clone, 0, nil
+}
+
+func (m *vulProfileMockCluster) PutVulnerabilityProfile(vp *share.CLUSVulnerabilityProfile, rev uint64) error {
+	m.vp = vp
+	return nil
+}
+
+func TestVulnerabilityProfileCrdEntry(t *testing.T) {
+	preTest()
+
+	var mockCluster vulProfileMockCluster
+	mockCluster.Init(nil, nil)
+	mockCluster.vp = &share.CLUSVulnerabilityProfile{
+		Name: share.DefaultVulnerabilityProfileName,
+		Entries: []*share.CLUSVulnerabilityProfileEntry{
+			{ID: 1, Name: "CVE-2021-0001", CfgType: share.UserCreated},
+		},
+	}
+	clusHelper = &mockCluster
+
+	var crdHandler nvCrdHandler
+	crdHandler.Init(share.CLUSLockPolicyKey)
+
+	// Crd conflicting with the user entry is rejected
+	crdCfg := api.RESTVulnerabilityProfileConfig{
+		Name: share.DefaultVulnerabilityProfileName,
+		Entries: &[]*api.RESTVulnerabilityProfileEntry{
+			{Name: "CVE-2021-0001", Comment: "crd"},
+		},
+	}
+	if err := crdHandler.crdHandleVulnProfileEntries(&crdCfg); err == nil {
+		t.Errorf("Crd vulnerability profile conflicting with user entry should fail\n")
+	}
+	if vp := mockCluster.vp; len(vp.Entries) != 1 || vp.Entries[0].CfgType != share.UserCreated || vp.Entries[0].Comment != "" {
+		t.Errorf("User vulnerability profile entry is changed: %+v\n", vp.Entries)
+	}
+
+	crdCfg.Entries = &[]*api.RESTVulnerabilityProfileEntry{
+		{Name: "CVE-2021-0002", Comment: "crd"},
+	}
+	if err := crdHandler.crdHandleVulnProfileEntries(&crdCfg); err != nil {
+		t.Errorf("Failed to apply crd vulnerability profile: %v\n", err)
+	}
+	if vp := mockCluster.vp; len(vp.Entries) != 2 || vp.Entries[1].Name != "CVE-2021-0002" || vp.Entries[1].CfgType != share.GroundCfg {
+		t.Errorf("Crd vulnerability profile entry is not configured correctly: %+v\n", vp.Entries)
+	}
+
+	// Entries defined by crd are removed with the crd, the user entry is kept
+	if err := crdHandler.crdHandleVulnProfileEntries(nil); err != nil {
+		t.Errorf("Failed to remove crd vulnerability profile: %v\n", err)
+	}
+	if vp := mockCluster.vp; len(vp.Entries) != 1 || vp.Entries[0].Name != "CVE-2021-0001" {
+		t.Errorf("Crd vulnerability profile is not removed: %+v\n", vp.Entries)
+	}
+
+	postTest()
+}
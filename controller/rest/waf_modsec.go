@@ -0,0 +1,455 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/controller/access"
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// Convert the SecRule directives of ModSecurity, such as the OWASP Core Rule Set, to waf rules. Only a subset can be
+// converted: the operators that can be written as a regex and the variables that can be mapped to a pattern context.
+// The transformations are not applied, as the patterns are always matched case-insensitively. A chain of rules is
+// converted to one waf rule with multiple patterns, because all patterns of a waf rule must be matched. The other
+// directives, such as SecAction and SecMarker, are ignored.
+
+const modSecRuleNamePrefix string = "modsec_"
+
+var modSecSeverities map[string]int = map[string]int{
+	"emergency": 0, "alert": 1, "critical": 2, "error": 3, "warning": 4, "notice": 5, "info": 6, "debug": 7,
+}
+
+// The variables that appear in both the url and the body are matched in the whole packet
+var modSecVarContexts map[string][]string = map[string][]string{
+	"REQUEST_URI":            []string{share.DlpPatternContextURI},
+	"REQUEST_URI_RAW":        []string{share.DlpPatternContextURI},
+	"REQUEST_FILENAME":       []string{share.DlpPatternContextURI},
+	"REQUEST_BASENAME":       []string{share.DlpPatternContextURI},
+	"REQUEST_LINE":           []string{share.DlpPatternContextURI},
+	"QUERY_STRING":           []string{share.DlpPatternContextURI},
+	"ARGS_GET":               []string{share.DlpPatternContextURI},
+	"ARGS_GET_NAMES":         []string{share.DlpPatternContextURI},
+	"REQUEST_HEADERS":        []string{share.DlpPatternContextHEAD},
+	"REQUEST_HEADERS_NAMES":  []string{share.DlpPatternContextHEAD},
+	"REQUEST_COOKIES":        []string{share.DlpPatternContextHEAD},
+	"REQUEST_COOKIES_NAMES":  []string{share.DlpPatternContextHEAD},
+	"RESPONSE_HEADERS":       []string{share.DlpPatternContextHEAD},
+	"RESPONSE_HEADERS_NAMES": []string{share.DlpPatternContextHEAD},
+	"REQUEST_BODY":           []string{share.DlpPatternContextBODY},
+	"ARGS_POST":              []string{share.DlpPatternContextBODY},
+	"ARGS_POST_NAMES":        []string{share.DlpPatternContextBODY},
+	"XML":                    []string{share.DlpPatternContextBODY},
+	"FILES":                  []string{share.DlpPatternContextBODY},
+	"FILES_NAMES":            []string{share.DlpPatternContextBODY},
+	"RESPONSE_BODY":          []string{share.DlpPatternContextBODY},
+	"ARGS":                   []string{share.DlpPatternContextURI, share.DlpPatternContextBODY},
+	"ARGS_NAMES":             []string{share.DlpPatternContextURI, share.DlpPatternContextBODY},
+}
+
+type modSecRule struct {
+	vars     string
+	operator string
+	actions  map[string]string
+}
+
+type modSecChain struct {
+	rules []*modSecRule
+	err   error
+}
+
+func modSecSeverity(severity string) (int, bool) {
+	if level, ok := modSecSeverities[strings.ToLower(severity)]; ok {
+		return level, true
+	} else if level, err := strconv.Atoi(severity); err == nil && level >= 0 && level <= 7 {
+		return level, true
+	}
+	return 0, false
+}
+
+// Join the continued lines. The comments and empty lines are removed
+func modSecDirectives(text string) []string {
+	list := make([]string, 0)
+	cont := ""
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if cont == "" {
+			line = strings.TrimLeft(line, " \t")
+			if line == "" || line[0] == '#' {
+				continue
+			}
+		}
+		if strings.HasSuffix(line, "\\") {
+			cont += line[:len(line)-1]
+			continue
+		}
+		list = append(list, cont+line)
+		cont = ""
+	}
+	if cont != "" {
+		list = append(list, cont)
+	}
+	return list
+}
+
+// Split the directive by the spaces outside the double quotes. Only the escaped double quote is unescaped, so the
+// escape sequences of the regex are kept.
+func modSecArgs(directive string) ([]string, error) {
+	args := make([]string, 0)
+	var arg strings.Builder
+	inArg, inQuote := false, false
+	for i := 0; i < len(directive); i++ {
+		c := directive[i]
+		switch {
+		case inQuote:
+			if c == '\\' && i+1 < len(directive) && directive[i+1] == '"' {
+				arg.WriteByte('"')
+				i++
+			} else if c == '"' {
+				inQuote = false
+			} else {
+				arg.WriteByte(c)
+			}
+		case c == '"':
+			inArg, inQuote = true, true
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// Split the actions by the commas outside the single quotes. For the repeated actions, such as the
+// transformations, only the last one is kept.
+func modSecActions(text string) map[string]string {
+	actions := make(map[string]string)
+	var item strings.Builder
+	add := func() {
+		kv := strings.SplitN(item.String(), ":", 2)
+		if name := strings.ToLower(strings.TrimSpace(kv[0])); name != "" {
+			value := ""
+			if len(kv) == 2 {
+				value = strings.Trim(strings.TrimSpace(kv[1]), "'")
+			}
+			actions[name] = value
+		}
+		item.Reset()
+	}
+
+	inQuote := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c == '\'' {
+			inQuote = !inQuote
+		} else if c == ',' && !inQuote {
+			add()
+			continue
+		}
+		item.WriteByte(c)
+	}
+	add()
+	return actions
+}
+
+func modSecContext(vars string) (string, error) {
+	contexts := utils.NewSet()
+	for _, v := range strings.Split(vars, "|") {
+		v = strings.TrimSpace(v)
+		if v == "" || v[0] == '!' {
+			// exclusions only make the match narrower
+			continue
+		} else if v[0] == '&' {
+			return "", fmt.Errorf("unsupported variable count (%s)", v)
+		}
+		name := strings.ToUpper(strings.SplitN(v, ":", 2)[0])
+		if ctxs, ok := modSecVarContexts[name]; !ok {
+			return "", fmt.Errorf("unsupported variable (%s)", name)
+		} else {
+			for _, ctx := range ctxs {
+				contexts.Add(ctx)
+			}
+		}
+	}
+
+	switch contexts.Cardinality() {
+	case 0:
+		return "", fmt.Errorf("variable is missing")
+	case 1:
+		return contexts.Any().(string), nil
+	}
+	return share.DlpPatternContextPACKET, nil
+}
+
+// Return the regex and the criteria op of the operator
+func modSecPattern(operator string) (string, string, error) {
+	op := share.CriteriaOpRegex
+	if strings.HasPrefix(operator, "!") {
+		op = share.CriteriaOpNotRegex
+		operator = operator[1:]
+	}
+
+	name, param := "@rx", operator
+	if strings.HasPrefix(operator, "@") {
+		kv := strings.SplitN(operator, " ", 2)
+		name, param = strings.ToLower(kv[0]), ""
+		if len(kv) == 2 {
+			param = strings.TrimLeft(kv[1], " \t")
+		}
+	}
+	if param == "" {
+		return "", "", fmt.Errorf("operator parameter is missing (%s)", name)
+	} else if strings.Contains(param, "%{") {
+		return "", "", fmt.Errorf("unsupported macro in operator (%s)", name)
+	}
+
+	quoted := regexp.QuoteMeta(param)
+	switch name {
+	case "@rx":
+		return param, op, nil
+	case "@pm":
+		words := strings.Fields(param)
+		for i, word := range words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+		return fmt.Sprintf("(?:%s)", strings.Join(words, "|")), op, nil
+	case "@contains":
+		return quoted, op, nil
+	case "@containsword":
+		return fmt.Sprintf(`\b%s\b`, quoted), op, nil
+	case "@beginswith":
+		return "^" + quoted, op, nil
+	case "@endswith":
+		return quoted + "$", op, nil
+	case "@streq":
+		return fmt.Sprintf("^%s$", quoted), op, nil
+	}
+	return "", "", fmt.Errorf("unsupported operator (%s)", name)
+}
+
+// Group the chained rules. A directive that cannot be parsed breaks the chain it belongs to.
+func modSecChains(text string) []*modSecChain {
+	chains := make([]*modSecChain, 0)
+	var cur *modSecChain
+	for _, d := range modSecDirectives(text) {
+		args, err := modSecArgs(d)
+		if err == nil && (len(args) == 0 || !strings.EqualFold(args[0], "SecRule")) {
+			if cur != nil {
+				cur.err = fmt.Errorf("chained rule is missing")
+				cur = nil
+			}
+			continue
+		}
+		if cur == nil {
+			cur = &modSecChain{}
+			chains = append(chains, cur)
+		}
+		if err == nil && len(args) != 3 && len(args) != 4 {
+			err = fmt.Errorf("invalid number of arguments (%d)", len(args)-1)
+		}
+		if err != nil {
+			cur.err = err
+			cur = nil
+			continue
+		}
+
+		rule := &modSecRule{vars: args[1], operator: args[2], actions: make(map[string]string)}
+		if len(args) == 4 {
+			rule.actions = modSecActions(args[3])
+		}
+		cur.rules = append(cur.rules, rule)
+		if _, ok := rule.actions["chain"]; !ok {
+			cur = nil
+		}
+	}
+	if cur != nil {
+		cur.err = fmt.Errorf("chained rule is missing")
+	}
+	return chains
+}
+
+// The id, severity and disruptive action are taken from the first rule of the chain. minLevel is negative
+// if the rules are not filtered by severity.
+func convertModSecChain(chain *modSecChain, minLevel int) (*api.RESTWafRule, string, error) {
+	var id string
+	if len(chain.rules) > 0 {
+		id = chain.rules[0].actions["id"]
+	}
+	if chain.err != nil {
+		return nil, id, chain.err
+	} else if id == "" {
+		return nil, id, fmt.Errorf("rule id is missing")
+	} else if _, err := strconv.ParseUint(id, 10, 32); err != nil {
+		return nil, id, fmt.Errorf("invalid rule id")
+	}
+
+	first := chain.rules[0].actions
+	if _, ok := first["allow"]; ok {
+		return nil, id, fmt.Errorf("unsupported action (allow)")
+	}
+	if minLevel >= 0 {
+		if severity, ok := first["severity"]; !ok {
+			return nil, id, fmt.Errorf("severity is missing")
+		} else if level, ok := modSecSeverity(severity); !ok {
+			return nil, id, fmt.Errorf("invalid severity (%s)", severity)
+		} else if level > minLevel {
+			return nil, id, fmt.Errorf("severity is lower than required (%s)", severity)
+		}
+	}
+
+	rule := &api.RESTWafRule{
+		Name:     modSecRuleNamePrefix + id,
+		Patterns: make([]api.RESTWafCriteriaEntry, 0, len(chain.rules)),
+		CfgType:  api.CfgTypeUserCreated,
+	}
+	hasRegex := false
+	for _, r := range chain.rules {
+		ctx, err := modSecContext(r.vars)
+		if err != nil {
+			return nil, id, err
+		}
+		value, op, err := modSecPattern(r.operator)
+		if err != nil {
+			return nil, id, err
+		}
+		if op == share.CriteriaOpRegex {
+			hasRegex = true
+		}
+		rule.Patterns = append(rule.Patterns, api.RESTWafCriteriaEntry{Key: share.DlpRuleKeyPattern, Value: value, Op: op, Context: ctx})
+	}
+	if !hasRegex {
+		return nil, id, fmt.Errorf("negated operators only")
+	}
+	return rule, id, nil
+}
+
+func convertModSecRules(text, minSeverity string) ([]*api.RESTWafRule, []*api.RESTWafModSecSkipped, error) {
+	minLevel := -1
+	if minSeverity != "" {
+		level, ok := modSecSeverity(minSeverity)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid severity (%s)", minSeverity)
+		}
+		minLevel = level
+	}
+
+	rules := make([]*api.RESTWafRule, 0)
+	skipped := make([]*api.RESTWafModSecSkipped, 0)
+	for _, chain := range modSecChains(text) {
+		if rule, id, err := convertModSecChain(chain, minLevel); err != nil {
+			skipped = append(skipped, &api.RESTWafModSecSkipped{ID: id, Reason: err.Error()})
+		} else {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, skipped, nil
+}
+
+// Convert the rules and move the ones that don't pass the waf rule validation, e.g. too long, to the skipped list
+func convertValidModSecRules(conf *api.RESTWafModSec) ([]*api.RESTWafRule, []*api.RESTWafModSecSkipped, error) {
+	converted, skipped, err := convertModSecRules(conf.Rules, conf.MinSeverity)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Invalid modsecurity config")
+		return nil, nil, err
+	}
+
+	rules := make([]*api.RESTWafRule, 0, len(converted))
+	for _, rule := range converted {
+		if err := validateWafRuleConfig([]api.RESTWafRule{*rule}); err != nil {
+			id := strings.TrimPrefix(rule.Name, modSecRuleNamePrefix)
+			skipped = append(skipped, &api.RESTWafModSecSkipped{ID: id, Reason: err.Error()})
+		} else {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, skipped, nil
+}
+
+// Add the converted rules to the replace list if it's used, otherwise to the change list
+func applyWafModSec(conf *api.RESTWafSensorConfig) error {
+	if conf.ModSec == nil {
+		return nil
+	}
+
+	converted, skipped, err := convertValidModSecRules(conf.ModSec)
+	if err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{"sensor": conf.Name, "converted": len(converted), "skipped": len(skipped)}).Info("Convert modsecurity rules")
+	if len(converted) == 0 {
+		return fmt.Errorf("no modsecurity rule can be converted")
+	}
+
+	rules := conf.Rules
+	if rules == nil {
+		if conf.RuleChgList == nil {
+			list := make([]api.RESTWafRule, 0)
+			conf.RuleChgList = &list
+		}
+		rules = conf.RuleChgList
+	}
+
+NEXT_RULE:
+	for _, rule := range converted {
+		for i := range *rules {
+			if (*rules)[i].Name == rule.Name {
+				(*rules)[i] = *rule
+				continue NEXT_RULE
+			}
+		}
+		*rules = append(*rules, *rule)
+	}
+	return nil
+}
+
+func handlerWafModSecConvert(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, access.AccessOPRead)
+	if acc == nil {
+		return
+	} else if !acc.Authorize(&share.CLUSWafSensor{}, nil) {
+		restRespAccessDenied(w, login)
+		return
+	}
+
+	var rconf api.RESTWafModSecData
+	body, _ := ioutil.ReadAll(r.Body)
+	if err := json.Unmarshal(body, &rconf); err != nil || rconf.Config == nil {
+		log.WithFields(log.Fields{"error": err}).Error("Request error")
+		restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+		return
+	}
+
+	rules, skipped, err := convertValidModSecRules(rconf.Config)
+	if err != nil {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+		return
+	}
+
+	resp := api.RESTWafModSecResult{Rules: rules, Skipped: skipped}
+	restRespSuccess(w, r, &resp, acc, login, nil, "Convert modsecurity rules")
+}
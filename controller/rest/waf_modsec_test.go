@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
+)
+
+const testModSecRules = `
+# comment
+SecMarker "BEGIN-REQUEST-941-APPLICATION-ATTACK-XSS"
+
+SecRule REQUEST_COOKIES|!REQUEST_COOKIES:/__utm/|REQUEST_COOKIES_NAMES|REQUEST_HEADERS:User-Agent "@rx (?i)<script[^>]*>" \
+    "id:941110,\
+    phase:2,\
+    block,\
+    t:none,t:lowercase,\
+    msg:'XSS Filter - Category 1: Script Tag Vector, \"quoted\"',\
+    severity:'CRITICAL'"
+
+SecRule ARGS "@pm union select" "id:942100,phase:2,block,severity:'WARNING'"
+
+SecRule REQUEST_FILENAME "@endsWith .php" "id:933100,phase:2,block,chain,severity:2"
+    SecRule REQUEST_BODY "!@contains <?php" "t:none"
+
+SecRule TX:DETECTION_PARANOIA_LEVEL "@lt 2" "id:941013,phase:1,pass,nolog,skipAfter:END-941"
+SecRule &REQUEST_HEADERS:Host "@eq 0" "id:920280,phase:1,block,severity:'WARNING'"
+SecRule REQUEST_URI "@rx ^/health$" "id:900100,phase:1,allow"
+SecRule REQUEST_URI "@rx %{tx.path}" "id:900200,phase:1,block,severity:'CRITICAL'"
+SecRule REQUEST_BODY "!@rx foo" "id:900300,phase:2,block,severity:'CRITICAL'"
+SecRule REQUEST_URI "@rx foo" "id:900400,phase:1,block,chain,severity:'CRITICAL'"
+SecAction "id:900500,phase:1,pass"
+`
+
+func TestConvertModSecRules(t *testing.T) {
+	rules, skipped, err := convertModSecRules(testModSecRules, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expect := []*api.RESTWafRule{
+		&api.RESTWafRule{Name: "modsec_941110", Patterns: []api.RESTWafCriteriaEntry{
+			{Key: share.DlpRuleKeyPattern, Value: "(?i)<script[^>]*>", Op: share.CriteriaOpRegex, Context: share.DlpPatternContextHEAD},
+		}},
+		&api.RESTWafRule{Name: "modsec_942100", Patterns: []api.RESTWafCriteriaEntry{
+			{Key: share.DlpRuleKeyPattern, Value: "(?:union|select)", Op: share.CriteriaOpRegex, Context: share.DlpPatternContextPACKET},
+		}},
+		&api.RESTWafRule{Name: "modsec_933100", Patterns: []api.RESTWafCriteriaEntry{
+			{Key: share.DlpRuleKeyPattern, Value: `\.php$`, Op: share.CriteriaOpRegex, Context: share.DlpPatternContextURI},
+			{Key: share.DlpRuleKeyPattern, Value: `<\?php`, Op: share.CriteriaOpNotRegex, Context: share.DlpPatternContextBODY},
+		}},
+	}
+	if len(rules) != len(expect) {
+		t.Fatalf("Unexpected rules: %+v", rules)
+	}
+	for i, rule := range rules {
+		if rule.Name != expect[i].Name || rule.CfgType != api.CfgTypeUserCreated || len(rule.Patterns) != len(expect[i].Patterns) {
+			t.Errorf("Unexpected rule: expect=%+v, actual=%+v", expect[i], rule)
+			continue
+		}
+		for j, pt := range rule.Patterns {
+			if pt != expect[i].Patterns[j] {
+				t.Errorf("Unexpected pattern: rule=%s, expect=%+v, actual=%+v", rule.Name, expect[i].Patterns[j], pt)
+			}
+		}
+	}
+
+	ids := []string{"941013", "920280", "900100", "900200", "900300", "900400"}
+	if len(skipped) != len(ids) {
+		t.Fatalf("Unexpected skipped rules: %+v", skipped)
+	}
+	for i, s := range skipped {
+		if s.ID != ids[i] || s.Reason == "" {
+			t.Errorf("Unexpected skipped rule: expect=%s, actual=%+v", ids[i], s)
+		}
+	}
+}
+
+func TestConvertModSecRulesSeverity(t *testing.T) {
+	if _, _, err := convertModSecRules(testModSecRules, "high"); err == nil {
+		t.Errorf("Invalid severity should fail")
+	}
+
+	for _, severity := range []string{"critical", "2"} {
+		rules, _, err := convertModSecRules(testModSecRules, severity)
+		if err != nil {
+			t.Fatalf("Unexpected error: severity=%s, err=%v", severity, err)
+		}
+		if len(rules) != 2 || rules[0].Name != "modsec_941110" || rules[1].Name != "modsec_933100" {
+			t.Errorf("Unexpected rules: severity=%s, rules=%+v", severity, rules)
+		}
+	}
+}
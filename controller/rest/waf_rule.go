@@ -479,6 +479,10 @@ func handlerWafSensorCreate(w http.ResponseWriter, r *http.Request, ps httproute
 		rules := make([]api.RESTWafRule, 0)
 		conf.Rules = &rules
 	}
+	if err := applyWafModSec(conf); err != nil {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+		return
+	}
 	if err := validateWafRuleConfig(*conf.Rules); err != nil {
 		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
 		return
@@ -766,6 +770,11 @@ func handlerWafSensorConfig(w http.ResponseWriter, r *http.Request, ps httproute
 		}
 	}
 
+	if err := applyWafModSec(conf); err != nil {
+		restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+		return
+	}
+
 	if conf.Rules != nil {
 		if err := validateWafRuleConfig(*conf.Rules); err != nil {
 			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
@@ -1016,6 +1025,68 @@ func handlerWafSensorDelete(w http.ResponseWriter, r *http.Request, ps httproute
 	}
 }
 
+// export a non-predefined waf sensor as NvWafSecurityRule. When withGroups is true, the groups that the sensor is applied to are exported as well
+func exportWafSecurityRule(sensor, defSensor *share.CLUSWafSensor, withGroups bool, acc *access.AccessControl) *resource.NvWafSecurityRule {
+	apiversion := fmt.Sprintf("%s/%s", common.OEMClusterSecurityRuleGroup, resource.NvWafSecurityRuleVersion)
+
+	ruleList := make([]*resource.NvSecurityWafRule, 0, len(sensor.RuleListNames))
+	for rName, _ := range sensor.RuleListNames {
+		if r, ok := defSensor.RuleList[rName]; ok {
+			patterns := make([]api.RESTWafCriteriaEntry, len(r.Patterns))
+			for idx, p := range r.Patterns {
+				patterns[idx] = api.RESTWafCriteriaEntry{
+					Key:     p.Key,
+					Value:   p.Value,
+					Op:      p.Op,
+					Context: p.Context,
+				}
+			}
+			if ss := strings.Split(rName, common.WAFRuleTag); len(ss) > 1 {
+				r.Name = ss[1] // use simple name for exported sensor's rules
+				rule := &resource.NvSecurityWafRule{
+					Name:     &r.Name,
+					Patterns: patterns,
+				}
+				ruleList = append(ruleList, rule)
+			}
+		}
+	}
+	// export the groups that the sensor is applied to. Otherwise the groups' settings are exported in their own NvSecurityRule objects
+	var groups []*resource.NvSecurityWafGroupBinding
+	if withGroups {
+		if cs, _ := cacher.GetWafSensor(sensor.Name, acc); cs != nil {
+			for _, g := range cs.GroupList {
+				if wafGroup, _ := cacher.GetWafGroup(g, acc); wafGroup != nil {
+					for _, s := range wafGroup.Sensors {
+						if s.Name == sensor.Name {
+							groups = append(groups, &resource.NvSecurityWafGroupBinding{Name: g, Action: s.Action})
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+
+	kind := resource.NvWafSecurityRuleKind
+	resptmp := resource.NvWafSecurityRule{
+		Kind: &kind,
+		Metadata: &cmetav1.ObjectMeta{
+			Name: &sensor.Name,
+		},
+		ApiVersion: &apiversion,
+		Spec: resource.NvSecurityWafSpec{
+			Sensor: &resource.NvSecurityWafSensor{
+				Name:     sensor.Name,
+				RuleList: ruleList,
+				Comment:  &sensor.Comment,
+			},
+			Groups: groups,
+		},
+	}
+	return &resptmp
+}
+
 func handlerWafExport(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug()
 	defer r.Body.Close()
@@ -1054,7 +1125,6 @@ func handlerWafExport(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 	}
 	defer clusHelper.ReleaseLock(lock)
 
-	apiversion := fmt.Sprintf("%s/%s", common.OEMClusterSecurityRuleGroup, resource.NvWafSecurityRuleVersion)
 	defSensor := clusHelper.GetWafSensor(share.CLUSWafDefaultSensor)
 	// export selected waf sensors
 	for _, name := range rconf.Names {
@@ -1069,44 +1139,7 @@ func handlerWafExport(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 			continue
 		}
 
-		ruleList := make([]*resource.NvSecurityWafRule, 0, len(sensor.RuleListNames))
-		for rName, _ := range sensor.RuleListNames {
-			if r, ok := defSensor.RuleList[rName]; ok {
-				patterns := make([]api.RESTWafCriteriaEntry, len(r.Patterns))
-				for idx, p := range r.Patterns {
-					patterns[idx] = api.RESTWafCriteriaEntry{
-						Key:     p.Key,
-						Value:   p.Value,
-						Op:      p.Op,
-						Context: p.Context,
-					}
-				}
-				if ss := strings.Split(rName, common.WAFRuleTag); len(ss) > 1 {
-					r.Name = ss[1] // use simple name for exported sensor's rules
-					rule := &resource.NvSecurityWafRule{
-						Name:     &r.Name,
-						Patterns: patterns,
-					}
-					ruleList = append(ruleList, rule)
-				}
-			}
-		}
-		kind := resource.NvWafSecurityRuleKind
-		resptmp := resource.NvWafSecurityRule{
-			Kind: &kind,
-			Metadata: &cmetav1.ObjectMeta{
-				Name: &sensor.Name,
-			},
-			ApiVersion: &apiversion,
-			Spec: resource.NvSecurityWafSpec{
-				Sensor: &resource.NvSecurityWafSensor{
-					Name:     sensor.Name,
-					RuleList: ruleList,
-					Comment:  &sensor.Comment,
-				},
-			},
-		}
-		resp.Items = append(resp.Items, &resptmp)
+		resp.Items = append(resp.Items, exportWafSecurityRule(sensor, defSensor, true, acc))
 	}
 
 	// tell the browser the returned content should be downloaded
@@ -1214,6 +1247,7 @@ func importWaf(scope string, loginDomainRoles access.DomainRole, importTask shar
 						importTask.Status = err.Error()
 						break
 					}
+					crdHandler.crdHandleWafSensorGroups(parsedCfg.WafSensorCfg.Name, parsedCfg.WafSensorGroups, nil)
 					progress += inc
 					importTask.Percentage = int(progress)
 					clusHelper.PutImportTask(&importTask)
@@ -3,13 +3,17 @@ package rest
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"path/filepath"
 	"sort"
 	"time"
 
 	"github.com/julienschmidt/httprouter"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 
 	"github.com/neuvector/neuvector/controller/api"
 	"github.com/neuvector/neuvector/controller/rpc"
@@ -457,6 +461,20 @@ func handlerWorkloadShowV2(w http.ResponseWriter, r *http.Request, ps httprouter
 	handlerWorkloadShowBase("v2", w, r, ps)
 }
 
+func validateQuarExceptions(excepts []share.CLUSQuarException) error {
+	for _, e := range excepts {
+		if ip, _ := utils.ParseIPRange(e.Address); ip == nil {
+			return fmt.Errorf("Invalid quarantine exception address: %s", e.Address)
+		}
+		if e.Ports != "" {
+			if _, _, _, err := utils.ParsePortRangeLink(e.Ports); err != nil {
+				return fmt.Errorf("Invalid quarantine exception ports: %s", e.Ports)
+			}
+		}
+	}
+	return nil
+}
+
 func handlerWorkloadConfig(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
 	defer r.Body.Close()
@@ -484,6 +502,14 @@ func handlerWorkloadConfig(w http.ResponseWriter, r *http.Request, ps httprouter
 		return
 	}
 
+	if rconf.Config.QuarExceptions != nil {
+		if err := validateQuarExceptions(*rconf.Config.QuarExceptions); err != nil {
+			log.WithFields(log.Fields{"id": id, "error": err}).Error()
+			restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, err.Error())
+			return
+		}
+	}
+
 	if rconf.Config.Quarantine != nil && *rconf.Config.Quarantine {
 		if wl.ShareNSWith != "" {
 			err := errors.New("Only the pod container can be quarantined")
@@ -522,8 +548,12 @@ func handlerWorkloadConfig(w http.ResponseWriter, r *http.Request, ps httprouter
 				cconf.QuarReason = share.QuarantineReasonUser
 			} else {
 				cconf.QuarReason = ""
+				cconf.QuarExceptions = nil
 			}
 		}
+		if rconf.Config.QuarExceptions != nil {
+			cconf.QuarExceptions = *rconf.Config.QuarExceptions
+		}
 
 		value, _ = json.Marshal(&cconf)
 		if err = cluster.PutRev(key, value, rev); err != nil {
@@ -806,6 +836,138 @@ func handlerWorkloadFileMonitorProfile(w http.ResponseWriter, r *http.Request, p
 	restRespSuccess(w, r, &resp, acc, login, nil, "Get container file monitor profile")
 }
 
+func handlerWorkloadFileBaseline(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	id := ps.ByName("id")
+
+	agentID, err := cacher.GetAgentbyWorkload(id, acc)
+	if agentID == "" {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+
+	baselines, err := rpc.GetFileBaseline(agentID, id)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Fail to make RPC call")
+		restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrClusterRPCError, err.Error())
+		return
+	}
+
+	resp := api.RESTFileBaselineData{Baselines: make([]*api.RESTFileBaseline, len(baselines))}
+	for i, bl := range baselines {
+		resp.Baselines[i] = &api.RESTFileBaseline{
+			Path:       bl.Path,
+			Hash:       bl.Hash,
+			BaselineAt: bl.BaselineAt,
+			Current:    bl.Current,
+			Drifted:    bl.Drifted,
+		}
+	}
+	restRespSuccess(w, r, &resp, acc, login, nil, "Get container file baseline")
+}
+
+// re-baseline the monitored files after their changes are approved
+func handlerWorkloadFileBaselineReset(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	}
+
+	id := ps.ByName("id")
+
+	var rconf api.RESTFileBaselineResetData
+	body, _ := ioutil.ReadAll(r.Body)
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &rconf); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Request error")
+			restRespError(w, http.StatusBadRequest, api.RESTErrInvalidRequest)
+			return
+		}
+	}
+
+	var paths []string
+	if rconf.Reset != nil {
+		for _, path := range rconf.Reset.Paths {
+			if !filepath.IsAbs(path) {
+				e := "File path must be absolute"
+				log.WithFields(log.Fields{"path": path}).Error(e)
+				restRespErrorMessage(w, http.StatusBadRequest, api.RESTErrInvalidRequest, e)
+				return
+			}
+			paths = append(paths, filepath.Clean(path))
+		}
+	}
+
+	agentID, err := cacher.GetAgentbyWorkload(id, acc)
+	if agentID == "" {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+
+	if err := rpc.ResetFileBaseline(agentID, id, paths); err != nil {
+		if grpc.Code(err) == codes.NotFound {
+			log.WithFields(log.Fields{"id": id, "paths": paths}).Error("File baseline not found")
+			restRespError(w, http.StatusNotFound, api.RESTErrObjectNotFound)
+		} else {
+			log.WithFields(log.Fields{"error": err}).Error("Fail to make RPC call")
+			restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrClusterRPCError, err.Error())
+		}
+		return
+	}
+
+	restRespSuccess(w, r, nil, acc, login, &rconf, "Reset container file baseline")
+}
+
+// collect the container's process tree, sockets, libraries, file changes and environment
+// from the enforcer and return them as a tarball for incident response
+func handlerWorkloadForensic(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
+	defer r.Body.Close()
+
+	acc, login := getAccessControl(w, r, "")
+	if acc == nil {
+		return
+	} else if !authDebugCaller(w, acc, login) {
+		return
+	}
+
+	id := ps.ByName("id")
+
+	agentID, err := cacher.GetAgentbyWorkload(id, acc)
+	if agentID == "" {
+		restRespNotFoundLogAccessDenied(w, login, err)
+		return
+	}
+
+	bundle, err := rpc.GetForensicBundle(agentID, id)
+	if err != nil {
+		switch grpc.Code(err) {
+		case codes.NotFound:
+			restRespError(w, http.StatusNotFound, api.RESTErrObjectNotFound)
+		default:
+			log.WithFields(log.Fields{"error": grpc.ErrorDesc(err)}).Error("Failed to collect forensic bundle")
+			restRespErrorMessage(w, http.StatusInternalServerError, api.RESTErrAgentError, "Failed to collect forensic bundle")
+		}
+		return
+	}
+
+	filename := fmt.Sprintf("forensic-%s-%s.tar.gz", id, time.Now().UTC().Format("20060102150405"))
+	w.Header().Set("Content-Disposition", "Attachment; filename="+filename)
+	w.Header().Set("Content-Type", "application/gzip")
+	w.WriteHeader(http.StatusOK)
+	w.Write(bundle)
+}
+
 /*
 func handlerWorkloadLogs(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	log.WithFields(log.Fields{"URL": r.URL.String()}).Debug("")
@@ -51,6 +51,7 @@ func findEnforcerServiceClient(id string) (share.EnforcerServiceClient, error) {
 }
 
 const defaultReqTimeout = time.Second * 8
+const forensicReqTimeout = time.Second * 60
 
 func Kick(agentID string, ctrlID string, reason string) error {
 	client, err := findEnforcerServiceClient(agentID)
@@ -344,6 +345,31 @@ func RunDockerBench(agentID string) error {
 	return err
 }
 
+func PushPolicyBatch(agentID string, batch *share.CLUSPolicyPushBatch) (*share.CLUSPolicyPushAck, error) {
+	client, err := findEnforcerServiceClient(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultReqTimeout)
+	defer cancel()
+
+	return client.PushPolicyBatch(ctx, batch)
+}
+
+func SetThreatSignatures(agentID string, sigs *share.CLUSThreatSignatureArray) error {
+	client, err := findEnforcerServiceClient(agentID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultReqTimeout)
+	defer cancel()
+
+	_, err = client.SetThreatSignatures(ctx, sigs)
+	return err
+}
+
 func RunKubernetesBench(agentID string) error {
 	client, err := findEnforcerServiceClient(agentID)
 	if err != nil {
@@ -373,6 +399,64 @@ func GetFileMonitorFile(agentID string, f *share.CLUSFilter) ([]*share.CLUSFileM
 	}
 }
 
+func GetFileBaseline(agentID, id string) ([]*share.CLUSFileBaseline, error) {
+	client, err := findEnforcerServiceClient(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultReqTimeout)
+	defer cancel()
+
+	f := &share.CLUSFilter{Workload: id}
+	if baselines, err := client.GetFileBaseline(ctx, f); err != nil {
+		return nil, err
+	} else {
+		return baselines.Baselines, nil
+	}
+}
+
+func ResetFileBaseline(agentID, id string, paths []string) error {
+	client, err := findEnforcerServiceClient(agentID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultReqTimeout)
+	defer cancel()
+
+	_, err = client.ResetFileBaseline(ctx, &share.CLUSFileBaselineReset{Workload: id, Paths: paths})
+	return err
+}
+
+func GetForensicBundle(agentID, id string) ([]byte, error) {
+	client, err := findEnforcerServiceClient(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the collection walks the container's writable layer, give it more time than a regular request
+	ctx, cancel := context.WithTimeout(context.Background(), forensicReqTimeout)
+	defer cancel()
+
+	stream, err := client.GetForensicBundle(ctx, &share.CLUSFilter{Workload: id})
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := make([]byte, 0)
+	for {
+		if out, err := stream.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		} else {
+			bundle = append(bundle, out.Bundle...)
+		}
+	}
+	return bundle, nil
+}
+
 func GetProcess(agentID, id string) ([]*share.CLUSProcess, error) {
 	client, err := findEnforcerServiceClient(agentID)
 	if err != nil {
@@ -172,6 +172,10 @@ func fillSystemResevedProcessRule(uuid string) *api.RESTProcessUuidEntry {
 		name = "<RiskyApp>" // risky app
 	case share.CLUSReservedUuidDockerCp:
 		name = "<docker cp>" // docker cp
+	case share.CLUSReservedUuidKernelLoad:
+		name = "<kernel_load>" // kernel module or eBPF program load
+	case share.CLUSReservedUuidEscape:
+		name = "<container_escape>" // container escape techniques
 	default:
 		return nil
 	}
@@ -195,6 +195,14 @@ func GetScannedImageSummary(reqImgRegistry utils.Set, reqImgRepo, reqImgTag stri
 	log.WithFields(log.Fields{"registry": reqImgRegistry, "repo": reqImgRepo, "tag": reqImgTag}).Debug()
 
 	sumMap := getScannedImages(reqImgRegistry, reqImgRepo, reqImgTag, vpf)
+	if len(sumMap) == 0 {
+		for _, m := range getMirroredImages(reqImgRegistry, reqImgRepo) {
+			if sumMap = getScannedImages(m.registry, m.repo, reqImgTag, vpf); len(sumMap) > 0 {
+				log.WithFields(log.Fields{"registry": m.registry, "repo": m.repo}).Debug("Scanned image found by registry mirror")
+				break
+			}
+		}
+	}
 	if len(sumMap) == 0 {
 		log.Debug("Scanned image not found")
 		summary := &nvsysadmission.ScannedImageSummary{VulNames: utils.NewSet()}
@@ -0,0 +1,112 @@
+package scan
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// Pull-through caches, such as the Harbor proxy cache and the containerd registry mirrors, rewrite the image
+// reference of the pod spec. The image of the mirror is matched to the scan result of the upstream image, and
+// vice versa, so the image is not denied as unscanned when only one of them is scanned.
+
+const dockerHubHost = "docker.io"
+
+var dockerHubHosts = utils.NewSet(dockerHubHost, "index.docker.io", "registry.hub.docker.com", "registry-1.docker.io")
+
+var mirrorLock sync.RWMutex
+var registryMirrors []*share.CLUSRegistryMirror
+
+type mirroredImage struct {
+	registry utils.Set
+	repo     string
+}
+
+func SetRegistryMirrors(mirrors []*share.CLUSRegistryMirror) {
+	mirrorLock.Lock()
+	defer mirrorLock.Unlock()
+	registryMirrors = mirrors
+}
+
+// Return the registry host and repository prefix without the scheme, the docker hub hosts are all taken as docker.io
+func normalizeMirrorPrefix(prefix string) string {
+	if i := strings.Index(prefix, "://"); i != -1 {
+		prefix = prefix[i+3:]
+	}
+	prefix = strings.Trim(prefix, "/")
+
+	host, path := prefix, ""
+	if i := strings.Index(prefix, "/"); i != -1 {
+		host, path = prefix[:i], prefix[i:]
+	}
+	host = strings.ToLower(host)
+	if dockerHubHosts.Contains(host) {
+		host = dockerHubHost
+	}
+	return host + path
+}
+
+// The registry is looked up in both schemes because the scheme of the mirror is not in the image reference
+func splitMirroredRef(ref string) *mirroredImage {
+	i := strings.Index(ref, "/")
+	if i == -1 {
+		return nil
+	}
+
+	host, repo := ref[:i], ref[i+1:]
+	image := &mirroredImage{registry: utils.NewSet(), repo: repo}
+	if host == dockerHubHost {
+		for h := range dockerHubHosts.Iter() {
+			image.registry.Add("https://" + h.(string) + "/")
+		}
+		if !strings.Contains(repo, "/") {
+			image.repo = "library/" + repo
+		}
+	} else {
+		image.registry.Add("https://" + host + "/")
+		image.registry.Add("http://" + host + "/")
+	}
+	return image
+}
+
+func getMirroredImages(reqImgRegistry utils.Set, reqImgRepo string) []*mirroredImage {
+	mirrorLock.RLock()
+	defer mirrorLock.RUnlock()
+
+	if len(registryMirrors) == 0 {
+		return nil
+	}
+
+	refs := utils.NewSet()
+	for r := range reqImgRegistry.Iter() {
+		refs.Add(normalizeMirrorPrefix(r.(string)) + "/" + reqImgRepo)
+	}
+
+	mapped := utils.NewSet()
+	for r := range refs.Iter() {
+		ref := r.(string)
+		for _, m := range registryMirrors {
+			mirror, upstream := normalizeMirrorPrefix(m.Mirror), normalizeMirrorPrefix(m.Upstream)
+			if mirror == "" || upstream == "" {
+				continue
+			}
+			if strings.HasPrefix(ref, mirror+"/") {
+				mapped.Add(upstream + ref[len(mirror):])
+			} else if strings.HasPrefix(ref, upstream+"/") {
+				mapped.Add(mirror + ref[len(upstream):])
+			}
+		}
+	}
+
+	images := make([]*mirroredImage, 0, mapped.Cardinality())
+	for r := range mapped.Iter() {
+		if ref := r.(string); !refs.Contains(ref) {
+			if image := splitMirroredRef(ref); image != nil {
+				images = append(images, image)
+			}
+		}
+	}
+	return images
+}
@@ -0,0 +1,53 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+func TestMirroredImages(t *testing.T) {
+	dockerHub := utils.NewSet("https://docker.io/", "https://index.docker.io/", "https://registry.hub.docker.com/", "https://registry-1.docker.io/")
+
+	SetRegistryMirrors([]*share.CLUSRegistryMirror{
+		{Mirror: "harbor.example.com/dockerhub-proxy", Upstream: "docker.io"},
+		{Mirror: "https://mirror.local:5000/", Upstream: "quay.io"},
+	})
+	defer SetRegistryMirrors(nil)
+
+	tests := []struct {
+		registry utils.Set
+		repo     string
+		expect   utils.Set
+		mapped   string
+	}{
+		// harbor proxy cache to docker hub
+		{utils.NewSet("https://harbor.example.com/"), "dockerhub-proxy/library/nginx", dockerHub, "library/nginx"},
+		{utils.NewSet("https://harbor.example.com/"), "dockerhub-proxy/nginx", dockerHub, "library/nginx"},
+		// docker hub to harbor proxy cache
+		{dockerHub, "library/nginx", utils.NewSet("https://harbor.example.com/", "http://harbor.example.com/"), "dockerhub-proxy/library/nginx"},
+		// registry mirror to quay.io, and the reverse
+		{utils.NewSet("https://mirror.local:5000/"), "coreos/etcd", utils.NewSet("https://quay.io/", "http://quay.io/"), "coreos/etcd"},
+		{utils.NewSet("https://quay.io/"), "coreos/etcd", utils.NewSet("https://mirror.local:5000/", "http://mirror.local:5000/"), "coreos/etcd"},
+	}
+
+	for i, test := range tests {
+		images := getMirroredImages(test.registry, test.repo)
+		if len(images) != 1 {
+			t.Errorf("Test %d: unexpected mirrored images: %d", i, len(images))
+			continue
+		}
+		if !images[0].registry.Equal(test.expect) || images[0].repo != test.mapped {
+			t.Errorf("Test %d: unexpected mirrored image: %+v %s", i, images[0].registry, images[0].repo)
+		}
+	}
+
+	// not mirrored
+	if images := getMirroredImages(utils.NewSet("https://harbor.example.com/"), "library/nginx"); len(images) != 0 {
+		t.Errorf("Unexpected mirrored images: %+v", images)
+	}
+	if images := getMirroredImages(utils.NewSet("https://gcr.io/"), "google/pause"); len(images) != 0 {
+		t.Errorf("Unexpected mirrored images: %+v", images)
+	}
+}
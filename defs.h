@@ -77,7 +77,10 @@
 #define DPI_APP_TNS                   2026
 #define DPI_APP_TDS                   2027
 #define DPI_APP_GRPC                  2028
-#define DPI_APP_MAX                   2029
+#define DPI_APP_MQTT                  2029
+#define DPI_APP_AMQP                  2030
+#define DPI_APP_STRATUM               2031
+#define DPI_APP_MAX                   2032
 
 #define DPI_APP_UNKNOWN               0
 #define DPI_APP_NOT_CHECKED           1    //just for report purpose
@@ -104,12 +107,16 @@
 #define DPI_PARSER_TNS                17
 #define DPI_PARSER_TDS                18
 #define DPI_PARSER_GRPC               19
-#define DPI_PARSER_MAX                20
+#define DPI_PARSER_MQTT               20
+#define DPI_PARSER_AMQP               21
+#define DPI_PARSER_STRATUM            22
+#define DPI_PARSER_MAX                23
 
 // Volume based
 #define THRT_ID_SYN_FLOOD       1001
 #define THRT_ID_ICMP_FLOOD      1002
 #define THRT_ID_IP_SRC_SESSION  1003
+#define THRT_ID_PORT_SCAN       1004
 
 // Pattern based
 #define THRT_ID_BAD_PACKET           2001
@@ -138,7 +145,8 @@
 #define THRT_ID_DNS_TUNNELING        2024
 #define THRT_ID_TCP_SMALL_MSS        2025
 #define THRT_ID_K8S_EXTIP_MITM       2026
-#define THRT_ID_MAX                  2027
+#define THRT_ID_CRYPTO_MINING        2027
+#define THRT_ID_MAX                  2028
 
 
 // --- messages
@@ -209,6 +217,11 @@ typedef struct {
 #define DP_POLICY_ACTION_VIOLATE       5
 #define DP_POLICY_ACTION_DENY          6
 
+// Traffic allowed by a rule can be throttled to the rate limit
+#define DP_POLICY_RATE_NONE    0
+#define DP_POLICY_RATE_BPS     1 // bytes per second
+#define DP_POLICY_RATE_CPS     2 // connections per second
+
 #define DP_POLICY_APP_ANY      0
 #define DP_POLICY_APP_UNKNOWN  0xffffffff
 
@@ -222,6 +235,8 @@ typedef struct {
 #define MSG_START    0x1
 #define MSG_END      0x2
 
+#define DP_MESH_ID_LEN 96 // peer identity of service mesh, such as spiffe id
+
 #define MAX_SIG_NAME_LEN 512 + 10
 #define DP_DLP_RULE_NAME_MAX_LEN MAX_SIG_NAME_LEN
 #define DP_DLP_RULE_PATTERN_MAX_LEN 512
@@ -262,6 +277,7 @@ typedef struct {
     uint8_t  XffIP[16];
     uint16_t XffApp;
     uint16_t XffPort;
+    char     MeshID[DP_MESH_ID_LEN];
 } DPMsgSession;
     
 typedef struct {
@@ -277,6 +293,7 @@ typedef struct {
 #define METER_ID_ICMP_FLOOD     1
 #define METER_ID_IP_SRC_SESSION 2
 #define METER_ID_TCP_NODATA     3
+#define METER_ID_PORT_SCAN      4
 
 typedef struct {
     uint8_t  EPMAC[6];
@@ -430,6 +447,7 @@ typedef struct {
     uint32_t PolicyId;
     uint32_t Violates;
     uint32_t ThreatID;
+    char     MeshID[DP_MESH_ID_LEN];
 } DPMsgConnect;
 
 typedef struct {
@@ -60,6 +60,7 @@ typedef struct io_counter_ {
 
     uint64_t drop_meters, proxy_meters;
     uint64_t cur_meters, cur_log_caches;
+    uint64_t grpc_compressed_msgs; // grpc messages not inspected by dlp/waf
     uint32_t type1_rules, type2_rules, domains, domain_ips;
 } io_counter_t;
 
@@ -166,6 +167,16 @@ typedef struct io_internal_subnet4_ {
     io_subnet4_t list[0];
 } io_internal_subnet4_t;
 
+typedef struct io_subnet6_ {
+    struct in6_addr ip;
+    struct in6_addr mask;
+} io_subnet6_t;
+
+typedef struct io_internal_subnet6_ {
+    int count;
+    io_subnet6_t list[0];
+} io_internal_subnet6_t;
+
 #define SPEC_INTERNAL_TUNNELIP "tunnelip"
 #define SPEC_INTERNAL_SVCIP "svcip"
 #define SPEC_INTERNAL_HOSTIP "hostip"
@@ -239,6 +250,7 @@ void dpi_handle_dlp_ctrl_req(int req);
 void dpi_get_device_counter(DPMsgDeviceCounter *c);
 void dpi_count_session(DPMsgSessionCount *c);
 void dpi_get_stats(io_stats_t *stats, dpi_stats_callback_fct cb);
+void dpi_meter_threshold_cfg(uint32_t syn_flood, uint32_t port_scan);
 
 
 #define GET_EP_FROM_MAC_MAP(buf)  (io_ep_t *)(buf + sizeof(io_mac_t) * 3)
@@ -259,9 +271,17 @@ typedef struct dpi_policy_rule_ {
     uint16_t dport_r;
     uint16_t proto;
     uint8_t action;
+    uint8_t rate_unit;
     bool ingress;
     bool vh;
+    bool v6;
+    struct in6_addr sip6;
+    struct in6_addr sip6_r;
+    struct in6_addr dip6;
+    struct in6_addr dip6_r;
     char fqdn[MAX_FQDN_LEN];
+    uint32_t rate;
+    uint32_t rate_id;
     uint32_t num_apps;
     dpi_policy_app_rule_t *app_rules;
 } dpi_policy_rule_t;
@@ -279,6 +299,7 @@ int dpi_policy_cfg(int cmd, dpi_policy_t *policy, int flag);
 void dp_policy_destroy(void *policy_hdl);
 void dpi_fqdn_entry_mark_delete(const char *name);
 void dpi_fqdn_entry_delete_marked();
+void dpi_fqdn_entry_expire();
 
 /*
  * -----------------------------------------------------
@@ -317,8 +338,13 @@ typedef struct fqdn_ipv4_entry_ {
 typedef struct fqdn_ipv4_item_ {
     struct cds_list_head node;
     uint32_t ip;
+    uint32_t expire;//0: configured ip, never expire
 } fqdn_ipv4_item_t;
 
+#define FQDN_IP_MIN_TTL           60
+#define FQDN_IP_MAX_TTL           86400
+#define FQDN_IP_DEFAULT_TTL       3600
+
 #define DPI_FQDN_DELETE_QLEN      32
 #define DPI_FQDN_MAX_ENTRIES      DP_POLICY_FQDN_MAX_ENTRIES
 typedef struct dpi_fqdn_hdl_ {
@@ -331,11 +357,13 @@ typedef struct dpi_fqdn_hdl_ {
     fqdn_name_entry_t *del_name_list[DPI_FQDN_DELETE_QLEN];
     fqdn_ipv4_entry_t *del_ipv4_list[DPI_FQDN_DELETE_QLEN];
     struct cds_list_head del_rlist;
+    struct cds_list_head del_iplist;
 } dpi_fqdn_hdl_t;
 
 typedef struct fqdn_iter_ctx_ {
     dpi_fqdn_hdl_t *hdl;
     bool more;
+    uint32_t now;
 } fqdn_iter_ctx_t;
 
 uint32_t config_fqdn_ipv4_mapping(dpi_fqdn_hdl_t *hdl, char *name, uint32_t ip, bool vh);
@@ -1302,6 +1302,28 @@ static int dp_ctrl_get_debug(json_t *msg)
 */
 
 
+
+// An ipv6 rule must have both addresses in ipv6, the external
+// placeholder is sent as "::" by the agent.
+static bool dp_ctrl_is_ip6_rule(json_t *rule_obj)
+{
+    const char *sip = json_string_value(json_object_get(rule_obj, "sip"));
+    const char *dip = json_string_value(json_object_get(rule_obj, "dip"));
+
+    return sip != NULL && dip != NULL && strchr(sip, ':') != NULL && strchr(dip, ':') != NULL;
+}
+
+static int dp_ctrl_parse_ip6(json_t *obj, struct in6_addr *ip6)
+{
+    const char *str = json_string_value(obj);
+
+    if (str == NULL || inet_pton(AF_INET6, str, ip6) != 1) {
+        memset(ip6, 0, sizeof(struct in6_addr));
+        return -1;
+    }
+    return 0;
+}
+
 static int dp_ctrl_cfg_policy(json_t *msg)
 {
     int cmd;
@@ -1350,19 +1372,37 @@ static int dp_ctrl_cfg_policy(json_t *msg)
         json_t *ip_obj, *fqdn_obj;
         rule_obj = json_array_get(obj, i);
         policy.rule_list[i].id = json_integer_value(json_object_get(rule_obj, "id"));
-        policy.rule_list[i].sip = inet_addr(json_string_value(json_object_get(rule_obj, "sip")));
-        policy.rule_list[i].dip = inet_addr(json_string_value(json_object_get(rule_obj, "dip")));
-        ip_obj = json_object_get(rule_obj, "sipr");
-        if (ip_obj) {
-            policy.rule_list[i].sip_r = inet_addr(json_string_value(ip_obj));
-        } else {
-            policy.rule_list[i].sip_r = policy.rule_list[i].sip;
-        }
-        ip_obj = json_object_get(rule_obj, "dipr");
-        if (ip_obj) {
-            policy.rule_list[i].dip_r = inet_addr(json_string_value(ip_obj));
+        if (dp_ctrl_is_ip6_rule(rule_obj)) {
+            policy.rule_list[i].v6 = true;
+            dp_ctrl_parse_ip6(json_object_get(rule_obj, "sip"), &policy.rule_list[i].sip6);
+            dp_ctrl_parse_ip6(json_object_get(rule_obj, "dip"), &policy.rule_list[i].dip6);
+            ip_obj = json_object_get(rule_obj, "sipr");
+            if (ip_obj) {
+                dp_ctrl_parse_ip6(ip_obj, &policy.rule_list[i].sip6_r);
+            } else {
+                policy.rule_list[i].sip6_r = policy.rule_list[i].sip6;
+            }
+            ip_obj = json_object_get(rule_obj, "dipr");
+            if (ip_obj) {
+                dp_ctrl_parse_ip6(ip_obj, &policy.rule_list[i].dip6_r);
+            } else {
+                policy.rule_list[i].dip6_r = policy.rule_list[i].dip6;
+            }
         } else {
-            policy.rule_list[i].dip_r = policy.rule_list[i].dip;
+            policy.rule_list[i].sip = inet_addr(json_string_value(json_object_get(rule_obj, "sip")));
+            policy.rule_list[i].dip = inet_addr(json_string_value(json_object_get(rule_obj, "dip")));
+            ip_obj = json_object_get(rule_obj, "sipr");
+            if (ip_obj) {
+                policy.rule_list[i].sip_r = inet_addr(json_string_value(ip_obj));
+            } else {
+                policy.rule_list[i].sip_r = policy.rule_list[i].sip;
+            }
+            ip_obj = json_object_get(rule_obj, "dipr");
+            if (ip_obj) {
+                policy.rule_list[i].dip_r = inet_addr(json_string_value(ip_obj));
+            } else {
+                policy.rule_list[i].dip_r = policy.rule_list[i].dip;
+            }
         }
         policy.rule_list[i].dport = json_integer_value(json_object_get(rule_obj, "port"));
         policy.rule_list[i].dport_r = json_integer_value(json_object_get(rule_obj, "portr"));
@@ -1370,6 +1410,9 @@ static int dp_ctrl_cfg_policy(json_t *msg)
         policy.rule_list[i].action = json_integer_value(json_object_get(rule_obj, "action"));
         policy.rule_list[i].ingress = json_boolean_value(json_object_get(rule_obj, "ingress"));
         policy.rule_list[i].vh = json_boolean_value(json_object_get(rule_obj, "vhost"));
+        policy.rule_list[i].rate = json_integer_value(json_object_get(rule_obj, "rate"));
+        policy.rule_list[i].rate_id = json_integer_value(json_object_get(rule_obj, "rate_id"));
+        policy.rule_list[i].rate_unit = json_integer_value(json_object_get(rule_obj, "rate_unit"));
         fqdn_obj = json_object_get(rule_obj,"fqdn");
         if (fqdn_obj != NULL) {
             strlcpy(policy.rule_list[i].fqdn, json_string_value(fqdn_obj), MAX_FQDN_LEN);
@@ -1456,6 +1499,54 @@ static int dp_ctrl_del_fqdn(json_t *msg)
 
 io_internal_subnet4_t *g_internal_subnet4;
 io_internal_subnet4_t *g_policy_addr;
+io_internal_subnet6_t *g_internal_subnet6;
+
+static int dp_ctrl_cfg_internal_net6(json_t *obj, int flag)
+{
+    int i, count, prev = 0;
+    json_t *nw_obj;
+    io_internal_subnet6_t *subnet6, *old;
+    static io_internal_subnet6_t *t_internal_subnet6 = NULL;
+
+    if (flag & MSG_START) {
+        free(t_internal_subnet6);
+        t_internal_subnet6 = NULL;
+    } else if (t_internal_subnet6) {
+        prev = t_internal_subnet6->count;
+    }
+
+    count = json_array_size(obj);
+    subnet6 = calloc(sizeof(io_internal_subnet6_t) + (prev + count) * sizeof(io_subnet6_t), 1);
+    if (!subnet6) {
+        DEBUG_ERROR(DBG_CTRL, "out of memory!!\n")
+        return -1;
+    }
+    if (prev > 0) {
+        memcpy(&subnet6->list[0], &t_internal_subnet6->list[0], sizeof(io_subnet6_t) * prev);
+    }
+    for (i = 0; i < count; i++) {
+        nw_obj = json_array_get(obj, i);
+        dp_ctrl_parse_ip6(json_object_get(nw_obj, "ip"), &subnet6->list[prev + i].ip);
+        dp_ctrl_parse_ip6(json_object_get(nw_obj, "mask"), &subnet6->list[prev + i].mask);
+    }
+    subnet6->count = prev + count;
+    free(t_internal_subnet6);
+    t_internal_subnet6 = subnet6;
+
+    if (!(flag & MSG_END)) {
+        return 0;
+    }
+
+    old = g_internal_subnet6;
+    g_internal_subnet6 = t_internal_subnet6;
+    t_internal_subnet6 = NULL;
+
+    synchronize_rcu();
+
+    free(old);
+
+    return 0;
+}
 
 //internal:true for internalSubnet, false for policy address map
 static int dp_ctrl_cfg_internal_net(json_t *msg, bool internal)
@@ -1468,6 +1559,9 @@ static int dp_ctrl_cfg_internal_net(json_t *msg, bool internal)
     bool multiple_msg = false;
 
     flag = json_integer_value(json_object_get(msg, "flag"));
+    if (internal) {
+        dp_ctrl_cfg_internal_net6(json_object_get(msg, "subnet_addr6"), flag);
+    }
     obj = json_object_get(msg, "subnet_addr");
     count = json_array_size(obj);
 
@@ -2252,6 +2346,19 @@ static int dp_ctrl_sys_conf(json_t *msg)
     return 0;
 }
 
+static int dp_ctrl_cfg_meter(json_t *msg)
+{
+    uint32_t syn_flood, port_scan;
+
+    syn_flood = json_integer_value(json_object_get(msg, "syn_flood"));
+    port_scan = json_integer_value(json_object_get(msg, "port_scan"));
+
+    DEBUG_CTRL("syn_flood=%u port_scan=%u\n", syn_flood, port_scan);
+
+    dpi_meter_threshold_cfg(syn_flood, port_scan);
+    return 0;
+}
+
 uint8_t g_disable_net_policy = 0;
 
 static int dp_ctrl_disable_net_policy(json_t *msg)
@@ -2385,6 +2492,8 @@ static int dp_ctrl_handler(int fd)
             ret = dp_ctrl_bld_dlp_update_ep(msg);
         } else if (strcmp(key, "ctrl_sys_conf") == 0) {
             ret = dp_ctrl_sys_conf(msg);
+        } else if (strcmp(key, "ctrl_cfg_meter") == 0) {
+            ret = dp_ctrl_cfg_meter(msg);
         } else if (strcmp(key, "ctrl_disable_net_policy") == 0) {
             ret = dp_ctrl_disable_net_policy(msg);
         } else if (strcmp(key, "ctrl_detect_unmanaged_wl") == 0) {
@@ -2613,6 +2722,9 @@ int dp_ctrl_connect_report(DPMsgSession *log, int count_session, int count_viola
                     log->ClientBytes > TUNNEL_THRESHOLD) {
                 conn->ClientPort = log->ClientPort;
             }
+            if (log->MeshID[0] != '\0') {
+                strlcpy(conn->MeshID, log->MeshID, sizeof(conn->MeshID));
+            }
         } else if (dp_rate_limiter_check(rl) == 0 && (n = calloc(sizeof(*n), 1)) != NULL) {
             DPMsgConnect *conn = &n->conn;
             mac_cpy(conn->EPMAC, log->EPMAC);
@@ -2659,6 +2771,7 @@ int dp_ctrl_connect_report(DPMsgSession *log, int count_session, int count_viola
             conn->ThreatID = log->ThreatID;
             conn->Severity = log->Severity;
             conn->PolicyId = log->PolicyId;
+            strlcpy(conn->MeshID, log->MeshID, sizeof(conn->MeshID));
             rcu_map_add(conn4_map, n, &key);
             (*cnt)++;
         }
@@ -2940,6 +3053,7 @@ void dp_ctrl_loop(void)
             last = now;
 
             dp_ctrl_update_app(false);
+            dpi_fqdn_entry_expire();
             dp_ctrl_update_fqdn_ip();
             dp_ctrl_consume_threat_log();
             dp_ctrl_update_ip_fqdn_storage();
@@ -41,6 +41,7 @@ void dpi_init(int reason)
     th_packet.defrag_data = malloc(DPI_MAX_PKT_LEN);
     th_packet.asm_pkt.ptr = malloc(DPI_MAX_PKT_LEN);
     th_packet.decoded_pkt.ptr = malloc(DPI_MAX_PKT_LEN);
+    th_packet.file_text.ptr = malloc(DPI_MAX_PKT_LEN);
 
     timer_wheel_init(&th_timer);
     dpi_frag_init();
@@ -197,6 +198,28 @@ bool dpi_is_ip4_internal(uint32_t ip)
     return false;
 }
 
+bool dpi_is_ip6_internal(struct in6_addr *ip)
+{
+    int i, j;
+    if (unlikely(th_internal_subnet6 == NULL) || (th_internal_subnet6->count == 0)
+        || IN6_IS_ADDR_LOOPBACK(ip) || IN6_IS_ADDR_LINKLOCAL(ip)) {
+        return true;
+    }
+    for (i = 0; i < th_internal_subnet6->count; i++) {
+        io_subnet6_t *subnet = &th_internal_subnet6->list[i];
+        for (j = 0; j < 16; j++) {
+            if ((ip->s6_addr[j] & subnet->mask.s6_addr[j]) != subnet->ip.s6_addr[j]) {
+                break;
+            }
+        }
+        if (j == 16) {
+            return true;
+        }
+    }
+    DEBUG_LOG(DBG_SESSION, NULL, "internal:false\n");
+    return false;
+}
+
 uint8_t dpi_ip4_iptype(uint32_t ip)
 {
     int i;
@@ -431,6 +454,7 @@ int dpi_recv_packet(io_ctx_t *ctx, uint8_t *ptr, int len)
         th_packet.dlp_area[c].dlp_flags = 0;
     }
     th_packet.decoded_pkt.len = 0;
+    th_packet.file_text.len = 0;
 
     th_packet.pkt = ptr;
     th_packet.cap_len = len;
@@ -439,6 +463,7 @@ int dpi_recv_packet(io_ctx_t *ctx, uint8_t *ptr, int len)
     rcu_read_lock();
 
     th_internal_subnet4 = g_internal_subnet4;
+    th_internal_subnet6 = g_internal_subnet6;
     th_policy_addr = g_policy_addr;
     th_specialip_subnet4 = g_specialip_subnet4;
     th_xff_enabled = g_xff_enabled;
@@ -71,6 +71,8 @@ static threat_property_t threat_property[] = {
 [DPI_THRT_SQL_INJECTION]    {THRT_ID_SQL_INJECTION, THRT_SEVERITY_CRITICAL, 0, 0, 10, },
 [DPI_THRT_APACHE_STRUTS_RCE] {THRT_ID_APACHE_STRUTS_RCE, THRT_SEVERITY_CRITICAL, 0, 0, 10, },
 [DPI_THRT_K8S_EXTIP_MITM]    {THRT_ID_K8S_EXTIP_MITM, THRT_SEVERITY_CRITICAL, 0, 0, 10, },
+[DPI_THRT_PORT_SCAN]        {THRT_ID_PORT_SCAN, THRT_SEVERITY_HIGH, 1, 1, 10, },
+[DPI_THRT_CRYPTO_MINING]    {THRT_ID_CRYPTO_MINING, THRT_SEVERITY_HIGH, 0, 0, 10, },
 };
 
 static threat_config_t threat_config[] = {
@@ -103,6 +105,8 @@ static threat_config_t threat_config[] = {
 [DPI_THRT_SQL_INJECTION]    {true, DPI_ACTION_DROP, },
 [DPI_THRT_APACHE_STRUTS_RCE]{true, DPI_ACTION_DROP, },
 [DPI_THRT_K8S_EXTIP_MITM]   {true, DPI_ACTION_DROP, },
+[DPI_THRT_PORT_SCAN]        {false, DPI_ACTION_ALLOW, }, // enabled when the threshold is configured
+[DPI_THRT_CRYPTO_MINING]    {true, DPI_ACTION_ALLOW, },
 };
 
 static int log_dlp_match(struct cds_lfht_node *ht_node, const void *key)
@@ -415,6 +419,12 @@ bool dpi_threat_status(uint32_t idx)
     return threat_config[idx].enable;
 }
 
+void dpi_threat_set_status(uint32_t idx, bool enable)
+{
+    if (unlikely(idx >= DPI_THRT_MAX)) return;
+    threat_config[idx].enable = enable;
+}
+
 uint8_t dpi_threat_action(uint32_t idx)
 {
     if (unlikely(idx >= DPI_THRT_MAX)) return DPI_ACTION_ALLOW;
@@ -798,6 +808,9 @@ void dpi_session_log(dpi_session_t *sess, DPMsgSession *dps)
         if (sess->policy_desc.flags & POLICY_DESC_UWLIP) {
             dps->Flags |= DPSESS_FLAG_UWLIP;
         }
+        if (sess->mesh_id[0] != '\0') {
+            strlcpy(dps->MeshID, sess->mesh_id, sizeof(dps->MeshID));
+        }
     } else {
         dps->EtherType = ETH_P_IPV6;
         memcpy(dps->ClientIP, &c->ip.ip6, 16);
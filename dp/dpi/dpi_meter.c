@@ -21,6 +21,8 @@ static meter_info_t meter_info[] = {
                             3, 30, 1, 2000, 2000},
 [DPI_METER_TCP_NODATA]     {"tcp_nodata", METER_ID_TCP_NODATA, DPI_THRT_TCP_NODATA, true, false, true, false,
                             10, 0, 10, 10, 10},
+[DPI_METER_PORT_SCAN]      {"port_scan", METER_ID_PORT_SCAN, DPI_THRT_PORT_SCAN, true, false, true, true,
+                            3, 30, 1, 100, 100},
 };
 
 meter_info_t *dpi_get_meter_info(int type)
@@ -29,6 +31,38 @@ meter_info_t *dpi_get_meter_info(int type)
     return &meter_info[type];
 }
 
+// Rate limit of a policy rule or of the group the rules lead to. Traffic of all
+// sessions matching the rules of the same rate id is counted in one-second
+// windows; as each thread keeps its own counters, the limit is enforced per dp
+// thread, so the aggregated rate of a node can reach the limit times the number
+// of dp threads.
+typedef struct dpi_ratelimit_ {
+    struct cds_lfht_node node;
+    timer_entry_t ts_entry;
+
+    uint32_t rate_id;
+    uint8_t unit;
+    uint32_t tick;
+    uint32_t count;
+} dpi_ratelimit_t;
+
+#define RATELIMIT_TIMEOUT 60
+
+static int ratelimit_match(struct cds_lfht_node *ht_node, const void *key)
+{
+    dpi_ratelimit_t *r = STRUCT_OF(ht_node, dpi_ratelimit_t, node);
+    const dpi_ratelimit_t *k = key;
+
+    return r->rate_id == k->rate_id && r->unit == k->unit;
+}
+
+static uint32_t ratelimit_hash(const void *key)
+{
+    const dpi_ratelimit_t *k = key;
+
+    return k->rate_id + k->unit;
+}
+
 static int meter_match(struct cds_lfht_node *ht_node, const void *key)
 {
     dpi_meter_t *m = STRUCT_OF(ht_node, dpi_meter_t, node);
@@ -58,6 +92,7 @@ static uint32_t meter_hash(const void *key)
 void dpi_meter_init(void)
 {
     rcu_map_init(&th_meter_map, 512, offsetof(dpi_meter_t, node), meter_match, meter_hash);
+    rcu_map_init(&th_ratelimit_map, 64, offsetof(dpi_ratelimit_t, node), ratelimit_match, ratelimit_hash);
 }
 
 static void make_key(dpi_meter_t *key, int type, uint8_t *ep_mac, uint8_t *peer_ip, bool ipv4)
@@ -276,8 +311,13 @@ int dpi_meter_synflood_inc(dpi_packet_t *p)
         }
 
         if (unlikely(fire)) {
-            // TODO: when to log?
             // not to increase meter drop count
+            meter_info_t *info = &meter_info[m->type];
+            if (th_snap.tick - m->last_log >= info->log_timeout && m->log_count > 0) {
+                dpi_ddos_log(log_id, m, "SYN rate %u(pps) exceeds the shreshold %u(pps)", m->last_count, info->lower_limit);
+                m->last_log = th_snap.tick;
+                m->log_count = 0;
+            }
 
             if (!p->ep->tap) {
                 // TODO: SYN proxy logic goes here
@@ -386,3 +426,113 @@ bool dpi_meter_session_rate(uint8_t type, dpi_session_t *s)
 
     return fire;
 }
+
+static void ratelimit_release(timer_entry_t *entry)
+{
+    dpi_ratelimit_t *r = STRUCT_OF(entry, dpi_ratelimit_t, ts_entry);
+
+    rcu_map_del(&th_ratelimit_map, r);
+    free(r);
+}
+
+// Count the amount, bytes or connections, against the rate limit of the rule or group.
+// Return true if the limit of the current second is exceeded.
+bool dpi_ratelimit_exceed(dpi_policy_desc_t *desc, uint32_t amount)
+{
+    dpi_ratelimit_t key, *r;
+
+    if (desc->rate_unit == DP_POLICY_RATE_NONE || desc->rate == 0) return false;
+
+    memset(&key, 0, sizeof(key));
+    key.rate_id = desc->rate_id;
+    key.unit = desc->rate_unit;
+    r = rcu_map_lookup(&th_ratelimit_map, &key);
+    if (r == NULL) {
+        r = calloc(1, sizeof(*r));
+        if (unlikely(r == NULL)) return false;
+
+        r->rate_id = desc->rate_id;
+        r->unit = desc->rate_unit;
+        r->tick = th_snap.tick;
+        rcu_map_add(&th_ratelimit_map, r, r);
+
+        timer_wheel_entry_init(&r->ts_entry);
+        timer_wheel_entry_start(&th_timer, &r->ts_entry,
+                                ratelimit_release, RATELIMIT_TIMEOUT, th_snap.tick);
+    } else {
+        timer_wheel_entry_refresh(&th_timer, &r->ts_entry, th_snap.tick);
+    }
+
+    if (r->tick != th_snap.tick) {
+        r->tick = th_snap.tick;
+        r->count = 0;
+    }
+
+    // The first packet of a window always passes, so a rate lower than
+    // the packet size doesn't block the traffic completely.
+    if (r->count > 0 && r->count + amount > desc->rate) {
+        DEBUG_LOG(DBG_POLICY, NULL, "policy %u exceeds rate limit %u of %x\n", desc->id, desc->rate, desc->rate_id);
+        return true;
+    }
+    r->count += amount;
+    return false;
+}
+
+// A session is counted when the client is rejected or ignored by the server
+void dpi_meter_port_scan_inc(dpi_session_t *s)
+{
+    uint32_t log_id = DPI_THRT_PORT_SCAN;
+
+    if (!dpi_threat_status(log_id)) return;
+    if (!(s->flags & DPI_SESS_FLAG_INGRESS)) return;
+
+    bool ipv4 = FLAGS_TEST(s->flags, DPI_SESS_FLAG_IPV4);
+    uint8_t *peer_ip = (uint8_t *)&s->client.ip;
+
+    bool fire = false, create = false;
+    dpi_meter_t *m = meter_inc(DPI_METER_PORT_SCAN, s->server.mac, peer_ip, ipv4, &fire, &create);
+    if (unlikely(m == NULL)) return;
+
+    if (unlikely(create || fire)) {
+        DPMsgThreatLog *log = &m->log;
+        memset(log, 0, sizeof(*log));
+        log_common(log, log_id);
+        log_session_flags(log, s);
+        log_session_detail(log, s);
+    }
+
+    if (unlikely(fire)) {
+        meter_info_t *info = &meter_info[m->type];
+        if (th_snap.tick - m->last_log >= info->log_timeout && m->log_count > 0) {
+            if (likely(ipv4)) {
+                dpi_ddos_log(log_id, m, "Port scan rate %u from "DBG_IPV4_FORMAT" exceeds the shreshold %u",
+                             m->last_count, DBG_IPV4_TUPLE(m->peer_ip.ip4), info->lower_limit);
+            } else {
+                dpi_ddos_log(log_id, m, "Port scan rate %u from "DBG_IPV6_FORMAT" exceeds the shreshold %u",
+                             m->last_count, DBG_IPV6_TUPLE(m->peer_ip.ip6), info->lower_limit);
+            }
+            m->last_log = th_snap.tick;
+            m->log_count = 0;
+        }
+    }
+}
+
+// Threshold 0 disables the detection, otherwise it is the rate per second to trigger it.
+static void meter_set_threshold(int type, uint32_t threshold)
+{
+    meter_info_t *info = &meter_info[type];
+
+    if (threshold == 0) {
+        dpi_threat_set_status(info->log_id, false);
+        return;
+    }
+
+    info->upper_limit = info->lower_limit = threshold;
+    dpi_threat_set_status(info->log_id, true);
+}
+
+void dpi_meter_threshold_cfg(uint32_t syn_flood, uint32_t port_scan)
+{
+    meter_set_threshold(DPI_METER_SYN_FLOOD, syn_flood);
+    meter_set_threshold(DPI_METER_PORT_SCAN, port_scan);
+}
@@ -24,6 +24,7 @@ enum {
     DPI_METER_ICMP_FLOOD,
     DPI_METER_IP_SRC_SESSION,
     DPI_METER_TCP_NODATA,
+    DPI_METER_PORT_SCAN,
     DPI_METER_MAX,
 };
 
@@ -53,6 +54,8 @@ int dpi_meter_synflood_inc(dpi_packet_t *p);
 int dpi_meter_session_inc(dpi_packet_t *p, dpi_session_t *s);
 void dpi_meter_session_dec(dpi_session_t *s);
 bool dpi_meter_session_rate(uint8_t type, dpi_session_t *s);
+void dpi_meter_port_scan_inc(dpi_session_t *s);
+bool dpi_ratelimit_exceed(dpi_policy_desc_t *desc, uint32_t amount);
 
 meter_info_t *dpi_get_meter_info(int type);
 
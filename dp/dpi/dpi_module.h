@@ -16,6 +16,7 @@
 
 extern rcu_map_t g_ep_map;
 extern io_internal_subnet4_t *g_internal_subnet4;
+extern io_internal_subnet6_t *g_internal_subnet6;
 extern io_spec_internal_subnet4_t *g_specialip_subnet4;
 extern uint8_t g_xff_enabled;
 extern uint8_t g_disable_net_policy;
@@ -44,12 +45,14 @@ typedef struct dpi_thread_data_ {
     rcu_map_t session6_map;
     rcu_map_t session6_proxymesh_map;
     rcu_map_t meter_map;
+    rcu_map_t ratelimit_map;
     rcu_map_t log_map;
     rcu_map_t unknown_ip_map;
     rcu_map_t ip_fqdn_storage_map;
 	timer_wheel_t timer;
 
 	io_internal_subnet4_t *subnet4;
+	io_internal_subnet6_t *subnet6;
 	io_spec_internal_subnet4_t *specialipsubnet4;
 	io_internal_subnet4_t *policyaddr;
 
@@ -76,12 +79,14 @@ extern dpi_thread_data_t g_dpi_thread_data[];
 #define th_session6_map (g_dpi_thread_data[THREAD_ID].session6_map)
 #define th_session6_proxymesh_map (g_dpi_thread_data[THREAD_ID].session6_proxymesh_map)
 #define th_meter_map    (g_dpi_thread_data[THREAD_ID].meter_map)
+#define th_ratelimit_map (g_dpi_thread_data[THREAD_ID].ratelimit_map)
 #define th_log_map      (g_dpi_thread_data[THREAD_ID].log_map)
 #define th_unknown_ip_map      (g_dpi_thread_data[THREAD_ID].unknown_ip_map)
 #define th_ip_fqdn_storage_map (g_dpi_thread_data[THREAD_ID].ip_fqdn_storage_map)
 #define th_timer        (g_dpi_thread_data[THREAD_ID].timer)
 
 #define th_internal_subnet4 (g_dpi_thread_data[THREAD_ID].subnet4)
+#define th_internal_subnet6 (g_dpi_thread_data[THREAD_ID].subnet6)
 #define th_specialip_subnet4 (g_dpi_thread_data[THREAD_ID].specialipsubnet4)
 #define th_policy_addr (g_dpi_thread_data[THREAD_ID].policyaddr)
 
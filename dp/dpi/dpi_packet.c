@@ -1159,6 +1159,13 @@ int dpi_inspect_ethernet(dpi_packet_t *p)
             dpi_pkt_proto_parser(p);
             dpi_pkt_policy_reeval(p);
         }
+
+        // Drop packets over the rule's bandwidth limit, tcp flow slows down by retransmission
+        if (unlikely(sess->policy_desc.rate_unit == DP_POLICY_RATE_BPS) && !p->ep->tap &&
+            (p->action <= DPI_ACTION_ALLOW || p->action == DPI_ACTION_BYPASS) &&
+            dpi_ratelimit_exceed(&sess->policy_desc, p->cap_len)) {
+            dpi_set_action(p, DPI_ACTION_DROP);
+        }
     }
 
     bool dlp_detect = dpi_dlp_ep_policy_check(p);
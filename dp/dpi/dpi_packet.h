@@ -149,6 +149,7 @@ typedef struct dpi_packet_ {
     uint8_t dlp_match_flags;
     dpi_dlp_area_t dlp_area[DPI_SIG_CONTEXT_TYPE_MAX];
     buf_t decoded_pkt;
+    buf_t file_text;
 
     uint8_t dlp_candidates_overflow;
     uint8_t has_dlp_candidates;
@@ -237,6 +238,7 @@ int dpi_ip_defrag(dpi_packet_t *p);
 int dpi_ipv6_defrag(dpi_packet_t *p);
 
 bool dpi_is_ip4_internal(uint32_t ip);
+bool dpi_is_ip6_internal(struct in6_addr *ip);
 uint8_t dpi_ip4_iptype(uint32_t ip);
 bool dpi_is_policy_addr(uint32_t ip);
 void dpi_print_ip4_internal_fp(FILE *logfp);
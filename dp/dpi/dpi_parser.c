@@ -23,6 +23,9 @@ int dpi_parser_2_app[DPI_PARSER_MAX] = {
 [DPI_PARSER_TNS]        DPI_APP_TNS,
 [DPI_PARSER_TDS]        DPI_APP_TDS,
 [DPI_PARSER_GRPC]       DPI_APP_GRPC,
+[DPI_PARSER_MQTT]       DPI_APP_MQTT,
+[DPI_PARSER_AMQP]       DPI_APP_AMQP,
+[DPI_PARSER_STRATUM]    DPI_APP_STRATUM,
 };
 
 dpi_parser_t *g_tcp_parser[DPI_PARSER_MAX];
@@ -355,6 +358,9 @@ extern dpi_parser_t *dpi_spark_parser(void);
 extern dpi_parser_t *dpi_tns_tcp_parser(void);
 extern dpi_parser_t *dpi_tds_tcp_parser(void);
 extern dpi_parser_t *dpi_grpc_tcp_parser(void);
+extern dpi_parser_t *dpi_mqtt_parser(void);
+extern dpi_parser_t *dpi_amqp_parser(void);
+extern dpi_parser_t *dpi_stratum_parser(void);
 
 static void register_parser(dpi_parser_t *parser)
 {
@@ -387,4 +393,7 @@ void dpi_parser_setup(void)
     register_parser(dpi_tns_tcp_parser());
     register_parser(dpi_tds_tcp_parser());
     register_parser(dpi_grpc_tcp_parser());
+    register_parser(dpi_mqtt_parser());
+    register_parser(dpi_amqp_parser());
+    register_parser(dpi_stratum_parser());
 }
@@ -268,6 +268,12 @@ void dpi_policy_hdl_destroy(dpi_policy_hdl_t *hdl)
     rcu_map_for_each(&hdl->range_policy_map, iter_delete_one_range_rule, hdl);
     rcu_map_destroy(&hdl->policy_map);
     rcu_map_destroy(&hdl->range_policy_map);
+    while (hdl->rule6_list) {
+        dpi_rule6_t *r6 = hdl->rule6_list;
+        hdl->rule6_list = r6->next;
+        free(r6);
+        th_counter.type1_rules--;
+    }
     free(hdl);
 }
 
@@ -493,6 +499,16 @@ static int dpi_add_app_rule(dpi_policy_hdl_t *hdl, dpi_rule_key_t *key_l, dpi_ru
     }
     app_desc.id = app_rule->rule_id;
     app_desc.action = app_rule->action;
+    // app rules merged from other policies don't share the rate limit
+    if (app_rule->rule_id == desc->id) {
+        app_desc.rate = desc->rate;
+        app_desc.rate_id = desc->rate_id;
+        app_desc.rate_unit = desc->rate_unit;
+    } else {
+        app_desc.rate = 0;
+        app_desc.rate_id = 0;
+        app_desc.rate_unit = DP_POLICY_RATE_NONE;
+    }
     app_desc.hdl_ver = 0;
     app_desc.flags = desc->flags;
     app_desc.order = desc->order;
@@ -551,6 +567,171 @@ int dpi_rule_add(dpi_policy_hdl_t *hdl, dpi_rule_key_t *key_l, dpi_rule_key_t *k
     return ret;
 }
 
+static int dpi_rule6_add_one(dpi_policy_hdl_t *hdl, dpi_policy_rule_t *rule, int dir,
+                             uint32_t app, bool any_app, dpi_policy_desc_t *desc)
+{
+    dpi_rule6_t *r6;
+
+    r6 = (dpi_rule6_t *)calloc(1, sizeof(dpi_rule6_t));
+    if (!r6) {
+        DEBUG_ERROR(DBG_POLICY, "OOM!!!\n");
+        return -1;
+    }
+    memcpy(&r6->sip, &rule->sip6, sizeof(struct in6_addr));
+    memcpy(&r6->sip_r, &rule->sip6_r, sizeof(struct in6_addr));
+    memcpy(&r6->dip, &rule->dip6, sizeof(struct in6_addr));
+    memcpy(&r6->dip_r, &rule->dip6_r, sizeof(struct in6_addr));
+    r6->dport = rule->dport;
+    r6->dport_r = rule->dport_r;
+    r6->proto = rule->proto;
+    r6->dir = dir;
+    r6->app = app;
+    r6->any_app = any_app;
+    policy_desc_cpy(&r6->desc, desc);
+
+    if (hdl->rule6_tail) {
+        hdl->rule6_tail->next = r6;
+    } else {
+        hdl->rule6_list = r6;
+    }
+    hdl->rule6_tail = r6;
+    th_counter.type1_rules++;
+    return 1;
+}
+
+static int dpi_rule6_add(dpi_policy_hdl_t *hdl, dpi_policy_rule_t *rule, int dir,
+                         dpi_policy_desc_t *desc)
+{
+    dpi_policy_desc_t app_desc;
+    int i, ret;
+
+    // same as ipv4, a check_app rule is matched first while the application is
+    // not identified yet, otherwise the rule covers any application
+    ret = dpi_rule6_add_one(hdl, rule, dir, 0,
+                            desc->id > 0 && desc->action != DP_POLICY_ACTION_CHECK_APP, desc);
+    if (ret < 0) {
+        return ret;
+    }
+
+    memcpy(&app_desc, desc, sizeof(dpi_policy_desc_t));
+    for (i = 0; i < rule->num_apps; i++) {
+        app_desc.id = rule->app_rules[i].rule_id;
+        app_desc.action = rule->app_rules[i].action;
+        if (app_desc.id != desc->id) {
+            app_desc.rate = 0;
+            app_desc.rate_id = 0;
+            app_desc.rate_unit = DP_POLICY_RATE_NONE;
+        }
+        if (dpi_rule6_add_one(hdl, rule, dir, rule->app_rules[i].app,
+                              rule->app_rules[i].app == DP_POLICY_APP_ANY, &app_desc) == 1) {
+            ret++;
+        }
+    }
+    return ret;
+}
+
+static bool ip6_in_range(struct in6_addr *ip, struct in6_addr *ip_l, struct in6_addr *ip_h)
+{
+    return memcmp(ip, ip_l, sizeof(struct in6_addr)) >= 0 &&
+           memcmp(ip, ip_h, sizeof(struct in6_addr)) <= 0;
+}
+
+static bool dpi_rule6_match(dpi_rule6_t *r6, struct in6_addr *sip, struct in6_addr *dip,
+                            uint16_t dport, uint16_t proto, uint32_t app, int is_ingress,
+                            bool is_internal)
+{
+    if (r6->dir != (is_ingress ? POLICY_RULE_DIR_INGRESS : POLICY_RULE_DIR_EGRESS)) {
+        return false;
+    }
+    if (r6->proto != 0 && r6->proto != proto) {
+        return false;
+    }
+    if (dport < r6->dport || dport > r6->dport_r) {
+        return false;
+    }
+    if (!r6->any_app && r6->app != app) {
+        return false;
+    }
+    // unspecified peer address is the placeholder of external
+    if (is_ingress) {
+        if (!(IN6_IS_ADDR_UNSPECIFIED(&r6->sip) && !is_internal) &&
+            !ip6_in_range(sip, &r6->sip, &r6->sip_r)) {
+            return false;
+        }
+        return ip6_in_range(dip, &r6->dip, &r6->dip_r);
+    } else {
+        if (!(IN6_IS_ADDR_UNSPECIFIED(&r6->dip) && !is_internal) &&
+            !ip6_in_range(dip, &r6->dip, &r6->dip_r)) {
+            return false;
+        }
+        return ip6_in_range(sip, &r6->sip, &r6->sip_r);
+    }
+}
+
+static void dpi_policy6_lookup_by_key(dpi_policy_hdl_t *hdl, struct in6_addr *sip,
+                                      struct in6_addr *dip, uint16_t dport, uint16_t proto,
+                                      uint32_t app, int is_ingress, dpi_policy_desc_t *desc)
+{
+    dpi_rule6_t *r6, *match = NULL;
+    bool is_internal;
+
+    if (unlikely(!hdl || th_disable_net_policy)) {
+        // workload just created, allow traffic pass until policy being configured
+        desc->id = 0;
+        desc->action = DP_POLICY_ACTION_OPEN;
+        desc->flags = POLICY_DESC_CHECK_VER | POLICY_DESC_TMP_OPEN;
+        desc->flags |= dpi_is_ip6_internal(is_ingress ? sip : dip) ?
+                           POLICY_DESC_INTERNAL : POLICY_DESC_EXTERNAL;
+        goto exit;
+    }
+
+    if (proto == IPPROTO_ICMPV6) {
+        // neighbor discovery must never be blocked, allow all icmpv6 as ipv4 icmp
+        desc->id = DEFAULT_ICMP_PASS_POLICY_ID;
+        desc->action = DP_POLICY_ACTION_ALLOW;
+        desc->flags = POLICY_DESC_INTERNAL;
+        desc->order = 0xffffffff;
+        goto exit;
+    }
+
+    is_internal = dpi_is_ip6_internal(is_ingress ? sip : dip);
+    if (is_internal && !(hdl->apply_dir & (is_ingress ? DP_POLICY_APPLY_INGRESS : DP_POLICY_APPLY_EGRESS))) {
+        // east-west traffic is always allowed
+        desc->id = 0;
+        desc->action = DP_POLICY_ACTION_OPEN;
+        desc->flags = POLICY_DESC_INTERNAL;
+        goto exit;
+    }
+
+    for (r6 = hdl->rule6_list; r6 != NULL; r6 = r6->next) {
+        if (!dpi_rule6_match(r6, sip, dip, dport, proto, app, is_ingress, is_internal)) {
+            continue;
+        }
+        // earlier rule wins, an exact application match wins over application any
+        if (match == NULL || r6->desc.order < match->desc.order ||
+            (r6->desc.order == match->desc.order && match->any_app && !r6->any_app)) {
+            match = r6;
+        }
+    }
+
+    if (match) {
+        policy_desc_cpy(desc, &match->desc);
+    } else {
+        desc->id = 0;
+        desc->action = hdl->def_action;
+        desc->flags = POLICY_DESC_CHECK_VER;
+        desc->order = 0xffffffff;
+    }
+    desc->flags |= is_internal ? POLICY_DESC_INTERNAL : POLICY_DESC_EXTERNAL;
+
+exit:
+    desc->hdl_ver = hdl?hdl->ver:0;
+    DEBUG_POLICY("client:"DBG_IPV6_FORMAT" server:"DBG_IPV6_FORMAT":%u app:%u ingress:%d "
+                 "match: " DP_POLICY_DESC_STR "\n",
+                 DBG_IPV6_TUPLE(*sip), DBG_IPV6_TUPLE(*dip), dport, app, is_ingress,
+                 DP_POLICY_DESC(desc));
+}
+
 static dpi_range_rule_item_t *dpi_range_rule_match(dpi_range_rule_t *r, dpi_rule_key_t *key)
 {
     dpi_range_rule_item_t *item = r->range_rule_list;
@@ -569,6 +750,33 @@ static dpi_range_rule_item_t *dpi_range_rule_match(dpi_range_rule_t *r, dpi_rule
     return item;
 }
 
+static int dpi_policy6_lookup(dpi_packet_t *p, dpi_policy_hdl_t *hdl, uint32_t app,
+                              bool to_server, dpi_policy_desc_t *desc)
+{
+    struct ip6_hdr *ip6h = (struct ip6_hdr *)(p->pkt + p->l3);
+    struct in6_addr *sip, *dip;
+    uint16_t dport;
+    int is_ingress;
+
+    switch (p->ip_proto) {
+    case IPPROTO_TCP:
+    case IPPROTO_UDP:
+        dport = to_server ? p->dport : p->sport;
+        break;
+    case IPPROTO_ICMPV6:
+        dport = 0;
+        break;
+    default:
+        return 0;
+    }
+
+    sip = to_server ? &ip6h->ip6_src : &ip6h->ip6_dst;
+    dip = to_server ? &ip6h->ip6_dst : &ip6h->ip6_src;
+    is_ingress = to_server?p->flags & DPI_PKT_FLAG_INGRESS:!(p->flags & DPI_PKT_FLAG_INGRESS);
+    dpi_policy6_lookup_by_key(hdl, sip, dip, dport, p->ip_proto, app, is_ingress, desc);
+    return 0;
+}
+
 int dpi_policy_lookup(dpi_packet_t *p, dpi_policy_hdl_t *hdl, uint32_t app,
                       bool to_server, bool xff, dpi_policy_desc_t *desc, uint32_t xff_replace_dst_ip)
 {
@@ -585,10 +793,15 @@ int dpi_policy_lookup(dpi_packet_t *p, dpi_policy_hdl_t *hdl, uint32_t app,
     if (!xff) {
         memset(desc, 0, sizeof(dpi_policy_desc_t));
     }
-    /* only support ipv4 policy for now */
     switch (p->eth_type) {
     case ETH_P_IP:
         break;
+    case ETH_P_IPV6:
+        // x-forwarded-for address is only tracked as ipv4
+        if (xff) {
+            goto exit;
+        }
+        return dpi_policy6_lookup(p, hdl, app, to_server, desc);
     default:
         not_support = 1;
         break;
@@ -1309,6 +1522,25 @@ int dpi_policy_cfg(int cmd, dpi_policy_t *p, int flag)
             memset(&key, 0, sizeof(key));
             memset(&desc, 0, sizeof(desc));
 
+            if (p->rule_list[i].v6) {
+                // domain name is only resolved to ipv4 addresses, agent doesn't send such rules
+                if (p->rule_list[i].fqdn[0] != '\0') {
+                    DEBUG_ERROR(DBG_POLICY, "domain rule %u is not supported on ipv6: %s\n",
+                                p->rule_list[i].id, p->rule_list[i].fqdn);
+                    continue;
+                }
+                desc.id = p->rule_list[i].id;
+                desc.action = p->rule_list[i].action;
+                desc.rate = p->rule_list[i].rate;
+                desc.rate_id = p->rule_list[i].rate_id;
+                desc.rate_unit = p->rule_list[i].rate_unit;
+                desc.flags = POLICY_DESC_CHECK_VER;
+                desc.order = ++order;
+                dir = p->rule_list[i].ingress?POLICY_RULE_DIR_INGRESS:POLICY_RULE_DIR_EGRESS;
+                dpi_rule6_add(hdl, &p->rule_list[i], dir, &desc);
+                continue;
+            }
+
             if (p->rule_list[i].fqdn[0] != '\0') {
                uint32_t code;
                if (p->rule_list[i].ingress) {
@@ -1341,6 +1573,9 @@ int dpi_policy_cfg(int cmd, dpi_policy_t *p, int flag)
             key.proto =  p->rule_list[i].proto;
             desc.id = p->rule_list[i].id;
             desc.action = p->rule_list[i].action;
+            desc.rate = p->rule_list[i].rate;
+            desc.rate_id = p->rule_list[i].rate_id;
+            desc.rate_unit = p->rule_list[i].rate_unit;
             desc.flags = POLICY_DESC_CHECK_VER;
             desc.order = ++order;
             dir = p->rule_list[i].ingress?POLICY_RULE_DIR_INGRESS:POLICY_RULE_DIR_EGRESS;
@@ -1469,6 +1704,7 @@ static dpi_fqdn_hdl_t *dpi_fqdn_hdl_init()
     rcu_map_init(&(hdl->fqdn_ipv4_map), 32, offsetof(fqdn_ipv4_entry_t, node),
                  fqdn_ipv4_match, fqdn_ipv4_hash);
     CDS_INIT_LIST_HEAD(&hdl->del_rlist);
+    CDS_INIT_LIST_HEAD(&hdl->del_iplist);
     return hdl;
 }
 
@@ -1545,14 +1781,46 @@ static bool find_record_or_ipentry(fqdn_ipv4_entry_t *entry, fqdn_record_t *r, b
     }
 }
 
+//caller make sure r is not NULL
+static fqdn_ipv4_item_t *find_record_ip_item(fqdn_record_t *r, uint32_t ip)
+{
+    fqdn_ipv4_item_t *ipv4_itr, *ipv4_next;
+    cds_list_for_each_entry_safe(ipv4_itr, ipv4_next, &r->iplist, node) {
+        if (ipv4_itr->ip == ip) {
+            return ipv4_itr;
+        }
+    }
+    return NULL;
+}
+
+//ip configured by ctrl never expires. Wildcard name cannot be resolved by
+//ctrl, its ips are all learned from dns, so they always age out. Snooped
+//ttl, including 0, is clamped to the min and max ttl.
+static uint32_t fqdn_ip_expire(fqdn_record_t *r, uint32_t ttl, bool configured)
+{
+    if (configured) {
+        if (!(r->flag & FQDN_RECORD_WILDCARD)) {
+            return 0;
+        }
+        ttl = FQDN_IP_DEFAULT_TTL;
+    }
+    if (ttl < FQDN_IP_MIN_TTL) {
+        ttl = FQDN_IP_MIN_TTL;
+    } else if (ttl > FQDN_IP_MAX_TTL) {
+        ttl = FQDN_IP_MAX_TTL;
+    }
+    return get_current_time() + ttl;
+}
+
 //caller make sure entry and r is not NULL
-static int config_record_ip_list(fqdn_ipv4_entry_t *entry, fqdn_record_t *r)
+static int config_record_ip_list(fqdn_ipv4_entry_t *entry, fqdn_record_t *r, uint32_t ttl, bool configured)
 {
     if (!entry || !r){
         return -1;
     }
     fqdn_record_item_t *record_item;
-    fqdn_ipv4_item_t *ipv4_item;
+    fqdn_ipv4_item_t *ipv4_item, *exist_item;
+    uint32_t expire = fqdn_ip_expire(r, ttl, configured);
     //init list
     config_fqdn_init_ip_record_list(entry,r);
     //one ip can map to multiple fqdn record
@@ -1576,15 +1844,23 @@ static int config_record_ip_list(fqdn_ipv4_entry_t *entry, fqdn_record_t *r)
     } else {
         free(record_item);
     }
-    if (!find_record_or_ipentry(entry, r, false)) {
+    exist_item = find_record_ip_item(r, entry->ip);
+    if (exist_item == NULL) {
         //add ipv4_item to fqdn_record's iplist
         ipv4_item->ip = entry->ip;
+        ipv4_item->expire = expire;
         cds_list_add_tail((struct cds_list_head *)ipv4_item, &r->iplist);
         r->ip_cnt++;
         //DEBUG_POLICY("add ip(0x%08x) to record(%p)code(0x%08x)'s iplist, ipcnt(%u)\n", ipv4_item->ip, r, r->code, r->ip_cnt);
         //return 1 means this ip may need to be sent to consul
         return 1;
     } else {
+        //refresh ttl, configured ip stays until ctrl removes the name
+        if (expire == 0) {
+            exist_item->expire = 0;
+        } else if (exist_item->expire != 0 && exist_item->expire < expire) {
+            exist_item->expire = expire;
+        }
         free(ipv4_item);
     }
     return 0;
@@ -1652,7 +1928,7 @@ uint32_t config_fqdn_ipv4_mapping(dpi_fqdn_hdl_t *hdl, char *name, uint32_t ip,
             DEBUG_ERROR(DBG_POLICY, "OOM!!!\n");
         } else {
             entry->ip = ip;
-            if (config_record_ip_list(entry, r) < 0){
+            if (config_record_ip_list(entry, r, 0, true) < 0){
                 free(entry);
                 return r->code;
             }
@@ -1661,7 +1937,7 @@ uint32_t config_fqdn_ipv4_mapping(dpi_fqdn_hdl_t *hdl, char *name, uint32_t ip,
             DEBUG_POLICY("create record ip:%x name:%s code %x\n", ip, name, r->code);
         }
     } else {
-        config_record_ip_list(ipv4_entry, r);
+        config_record_ip_list(ipv4_entry, r, 0, true);
     }
     return r->code;
 }
@@ -1759,7 +2035,7 @@ static int policy_match_ipv4_fqdn_code(dpi_fqdn_hdl_t *fqdn_hdl, uint32_t ip, dp
 }
 
 //caller make sure hdl, name_entry and ip not NULL
-static int associate_ip_record(dpi_fqdn_hdl_t *hdl, fqdn_name_entry_t *name_entry, uint32_t *ip, int cnt)
+static int associate_ip_record(dpi_fqdn_hdl_t *hdl, fqdn_name_entry_t *name_entry, uint32_t *ip, uint32_t *ttl, int cnt)
 {
     fqdn_ipv4_entry_t *ipv4_entry = NULL;
     int i, ret;
@@ -1767,7 +2043,7 @@ static int associate_ip_record(dpi_fqdn_hdl_t *hdl, fqdn_name_entry_t *name_entr
     for (i = 0; i < cnt; i++) {
         ipv4_entry = rcu_map_lookup(&hdl->fqdn_ipv4_map, &ip[i]);
         if (ipv4_entry) {
-            ret = config_record_ip_list(ipv4_entry, name_entry->r);
+            ret = config_record_ip_list(ipv4_entry, name_entry->r, ttl[i], false);
             //existing ip entry associated with wildcard fqdn
             if ((ret == 1) && (name_entry->r->flag & FQDN_RECORD_WILDCARD)) {
                 new_ip = true;
@@ -1779,7 +2055,7 @@ static int associate_ip_record(dpi_fqdn_hdl_t *hdl, fqdn_name_entry_t *name_entr
                 return -1;
             }
             ipv4_entry->ip = ip[i];
-            if (config_record_ip_list(ipv4_entry, name_entry->r) < 0){
+            if (config_record_ip_list(ipv4_entry, name_entry->r, ttl[i], false) < 0){
                 free(ipv4_entry);
                 return -1;
             }
@@ -1799,7 +2075,7 @@ static int associate_ip_record(dpi_fqdn_hdl_t *hdl, fqdn_name_entry_t *name_entr
 }
 
 // Called from parser
-int snooped_fqdn_ipv4_mapping(char *name, uint32_t *ip, int cnt)
+int snooped_fqdn_ipv4_mapping(char *name, uint32_t *ip, uint32_t *ttl, int cnt)
 {
     fqdn_name_entry_t *name_entry = NULL;
     dpi_fqdn_hdl_t *hdl = g_fqdn_hdl;
@@ -1813,7 +2089,7 @@ int snooped_fqdn_ipv4_mapping(char *name, uint32_t *ip, int cnt)
     if (name_entry &&
         !(name_entry->r->flag & (FQDN_RECORD_TO_DELETE|FQDN_RECORD_DELETED))) {
         DEBUG_POLICY("exact match name: (%s)\n", name);
-        associate_ip_record(hdl, name_entry, ip, cnt);
+        associate_ip_record(hdl, name_entry, ip, ttl, cnt);
     }
     char dname[MAX_FQDN_LEN];
     int i, j;
@@ -1831,7 +2107,7 @@ int snooped_fqdn_ipv4_mapping(char *name, uint32_t *ip, int cnt)
             if (name_entry &&
                 !(name_entry->r->flag & (FQDN_RECORD_TO_DELETE|FQDN_RECORD_DELETED))) {
                 DEBUG_POLICY("wildcard match name: (%s)\n", &dname[i]);
-                associate_ip_record(hdl, name_entry, ip, cnt);
+                associate_ip_record(hdl, name_entry, ip, ttl, cnt);
             }
         }
     }
@@ -2000,6 +2276,94 @@ void dpi_fqdn_entry_delete_marked()
     return;
 }
 
+bool check_fqdn_ipv4_expire(struct cds_lfht_node *ht_node, void *args)
+{
+    fqdn_iter_ctx_t *ctx = (fqdn_iter_ctx_t *)args;
+    fqdn_name_entry_t *name_entry = (fqdn_name_entry_t *)ht_node;
+    fqdn_record_t *r = name_entry->r;
+    fqdn_ipv4_item_t *ipv4_itr, *ipv4_next;
+    bool expired = false;
+
+    if (r->flag & (FQDN_RECORD_TO_DELETE|FQDN_RECORD_DELETED)) {
+        return false;
+    }
+    if(r->iplist.prev==NULL && r->iplist.next==NULL) {
+        return false;
+    }
+    cds_list_for_each_entry_safe(ipv4_itr, ipv4_next, &r->iplist, node) {
+        if (ipv4_itr->expire == 0 || ipv4_itr->expire > ctx->now) {
+            continue;
+        }
+        if (ctx->hdl->del_ipv4_cnt == DPI_FQDN_DELETE_QLEN) {
+            ctx->more = true;
+            break;
+        }
+
+        fqdn_ipv4_entry_t *entry = rcu_map_lookup(&ctx->hdl->fqdn_ipv4_map, &ipv4_itr->ip);
+        if (entry) {
+            fqdn_record_item_t *r_itr, *r_next;
+            cds_list_for_each_entry_safe(r_itr, r_next, &entry->rlist, node) {
+                if (r_itr->r == r) {
+                    cds_list_del((struct cds_list_head *)r_itr);
+                    cds_list_add_tail((struct cds_list_head *)r_itr, &ctx->hdl->del_rlist);
+                }
+            }
+            if (cds_list_empty(&entry->rlist)) {
+                enqueue_fqdn_ipv4_to_del(ctx->hdl, entry);
+                rcu_map_del(&ctx->hdl->fqdn_ipv4_map, entry);
+            }
+        }
+        DEBUG_POLICY("Expire fqdn ipv4 %x name %s\n", ipv4_itr->ip, r->name);
+        cds_list_del((struct cds_list_head *)ipv4_itr);
+        cds_list_add_tail((struct cds_list_head *)ipv4_itr, &ctx->hdl->del_iplist);
+        r->ip_cnt--;
+        expired = true;
+    }
+    if (expired && (r->flag & FQDN_RECORD_WILDCARD)) {
+        //let ctrl learn the shrunk ip set
+        uatomic_set(&r->record_updated, 1);
+    }
+    return ctx->more;
+}
+
+// Called by ctrl thread periodically to age out dns learned ips
+void dpi_fqdn_entry_expire()
+{
+    fqdn_iter_ctx_t ctx;
+
+    if (g_fqdn_hdl == NULL) {
+        return;
+    }
+
+    memset(&ctx, 0, sizeof(ctx));
+    ctx.hdl = g_fqdn_hdl;
+    ctx.now = get_current_time();
+
+    do {
+        ctx.more = false;
+        rcu_read_lock();
+        rcu_map_for_each(&ctx.hdl->fqdn_name_map, check_fqdn_ipv4_expire, &ctx);
+        rcu_read_unlock();
+
+        if (cds_list_empty(&ctx.hdl->del_iplist)) {
+            break;
+        }
+        synchronize_rcu();
+        free_fqdn_ipv4(ctx.hdl);
+
+        fqdn_ipv4_item_t *ipv4_itr, *ipv4_next;
+        cds_list_for_each_entry_safe(ipv4_itr, ipv4_next, &ctx.hdl->del_iplist, node) {
+            cds_list_del((struct cds_list_head *)ipv4_itr);
+            free(ipv4_itr);
+        }
+        fqdn_record_item_t *r_itr, *r_next;
+        cds_list_for_each_entry_safe(r_itr, r_next, &ctx.hdl->del_rlist, node) {
+            cds_list_del((struct cds_list_head *)r_itr);
+            free(r_itr);
+        }
+    } while (ctx.more);
+}
+
 int dpi_policy_init() {
     g_fqdn_hdl = dpi_fqdn_hdl_init();
     if (g_fqdn_hdl == NULL) {
@@ -22,7 +22,8 @@ typedef struct dpi_rule_key_ {
 typedef struct dpi_policy_desc_ {
     uint32_t id;
     uint8_t action;
-    uint8_t pad[3];
+    uint8_t rate_unit;
+    uint8_t pad[2];
     uint16_t flags;
 #define POLICY_DESC_CHECK_VER      0x0001
 #define POLICY_DESC_INTERNAL       0x0002
@@ -37,6 +38,8 @@ typedef struct dpi_policy_desc_ {
 #define POLICY_DESC_UWLIP          0x0400
     uint16_t hdl_ver;
     uint32_t order;
+    uint32_t rate;
+    uint32_t rate_id; // rules sharing the id, e.g. rules to a rate limited group, share one meter
 } dpi_policy_desc_t;
 
 typedef struct dpi_rule_ {
@@ -66,11 +69,29 @@ typedef struct dpi_range_rule_ {
     dpi_range_rule_item_t *range_rule_list;
 } dpi_range_rule_t;
 
+// ipv6 rules are few per workload, keep them in a list ordered by rule order
+typedef struct dpi_rule6_ {
+    struct dpi_rule6_ *next;
+    dpi_policy_desc_t desc;
+    struct in6_addr sip;
+    struct in6_addr sip_r;
+    struct in6_addr dip;
+    struct in6_addr dip_r;
+    uint16_t dport;
+    uint16_t dport_r;
+    uint16_t proto;
+    uint8_t dir;
+    uint8_t any_app;
+    uint32_t app;
+} dpi_rule6_t;
+
 typedef struct dpi_policy_hdl_ {
     uint16_t ref_cnt;
     uint16_t ver;
     rcu_map_t policy_map;
     rcu_map_t range_policy_map;
+    dpi_rule6_t *rule6_list;
+    dpi_rule6_t *rule6_tail;
     int def_action;
     int apply_dir;
     uint32_t flag;
@@ -95,7 +116,7 @@ int dpi_policy_lookup(dpi_packet_t *p, dpi_policy_hdl_t *hdl, uint32_t app,
                       bool to_server, bool xff, dpi_policy_desc_t *desc, uint32_t xff_replace_dst_ip);
 int dpi_policy_reeval(dpi_packet_t *p, bool to_server);
 int dpi_policy_init();
-int snooped_fqdn_ipv4_mapping(char *name, uint32_t *ip, int cnt);
+int snooped_fqdn_ipv4_mapping(char *name, uint32_t *ip, uint32_t *ttl, int cnt);
 int sniff_ip_fqdn_storage(char *name, uint32_t *ip, int cnt);
 void dpi_unknown_ip_init(void);
 void dpi_ip_fqdn_storage_init(void);
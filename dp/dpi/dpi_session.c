@@ -708,6 +708,9 @@ static void dpi_fill_proxymesh_policy_desc(dpi_packet_t *p, bool to_server, dpi_
 
     desc->id = 0;
     desc->action = DP_POLICY_ACTION_OPEN;
+    desc->rate = 0;
+    desc->rate_id = 0;
+    desc->rate_unit = DP_POLICY_RATE_NONE;
     //desc->flags = POLICY_DESC_CHECK_VER;
     desc->flags = 0;
 
@@ -740,6 +743,12 @@ static dpi_session_t *dpi_session_create(dpi_packet_t *p, bool to_server)
             dpi_set_action(p, DPI_ACTION_DROP);
             return NULL;
         }
+        // Throttle new connections, the client will retry.
+        if (unlikely(policy_desc.rate_unit == DP_POLICY_RATE_CPS) && !p->ep->tap &&
+            dpi_ratelimit_exceed(&policy_desc, 1)) {
+            dpi_set_action(p, DPI_ACTION_DROP);
+            return NULL;
+        }
     } else {
         //no policy match for proxymesh 'lo' i/f's session
         dpi_fill_proxymesh_policy_desc(p,to_server,&policy_desc);
@@ -1187,6 +1196,8 @@ const char *get_tcp_event_name(int evt)
 
 static void tcp_scan_detection_reset(dpi_packet_t *p, dpi_session_t *s)
 {
+    if (FLAGS_TEST(s->flags, DPI_SESS_FLAG_SCAN)) return;
+
     if (!dpi_is_client_pkt(p) &&
         s->client.tcp_state == TCP_SYN_SENT && s->server.tcp_state == TCP_SYN_RECV) {
         // SYN -> RST, not necessarily a scan, could be mis-config
@@ -1199,10 +1210,16 @@ static void tcp_scan_detection_reset(dpi_packet_t *p, dpi_session_t *s)
         // RST is the first packet of the wing. This could be ACK scan.
         FLAGS_SET(s->flags, DPI_SESS_FLAG_SCAN);
     }
+
+    if (FLAGS_TEST(s->flags, DPI_SESS_FLAG_SCAN)) {
+        dpi_meter_port_scan_inc(s);
+    }
 }
 
 static void tcp_scan_detection_release(dpi_session_t *s)
 {
+    if (FLAGS_TEST(s->flags, DPI_SESS_FLAG_SCAN)) return;
+
     if (s->client.tcp_state == TCP_SYN_SENT && s->server.tcp_state == TCP_SYN_RECV) {
         // SYN with no reply, not necessarily a scan, could be mis-config
         FLAGS_SET(s->flags, DPI_SESS_FLAG_SCAN);
@@ -1211,6 +1228,10 @@ static void tcp_scan_detection_release(dpi_session_t *s)
     } else if (FLAGS_TEST(s->flags, DPI_SESS_FLAG_MID_STREAM) && (s->client.pkts == 0 || s->server.pkts == 0)) {
         FLAGS_SET(s->flags, DPI_SESS_FLAG_SCAN);
     }
+
+    if (FLAGS_TEST(s->flags, DPI_SESS_FLAG_SCAN)) {
+        dpi_meter_port_scan_inc(s);
+    }
 }
 
 static int tcp_update_state(dpi_packet_t *p, dpi_session_t *s)
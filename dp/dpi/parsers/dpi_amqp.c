@@ -0,0 +1,187 @@
+#include <string.h>
+#include <ctype.h>
+
+#include "dpi/dpi_module.h"
+
+#define AMQP_PROTO_HDR_LEN    8
+#define AMQP_FRAME_HDR_LEN    7
+
+#define AMQP_FRAME_METHOD     1
+#define AMQP_CLASS_CONNECTION 10
+#define AMQP_METHOD_START     10
+
+/* Protocol header: "AMQP" followed by 4 version bytes
+ * 0-9-1: AMQP 0 0 9 1
+ * 1.0  : AMQP id 1 0 0, id 0: amqp, 2: tls, 3: sasl
+ */
+static uint8_t amqp_proto_hdr[] = {'A', 'M', 'Q', 'P'};
+
+typedef struct amqp_wing_ {
+    uint32_t seq;
+} amqp_wing_t;
+
+typedef struct amqp_data_ {
+    amqp_wing_t client, server;
+    uint8_t major, minor;
+    bool has_proto_hdr;
+} amqp_data_t;
+
+//return 0 : not enough data
+//       1 : amqp protocol header
+//       -1: not amqp protocol header
+static int amqp_parse_proto_hdr(amqp_data_t *data, uint8_t *ptr, uint32_t len)
+{
+    if (len < AMQP_PROTO_HDR_LEN) {
+        return memcmp(ptr, amqp_proto_hdr, min(len, sizeof(amqp_proto_hdr))) == 0 ? 0 : -1;
+    }
+    if (memcmp(ptr, amqp_proto_hdr, sizeof(amqp_proto_hdr)) != 0) {
+        return -1;
+    }
+
+    if (ptr[4] == 0 && ptr[5] == 0 && ptr[6] == 9) {
+        // 0-9 and 0-9-1
+        data->major = 0;
+        data->minor = 9;
+    } else if (ptr[4] <= 3 && ptr[5] == 1 && ptr[6] == 0 && ptr[7] == 0) {
+        data->major = 1;
+        data->minor = 0;
+    } else {
+        return -1;
+    }
+    return 1;
+}
+
+//return 0 : not enough data
+//       1 : amqp server response
+//       -1: not amqp server response
+static int amqp_parse_server(amqp_data_t *data, uint8_t *ptr, uint32_t len)
+{
+    //server echos protocol header in 1.0, or replies its supported
+    //version when the requested one is not acceptable
+    if (ptr[0] == 'A') {
+        amqp_data_t srv;
+        return amqp_parse_proto_hdr(&srv, ptr, len);
+    }
+
+    if (data->major != 0) {
+        return -1;
+    }
+
+    //0-9-1 server starts with connection.start method on channel 0
+    if (len < AMQP_FRAME_HDR_LEN + 4) {
+        return 0;
+    }
+    if (ptr[0] != AMQP_FRAME_METHOD || GET_BIG_INT16(ptr + 1) != 0) {
+        return -1;
+    }
+    if (GET_BIG_INT16(ptr + AMQP_FRAME_HDR_LEN) != AMQP_CLASS_CONNECTION ||
+        GET_BIG_INT16(ptr + AMQP_FRAME_HDR_LEN + 2) != AMQP_METHOD_START) {
+        return -1;
+    }
+    return 1;
+}
+
+static void amqp_parser(dpi_packet_t *p)
+{
+    amqp_data_t *data;
+    uint8_t *ptr;
+    uint32_t len;
+    int res;
+
+    DEBUG_LOG_FUNC_ENTRY(DBG_PARSER,NULL);
+
+    if (unlikely((data = dpi_get_parser_data(p)) == NULL)) {
+        if (!dpi_is_client_pkt(p)) {
+            DEBUG_LOG(DBG_PARSER, p, "Not AMQP: First packet from server\n");
+            dpi_fire_parser(p);
+            return;
+        }
+
+        if ((data = calloc(1, sizeof(*data))) == NULL) {
+            dpi_fire_parser(p);
+            return;
+        }
+
+        dpi_session_t *s = p->session;
+        data->client.seq = s->client.init_seq;
+        data->server.seq = s->server.init_seq;
+        dpi_put_parser_data(p, data);
+    }
+
+    amqp_wing_t *w;
+    w = dpi_is_client_pkt(p) ? &data->client : &data->server;
+
+    if (w->seq == p->this_wing->init_seq) {
+        ptr = dpi_pkt_ptr(p);
+        len = dpi_pkt_len(p);
+    } else if (dpi_is_seq_in_pkt(p, w->seq)) {
+        uint32_t shift = u32_distance(dpi_pkt_seq(p), w->seq);
+        ptr = dpi_pkt_ptr(p) + shift;
+        len = dpi_pkt_len(p) - shift;
+    } else {
+        dpi_fire_parser(p);
+        return;
+    }
+
+    if (len == 0) {
+        return;
+    }
+
+    if (dpi_is_client_pkt(p)) {
+        if (data->has_proto_hdr) {
+            w->seq += len;
+            dpi_set_asm_seq(p, w->seq);
+            return;
+        }
+
+        res = amqp_parse_proto_hdr(data, ptr, len);
+        if (res == 1) {
+            DEBUG_LOG(DBG_PARSER, p, "AMQP: protocol header, version=%u-%u\n", data->major, data->minor);
+            data->has_proto_hdr = true;
+            w->seq += len;
+            dpi_set_asm_seq(p, w->seq);
+        } else if (res == -1) {
+            DEBUG_LOG(DBG_PARSER, p, "Not AMQP protocol header\n");
+            dpi_fire_parser(p);
+        }
+    } else {
+        if (!data->has_proto_hdr) {
+            dpi_fire_parser(p);
+            return;
+        }
+
+        res = amqp_parse_server(data, ptr, len);
+        if (res == 1) {
+            DEBUG_LOG(DBG_PARSER, p, "AMQP: server response\n");
+            dpi_finalize_parser(p);
+            dpi_ignore_parser(p);
+        } else if (res == -1) {
+            DEBUG_LOG(DBG_PARSER, p, "Not AMQP server\n");
+            dpi_fire_parser(p);
+        }
+    }
+}
+
+static void amqp_new_session(dpi_packet_t *p)
+{
+    dpi_hire_parser(p);
+}
+
+static void amqp_delete_data(void *data)
+{
+    free(data);
+}
+
+static dpi_parser_t dpi_parser_amqp = {
+    new_session: amqp_new_session,
+    delete_data: amqp_delete_data,
+    parser:      amqp_parser,
+    name:        "amqp",
+    ip_proto:    IPPROTO_TCP,
+    type:        DPI_PARSER_AMQP,
+};
+
+dpi_parser_t *dpi_amqp_parser(void)
+{
+    return &dpi_parser_amqp;
+}
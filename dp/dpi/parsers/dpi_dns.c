@@ -19,6 +19,7 @@ typedef struct dns_question_ {
 
 typedef struct dns_answer_ {
     bool ip;
+    uint32_t ttl;
     char question[MAX_LABEL_LEN];
     union {
         uint32_t ip4;
@@ -236,6 +237,7 @@ static int get_domain_ip_mapping(dpi_packet_t *p, dns_question_t* questions, int
     for (i=0; i < qn; i++) {
         char name[MAX_LABEL_LEN];
         uint32_t ips[an];
+        uint32_t ttls[an];
         int cnt = 0;
 
         strlcpy(name, questions[i].question, MAX_LABEL_LEN);
@@ -246,6 +248,7 @@ static int get_domain_ip_mapping(dpi_packet_t *p, dns_question_t* questions, int
                     DEBUG_LOG(DBG_PARSER, p, "%s --> "DBG_IPV4_FORMAT"\n",questions[i].question,
                             DBG_IPV4_TUPLE(answers[j].ip4));
                     ips[cnt] = answers[j].ip4;
+                    ttls[cnt] = answers[j].ttl;
                     cnt++;
                 } else {
                     strlcpy(name, answers[j].cname, MAX_LABEL_LEN);
@@ -253,7 +256,7 @@ static int get_domain_ip_mapping(dpi_packet_t *p, dns_question_t* questions, int
             }
         }
         if (cnt > 0) {
-            snooped_fqdn_ipv4_mapping(questions[i].question, ips, cnt);
+            snooped_fqdn_ipv4_mapping(questions[i].question, ips, ttls, cnt);
             sniff_ip_fqdn_storage(questions[i].question, ips, cnt);
         }
     }
@@ -312,6 +315,7 @@ static int dns_answer(dpi_packet_t *p, uint8_t *ptr, int len, int shift, int cou
         }
 
         uint16_t type = ntohs(*(uint16_t *)(ptr + shift));
+        uint32_t ttl = ntohl(*(uint32_t *)(ptr + shift + 4));
         uint16_t rd_len = ntohs(*(uint16_t *)(ptr + shift + 8));
         shift += 10;
 
@@ -327,6 +331,7 @@ static int dns_answer(dpi_packet_t *p, uint8_t *ptr, int len, int shift, int cou
                 uint8_t *addr = ptr + shift;
                 memcpy(&answers[*aw_count].ip4, addr, 4);
                 answers[*aw_count].ip = true;
+                answers[*aw_count].ttl = ttl;
                 (*aw_count)++;
             }
         }else if (type == DNS_TYPE_CNAME && answers != NULL) {
@@ -25,6 +25,11 @@
 
 /* Flags */
 #define HTTP2_FLAGS_ACK 0x01 /* for SETTINGS */
+#define HTTP2_FLAGS_PADDED 0x08 /* for DATA */
+
+/* gRPC length-prefixed message: compressed flag(1) + length(4) */
+#define GRPC_MSG_PREFIX_LENGTH 5
+#define GRPC_MSG_FLAG_COMPRESSED 0x01
 
 #define HTTP2_HEADER_CONTENT_TYPE "content-type"/*12 bytes*/
 #define HTTP2_HDR_CONT_TYPE_LEN 12
@@ -61,6 +66,17 @@ typedef struct grpc_wing_ {
     uint32_t h2_strm_hdr_cnt;
     uint32_t pktcnt;
     uint8_t setting_flag;
+
+    /* state to decode DATA frames after grpc is identified */
+    uint8_t frame_hdr[HTTP2_FRAME_HEADER_LENGTH];
+    uint8_t frame_hdr_len;
+    uint8_t frame_type;
+    bool pad_pending;
+    uint32_t frame_left;
+    uint32_t data_left;
+    uint8_t msg_prefix[GRPC_MSG_PREFIX_LENGTH];
+    uint8_t msg_prefix_len;
+    uint32_t msg_left;
 } grpc_wing_t;
 
 typedef struct grpc_data_ {
@@ -101,6 +117,105 @@ static bool grpc_find_grpc_content_type(dpi_packet_t *p, uint8_t *ptr, uint32_t
     return false;
 }
 
+// Collect message payload of grpc DATA frames into decoded buffer, so
+// dlp/waf patterns can match the message without http2 framing. Frames
+// and messages can span multiple packets.
+// Compressed messages (grpc-encoding gzip, deflate or snappy) are NOT
+// inspected, dp does not link a decompression library. They are skipped
+// and counted, so the gap is visible in the debug log.
+static void grpc_decode_msg(dpi_packet_t *p, grpc_wing_t *w, uint8_t *ptr, uint32_t len, buf_t *dec)
+{
+    uint32_t n;
+
+    while (len > 0) {
+        if (w->msg_prefix_len < GRPC_MSG_PREFIX_LENGTH) {
+            n = min(GRPC_MSG_PREFIX_LENGTH - w->msg_prefix_len, len);
+            memcpy(w->msg_prefix + w->msg_prefix_len, ptr, n);
+            w->msg_prefix_len += n;
+            ptr += n;
+            len -= n;
+            if (w->msg_prefix_len == GRPC_MSG_PREFIX_LENGTH) {
+                w->msg_left = GET_BIG_INT32(w->msg_prefix + 1);
+                if ((w->msg_prefix[0] & GRPC_MSG_FLAG_COMPRESSED) && w->msg_left > 0) {
+                    th_counter.grpc_compressed_msgs ++;
+                    DEBUG_LOG(DBG_PARSER, p, "skip compressed grpc message len(%u) total(%llu)\n",
+                              w->msg_left, th_counter.grpc_compressed_msgs);
+                }
+                if (w->msg_left == 0) {
+                    w->msg_prefix_len = 0;
+                }
+            }
+            continue;
+        }
+
+        n = min(w->msg_left, len);
+        if (!(w->msg_prefix[0] & GRPC_MSG_FLAG_COMPRESSED)) {
+            uint32_t room = DPI_MAX_PKT_LEN - dec->len;
+            memcpy(dec->ptr + dec->len, ptr, min(n, room));
+            dec->len += min(n, room);
+        }
+        ptr += n;
+        len -= n;
+        w->msg_left -= n;
+        if (w->msg_left == 0) {
+            w->msg_prefix_len = 0;
+        }
+    }
+}
+
+static void grpc_decode_data(dpi_packet_t *p, grpc_wing_t *w, uint8_t *ptr, uint32_t len)
+{
+    uint8_t *end = ptr + len;
+    buf_t *dec = &p->decoded_pkt;
+    uint32_t n;
+
+    dec->len = 0;
+    dec->seq = dpi_ptr_2_seq(p, ptr);
+
+    while (ptr < end) {
+        if (w->frame_left == 0) {
+            n = min(HTTP2_FRAME_HEADER_LENGTH - w->frame_hdr_len, end - ptr);
+            memcpy(w->frame_hdr + w->frame_hdr_len, ptr, n);
+            w->frame_hdr_len += n;
+            ptr += n;
+            if (w->frame_hdr_len < HTTP2_FRAME_HEADER_LENGTH) {
+                break;
+            }
+            w->frame_hdr_len = 0;
+            w->frame_left = GET_BIG_INT24(w->frame_hdr);
+            w->frame_type = w->frame_hdr[3];
+            w->pad_pending = (w->frame_type == HTTP2_DATA && (w->frame_hdr[4] & HTTP2_FLAGS_PADDED));
+            w->data_left = w->pad_pending ? 0 : w->frame_left;
+            continue;
+        }
+
+        n = min(w->frame_left, end - ptr);
+        if (w->frame_type == HTTP2_DATA) {
+            uint8_t *dptr = ptr;
+            uint32_t dlen = n;
+
+            if (w->pad_pending) {
+                //pad length field is not counted as data
+                uint8_t pad = *dptr;
+                w->pad_pending = false;
+                w->data_left = w->frame_left > pad + 1 ? w->frame_left - pad - 1 : 0;
+                dptr ++;
+                dlen --;
+            }
+            dlen = min(dlen, w->data_left);
+            grpc_decode_msg(p, w, dptr, dlen, dec);
+            w->data_left -= dlen;
+        }
+        ptr += n;
+        w->frame_left -= n;
+    }
+
+    if (dec->len > 0) {
+        DEBUG_LOG(DBG_PARSER, p, "%s grpc decoded len(%u)\n",
+                  dpi_is_client_pkt(p) ? "c2s" : "s2c", dec->len);
+    }
+}
+
 static void grpc_parser(dpi_packet_t *p)
 {
     dpi_session_t *s = p->session;
@@ -147,6 +262,13 @@ static void grpc_parser(dpi_packet_t *p)
         return;
     }
 
+    if (data->isgrpc && dpi_is_parser_final(p)) {
+        grpc_decode_data(p, w, ptr, len);
+        w->seq = dpi_ptr_2_seq(p, ptr + len);
+        dpi_set_asm_seq(p, w->seq);
+        return;
+    }
+
     /*
      * There is not enough space to hold http2 frame 
      * header or initial c2s magic frame sequences
@@ -272,7 +394,10 @@ static void grpc_parser(dpi_packet_t *p)
         data->isgrpc) {
         DEBUG_LOG(DBG_PARSER, p, "HTTP2 PREFACE ESTABLISHED, GRPC IDENTIFIED\n");
         dpi_finalize_parser(p);
-        dpi_ignore_parser(p);
+        //keep decoding DATA frames for dlp/waf inspection
+        if (p->ep == NULL || p->ep->dlp_detector == NULL) {
+            dpi_ignore_parser(p);
+        }
     }
 }
 
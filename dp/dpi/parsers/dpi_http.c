@@ -2,6 +2,7 @@
 #include <ctype.h>
 
 #include "dpi/dpi_module.h"
+#include "dpi/sig/dpi_search.h"
 
 #define PCRE2_CODE_UNIT_WIDTH 8
 #include <pcre2.h>
@@ -37,6 +38,15 @@ typedef struct http_wing_ {
 #define HTTP_ENCODE_COMPRESS  2
 #define HTTP_ENCODE_DEFLATE   3
             encode: 2;
+#define HTTP_FILE_NONE  0
+#define HTTP_FILE_OLE2  1
+#define HTTP_FILE_PDF   2
+    uint8_t file_type:2,
+            file_str: 1,  // in a pdf literal string
+            file_esc: 1,  // escaped character in a pdf literal string
+            file_sep: 1;  // separator is the last extracted character
+    uint8_t file_depth;   // nested parentheses in a pdf literal string
+    uint32_t file_magic;  // last 4 bytes of the body
     uint32_t cmd_start;
     uint32_t body_start;
     uint32_t hdr_start;
@@ -69,6 +79,8 @@ typedef struct http_ctx_ {
     http_wing_t *w;
 } http_ctx_t;
 
+static void http_body_file_text(http_ctx_t *ctx, uint8_t *ptr, int len);
+
 typedef struct http_method_ {
     char *name;
     uint8_t len;
@@ -341,6 +353,10 @@ static void set_body_done(http_wing_t *w)
     w->flags &= ~(HTTP_FLAGS_CONTENT_LEN | HTTP_FLAGS_CONN_CLOSE | HTTP_FLAGS_CHUNKED |
                   HTTP_FLAGS_NEGATIVE_LEN);
     w->content_len = 0;
+    w->file_type = HTTP_FILE_NONE;
+    w->file_str = w->file_esc = w->file_sep = 0;
+    w->file_depth = 0;
+    w->file_magic = 0;
 }
 
 static void set_body_conn_close(http_wing_t *w)
@@ -624,6 +640,64 @@ static void http_header_xforwarded_for(http_ctx_t *ctx, uint8_t *ptr, int len)
     consume_tokens(ptr, len, http_header_xforwarded_for_token, ctx);
 }
 
+// Mesh identity is only taken from the headers that the sidecar adds to the plain-text request, which
+// is visible on the pod loopback tapped in proxymesh mode. The SDS peer metadata is not consulted.
+static void http_set_mesh_id(http_ctx_t *ctx, uint8_t *ptr, int len)
+{
+    dpi_session_t *s = ctx->p->session;
+    uint8_t *end = ptr + len;
+    int id_len;
+
+    while (ptr < end && (isblank(*ptr) || *ptr == '"')) ptr ++;
+    while (end > ptr && (isblank(*(end - 1)) || *(end - 1) == '"')) end --;
+
+    id_len = end - ptr;
+    if (id_len <= 0) {
+        return;
+    }
+    if (id_len >= sizeof(s->mesh_id)) {
+        id_len = sizeof(s->mesh_id) - 1;
+    }
+    memcpy(s->mesh_id, ptr, id_len);
+    s->mesh_id[id_len] = '\0';
+
+    DEBUG_LOG(DBG_PARSER, ctx->p, "mesh id: %s\n", s->mesh_id);
+}
+
+// Istio sidecar appends the peer certificate info, the last element is added by the nearest proxy.
+//   X-Forwarded-Client-Cert: By=spiffe://cluster.local/ns/a/sa/x;Hash=...;URI=spiffe://cluster.local/ns/b/sa/y
+static void http_header_xforwarded_client_cert(http_ctx_t *ctx, uint8_t *ptr, int len)
+{
+    uint8_t *end = ptr + len, *l;
+
+    DEBUG_LOG_FUNC_ENTRY(DBG_PARSER, ctx->p);
+
+    for (l = end - 1; l >= ptr; l --) {
+        if (*l == ',') {
+            ptr = l + 1;
+            break;
+        }
+    }
+
+    for (l = ptr; l + 4 <= end; l ++) {
+        if ((l == ptr || *(l - 1) == ';' || isblank(*(l - 1))) && strncasecmp((char *)l, "URI=", 4) == 0) {
+            uint8_t *uri = l + 4, *e = uri;
+
+            while (e < end && *e != ';') e ++;
+            http_set_mesh_id(ctx, uri, e - uri);
+            return;
+        }
+    }
+}
+
+// Linkerd proxy identity of the client, in the form of <sa>.<ns>.serviceaccount.identity.linkerd.<domain>
+static void http_header_l5d_client_id(http_ctx_t *ctx, uint8_t *ptr, int len)
+{
+    DEBUG_LOG_FUNC_ENTRY(DBG_PARSER, ctx->p);
+
+    http_set_mesh_id(ctx, ptr, len);
+}
+
 static int http_header_host_token(void *param, uint8_t *ptr, int len, int token_idx)
 {
     http_ctx_t *ctx = param;
@@ -738,6 +812,14 @@ static int http_parse_header(http_ctx_t *ctx, uint8_t *ptr, int len, bool *done)
         } else
         if (shift > 16 && strncasecmp((char *)ptr, "X-Forwarded-For:", 16) == 0) {
             http_header_xforwarded_for(ctx, ptr + 16, shift - eols - 16);
+        } else
+        if (shift > 24 && is_request(ctx->w) && FLAGS_TEST(ctx->p->session->flags, DPI_SESS_FLAG_PROXYMESH) &&
+            strncasecmp((char *)ptr, "X-Forwarded-Client-Cert:", 24) == 0) {
+            http_header_xforwarded_client_cert(ctx, ptr + 24, shift - eols - 24);
+        } else
+        if (shift > 14 && is_request(ctx->w) && FLAGS_TEST(ctx->p->session->flags, DPI_SESS_FLAG_PROXYMESH) &&
+            strncasecmp((char *)ptr, "l5d-client-id:", 14) == 0) {
+            http_header_l5d_client_id(ctx, ptr + 14, shift - eols - 14);
         } else if (!is_request(ctx->w)) {
             // TODO: move to signature
             if (shift > 7 && strncasecmp((char *)ptr, "Server:", 7) == 0) {
@@ -797,10 +879,12 @@ static int http_body_chunk(http_ctx_t *ctx, uint8_t *ptr, int len, bool *done)
             if (w->content_len > 0) {
                 if (len < w->content_len) {
                     DEBUG_LOG(DBG_PARSER, p, "consume=%u\n", len);
+                    http_body_file_text(ctx, ptr, len);
                     w->content_len -= len;
                     return consume + len;
                 } else {
                     DEBUG_LOG(DBG_PARSER, p, "chunk done, consume=%u\n", w->content_len);
+                    http_body_file_text(ctx, ptr, w->content_len);
 
                     ptr += w->content_len;
                     len -= w->content_len;
@@ -872,6 +956,102 @@ static void buffer_body(http_ctx_t *ctx, uint8_t *ptr, int len) {
     }
 }
 
+#define HTTP_MAGIC_PDF   0x25504446 // %PDF
+#define HTTP_MAGIC_OLE2  0xD0CF11E0 // legacy office documents
+
+static inline bool to_extract_file_text(http_ctx_t *ctx)
+{
+    dpi_packet_t *p = ctx->p;
+
+    return p->ep != NULL && p->ep->dlp_detector != NULL &&
+           ((dpi_detector_t *)p->ep->dlp_detector)->dlp_file_text &&
+           ctx->w->encode == HTTP_ENCODE_NONE;
+}
+
+static inline void file_text_add(dpi_packet_t *p, http_wing_t *w, uint8_t c)
+{
+    if (p->file_text.len >= DPI_MAX_PKT_LEN) return;
+
+    if (c == ' ') {
+        if (w->file_sep || p->file_text.len == 0) return;
+        w->file_sep = 1;
+    } else {
+        w->file_sep = 0;
+    }
+    p->file_text.ptr[p->file_text.len ++] = c;
+}
+
+// Extract the text of the documents uploaded or downloaded in the body, so the dlp patterns in the
+// file context can inspect it. The documents are recognized by their magic bytes at any position,
+// so the files in multipart forms are handled too. Text is taken from the ascii and utf-16 strings
+// of legacy office documents, and from the literal strings of pdf files. Compressed content, such
+// as the deflated parts of office open xml documents and the flate-encoded pdf streams, is not
+// extracted; the file types are still detected by the patterns in the body context.
+static void http_body_file_text(http_ctx_t *ctx, uint8_t *ptr, int len)
+{
+    dpi_packet_t *p = ctx->p;
+    http_wing_t *w = ctx->w;
+    uint8_t *end = ptr + len;
+
+    if (len <= 0 || !to_extract_file_text(ctx)) return;
+
+    for (; ptr < end; ptr ++) {
+        uint8_t c = *ptr;
+
+        w->file_magic = (w->file_magic << 8) | c;
+        if (w->file_magic == HTTP_MAGIC_PDF) {
+            DEBUG_LOG(DBG_PARSER, p, "pdf file\n");
+            w->file_type = HTTP_FILE_PDF;
+            w->file_str = w->file_esc = 0;
+            w->file_depth = 0;
+            continue;
+        } else if (w->file_magic == HTTP_MAGIC_OLE2) {
+            DEBUG_LOG(DBG_PARSER, p, "ole2 file\n");
+            w->file_type = HTTP_FILE_OLE2;
+            continue;
+        }
+
+        switch (w->file_type) {
+        case HTTP_FILE_OLE2:
+            if (isprint(c)) {
+                file_text_add(p, w, c);
+            } else if (c == 0 && isprint((w->file_magic >> 8) & 0xff)) {
+                // utf-16le, the previous byte is printable
+            } else {
+                file_text_add(p, w, ' ');
+            }
+            break;
+        case HTTP_FILE_PDF:
+            if (!w->file_str) {
+                if (c == '(') {
+                    w->file_str = 1;
+                    w->file_depth = 0;
+                } else if (c == '\n' || c == '\r') {
+                    file_text_add(p, w, ' ');
+                }
+            } else if (w->file_esc) {
+                w->file_esc = 0;
+                if (isprint(c)) file_text_add(p, w, c);
+            } else if (c == '\\') {
+                w->file_esc = 1;
+            } else if (c == '(') {
+                w->file_depth ++;
+                file_text_add(p, w, c);
+            } else if (c == ')') {
+                if (w->file_depth == 0) {
+                    w->file_str = 0;
+                } else {
+                    w->file_depth --;
+                    file_text_add(p, w, c);
+                }
+            } else if (isprint(c)) {
+                file_text_add(p, w, c);
+            }
+            break;
+        }
+    }
+}
+
 static int http_parse_body(http_ctx_t *ctx, uint8_t *ptr, int len, bool *done)
 {
     dpi_packet_t *p = ctx->p;
@@ -882,6 +1062,7 @@ static int http_parse_body(http_ctx_t *ctx, uint8_t *ptr, int len, bool *done)
     *done = false;
     if (w->flags & HTTP_FLAGS_CONN_CLOSE) {
         DEBUG_LOG(DBG_PARSER, p, "consume all=%u\n", len);
+        http_body_file_text(ctx, ptr, len);
         return len;
     } else if (w->flags & HTTP_FLAGS_CHUNKED) {
         return http_body_chunk(ctx, ptr, len, done);
@@ -889,11 +1070,13 @@ static int http_parse_body(http_ctx_t *ctx, uint8_t *ptr, int len, bool *done)
         if (len < w->content_len) {
             DEBUG_LOG(DBG_PARSER, p, "consume=%u\n", len);
             buffer_body(ctx, ptr, len);
+            http_body_file_text(ctx, ptr, len);
             w->content_len -= len;
             return len;
         } else {
             DEBUG_LOG(DBG_PARSER, p, "body done. consume=%u\n", w->content_len);
             buffer_body(ctx, ptr, w->content_len);
+            http_body_file_text(ctx, ptr, w->content_len);
             *done = true;
             return w->content_len;
         }
@@ -1131,6 +1314,15 @@ static void http_parser(dpi_packet_t *p)
             p->dlp_area[DPI_SIG_CONTEXT_TYPE_BODY].dlp_start = w->body_start;
             p->dlp_area[DPI_SIG_CONTEXT_TYPE_BODY].dlp_end  = w->seq;
 
+            if (p->file_text.len > 0) {
+                dpi_dlp_area_t *dlparea = &p->dlp_area[DPI_SIG_CONTEXT_TYPE_FILE];
+                dlparea->dlp_ptr = p->file_text.ptr;
+                dlparea->dlp_len = p->file_text.len;
+                dlparea->dlp_start = dpi_pkt_seq(p);
+                dlparea->dlp_end = dlparea->dlp_start + p->file_text.len;
+                dlparea->dlp_offset = 0;
+            }
+
             if (done) {
                 if (unlikely(is_slowloris_on_for_wing(s, w))) {
                     dpi_session_stop_tick_for(s, DPI_SESS_TICK_FLAG_SLOWLORIS, p);
@@ -0,0 +1,228 @@
+#include <string.h>
+#include <ctype.h>
+
+#include "dpi/dpi_module.h"
+
+#define MQTT_TYPE_CONNECT     1
+#define MQTT_TYPE_CONNACK     2
+
+#define MQTT_PROTO_V31        3
+#define MQTT_PROTO_V311       4
+#define MQTT_PROTO_V5         5
+
+#define MQTT_MAX_REMAIN_LEN   268435455
+
+typedef struct mqtt_wing_ {
+    uint32_t seq;
+} mqtt_wing_t;
+
+typedef struct mqtt_data_ {
+    mqtt_wing_t client, server;
+    uint8_t version;
+    bool connect;
+} mqtt_data_t;
+
+//return header length, 0 : not enough data
+//                     -1 : invalid remaining length
+static int mqtt_remain_len(uint8_t *ptr, uint32_t len, uint32_t *remain)
+{
+    uint32_t mul = 1, val = 0;
+    int i;
+
+    for (i = 1; i <= 4; i++) {
+        if (i >= len) {
+            return 0;
+        }
+        val += (ptr[i] & 0x7f) * mul;
+        if (!(ptr[i] & 0x80)) {
+            *remain = val;
+            return i + 1;
+        }
+        mul <<= 7;
+    }
+    return -1;
+}
+
+//return 0 : not enough data
+//       1 : mqtt connect
+//       -1: not mqtt connect
+static int mqtt_parse_connect(mqtt_data_t *data, uint8_t *ptr, uint32_t len)
+{
+    uint32_t remain, name_len;
+    int shift;
+
+    if ((ptr[0] >> 4) != MQTT_TYPE_CONNECT || (ptr[0] & 0x0f) != 0) {
+        return -1;
+    }
+
+    shift = mqtt_remain_len(ptr, len, &remain);
+    if (shift <= 0) {
+        return shift;
+    }
+    if (remain < 10 || remain > MQTT_MAX_REMAIN_LEN) {
+        return -1;
+    }
+    if (len < shift + 2) {
+        return 0;
+    }
+
+    name_len = GET_BIG_INT16(ptr + shift);
+    shift += 2;
+    if (len < shift + name_len + 1) {
+        return 0;
+    }
+
+    if (name_len == 4 && memcmp(ptr + shift, "MQTT", 4) == 0) {
+        data->version = ptr[shift + name_len];
+        if (data->version != MQTT_PROTO_V311 && data->version != MQTT_PROTO_V5) {
+            return -1;
+        }
+    } else if (name_len == 6 && memcmp(ptr + shift, "MQIsdp", 6) == 0) {
+        data->version = ptr[shift + name_len];
+        if (data->version != MQTT_PROTO_V31) {
+            return -1;
+        }
+    } else {
+        return -1;
+    }
+    return 1;
+}
+
+//return 0 : not enough data
+//       1 : mqtt connack
+//       -1: not mqtt connack
+static int mqtt_parse_connack(mqtt_data_t *data, uint8_t *ptr, uint32_t len)
+{
+    uint32_t remain;
+    int shift;
+
+    if ((ptr[0] >> 4) != MQTT_TYPE_CONNACK || (ptr[0] & 0x0f) != 0) {
+        return -1;
+    }
+
+    shift = mqtt_remain_len(ptr, len, &remain);
+    if (shift <= 0) {
+        return shift;
+    }
+    //v5 connack carries properties after the flag and reason code
+    if (data->version == MQTT_PROTO_V5) {
+        if (remain < 2) {
+            return -1;
+        }
+    } else if (remain != 2) {
+        return -1;
+    }
+    if (len < shift + 2) {
+        return 0;
+    }
+    //only session-present bit is defined in acknowledge flags
+    if ((ptr[shift] & 0xfe) != 0) {
+        return -1;
+    }
+    return 1;
+}
+
+static void mqtt_parser(dpi_packet_t *p)
+{
+    mqtt_data_t *data;
+    uint8_t *ptr;
+    uint32_t len;
+    int res;
+
+    DEBUG_LOG_FUNC_ENTRY(DBG_PARSER,NULL);
+
+    if (unlikely((data = dpi_get_parser_data(p)) == NULL)) {
+        if (!dpi_is_client_pkt(p)) {
+            DEBUG_LOG(DBG_PARSER, p, "Not MQTT: First packet from server\n");
+            dpi_fire_parser(p);
+            return;
+        }
+
+        if ((data = calloc(1, sizeof(*data))) == NULL) {
+            dpi_fire_parser(p);
+            return;
+        }
+
+        dpi_session_t *s = p->session;
+        data->client.seq = s->client.init_seq;
+        data->server.seq = s->server.init_seq;
+        dpi_put_parser_data(p, data);
+    }
+
+    mqtt_wing_t *w;
+    w = dpi_is_client_pkt(p) ? &data->client : &data->server;
+
+    if (w->seq == p->this_wing->init_seq) {
+        ptr = dpi_pkt_ptr(p);
+        len = dpi_pkt_len(p);
+    } else if (dpi_is_seq_in_pkt(p, w->seq)) {
+        uint32_t shift = u32_distance(dpi_pkt_seq(p), w->seq);
+        ptr = dpi_pkt_ptr(p) + shift;
+        len = dpi_pkt_len(p) - shift;
+    } else {
+        dpi_fire_parser(p);
+        return;
+    }
+
+    if (len == 0) {
+        return;
+    }
+
+    if (dpi_is_client_pkt(p)) {
+        if (data->connect) {
+            w->seq += len;
+            dpi_set_asm_seq(p, w->seq);
+            return;
+        }
+
+        res = mqtt_parse_connect(data, ptr, len);
+        if (res == 1) {
+            DEBUG_LOG(DBG_PARSER, p, "MQTT: connect, version=%u\n", data->version);
+            data->connect = true;
+            w->seq += len;
+            dpi_set_asm_seq(p, w->seq);
+        } else if (res == -1) {
+            DEBUG_LOG(DBG_PARSER, p, "Not MQTT connect\n");
+            dpi_fire_parser(p);
+        }
+    } else {
+        if (!data->connect) {
+            dpi_fire_parser(p);
+            return;
+        }
+
+        res = mqtt_parse_connack(data, ptr, len);
+        if (res == 1) {
+            DEBUG_LOG(DBG_PARSER, p, "MQTT: connack\n");
+            dpi_finalize_parser(p);
+            dpi_ignore_parser(p);
+        } else if (res == -1) {
+            DEBUG_LOG(DBG_PARSER, p, "Not MQTT connack\n");
+            dpi_fire_parser(p);
+        }
+    }
+}
+
+static void mqtt_new_session(dpi_packet_t *p)
+{
+    dpi_hire_parser(p);
+}
+
+static void mqtt_delete_data(void *data)
+{
+    free(data);
+}
+
+static dpi_parser_t dpi_parser_mqtt = {
+    new_session: mqtt_new_session,
+    delete_data: mqtt_delete_data,
+    parser:      mqtt_parser,
+    name:        "mqtt",
+    ip_proto:    IPPROTO_TCP,
+    type:        DPI_PARSER_MQTT,
+};
+
+dpi_parser_t *dpi_mqtt_parser(void)
+{
+    return &dpi_parser_mqtt;
+}
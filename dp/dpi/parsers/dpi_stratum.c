@@ -0,0 +1,180 @@
+#include <string.h>
+#include <ctype.h>
+
+#include "dpi/dpi_module.h"
+
+// Stratum is the json-rpc protocol used by the miners to talk to the mining pools.
+// Messages are newline delimited json objects, the client always speaks first.
+#define STRATUM_MAX_LINE      2048
+#define STRATUM_METHOD_LEN    32
+
+typedef struct stratum_data_ {
+    uint32_t seq;
+} stratum_data_t;
+
+static const char *stratum_skip_space(const char *ptr, const char *end)
+{
+    while (ptr < end && isspace(*ptr)) {
+        ptr ++;
+    }
+    return ptr;
+}
+
+// Locate "key" : "value" in the json line and copy the value
+static bool stratum_get_string(const char *ptr, const char *end, const char *key, char *value, int size)
+{
+    int klen = strlen(key);
+    const char *q;
+
+    for (q = ptr; q + klen + 2 < end; q ++) {
+        if (*q != '"' || q[klen + 1] != '"' || memcmp(q + 1, key, klen) != 0) {
+            continue;
+        }
+
+        q = stratum_skip_space(q + klen + 2, end);
+        if (q >= end || *q != ':') {
+            return false;
+        }
+        q = stratum_skip_space(q + 1, end);
+        if (q >= end || *q != '"') {
+            return false;
+        }
+
+        int i = 0;
+        for (q ++; q < end && *q != '"' && i < size - 1; q ++) {
+            value[i ++] = *q;
+        }
+        value[i] = '\0';
+        return q < end && *q == '"';
+    }
+    return false;
+}
+
+static bool stratum_has_key(const char *ptr, const char *end, const char *key)
+{
+    int klen = strlen(key);
+    const char *q;
+
+    for (q = ptr; q + klen + 2 <= end; q ++) {
+        if (*q == '"' && q[klen + 1] == '"' && memcmp(q + 1, key, klen) == 0) {
+            return true;
+        }
+    }
+    return false;
+}
+
+//return 0 : not enough data
+//       1 : stratum request, method is filled
+//       -1: not stratum
+static int stratum_parse_request(uint8_t *ptr, uint32_t len, char *method)
+{
+    const char *line = (const char *)ptr, *end = (const char *)ptr + len, *eol;
+
+    line = stratum_skip_space(line, end);
+    if (line >= end) {
+        return 0;
+    }
+    if (*line != '{') {
+        return -1;
+    }
+
+    eol = memchr(line, '\n', end - line);
+    if (eol == NULL) {
+        return len < STRATUM_MAX_LINE ? 0 : -1;
+    }
+
+    if (!stratum_get_string(line, eol, "method", method, STRATUM_METHOD_LEN)) {
+        return -1;
+    }
+
+    // stratum v1: mining.subscribe, mining.authorize, mining.submit ...
+    if (strncmp(method, "mining.", 7) == 0) {
+        return 1;
+    }
+    // cryptonote pools, i.e. xmrig: login with the miner agent
+    if (strcmp(method, "login") == 0 && stratum_has_key(line, eol, "agent")) {
+        return 1;
+    }
+    return -1;
+}
+
+static void stratum_parser(dpi_packet_t *p)
+{
+    stratum_data_t *data;
+    char method[STRATUM_METHOD_LEN];
+    uint8_t *ptr;
+    uint32_t len;
+    int res;
+
+    DEBUG_LOG_FUNC_ENTRY(DBG_PARSER,NULL);
+
+    if (!dpi_is_client_pkt(p)) {
+        if (unlikely(dpi_get_parser_data(p) == NULL)) {
+            DEBUG_LOG(DBG_PARSER, p, "Not stratum: First packet from server\n");
+            dpi_fire_parser(p);
+        }
+        return;
+    }
+
+    if (unlikely((data = dpi_get_parser_data(p)) == NULL)) {
+        if ((data = calloc(1, sizeof(*data))) == NULL) {
+            dpi_fire_parser(p);
+            return;
+        }
+
+        dpi_session_t *s = p->session;
+        data->seq = s->client.init_seq;
+        dpi_put_parser_data(p, data);
+    }
+
+    if (data->seq == p->this_wing->init_seq) {
+        ptr = dpi_pkt_ptr(p);
+        len = dpi_pkt_len(p);
+    } else if (dpi_is_seq_in_pkt(p, data->seq)) {
+        uint32_t shift = u32_distance(dpi_pkt_seq(p), data->seq);
+        ptr = dpi_pkt_ptr(p) + shift;
+        len = dpi_pkt_len(p) - shift;
+    } else {
+        dpi_fire_parser(p);
+        return;
+    }
+
+    if (len == 0) {
+        return;
+    }
+
+    res = stratum_parse_request(ptr, len, method);
+    if (res == 1) {
+        DEBUG_LOG(DBG_PARSER, p, "Stratum: method=%s\n", method);
+        dpi_threat_trigger(DPI_THRT_CRYPTO_MINING, p, "Stratum request: %s", method);
+        dpi_finalize_parser(p);
+        dpi_ignore_parser(p);
+    } else if (res == -1) {
+        DEBUG_LOG(DBG_PARSER, p, "Not stratum request\n");
+        dpi_fire_parser(p);
+    }
+}
+
+static void stratum_new_session(dpi_packet_t *p)
+{
+    dpi_hire_parser(p);
+}
+
+static void stratum_delete_data(void *data)
+{
+    free(data);
+}
+
+static dpi_parser_t dpi_parser_stratum = {
+    new_session: stratum_new_session,
+    delete_data: stratum_delete_data,
+    parser:      stratum_parser,
+    name:        "stratum",
+    ip_proto:    IPPROTO_TCP,
+    type:        DPI_PARSER_STRATUM,
+};
+
+dpi_parser_t *dpi_stratum_parser(void)
+{
+    return &dpi_parser_stratum;
+}
@@ -402,6 +402,11 @@ static void dpi_dlp_hs_search_detect (void *context, void *packet)
         dpi_dlp_hsdb_detect(hs_search, p, DPI_SIG_CONTEXT_TYPE_BODY);
         skip_packet = true;
     }
+
+    if (p->dlp_area[DPI_SIG_CONTEXT_TYPE_FILE].dlp_len > 0) {
+        dpi_dlp_hsdb_detect(hs_search, p, DPI_SIG_CONTEXT_TYPE_FILE);
+        skip_packet = true;
+    }
     
     if (!skip_packet && p->dlp_area[DPI_SIG_CONTEXT_TYPE_PACKET_ORIGIN].dlp_len > 0) {
         dpi_dlp_hsdb_detect(hs_search, p, DPI_SIG_CONTEXT_TYPE_PACKET_ORIGIN);
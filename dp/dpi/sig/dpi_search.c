@@ -502,6 +502,9 @@ static dpi_dlp_context_status_t app_dlp_context_status[] = {
 [DPI_APP_TNS - DPI_APP_PROTO_MARK]                   {0, 0, 0,},
 [DPI_APP_TDS - DPI_APP_PROTO_MARK]                   {0, 0, 0,},
 [DPI_APP_GRPC - DPI_APP_PROTO_MARK]                  {0, 0, 0,},
+[DPI_APP_MQTT - DPI_APP_PROTO_MARK]                  {0, 0, 0,},
+[DPI_APP_AMQP - DPI_APP_PROTO_MARK]                  {0, 0, 0,},
+[DPI_APP_STRATUM - DPI_APP_PROTO_MARK]               {0, 0, 0,},
 };
 
 static bool dpi_support_dlp_context (dpi_packet_t *p, dpi_sig_context_class_t c)
@@ -42,6 +42,7 @@ typedef struct dpi_detector_ {
     uint16_t dlp_ver;
     //int def_action;
     int dlp_apply_dir;
+    bool dlp_file_text;
 } dpi_detector_t;
 
 typedef struct dpi_hs_class_ {
@@ -328,6 +328,7 @@ static dpi_sig_context_class_t ContextType2Class[DPI_SIG_CONTEXT_TYPE_MAX] = {
     [DPI_SIG_CONTEXT_TYPE_BODY]               DPI_SIG_CONTEXT_CLASS_BODY,
     [DPI_SIG_CONTEXT_TYPE_SQL_QUERY]          DPI_SIG_CONTEXT_CLASS_BODY,
     [DPI_SIG_CONTEXT_TYPE_PACKET_ORIGIN]      DPI_SIG_CONTEXT_CLASS_PACKET,
+    [DPI_SIG_CONTEXT_TYPE_FILE]               DPI_SIG_CONTEXT_CLASS_BODY,
 };
 
 dpi_sig_context_class_t dpi_dlp_ctxt_type_2_cat (dpi_sig_context_type_t t)
@@ -366,6 +367,8 @@ void dpi_dlp_register_options (dpi_dlp_parser_t *dlpruleparser)
                              "context", dpi_sigopt_context_register());
     dpi_register_dlp_ruleopt_api(&dlpruleparser->dlprulelist,
                              "pcre", dpi_sigopt_pcre_register());
+    dpi_register_dlp_ruleopt_api(&dlpruleparser->dlprulelist,
+                             "checksum", dpi_sigopt_checksum_register());
 }
 
 void dpi_dlp_init(void)
@@ -21,6 +21,7 @@ typedef enum dpi_sigopt_type_ {
     DPI_SIGOPT_NAME,
     DPI_SIGOPT_CONTEXT,
     DPI_SIGOPT_PCRE,
+    DPI_SIGOPT_CHECKSUM,
     DPI_SIGOPT_MAX,
 } dpi_sigopt_type_t;
 
@@ -39,6 +40,7 @@ typedef enum dpi_sig_context_type_ {
     DPI_SIG_CONTEXT_TYPE_BODY,
     DPI_SIG_CONTEXT_TYPE_SQL_QUERY,
     DPI_SIG_CONTEXT_TYPE_PACKET_ORIGIN,
+    DPI_SIG_CONTEXT_TYPE_FILE,
     DPI_SIG_CONTEXT_TYPE_MAX,
 } dpi_sig_context_type_t;
 
@@ -150,6 +152,13 @@ typedef struct dpi_sigopt_pcre_pattern_ {
     uint8_t flags;
     uint8_t class;  //dpi_sig_context_class_t
     uint8_t type;  //dpi_sig_context_type_t
+#define DPI_SIGOPT_CHECKSUM_NONE     0
+#define DPI_SIGOPT_CHECKSUM_LUHN     1
+#define DPI_SIGOPT_CHECKSUM_IBAN     2
+#define DPI_SIGOPT_CHECKSUM_VERHOEFF 3
+#define DPI_SIGOPT_CHECKSUM_NHS      4
+#define DPI_SIGOPT_CHECKSUM_MRZ      5
+    uint8_t checksum; //checksum of the matched text
 
     struct {
         uint8_t *string;           /*pcre signature*/
@@ -178,4 +187,6 @@ dpi_sigopt_api_t *dpi_sigopt_sig_id_register (void);
 dpi_sigopt_api_t *dpi_sigopt_name_register (void);
 dpi_sigopt_api_t *dpi_sigopt_context_register (void);
 dpi_sigopt_api_t *dpi_sigopt_pcre_register (void);
+dpi_sigopt_api_t *dpi_sigopt_checksum_register (void);
+int dpi_sigopt_checksum_valid (uint8_t checksum, const uint8_t *ptr, int len);
 #endif
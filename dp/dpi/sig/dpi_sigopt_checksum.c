@@ -0,0 +1,221 @@
+#include <stdio.h>
+#include <string.h>
+#include <ctype.h>
+
+#include "dpi/sig/dpi_sig.h"
+#include "dpi/dpi_module.h"
+
+// Validate the text matched by a pcre pattern, so the numbers with the known formats but the wrong check
+// digits are not reported. Separators, such as spaces and dashes, in the matched text are ignored.
+
+#define MAX_CHECKSUM_DIGITS 64
+
+static int checksum_digits (const uint8_t *ptr, int len, uint8_t *digits)
+{
+    int i, n = 0;
+
+    for (i = 0; i < len && n < MAX_CHECKSUM_DIGITS; i ++) {
+        if (isdigit(ptr[i])) {
+            digits[n ++] = ptr[i] - '0';
+        }
+    }
+    return n;
+}
+
+// credit card, canada sin
+static bool checksum_luhn (const uint8_t *ptr, int len)
+{
+    uint8_t digits[MAX_CHECKSUM_DIGITS];
+    int i, n, sum = 0;
+
+    n = checksum_digits(ptr, len, digits);
+    if (n < 2) return false;
+
+    for (i = 0; i < n; i ++) {
+        int d = digits[n - 1 - i];
+        if (i & 1) {
+            d *= 2;
+            if (d > 9) d -= 9;
+        }
+        sum += d;
+    }
+    return sum % 10 == 0;
+}
+
+// iso 13616, the country code and check digits are moved to the end and the number mod 97 must be 1
+static bool checksum_iban (const uint8_t *ptr, int len)
+{
+    uint8_t chars[MAX_CHECKSUM_DIGITS];
+    int i, n = 0, rem = 0;
+
+    for (i = 0; i < len && n < MAX_CHECKSUM_DIGITS; i ++) {
+        if (isalnum(ptr[i])) {
+            chars[n ++] = toupper(ptr[i]);
+        }
+    }
+    if (n < 15) return false;
+
+    for (i = 0; i < n; i ++) {
+        uint8_t c = chars[(i + 4) % n];
+        if (isdigit(c)) {
+            rem = (rem * 10 + (c - '0')) % 97;
+        } else {
+            rem = (rem * 100 + (c - 'A' + 10)) % 97;
+        }
+    }
+    return rem == 1;
+}
+
+static const uint8_t verhoeff_d[10][10] = {
+    {0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+    {1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+    {2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+    {3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+    {4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+    {5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+    {6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+    {7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+    {8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+    {9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+};
+
+static const uint8_t verhoeff_p[8][10] = {
+    {0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+    {1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+    {5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+    {8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+    {9, 4, 5, 3, 1, 2, 6, 8, 7, 0},
+    {4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+    {2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+    {7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+};
+
+// india aadhaar
+static bool checksum_verhoeff (const uint8_t *ptr, int len)
+{
+    uint8_t digits[MAX_CHECKSUM_DIGITS];
+    int i, n, c = 0;
+
+    n = checksum_digits(ptr, len, digits);
+    if (n < 2) return false;
+
+    for (i = 0; i < n; i ++) {
+        c = verhoeff_d[c][verhoeff_p[i % 8][digits[n - 1 - i]]];
+    }
+    return c == 0;
+}
+
+// uk nhs number, mod 11 with the weights from 10 to 2
+static bool checksum_nhs (const uint8_t *ptr, int len)
+{
+    uint8_t digits[MAX_CHECKSUM_DIGITS];
+    int i, n, sum = 0, check;
+
+    n = checksum_digits(ptr, len, digits);
+    if (n != 10) return false;
+
+    for (i = 0; i < 9; i ++) {
+        sum += digits[i] * (10 - i);
+    }
+    check = 11 - sum % 11;
+    if (check == 11) check = 0;
+    return check != 10 && check == digits[9];
+}
+
+static int mrz_value (uint8_t c)
+{
+    if (isdigit(c)) {
+        return c - '0';
+    } else if (isalpha(c)) {
+        return toupper(c) - 'A' + 10;
+    }
+    return 0; // filler '<'
+}
+
+static bool mrz_field_valid (const uint8_t *ptr, int len)
+{
+    static const int weights[3] = {7, 3, 1};
+    int i, sum = 0;
+
+    if (!isdigit(ptr[len])) return false;
+
+    for (i = 0; i < len; i ++) {
+        sum += mrz_value(ptr[i]) * weights[i % 3];
+    }
+    return sum % 10 == ptr[len] - '0';
+}
+
+// icao 9303, the document number, birth date and expiry date of the second line of a passport
+static bool checksum_mrz (const uint8_t *ptr, int len)
+{
+    if (len < 28) return false;
+
+    return mrz_field_valid(ptr, 9) && mrz_field_valid(ptr + 13, 6) && mrz_field_valid(ptr + 21, 6);
+}
+
+int dpi_sigopt_checksum_valid (uint8_t checksum, const uint8_t *ptr, int len)
+{
+    switch (checksum) {
+    case DPI_SIGOPT_CHECKSUM_LUHN:
+        return checksum_luhn(ptr, len);
+    case DPI_SIGOPT_CHECKSUM_IBAN:
+        return checksum_iban(ptr, len);
+    case DPI_SIGOPT_CHECKSUM_VERHOEFF:
+        return checksum_verhoeff(ptr, len);
+    case DPI_SIGOPT_CHECKSUM_NHS:
+        return checksum_nhs(ptr, len);
+    case DPI_SIGOPT_CHECKSUM_MRZ:
+        return checksum_mrz(ptr, len);
+    }
+    return 1;
+}
+
+static dpi_sigopt_status_t dpi_sigopt_checksum_parser (char *value, dpi_sig_t *sig)
+{
+    DEBUG_LOG_FUNC_ENTRY(DBG_DETECT, NULL);
+    dpi_sigopt_pcre_pattern_t *data;
+
+    if (value == NULL) {
+        return DPI_SIGOPT_INVALID_OPTION_VALUE;
+    }
+
+    if (sig->last_pattern == NULL) {
+        return DPI_SIGOPT_MISSING_OPTION;
+    }
+
+    data = sig->last_pattern;
+
+    if (data->checksum != DPI_SIGOPT_CHECKSUM_NONE) {
+        return DPI_SIGOPT_DUP_OPTION;
+    }
+    if (FLAGS_TEST(data->flags, DPI_SIGOPT_PAT_FLAG_NEGATIVE)) {
+        return DPI_SIGOPT_INVALID_OPTION_VALUE;
+    }
+
+    if (strcasecmp(value, "luhn") == 0) {
+        data->checksum = DPI_SIGOPT_CHECKSUM_LUHN;
+    } else if (strcasecmp(value, "iban") == 0) {
+        data->checksum = DPI_SIGOPT_CHECKSUM_IBAN;
+    } else if (strcasecmp(value, "verhoeff") == 0) {
+        data->checksum = DPI_SIGOPT_CHECKSUM_VERHOEFF;
+    } else if (strcasecmp(value, "nhs") == 0) {
+        data->checksum = DPI_SIGOPT_CHECKSUM_NHS;
+    } else if (strcasecmp(value, "mrz") == 0) {
+        data->checksum = DPI_SIGOPT_CHECKSUM_MRZ;
+    } else {
+        return DPI_SIGOPT_INVALID_OPTION_VALUE;
+    }
+
+    return DPI_SIGOPT_OK;
+}
+
+static dpi_sigopt_api_t SIGOPTChecksum = {
+    type:    DPI_SIGOPT_CHECKSUM,
+    parser:  dpi_sigopt_checksum_parser,
+};
+
+dpi_sigopt_api_t *dpi_sigopt_checksum_register (void)
+{
+    DEBUG_LOG_FUNC_ENTRY(DBG_DETECT, NULL);
+    return &SIGOPTChecksum;
+}
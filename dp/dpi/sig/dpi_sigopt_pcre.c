@@ -53,6 +53,11 @@ static dpi_sigopt_status_t dpi_sigopt_context_parser (char *value, dpi_sig_t *si
     } else if (strcasecmp(value, "sql_query") == 0) {
         data->type = DPI_SIG_CONTEXT_TYPE_SQL_QUERY;
         data->class = DPI_SIG_CONTEXT_CLASS_BODY;
+    } else if (strcasecmp(value, "file") == 0) {
+        // text is extracted from the documents only if any pattern is in the file context
+        data->type = DPI_SIG_CONTEXT_TYPE_FILE;
+        data->class = DPI_SIG_CONTEXT_CLASS_BODY;
+        ((dpi_detector_t *)sig->detector)->dlp_file_text = true;
     } else {
         return DPI_SIGOPT_INVALID_OPTION_VALUE;
     }
@@ -177,8 +182,9 @@ dpi_sigopt_pcre_search (dpi_sigopt_pcre_pattern_t *data, dpi_packet_t *p,
             }
         }
 
-        // Note: we must do confirm in PCRE if a start_offset was specified.
-        if (offset == 0) {
+        // Note: we must do confirm in PCRE if a start_offset was specified or
+        // the checksum of the matched text is validated.
+        if (offset == 0 && data->checksum == DPI_SIGOPT_CHECKSUM_NONE) {
             if (data->pcre.hs_noconfirm || (!is_prefiltering)) {
                 return hs_match; // No confirm necessary.
             }
@@ -207,6 +213,20 @@ pcre_confirm:
         return ret;
     }
     rc = pcre2_match(data->pcre.recompiled, (PCRE2_SPTR)ptr, len, offset, 0, match_data, NULL);
+
+    // Try the next match if the matched text fails the checksum
+    while (rc >= 0 && data->checksum != DPI_SIGOPT_CHECKSUM_NONE) {
+        ovector = pcre2_get_ovector_pointer(match_data);
+        if (dpi_sigopt_checksum_valid(data->checksum, ptr + ovector[0], (int)(ovector[1] - ovector[0]))) {
+            break;
+        }
+        if (ovector[1] >= len) {
+            rc = PCRE2_ERROR_NOMATCH;
+            break;
+        }
+        rc = pcre2_match(data->pcre.recompiled, (PCRE2_SPTR)ptr, len,
+                         ovector[1] > ovector[0] ? ovector[1] : ovector[0] + 1, 0, match_data, NULL);
+    }
     
     /* Matching failed: handle error cases */
     if (rc < 0) { 
@@ -246,8 +266,11 @@ static int dpi_sigopt_pcre_handler (void *context, dpi_packet_t *pkt, dpi_sig_t
 
     t = (pkt->dlp_pat_context == DPI_SIG_CONTEXT_TYPE_MAX) ? data->type : pkt->dlp_pat_context;
 
+    // The file text is scanned with the body patterns, always confirm it in its own area.
+    // The checksum of the matched text is validated in the confirmation too.
     if ((data->pcre.hs_noconfirm || !(data->pcre.hs_flags & HS_FLAG_PREFILTER)) &&
         !(FLAGS_TEST(data->flags, DPI_SIGOPT_PAT_FLAG_NEGATIVE)) &&
+        t != DPI_SIG_CONTEXT_TYPE_FILE && data->checksum == DPI_SIGOPT_CHECKSUM_NONE &&
         pkt->dlp_area[t].dlp_offset == 0) {
         return 1;
     }
@@ -61,6 +61,11 @@ static int dp_nfq_rx_cb(struct nfq_q_handle *qh, struct nfgenmsg *nfmsg,
 	ret = nfq_get_payload(nfa, &payload_data);
 	if (ret >= 0) {
         int total_len = ret + sizeof(struct ethhdr);
+        // hw_protocol is not set for locally generated packets on the
+        // output hook, so take the ether type from the IP version.
+        if (ret > 0 && (payload_data[0] >> 4) == 6) {
+            nfq_eth->h_proto = htons(ETH_P_IPV6);
+        }
         memcpy(&dpi_rcv_pkt_ptr[sizeof(struct ethhdr)], payload_data, ret);
         verdict = dpi_recv_packet(&context, dpi_rcv_pkt_ptr, total_len);
         if (verdict == 1) {//drop
@@ -179,6 +184,18 @@ int dp_open_nfq_handle(dp_context_t *ctx, int qnum, bool jumboframe, uint blocks
         return -1;
 	}
 
+    DEBUG_CTRL("unbinding existing nf_queue handler for AF_INET6 (if any)\n");
+	if (nfq_unbind_pf(nfq_hdl, AF_INET6) < 0) {
+        DEBUG_ERROR(DBG_CTRL, "error during nfq_unbind_pf() for AF_INET6\n");
+        return -1;
+	}
+
+    DEBUG_CTRL("binding nfnetlink_queue as nf_queue handler for AF_INET6\n");
+	if (nfq_bind_pf(nfq_hdl, AF_INET6) < 0) {
+        DEBUG_ERROR(DBG_CTRL, "error during nfq_bind_pf() for AF_INET6\n");
+        return -1;
+	}
+
     DEBUG_CTRL("binding this socket to queue(%d)\n", nfq_queue_num);
 	nfq_q_hdl = nfq_create_queue(nfq_hdl, nfq_queue_num, &dp_nfq_rx_cb, (void *)ctx);
 	if (!nfq_q_hdl) {
@@ -17,4 +17,7 @@ cd agent; make || exit $?; cd ..
 echo "==> Making controller"
 cd controller; make || exit $?; cd ..
 
+echo "==> Making openapi document"
+go run ./tools/openapigen -o controller/api/openapi.json || exit $?
+
 exit 0
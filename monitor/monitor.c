@@ -46,6 +46,7 @@
 #define ENV_NO_AUTO_BENCHMARK  "ENF_NO_AUTO_BENCHMARK"
 #define ENV_NO_SYSTEM_PROTECT  "ENF_NO_SYSTEM_PROFILES"
 #define ENV_POLICY_PULLER      "ENF_NETPOLICY_PULL_INTERVAL"
+#define ENV_RESTRICTED_MODE    "ENF_RESTRICTED_MODE"
 #define ENV_PWD_VALID_UNIT     "PWD_VALID_UNIT"
 #define ENV_RANCHER_EP         "RANCHER_EP"
 #define ENV_RANCHER_SSO        "RANCHER_SSO"
@@ -90,7 +91,7 @@ enum {
     MODE_SCANNER,
 };
 
-#define PROC_ARGS_MAX 32
+#define PROC_ARGS_MAX 48
 
 typedef struct proc_info_ {
     char name[32];
@@ -492,6 +493,11 @@ static pid_t fork_exec(int i)
         if (getenv(ENV_NO_SYSTEM_PROTECT)) {
             args[a ++] = "-no_sys_protect";
         }
+        if ((enable = getenv(ENV_RESTRICTED_MODE)) != NULL) {
+            if (checkImplicitEnableFlag(enable) == 1) {
+                args[a ++] = "-restricted";
+            }
+        }
         if ((policy_pull_period = getenv(ENV_POLICY_PULLER)) != NULL) {
             args[a ++] = "-policy_puller";
             args[a ++] = policy_pull_period;
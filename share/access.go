@@ -35,6 +35,8 @@ const (
 	PERM_CLOUD                 = 0x00040000 // for cloud services like aws lambda
 	PERM_WORKLOAD_BASIC        = 0x00080000 // workload(pod). namespaced
 	PERM_VULNERABILITY         = 0x00100000 // for vulnerability profile
+	PERM_RISK_APPROVAL         = 0x00200000 // (modify only) for approving the accepted risks of vulnerabilities & compliance checks
+	PERM_POLICY_APPROVAL       = 0x00400000 // (modify only) for approving the network rule changes
 
 	// composite permissions (~= permanent boost)
 	PERMS_RUNTIME_SCAN     = PERM_RUNTIME_SCAN_BASIC | PERM_WORKLOAD_BASIC | PERM_INFRA_BASIC
@@ -64,8 +66,8 @@ const (
 	PERMS_DOMAIN = PERMS_DOMAIN_READ | PERMS_DOMAIN_WRITE // sum of all permissions that are supporedt in domain
 
 	// customer-configurable permissions: (PERM_NV_RESOURCE is non-customer-configurable permission)
-	PERMS_GLOBAL_CONFIGURABLE_READ  = PERM_ADM_CONTROL | PERM_AUTHENTICATION | PERM_CLOUD | PERM_INFRA_BASIC | PERM_VULNERABILITY | PERMS_DOMAIN_READ                                        // sum of all configurable(non-hidden) read permissions
-	PERMS_GLOBAL_CONFIGURABLE_WRITE = PERM_ADM_CONTROL | PERM_AUTHENTICATION | PERM_CLOUD | PERM_INFRA_BASIC | PERM_VULNERABILITY | PERMS_DOMAIN_WRITE | PERM_SYSTEM_CONFIG | PERM_CICD_SCAN // sum of all configurable(non-hidden) write permissions
+	PERMS_GLOBAL_CONFIGURABLE_READ  = PERM_ADM_CONTROL | PERM_AUTHENTICATION | PERM_CLOUD | PERM_INFRA_BASIC | PERM_VULNERABILITY | PERMS_DOMAIN_READ                                                                                    // sum of all configurable(non-hidden) read permissions
+	PERMS_GLOBAL_CONFIGURABLE_WRITE = PERM_ADM_CONTROL | PERM_AUTHENTICATION | PERM_CLOUD | PERM_INFRA_BASIC | PERM_VULNERABILITY | PERMS_DOMAIN_WRITE | PERM_SYSTEM_CONFIG | PERM_CICD_SCAN | PERM_RISK_APPROVAL | PERM_POLICY_APPROVAL // sum of all configurable(non-hidden) write permissions
 
 	// Effective permissions for reserved fedAdmin/fedReader/admin/reader roles on global domain, only they have PERM_NV_RESOURCE permission
 	PERMS_CLUSTER_READ  = PERM_NV_RESOURCE | PERMS_GLOBAL_CONFIGURABLE_READ
@@ -98,6 +100,8 @@ const ( // permission option id, id that ends with "_basic" is used by controlle
 	PERM_SECURITY_EVENTS_BASIC_ID = "security_events_basic"
 	PERM_WORKLOAD_BASIC_ID        = "workload_basic"
 	PERM_VULNERABILITY_ID         = "vulnerability"
+	PERM_RISK_APPROVAL_ID         = "risk_approval"
+	PERM_POLICY_APPROVAL_ID       = "policy_approval"
 
 	// complex permissions, can be seen by customers
 	PERMS_RUNTIME_SCAN_ID     = "rt_scan"         // == PERM_RUNTIME_SCAN_BASIC | PERM_WORKLOAD_BASIC | PERM_INFRA_BASIC
@@ -203,6 +207,14 @@ func (o *CLUSVulnerabilityProfile) GetDomain(f GetAccessObjectFunc) ([]string, [
 	return nil, nil
 }
 
+func (o *CLUSRiskAcceptance) GetDomain(f GetAccessObjectFunc) ([]string, []string) {
+	return nil, nil
+}
+
+func (o *CLUSPolicyChange) GetDomain(f GetAccessObjectFunc) ([]string, []string) {
+	return nil, nil
+}
+
 func (o *CLUSDomain) GetDomain(f GetAccessObjectFunc) ([]string, []string) {
 	return nil, nil
 }
@@ -476,6 +488,10 @@ func (o *CLUSWafRule) GetDomain(f GetAccessObjectFunc) ([]string, []string) {
 	return []string{AccessAllAsReader}, nil
 }
 
+func (o *CLUSThreatSignatureBundle) GetDomain(f GetAccessObjectFunc) ([]string, []string) {
+	return nil, nil
+}
+
 func (o *CLUSWafGroup) GetDomain(f GetAccessObjectFunc) ([]string, []string) {
 	if f != nil {
 		if g := f(o.Name); g != nil && g.(*CLUSGroup) != nil { // CLUSWafGroup.Name is group name
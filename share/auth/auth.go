@@ -2,9 +2,13 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -37,7 +41,7 @@ type RemoteAuthInterface interface {
 	SAMLSPAuth(csaml *share.CLUSServerSAML, tokenData *api.RESTAuthToken) (map[string][]string, error)
 	OIDCDiscover(issuer string) (string, string, string, string, error)
 	OIDCGetRedirectURL(csaml *share.CLUSServerOIDC, redir *api.RESTTokenRedirect) (string, error)
-	OIDCAuth(coidc *share.CLUSServerOIDC, tokenData *api.RESTAuthToken) (map[string]interface{}, error)
+	OIDCAuth(coidc *share.CLUSServerOIDC, tokenData *api.RESTAuthToken) (map[string]interface{}, string, error)
 }
 
 func NewRemoteAuther() RemoteAuthInterface {
@@ -92,18 +96,22 @@ func (a *remoteAuth) LDAPAuth(cldap *share.CLUSServerLDAP, username, password st
 		client.GroupFilter = fmt.Sprintf(ldapGroupFilter, cldap.GroupMemberAttr, username)
 	}
 
-	groups, _ := client.GetGroupsOfUser()
-	log.WithFields(log.Fields{"filter": client.GroupFilter, "groups": groups}).Debug("group member query")
+	groups, _ := client.GetGroupEntriesOfUser()
+	log.WithFields(log.Fields{"filter": client.GroupFilter, "groups": len(groups)}).Debug("group member query")
 
 	// add nested group query for MSAD
+	expandNested := true
 	if cldap.Type == api.ServerLDAPTypeMSAD {
 		client.GroupFilter = fmt.Sprintf(adNestedGroupFilter, dn)
 
-		groups2, _ := client.GetGroupsOfUser()
-		log.WithFields(log.Fields{"filter": client.GroupFilter, "groups": groups2}).Debug("nested group member query")
+		groups2, err := client.GetGroupEntriesOfUser()
+		log.WithFields(log.Fields{"filter": client.GroupFilter, "groups": len(groups2), "error": err}).Debug("nested group member query")
 
-		groups = append(groups, groups2...)
-		groups = removeDuplicateValues(groups)
+		// The server resolves the whole chain. Expand level by level only if the matching rule is not supported.
+		if err == nil {
+			groups = append(groups, groups2...)
+			expandNested = false
+		}
 	}
 
 	// There is a case (Ticket 1137), where group members are stored with their dn, so if groups are not found,
@@ -116,10 +124,28 @@ func (a *remoteAuth) LDAPAuth(cldap *share.CLUSServerLDAP, username, password st
 		}
 
 		log.WithFields(log.Fields{"filter": client.GroupFilter}).Debug("group member query")
-		groups, _ = client.GetGroupsOfUser()
+		groups, _ = client.GetGroupEntriesOfUser()
+	}
+
+	// Groups refer to their member groups by dn
+	if expandNested && len(groups) > 0 {
+		groups = client.ExpandNestedGroups(groups, func(groupDN string) string {
+			if cldap.Type == api.ServerLDAPTypeMSAD {
+				return fmt.Sprintf(adGroupFilter, cldap.GroupMemberAttr, groupDN)
+			} else {
+				return fmt.Sprintf(ldapGroupFilter, cldap.GroupMemberAttr, groupDN)
+			}
+		})
+		log.WithFields(log.Fields{"groups": len(groups)}).Debug("nested groups expanded")
 	}
 
-	return attrs, groups, nil
+	names := make([]string, len(groups))
+	for i, g := range groups {
+		names[i] = g.CN
+	}
+	names = removeDuplicateValues(names)
+
+	return attrs, names, nil
 }
 
 func (a *remoteAuth) SAMLSPGetRedirectURL(csaml *share.CLUSServerSAML, redir *api.RESTTokenRedirect) (string, error) {
@@ -182,15 +208,44 @@ func (a *remoteAuth) generateState() string {
 	return utils.EncryptURLSafe(s)
 }
 
-func (a *remoteAuth) verifyState(state string) error {
-	if tsStr := utils.DecryptURLSafe(state); tsStr == "" {
-		return errors.New("Invalid state: wrong encryption")
-	} else if ts, err := strconv.ParseInt(tsStr, 10, 64); err != nil {
-		return errors.New("Invalid state: wrong format")
+// The PKCE code verifier is carried in the encrypted state, so the code can be exchanged
+// by any controller without keeping the verifier around.
+func (a *remoteAuth) generateStateWithVerifier(verifier string) string {
+	s := fmt.Sprintf("%d:%s", time.Now().Unix(), verifier)
+	return utils.EncryptURLSafe(s)
+}
+
+// Return the PKCE code verifier in the state, if any
+func (a *remoteAuth) verifyState(state string) (string, error) {
+	var verifier string
+	tsStr := utils.DecryptURLSafe(state)
+	if tsStr == "" {
+		return "", errors.New("Invalid state: wrong encryption")
+	}
+	if i := strings.Index(tsStr, ":"); i != -1 {
+		verifier = tsStr[i+1:]
+		tsStr = tsStr[:i]
+	}
+	if ts, err := strconv.ParseInt(tsStr, 10, 64); err != nil {
+		return "", errors.New("Invalid state: wrong format")
 	} else if time.Now().Unix()-ts > stateTimeout {
-		return errors.New("Invalid state: expired")
+		return "", errors.New("Invalid state: expired")
+	}
+	return verifier, nil
+}
+
+// RFC 7636, use 32 random bytes as the verifier and S256 as the challenge method
+func generatePKCEVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
-	return nil
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
 }
 
 func (a *remoteAuth) OIDCGetRedirectURL(coidc *share.CLUSServerOIDC, redir *api.RESTTokenRedirect) (string, error) {
@@ -200,11 +255,26 @@ func (a *remoteAuth) OIDCGetRedirectURL(coidc *share.CLUSServerOIDC, redir *api.
 		Endpoint:     oauth2.Endpoint{AuthURL: coidc.AuthURL, TokenURL: coidc.TokenURL},
 		Scopes:       coidc.Scopes,
 	}
-	url := fmt.Sprintf("%s&redirect_uri=%s", cfg.AuthCodeURL(a.generateState()), redir.Redirect)
+
+	var authURL string
+	if coidc.UsePKCE {
+		verifier, err := generatePKCEVerifier()
+		if err != nil {
+			return "", err
+		}
+		authURL = cfg.AuthCodeURL(a.generateStateWithVerifier(verifier),
+			oauth2.SetAuthURLParam("code_challenge", pkceChallenge(verifier)),
+			oauth2.SetAuthURLParam("code_challenge_method", "S256"))
+	} else {
+		authURL = cfg.AuthCodeURL(a.generateState())
+	}
+	url := fmt.Sprintf("%s&redirect_uri=%s", authURL, redir.Redirect)
 	return url, nil
 }
 
-func (a *remoteAuth) OIDCAuth(coidc *share.CLUSServerOIDC, tokenData *api.RESTAuthToken) (map[string]interface{}, error) {
+// Return the claims and the refresh token issued by the server. The user is authenticated either by
+// the authorization code, or by the refresh token returned at the previous login.
+func (a *remoteAuth) OIDCAuth(coidc *share.CLUSServerOIDC, tokenData *api.RESTAuthToken) (map[string]interface{}, string, error) {
 	cfg := oauth2.Config{
 		ClientID:     coidc.ClientID,
 		ClientSecret: coidc.ClientSecret,
@@ -213,34 +283,48 @@ func (a *remoteAuth) OIDCAuth(coidc *share.CLUSServerOIDC, tokenData *api.RESTAu
 		Scopes:       coidc.Scopes,
 	}
 
-	if err := a.verifyState(tokenData.State); err != nil {
-		return nil, err
-	}
-
-	if tokenData.Token == "" {
-		return nil, errors.New("OpenID Connect code not present")
-	}
+	var token *oauth2.Token
+	var err error
+	if tokenData.RefreshToken != "" {
+		src := cfg.TokenSource(context.Background(), &oauth2.Token{RefreshToken: tokenData.RefreshToken})
+		if token, err = src.Token(); err != nil {
+			return nil, "", err
+		}
+	} else {
+		verifier, err := a.verifyState(tokenData.State)
+		if err != nil {
+			return nil, "", err
+		}
 
-	token, err := cfg.Exchange(context.Background(), tokenData.Token)
-	if err != nil {
-		return nil, err
-	}
+		if tokenData.Token == "" {
+			return nil, "", errors.New("OpenID Connect code not present")
+		}
 
-	rawIDToken, ok := token.Extra("id_token").(string)
-	if !ok {
-		return nil, errors.New("OpenID Connect token not present")
+		var opts []oauth2.AuthCodeOption
+		if verifier != "" {
+			opts = append(opts, oauth2.SetAuthURLParam("code_verifier", verifier))
+		}
+		if token, err = cfg.Exchange(context.Background(), tokenData.Token, opts...); err != nil {
+			return nil, "", err
+		}
 	}
 
-	keySet := oidc.NewRemoteKeySet(context.Background(), coidc.JWKSURL, nil)
-	verifier := oidc.NewVerifier(keySet, &oidc.Config{ClientID: coidc.ClientID}, coidc.Issuer)
-	idToken, err := verifier.Verify(context.Background(), rawIDToken)
-	if err != nil {
-		return nil, err
-	}
+	// ID token is optional in the response of refresh request
+	var claims map[string]interface{}
+	if rawIDToken, ok := token.Extra("id_token").(string); ok {
+		keySet := oidc.NewRemoteKeySet(context.Background(), coidc.JWKSURL, nil)
+		verifier := oidc.NewVerifier(keySet, &oidc.Config{ClientID: coidc.ClientID}, coidc.Issuer)
+		idToken, err := verifier.Verify(context.Background(), rawIDToken)
+		if err != nil {
+			return nil, "", err
+		}
 
-	claims, err := idToken.Claims()
-	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("Failed to parse claims")
+		claims, err = idToken.Claims()
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Failed to parse claims")
+		}
+	} else if tokenData.RefreshToken == "" {
+		return nil, "", errors.New("OpenID Connect token not present")
 	}
 
 	// Make UserInfo request
@@ -250,14 +334,20 @@ func (a *remoteAuth) OIDCAuth(coidc *share.CLUSServerOIDC, tokenData *api.RESTAu
 	userInfo, err2 := oidc.UserInfoReq(ctx, coidc.UserInfoURL, oauth2.StaticTokenSource(token))
 	if err2 != nil {
 		log.WithFields(log.Fields{"error": err2}).Error("Failed on UserInfo reqeuest")
-		return claims, err
+		if claims == nil {
+			return nil, "", err2
+		}
+		return claims, token.RefreshToken, nil
 	}
 
 	// Merge claims from UserInfo call
 	uiClaims := make(map[string]interface{})
 	if err2 = userInfo.Claims(&uiClaims); err2 != nil {
 		log.WithFields(log.Fields{"error": err2}).Error("Failed to parse UserInfo claims")
-		return claims, err
+		if claims == nil {
+			return nil, "", err2
+		}
+		return claims, token.RefreshToken, nil
 	}
 
 	if claims == nil {
@@ -267,7 +357,7 @@ func (a *remoteAuth) OIDCAuth(coidc *share.CLUSServerOIDC, tokenData *api.RESTAu
 		claims[k] = v
 	}
 
-	return claims, nil
+	return claims, token.RefreshToken, nil
 }
 
 func removeDuplicateValues(strSlice []string) []string {
@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -132,29 +133,155 @@ func (lc *LDAPClient) Authenticate(password string) (string, map[string]string,
 	return userDN, attrs, nil
 }
 
-// GetGroupsOfUser returns the group for a user.
-func (lc *LDAPClient) GetGroupsOfUser() ([]string, error) {
+const (
+	ldapPageSize          = 500 // RFC 2696 paged results, AD limits the page to 1000 entries by default
+	ldapNestedGroupDepth  = 5
+	ldapGroupCacheTimeout = time.Minute * 5
+	ldapGroupCacheMax     = 4096
+)
+
+type LDAPGroup struct {
+	DN string
+	CN string
+}
+
+type ldapGroupCacheEntry struct {
+	groups   []*LDAPGroup
+	expireAt time.Time
+}
+
+// Parent groups of the nested groups, cached across logins as group membership rarely changes
+type ldapGroupCache struct {
+	mutex   sync.Mutex
+	entries map[string]*ldapGroupCacheEntry
+}
+
+var groupCache = ldapGroupCache{entries: make(map[string]*ldapGroupCacheEntry)}
+
+func (c *ldapGroupCache) get(key string, now time.Time) ([]*LDAPGroup, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if e, ok := c.entries[key]; ok {
+		if now.Before(e.expireAt) {
+			return e.groups, true
+		}
+		delete(c.entries, key)
+	}
+	return nil, false
+}
+
+func (c *ldapGroupCache) put(key string, groups []*LDAPGroup, now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.entries) >= ldapGroupCacheMax {
+		for k, e := range c.entries {
+			if !now.Before(e.expireAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= ldapGroupCacheMax {
+			c.entries = make(map[string]*ldapGroupCacheEntry)
+		}
+	}
+	c.entries[key] = &ldapGroupCacheEntry{groups: groups, expireAt: now.Add(ldapGroupCacheTimeout)}
+}
+
+func (lc *LDAPClient) searchGroups(filter string) ([]*LDAPGroup, error) {
 	err := lc.Connect()
 	if err != nil {
 		return nil, err
 	}
 
-	log.WithFields(log.Fields{"filter": lc.GroupFilter}).Debug()
+	log.WithFields(log.Fields{"filter": filter}).Debug()
 	searchRequest := ldap.NewSearchRequest(
 		lc.Base,
 		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
-		lc.GroupFilter,
+		filter,
 		[]string{"cn"}, // can it be something else than "cn"?
 		nil,
 	)
-	sr, err := lc.Conn.Search(searchRequest)
+	// The paging control is not critical, servers that don't support it return all entries at once.
+	sr, err := lc.Conn.SearchWithPaging(searchRequest, ldapPageSize)
 	if err != nil {
 		return nil, err
 	}
-	groups := []string{}
+	groups := make([]*LDAPGroup, 0, len(sr.Entries))
 	for _, entry := range sr.Entries {
 		log.WithFields(log.Fields{"cn": entry.GetAttributeValue("cn")}).Debug("group")
-		groups = append(groups, entry.GetAttributeValue("cn"))
+		groups = append(groups, &LDAPGroup{DN: entry.DN, CN: entry.GetAttributeValue("cn")})
 	}
 	return groups, nil
 }
+
+// GetGroupEntriesOfUser returns the groups that match the group filter.
+func (lc *LDAPClient) GetGroupEntriesOfUser() ([]*LDAPGroup, error) {
+	return lc.searchGroups(lc.GroupFilter)
+}
+
+// GetGroupsOfUser returns the group for a user.
+func (lc *LDAPClient) GetGroupsOfUser() ([]string, error) {
+	entries, err := lc.GetGroupEntriesOfUser()
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]string, len(entries))
+	for i, g := range entries {
+		groups[i] = g.CN
+	}
+	return groups, nil
+}
+
+// ExpandNestedGroups adds the groups that the given groups are members of, recursively. parentFilter returns
+// the filter to query the parent groups of a group by its escaped DN.
+func (lc *LDAPClient) ExpandNestedGroups(groups []*LDAPGroup, parentFilter func(dn string) string) []*LDAPGroup {
+	now := time.Now()
+	return expandNestedGroups(groups, ldapNestedGroupDepth, func(dn string) ([]*LDAPGroup, error) {
+		filter := parentFilter(ldap.EscapeFilter(dn))
+		key := fmt.Sprintf("%s:%d/%s/%s", lc.Host, lc.Port, lc.Base, filter)
+		if parents, ok := groupCache.get(key, now); ok {
+			return parents, nil
+		}
+		parents, err := lc.searchGroups(filter)
+		if err == nil {
+			groupCache.put(key, parents, now)
+		}
+		return parents, err
+	})
+}
+
+// Breadth-first expansion, so each group is queried once even if the membership has cycles
+func expandNestedGroups(groups []*LDAPGroup, maxDepth int, getParents func(dn string) ([]*LDAPGroup, error)) []*LDAPGroup {
+	visited := make(map[string]bool, len(groups))
+	all := make([]*LDAPGroup, 0, len(groups))
+	level := make([]*LDAPGroup, 0, len(groups))
+	for _, g := range groups {
+		if !visited[g.DN] {
+			visited[g.DN] = true
+			all = append(all, g)
+			level = append(level, g)
+		}
+	}
+
+	for depth := 0; depth < maxDepth && len(level) > 0; depth++ {
+		var next []*LDAPGroup
+		for _, g := range level {
+			parents, err := getParents(g.DN)
+			if err != nil {
+				log.WithFields(log.Fields{"group": g.DN, "error": err}).Error("Failed to query parent groups")
+				continue
+			}
+			for _, p := range parents {
+				if !visited[p.DN] {
+					visited[p.DN] = true
+					all = append(all, p)
+					next = append(next, p)
+				}
+			}
+		}
+		level = next
+	}
+	if len(level) > 0 {
+		log.WithFields(log.Fields{"depth": maxDepth}).Info("Nested group depth limit reached")
+	}
+	return all
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestExpandNestedGroups(t *testing.T) {
+	// g1 -> g2 -> g3 -> g1 (cycle), g2 -> g4 -> g5 -> g6
+	parents := map[string][]string{
+		"g1": []string{"g2"},
+		"g2": []string{"g3", "g4"},
+		"g3": []string{"g1"},
+		"g4": []string{"g5"},
+		"g5": []string{"g6"},
+	}
+	queried := make(map[string]int)
+	getParents := func(dn string) ([]*LDAPGroup, error) {
+		queried[dn]++
+		if dn == "bad" {
+			return nil, errors.New("search failed")
+		}
+		groups := make([]*LDAPGroup, 0)
+		for _, p := range parents[dn] {
+			groups = append(groups, &LDAPGroup{DN: p, CN: p})
+		}
+		return groups, nil
+	}
+	names := func(groups []*LDAPGroup) []string {
+		list := make([]string, len(groups))
+		for i, g := range groups {
+			list[i] = g.CN
+		}
+		sort.Strings(list)
+		return list
+	}
+
+	groups := expandNestedGroups([]*LDAPGroup{&LDAPGroup{DN: "g1", CN: "g1"}, &LDAPGroup{DN: "bad", CN: "bad"}}, 10, getParents)
+	expect := []string{"bad", "g1", "g2", "g3", "g4", "g5", "g6"}
+	if list := names(groups); len(list) != len(expect) {
+		t.Errorf("Unexpected groups: %v", list)
+	} else {
+		for i := range list {
+			if list[i] != expect[i] {
+				t.Errorf("Unexpected groups: %v", list)
+				break
+			}
+		}
+	}
+	for dn, count := range queried {
+		if count != 1 {
+			t.Errorf("Group is queried more than once: group=%v count=%v", dn, count)
+		}
+	}
+
+	// Depth limit
+	groups = expandNestedGroups([]*LDAPGroup{&LDAPGroup{DN: "g1", CN: "g1"}}, 2, getParents)
+	if list := names(groups); len(list) != 4 || list[3] != "g4" {
+		t.Errorf("Unexpected groups with depth limit: %v", list)
+	}
+}
+
+func TestLDAPGroupCache(t *testing.T) {
+	cache := ldapGroupCache{entries: make(map[string]*ldapGroupCacheEntry)}
+	now := time.Now()
+
+	cache.put("g1", []*LDAPGroup{&LDAPGroup{DN: "g2", CN: "g2"}}, now)
+	if groups, ok := cache.get("g1", now.Add(time.Minute)); !ok || len(groups) != 1 {
+		t.Errorf("Failed to get cached groups: %v %v", ok, groups)
+	}
+	if _, ok := cache.get("g1", now.Add(ldapGroupCacheTimeout)); ok {
+		t.Errorf("Cached groups should expire")
+	}
+	if len(cache.entries) != 0 {
+		t.Errorf("Expired entry is not removed: %v", cache.entries)
+	}
+}
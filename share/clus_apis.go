@@ -34,6 +34,7 @@ const CLUSLockCrdQueueKey string = CLUSLockStore + "crd_queue"
 const CLUSLockCloudKey string = CLUSLockStore + "cloud"
 const CLUSLockFedScanDataKey string = CLUSLockStore + "fed_scan_data"
 const CLUSLockApikeyKey string = CLUSLockStore + "apikey"
+const CLUSLockKvKeyringKey string = CLUSLockStore + "kv_keyring"
 
 //const CLUSLockResponseRuleKey string = CLUSLockStore + "response_rule"
 
@@ -72,6 +73,10 @@ const (
 	CFGEndpointPwdProfile           = "pwd_profile"
 	CFGEndpointApikey               = "apikey"
 	CFGEndpointSigstoreRootsOfTrust = "sigstore_roots_of_trust"
+	CFGEndpointThreatSignature      = "threat_signature"
+	CFGEndpointBackup               = "backup"
+	CFGEndpointReport               = "report"
+	CFGEndpointPolicyChange         = "policy_change"
 )
 const CLUSConfigStore string = CLUSObjectStore + "config/"
 const CLUSConfigSystemKey string = CLUSConfigStore + CFGEndpointSystem
@@ -103,6 +108,10 @@ const CLUSConfigUserRoleStore string = CLUSConfigStore + CFGEndpointUserRole + "
 const CLUSConfigPwdProfileStore string = CLUSConfigStore + CFGEndpointPwdProfile + "/"
 const CLUSConfigApikeyStore string = CLUSConfigStore + CFGEndpointApikey + "/"
 const CLUSConfigSigstoreRootsOfTrust string = CLUSConfigStore + CFGEndpointSigstoreRootsOfTrust + "/"
+const CLUSConfigThreatSignatureKey string = CLUSConfigStore + CFGEndpointThreatSignature
+const CLUSConfigBackupKey string = CLUSConfigStore + CFGEndpointBackup
+const CLUSConfigReportStore string = CLUSConfigStore + CFGEndpointReport + "/"
+const CLUSConfigPolicyChangeStore string = CLUSConfigStore + CFGEndpointPolicyChange + "/"
 
 // !!! NOTE: When adding new config items, update the import/export list as well !!!
 
@@ -164,6 +173,15 @@ const CLUSCtrlUsageReportStore string = CLUSStateStore + "usage_report/"
 const CLUSCtrlVerKey string = CLUSStateStore + "ctrl_ver"
 const CLUSExpiredTokenStore string = CLUSStateStore + "expired_token/"
 const CLUSImportStore string = CLUSStateStore + "import/"
+const CLUSConfigAuditHeadKey string = CLUSStateStore + "config_audit_head"
+const CLUSRecoveryTokenKey string = CLUSStateStore + "recovery_token"
+const CLUSBackupStatusKey string = CLUSStateStore + "backup_status"
+const CLUSKvStoreStatsKey string = CLUSStateStore + "kv_stats"
+const CLUSKvKeyringKey string = CLUSStateStore + "kv_keyring"
+const CLUSFedReportStore string = CLUSStateStore + "fed_report/"
+const CLUSFedReportTrendKey string = CLUSFedReportStore + "trend"
+const CLUSReportStore string = CLUSStateStore + "report/"
+const CLUSReportStatusKey string = CLUSStateStore + "report_status"
 
 func CLUSExpiredTokenKey(token string) string {
 	return fmt.Sprintf("%s%s", CLUSExpiredTokenStore, token)
@@ -399,6 +417,24 @@ func CLUSVulnerabilityProfileKey(name string) string {
 	return fmt.Sprintf("%s%s", CLUSConfigVulnerabilityProfileStore, name)
 }
 
+const CLUSConfigComplianceAcceptanceStore string = CLUSConfigComplianceStore + "acceptance/"
+const CLUSConfigVulnerabilityAcceptanceStore string = CLUSConfigVulnerabilityStore + "acceptance/"
+
+func CLUSRiskAcceptanceStore(riskType string) string {
+	if riskType == RiskAcceptanceCompliance {
+		return CLUSConfigComplianceAcceptanceStore
+	}
+	return CLUSConfigVulnerabilityAcceptanceStore
+}
+
+func CLUSRiskAcceptanceKey(riskType, id string) string {
+	return fmt.Sprintf("%s%s", CLUSRiskAcceptanceStore(riskType), id)
+}
+
+func CLUSPolicyChangeKey(id string) string {
+	return fmt.Sprintf("%s%s", CLUSConfigPolicyChangeStore, id)
+}
+
 func CLUSDomainConfigKey(name string) string {
 	return fmt.Sprintf("%s%s", CLUSConfigDomainStore, name)
 }
@@ -558,11 +594,12 @@ func CLUSKeyLastToken(key string) string {
 }
 
 const (
-	CLUSResCfgRule     = "rule"
-	CLUSResCfgRuleList = "rules"
+	CLUSResCfgRule       = "rule"
+	CLUSResCfgRuleList   = "rules"
+	CLUSResCfgQuarantine = "quarantine"
 )
 
-func CLUSPolicyKey2ResPolicySubkey(key string) (string, string) { // returns policy name(like "default"/"fed") & "rule"/"rules"
+func CLUSPolicyKey2ResPolicySubkey(key string) (string, string) { // returns policy name(like "default"/"fed") & "rule"/"rules"/"quarantine"
 	return CLUSKeyNthToken(key, 3), CLUSKeyNthToken(key, 4)
 }
 
@@ -622,6 +659,10 @@ func CLUSVulnerabilityProfileKey2Name(key string) string {
 	return keyLastToken(key)
 }
 
+func CLUSRiskAcceptanceKey2ID(key string) string {
+	return keyLastToken(key)
+}
+
 func CLUSDomainKey2Name(key string) string {
 	return keyLastToken(key)
 }
@@ -667,8 +708,16 @@ type CLUSScanState struct {
 	Status    string    `json:"status"`
 }
 
+// The image pulled through the mirror is matched to the scan result of the upstream image, and vice versa.
+// Both are the registry with an optional repository prefix, such as harbor.example.com/dockerhub-proxy.
+type CLUSRegistryMirror struct {
+	Mirror   string `json:"mirror"`
+	Upstream string `json:"upstream"`
+}
+
 type CLUSScanConfig struct {
-	AutoScan bool `json:"auto_scan"`
+	AutoScan        bool                  `json:"auto_scan"`
+	RegistryMirrors []*CLUSRegistryMirror `json:"registry_mirrors,omitempty"`
 }
 
 type CLUSCtrlVersion struct {
@@ -677,15 +726,26 @@ type CLUSCtrlVersion struct {
 }
 
 type CLUSSyslogConfig struct {
-	SyslogIP         net.IP   `json:"syslog_ip"`
-	SyslogServer     string   `json:"syslog_server"`
-	SyslogIPProto    uint8    `json:"syslog_ip_proto"`
-	SyslogPort       uint16   `json:"syslog_port"`
-	SyslogLevel      string   `json:"syslog_level"`
-	SyslogEnable     bool     `json:"syslog_enable"`
-	SyslogCategories []string `json:"syslog_categories"`
-	SyslogInJSON     bool     `json:"syslog_in_json"`
-	SyslogServerCert string   `json:"syslog_server_cert"`
+	SyslogIP             net.IP                  `json:"syslog_ip"`
+	SyslogServer         string                  `json:"syslog_server"`
+	SyslogIPProto        uint8                   `json:"syslog_ip_proto"`
+	SyslogPort           uint16                  `json:"syslog_port"`
+	SyslogLevel          string                  `json:"syslog_level"`
+	SyslogEnable         bool                    `json:"syslog_enable"`
+	SyslogCategories     []string                `json:"syslog_categories"`
+	SyslogInJSON         bool                    `json:"syslog_in_json"`
+	SyslogServerCert     string                  `json:"syslog_server_cert"` // CA to verify the server in tls
+	SyslogClientCert     string                  `json:"syslog_client_cert"`
+	SyslogClientKey      string                  `json:"syslog_client_key,cloak"`
+	SyslogStructuredData bool                    `json:"syslog_structured_data"`
+	SyslogCategoryMaps   []CLUSSyslogCategoryMap `json:"syslog_category_maps"`
+}
+
+// Facility and level of the syslog messages of a category. Empty value to use the default.
+type CLUSSyslogCategoryMap struct {
+	Category string `json:"category"`
+	Facility string `json:"facility"`
+	Level    string `json:"level"`
 }
 
 type CLUSSystemUsageReport struct {
@@ -741,12 +801,78 @@ type CLUSIBMSAOnboardData struct {
 	ProviderID string `json:"provider_id"`
 }
 
+type CLUSWebhookHeader struct {
+	Name    string `json:"name"`
+	Value   string `json:"value,cloak"`
+	Cloaked bool   `json:"cloaked"` // false for the values written in plain text by earlier versions
+}
+
 type CLUSWebhook struct {
-	Name    string   `json:"name"`
-	Url     string   `json:"url"`
-	Enable  bool     `json:"enable"`
-	Type    string   `json:"type"`
-	CfgType TCfgType `json:"cfg_type"`
+	Name       string              `json:"name"`
+	Url        string              `json:"url"`
+	Enable     bool                `json:"enable"`
+	Type       string              `json:"type"`
+	Headers    []CLUSWebhookHeader `json:"headers,omitempty"`
+	RoutingKey string              `json:"routing_key,cloak,omitempty"` // PagerDuty integration key
+	CfgType    TCfgType            `json:"cfg_type"`
+}
+
+type CLUSKafkaTopic struct {
+	Category string `json:"category"`
+	Topic    string `json:"topic"`
+}
+
+// Exporter that pushes logs to Splunk HTTP Event Collector, Elasticsearch bulk API or Kafka
+type CLUSLogExporter struct {
+	Name          string           `json:"name"`
+	Type          string           `json:"type"`
+	Enable        bool             `json:"enable"`
+	URL           string           `json:"url"`
+	Token         string           `json:"token,cloak"` // HEC token, or Elasticsearch api key
+	Username      string           `json:"username"`    // Elasticsearch or Kafka SASL user
+	Password      string           `json:"password,cloak"`
+	Index         string           `json:"index"`
+	Categories    []string         `json:"categories"`
+	CACert        string           `json:"ca_cert"`
+	SkipVerify    bool             `json:"skip_verify"`
+	Brokers       []string         `json:"brokers"` // kafka bootstrap brokers, host:port
+	Topic         string           `json:"topic"`   // kafka default topic
+	Topics        []CLUSKafkaTopic `json:"topics"`  // kafka topic by category
+	TLS           bool             `json:"tls"`
+	SASLMechanism string           `json:"sasl_mechanism"`
+	PartitionKey  string           `json:"partition_key"`
+}
+
+type CLUSSMTPRoute struct {
+	Recipients []string `json:"recipients"`
+	Categories []string `json:"categories"`
+	Level      string   `json:"level"` // the lowest severity sent to the recipients
+}
+
+type CLUSSMTPConfig struct {
+	Enable      bool            `json:"enable"`
+	Server      string          `json:"server"`
+	Port        uint16          `json:"port"`
+	Security    string          `json:"security"` // starttls, tls or none
+	SkipVerify  bool            `json:"skip_verify"`
+	Username    string          `json:"username"`
+	Password    string          `json:"password,cloak"`
+	From        string          `json:"from"`
+	Routes      []CLUSSMTPRoute `json:"routes"`
+	Digest      string          `json:"digest"`       // hourly or daily, empty to send every event immediately
+	DigestLevel string          `json:"digest_level"` // the events of this severity or lower are sent in the digest
+}
+
+type CLUSLogCorrelation struct {
+	Enable bool   `json:"enable"`
+	Window uint32 `json:"window"` // in seconds
+}
+
+// Collector of the connection records in IPFIX or NetFlow v9
+type CLUSFlowExport struct {
+	Enable    bool   `json:"enable"`
+	Collector string `json:"collector"` // host:port
+	Format    string `json:"format"`    // ipfix or netflow9
 }
 
 type CLUSSystemConfig struct {
@@ -759,10 +885,15 @@ type CLUSSystemConfig struct {
 	AuthOrder            []string                  `json:"auth_order"`
 	AuthByPlatform       bool                      `json:"auth_by_platform"`
 	RancherEP            string                    `json:"rancher_ep"`
+	RancherProjectRoles  map[string]string         `json:"rancher_project_roles,omitempty"` // rancher project role template => nv role
 	InternalSubnets      []string                  `json:"configured_internal_subnets,omitempty"`
 	WebhookEnable_UNUSED bool                      `json:"webhook_enable"`
 	WebhookUrl_UNUSED    string                    `json:"webhook_url"`
 	Webhooks             []CLUSWebhook             `json:"webhooks"`
+	FlowExport           CLUSFlowExport            `json:"flow_export"`
+	LogExporters         []CLUSLogExporter         `json:"log_exporters"`
+	SMTP                 CLUSSMTPConfig            `json:"smtp"`
+	LogCorrelation       CLUSLogCorrelation        `json:"log_correlation"`
 	ClusterName          string                    `json:"cluster_name"`
 	ControllerDebug      []string                  `json:"controller_debug"`
 	TapProxymesh         bool                      `json:"tap_proxymesh"`
@@ -777,12 +908,19 @@ type CLUSSystemConfig struct {
 	NetServicePolicyMode string                    `json:"net_service_policy_mode"`
 	DisableNetPolicy     bool                      `json:"disable_net_policy"`
 	DetectUnmanagedWl    bool                      `json:"detect_unmanaged_wl"`
+	HostNetPolicyStatus  bool                      `json:"host_net_policy_status"`
+	HostNetPolicyMode    string                    `json:"host_net_policy_mode"`
+	SynFloodThreshold    uint32                    `json:"syn_flood_threshold,omitempty"`
+	PortScanThreshold    uint32                    `json:"port_scan_threshold,omitempty"`
 	ModeAutoD2M          bool                      `json:"mode_auto_d2m"`
 	ModeAutoD2MDuration  int64                     `json:"mode_auto_d2m_duration"`
 	ModeAutoM2P          bool                      `json:"mode_auto_m2p"`
 	ModeAutoM2PDuration  int64                     `json:"mode_auto_m2p_duration"`
 	ScannerAutoscale     CLUSSystemConfigAutoscale `json:"scanner_autoscale"`
 	NoTelemetryReport    bool                      `json:"no_telemetry_report,omitempty"`
+	KubeCISProfile       string                    `json:"kube_cis_profile,omitempty"`       // empty to select by the platform
+	BenchMode            string                    `json:"bench_mode,omitempty"`             // empty for the CIS benchmarks
+	PolicyChangeApproval bool                      `json:"policy_change_approval,omitempty"` // network rule changes must be approved
 }
 
 type CLUSSystemConfigAutoscale struct {
@@ -812,8 +950,12 @@ type CLUSUser struct {
 	RoleDomains      map[string][]string `json:"role_domains"`
 	LastLoginAt      time.Time           `json:"last_login_at"`
 	LoginCount       uint32              `json:"login_count"`
-	FailedLoginCount uint32              `json:"failed_login_count"` // failed consecutive login failure. reset to 0 after a successful login
-	BlockLoginSince  time.Time           `json:"block_login_since"`  // reset to 0 after a successful login
+	FailedLoginCount uint32              `json:"failed_login_count"`         // failed consecutive login failure. reset to 0 after a successful login
+	BlockLoginSince  time.Time           `json:"block_login_since"`          // reset to 0 after a successful login
+	MFASecret        string              `json:"mfa_secret,cloak,omitempty"` // encrypted TOTP secret
+	MFAEnabled       bool                `json:"mfa_enabled,omitempty"`      // set after the secret is verified by the user
+	MFABackupCodes   []string            `json:"mfa_backup_codes,omitempty"` // hashes of unused backup codes
+	MFALastStep      int64               `json:"mfa_last_step,omitempty"`    // last accepted TOTP time step, to reject replayed codes
 }
 
 type GroupRoleMapping struct {
@@ -861,6 +1003,8 @@ type CLUSServerOIDC struct {
 	ClientSecret string   `json:"client_secret,cloak"`
 	Scopes       []string `json:"scopes"`
 	GroupClaim   string   `json:"group_claim"`
+	UsePKCE      bool     `json:"use_pkce"`      // client secret is optional with PKCE
+	RefreshLogin bool     `json:"refresh_login"` // return refresh token so API clients can login again with it
 }
 
 type CLUSServer struct {
@@ -907,6 +1051,7 @@ type CLUSHost struct {
 	CapKubeBench   bool                    `json:"cap_kube_bench"`
 	StorageDriver  string                  `json:"storage_driver"`
 	CgroupVersion  int                     `json:"cgroup_version"`
+	Labels         map[string]string       `json:"labels"`
 }
 
 type CLUSDevice struct {
@@ -933,6 +1078,7 @@ type CLUSDevice struct {
 
 type CLUSAgent struct {
 	CLUSDevice
+	Restricted bool `json:"restricted"` // without privileged access or host PID namespace
 }
 
 type CLUSController struct {
@@ -1015,6 +1161,7 @@ type CLUSWorkload struct {
 	CPUs         string                    `json:"cpus"`
 	ProxyMesh    bool                      `json:"proxymesh"`
 	Sidecar      bool                      `json:"sidecar"`
+	Owner        string                    `json:"owner,omitempty"` // kubernetes owner as kind/name, set by the controller
 }
 
 type CLUSDomain struct {
@@ -1023,6 +1170,8 @@ type CLUSDomain struct {
 	Disable bool              `json:"disable"`
 	Tags    []string          `json:"tags"`   // compliance tags
 	Labels  map[string]string `json:"labels"` // from k8s
+	// policy mode of the new services in the domain, the system setting is used if it is empty
+	NewServicePolicyMode string `json:"new_service_policy_mode,omitempty"`
 }
 
 type CLUSCriteriaEntry struct {
@@ -1063,6 +1212,19 @@ type CLUSGroup struct {
 	CapIntcp        bool                `json:"cap_intcp"`
 	CfgType         TCfgType            `json:"cfg_type"`
 	BaselineProfile string              `json:"baseline_profile"`
+	Template        bool                `json:"template,omitempty"` // rules are inherited by members with lower precedence
+	AutoMode        *CLUSGroupAutoMode  `json:"auto_mode,omitempty"`
+	RateLimit       uint32              `json:"rate_limit,omitempty"` // shared by the allow rules to the group without their own limit
+	RateLimitUnit   string              `json:"rate_limit_unit,omitempty"`
+}
+
+// Schedule of the policy mode transitions of a group. The durations in seconds override the system-wide
+// auto mode settings, and a learned rule resets the no-learn period before the group is promoted to Monitor.
+type CLUSGroupAutoMode struct {
+	Hold                bool   `json:"hold,omitempty"`
+	ModeAutoD2MDuration int64  `json:"mode_auto_d2m_duration,omitempty"`
+	ModeAutoM2PDuration int64  `json:"mode_auto_m2p_duration,omitempty"`
+	NoLearnDays         uint32 `json:"no_learn_days,omitempty"`
 }
 
 type CLUSPolicyRule struct {
@@ -1081,6 +1243,38 @@ type CLUSPolicyRule struct {
 	LastModAt      time.Time `json:"last_modified_at"`
 	CfgType        TCfgType  `json:"cfg_type"`
 	Priority       uint32    `json:"priority"`
+	RateLimit      uint32    `json:"rate_limit,omitempty"`
+	RateLimitUnit  string    `json:"rate_limit_unit,omitempty"`
+	ExpireAt       int64     `json:"expire_at,omitempty"` // unix time, the rule is disabled when it expires. 0: never
+}
+
+const (
+	PolicyChangePending  = "pending"
+	PolicyChangeApproved = "approved"
+	PolicyChangeRejected = "rejected"
+)
+
+// One operation of a policy change. Rule is the rule after the change, it's nil for delete. Base is the rule when
+// the change is submitted, it's nil for create. The change cannot be approved if the rule is modified since.
+type CLUSPolicyChangeOp struct {
+	Op   string          `json:"op"` // create, update or delete
+	ID   uint32          `json:"id"` // 0 for the new rule whose ID is assigned when approved
+	Rule *CLUSPolicyRule `json:"rule,omitempty"`
+	Base *CLUSPolicyRule `json:"base,omitempty"`
+}
+
+// The local network rule changes that are staged until another user with the policy approval permission approves them
+type CLUSPolicyChange struct {
+	ID            string                `json:"id"`
+	Comment       string                `json:"comment"`
+	Ops           []*CLUSPolicyChangeOp `json:"ops"`
+	Status        string                `json:"status"`
+	SubmittedBy   string                `json:"submitted_by"`
+	SubmittedAt   time.Time             `json:"submitted_at"`
+	ReviewedBy    string                `json:"reviewed_by,omitempty"`
+	ReviewedAt    time.Time             `json:"reviewed_at,omitempty"`
+	ReviewComment string                `json:"review_comment,omitempty"`
+	CreatedIDs    []uint32              `json:"created_ids,omitempty"` // IDs of the new rules when the change is approved
 }
 
 type CLUSRuleHead struct {
@@ -1102,10 +1296,17 @@ func QuarantineReasonEvent(event string, id uint32) string {
 	return fmt.Sprintf("%s (rule %d)", event, id)
 }
 
+// The quarantined workload can still reach, or be reached from, the exception addresses
+type CLUSQuarException struct {
+	Address string `json:"address"`         // IP, IP range or CIDR
+	Ports   string `json:"ports,omitempty"` // egress server ports, empty for any, otherwise "tcp/514", "udp/53", "tcp/8000-8080" ...
+}
+
 type CLUSWorkloadConfig struct {
-	Wire       string `json:"wire,omitempty"`
-	Quarantine bool   `json:"quarantine,omitempty"`
-	QuarReason string `json:"quarantine_reason,omitempty"`
+	Wire           string              `json:"wire,omitempty"`
+	Quarantine     bool                `json:"quarantine,omitempty"`
+	QuarReason     string              `json:"quarantine_reason,omitempty"`
+	QuarExceptions []CLUSQuarException `json:"quarantine_exceptions,omitempty"`
 }
 
 type CLUSAgentConfig struct {
@@ -1130,6 +1331,7 @@ var CLUSWLService string = "nv.service"
 var CLUSWLModeGroup string = "nv.mode_group"
 var CLUSWLAddressGroup string = "nv.address_group"
 var CLUSHostAddrGroup string = "nv.hostaddr_group" //used as wlid for "nodes" in policy calculation
+var CLUSHostNodeWL string = "nv.host_node"         //used as wlid for host processes when host network policy is enabled
 var CLUSWLFqdnPrefix string = "fqdn:"
 var CLUSWLFqdnVhPrefix string = "vh:"
 var CLUSLearnedHostPrefix string = "Host:"
@@ -1144,6 +1346,9 @@ const PolicyFedRuleIDMax = 110000 // exclusive
 const PolicyGroundRuleIDBase = 110000
 const PolicyGroundRuleIDMax = 120000
 
+// Rate ids of the group rate limits, apart from the policy ids
+const PolicyRateGroupIDBase uint32 = 0x80000000
+
 // Special internal subnet IP
 const (
 	SpecInternalTunnelIP = "tunnelip"
@@ -1171,19 +1376,24 @@ type CLUSWorkloadAddr struct {
 }
 
 type CLUSGroupIPPolicy struct {
-	ID     uint32              `json:"policy_id"`
-	From   []*CLUSWorkloadAddr `json:"from_addr"`
-	To     []*CLUSWorkloadAddr `json:"to_addr,omitempty"`
-	Action uint8               `json:"action"`
+	ID        uint32              `json:"policy_id"`
+	From      []*CLUSWorkloadAddr `json:"from_addr"`
+	To        []*CLUSWorkloadAddr `json:"to_addr,omitempty"`
+	Action    uint8               `json:"action"`
+	RateLimit uint32              `json:"rate_limit,omitempty"`
+	RateID    uint32              `json:"rate_id,omitempty"` // policies of the same id are metered together
+	RateUnit  uint8               `json:"rate_unit,omitempty"`
 }
 
 type CLUSGroupIPPolicyVer struct {
-	Key                  string `json:"key"`
-	PolicyIPRulesVersion string `json:"pol_version"`
-	SlotNo               int    `json:"slot_no"`
-	RulesLen             int    `json:"rules_len"`
-	WorkloadSlot         int    `json:"workload_slot,omitempty"`
-	WorkloadLen          int    `json:"workload_len,omitempty"`
+	Key                  string   `json:"key"`
+	PolicyIPRulesVersion string   `json:"pol_version"`
+	SlotNo               int      `json:"slot_no"`
+	RulesLen             int      `json:"rules_len"`
+	WorkloadSlot         int      `json:"workload_slot,omitempty"`
+	WorkloadLen          int      `json:"workload_len,omitempty"`
+	SlotHash             []string `json:"slot_hash,omitempty"`
+	TraceParent          string   `json:"trace_parent,omitempty"` // trace of the calculation, continued by the enforcers
 }
 
 type CLUSDlpRuleVer struct {
@@ -1292,6 +1502,22 @@ const (
 	CLUSEvGroupAutoPromote
 	CLUSEvAuthDefAdminPwdUnchanged // default admin's password is not changed yet. reported every 24 hours
 	CLUSEvScannerAutoScaleDisabled // when scanner autoscale is disabled by controller
+	CLUSEvCrdDriftRepaired         // for crd Config repaired due to out-of-band modification
+	CLUSEvAuthAccountRecovered     // local admin account is recovered with the break-glass token
+	CLUSEvConfigBackup             // configuration is backed up to object storage
+	CLUSEvConfigBackupFail
+	CLUSEvConfigRestore // configuration is restored from a backup in object storage
+	CLUSEvKvStoreSizeHigh
+	CLUSEvKvKeyRotated    // data key of kv encryption is rotated
+	CLUSEvReportGenerated // compliance or vulnerability report is generated
+	CLUSEvReportGenerateFail
+	CLUSEvRiskAcceptanceProposed // risk of a vulnerability or compliance check is proposed to be accepted
+	CLUSEvRiskAcceptanceApproved
+	CLUSEvRiskAcceptanceRejected
+	CLUSEvPolicyRuleExpired     // temporary network, response or process rule is expired
+	CLUSEvPolicyChangeSubmitted // network rule changes are submitted for approval
+	CLUSEvPolicyChangeApproved
+	CLUSEvPolicyChangeRejected
 )
 
 const (
@@ -1307,6 +1533,12 @@ const (
 	CLUSIncidContainerTunnel
 	CLUSIncidHostProcessViolation
 	CLUSIncidContainerProcessViolation
+	CLUSIncidHostFileContentDrift
+	CLUSIncidContainerFileContentDrift
+	CLUSIncidHostKernelLoad
+	CLUSIncidContainerKernelLoad
+	CLUSIncidContainerEscape
+	CLUSIncidContainerCryptoMining
 )
 
 const (
@@ -1341,6 +1573,8 @@ type CLUSEventLog struct {
 	RESTMethod     string            `json:"rest_method,omitempty"`
 	RESTRequest    string            `json:"rest_request,omitempty"`
 	RESTBody       string            `json:"rest_body,omitempty"`
+	RESTBefore     string            `json:"rest_before,omitempty"` // masked object before the change
+	RESTAfter      string            `json:"rest_after,omitempty"`  // masked object after the change
 	EnforcerLimit  int               `json:"enforcer_limit,omitempty"`
 	LicenseExpire  time.Time         `json:"license_expire,omitempty"`
 	GroupName      string            `json:"group_name"`
@@ -1413,6 +1647,7 @@ type CLUSIncidentLog struct {
 	RuleID       string       `json:"rule_id"`
 	Group        string       `json:"group"`
 	Msg          string       `json:"message"`
+	Mitre        []string     `json:"mitre,omitempty"`
 }
 
 type CLUSAuditBenchItem struct {
@@ -1446,12 +1681,14 @@ const SnifferIdAgentField = 8
 type CLUSComplianceProfileEntry struct {
 	TestNum string   `json:"test_num"`
 	Tags    []string `json:"tags"`
+	CfgType TCfgType `json:"cfg_type"` // GroundCfg if the entry is defined by crd
 }
 
 type CLUSComplianceProfile struct {
-	Name          string                                `json:"name"`
-	DisableSystem bool                                  `json:"disable_system"`
-	Entries       map[string]CLUSComplianceProfileEntry `json:"entries"`
+	Name              string                                `json:"name"`
+	DisableSystem     bool                                  `json:"disable_system"`
+	Entries           map[string]CLUSComplianceProfileEntry `json:"entries"`
+	UserDisableSystem *bool                                 `json:"user_disable_system,omitempty"` // set when DisableSystem is defined by crd, restored after the crd is deleted
 }
 
 type CLUSVulnerabilityProfileEntry struct {
@@ -1462,6 +1699,7 @@ type CLUSVulnerabilityProfileEntry struct {
 	Days       uint     `json:"days"` // Only used for 'recent' vuln entries
 	Domains    []string `json:"domains"`
 	Images     []string `json:"images"`
+	CfgType    TCfgType `json:"cfg_type"` // GroundCfg if the entry is defined by crd
 }
 
 type CLUSVulnerabilityProfile struct {
@@ -1469,6 +1707,33 @@ type CLUSVulnerabilityProfile struct {
 	Entries []*CLUSVulnerabilityProfileEntry `json:"entries"`
 }
 
+const (
+	RiskAcceptanceVulnerability = "vulnerability"
+	RiskAcceptanceCompliance    = "compliance"
+
+	RiskAcceptanceProposed = "proposed"
+	RiskAcceptanceApproved = "approved"
+	RiskAcceptanceRejected = "rejected"
+)
+
+// An exception of a vulnerability or a compliance check failure. It is proposed by a user and takes effect in the
+// default profile only after another user with the risk approval permission approves it, until it expires.
+type CLUSRiskAcceptance struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	Name          string    `json:"name"` // cve name or compliance check number
+	Domains       []string  `json:"domains"`
+	Images        []string  `json:"images"` // only for vulnerability
+	Justification string    `json:"justification"`
+	ExpireAt      time.Time `json:"expire_at"`
+	Status        string    `json:"status"`
+	ProposedBy    string    `json:"proposed_by"`
+	ProposedAt    time.Time `json:"proposed_at"`
+	ReviewedBy    string    `json:"reviewed_by,omitempty"`
+	ReviewedAt    time.Time `json:"reviewed_at,omitempty"`
+	ReviewComment string    `json:"review_comment,omitempty"`
+}
+
 type CLUSBenchItem struct {
 	Level       string   `json:"level"`
 	TestNum     string   `json:"test_number"`
@@ -1479,6 +1744,23 @@ type CLUSBenchItem struct {
 	Automated   bool     `json:"automated"`
 	Profile     string   `json:"profile"`
 	Group       string   `json:"group"`
+
+	Stig *CLUSBenchStig `json:"stig,omitempty"`
+}
+
+// The DISA STIG identifiers of a check in the STIG benchmark mode
+type CLUSBenchStig struct {
+	VulnID   string `json:"vuln_id"`
+	RuleID   string `json:"rule_id,omitempty"`
+	StigID   string `json:"stig_id"`
+	Severity string `json:"severity"` // high, medium or low
+}
+
+type CLUSBenchStigInfo struct {
+	StigID  string `json:"stig_id"`
+	Title   string `json:"title"`
+	Version string `json:"version"`
+	Release string `json:"release"`
 }
 
 type CLUSBenchState struct {
@@ -1490,6 +1772,8 @@ type CLUSBenchReport struct {
 	RunAt   time.Time        `json:"run_at"`
 	Version string           `json:"version"`
 	Items   []*CLUSBenchItem `json:"items"`
+
+	Stig *CLUSBenchStigInfo `json:"stig,omitempty"` // benchmark of the STIG checks
 }
 
 type BenchType string
@@ -1531,13 +1815,27 @@ const (
 	BenchProfileL2  = "Level 2"
 )
 
+const (
+	BenchModeCIS  = "cis"
+	BenchModeSTIG = "stig"
+
+	BenchStigSeverityHigh   = "high" // CAT I
+	BenchStigSeverityMedium = "medium"
+	BenchStigSeverityLow    = "low"
+)
+
 type CLUSCustomCheck struct {
-	Name   string `json:"name"`
-	Script string `json:"script"`
+	Name        string `json:"name"`
+	Script      string `json:"script"`
+	Description string `json:"description,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+	Profile     string `json:"profile,omitempty"` // Level 1, Level 2
+	Scored      bool   `json:"scored,omitempty"`
 }
 
 type CLUSCustomCheckGroup struct {
-	Scripts []*CLUSCustomCheck `json:"scripts"`
+	Scripts  []*CLUSCustomCheck `json:"scripts"`
+	Schedule uint32             `json:"schedule,omitempty"` // in minutes, 0: run only when the scripts or the members change
 }
 
 type CLUSEventCondition struct {
@@ -1546,15 +1844,20 @@ type CLUSEventCondition struct {
 }
 
 type CLUSResponseRule struct {
-	ID         uint32               `json:"id"`
-	Event      string               `json:"event"`
-	Comment    string               `json:"comment,omitempty"`
-	Group      string               `json:"group,omitempty"`
-	Conditions []CLUSEventCondition `json:"conditions,omitempty"`
-	Actions    []string             `json:"actions"`
-	Webhooks   []string             `json:"webhooks"`
-	Disable    bool                 `json:"disable,omitempty"`
-	CfgType    TCfgType             `json:"cfg_type"`
+	ID              uint32               `json:"id"`
+	Event           string               `json:"event"`
+	Comment         string               `json:"comment,omitempty"`
+	Group           string               `json:"group,omitempty"`
+	Conditions      []CLUSEventCondition `json:"conditions,omitempty"`
+	Actions         []string             `json:"actions"`
+	Webhooks        []string             `json:"webhooks"`
+	WebhookTemplate string               `json:"webhook_template,omitempty"`
+	WebhookSeverity string               `json:"webhook_severity,omitempty"`  // PagerDuty severity
+	WebhookDedupKey string               `json:"webhook_dedup_key,omitempty"` // PagerDuty dedup key
+	QuarExceptions  []CLUSQuarException  `json:"quarantine_exceptions,omitempty"`
+	Disable         bool                 `json:"disable,omitempty"`
+	CfgType         TCfgType             `json:"cfg_type"`
+	ExpireAt        int64                `json:"expire_at,omitempty"` // unix time, the rule is disabled when it expires. 0: never
 }
 
 func CLUSResponseRuleKey(policyName string, id uint32) string {
@@ -1565,6 +1868,20 @@ func CLUSResponseRuleListKey(name string) string {
 	return fmt.Sprintf("%s%s/rules", CLUSConfigResponseRuleStore, name)
 }
 
+func CLUSResponseQuarantineKey(name string) string {
+	return fmt.Sprintf("%s%s/%s", CLUSConfigResponseRuleStore, name, CLUSResCfgQuarantine)
+}
+
+// Workloads running the image are quarantined in all clusters of the federation
+type CLUSFedQuarantineImage struct {
+	Digest    string    `json:"digest"` // image id
+	Name      string    `json:"name,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+	User      string    `json:"user,omitempty"`    // set when it's added by REST api
+	Cluster   string    `json:"cluster,omitempty"` // set when it's added by fed-quarantine response rule action
+	CreatedAt time.Time `json:"created_at"`
+}
+
 func CLUSProfileKey(group string) string {
 	return fmt.Sprintf("%s%s", ProfileProcessStore, group)
 }
@@ -1587,6 +1904,11 @@ type CLUSProcessProfileEntry struct {
 	DerivedGroup    string    `json:"dgroup"`
 	AllowFileUpdate bool      `json:"allow_update"`
 	ProbeCmds       []string  `json:"probe_cmds"`
+	Args            string    `json:"args,omitempty"`
+	ArgsMatch       string    `json:"args_match,omitempty"`
+	Syscalls        []string  `json:"syscalls,omitempty"`  // sampled system calls, reported by enforcers only
+	Unit            string    `json:"unit,omitempty"`      // systemd unit of a host service, "nodes" group only
+	ExpireAt        int64     `json:"expire_at,omitempty"` // unix time, the entry is removed when it expires. 0: never
 }
 
 type CLUSProcessProfile struct {
@@ -1597,6 +1919,15 @@ type CLUSProcessProfile struct {
 	Baseline     string                     `json:"baseline"`
 	Process      []*CLUSProcessProfileEntry `json:"process"`
 	CfgType      TCfgType                   `json:"cfg_type"`
+	Syscalls     []string                   `json:"syscalls,omitempty"` // learned system calls, sorted
+	ZeroDrift    *CLUSZeroDrift             `json:"zero_drift,omitempty"`
+}
+
+// Exceptions of the zero-drift baseline, such as the package managers that install files at runtime.
+type CLUSZeroDrift struct {
+	ReportOnly bool     `json:"report_only,omitempty"` // the drift is reported but not denied
+	Paths      []string `json:"paths,omitempty"`       // a trailing "/*" matches all files under the directory
+	Names      []string `json:"names,omitempty"`
 }
 
 type CLUSRegistryFilter struct {
@@ -1935,6 +2266,7 @@ type CLUSFileMonitorFilter struct {
 	CustomerAdd  bool   `json:"customer_add"`
 	Behavior     string `json:"behavior"`
 	DerivedGroup string `json:"dgroup,omitempty"`
+	Baseline     bool   `json:"baseline,omitempty"`
 }
 
 type CLUSFileMonitorProfile struct {
@@ -1976,7 +2308,9 @@ type CLUSCrdSecurityRule struct {
 	WafGroupSensors []string              `json:"waf_group_sensors"` // waf sensors associated with the target group
 	AdmCtrlRules    map[string]uint32     `json:"admctrl_rules"`     // map key is the generated name of admission control rule, valud is assigned rule id
 	DlpSensor       string                `json:"dlp_sensor"`        // dlp sensor defined in this crd security rule
+	DlpSensorGroups []string              `json:"dlp_sensor_groups"` // groups the dlp sensor defined in this crd security rule is applied to
 	WafSensor       string                `json:"waf_sensor"`        // waf sensor defined in this crd security rule
+	WafSensorGroups []string              `json:"waf_sensor_groups"` // groups the waf sensor defined in this crd security rule is applied to
 	Uid             string                `json:"uid"`               // metadata.uid in admissionreview CREATE request
 }
 
@@ -1992,6 +2326,14 @@ const (
 	FedSystemConfigType        = "fed_system_config"
 )
 
+// types of the fed objects that can be selected for deployment
+const (
+	FedSyncObjGroup         = "group"          // by group name
+	FedSyncObjNetworkRule   = "network_rule"   // by rule id
+	FedSyncObjResponseRule  = "response_rule"  // by rule id
+	FedSyncObjAdmissionRule = "admission_rule" // by rule id
+)
+
 const (
 	CriticalAdmCtrlExceptRulesType = "critical_allow"
 	CrdAdmCtrlExceptRulesType      = "crd_allow"
@@ -2073,6 +2415,20 @@ func CLUSFedJointClusterStatusKey(id string) string {
 	return fmt.Sprintf("%s%s/%s", CLUSConfigFederationStore, CLUSFedClustersStatusSubKey, id)
 }
 
+func CLUSFedClusterReportKey(id string) string {
+	// ex: state/fed_report/cluster/{000-111-222}
+	return fmt.Sprintf("%scluster/%s", CLUSFedReportStore, id)
+}
+
+func CLUSConfigReportKey(name string) string {
+	return fmt.Sprintf("%s%s", CLUSConfigReportStore, name)
+}
+
+func CLUSReportKey(id string) string {
+	// ex: state/report/{000-111-222}
+	return fmt.Sprintf("%s%s", CLUSReportStore, id)
+}
+
 func CLUSFedKey2CfgKey(key string) string {
 	return CLUSKeyNthToken(key, 3)
 }
@@ -2118,12 +2474,40 @@ type CLUSFedMembership struct { // stored on each cluster (master & joint cluste
 	JointCluster     CLUSFedJointClusterInfo  `json:"joint_cluster,omitempty"`  // meaningful when the role is "joint"
 	PendingDismiss   bool                     `json:"pending_dismiss"`          // set to true when the cluster is demoted/kicked & leaves fed. set to false when the fed rules cleanup is done
 	PendingDismissAt time.Time                `json:"pending_dismiss_at"`
-	UseProxy         string                   `json:"use_proxy"` // http / https
+	UseProxy         string                   `json:"use_proxy"`            // http / https
+	StandbyID        string                   `json:"standby_id,omitempty"` // id of the joint cluster designated to take over the federation when the master cluster is unreachable
+	Standby          *CLUSFedStandbySnapshot  `json:"standby,omitempty"`    // meaningful on the standby cluster only
+	FedProxy         *CLUSFedProxyConfig      `json:"fed_proxy,omitempty"`  // egress proxy for federation requests sent by this cluster
+}
+
+// When configured, federation requests sent by the cluster only go thru this proxy
+type CLUSFedProxyConfig struct {
+	URL         string   `json:"url"` // http(s) proxy url. empty means no proxy
+	Username    string   `json:"username"`
+	Password    string   `json:"password,cloak"`
+	ClientCert  string   `json:"client_cert"`      // PEM client cert presented in TLS handshake
+	ClientKey   string   `json:"client_key,cloak"` // PEM client key
+	PinnedCerts []string `json:"pinned_certs"`     // sha256 fingerprints of the accepted remote fed rest server certs
+}
+
+// fed config snapshot that the master cluster gives to the standby cluster in polling responses
+type CLUSFedStandbySnapshot struct {
+	MasterName    string                    `json:"master_name"`
+	PingInterval  uint32                    `json:"ping_interval,omitempty"`
+	PollInterval  uint32                    `json:"poll_interval,omitempty"`
+	JointClusters []CLUSFedJointClusterInfo `json:"joint_clusters"` // other joint clusters, whose secret/keys are for re-keying them when taking over
 }
 
 // fed registry scan data is always deployed
 type CLUSFedSettings struct { // stored on each cluster (master & joint cluster)
-	DeployRepoScanData bool `json:"deploy_repo_scan_data"` // whether fed repo scan data(for _repo_scan on master cluster) deployment is enabled
+	DeployRepoScanData bool                            `json:"deploy_repo_scan_data"`    // whether fed repo scan data(for _repo_scan on master cluster) deployment is enabled
+	SyncSelectors      map[string]*CLUSFedSyncSelector `json:"sync_selectors,omitempty"` // key is FedSyncObj type. only on master cluster
+}
+
+// Without selector, all fed objects of the type are deployed to managed clusters
+type CLUSFedSyncSelector struct {
+	Include []string `json:"include,omitempty"` // only these objects are deployed if it's not empty
+	Exclude []string `json:"exclude,omitempty"` // these objects are not deployed
 }
 
 type CLUSFedClusterStatus struct {
@@ -2137,6 +2521,61 @@ type CLUSFedJoinedClusterList struct { // only available on master cluster
 	IDs []string `json:"ids,omitempty"` // all non-master clusters' id in the federation
 }
 
+// summarized scan/benchmark results of a cluster in the federation. collected by master cluster, stored under state/fed_report/cluster/{id}
+type CLUSFedClusterReport struct {
+	ID          string                      `json:"id"`
+	Name        string                      `json:"name"`
+	CollectedAt time.Time                   `json:"collected_at"`
+	Images      []*CLUSFedImageVulSummary   `json:"images"`
+	Compliances []*CLUSFedComplianceSummary `json:"compliances"`         // failed checks only
+	Addresses   []string                    `json:"addresses,omitempty"` // host ips of the cluster, for matching flows across clusters
+	Flows       []*CLUSFedConverFlow        `json:"flows,omitempty"`     // flows between local workloads and the endpoints outside the cluster
+}
+
+type CLUSFedImageVulSummary struct {
+	ImageID  string   `json:"image_id"`
+	Digest   string   `json:"digest,omitempty"` // manifest digest, only known for registry images
+	Names    []string `json:"names"`
+	HighVuls int      `json:"high"`
+	MedVuls  int      `json:"medium"`
+}
+
+type CLUSFedComplianceSummary struct {
+	Name        string `json:"name"` // test number
+	Category    string `json:"category"`
+	Level       string `json:"level"`
+	Description string `json:"description"`
+	Workloads   int    `json:"workloads"`
+	Nodes       int    `json:"nodes"`
+}
+
+type CLUSFedConverFlow struct {
+	Ingress     bool   `json:"ingress"` // true: from remote ip to local service; false: from local service to remote ip
+	Service     string `json:"service"` // learned group of the local workload
+	Domain      string `json:"domain"`
+	IP          string `json:"ip"`                    // remote ip
+	Port        uint16 `json:"port"`                  // server port
+	MappedPort  uint16 `json:"mapped_port,omitempty"` // host port of the local server in ingress flow
+	IPProto     uint8  `json:"ip_proto"`
+	Application string `json:"application,omitempty"`
+	Bytes       uint64 `json:"bytes"`
+	Sessions    uint32 `json:"sessions"`
+	LastSeen    uint32 `json:"last_seen"`
+}
+
+type CLUSFedReportTrendPoint struct {
+	CollectedAt        time.Time `json:"collected_at"`
+	Clusters           int       `json:"clusters"`
+	Images             int       `json:"images"`
+	HighVuls           int       `json:"high"`
+	MedVuls            int       `json:"medium"`
+	ComplianceFailures int       `json:"compliance_failures"`
+}
+
+type CLUSFedReportTrend struct {
+	Points []*CLUSFedReportTrendPoint `json:"points"`
+}
+
 type TCspType int
 
 const (
@@ -2180,9 +2619,10 @@ type CLUSFedNetworkRulesData struct {
 }
 
 type CLUSFedResponseRulesData struct {
-	Revision  uint64                       `json:"revision"`
-	Rules     map[uint32]*CLUSResponseRule `json:"rules"`
-	RuleHeads []*CLUSRuleHead              `json:"rule_heads"`
+	Revision   uint64                       `json:"revision"`
+	Rules      map[uint32]*CLUSResponseRule `json:"rules"`
+	RuleHeads  []*CLUSRuleHead              `json:"rule_heads"`
+	QuarImages []*CLUSFedQuarantineImage    `json:"quarantine_images"` // nil from older master cluster
 }
 
 type CLUSFedFileMonitorData struct {
@@ -2215,7 +2655,9 @@ type CLUSFedScanRevisions struct {
 
 // dlp rule
 const (
-	DlpRuleKeyPattern string = "pattern"
+	DlpRuleKeyPattern  string = "pattern"
+	DlpRuleKeyFileType string = "file_type" // the value is one of the file types, matched by the magic bytes in body
+	DlpRuleKeyChecksum string = "checksum"  // the value is the checksum validated on the text matched by the patterns
 )
 
 const (
@@ -2223,9 +2665,39 @@ const (
 	DlpPatternContextHEAD    string = "header"
 	DlpPatternContextBODY    string = "body"
 	DlpPatternContextPACKET  string = "packet"
+	DlpPatternContextFILE    string = "file" // text extracted from the documents in http body
 	DlpPatternContextDefault string = "body"
 )
 
+const (
+	DlpFileTypePDF          string = "pdf"
+	DlpFileTypeOffice       string = "office"        // docx, xlsx, pptx
+	DlpFileTypeOfficeLegacy string = "office_legacy" // doc, xls, ppt
+	DlpFileTypeZip          string = "zip"
+	DlpFileTypeGzip         string = "gzip"
+	DlpFileTypeRar          string = "rar"
+	DlpFileType7z           string = "7z"
+)
+
+// Magic bytes of the file types. The office documents are zip archives with the known entries.
+var DlpFileTypeMagic map[string]string = map[string]string{
+	DlpFileTypePDF:          `%PDF-\d\.\d`,
+	DlpFileTypeOffice:       `PK\x03\x04.{26}(\[Content_Types\]\.xml|word/|xl/|ppt/)`,
+	DlpFileTypeOfficeLegacy: `\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1`,
+	DlpFileTypeZip:          `PK\x03\x04`,
+	DlpFileTypeGzip:         `\x1F\x8B\x08`,
+	DlpFileTypeRar:          `Rar!\x1A\x07`,
+	DlpFileType7z:           `7z\xBC\xAF\x27\x1C`,
+}
+
+const (
+	DlpChecksumLuhn     string = "luhn"     // credit card, canada sin
+	DlpChecksumIban     string = "iban"     // iso 13616 mod 97
+	DlpChecksumVerhoeff string = "verhoeff" // india aadhaar
+	DlpChecksumNhs      string = "nhs"      // uk nhs number mod 11
+	DlpChecksumMrz      string = "mrz"      // icao 9303 passport machine readable zone
+)
+
 const (
 	CLUSDlpDefaultSensor = "sensor.dlpdfltnv"
 	CLUSDlpSsnSensor     = "sensor.ssn"
@@ -2389,6 +2861,24 @@ type CLUSWafGroup struct {
 	CfgType TCfgType          `json:"cfg_type"`
 }
 
+// threat signature bundle, imported at runtime and pushed to enforcers
+type CLUSThreatSignature struct {
+	ID       uint32                 `json:"id"`
+	Name     string                 `json:"name"`
+	Comment  string                 `json:"comment,omitempty"`
+	Severity string                 `json:"severity"`
+	Action   string                 `json:"action"`
+	Patterns []CLUSWafCriteriaEntry `json:"patterns"`
+	Disable  bool                   `json:"disable,omitempty"`
+}
+
+type CLUSThreatSignatureBundle struct {
+	Version     string                 `json:"version"`
+	ImportedAt  time.Time              `json:"imported_at"`
+	Signatures  []*CLUSThreatSignature `json:"signatures"`
+	MiningPools []string               `json:"mining_pools,omitempty"` // ip, cidr or fqdn
+}
+
 type CLUSCrdRecord struct {
 	CrdRecord *admissionv1beta1.AdmissionReview
 }
@@ -2413,6 +2903,8 @@ const CLUSReservedUuidRootEscalation string = "00000000-0000-0000-0000-000000000
 const CLUSReservedUuidDockerCp string = "00000000-0000-0000-0000-000000000004"       // docker cp
 const CLUSReservedUuidAnchorMode string = "00000000-0000-0000-0000-000000000005"     // rejected by anchor mode
 const CLUSReservedUuidShieldMode string = "00000000-0000-0000-0000-000000000006"     // rejected by non-family process
+const CLUSReservedUuidKernelLoad string = "00000000-0000-0000-0000-000000000007"     // kernel module or eBPF program load
+const CLUSReservedUuidEscape string = "00000000-0000-0000-0000-000000000008"         // container escape techniques
 
 type ProcRule struct {
 	Active int                     `json:"active"`
@@ -2563,9 +3055,10 @@ func CLUSUserRoleKey(name string) string {
 }
 
 type CLUSRolePermission struct {
-	ID    string `json:"id"`
-	Read  bool   `json:"read"`
-	Write bool   `json:"write"`
+	ID      string   `json:"id"`
+	Read    bool     `json:"read"`
+	Write   bool     `json:"write"`
+	Domains []string `json:"domains,omitempty"` // the permission is only effective on objects in these domains. empty means no restriction
 }
 
 type CLUSUserRole struct {
@@ -2581,6 +3074,15 @@ type CLUSUserRoleInternal struct {
 	Reserved     bool   `json:"reserved"`      // true for pre-defined non-hidden roles: fedAdmin/admin/reader
 	ReadPermits  uint64 `json:"read_permits"`  // sum of all  read permissions of this role
 	WritePermits uint64 `json:"write_permits"` // sum of all write permissions of this role
+	// permissions(included in ReadPermits/WritePermits as well) that are only effective on objects in the specified domains
+	DomainScopes []*CLUSRoleDomainScope `json:"domain_scopes,omitempty"`
+}
+
+type CLUSRoleDomainScope struct {
+	ID           string   `json:"id"` // permission id
+	ReadPermits  uint64   `json:"read_permits"`
+	WritePermits uint64   `json:"write_permits"`
+	Domains      []string `json:"domains"`
 }
 
 type CLUSCIScanDummy struct{} // dummy type just for access control checking purpose
@@ -2603,21 +3105,31 @@ type CLUSActivePwdProfileConfig struct {
 }
 
 type CLUSPwdProfile struct {
-	Name                        string `json:"name"`
-	Comment                     string `json:"comment"`
-	MinLen                      int    `json:"min_len"`
-	MinUpperCount               int    `json:"min_uppercase_count"` // for alphabet characters
-	MinLowerCount               int    `json:"min_lowercase_count"` // for alphabet characters
-	MinDigitCount               int    `json:"min_digit_count"`
-	MinSpecialCount             int    `json:"min_special_count"`
-	EnablePwdExpiration         bool   `json:"enable_password_expiration"`
-	PwdExpireAfterDays          int    `json:"password_expire_after_days"` // must be > 0 when EnablePwdExpiration is true
-	EnablePwdHistory            bool   `json:"enable_password_history"`
-	PwdHistoryCount             int    `json:"password_keep_history_count"`
-	EnableBlockAfterFailedLogin bool   `json:"enable_block_after_failed_login"` // for "Block X minutes after N times failed attempts"
-	BlockAfterFailedCount       int    `json:"block_after_failed_login_count"`  // must be > 0 when EnableBlockAfterFailedLogin is true
-	BlockMinutes                int    `json:"block_minutes"`                   // must be > 0 when EnableBlockAfterFailedLogin is true
-	SessionTimeout              uint32 `json:"session_timeout"`                 // for default user session timeout (in seconds)
+	Name                        string              `json:"name"`
+	Comment                     string              `json:"comment"`
+	MinLen                      int                 `json:"min_len"`
+	MinUpperCount               int                 `json:"min_uppercase_count"` // for alphabet characters
+	MinLowerCount               int                 `json:"min_lowercase_count"` // for alphabet characters
+	MinDigitCount               int                 `json:"min_digit_count"`
+	MinSpecialCount             int                 `json:"min_special_count"`
+	EnablePwdExpiration         bool                `json:"enable_password_expiration"`
+	PwdExpireAfterDays          int                 `json:"password_expire_after_days"` // must be > 0 when EnablePwdExpiration is true
+	EnablePwdHistory            bool                `json:"enable_password_history"`
+	PwdHistoryCount             int                 `json:"password_keep_history_count"`
+	EnableBlockAfterFailedLogin bool                `json:"enable_block_after_failed_login"` // for "Block X minutes after N times failed attempts"
+	BlockAfterFailedCount       int                 `json:"block_after_failed_login_count"`  // must be > 0 when EnableBlockAfterFailedLogin is true
+	BlockMinutes                int                 `json:"block_minutes"`                   // must be > 0 when EnableBlockAfterFailedLogin is true
+	SessionTimeout              uint32              `json:"session_timeout"`                 // for default user session timeout (in seconds)
+	MFARequiredRoles            []string            `json:"mfa_required_roles,omitempty"`    // local users of these global roles must enroll TOTP MFA
+	SessionPolicies             []CLUSSessionPolicy `json:"session_policies,omitempty"`      // per-role session limits
+}
+
+// Limits on the login sessions of the users whose global role is Role. 0 means no limit.
+type CLUSSessionPolicy struct {
+	Role        string `json:"role"`
+	IdleTimeout uint32 `json:"idle_timeout"` // in seconds. the shorter one of it and the user's timeout is used
+	MaxLifetime uint32 `json:"max_lifetime"` // in seconds, since login
+	MaxSessions int    `json:"max_sessions"` // the oldest sessions are revoked when the limit is exceeded
 }
 
 // Import task
@@ -2663,6 +3175,142 @@ type CLUSImportTask struct {
 	CallerFullname string    `json:"caller_fullname"`
 	CallerRemote   string    `json:"caller_remote"`
 	CallerID       string    `json:"caller_id"`
+	Sections       []string  `json:"sections,omitempty"` // config sections to import. all sections in the file if it's empty
+}
+
+// the latest record of the config audit chain, so the chain survives restarts of all controllers
+type CLUSConfigAuditHead struct {
+	Seq  uint64 `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// One-time token to recover the local admin account. Only the hash of the token is kept.
+type CLUSRecoveryToken struct {
+	Username  string    `json:"username"`
+	TokenHash string    `json:"token_hash"`
+	ExpireAt  time.Time `json:"expire_at"`
+}
+
+const (
+	BackupStorageS3    = "s3"
+	BackupStorageGCS   = "gcs"
+	BackupStorageAzure = "azure"
+)
+
+// Object storage that configuration backups are uploaded to
+type CLUSBackupStorage struct {
+	Type        string `json:"type"`
+	Bucket      string `json:"bucket"` // bucket of s3/gcs, or container of azure blob
+	Prefix      string `json:"prefix"`
+	Region      string `json:"region"`
+	Endpoint    string `json:"endpoint"` // for s3 compatible storage
+	AccessKey   string `json:"access_key"`
+	SecretKey   string `json:"secret_key,cloak"`
+	AccountName string `json:"account_name"` // azure storage account
+	SASToken    string `json:"sas_token,cloak"`
+}
+
+type CLUSBackupConfig struct {
+	Enabled    bool              `json:"enabled"`
+	Interval   uint32            `json:"interval"`  // in hours
+	Retention  uint32            `json:"retention"` // number of backups kept in the storage
+	Passphrase string            `json:"passphrase,cloak"`
+	Storage    CLUSBackupStorage `json:"storage"`
+}
+
+type CLUSBackupStatus struct {
+	LastRunAt     time.Time `json:"last_run_at"`
+	LastBackupAt  time.Time `json:"last_backup_at"`
+	LastBackup    string    `json:"last_backup"`
+	LastError     string    `json:"last_error"`
+	LastRestoreAt time.Time `json:"last_restore_at"`
+	LastRestored  string    `json:"last_restored"`
+}
+
+const (
+	ReportTypeCompliance    = "compliance"
+	ReportTypeVulnerability = "vulnerability"
+
+	ReportFormatPDF = "pdf"
+	ReportFormatCSV = "csv"
+)
+
+// Schedule of the compliance or vulnerability report. The report is generated in the interval, or on demand if the
+// interval is 0. The title is a template of the organization name, the scope and the date.
+type CLUSReportSchedule struct {
+	Name       string   `json:"name"`
+	Comment    string   `json:"comment"`
+	Type       string   `json:"type"`
+	Format     string   `json:"format"`
+	Interval   uint32   `json:"interval"`  // in hours
+	Retention  uint32   `json:"retention"` // number of generated reports kept
+	OrgName    string   `json:"org_name"`
+	Title      string   `json:"title"`
+	Domains    []string `json:"domains,omitempty"`  // namespaces in the scope, all if it's empty
+	Tags       []string `json:"tags,omitempty"`     // compliance template tags in the scope, such as PCI
+	Severity   string   `json:"severity,omitempty"` // minimum vulnerability severity in the scope
+	Recipients []string `json:"recipients,omitempty"`
+	Upload     bool     `json:"upload"` // upload to the object storage of the configuration backup
+}
+
+type CLUSReportScheduleStatus struct {
+	LastRunAt time.Time `json:"last_run_at"`
+	LastError string    `json:"last_error"`
+}
+
+type CLUSReportStatus struct {
+	Schedules map[string]*CLUSReportScheduleStatus `json:"schedules"` // key is schedule name
+}
+
+type CLUSReport struct {
+	ID          string    `json:"id"`
+	Schedule    string    `json:"schedule"`
+	Type        string    `json:"type"`
+	Format      string    `json:"format"`
+	Title       string    `json:"title"`
+	FileName    string    `json:"file_name"`
+	GeneratedAt time.Time `json:"generated_at"`
+	GeneratedBy string    `json:"generated_by"`
+	Entries     int       `json:"entries"`
+	Data        []byte    `json:"data"`
+}
+
+type CLUSKvPrefixStats struct {
+	Prefix string `json:"prefix"`
+	Keys   int    `json:"keys"`
+	Size   int64  `json:"size"`
+}
+
+// Size of the kv store, collected by the lead controller
+type CLUSKvStoreStats struct {
+	UpdatedAt     time.Time            `json:"updated_at"`
+	Keys          int                  `json:"keys"`
+	Size          int64                `json:"size"`
+	SizeLimit     int64                `json:"size_limit"`
+	Prefixes      []*CLUSKvPrefixStats `json:"prefixes"`
+	LastCompactAt time.Time            `json:"last_compact_at"`
+	CompactedKeys int                  `json:"compacted_keys"` // orphaned keys removed in the last compaction
+}
+
+// Envelope encryption of cloaked kv values
+const (
+	KvKeyProviderLocal  = "local"
+	KvKeyProviderAwsKMS = "aws_kms"
+	KvKeyProviderVault  = "vault"
+)
+
+type CLUSKvDataKey struct {
+	ID         string    `json:"id"`
+	WrappedKey string    `json:"wrapped_key"` // data key encrypted by the key encryption key of the provider
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CLUSKvKeyring struct {
+	Provider  string           `json:"provider"`
+	KeyID     string           `json:"key_id"` // key encryption key that wraps the data keys
+	ActiveKey string           `json:"active_key"`
+	DataKeys  []*CLUSKvDataKey `json:"data_keys"`
+	RotatedAt time.Time        `json:"rotated_at"`
 }
 
 func CLUSNodeProfileStoreKey(nodeID string) string {
@@ -2737,6 +3385,8 @@ type CLUSApikey struct {
 	ExpirationTimestamp int64               `json:"expiration_timestamp"`
 	CreatedTimestamp    int64               `json:"created_timestamp"`
 	CreatedByEntity     string              `json:"created_by_entity"` // it could be username or apikey (access key)
+	Scopes              []string            `json:"scopes,omitempty"`
+	LastUsedTimestamp   int64               `json:"last_used_timestamp"`
 }
 
 type CLUSSigstoreRootOfTrust struct {
@@ -0,0 +1,1261 @@
+package cluster
+
+// Cluster driver on an external etcd v3 cluster, used instead of the embedded consul when the deployment
+// already runs etcd. It talks to the grpc-gateway of etcd (JSON over http), so no extra client library is needed.
+// Cluster membership and lead election, which come from serf and raft in consul, are emulated with keys attached
+// to leases: a member key is kept alive by its owner, and the lead key is created by the first server that finds it
+// missing.
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share/cluster/api"
+)
+
+const etcdDefaultKeyPrefix = "/neuvector/"
+const etcdMemberTTL = 15 // in seconds
+const etcdLockTTL = 15   // in seconds
+const etcdRequestTimeout = time.Second * 10
+const etcdWatchTimeout = time.Minute * 5
+const etcdRetryInterval = time.Second * 3
+const etcdLockRetryInterval = time.Millisecond * 500
+const etcdCongestWait = time.Second * 2
+
+type EtcdConfig struct {
+	Endpoints []string // like https://10.1.1.1:2379
+	CACert    string   // file of the ca cert that signs etcd server cert
+	Cert      string   // file of the client cert
+	Key       string   // file of the client key
+	KeyPrefix string   // all keys are put under the prefix, "/neuvector/" by default
+}
+
+type etcdMethod struct {
+	cfg         EtcdConfig
+	client      *http.Client
+	watchClient *http.Client // without timeout, for the long-running watch streams
+	clusterIP   string
+	rpcPort     uint
+	endpoint    int // index of the endpoint in use
+	lease       int64
+	leaveCh     chan struct{}
+	mutex       sync.Mutex
+}
+
+var etcd etcdMethod
+
+// UseEtcd selects etcd as the cluster kv store. It must be called before the cluster is started.
+func UseEtcd(cfg *EtcdConfig) error {
+	if len(cfg.Endpoints) == 0 {
+		return errors.New("No etcd endpoint")
+	}
+
+	tlsConfig := &tls.Config{}
+	if cfg.CACert != "" {
+		data, err := ioutil.ReadFile(cfg.CACert)
+		if err != nil {
+			return err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("Invalid etcd ca cert %s", cfg.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.Cert != "" || cfg.Key != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.Cert, cfg.Key)
+		if err != nil {
+			return err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport := &http.Transport{
+		TLSClientConfig: tlsConfig,
+		MaxIdleConns:    16,
+		IdleConnTimeout: 90 * time.Second,
+	}
+
+	etcd.cfg = *cfg
+	etcd.cfg.Endpoints = make([]string, len(cfg.Endpoints))
+	for i, ep := range cfg.Endpoints {
+		etcd.cfg.Endpoints[i] = strings.TrimSuffix(strings.TrimSpace(ep), "/")
+	}
+	if etcd.cfg.KeyPrefix == "" {
+		etcd.cfg.KeyPrefix = etcdDefaultKeyPrefix
+	}
+	etcd.client = &http.Client{Transport: transport, Timeout: etcdRequestTimeout}
+	etcd.watchClient = &http.Client{Transport: transport}
+	driver = &etcd
+
+	log.WithFields(log.Fields{"endpoints": etcd.cfg.Endpoints, "prefix": etcd.cfg.KeyPrefix}).Info("Use etcd")
+	return nil
+}
+
+// -- grpc-gateway messages. []byte is base64 encoded in json; int64 is a string in json.
+
+type etcdInt64 int64
+
+func (i etcdInt64) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(strconv.FormatInt(int64(i), 10))), nil
+}
+
+func (i *etcdInt64) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), "\"")
+	if s == "" || s == "null" {
+		*i = 0
+		return nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	*i = etcdInt64(v)
+	return err
+}
+
+type etcdHeader struct {
+	Revision etcdInt64 `json:"revision"`
+}
+
+type etcdKV struct {
+	Key            []byte    `json:"key"`
+	Value          []byte    `json:"value"`
+	CreateRevision etcdInt64 `json:"create_revision"`
+	ModRevision    etcdInt64 `json:"mod_revision"`
+	Lease          etcdInt64 `json:"lease"`
+}
+
+type etcdRangeReq struct {
+	Key       []byte `json:"key"`
+	RangeEnd  []byte `json:"range_end,omitempty"`
+	KeysOnly  bool   `json:"keys_only,omitempty"`
+	CountOnly bool   `json:"count_only,omitempty"`
+}
+
+type etcdRangeResp struct {
+	Header etcdHeader `json:"header"`
+	Kvs    []*etcdKV  `json:"kvs"`
+	Count  etcdInt64  `json:"count"`
+}
+
+type etcdPutReq struct {
+	Key   []byte    `json:"key"`
+	Value []byte    `json:"value"`
+	Lease etcdInt64 `json:"lease,omitempty"`
+}
+
+type etcdDeleteRangeReq struct {
+	Key      []byte `json:"key"`
+	RangeEnd []byte `json:"range_end,omitempty"`
+}
+
+type etcdCompare struct {
+	Result         string    `json:"result"`
+	Target         string    `json:"target"`
+	Key            []byte    `json:"key"`
+	CreateRevision etcdInt64 `json:"create_revision,omitempty"`
+	ModRevision    etcdInt64 `json:"mod_revision,omitempty"`
+	Value          []byte    `json:"value,omitempty"`
+}
+
+type etcdRequestOp struct {
+	RequestPut         *etcdPutReq         `json:"request_put,omitempty"`
+	RequestDeleteRange *etcdDeleteRangeReq `json:"request_delete_range,omitempty"`
+}
+
+type etcdTxnReq struct {
+	Compare []*etcdCompare   `json:"compare,omitempty"`
+	Success []*etcdRequestOp `json:"success,omitempty"`
+	Failure []*etcdRequestOp `json:"failure,omitempty"`
+}
+
+type etcdTxnResp struct {
+	Header    etcdHeader `json:"header"`
+	Succeeded bool       `json:"succeeded"`
+}
+
+type etcdLeaseReq struct {
+	TTL etcdInt64 `json:"TTL,omitempty"`
+	ID  etcdInt64 `json:"ID,omitempty"`
+}
+
+type etcdLeaseResp struct {
+	ID  etcdInt64 `json:"ID"`
+	TTL etcdInt64 `json:"TTL"`
+}
+
+type etcdKeepAliveResp struct {
+	Result etcdLeaseResp `json:"result"`
+}
+
+type etcdWatchCreateReq struct {
+	Key           []byte    `json:"key"`
+	RangeEnd      []byte    `json:"range_end,omitempty"`
+	StartRevision etcdInt64 `json:"start_revision,omitempty"`
+}
+
+type etcdWatchReq struct {
+	CreateRequest *etcdWatchCreateReq `json:"create_request"`
+}
+
+type etcdWatchResp struct {
+	Result *struct {
+		Canceled        bool              `json:"canceled"`
+		CompactRevision etcdInt64         `json:"compact_revision"`
+		Events          []json.RawMessage `json:"events"`
+	} `json:"result"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type etcdStatusResp struct {
+	Leader etcdInt64 `json:"leader"`
+}
+
+type etcdMember struct {
+	Name   string `json:"name"`
+	Server bool   `json:"server"`
+}
+
+// --
+
+func (m *etcdMethod) kvKey(key string) []byte {
+	return []byte(m.cfg.KeyPrefix + "kv/" + key)
+}
+
+func (m *etcdMethod) memberPrefix() string {
+	return m.cfg.KeyPrefix + "cluster/members/"
+}
+
+func (m *etcdMethod) leadKey() []byte {
+	return []byte(m.cfg.KeyPrefix + "cluster/lead")
+}
+
+// The end of the range that covers all keys with the prefix
+func etcdPrefixEnd(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	// the whole key space
+	return []byte{0}
+}
+
+// Same as the keys listed by consul with a separator, the keys with the separator after the prefix are folded
+// to the part up to the separator
+func etcdFoldKeys(keys []string, prefix, separator string) []string {
+	if separator == "" {
+		return keys
+	}
+
+	folded := make([]string, 0, len(keys))
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		if i := strings.Index(key[len(prefix):], separator); i >= 0 {
+			key = key[:len(prefix)+i+len(separator)]
+		}
+		if !seen[key] {
+			seen[key] = true
+			folded = append(folded, key)
+		}
+	}
+	return folded
+}
+
+func (m *etcdMethod) post(client *http.Client, ctx context.Context, path string, body []byte) (*http.Response, error) {
+	var err error
+	for i := 0; i < len(m.cfg.Endpoints); i++ {
+		m.mutex.Lock()
+		ep := m.cfg.Endpoints[m.endpoint]
+		m.mutex.Unlock()
+
+		var req *http.Request
+		if req, err = http.NewRequest("POST", ep+path, bytes.NewReader(body)); err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if ctx != nil {
+			req = req.WithContext(ctx)
+		}
+
+		var resp *http.Response
+		if resp, err = client.Do(req); err == nil {
+			return resp, nil
+		} else if ctx != nil && ctx.Err() != nil {
+			return nil, err
+		}
+
+		// switch to next endpoint
+		log.WithFields(log.Fields{"endpoint": ep, "error": err}).Error("etcd request")
+		m.mutex.Lock()
+		m.endpoint = (m.endpoint + 1) % len(m.cfg.Endpoints)
+		m.mutex.Unlock()
+	}
+	return nil, err
+}
+
+func (m *etcdMethod) call(path string, req interface{}, resp interface{}) error {
+	body, _ := json.Marshal(req)
+	r, err := m.post(m.client, nil, path, body)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if r.StatusCode != http.StatusOK {
+		return fmt.Errorf("etcd %s: %s %s", path, r.Status, string(data))
+	}
+	if resp != nil {
+		return json.Unmarshal(data, resp)
+	}
+	return nil
+}
+
+func (m *etcdMethod) rangeKeys(req *etcdRangeReq) (*etcdRangeResp, error) {
+	var resp etcdRangeResp
+	if err := m.call("/v3/kv/range", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (m *etcdMethod) txn(req *etcdTxnReq) (bool, error) {
+	var resp etcdTxnResp
+	if err := m.call("/v3/kv/txn", req, &resp); err != nil {
+		return false, err
+	}
+	return resp.Succeeded, nil
+}
+
+func (m *etcdMethod) grantLease(ttl int64) (int64, error) {
+	var resp etcdLeaseResp
+	if err := m.call("/v3/lease/grant", &etcdLeaseReq{TTL: etcdInt64(ttl)}, &resp); err != nil {
+		return 0, err
+	}
+	return int64(resp.ID), nil
+}
+
+// It returns false if the lease has expired
+func (m *etcdMethod) keepAlive(lease int64) (bool, error) {
+	var resp etcdKeepAliveResp
+	if err := m.call("/v3/lease/keepalive", &etcdLeaseReq{ID: etcdInt64(lease)}, &resp); err != nil {
+		return false, err
+	}
+	return resp.Result.TTL > 0, nil
+}
+
+func (m *etcdMethod) revokeLease(lease int64) error {
+	return m.call("/v3/lease/revoke", &etcdLeaseReq{ID: etcdInt64(lease)}, nil)
+}
+
+// Keep the lease alive until stopCh is closed. lostCh is closed if the lease cannot be renewed in its ttl.
+func (m *etcdMethod) keepLeaseAlive(lease int64, ttl int64, stopCh <-chan struct{}, lostCh chan struct{}) {
+	ticker := time.NewTicker(time.Duration(ttl) * time.Second / 3)
+	defer ticker.Stop()
+
+	renewedAt := time.Now()
+	for {
+		select {
+		case <-ticker.C:
+			alive, err := m.keepAlive(lease)
+			if err == nil && alive {
+				renewedAt = time.Now()
+			} else if err == nil || time.Since(renewedAt) > time.Duration(ttl)*time.Second {
+				log.WithFields(log.Fields{"lease": lease, "error": err}).Error("Lease lost")
+				close(lostCh)
+				return
+			}
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// -- cluster
+
+func (m *etcdMethod) Start(cc *ClusterConfig, eCh chan error, recover bool) {
+	log.WithFields(log.Fields{"config": cc, "recover": recover}).Info()
+
+	if cc.AdvertiseAddr != "" {
+		m.clusterIP = cc.AdvertiseAddr
+	} else if cc.BindAddr != "" {
+		m.clusterIP = cc.BindAddr
+	} else {
+		eCh <- fmt.Errorf("No bind address")
+		return
+	}
+	m.rpcPort = cc.RPCPort
+	if m.rpcPort == 0 {
+		m.rpcPort = defaultRPCPort
+	}
+
+	lease, err := m.grantLease(etcdMemberTTL)
+	if err != nil {
+		eCh <- err
+		return
+	}
+
+	member := etcdMember{Name: m.clusterIP, Server: cc.Server}
+	value, _ := json.Marshal(&member)
+	put := &etcdPutReq{Key: []byte(m.memberPrefix() + m.clusterIP), Value: value, Lease: etcdInt64(lease)}
+	if err := m.call("/v3/kv/put", put, nil); err != nil {
+		m.revokeLease(lease)
+		eCh <- err
+		return
+	}
+
+	leaveCh := make(chan struct{})
+	lostCh := make(chan struct{})
+	m.mutex.Lock()
+	m.lease = lease
+	m.leaveCh = leaveCh
+	m.mutex.Unlock()
+
+	go m.keepLeaseAlive(lease, etcdMemberTTL, leaveCh, lostCh)
+
+	// Servers take the lead if there is no lead
+	ticker := time.NewTicker(time.Second * 2)
+	defer ticker.Stop()
+	for {
+		if cc.Server {
+			m.campaign(lease)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-lostCh:
+			eCh <- errors.New("Cluster member lease lost")
+			return
+		case <-leaveCh:
+			eCh <- errors.New("Left cluster")
+			return
+		}
+	}
+}
+
+func (m *etcdMethod) campaign(lease int64) {
+	lead := []byte(fmt.Sprintf("%s:%d", m.clusterIP, m.rpcPort))
+	txn := &etcdTxnReq{
+		Compare: []*etcdCompare{&etcdCompare{Result: "EQUAL", Target: "CREATE", Key: m.leadKey()}},
+		Success: []*etcdRequestOp{
+			&etcdRequestOp{RequestPut: &etcdPutReq{Key: m.leadKey(), Value: lead, Lease: etcdInt64(lease)}},
+		},
+	}
+	if ok, err := m.txn(txn); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to campaign")
+	} else if ok {
+		log.WithFields(log.Fields{"lead": string(lead)}).Info("Take lead")
+	}
+}
+
+func (m *etcdMethod) Join(cc *ClusterConfig) error {
+	return nil
+}
+
+func (m *etcdMethod) Reload(cc *ClusterConfig) error {
+	return nil
+}
+
+// Revoking the lease removes the member key, as well as the lead key if it's the lead
+func (m *etcdMethod) Leave(server bool) error {
+	m.mutex.Lock()
+	lease := m.lease
+	if m.leaveCh != nil {
+		close(m.leaveCh)
+		m.leaveCh = nil
+	}
+	m.lease = 0
+	m.mutex.Unlock()
+
+	if lease == 0 {
+		return nil
+	}
+	return m.revokeLease(lease)
+}
+
+func (m *etcdMethod) ForceLeave(node string, server bool) error {
+	del := &etcdDeleteRangeReq{Key: []byte(m.memberPrefix() + node)}
+	if err := m.call("/v3/kv/deleterange", del, nil); err != nil {
+		return err
+	}
+
+	if lead, _ := m.GetLead(); lead != "" && strings.HasPrefix(lead, node+":") {
+		return m.call("/v3/kv/deleterange", &etcdDeleteRangeReq{Key: m.leadKey()}, nil)
+	}
+	return nil
+}
+
+func (m *etcdMethod) GetSelfAddress() string {
+	return m.clusterIP
+}
+
+func (m *etcdMethod) GetLead() (string, error) {
+	resp, err := m.rangeKeys(&etcdRangeReq{Key: m.leadKey()})
+	if err != nil {
+		return "", err
+	} else if len(resp.Kvs) == 0 {
+		return "", nil
+	}
+	return string(resp.Kvs[0].Value), nil
+}
+
+func (m *etcdMethod) ServerAlive() (bool, error) {
+	var resp etcdStatusResp
+	if err := m.call("/v3/maintenance/status", struct{}{}, &resp); err != nil {
+		log.WithFields(log.Fields{"err": err}).Error()
+		return false, err
+	}
+	return resp.Leader != 0, nil
+}
+
+func (m *etcdMethod) getMembers() (uint64, []*etcdMember, error) {
+	prefix := []byte(m.memberPrefix())
+	resp, err := m.rangeKeys(&etcdRangeReq{Key: prefix, RangeEnd: etcdPrefixEnd(prefix)})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	members := make([]*etcdMember, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var member etcdMember
+		if err := json.Unmarshal(kv.Value, &member); err == nil {
+			members = append(members, &member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+	return uint64(resp.Header.Revision), members, nil
+}
+
+func (m *etcdMethod) GetAllMembers() []ClusterMemberInfo {
+	_, members, err := m.getMembers()
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error()
+		return nil
+	}
+
+	nodes := make([]ClusterMemberInfo, len(members))
+	for i, member := range members {
+		nodes[i].Name = member.Name
+		if member.Server {
+			nodes[i].Role = NodeRoleServer
+		} else {
+			nodes[i].Role = NodeRoleClient
+		}
+		nodes[i].State = NodeStateAlive
+	}
+	return nodes
+}
+
+// -- lock and session
+
+type etcdLock struct {
+	key    string
+	wait   time.Duration
+	lease  int64
+	stopCh chan struct{}
+}
+
+// Same as consul lock, it returns (nil, nil) if the lock cannot be acquired in the wait time
+func (l *etcdLock) Lock(stopCh <-chan struct{}) (<-chan struct{}, error) {
+	lease, err := etcd.grantLease(etcdLockTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	key := etcd.kvKey(l.key)
+	txn := &etcdTxnReq{
+		Compare: []*etcdCompare{&etcdCompare{Result: "EQUAL", Target: "CREATE", Key: key}},
+		Success: []*etcdRequestOp{
+			&etcdRequestOp{RequestPut: &etcdPutReq{Key: key, Value: []byte(etcd.clusterIP), Lease: etcdInt64(lease)}},
+		},
+	}
+
+	start := time.Now()
+	for {
+		if ok, err := etcd.txn(txn); err != nil {
+			etcd.revokeLease(lease)
+			return nil, err
+		} else if ok {
+			break
+		}
+
+		if l.wait > 0 && time.Since(start) > l.wait {
+			etcd.revokeLease(lease)
+			return nil, nil
+		}
+		select {
+		case <-time.After(etcdLockRetryInterval):
+		case <-stopCh:
+			etcd.revokeLease(lease)
+			return nil, nil
+		}
+	}
+
+	l.lease = lease
+	l.stopCh = make(chan struct{})
+	lostCh := make(chan struct{})
+	go etcd.keepLeaseAlive(lease, etcdLockTTL, l.stopCh, lostCh)
+	return lostCh, nil
+}
+
+func (l *etcdLock) Unlock() error {
+	if l.lease == 0 {
+		return errors.New("Lock not held")
+	}
+
+	close(l.stopCh)
+	lease := l.lease
+	l.lease = 0
+	return etcd.revokeLease(lease)
+}
+
+func (l *etcdLock) Key() string {
+	return l.key
+}
+
+func (m *etcdMethod) NewLock(key string, wait time.Duration) (LockInterface, error) {
+	return &etcdLock{key: key, wait: wait}, nil
+}
+
+type etcdSession struct {
+	id    string
+	lease int64
+}
+
+func (s *etcdSession) Associate(key string) error {
+	k := etcd.kvKey(key)
+	txn := &etcdTxnReq{
+		Compare: []*etcdCompare{&etcdCompare{Result: "EQUAL", Target: "CREATE", Key: k}},
+		Success: []*etcdRequestOp{
+			&etcdRequestOp{RequestPut: &etcdPutReq{Key: k, Value: []byte(s.id), Lease: etcdInt64(s.lease)}},
+		},
+	}
+	if ok, err := etcd.txn(txn); err != nil {
+		return fmt.Errorf("Failed to hold, err=%s", err.Error())
+	} else if !ok {
+		return fmt.Errorf("Failed to hold")
+	} else {
+		return nil
+	}
+}
+
+func (s *etcdSession) Disassociate(key string) error {
+	k := etcd.kvKey(key)
+	txn := &etcdTxnReq{
+		Compare: []*etcdCompare{&etcdCompare{Result: "EQUAL", Target: "VALUE", Key: k, Value: []byte(s.id)}},
+		Success: []*etcdRequestOp{&etcdRequestOp{RequestDeleteRange: &etcdDeleteRangeReq{Key: k}}},
+	}
+	if ok, err := etcd.txn(txn); err != nil {
+		return nil
+	} else if !ok {
+		return fmt.Errorf("Failed to release")
+	} else {
+		return nil
+	}
+}
+
+func (m *etcdMethod) NewSession(name string, ttl time.Duration) (SessionInterface, error) {
+	seconds := int64((ttl + time.Second - 1) / time.Second)
+	lease, err := m.grantLease(seconds)
+	if err != nil {
+		return nil, err
+	}
+	return &etcdSession{id: fmt.Sprintf("%s-%x", name, lease), lease: lease}, nil
+}
+
+// -- kv
+
+func (m *etcdMethod) Exist(key string) bool {
+	k := m.kvKey(key)
+	resp, err := m.rangeKeys(&etcdRangeReq{Key: k, RangeEnd: etcdPrefixEnd(k), CountOnly: true})
+	return err == nil && resp.Count > 0
+}
+
+func (m *etcdMethod) GetKeys(prefix, separater string) ([]string, error) {
+	keys, err := m.GetStoreKeys(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return etcdFoldKeys(keys, prefix, separater), nil
+}
+
+func (m *etcdMethod) Get(key string) ([]byte, error) {
+	value, _, err := m.GetRev(key)
+	return value, err
+}
+
+func (m *etcdMethod) GetRev(key string) ([]byte, uint64, error) {
+	resp, err := m.rangeKeys(&etcdRangeReq{Key: m.kvKey(key)})
+	if err != nil {
+		return nil, 0, err
+	} else if len(resp.Kvs) == 0 {
+		return nil, 0, ErrKeyNotFound
+	}
+	return resp.Kvs[0].Value, uint64(resp.Kvs[0].ModRevision), nil
+}
+
+func (m *etcdMethod) GetStoreKeys(store string) ([]string, error) {
+	k := m.kvKey(store)
+	resp, err := m.rangeKeys(&etcdRangeReq{Key: k, RangeEnd: etcdPrefixEnd(k), KeysOnly: true})
+	if err != nil {
+		return nil, err
+	} else if len(resp.Kvs) == 0 {
+		return nil, ErrEmptyStore
+	}
+
+	trim := len(m.kvKey(""))
+	keys := make([]string, len(resp.Kvs))
+	for i, kv := range resp.Kvs {
+		keys[i] = string(kv.Key[trim:])
+	}
+	return keys, nil
+}
+
+func (m *etcdMethod) Put(key string, value []byte) error {
+	return m.call("/v3/kv/put", &etcdPutReq{Key: m.kvKey(key), Value: value}, nil)
+}
+
+// Same as consul CAS, rev 0 means the key must not exist
+func (m *etcdMethod) revCompare(key []byte, rev uint64) *etcdCompare {
+	if rev == 0 {
+		return &etcdCompare{Result: "EQUAL", Target: "CREATE", Key: key}
+	}
+	return &etcdCompare{Result: "EQUAL", Target: "MOD", Key: key, ModRevision: etcdInt64(rev)}
+}
+
+func (m *etcdMethod) PutRev(key string, value []byte, rev uint64) error {
+	k := m.kvKey(key)
+	txn := &etcdTxnReq{
+		Compare: []*etcdCompare{m.revCompare(k, rev)},
+		Success: []*etcdRequestOp{&etcdRequestOp{RequestPut: &etcdPutReq{Key: k, Value: value}}},
+	}
+	ok, err := m.txn(txn)
+	if !ok && err == nil {
+		err = errPutCAS
+	}
+	return err
+}
+
+func (m *etcdMethod) PutIfNotExist(key string, value []byte) error {
+	return m.PutRev(key, value, 0)
+}
+
+func (m *etcdMethod) Delete(key string) error {
+	return m.call("/v3/kv/deleterange", &etcdDeleteRangeReq{Key: m.kvKey(key)}, nil)
+}
+
+func (m *etcdMethod) list(keyPrefix string) (uint64, api.KVPairs, error) {
+	k := m.kvKey(keyPrefix)
+	resp, err := m.rangeKeys(&etcdRangeReq{Key: k, RangeEnd: etcdPrefixEnd(k)})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	trim := len(m.kvKey(""))
+	var pairs api.KVPairs
+	for _, kv := range resp.Kvs {
+		pairs = append(pairs, &api.KVPair{
+			Key:         string(kv.Key[trim:]),
+			Value:       kv.Value,
+			CreateIndex: uint64(kv.CreateRevision),
+			ModifyIndex: uint64(kv.ModRevision),
+		})
+	}
+	return uint64(resp.Header.Revision), pairs, nil
+}
+
+func (m *etcdMethod) List(keyPrefix string) (api.KVPairs, error) {
+	_, pairs, err := m.list(keyPrefix)
+	return pairs, err
+}
+
+func (m *etcdMethod) DeleteTree(keyPrefix string) error {
+	k := m.kvKey(keyPrefix)
+	return m.call("/v3/kv/deleterange", &etcdDeleteRangeReq{Key: k, RangeEnd: etcdPrefixEnd(k)}, nil)
+}
+
+// etcd rejects a transaction that writes a key more than once, including a put inside a deleted range, so the
+// entries are merged into one operation per key, in which the later entry wins. A tree deletion is expanded to
+// the deletion of the keys that exist under the prefix.
+func (m *etcdMethod) Transact(entries []transactEntry) (bool, error) {
+	var cmps []*etcdCompare
+	var ops []*etcdRequestOp
+	opIndex := make(map[string]int)
+	setOp := func(key string, op *etcdRequestOp) {
+		if i, ok := opIndex[key]; ok {
+			ops[i] = op
+		} else {
+			opIndex[key] = len(ops)
+			ops = append(ops, op)
+		}
+	}
+	deleteOp := func(key string) *etcdRequestOp {
+		return &etcdRequestOp{RequestDeleteRange: &etcdDeleteRangeReq{Key: m.kvKey(key)}}
+	}
+
+	for _, e := range entries {
+		switch e.verb {
+		case clusterTransactPut:
+			setOp(e.key, &etcdRequestOp{RequestPut: &etcdPutReq{Key: m.kvKey(e.key), Value: e.value}})
+		case clusterTransactPutRev:
+			cmps = append(cmps, m.revCompare(m.kvKey(e.key), e.rev))
+			setOp(e.key, &etcdRequestOp{RequestPut: &etcdPutReq{Key: m.kvKey(e.key), Value: e.value}})
+		case clusterTransactDelete:
+			setOp(e.key, deleteOp(e.key))
+		case clusterTransactDeleteRev:
+			cmps = append(cmps, m.revCompare(m.kvKey(e.key), e.rev))
+			setOp(e.key, deleteOp(e.key))
+		case clusterTransactCheckRev:
+			cmps = append(cmps, m.revCompare(m.kvKey(e.key), e.rev))
+		case clusterTransactDeleteTree:
+			keys, err := m.GetStoreKeys(e.key)
+			if err != nil && err != ErrEmptyStore {
+				return false, err
+			}
+			for key := range opIndex {
+				if strings.HasPrefix(key, e.key) {
+					keys = append(keys, key)
+				}
+			}
+			for _, key := range keys {
+				setOp(key, deleteOp(key))
+			}
+		default:
+			return false, errors.New("Unsupported verb")
+		}
+	}
+
+	ok, err := m.txn(&etcdTxnReq{Compare: cmps, Success: ops})
+	if err == nil && !ok {
+		log.WithFields(log.Fields{"entries": len(entries)}).Error("Transaction compare failed")
+	}
+	return ok, err
+}
+
+// -- watch
+
+type etcdWatchPlan struct {
+	key        string // same as the key of consul watch plan
+	prefix     []byte
+	rangeEnd   []byte
+	fetch      func() (uint64, uint64, interface{}, error) // returns revision, index and result
+	handler    func(uint64, interface{})
+	congestCtl bool
+	paused     bool
+	stopped    bool
+	stopCh     chan struct{}
+	fail       func() bool
+	recover    func()
+	mutex      sync.Mutex
+}
+
+var etcdWatchPlans []*etcdWatchPlan = make([]*etcdWatchPlan, 0)
+var etcdWatchMutex sync.Mutex
+
+func (p *etcdWatchPlan) stop() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if !p.stopped {
+		p.stopped = true
+		close(p.stopCh)
+	}
+}
+
+func (p *etcdWatchPlan) setPaused(paused bool) {
+	p.mutex.Lock()
+	p.paused = paused
+	p.mutex.Unlock()
+}
+
+func (p *etcdWatchPlan) isCongestCtl() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.congestCtl
+}
+
+// Block while paused
+func (p *etcdWatchPlan) shouldStop() bool {
+	for {
+		p.mutex.Lock()
+		paused, stopped := p.paused, p.stopped
+		p.mutex.Unlock()
+		if stopped {
+			return true
+		} else if !paused {
+			return false
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+}
+
+// Same as consul watch, the handler is called with the whole result when any key in the range is changed
+func (p *etcdWatchPlan) run() {
+	var lastResult interface{}
+	first := true
+	failures := 0
+	reportFail := true
+	for !p.shouldStop() {
+		rev, index, result, err := p.fetch()
+		if err == nil {
+			if failures > 0 && p.recover != nil {
+				p.recover()
+			}
+			failures = 0
+			reportFail = true
+
+			if first || !reflect.DeepEqual(result, lastResult) {
+				first = false
+				lastResult = result
+				p.handler(index, result)
+			}
+
+			err = etcd.waitChange(p.prefix, p.rangeEnd, rev+1, p.stopCh)
+		}
+
+		if p.shouldStop() {
+			break
+		}
+
+		if err != nil {
+			failures++
+			if reportFail && p.fail != nil && p.fail() {
+				// report is accepted
+				reportFail = false
+			}
+			log.WithFields(log.Fields{"key": p.key, "error": err, "fails": failures}).Error("etcd watch")
+
+			select {
+			case <-time.After(etcdRetryInterval):
+			case <-p.stopCh:
+				return
+			}
+		} else if p.isCongestCtl() {
+			// collect the surge of updates in one query
+			select {
+			case <-time.After(etcdCongestWait):
+			case <-p.stopCh:
+				return
+			}
+		}
+	}
+}
+
+// It returns when a key in the range is changed since the revision, or when the watch times out
+func (m *etcdMethod) waitChange(key, rangeEnd []byte, rev uint64, stopCh chan struct{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), etcdWatchTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	req := &etcdWatchReq{CreateRequest: &etcdWatchCreateReq{Key: key, RangeEnd: rangeEnd, StartRevision: etcdInt64(rev)}}
+	body, _ := json.Marshal(req)
+	r, err := m.post(m.watchClient, ctx, "/v3/watch", body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		return fmt.Errorf("etcd watch: %s", r.Status)
+	}
+
+	dec := json.NewDecoder(r.Body)
+	for {
+		var resp etcdWatchResp
+		if err := dec.Decode(&resp); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if resp.Error != nil {
+			return fmt.Errorf("etcd watch: %s", resp.Error.Message)
+		}
+		if resp.Result != nil && (resp.Result.Canceled || resp.Result.CompactRevision > 0 || len(resp.Result.Events) > 0) {
+			// query again if the revision has been compacted
+			return nil
+		}
+	}
+}
+
+func (m *etcdMethod) register(p *etcdWatchPlan) {
+	p.stopCh = make(chan struct{})
+
+	etcdWatchMutex.Lock()
+	if len(etcdWatchPlans) == 0 {
+		p.fail = watcherFailFunc
+		p.recover = watcherRecoverFunc
+	}
+	etcdWatchPlans = append(etcdWatchPlans, p)
+	etcdWatchMutex.Unlock()
+
+	p.run()
+}
+
+func (m *etcdMethod) registerNodeUpdate() {
+	prefix := []byte(m.memberPrefix())
+	m.register(&etcdWatchPlan{
+		key:      "nodes",
+		prefix:   prefix,
+		rangeEnd: etcdPrefixEnd(prefix),
+		fetch: func() (uint64, uint64, interface{}, error) {
+			rev, members, err := m.getMembers()
+			nodes := make([]*api.Node, len(members))
+			for i, member := range members {
+				nodes[i] = &api.Node{Node: member.Name, Address: member.Name}
+			}
+			return rev, rev, nodes, err
+		},
+		handler: func(idx uint64, data interface{}) {
+			nodeUpdateCallback(data.([]*api.Node))
+		},
+	})
+}
+
+// Members are "passing" while their keys exist. A member whose key is removed turns "critical"
+func (m *etcdMethod) registerStateUpdate() {
+	prefix := []byte(m.memberPrefix())
+	known := make(map[string]bool)
+	m.register(&etcdWatchPlan{
+		key:      "checks",
+		prefix:   prefix,
+		rangeEnd: etcdPrefixEnd(prefix),
+		fetch: func() (uint64, uint64, interface{}, error) {
+			rev, members, err := m.getMembers()
+			checks := make([]*api.HealthCheck, len(members))
+			for i, member := range members {
+				checks[i] = &api.HealthCheck{Node: member.Name, Status: "passing"}
+			}
+			return rev, rev, checks, err
+		},
+		handler: func(idx uint64, data interface{}) {
+			checks := data.([]*api.HealthCheck)
+			alive := make(map[string]bool)
+			for _, check := range checks {
+				alive[check.Node] = true
+			}
+			for node := range known {
+				if !alive[node] {
+					checks = append(checks, &api.HealthCheck{Node: node, Status: "critical"})
+				}
+			}
+			known = alive
+			stateUpdateCallback(checks)
+		},
+	})
+}
+
+func (m *etcdMethod) registerKeyUpdate(key string) {
+	log.WithFields(log.Fields{"key": key}).Debug("")
+
+	m.register(&etcdWatchPlan{
+		key:    key,
+		prefix: m.kvKey(key),
+		fetch: func() (uint64, uint64, interface{}, error) {
+			resp, err := m.rangeKeys(&etcdRangeReq{Key: m.kvKey(key)})
+			if err != nil {
+				return 0, 0, nil, err
+			} else if len(resp.Kvs) == 0 {
+				return uint64(resp.Header.Revision), uint64(resp.Header.Revision), nil, nil
+			}
+			kv := resp.Kvs[0]
+			pair := &api.KVPair{
+				Key:         key,
+				Value:       kv.Value,
+				CreateIndex: uint64(kv.CreateRevision),
+				ModifyIndex: uint64(kv.ModRevision),
+			}
+			return uint64(resp.Header.Revision), pair.ModifyIndex, pair, nil
+		},
+		handler: func(idx uint64, data interface{}) {
+			keyUpdateCallback(idx, key, data)
+		},
+	})
+}
+
+func (m *etcdMethod) registerStoreUpdate(store string, bCongestCtl bool) {
+	log.WithFields(log.Fields{"store": store}).Debug("")
+
+	prefix := m.kvKey(store)
+	m.register(&etcdWatchPlan{
+		key:        store,
+		prefix:     prefix,
+		rangeEnd:   etcdPrefixEnd(prefix),
+		congestCtl: bCongestCtl,
+		fetch: func() (uint64, uint64, interface{}, error) {
+			rev, pairs, err := m.list(store)
+			return rev, rev, pairs, err
+		},
+		handler: func(idx uint64, data interface{}) {
+			storeUpdateCallback(idx, store, data)
+		},
+	})
+}
+
+func (m *etcdMethod) RegisterNodeWatcher(watcher NodeWatcher) {
+	w := addNodeWatcher(watcher)
+	if !w || len(nodeWatchers) != 1 {
+		return
+	}
+
+	go m.registerNodeUpdate()
+}
+
+func (m *etcdMethod) RegisterKeyWatcher(key string, watcher KeyWatcher) {
+	keyWatcherMutex.Lock()
+	defer keyWatcherMutex.Unlock()
+
+	w := addKeyWatcher(key, watcher)
+	if !w || len(keyWatchers[key]) != 1 {
+		return
+	}
+
+	go m.registerKeyUpdate(key)
+}
+
+func (m *etcdMethod) RegisterStateWatcher(watcher StateWatcher) {
+	w := addStateWatcher(watcher)
+	if !w || len(stateWatchers) != 1 {
+		return
+	}
+
+	go m.registerStateUpdate()
+}
+
+func (m *etcdMethod) RegisterStoreWatcher(store string, watcher StoreWatcher, bCongestCtl bool) {
+	storeWatcherMutex.Lock()
+	defer storeWatcherMutex.Unlock()
+
+	w := addStoreWatcher(store, watcher, bCongestCtl)
+	if !w || len(storeWatchers[store]) != 1 {
+		return
+	}
+
+	go m.registerStoreUpdate(store, bCongestCtl)
+}
+
+func (m *etcdMethod) RegisterExistingWatchers() {
+	log.Debug("")
+
+	if len(nodeWatchers) > 0 {
+		go m.registerNodeUpdate()
+	}
+	keyWatcherMutex.RLock()
+	for key := range keyWatchers {
+		go m.registerKeyUpdate(key)
+	}
+	keyWatcherMutex.RUnlock()
+	if len(stateWatchers) > 0 {
+		go m.registerStateUpdate()
+	}
+	storeWatcherMutex.RLock()
+	for store := range storeWatchers {
+		go m.registerStoreUpdate(store, storeWatchersCongestCtl[store])
+	}
+	storeWatcherMutex.RUnlock()
+}
+
+func (m *etcdMethod) RegisterWatcherMonitor(failFunc func() bool, recoverFunc func()) {
+	log.Debug("")
+	watcherFailFunc = failFunc
+	watcherRecoverFunc = recoverFunc
+
+	etcdWatchMutex.Lock()
+	defer etcdWatchMutex.Unlock()
+	if len(etcdWatchPlans) > 0 {
+		p := etcdWatchPlans[0]
+		p.fail = watcherFailFunc
+		p.recover = watcherRecoverFunc
+	}
+}
+
+func (m *etcdMethod) StopAllWatchers() {
+	etcdWatchMutex.Lock()
+	defer etcdWatchMutex.Unlock()
+	for _, p := range etcdWatchPlans {
+		p.stop()
+	}
+	etcdWatchPlans = etcdWatchPlans[:0]
+}
+
+func (m *etcdMethod) PauseAllWatchers(includeMonitorWatch bool) {
+	etcdWatchMutex.Lock()
+	defer etcdWatchMutex.Unlock()
+	for _, p := range etcdWatchPlans {
+		if p.recover == nil || includeMonitorWatch {
+			p.setPaused(true)
+		}
+	}
+}
+
+func (m *etcdMethod) ResumeAllWatchers() {
+	etcdWatchMutex.Lock()
+	defer etcdWatchMutex.Unlock()
+	for _, p := range etcdWatchPlans {
+		p.setPaused(false)
+	}
+}
+
+func (m *etcdMethod) PauseWatcher(key string) {
+	etcdWatchMutex.Lock()
+	defer etcdWatchMutex.Unlock()
+	for _, p := range etcdWatchPlans {
+		if p.key == key {
+			log.WithFields(log.Fields{"key": p.key}).Debug("")
+			p.setPaused(true)
+		}
+	}
+}
+
+func (m *etcdMethod) ResumeWatcher(key string) {
+	etcdWatchMutex.Lock()
+	defer etcdWatchMutex.Unlock()
+	for _, p := range etcdWatchPlans {
+		if p.key == key {
+			log.WithFields(log.Fields{"key": p.key}).Debug("")
+			p.setPaused(false)
+		}
+	}
+}
+
+func (m *etcdMethod) SetWatcherCongestionCtl(key string, enabled bool) {
+	etcdWatchMutex.Lock()
+	defer etcdWatchMutex.Unlock()
+	for _, p := range etcdWatchPlans {
+		if p.key == key {
+			log.WithFields(log.Fields{"key": p.key, "enabled": enabled}).Debug("")
+			p.mutex.Lock()
+			p.congestCtl = enabled
+			p.mutex.Unlock()
+			break
+		}
+	}
+}
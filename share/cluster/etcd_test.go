@@ -0,0 +1,556 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// In-memory grpc-gateway of etcd, with the keys attached to leases and the watch streams
+type etcdFakeKV struct {
+	value  []byte
+	create int64
+	mod    int64
+	lease  int64
+}
+
+type etcdFake struct {
+	server    *httptest.Server
+	mutex     sync.Mutex
+	rev       int64
+	kvs       map[string]*etcdFakeKV
+	changes   map[string]int64 // key to the revision of its last change, including deletion
+	leases    map[int64]bool
+	lastLease int64
+	watchFail int // number of watch requests to fail
+	changeCh  chan struct{}
+}
+
+func newEtcdFake() (*etcdFake, func()) {
+	f := &etcdFake{
+		kvs:      make(map[string]*etcdFakeKV),
+		changes:  make(map[string]int64),
+		leases:   make(map[int64]bool),
+		rev:      1,
+		changeCh: make(chan struct{}),
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+
+	savedCfg, savedClient, savedWatchClient, savedIP := etcd.cfg, etcd.client, etcd.watchClient, etcd.clusterIP
+	etcd.cfg = EtcdConfig{Endpoints: []string{f.server.URL}, KeyPrefix: "/test/"}
+	etcd.client = f.server.Client()
+	etcd.watchClient = f.server.Client()
+	etcd.clusterIP = "10.1.1.1"
+	return f, func() {
+		f.server.Close()
+		etcd.cfg, etcd.client, etcd.watchClient, etcd.clusterIP = savedCfg, savedClient, savedWatchClient, savedIP
+	}
+}
+
+func inEtcdRange(key, start, end []byte) bool {
+	if len(end) == 0 {
+		return bytes.Equal(key, start)
+	}
+	return bytes.Compare(key, start) >= 0 && (bytes.Equal(end, []byte{0}) || bytes.Compare(key, end) < 0)
+}
+
+// mutex locked
+func (f *etcdFake) changed(key string) {
+	f.rev++
+	f.changes[key] = f.rev
+	close(f.changeCh)
+	f.changeCh = make(chan struct{})
+}
+
+// mutex locked
+func (f *etcdFake) put(req *etcdPutReq) {
+	key := string(req.Key)
+	kv, ok := f.kvs[key]
+	f.changed(key)
+	if !ok {
+		kv = &etcdFakeKV{create: f.rev}
+		f.kvs[key] = kv
+	}
+	kv.value, kv.mod, kv.lease = req.Value, f.rev, int64(req.Lease)
+}
+
+// mutex locked
+func (f *etcdFake) deleteRange(req *etcdDeleteRangeReq) {
+	for key := range f.kvs {
+		if inEtcdRange([]byte(key), req.Key, req.RangeEnd) {
+			delete(f.kvs, key)
+			f.changed(key)
+		}
+	}
+}
+
+// The lease is gone and so are its keys, as if it expired
+func (f *etcdFake) expireLease(id int64) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	delete(f.leases, id)
+	for key, kv := range f.kvs {
+		if kv.lease == id {
+			delete(f.kvs, key)
+			f.changed(key)
+		}
+	}
+}
+
+func (f *etcdFake) get(key string) *etcdFakeKV {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.kvs[etcd.cfg.KeyPrefix+"kv/"+key]
+}
+
+// mutex locked
+func (f *etcdFake) compare(c *etcdCompare) bool {
+	kv := f.kvs[string(c.Key)]
+	if kv == nil {
+		kv = &etcdFakeKV{}
+	}
+	switch c.Target {
+	case "CREATE":
+		return kv.create == int64(c.CreateRevision)
+	case "MOD":
+		return kv.mod == int64(c.ModRevision)
+	case "VALUE":
+		return bytes.Equal(kv.value, c.Value)
+	}
+	return false
+}
+
+func (f *etcdFake) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	if r.URL.Path == "/v3/watch" {
+		f.watch(w, r, body)
+		return
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var resp interface{} = struct{}{}
+	switch r.URL.Path {
+	case "/v3/kv/range":
+		var req etcdRangeReq
+		json.Unmarshal(body, &req)
+		rr := &etcdRangeResp{Header: etcdHeader{Revision: etcdInt64(f.rev)}}
+		keys := make([]string, 0)
+		for key := range f.kvs {
+			if inEtcdRange([]byte(key), req.Key, req.RangeEnd) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			kv := f.kvs[key]
+			rr.Kvs = append(rr.Kvs, &etcdKV{Key: []byte(key), Value: kv.value, CreateRevision: etcdInt64(kv.create),
+				ModRevision: etcdInt64(kv.mod), Lease: etcdInt64(kv.lease)})
+		}
+		rr.Count = etcdInt64(len(keys))
+		resp = rr
+	case "/v3/kv/put":
+		var req etcdPutReq
+		json.Unmarshal(body, &req)
+		f.put(&req)
+	case "/v3/kv/deleterange":
+		var req etcdDeleteRangeReq
+		json.Unmarshal(body, &req)
+		f.deleteRange(&req)
+	case "/v3/kv/txn":
+		var req etcdTxnReq
+		json.Unmarshal(body, &req)
+		ok := true
+		for _, c := range req.Compare {
+			ok = ok && f.compare(c)
+		}
+		ops := req.Success
+		if !ok {
+			ops = req.Failure
+		}
+		for _, op := range ops {
+			if op.RequestPut != nil {
+				f.put(op.RequestPut)
+			} else if op.RequestDeleteRange != nil {
+				f.deleteRange(op.RequestDeleteRange)
+			}
+		}
+		resp = &etcdTxnResp{Header: etcdHeader{Revision: etcdInt64(f.rev)}, Succeeded: ok}
+	case "/v3/lease/grant":
+		var req etcdLeaseReq
+		json.Unmarshal(body, &req)
+		f.lastLease++
+		f.leases[f.lastLease] = true
+		resp = &etcdLeaseResp{ID: etcdInt64(f.lastLease), TTL: req.TTL}
+	case "/v3/lease/keepalive":
+		var req etcdLeaseReq
+		json.Unmarshal(body, &req)
+		ka := &etcdKeepAliveResp{Result: etcdLeaseResp{ID: req.ID}}
+		if f.leases[int64(req.ID)] {
+			ka.Result.TTL = etcdInt64(etcdLockTTL)
+		}
+		resp = ka
+	case "/v3/lease/revoke":
+		var req etcdLeaseReq
+		json.Unmarshal(body, &req)
+		delete(f.leases, int64(req.ID))
+		for key, kv := range f.kvs {
+			if kv.lease == int64(req.ID) {
+				delete(f.kvs, key)
+				f.changed(key)
+			}
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	data, _ := json.Marshal(resp)
+	w.Write(data)
+}
+
+// The stream ends after the first event, as waitChange only waits for one
+func (f *etcdFake) watch(w http.ResponseWriter, r *http.Request, body []byte) {
+	var req etcdWatchReq
+	json.Unmarshal(body, &req)
+	cr := req.CreateRequest
+
+	f.mutex.Lock()
+	if f.watchFail > 0 {
+		f.watchFail--
+		f.mutex.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	f.mutex.Unlock()
+
+	w.Write([]byte(`{"result":{"created":true}}` + "\n"))
+	w.(http.Flusher).Flush()
+	for {
+		f.mutex.Lock()
+		for key, rev := range f.changes {
+			if rev >= int64(cr.StartRevision) && inEtcdRange([]byte(key), cr.Key, cr.RangeEnd) {
+				f.mutex.Unlock()
+				w.Write([]byte(`{"result":{"events":[{}]}}` + "\n"))
+				return
+			}
+		}
+		changeCh := f.changeCh
+		f.mutex.Unlock()
+
+		select {
+		case <-changeCh:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func TestEtcdPrefixEnd(t *testing.T) {
+	if end := etcdPrefixEnd([]byte("/neuvector/kv/object/")); string(end) != "/neuvector/kv/object0" {
+		t.Errorf("Unexpected range end: %s", string(end))
+	}
+	if end := etcdPrefixEnd([]byte{'a', 0xff}); !reflect.DeepEqual(end, []byte{'b'}) {
+		t.Errorf("Unexpected range end: %v", end)
+	}
+}
+
+func TestEtcdFoldKeys(t *testing.T) {
+	keys := []string{"object/config/a", "object/config/b", "object/host/h1", "object/x"}
+	folded := etcdFoldKeys(keys, "object/", "/")
+	if !reflect.DeepEqual(folded, []string{"object/config/", "object/host/", "object/x"}) {
+		t.Errorf("Unexpected keys: %v", folded)
+	}
+	if folded = etcdFoldKeys(keys, "object/", ""); !reflect.DeepEqual(folded, keys) {
+		t.Errorf("Keys should not be folded without separator: %v", folded)
+	}
+}
+
+func TestEtcdTransact(t *testing.T) {
+	var txn etcdTxnReq
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/v3/kv/range":
+			w.Write([]byte(`{"header":{"revision":"9"},"kvs":[{"key":"L3Rlc3Qva3YvdHJlZS9h"}],"count":"1"}`))
+		case "/v3/kv/txn":
+			json.Unmarshal(body, &txn)
+			w.Write([]byte(`{"header":{"revision":"10"},"succeeded":true}`))
+		}
+	}))
+	defer server.Close()
+
+	savedCfg, savedClient := etcd.cfg, etcd.client
+	defer func() { etcd.cfg, etcd.client = savedCfg, savedClient }()
+	etcd.cfg = EtcdConfig{Endpoints: []string{server.URL}, KeyPrefix: "/test/"}
+	etcd.client = server.Client()
+
+	// "tree/a" exists in the store
+	entries := []transactEntry{
+		{verb: clusterTransactPut, key: "tree/b", value: []byte("1")},
+		{verb: clusterTransactDeleteTree, key: "tree/"},
+		{verb: clusterTransactPut, key: "tree/c", value: []byte("2")},
+		{verb: clusterTransactPutRev, key: "other", value: []byte("3"), rev: 5},
+		{verb: clusterTransactPut, key: "other", value: []byte("4")},
+	}
+	if ok, err := etcd.Transact(entries); !ok || err != nil {
+		t.Fatalf("Transaction failed: %v, %v", ok, err)
+	}
+
+	if len(txn.Compare) != 1 || string(txn.Compare[0].Key) != "/test/kv/other" || txn.Compare[0].ModRevision != 5 {
+		t.Errorf("Unexpected compares: %+v", txn.Compare)
+	}
+	ops := make(map[string]string)
+	for _, op := range txn.Success {
+		if op.RequestPut != nil {
+			ops[string(op.RequestPut.Key)] = "put " + string(op.RequestPut.Value)
+		} else if op.RequestDeleteRange != nil {
+			ops[string(op.RequestDeleteRange.Key)] = "delete"
+		}
+	}
+	expect := map[string]string{
+		"/test/kv/tree/a": "delete",
+		"/test/kv/tree/b": "delete",
+		"/test/kv/tree/c": "put 2",
+		"/test/kv/other":  "put 4",
+	}
+	if len(txn.Success) != len(expect) || !reflect.DeepEqual(ops, expect) {
+		t.Errorf("Unexpected operations: %v", ops)
+	}
+}
+
+func TestEtcdLock(t *testing.T) {
+	f, restore := newEtcdFake()
+	defer restore()
+
+	l1, _ := etcd.NewLock("lock/policy", time.Millisecond*100)
+	lostCh, err := l1.Lock(nil)
+	if lostCh == nil || err != nil {
+		t.Fatalf("Failed to lock: %v", err)
+	}
+	if kv := f.get("lock/policy"); kv == nil || string(kv.value) != etcd.clusterIP || kv.lease == 0 {
+		t.Errorf("Lock key is not attached to a lease: %+v", kv)
+	}
+
+	// The lock is held by others until the wait time
+	l2, _ := etcd.NewLock("lock/policy", time.Millisecond*100)
+	if ch, err := l2.Lock(nil); ch != nil || err != nil {
+		t.Errorf("Lock should not be acquired: %v", err)
+	}
+	stopCh := make(chan struct{})
+	close(stopCh)
+	l3, _ := etcd.NewLock("lock/policy", 0)
+	if ch, err := l3.Lock(stopCh); ch != nil || err != nil {
+		t.Errorf("Lock should be stopped: %v", err)
+	}
+
+	if err := l1.Unlock(); err != nil {
+		t.Errorf("Failed to unlock: %v", err)
+	}
+	if kv := f.get("lock/policy"); kv != nil {
+		t.Errorf("Lock key should be removed with its lease: %+v", kv)
+	}
+	if err := l1.Unlock(); err == nil {
+		t.Errorf("Unlock should fail if the lock is not held")
+	}
+	select {
+	case <-lostCh:
+		t.Errorf("Lock should not be reported lost after unlock")
+	default:
+	}
+
+	if ch, err := l2.Lock(nil); ch == nil || err != nil {
+		t.Errorf("Lock should be acquired after it's released: %v", err)
+	}
+	l2.Unlock()
+}
+
+func TestEtcdLeaseLost(t *testing.T) {
+	f, restore := newEtcdFake()
+	defer restore()
+
+	// Lease expired on the server
+	lease, _ := etcd.grantLease(1)
+	stopCh := make(chan struct{})
+	lostCh := make(chan struct{})
+	go etcd.keepLeaseAlive(lease, 1, stopCh, lostCh)
+	time.Sleep(time.Millisecond * 500)
+	f.expireLease(lease)
+	select {
+	case <-lostCh:
+	case <-time.After(time.Second * 2):
+		t.Errorf("Expired lease is not reported lost")
+	}
+	close(stopCh)
+
+	// Lease cannot be renewed in its ttl
+	lease, _ = etcd.grantLease(1)
+	stopCh = make(chan struct{})
+	lostCh = make(chan struct{})
+	go etcd.keepLeaseAlive(lease, 1, stopCh, lostCh)
+	time.Sleep(time.Millisecond * 500)
+	select {
+	case <-lostCh:
+		t.Errorf("Lease renewed in time should not be lost")
+	default:
+	}
+	f.server.Close()
+	select {
+	case <-lostCh:
+	case <-time.After(time.Second * 3):
+		t.Errorf("Lease not renewed in its ttl is not reported lost")
+	}
+	close(stopCh)
+}
+
+func TestEtcdSession(t *testing.T) {
+	f, restore := newEtcdFake()
+	defer restore()
+
+	s1, err := etcd.NewSession("ctrl1", time.Second*10)
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	s2, _ := etcd.NewSession("ctrl2", time.Second*10)
+
+	if err := s1.Associate("object/hold"); err != nil {
+		t.Errorf("Failed to associate: %v", err)
+	}
+	if err := s2.Associate("object/hold"); err == nil {
+		t.Errorf("Key held by another session should not be associated")
+	}
+	if err := s2.Disassociate("object/hold"); err == nil {
+		t.Errorf("Key held by another session should not be disassociated")
+	}
+	if kv := f.get("object/hold"); kv == nil || kv.lease != s1.(*etcdSession).lease {
+		t.Errorf("Key should be held by the first session: %+v", kv)
+	}
+
+	if err := s1.Disassociate("object/hold"); err != nil {
+		t.Errorf("Failed to disassociate: %v", err)
+	}
+	if err := s2.Associate("object/hold"); err != nil {
+		t.Errorf("Released key should be associated: %v", err)
+	}
+
+	// Key is released when the session expires
+	f.expireLease(s2.(*etcdSession).lease)
+	if kv := f.get("object/hold"); kv != nil {
+		t.Errorf("Key should be released with the session: %+v", kv)
+	}
+	if err := s1.Associate("object/hold"); err != nil {
+		t.Errorf("Key of the expired session should be associated: %v", err)
+	}
+}
+
+func TestEtcdPutRev(t *testing.T) {
+	_, restore := newEtcdFake()
+	defer restore()
+
+	if err := etcd.PutIfNotExist("object/a", []byte("1")); err != nil {
+		t.Fatalf("Failed to put: %v", err)
+	}
+	if err := etcd.PutIfNotExist("object/a", []byte("2")); err != errPutCAS {
+		t.Errorf("Existing key should not be put: %v", err)
+	}
+
+	value, rev, err := etcd.GetRev("object/a")
+	if err != nil || string(value) != "1" || rev == 0 {
+		t.Fatalf("Unexpected key: %s %d %v", string(value), rev, err)
+	}
+	if err := etcd.PutRev("object/a", []byte("3"), rev); err != nil {
+		t.Errorf("Failed to put with the current revision: %v", err)
+	}
+	// The revision is stale after the put
+	if err := etcd.PutRev("object/a", []byte("4"), rev); err != errPutCAS {
+		t.Errorf("Put with the stale revision should fail: %v", err)
+	}
+	if value, _ := etcd.Get("object/a"); string(value) != "3" {
+		t.Errorf("Unexpected value: %s", string(value))
+	}
+	if err := etcd.PutRev("object/b", []byte("1"), rev); err != errPutCAS {
+		t.Errorf("Put of missing key with a revision should fail: %v", err)
+	}
+}
+
+func TestEtcdWatchReconnect(t *testing.T) {
+	f, restore := newEtcdFake()
+	defer restore()
+
+	savedFail, savedRecover := watcherFailFunc, watcherRecoverFunc
+	defer func() {
+		etcd.StopAllWatchers()
+		keyWatcherMutex.Lock()
+		delete(keyWatchers, "object/watch")
+		keyWatcherMutex.Unlock()
+		watcherFailFunc, watcherRecoverFunc = savedFail, savedRecover
+	}()
+
+	type update struct {
+		nType ClusterNotifyType
+		value string
+	}
+	updateCh := make(chan update, 8)
+	failCh := make(chan bool, 8)
+	recoverCh := make(chan bool, 8)
+	watcherFailFunc = func() bool {
+		failCh <- true
+		return true
+	}
+	watcherRecoverFunc = func() { recoverCh <- true }
+
+	expect := func(nType ClusterNotifyType, value string) {
+		select {
+		case u := <-updateCh:
+			if u.nType != nType || u.value != value {
+				t.Errorf("Unexpected update: %+v, expect %v %s", u, nType, value)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("No update of %s", value)
+		}
+	}
+
+	etcd.Put("object/watch", []byte("1"))
+	f.mutex.Lock()
+	f.watchFail = 1
+	f.mutex.Unlock()
+	etcd.RegisterKeyWatcher("object/watch", func(nType ClusterNotifyType, key string, value []byte, idx uint64) {
+		updateCh <- update{nType: nType, value: string(value)}
+	})
+
+	// The first fetch is reported, then the watch fails and reconnects
+	expect(ClusterNotifyAdd, "1")
+	select {
+	case <-failCh:
+	case <-time.After(time.Second * 2):
+		t.Errorf("Watch failure is not reported")
+	}
+	select {
+	case <-recoverCh:
+	case <-time.After(etcdRetryInterval + time.Second*2):
+		t.Errorf("Watch recovery is not reported")
+	}
+
+	// Changes are seen after reconnect
+	etcd.Put("object/watch", []byte("2"))
+	expect(ClusterNotifyModify, "2")
+	etcd.Delete("object/watch")
+	expect(ClusterNotifyDelete, "")
+
+	// No more update after the watcher is stopped
+	etcd.StopAllWatchers()
+	time.Sleep(time.Millisecond * 200)
+	etcd.Put("object/watch", []byte("3"))
+	select {
+	case u := <-updateCh:
+		t.Errorf("Unexpected update after stop: %+v", u)
+	case <-time.After(time.Millisecond * 500):
+	}
+}
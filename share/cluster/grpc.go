@@ -18,6 +18,7 @@ import (
 	"google.golang.org/grpc/credentials"
 
 	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/otel"
 	"github.com/neuvector/neuvector/share/utils"
 )
 
@@ -36,7 +37,7 @@ func middlefunc(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo
 			}
 		}
 	*/
-	return handler(ctx, req)
+	return otel.UnaryServerInterceptor(ctx, req, info, handler)
 }
 
 // --
@@ -95,6 +96,39 @@ func NewGRPCServerTCP(endpoint string) (*GRPCServer, error) {
 	return &s, nil
 }
 
+// Server for external clients. Only server certificate is verified, clients are authenticated by the services.
+func NewGRPCServerTLS(endpoint, certFile, keyFile string) (*GRPCServer, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		Certificates:             []tls.Certificate{cert},
+		MinVersion:               tls.VersionTLS11,
+		PreferServerCipherSuites: true,
+		CipherSuites:             utils.GetSupportedTLSCipherSuites(),
+	}
+	creds := credentials.NewTLS(config)
+
+	opts := []grpc.ServerOption{
+		grpc.Creds(creds),
+		grpc.MaxMsgSize(GRPCMaxMsgSize),
+	}
+
+	listen, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	s := GRPCServer{
+		stopped: true,
+		listen:  listen,
+		server:  grpc.NewServer(opts...),
+	}
+	return &s, nil
+}
+
 func NewGRPCServerUnix(socket string) (*GRPCServer, error) {
 	opts := []grpc.ServerOption{
 		grpc.RPCCompressor(grpc.NewGZIPCompressor()),
@@ -250,6 +284,8 @@ func newGRPCClientTCP(ctx context.Context, key, endpoint string, cb GRPCCallback
 		}
 	}
 
+	opts = append(opts, grpc.WithUnaryInterceptor(otel.UnaryClientInterceptor))
+
 	conn, err := grpc.DialContext(ctx, endpoint, opts...)
 	if err != nil {
 		return nil, err
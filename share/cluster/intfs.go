@@ -381,6 +381,9 @@ var ErrEmptyStore error = errors.New("Empty store")
 
 var KVValueSizeMax = 512 * 1024
 
+// A transaction with more entries is applied in multiple kv transactions, so it's not atomic any more
+const KVTransactEntriesMax = 64
+
 type LockInterface interface {
 	Lock(stopCh <-chan struct{}) (<-chan struct{}, error)
 	Unlock() error
@@ -822,12 +825,12 @@ func (t *ClusterTransact) Apply() (bool, error) {
 		return true, nil
 	}
 
-	if len(t.entries) <= 64 {
+	if len(t.entries) <= KVTransactEntriesMax {
 		return apply(t.entries)
 	} else {
 		var errFinal error
-		for i := 0; i < len(t.entries); i += 64 {
-			entries := t.entries[i:min(i+64, len(t.entries))]
+		for i := 0; i < len(t.entries); i += KVTransactEntriesMax {
+			entries := t.entries[i:min(i+KVTransactEntriesMax, len(t.entries))]
 			if _, err := apply(entries); err != nil {
 				log.WithFields(log.Fields{"i": i, "len": len(t.entries), "error": err}).Error("Failed to write txn keys")
 				// There is no better way to handle one transaction error when there are >64 entries.
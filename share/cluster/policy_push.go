@@ -0,0 +1,47 @@
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// Batches smaller than this are not worth compressing
+const policyPushCompressMin = 1024
+
+func EncodePolicyPushEntries(entries []*share.CLUSPolicyPushEntry) (share.PolicyPushCompression, []byte, error) {
+	data, err := proto.Marshal(&share.CLUSPolicyPushEntryArray{Entries: entries})
+	if err != nil {
+		return share.PolicyPushCompression_CompressNone, nil, err
+	}
+	if len(data) < policyPushCompressMin {
+		return share.PolicyPushCompression_CompressNone, data, nil
+	}
+	return share.PolicyPushCompression_CompressGzip, utils.GzipBytes(data), nil
+}
+
+func DecodePolicyPushEntries(batch *share.CLUSPolicyPushBatch) ([]*share.CLUSPolicyPushEntry, error) {
+	data := batch.Entries
+	switch batch.Compression {
+	case share.PolicyPushCompression_CompressNone:
+	case share.PolicyPushCompression_CompressGzip:
+		if data = utils.GunzipBytes(batch.Entries); data == nil {
+			return nil, fmt.Errorf("Invalid gzip data")
+		}
+	default:
+		// zstd is reserved until the library is available in all components
+		return nil, fmt.Errorf("Unsupported compression: %s", batch.Compression)
+	}
+
+	var arr share.CLUSPolicyPushEntryArray
+	if err := proto.Unmarshal(data, &arr); err != nil {
+		return nil, err
+	}
+	if len(arr.Entries) != int(batch.Count) {
+		return nil, fmt.Errorf("Entry count mismatch: expected=%d received=%d", batch.Count, len(arr.Entries))
+	}
+	return arr.Entries, nil
+}
@@ -0,0 +1,53 @@
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/neuvector/neuvector/share"
+)
+
+func TestPolicyPushEncoding(t *testing.T) {
+	for _, count := range []int{1, 100} {
+		entries := make([]*share.CLUSPolicyPushEntry, count)
+		for i := 0; i < count; i++ {
+			entries[i] = &share.CLUSPolicyPushEntry{
+				Key:   fmt.Sprintf("node/h1/profiles/process/group-%d", i),
+				Value: []byte(fmt.Sprintf("{\"group\": \"group-%d\"}", i)),
+			}
+		}
+		entries[0].Value, entries[0].Delete = nil, true
+
+		compress, data, err := EncodePolicyPushEntries(entries)
+		if err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+		if count == 1 && compress != share.PolicyPushCompression_CompressNone {
+			t.Errorf("Small batch should not be compressed: %s", compress)
+		} else if count == 100 && compress != share.PolicyPushCompression_CompressGzip {
+			t.Errorf("Large batch should be compressed: %s", compress)
+		}
+
+		batch := &share.CLUSPolicyPushBatch{Compression: compress, Count: uint32(count), Entries: data}
+		decoded, err := DecodePolicyPushEntries(batch)
+		if err != nil {
+			t.Fatalf("Failed to decode: %v", err)
+		}
+		for i, e := range decoded {
+			if e.Key != entries[i].Key || !bytes.Equal(e.Value, entries[i].Value) || e.Delete != entries[i].Delete {
+				t.Errorf("Unexpected entry %d: %+v", i, e)
+			}
+		}
+
+		batch.Count++
+		if _, err := DecodePolicyPushEntries(batch); err == nil {
+			t.Errorf("Count mismatch should fail")
+		}
+	}
+
+	batch := &share.CLUSPolicyPushBatch{Compression: share.PolicyPushCompression_CompressZstd}
+	if _, err := DecodePolicyPushEntries(batch); err == nil {
+		t.Errorf("Unsupported compression should fail")
+	}
+}
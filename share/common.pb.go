@@ -10,6 +10,7 @@ It is generated from these files:
 	enforcer_service.proto
 	scan.proto
 	scanner_service.proto
+	v1_api.proto
 
 It has these top-level messages:
 	RPCVoid
@@ -85,6 +86,17 @@ It has these top-level messages:
 	CLUSWorkloadIntercept
 	CLUSMeter
 	CLUSMeterArray
+	CLUSThreatSignaturePattern
+	CLUSThreatSignatureEntry
+	CLUSThreatSignatureArray
+	CLUSFileBaseline
+	CLUSFileBaselineArray
+	CLUSFileBaselineReset
+	CLUSForensicBundle
+	CLUSPolicyPushEntry
+	CLUSPolicyPushEntryArray
+	CLUSPolicyPushBatch
+	CLUSPolicyPushAck
 	ScanVulnerability
 	ScanLayerResult
 	ScanModule
@@ -104,6 +116,15 @@ It has these top-level messages:
 	SigstoreVerifier
 	SigstoreKeypairOptions
 	SigstoreKeylessOptions
+	APIScanFilter
+	APIVulnerability
+	APIWorkloadScanResult
+	APIPolicyRule
+	APIPolicyRuleOp
+	APIPolicyRuleBatch
+	APIPolicyRuleBatchResult
+	APIConversationFilter
+	APIConversation
 */
 package share
 
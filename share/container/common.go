@@ -37,6 +37,10 @@ const (
 	RancherKeyStackServiceName string = "io.rancher.stack_service.name"
 )
 
+const (
+	PodmanSystemdUnitKey string = "PODMAN_SYSTEMD_UNIT"
+)
+
 const (
 	RancherOverlayNetworkName string = "rancher"
 )
@@ -80,6 +84,19 @@ const (
 	KubeRancherIngressNamespace string = "ingress-nginx"
 )
 
+// The alloc_id label is always set by the nomad docker driver, the others are set by its extra_labels option
+const (
+	NomadAllocID          string = "com.hashicorp.nomad.alloc_id"
+	NomadJobName          string = "com.hashicorp.nomad.job_name"
+	NomadTaskGroupName    string = "com.hashicorp.nomad.task_group_name"
+	NomadTaskName         string = "com.hashicorp.nomad.task_name"
+	NomadNamespace        string = "com.hashicorp.nomad.namespace"
+	NomadEnvJobName       string = "NOMAD_JOB_NAME"
+	NomadEnvGroupName     string = "NOMAD_GROUP_NAME"
+	NomadEnvNamespace     string = "NOMAD_NAMESPACE"
+	NomadNamespaceDefault string = "default"
+)
+
 const (
 	AliyunSystem string = "aliyun.system"
 	AliyunAddon  string = "aliyun.addon"
@@ -90,6 +107,7 @@ const (
 	ECSTaskDefinition   string = "com.amazonaws.ecs.task-definition-family"
 	ECSContainerName    string = "com.amazonaws.ecs.container-name"
 	ECSCluster          string = "com.amazonaws.ecs.cluster"
+	ECSTaskArn          string = "com.amazonaws.ecs.task-arn"
 )
 
 const (
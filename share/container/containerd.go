@@ -9,11 +9,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/containerd/containerd"
 	apiEvents "github.com/containerd/containerd/api/events"
 	"github.com/containerd/containerd/containers"
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/namespaces"
 	"github.com/containerd/containerd/oci"
 	"github.com/containerd/typeurl"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -31,6 +34,11 @@ import (
 const defaultContainerdSock = "/run/containerd/containerd.sock"
 const defaultContainerdNamespace = "default"
 const k8sContainerdNamespace = "k8s.io"
+const nerdctlNameLabel = "nerdctl/name"
+
+// the containerd embedded in k3s and microk8s
+const k3sContainerdSock = "/run/k3s/containerd/containerd.sock"
+const microk8sContainerdSock = "/var/snap/microk8s/common/run/containerd.sock"
 
 type containerdDriver struct {
 	sys           *system.SystemTools
@@ -46,6 +54,8 @@ type containerdDriver struct {
 	rtProcMap     utils.Set
 	snapshotter   string
 	pidHost		  bool
+	nsMap         map[string]string // container id => containerd namespace
+	nsMutex       sync.RWMutex
 }
 
 // patch for the mismatched grpc versions
@@ -102,6 +112,7 @@ func containerdConnect(endpoint string, sys *system.SystemTools) (Runtime, error
 		sys: sys, client: client, version: &ver, criClient: cri, endpoint: endpoint, endpointHost: sockPath,
 		// Read /host/proc/sys/kernel/hostname doesn't give the correct node hostname. Change UTS namespace to read it
 		sysInfo: sys.GetSystemInfo(), nodeHostname: sys.GetHostname(1), snapshotter: snapshotter, selfID: id,
+		nsMap: make(map[string]string),
 	}
 
 	driver.rtProcMap = utils.NewSet("runc", "containerd", "containerd-shim", "containerd-shim-runc-v1", "containerd-shim-runc-v2")
@@ -190,6 +201,51 @@ func (d *containerdDriver) GetDevice(id string) (*share.CLUSDevice, *ContainerMe
 	return getDevice(id, d, d.sys)
 }
 
+// Besides the k8s.io namespace used by kubernetes, the containers can be created in other namespaces, such as
+// the ones launched by nerdctl or ctr in the "default" namespace on the standalone containerd hosts.
+func (d *containerdDriver) listNamespaces(ctx context.Context) []string {
+	nss, err := d.client.NamespaceService().List(ctx)
+	if err != nil || len(nss) == 0 {
+		log.WithFields(log.Fields{"error": err}).Debug("Failed to list namespaces")
+		return []string{k8sContainerdNamespace}
+	}
+	return nss
+}
+
+func (d *containerdDriver) setContainerNamespace(id, ns string) {
+	d.nsMutex.Lock()
+	defer d.nsMutex.Unlock()
+	if ns == "" {
+		delete(d.nsMap, id)
+	} else {
+		d.nsMap[id] = ns
+	}
+}
+
+// Load the container from its namespace; search all namespaces if the container is not listed before
+func (d *containerdDriver) loadContainer(ctx context.Context, id string) (context.Context, containerd.Container, error) {
+	d.nsMutex.RLock()
+	ns, ok := d.nsMap[id]
+	d.nsMutex.RUnlock()
+	if ok {
+		nsCtx := namespaces.WithNamespace(ctx, ns)
+		if c, err := d.client.LoadContainer(nsCtx, id); err == nil {
+			return nsCtx, c, nil
+		}
+	}
+
+	var err error
+	for _, ns = range d.listNamespaces(ctx) {
+		nsCtx := namespaces.WithNamespace(ctx, ns)
+		var c containerd.Container
+		if c, err = d.client.LoadContainer(nsCtx, id); err == nil {
+			d.setContainerNamespace(id, ns)
+			return nsCtx, c, nil
+		}
+	}
+	return ctx, nil, err
+}
+
 // When a container task is killed, 'task' can still be retrieved; but when it is deleted, task will be nil
 func (d *containerdDriver) getSpecs(ctx context.Context, c containerd.Container) (*containers.Container, *oci.Spec, int, *containerd.Status, int, error) {
 	info, err := c.Info(ctx)
@@ -210,23 +266,26 @@ func (d *containerdDriver) getSpecs(ctx context.Context, c containerd.Container)
 
 	// if image name is a digest identifier
 	if strings.HasPrefix(info.Image, "sha256:") {
-		if imageName := d.reverseImageNameFromDigestName(info.Image); imageName != "" {
+		if imageName := d.reverseImageNameFromDigestName(ctx, info.Image); imageName != "" {
 			info.Image = imageName
 		}
 	}
 
-	if meta, pid, attempt, err := d.GetContainerCriSupplement(c.ID()); err == nil {
-		// log.WithFields(log.Fields{"meta": meta}).Info("CRI")
-		state := containerd.Stopped
-		if meta.Running {
-			state = containerd.Running
-		}
-		status := &containerd.Status{
-			Status:     state,
-			ExitStatus: uint32(meta.ExitCode),
-			ExitTime:   meta.FinishedAt,
+	// only the containers in the k8s.io namespace are managed by cri
+	if ns, _ := namespaces.Namespace(ctx); ns == k8sContainerdNamespace {
+		if meta, pid, attempt, err := d.GetContainerCriSupplement(c.ID()); err == nil {
+			// log.WithFields(log.Fields{"meta": meta}).Info("CRI")
+			state := containerd.Stopped
+			if meta.Running {
+				state = containerd.Running
+			}
+			status := &containerd.Status{
+				Status:     state,
+				ExitStatus: uint32(meta.ExitCode),
+				ExitTime:   meta.FinishedAt,
+			}
+			return &info, spec, pid, status, int(attempt), nil
 		}
-		return &info, spec, pid, status, int(attempt), nil
 	}
 
 	// 2nd try on the ctr task
@@ -257,7 +316,7 @@ func (d *containerdDriver) getSpecs(ctx context.Context, c containerd.Container)
 	return &info, spec, rootpid, status, attempts, nil
 }
 
-func (d *containerdDriver) getMeta(info *containers.Container, spec *oci.Spec, pid int, attempt int) (*ContainerMeta, string, time.Time) {
+func (d *containerdDriver) getMeta(ctx context.Context, info *containers.Container, spec *oci.Spec, pid int, attempt int) (*ContainerMeta, string, time.Time) {
 	var author string
 	var imgCreateAt time.Time
 
@@ -269,7 +328,14 @@ func (d *containerdDriver) getMeta(info *containers.Container, spec *oci.Spec, p
 		Hostname: spec.Hostname,
 		Pid:      pid,
 	}
-	if image, err := d.GetImage(info.Image); err == nil {
+	var image *ImageMeta
+	var err error
+	if ns, _ := namespaces.Namespace(ctx); ns == k8sContainerdNamespace && d.criClient != nil {
+		image, err = d.GetImage(info.Image)
+	} else {
+		image, err = d.getImageMeta(ctx, info.Image)
+	}
+	if err == nil {
 		author = image.Author
 		imgCreateAt = image.CreatedAt
 		for k, v := range image.Labels {
@@ -317,6 +383,8 @@ func (d *containerdDriver) getMeta(info *containers.Container, spec *oci.Spec, p
 					info.Labels["io.kubernetes.pod.uid"] + "_" +
 					fmt.Sprintf("%d", attempt)
 			}
+		} else if name, ok := info.Labels[nerdctlNameLabel]; ok && name != "" {
+			meta.Name = name
 		} else {
 			log.Debug("no k8s namespace label")
 		}
@@ -356,27 +424,38 @@ func (d *containerdDriver) isPrivileged(spec *oci.Spec, id string, bSandBox bool
 
 func (d *containerdDriver) ListContainers(runningOnly bool) ([]*ContainerMeta, error) {
 	ctx, cancel := context.WithCancel(context.Background())
-	containers, err := d.client.Containers(ctx)
 	defer cancel()
-	if err != nil {
-		log.WithFields(log.Fields{"error": err.Error()}).Error("Failed to list containers")
-		return nil, wrapIntoErrorString(err)
-	}
 
-	metas := make([]*ContainerMeta, 0, len(containers))
-	for _, c := range containers {
-		info, spec, pid, status, attempt, err := d.getSpecs(ctx, c)
-		if err != nil {
-			log.WithFields(log.Fields{"id": c.ID(), "error": err.Error()}).Error("Failed to get container info")
+	var listed bool
+	var err error
+	metas := make([]*ContainerMeta, 0)
+	for _, ns := range d.listNamespaces(ctx) {
+		nsCtx := namespaces.WithNamespace(ctx, ns)
+		var containers []containerd.Container
+		if containers, err = d.client.Containers(nsCtx); err != nil {
+			log.WithFields(log.Fields{"namespace": ns, "error": err.Error()}).Error("Failed to list containers")
 			continue
 		}
+		listed = true
+
+		for _, c := range containers {
+			d.setContainerNamespace(c.ID(), ns)
+			info, spec, pid, status, attempt, err := d.getSpecs(nsCtx, c)
+			if err != nil {
+				log.WithFields(log.Fields{"id": c.ID(), "error": err.Error()}).Error("Failed to get container info")
+				continue
+			}
 
-		if runningOnly && (status == nil || status.Status != containerd.Running) {
-			continue
-		}
+			if runningOnly && (status == nil || status.Status != containerd.Running) {
+				continue
+			}
 
-		meta, _, _ := d.getMeta(info, spec, pid, attempt)
-		metas = append(metas, meta)
+			meta, _, _ := d.getMeta(nsCtx, info, spec, pid, attempt)
+			metas = append(metas, meta)
+		}
+	}
+	if !listed && err != nil {
+		return nil, wrapIntoErrorString(err)
 	}
 
 	return metas, nil
@@ -385,7 +464,7 @@ func (d *containerdDriver) ListContainers(runningOnly bool) ([]*ContainerMeta, e
 func (d *containerdDriver) GetContainer(id string) (*ContainerMetaExtra, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	c, err := d.client.LoadContainer(ctx, id)
+	ctx, c, err := d.loadContainer(ctx, id)
 	if err != nil {
 		log.WithFields(log.Fields{"error": err.Error()}).Error("Failed to get container")
 		return nil, wrapIntoErrorString(err)
@@ -402,7 +481,7 @@ func (d *containerdDriver) GetContainer(id string) (*ContainerMetaExtra, error)
 		bSandBox = true
 	}
 
-	cmeta, author, imgCreatedAt := d.getMeta(info, spec, pid, attempt)
+	cmeta, author, imgCreatedAt := d.getMeta(ctx, info, spec, pid, attempt)
 	meta := &ContainerMetaExtra{
 		ContainerMeta: *cmeta,
 		Author:        author,
@@ -454,9 +533,60 @@ func (d *containerdDriver) GetImageHistory(name string) ([]*ImageHistory, error)
 func (d *containerdDriver) GetImage(name string) (*ImageMeta, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	if d.criClient == nil {
+		return d.getImageMeta(namespaces.WithNamespace(ctx, defaultContainerdNamespace), name)
+	}
 	return criGetImageMeta(d.criClient, ctx, name)
 }
 
+// Read the image meta from the image config in the content store of the namespace, for the images not managed by cri
+func (d *containerdDriver) getImageMeta(ctx context.Context, name string) (*ImageMeta, error) {
+	image, err := d.client.GetImage(ctx, name)
+	if err != nil {
+		return nil, wrapIntoErrorString(err)
+	}
+
+	desc, err := image.Config(ctx)
+	if err != nil {
+		return nil, wrapIntoErrorString(err)
+	}
+
+	data, err := content.ReadBlob(ctx, d.client.ContentStore(), desc)
+	if err != nil {
+		return nil, wrapIntoErrorString(err)
+	}
+
+	// use a partial structure of the oci image config
+	var spec struct {
+		Created *time.Time `json:"created,omitempty"`
+		Author  string     `json:"author,omitempty"`
+		Config  struct {
+			Env    []string          `json:"Env,omitempty"`
+			Labels map[string]string `json:"Labels,omitempty"`
+		} `json:"config,omitempty"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+
+	meta := &ImageMeta{
+		ID:     TrimImageID(desc.Digest.String()),
+		Digest: image.Target().Digest.String(),
+		Author: spec.Author,
+		Env:    spec.Config.Env,
+		Labels: spec.Config.Labels,
+	}
+	if meta.Labels == nil {
+		meta.Labels = make(map[string]string)
+	}
+	if spec.Created != nil {
+		meta.CreatedAt = *spec.Created
+	}
+	meta.Size, _ = image.Size(ctx)
+	meta.RepoTags = []string{image.Name()}
+	return meta, nil
+}
+
 func (d *containerdDriver) GetImageFile(id string) (io.ReadCloser, error) {
 	return nil, ErrMethodNotSupported
 }
@@ -466,14 +596,17 @@ func (d *containerdDriver) ListContainerIDs() (utils.Set, utils.Set) {
 	defer cancel()
 
 	ids := utils.NewSet()
-	containers, err := d.client.Containers(ctx)
-	if err != nil {
-		log.WithFields(log.Fields{"error": err.Error()}).Error("Failed to list containers")
-		return ids, nil
-	}
+	for _, ns := range d.listNamespaces(ctx) {
+		containers, err := d.client.Containers(namespaces.WithNamespace(ctx, ns))
+		if err != nil {
+			log.WithFields(log.Fields{"namespace": ns, "error": err.Error()}).Error("Failed to list containers")
+			continue
+		}
 
-	for _, c := range containers {
-		ids.Add(c.ID())
+		for _, c := range containers {
+			d.setContainerNamespace(c.ID(), ns)
+			ids.Add(c.ID())
+		}
 	}
 	return ids, nil
 }
@@ -551,7 +684,8 @@ func (d *containerdDriver) MonitorEvent(cb EventCallback, cpath bool) error {
 					switch event := v.(type) {
 					case *apiEvents.TaskStart:
 						// TaskStart{ContainerID:25d62dcf65...,Pid:10560,}
-						log.WithFields(log.Fields{"event": v}).Debug("start")
+						log.WithFields(log.Fields{"event": v, "namespace": ev.Namespace}).Debug("start")
+						d.setContainerNamespace(event.ContainerID, ev.Namespace)
 						cb(EventContainerStart, event.ContainerID, int(event.Pid))
 					case *apiEvents.TaskExit:
 						// TaskExit{ContainerID:25d62dcf65...,ID:25d62dcf65...,Pid:10560,ExitStatus:0,ExitedAt:2018-11-01 07:40:22.252023597 +0000 UTC,}
@@ -561,6 +695,7 @@ func (d *containerdDriver) MonitorEvent(cb EventCallback, cpath bool) error {
 						// ContainerDelete{ID:25d62dcf65...,}
 						log.WithFields(log.Fields{"event": v}).Debug("delete")
 						cb(EventContainerDelete, event.ID, 0)
+						d.setContainerNamespace(event.ID, "")
 					default:
 						log.WithFields(log.Fields{"event": v}).Debug("Unknown containderd event")
 					}
@@ -604,10 +739,7 @@ func (d *containerdDriver) GetStorageDriver() string {
 	return d.snapshotter
 }
 
-func (d *containerdDriver) reverseImageNameFromDigestName(digestName string) string {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
+func (d *containerdDriver) reverseImageNameFromDigestName(ctx context.Context, digestName string) string {
 	if image, err := d.client.GetImage(ctx, digestName); err == nil {
 		digest := image.Target().Digest.String()
 		if images, err := d.client.ListImages(ctx, ""); err == nil {
@@ -670,7 +802,7 @@ func (d *containerdDriver) decodeExtension_attempt(extData []byte) (int, error)
 
 func (d *containerdDriver) GetContainerCriSupplement(id string) (*ContainerMetaExtra, int, uint32, error) {
 	if d.criClient == nil {
-		return nil, 0, 0, nil
+		return nil, 0, 0, ErrMethodNotSupported
 	}
 
 	var meta *ContainerMetaExtra
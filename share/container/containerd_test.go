@@ -0,0 +1,270 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd"
+	containersapi "github.com/containerd/containerd/api/services/containers/v1"
+	imagesapi "github.com/containerd/containerd/api/services/images/v1"
+	namespacesapi "github.com/containerd/containerd/api/services/namespaces/v1"
+	"github.com/containerd/containerd/api/types"
+	"github.com/containerd/containerd/containers"
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/oci"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// containers and images are kept per containerd namespace
+type mockContainerd struct {
+	containers map[string]map[string]*containersapi.Container
+	images     map[string]map[string]*imagesapi.Image
+	blobs      map[digest.Digest][]byte
+	nsListed   int
+}
+
+type mockContainersClient struct {
+	containersapi.ContainersClient
+	*mockContainerd
+}
+
+func (m mockContainersClient) Get(ctx context.Context, in *containersapi.GetContainerRequest, opts ...grpc.CallOption) (*containersapi.GetContainerResponse, error) {
+	ns, _ := namespaces.Namespace(ctx)
+	if c, ok := m.containers[ns][in.ID]; ok {
+		return &containersapi.GetContainerResponse{Container: *c}, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "container %s not found", in.ID)
+}
+
+type mockNamespacesClient struct {
+	namespacesapi.NamespacesClient
+	*mockContainerd
+}
+
+func (m mockNamespacesClient) List(ctx context.Context, in *namespacesapi.ListNamespacesRequest, opts ...grpc.CallOption) (*namespacesapi.ListNamespacesResponse, error) {
+	m.nsListed++
+	var resp namespacesapi.ListNamespacesResponse
+	for ns := range m.containers {
+		resp.Namespaces = append(resp.Namespaces, namespacesapi.Namespace{Name: ns})
+	}
+	return &resp, nil
+}
+
+type mockImagesClient struct {
+	imagesapi.ImagesClient
+	*mockContainerd
+}
+
+func (m mockImagesClient) Get(ctx context.Context, in *imagesapi.GetImageRequest, opts ...grpc.CallOption) (*imagesapi.GetImageResponse, error) {
+	ns, _ := namespaces.Namespace(ctx)
+	if image, ok := m.images[ns][in.Name]; ok {
+		return &imagesapi.GetImageResponse{Image: image}, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "image %s not found", in.Name)
+}
+
+type mockReaderAt struct {
+	*bytes.Reader
+}
+
+func (r mockReaderAt) Close() error {
+	return nil
+}
+
+type mockContentStore struct {
+	content.Store
+	*mockContainerd
+}
+
+func (m mockContentStore) ReaderAt(ctx context.Context, desc ocispec.Descriptor) (content.ReaderAt, error) {
+	if data, ok := m.blobs[desc.Digest]; ok {
+		return mockReaderAt{bytes.NewReader(data)}, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "content %s not found", desc.Digest)
+}
+
+func (m *mockContainerd) addBlob(t *testing.T, mediaType string, v interface{}) ocispec.Descriptor {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Failed to marshal blob: %v", err)
+	}
+	dgst := digest.FromBytes(data)
+	m.blobs[dgst] = data
+	return ocispec.Descriptor{MediaType: mediaType, Digest: dgst, Size: int64(len(data))}
+}
+
+func newMockContainerdDriver(t *testing.T, m *mockContainerd) *containerdDriver {
+	client, err := containerd.New("", containerd.WithServices(
+		containerd.WithContainerService(mockContainersClient{mockContainerd: m}),
+		containerd.WithImageService(mockImagesClient{mockContainerd: m}),
+		containerd.WithNamespaceService(mockNamespacesClient{mockContainerd: m}),
+		containerd.WithContentStore(mockContentStore{mockContainerd: m}),
+	))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return &containerdDriver{client: client, nsMap: make(map[string]string)}
+}
+
+func newMockContainerd() *mockContainerd {
+	return &mockContainerd{
+		containers: map[string]map[string]*containersapi.Container{
+			k8sContainerdNamespace: {
+				"c1": {ID: "c1", Image: "nginx:1.19"},
+			},
+			defaultContainerdNamespace: {
+				"c2": {ID: "c2", Image: "docker.io/library/redis:6"},
+			},
+		},
+		images: make(map[string]map[string]*imagesapi.Image),
+		blobs:  make(map[digest.Digest][]byte),
+	}
+}
+
+func TestContainerdLoadContainer(t *testing.T) {
+	m := newMockContainerd()
+	d := newMockContainerdDriver(t, m)
+	ctx := context.Background()
+
+	// the namespace is searched and remembered
+	nsCtx, c, err := d.loadContainer(ctx, "c2")
+	if err != nil || c.ID() != "c2" {
+		t.Fatalf("Failed to load container: %v", err)
+	}
+	if ns, _ := namespaces.Namespace(nsCtx); ns != defaultContainerdNamespace {
+		t.Errorf("Unexpected namespace: %v", ns)
+	}
+	if d.nsMap["c2"] != defaultContainerdNamespace {
+		t.Errorf("Namespace is not remembered: %+v", d.nsMap)
+	}
+
+	listed := m.nsListed
+	if _, _, err = d.loadContainer(ctx, "c2"); err != nil {
+		t.Errorf("Failed to load container: %v", err)
+	}
+	if m.nsListed != listed {
+		t.Errorf("Namespaces should not be listed for a known container")
+	}
+
+	// the container is moved, the remembered namespace is stale
+	m.containers[k8sContainerdNamespace]["c2"] = m.containers[defaultContainerdNamespace]["c2"]
+	delete(m.containers[defaultContainerdNamespace], "c2")
+	if nsCtx, _, err = d.loadContainer(ctx, "c2"); err != nil {
+		t.Errorf("Failed to load container: %v", err)
+	} else if ns, _ := namespaces.Namespace(nsCtx); ns != k8sContainerdNamespace || d.nsMap["c2"] != k8sContainerdNamespace {
+		t.Errorf("Unexpected namespace: %v, %+v", ns, d.nsMap)
+	}
+
+	if _, _, err = d.loadContainer(ctx, "c3"); err == nil {
+		t.Errorf("Unknown container should not be loaded")
+	}
+
+	// forget the namespace of a deleted container
+	d.setContainerNamespace("c2", "")
+	if _, ok := d.nsMap["c2"]; ok {
+		t.Errorf("Namespace is not removed: %+v", d.nsMap)
+	}
+}
+
+func TestContainerdImageMeta(t *testing.T) {
+	m := newMockContainerd()
+	d := newMockContainerdDriver(t, m)
+
+	created := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	config := m.addBlob(t, ocispec.MediaTypeImageConfig, map[string]interface{}{
+		"created": created,
+		"author":  "dev@example.com",
+		"config": map[string]interface{}{
+			"Env":    []string{"PATH=/usr/bin"},
+			"Labels": map[string]string{"version": "6"},
+		},
+	})
+	layer := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayerGzip, Digest: digest.FromString("layer"), Size: 1000}
+	manifest := m.addBlob(t, ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		Config: config,
+		Layers: []ocispec.Descriptor{layer},
+	})
+	m.images[defaultContainerdNamespace] = map[string]*imagesapi.Image{
+		"docker.io/library/redis:6": {
+			Name:   "docker.io/library/redis:6",
+			Target: types.Descriptor{MediaType: manifest.MediaType, Digest: manifest.Digest, Size_: manifest.Size},
+		},
+	}
+
+	ctx := namespaces.WithNamespace(context.Background(), defaultContainerdNamespace)
+	meta, err := d.getImageMeta(ctx, "docker.io/library/redis:6")
+	if err != nil {
+		t.Fatalf("Failed to get image meta: %v", err)
+	}
+	if meta.ID != TrimImageID(config.Digest.String()) || meta.Digest != manifest.Digest.String() {
+		t.Errorf("Unexpected image id: %v %v", meta.ID, meta.Digest)
+	}
+	if meta.Author != "dev@example.com" || !meta.CreatedAt.Equal(created) {
+		t.Errorf("Unexpected image author: %v %v", meta.Author, meta.CreatedAt)
+	}
+	if len(meta.Env) != 1 || meta.Labels["version"] != "6" {
+		t.Errorf("Unexpected image config: %+v %+v", meta.Env, meta.Labels)
+	}
+	if meta.Size != manifest.Size+config.Size+layer.Size {
+		t.Errorf("Unexpected image size: %v", meta.Size)
+	}
+	if len(meta.RepoTags) != 1 || meta.RepoTags[0] != "docker.io/library/redis:6" {
+		t.Errorf("Unexpected image tags: %+v", meta.RepoTags)
+	}
+
+	// images are not shared across namespaces
+	ctx = namespaces.WithNamespace(context.Background(), k8sContainerdNamespace)
+	if _, err = d.getImageMeta(ctx, "docker.io/library/redis:6"); err == nil {
+		t.Errorf("Image in other namespace should not be found")
+	}
+
+	// image without config labels
+	config = m.addBlob(t, ocispec.MediaTypeImageConfig, map[string]interface{}{"config": map[string]interface{}{}})
+	manifest = m.addBlob(t, ocispec.MediaTypeImageManifest, ocispec.Manifest{Config: config})
+	m.images[defaultContainerdNamespace]["busybox"] = &imagesapi.Image{
+		Name:   "busybox",
+		Target: types.Descriptor{MediaType: manifest.MediaType, Digest: manifest.Digest, Size_: manifest.Size},
+	}
+	ctx = namespaces.WithNamespace(context.Background(), defaultContainerdNamespace)
+	if meta, err = d.getImageMeta(ctx, "busybox"); err != nil || meta.Labels == nil || !meta.CreatedAt.IsZero() {
+		t.Errorf("Unexpected image meta: %+v %v", meta, err)
+	}
+}
+
+func TestContainerdMetaName(t *testing.T) {
+	d := newMockContainerdDriver(t, newMockContainerd())
+	spec := &oci.Spec{Linux: &specs.Linux{}}
+
+	tests := []struct {
+		ns     string
+		labels map[string]string
+		name   string
+	}{
+		{defaultContainerdNamespace, map[string]string{nerdctlNameLabel: "redis-1"}, "redis-1"},
+		{defaultContainerdNamespace, map[string]string{nerdctlNameLabel: ""}, "c2"},
+		{defaultContainerdNamespace, map[string]string{}, "c2"},
+		{k8sContainerdNamespace, map[string]string{
+			nerdctlNameLabel:              "redis-1",
+			"io.kubernetes.pod.namespace": "default",
+			"io.kubernetes.pod.name":      "redis",
+			"io.kubernetes.pod.uid":       "u1",
+		}, "k8s_POD_redis_default_u1_0"},
+	}
+
+	for _, test := range tests {
+		ctx := namespaces.WithNamespace(context.Background(), test.ns)
+		info := &containers.Container{ID: "c2", Image: "docker.io/library/redis:6", Labels: test.labels}
+		if meta, _, _ := d.getMeta(ctx, info, spec, 100, 0); meta.Name != test.name {
+			t.Errorf("Unexpected name: labels=%+v expect=%v actual=%v", test.labels, test.name, meta.Name)
+		}
+	}
+}
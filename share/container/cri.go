@@ -0,0 +1,515 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	criRT "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/system"
+	"github.com/neuvector/neuvector/share/system/sysinfo"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// The generic CRI driver only talks the CRI gRPC API. It is used when the native API of the runtime is
+// not available or not compatible. The CRI API in use has no event stream, so the container events are
+// generated by polling the container states.
+
+const criEventPollInterval = time.Second * 5
+const criEventMaxFailures = 12
+
+// the CRI sockets that are tried when no native runtime can be connected
+var criFallbackSocks = []string{
+	defaultContainerdSock,
+	defaultCriOSock,
+	k3sContainerdSock,
+	microk8sContainerdSock,
+	"/var/run/cri-dockerd.sock",
+}
+
+type criDriver struct {
+	sys           *system.SystemTools
+	sysInfo       *sysinfo.SysInfo
+	endpoint      string
+	endpointHost  string
+	nodeHostname  string
+	selfID        string
+	criClient     *grpc.ClientConn
+	version       *criRT.VersionResponse
+	pidHost       bool
+	rtProcMap     utils.Set
+	cancelMonitor context.CancelFunc
+}
+
+type criGenericInfo struct {
+	Info struct {
+		SandboxID  string `json:"sandboxID"`
+		Pid        int    `json:"pid"`
+		Image      string `json:"image"`
+		Privileged bool   `json:"privileged"` // cri-o
+		Config     struct {
+			Linux struct {
+				SecurityContext struct {
+					Privileged bool `json:"privileged"` // containerd
+				} `json:"security_context"`
+			} `json:"linux"`
+		} `json:"config"`
+	} `json:"info"`
+}
+
+func criConnect(endpoint string, sys *system.SystemTools) (Runtime, error) {
+	log.WithFields(log.Fields{"endpoint": endpoint}).Debug("Connecting to cri")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cri, ver, err := newCriClient(strings.TrimPrefix(endpoint, "unix://"), ctx)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Fail to create cri client")
+		return nil, err
+	}
+
+	sockPath := endpoint
+	id, _, _ := sys.GetSelfContainerID() // not relaible, could be sandboxID
+	id, _ = criGetSelfID(cri, ctx, id)
+	if path, err := criGetContainerSocketPath(cri, ctx, id, endpoint); err == nil {
+		sockPath = path
+		log.WithFields(log.Fields{"selfID": id, "sockPath": sockPath}).Info()
+	}
+
+	log.WithFields(log.Fields{"endpoint": endpoint, "sockPath": sockPath, "version": ver}).Info("cri connected")
+	driver := &criDriver{
+		sys: sys, version: ver, criClient: cri, endpoint: endpoint, endpointHost: sockPath,
+		// Read /host/proc/sys/kernel/hostname doesn't give the correct node hostname. Change UTS namespace to read it
+		sysInfo: sys.GetSystemInfo(), nodeHostname: sys.GetHostname(1), selfID: id,
+	}
+
+	driver.rtProcMap = utils.NewSet("runc", "crun", "conmon", "crio", "cri-dockerd",
+		"containerd", "containerd-shim", "containerd-shim-runc-v1", "containerd-shim-runc-v2")
+
+	name, _ := os.Readlink("/proc/1/exe")
+	if name == "/usr/local/bin/monitor" || strings.HasPrefix(name, "/usr/bin/python") { // when pid mode != host, 'pythohn' is for allinone
+		driver.pidHost = false
+	} else {
+		driver.pidHost = true
+	}
+	return driver, nil
+}
+
+func (d *criDriver) reConnect() error {
+	if !d.pidHost {
+		return errors.New("Not pidHost")
+	}
+	// the original socket has been recreated and its mounted path was also lost.
+	endpoint := d.endpoint
+	if d.endpointHost != "" { // use the host
+		endpoint = filepath.Join("/proc/1/root", strings.TrimPrefix(d.endpointHost, "unix://"))
+	}
+
+	log.WithFields(log.Fields{"endpoint": endpoint}).Info("Reconnecting ...")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cri, ver, err := newCriClient(endpoint, ctx)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Fail to create cri client")
+		return err
+	}
+
+	log.WithFields(log.Fields{"endpoint": endpoint, "version": ver}).Info("cri connected")
+
+	// update records
+	d.criClient = cri
+	d.version = ver
+	return nil
+}
+
+func (d *criDriver) String() string {
+	return RuntimeCri
+}
+
+func (d *criDriver) GetHost() (*share.CLUSHost, error) {
+	var host share.CLUSHost
+
+	host.Runtime = d.String()
+	if d.version != nil {
+		host.RuntimeVer = fmt.Sprintf("%s %s", d.version.RuntimeName, d.version.RuntimeVersion)
+		host.RuntimeAPIVer = d.version.RuntimeApiVersion
+	}
+
+	if d.sysInfo != nil {
+		host.Name = d.nodeHostname
+		host.ID = fmt.Sprintf("%s:%s", d.nodeHostname, d.sysInfo.Product.UUID)
+		host.OS = d.sysInfo.OS.Name
+		host.Kernel = d.sysInfo.Kernel.Release
+		host.CPUs = int64(d.sysInfo.CPU.Threads)
+		host.Memory = int64(d.sysInfo.Memory.Size) * 1024 * 1024
+	}
+
+	return &host, nil
+}
+
+func (d *criDriver) GetSelfID() string {
+	return d.selfID
+}
+
+func (d *criDriver) GetDevice(id string) (*share.CLUSDevice, *ContainerMetaExtra, error) {
+	return getDevice(id, d, d.sys)
+}
+
+func (d *criDriver) getInfo(infoMap map[string]string) *criGenericInfo {
+	var info criGenericInfo
+	jsonInfo := buildJsonFromMap(infoMap) // from map[string]string
+	if err := json.Unmarshal([]byte(jsonInfo), &info); err != nil {
+		// the verbose info is runtime specific and optional
+		log.WithFields(log.Fields{"error": err}).Debug()
+	}
+	return &info
+}
+
+func (d *criDriver) ListContainers(runningOnly bool) ([]*ContainerMeta, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ids, stops, err := d.listContainerStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metas := make([]*ContainerMeta, 0, ids.Cardinality())
+	for id := range ids.Iter() {
+		if runningOnly && stops.Contains(id) {
+			continue
+		}
+		m, err := d.getContainer(id.(string), ctx)
+		if err != nil {
+			log.WithFields(log.Fields{"id": id, "error": err}).Error("Fail: container")
+			continue
+		}
+		metas = append(metas, &m.ContainerMeta)
+	}
+	return metas, nil
+}
+
+func (d *criDriver) GetContainer(id string) (*ContainerMetaExtra, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	return d.getContainer(id, ctx)
+}
+
+func (d *criDriver) getContainer(id string, ctx context.Context) (*ContainerMetaExtra, error) {
+	var meta *ContainerMetaExtra
+	pod, err := criPodSandboxStatus(d.criClient, ctx, id)
+	if err == nil && pod != nil && pod.Status != nil {
+		// a POD
+		info := d.getInfo(pod.Info)
+		meta = &ContainerMetaExtra{
+			ContainerMeta: ContainerMeta{
+				ID:  id,
+				Pid: info.Info.Pid,
+				Name: "k8s_POD_" + pod.Status.Labels["io.kubernetes.pod.name"] + "_" +
+					pod.Status.Labels["io.kubernetes.pod.namespace"] + "_" +
+					pod.Status.Labels["io.kubernetes.pod.uid"] + "_" +
+					fmt.Sprintf("%d", pod.Status.Metadata.Attempt),
+				Image:   info.Info.Image,
+				Labels:  pod.Status.Labels,
+				Envs:    make([]string, 0),
+				Sandbox: id,
+			},
+			Privileged: info.Info.Privileged || info.Info.Config.Linux.SecurityContext.Privileged,
+			Running:    pod.Status.State == criRT.PodSandboxState_SANDBOX_READY,
+			Networks:   utils.NewSet(),
+		}
+		if pod.Status.CreatedAt > 0 {
+			meta.CreatedAt = time.Unix(0, pod.Status.CreatedAt)
+			meta.StartedAt = meta.CreatedAt
+		}
+	} else {
+		// an APP container
+		cs, err := criContainerStatus(d.criClient, ctx, id)
+		if err != nil || cs == nil || cs.Status == nil {
+			log.WithFields(log.Fields{"id": id, "error": err}).Error("Fail to get container")
+			if err == nil {
+				err = ErrNotFound
+			}
+			return nil, err
+		}
+
+		info := d.getInfo(cs.Info)
+		meta = &ContainerMetaExtra{
+			ContainerMeta: ContainerMeta{
+				ID:  id,
+				Pid: info.Info.Pid,
+				Name: "k8s_" + cs.Status.Labels["io.kubernetes.container.name"] + "_" +
+					cs.Status.Labels["io.kubernetes.pod.name"] + "_" +
+					cs.Status.Labels["io.kubernetes.pod.namespace"] + "_" +
+					cs.Status.Labels["io.kubernetes.pod.uid"] + "_" +
+					fmt.Sprintf("%d", cs.Status.Metadata.Attempt),
+				Labels:  cs.Status.Labels,
+				Envs:    make([]string, 0),
+				Sandbox: info.Info.SandboxID,
+				isChild: true,
+			},
+			ImageDigest: imageRef2Digest(cs.Status.ImageRef),
+			Privileged:  info.Info.Privileged || info.Info.Config.Linux.SecurityContext.Privileged,
+			ExitCode:    int(cs.Status.ExitCode),
+			Running:     cs.Status.State == criRT.ContainerState_CONTAINER_RUNNING || cs.Status.State == criRT.ContainerState_CONTAINER_CREATED,
+			Networks:    utils.NewSet(),
+			LogPath:     cs.Status.LogPath,
+		}
+		if meta.Labels == nil {
+			meta.Labels = make(map[string]string)
+		}
+		if cs.Status.Image != nil {
+			meta.Image = cs.Status.Image.Image
+		} else {
+			meta.Image = cs.Status.ImageRef
+		}
+		if cs.Status.CreatedAt > 0 {
+			meta.CreatedAt = time.Unix(0, cs.Status.CreatedAt)
+		}
+		if cs.Status.StartedAt > 0 {
+			meta.StartedAt = time.Unix(0, cs.Status.StartedAt)
+		} else {
+			meta.StartedAt = meta.CreatedAt
+		}
+		if cs.Status.FinishedAt > 0 {
+			meta.FinishedAt = time.Unix(0, cs.Status.FinishedAt)
+		}
+
+		// the network/pid namespaces are from the POD
+		pod, _ = criPodSandboxStatus(d.criClient, ctx, meta.Sandbox)
+	}
+
+	if meta.Image != "" {
+		if image, _ := d.GetImage(meta.Image); image != nil {
+			meta.ImageID = image.ID
+			meta.Author = image.Author
+			meta.ImgCreateAt = image.CreatedAt
+			for k, v := range image.Labels {
+				// Not to overwrite container labels when merging
+				if _, ok := meta.Labels[k]; !ok {
+					meta.Labels[k] = v
+				}
+			}
+		}
+	}
+
+	if pod == nil || pod.Status == nil || pod.Status.Linux == nil || pod.Status.Linux.Namespaces == nil || pod.Status.Linux.Namespaces.Options == nil {
+		log.WithFields(log.Fields{"id": id}).Debug("Fail to get sandbox linux namespaces")
+	} else {
+		opts := pod.Status.Linux.Namespaces.Options
+		if opts.Network == criRT.NamespaceMode_NODE {
+			meta.NetMode = "host"
+		} else {
+			meta.NetMode = "default"
+		}
+		if opts.Pid == criRT.NamespaceMode_NODE {
+			meta.PidMode = "host"
+		}
+	}
+
+	// avoid false-positive event which is different from the process monitor
+	if d.pidHost && meta.Pid > 0 {
+		if _, err := os.Stat(fmt.Sprintf("/proc/%d", meta.Pid)); err != nil {
+			log.WithFields(log.Fields{"id": id, "pid": meta.Pid}).Debug("dead rootPid")
+			meta.Running = false
+		}
+	}
+	return meta, nil
+}
+
+func (d *criDriver) GetImageHistory(name string) ([]*ImageHistory, error) {
+	return nil, ErrMethodNotSupported
+}
+
+func (d *criDriver) GetImage(name string) (*ImageMeta, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	return criGetImageMeta(d.criClient, ctx, name)
+}
+
+func (d *criDriver) GetImageFile(id string) (io.ReadCloser, error) {
+	return nil, ErrMethodNotSupported
+}
+
+// Return all containers and pods, and the ones that are not running
+func (d *criDriver) listContainerStates(ctx context.Context) (utils.Set, utils.Set, error) {
+	ids := utils.NewSet()
+	stops := utils.NewSet()
+
+	running, err := criListContainers(d.criClient, ctx, true)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Fail to list containers")
+		return ids, stops, err
+	}
+	for _, c := range running.Containers {
+		ids.Add(c.Id)
+	}
+	if exited, err := criListContainers(d.criClient, ctx, false); err == nil {
+		for _, c := range exited.Containers {
+			ids.Add(c.Id)
+			stops.Add(c.Id)
+		}
+	}
+	if ready, err := criListPodSandboxes(d.criClient, ctx, true); err == nil {
+		for _, pod := range ready.Items {
+			ids.Add(pod.Id)
+		}
+	}
+	if notReady, err := criListPodSandboxes(d.criClient, ctx, false); err == nil {
+		for _, pod := range notReady.Items {
+			ids.Add(pod.Id)
+			stops.Add(pod.Id)
+		}
+	}
+	return ids, stops, nil
+}
+
+func (d *criDriver) ListContainerIDs() (utils.Set, utils.Set) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ids, stops, _ := d.listContainerStates(ctx)
+	return ids, stops
+}
+
+func (d *criDriver) GetNetworkEndpoint(netName, container, epName string) (*NetworkEndpoint, error) {
+	return nil, ErrMethodNotSupported
+}
+
+func (d *criDriver) ListNetworks() (map[string]*Network, error) {
+	return make(map[string]*Network), nil
+}
+
+func (d *criDriver) GetService(id string) (*Service, error) {
+	return nil, ErrMethodNotSupported
+}
+
+func (d *criDriver) ListServices() ([]*Service, error) {
+	return make([]*Service, 0), nil
+}
+
+func (d *criDriver) IsDaemonProcess(proc string, cmds []string) bool {
+	return false
+}
+
+func (d *criDriver) IsRuntimeProcess(proc string, cmds []string) bool {
+	return d.rtProcMap.Contains(proc)
+}
+
+func (d *criDriver) GetParent(info *ContainerMetaExtra, pidMap map[int]string) (bool, string) {
+	if info.Sandbox == "" || info.ID == info.Sandbox {
+		return false, ""
+	}
+	return true, info.Sandbox
+}
+
+func (d *criDriver) StopMonitorEvent() {
+	if d.cancelMonitor != nil {
+		d.cancelMonitor()
+	}
+}
+
+type criStateEvent struct {
+	event Event
+	id    string
+}
+
+// Compare the container states of two polls; the state is true if the container is running
+func criStateEvents(old, cur map[string]bool) []criStateEvent {
+	events := make([]criStateEvent, 0)
+	for id, running := range cur {
+		if wasRunning, ok := old[id]; running && (!ok || !wasRunning) {
+			events = append(events, criStateEvent{event: EventContainerStart, id: id})
+		} else if !running && ok && wasRunning {
+			events = append(events, criStateEvent{event: EventContainerStop, id: id})
+		}
+	}
+	for id, wasRunning := range old {
+		if _, ok := cur[id]; !ok {
+			if wasRunning {
+				events = append(events, criStateEvent{event: EventContainerStop, id: id})
+			}
+			events = append(events, criStateEvent{event: EventContainerDelete, id: id})
+		}
+	}
+	return events
+}
+
+func (d *criDriver) pollContainerStates(ctx context.Context) (map[string]bool, error) {
+	ids, stops, err := d.listContainerStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]bool, ids.Cardinality())
+	for id := range ids.Iter() {
+		states[id.(string)] = !stops.Contains(id)
+	}
+	return states, nil
+}
+
+func (d *criDriver) MonitorEvent(cb EventCallback, cpath bool) error {
+	if cpath {
+		return ErrMethodNotSupported
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancelMonitor = cancel
+
+	states, _ := d.pollContainerStates(ctx)
+	ticker := time.NewTicker(criEventPollInterval)
+	defer ticker.Stop()
+
+	var failures int
+	for {
+		select {
+		case <-ticker.C:
+			cur, err := d.pollContainerStates(ctx)
+			if err != nil {
+				if err := d.reConnect(); err != nil {
+					log.WithFields(log.Fields{"err": err}).Error()
+				}
+				if failures++; failures >= criEventMaxFailures { // restart enforcer
+					cb(EventSocketError, "", 0)
+					failures = 0
+				}
+				continue
+			}
+			failures = 0
+
+			if states != nil {
+				for _, ev := range criStateEvents(states, cur) {
+					log.WithFields(log.Fields{"event": ev.event, "id": ev.id}).Debug()
+					cb(ev.event, ev.id, 0)
+				}
+			}
+			states = cur
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (d *criDriver) GetProxy() (string, string, string) {
+	return "", "", ""
+}
+
+func (d *criDriver) GetDefaultRegistries() []string {
+	return nil
+}
+
+func (d *criDriver) GetStorageDriver() string {
+	return ""
+}
@@ -0,0 +1,36 @@
+package container
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestCriStateEvents(t *testing.T) {
+	old := map[string]bool{"running": true, "stopping": true, "removed": true, "exited": false, "removedExited": false}
+	cur := map[string]bool{"running": true, "stopping": false, "exited": false, "started": true, "created": false, "restarted": true}
+	old["restarted"] = false
+
+	events := criStateEvents(old, cur)
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].id == events[j].id {
+			return events[i].event < events[j].event
+		}
+		return events[i].id < events[j].id
+	})
+	expect := []criStateEvent{
+		{event: EventContainerDelete, id: "removed"},
+		{event: EventContainerStop, id: "removed"},
+		{event: EventContainerDelete, id: "removedExited"},
+		{event: EventContainerStart, id: "restarted"},
+		{event: EventContainerStart, id: "started"},
+		{event: EventContainerStop, id: "stopping"},
+	}
+	if !reflect.DeepEqual(events, expect) {
+		t.Errorf("Unexpected events:\n  expect=%+v\n  actual=%+v", expect, events)
+	}
+
+	if events = criStateEvents(cur, cur); len(events) != 0 {
+		t.Errorf("Unexpected events without change: %+v", events)
+	}
+}
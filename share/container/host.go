@@ -0,0 +1,157 @@
+package container
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/system"
+	"github.com/neuvector/neuvector/share/system/sysinfo"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// The host driver is used when the enforcer runs on a virtual machine or a bare-metal host without any
+// container runtime, such as a systemd service. There is no container, only the host and its processes,
+// files and connections are protected.
+
+const machineIDFile = "/etc/machine-id"
+
+type hostDriver struct {
+	sys      *system.SystemTools
+	sysInfo  *sysinfo.SysInfo
+	hostname string
+	stopCh   chan struct{}
+}
+
+func ConnectHost(sys *system.SystemTools) (Runtime, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{"hostname": hostname}).Info("No container runtime, protect the host")
+	return &hostDriver{sys: sys, sysInfo: sys.GetSystemInfo(), hostname: hostname, stopCh: make(chan struct{})}, nil
+}
+
+func (d *hostDriver) String() string {
+	return RuntimeHost
+}
+
+// The product uuid is not available on some virtual machines, the machine-id of systemd is used instead.
+func (d *hostDriver) GetHost() (*share.CLUSHost, error) {
+	var host share.CLUSHost
+
+	host.Runtime = d.String()
+	host.Name = d.hostname
+
+	var uuid string
+	if d.sysInfo != nil {
+		uuid = d.sysInfo.Product.UUID
+		host.OS = d.sysInfo.OS.Name
+		host.Kernel = d.sysInfo.Kernel.Release
+		host.CPUs = int64(d.sysInfo.CPU.Threads)
+		host.Memory = int64(d.sysInfo.Memory.Size) * 1024 * 1024
+	}
+	if uuid == "" {
+		if data, err := ioutil.ReadFile(machineIDFile); err == nil {
+			uuid = strings.TrimSpace(string(data))
+		}
+	}
+	host.ID = fmt.Sprintf("%s:%s", d.hostname, uuid)
+
+	return &host, nil
+}
+
+func (d *hostDriver) GetSelfID() string {
+	return ""
+}
+
+func (d *hostDriver) GetDevice(id string) (*share.CLUSDevice, *ContainerMetaExtra, error) {
+	return nil, nil, ErrNotFound
+}
+
+func (d *hostDriver) GetContainer(id string) (*ContainerMetaExtra, error) {
+	return nil, ErrNotFound
+}
+
+func (d *hostDriver) ListContainers(runningOnly bool) ([]*ContainerMeta, error) {
+	return make([]*ContainerMeta, 0), nil
+}
+
+func (d *hostDriver) ListContainerIDs() (utils.Set, utils.Set) {
+	return utils.NewSet(), nil
+}
+
+func (d *hostDriver) GetImageHistory(name string) ([]*ImageHistory, error) {
+	return nil, ErrMethodNotSupported
+}
+
+func (d *hostDriver) GetImage(name string) (*ImageMeta, error) {
+	return nil, ErrMethodNotSupported
+}
+
+func (d *hostDriver) GetImageFile(id string) (io.ReadCloser, error) {
+	return nil, ErrMethodNotSupported
+}
+
+func (d *hostDriver) GetNetworkEndpoint(netName, container, epName string) (*NetworkEndpoint, error) {
+	return nil, ErrMethodNotSupported
+}
+
+func (d *hostDriver) ListNetworks() (map[string]*Network, error) {
+	return make(map[string]*Network), nil
+}
+
+func (d *hostDriver) GetService(id string) (*Service, error) {
+	return nil, ErrMethodNotSupported
+}
+
+func (d *hostDriver) ListServices() ([]*Service, error) {
+	return make([]*Service, 0), nil
+}
+
+func (d *hostDriver) GetParent(info *ContainerMetaExtra, pidMap map[int]string) (bool, string) {
+	return false, ""
+}
+
+func (d *hostDriver) IsDaemonProcess(proc string, cmds []string) bool {
+	return false
+}
+
+func (d *hostDriver) IsRuntimeProcess(proc string, cmds []string) bool {
+	return false
+}
+
+// No container event, wait until the monitor is stopped
+func (d *hostDriver) MonitorEvent(cb EventCallback, cpath bool) error {
+	if cpath {
+		return ErrMethodNotSupported
+	}
+	<-d.stopCh
+	return nil
+}
+
+func (d *hostDriver) StopMonitorEvent() {
+	select {
+	case <-d.stopCh:
+	default:
+		close(d.stopCh)
+	}
+}
+
+func (d *hostDriver) GetProxy() (string, string, string) {
+	return "", "", ""
+}
+
+func (d *hostDriver) GetDefaultRegistries() []string {
+	return nil
+}
+
+func (d *hostDriver) GetStorageDriver() string {
+	return ""
+}
@@ -0,0 +1,608 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/system"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// The podman driver uses the libpod REST API of the podman system service. The libpod API is versioned
+// by the podman release; v3.0.0 is the oldest one that has the fields in use.
+
+const defaultPodmanSock = "/run/podman/podman.sock"
+const podmanAPIPrefix = "http://d/v3.0.0/libpod"
+
+type podmanDriver struct {
+	sys           *system.SystemTools
+	endpoint      string
+	endpointHost  string
+	selfID        string
+	client        *http.Client
+	evClient      *http.Client
+	info          *podmanInfo
+	rtProcMap     utils.Set
+	pidHost       bool
+	cancelMonitor context.CancelFunc
+}
+
+type podmanInfo struct {
+	Host struct {
+		Arch         string `json:"arch"`
+		CPUs         int64  `json:"cpus"`
+		Hostname     string `json:"hostname"`
+		Kernel       string `json:"kernel"`
+		MemTotal     int64  `json:"memTotal"`
+		Distribution struct {
+			Distribution string `json:"distribution"`
+			Version      string `json:"version"`
+		} `json:"distribution"`
+	} `json:"host"`
+	Store struct {
+		GraphDriverName string `json:"graphDriverName"`
+	} `json:"store"`
+	Version struct {
+		APIVersion string `json:"APIVersion"`
+		Version    string `json:"Version"`
+	} `json:"version"`
+}
+
+type podmanContainerSummary struct {
+	ID     string            `json:"Id"`
+	Names  []string          `json:"Names"`
+	Image  string            `json:"Image"`
+	State  string            `json:"State"`
+	Labels map[string]string `json:"Labels"`
+}
+
+type podmanPortBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string `json:"HostPort"`
+}
+
+type podmanContainer struct {
+	ID      string    `json:"Id"`
+	Name    string    `json:"Name"`
+	Created time.Time `json:"Created"`
+	State   struct {
+		Status     string    `json:"Status"`
+		Running    bool      `json:"Running"`
+		Pid        int       `json:"Pid"`
+		ExitCode   int       `json:"ExitCode"`
+		StartedAt  time.Time `json:"StartedAt"`
+		FinishedAt time.Time `json:"FinishedAt"`
+	} `json:"State"`
+	Image     string `json:"Image"`
+	ImageName string `json:"ImageName"`
+	Pod       string `json:"Pod"`
+	IsInfra   bool   `json:"IsInfra"`
+	Config    struct {
+		Hostname string            `json:"Hostname"`
+		Env      []string          `json:"Env"`
+		Labels   map[string]string `json:"Labels"`
+	} `json:"Config"`
+	HostConfig struct {
+		NetworkMode string `json:"NetworkMode"`
+		PidMode     string `json:"PidMode"`
+		Privileged  bool   `json:"Privileged"`
+		Memory      int64  `json:"Memory"`
+		CpusetCpus  string `json:"CpusetCpus"`
+		LogConfig   struct {
+			Path string `json:"Path"`
+		} `json:"LogConfig"`
+	} `json:"HostConfig"`
+	NetworkSettings struct {
+		IPAddress   string                         `json:"IPAddress"`
+		IPPrefixLen int                            `json:"IPPrefixLen"`
+		Ports       map[string][]podmanPortBinding `json:"Ports"`
+		Networks    map[string]struct {
+			NetworkID string `json:"NetworkID"`
+		} `json:"Networks"`
+	} `json:"NetworkSettings"`
+	Mounts []struct {
+		Source      string `json:"Source"`
+		Destination string `json:"Destination"`
+	} `json:"Mounts"`
+}
+
+type podmanPod struct {
+	ID               string `json:"Id"`
+	InfraContainerID string `json:"InfraContainerID"`
+}
+
+type podmanImage struct {
+	ID          string            `json:"Id"`
+	Digest      string            `json:"Digest"`
+	RepoTags    []string          `json:"RepoTags"`
+	RepoDigests []string          `json:"RepoDigests"`
+	Created     time.Time         `json:"Created"`
+	Size        int64             `json:"Size"`
+	Author      string            `json:"Author"`
+	Labels      map[string]string `json:"Labels"`
+	Config      struct {
+		Env []string `json:"Env"`
+	} `json:"Config"`
+	RootFS struct {
+		Layers []string `json:"Layers"`
+	} `json:"RootFS"`
+	History []struct {
+		CreatedBy string `json:"created_by"`
+	} `json:"History"`
+}
+
+type podmanEvent struct {
+	Type   string `json:"Type"`
+	Action string `json:"Action"`
+	Status string `json:"status"`
+	ID     string `json:"id"`
+	Actor  struct {
+		ID         string            `json:"ID"`
+		Attributes map[string]string `json:"Attributes"`
+	} `json:"Actor"`
+}
+
+func newPodmanClient(sock string, timeout time.Duration) *http.Client {
+	sock = strings.TrimPrefix(sock, "unix://")
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", sock)
+			},
+		},
+	}
+}
+
+func podmanGet(client *http.Client, path string, v interface{}) error {
+	resp, err := client.Get(podmanAPIPrefix + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	} else if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Unexpected status: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func podmanConnect(endpoint string, sys *system.SystemTools) (Runtime, error) {
+	log.WithFields(log.Fields{"endpoint": endpoint}).Debug("Connecting to podman")
+
+	client := newPodmanClient(endpoint, clientConnectTimeout)
+	var info podmanInfo
+	if err := podmanGet(client, "/info", &info); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to get info")
+		return nil, err
+	}
+
+	driver := &podmanDriver{
+		sys: sys, endpoint: endpoint, endpointHost: endpoint, client: client, info: &info,
+		evClient: newPodmanClient(endpoint, 0),
+	}
+
+	driver.selfID, _, _ = sys.GetSelfContainerID()
+	if c, err := driver.inspectContainer(driver.selfID); err == nil {
+		sock := strings.TrimPrefix(endpoint, "unix://")
+		for _, m := range c.Mounts {
+			if m.Destination == sock {
+				driver.endpointHost = m.Source
+				break
+			}
+		}
+	}
+
+	log.WithFields(log.Fields{"endpoint": endpoint, "sockPath": driver.endpointHost, "version": info.Version.Version}).Info("podman connected")
+
+	driver.rtProcMap = utils.NewSet("podman", "conmon", "runc", "crun")
+	name, _ := os.Readlink("/proc/1/exe")
+	if name == "/usr/local/bin/monitor" || strings.HasPrefix(name, "/usr/bin/python") { // when pid mode != host, 'pythohn' is for allinone
+		driver.pidHost = false
+	} else {
+		driver.pidHost = true
+	}
+	return driver, nil
+}
+
+func (d *podmanDriver) reConnect() error {
+	if !d.pidHost {
+		return errors.New("Not pidHost")
+	}
+	// the original socket has been recreated and its mounted path was also lost.
+	endpoint := d.endpoint
+	if d.endpointHost != "" { // use the host
+		endpoint = filepath.Join("/proc/1/root", strings.TrimPrefix(d.endpointHost, "unix://"))
+	}
+
+	log.WithFields(log.Fields{"endpoint": endpoint}).Info("Reconnecting ...")
+
+	client := newPodmanClient(endpoint, clientConnectTimeout)
+	var info podmanInfo
+	if err := podmanGet(client, "/info", &info); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to get info")
+		return err
+	}
+
+	log.WithFields(log.Fields{"endpoint": endpoint, "version": info.Version.Version}).Info("podman connected")
+
+	// update records
+	d.client = client
+	d.evClient = newPodmanClient(endpoint, 0)
+	d.info = &info
+	return nil
+}
+
+func (d *podmanDriver) String() string {
+	return RuntimePodman
+}
+
+func (d *podmanDriver) GetHost() (*share.CLUSHost, error) {
+	var host share.CLUSHost
+
+	host.Runtime = d.String()
+	if d.info != nil {
+		host.RuntimeVer = d.info.Version.Version
+		host.RuntimeAPIVer = d.info.Version.APIVersion
+		host.Name = d.info.Host.Hostname
+		host.ID = fmt.Sprintf("%s:%s", d.info.Host.Hostname, d.info.Host.Distribution.Distribution)
+		host.OS = strings.TrimSpace(d.info.Host.Distribution.Distribution + " " + d.info.Host.Distribution.Version)
+		host.Kernel = d.info.Host.Kernel
+		host.CPUs = d.info.Host.CPUs
+		host.Memory = d.info.Host.MemTotal
+		host.StorageDriver = d.info.Store.GraphDriverName
+	}
+
+	return &host, nil
+}
+
+func (d *podmanDriver) GetSelfID() string {
+	return d.selfID
+}
+
+func (d *podmanDriver) GetDevice(id string) (*share.CLUSDevice, *ContainerMetaExtra, error) {
+	return getDevice(id, d, d.sys)
+}
+
+func (d *podmanDriver) listContainers(all bool) ([]podmanContainerSummary, error) {
+	var list []podmanContainerSummary
+	if err := podmanGet(d.client, fmt.Sprintf("/containers/json?all=%v", all), &list); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to list containers")
+		return nil, err
+	}
+	return list, nil
+}
+
+func (d *podmanDriver) ListContainerIDs() (utils.Set, utils.Set) {
+	ids := utils.NewSet()
+	stops := utils.NewSet()
+	if list, err := d.listContainers(true); err == nil {
+		for _, c := range list {
+			ids.Add(c.ID)
+			if c.State != "running" {
+				stops.Add(c.ID)
+			}
+		}
+	}
+	return ids, stops
+}
+
+func (d *podmanDriver) ListContainers(runningOnly bool) ([]*ContainerMeta, error) {
+	list, err := d.listContainers(!runningOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	metas := make([]*ContainerMeta, len(list))
+	for i, c := range list {
+		metas[i] = &ContainerMeta{
+			ID:     c.ID,
+			Image:  c.Image,
+			Labels: c.Labels,
+		}
+		if len(c.Names) > 0 {
+			metas[i].Name = trimContainerName(c.Names[0])
+		}
+	}
+	return metas, nil
+}
+
+func (d *podmanDriver) inspectContainer(id string) (*podmanContainer, error) {
+	if id == "" {
+		return nil, ErrNotFound
+	}
+	var c podmanContainer
+	if err := podmanGet(d.client, "/containers/"+url.PathEscape(id)+"/json", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (d *podmanDriver) GetContainer(id string) (*ContainerMetaExtra, error) {
+	info, err := d.inspectContainer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &ContainerMetaExtra{
+		ContainerMeta: ContainerMeta{
+			ID:       info.ID,
+			Name:     trimContainerName(info.Name),
+			Image:    info.ImageName,
+			Labels:   info.Config.Labels,
+			Hostname: info.Config.Hostname,
+			Pid:      info.State.Pid,
+			Envs:     info.Config.Env,
+			PidMode:  info.HostConfig.PidMode,
+			NetMode:  info.HostConfig.NetworkMode,
+		},
+		ImageID:     TrimImageID(info.Image),
+		Privileged:  info.HostConfig.Privileged,
+		CreatedAt:   info.Created,
+		StartedAt:   info.State.StartedAt,
+		FinishedAt:  info.State.FinishedAt,
+		Running:     info.State.Running,
+		ExitCode:    info.State.ExitCode,
+		MemoryLimit: info.HostConfig.Memory,
+		CPUs:        info.HostConfig.CpusetCpus,
+		IPAddress:   info.NetworkSettings.IPAddress,
+		IPPrefixLen: info.NetworkSettings.IPPrefixLen,
+		MappedPorts: make(map[share.CLUSProtoPort]*share.CLUSMappedPort),
+		Networks:    utils.NewSet(),
+		LogPath:     info.HostConfig.LogConfig.Path,
+	}
+	if meta.Labels == nil {
+		meta.Labels = make(map[string]string)
+	}
+
+	// The containers in a pod share the network namespace of the infra container
+	if info.Pod != "" {
+		if info.IsInfra {
+			meta.Sandbox = info.ID
+		} else {
+			var pod podmanPod
+			if err := podmanGet(d.client, "/pods/"+url.PathEscape(info.Pod)+"/json", &pod); err == nil && pod.InfraContainerID != "" {
+				meta.Sandbox = pod.InfraContainerID
+				meta.NetMode = "container:" + pod.InfraContainerID
+			}
+		}
+	}
+	meta.isChild, _ = d.GetParent(meta, nil)
+
+	if image, err := d.GetImage(info.Image); err == nil {
+		meta.Author = image.Author
+		meta.ImgCreateAt = image.CreatedAt
+		meta.ImageDigest = image.Digest
+	}
+
+	for pstr, bind := range info.NetworkSettings.Ports {
+		if len(bind) > 0 {
+			ipproto, port := parsePortString(pstr)
+			hport, _ := strconv.Atoi(bind[0].HostPort)
+			cp := share.CLUSProtoPort{
+				Port:    uint16(port),
+				IPProto: uint8(ipproto),
+			}
+			meta.MappedPorts[cp] = &share.CLUSMappedPort{
+				CLUSProtoPort: cp,
+				HostIP:        net.ParseIP(bind[0].HostIP),
+				HostPort:      uint16(hport),
+			}
+		}
+	}
+
+	for name, n := range info.NetworkSettings.Networks {
+		if n.NetworkID != "" {
+			meta.Networks.Add(n.NetworkID)
+		} else {
+			meta.Networks.Add(name)
+		}
+	}
+
+	return meta, nil
+}
+
+func (d *podmanDriver) inspectImage(name string) (*podmanImage, error) {
+	var image podmanImage
+	if err := podmanGet(d.client, "/images/"+url.PathEscape(name)+"/json", &image); err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (d *podmanDriver) GetImageHistory(name string) ([]*ImageHistory, error) {
+	image, err := d.inspectImage(name)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*ImageHistory, len(image.History))
+	for i, h := range image.History {
+		list[i] = &ImageHistory{Cmd: h.CreatedBy}
+	}
+	return list, nil
+}
+
+func (d *podmanDriver) GetImage(name string) (*ImageMeta, error) {
+	image, err := d.inspectImage(name)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &ImageMeta{
+		ID:        TrimImageID(image.ID),
+		Digest:    image.Digest,
+		CreatedAt: image.Created,
+		Size:      image.Size,
+		Env:       image.Config.Env,
+		Author:    image.Author,
+		Labels:    image.Labels,
+		RepoTags:  image.RepoTags,
+	}
+
+	// our order is [0] is the top layer, so we need reverse the layers
+	l := len(image.RootFS.Layers)
+	meta.Layers = make([]string, l)
+	for i, layer := range image.RootFS.Layers {
+		meta.Layers[l-i-1] = layer
+	}
+	return meta, nil
+}
+
+func (d *podmanDriver) GetImageFile(id string) (io.ReadCloser, error) {
+	resp, err := d.evClient.Get(podmanAPIPrefix + "/images/" + url.PathEscape(id) + "/get")
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Failed to get image")
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("Unexpected status: %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func (d *podmanDriver) GetNetworkEndpoint(netName, container, epName string) (*NetworkEndpoint, error) {
+	return nil, ErrMethodNotSupported
+}
+
+func (d *podmanDriver) ListNetworks() (map[string]*Network, error) {
+	return make(map[string]*Network), nil
+}
+
+func (d *podmanDriver) GetService(id string) (*Service, error) {
+	return nil, ErrMethodNotSupported
+}
+
+func (d *podmanDriver) ListServices() ([]*Service, error) {
+	return make([]*Service, 0), nil
+}
+
+func (d *podmanDriver) IsDaemonProcess(proc string, cmds []string) bool {
+	// podman system service
+	return proc == "podman" && len(cmds) > 2 && cmds[1] == "system" && cmds[2] == "service"
+}
+
+func (d *podmanDriver) IsRuntimeProcess(proc string, cmds []string) bool {
+	return d.rtProcMap.Contains(proc)
+}
+
+func (d *podmanDriver) GetParent(meta *ContainerMetaExtra, pidMap map[int]string) (bool, string) {
+	if strings.HasPrefix(meta.NetMode, "container:") {
+		return true, meta.NetMode[10:]
+	}
+	return false, ""
+}
+
+func (d *podmanDriver) StopMonitorEvent() {
+	if d.cancelMonitor != nil {
+		d.cancelMonitor()
+	}
+}
+
+func podmanEvent2Callback(e *podmanEvent) (Event, string) {
+	if e.Type != "container" {
+		return "", ""
+	}
+
+	id := e.Actor.ID
+	if id == "" {
+		id = e.ID
+	}
+	action := e.Action
+	if action == "" {
+		action = e.Status
+	}
+
+	switch action {
+	case "start", "restart", "unpause":
+		return EventContainerStart, id
+	case "died", "die", "stop", "pause":
+		return EventContainerStop, id
+	case "remove":
+		return EventContainerDelete, id
+	}
+	return "", ""
+}
+
+func (d *podmanDriver) MonitorEvent(cb EventCallback, cpath bool) error {
+	if cpath {
+		return ErrMethodNotSupported
+	}
+
+	var sendErr int
+	filters := url.QueryEscape(`{"type":["container"]}`)
+	for {
+		ctx, cancel := context.WithCancel(context.Background())
+		d.cancelMonitor = cancel
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, podmanAPIPrefix+"/events?stream=true&filters="+filters, nil)
+		resp, err := d.evClient.Do(req)
+		if err == nil {
+			sendErr = 0
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				var e podmanEvent
+				if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+					log.WithFields(log.Fields{"error": err}).Error("Unmarshal podman event error")
+					continue
+				}
+				if ev, id := podmanEvent2Callback(&e); ev != "" {
+					log.WithFields(log.Fields{"event": e}).Debug()
+					cb(ev, id, 0)
+				}
+			}
+			resp.Body.Close()
+			err = scanner.Err()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		log.WithFields(log.Fields{"error": err}).Error("Podman event monitor error")
+		time.Sleep(5 * time.Second)
+		if err := d.reConnect(); err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Failed to reconnect to podman")
+			if sendErr++; sendErr >= 12 {
+				// Notify caller if continously failing for 1 minute
+				cb(EventSocketError, "", 0)
+				sendErr = 0
+			}
+		}
+	}
+}
+
+func (d *podmanDriver) GetProxy() (string, string, string) {
+	return "", "", ""
+}
+
+func (d *podmanDriver) GetDefaultRegistries() []string {
+	return nil
+}
+
+func (d *podmanDriver) GetStorageDriver() string {
+	if d.info != nil {
+		return d.info.Store.GraphDriverName
+	}
+	return ""
+}
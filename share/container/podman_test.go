@@ -0,0 +1,31 @@
+package container
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPodmanEvent(t *testing.T) {
+	tests := []struct {
+		line  string
+		event Event
+		id    string
+	}{
+		{`{"Type":"container","Action":"start","Actor":{"ID":"c1","Attributes":{"name":"web"}}}`, EventContainerStart, "c1"},
+		{`{"Type":"container","Action":"died","Actor":{"ID":"c1"}}`, EventContainerStop, "c1"},
+		{`{"Type":"container","Action":"remove","Actor":{"ID":"c1"}}`, EventContainerDelete, "c1"},
+		{`{"Type":"container","status":"start","id":"c2"}`, EventContainerStart, "c2"},
+		{`{"Type":"container","Action":"exec","Actor":{"ID":"c1"}}`, "", ""},
+		{`{"Type":"image","Action":"remove","Actor":{"ID":"i1"}}`, "", ""},
+	}
+
+	for _, test := range tests {
+		var e podmanEvent
+		if err := json.Unmarshal([]byte(test.line), &e); err != nil {
+			t.Fatalf("Failed to parse event: %s", test.line)
+		}
+		if ev, id := podmanEvent2Callback(&e); ev != test.event || id != test.id {
+			t.Errorf("Unexpected event: %s, expect=%v:%v actual=%v:%v", test.line, test.event, test.id, ev, id)
+		}
+	}
+}
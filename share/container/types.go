@@ -56,6 +56,9 @@ const (
 	RuntimeDocker     = "docker"
 	RuntimeContainerd = "containerd"
 	RuntimeCriO       = "cri-o"
+	RuntimeCri        = "cri"
+	RuntimePodman     = "podman"
+	RuntimeHost       = "host" // no container runtime
 )
 
 const (
@@ -184,7 +187,13 @@ func Connect(endpoint string, sys *system.SystemTools) (Runtime, error) {
 			return nil, err
 		}
 
-		rt, err := dockerConnect(endpoint, sys)
+		// podman also serves the docker API, so it must be identified by the libpod API first
+		rt, err := podmanConnect(endpoint, sys)
+		if err == nil {
+			return rt, nil
+		}
+
+		rt, err = dockerConnect(endpoint, sys)
 		if err == nil {
 			return rt, nil
 		}
@@ -198,27 +207,52 @@ func Connect(endpoint string, sys *system.SystemTools) (Runtime, error) {
 		if err == nil {
 			return rt, nil
 		}
+
+		// the native API is not available or not compatible
+		rt, err = criConnect(endpoint, sys)
+		if err == nil {
+			return rt, nil
+		}
 	} else {
-		if isUnixSockFile(defaultDockerSocket) {
-			rt, err := dockerConnect(defaultDockerSocket, sys)
+		if isUnixSockFile(defaultPodmanSock) && (!isUnixSockFile(defaultDockerSocket) || isSameFile(defaultDockerSocket, defaultPodmanSock)) {
+			rt, err := podmanConnect(defaultPodmanSock, sys)
 			if err == nil {
 				return rt, nil
 			}
 		}
 
-		if isUnixSockFile(defaultContainerdSock) {
-			rt, err := containerdConnect(defaultContainerdSock, sys)
+		if isUnixSockFile(defaultDockerSocket) {
+			rt, err := dockerConnect(defaultDockerSocket, sys)
 			if err == nil {
 				return rt, nil
 			}
 		}
 
+		for _, sock := range []string{defaultContainerdSock, k3sContainerdSock, microk8sContainerdSock} {
+			if isUnixSockFile(sock) {
+				rt, err := containerdConnect(sock, sys)
+				if err == nil {
+					return rt, nil
+				}
+			}
+		}
+
 		if isUnixSockFile(defaultCriOSock) {
 			rt, err := crioConnect(defaultCriOSock, sys)
 			if err == nil {
 				return rt, nil
 			}
 		}
+
+		// the native API is not available or not compatible
+		for _, sock := range criFallbackSocks {
+			if isUnixSockFile(sock) {
+				rt, err := criConnect(sock, sys)
+				if err == nil {
+					return rt, nil
+				}
+			}
+		}
 	}
 
 	return nil, ErrUnknownRuntime
@@ -239,6 +273,19 @@ func isUnixSockFile(filename string) bool {
 	return (info.Mode() & os.ModeSocket) != 0
 }
 
+// podman-docker links the docker socket to the podman socket
+func isSameFile(file1, file2 string) bool {
+	info1, err := os.Stat(strings.TrimPrefix(file1, "unix://"))
+	if err != nil {
+		return false
+	}
+	info2, err := os.Stat(strings.TrimPrefix(file2, "unix://"))
+	if err != nil {
+		return false
+	}
+	return os.SameFile(info1, info2)
+}
+
 //// construct a json string from map[]
 func buildJsonFromMap(info map[string]string) string {
 	// sort all keys
@@ -356,13 +356,16 @@ func (m *CLUSAdmissionResponse) GetReason() string {
 }
 
 type CLUSProcProfileReq struct {
-	GroupName string `protobuf:"bytes,1,opt,name=GroupName" json:"GroupName,omitempty"`
-	Name      string `protobuf:"bytes,2,opt,name=Name" json:"Name,omitempty"`
-	Path      string `protobuf:"bytes,3,opt,name=Path" json:"Path,omitempty"`
-	User      string `protobuf:"bytes,4,opt,name=User" json:"User,omitempty"`
-	Uid       int32  `protobuf:"varint,5,opt,name=Uid" json:"Uid,omitempty"`
-	Hash      []byte `protobuf:"bytes,6,opt,name=Hash,proto3" json:"Hash,omitempty"`
-	Action    string `protobuf:"bytes,7,opt,name=Action" json:"Action,omitempty"`
+	GroupName string   `protobuf:"bytes,1,opt,name=GroupName" json:"GroupName,omitempty"`
+	Name      string   `protobuf:"bytes,2,opt,name=Name" json:"Name,omitempty"`
+	Path      string   `protobuf:"bytes,3,opt,name=Path" json:"Path,omitempty"`
+	User      string   `protobuf:"bytes,4,opt,name=User" json:"User,omitempty"`
+	Uid       int32    `protobuf:"varint,5,opt,name=Uid" json:"Uid,omitempty"`
+	Hash      []byte   `protobuf:"bytes,6,opt,name=Hash,proto3" json:"Hash,omitempty"`
+	Action    string   `protobuf:"bytes,7,opt,name=Action" json:"Action,omitempty"`
+	Args      string   `protobuf:"bytes,8,opt,name=Args" json:"Args,omitempty"`
+	ArgsMatch string   `protobuf:"bytes,9,opt,name=ArgsMatch" json:"ArgsMatch,omitempty"`
+	Syscalls  []string `protobuf:"bytes,10,rep,name=Syscalls" json:"Syscalls,omitempty"`
 }
 
 func (m *CLUSProcProfileReq) Reset()                    { *m = CLUSProcProfileReq{} }
@@ -419,6 +422,27 @@ func (m *CLUSProcProfileReq) GetAction() string {
 	return ""
 }
 
+func (m *CLUSProcProfileReq) GetArgs() string {
+	if m != nil {
+		return m.Args
+	}
+	return ""
+}
+
+func (m *CLUSProcProfileReq) GetArgsMatch() string {
+	if m != nil {
+		return m.ArgsMatch
+	}
+	return ""
+}
+
+func (m *CLUSProcProfileReq) GetSyscalls() []string {
+	if m != nil {
+		return m.Syscalls
+	}
+	return nil
+}
+
 type CLUSProcProfileArray struct {
 	Processes []*CLUSProcProfileReq `protobuf:"bytes,1,rep,name=Processes" json:"Processes,omitempty"`
 }
@@ -517,6 +541,7 @@ type CLUSConnection struct {
 	TmpOpen      bool   `protobuf:"varint,31,opt,name=TmpOpen" json:"TmpOpen,omitempty"`
 	UwlIp        bool   `protobuf:"varint,32,opt,name=UwlIp" json:"UwlIp,omitempty"`
 	FQDN         string `protobuf:"bytes,33,opt,name=FQDN" json:"FQDN,omitempty"`
+	MeshID       string `protobuf:"bytes,34,opt,name=MeshID" json:"MeshID,omitempty"`
 }
 
 func (m *CLUSConnection) Reset()                    { *m = CLUSConnection{} }
@@ -755,6 +780,13 @@ func (m *CLUSConnection) GetFQDN() string {
 	return ""
 }
 
+func (m *CLUSConnection) GetMeshID() string {
+	if m != nil {
+		return m.MeshID
+	}
+	return ""
+}
+
 type CLUSConnectionArray struct {
 	Connections []*CLUSConnection `protobuf:"bytes,1,rep,name=Connections" json:"Connections,omitempty"`
 }
@@ -2543,153 +2575,155 @@ var _ControllerCtrlService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("controller_service.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 2361 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x59, 0xcd, 0x72, 0x1b, 0xb9,
-	0x11, 0x5e, 0x52, 0x92, 0x25, 0x41, 0x94, 0x4c, 0xc1, 0x92, 0x3c, 0x4b, 0x6b, 0x1d, 0x65, 0xf2,
-	0xa7, 0x72, 0x25, 0xce, 0x86, 0xa9, 0xac, 0xd7, 0xeb, 0x6c, 0x5c, 0x14, 0x29, 0xd9, 0x5c, 0x51,
-	0x32, 0x77, 0x48, 0xd9, 0xb9, 0xa5, 0x60, 0xb2, 0x4d, 0x4d, 0x69, 0x38, 0xa0, 0x31, 0xa0, 0x6c,
-	0xe6, 0x21, 0x72, 0x4a, 0x55, 0x6a, 0x5f, 0x20, 0xa7, 0xbc, 0x42, 0x8e, 0xa9, 0xe4, 0x9e, 0x57,
-	0xc8, 0x3b, 0xa4, 0x72, 0x4b, 0x35, 0x7e, 0x66, 0x30, 0x43, 0x7a, 0x6d, 0xdf, 0xd0, 0x1f, 0x80,
-	0xee, 0x46, 0xa3, 0xff, 0x30, 0x43, 0xbc, 0x01, 0x8f, 0xa5, 0xe0, 0x51, 0x04, 0xe2, 0x0f, 0x09,
-	0x88, 0xeb, 0x70, 0x00, 0xf7, 0x27, 0x82, 0x4b, 0x4e, 0x57, 0x92, 0x4b, 0x26, 0xa0, 0x56, 0x19,
-	0xf0, 0xf1, 0x98, 0xc7, 0x1a, 0xac, 0x91, 0x64, 0xc0, 0xcc, 0xd8, 0xff, 0x47, 0x89, 0xdc, 0x6e,
-	0x0c, 0xd9, 0x44, 0x82, 0xe8, 0x0d, 0x58, 0xdc, 0x1e, 0xb3, 0x11, 0x04, 0xf0, 0x7a, 0x0a, 0x89,
-	0xa4, 0x35, 0xb2, 0x16, 0xc0, 0x28, 0x4c, 0xa4, 0x98, 0x79, 0xa5, 0x83, 0xd2, 0xe1, 0x7a, 0x90,
-	0xd2, 0xf4, 0x2e, 0x21, 0x01, 0x4c, 0x78, 0x12, 0x4a, 0x2e, 0x66, 0x5e, 0x59, 0xcd, 0x3a, 0x08,
-	0xad, 0x92, 0xa5, 0x3e, 0x1b, 0x79, 0x4b, 0x6a, 0x02, 0x87, 0x74, 0x87, 0xac, 0xf4, 0xf9, 0x15,
-	0xc4, 0xde, 0xb2, 0xc2, 0x34, 0x81, 0x7c, 0x50, 0x6e, 0x87, 0xcd, 0x40, 0x24, 0xde, 0xca, 0x41,
-	0xe9, 0x70, 0x2d, 0x70, 0x10, 0xfa, 0x53, 0xb2, 0x65, 0xd4, 0x7b, 0x0e, 0x22, 0x09, 0x79, 0xec,
-	0xdd, 0x50, 0xdb, 0x0b, 0x28, 0x9e, 0xe3, 0xd6, 0x13, 0x90, 0xb8, 0x33, 0x06, 0x91, 0x04, 0x90,
-	0x4c, 0x78, 0x9c, 0x00, 0x9e, 0xc1, 0x62, 0xea, 0x0c, 0x9b, 0x41, 0x4a, 0xd3, 0x03, 0xb2, 0x71,
-	0xc6, 0xde, 0xa6, 0xd3, 0x65, 0x35, 0xed, 0x42, 0xd4, 0x27, 0x95, 0xf6, 0x30, 0x82, 0x74, 0xc9,
-	0x92, 0x5a, 0x92, 0xc3, 0x50, 0x43, 0x33, 0xb6, 0x1a, 0xea, 0x03, 0x16, 0x50, 0xfa, 0x63, 0xb2,
-	0x69, 0x90, 0xd6, 0x51, 0x3f, 0x1c, 0x83, 0x3a, 0xec, 0x7a, 0x90, 0x07, 0xfd, 0x7f, 0x95, 0xc9,
-	0x2d, 0x83, 0x68, 0x5b, 0x83, 0x68, 0x31, 0xc9, 0x50, 0x93, 0xe6, 0xf3, 0xe3, 0xd6, 0x91, 0x95,
-	0xa1, 0xef, 0x23, 0x87, 0xd1, 0x43, 0x72, 0x53, 0xd1, 0x4d, 0x01, 0x4c, 0x82, 0x92, 0xa1, 0x2f,
-	0xa6, 0x08, 0xd3, 0x47, 0x64, 0x45, 0x41, 0xde, 0xd2, 0xc1, 0xd2, 0xe1, 0x46, 0xfd, 0x27, 0xf7,
-	0x95, 0x9b, 0xdc, 0x5f, 0x20, 0xf8, 0xbe, 0x5a, 0x77, 0x1c, 0x4b, 0x31, 0x0b, 0xf4, 0x1e, 0xba,
-	0x4f, 0xd6, 0x83, 0x6e, 0xb3, 0x07, 0xe2, 0x1a, 0x84, 0x39, 0x6b, 0x06, 0xe0, 0x31, 0x53, 0xa2,
-	0xcb, 0x85, 0x54, 0xc7, 0xdc, 0x0c, 0xf2, 0x20, 0xdd, 0x22, 0xe5, 0x76, 0xcb, 0x5c, 0x65, 0xb9,
-	0xdd, 0xaa, 0x05, 0x84, 0x64, 0x82, 0xd0, 0x79, 0xae, 0xc0, 0xfa, 0x1c, 0x0e, 0xe9, 0x7d, 0xb2,
-	0x72, 0xcd, 0xa2, 0xa9, 0x3e, 0xd0, 0x46, 0xdd, 0x73, 0x14, 0x7e, 0x3e, 0x8d, 0x62, 0x10, 0xec,
-	0x65, 0x18, 0x85, 0x72, 0x16, 0xe8, 0x65, 0x5f, 0x95, 0xbf, 0x2c, 0xf9, 0x3f, 0x23, 0xbb, 0xd6,
-	0xb6, 0x20, 0x5c, 0x5b, 0x6a, 0xe1, 0x25, 0x2b, 0xdc, 0xff, 0x86, 0x6c, 0x35, 0x3b, 0x17, 0xbd,
-	0x93, 0x30, 0x82, 0x2e, 0x1b, 0x5c, 0x81, 0xa4, 0x94, 0x2c, 0xe3, 0x4a, 0xb5, 0xa6, 0x12, 0xa8,
-	0x31, 0x62, 0xe7, 0x2c, 0x35, 0xa9, 0x1a, 0xa3, 0xa2, 0x1d, 0x88, 0x8d, 0x5b, 0xe0, 0xd0, 0xff,
-	0x23, 0xd9, 0x41, 0x5e, 0x8d, 0xe1, 0x38, 0x4c, 0xf0, 0x52, 0x6c, 0x2c, 0x15, 0x64, 0xd2, 0x3d,
-	0x72, 0xe3, 0x29, 0x4f, 0x64, 0xbb, 0x65, 0xf8, 0x19, 0x0a, 0xfd, 0x15, 0x47, 0xcd, 0xee, 0x85,
-	0xf6, 0xb6, 0xa5, 0x20, 0xa5, 0x31, 0x56, 0x70, 0x7c, 0x06, 0x63, 0x8c, 0xb9, 0x65, 0x35, 0xeb,
-	0x20, 0x7e, 0x9b, 0xec, 0x16, 0x64, 0x9b, 0x20, 0xf0, 0xc8, 0x6a, 0x23, 0x8a, 0xf8, 0x1b, 0x18,
-	0x2a, 0x0d, 0xd6, 0x02, 0x4b, 0xa2, 0x1a, 0x01, 0xb0, 0x84, 0xc7, 0x56, 0x0d, 0x4d, 0xf9, 0x7f,
-	0x2b, 0x11, 0x8a, 0xbc, 0xba, 0x82, 0x0f, 0xba, 0x82, 0xbf, 0x0a, 0x23, 0xcc, 0x0a, 0x78, 0xf5,
-	0x4f, 0x04, 0x9f, 0x4e, 0x94, 0x21, 0xf4, 0x61, 0x32, 0x60, 0xa1, 0x85, 0x28, 0x59, 0xee, 0x32,
-	0x79, 0x69, 0x12, 0x81, 0x1a, 0x23, 0x76, 0x91, 0xa4, 0xbe, 0xa3, 0xc6, 0x68, 0xc9, 0x8b, 0x70,
-	0xa8, 0x9c, 0x65, 0x25, 0xc0, 0x21, 0xae, 0x7a, 0xca, 0x92, 0x4b, 0xe5, 0x24, 0x95, 0x40, 0x8d,
-	0x51, 0xdd, 0xc6, 0x40, 0xa2, 0xff, 0xaf, 0x6a, 0x75, 0x35, 0xe5, 0x3f, 0xd3, 0x56, 0x77, 0xb4,
-	0x6d, 0x08, 0xc1, 0x66, 0xf4, 0x01, 0x59, 0x47, 0x0c, 0x92, 0x04, 0x30, 0xfc, 0xd1, 0xd7, 0x3f,
-	0x35, 0xae, 0x33, 0x7f, 0xba, 0x20, 0x5b, 0xeb, 0x33, 0x6d, 0x4a, 0x74, 0x89, 0xc6, 0x00, 0xb1,
-	0x60, 0xfa, 0x21, 0x16, 0xd8, 0x23, 0x37, 0x4e, 0xc2, 0x48, 0x82, 0xb0, 0xe6, 0xd4, 0xd4, 0x22,
-	0x2b, 0xf8, 0x67, 0xe4, 0xf6, 0xbc, 0x08, 0xad, 0x76, 0x9d, 0xac, 0x20, 0x61, 0x55, 0xde, 0x77,
-	0x54, 0x9e, 0xd3, 0x28, 0xd0, 0x4b, 0xfd, 0xbf, 0xae, 0x6a, 0x2f, 0x6e, 0xf2, 0x38, 0x06, 0x65,
-	0x15, 0x75, 0xed, 0x23, 0x88, 0x65, 0xea, 0x78, 0x96, 0xfc, 0x3e, 0xef, 0x6b, 0x46, 0x21, 0xc4,
-	0xf2, 0x45, 0xc7, 0xe8, 0x9a, 0xd2, 0x2a, 0x93, 0xaa, 0x00, 0x7e, 0xd1, 0x31, 0x37, 0x97, 0xd2,
-	0xd9, 0xbe, 0x76, 0x57, 0x5d, 0x61, 0x25, 0x48, 0xe9, 0x6c, 0x5f, 0xbb, 0x6b, 0xee, 0x32, 0xa5,
-	0xb1, 0x26, 0xf4, 0x06, 0x7c, 0x02, 0xe6, 0x3a, 0x35, 0x81, 0x7a, 0x9f, 0x83, 0x7c, 0xc3, 0xc5,
-	0x95, 0xb7, 0xa6, 0xf5, 0x36, 0x24, 0x46, 0x80, 0xe6, 0xab, 0x32, 0xcb, 0xba, 0x0a, 0x3b, 0x07,
-	0x51, 0xd5, 0x24, 0xcb, 0x3c, 0x44, 0xcf, 0x3b, 0x69, 0xc7, 0x23, 0xab, 0xed, 0x6e, 0x17, 0x0b,
-	0x9f, 0xb7, 0xa1, 0x26, 0x2d, 0x89, 0xb5, 0xa0, 0x31, 0x99, 0x44, 0xe1, 0x80, 0x29, 0xf7, 0xaa,
-	0xe8, 0x5a, 0xe0, 0x40, 0xa8, 0xeb, 0xd1, 0x4c, 0x42, 0xe2, 0x6d, 0x1e, 0x94, 0x0e, 0x97, 0x03,
-	0x4d, 0xe8, 0xd3, 0xa9, 0x68, 0x4b, 0xbc, 0x2d, 0x53, 0x5f, 0x0c, 0x8d, 0x3c, 0x4f, 0x42, 0x91,
-	0xc8, 0x1e, 0x40, 0xdc, 0x90, 0xde, 0x4d, 0xcd, 0xd3, 0x81, 0x50, 0xdf, 0x0e, 0x4b, 0x17, 0x54,
-	0xb5, 0xbe, 0x19, 0x82, 0xdc, 0xfb, 0x97, 0x98, 0xb6, 0xdb, 0x2d, 0x6f, 0x5b, 0x73, 0xb7, 0xb4,
-	0x96, 0x7c, 0x0d, 0x22, 0x94, 0x33, 0x8f, 0x5a, 0xc9, 0x9a, 0xc6, 0x6a, 0xd1, 0xe5, 0x51, 0x38,
-	0x98, 0x99, 0x68, 0xb9, 0xa5, 0xeb, 0x96, 0x8b, 0x29, 0x5b, 0xc4, 0x23, 0x01, 0x49, 0xe2, 0xed,
-	0xe8, 0xa4, 0x60, 0x48, 0xdc, 0x7d, 0xfc, 0x56, 0x82, 0x88, 0x59, 0xd4, 0x05, 0x10, 0xde, 0xae,
-	0x9a, 0xce, 0x61, 0x18, 0x07, 0x1d, 0x3e, 0x30, 0x0b, 0xf6, 0xd4, 0x82, 0x0c, 0x40, 0xdd, 0xb4,
-	0xac, 0xf6, 0xd0, 0xbb, 0xad, 0x75, 0xb3, 0x34, 0xce, 0x3d, 0x0f, 0x79, 0xc4, 0xd0, 0x94, 0x9e,
-	0x9e, 0xb3, 0x34, 0xfa, 0x65, 0x87, 0x8f, 0x2e, 0xda, 0x2d, 0xef, 0x53, 0xed, 0x97, 0x9a, 0xc2,
-	0xec, 0xf0, 0xfb, 0x57, 0xaf, 0xbc, 0x9a, 0x92, 0x83, 0x43, 0x75, 0xfa, 0xeb, 0xc1, 0xf1, 0x5b,
-	0xf4, 0xb8, 0x3b, 0x0a, 0x4e, 0x69, 0xd4, 0xad, 0xcf, 0x7b, 0xe1, 0x10, 0x06, 0x4c, 0x78, 0xfb,
-	0x5a, 0xb7, 0x14, 0xc0, 0xd9, 0x33, 0x48, 0x2e, 0xfb, 0xbc, 0x77, 0x2d, 0xbc, 0xcf, 0xf4, 0x6c,
-	0x0a, 0xa8, 0x73, 0x85, 0xf1, 0x95, 0x3a, 0x8a, 0x77, 0xd7, 0x9c, 0xcb, 0x02, 0x68, 0xb3, 0xfe,
-	0x78, 0xf2, 0x6c, 0x02, 0xb1, 0xf7, 0x03, 0x6d, 0x33, 0x43, 0xa2, 0x77, 0x5c, 0xbc, 0x89, 0xda,
-	0x13, 0xef, 0x40, 0xe1, 0x9a, 0xc0, 0xb8, 0x3f, 0xf9, 0xb6, 0x75, 0xee, 0xfd, 0x50, 0xc7, 0x3d,
-	0x8e, 0xfd, 0x73, 0x72, 0x2b, 0x1f, 0xa7, 0x36, 0x55, 0x6d, 0x64, 0x90, 0x8d, 0xfc, 0x5d, 0x27,
-	0xf2, 0xb3, 0xd9, 0xc0, 0x5d, 0xe9, 0x8f, 0x75, 0xa6, 0xc6, 0xde, 0x4b, 0xc8, 0x34, 0xe5, 0xff,
-	0x32, 0xcd, 0x94, 0x18, 0xfa, 0x5b, 0xf5, 0xdb, 0x86, 0x53, 0xb6, 0x4c, 0x4f, 0xdb, 0x14, 0x8a,
-	0x6d, 0x8c, 0x9e, 0x6b, 0xc7, 0x12, 0xc4, 0x35, 0x8b, 0x4c, 0x3f, 0x54, 0x40, 0xfd, 0x06, 0xb9,
-	0x89, 0xe2, 0x7a, 0xb3, 0x78, 0xe0, 0xf4, 0x89, 0x4d, 0x26, 0x61, 0xc4, 0xb3, 0x3e, 0xd1, 0xd2,
-	0xca, 0x02, 0x82, 0x8f, 0x6d, 0x4d, 0xc0, 0xb1, 0xff, 0x98, 0x6c, 0x66, 0x2c, 0x26, 0xd1, 0xec,
-	0x7d, 0x0c, 0x54, 0x29, 0x2e, 0x67, 0xa5, 0xd8, 0xff, 0xae, 0xa4, 0xd3, 0x73, 0x33, 0x6d, 0x7b,
-	0x9b, 0x7c, 0x8a, 0x0a, 0x62, 0x40, 0x3d, 0x11, 0x6c, 0x72, 0x79, 0xce, 0x87, 0x60, 0x1b, 0x3e,
-	0x07, 0x51, 0xf3, 0x3c, 0xe0, 0x53, 0x19, 0xc6, 0x60, 0x3b, 0x3e, 0x07, 0x41, 0x69, 0x9d, 0x84,
-	0xbf, 0x52, 0xc9, 0xaf, 0x12, 0xa8, 0x31, 0x96, 0xee, 0x6e, 0x4f, 0xa5, 0xbc, 0x4a, 0x50, 0xee,
-	0xf6, 0xd0, 0x45, 0xb0, 0xaf, 0xe8, 0xb3, 0xe4, 0x2a, 0x31, 0xdd, 0x4d, 0x06, 0xf8, 0x43, 0x52,
-	0x41, 0xd5, 0x94, 0xcc, 0x67, 0x93, 0x24, 0x35, 0x40, 0x29, 0x33, 0x00, 0x72, 0xec, 0x73, 0x63,
-	0x92, 0x72, 0x9f, 0xe3, 0xf9, 0x8f, 0xe3, 0xe1, 0x84, 0x87, 0xb1, 0xb4, 0x69, 0xd7, 0xd2, 0xe8,
-	0x58, 0x8d, 0x28, 0x64, 0x89, 0x6d, 0x9b, 0x15, 0xe1, 0xff, 0xaf, 0xa4, 0xbd, 0x48, 0x47, 0x15,
-	0x56, 0x80, 0xe6, 0x25, 0x0c, 0xae, 0x9c, 0x36, 0x63, 0x53, 0xb5, 0x19, 0x0b, 0xcc, 0x6f, 0xa4,
-	0x2f, 0xa5, 0xd2, 0x77, 0xc8, 0x0a, 0x26, 0xc7, 0x54, 0x82, 0x22, 0x30, 0x09, 0x38, 0xd9, 0x0f,
-	0x0f, 0xba, 0x84, 0x29, 0xc4, 0xc5, 0xe8, 0x3e, 0x59, 0xed, 0x00, 0x13, 0x31, 0x0c, 0x55, 0x66,
-	0x5f, 0x3b, 0x2a, 0x7b, 0xa5, 0xc0, 0x42, 0x78, 0xaa, 0x56, 0x98, 0xb0, 0x97, 0x11, 0x0c, 0x55,
-	0x7e, 0x5f, 0x0b, 0x52, 0x1a, 0x6d, 0xa8, 0xdb, 0xd1, 0x61, 0xbf, 0xa7, 0x92, 0xfc, 0x52, 0x90,
-	0x01, 0x2a, 0x08, 0x59, 0x22, 0xcf, 0x38, 0xce, 0xae, 0xeb, 0xd9, 0x14, 0xf0, 0xff, 0x5c, 0x22,
-	0x7b, 0xf9, 0xb3, 0x9f, 0x85, 0xc9, 0x98, 0xc9, 0xc1, 0x25, 0xfd, 0x2d, 0xd9, 0x68, 0x46, 0x53,
-	0x6c, 0xf4, 0x10, 0x56, 0x76, 0xd8, 0xa8, 0xd7, 0xdc, 0x8a, 0x9f, 0xb7, 0x57, 0xe0, 0x2e, 0xc7,
-	0xdd, 0x46, 0x77, 0xb5, 0xbb, 0xfc, 0xfe, 0xdd, 0xce, 0x72, 0xff, 0xef, 0x25, 0xd3, 0x84, 0xa8,
-	0x45, 0xe8, 0xdc, 0x3d, 0xc9, 0xe4, 0x54, 0x27, 0x35, 0x60, 0x43, 0x10, 0xa6, 0xf9, 0x32, 0x14,
-	0x46, 0x5c, 0xea, 0x99, 0xca, 0x7f, 0x6d, 0xc4, 0xe5, 0x51, 0x5c, 0xe7, 0xc8, 0x39, 0x63, 0x6f,
-	0x4d, 0xbf, 0x59, 0x40, 0xe9, 0xd7, 0x84, 0x58, 0x43, 0x00, 0x5e, 0x26, 0x26, 0x90, 0xcf, 0x16,
-	0x6a, 0x6f, 0x97, 0x05, 0xce, 0x06, 0xff, 0x8d, 0x56, 0xbf, 0x27, 0xb9, 0x80, 0x17, 0x0a, 0x13,
-	0xed, 0xf8, 0x95, 0x72, 0xce, 0xa6, 0x14, 0x11, 0x88, 0xb4, 0x8d, 0x48, 0x69, 0xcc, 0xcb, 0xa7,
-	0x60, 0x9f, 0x7f, 0x38, 0xa4, 0xbf, 0x4a, 0xf3, 0xce, 0x92, 0xca, 0x3b, 0xb6, 0xdd, 0x72, 0xd9,
-	0xe6, 0x33, 0x8f, 0xff, 0x9f, 0x12, 0xd9, 0x47, 0xc9, 0xa7, 0xe1, 0xe0, 0xaa, 0xc3, 0x47, 0x61,
-	0x6c, 0x0b, 0xa8, 0x9b, 0x5f, 0xde, 0xa5, 0xc1, 0xe7, 0x64, 0xb9, 0x3f, 0x9b, 0xe8, 0xcb, 0xda,
-	0x4a, 0x3b, 0xa5, 0x39, 0x56, 0xb8, 0x26, 0x50, 0x2b, 0xf1, 0x3a, 0xcc, 0xdb, 0x45, 0x87, 0x80,
-	0xa1, 0xd0, 0xe1, 0xb1, 0x13, 0x3d, 0x99, 0x46, 0x51, 0x8c, 0xcd, 0x9d, 0x8e, 0x86, 0x1c, 0x86,
-	0xe9, 0x03, 0x69, 0xb3, 0x5f, 0x3f, 0xe0, 0x1c, 0x04, 0x35, 0x45, 0x4a, 0x35, 0x87, 0xfa, 0x71,
-	0x93, 0xd2, 0xfe, 0x9f, 0x4c, 0x4b, 0xad, 0xf4, 0x52, 0x8f, 0xdf, 0xf7, 0x9a, 0xd7, 0x23, 0xab,
-	0x6a, 0x75, 0xda, 0xa7, 0x59, 0x72, 0x4e, 0xd9, 0xa5, 0xc5, 0xca, 0x66, 0xb2, 0xcc, 0x71, 0x1c,
-	0xc4, 0x6f, 0xea, 0x24, 0x7a, 0x3a, 0x7d, 0x09, 0x22, 0x06, 0x09, 0xf8, 0x68, 0x56, 0x2a, 0xed,
-	0x91, 0x1b, 0x2d, 0x3e, 0x38, 0x4d, 0x5f, 0x60, 0x86, 0xca, 0xa5, 0xe2, 0x75, 0x9d, 0x8a, 0xef,
-	0x1d, 0x92, 0x6a, 0xb1, 0xa4, 0xd0, 0x35, 0xb2, 0xdc, 0xe2, 0x31, 0x54, 0x3f, 0xa1, 0x04, 0x9f,
-	0x17, 0x09, 0xc4, 0xc3, 0x6a, 0xe9, 0xde, 0x43, 0x42, 0xe7, 0x9d, 0x80, 0x56, 0x49, 0xa5, 0xcb,
-	0xa6, 0x89, 0x45, 0xab, 0x9f, 0xd0, 0x6d, 0xb2, 0x19, 0x40, 0x32, 0x1d, 0xa7, 0x50, 0xe9, 0xde,
-	0x53, 0xb2, 0xbb, 0xf0, 0x46, 0x71, 0x37, 0x4e, 0x1c, 0xcd, 0xb4, 0xfd, 0xab, 0x9f, 0xd0, 0x4d,
-	0xb2, 0xae, 0x91, 0x13, 0x18, 0x56, 0x4b, 0x74, 0x8b, 0x10, 0x4d, 0xa2, 0x65, 0xaa, 0xe5, 0xfa,
-	0x39, 0xd9, 0x71, 0x8a, 0x06, 0x9b, 0xf4, 0xf4, 0xd7, 0x12, 0xfa, 0x05, 0xa9, 0xb6, 0x93, 0x27,
-	0x41, 0xb7, 0xd9, 0xe4, 0xe3, 0x09, 0x36, 0x41, 0x30, 0xa4, 0x5b, 0xb6, 0x64, 0x76, 0x9b, 0xcf,
-	0x79, 0x38, 0xac, 0x51, 0x27, 0x96, 0x8e, 0x38, 0x8f, 0x80, 0xc5, 0xf5, 0xef, 0xd0, 0x77, 0x53,
-	0x86, 0x58, 0x05, 0xec, 0xc7, 0x14, 0xc3, 0xf8, 0x21, 0xd9, 0x70, 0x3e, 0x4b, 0xcc, 0xf1, 0xb4,
-	0xd9, 0x65, 0xd1, 0xa7, 0x8b, 0xdf, 0xe9, 0x3a, 0xa3, 0x3e, 0xc9, 0xd0, 0xbb, 0x66, 0xe1, 0x3b,
-	0xbe, 0xd5, 0xd4, 0xb6, 0x9d, 0x17, 0x31, 0x9a, 0x2f, 0x92, 0xf5, 0xbf, 0x94, 0xc9, 0x6e, 0x5e,
-	0x37, 0xab, 0xd4, 0xd7, 0xe4, 0x66, 0xe1, 0xa9, 0x4f, 0x6b, 0xef, 0xfe, 0x04, 0x50, 0x2b, 0x28,
-	0x4d, 0x8f, 0xd3, 0x87, 0xb5, 0x5d, 0xd6, 0x93, 0x02, 0xd8, 0xf8, 0x63, 0x98, 0x1c, 0x96, 0x68,
-	0x83, 0x6c, 0xcf, 0xbd, 0xcf, 0xe9, 0x7e, 0x9e, 0x45, 0xfe, 0xe5, 0x3e, 0xa7, 0xc9, 0x6f, 0x48,
-	0xb5, 0x37, 0x7d, 0x39, 0x0e, 0x65, 0x76, 0x6c, 0x3a, 0x6f, 0x89, 0xe2, 0xb6, 0xfa, 0x3f, 0x4b,
-	0xc4, 0xcb, 0x2c, 0x73, 0x31, 0x19, 0x09, 0x36, 0x04, 0x6b, 0x9c, 0x47, 0xa4, 0x6a, 0x11, 0xfb,
-	0x65, 0x86, 0xee, 0x16, 0x5e, 0x60, 0xfa, 0x33, 0xc1, 0x82, 0x33, 0x7d, 0x81, 0x0a, 0x4d, 0x74,
-	0x3c, 0x8c, 0xa6, 0x11, 0xc3, 0xcd, 0x1f, 0xe0, 0x47, 0xce, 0x3e, 0x23, 0xfb, 0xc3, 0xf6, 0xd5,
-	0xff, 0x5d, 0x26, 0x7b, 0xd9, 0x49, 0xd4, 0xf3, 0xce, 0x9e, 0xe3, 0x0c, 0x23, 0x53, 0x39, 0x47,
-	0xfa, 0x41, 0x80, 0xde, 0x71, 0x58, 0x14, 0x3f, 0x51, 0xd4, 0xf6, 0x17, 0x4f, 0x1a, 0x6f, 0x3c,
-	0x25, 0xdb, 0x3a, 0xd0, 0x9d, 0x47, 0x73, 0x8e, 0x5f, 0xf1, 0xf1, 0x5d, 0x73, 0x5f, 0xda, 0x85,
-	0xee, 0xf4, 0x5b, 0xb2, 0xa3, 0x91, 0xfc, 0x73, 0x36, 0xf5, 0xf2, 0x77, 0x3c, 0x8c, 0xbf, 0x8f,
-	0xe5, 0x37, 0x56, 0x3f, 0xa7, 0x37, 0xa6, 0xb5, 0x85, 0xfd, 0xf3, 0xfb, 0x78, 0xd5, 0xff, 0xbb,
-	0xea, 0x46, 0x0e, 0xa6, 0x63, 0x6b, 0xd4, 0x07, 0x64, 0x35, 0x80, 0xd7, 0x58, 0xdc, 0xe9, 0x9e,
-	0xb3, 0xdf, 0xe9, 0x86, 0x6b, 0x3b, 0x73, 0x38, 0xb6, 0xb8, 0x8f, 0x31, 0xab, 0xbd, 0xd6, 0x5d,
-	0x81, 0x8a, 0x95, 0x8f, 0xda, 0xfe, 0x79, 0x89, 0x3e, 0xfe, 0xd8, 0xf3, 0x15, 0x63, 0xa5, 0x45,
-	0x76, 0x9e, 0x80, 0x9c, 0x6f, 0x99, 0x8b, 0x6e, 0xb6, 0x9f, 0xe7, 0x59, 0x58, 0xfd, 0x90, 0xd0,
-	0x16, 0x44, 0x20, 0xa1, 0xc9, 0xe3, 0x6b, 0x10, 0x89, 0x7e, 0x1b, 0xdf, 0x72, 0xf6, 0xd8, 0xce,
-	0x77, 0x41, 0xb0, 0x6e, 0xe9, 0xad, 0x69, 0x6f, 0xfb, 0x41, 0xdb, 0x1e, 0x90, 0x6a, 0x0f, 0xa4,
-	0xdd, 0xa3, 0xda, 0xdf, 0x0f, 0xdb, 0xd8, 0x22, 0xbb, 0xaa, 0x4d, 0x9b, 0x6b, 0xc8, 0x8a, 0x27,
-	0xbe, 0x33, 0xd7, 0x24, 0x39, 0x8b, 0x7f, 0x41, 0x36, 0xfa, 0x22, 0x1c, 0x8d, 0x40, 0xa8, 0x5b,
-	0x2f, 0xee, 0x2d, 0x0a, 0x7d, 0x44, 0x2a, 0x3a, 0x08, 0xc2, 0x78, 0xd4, 0x1c, 0x0f, 0x8b, 0x11,
-	0xa2, 0x27, 0xec, 0x5d, 0x17, 0x37, 0x7f, 0x45, 0x3c, 0x47, 0x96, 0x69, 0xef, 0xb4, 0x46, 0xef,
-	0x15, 0x7c, 0x42, 0x6e, 0xab, 0x42, 0xaa, 0x6b, 0xa7, 0x5b, 0x69, 0x73, 0x3a, 0x14, 0xdb, 0xbb,
-	0x39, 0x3e, 0x4f, 0xc9, 0xf6, 0x5c, 0xad, 0xa5, 0x3f, 0x72, 0x38, 0xbc, 0xab, 0x4d, 0x9b, 0xe3,
-	0xf4, 0x73, 0xb2, 0x86, 0x65, 0x4d, 0x32, 0x39, 0x6f, 0xf2, 0x6a, 0x4e, 0x25, 0x5c, 0xd1, 0xc0,
-	0x94, 0x90, 0x80, 0xcc, 0x1a, 0x94, 0x7e, 0x38, 0x06, 0x41, 0xdd, 0x30, 0xcd, 0xb7, 0x4e, 0x73,
-	0x02, 0x1b, 0x36, 0x44, 0x4e, 0xbf, 0xc4, 0x2a, 0xda, 0xe7, 0xcf, 0xba, 0x0d, 0xea, 0xba, 0xf3,
-	0x5c, 0xab, 0x53, 0x64, 0xf1, 0xf2, 0x86, 0xfa, 0x2b, 0xf2, 0xeb, 0xff, 0x07, 0x00, 0x00, 0xff,
-	0xff, 0xfe, 0xc1, 0xfc, 0x73, 0x52, 0x19, 0x00, 0x00,
+	// 2396 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x59, 0xdb, 0x72, 0x1b, 0xb9,
+	0xd1, 0x36, 0x49, 0xc9, 0x92, 0x5a, 0x07, 0x53, 0xb0, 0x24, 0xcf, 0xd2, 0x5a, 0xff, 0xfa, 0x27,
+	0xc9, 0x46, 0xe5, 0x4a, 0x9c, 0x8d, 0x52, 0x59, 0xaf, 0xd7, 0xd9, 0xb8, 0x28, 0x52, 0xb2, 0xb9,
+	0x3a, 0x98, 0x3b, 0xa4, 0xec, 0xdc, 0xa5, 0x60, 0x12, 0xa6, 0xa6, 0x34, 0x1c, 0x70, 0x01, 0x50,
+	0x36, 0xf3, 0x10, 0xb9, 0x4a, 0x55, 0x6a, 0x1f, 0x26, 0xb9, 0x4b, 0x25, 0xf7, 0x79, 0x85, 0xbc,
+	0x43, 0x2a, 0xb9, 0x4a, 0x35, 0x0e, 0x33, 0xe0, 0x90, 0x5e, 0xdb, 0x57, 0x44, 0x7f, 0x00, 0xfa,
+	0x34, 0xdd, 0x8d, 0x06, 0x08, 0x41, 0x8f, 0xa7, 0x4a, 0xf0, 0x24, 0x61, 0xe2, 0xf7, 0x92, 0x89,
+	0xeb, 0xb8, 0xc7, 0x1e, 0x8c, 0x04, 0x57, 0x9c, 0x2c, 0xca, 0x4b, 0x2a, 0x58, 0x6d, 0xad, 0xc7,
+	0x87, 0x43, 0x9e, 0x1a, 0xb0, 0x06, 0xb2, 0x47, 0xed, 0x38, 0xfc, 0x5b, 0x09, 0xee, 0xd4, 0xfb,
+	0x74, 0xa4, 0x98, 0xe8, 0xf4, 0x68, 0xda, 0x1a, 0xd2, 0x01, 0x8b, 0xd8, 0x77, 0x63, 0x26, 0x15,
+	0xa9, 0xc1, 0x72, 0xc4, 0x06, 0xb1, 0x54, 0x62, 0x12, 0x94, 0xf6, 0x4a, 0xfb, 0x2b, 0x51, 0x46,
+	0x93, 0x7b, 0x00, 0x11, 0x1b, 0x71, 0x19, 0x2b, 0x2e, 0x26, 0x41, 0x59, 0xcf, 0x7a, 0x08, 0xa9,
+	0x42, 0xa5, 0x4b, 0x07, 0x41, 0x45, 0x4f, 0xe0, 0x90, 0x6c, 0xc1, 0x62, 0x97, 0x5f, 0xb1, 0x34,
+	0x58, 0xd0, 0x98, 0x21, 0x90, 0x0f, 0xca, 0x3d, 0xa5, 0x13, 0x26, 0x64, 0xb0, 0xb8, 0x57, 0xda,
+	0x5f, 0x8e, 0x3c, 0x84, 0x7c, 0x06, 0x1b, 0x56, 0xbd, 0x17, 0x4c, 0xc8, 0x98, 0xa7, 0xc1, 0x4d,
+	0xbd, 0xbd, 0x80, 0xa2, 0x1d, 0xb7, 0x9f, 0x32, 0x85, 0x3b, 0x53, 0x26, 0x64, 0xc4, 0xe4, 0x88,
+	0xa7, 0x92, 0xa1, 0x0d, 0x0e, 0xd3, 0x36, 0xac, 0x47, 0x19, 0x4d, 0xf6, 0x60, 0xf5, 0x8c, 0xbe,
+	0xcd, 0xa6, 0xcb, 0x7a, 0xda, 0x87, 0x48, 0x08, 0x6b, 0xad, 0x7e, 0xc2, 0xb2, 0x25, 0x15, 0xbd,
+	0x64, 0x0a, 0x43, 0x0d, 0xed, 0xd8, 0x69, 0x68, 0x0c, 0x2c, 0xa0, 0xe4, 0xc7, 0xb0, 0x6e, 0x91,
+	0xe6, 0x61, 0x37, 0x1e, 0x32, 0x6d, 0xec, 0x4a, 0x34, 0x0d, 0x86, 0xff, 0x28, 0xc3, 0x6d, 0x8b,
+	0x18, 0x5f, 0x33, 0xd1, 0xa4, 0x8a, 0xa2, 0x26, 0x8d, 0x17, 0x47, 0xcd, 0x43, 0x27, 0xc3, 0x7c,
+	0x8f, 0x29, 0x8c, 0xec, 0xc3, 0x2d, 0x4d, 0x37, 0x04, 0xa3, 0x8a, 0x69, 0x19, 0xe6, 0xc3, 0x14,
+	0x61, 0xf2, 0x18, 0x16, 0x35, 0x14, 0x54, 0xf6, 0x2a, 0xfb, 0xab, 0x07, 0x3f, 0x79, 0xa0, 0xc3,
+	0xe4, 0xc1, 0x1c, 0xc1, 0x0f, 0xf4, 0xba, 0xa3, 0x54, 0x89, 0x49, 0x64, 0xf6, 0x90, 0x5d, 0x58,
+	0x89, 0xda, 0x8d, 0x0e, 0x13, 0xd7, 0x4c, 0x58, 0x5b, 0x73, 0x00, 0xcd, 0xcc, 0x88, 0x36, 0x17,
+	0x4a, 0x9b, 0xb9, 0x1e, 0x4d, 0x83, 0x64, 0x03, 0xca, 0xad, 0xa6, 0xfd, 0x94, 0xe5, 0x56, 0xb3,
+	0x16, 0x01, 0xe4, 0x82, 0x30, 0x78, 0xae, 0x98, 0x8b, 0x39, 0x1c, 0x92, 0x07, 0xb0, 0x78, 0x4d,
+	0x93, 0xb1, 0x31, 0x68, 0xf5, 0x20, 0xf0, 0x14, 0x7e, 0x31, 0x4e, 0x52, 0x26, 0xe8, 0xab, 0x38,
+	0x89, 0xd5, 0x24, 0x32, 0xcb, 0xbe, 0x2a, 0x7f, 0x59, 0x0a, 0x7f, 0x0a, 0xdb, 0xce, 0xb7, 0x4c,
+	0xf8, 0xbe, 0x34, 0xc2, 0x4b, 0x4e, 0x78, 0xf8, 0x0d, 0x6c, 0x34, 0x4e, 0x2f, 0x3a, 0xc7, 0x71,
+	0xc2, 0xda, 0xb4, 0x77, 0xc5, 0x14, 0x21, 0xb0, 0x80, 0x2b, 0xf5, 0x9a, 0xb5, 0x48, 0x8f, 0x11,
+	0x3b, 0xa7, 0x99, 0x4b, 0xf5, 0x18, 0x15, 0x3d, 0x65, 0xa9, 0x0d, 0x0b, 0x1c, 0x86, 0x7f, 0x80,
+	0x2d, 0xe4, 0x55, 0xef, 0x0f, 0x63, 0x89, 0x1f, 0xc5, 0xe5, 0x52, 0x41, 0x26, 0xd9, 0x81, 0x9b,
+	0xcf, 0xb8, 0x54, 0xad, 0xa6, 0xe5, 0x67, 0x29, 0x8c, 0x57, 0x1c, 0x35, 0xda, 0x17, 0x26, 0xda,
+	0x2a, 0x51, 0x46, 0x63, 0xae, 0xe0, 0xf8, 0x8c, 0x0d, 0x31, 0xe7, 0x16, 0xf4, 0xac, 0x87, 0x84,
+	0x2d, 0xd8, 0x2e, 0xc8, 0xb6, 0x49, 0x10, 0xc0, 0x52, 0x3d, 0x49, 0xf8, 0x1b, 0xd6, 0xd7, 0x1a,
+	0x2c, 0x47, 0x8e, 0x44, 0x35, 0x22, 0x46, 0x25, 0x4f, 0x9d, 0x1a, 0x86, 0x0a, 0xff, 0x5b, 0x02,
+	0x82, 0xbc, 0xda, 0x82, 0xf7, 0xda, 0x82, 0xbf, 0x8e, 0x13, 0xac, 0x0a, 0xf8, 0xe9, 0x9f, 0x0a,
+	0x3e, 0x1e, 0x69, 0x47, 0x18, 0x63, 0x72, 0x60, 0xae, 0x87, 0x08, 0x2c, 0xb4, 0xa9, 0xba, 0xb4,
+	0x85, 0x40, 0x8f, 0x11, 0xbb, 0x90, 0x59, 0xec, 0xe8, 0x31, 0x7a, 0xf2, 0x22, 0xee, 0xeb, 0x60,
+	0x59, 0x8c, 0x70, 0x88, 0xab, 0x9e, 0x51, 0x79, 0xa9, 0x83, 0x64, 0x2d, 0xd2, 0x63, 0x54, 0xb7,
+	0xde, 0x53, 0x18, 0xff, 0x4b, 0x46, 0x5d, 0x43, 0xe1, 0xda, 0xba, 0x18, 0xc8, 0x60, 0xd9, 0x70,
+	0xc4, 0x31, 0xea, 0x8a, 0xbf, 0x67, 0x54, 0xf5, 0x2e, 0x83, 0x15, 0xa3, 0x6b, 0x06, 0xe8, 0xba,
+	0x30, 0x91, 0x3d, 0x9a, 0x24, 0x32, 0x80, 0xbd, 0x0a, 0xd6, 0x36, 0x47, 0x87, 0xcf, 0x61, 0xab,
+	0x60, 0x7b, 0x5d, 0x08, 0x3a, 0x21, 0x0f, 0x61, 0x05, 0x31, 0x26, 0x25, 0xc3, 0x62, 0x82, 0x99,
+	0xf3, 0x89, 0x0d, 0xc4, 0x59, 0x5f, 0x45, 0xf9, 0xda, 0x90, 0xc2, 0xb6, 0x0b, 0xb0, 0x7a, 0x0f,
+	0xb1, 0x68, 0xfc, 0x21, 0xfe, 0xdc, 0x81, 0x9b, 0xc7, 0x71, 0xa2, 0x98, 0x70, 0x1f, 0xc7, 0x50,
+	0xf3, 0x7c, 0x1a, 0x9e, 0xc1, 0x9d, 0x59, 0x11, 0x46, 0xed, 0x03, 0x58, 0x44, 0xc2, 0xa9, 0xbc,
+	0xeb, 0xa9, 0x3c, 0xa3, 0x51, 0x64, 0x96, 0x86, 0x7f, 0x5d, 0x32, 0x39, 0xd1, 0xe0, 0x69, 0xca,
+	0x8c, 0x8f, 0x31, 0x88, 0x06, 0x2c, 0x55, 0x59, 0x18, 0x3b, 0xf2, 0x87, 0x62, 0xb9, 0x91, 0xc4,
+	0x2c, 0x55, 0x2f, 0x4f, 0xad, 0xae, 0x19, 0xad, 0xfd, 0xaf, 0xcb, 0xc1, 0xcb, 0x53, 0x1b, 0x07,
+	0x19, 0x9d, 0xef, 0x6b, 0xb5, 0x75, 0x40, 0xac, 0x45, 0x19, 0x9d, 0xef, 0x6b, 0xb5, 0x6d, 0x64,
+	0x64, 0x34, 0x9e, 0x30, 0x9d, 0x1e, 0x1f, 0x31, 0x1b, 0x1c, 0x86, 0x40, 0xbd, 0xcf, 0x99, 0x7a,
+	0xc3, 0xc5, 0x95, 0x0d, 0x0f, 0x47, 0x62, 0x3e, 0x19, 0xbe, 0xba, 0x4e, 0xad, 0xe8, 0x24, 0xf6,
+	0x10, 0x9c, 0xf7, 0xea, 0x18, 0x98, 0xf9, 0x1c, 0x41, 0xce, 0xad, 0x76, 0x1b, 0x8f, 0xd1, 0x60,
+	0x55, 0x4f, 0x3a, 0x12, 0x4f, 0x96, 0xfa, 0x68, 0x94, 0xc4, 0x3d, 0xaa, 0x83, 0x75, 0xcd, 0x9c,
+	0x2c, 0x1e, 0x84, 0xba, 0x1e, 0x4e, 0x14, 0x93, 0xc1, 0xfa, 0x5e, 0x69, 0x7f, 0x21, 0x32, 0x84,
+	0xb1, 0x4e, 0xe7, 0xae, 0x0c, 0x36, 0xec, 0x69, 0x65, 0x69, 0xe4, 0x79, 0x1c, 0x0b, 0xa9, 0x3a,
+	0x8c, 0xa5, 0x75, 0x15, 0xdc, 0x32, 0x3c, 0x3d, 0x08, 0xf5, 0x3d, 0xa5, 0xd9, 0x82, 0xaa, 0xd1,
+	0x37, 0x47, 0x90, 0x7b, 0xf7, 0x12, 0x0f, 0x81, 0x56, 0x33, 0xd8, 0x34, 0xdc, 0x1d, 0x6d, 0x24,
+	0x5f, 0x33, 0x11, 0xab, 0x49, 0x40, 0x9c, 0x64, 0x43, 0xe3, 0xd9, 0xd3, 0xe6, 0x49, 0xdc, 0x9b,
+	0xd8, 0xdc, 0xbb, 0x6d, 0x4e, 0x41, 0x1f, 0xd3, 0xbe, 0x48, 0x07, 0x82, 0x49, 0x19, 0x6c, 0x99,
+	0x12, 0x63, 0x49, 0xdc, 0x7d, 0xf4, 0x56, 0x31, 0x91, 0xd2, 0xa4, 0xcd, 0x98, 0x08, 0xb6, 0xf5,
+	0xf4, 0x14, 0x86, 0x79, 0x70, 0xca, 0x7b, 0x76, 0xc1, 0x8e, 0x5e, 0x90, 0x03, 0xa8, 0x9b, 0x91,
+	0xd5, 0xea, 0x07, 0x77, 0x8c, 0x6e, 0x8e, 0xc6, 0xb9, 0x17, 0x31, 0x4f, 0x28, 0xba, 0x32, 0x30,
+	0x73, 0x8e, 0xc6, 0xb8, 0x3c, 0xe5, 0x83, 0x8b, 0x56, 0x33, 0xf8, 0xc4, 0xc4, 0xa5, 0xa1, 0xb0,
+	0xd6, 0xfc, 0xee, 0xf5, 0xeb, 0xa0, 0xa6, 0xe5, 0xe0, 0x50, 0x5b, 0x7f, 0xdd, 0x3b, 0x7a, 0x8b,
+	0x11, 0x77, 0x57, 0xc3, 0x19, 0x8d, 0xba, 0x75, 0x79, 0x27, 0xee, 0xb3, 0x1e, 0x15, 0xc1, 0xae,
+	0xd1, 0x2d, 0x03, 0x70, 0xf6, 0x8c, 0xc9, 0xcb, 0x2e, 0xef, 0x5c, 0x8b, 0xe0, 0x53, 0x33, 0x9b,
+	0x01, 0xda, 0xae, 0x38, 0xbd, 0xd2, 0xa6, 0x04, 0xf7, 0xac, 0x5d, 0x0e, 0x40, 0x9f, 0x75, 0x87,
+	0xa3, 0xe7, 0x23, 0x96, 0x06, 0xff, 0x67, 0x7c, 0x66, 0x49, 0x8c, 0x8e, 0x8b, 0x37, 0x49, 0x6b,
+	0x14, 0xec, 0x69, 0xdc, 0x10, 0x98, 0xf7, 0xc7, 0xdf, 0x36, 0xcf, 0x83, 0xff, 0x37, 0x79, 0x8f,
+	0x63, 0xb4, 0x11, 0xc5, 0xb5, 0x9a, 0x41, 0x68, 0x6c, 0x34, 0x54, 0x78, 0x0e, 0xb7, 0xa7, 0xf3,
+	0xd7, 0x95, 0xb0, 0xd5, 0x1c, 0x72, 0x15, 0x61, 0xdb, 0xab, 0x08, 0xf9, 0x6c, 0xe4, 0xaf, 0x0c,
+	0x87, 0xe6, 0x3c, 0xc0, 0x0e, 0x4f, 0xa8, 0xec, 0x60, 0xf9, 0x45, 0x56, 0x8f, 0xb1, 0x24, 0x6c,
+	0x1c, 0xdc, 0xb1, 0x9c, 0xf2, 0x65, 0x66, 0x3a, 0x2b, 0xd4, 0x9f, 0xc1, 0x86, 0x99, 0x6b, 0xa5,
+	0x8a, 0x89, 0x6b, 0x9a, 0xd8, 0xae, 0xab, 0x80, 0x86, 0x75, 0xb8, 0x85, 0xe2, 0x3a, 0x93, 0xb4,
+	0xe7, 0x75, 0xa3, 0x0d, 0xaa, 0xd8, 0x80, 0xe7, 0xdd, 0xa8, 0xa3, 0xb5, 0x67, 0x04, 0x1f, 0xba,
+	0x93, 0x07, 0xc7, 0xe1, 0x13, 0x58, 0xcf, 0x59, 0x8c, 0x92, 0xc9, 0xfb, 0x18, 0xe8, 0x03, 0xbf,
+	0x9c, 0x1f, 0xf8, 0xe1, 0xf7, 0x25, 0x53, 0xb6, 0x1b, 0x59, 0x73, 0xdd, 0xe0, 0x63, 0x54, 0x10,
+	0x13, 0xed, 0xa9, 0xa0, 0xa3, 0xcb, 0x73, 0xde, 0x67, 0xae, 0xad, 0xf4, 0x10, 0x3d, 0xcf, 0x23,
+	0x3e, 0x56, 0x71, 0xca, 0x5c, 0x5f, 0xe9, 0x21, 0x28, 0xed, 0x54, 0xf2, 0xd7, 0xba, 0x28, 0xae,
+	0x45, 0x7a, 0x8c, 0x0d, 0x42, 0xbb, 0xa3, 0x4b, 0xe1, 0x5a, 0x54, 0x6e, 0x77, 0x30, 0x74, 0xb0,
+	0x7b, 0xe9, 0x52, 0x79, 0x25, 0x6d, 0x0f, 0x95, 0x03, 0x61, 0x1f, 0xd6, 0x50, 0x35, 0x2d, 0xf3,
+	0xf9, 0x48, 0x66, 0x0e, 0x28, 0xe5, 0x0e, 0x40, 0x8e, 0x5d, 0x6e, 0x5d, 0x52, 0xee, 0x72, 0xb4,
+	0xff, 0x28, 0xed, 0x8f, 0x78, 0x9c, 0x2a, 0x57, 0x8e, 0x1d, 0x8d, 0x01, 0x57, 0x4f, 0x62, 0x2a,
+	0x5d, 0x73, 0xae, 0x89, 0xf0, 0x3f, 0x25, 0x13, 0x45, 0x26, 0xdb, 0xf0, 0x64, 0x68, 0x5c, 0xb2,
+	0xde, 0x95, 0xd7, 0xcc, 0xac, 0xeb, 0x66, 0x66, 0x8e, 0xfb, 0xad, 0xf4, 0x4a, 0x26, 0x7d, 0x0b,
+	0x16, 0xb1, 0x68, 0x66, 0x12, 0x34, 0x81, 0xc5, 0xc1, 0xab, 0x8a, 0x68, 0x68, 0x05, 0x4b, 0x8b,
+	0x8f, 0x91, 0x5d, 0x58, 0x3a, 0x65, 0x54, 0xa4, 0xac, 0xaf, 0x2b, 0xfe, 0xf2, 0x61, 0x39, 0x28,
+	0x45, 0x0e, 0x42, 0xab, 0x9a, 0xb1, 0xa4, 0xaf, 0x12, 0xd6, 0xd7, 0x75, 0x7f, 0x39, 0xca, 0x68,
+	0xf4, 0xa1, 0x69, 0x7a, 0xfb, 0xdd, 0x8e, 0x2e, 0xfe, 0x95, 0x28, 0x07, 0x74, 0x72, 0x52, 0xa9,
+	0xce, 0x38, 0xce, 0xae, 0x98, 0xd9, 0x0c, 0x08, 0xff, 0x54, 0x82, 0x9d, 0x69, 0xdb, 0xcf, 0x62,
+	0x39, 0xd4, 0xbd, 0xc3, 0x6f, 0x60, 0xb5, 0x91, 0x8c, 0xa5, 0x62, 0x02, 0x61, 0xed, 0x87, 0xd5,
+	0x83, 0x9a, 0xdf, 0x09, 0x4c, 0xfb, 0x2b, 0xf2, 0x97, 0xe3, 0x6e, 0xab, 0xbb, 0xde, 0x5d, 0x7e,
+	0xff, 0x6e, 0x6f, 0x79, 0xf8, 0x97, 0x12, 0x6c, 0xe5, 0x8b, 0x30, 0xb8, 0x3b, 0x8a, 0xaa, 0xb1,
+	0x29, 0x76, 0x8c, 0xf6, 0x99, 0xb0, 0x2d, 0x9e, 0xa5, 0x30, 0xe3, 0xb2, 0xc8, 0xd4, 0xf1, 0xeb,
+	0x32, 0x6e, 0x1a, 0xc5, 0x75, 0x9e, 0x9c, 0x33, 0xfa, 0xd6, 0x76, 0xb5, 0x05, 0x94, 0x7c, 0x0d,
+	0xe0, 0x1c, 0xc1, 0xf0, 0x63, 0x62, 0x01, 0xf9, 0x74, 0xae, 0xf6, 0x6e, 0x59, 0xe4, 0x6d, 0x08,
+	0xdf, 0x18, 0xf5, 0x3b, 0x8a, 0x0b, 0xf6, 0x52, 0x63, 0xa2, 0x95, 0xbe, 0xd6, 0xc1, 0xd9, 0x50,
+	0x22, 0x61, 0x22, 0x6b, 0x2f, 0x32, 0x1a, 0xeb, 0xf5, 0x09, 0x73, 0x97, 0x4c, 0x1c, 0x92, 0x5f,
+	0x66, 0x75, 0xa7, 0xa2, 0xeb, 0x8e, 0x6b, 0xc3, 0x7c, 0xb6, 0xd3, 0x95, 0x27, 0xfc, 0x57, 0x09,
+	0x76, 0x51, 0xf2, 0x49, 0xdc, 0xbb, 0x3a, 0xe5, 0x83, 0x38, 0x75, 0x07, 0xab, 0x5f, 0x5f, 0xde,
+	0xa5, 0xc1, 0xe7, 0xb0, 0xd0, 0x9d, 0x8c, 0xcc, 0xc7, 0xda, 0xc8, 0x3a, 0xa8, 0x19, 0x56, 0xb8,
+	0x26, 0xd2, 0x2b, 0xf1, 0x73, 0xd8, 0x1b, 0x92, 0x49, 0x01, 0x4b, 0x61, 0xc0, 0x63, 0xbf, 0x7b,
+	0x3c, 0x4e, 0x92, 0x14, 0x9b, 0x3e, 0x93, 0x0d, 0x53, 0x18, 0x96, 0x0f, 0xa4, 0xed, 0x7e, 0x73,
+	0x4d, 0xf4, 0x10, 0xd4, 0x14, 0x29, 0xdd, 0x34, 0x9a, 0x2b, 0x54, 0x46, 0x87, 0x7f, 0xb4, 0x8d,
+	0xbb, 0xd6, 0x4b, 0x5f, 0xb1, 0xdf, 0xeb, 0xde, 0x00, 0x96, 0xf4, 0xea, 0xac, 0x7f, 0x73, 0xe4,
+	0x8c, 0xb2, 0x95, 0xf9, 0xca, 0xe6, 0xb2, 0xac, 0x39, 0x1e, 0x12, 0x36, 0x4c, 0x11, 0x3d, 0x19,
+	0xbf, 0x62, 0x22, 0x65, 0x8a, 0xc9, 0x88, 0x49, 0xad, 0xd2, 0x0e, 0xdc, 0x6c, 0xf2, 0xde, 0x49,
+	0x76, 0xcf, 0xb3, 0xd4, 0x54, 0x29, 0x5e, 0x31, 0xa5, 0xf8, 0xfe, 0x3e, 0x54, 0x8b, 0x47, 0x0a,
+	0x59, 0x86, 0x85, 0x26, 0x4f, 0x59, 0xf5, 0x06, 0x01, 0xbc, 0xc4, 0x48, 0x96, 0xf6, 0xab, 0xa5,
+	0xfb, 0x8f, 0x80, 0xcc, 0x06, 0x01, 0xa9, 0xc2, 0x5a, 0x9b, 0x8e, 0xa5, 0x43, 0xab, 0x37, 0xc8,
+	0x26, 0xac, 0x47, 0x4c, 0x8e, 0x87, 0x19, 0x54, 0xba, 0xff, 0x0c, 0xb6, 0xe7, 0x7e, 0x51, 0xdc,
+	0x8d, 0x13, 0x87, 0x13, 0xe3, 0xff, 0xea, 0x0d, 0xb2, 0x0e, 0x2b, 0x06, 0x39, 0x66, 0xfd, 0x6a,
+	0x89, 0x6c, 0x00, 0x18, 0x12, 0x3d, 0x53, 0x2d, 0x1f, 0x9c, 0xc3, 0x96, 0x77, 0x68, 0xd0, 0x51,
+	0xc7, 0xbc, 0xc9, 0x90, 0x2f, 0xa0, 0xda, 0x92, 0x4f, 0xa3, 0x76, 0xa3, 0xc1, 0x87, 0x23, 0xc1,
+	0xa4, 0x64, 0x7d, 0xb2, 0xe1, 0x8e, 0xcc, 0x76, 0xe3, 0x05, 0x8f, 0xfb, 0x35, 0xe2, 0xe5, 0xd2,
+	0x21, 0xe7, 0x09, 0xa3, 0xe9, 0xc1, 0xf7, 0x18, 0xbb, 0x19, 0x43, 0x3c, 0x05, 0xdc, 0x93, 0x8d,
+	0x65, 0xfc, 0x08, 0x56, 0xbd, 0xc7, 0x8f, 0x19, 0x9e, 0xae, 0xba, 0xcc, 0x7b, 0x20, 0xf9, 0x2d,
+	0xac, 0x64, 0x0f, 0x3f, 0xe4, 0x9e, 0x5d, 0xf8, 0x8e, 0x17, 0xa1, 0xda, 0xa6, 0x77, 0xef, 0x46,
+	0xf7, 0x25, 0xea, 0xe0, 0xcf, 0x65, 0xd8, 0x9e, 0xd6, 0xcd, 0x29, 0xf5, 0x35, 0xdc, 0x2a, 0x3c,
+	0x28, 0x90, 0xda, 0xbb, 0x1f, 0x1a, 0x6a, 0x05, 0xa5, 0xc9, 0x11, 0x6c, 0x17, 0x96, 0x75, 0x94,
+	0x60, 0x74, 0xf8, 0x31, 0x4c, 0xf6, 0x4b, 0xa4, 0x0e, 0x9b, 0x33, 0xaf, 0x00, 0x64, 0x77, 0x9a,
+	0xc5, 0xf4, 0xfb, 0xc0, 0x8c, 0x26, 0xbf, 0x86, 0x6a, 0x67, 0xfc, 0x6a, 0x18, 0xab, 0xdc, 0x6c,
+	0x32, 0xeb, 0x89, 0xe2, 0xb6, 0x83, 0xbf, 0x97, 0x20, 0xc8, 0x3d, 0x73, 0x31, 0x1a, 0x08, 0xda,
+	0x67, 0xce, 0x39, 0x8f, 0xa1, 0xea, 0x10, 0xf7, 0xfe, 0x43, 0xb6, 0x0b, 0x37, 0x33, 0xf3, 0x18,
+	0x31, 0xc7, 0xa6, 0x2f, 0x50, 0xa1, 0x91, 0xc9, 0x87, 0xc1, 0x38, 0xa1, 0xb8, 0xf9, 0x03, 0xe2,
+	0xc8, 0xdb, 0x67, 0x65, 0x7f, 0xd8, 0xbe, 0x83, 0x7f, 0x96, 0x61, 0x27, 0xb7, 0x44, 0x5f, 0xfb,
+	0x9c, 0x1d, 0x67, 0x50, 0xb5, 0xc1, 0x91, 0x3d, 0x3b, 0x90, 0xbb, 0x1e, 0x8b, 0xe2, 0x43, 0x48,
+	0x6d, 0x77, 0xfe, 0xa4, 0x8d, 0xc6, 0x13, 0xd8, 0x34, 0x89, 0xee, 0x5d, 0xa6, 0xa7, 0xf8, 0x15,
+	0x2f, 0xe5, 0x35, 0xff, 0x06, 0x5e, 0xe8, 0x4e, 0xbf, 0x85, 0x2d, 0x83, 0x4c, 0x5f, 0x73, 0xc9,
+	0x3d, 0x6f, 0xcb, 0x9c, 0x0b, 0xf3, 0x0f, 0xb1, 0xfc, 0xc6, 0xe9, 0xe7, 0xf5, 0xc6, 0xa4, 0x36,
+	0xb7, 0x7f, 0x7e, 0x1f, 0xaf, 0x83, 0x7f, 0x2f, 0xf9, 0x99, 0x83, 0xe5, 0xd8, 0x39, 0xf5, 0x21,
+	0x2c, 0x45, 0xec, 0x3b, 0x3c, 0xdc, 0xc9, 0x8e, 0xb7, 0xdf, 0xeb, 0x86, 0x6b, 0x5b, 0x33, 0x38,
+	0xb6, 0xb8, 0x4f, 0x60, 0xdd, 0x6e, 0xb4, 0xb9, 0xf2, 0x51, 0xdb, 0x3f, 0x2f, 0x91, 0x27, 0x1f,
+	0x6b, 0x5f, 0x31, 0x57, 0x9a, 0xb0, 0xf5, 0x94, 0xa9, 0xd9, 0x96, 0xb9, 0x18, 0x66, 0xbb, 0xd3,
+	0x3c, 0x0b, 0xab, 0x1f, 0x01, 0x69, 0xb2, 0x84, 0x29, 0xd6, 0xe0, 0xe9, 0x35, 0x13, 0xd2, 0xdc,
+	0x99, 0x6f, 0x7b, 0x7b, 0x5c, 0xe7, 0x3b, 0x27, 0x59, 0x37, 0xcc, 0xd6, 0xac, 0xb7, 0xfd, 0xa0,
+	0x6d, 0x0f, 0xa1, 0xda, 0x61, 0xca, 0xed, 0xd1, 0xed, 0xef, 0x87, 0x6d, 0x6c, 0xc2, 0xb6, 0x6e,
+	0xd3, 0x66, 0x1a, 0xb2, 0xa2, 0xc5, 0x77, 0x67, 0x9a, 0x24, 0x6f, 0xf1, 0xcf, 0x61, 0xb5, 0x2b,
+	0xe2, 0xc1, 0x80, 0x09, 0xfd, 0xd5, 0x8b, 0x7b, 0x8b, 0x42, 0x1f, 0xc3, 0x9a, 0x49, 0x82, 0x38,
+	0x1d, 0x34, 0x86, 0xfd, 0x62, 0x86, 0x98, 0x09, 0xf7, 0xad, 0x8b, 0x9b, 0xbf, 0x82, 0xc0, 0x93,
+	0x65, 0xdb, 0x3b, 0xa3, 0xd1, 0x7b, 0x05, 0x1f, 0xc3, 0x1d, 0x7d, 0x90, 0x9a, 0xb3, 0xd3, 0x3f,
+	0x69, 0xa7, 0x74, 0x28, 0xb6, 0x77, 0x33, 0x7c, 0x9e, 0xc1, 0xe6, 0xcc, 0x59, 0x4b, 0x7e, 0xe4,
+	0x71, 0x78, 0x57, 0x9b, 0x36, 0xc3, 0xe9, 0x67, 0xb0, 0x8c, 0xc7, 0x9a, 0xa2, 0x6a, 0xd6, 0xe5,
+	0xd5, 0x29, 0x95, 0x70, 0x45, 0x1d, 0x4b, 0x82, 0x64, 0x2a, 0x6f, 0x50, 0xf0, 0x3d, 0x5c, 0x10,
+	0x3f, 0x4d, 0xa7, 0x5b, 0xa7, 0x19, 0x81, 0x75, 0x97, 0x22, 0x27, 0x5f, 0xe2, 0x29, 0xda, 0xe5,
+	0xcf, 0xdb, 0x75, 0xe2, 0x87, 0xf3, 0x4c, 0xab, 0x53, 0x64, 0xf1, 0xea, 0xa6, 0xfe, 0xef, 0xe5,
+	0x57, 0xff, 0x1b, 0x00, 0x18, 0x85, 0x44, 0x8d, 0xb8, 0x19, 0x00, 0x00,
 }
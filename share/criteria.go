@@ -51,6 +51,8 @@ const (
 	CriteriaKeySaBindRiskyRole     string = "saBindRiskyRole"
 	CriteriaKeyImageVerifiers      string = "imageVerifiers"
 	CriteriaKeyAnnotations         string = "annotations"
+	CriteriaKeyPrivilegedSCC       string = "privilegedScc" // OpenShift SCC that allows privileged containers
+	CriteriaKeyOwner               string = "ownerRef"      // kubernetes owner in the format of kind/name, e.g. deployment/web
 )
 
 const (
@@ -81,6 +83,8 @@ const (
 	CriteriaOpExist               string = "exist"
 	CriteriaOpNotExist            string = "notExist"
 	CriteriaOpContainsTagAny      string = "containsTagAny"
+	CriteriaOpIn                  string = "in"    // comma-separated values
+	CriteriaOpNotIn               string = "notIn" // comma-separated values
 )
 
 const (
@@ -192,6 +196,8 @@ func IsWorkloadSelected(workload *CLUSWorkload, selector []CLUSCriteriaEntry, do
 			ret, positive = isCriterionMet(&crt, workload.Service)
 		case CriteriaKeyDomain, CriteriaKeyNamespace:
 			ret, positive = isCriterionMet(&crt, workload.Domain)
+		case CriteriaKeyOwner:
+			ret, positive = isCriterionMet(&crt, workload.Owner)
 		case CriteriaKeyAddress:
 			// Address criteria doesn't match workload address for now
 			return false
@@ -201,15 +207,13 @@ func IsWorkloadSelected(workload *CLUSWorkload, selector []CLUSCriteriaEntry, do
 			if strings.HasPrefix(crt.Key, "ns:") {
 				if domain != nil {
 					key = "ns-label" // create "or" combination
-					if v, ok := domain.Labels[crt.Key[3:]]; ok {
-						ret, positive = isCriterionMet(&crt, v)
-					}
+					v, ok := domain.Labels[crt.Key[3:]]
+					ret, positive = isLabelCriterionMet(&crt, v, ok)
 				}
 			} else {
 				key = "pod-label" // create "or" combination
-				if v, ok := workload.Labels[crt.Key]; ok {
-					ret, positive = isCriterionMet(&crt, v)
-				}
+				v, ok := workload.Labels[crt.Key]
+				ret, positive = isLabelCriterionMet(&crt, v, ok)
 			}
 		}
 
@@ -256,6 +260,34 @@ func EqualMatch(match, value string) bool {
 	}
 }
 
+// Like the set-based label selector of kubernetes, exist and notExist ignore the value, and notIn matches
+// the missing label. Other criteria don't match the missing label.
+func isLabelCriterionMet(crt *CLUSCriteriaEntry, value string, exist bool) (bool, bool) {
+	switch crt.Op {
+	case CriteriaOpExist:
+		return exist, true
+	case CriteriaOpNotExist:
+		return !exist, false
+	}
+
+	if !exist {
+		if crt.Op == CriteriaOpNotIn {
+			return true, false
+		}
+		return false, true
+	}
+	return isCriterionMet(crt, value)
+}
+
+func isValueInList(list, value string) bool {
+	for _, v := range strings.Split(list, ",") {
+		if EqualMatch(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
 func isCriterionMet(crt *CLUSCriteriaEntry, value string) (bool, bool) {
 	switch crt.Op {
 	case CriteriaOpEqual:
@@ -276,6 +308,10 @@ func isCriterionMet(crt *CLUSCriteriaEntry, value string) (bool, bool) {
 	case CriteriaOpNotRegex:
 		matched, _ := regexp.MatchString(crt.Value, value)
 		return !matched, false
+	case CriteriaOpIn:
+		return isValueInList(crt.Value, value), true
+	case CriteriaOpNotIn:
+		return !isValueInList(crt.Value, value), false
 	}
 
 	return false, true
@@ -65,6 +65,30 @@ func (x SnifferStatus) String() string {
 }
 func (SnifferStatus) EnumDescriptor() ([]byte, []int) { return fileDescriptor2, []int{1} }
 
+type PolicyPushCompression int32
+
+const (
+	PolicyPushCompression_CompressNone PolicyPushCompression = 0
+	PolicyPushCompression_CompressGzip PolicyPushCompression = 1
+	PolicyPushCompression_CompressZstd PolicyPushCompression = 2
+)
+
+var PolicyPushCompression_name = map[int32]string{
+	0: "CompressNone",
+	1: "CompressGzip",
+	2: "CompressZstd",
+}
+var PolicyPushCompression_value = map[string]int32{
+	"CompressNone": 0,
+	"CompressGzip": 1,
+	"CompressZstd": 2,
+}
+
+func (x PolicyPushCompression) String() string {
+	return proto.EnumName(PolicyPushCompression_name, int32(x))
+}
+func (PolicyPushCompression) EnumDescriptor() ([]byte, []int) { return fileDescriptor2, []int{2} }
+
 type CLUSKick struct {
 	CtrlID string `protobuf:"bytes,1,opt,name=CtrlID" json:"CtrlID,omitempty"`
 	Reason string `protobuf:"bytes,2,opt,name=Reason" json:"Reason,omitempty"`
@@ -2409,6 +2433,342 @@ func (m *CLUSMeterArray) GetMeters() []*CLUSMeter {
 	return nil
 }
 
+type CLUSThreatSignaturePattern struct {
+	Op      string `protobuf:"bytes,1,opt,name=Op" json:"Op,omitempty"`
+	Value   string `protobuf:"bytes,2,opt,name=Value" json:"Value,omitempty"`
+	Context string `protobuf:"bytes,3,opt,name=Context" json:"Context,omitempty"`
+}
+
+func (m *CLUSThreatSignaturePattern) Reset()                    { *m = CLUSThreatSignaturePattern{} }
+func (m *CLUSThreatSignaturePattern) String() string            { return proto.CompactTextString(m) }
+func (*CLUSThreatSignaturePattern) ProtoMessage()               {}
+func (*CLUSThreatSignaturePattern) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{43} }
+
+func (m *CLUSThreatSignaturePattern) GetOp() string {
+	if m != nil {
+		return m.Op
+	}
+	return ""
+}
+
+func (m *CLUSThreatSignaturePattern) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *CLUSThreatSignaturePattern) GetContext() string {
+	if m != nil {
+		return m.Context
+	}
+	return ""
+}
+
+type CLUSThreatSignatureEntry struct {
+	ID       uint32                        `protobuf:"varint,1,opt,name=ID" json:"ID,omitempty"`
+	Name     string                        `protobuf:"bytes,2,opt,name=Name" json:"Name,omitempty"`
+	Action   string                        `protobuf:"bytes,3,opt,name=Action" json:"Action,omitempty"`
+	Patterns []*CLUSThreatSignaturePattern `protobuf:"bytes,4,rep,name=Patterns" json:"Patterns,omitempty"`
+}
+
+func (m *CLUSThreatSignatureEntry) Reset()                    { *m = CLUSThreatSignatureEntry{} }
+func (m *CLUSThreatSignatureEntry) String() string            { return proto.CompactTextString(m) }
+func (*CLUSThreatSignatureEntry) ProtoMessage()               {}
+func (*CLUSThreatSignatureEntry) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{44} }
+
+func (m *CLUSThreatSignatureEntry) GetID() uint32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *CLUSThreatSignatureEntry) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CLUSThreatSignatureEntry) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *CLUSThreatSignatureEntry) GetPatterns() []*CLUSThreatSignaturePattern {
+	if m != nil {
+		return m.Patterns
+	}
+	return nil
+}
+
+type CLUSThreatSignatureArray struct {
+	Version     string                      `protobuf:"bytes,1,opt,name=Version" json:"Version,omitempty"`
+	Signatures  []*CLUSThreatSignatureEntry `protobuf:"bytes,2,rep,name=Signatures" json:"Signatures,omitempty"`
+	MiningPools []string                    `protobuf:"bytes,3,rep,name=MiningPools" json:"MiningPools,omitempty"`
+}
+
+func (m *CLUSThreatSignatureArray) Reset()                    { *m = CLUSThreatSignatureArray{} }
+func (m *CLUSThreatSignatureArray) String() string            { return proto.CompactTextString(m) }
+func (*CLUSThreatSignatureArray) ProtoMessage()               {}
+func (*CLUSThreatSignatureArray) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{45} }
+
+func (m *CLUSThreatSignatureArray) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *CLUSThreatSignatureArray) GetSignatures() []*CLUSThreatSignatureEntry {
+	if m != nil {
+		return m.Signatures
+	}
+	return nil
+}
+
+func (m *CLUSThreatSignatureArray) GetMiningPools() []string {
+	if m != nil {
+		return m.MiningPools
+	}
+	return nil
+}
+
+type CLUSFileBaseline struct {
+	Path       string `protobuf:"bytes,1,opt,name=Path" json:"Path,omitempty"`
+	Hash       string `protobuf:"bytes,2,opt,name=Hash" json:"Hash,omitempty"`
+	BaselineAt int64  `protobuf:"varint,3,opt,name=BaselineAt" json:"BaselineAt,omitempty"`
+	Current    string `protobuf:"bytes,4,opt,name=Current" json:"Current,omitempty"`
+	Drifted    bool   `protobuf:"varint,5,opt,name=Drifted" json:"Drifted,omitempty"`
+}
+
+func (m *CLUSFileBaseline) Reset()                    { *m = CLUSFileBaseline{} }
+func (m *CLUSFileBaseline) String() string            { return proto.CompactTextString(m) }
+func (*CLUSFileBaseline) ProtoMessage()               {}
+func (*CLUSFileBaseline) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{46} }
+
+func (m *CLUSFileBaseline) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *CLUSFileBaseline) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *CLUSFileBaseline) GetBaselineAt() int64 {
+	if m != nil {
+		return m.BaselineAt
+	}
+	return 0
+}
+
+func (m *CLUSFileBaseline) GetCurrent() string {
+	if m != nil {
+		return m.Current
+	}
+	return ""
+}
+
+func (m *CLUSFileBaseline) GetDrifted() bool {
+	if m != nil {
+		return m.Drifted
+	}
+	return false
+}
+
+type CLUSFileBaselineArray struct {
+	Baselines []*CLUSFileBaseline `protobuf:"bytes,1,rep,name=Baselines" json:"Baselines,omitempty"`
+}
+
+func (m *CLUSFileBaselineArray) Reset()                    { *m = CLUSFileBaselineArray{} }
+func (m *CLUSFileBaselineArray) String() string            { return proto.CompactTextString(m) }
+func (*CLUSFileBaselineArray) ProtoMessage()               {}
+func (*CLUSFileBaselineArray) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{47} }
+
+func (m *CLUSFileBaselineArray) GetBaselines() []*CLUSFileBaseline {
+	if m != nil {
+		return m.Baselines
+	}
+	return nil
+}
+
+type CLUSFileBaselineReset struct {
+	Workload string   `protobuf:"bytes,1,opt,name=Workload" json:"Workload,omitempty"`
+	Paths    []string `protobuf:"bytes,2,rep,name=Paths" json:"Paths,omitempty"`
+}
+
+func (m *CLUSFileBaselineReset) Reset()                    { *m = CLUSFileBaselineReset{} }
+func (m *CLUSFileBaselineReset) String() string            { return proto.CompactTextString(m) }
+func (*CLUSFileBaselineReset) ProtoMessage()               {}
+func (*CLUSFileBaselineReset) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{48} }
+
+func (m *CLUSFileBaselineReset) GetWorkload() string {
+	if m != nil {
+		return m.Workload
+	}
+	return ""
+}
+
+func (m *CLUSFileBaselineReset) GetPaths() []string {
+	if m != nil {
+		return m.Paths
+	}
+	return nil
+}
+
+type CLUSForensicBundle struct {
+	Bundle []byte `protobuf:"bytes,1,opt,name=Bundle,proto3" json:"Bundle,omitempty"`
+}
+
+func (m *CLUSForensicBundle) Reset()                    { *m = CLUSForensicBundle{} }
+func (m *CLUSForensicBundle) String() string            { return proto.CompactTextString(m) }
+func (*CLUSForensicBundle) ProtoMessage()               {}
+func (*CLUSForensicBundle) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{49} }
+
+func (m *CLUSForensicBundle) GetBundle() []byte {
+	if m != nil {
+		return m.Bundle
+	}
+	return nil
+}
+
+type CLUSPolicyPushEntry struct {
+	Key    string `protobuf:"bytes,1,opt,name=Key" json:"Key,omitempty"`
+	Value  []byte `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
+	Delete bool   `protobuf:"varint,3,opt,name=Delete" json:"Delete,omitempty"`
+}
+
+func (m *CLUSPolicyPushEntry) Reset()                    { *m = CLUSPolicyPushEntry{} }
+func (m *CLUSPolicyPushEntry) String() string            { return proto.CompactTextString(m) }
+func (*CLUSPolicyPushEntry) ProtoMessage()               {}
+func (*CLUSPolicyPushEntry) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{50} }
+
+func (m *CLUSPolicyPushEntry) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *CLUSPolicyPushEntry) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *CLUSPolicyPushEntry) GetDelete() bool {
+	if m != nil {
+		return m.Delete
+	}
+	return false
+}
+
+type CLUSPolicyPushEntryArray struct {
+	Entries []*CLUSPolicyPushEntry `protobuf:"bytes,1,rep,name=Entries" json:"Entries,omitempty"`
+}
+
+func (m *CLUSPolicyPushEntryArray) Reset()                    { *m = CLUSPolicyPushEntryArray{} }
+func (m *CLUSPolicyPushEntryArray) String() string            { return proto.CompactTextString(m) }
+func (*CLUSPolicyPushEntryArray) ProtoMessage()               {}
+func (*CLUSPolicyPushEntryArray) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{51} }
+
+func (m *CLUSPolicyPushEntryArray) GetEntries() []*CLUSPolicyPushEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+type CLUSPolicyPushBatch struct {
+	CtrlID      string                `protobuf:"bytes,1,opt,name=CtrlID" json:"CtrlID,omitempty"`
+	Epoch       uint64                `protobuf:"varint,2,opt,name=Epoch" json:"Epoch,omitempty"`
+	Seq         uint64                `protobuf:"varint,3,opt,name=Seq" json:"Seq,omitempty"`
+	Compression PolicyPushCompression `protobuf:"varint,4,opt,name=Compression,enum=share.PolicyPushCompression" json:"Compression,omitempty"`
+	Count       uint32                `protobuf:"varint,5,opt,name=Count" json:"Count,omitempty"`
+	Entries     []byte                `protobuf:"bytes,6,opt,name=Entries,proto3" json:"Entries,omitempty"`
+}
+
+func (m *CLUSPolicyPushBatch) Reset()                    { *m = CLUSPolicyPushBatch{} }
+func (m *CLUSPolicyPushBatch) String() string            { return proto.CompactTextString(m) }
+func (*CLUSPolicyPushBatch) ProtoMessage()               {}
+func (*CLUSPolicyPushBatch) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{52} }
+
+func (m *CLUSPolicyPushBatch) GetCtrlID() string {
+	if m != nil {
+		return m.CtrlID
+	}
+	return ""
+}
+
+func (m *CLUSPolicyPushBatch) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *CLUSPolicyPushBatch) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *CLUSPolicyPushBatch) GetCompression() PolicyPushCompression {
+	if m != nil {
+		return m.Compression
+	}
+	return PolicyPushCompression_CompressNone
+}
+
+func (m *CLUSPolicyPushBatch) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *CLUSPolicyPushBatch) GetEntries() []byte {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+type CLUSPolicyPushAck struct {
+	Seq    uint64 `protobuf:"varint,1,opt,name=Seq" json:"Seq,omitempty"`
+	Resync bool   `protobuf:"varint,2,opt,name=Resync" json:"Resync,omitempty"`
+}
+
+func (m *CLUSPolicyPushAck) Reset()                    { *m = CLUSPolicyPushAck{} }
+func (m *CLUSPolicyPushAck) String() string            { return proto.CompactTextString(m) }
+func (*CLUSPolicyPushAck) ProtoMessage()               {}
+func (*CLUSPolicyPushAck) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{53} }
+
+func (m *CLUSPolicyPushAck) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *CLUSPolicyPushAck) GetResync() bool {
+	if m != nil {
+		return m.Resync
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*CLUSKick)(nil), "share.CLUSKick")
 	proto.RegisterType((*CLUSFilter)(nil), "share.CLUSFilter")
@@ -2453,8 +2813,20 @@ func init() {
 	proto.RegisterType((*CLUSWorkloadIntercept)(nil), "share.CLUSWorkloadIntercept")
 	proto.RegisterType((*CLUSMeter)(nil), "share.CLUSMeter")
 	proto.RegisterType((*CLUSMeterArray)(nil), "share.CLUSMeterArray")
+	proto.RegisterType((*CLUSThreatSignaturePattern)(nil), "share.CLUSThreatSignaturePattern")
+	proto.RegisterType((*CLUSThreatSignatureEntry)(nil), "share.CLUSThreatSignatureEntry")
+	proto.RegisterType((*CLUSThreatSignatureArray)(nil), "share.CLUSThreatSignatureArray")
+	proto.RegisterType((*CLUSFileBaseline)(nil), "share.CLUSFileBaseline")
+	proto.RegisterType((*CLUSFileBaselineArray)(nil), "share.CLUSFileBaselineArray")
+	proto.RegisterType((*CLUSFileBaselineReset)(nil), "share.CLUSFileBaselineReset")
+	proto.RegisterType((*CLUSForensicBundle)(nil), "share.CLUSForensicBundle")
+	proto.RegisterType((*CLUSPolicyPushEntry)(nil), "share.CLUSPolicyPushEntry")
+	proto.RegisterType((*CLUSPolicyPushEntryArray)(nil), "share.CLUSPolicyPushEntryArray")
+	proto.RegisterType((*CLUSPolicyPushBatch)(nil), "share.CLUSPolicyPushBatch")
+	proto.RegisterType((*CLUSPolicyPushAck)(nil), "share.CLUSPolicyPushAck")
 	proto.RegisterEnum("share.SnifferCmd", SnifferCmd_name, SnifferCmd_value)
 	proto.RegisterEnum("share.SnifferStatus", SnifferStatus_name, SnifferStatus_value)
+	proto.RegisterEnum("share.PolicyPushCompression", PolicyPushCompression_name, PolicyPushCompression_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -2559,6 +2931,11 @@ type EnforcerServiceClient interface {
 	GetContainerIntercept(ctx context.Context, in *CLUSFilter, opts ...grpc.CallOption) (*CLUSWorkloadIntercept, error)
 	GetMeterList(ctx context.Context, in *CLUSFilter, opts ...grpc.CallOption) (EnforcerService_GetMeterListClient, error)
 	ProfilingCmd(ctx context.Context, in *CLUSProfilingRequest, opts ...grpc.CallOption) (*RPCVoid, error)
+	SetThreatSignatures(ctx context.Context, in *CLUSThreatSignatureArray, opts ...grpc.CallOption) (*RPCVoid, error)
+	GetFileBaseline(ctx context.Context, in *CLUSFilter, opts ...grpc.CallOption) (*CLUSFileBaselineArray, error)
+	ResetFileBaseline(ctx context.Context, in *CLUSFileBaselineReset, opts ...grpc.CallOption) (*RPCVoid, error)
+	GetForensicBundle(ctx context.Context, in *CLUSFilter, opts ...grpc.CallOption) (EnforcerService_GetForensicBundleClient, error)
+	PushPolicyBatch(ctx context.Context, in *CLUSPolicyPushBatch, opts ...grpc.CallOption) (*CLUSPolicyPushAck, error)
 }
 
 type enforcerServiceClient struct {
@@ -2904,6 +3281,74 @@ func (c *enforcerServiceClient) ProfilingCmd(ctx context.Context, in *CLUSProfil
 	return out, nil
 }
 
+func (c *enforcerServiceClient) SetThreatSignatures(ctx context.Context, in *CLUSThreatSignatureArray, opts ...grpc.CallOption) (*RPCVoid, error) {
+	out := new(RPCVoid)
+	err := grpc.Invoke(ctx, "/share.EnforcerService/SetThreatSignatures", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *enforcerServiceClient) GetFileBaseline(ctx context.Context, in *CLUSFilter, opts ...grpc.CallOption) (*CLUSFileBaselineArray, error) {
+	out := new(CLUSFileBaselineArray)
+	err := grpc.Invoke(ctx, "/share.EnforcerService/GetFileBaseline", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *enforcerServiceClient) ResetFileBaseline(ctx context.Context, in *CLUSFileBaselineReset, opts ...grpc.CallOption) (*RPCVoid, error) {
+	out := new(RPCVoid)
+	err := grpc.Invoke(ctx, "/share.EnforcerService/ResetFileBaseline", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *enforcerServiceClient) GetForensicBundle(ctx context.Context, in *CLUSFilter, opts ...grpc.CallOption) (EnforcerService_GetForensicBundleClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_EnforcerService_serviceDesc.Streams[4], c.cc, "/share.EnforcerService/GetForensicBundle", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &enforcerServiceGetForensicBundleClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EnforcerService_GetForensicBundleClient interface {
+	Recv() (*CLUSForensicBundle, error)
+	grpc.ClientStream
+}
+
+type enforcerServiceGetForensicBundleClient struct {
+	grpc.ClientStream
+}
+
+func (x *enforcerServiceGetForensicBundleClient) Recv() (*CLUSForensicBundle, error) {
+	m := new(CLUSForensicBundle)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *enforcerServiceClient) PushPolicyBatch(ctx context.Context, in *CLUSPolicyPushBatch, opts ...grpc.CallOption) (*CLUSPolicyPushAck, error) {
+	out := new(CLUSPolicyPushAck)
+	err := grpc.Invoke(ctx, "/share.EnforcerService/PushPolicyBatch", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for EnforcerService service
 
 type EnforcerServiceServer interface {
@@ -2934,6 +3379,11 @@ type EnforcerServiceServer interface {
 	GetContainerIntercept(context.Context, *CLUSFilter) (*CLUSWorkloadIntercept, error)
 	GetMeterList(*CLUSFilter, EnforcerService_GetMeterListServer) error
 	ProfilingCmd(context.Context, *CLUSProfilingRequest) (*RPCVoid, error)
+	SetThreatSignatures(context.Context, *CLUSThreatSignatureArray) (*RPCVoid, error)
+	GetFileBaseline(context.Context, *CLUSFilter) (*CLUSFileBaselineArray, error)
+	ResetFileBaseline(context.Context, *CLUSFileBaselineReset) (*RPCVoid, error)
+	GetForensicBundle(*CLUSFilter, EnforcerService_GetForensicBundleServer) error
+	PushPolicyBatch(context.Context, *CLUSPolicyPushBatch) (*CLUSPolicyPushAck, error)
 }
 
 func RegisterEnforcerServiceServer(s *grpc.Server, srv EnforcerServiceServer) {
@@ -3438,6 +3888,99 @@ func _EnforcerService_ProfilingCmd_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EnforcerService_SetThreatSignatures_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CLUSThreatSignatureArray)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnforcerServiceServer).SetThreatSignatures(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/share.EnforcerService/SetThreatSignatures",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnforcerServiceServer).SetThreatSignatures(ctx, req.(*CLUSThreatSignatureArray))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EnforcerService_GetFileBaseline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CLUSFilter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnforcerServiceServer).GetFileBaseline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/share.EnforcerService/GetFileBaseline",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnforcerServiceServer).GetFileBaseline(ctx, req.(*CLUSFilter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EnforcerService_ResetFileBaseline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CLUSFileBaselineReset)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnforcerServiceServer).ResetFileBaseline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/share.EnforcerService/ResetFileBaseline",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnforcerServiceServer).ResetFileBaseline(ctx, req.(*CLUSFileBaselineReset))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EnforcerService_GetForensicBundle_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CLUSFilter)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EnforcerServiceServer).GetForensicBundle(m, &enforcerServiceGetForensicBundleServer{stream})
+}
+
+type EnforcerService_GetForensicBundleServer interface {
+	Send(*CLUSForensicBundle) error
+	grpc.ServerStream
+}
+
+type enforcerServiceGetForensicBundleServer struct {
+	grpc.ServerStream
+}
+
+func (x *enforcerServiceGetForensicBundleServer) Send(m *CLUSForensicBundle) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _EnforcerService_PushPolicyBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CLUSPolicyPushBatch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnforcerServiceServer).PushPolicyBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/share.EnforcerService/PushPolicyBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnforcerServiceServer).PushPolicyBatch(ctx, req.(*CLUSPolicyPushBatch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _EnforcerService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "share.EnforcerService",
 	HandlerType: (*EnforcerServiceServer)(nil),
@@ -3534,6 +4077,22 @@ var _EnforcerService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ProfilingCmd",
 			Handler:    _EnforcerService_ProfilingCmd_Handler,
 		},
+		{
+			MethodName: "SetThreatSignatures",
+			Handler:    _EnforcerService_SetThreatSignatures_Handler,
+		},
+		{
+			MethodName: "GetFileBaseline",
+			Handler:    _EnforcerService_GetFileBaseline_Handler,
+		},
+		{
+			MethodName: "ResetFileBaseline",
+			Handler:    _EnforcerService_ResetFileBaseline_Handler,
+		},
+		{
+			MethodName: "PushPolicyBatch",
+			Handler:    _EnforcerService_PushPolicyBatch_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -3556,6 +4115,11 @@ var _EnforcerService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _EnforcerService_GetMeterList_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "GetForensicBundle",
+			Handler:       _EnforcerService_GetForensicBundle_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "enforcer_service.proto",
 }
@@ -3627,238 +4191,267 @@ var _EnforcerScanService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("enforcer_service.proto", fileDescriptor2) }
 
 var fileDescriptor2 = []byte{
-	// 3727 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x5a, 0x5f, 0x73, 0x1b, 0x39,
-	0x72, 0x3f, 0x92, 0xfa, 0x43, 0x42, 0xd4, 0x1f, 0x8f, 0xbd, 0xf6, 0x98, 0xd6, 0x7a, 0xb5, 0xb3,
-	0xb7, 0x17, 0x65, 0xb3, 0xe5, 0xf3, 0xe9, 0x76, 0xf7, 0x2e, 0x4e, 0xd5, 0xee, 0x52, 0xa4, 0xa4,
-	0x65, 0xad, 0xa8, 0xd0, 0xa0, 0x7c, 0x76, 0x52, 0xb9, 0x4a, 0x8d, 0x49, 0x50, 0x9a, 0x12, 0x39,
-	0x33, 0x87, 0x01, 0x65, 0x2b, 0xaf, 0x79, 0xc8, 0x53, 0x9e, 0xf3, 0x90, 0xca, 0x87, 0xc8, 0x7b,
-	0x2a, 0x55, 0xa9, 0xca, 0x53, 0x3e, 0x40, 0xde, 0x52, 0x79, 0xcf, 0x5b, 0x3e, 0x41, 0x52, 0xdd,
-	0x0d, 0xcc, 0x00, 0x43, 0x4a, 0xbe, 0x7b, 0x22, 0xfa, 0x87, 0x46, 0x03, 0x68, 0x34, 0xba, 0x1b,
-	0x3d, 0x64, 0x0f, 0x45, 0x3c, 0x49, 0xe4, 0x48, 0xc8, 0xbf, 0xce, 0x84, 0xbc, 0x8e, 0x46, 0xe2,
-	0x59, 0x2a, 0x13, 0x95, 0x78, 0xab, 0xd9, 0x65, 0x28, 0x45, 0xab, 0x39, 0x4a, 0x66, 0xb3, 0x24,
-	0x26, 0xb0, 0xc5, 0xb2, 0x51, 0xa8, 0xdb, 0xc1, 0x0b, 0x56, 0xef, 0x9c, 0xbe, 0x1a, 0xfe, 0x18,
-	0x8d, 0xae, 0xbc, 0x87, 0x6c, 0xad, 0xa3, 0xe4, 0xb4, 0xd7, 0xf5, 0x2b, 0x7b, 0x95, 0xfd, 0x06,
-	0xd7, 0x14, 0xe0, 0x5c, 0x84, 0x59, 0x12, 0xfb, 0x55, 0xc2, 0x89, 0x0a, 0xc6, 0x8c, 0xc1, 0xd8,
-	0xe3, 0x68, 0xaa, 0x84, 0xf4, 0x5a, 0xac, 0xfe, 0x3a, 0x91, 0x57, 0xd3, 0x24, 0x1c, 0xeb, 0xf1,
-	0x39, 0xed, 0x6d, 0xb1, 0x6a, 0xaf, 0x8b, 0xa3, 0x37, 0x79, 0xb5, 0xd7, 0xf5, 0x1e, 0xb0, 0xd5,
-	0xa1, 0x0a, 0xa5, 0xf2, 0x6b, 0x08, 0x11, 0x01, 0xe8, 0x69, 0x34, 0x8b, 0x94, 0xbf, 0x42, 0x28,
-	0x12, 0xc1, 0xff, 0xad, 0xb3, 0x0d, 0x98, 0x66, 0x28, 0xb2, 0x2c, 0x4a, 0x62, 0x2d, 0xab, 0x92,
-	0xcb, 0xb2, 0xe7, 0xad, 0x96, 0xe6, 0xdd, 0x65, 0x8d, 0x23, 0x75, 0x29, 0xe4, 0xf9, 0x4d, 0x2a,
-	0xf4, 0x5c, 0x05, 0xe0, 0xf9, 0x6c, 0xbd, 0x37, 0x18, 0x80, 0x1a, 0xf4, 0x8c, 0x86, 0x84, 0x71,
-	0x9d, 0x69, 0x24, 0x62, 0xd5, 0x6f, 0x77, 0xfc, 0xd5, 0xbd, 0xca, 0x7e, 0x93, 0x17, 0x00, 0xf4,
-	0x0e, 0x85, 0xbc, 0x16, 0x12, 0x7a, 0xd7, 0xa8, 0x37, 0x07, 0x60, 0x3d, 0xc4, 0xda, 0x1b, 0xf8,
-	0xeb, 0xd8, 0x99, 0xd3, 0xd0, 0x47, 0x8c, 0xbd, 0x81, 0x5f, 0xa7, 0x3e, 0x43, 0x7b, 0x4f, 0x19,
-	0x23, 0xbe, 0x41, 0x22, 0x95, 0xdf, 0xc0, 0x05, 0x59, 0x08, 0xf4, 0x13, 0x2f, 0xf6, 0x33, 0xea,
-	0x2f, 0x10, 0x90, 0xdd, 0xeb, 0xf4, 0x07, 0x9d, 0x64, 0x2c, 0xfc, 0x0d, 0xec, 0xcd, 0x69, 0xd3,
-	0x87, 0x6a, 0x68, 0x16, 0x7d, 0xa8, 0x85, 0x3d, 0xb6, 0x41, 0xb3, 0x0c, 0x55, 0xa8, 0x84, 0xbf,
-	0x89, 0xdd, 0x36, 0x04, 0x1c, 0x34, 0x0f, 0x71, 0x6c, 0x11, 0x87, 0x05, 0x59, 0x6b, 0xbf, 0x52,
-	0x99, 0xbf, 0xed, 0xac, 0xfd, 0x4a, 0x65, 0xd6, 0xda, 0xa1, 0x7f, 0xc7, 0x59, 0x3b, 0xf4, 0xe7,
-	0x6b, 0x38, 0xbc, 0x51, 0x22, 0xf3, 0xef, 0xed, 0x55, 0xf6, 0x57, 0xb8, 0x0d, 0x15, 0x6b, 0x20,
-	0x0e, 0x8f, 0x38, 0x2c, 0x08, 0x38, 0xda, 0x69, 0x3a, 0x8d, 0x46, 0xa1, 0x8a, 0x92, 0xd8, 0xbf,
-	0x4f, 0xab, 0xb4, 0x20, 0x6f, 0x87, 0xd5, 0xda, 0x17, 0xc2, 0x7f, 0x80, 0x3d, 0xd0, 0xf4, 0x3c,
-	0xb6, 0xd2, 0x1b, 0x4f, 0x85, 0xff, 0x11, 0x42, 0xd8, 0x06, 0xec, 0x34, 0x9a, 0x08, 0xff, 0x21,
-	0x61, 0xd0, 0x46, 0x4b, 0x89, 0x2f, 0xa4, 0xc8, 0x32, 0xff, 0xd1, 0x5e, 0x65, 0xbf, 0xce, 0x0d,
-	0x09, 0x32, 0xcf, 0xc3, 0xd4, 0xf7, 0x11, 0x85, 0x26, 0x20, 0xfd, 0x68, 0xec, 0x3f, 0x26, 0xa4,
-	0x1f, 0x8d, 0x41, 0xfb, 0x83, 0x64, 0x1a, 0x8d, 0x6e, 0x7a, 0x63, 0xbf, 0x45, 0xda, 0x37, 0xb4,
-	0x17, 0xb0, 0x26, 0xb5, 0xdb, 0x23, 0x5c, 0xf6, 0x13, 0xec, 0x77, 0x30, 0xef, 0xa7, 0x6c, 0x93,
-	0x54, 0xd1, 0xce, 0x66, 0xa8, 0xc0, 0x5d, 0x64, 0x72, 0x41, 0xe0, 0x22, 0x75, 0x18, 0xae, 0x8f,
-	0x89, 0xcb, 0x01, 0xbd, 0x9f, 0xb1, 0xad, 0x7c, 0x18, 0xa9, 0xf2, 0x29, 0xaa, 0xb2, 0x84, 0x02,
-	0x5f, 0x3e, 0x90, 0xf8, 0x3e, 0x21, 0x3e, 0x17, 0x85, 0xbd, 0xfd, 0x90, 0x64, 0xaa, 0x0f, 0x56,
-	0xb7, 0x87, 0x5b, 0xce, 0x69, 0xb8, 0xcf, 0x6f, 0x26, 0x93, 0xde, 0xc0, 0xff, 0x14, 0x4d, 0x9d,
-	0x08, 0xf0, 0x26, 0x6f, 0x26, 0x93, 0x76, 0x9a, 0xfa, 0x01, 0x2e, 0x50, 0x53, 0xa0, 0xe3, 0x37,
-	0x93, 0x09, 0x1a, 0xf7, 0x67, 0x74, 0x1b, 0x35, 0x19, 0x1c, 0xb2, 0x1d, 0xcb, 0x01, 0xb4, 0xa5,
-	0x0c, 0x6f, 0xbc, 0x67, 0x70, 0x93, 0x90, 0xce, 0xfc, 0xca, 0x5e, 0x6d, 0x7f, 0xe3, 0xc0, 0x7b,
-	0x86, 0xbe, 0xee, 0x99, 0xc5, 0xca, 0x73, 0x9e, 0xe0, 0x3f, 0x2b, 0xcc, 0xb3, 0x7a, 0x3a, 0xc9,
-	0x3c, 0x06, 0xa7, 0x05, 0x86, 0x37, 0x97, 0x96, 0x24, 0x32, 0xfe, 0x02, 0x42, 0x85, 0xcd, 0xe5,
-	0x79, 0x67, 0x90, 0x33, 0x91, 0x1b, 0x2b, 0xa1, 0x9a, 0xef, 0x55, 0xb7, 0xe0, 0xab, 0xe5, 0x7c,
-	0x16, 0xea, 0xed, 0xb3, 0xed, 0xce, 0x5c, 0xc2, 0xed, 0xcb, 0x19, 0xc9, 0xf9, 0x94, 0x61, 0x3c,
-	0xf6, 0xb9, 0xec, 0x15, 0x7c, 0xab, 0xfa, 0xd8, 0x6d, 0x30, 0xf8, 0x6f, 0xc6, 0xee, 0xc3, 0xc6,
-	0xba, 0xa1, 0x0a, 0xd3, 0x50, 0x5d, 0x9a, 0x9d, 0xed, 0xb2, 0x06, 0x7f, 0x33, 0x08, 0x47, 0x57,
-	0x42, 0xd1, 0xbe, 0x56, 0x78, 0x01, 0x80, 0x6c, 0xfe, 0xa6, 0x2b, 0x93, 0xd4, 0x70, 0x54, 0x91,
-	0xc3, 0x05, 0x41, 0xc6, 0x79, 0x2e, 0xa3, 0x46, 0x32, 0xce, 0x6d, 0x19, 0xe7, 0x8e, 0x8c, 0x15,
-	0x92, 0xe1, 0x80, 0x60, 0xe0, 0x47, 0x52, 0x26, 0xd2, 0x30, 0xad, 0x22, 0x93, 0x83, 0x79, 0x5f,
-	0xb2, 0x7b, 0x67, 0x89, 0x71, 0xda, 0x86, 0x71, 0x0d, 0x19, 0x17, 0x3b, 0xe0, 0xcc, 0x7a, 0x83,
-	0xeb, 0xaf, 0x0c, 0xdf, 0x3a, 0xb9, 0x02, 0x0b, 0xd2, 0x1c, 0xdf, 0x18, 0x8e, 0x7a, 0xce, 0x61,
-	0x20, 0x70, 0x48, 0xe7, 0x9d, 0x81, 0x61, 0x68, 0x20, 0x83, 0x85, 0x78, 0xcf, 0xd9, 0xfd, 0xf3,
-	0xce, 0xe0, 0x2c, 0xd1, 0x6a, 0x36, 0x8c, 0x0c, 0x19, 0x97, 0x75, 0x81, 0xc4, 0x57, 0xdd, 0x5c,
-	0xe2, 0x06, 0x49, 0x2c, 0x10, 0x5c, 0x53, 0xa7, 0x9f, 0x33, 0x34, 0xf5, 0x9a, 0x0a, 0x08, 0x34,
-	0xf5, 0xe7, 0x10, 0x9b, 0x0c, 0xcb, 0x26, 0x69, 0xca, 0xc6, 0xe0, 0x44, 0x8e, 0x65, 0x78, 0x31,
-	0x13, 0xb1, 0xca, 0xd0, 0x11, 0xaf, 0xf0, 0x02, 0xf0, 0xbe, 0x60, 0x3b, 0xe7, 0xd1, 0x4c, 0x24,
-	0x73, 0x55, 0x30, 0x6d, 0x23, 0xd3, 0x02, 0x8e, 0xa7, 0x97, 0xa8, 0x70, 0x9a, 0x5b, 0xd7, 0x8e,
-	0x3e, 0x3d, 0x1b, 0x84, 0x55, 0xdb, 0xa6, 0xaf, 0x1d, 0xb3, 0x6d, 0xf7, 0x7b, 0x6c, 0xc3, 0x36,
-	0x7a, 0xed, 0x98, 0x6d, 0x8b, 0x0f, 0x58, 0xd3, 0x31, 0xf7, 0xfb, 0xb4, 0x2f, 0xc7, 0xd6, 0x9f,
-	0x32, 0x66, 0x19, 0xfa, 0x03, 0xd2, 0x5e, 0xcf, 0xe9, 0x07, 0xa3, 0xea, 0x0b, 0x25, 0x64, 0x86,
-	0xee, 0x7a, 0x85, 0x5b, 0x08, 0xac, 0x62, 0x20, 0x93, 0xf7, 0x37, 0x9a, 0xe1, 0x21, 0xad, 0xc2,
-	0x82, 0x30, 0xa4, 0xcf, 0xa5, 0xee, 0x7f, 0x44, 0x9a, 0xcb, 0x01, 0x58, 0x63, 0x67, 0x2e, 0x4f,
-	0x93, 0x8b, 0x4e, 0x38, 0xba, 0x14, 0x19, 0xfa, 0xf3, 0x15, 0xee, 0x60, 0x70, 0xc3, 0x8f, 0xa5,
-	0x10, 0xe3, 0x42, 0xb7, 0x8f, 0xc9, 0x25, 0xba, 0x28, 0xcc, 0xd4, 0xce, 0x32, 0x31, 0x7b, 0x3b,
-	0xbd, 0xc9, 0xd0, 0xdf, 0xaf, 0xf0, 0x02, 0xc8, 0xa5, 0x14, 0x2c, 0x4f, 0x2c, 0x29, 0x0e, 0xdf,
-	0x20, 0x94, 0x99, 0x28, 0x9c, 0xd3, 0xee, 0x5e, 0x0d, 0xf8, 0x5c, 0x14, 0xce, 0x91, 0x10, 0x63,
-	0x36, 0x1f, 0x23, 0x9b, 0x0b, 0x82, 0x65, 0x50, 0x48, 0x81, 0x90, 0xff, 0x0b, 0x3e, 0x9f, 0x6a,
-	0xc7, 0xbf, 0xc9, 0x17, 0x70, 0x97, 0xf7, 0x80, 0x78, 0x3f, 0x29, 0xf3, 0x12, 0x8e, 0xb3, 0x23,
-	0xd6, 0x4d, 0x66, 0x61, 0x14, 0x67, 0x18, 0x03, 0x36, 0xb9, 0x0b, 0x82, 0xcf, 0xb3, 0x81, 0xde,
-	0x20, 0xc3, 0x90, 0xb0, 0xc9, 0xcb, 0x30, 0x9c, 0xf3, 0x49, 0xc2, 0x93, 0xb9, 0x8a, 0x62, 0x91,
-	0xe9, 0x00, 0x61, 0x21, 0x18, 0x9c, 0xb3, 0x64, 0x82, 0x11, 0xa2, 0xc9, 0xb1, 0x0d, 0x09, 0xe1,
-	0x60, 0xe8, 0xff, 0x14, 0x91, 0xea, 0x60, 0x08, 0x9a, 0xc3, 0xcc, 0x11, 0xcc, 0xa3, 0x93, 0xc4,
-	0x71, 0xe6, 0x7f, 0x4e, 0x1a, 0x76, 0xd1, 0x9c, 0x6f, 0x10, 0x66, 0x19, 0xf1, 0xfd, 0xcc, 0xe2,
-	0xcb, 0xd1, 0xe0, 0x0d, 0x7b, 0x80, 0x0e, 0x56, 0xc8, 0xe8, 0x5a, 0x8c, 0x75, 0x64, 0x4e, 0x31,
-	0xd0, 0x43, 0x14, 0xab, 0xe8, 0x74, 0x22, 0x4d, 0x21, 0xb4, 0xe9, 0x30, 0x4e, 0x31, 0x42, 0x53,
-	0x98, 0x40, 0xcf, 0xa7, 0xa2, 0xd7, 0xd5, 0x31, 0x41, 0x53, 0xc1, 0xbf, 0x56, 0xd9, 0x47, 0x0b,
-	0xa2, 0xa1, 0x6f, 0x21, 0xc9, 0x85, 0x84, 0x59, 0x8e, 0x7a, 0x03, 0x14, 0xdc, 0xe4, 0x44, 0x00,
-	0xda, 0xcd, 0x20, 0xcf, 0xac, 0x11, 0x8a, 0x04, 0xcc, 0x86, 0xdd, 0x1c, 0x1d, 0x72, 0x93, 0x6b,
-	0x0a, 0x70, 0x64, 0xe0, 0x3a, 0xa3, 0xd5, 0x14, 0xe8, 0x14, 0xa3, 0xee, 0x1a, 0x25, 0x3c, 0x98,
-	0x4c, 0x3e, 0x60, 0xab, 0xf0, 0xcb, 0xd1, 0xbb, 0x6e, 0x72, 0x22, 0xec, 0x84, 0xb9, 0xee, 0x26,
-	0xcc, 0xc5, 0xce, 0x1b, 0xce, 0xce, 0xad, 0xc4, 0x89, 0xb9, 0x89, 0x93, 0xc7, 0x56, 0x8e, 0x5f,
-	0x76, 0xcf, 0xd0, 0x53, 0x36, 0x38, 0xb6, 0xbd, 0x9f, 0xb3, 0x95, 0x76, 0x9a, 0x82, 0x73, 0x84,
-	0x80, 0xfe, 0xc4, 0x0a, 0xe8, 0x65, 0xe5, 0x73, 0x64, 0x0c, 0x06, 0xac, 0xb5, 0x54, 0x7f, 0x94,
-	0x23, 0x1c, 0xb0, 0x55, 0xb2, 0x5e, 0x4a, 0x10, 0x76, 0x6f, 0x93, 0x07, 0x4c, 0x9c, 0x58, 0x83,
-	0x7f, 0xab, 0x30, 0x7f, 0x29, 0x43, 0x3f, 0x4c, 0xbd, 0x63, 0xb6, 0xae, 0x9b, 0x5a, 0xe4, 0x97,
-	0x77, 0x89, 0xec, 0x87, 0xe9, 0x33, 0xfd, 0x7b, 0x14, 0x2b, 0x79, 0xc3, 0xcd, 0xe0, 0xd6, 0x6f,
-	0x59, 0xd3, 0xee, 0x00, 0x4b, 0xba, 0x12, 0x37, 0xfa, 0xd5, 0x04, 0x4d, 0xef, 0x57, 0x6c, 0xf5,
-	0x3a, 0x9c, 0xce, 0x05, 0x9e, 0xf7, 0xc6, 0xc1, 0xa7, 0x77, 0xcd, 0x83, 0x9b, 0xe5, 0xc4, 0xff,
-	0xa2, 0xfa, 0xeb, 0x4a, 0xf0, 0x2f, 0x75, 0x4a, 0x98, 0x06, 0x32, 0x79, 0x2b, 0x86, 0xf3, 0xd9,
-	0x2c, 0x94, 0x37, 0xe8, 0xe1, 0x92, 0x58, 0x85, 0x51, 0x2c, 0x24, 0x6d, 0x00, 0x13, 0x4d, 0x1b,
-	0xc3, 0x7b, 0x1a, 0x8d, 0x1d, 0xb6, 0xaa, 0xbe, 0xa7, 0x2e, 0x0c, 0xf7, 0x74, 0x10, 0x8d, 0x07,
-	0x32, 0x19, 0x01, 0x13, 0x59, 0xb5, 0x85, 0xc0, 0x6c, 0x67, 0xe2, 0x1d, 0x50, 0x22, 0xcb, 0x84,
-	0x49, 0x71, 0x1c, 0x0c, 0x7c, 0xc7, 0x99, 0x78, 0x37, 0x9c, 0x67, 0x69, 0x34, 0x02, 0xd4, 0xe4,
-	0x37, 0x0e, 0x88, 0x79, 0x95, 0x99, 0x79, 0xa8, 0x92, 0x34, 0xd3, 0x76, 0x5a, 0x42, 0xbd, 0x2f,
-	0xd8, 0xd6, 0xeb, 0xd3, 0xa1, 0x4a, 0x64, 0x78, 0x21, 0x5e, 0x87, 0x6a, 0x74, 0x49, 0xa6, 0x7b,
-	0x58, 0xf5, 0x2b, 0xbc, 0xd4, 0x03, 0xd6, 0x3a, 0x88, 0xc6, 0x43, 0xa1, 0xb4, 0x19, 0x6b, 0x0a,
-	0x56, 0xad, 0xfd, 0xea, 0x79, 0xf8, 0x76, 0x2a, 0xb4, 0x2d, 0x3b, 0x18, 0xac, 0xa7, 0x17, 0x27,
-	0x2a, 0x9a, 0xdc, 0xa0, 0x2c, 0x91, 0xe9, 0xa7, 0x58, 0x09, 0x45, 0xff, 0x2d, 0x93, 0xd1, 0xe1,
-	0x34, 0x19, 0x5d, 0xf1, 0x24, 0xd1, 0x39, 0xc1, 0x26, 0x2f, 0xa1, 0x0e, 0x5f, 0x3f, 0x94, 0x57,
-	0x99, 0x7e, 0xa0, 0x95, 0x50, 0xc8, 0x91, 0x72, 0x04, 0xad, 0xa6, 0x13, 0x2b, 0xfd, 0x58, 0x5b,
-	0xec, 0xf0, 0x9e, 0x31, 0x2f, 0x07, 0xbb, 0x91, 0xec, 0x27, 0x31, 0xb0, 0xd3, 0xcb, 0x6d, 0x49,
-	0x0f, 0x9c, 0xc5, 0x71, 0x34, 0x15, 0xfd, 0x24, 0x3e, 0xba, 0xce, 0xd3, 0x86, 0x4d, 0xee, 0x82,
-	0x16, 0xd7, 0x89, 0x4c, 0xe6, 0xa9, 0x79, 0xc9, 0xb9, 0x20, 0x46, 0x38, 0x02, 0x8e, 0x43, 0xda,
-	0xf9, 0x3d, 0xda, 0x91, 0x8b, 0xc2, 0x8e, 0x72, 0xa4, 0x1f, 0x2b, 0x62, 0xf5, 0x68, 0x47, 0x0b,
-	0x1d, 0x0e, 0x37, 0xac, 0x1b, 0x55, 0x75, 0xbf, 0xc4, 0x6d, 0x3a, 0xdc, 0x35, 0xa0, 0x0f, 0x78,
-	0x50, 0x5e, 0x03, 0xc6, 0x2f, 0x9b, 0x6f, 0x10, 0xaa, 0xcb, 0x4c, 0x3f, 0x05, 0x4b, 0xa8, 0xb5,
-	0x73, 0x9c, 0x24, 0xd3, 0xaf, 0x43, 0x17, 0x04, 0xfb, 0xd1, 0x40, 0x2f, 0x7e, 0x3d, 0xa6, 0x34,
-	0x63, 0x93, 0x3b, 0x98, 0x35, 0x63, 0x2f, 0xa6, 0x19, 0x7d, 0x67, 0x46, 0x8d, 0x5a, 0x33, 0xf6,
-	0x62, 0x9c, 0xf1, 0xb1, 0x33, 0x23, 0x81, 0xa0, 0x95, 0x7e, 0xf8, 0xfe, 0xe8, 0x3a, 0x9c, 0x76,
-	0x2e, 0xc3, 0xf8, 0xe5, 0x5c, 0xcc, 0x85, 0x79, 0x63, 0x2e, 0x76, 0x80, 0xcc, 0x7e, 0xf8, 0xfe,
-	0x24, 0x91, 0x26, 0xc0, 0xd2, 0x6b, 0xd3, 0x05, 0x83, 0xff, 0xa9, 0x58, 0xee, 0x43, 0x5f, 0x57,
-	0x70, 0x51, 0x83, 0x88, 0x0a, 0x3b, 0xab, 0x1c, 0x9a, 0x18, 0x36, 0xd2, 0x88, 0x6a, 0x2e, 0xab,
-	0x1c, 0xdb, 0x80, 0x9d, 0x85, 0x33, 0x2a, 0xb5, 0x34, 0x38, 0xb6, 0x01, 0xe3, 0xf3, 0x68, 0xac,
-	0x5d, 0x00, 0xb6, 0x01, 0x3b, 0x02, 0x8c, 0x6e, 0x3c, 0xb6, 0xb1, 0xaa, 0x32, 0x0a, 0x63, 0x48,
-	0x54, 0xcd, 0x1d, 0x2f, 0x00, 0xec, 0x55, 0xa1, 0x54, 0x40, 0xe9, 0x94, 0xbf, 0x00, 0xe0, 0x15,
-	0xca, 0x45, 0x9a, 0x48, 0x25, 0xc6, 0xfa, 0x4a, 0xe7, 0x34, 0x26, 0x7e, 0xc6, 0x55, 0xe0, 0x8d,
-	0x6e, 0xf0, 0x02, 0x08, 0xce, 0x28, 0x02, 0xdb, 0x7b, 0xa5, 0xe0, 0xf1, 0x35, 0x6b, 0x14, 0xee,
-	0x8b, 0xbc, 0xfd, 0x23, 0xcb, 0x0b, 0xdb, 0x03, 0x78, 0xc1, 0x19, 0xc4, 0xf4, 0xcc, 0xc4, 0xee,
-	0x7c, 0x16, 0x0c, 0xe7, 0xa6, 0x2a, 0x56, 0xed, 0x8d, 0x8d, 0x36, 0xab, 0x85, 0x36, 0x5b, 0xac,
-	0xde, 0xb9, 0x8c, 0xa6, 0x63, 0x29, 0x62, 0xbf, 0xb6, 0x57, 0xdb, 0x5f, 0xe5, 0x39, 0x4d, 0xf5,
-	0x03, 0xa9, 0x32, 0x70, 0xb5, 0x2b, 0x54, 0xe1, 0x32, 0x74, 0x70, 0xce, 0x1e, 0x2d, 0xce, 0x47,
-	0x3b, 0xf8, 0x53, 0xc6, 0x72, 0xc4, 0x6c, 0xe1, 0x71, 0x79, 0x0b, 0x39, 0x07, 0xb7, 0x98, 0x83,
-	0xbf, 0xad, 0xd0, 0xa3, 0x52, 0x1b, 0x5b, 0xa4, 0x12, 0x09, 0x4d, 0x3c, 0xf3, 0x50, 0x5d, 0xea,
-	0x9d, 0x60, 0x1b, 0xb0, 0x7e, 0x98, 0x5d, 0xe9, 0x17, 0x24, 0xb6, 0x21, 0x7d, 0xe8, 0x65, 0xdd,
-	0x48, 0xa2, 0x21, 0xd4, 0x39, 0x11, 0x90, 0x0c, 0x40, 0xb6, 0x20, 0x46, 0x54, 0xe1, 0xab, 0x73,
-	0x43, 0x02, 0x3f, 0xc8, 0x87, 0xd7, 0x61, 0x6d, 0xbf, 0xc1, 0x89, 0x08, 0x4e, 0x29, 0x14, 0x97,
-	0x16, 0x41, 0x9b, 0x7b, 0x6e, 0x46, 0xd0, 0xbe, 0x5a, 0xd6, 0xbe, 0x4a, 0xfc, 0x46, 0xda, 0xff,
-	0x9a, 0x0a, 0x40, 0x1c, 0x4d, 0x26, 0x42, 0x72, 0xf1, 0xbb, 0xb9, 0xc8, 0x94, 0xf7, 0x19, 0xab,
-	0x75, 0x66, 0x74, 0x36, 0x5b, 0x07, 0xf7, 0xb4, 0x18, 0xcd, 0xd3, 0x99, 0x8d, 0x39, 0xf4, 0x5a,
-	0xf5, 0xcb, 0x06, 0xa6, 0x63, 0x4f, 0x19, 0x33, 0xaf, 0x52, 0x9d, 0xd4, 0x35, 0xb8, 0x85, 0x40,
-	0x3f, 0x4c, 0x7a, 0x36, 0x9f, 0xbd, 0x15, 0x52, 0x5b, 0xbe, 0x85, 0x18, 0x47, 0x31, 0x8c, 0xfe,
-	0x46, 0xf4, 0xe2, 0xfe, 0xa1, 0xbe, 0x07, 0x0e, 0x06, 0x41, 0x8a, 0x2a, 0xab, 0x78, 0x19, 0x1a,
-	0x5c, 0x53, 0x90, 0x9e, 0x77, 0xe7, 0x12, 0x2b, 0x5a, 0xbd, 0x78, 0x28, 0x46, 0x49, 0x3c, 0xd6,
-	0x59, 0xda, 0x02, 0x1e, 0x7c, 0x4e, 0xc7, 0x98, 0x6f, 0x39, 0x4b, 0x93, 0x38, 0xb3, 0xb3, 0x4b,
-	0xdc, 0x4e, 0xf0, 0x1d, 0xbb, 0x67, 0xb1, 0xe9, 0x79, 0x4a, 0x4c, 0x77, 0xd5, 0x59, 0x83, 0xbf,
-	0xab, 0xea, 0x1a, 0x2d, 0x49, 0x58, 0x18, 0xeb, 0xb3, 0xf5, 0xf6, 0x85, 0x88, 0x55, 0xae, 0x44,
-	0x43, 0x7e, 0x50, 0x93, 0x5f, 0xb2, 0xb5, 0xa1, 0x0a, 0xd5, 0x9c, 0x52, 0x88, 0xad, 0x83, 0x07,
-	0xee, 0x09, 0x51, 0x1f, 0xd7, 0x3c, 0x60, 0x8b, 0x6d, 0x79, 0x41, 0x45, 0x86, 0x06, 0xc7, 0x76,
-	0xe9, 0x2c, 0xd6, 0x16, 0xce, 0xc2, 0x63, 0x2b, 0xa0, 0x73, 0xd4, 0x61, 0x8d, 0x63, 0xdb, 0xf5,
-	0x36, 0x75, 0xec, 0x70, 0xbd, 0x0d, 0xe4, 0x1c, 0xd8, 0xd9, 0xc0, 0xce, 0x9c, 0xce, 0x6b, 0x55,
-	0xb4, 0xbc, 0xbc, 0x56, 0x95, 0x11, 0xbd, 0xb4, 0x56, 0xa5, 0x0f, 0x27, 0xe7, 0x29, 0x9d, 0x5a,
-	0x37, 0x79, 0x17, 0x5b, 0x45, 0xf4, 0xe2, 0xd4, 0x3e, 0x67, 0xdb, 0x16, 0xdb, 0x60, 0x14, 0xa6,
-	0x78, 0x3f, 0x47, 0x3a, 0xb9, 0x6b, 0x72, 0x6c, 0x07, 0x2f, 0x49, 0x5a, 0x7e, 0xbb, 0x4f, 0x93,
-	0x0b, 0x2e, 0x7e, 0xb7, 0xe0, 0x92, 0xf2, 0x92, 0x3c, 0x39, 0xa5, 0x72, 0x49, 0xbe, 0x66, 0x97,
-	0xe4, 0xff, 0x64, 0x99, 0xc8, 0x0c, 0x99, 0x93, 0x8b, 0xbf, 0x7c, 0xab, 0xa7, 0x27, 0x22, 0xf8,
-	0x27, 0x6d, 0x1b, 0x26, 0x92, 0x98, 0x18, 0x51, 0xb1, 0x62, 0x84, 0xe5, 0x0f, 0x37, 0x8b, 0xe8,
-	0x02, 0x50, 0x4d, 0x3f, 0x4a, 0x0c, 0x76, 0x52, 0x44, 0x12, 0x68, 0x23, 0x36, 0x2c, 0x22, 0x09,
-	0xb4, 0x31, 0xe2, 0xbc, 0x8a, 0xc6, 0xe6, 0x41, 0x03, 0x6d, 0x8c, 0x38, 0x80, 0xad, 0xeb, 0x88,
-	0xa3, 0xb1, 0xce, 0x6c, 0x9c, 0xf9, 0x75, 0x74, 0x3a, 0xd8, 0xc6, 0xb1, 0x49, 0x42, 0xf5, 0xf7,
-	0x3a, 0xc7, 0x36, 0x60, 0xaf, 0x32, 0x21, 0x31, 0xd1, 0x6b, 0x70, 0x6c, 0xe3, 0x23, 0x8b, 0xec,
-	0x92, 0x1e, 0x30, 0xc6, 0x02, 0x7d, 0xb6, 0x8e, 0x9a, 0x6b, 0x2b, 0xcc, 0xe3, 0x6a, 0xdc, 0x90,
-	0xd6, 0x13, 0x69, 0x93, 0x46, 0x10, 0x15, 0x74, 0xf3, 0x68, 0x5b, 0x04, 0x9f, 0xe7, 0x8b, 0xc1,
-	0xc7, 0x73, 0x3d, 0x77, 0x39, 0xee, 0x7c, 0x4f, 0xce, 0x4d, 0x3f, 0x0e, 0xba, 0xd3, 0x14, 0x9f,
-	0x91, 0xcb, 0x74, 0x7d, 0xcb, 0x23, 0x35, 0xf8, 0xe7, 0x2a, 0x85, 0x12, 0x57, 0x04, 0xad, 0x07,
-	0x7c, 0x7c, 0x32, 0xce, 0xe5, 0x60, 0x75, 0x17, 0x9e, 0x93, 0x62, 0xd2, 0x1e, 0x29, 0x23, 0x87,
-	0x28, 0xb8, 0x1d, 0xed, 0x34, 0x9d, 0xde, 0x18, 0xf7, 0xbf, 0xca, 0x73, 0x1a, 0xc6, 0xbc, 0x9e,
-	0xf6, 0xc3, 0x11, 0xdc, 0x66, 0xd0, 0xb9, 0xa6, 0xbc, 0xaf, 0x59, 0x5d, 0xcf, 0x47, 0x21, 0xc0,
-	0x0d, 0x54, 0xee, 0x8a, 0x78, 0xce, 0x0a, 0xc3, 0x5e, 0x87, 0x13, 0x1a, 0xb6, 0xf6, 0xc1, 0x61,
-	0x86, 0x15, 0x76, 0x23, 0xa3, 0x71, 0xe6, 0xaf, 0xef, 0xd5, 0xc0, 0x16, 0xa0, 0x0d, 0xe7, 0xf6,
-	0x2e, 0x9c, 0x20, 0x5c, 0x47, 0xd8, 0x90, 0x98, 0x5b, 0xcc, 0xa7, 0x02, 0xbf, 0x9d, 0x50, 0xfa,
-	0x90, 0xd3, 0xc1, 0xbf, 0x57, 0x9c, 0x07, 0xbc, 0x9e, 0x0a, 0x1e, 0x40, 0xa7, 0x8c, 0x15, 0xd4,
-	0xed, 0xaf, 0xc5, 0x82, 0xe7, 0x59, 0xd1, 0xa4, 0xd7, 0xa2, 0x35, 0xbe, 0xf5, 0x5b, 0xb6, 0x5d,
-	0xea, 0x5e, 0xf2, 0x66, 0xfc, 0xca, 0x7d, 0x33, 0x3e, 0xbd, 0x75, 0xb6, 0x85, 0x07, 0xe3, 0x5f,
-	0x2c, 0x3b, 0x79, 0x9a, 0x66, 0x99, 0x05, 0x95, 0xbf, 0xe6, 0x41, 0x7e, 0x12, 0x2a, 0x25, 0x24,
-	0x16, 0xbd, 0x6b, 0x98, 0x9f, 0x68, 0x3a, 0x98, 0xb0, 0xdd, 0x5b, 0x44, 0x93, 0x65, 0x1d, 0xb3,
-	0x2d, 0x0b, 0x8c, 0x72, 0x73, 0xbf, 0x7d, 0xf5, 0xa4, 0x9d, 0xd2, 0xa8, 0xe0, 0x8f, 0x97, 0x1f,
-	0xc4, 0x08, 0x3f, 0xc7, 0x84, 0x23, 0xa3, 0xa7, 0x7e, 0x38, 0x0a, 0xfe, 0xca, 0x29, 0x1a, 0x14,
-	0xac, 0xb4, 0xa0, 0x6f, 0xd9, 0x46, 0x01, 0xdd, 0x51, 0x3a, 0x28, 0x98, 0xb8, 0x3d, 0x20, 0xf8,
-	0x8f, 0x0a, 0x7b, 0x68, 0x3f, 0xd3, 0xf5, 0x55, 0xbd, 0xed, 0x36, 0x9a, 0x8c, 0xaa, 0x6a, 0x65,
-	0x54, 0xc5, 0x0d, 0xad, 0xd9, 0x9e, 0x02, 0x33, 0x59, 0x29, 0x42, 0x25, 0xc6, 0x6d, 0xa5, 0x8b,
-	0xed, 0x05, 0x00, 0xa7, 0xf0, 0x2a, 0x1d, 0x87, 0x4a, 0xb4, 0x95, 0x2e, 0xb2, 0xe7, 0x34, 0x8c,
-	0xc4, 0xc7, 0x19, 0x4e, 0x4f, 0xe9, 0x44, 0x01, 0x80, 0xed, 0x77, 0x26, 0x17, 0x68, 0xe0, 0xeb,
-	0x14, 0x9d, 0x35, 0x19, 0x70, 0xf6, 0x64, 0xf9, 0x5e, 0x48, 0x57, 0xbf, 0x74, 0x0b, 0x2c, 0x1f,
-	0x2f, 0xa9, 0x52, 0x14, 0x43, 0x4c, 0x85, 0xe5, 0xbf, 0x74, 0x6e, 0xa9, 0x39, 0x30, 0x45, 0x03,
-	0xed, 0xec, 0xb2, 0x06, 0x17, 0xa3, 0xb9, 0xcc, 0xa2, 0x6b, 0x52, 0x51, 0x9d, 0x17, 0x80, 0x95,
-	0x0d, 0x55, 0x9d, 0x6c, 0xc8, 0xe8, 0xaf, 0x66, 0xe9, 0xef, 0x01, 0x5b, 0xe5, 0xe2, 0x42, 0xbc,
-	0xd7, 0xc9, 0x32, 0x11, 0xa0, 0x9f, 0x43, 0x71, 0x19, 0x5e, 0x47, 0x89, 0xd4, 0xf9, 0x41, 0x4e,
-	0x7f, 0x40, 0x3f, 0x9e, 0x2e, 0x4b, 0xad, 0x53, 0x9c, 0x80, 0xb6, 0xad, 0xb3, 0xba, 0xab, 0xb3,
-	0x53, 0xa7, 0x80, 0x64, 0xb6, 0x97, 0x67, 0xad, 0xb6, 0xc2, 0x5a, 0x8b, 0x0a, 0x33, 0xfc, 0x46,
-	0x5b, 0xff, 0x58, 0x65, 0x8f, 0xa1, 0x3b, 0x4f, 0x89, 0x62, 0x25, 0xe4, 0x48, 0xa4, 0xf4, 0x4d,
-	0xd8, 0x94, 0xee, 0x4c, 0x3e, 0x6e, 0x30, 0x91, 0xeb, 0x09, 0xdb, 0x78, 0x09, 0xda, 0x1d, 0x5d,
-	0x26, 0x84, 0x26, 0xe8, 0xe8, 0x55, 0x07, 0x30, 0xaa, 0x11, 0x12, 0x01, 0xe8, 0x61, 0xa7, 0xf8,
-	0xe6, 0x4d, 0x04, 0xe8, 0xbe, 0x17, 0xe7, 0x25, 0xc2, 0x06, 0xd7, 0x14, 0xe0, 0x47, 0xef, 0x11,
-	0x27, 0xb3, 0xd1, 0x14, 0x7e, 0xea, 0x40, 0x0e, 0xda, 0x2b, 0xe9, 0xc7, 0x86, 0x80, 0x83, 0x78,
-	0x89, 0x83, 0xdc, 0xaa, 0x0d, 0xc1, 0x53, 0xf5, 0x48, 0xff, 0xa5, 0x81, 0x78, 0x28, 0xf8, 0xba,
-	0x60, 0xf0, 0x0f, 0xda, 0xff, 0x2e, 0x68, 0x67, 0x21, 0x03, 0xc5, 0x3d, 0x4c, 0xa3, 0x98, 0xbc,
-	0x63, 0x9d, 0x6b, 0x0a, 0xb2, 0xc3, 0x97, 0xf3, 0x50, 0x86, 0x31, 0xbc, 0x7d, 0xf5, 0x73, 0xc5,
-	0x42, 0xbc, 0x6f, 0xa8, 0x10, 0x4a, 0x01, 0x6b, 0xe3, 0x60, 0xcf, 0x3a, 0xb1, 0xa5, 0x47, 0x42,
-	0xa5, 0xd2, 0x0c, 0x32, 0xe2, 0x06, 0x30, 0xe1, 0xf7, 0x05, 0xb0, 0x16, 0x6c, 0xe4, 0x35, 0x5d,
-	0x43, 0xde, 0xf9, 0xef, 0x85, 0x87, 0x6c, 0x0d, 0x4e, 0x2f, 0xaf, 0xef, 0x6a, 0x0a, 0x4e, 0x09,
-	0xbf, 0xf2, 0x99, 0xff, 0x49, 0x20, 0x01, 0x36, 0x7c, 0x1a, 0x66, 0x8a, 0x7a, 0x28, 0x1d, 0x2a,
-	0x80, 0xfc, 0x4b, 0xf7, 0x9a, 0xfb, 0xa5, 0x7b, 0x98, 0x86, 0xb1, 0xc9, 0x89, 0xa0, 0x8d, 0x9f,
-	0xb1, 0xd2, 0x54, 0x48, 0xca, 0xfa, 0xe8, 0x2d, 0x6d, 0x21, 0xd0, 0x7f, 0x9a, 0xbc, 0x33, 0xfd,
-	0xfa, 0x5f, 0x0a, 0x05, 0x62, 0xbe, 0x87, 0xb3, 0xfc, 0x7b, 0x78, 0xf0, 0x82, 0x6d, 0xe5, 0x8a,
-	0xa0, 0x5b, 0xb0, 0xcf, 0xd6, 0xf4, 0x77, 0x18, 0xba, 0x06, 0x3b, 0x96, 0x52, 0xb1, 0x83, 0xeb,
-	0xfe, 0x2f, 0x0e, 0x19, 0x2b, 0x9e, 0x62, 0xde, 0x0e, 0x6b, 0x62, 0x32, 0xa5, 0xa1, 0x9d, 0x9f,
-	0x78, 0xdb, 0x6c, 0x03, 0x32, 0x6f, 0x03, 0x54, 0xbc, 0x7b, 0x6c, 0x93, 0x8b, 0x59, 0x72, 0x2d,
-	0x0c, 0x54, 0xfd, 0xe2, 0x6b, 0xb6, 0xe9, 0x3c, 0x16, 0x3c, 0xc6, 0xd6, 0x8e, 0xc3, 0x68, 0x2a,
-	0xc6, 0x3b, 0x3f, 0xf1, 0x36, 0xd8, 0x3a, 0x9f, 0xc7, 0x71, 0x14, 0x5f, 0xec, 0x54, 0x80, 0x00,
-	0x69, 0xa9, 0x18, 0xef, 0x54, 0x0f, 0x4e, 0x99, 0x67, 0x6c, 0xad, 0x13, 0xa6, 0x43, 0xfa, 0x57,
-	0x8d, 0xf7, 0x0d, 0xdb, 0xe9, 0x65, 0x27, 0x7c, 0xd0, 0xe9, 0x24, 0xb3, 0x54, 0x42, 0xea, 0x35,
-	0xf6, 0xb6, 0xf4, 0xf2, 0xf9, 0xa0, 0xf3, 0x9b, 0x24, 0x1a, 0xb7, 0xec, 0x4c, 0xed, 0x30, 0x49,
-	0xa6, 0x22, 0x8c, 0x0f, 0xfe, 0x7e, 0x8b, 0x6d, 0x1b, 0x71, 0x46, 0xd6, 0x1f, 0xb1, 0x15, 0xfc,
-	0xdb, 0xcd, 0xb6, 0xc5, 0x0f, 0x40, 0xab, 0x24, 0xd0, 0xfb, 0x96, 0x6d, 0x9d, 0x08, 0xa5, 0xab,
-	0x90, 0xa7, 0x51, 0xa6, 0xbc, 0x7b, 0xee, 0x73, 0x57, 0x09, 0xd9, 0x7a, 0xb4, 0xf8, 0xf9, 0x1b,
-	0xb5, 0xfd, 0xbc, 0xe2, 0xfd, 0x82, 0x35, 0x3b, 0x53, 0x11, 0x9a, 0x6f, 0x46, 0xcb, 0x46, 0x97,
-	0xa7, 0xfc, 0x39, 0xab, 0xc3, 0x94, 0x2a, 0x54, 0xd9, 0x32, 0x76, 0xfb, 0xc4, 0x88, 0xe9, 0x5b,
-	0x76, 0xaf, 0x58, 0xa3, 0xf9, 0x06, 0x5d, 0xd6, 0xcc, 0xe3, 0xc5, 0x35, 0x1a, 0xd6, 0xef, 0x99,
-	0x77, 0x22, 0x54, 0xf9, 0x23, 0x76, 0x59, 0x80, 0xe3, 0x30, 0x4b, 0xbc, 0x3f, 0xb2, 0x8f, 0x40,
-	0x42, 0xb9, 0x3a, 0xbe, 0x74, 0xfd, 0x9f, 0x7c, 0xa0, 0x6e, 0xef, 0xfd, 0x8a, 0x35, 0x9d, 0xea,
-	0x79, 0x79, 0x21, 0x0b, 0xa5, 0x20, 0xc3, 0xf8, 0x1d, 0xdb, 0xb6, 0x4b, 0x43, 0x20, 0xab, 0x3c,
-	0x76, 0xf7, 0x96, 0x32, 0x12, 0x5d, 0x8e, 0x0e, 0xd6, 0x79, 0xad, 0xc2, 0xcc, 0x32, 0x11, 0x4f,
-	0x6f, 0x2d, 0xe3, 0x18, 0x21, 0xf6, 0xbd, 0x79, 0xbc, 0xe4, 0xb5, 0x49, 0xd5, 0x0f, 0x47, 0xa1,
-	0xe5, 0x2a, 0xc1, 0xf7, 0x6c, 0x03, 0x8e, 0x54, 0xbf, 0x4a, 0x3d, 0x7f, 0x91, 0x75, 0x99, 0xe9,
-	0xd9, 0x0f, 0xdf, 0x63, 0x32, 0x5c, 0xeb, 0x81, 0xba, 0x64, 0x3e, 0xf3, 0xbe, 0x6d, 0x3d, 0x5c,
-	0xec, 0x83, 0x31, 0xcf, 0x2b, 0xde, 0x29, 0xdb, 0x39, 0x11, 0xca, 0x7e, 0x6e, 0x66, 0x8e, 0xa4,
-	0xd2, 0xdb, 0xb6, 0x75, 0x7b, 0x5f, 0xf6, 0xbc, 0xe2, 0x3d, 0x67, 0x5b, 0x7c, 0x1e, 0x77, 0x93,
-	0xd1, 0x95, 0x90, 0x87, 0x22, 0x1e, 0x5d, 0x2e, 0xa8, 0xb7, 0x7c, 0x1b, 0xbe, 0x62, 0x1e, 0x9f,
-	0xc7, 0x3f, 0xce, 0xdf, 0x0a, 0x19, 0x0b, 0x25, 0xb2, 0xdf, 0x6f, 0xd4, 0x0f, 0x68, 0xd2, 0xe5,
-	0x12, 0xda, 0x07, 0xac, 0x71, 0x69, 0xb1, 0xeb, 0xd7, 0x8c, 0x9d, 0x08, 0x65, 0x1e, 0xd0, 0x1f,
-	0xb8, 0xfc, 0x8e, 0x35, 0x7d, 0x87, 0xd7, 0x52, 0x43, 0x3f, 0x44, 0x99, 0x4a, 0xe4, 0xcd, 0x1f,
-	0x24, 0xe0, 0x88, 0xee, 0xa5, 0x93, 0xf3, 0x2e, 0x5d, 0xc2, 0x5d, 0x29, 0x72, 0xea, 0x71, 0xe6,
-	0x2f, 0x88, 0xd1, 0xa9, 0xfb, 0x32, 0x61, 0x9f, 0xdd, 0x9d, 0xfd, 0xd3, 0xd2, 0xfa, 0xf6, 0x85,
-	0xb7, 0x52, 0xf0, 0x65, 0x02, 0x3f, 0xbd, 0x2b, 0x81, 0x27, 0x71, 0xbf, 0x61, 0x1f, 0x17, 0xe2,
-	0xf2, 0xff, 0x9c, 0x58, 0xe9, 0xfb, 0x12, 0xb1, 0xc1, 0x9d, 0x19, 0x2f, 0xc9, 0x1d, 0xb0, 0xd6,
-	0xa2, 0xdc, 0x3c, 0xeb, 0xfd, 0xfd, 0x9c, 0x93, 0x9b, 0x45, 0xfe, 0x80, 0x1b, 0xcf, 0x0d, 0xbb,
-	0x48, 0x7a, 0x3e, 0x70, 0x2c, 0x8b, 0x59, 0xd2, 0x0b, 0xd6, 0x3c, 0x11, 0x0a, 0x83, 0xed, 0x6d,
-	0x71, 0xe5, 0xa3, 0x72, 0x70, 0x36, 0x51, 0xe5, 0xcf, 0xd0, 0x45, 0x4e, 0xa2, 0x69, 0x14, 0x5f,
-	0x80, 0x97, 0x79, 0xe2, 0x9a, 0x10, 0x75, 0x18, 0x3f, 0x53, 0xba, 0x1b, 0x07, 0x2f, 0xd9, 0xfd,
-	0x3c, 0x1c, 0x8e, 0xc2, 0xd8, 0x84, 0xc4, 0x17, 0xac, 0x09, 0xa4, 0xbe, 0x36, 0x59, 0xee, 0xb9,
-	0x00, 0xd4, 0x71, 0xda, 0x48, 0xdc, 0xb6, 0xba, 0x20, 0x14, 0xbc, 0x5d, 0xc3, 0x3f, 0xb4, 0xfe,
-	0xf2, 0xff, 0x03, 0x00, 0x00, 0xff, 0xff, 0xb7, 0xb6, 0xd4, 0x98, 0x0b, 0x2b, 0x00, 0x00,
+	// 4187 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x5b, 0x5f, 0x73, 0xdb, 0x48,
+	0x72, 0x5f, 0x92, 0xfa, 0x43, 0x8e, 0xfe, 0x51, 0xf0, 0x3f, 0x98, 0xf6, 0x7a, 0xb5, 0xd8, 0xdb,
+	0x8b, 0xe3, 0x6c, 0xf9, 0x7c, 0xba, 0xdd, 0xbd, 0x8b, 0x53, 0xb7, 0xbb, 0x14, 0x29, 0xc9, 0x2c,
+	0x8b, 0x5a, 0x78, 0x28, 0xad, 0x9d, 0xab, 0x5c, 0xa5, 0x60, 0x70, 0x28, 0xa1, 0x44, 0x02, 0xd8,
+	0x01, 0x28, 0x5b, 0xf7, 0x9a, 0x87, 0xbc, 0xe5, 0x2d, 0x75, 0x0f, 0xa9, 0x7c, 0x88, 0xbc, 0xa7,
+	0x52, 0x95, 0xaa, 0xe4, 0x25, 0x1f, 0x20, 0x6f, 0xa9, 0xbc, 0xdf, 0x5b, 0x3e, 0x41, 0x52, 0xdd,
+	0x3d, 0x03, 0x0c, 0x40, 0x48, 0xbe, 0x3c, 0x69, 0xfa, 0x37, 0x3d, 0x3d, 0x33, 0x3d, 0x3d, 0xdd,
+	0x3d, 0x0d, 0x8a, 0xdd, 0x15, 0xe1, 0x24, 0x92, 0xbe, 0x90, 0x7f, 0x9d, 0x08, 0x79, 0x19, 0xf8,
+	0xe2, 0x69, 0x2c, 0xa3, 0x34, 0xb2, 0x96, 0x93, 0x73, 0x4f, 0x8a, 0xce, 0xba, 0x1f, 0xcd, 0x66,
+	0x51, 0x48, 0x60, 0x87, 0x25, 0xbe, 0xa7, 0xda, 0xce, 0x73, 0xd6, 0xec, 0x1d, 0x9d, 0x8e, 0x5e,
+	0x06, 0xfe, 0x85, 0x75, 0x97, 0xad, 0xf4, 0x52, 0x39, 0x1d, 0xf4, 0xed, 0xda, 0x4e, 0xed, 0x71,
+	0x8b, 0x2b, 0x0a, 0x70, 0x2e, 0xbc, 0x24, 0x0a, 0xed, 0x3a, 0xe1, 0x44, 0x39, 0x63, 0xc6, 0x60,
+	0xec, 0x41, 0x30, 0x4d, 0x85, 0xb4, 0x3a, 0xac, 0xf9, 0x3a, 0x92, 0x17, 0xd3, 0xc8, 0x1b, 0xab,
+	0xf1, 0x19, 0x6d, 0x6d, 0xb2, 0xfa, 0xa0, 0x8f, 0xa3, 0x37, 0x78, 0x7d, 0xd0, 0xb7, 0x6e, 0xb3,
+	0xe5, 0x51, 0xea, 0xc9, 0xd4, 0x6e, 0x20, 0x44, 0x04, 0xa0, 0x47, 0xc1, 0x2c, 0x48, 0xed, 0x25,
+	0x42, 0x91, 0x70, 0xfe, 0x77, 0x95, 0xad, 0xc1, 0x34, 0x23, 0x91, 0x24, 0x41, 0x14, 0x2a, 0x59,
+	0xb5, 0x4c, 0x96, 0x39, 0x6f, 0xbd, 0x34, 0xef, 0x43, 0xd6, 0xda, 0x4f, 0xcf, 0x85, 0x3c, 0xb9,
+	0x8a, 0x85, 0x9a, 0x2b, 0x07, 0x2c, 0x9b, 0xad, 0x0e, 0x5c, 0x17, 0xd4, 0xa0, 0x66, 0xd4, 0x24,
+	0x8c, 0xeb, 0x4d, 0x03, 0x11, 0xa6, 0xc3, 0x6e, 0xcf, 0x5e, 0xde, 0xa9, 0x3d, 0x5e, 0xe7, 0x39,
+	0x00, 0xbd, 0x23, 0x21, 0x2f, 0x85, 0x84, 0xde, 0x15, 0xea, 0xcd, 0x00, 0x58, 0x0f, 0xb1, 0x0e,
+	0x5c, 0x7b, 0x15, 0x3b, 0x33, 0x1a, 0xfa, 0x88, 0x71, 0xe0, 0xda, 0x4d, 0xea, 0xd3, 0xb4, 0xf5,
+	0x88, 0x31, 0xe2, 0x73, 0x23, 0x99, 0xda, 0x2d, 0x5c, 0x90, 0x81, 0x40, 0x3f, 0xf1, 0x62, 0x3f,
+	0xa3, 0xfe, 0x1c, 0x01, 0xd9, 0x83, 0xde, 0xd0, 0xed, 0x45, 0x63, 0x61, 0xaf, 0x61, 0x6f, 0x46,
+	0xeb, 0x3e, 0x54, 0xc3, 0x7a, 0xde, 0x87, 0x5a, 0xd8, 0x61, 0x6b, 0x34, 0xcb, 0x28, 0xf5, 0x52,
+	0x61, 0x6f, 0x60, 0xb7, 0x09, 0x01, 0x07, 0xcd, 0x43, 0x1c, 0x9b, 0xc4, 0x61, 0x40, 0xc6, 0xda,
+	0x2f, 0xd2, 0xc4, 0xde, 0x2a, 0xac, 0xfd, 0x22, 0x4d, 0x8c, 0xb5, 0x43, 0x7f, 0xbb, 0xb0, 0x76,
+	0xe8, 0xcf, 0xd6, 0xb0, 0x77, 0x95, 0x8a, 0xc4, 0xde, 0xde, 0xa9, 0x3d, 0x5e, 0xe2, 0x26, 0x94,
+	0xaf, 0x81, 0x38, 0x2c, 0xe2, 0x30, 0x20, 0xe0, 0xe8, 0xc6, 0xf1, 0x34, 0xf0, 0xbd, 0x34, 0x88,
+	0x42, 0xfb, 0x16, 0xad, 0xd2, 0x80, 0xac, 0x36, 0x6b, 0x74, 0xcf, 0x84, 0x7d, 0x1b, 0x7b, 0xa0,
+	0x69, 0x59, 0x6c, 0x69, 0x30, 0x9e, 0x0a, 0xfb, 0x0e, 0x42, 0xd8, 0x06, 0xec, 0x28, 0x98, 0x08,
+	0xfb, 0x2e, 0x61, 0xd0, 0x46, 0x4b, 0x09, 0xcf, 0xa4, 0x48, 0x12, 0xfb, 0xde, 0x4e, 0xed, 0x71,
+	0x93, 0x6b, 0x12, 0x64, 0x9e, 0x78, 0xb1, 0x6d, 0x23, 0x0a, 0x4d, 0x40, 0x86, 0xc1, 0xd8, 0xbe,
+	0x4f, 0xc8, 0x30, 0x18, 0x83, 0xf6, 0xdd, 0x68, 0x1a, 0xf8, 0x57, 0x83, 0xb1, 0xdd, 0x21, 0xed,
+	0x6b, 0xda, 0x72, 0xd8, 0x3a, 0xb5, 0xbb, 0x3e, 0x2e, 0xfb, 0x01, 0xf6, 0x17, 0x30, 0xeb, 0x27,
+	0x6c, 0x83, 0x54, 0xd1, 0x4d, 0x66, 0xa8, 0xc0, 0x87, 0xc8, 0x54, 0x04, 0x81, 0x8b, 0xd4, 0xa1,
+	0xb9, 0x3e, 0x26, 0xae, 0x02, 0x68, 0xfd, 0x94, 0x6d, 0x66, 0xc3, 0x48, 0x95, 0x8f, 0x50, 0x95,
+	0x25, 0x14, 0xf8, 0xb2, 0x81, 0xc4, 0xf7, 0x09, 0xf1, 0x15, 0x51, 0xd8, 0xdb, 0x8b, 0x28, 0x49,
+	0x87, 0x60, 0x75, 0x3b, 0xb8, 0xe5, 0x8c, 0x86, 0xfb, 0xfc, 0x66, 0x32, 0x19, 0xb8, 0xf6, 0xa7,
+	0x68, 0xea, 0x44, 0x80, 0x37, 0x79, 0x33, 0x99, 0x74, 0xe3, 0xd8, 0x76, 0x70, 0x81, 0x8a, 0x02,
+	0x1d, 0xbf, 0x99, 0x4c, 0xd0, 0xb8, 0x3f, 0xa3, 0xdb, 0xa8, 0x48, 0x67, 0x8f, 0xb5, 0x0d, 0x07,
+	0xd0, 0x95, 0xd2, 0xbb, 0xb2, 0x9e, 0xb2, 0xa6, 0xa2, 0x13, 0xbb, 0xb6, 0xd3, 0x78, 0xbc, 0xb6,
+	0x6b, 0x3d, 0x45, 0x5f, 0xf7, 0xd4, 0x60, 0xe5, 0x19, 0x8f, 0xf3, 0x9f, 0x35, 0x66, 0x19, 0x3d,
+	0xbd, 0x68, 0x1e, 0x82, 0xd3, 0x02, 0xc3, 0x9b, 0x4b, 0x43, 0x12, 0x19, 0x7f, 0x0e, 0xa1, 0xc2,
+	0xe6, 0xf2, 0xa4, 0xe7, 0x66, 0x4c, 0xe4, 0xc6, 0x4a, 0xa8, 0xe2, 0x3b, 0xed, 0xe7, 0x7c, 0x8d,
+	0x8c, 0xcf, 0x40, 0xad, 0xc7, 0x6c, 0xab, 0x37, 0x97, 0x70, 0xfb, 0x32, 0x46, 0x72, 0x3e, 0x65,
+	0x18, 0x8f, 0x7d, 0x2e, 0x07, 0x39, 0xdf, 0xb2, 0x3a, 0x76, 0x13, 0x74, 0xfe, 0x9b, 0xb1, 0x5b,
+	0xb0, 0xb1, 0xbe, 0x97, 0x7a, 0xb1, 0x97, 0x9e, 0xeb, 0x9d, 0x3d, 0x64, 0x2d, 0xfe, 0xc6, 0xf5,
+	0xfc, 0x0b, 0x91, 0xd2, 0xbe, 0x96, 0x78, 0x0e, 0x80, 0x6c, 0xfe, 0xa6, 0x2f, 0xa3, 0x58, 0x73,
+	0xd4, 0x91, 0xa3, 0x08, 0x82, 0x8c, 0x93, 0x4c, 0x46, 0x83, 0x64, 0x9c, 0x98, 0x32, 0x4e, 0x0a,
+	0x32, 0x96, 0x48, 0x46, 0x01, 0x04, 0x03, 0xdf, 0x97, 0x32, 0x92, 0x9a, 0x69, 0x19, 0x99, 0x0a,
+	0x98, 0xf5, 0x05, 0xdb, 0x3e, 0x8e, 0xb4, 0xd3, 0xd6, 0x8c, 0x2b, 0xc8, 0xb8, 0xd8, 0x01, 0x67,
+	0x36, 0x70, 0x2f, 0xbf, 0xd4, 0x7c, 0xab, 0xe4, 0x0a, 0x0c, 0x48, 0x71, 0x7c, 0xad, 0x39, 0x9a,
+	0x19, 0x87, 0x86, 0xc0, 0x21, 0x9d, 0xf4, 0x5c, 0xcd, 0xd0, 0x42, 0x06, 0x03, 0xb1, 0x9e, 0xb1,
+	0x5b, 0x27, 0x3d, 0xf7, 0x38, 0x52, 0x6a, 0xd6, 0x8c, 0x0c, 0x19, 0xab, 0xba, 0x40, 0xe2, 0x69,
+	0x3f, 0x93, 0xb8, 0x46, 0x12, 0x73, 0x04, 0xd7, 0xd4, 0x1b, 0x66, 0x0c, 0xeb, 0x6a, 0x4d, 0x39,
+	0x04, 0x9a, 0xfa, 0x1e, 0x62, 0x93, 0x66, 0xd9, 0x20, 0x4d, 0x99, 0x18, 0x9c, 0xc8, 0x81, 0xf4,
+	0xce, 0x66, 0x22, 0x4c, 0x13, 0x74, 0xc4, 0x4b, 0x3c, 0x07, 0xac, 0x27, 0xac, 0x7d, 0x12, 0xcc,
+	0x44, 0x34, 0x4f, 0x73, 0xa6, 0x2d, 0x64, 0x5a, 0xc0, 0xf1, 0xf4, 0xa2, 0xd4, 0x9b, 0x66, 0xd6,
+	0xd5, 0x56, 0xa7, 0x67, 0x82, 0xb0, 0x6a, 0xd3, 0xf4, 0x95, 0x63, 0x36, 0x20, 0xe0, 0x30, 0x8d,
+	0x5e, 0x39, 0x66, 0x03, 0x82, 0x7d, 0x15, 0xcc, 0xfd, 0x16, 0xed, 0xcb, 0xc4, 0x40, 0x7b, 0x86,
+	0xa1, 0xdf, 0x26, 0xed, 0x0d, 0x0a, 0xfd, 0x60, 0x54, 0x43, 0x91, 0x0a, 0x99, 0xa0, 0xbb, 0x5e,
+	0xe2, 0x06, 0x02, 0xab, 0x70, 0x65, 0xf4, 0xfe, 0x4a, 0x31, 0xdc, 0xa5, 0x55, 0x18, 0x10, 0x86,
+	0xf4, 0xb9, 0x54, 0xfd, 0xf7, 0x48, 0x73, 0x19, 0x00, 0x6b, 0xec, 0xcd, 0xe5, 0x51, 0x74, 0xd6,
+	0xf3, 0xfc, 0x73, 0x91, 0xa0, 0x3f, 0x5f, 0xe2, 0x05, 0x0c, 0x6e, 0xf8, 0x81, 0x14, 0x62, 0x9c,
+	0xeb, 0xf6, 0x3e, 0xb9, 0xc4, 0x22, 0x0a, 0x33, 0x75, 0x93, 0x44, 0xcc, 0xde, 0x4e, 0xaf, 0x12,
+	0xf4, 0xf7, 0x4b, 0x3c, 0x07, 0x32, 0x29, 0x39, 0xcb, 0x03, 0x43, 0x4a, 0x81, 0xcf, 0xf5, 0x64,
+	0x22, 0x72, 0xe7, 0xf4, 0x70, 0xa7, 0x01, 0x7c, 0x45, 0x14, 0xce, 0x91, 0x10, 0x6d, 0x36, 0x1f,
+	0x23, 0x5b, 0x11, 0x04, 0xcb, 0xa0, 0x90, 0x02, 0x21, 0xff, 0xe7, 0x7c, 0x3e, 0x55, 0x8e, 0x7f,
+	0x83, 0x2f, 0xe0, 0x45, 0xde, 0x5d, 0xe2, 0xfd, 0xa4, 0xcc, 0x4b, 0x38, 0xce, 0x8e, 0x58, 0x3f,
+	0x9a, 0x79, 0x41, 0x98, 0x60, 0x0c, 0xd8, 0xe0, 0x45, 0x10, 0x7c, 0x9e, 0x09, 0x0c, 0xdc, 0x04,
+	0x43, 0xc2, 0x06, 0x2f, 0xc3, 0x70, 0xce, 0x87, 0x11, 0x8f, 0xe6, 0x69, 0x10, 0x8a, 0x44, 0x05,
+	0x08, 0x03, 0xc1, 0xe0, 0x9c, 0x44, 0x13, 0x8c, 0x10, 0xeb, 0x1c, 0xdb, 0x90, 0x10, 0xba, 0x23,
+	0xfb, 0x27, 0x88, 0xd4, 0xdd, 0x11, 0x68, 0x0e, 0x33, 0x47, 0x30, 0x8f, 0x5e, 0x14, 0x86, 0x89,
+	0xfd, 0x39, 0x69, 0xb8, 0x88, 0x66, 0x7c, 0xae, 0x97, 0x24, 0xc4, 0xf7, 0x53, 0x83, 0x2f, 0x43,
+	0x9d, 0x37, 0xec, 0x36, 0x3a, 0x58, 0x21, 0x83, 0x4b, 0x31, 0x56, 0x91, 0x39, 0xc6, 0x40, 0x0f,
+	0x51, 0xac, 0xa6, 0xd2, 0x89, 0x38, 0x86, 0xd0, 0xa6, 0xc2, 0x38, 0xc5, 0x08, 0x45, 0x01, 0x0e,
+	0xea, 0x1a, 0xf4, 0x55, 0x4c, 0x50, 0x94, 0xf3, 0x2f, 0x75, 0x76, 0x67, 0x41, 0x34, 0xf4, 0x2d,
+	0x24, 0xb9, 0x90, 0x30, 0x4b, 0x7f, 0xe0, 0xa2, 0xe0, 0x75, 0x4e, 0x04, 0xa0, 0xfd, 0x04, 0xf2,
+	0xcc, 0x06, 0xa1, 0x48, 0xc0, 0x6c, 0xd8, 0xcd, 0xd1, 0x21, 0xaf, 0x73, 0x45, 0x01, 0x8e, 0x0c,
+	0x5c, 0x65, 0xb4, 0x8a, 0x02, 0x9d, 0x62, 0xd4, 0x5d, 0xa1, 0x84, 0x07, 0xda, 0x20, 0x19, 0xfe,
+	0x72, 0xf4, 0xae, 0x1b, 0x9c, 0x08, 0x33, 0x61, 0x6e, 0x16, 0x13, 0xe6, 0x7c, 0xe7, 0xad, 0xc2,
+	0xce, 0x8d, 0xc4, 0x89, 0x15, 0x13, 0x27, 0x8b, 0x2d, 0x1d, 0xbc, 0xea, 0x1f, 0xa3, 0xa7, 0x6c,
+	0x71, 0x6c, 0x5b, 0x3f, 0x63, 0x4b, 0xdd, 0x38, 0x06, 0xe7, 0x08, 0x01, 0xfd, 0x81, 0x11, 0xd0,
+	0xcb, 0xca, 0xe7, 0xc8, 0xe8, 0xb8, 0xac, 0x53, 0xa9, 0x3f, 0xca, 0x11, 0x76, 0xd9, 0x32, 0x59,
+	0x2f, 0x25, 0x08, 0x0f, 0xaf, 0x93, 0x07, 0x4c, 0x9c, 0x58, 0x9d, 0x7f, 0xad, 0x31, 0xbb, 0x92,
+	0x61, 0xe8, 0xc5, 0xd6, 0x01, 0x5b, 0x55, 0x4d, 0x25, 0xf2, 0x8b, 0x9b, 0x44, 0x0e, 0xbd, 0xf8,
+	0xa9, 0xfa, 0xbb, 0x1f, 0xa6, 0xf2, 0x8a, 0xeb, 0xc1, 0x9d, 0xdf, 0xb2, 0x75, 0xb3, 0x03, 0x2c,
+	0xe9, 0x42, 0x5c, 0xa9, 0x57, 0x13, 0x34, 0xad, 0x5f, 0xb2, 0xe5, 0x4b, 0x6f, 0x3a, 0x17, 0x78,
+	0xde, 0x6b, 0xbb, 0x9f, 0xde, 0x34, 0x0f, 0x6e, 0x96, 0x13, 0xff, 0xf3, 0xfa, 0xaf, 0x6a, 0xce,
+	0x3f, 0x37, 0x29, 0x61, 0x72, 0x65, 0xf4, 0x56, 0x8c, 0xe6, 0xb3, 0x99, 0x27, 0xaf, 0xd0, 0xc3,
+	0x45, 0x61, 0xea, 0x05, 0xa1, 0x90, 0xb4, 0x01, 0x4c, 0x34, 0x4d, 0x0c, 0xef, 0x69, 0x30, 0x2e,
+	0xb0, 0xd5, 0xd5, 0x3d, 0x2d, 0xc2, 0x70, 0x4f, 0xdd, 0x60, 0xec, 0xca, 0xc8, 0x07, 0x26, 0xb2,
+	0x6a, 0x03, 0x81, 0xd9, 0x8e, 0xc5, 0x3b, 0xa0, 0x44, 0x92, 0x08, 0x9d, 0xe2, 0x14, 0x30, 0xf0,
+	0x1d, 0xc7, 0xe2, 0xdd, 0x68, 0x9e, 0xc4, 0x81, 0x0f, 0xa8, 0xce, 0x6f, 0x0a, 0x20, 0xe6, 0x55,
+	0x7a, 0xe6, 0x51, 0x1a, 0xc5, 0x89, 0xb2, 0xd3, 0x12, 0x6a, 0x3d, 0x61, 0x9b, 0xaf, 0x8f, 0x46,
+	0x69, 0x24, 0xbd, 0x33, 0xf1, 0xda, 0x4b, 0xfd, 0x73, 0x32, 0xdd, 0xbd, 0xba, 0x5d, 0xe3, 0xa5,
+	0x1e, 0xb0, 0x56, 0x37, 0x18, 0x8f, 0x44, 0xaa, 0xcc, 0x58, 0x51, 0xb0, 0x6a, 0xe5, 0x57, 0x4f,
+	0xbc, 0xb7, 0x53, 0xa1, 0x6c, 0xb9, 0x80, 0xc1, 0x7a, 0x06, 0x61, 0x94, 0x06, 0x93, 0x2b, 0x94,
+	0x25, 0x12, 0xf5, 0x14, 0x2b, 0xa1, 0xc0, 0x07, 0xeb, 0xdf, 0x9b, 0x46, 0xfe, 0x05, 0x8f, 0x22,
+	0x95, 0x13, 0x6c, 0xf0, 0x12, 0x5a, 0xe0, 0x1b, 0x7a, 0xf2, 0x22, 0x51, 0x0f, 0xb4, 0x12, 0x0a,
+	0x39, 0x52, 0x86, 0xa0, 0xd5, 0xf4, 0xc2, 0x54, 0x3d, 0xd6, 0x16, 0x3b, 0xac, 0xa7, 0xcc, 0xca,
+	0xc0, 0x7e, 0x20, 0x87, 0x51, 0x08, 0xec, 0xf4, 0x72, 0xab, 0xe8, 0x81, 0xb3, 0x38, 0x08, 0xa6,
+	0x62, 0x18, 0x85, 0xfb, 0x97, 0x59, 0xda, 0xb0, 0xc1, 0x8b, 0xa0, 0xc1, 0x75, 0x28, 0xa3, 0x79,
+	0xac, 0x5f, 0x72, 0x45, 0x10, 0x23, 0x1c, 0x01, 0x07, 0x1e, 0xed, 0x7c, 0x9b, 0x76, 0x54, 0x44,
+	0x61, 0x47, 0x19, 0x32, 0x0c, 0x53, 0x62, 0xb5, 0x68, 0x47, 0x0b, 0x1d, 0x05, 0x6e, 0x58, 0x37,
+	0xaa, 0xea, 0x56, 0x89, 0x5b, 0x77, 0x14, 0xd7, 0x80, 0x3e, 0xe0, 0x76, 0x79, 0x0d, 0x80, 0x16,
+	0xf8, 0x5c, 0x2f, 0x3d, 0x4f, 0xd4, 0x53, 0xb0, 0x84, 0x1a, 0x3b, 0xc7, 0x49, 0x12, 0xf5, 0x3a,
+	0x2c, 0x82, 0x60, 0x3f, 0x0a, 0x18, 0x84, 0xaf, 0xc7, 0x94, 0x66, 0x6c, 0xf0, 0x02, 0x66, 0xcc,
+	0x38, 0x08, 0x69, 0x46, 0xbb, 0x30, 0xe3, 0x20, 0x2c, 0xcf, 0x38, 0x08, 0x71, 0xc6, 0xfb, 0x85,
+	0x19, 0x09, 0x04, 0xad, 0x0c, 0xbd, 0xf7, 0xfb, 0x97, 0xde, 0xb4, 0x77, 0xee, 0x85, 0xaf, 0xe6,
+	0x62, 0x2e, 0xf4, 0x1b, 0x73, 0xb1, 0x03, 0x64, 0x0e, 0xbd, 0xf7, 0x87, 0x91, 0xd4, 0x01, 0x96,
+	0x5e, 0x9b, 0x45, 0xd0, 0xf9, 0x43, 0xcd, 0x70, 0x1f, 0xea, 0xba, 0x82, 0x8b, 0x72, 0x03, 0x2a,
+	0xec, 0x2c, 0x73, 0x68, 0x62, 0xd8, 0x88, 0x03, 0xaa, 0xb9, 0x2c, 0x73, 0x6c, 0x03, 0x76, 0xec,
+	0xcd, 0xa8, 0xd4, 0xd2, 0xe2, 0xd8, 0x06, 0x8c, 0xcf, 0x83, 0xb1, 0x72, 0x01, 0xd8, 0x06, 0x6c,
+	0x1f, 0x30, 0xba, 0xf1, 0xd8, 0xc6, 0xaa, 0x8a, 0xef, 0x85, 0x90, 0xa8, 0xea, 0x3b, 0x9e, 0x03,
+	0xd8, 0x0b, 0x45, 0x22, 0xa0, 0x54, 0xca, 0x9f, 0x03, 0xf0, 0x0a, 0xe5, 0x22, 0x8e, 0x64, 0x2a,
+	0xc6, 0xea, 0x4a, 0x67, 0x34, 0x8c, 0xcc, 0x5c, 0x05, 0xde, 0xe8, 0x16, 0xcf, 0x01, 0xe7, 0x98,
+	0xdd, 0x29, 0xef, 0x95, 0x82, 0xc7, 0x57, 0xac, 0x95, 0xbb, 0x2f, 0xf2, 0xf6, 0xf7, 0x0c, 0x2f,
+	0x6c, 0x0e, 0xe0, 0x39, 0xa7, 0x13, 0x32, 0x2b, 0xeb, 0xce, 0x66, 0xc1, 0x70, 0xae, 0xab, 0x62,
+	0xf5, 0xc1, 0x58, 0x6b, 0xb3, 0x9e, 0x6b, 0x13, 0xaa, 0x46, 0xe7, 0xc1, 0x74, 0x2c, 0x45, 0x68,
+	0x37, 0x76, 0x1a, 0x8f, 0x97, 0x79, 0x46, 0x53, 0xfd, 0x40, 0xa6, 0x09, 0xb8, 0xda, 0x25, 0xaa,
+	0x70, 0x69, 0xda, 0x39, 0x61, 0xf7, 0x16, 0xe7, 0xa3, 0x1d, 0xfc, 0x39, 0x63, 0x19, 0xa2, 0xb7,
+	0x70, 0xbf, 0xbc, 0x85, 0x8c, 0x83, 0x1b, 0xcc, 0xce, 0xdf, 0xd4, 0xe8, 0x51, 0xa9, 0x8c, 0x2d,
+	0x48, 0x23, 0x09, 0x4d, 0x3c, 0x73, 0x2f, 0x3d, 0x57, 0x3b, 0xc1, 0x36, 0x60, 0x43, 0x2f, 0xb9,
+	0x50, 0x2f, 0x48, 0x6c, 0x43, 0xfa, 0x30, 0x48, 0xfa, 0x81, 0x44, 0x43, 0x68, 0x72, 0x22, 0x20,
+	0x19, 0x80, 0x6c, 0x41, 0xf8, 0x54, 0xe1, 0x6b, 0x72, 0x4d, 0x02, 0x3f, 0xc8, 0x87, 0xd7, 0x61,
+	0xe3, 0x71, 0x8b, 0x13, 0xe1, 0x1c, 0x51, 0x28, 0x2e, 0x2d, 0x82, 0x36, 0xf7, 0x4c, 0x8f, 0xa0,
+	0x7d, 0x75, 0x8c, 0x7d, 0x95, 0xf8, 0xb5, 0xb4, 0xff, 0xd1, 0x15, 0x80, 0x30, 0x98, 0x4c, 0x84,
+	0xe4, 0xe2, 0xc7, 0xb9, 0x48, 0x52, 0xeb, 0x33, 0xd6, 0xe8, 0xcd, 0xe8, 0x6c, 0x36, 0x77, 0xb7,
+	0x95, 0x18, 0xc5, 0xd3, 0x9b, 0x8d, 0x39, 0xf4, 0x1a, 0xf5, 0xcb, 0x16, 0xa6, 0x63, 0x8f, 0x18,
+	0xd3, 0xaf, 0x52, 0x95, 0xd4, 0xb5, 0xb8, 0x81, 0x40, 0x3f, 0x4c, 0x7a, 0x3c, 0x9f, 0xbd, 0x15,
+	0x52, 0x59, 0xbe, 0x81, 0x68, 0x47, 0x31, 0x0a, 0x7e, 0x27, 0x06, 0xe1, 0x70, 0x4f, 0xdd, 0x83,
+	0x02, 0x06, 0x41, 0x8a, 0x2a, 0xab, 0x78, 0x19, 0x5a, 0x5c, 0x51, 0x90, 0x9e, 0xf7, 0xe7, 0x12,
+	0x2b, 0x5a, 0x83, 0x70, 0x24, 0xfc, 0x28, 0x1c, 0xab, 0x2c, 0x6d, 0x01, 0x77, 0x3e, 0xa7, 0x63,
+	0xcc, 0xb6, 0x9c, 0xc4, 0x51, 0x98, 0x98, 0xd9, 0x25, 0x6e, 0xc7, 0xf9, 0x96, 0x6d, 0x1b, 0x6c,
+	0x6a, 0x9e, 0x12, 0xd3, 0x4d, 0x75, 0x56, 0xe7, 0x6f, 0xeb, 0x6c, 0xcd, 0x90, 0xb0, 0x30, 0xd6,
+	0x66, 0xab, 0xdd, 0x33, 0x28, 0x81, 0x6a, 0x25, 0x6a, 0xf2, 0x83, 0x9a, 0xfc, 0x82, 0xad, 0x40,
+	0x89, 0x71, 0x4e, 0x29, 0xc4, 0xe6, 0xee, 0xed, 0xe2, 0x09, 0x51, 0x1f, 0x57, 0x3c, 0x60, 0x8b,
+	0x5d, 0x79, 0x46, 0x45, 0x86, 0x16, 0xc7, 0x76, 0xe9, 0x2c, 0x56, 0x16, 0xce, 0xc2, 0x62, 0x4b,
+	0xa0, 0x73, 0xd4, 0x61, 0x83, 0x63, 0xbb, 0xe8, 0x6d, 0x9a, 0xd8, 0x51, 0xf4, 0x36, 0x90, 0x73,
+	0x60, 0x67, 0x0b, 0x3b, 0x33, 0x3a, 0xab, 0x55, 0xd1, 0xf2, 0xb2, 0x5a, 0x55, 0x42, 0x74, 0x65,
+	0xad, 0x4a, 0x1d, 0x4e, 0xc6, 0x53, 0x3a, 0xb5, 0x7e, 0xf4, 0x2e, 0x34, 0x8a, 0xe8, 0xf9, 0xa9,
+	0x7d, 0xce, 0xb6, 0x0c, 0x36, 0xd7, 0xf7, 0x62, 0xbc, 0x9f, 0xbe, 0x4a, 0xee, 0xd6, 0x39, 0xb6,
+	0x9d, 0x57, 0x24, 0x2d, 0xbb, 0xdd, 0x47, 0xd1, 0x19, 0x17, 0x3f, 0x2e, 0xb8, 0xa4, 0xac, 0x24,
+	0x4f, 0x4e, 0xa9, 0x5c, 0x92, 0x6f, 0x98, 0x25, 0xf9, 0x3f, 0xab, 0x12, 0x99, 0x20, 0x73, 0x74,
+	0xf6, 0x9b, 0xb7, 0x6a, 0x7a, 0x22, 0x9c, 0x7f, 0x54, 0xb6, 0xa1, 0x23, 0x89, 0x8e, 0x11, 0x35,
+	0x23, 0x46, 0x18, 0xfe, 0x70, 0x23, 0x8f, 0x2e, 0x00, 0x35, 0xd4, 0xa3, 0x44, 0x63, 0x87, 0x79,
+	0x24, 0x71, 0x0f, 0x15, 0x36, 0xca, 0x23, 0x89, 0x3b, 0x22, 0x8c, 0x9f, 0x06, 0x63, 0xfd, 0xa0,
+	0xe1, 0xa7, 0x84, 0xed, 0x03, 0xb6, 0xaa, 0x22, 0x8e, 0xc2, 0x7a, 0xb3, 0x31, 0xd4, 0x87, 0xc0,
+	0xe9, 0x60, 0x1b, 0xc7, 0x46, 0x11, 0xd5, 0xdf, 0x9b, 0x1c, 0xdb, 0x80, 0x9d, 0x26, 0x42, 0x62,
+	0xa2, 0xd7, 0xe2, 0xd8, 0xc6, 0x47, 0x16, 0xd9, 0x25, 0x3d, 0x60, 0x14, 0x05, 0x96, 0x8e, 0x9a,
+	0xeb, 0xa6, 0x98, 0xc7, 0x35, 0xb8, 0x26, 0x8d, 0x27, 0xd2, 0x06, 0x8d, 0x20, 0xca, 0xe9, 0xb3,
+	0xb6, 0xa1, 0x1e, 0xed, 0xdd, 0x16, 0x82, 0x8f, 0x55, 0xf4, 0xdc, 0xe5, 0xb8, 0xf3, 0x1d, 0xb3,
+	0x8c, 0xc7, 0x41, 0x7f, 0x1a, 0xe3, 0x33, 0xb2, 0x4a, 0xd7, 0xd7, 0x3c, 0x52, 0x9d, 0x7f, 0xaa,
+	0xb3, 0x7b, 0x8b, 0x22, 0x68, 0x3d, 0xe0, 0xe3, 0xa3, 0x71, 0x26, 0x07, 0xda, 0xf8, 0x9c, 0x14,
+	0x93, 0xae, 0x9f, 0x6a, 0x39, 0x44, 0xc1, 0xed, 0x80, 0xa2, 0xfb, 0x95, 0x76, 0xff, 0xcb, 0x3c,
+	0xa3, 0x61, 0xcc, 0xeb, 0xe9, 0xd0, 0xf3, 0xe1, 0x36, 0x83, 0xce, 0x15, 0x65, 0x7d, 0xc5, 0x9a,
+	0x6a, 0x3e, 0x0a, 0x01, 0xc5, 0x40, 0x55, 0x5c, 0x11, 0xcf, 0x58, 0x61, 0xd8, 0x6b, 0x6f, 0x42,
+	0xc3, 0x56, 0x3e, 0x38, 0x4c, 0xb3, 0xc2, 0x6e, 0x64, 0x30, 0x86, 0xca, 0x61, 0x03, 0x6c, 0x01,
+	0xda, 0x70, 0x6e, 0xef, 0xbc, 0x89, 0x0c, 0x94, 0x39, 0x6c, 0x70, 0x4d, 0x62, 0x6e, 0x31, 0x9f,
+	0x0a, 0xfc, 0x76, 0x42, 0xe9, 0x43, 0x46, 0x3b, 0xff, 0x56, 0x63, 0x77, 0x16, 0xa7, 0x82, 0x07,
+	0xd0, 0x11, 0x63, 0x39, 0x75, 0xfd, 0x6b, 0x31, 0xe7, 0x79, 0x9a, 0x37, 0xe9, 0xb5, 0x68, 0x8c,
+	0xef, 0xfc, 0x96, 0x6d, 0x95, 0xba, 0x2b, 0xde, 0x8c, 0x5f, 0x16, 0xdf, 0x8c, 0x8f, 0xae, 0x9d,
+	0x6d, 0xe1, 0xc1, 0xf8, 0x97, 0x55, 0x27, 0x4f, 0xd3, 0x54, 0x59, 0x50, 0xf9, 0x6b, 0x1e, 0xe4,
+	0x27, 0x5e, 0x9a, 0x0a, 0x89, 0x45, 0xef, 0x06, 0xe6, 0x27, 0x8a, 0x76, 0x26, 0xec, 0xe1, 0x35,
+	0xa2, 0xc9, 0xb2, 0x0e, 0xd8, 0xa6, 0x01, 0x06, 0x99, 0xb9, 0x5f, 0xbf, 0x7a, 0xd2, 0x4e, 0x69,
+	0x94, 0xf3, 0xa7, 0xd5, 0x07, 0xe1, 0xe3, 0xe7, 0x18, 0xcf, 0xd7, 0x7a, 0x1a, 0x7a, 0xbe, 0xf3,
+	0x57, 0xac, 0x53, 0xc9, 0x4a, 0x0b, 0xfa, 0x86, 0xad, 0xe5, 0xd0, 0x0d, 0xa5, 0x83, 0x9c, 0x89,
+	0x9b, 0x03, 0x9c, 0xff, 0xa8, 0xb1, 0xbb, 0x06, 0x9b, 0xbe, 0xaa, 0xd7, 0xdd, 0x46, 0x9d, 0x51,
+	0xd5, 0x8d, 0x8c, 0x2a, 0xbf, 0xa1, 0x0d, 0xd3, 0x53, 0x60, 0x26, 0x2b, 0x85, 0x97, 0x8a, 0x71,
+	0x37, 0x55, 0xc5, 0xf6, 0x1c, 0x80, 0x53, 0x38, 0x8d, 0xc7, 0x5e, 0x2a, 0xba, 0xa9, 0x2a, 0xb2,
+	0x67, 0x34, 0x8c, 0xc4, 0xc7, 0x19, 0x4e, 0x4f, 0xe9, 0x44, 0x0e, 0x80, 0xed, 0xf7, 0x26, 0x67,
+	0x68, 0xe0, 0xab, 0x14, 0x9d, 0x15, 0xe9, 0x70, 0xf6, 0xa0, 0x7a, 0x2f, 0xa4, 0xab, 0x5f, 0x14,
+	0x0b, 0x2c, 0x1f, 0x2f, 0x6a, 0xc9, 0x18, 0xa2, 0x2b, 0x2c, 0xff, 0xa5, 0x72, 0x4b, 0xc5, 0x81,
+	0x29, 0x1a, 0x68, 0x07, 0x3e, 0x58, 0x08, 0x7f, 0x2e, 0x93, 0xe0, 0x92, 0x54, 0xd4, 0xe4, 0x39,
+	0x60, 0x64, 0x43, 0xf5, 0x42, 0x36, 0xa4, 0xf5, 0xd7, 0x30, 0xf4, 0x77, 0x9b, 0x2d, 0x73, 0x71,
+	0x26, 0xde, 0xab, 0x64, 0x99, 0x08, 0xd0, 0xcf, 0x9e, 0x38, 0xf7, 0x2e, 0x83, 0x48, 0xaa, 0xfc,
+	0x20, 0xa3, 0x3f, 0xa0, 0x1f, 0x4b, 0x95, 0xa5, 0x56, 0x29, 0x4e, 0x40, 0xdb, 0xd4, 0x59, 0xb3,
+	0xa8, 0xb3, 0x23, 0x66, 0x57, 0x6c, 0x2f, 0xcb, 0x5a, 0xf9, 0xbc, 0x3a, 0x6b, 0x2d, 0xf1, 0x6b,
+	0x6d, 0xfd, 0x43, 0x9d, 0xdd, 0x87, 0xee, 0x2c, 0x25, 0x0a, 0x53, 0x21, 0x7d, 0x11, 0xd3, 0x37,
+	0x61, 0x5d, 0xba, 0xd3, 0xf9, 0xb8, 0xc6, 0x44, 0xa6, 0x27, 0x6c, 0xe3, 0x25, 0xe8, 0xf6, 0x54,
+	0x99, 0x10, 0x9a, 0xa0, 0xa3, 0xd3, 0x1e, 0x60, 0x54, 0x23, 0x24, 0x02, 0xd0, 0xbd, 0x5e, 0xfe,
+	0xcd, 0x9b, 0x08, 0xd0, 0xfd, 0x20, 0xcc, 0x4a, 0x84, 0x2d, 0xae, 0x28, 0xc0, 0xf7, 0xdf, 0x23,
+	0x4e, 0x66, 0xa3, 0x28, 0xfc, 0xd4, 0x81, 0x1c, 0xb4, 0x57, 0xd2, 0x8f, 0x09, 0x01, 0xc7, 0xfe,
+	0xfb, 0x8c, 0x54, 0x6e, 0xd5, 0x84, 0xe0, 0xa9, 0xba, 0xaf, 0x7e, 0xd2, 0x40, 0x3c, 0x14, 0x7c,
+	0x8b, 0xa0, 0xf3, 0x7b, 0xe5, 0x7f, 0x17, 0xb4, 0xb3, 0x90, 0x81, 0xe2, 0x1e, 0xa6, 0x41, 0x48,
+	0xde, 0xb1, 0xc9, 0x15, 0x05, 0xd9, 0xe1, 0xab, 0xb9, 0x27, 0xbd, 0x10, 0xde, 0xbe, 0xea, 0xb9,
+	0x62, 0x20, 0xd6, 0xd7, 0x54, 0x08, 0xa5, 0x80, 0xb5, 0xb6, 0xbb, 0x63, 0x9c, 0x58, 0xe5, 0x91,
+	0x50, 0xa9, 0x34, 0x81, 0x8c, 0xb8, 0x05, 0x4c, 0xf8, 0x7d, 0x01, 0xac, 0x05, 0x1b, 0x59, 0x4d,
+	0x57, 0x93, 0x37, 0xfe, 0x7a, 0x01, 0xca, 0x54, 0x02, 0x7f, 0x2b, 0x40, 0x07, 0xa7, 0x28, 0x38,
+	0x25, 0xfc, 0xca, 0xa7, 0x7f, 0x27, 0x81, 0x04, 0xd8, 0xf0, 0x91, 0x97, 0xa4, 0xd4, 0x43, 0xe9,
+	0x50, 0x0e, 0x64, 0x5f, 0xba, 0x57, 0x8a, 0x5f, 0xba, 0x47, 0xb1, 0x17, 0xea, 0x9c, 0x08, 0xda,
+	0xf8, 0x19, 0x2b, 0x8e, 0x85, 0xa4, 0xac, 0x8f, 0xde, 0xd2, 0x06, 0x02, 0xfd, 0x47, 0xd1, 0x3b,
+	0xdd, 0xaf, 0x7e, 0xa5, 0x90, 0x23, 0xfa, 0x7b, 0x38, 0xcb, 0xbe, 0x87, 0x3b, 0xcf, 0xd9, 0x66,
+	0xa6, 0x08, 0xba, 0x05, 0x8f, 0xd9, 0x8a, 0xfa, 0x0e, 0x43, 0xd7, 0xa0, 0x6d, 0x28, 0x15, 0x3b,
+	0xb8, 0xea, 0xd7, 0xae, 0xfa, 0xe4, 0x1c, 0xbc, 0xdc, 0x28, 0x38, 0x0b, 0xbd, 0x74, 0x2e, 0x85,
+	0x0a, 0x2e, 0x70, 0xc6, 0xdf, 0xc7, 0xfa, 0x8c, 0xbf, 0x8f, 0x41, 0x2f, 0x3f, 0x64, 0x01, 0xb0,
+	0xc5, 0x89, 0xc0, 0x9b, 0x1a, 0x85, 0xa9, 0x78, 0x9f, 0x2a, 0x27, 0xa1, 0x49, 0xe7, 0xef, 0x55,
+	0xad, 0xb7, 0x24, 0x9e, 0x02, 0x5f, 0xb9, 0x02, 0xaf, 0x9d, 0x77, 0xbd, 0x32, 0x95, 0x2a, 0x3a,
+	0xea, 0x5f, 0x1b, 0x01, 0x91, 0xec, 0xc6, 0x2c, 0xe0, 0x56, 0xef, 0xc6, 0x88, 0x99, 0xbf, 0xaf,
+	0x5e, 0x17, 0x29, 0xcf, 0x66, 0xab, 0x3f, 0x08, 0x09, 0xf5, 0x48, 0xb5, 0x73, 0x4d, 0x5a, 0xdf,
+	0x32, 0x96, 0xf1, 0xc2, 0x07, 0x5d, 0x98, 0xf7, 0x93, 0xeb, 0xe7, 0x55, 0x59, 0x46, 0x3e, 0x04,
+	0x6e, 0xe5, 0x30, 0x08, 0x83, 0xf0, 0xcc, 0x8d, 0xa2, 0xa9, 0x0e, 0xe5, 0x26, 0xe4, 0xfc, 0x9d,
+	0x2a, 0x0d, 0x81, 0x97, 0xda, 0xf3, 0x12, 0x81, 0x57, 0xe8, 0x9a, 0xa2, 0xc0, 0x0b, 0x2f, 0xc9,
+	0xc2, 0x1a, 0xb4, 0xc1, 0x74, 0xf4, 0x98, 0x2e, 0x9d, 0x45, 0x83, 0x1b, 0x08, 0x1e, 0xd4, 0x5c,
+	0x4a, 0xa1, 0x0c, 0xbb, 0xc5, 0x35, 0x09, 0x3d, 0x7d, 0x19, 0x4c, 0xa0, 0xba, 0xb3, 0x4c, 0x85,
+	0x03, 0x45, 0xea, 0xf2, 0x8d, 0xb9, 0x9e, 0xac, 0x7c, 0xa3, 0x81, 0xaa, 0xf2, 0x8d, 0x39, 0x80,
+	0xe7, 0x9c, 0xce, 0x60, 0x51, 0x1e, 0x17, 0x89, 0x48, 0x6f, 0xfc, 0x75, 0x13, 0x7c, 0x2c, 0xc1,
+	0x4a, 0x5e, 0x9d, 0xaa, 0x17, 0x48, 0x38, 0x5f, 0x50, 0x46, 0x7e, 0x10, 0x49, 0x11, 0x26, 0x81,
+	0xbf, 0x37, 0x0f, 0xe1, 0xce, 0xdd, 0x65, 0x2b, 0xd4, 0x52, 0x8f, 0x24, 0x45, 0x39, 0xa7, 0x14,
+	0x14, 0xa9, 0xaa, 0xef, 0xce, 0x93, 0xf3, 0x2c, 0xcb, 0x7b, 0x99, 0x67, 0x79, 0x2f, 0xc5, 0x55,
+	0xd1, 0xc8, 0xd7, 0xb5, 0x91, 0x63, 0x32, 0x3e, 0x15, 0xa9, 0x76, 0x61, 0x8a, 0x72, 0x5c, 0x66,
+	0x57, 0x88, 0x25, 0x15, 0x7d, 0xc9, 0x56, 0x8b, 0x39, 0x97, 0x19, 0x8e, 0x4a, 0x23, 0xb8, 0x66,
+	0x75, 0xfe, 0xbd, 0x56, 0x5e, 0xe9, 0x9e, 0xae, 0xa9, 0x57, 0xfe, 0x78, 0xec, 0x36, 0x5b, 0xde,
+	0x8f, 0x23, 0xff, 0x5c, 0xd5, 0x87, 0x88, 0x80, 0x7d, 0x8d, 0xc4, 0x8f, 0xea, 0x37, 0x05, 0xd0,
+	0x84, 0xbc, 0xab, 0x17, 0xcd, 0x62, 0x49, 0xb5, 0x76, 0xf5, 0xda, 0xd7, 0x79, 0x57, 0x3e, 0x99,
+	0xc1, 0xc3, 0xcd, 0x01, 0xb9, 0x53, 0x5c, 0x36, 0x9d, 0xa2, 0x9d, 0xef, 0x91, 0x7e, 0xa8, 0x95,
+	0xed, 0xe3, 0xd7, 0x54, 0xf3, 0xc8, 0x25, 0x77, 0xfd, 0x0b, 0xbd, 0xac, 0x5a, 0xbe, 0x2c, 0xfc,
+	0xed, 0x5b, 0x72, 0x15, 0xfa, 0x3a, 0x6e, 0x10, 0xf5, 0x64, 0x8f, 0xb1, 0xbc, 0x48, 0x64, 0xb5,
+	0xd9, 0x3a, 0x3e, 0xf3, 0x14, 0xd4, 0xfe, 0xc8, 0xda, 0x62, 0x6b, 0x50, 0x13, 0xd0, 0x40, 0xcd,
+	0xda, 0x66, 0x1b, 0x5c, 0xcc, 0xa2, 0x4b, 0xa1, 0xa1, 0xfa, 0x93, 0xaf, 0xd8, 0x46, 0xa1, 0x8c,
+	0x61, 0x31, 0xb6, 0x72, 0xe0, 0x05, 0x53, 0x31, 0x6e, 0x7f, 0x64, 0xad, 0xc1, 0xb7, 0xa6, 0x10,
+	0xae, 0x5e, 0xbb, 0x06, 0x04, 0x48, 0x8b, 0xc5, 0xb8, 0x5d, 0x7f, 0x32, 0x64, 0x77, 0x2a, 0xf5,
+	0x01, 0xab, 0xd0, 0xe4, 0x71, 0x14, 0x8a, 0xf6, 0x47, 0x26, 0x72, 0xf8, 0xbb, 0x20, 0x6e, 0xd7,
+	0x4c, 0xe4, 0x37, 0x49, 0x3a, 0x6e, 0xd7, 0x77, 0x8f, 0x98, 0xa5, 0x83, 0x6a, 0xcf, 0x8b, 0x47,
+	0xf4, 0xf3, 0x41, 0xeb, 0x6b, 0xd6, 0x1e, 0x24, 0x87, 0xdc, 0xed, 0x69, 0x6e, 0x31, 0xb6, 0x36,
+	0xd5, 0x69, 0x70, 0xb7, 0xf7, 0x43, 0x14, 0x8c, 0x3b, 0xe6, 0x93, 0x74, 0x2f, 0x8a, 0xa6, 0xc2,
+	0x0b, 0x77, 0xff, 0xd0, 0x66, 0x5b, 0x5a, 0x9c, 0x96, 0xf5, 0x27, 0x6c, 0x09, 0x7f, 0x5f, 0xb8,
+	0x65, 0xf0, 0x03, 0xd0, 0x29, 0x09, 0xb4, 0xbe, 0x61, 0x9b, 0x87, 0x22, 0x55, 0x9f, 0x5b, 0x8e,
+	0x82, 0x24, 0xb5, 0xb6, 0x8b, 0x77, 0x36, 0x15, 0xb2, 0x73, 0x6f, 0xf1, 0x77, 0x3e, 0x68, 0xcf,
+	0xcf, 0x6a, 0xd6, 0xcf, 0xd9, 0x7a, 0x6f, 0x2a, 0x3c, 0xfd, 0x71, 0xbc, 0x6a, 0x74, 0x79, 0xca,
+	0x9f, 0xb1, 0x26, 0x4c, 0x99, 0x7a, 0x69, 0x52, 0xc5, 0x6e, 0x86, 0x26, 0x62, 0xfa, 0x86, 0x6d,
+	0xe7, 0x6b, 0xd4, 0x3f, 0xb6, 0x29, 0x6b, 0xe6, 0xfe, 0xe2, 0x1a, 0x35, 0xeb, 0x77, 0xcc, 0x3a,
+	0x14, 0x69, 0xf9, 0xd7, 0x3a, 0x65, 0x01, 0x85, 0xcc, 0xb0, 0xc4, 0xfb, 0x92, 0xdd, 0x01, 0x09,
+	0xe5, 0xcf, 0x80, 0x95, 0xeb, 0xff, 0xe4, 0x03, 0x1f, 0x28, 0xad, 0x5f, 0xb2, 0xf5, 0xc2, 0x67,
+	0xc2, 0xf2, 0x42, 0x16, 0x6a, 0xde, 0x9a, 0xf1, 0x5b, 0xb6, 0x65, 0xd6, 0xc0, 0x41, 0x56, 0x79,
+	0xec, 0xc3, 0x6b, 0xea, 0xe5, 0xe4, 0x7e, 0x7a, 0x6c, 0xbb, 0x58, 0x81, 0xae, 0x12, 0xf1, 0xe8,
+	0xda, 0x7a, 0xb5, 0x16, 0x62, 0x5e, 0xc3, 0xfb, 0x15, 0x65, 0x35, 0x2a, 0xf3, 0x76, 0x3a, 0x55,
+	0x5d, 0xaa, 0x1c, 0xfa, 0x1d, 0x5b, 0x83, 0x23, 0x25, 0x34, 0xb1, 0xec, 0x45, 0xd6, 0x2a, 0xd3,
+	0x33, 0x2b, 0x7c, 0x07, 0x64, 0xb8, 0x46, 0x25, 0xae, 0x62, 0x3e, 0x5d, 0xc8, 0xeb, 0xdc, 0x5d,
+	0xec, 0x83, 0x31, 0xcf, 0x6a, 0xd6, 0x11, 0x6b, 0x1f, 0x8a, 0xd4, 0xac, 0xab, 0x25, 0x05, 0x49,
+	0xa5, 0x22, 0x5e, 0xe7, 0xfa, 0xbe, 0xe4, 0x59, 0xcd, 0x7a, 0xc6, 0x36, 0xf9, 0x3c, 0xec, 0x47,
+	0xfe, 0x85, 0x90, 0x7b, 0x22, 0xf4, 0xcf, 0x17, 0xd4, 0x5b, 0xa2, 0xad, 0x2f, 0x99, 0xc5, 0xe7,
+	0xe1, 0xcb, 0xf9, 0x5b, 0x21, 0x43, 0x91, 0x8a, 0xe4, 0x8f, 0x1b, 0xf5, 0x02, 0x4d, 0xba, 0xfc,
+	0xad, 0xe0, 0x03, 0xd6, 0x58, 0x59, 0xd5, 0xff, 0x15, 0x63, 0x87, 0x22, 0xd5, 0x95, 0xc2, 0x0f,
+	0x5c, 0xfe, 0x82, 0x35, 0x7d, 0x8b, 0xd7, 0x52, 0x41, 0x2f, 0x82, 0x24, 0x8d, 0xe4, 0xd5, 0xff,
+	0x4b, 0xc0, 0x3e, 0xdd, 0xcb, 0xc2, 0xe3, 0xbe, 0x72, 0x09, 0x37, 0xd5, 0x02, 0x62, 0x8b, 0x33,
+	0x7b, 0x41, 0x8c, 0x0a, 0x39, 0x55, 0xc2, 0x3e, 0xbb, 0xb9, 0xcc, 0x41, 0x4b, 0x1b, 0x9a, 0x17,
+	0xde, 0xa8, 0x35, 0x54, 0x09, 0xfc, 0xf4, 0xa6, 0x4a, 0x05, 0x89, 0xfb, 0x81, 0x7d, 0x9c, 0x8b,
+	0xcb, 0x7e, 0x5c, 0x67, 0xd4, 0x29, 0x2a, 0xc4, 0x3a, 0x37, 0x3e, 0xed, 0x49, 0xae, 0xcb, 0x3a,
+	0x8b, 0x72, 0xb3, 0xe7, 0xfd, 0x1f, 0xe7, 0x9c, 0x8a, 0xcf, 0xe5, 0x17, 0xb8, 0xf1, 0xcc, 0xb0,
+	0xf3, 0xd7, 0xdd, 0x07, 0x8e, 0x65, 0xf1, 0x39, 0xf8, 0x9c, 0xad, 0x1f, 0x8a, 0x14, 0x5f, 0x15,
+	0xd7, 0xc5, 0x95, 0x3b, 0xe5, 0x57, 0x88, 0x8e, 0x2a, 0x7f, 0x81, 0x2e, 0x72, 0x12, 0x4c, 0x83,
+	0xf0, 0x0c, 0xbc, 0xcc, 0x83, 0xa2, 0x09, 0x51, 0x87, 0xf6, 0x33, 0xe5, 0xbb, 0x71, 0xc0, 0x6e,
+	0x8d, 0x44, 0x5a, 0x4a, 0xbd, 0x13, 0xeb, 0x86, 0xbc, 0x1c, 0xa7, 0x5d, 0x90, 0xb3, 0xc7, 0xb6,
+	0xd4, 0x1d, 0xcb, 0xf2, 0xee, 0x0f, 0x28, 0x61, 0x31, 0x27, 0xee, 0xb2, 0x6d, 0x4c, 0x66, 0x0b,
+	0x52, 0xae, 0x1b, 0x82, 0x9c, 0x0b, 0xcb, 0xe8, 0xe1, 0x35, 0x2b, 0xe5, 0xb4, 0x15, 0x0b, 0x31,
+	0x3d, 0x71, 0x91, 0xfb, 0x59, 0xcd, 0x3a, 0x64, 0x5b, 0x90, 0xba, 0x50, 0x20, 0xa2, 0xec, 0xb1,
+	0x3a, 0xf5, 0xc4, 0xbe, 0x8e, 0x5d, 0xd9, 0xd7, 0xf5, 0x2f, 0x76, 0x5f, 0xb1, 0x5b, 0x59, 0xae,
+	0xe1, 0x7b, 0xa1, 0xce, 0x37, 0x9e, 0xb3, 0x75, 0x20, 0x95, 0xbe, 0x92, 0x2c, 0x2c, 0x00, 0xa8,
+	0x72, 0x2a, 0x7d, 0x5c, 0x5b, 0x46, 0x17, 0xc4, 0xd9, 0xb7, 0x2b, 0xf8, 0x6f, 0x11, 0xbf, 0xf8,
+	0xbf, 0x01, 0x00, 0x0c, 0x6e, 0x11, 0x62, 0x51, 0x31, 0x00, 0x00,
 }
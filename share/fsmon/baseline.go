@@ -0,0 +1,370 @@
+package fsmon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/global"
+	"github.com/neuvector/neuvector/share/osutil"
+	"github.com/neuvector/neuvector/share/utils"
+)
+
+// larger files are not baselined, hashing them on every change is too expensive
+const baselineMaxFileSize = 64 * 1024 * 1024
+
+var ErrBaselineNotFound = errors.New("File baseline not found")
+
+type fileBaseline struct {
+	path    string // path inside the container
+	hash    string // approved content hash
+	current string // last drifted content hash, empty when it matches the baseline
+	at      time.Time
+}
+
+type containerBaselines struct {
+	id    string                   // container id, empty for the host
+	files map[string]*fileBaseline // key is the full path
+}
+
+// the approved hashes are kept on disk, so an enforcer restart does not approve the drifted contents
+type baselineRecord struct {
+	Hash string `json:"hash"`
+	At   int64  `json:"at"`
+}
+
+// a file event waiting for its content hash
+type driftCheck struct {
+	rootPid int
+	path    string
+	fmod    *fileMod
+	event   uint32
+}
+
+func isBaselineFile(finfo *osutil.FileInfoExt) bool {
+	if finfo == nil || finfo.FileMode.IsDir() || finfo.Link != "" {
+		return false
+	}
+	if flt, ok := finfo.Filter.(*filterRegex); ok {
+		return flt.baseline
+	}
+	return false
+}
+
+func hashBaselineFile(fullPath string) (string, error) {
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return "", err
+	}
+	if info.Size() > baselineMaxFileSize {
+		return "", fmt.Errorf("file size %d exceeds the baseline limit", info.Size())
+	}
+	return osutil.GetFileContentHash(fullPath)
+}
+
+func (w *FileWatch) baselineFile(id string) string {
+	if id == "" {
+		id = "host"
+	}
+	return filepath.Join(w.baselineDir, id+".json")
+}
+
+func (w *FileWatch) loadBaselines(id string) map[string]*baselineRecord {
+	records := make(map[string]*baselineRecord)
+	if w.baselineDir == "" {
+		return records
+	}
+
+	dat, err := ioutil.ReadFile(w.baselineFile(id))
+	if err != nil {
+		return records
+	}
+	if err := json.Unmarshal(dat, &records); err != nil {
+		log.WithFields(log.Fields{"id": id, "error": err}).Error("FMON: invalid baseline file")
+		return make(map[string]*baselineRecord)
+	}
+	return records
+}
+
+func (w *FileWatch) saveBaselines(rootPid int) {
+	if w.baselineDir == "" {
+		return
+	}
+
+	w.mux.Lock()
+	set, ok := w.baselines[rootPid]
+	if !ok {
+		w.mux.Unlock()
+		return
+	}
+	id := set.id
+	records := make(map[string]*baselineRecord, len(set.files))
+	for _, bl := range set.files {
+		records[bl.path] = &baselineRecord{Hash: bl.hash, At: bl.at.Unix()}
+	}
+	w.mux.Unlock()
+
+	dat, _ := json.Marshal(records)
+	if err := os.MkdirAll(w.baselineDir, 0700); err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("FMON: baseline directory")
+		return
+	}
+
+	// replace the file at once, a partial write should not lose the approved hashes
+	file := w.baselineFile(id)
+	if err := ioutil.WriteFile(file+".tmp", dat, 0600); err != nil {
+		log.WithFields(log.Fields{"id": id, "error": err}).Error("FMON: save baseline")
+		return
+	}
+	if err := os.Rename(file+".tmp", file); err != nil {
+		log.WithFields(log.Fields{"id": id, "error": err}).Error("FMON: save baseline")
+	}
+}
+
+// the baseline is only recorded once, a profile update or an enforcer restart should not approve the drifted contents
+func (w *FileWatch) recordBaselines(id string, rootPid int, dirList map[string]*osutil.FileInfoExt, singleFiles []*osutil.FileInfoExt) {
+	files := make([]*osutil.FileInfoExt, 0)
+	for _, finfo := range singleFiles {
+		if isBaselineFile(finfo) {
+			files = append(files, finfo)
+		}
+	}
+	for _, dir := range dirList {
+		if dir == nil {
+			continue
+		}
+		for _, finfo := range dir.Children {
+			if isBaselineFile(finfo) {
+				files = append(files, finfo)
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		return
+	}
+
+	w.mux.Lock()
+	set, ok := w.baselines[rootPid]
+	if !ok {
+		set = &containerBaselines{id: id, files: make(map[string]*fileBaseline)}
+		w.baselines[rootPid] = set
+	}
+	w.mux.Unlock()
+
+	records := w.loadBaselines(id)
+
+	var changed bool
+	now := time.Now().UTC()
+	for _, finfo := range files {
+		w.mux.Lock()
+		_, ok := set.files[finfo.Path]
+		w.mux.Unlock()
+		if ok {
+			continue
+		}
+
+		_, path := global.SYS.ParseContainerFilePath(finfo.Path)
+		hash, err := hashBaselineFile(finfo.Path)
+		if err != nil {
+			mLog.WithFields(log.Fields{"path": finfo.Path, "error": err}).Debug("FMON: baseline")
+			if _, ok := records[path]; !ok {
+				continue
+			}
+		}
+
+		bl := &fileBaseline{path: path, hash: hash, at: now}
+		if rec, ok := records[path]; ok {
+			bl.hash = rec.Hash
+			bl.at = time.Unix(rec.At, 0).UTC()
+			if hash != "" && hash != rec.Hash {
+				bl.current = hash // drifted while it was not watched
+			}
+		} else {
+			changed = true
+		}
+
+		w.mux.Lock()
+		set.files[finfo.Path] = bl
+		w.mux.Unlock()
+	}
+
+	if changed {
+		w.saveBaselines(rootPid)
+	}
+}
+
+func (w *FileWatch) isBaselineEvent(rootPid int, fullPath string, event uint32) bool {
+	if event != fileEventModified && event != fileEventAccessed && event != fileEventMovedTo {
+		return false
+	}
+
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if set, ok := w.baselines[rootPid]; ok {
+		_, ok = set.files[fullPath]
+		return ok
+	}
+	return false
+}
+
+// the event is reported after the content is hashed by the drift worker
+func (w *FileWatch) queueDriftCheck(rootPid int, fmod *fileMod, path, fullPath string, event uint32) {
+	w.mux.Lock()
+	if dc, ok := w.driftPending[fullPath]; ok && dc.event == fileEventModified && event == fileEventAccessed {
+		event = fileEventModified // keep the stronger event of the merged ones
+	}
+	w.driftPending[fullPath] = &driftCheck{rootPid: rootPid, path: path, fmod: fmod, event: event}
+	w.mux.Unlock()
+
+	select {
+	case w.driftChan <- struct{}{}:
+	default: // the worker is already signaled
+	}
+}
+
+func (w *FileWatch) driftLoop() {
+	for range w.driftChan {
+		w.checkPendingDrifts()
+	}
+}
+
+func (w *FileWatch) checkPendingDrifts() {
+	w.mux.Lock()
+	pending := w.driftPending
+	w.driftPending = make(map[string]*driftCheck)
+	w.mux.Unlock()
+
+	for fullPath, dc := range pending {
+		event := dc.event
+		if w.checkContentDrift(dc.rootPid, fullPath, event) {
+			event = fileEventContentDrift
+		}
+		w.learnFromEvents(dc.rootPid, dc.fmod, dc.path, event)
+	}
+}
+
+// return true when the content is changed from its baseline, it is reported once for every new content
+func (w *FileWatch) checkContentDrift(rootPid int, fullPath string, event uint32) bool {
+	if event != fileEventModified && event != fileEventAccessed && event != fileEventMovedTo {
+		return false
+	}
+
+	w.mux.Lock()
+	var bl *fileBaseline
+	if set, ok := w.baselines[rootPid]; ok {
+		bl = set.files[fullPath]
+	}
+	w.mux.Unlock()
+	if bl == nil {
+		return false
+	}
+
+	hash, err := hashBaselineFile(fullPath)
+	if err != nil {
+		return false
+	}
+
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if hash == bl.hash {
+		bl.current = "" // restored
+		return false
+	}
+	if hash == bl.current {
+		return false
+	}
+	bl.current = hash
+	return true
+}
+
+func (w *FileWatch) GetFileBaselines(rootPid int) []*share.CLUSFileBaseline {
+	if !w.bEnable {
+		return nil
+	}
+
+	w.mux.Lock()
+	list := make([]*share.CLUSFileBaseline, 0)
+	if set, ok := w.baselines[rootPid]; ok {
+		for _, bl := range set.files {
+			list = append(list, &share.CLUSFileBaseline{
+				Path:       bl.path,
+				Hash:       bl.hash,
+				BaselineAt: bl.at.Unix(),
+				Current:    bl.current,
+				Drifted:    bl.current != "",
+			})
+		}
+	}
+	w.mux.Unlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Path < list[j].Path })
+	return list
+}
+
+// approve the current contents as the new baselines, all files are re-baselined if paths is empty
+func (w *FileWatch) ResetFileBaselines(rootPid int, paths []string) (int, error) {
+	if !w.bEnable {
+		return 0, ErrBaselineNotFound
+	}
+
+	pathSet := utils.NewSetFromStringSlice(paths)
+	fullPaths := make([]string, 0)
+	w.mux.Lock()
+	if set, ok := w.baselines[rootPid]; ok {
+		for fullPath, bl := range set.files {
+			if pathSet.Cardinality() == 0 || pathSet.Contains(bl.path) {
+				fullPaths = append(fullPaths, fullPath)
+			}
+		}
+	}
+	w.mux.Unlock()
+
+	if len(fullPaths) == 0 {
+		return 0, ErrBaselineNotFound
+	}
+
+	var cnt int
+	now := time.Now().UTC()
+	for _, fullPath := range fullPaths {
+		hash, err := hashBaselineFile(fullPath)
+		if err != nil {
+			mLog.WithFields(log.Fields{"path": fullPath, "error": err}).Debug("FMON: baseline")
+			continue
+		}
+
+		w.mux.Lock()
+		if set, ok := w.baselines[rootPid]; ok {
+			if bl, ok := set.files[fullPath]; ok {
+				bl.hash = hash
+				bl.current = ""
+				bl.at = now
+				cnt++
+			}
+		}
+		w.mux.Unlock()
+	}
+
+	if cnt > 0 {
+		w.saveBaselines(rootPid)
+	}
+	return cnt, nil
+}
+
+// the approved hashes are removed with the container
+func (w *FileWatch) RemoveFileBaselines(id string) {
+	if w.baselineDir == "" {
+		return
+	}
+	if err := os.Remove(w.baselineFile(id)); err != nil && !os.IsNotExist(err) {
+		log.WithFields(log.Fields{"id": id, "error": err}).Error("FMON: remove baseline")
+	}
+}
@@ -0,0 +1,203 @@
+package fsmon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neuvector/neuvector/share/global"
+	"github.com/neuvector/neuvector/share/osutil"
+	"github.com/neuvector/neuvector/share/system"
+)
+
+func newBaselineWatch(dir string) *FileWatch {
+	return &FileWatch{
+		bEnable:      true,
+		groups:       make(map[int]*groupInfo),
+		baselines:    make(map[int]*containerBaselines),
+		baselineDir:  dir,
+		driftPending: make(map[string]*driftCheck),
+		driftChan:    make(chan struct{}, 1),
+	}
+}
+
+// create a monitored file and return its path as seen through the root of the process
+func prepareBaselineFile(t *testing.T, content string) (int, string, string) {
+	if global.SYS == nil {
+		global.SYS = system.NewSystemTools()
+	}
+
+	dir, err := ioutil.TempDir("", "fbaseline")
+	if err != nil {
+		t.Fatalf("Failed to create directory: %v\n", err)
+	}
+	path := filepath.Join(dir, "app.conf")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v\n", err)
+	}
+
+	pid := os.Getpid()
+	return pid, path, global.SYS.ContainerFilePath(pid, path)
+}
+
+func baselineFileInfo(fullPath string) []*osutil.FileInfoExt {
+	return []*osutil.FileInfoExt{{Path: fullPath, Filter: &filterRegex{path: fullPath, baseline: true}}}
+}
+
+func TestFileBaselineDrift(t *testing.T) {
+	pid, path, fullPath := prepareBaselineFile(t, "port=80\n")
+	defer os.RemoveAll(filepath.Dir(path))
+
+	w := newBaselineWatch("")
+	w.recordBaselines("c1", pid, nil, baselineFileInfo(fullPath))
+	if bls := w.GetFileBaselines(pid); len(bls) != 1 || bls[0].Path != path || bls[0].Drifted {
+		t.Fatalf("Unexpected baselines: %+v\n", bls)
+	}
+
+	if w.checkContentDrift(pid, fullPath, fileEventModified) {
+		t.Errorf("Unchanged content should not drift\n")
+	}
+
+	ioutil.WriteFile(path, []byte("port=8080\n"), 0644)
+	if !w.checkContentDrift(pid, fullPath, fileEventModified) {
+		t.Errorf("Changed content should drift\n")
+	}
+	if w.checkContentDrift(pid, fullPath, fileEventAccessed) {
+		t.Errorf("The same drifted content should be reported once\n")
+	}
+	if bls := w.GetFileBaselines(pid); len(bls) != 1 || !bls[0].Drifted {
+		t.Errorf("Drift is not recorded: %+v\n", bls)
+	}
+	if w.checkContentDrift(pid, fullPath, fileEventAttr) {
+		t.Errorf("Attribute change should not be checked\n")
+	}
+
+	ioutil.WriteFile(path, []byte("port=80\n"), 0644)
+	if w.checkContentDrift(pid, fullPath, fileEventModified) {
+		t.Errorf("Restored content should not drift\n")
+	}
+	if bls := w.GetFileBaselines(pid); len(bls) != 1 || bls[0].Drifted {
+		t.Errorf("Restored content is still drifted: %+v\n", bls)
+	}
+}
+
+func TestFileBaselinePersist(t *testing.T) {
+	pid, path, fullPath := prepareBaselineFile(t, "port=80\n")
+	defer os.RemoveAll(filepath.Dir(path))
+
+	dir, _ := ioutil.TempDir("", "fbaseline-store")
+	defer os.RemoveAll(dir)
+
+	w := newBaselineWatch(dir)
+	w.recordBaselines("c1", pid, nil, baselineFileInfo(fullPath))
+	approved := w.GetFileBaselines(pid)[0].Hash
+
+	// the content is changed while the enforcer restarts
+	ioutil.WriteFile(path, []byte("port=8080\n"), 0644)
+
+	w = newBaselineWatch(dir)
+	w.recordBaselines("c1", pid, nil, baselineFileInfo(fullPath))
+	bls := w.GetFileBaselines(pid)
+	if len(bls) != 1 || bls[0].Hash != approved || !bls[0].Drifted {
+		t.Errorf("Changed content should not be the new baseline: %+v\n", bls)
+	}
+
+	// a profile update restarts the watch, the container cleanup drops the cached baselines
+	delete(w.baselines, pid)
+	w.recordBaselines("c1", pid, nil, baselineFileInfo(fullPath))
+	if bls := w.GetFileBaselines(pid); len(bls) != 1 || bls[0].Hash != approved {
+		t.Errorf("Baseline is changed by a restarted watch: %+v\n", bls)
+	}
+
+	w.RemoveFileBaselines("c1")
+	if _, err := os.Stat(w.baselineFile("c1")); !os.IsNotExist(err) {
+		t.Errorf("Baseline file is not removed: %v\n", err)
+	}
+}
+
+func TestFileBaselineReset(t *testing.T) {
+	pid, path, fullPath := prepareBaselineFile(t, "port=80\n")
+	defer os.RemoveAll(filepath.Dir(path))
+
+	dir, _ := ioutil.TempDir("", "fbaseline-store")
+	defer os.RemoveAll(dir)
+
+	w := newBaselineWatch(dir)
+	if _, err := w.ResetFileBaselines(pid, nil); err != ErrBaselineNotFound {
+		t.Errorf("Reset should fail without baseline: %v\n", err)
+	}
+
+	w.recordBaselines("c1", pid, nil, baselineFileInfo(fullPath))
+	ioutil.WriteFile(path, []byte("port=8080\n"), 0644)
+	w.checkContentDrift(pid, fullPath, fileEventModified)
+
+	if _, err := w.ResetFileBaselines(pid, []string{"/etc/other.conf"}); err != ErrBaselineNotFound {
+		t.Errorf("Reset should fail when no path matches: %v\n", err)
+	}
+	if cnt, err := w.ResetFileBaselines(pid, []string{path}); err != nil || cnt != 1 {
+		t.Errorf("Failed to reset baseline: cnt=%v error=%v\n", cnt, err)
+	}
+	approved := w.GetFileBaselines(pid)[0]
+	if approved.Drifted {
+		t.Errorf("Reset baseline should not drift: %+v\n", approved)
+	}
+
+	// the approved content is kept after restart
+	w = newBaselineWatch(dir)
+	w.recordBaselines("c1", pid, nil, baselineFileInfo(fullPath))
+	if bls := w.GetFileBaselines(pid); len(bls) != 1 || bls[0].Hash != approved.Hash || bls[0].Drifted {
+		t.Errorf("Reset baseline is not persisted: %+v\n", bls)
+	}
+}
+
+func TestFileBaselineQueue(t *testing.T) {
+	pid, path, fullPath := prepareBaselineFile(t, "port=80\n")
+	defer os.RemoveAll(filepath.Dir(path))
+
+	w := newBaselineWatch("")
+	if w.isBaselineEvent(pid, fullPath, fileEventModified) {
+		t.Errorf("File without baseline should not be queued\n")
+	}
+
+	w.recordBaselines("c1", pid, nil, baselineFileInfo(fullPath))
+	if !w.isBaselineEvent(pid, fullPath, fileEventModified) || w.isBaselineEvent(pid, fullPath, fileEventAttr) {
+		t.Errorf("Unexpected baseline events\n")
+	}
+
+	// events of the same file are merged before the content is hashed
+	fmod := &fileMod{finfo: baselineFileInfo(fullPath)[0]}
+	w.queueDriftCheck(pid, fmod, path, fullPath, fileEventModified)
+	w.queueDriftCheck(pid, fmod, path, fullPath, fileEventAccessed)
+	if len(w.driftPending) != 1 || w.driftPending[fullPath].event != fileEventModified {
+		t.Errorf("Unexpected pending checks: %+v\n", w.driftPending)
+	}
+	if len(w.driftChan) != 1 {
+		t.Errorf("Drift worker is not signaled\n")
+	}
+
+	ioutil.WriteFile(path, []byte("port=8080\n"), 0644)
+	w.checkPendingDrifts()
+	if len(w.driftPending) != 0 {
+		t.Errorf("Pending checks are not handled: %+v\n", w.driftPending)
+	}
+	if bls := w.GetFileBaselines(pid); len(bls) != 1 || !bls[0].Drifted {
+		t.Errorf("Drift is not recorded: %+v\n", bls)
+	}
+}
+
+func TestFileBaselineSizeLimit(t *testing.T) {
+	pid, path, fullPath := prepareBaselineFile(t, "")
+	defer os.RemoveAll(filepath.Dir(path))
+
+	os.Truncate(path, baselineMaxFileSize+1) // sparse
+	if _, err := hashBaselineFile(fullPath); err == nil {
+		t.Errorf("Large file should not be hashed\n")
+	}
+
+	w := newBaselineWatch("")
+	w.recordBaselines("c1", pid, nil, baselineFileInfo(fullPath))
+	if bls := w.GetFileBaselines(pid); len(bls) != 0 {
+		t.Errorf("Large file should not be baselined: %+v\n", bls)
+	}
+}
@@ -92,6 +92,7 @@ const (
 	fileEventMovedTo
 	fileEventDirMovedFrom
 	fileEventDirMovedTo
+	fileEventContentDrift
 )
 
 var fileEventMsg = map[uint32]string{
@@ -113,6 +114,7 @@ var fileEventMsg = map[uint32]string{
 	fileEventMovedTo:        "File was moved to.",
 	fileEventDirMovedFrom:   "Directory was moved from.",
 	fileEventDirMovedTo:     "Directory was moved to.",
+	fileEventContentDrift:   "File content drifted from its baseline.",
 }
 
 type SendFileAccessRuleCallback func(rules []*share.CLUSFileAccessRuleReq) error
@@ -134,17 +136,21 @@ type groupInfo struct {
 }
 
 type FileWatch struct {
-	mux        sync.Mutex
-	bEnable    bool // profile function is enabled, default: true
-	aufs       bool
-	fanotifier *FaNotify
-	inotifier  *Inotify
-	fileEvents map[string]*fileMod
-	groups     map[int]*groupInfo
-	sendrpt    SendAggregateReportCallback
-	sendRule   SendFileAccessRuleCallback
-	estRuleSrc EstimateRuleSrcCallback
-	walkerTask *workerlet.Tasker
+	mux          sync.Mutex
+	bEnable      bool // profile function is enabled, default: true
+	aufs         bool
+	fanotifier   *FaNotify
+	inotifier    *Inotify
+	fileEvents   map[string]*fileMod
+	groups       map[int]*groupInfo
+	sendrpt      SendAggregateReportCallback
+	sendRule     SendFileAccessRuleCallback
+	estRuleSrc   EstimateRuleSrcCallback
+	walkerTask   *workerlet.Tasker
+	baselines    map[int]*containerBaselines
+	baselineDir  string
+	driftPending map[string]*driftCheck
+	driftChan    chan struct{}
 }
 
 type MonitorMessage struct {
@@ -165,6 +171,7 @@ type MonitorMessage struct {
 	Count     int
 	StartAt   time.Time
 	Action    string
+	Drift     bool
 }
 
 type ProcInfo struct {
@@ -219,6 +226,8 @@ type FileMonitorConfig struct {
 	SendReport     SendAggregateReportCallback
 	SendAccessRule SendFileAccessRuleCallback
 	EstRule        EstimateRuleSrcCallback
+	InotifyOnly    bool   // fanotify requires CAP_SYS_ADMIN, which is not granted in the restricted mode
+	BaselineDir    string // where the approved file hashes are kept
 }
 
 func NewFileWatcher(config *FileMonitorConfig) (*FileWatch, error) {
@@ -231,14 +240,18 @@ func NewFileWatcher(config *FileMonitorConfig) (*FileWatch, error) {
 	}
 
 	fw := &FileWatch{
-		bEnable:    config.ProfileEnable,
-		aufs:       config.IsAufs,
-		fileEvents: make(map[string]*fileMod),
-		groups:     make(map[int]*groupInfo),
-		sendrpt:    config.SendReport,
-		sendRule:   config.SendAccessRule,
-		estRuleSrc: config.EstRule,
-		walkerTask: config.WalkerTask,
+		bEnable:      config.ProfileEnable,
+		aufs:         config.IsAufs,
+		fileEvents:   make(map[string]*fileMod),
+		groups:       make(map[int]*groupInfo),
+		sendrpt:      config.SendReport,
+		sendRule:     config.SendAccessRule,
+		estRuleSrc:   config.EstRule,
+		walkerTask:   config.WalkerTask,
+		baselines:    make(map[int]*containerBaselines),
+		baselineDir:  config.BaselineDir,
+		driftPending: make(map[string]*driftCheck),
+		driftChan:    make(chan struct{}, 1),
 	}
 
 	if !fw.bEnable {
@@ -247,8 +260,13 @@ func NewFileWatcher(config *FileMonitorConfig) (*FileWatch, error) {
 		return fw, nil
 	}
 
-	n, err := NewFaNotify(config.EndChan, config.PidLookup, global.SYS)
-	if err != nil {
+	var n *FaNotify
+	var err error
+	if config.InotifyOnly {
+		// file modifications are still reported by inotify, but not the file accesses
+		log.Info("File access monitor is disabled, monitor file changes by inotify")
+		config.EndChan <- true
+	} else if n, err = NewFaNotify(config.EndChan, config.PidLookup, global.SYS); err != nil {
 		log.WithFields(log.Fields{"error": err}).Error("Open fanotify fail")
 		return nil, err
 	}
@@ -259,13 +277,16 @@ func NewFileWatcher(config *FileMonitorConfig) (*FileWatch, error) {
 		return nil, err
 	}
 
-	go n.MonitorFileEvents()
+	if n != nil {
+		go n.MonitorFileEvents()
+	}
 	go ni.MonitorFileEvents()
 
 	fw.fanotifier = n
 	fw.inotifier = ni
 
 	go fw.loop()
+	go fw.driftLoop()
 	return fw, nil
 }
 
@@ -286,6 +307,7 @@ func (w *FileWatch) sendMsg(cid string, path string, event uint32, pInfo []*Proc
 			Package: osutil.IsPackageLib(path),
 			Msg:     eventMsg,
 			Action:  share.PolicyActionViolate,
+			Drift:   event == fileEventContentDrift,
 		}
 
 		w.sendrpt(&msg)
@@ -302,6 +324,7 @@ func (w *FileWatch) sendMsg(cid string, path string, event uint32, pInfo []*Proc
 				Package: osutil.IsPackageLib(path),
 				Msg:     eventMsg,
 				Action:  share.PolicyActionViolate,
+				Drift:   event == fileEventContentDrift,
 			}
 			if pi != nil {
 				msg.ProcName = pi.Name
@@ -488,7 +511,9 @@ func (w *FileWatch) UpdateAccessRules(name string, rootPid int, conf *share.CLUS
 	}
 	w.mux.Unlock()
 
-	w.fanotifier.UpdateAccessRule(rootPid, conf)
+	if w.fanotifier != nil {
+		w.fanotifier.UpdateAccessRule(rootPid, conf)
+	}
 }
 
 func (w *FileWatch) Close() {
@@ -535,6 +560,11 @@ func (w *FileWatch) addFile(bIncInotify bool, finfo *osutil.FileInfoExt) {
 		return
 	}
 
+	if w.fanotifier == nil {
+		w.inotifier.AddMonitorFile(finfo.Path, w.cbNotify, finfo)
+		return
+	}
+
 	w.fanotifier.AddMonitorFile(finfo.Path, finfo.Filter, finfo.Protect, finfo.UserAdded, w.cbNotify, finfo)
 	//if _, path := global.SYS.ParseContainerFilePath(finfo.Path); packageFile.Contains(path) {
 	flt := finfo.Filter.(*filterRegex)
@@ -544,7 +574,9 @@ func (w *FileWatch) addFile(bIncInotify bool, finfo *osutil.FileInfoExt) {
 }
 
 func (w *FileWatch) removeFile(fullpath string) {
-	w.fanotifier.RemoveMonitorFile(fullpath) // should not
+	if w.fanotifier != nil {
+		w.fanotifier.RemoveMonitorFile(fullpath) // should not
+	}
 	w.inotifier.RemoveMonitorFile(fullpath)
 }
 
@@ -558,6 +590,11 @@ func (w *FileWatch) addDir(bIncInotify bool, finfo *osutil.FileInfoExt, files ma
 		ff[fpath] = fi
 	}
 
+	if w.fanotifier == nil {
+		w.inotifier.AddMonitorDirFile(finfo.Path, nil, w.cbNotify, finfo)
+		return
+	}
+
 	w.fanotifier.AddMonitorDirFile(finfo.Path, finfo.Filter, finfo.Protect, finfo.UserAdded, ff, w.cbNotify, finfo)
 	if bIncInotify {
 		w.inotifier.AddMonitorDirFile(finfo.Path, nil, w.cbNotify, finfo)
@@ -583,7 +620,7 @@ func (w *FileWatch) getCoreFile(cid string, pid int, profile *share.CLUSFileMoni
 
 	// get files and dirs from all filters
 	for _, filter := range profile.Filters {
-		flt := &filterRegex{path: filterIndexKey(filter), recursive: filter.Recursive}
+		flt := &filterRegex{path: filterIndexKey(filter), recursive: filter.Recursive, baseline: filter.Baseline}
 		flt.regex, _ = regexp.Compile(fmt.Sprintf("^%s$", flt.path))
 		bBlockAccess := filter.Behavior == share.FileAccessBehaviorBlock
 		bUserAdded := filter.CustomerAdd
@@ -601,7 +638,7 @@ func (w *FileWatch) getCoreFile(cid string, pid int, profile *share.CLUSFileMoni
 
 	// get files and dirs from all filters
 	for _, filter := range profile.FiltersCRD {
-		flt := &filterRegex{path: filterIndexKey(filter), recursive: filter.Recursive}
+		flt := &filterRegex{path: filterIndexKey(filter), recursive: filter.Recursive, baseline: filter.Baseline}
 		flt.regex, _ = regexp.Compile(fmt.Sprintf("^%s$", flt.path))
 		bBlockAccess := filter.Behavior == share.FileAccessBehaviorBlock
 		bUserAdded := filter.CustomerAdd
@@ -689,12 +726,17 @@ func (w *FileWatch) StartWatch(id string, rootPid int, conf *FsmonConfig, capBlo
 		}
 	}
 	dirs, files := w.getCoreFile(id, rootPid, conf.Profile)
+	w.recordBaselines(id, rootPid, dirs, files)
 
-	w.fanotifier.SetMode(rootPid, access, perm, capBlock, bNeuvectorSvc)
+	if w.fanotifier != nil {
+		w.fanotifier.SetMode(rootPid, access, perm, capBlock, bNeuvectorSvc)
+	}
 
 	w.addCoreFile(!bNeuvectorSvc, id, dirs, files)
 
-	w.fanotifier.StartMonitor(rootPid)
+	if w.fanotifier != nil {
+		w.fanotifier.StartMonitor(rootPid)
+	}
 
 	w.mux.Lock()
 	grp, ok := w.groups[rootPid]
@@ -747,7 +789,11 @@ func (w *FileWatch) HandleWatchedFiles() {
 				event = w.handleFileEvents(fmod, info, fullPath, pid)
 			}
 			if event != 0 {
-				w.learnFromEvents(pid, fmod, path, event)
+				if w.isBaselineEvent(pid, fullPath, event) {
+					w.queueDriftCheck(pid, fmod, path, fullPath, event)
+				} else {
+					w.learnFromEvents(pid, fmod, path, event)
+				}
 			}
 		}
 	}
@@ -893,22 +939,25 @@ func (w *FileWatch) ContainerCleanup(rootPid int) {
 	if !w.bEnable {
 		return
 	}
-	w.fanotifier.ContainerCleanup(rootPid)
+	if w.fanotifier != nil {
+		w.fanotifier.ContainerCleanup(rootPid)
+	}
 	w.inotifier.ContainerCleanup(rootPid)
 	w.mux.Lock()
 	defer w.mux.Unlock()
 	delete(w.groups, rootPid)
+	delete(w.baselines, rootPid)
 }
 
 func (w *FileWatch) GetWatchFileList(rootPid int) []*share.CLUSFileMonitorFile {
-	if !w.bEnable {
+	if !w.bEnable || w.fanotifier == nil {
 		return nil
 	}
 	return w.fanotifier.GetWatchFileList(rootPid)
 }
 
 func (w *FileWatch) GetAllFileMonitorFile() []*share.CLUSFileMonitorFile {
-	if !w.bEnable {
+	if !w.bEnable || w.fanotifier == nil {
 		return nil
 	}
 	return w.fanotifier.GetWatches()
@@ -11,6 +11,7 @@ type filterRegex struct {
 	path  string
 	regex *regexp.Regexp
 	recursive bool
+	baseline bool // record the content hash as integrity baseline
 }
 
 type IFile struct {
@@ -34,6 +34,16 @@ func SetGlobalObjects(rtSocket string, regResource RegisterDriverFunc) (string,
 	SYS = system.NewSystemTools()
 
 	RT, err = container.Connect(rtSocket, SYS)
+	if err == container.ErrUnknownRuntime && rtSocket == "" && !SYS.IsRunningInContainer() {
+		// virtual machine or bare-metal host without container runtime
+		if RT, err = container.ConnectHost(SYS); err == nil {
+			ORCH = &orchHub{Driver: orchAPI.GetDriver("", "", share.NetworkDefault, "", "", SYS, RT)}
+			if regResource != nil {
+				ORCH.ResourceDriver = regResource("", "", share.NetworkDefault)
+			}
+			return "", "", share.NetworkDefault, nil, nil
+		}
+	}
  	if err != nil {
 		return "", "", "", nil, err
 	}
@@ -92,6 +102,12 @@ func getContainerPlatform(c *container.ContainerMeta) string {
 	if strings.HasPrefix(c.Image, container.ECSAgentImagePrefix) {
 		return share.PlatformAmazonECS
 	}
+	if _, ok := c.Labels[container.ECSCluster]; ok {
+		return share.PlatformAmazonECS
+	}
+	if _, ok := c.Labels[container.NomadAllocID]; ok {
+		return share.PlatformNomad
+	}
 
 	return share.PlatformDocker
 }
@@ -108,6 +124,8 @@ func normalize(platform, flavor string) (string, string) {
 		platform = share.PlatformRancher
 	case strings.ToLower(share.PlatformAliyun):
 		platform = share.PlatformAliyun
+	case strings.ToLower(share.PlatformNomad):
+		platform = share.PlatformNomad
 	}
 
 	switch strings.ToLower(flavor) {
@@ -123,6 +141,10 @@ func normalize(platform, flavor string) (string, string) {
 		flavor = share.FlavorIKE
 	case strings.ToLower(share.FlavorGKE):
 		flavor = share.FlavorGKE
+	case strings.ToLower(share.FlavorK3s):
+		flavor = share.FlavorK3s
+	case strings.ToLower(share.FlavorMicroK8s):
+		flavor = share.FlavorMicroK8s
 	}
 
 	return platform, flavor
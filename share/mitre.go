@@ -0,0 +1,137 @@
+package share
+
+// MITRE ATT&CK tactics and techniques of the built-in runtime detections
+
+const (
+	MitreTacticReconnaissance      = "TA0043"
+	MitreTacticInitialAccess       = "TA0001"
+	MitreTacticExecution           = "TA0002"
+	MitreTacticPersistence         = "TA0003"
+	MitreTacticPrivilegeEscalation = "TA0004"
+	MitreTacticDefenseEvasion      = "TA0005"
+	MitreTacticCredentialAccess    = "TA0006"
+	MitreTacticDiscovery           = "TA0007"
+	MitreTacticCollection          = "TA0009"
+	MitreTacticCommandAndControl   = "TA0011"
+	MitreTacticImpact              = "TA0040"
+)
+
+// In the order of the ATT&CK enterprise matrix
+var CLUSMitreTactics = []struct {
+	ID   string
+	Name string
+}{
+	{MitreTacticReconnaissance, "Reconnaissance"},
+	{MitreTacticInitialAccess, "Initial Access"},
+	{MitreTacticExecution, "Execution"},
+	{MitreTacticPersistence, "Persistence"},
+	{MitreTacticPrivilegeEscalation, "Privilege Escalation"},
+	{MitreTacticDefenseEvasion, "Defense Evasion"},
+	{MitreTacticCredentialAccess, "Credential Access"},
+	{MitreTacticDiscovery, "Discovery"},
+	{MitreTacticCollection, "Collection"},
+	{MitreTacticCommandAndControl, "Command and Control"},
+	{MitreTacticImpact, "Impact"},
+}
+
+const (
+	MitreTechGatherDNS            = "T1590.002"
+	MitreTechExploitPublicApp     = "T1190"
+	MitreTechCommandInterpreter   = "T1059"
+	MitreTechKernelModules        = "T1547.006"
+	MitreTechExploitPrivilege     = "T1068"
+	MitreTechEscapeToHost         = "T1611"
+	MitreTechBoundaryBridging     = "T1599"
+	MitreTechBruteForce           = "T1110"
+	MitreTechAdversaryInTheMiddle = "T1557"
+	MitreTechNetworkServiceDisc   = "T1046"
+	MitreTechLocalData            = "T1005"
+	MitreTechIngressToolTransfer  = "T1105"
+	MitreTechProtocolTunneling    = "T1572"
+	MitreTechNonAppLayerProtocol  = "T1095"
+	MitreTechDNSProtocol          = "T1071.004"
+	MitreTechStoredDataManip      = "T1565.001"
+	MitreTechResourceHijacking    = "T1496"
+	MitreTechNetworkDoS           = "T1498"
+	MitreTechEndpointDoS          = "T1499"
+)
+
+type CLUSMitreTechnique struct {
+	Name   string
+	Tactic string
+}
+
+var CLUSMitreTechniques = map[string]CLUSMitreTechnique{
+	MitreTechGatherDNS:            {"Gather Victim Network Information: DNS", MitreTacticReconnaissance},
+	MitreTechExploitPublicApp:     {"Exploit Public-Facing Application", MitreTacticInitialAccess},
+	MitreTechCommandInterpreter:   {"Command and Scripting Interpreter", MitreTacticExecution},
+	MitreTechKernelModules:        {"Boot or Logon Autostart Execution: Kernel Modules and Extensions", MitreTacticPersistence},
+	MitreTechExploitPrivilege:     {"Exploitation for Privilege Escalation", MitreTacticPrivilegeEscalation},
+	MitreTechEscapeToHost:         {"Escape to Host", MitreTacticPrivilegeEscalation},
+	MitreTechBoundaryBridging:     {"Network Boundary Bridging", MitreTacticDefenseEvasion},
+	MitreTechBruteForce:           {"Brute Force", MitreTacticCredentialAccess},
+	MitreTechAdversaryInTheMiddle: {"Adversary-in-the-Middle", MitreTacticCredentialAccess},
+	MitreTechNetworkServiceDisc:   {"Network Service Discovery", MitreTacticDiscovery},
+	MitreTechLocalData:            {"Data from Local System", MitreTacticCollection},
+	MitreTechIngressToolTransfer:  {"Ingress Tool Transfer", MitreTacticCommandAndControl},
+	MitreTechProtocolTunneling:    {"Protocol Tunneling", MitreTacticCommandAndControl},
+	MitreTechNonAppLayerProtocol:  {"Non-Application Layer Protocol", MitreTacticCommandAndControl},
+	MitreTechDNSProtocol:          {"Application Layer Protocol: DNS", MitreTacticCommandAndControl},
+	MitreTechStoredDataManip:      {"Data Manipulation: Stored Data Manipulation", MitreTacticImpact},
+	MitreTechResourceHijacking:    {"Resource Hijacking", MitreTacticImpact},
+	MitreTechNetworkDoS:           {"Network Denial of Service", MitreTacticImpact},
+	MitreTechEndpointDoS:          {"Endpoint Denial of Service", MitreTacticImpact},
+}
+
+func appendUniq(list []string, v string) []string {
+	for _, e := range list {
+		if e == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
+
+// The tactics of the techniques, without duplicates
+func MitreTactics(techniques []string) []string {
+	var tactics []string
+	for _, id := range techniques {
+		if t, ok := CLUSMitreTechniques[id]; ok {
+			tactics = appendUniq(tactics, t.Tactic)
+		}
+	}
+	return tactics
+}
+
+// Merge the techniques reported with the log into the built-in ones
+func MergeMitreTechniques(builtin, reported []string) []string {
+	var techniques []string
+	for _, id := range builtin {
+		techniques = appendUniq(techniques, id)
+	}
+	for _, id := range reported {
+		techniques = appendUniq(techniques, id)
+	}
+	return techniques
+}
+
+var CLUSIncidentMitreMap = map[TLogIncident][]string{
+	CLUSIncidHostPrivilEscalate:           {MitreTechExploitPrivilege},
+	CLUSIncidContainerPrivilEscalate:      {MitreTechExploitPrivilege},
+	CLUSIncidHostSuspiciousProcess:        {MitreTechCommandInterpreter},
+	CLUSIncidContainerSuspiciousProcess:   {MitreTechCommandInterpreter},
+	CLUSIncidHostFileAccessViolation:      {MitreTechLocalData},
+	CLUSIncidContainerFileAccessViolation: {MitreTechLocalData},
+	CLUSIncidHostPackageUpdated:           {MitreTechIngressToolTransfer},
+	CLUSIncidContainerPackageUpdated:      {MitreTechIngressToolTransfer},
+	CLUSIncidHostTunnel:                   {MitreTechProtocolTunneling},
+	CLUSIncidContainerTunnel:              {MitreTechProtocolTunneling},
+	CLUSIncidHostProcessViolation:         {MitreTechCommandInterpreter},
+	CLUSIncidContainerProcessViolation:    {MitreTechCommandInterpreter},
+	CLUSIncidHostFileContentDrift:         {MitreTechStoredDataManip},
+	CLUSIncidContainerFileContentDrift:    {MitreTechStoredDataManip},
+	CLUSIncidHostKernelLoad:               {MitreTechKernelModules},
+	CLUSIncidContainerKernelLoad:          {MitreTechKernelModules, MitreTechEscapeToHost},
+	CLUSIncidContainerEscape:              {MitreTechEscapeToHost},
+	CLUSIncidContainerCryptoMining:        {MitreTechResourceHijacking},
+}
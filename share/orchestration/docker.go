@@ -94,6 +94,13 @@ func (d *base) GetService(meta *container.ContainerMeta, node string) *Service {
 		return &Service{Name: project + "." + service}
 	} else if service, _ = meta.Labels[container.DockerSwarmServiceKey]; service != "" {
 		return &Service{Name: service}
+	} else if unit, _ := meta.Labels[container.PodmanSystemdUnitKey]; unit != "" {
+		// podman containers managed by systemd units, the instance of a template unit is removed.
+		unit = strings.TrimSuffix(unit, ".service")
+		if i := strings.Index(unit, "@"); i > 0 {
+			unit = unit[:i]
+		}
+		return &Service{Name: unit}
 	}
 
 	return &Service{Name: container.TrimContainerImageRepo(container.TrimContainerImageVersion(meta.Image))}
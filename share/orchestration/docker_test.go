@@ -32,6 +32,36 @@ func TestServiceName(t *testing.T) {
 	}
 }
 
+func TestPodmanSystemdServiceName(t *testing.T) {
+	var driver base
+
+	meta := container.ContainerMeta{
+		Image:  "registry.access.redhat.com/ubi8/nginx-120:latest",
+		Labels: make(map[string]string),
+	}
+
+	meta.Labels[container.PodmanSystemdUnitKey] = "web.service"
+	svc := driver.GetService(&meta, "")
+	expect := "web"
+	if svc.Name != expect {
+		t.Errorf("Error: expect=%v actual=%v\n", expect, svc)
+	}
+
+	meta.Labels[container.PodmanSystemdUnitKey] = "sensor@2.service"
+	svc = driver.GetService(&meta, "")
+	expect = "sensor"
+	if svc.Name != expect {
+		t.Errorf("Error: expect=%v actual=%v\n", expect, svc)
+	}
+
+	delete(meta.Labels, container.PodmanSystemdUnitKey)
+	svc = driver.GetService(&meta, "")
+	expect = "ubi8/nginx-120"
+	if svc.Name != expect {
+		t.Errorf("Error: expect=%v actual=%v\n", expect, svc)
+	}
+}
+
 func TestPlatformDTR(t *testing.T) {
 	var driver base
 
@@ -2,6 +2,9 @@ package orchestration
 
 import (
 	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
 
 	"github.com/neuvector/neuvector/share"
 	"github.com/neuvector/neuvector/share/container"
@@ -9,6 +12,10 @@ import (
 
 type ecs struct {
 	noop
+
+	lock     sync.Mutex
+	clients  map[string]*ECSClient // region => client
+	services map[string]string     // task arn => service name, empty if the task is not started by a service
 }
 
 var ecsDriver ecs
@@ -33,6 +40,13 @@ func (d *ecs) GetService(meta *container.ContainerMeta, node string) *Service {
 
 	cluster, _ := meta.Labels[container.ECSCluster]
 	task, _ := meta.Labels[container.ECSTaskDefinition]
+	if arn, _ := meta.Labels[container.ECSTaskArn]; arn != "" && cluster != "" {
+		// the containers of the tasks of a service are in the same group
+		if service := d.getTaskService(cluster, arn); service != "" {
+			return &Service{Name: cluster + "." + service}
+		}
+	}
+
 	container, _ := meta.Labels[container.ECSContainerName]
 	if cluster != "" && task != "" && container != "" {
 		return &Service{Name: cluster + "." + task + "." + container}
@@ -43,6 +57,41 @@ func (d *ecs) GetService(meta *container.ContainerMeta, node string) *Service {
 	return baseDriver.GetService(meta, node)
 }
 
+// The task group is only available from the ECS API. The result is cached as the group of a task doesn't change.
+func (d *ecs) getTaskService(cluster, arn string) string {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.services == nil {
+		d.services = make(map[string]string)
+		d.clients = make(map[string]*ECSClient)
+	}
+	if service, ok := d.services[arn]; ok {
+		return service
+	}
+
+	region, _ := ParseECSTaskArn(arn)
+	client, ok := d.clients[region]
+	if !ok {
+		var err error
+		if client, err = NewECSClient(region); err != nil {
+			log.WithFields(log.Fields{"region": region, "error": err}).Error("Failed to create ECS client")
+			return ""
+		}
+		d.clients[region] = client
+	}
+
+	// Not to retry if failed, so the containers of the task are always in the same group
+	var service string
+	if tasks, err := client.DescribeTasks(cluster, []string{arn}); err != nil {
+		log.WithFields(log.Fields{"task": arn, "error": err}).Error("Failed to describe ECS task")
+	} else if len(tasks) > 0 {
+		service = ECSServiceName(tasks[0].Group)
+	}
+	d.services[arn] = service
+	return service
+}
+
 func (d *ecs) GetPlatformRole(m *container.ContainerMeta) (string, bool) {
 	if strings.HasPrefix(m.Image, container.ECSAgentImagePrefix) {
 		return container.PlatformContainerECSAgent, false
@@ -0,0 +1,227 @@
+package orchestration
+
+// The ECS tasks are discovered by the task metadata endpoint and the ECS API. The task metadata endpoint tells
+// the cluster and the region of the calling task, and the ECS API lists the tasks of the cluster, including the
+// Fargate tasks that have no enforcer.
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+const ecsRequestTimeout = time.Second * 30
+const ecsDescribeTasksMax = 100
+const ecsMetadataEnv = "ECS_CONTAINER_METADATA_URI_V4"
+
+const (
+	ECSLaunchTypeEC2     = "EC2"
+	ECSLaunchTypeFargate = "FARGATE"
+)
+
+type ECSTaskContainer struct {
+	Name      string `json:"name"`
+	RuntimeID string `json:"runtimeId"`
+}
+
+type ECSTaskAttachment struct {
+	Type    string `json:"type"`
+	Details []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"details"`
+}
+
+type ECSTask struct {
+	TaskArn              string              `json:"taskArn"`
+	TaskDefinitionArn    string              `json:"taskDefinitionArn"`
+	ClusterArn           string              `json:"clusterArn"`
+	ContainerInstanceArn string              `json:"containerInstanceArn"`
+	Group                string              `json:"group"`
+	LaunchType           string              `json:"launchType"`
+	LastStatus           string              `json:"lastStatus"`
+	Containers           []ECSTaskContainer  `json:"containers"`
+	Attachments          []ECSTaskAttachment `json:"attachments"`
+}
+
+// The metadata of the calling task
+type ECSTaskMetadata struct {
+	Cluster string `json:"Cluster"`
+	TaskARN string `json:"TaskARN"`
+	Family  string `json:"Family"`
+}
+
+// Credentials are resolved by the default chain of aws sdk, including environment variables, the task role
+// and instance role.
+type ECSClient struct {
+	client   *http.Client
+	signer   *v4.Signer
+	endpoint string
+	region   string
+}
+
+func NewECSClient(region string) (*ECSClient, error) {
+	if region == "" {
+		return nil, errors.New("ECS region is not specified")
+	}
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(region)})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ECSClient{
+		client:   &http.Client{Transport: &http.Transport{Proxy: http.ProxyFromEnvironment}, Timeout: ecsRequestTimeout},
+		signer:   v4.NewSigner(sess.Config.Credentials),
+		endpoint: fmt.Sprintf("https://ecs.%s.amazonaws.com", region),
+		region:   region,
+	}, nil
+}
+
+func sendECSRequest(client *http.Client, req *http.Request, resp interface{}) error {
+	r, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if r.StatusCode != http.StatusOK {
+		msg := string(body)
+		if len(msg) > 256 {
+			msg = msg[:256]
+		}
+		return fmt.Errorf("%s %s: %s, %s", req.Method, req.URL.Path, r.Status, msg)
+	}
+	return json.Unmarshal(body, resp)
+}
+
+func (c *ECSClient) call(action string, body, resp interface{}) error {
+	data, _ := json.Marshal(body)
+	req, err := http.NewRequest("POST", c.endpoint+"/", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AmazonEC2ContainerServiceV20141113."+action)
+	if _, err = c.signer.Sign(req, bytes.NewReader(data), "ecs", c.region, time.Now()); err != nil {
+		return err
+	}
+	return sendECSRequest(c.client, req, resp)
+}
+
+// Return the running tasks of the cluster
+func (c *ECSClient) ListTasks(cluster string) ([]*ECSTask, error) {
+	arns := make([]string, 0)
+	var token string
+	for {
+		var resp struct {
+			TaskArns  []string `json:"taskArns"`
+			NextToken string   `json:"nextToken"`
+		}
+		req := map[string]string{"cluster": cluster, "desiredStatus": "RUNNING"}
+		if token != "" {
+			req["nextToken"] = token
+		}
+		if err := c.call("ListTasks", req, &resp); err != nil {
+			return nil, err
+		}
+		arns = append(arns, resp.TaskArns...)
+		if token = resp.NextToken; token == "" {
+			break
+		}
+	}
+
+	return c.DescribeTasks(cluster, arns)
+}
+
+func (c *ECSClient) DescribeTasks(cluster string, arns []string) ([]*ECSTask, error) {
+	tasks := make([]*ECSTask, 0, len(arns))
+	for i := 0; i < len(arns); i += ecsDescribeTasksMax {
+		end := i + ecsDescribeTasksMax
+		if end > len(arns) {
+			end = len(arns)
+		}
+
+		var resp struct {
+			Tasks []*ECSTask `json:"tasks"`
+		}
+		req := map[string]interface{}{"cluster": cluster, "tasks": arns[i:end]}
+		if err := c.call("DescribeTasks", req, &resp); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, resp.Tasks...)
+	}
+	return tasks, nil
+}
+
+// The task metadata endpoint is injected to every container of the ECS tasks
+func GetECSTaskMetadata() (*ECSTaskMetadata, error) {
+	uri := os.Getenv(ecsMetadataEnv)
+	if uri == "" {
+		return nil, errors.New("Not in an ECS task")
+	}
+
+	req, err := http.NewRequest("GET", strings.TrimRight(uri, "/")+"/task", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta ECSTaskMetadata
+	client := &http.Client{Timeout: ecsRequestTimeout}
+	if err = sendECSRequest(client, req, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// The ARN is arn:aws:ecs:<region>:<account>:task/<cluster>/<id>, or arn:aws:ecs:<region>:<account>:task/<id>
+// of the old format, which has no cluster name.
+func ParseECSTaskArn(arn string) (string, string) {
+	tokens := strings.SplitN(arn, ":", 6)
+	if len(tokens) != 6 || tokens[2] != "ecs" {
+		return "", ""
+	}
+
+	var cluster string
+	if res := strings.Split(tokens[5], "/"); len(res) == 3 {
+		cluster = res[1]
+	}
+	return tokens[3], cluster
+}
+
+// The tasks started by a service are in the group of service:<name>
+func ECSServiceName(group string) string {
+	if strings.HasPrefix(group, "service:") {
+		return group[8:]
+	}
+	return ""
+}
+
+// The private IP of the task in the awsvpc network mode, including all Fargate tasks
+func (t *ECSTask) PrivateIPv4() string {
+	for _, a := range t.Attachments {
+		if a.Type != "ElasticNetworkInterface" {
+			continue
+		}
+		for _, d := range a.Details {
+			if d.Name == "privateIPv4Address" {
+				return d.Value
+			}
+		}
+	}
+	return ""
+}
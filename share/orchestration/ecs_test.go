@@ -0,0 +1,67 @@
+package orchestration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/neuvector/neuvector/share/container"
+)
+
+func TestECSTaskArn(t *testing.T) {
+	region, cluster := ParseECSTaskArn("arn:aws:ecs:us-west-2:123456789012:task/prod/0a1b2c3d4e5f")
+	if region != "us-west-2" || cluster != "prod" {
+		t.Errorf("Unexpected task arn: region=%v cluster=%v", region, cluster)
+	}
+	region, cluster = ParseECSTaskArn("arn:aws:ecs:us-east-1:123456789012:task/0a1b2c3d4e5f")
+	if region != "us-east-1" || cluster != "" {
+		t.Errorf("Unexpected task arn: region=%v cluster=%v", region, cluster)
+	}
+	if region, _ = ParseECSTaskArn("arn:aws:kms:us-east-1:123456789012:key/1234"); region != "" {
+		t.Errorf("Unexpected region: %v", region)
+	}
+
+	if name := ECSServiceName("service:web"); name != "web" {
+		t.Errorf("Unexpected service: %v", name)
+	}
+	if name := ECSServiceName("family:web"); name != "" {
+		t.Errorf("Unexpected service: %v", name)
+	}
+}
+
+func TestECSTaskIP(t *testing.T) {
+	data := `{"taskArn":"arn:aws:ecs:us-west-2:123456789012:task/prod/0a1b","launchType":"FARGATE","group":"service:web",
+		"attachments":[{"type":"ElasticNetworkInterface","details":[{"name":"subnetId","value":"subnet-1"},
+		{"name":"privateIPv4Address","value":"10.0.1.25"}]}]}`
+	var task ECSTask
+	if err := json.Unmarshal([]byte(data), &task); err != nil {
+		t.Fatalf("Failed to parse task: %v", err)
+	}
+	if ip := task.PrivateIPv4(); ip != "10.0.1.25" {
+		t.Errorf("Unexpected task IP: %v", ip)
+	}
+}
+
+func TestECSServiceName(t *testing.T) {
+	driver := &ecs{
+		services: map[string]string{"arn:aws:ecs:us-west-2:123456789012:task/prod/0a1b": "web"},
+		clients:  make(map[string]*ECSClient),
+	}
+
+	meta := container.ContainerMeta{
+		Labels: map[string]string{
+			container.ECSCluster:        "prod",
+			container.ECSTaskDefinition: "web-task",
+			container.ECSContainerName:  "nginx",
+			container.ECSTaskArn:        "arn:aws:ecs:us-west-2:123456789012:task/prod/0a1b",
+		},
+	}
+	if svc := driver.GetService(&meta, ""); svc.Name != "prod.web" {
+		t.Errorf("Unexpected service: %v", svc.Name)
+	}
+
+	// not started by a service
+	driver.services[meta.Labels[container.ECSTaskArn]] = ""
+	if svc := driver.GetService(&meta, ""); svc.Name != "prod.web-task.nginx" {
+		t.Errorf("Unexpected service: %v", svc.Name)
+	}
+}
@@ -0,0 +1,73 @@
+package orchestration
+
+import (
+	"strings"
+
+	"github.com/neuvector/neuvector/share"
+	"github.com/neuvector/neuvector/share/container"
+)
+
+// The tasks of a nomad task group share the network namespace like the containers of a kubernetes pod, so
+// they are grouped by the job and task group. The nomad namespace is taken as the domain.
+type nomad struct {
+	noop
+}
+
+func getContainerEnv(envs []string, key string) string {
+	prefix := key + "="
+	for _, e := range envs {
+		if strings.HasPrefix(e, prefix) {
+			return e[len(prefix):]
+		}
+	}
+	return ""
+}
+
+// The labels other than alloc_id are optional, fall back to the nomad environment variables of the task
+func getNomadTaskGroup(meta *container.ContainerMeta) (string, string, string) {
+	job, _ := meta.Labels[container.NomadJobName]
+	if job == "" {
+		job = getContainerEnv(meta.Envs, container.NomadEnvJobName)
+	}
+	group, _ := meta.Labels[container.NomadTaskGroupName]
+	if group == "" {
+		group = getContainerEnv(meta.Envs, container.NomadEnvGroupName)
+	}
+	namespace, _ := meta.Labels[container.NomadNamespace]
+	if namespace == "" {
+		if namespace = getContainerEnv(meta.Envs, container.NomadEnvNamespace); namespace == "" {
+			namespace = container.NomadNamespaceDefault
+		}
+	}
+	return namespace, job, group
+}
+
+func (d *nomad) GetService(meta *container.ContainerMeta, node string) *Service {
+	if seviceName, ok := meta.Labels[container.NeuvectorSetServiceName]; ok {
+		return &Service{Name: seviceName}
+	}
+
+	if _, ok := meta.Labels[container.NomadAllocID]; ok {
+		if namespace, job, group := getNomadTaskGroup(meta); job != "" {
+			if group != "" && group != job {
+				job = job + "." + group
+			}
+			return &Service{Domain: namespace, Name: job}
+		}
+	}
+
+	return baseDriver.GetService(meta, node)
+}
+
+func (d *nomad) GetDomain(labels map[string]string) string {
+	if namespace, ok := labels[container.NomadNamespace]; ok {
+		return namespace
+	}
+	return baseDriver.GetDomain(labels)
+}
+
+func (d *nomad) SetIPAddrScope(ports map[string][]share.CLUSIPAddr,
+	meta *container.ContainerMeta, nets map[string]*container.Network,
+) {
+	baseDriver.SetIPAddrScope(ports, meta, nets)
+}
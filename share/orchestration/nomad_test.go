@@ -0,0 +1,35 @@
+package orchestration
+
+import (
+	"testing"
+
+	"github.com/neuvector/neuvector/share/container"
+)
+
+func TestNomadServiceName(t *testing.T) {
+	driver := &nomad{}
+
+	meta := container.ContainerMeta{
+		Labels: map[string]string{
+			container.NomadAllocID:       "5f2a0c1e-1b2c-3d4e-5f60-718293a4b5c6",
+			container.NomadJobName:       "shop",
+			container.NomadTaskGroupName: "cart",
+			container.NomadNamespace:     "prod",
+		},
+	}
+	if svc := driver.GetService(&meta, ""); svc.Name != "shop.cart" || svc.Domain != "prod" {
+		t.Errorf("Unexpected service: %+v", svc)
+	}
+
+	// only alloc_id label, names from the task environment
+	meta = container.ContainerMeta{
+		Labels: map[string]string{container.NomadAllocID: "5f2a0c1e-1b2c-3d4e-5f60-718293a4b5c6"},
+		Envs: []string{
+			"PATH=/usr/bin", "NOMAD_JOB_NAME=redis", "NOMAD_GROUP_NAME=redis",
+			"NOMAD_NAMESPACE=default",
+		},
+	}
+	if svc := driver.GetService(&meta, ""); svc.Name != "redis" || svc.Domain != "default" {
+		t.Errorf("Unexpected service: %+v", svc)
+	}
+}
@@ -95,6 +95,9 @@ func GetDriver(platform, flavor, network string, ver1, ver2 string,
 	case share.PlatformAmazonECS:
 		driver := &ecs{noop: noop{platform: platform, flavor: flavor, network: network}}
 		return driver
+	case share.PlatformNomad:
+		driver := &nomad{noop: noop{platform: platform, flavor: flavor, network: network}}
+		return driver
 	case share.PlatformDocker:
 		driver := &docker{
 			noop:      noop{platform: platform, flavor: flavor, network: network},
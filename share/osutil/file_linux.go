@@ -101,6 +101,21 @@ func GetFileHash(filepath string) ([fileHashKeep]byte, error) {
 	}
 }
 
+// hash the whole file content, it is used as the integrity baseline
+func GetFileContentHash(filepath string) (string, error) {
+	f, err := os.Open(filepath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
 func IsPackageLib(path string) bool {
 	return packageFiles.Contains(path)
 }
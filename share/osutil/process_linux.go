@@ -8,6 +8,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
@@ -472,6 +473,111 @@ func CheckProcessOpenDevTun(pid int) (bool, string) {
 	return false, ""
 }
 
+// Get the link targets of the file descriptors opened by the process from /proc/<pid>/fd, the key is the fd number.
+// The targets of sockets, pipes and anonymous inodes are not paths, like "socket:[1234]" or "anon_inode:bpf-prog"
+func GetProcessFds(pid int) map[string]string {
+	fdDir := global.SYS.ContainerProcFilePath(pid, "/fd")
+
+	d, err := os.Open(fdDir)
+	if err != nil {
+		return nil
+	}
+	defer d.Close()
+
+	names, err := d.Readdirnames(-1)
+	if err != nil {
+		return nil
+	}
+
+	fds := make(map[string]string, len(names))
+	for _, name := range names {
+		if fl, err := os.Readlink(fdDir + "/" + name); err == nil {
+			fds[name] = fl
+		}
+	}
+	return fds
+}
+
+// Get the open flags of a file descriptor from /proc/<pid>/fdinfo/<fd>
+func GetProcessFdFlags(pid int, fd string) (int, bool) {
+	dat, err := ioutil.ReadFile(global.SYS.ContainerProcFilePath(pid, "/fdinfo/"+fd))
+	if err != nil {
+		return 0, false
+	}
+
+	for _, line := range strings.Split(string(dat), "\n") {
+		if strings.HasPrefix(line, "flags:") {
+			if flags, err := strconv.ParseInt(strings.TrimSpace(line[6:]), 8, 32); err == nil {
+				return int(flags), true
+			}
+			break
+		}
+	}
+	return 0, false
+}
+
+// Get the file paths opened by the process and their open flags
+func GetProcessOpenFiles(pid int) map[string]int {
+	fds := GetProcessFds(pid)
+	if fds == nil {
+		return nil
+	}
+
+	opens := make(map[string]int)
+	for fd, fl := range fds {
+		if !filepath.IsAbs(fl) {
+			continue // sockets, pipes and anonymous inodes
+		}
+		if flags, ok := GetProcessFdFlags(pid, fd); ok {
+			opens[fl] |= flags
+		}
+	}
+	return opens
+}
+
+// Get the shared libraries and other files mapped into the process memory from /proc/<pid>/maps
+func GetProcessMappedFiles(pid int) []string {
+	f, err := os.Open(global.SYS.ContainerProcFilePath(pid, "/maps"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	files := utils.NewSet()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// address perms offset dev inode pathname
+		tokens := strings.Fields(scanner.Text())
+		if len(tokens) < 6 || !filepath.IsAbs(tokens[5]) {
+			continue // anonymous mappings, [heap], [stack], [vdso]
+		}
+		files.Add(tokens[5])
+	}
+
+	list := make([]string, 0, files.Cardinality())
+	for file := range files.Iter() {
+		list = append(list, file.(string))
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Get the environment variables of the process from /proc/<pid>/environ
+func GetProcessEnviron(pid int) ([]string, error) {
+	dat, err := ioutil.ReadFile(global.SYS.ContainerProcFilePath(pid, "/environ"))
+	if err != nil {
+		return nil, err
+	}
+
+	envs := make([]string, 0)
+	for _, env := range strings.Split(string(dat), "\x00") {
+		if env != "" {
+			envs = append(envs, env)
+		}
+	}
+	return envs, nil
+}
+
 func getSocketInode(name string) (uint32, error) {
 	a := strings.Index(name, "[")
 	b := strings.LastIndex(name, "]")
@@ -540,6 +646,55 @@ func GetProcessName(pid int) string {
 	return name
 }
 
+// Sample the system call which the process is currently blocked in from /proc/<pid>/syscall
+// It is a point-in-time sample, and system call names are only resolved on x86_64 hosts.
+func GetProcessSyscall(pid int) (string, bool) {
+	dat, err := ioutil.ReadFile(global.SYS.ContainerProcFilePath(pid, "/syscall"))
+	if err != nil {
+		return "", false
+	}
+	return parseProcessSyscall(dat)
+}
+
+// "running" or a negative number means the process is not inside a system call
+func parseProcessSyscall(dat []byte) (string, bool) {
+	fields := strings.Fields(string(dat))
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	nr, err := strconv.Atoi(fields[0])
+	if err != nil || nr < 0 {
+		return "", false
+	}
+
+	name := syscallName(nr)
+	return name, name != ""
+}
+
+// The systemd unit of a host process is the last ".service" or ".scope" element of its cgroup path
+// in /proc/<pid>/cgroup, like "0::/system.slice/sshd.service" or "1:name=systemd:/system.slice/cron.service"
+func GetProcessSystemdUnit(pid int) string {
+	dat, err := ioutil.ReadFile(global.SYS.ContainerProcFilePath(pid, "/cgroup"))
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(dat), "\n") {
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 || (fields[1] != "" && fields[1] != "name=systemd") {
+			continue
+		}
+		tokens := strings.Split(fields[2], "/")
+		for i := len(tokens) - 1; i >= 0; i-- {
+			if strings.HasSuffix(tokens[i], ".service") || strings.HasSuffix(tokens[i], ".scope") {
+				return tokens[i]
+			}
+		}
+	}
+	return ""
+}
+
 func GetSessionId(pid int) int {
 	sid, err := unix.Getsid(pid)
 	if err != nil {
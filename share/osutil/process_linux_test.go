@@ -0,0 +1,34 @@
+package osutil
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestParseProcessSyscall(t *testing.T) {
+	if runtime.GOARCH != "amd64" {
+		t.Skip("system call names are only resolved on x86_64")
+	}
+
+	tests := []struct {
+		dat  string
+		name string
+		ok   bool
+	}{
+		{"0 0x3 0x7ffd 0x2000 0x0 0x0 0x0 0x7ffd 0x7f12\n", "read", true},
+		{"232 0x4 0x7ffd 0x80 0xffffffff 0x0 0x0 0x7ffd 0x7f12\n", "epoll_wait", true},
+		{"288 0x3 0x0 0x0 0x80800 0x0 0x0 0x7ffd 0x7f12\n", "accept4", true},
+		{"435 0x7ffd 0x58 0x0 0x0 0x0 0x0 0x7ffd 0x7f12\n", "clone3", true},
+		{"running\n", "", false},
+		{"-1 0x7ffd 0x7f12\n", "", false},
+		{"400 0x0 0x0 0x0 0x0 0x0 0x0 0x7ffd 0x7f12\n", "", false}, // unassigned number
+		{"", "", false},
+	}
+
+	for _, test := range tests {
+		name, ok := parseProcessSyscall([]byte(test.dat))
+		if name != test.name || ok != test.ok {
+			t.Errorf("Unexpected system call: data=%q, name=%v, ok=%v, expect=%v/%v\n", test.dat, name, ok, test.name, test.ok)
+		}
+	}
+}
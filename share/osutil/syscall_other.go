@@ -0,0 +1,9 @@
+//go:build !amd64
+// +build !amd64
+
+package osutil
+
+// system call numbers differ by architecture, only x86_64 names are resolved
+func syscallName(nr int) string {
+	return ""
+}
@@ -0,0 +1,117 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// for streaming responses
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+type httpHandler struct {
+	handler http.Handler
+	route   func(r *http.Request) string
+}
+
+// HTTPHandler traces the requests and records their durations. route returns the matched route of the request,
+// such as /v1/workload/:id, it's used as the span name, so the URL parameters should not be in it.
+func HTTPHandler(handler http.Handler, route func(r *http.Request) string) http.Handler {
+	return httpHandler{handler: handler, route: route}
+}
+
+func (h httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !Enabled() {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	route := h.route(r)
+	ctx := ContextWithTraceParent(r.Context(), r.Header.Get(TraceParentHeader))
+	ctx, span := StartSpan(ctx, r.Method+" "+route, SpanKindServer)
+	sw := &statusWriter{ResponseWriter: w}
+	h.handler.ServeHTTP(sw, r.WithContext(ctx))
+
+	if sw.status == 0 {
+		sw.status = http.StatusOK
+	}
+	span.SetAttribute("http.method", r.Method)
+	span.SetAttribute("http.route", route)
+	span.SetAttribute("http.status_code", sw.status)
+	if sw.status >= http.StatusInternalServerError {
+		span.SetError(fmt.Errorf("%d %s", sw.status, http.StatusText(sw.status)))
+	}
+	span.End()
+
+	RecordDuration("http.server.duration", span.Duration(),
+		"http.method", r.Method, "http.route", route, "http.status_code", strconv.Itoa(sw.status))
+}
+
+// UnaryServerInterceptor traces the gRPC requests, joining the trace of the caller if it's propagated
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !Enabled() {
+		return handler(ctx, req)
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if tp := md.Get(TraceParentHeader); len(tp) > 0 {
+			ctx = ContextWithTraceParent(ctx, tp[0])
+		}
+	}
+	ctx, span := StartSpan(ctx, info.FullMethod, SpanKindServer)
+	resp, err := handler(ctx, req)
+	span.SetAttribute("rpc.system", "grpc")
+	span.SetAttribute("rpc.method", info.FullMethod)
+	span.SetError(err)
+	span.End()
+
+	RecordDuration("rpc.server.duration", span.Duration(),
+		"rpc.method", info.FullMethod, "rpc.grpc.status_code", strconv.Itoa(int(status.Code(err))))
+	return resp, err
+}
+
+// UnaryClientInterceptor propagates the trace to the server. The call is traced only if it's a part of a trace,
+// so the periodic calls don't start their own traces.
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if TraceParent(ctx) == "" {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	ctx, span := StartSpan(ctx, method, SpanKindClient)
+	ctx = metadata.AppendToOutgoingContext(ctx, TraceParentHeader, TraceParent(ctx))
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	span.SetAttribute("rpc.system", "grpc")
+	span.SetAttribute("rpc.method", method)
+	span.SetError(err)
+	span.End()
+	return err
+}
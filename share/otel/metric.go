@@ -0,0 +1,138 @@
+package otel
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bucket bounds of the duration histograms in seconds
+var durationBounds = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type histogramPoint struct {
+	attrs  []attribute
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+type counterPoint struct {
+	attrs []attribute
+	value int64
+}
+
+// metric is either a histogram or a counter. Points are keyed by the attributes.
+type metric struct {
+	name       string
+	unit       string
+	histograms map[string]*histogramPoint
+	counters   map[string]*counterPoint
+}
+
+var metricMutex sync.Mutex
+var metricMap map[string]*metric = make(map[string]*metric)
+
+// Attributes are given as key and value pairs
+func metricAttrs(kv []string) (string, []attribute) {
+	attrs := make([]attribute, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		attrs = append(attrs, attribute{key: kv[i], value: kv[i+1]})
+	}
+	return strings.Join(kv, "\x00"), attrs
+}
+
+func getMetric(name, unit string) *metric {
+	m, ok := metricMap[name]
+	if !ok {
+		m = &metric{name: name, unit: unit}
+		metricMap[name] = m
+	}
+	return m
+}
+
+// RecordDuration adds the duration in seconds to the histogram. The attributes are given as key and value pairs,
+// they should have low cardinality.
+func RecordDuration(name string, d time.Duration, kv ...string) {
+	if !Enabled() {
+		return
+	}
+
+	key, attrs := metricAttrs(kv)
+	v := d.Seconds()
+
+	metricMutex.Lock()
+	defer metricMutex.Unlock()
+
+	m := getMetric(name, "s")
+	if m.histograms == nil {
+		m.histograms = make(map[string]*histogramPoint)
+	}
+	p, ok := m.histograms[key]
+	if !ok {
+		p = &histogramPoint{attrs: attrs, counts: make([]uint64, len(durationBounds)+1)}
+		m.histograms[key] = p
+	}
+	p.counts[sort.SearchFloat64s(durationBounds, v)]++
+	p.count++
+	p.sum += v
+}
+
+// AddCount adds to the monotonic counter. The attributes are given as key and value pairs.
+func AddCount(name string, n int64, kv ...string) {
+	if !Enabled() {
+		return
+	}
+
+	key, attrs := metricAttrs(kv)
+
+	metricMutex.Lock()
+	defer metricMutex.Unlock()
+
+	m := getMetric(name, "1")
+	if m.counters == nil {
+		m.counters = make(map[string]*counterPoint)
+	}
+	p, ok := m.counters[key]
+	if !ok {
+		p = &counterPoint{attrs: attrs}
+		m.counters[key] = p
+	}
+	p.value += n
+}
+
+// Metrics are cumulative since the start
+func collectMetrics(start, now time.Time) []*otlpMetric {
+	metricMutex.Lock()
+	defer metricMutex.Unlock()
+
+	startNano, nowNano := otlpTime(start), otlpTime(now)
+	metrics := make([]*otlpMetric, 0, len(metricMap))
+	for _, m := range metricMap {
+		om := &otlpMetric{Name: m.name, Unit: m.unit}
+		if m.histograms != nil {
+			om.Histogram = &otlpHistogram{AggregationTemporality: otlpTemporalityCumulative}
+			for _, p := range m.histograms {
+				counts := make([]otlpUint64, len(p.counts))
+				for i, c := range p.counts {
+					counts[i] = otlpUint64(c)
+				}
+				om.Histogram.DataPoints = append(om.Histogram.DataPoints, &otlpHistogramPoint{
+					Attributes: otlpAttributes(p.attrs), StartTimeUnixNano: startNano, TimeUnixNano: nowNano,
+					Count: otlpUint64(p.count), Sum: p.sum, BucketCounts: counts, ExplicitBounds: durationBounds,
+				})
+			}
+		} else {
+			om.Sum = &otlpSum{AggregationTemporality: otlpTemporalityCumulative, IsMonotonic: true}
+			for _, p := range m.counters {
+				om.Sum.DataPoints = append(om.Sum.DataPoints, &otlpNumberPoint{
+					Attributes: otlpAttributes(p.attrs), StartTimeUnixNano: startNano, TimeUnixNano: nowNano,
+					AsInt: otlpInt64(p.value),
+				})
+			}
+		}
+		metrics = append(metrics, om)
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+	return metrics
+}
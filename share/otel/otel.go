@@ -0,0 +1,230 @@
+// Package otel produces OpenTelemetry traces and metrics, and exports them to a collector with OTLP/HTTP in
+// JSON encoding. Only the parts used by the controller and the enforcer are implemented.
+package otel
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const EnvEndpoint = "OTEL_EXPORTER_OTLP_ENDPOINT"
+const EnvHeaders = "OTEL_EXPORTER_OTLP_HEADERS"
+
+const spanQueueSize = 4096
+const spanBatchMax = 512
+const spanFlushPeriod = time.Second * 5
+const metricPeriod = time.Second * 30
+const exportTimeout = time.Second * 10
+const scopeName = "github.com/neuvector/neuvector"
+
+type Config struct {
+	Endpoint    string            // base URL of the OTLP/HTTP receiver, such as http://otel-collector:4318
+	Headers     map[string]string // such as the authorization header of the collector
+	ServiceName string
+	InstanceID  string
+	HostName    string
+	SampleRatio float64 // ratio of the traces to sample, 0 or 1 to sample all
+	SkipVerify  bool
+}
+
+type exporter struct {
+	cfg       Config
+	client    *http.Client
+	resource  *otlpResource
+	startTime time.Time
+	spanQueue chan *Span
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	dropped   uint64
+}
+
+var theExporter atomic.Value // *exporter
+
+func getExporter() *exporter {
+	if e, ok := theExporter.Load().(*exporter); ok {
+		return e
+	}
+	return nil
+}
+
+func Enabled() bool {
+	return getExporter() != nil
+}
+
+// Parse the headers in the format of OTEL_EXPORTER_OTLP_HEADERS, such as "api-key=abc,tenant=t1"
+func ParseHeaders(s string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, kv := range strings.Split(s, ",") {
+		if kv = strings.TrimSpace(kv); kv == "" {
+			continue
+		}
+		i := strings.Index(kv, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("Invalid header %s", kv)
+		}
+		k, _ := url.QueryUnescape(strings.TrimSpace(kv[:i]))
+		v, _ := url.QueryUnescape(strings.TrimSpace(kv[i+1:]))
+		headers[k] = v
+	}
+	return headers, nil
+}
+
+// Start to export traces and metrics. If the endpoint is empty, it's taken from OTEL_EXPORTER_OTLP_ENDPOINT;
+// nothing is recorded if neither is set.
+func Init(cfg Config) error {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = os.Getenv(EnvEndpoint)
+	}
+	if cfg.Endpoint == "" {
+		return nil
+	}
+	if u, err := url.Parse(cfg.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("Invalid OTLP endpoint: %s", cfg.Endpoint)
+	}
+	if cfg.Headers == nil {
+		if s := os.Getenv(EnvHeaders); s != "" {
+			headers, err := ParseHeaders(s)
+			if err != nil {
+				return err
+			}
+			cfg.Headers = headers
+		}
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return fmt.Errorf("Invalid sample ratio: %v", cfg.SampleRatio)
+	}
+
+	e := &exporter{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: exportTimeout,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.SkipVerify},
+			},
+		},
+		resource: &otlpResource{Attributes: otlpAttributes([]attribute{
+			{key: "service.name", value: cfg.ServiceName},
+			{key: "service.instance.id", value: cfg.InstanceID},
+			{key: "host.name", value: cfg.HostName},
+		})},
+		startTime: time.Now(),
+		spanQueue: make(chan *Span, spanQueueSize),
+		stopCh:    make(chan struct{}),
+	}
+	e.wg.Add(1)
+	go e.run()
+	theExporter.Store(e)
+
+	log.WithFields(log.Fields{"endpoint": cfg.Endpoint, "service": cfg.ServiceName}).Info("OpenTelemetry export enabled")
+	return nil
+}
+
+// Stop the export, the pending spans and the metrics are sent before it returns
+func Close() {
+	if e := getExporter(); e != nil {
+		theExporter.Store((*exporter)(nil))
+		close(e.stopCh)
+		e.wg.Wait()
+	}
+}
+
+func (e *exporter) enqueue(s *Span) {
+	select {
+	case e.spanQueue <- s:
+	default:
+		if dropped := atomic.AddUint64(&e.dropped, 1); dropped%1000 == 1 {
+			log.WithFields(log.Fields{"dropped": dropped}).Error("Span queue is full")
+		}
+	}
+}
+
+func (e *exporter) run() {
+	defer e.wg.Done()
+
+	spanTicker := time.NewTicker(spanFlushPeriod)
+	defer spanTicker.Stop()
+	metricTicker := time.NewTicker(metricPeriod)
+	defer metricTicker.Stop()
+
+	batch := make([]*Span, 0, spanBatchMax)
+	for {
+		select {
+		case s := <-e.spanQueue:
+			if batch = append(batch, s); len(batch) >= spanBatchMax {
+				e.exportSpans(batch)
+				batch = make([]*Span, 0, spanBatchMax)
+			}
+		case <-spanTicker.C:
+			if len(batch) > 0 {
+				e.exportSpans(batch)
+				batch = make([]*Span, 0, spanBatchMax)
+			}
+		case <-metricTicker.C:
+			e.exportMetrics()
+		case <-e.stopCh:
+			for len(e.spanQueue) > 0 {
+				batch = append(batch, <-e.spanQueue)
+			}
+			if len(batch) > 0 {
+				e.exportSpans(batch)
+			}
+			e.exportMetrics()
+			return
+		}
+	}
+}
+
+func (e *exporter) post(path string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", strings.TrimSuffix(e.cfg.Endpoint, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("Status %d: %s", resp.StatusCode, string(data))
+	}
+	return nil
+}
+
+func (e *exporter) exportSpans(spans []*Span) {
+	if err := e.post("/v1/traces", spans2OTLP(e.resource, spans)); err != nil {
+		log.WithFields(log.Fields{"spans": len(spans), "error": err}).Error("Failed to export spans")
+	}
+}
+
+func (e *exporter) exportMetrics() {
+	metrics := collectMetrics(e.startTime, time.Now())
+	if len(metrics) == 0 {
+		return
+	}
+	if err := e.post("/v1/metrics", metrics2OTLP(e.resource, metrics)); err != nil {
+		log.WithFields(log.Fields{"metrics": len(metrics), "error": err}).Error("Failed to export metrics")
+	}
+}
@@ -0,0 +1,162 @@
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func resetExporter() {
+	Close()
+	metricMap = make(map[string]*metric)
+}
+
+func TestTraceParent(t *testing.T) {
+	tp := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := ContextWithTraceParent(context.Background(), tp)
+	if TraceParent(ctx) != tp {
+		t.Errorf("Unexpected traceparent: %s", TraceParent(ctx))
+	}
+
+	for _, v := range []string{
+		"", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473x-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		if TraceParent(ContextWithTraceParent(context.Background(), v)) != "" {
+			t.Errorf("Invalid traceparent should be ignored: %s", v)
+		}
+	}
+
+	// nothing is recorded if the export is not enabled
+	if _, span := StartSpan(ctx, "test", SpanKindInternal); span != nil {
+		t.Errorf("Span should be nil when the export is disabled")
+	}
+}
+
+func TestSample(t *testing.T) {
+	e := &exporter{cfg: Config{SampleRatio: 0.5}}
+	low := [16]byte{8: 0x10}
+	high := [16]byte{8: 0xf0}
+	if !e.sample(low) || e.sample(high) {
+		t.Errorf("Unexpected sampling")
+	}
+}
+
+func TestExport(t *testing.T) {
+	var mutex sync.Mutex
+	var traces otlpTracesResult
+	var metrics map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mutex.Lock()
+		defer mutex.Unlock()
+		if r.Header.Get("X-Tenant") != "t1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/traces":
+			json.Unmarshal(body, &traces)
+		case "/v1/metrics":
+			json.Unmarshal(body, &metrics)
+		}
+	}))
+	defer server.Close()
+
+	if err := Init(Config{Endpoint: server.URL, Headers: map[string]string{"X-Tenant": "t1"}, ServiceName: "test"}); err != nil {
+		t.Fatalf("Failed to init: %v", err)
+	}
+	defer resetExporter()
+
+	ctx := ContextWithTraceParent(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, parent := StartSpan(ctx, "parent", SpanKindServer)
+	_, child := StartSpan(ctx, "child", SpanKindInternal)
+	child.SetAttribute("rules", 3)
+	child.SetError(errors.New("failed"))
+	child.End()
+	parent.End()
+	parent.End()
+	RecordDuration("test.duration", time.Millisecond*20, "k", "v")
+	RecordDuration("test.duration", time.Second*60, "k", "v")
+	Close()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(traces.ResourceSpans) != 1 || len(traces.ResourceSpans[0].ScopeSpans[0].Spans) != 2 {
+		t.Fatalf("Unexpected traces: %+v", traces)
+	}
+	spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	c, p := spans[0], spans[1]
+	if c.Name != "child" || p.Name != "parent" || c.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || c.TraceID != p.TraceID {
+		t.Errorf("Unexpected spans: %+v", spans)
+	}
+	if c.ParentSpanID != p.SpanID || p.ParentSpanID != "00f067aa0ba902b7" || c.Status.Code != otlpStatusError {
+		t.Errorf("Unexpected span relation: %+v", spans)
+	}
+	if len(c.Attributes) != 1 || c.Attributes[0].Value.IntValue != "3" {
+		t.Errorf("Unexpected attributes: %+v", c.Attributes)
+	}
+
+	data, _ := json.Marshal(metrics)
+	var m otlpMetricsResult
+	json.Unmarshal(data, &m)
+	if len(m.ResourceMetrics) != 1 || len(m.ResourceMetrics[0].ScopeMetrics[0].Metrics) != 1 {
+		t.Fatalf("Unexpected metrics: %s", string(data))
+	}
+	h := m.ResourceMetrics[0].ScopeMetrics[0].Metrics[0].Histogram
+	if len(h.DataPoints) != 1 || h.DataPoints[0].Count != "2" {
+		t.Fatalf("Unexpected histogram: %s", string(data))
+	}
+	counts := h.DataPoints[0].BucketCounts
+	if len(counts) != len(durationBounds)+1 || counts[2] != "1" || counts[len(counts)-1] != "1" {
+		t.Errorf("Unexpected bucket counts: %v", counts)
+	}
+}
+
+// -- decoded OTLP
+
+type otlpTracesResult struct {
+	ResourceSpans []struct {
+		ScopeSpans []struct {
+			Spans []struct {
+				TraceID      string `json:"traceId"`
+				SpanID       string `json:"spanId"`
+				ParentSpanID string `json:"parentSpanId"`
+				Name         string `json:"name"`
+				Attributes   []struct {
+					Key   string `json:"key"`
+					Value struct {
+						IntValue string `json:"intValue"`
+					} `json:"value"`
+				} `json:"attributes"`
+				Status struct {
+					Code int `json:"code"`
+				} `json:"status"`
+			} `json:"spans"`
+		} `json:"scopeSpans"`
+	} `json:"resourceSpans"`
+}
+
+type otlpMetricsResult struct {
+	ResourceMetrics []struct {
+		ScopeMetrics []struct {
+			Metrics []struct {
+				Name      string `json:"name"`
+				Histogram struct {
+					DataPoints []struct {
+						Count        string   `json:"count"`
+						BucketCounts []string `json:"bucketCounts"`
+					} `json:"dataPoints"`
+				} `json:"histogram"`
+			} `json:"metrics"`
+		} `json:"scopeMetrics"`
+	} `json:"resourceMetrics"`
+}
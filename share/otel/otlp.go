@@ -0,0 +1,196 @@
+package otel
+
+// OTLP JSON encoding. 64-bit integers are encoded as strings and ids as hex strings, as the protobuf JSON
+// mapping of OTLP specifies.
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const otlpTemporalityCumulative = 2
+const otlpStatusError = 2
+
+type otlpUint64 uint64
+
+func (v otlpUint64) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + strconv.FormatUint(uint64(v), 10) + `"`), nil
+}
+
+type otlpInt64 int64
+
+func (v otlpInt64) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + strconv.FormatInt(int64(v), 10) + `"`), nil
+}
+
+func otlpTime(t time.Time) otlpUint64 {
+	return otlpUint64(t.UnixNano())
+}
+
+type otlpAnyValue struct {
+	StringValue *string    `json:"stringValue,omitempty"`
+	BoolValue   *bool      `json:"boolValue,omitempty"`
+	IntValue    *otlpInt64 `json:"intValue,omitempty"`
+	DoubleValue *float64   `json:"doubleValue,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+func otlpAttributes(attrs []attribute) []otlpKeyValue {
+	kvs := make([]otlpKeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		kv := otlpKeyValue{Key: a.key}
+		switch v := a.value.(type) {
+		case string:
+			kv.Value.StringValue = &v
+		case bool:
+			kv.Value.BoolValue = &v
+		case int:
+			i := otlpInt64(v)
+			kv.Value.IntValue = &i
+		case int32:
+			i := otlpInt64(v)
+			kv.Value.IntValue = &i
+		case int64:
+			i := otlpInt64(v)
+			kv.Value.IntValue = &i
+		case uint32:
+			i := otlpInt64(v)
+			kv.Value.IntValue = &i
+		case float64:
+			kv.Value.DoubleValue = &v
+		default:
+			s := fmt.Sprintf("%v", v)
+			kv.Value.StringValue = &s
+		}
+		kvs = append(kvs, kv)
+	}
+	return kvs
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+// -- traces
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano otlpUint64     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   otlpUint64     `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope   `json:"scope"`
+	Spans []*otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   *otlpResource     `json:"resource"`
+	ScopeSpans []*otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTraces struct {
+	ResourceSpans []*otlpResourceSpans `json:"resourceSpans"`
+}
+
+func spans2OTLP(resource *otlpResource, spans []*Span) *otlpTraces {
+	ss := &otlpScopeSpans{Scope: otlpScope{Name: scopeName}, Spans: make([]*otlpSpan, len(spans))}
+	for i, s := range spans {
+		s.mutex.Lock()
+		o := &otlpSpan{
+			TraceID:           hex.EncodeToString(s.traceID[:]),
+			SpanID:            hex.EncodeToString(s.spanID[:]),
+			Name:              s.name,
+			Kind:              s.kind,
+			StartTimeUnixNano: otlpTime(s.start),
+			EndTimeUnixNano:   otlpTime(s.end),
+			Attributes:        otlpAttributes(s.attrs),
+		}
+		if s.parentID != [8]byte{} {
+			o.ParentSpanID = hex.EncodeToString(s.parentID[:])
+		}
+		if s.isError {
+			o.Status = otlpStatus{Code: otlpStatusError, Message: s.message}
+		}
+		s.mutex.Unlock()
+		ss.Spans[i] = o
+	}
+	return &otlpTraces{ResourceSpans: []*otlpResourceSpans{{Resource: resource, ScopeSpans: []*otlpScopeSpans{ss}}}}
+}
+
+// -- metrics
+
+type otlpHistogramPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano otlpUint64     `json:"startTimeUnixNano"`
+	TimeUnixNano      otlpUint64     `json:"timeUnixNano"`
+	Count             otlpUint64     `json:"count"`
+	Sum               float64        `json:"sum"`
+	BucketCounts      []otlpUint64   `json:"bucketCounts"`
+	ExplicitBounds    []float64      `json:"explicitBounds"`
+}
+
+type otlpHistogram struct {
+	DataPoints             []*otlpHistogramPoint `json:"dataPoints"`
+	AggregationTemporality int                   `json:"aggregationTemporality"`
+}
+
+type otlpNumberPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano otlpUint64     `json:"startTimeUnixNano"`
+	TimeUnixNano      otlpUint64     `json:"timeUnixNano"`
+	AsInt             otlpInt64      `json:"asInt"`
+}
+
+type otlpSum struct {
+	DataPoints             []*otlpNumberPoint `json:"dataPoints"`
+	AggregationTemporality int                `json:"aggregationTemporality"`
+	IsMonotonic            bool               `json:"isMonotonic"`
+}
+
+type otlpMetric struct {
+	Name      string         `json:"name"`
+	Unit      string         `json:"unit,omitempty"`
+	Histogram *otlpHistogram `json:"histogram,omitempty"`
+	Sum       *otlpSum       `json:"sum,omitempty"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope     `json:"scope"`
+	Metrics []*otlpMetric `json:"metrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     *otlpResource       `json:"resource"`
+	ScopeMetrics []*otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpMetrics struct {
+	ResourceMetrics []*otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+func metrics2OTLP(resource *otlpResource, metrics []*otlpMetric) *otlpMetrics {
+	sm := &otlpScopeMetrics{Scope: otlpScope{Name: scopeName}, Metrics: metrics}
+	return &otlpMetrics{ResourceMetrics: []*otlpResourceMetrics{{Resource: resource, ScopeMetrics: []*otlpScopeMetrics{sm}}}}
+}
@@ -0,0 +1,165 @@
+package otel
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	SpanKindInternal = 1
+	SpanKindServer   = 2
+	SpanKindClient   = 3
+)
+
+// W3C trace context header
+const TraceParentHeader = "traceparent"
+
+type attribute struct {
+	key   string
+	value interface{}
+}
+
+type spanContext struct {
+	traceID [16]byte
+	spanID  [8]byte
+	sampled bool
+}
+
+type spanContextKey struct{}
+
+// Span is a timed operation of a trace. A nil span is valid and does nothing, so callers don't need to check
+// whether the export is enabled.
+type Span struct {
+	spanContext
+	parentID [8]byte
+	name     string
+	kind     int
+	start    time.Time
+	end      time.Time
+	mutex    sync.Mutex
+	attrs    []attribute
+	isError  bool
+	message  string
+}
+
+func newID(b []byte) {
+	rand.Read(b)
+}
+
+// Start a span, it's the child of the span in the context if there is one. The returned context has the new span.
+func StartSpan(ctx context.Context, name string, kind int) (context.Context, *Span) {
+	e := getExporter()
+	if e == nil {
+		return ctx, nil
+	}
+
+	s := &Span{name: name, kind: kind, start: time.Now()}
+	if parent, ok := ctx.Value(spanContextKey{}).(spanContext); ok {
+		s.traceID = parent.traceID
+		s.parentID = parent.spanID
+		s.sampled = parent.sampled
+	} else {
+		newID(s.traceID[:])
+		s.sampled = e.sample(s.traceID)
+	}
+	newID(s.spanID[:])
+	return context.WithValue(ctx, spanContextKey{}, s.spanContext), s
+}
+
+// The trace is sampled if the lower 8 bytes of the trace id is less than the ratio, same as TraceIdRatioBased
+func (e *exporter) sample(traceID [16]byte) bool {
+	if e.cfg.SampleRatio == 0 || e.cfg.SampleRatio >= 1 {
+		return true
+	}
+	bound := uint64(e.cfg.SampleRatio * (1 << 63))
+	return binary.BigEndian.Uint64(traceID[8:])>>1 < bound
+}
+
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	s.attrs = append(s.attrs, attribute{key: key, value: value})
+	s.mutex.Unlock()
+}
+
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mutex.Lock()
+	s.isError = true
+	s.message = err.Error()
+	s.mutex.Unlock()
+}
+
+// End the span and queue it to export if the trace is sampled
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	ended := !s.end.IsZero()
+	if !ended {
+		s.end = time.Now()
+	}
+	s.mutex.Unlock()
+
+	if !ended && s.sampled {
+		if e := getExporter(); e != nil {
+			e.enqueue(s)
+		}
+	}
+}
+
+func (s *Span) Duration() time.Duration {
+	if s == nil || s.end.IsZero() {
+		return 0
+	}
+	return s.end.Sub(s.start)
+}
+
+// TraceParent returns the W3C traceparent of the span in the context to propagate the trace, or an empty string
+func TraceParent(ctx context.Context) string {
+	sc, ok := ctx.Value(spanContextKey{}).(spanContext)
+	if !ok {
+		return ""
+	}
+	var flags byte
+	if sc.sampled {
+		flags = 1
+	}
+	return fmt.Sprintf("00-%s-%s-%02x", hex.EncodeToString(sc.traceID[:]), hex.EncodeToString(sc.spanID[:]), flags)
+}
+
+// ContextWithTraceParent returns a context with the remote parent span, so the spans started with it join the
+// trace. The context is returned as is if the traceparent is invalid.
+func ContextWithTraceParent(ctx context.Context, tp string) context.Context {
+	// version-traceid-spanid-flags
+	if len(tp) < 55 || tp[2] != '-' || tp[35] != '-' || tp[52] != '-' || tp[:2] == "ff" {
+		return ctx
+	}
+
+	var sc spanContext
+	var flags [1]byte
+	if _, err := hex.Decode(sc.traceID[:], []byte(tp[3:35])); err != nil {
+		return ctx
+	}
+	if _, err := hex.Decode(sc.spanID[:], []byte(tp[36:52])); err != nil {
+		return ctx
+	}
+	if _, err := hex.Decode(flags[:], []byte(tp[53:55])); err != nil {
+		return ctx
+	}
+	if sc.traceID == [16]byte{} || sc.spanID == [8]byte{} {
+		return ctx
+	}
+	sc.sampled = flags[0]&1 == 1
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
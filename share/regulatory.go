@@ -0,0 +1,211 @@
+package share
+
+import (
+	"sort"
+)
+
+// The regulatory frameworks, and the controls that the compliance checks, the admission control rules and the runtime
+// detections help to satisfy. The findings are mapped to the security areas, then to the controls of the areas.
+
+const (
+	RegulatoryPCIDSS    = "PCI-DSS"     // PCI DSS v4.0
+	RegulatoryNIST80053 = "NIST-800-53" // NIST SP 800-53 rev. 5
+	RegulatoryHIPAA     = "HIPAA"       // HIPAA Security Rule, 45 CFR Part 164
+)
+
+type CLUSRegulatoryFramework struct {
+	ID   string
+	Name string
+}
+
+var CLUSRegulatoryFrameworks = []CLUSRegulatoryFramework{
+	{RegulatoryPCIDSS, "Payment Card Industry Data Security Standard v4.0"},
+	{RegulatoryNIST80053, "NIST SP 800-53 Security and Privacy Controls rev. 5"},
+	{RegulatoryHIPAA, "HIPAA Security Rule"},
+}
+
+const (
+	RegulatoryAreaAudit         = "audit"
+	RegulatoryAreaAccess        = "access"
+	RegulatoryAreaAuth          = "authentication"
+	RegulatoryAreaEncryption    = "encryption"
+	RegulatoryAreaHardening     = "hardening"
+	RegulatoryAreaChange        = "change"
+	RegulatoryAreaVulnerability = "vulnerability"
+	RegulatoryAreaIntegrity     = "integrity"
+	RegulatoryAreaIntrusion     = "intrusion"
+	RegulatoryAreaNetwork       = "network"
+)
+
+type CLUSRegulatoryControl struct {
+	Framework string
+	ID        string
+	Name      string
+}
+
+// The reference of the control in the findings, such as "PCI-DSS 10.2"
+func (c *CLUSRegulatoryControl) Ref() string {
+	return c.Framework + " " + c.ID
+}
+
+var CLUSRegulatoryAreaControls = map[string][]CLUSRegulatoryControl{
+	RegulatoryAreaAudit: {
+		{RegulatoryPCIDSS, "10.2", "Audit logs are implemented to support the detection of anomalies and suspicious activity"},
+		{RegulatoryNIST80053, "AU-2", "Event Logging"},
+		{RegulatoryNIST80053, "AU-12", "Audit Record Generation"},
+		{RegulatoryHIPAA, "164.312(b)", "Audit controls"},
+	},
+	RegulatoryAreaAccess: {
+		{RegulatoryPCIDSS, "7.2", "Access to system components and data is appropriately defined and assigned"},
+		{RegulatoryNIST80053, "AC-3", "Access Enforcement"},
+		{RegulatoryNIST80053, "AC-6", "Least Privilege"},
+		{RegulatoryHIPAA, "164.312(a)(1)", "Access control"},
+	},
+	RegulatoryAreaAuth: {
+		{RegulatoryPCIDSS, "8.3", "Strong authentication for users and administrators is established and managed"},
+		{RegulatoryNIST80053, "IA-2", "Identification and Authentication (Organizational Users)"},
+		{RegulatoryNIST80053, "IA-5", "Authenticator Management"},
+		{RegulatoryHIPAA, "164.312(d)", "Person or entity authentication"},
+	},
+	RegulatoryAreaEncryption: {
+		{RegulatoryPCIDSS, "4.2", "Data is protected with strong cryptography during transmission"},
+		{RegulatoryNIST80053, "SC-8", "Transmission Confidentiality and Integrity"},
+		{RegulatoryNIST80053, "SC-12", "Cryptographic Key Establishment and Management"},
+		{RegulatoryHIPAA, "164.312(e)(1)", "Transmission security"},
+	},
+	RegulatoryAreaHardening: {
+		{RegulatoryPCIDSS, "2.2", "System components are configured and managed securely"},
+		{RegulatoryNIST80053, "CM-6", "Configuration Settings"},
+		{RegulatoryNIST80053, "CM-7", "Least Functionality"},
+		{RegulatoryHIPAA, "164.308(a)(1)(ii)(B)", "Risk management"},
+	},
+	RegulatoryAreaChange: {
+		{RegulatoryPCIDSS, "6.5", "Changes to all system components are managed securely"},
+		{RegulatoryNIST80053, "CM-3", "Configuration Change Control"},
+		{RegulatoryHIPAA, "164.308(a)(1)(ii)(D)", "Information system activity review"},
+	},
+	RegulatoryAreaVulnerability: {
+		{RegulatoryPCIDSS, "6.3", "Security vulnerabilities are identified and addressed"},
+		{RegulatoryPCIDSS, "11.3", "External and internal vulnerabilities are regularly identified, prioritized, and addressed"},
+		{RegulatoryNIST80053, "RA-5", "Vulnerability Monitoring and Scanning"},
+		{RegulatoryNIST80053, "SI-2", "Flaw Remediation"},
+		{RegulatoryHIPAA, "164.308(a)(1)(ii)(A)", "Risk analysis"},
+	},
+	RegulatoryAreaIntegrity: {
+		{RegulatoryPCIDSS, "11.5", "Network intrusions and unexpected file changes are detected and responded to"},
+		{RegulatoryNIST80053, "SI-7", "Software, Firmware, and Information Integrity"},
+		{RegulatoryHIPAA, "164.312(c)(1)", "Integrity"},
+	},
+	RegulatoryAreaIntrusion: {
+		{RegulatoryPCIDSS, "5.2", "Malicious software is prevented, or detected and addressed"},
+		{RegulatoryPCIDSS, "11.5", "Network intrusions and unexpected file changes are detected and responded to"},
+		{RegulatoryNIST80053, "SI-3", "Malicious Code Protection"},
+		{RegulatoryNIST80053, "SI-4", "System Monitoring"},
+		{RegulatoryHIPAA, "164.308(a)(5)(ii)(B)", "Protection from malicious software"},
+		{RegulatoryHIPAA, "164.308(a)(6)(ii)", "Response and reporting"},
+	},
+	RegulatoryAreaNetwork: {
+		{RegulatoryPCIDSS, "1.3", "Network access to and from the cardholder data environment is restricted"},
+		{RegulatoryNIST80053, "SC-7", "Boundary Protection"},
+		{RegulatoryNIST80053, "AC-4", "Information Flow Enforcement"},
+		{RegulatoryHIPAA, "164.312(a)(1)", "Access control"},
+	},
+}
+
+// The controls of the framework without duplicates, sorted by the control ID
+func RegulatoryFrameworkControls(framework string) []CLUSRegulatoryControl {
+	var controls []CLUSRegulatoryControl
+	refs := make(map[string]bool)
+	for _, list := range CLUSRegulatoryAreaControls {
+		for _, c := range list {
+			if c.Framework == framework && !refs[c.ID] {
+				refs[c.ID] = true
+				controls = append(controls, c)
+			}
+		}
+	}
+	sort.Slice(controls, func(i, j int) bool { return controls[i].ID < controls[j].ID })
+	return controls
+}
+
+// The references of the controls of the areas, without duplicates
+func RegulatoryControls(areas []string) []string {
+	var refs []string
+	for _, area := range areas {
+		for _, c := range CLUSRegulatoryAreaControls[area] {
+			refs = appendUniq(refs, c.Ref())
+		}
+	}
+	sort.Strings(refs)
+	return refs
+}
+
+// Every threat detection is an intrusion detection
+var CLUSThreatRegulatoryAreas = []string{RegulatoryAreaIntrusion}
+
+// Every network violation is reported by the segmentation of the network policy
+var CLUSViolationRegulatoryAreas = []string{RegulatoryAreaNetwork}
+
+var CLUSIncidentRegulatoryMap = map[TLogIncident][]string{
+	CLUSIncidHostPrivilEscalate:           {RegulatoryAreaIntrusion, RegulatoryAreaAccess},
+	CLUSIncidContainerPrivilEscalate:      {RegulatoryAreaIntrusion, RegulatoryAreaAccess},
+	CLUSIncidHostSuspiciousProcess:        {RegulatoryAreaIntrusion},
+	CLUSIncidContainerSuspiciousProcess:   {RegulatoryAreaIntrusion},
+	CLUSIncidHostFileAccessViolation:      {RegulatoryAreaIntegrity, RegulatoryAreaAccess},
+	CLUSIncidContainerFileAccessViolation: {RegulatoryAreaIntegrity, RegulatoryAreaAccess},
+	CLUSIncidHostPackageUpdated:           {RegulatoryAreaIntegrity, RegulatoryAreaChange},
+	CLUSIncidContainerPackageUpdated:      {RegulatoryAreaIntegrity, RegulatoryAreaChange},
+	CLUSIncidHostTunnel:                   {RegulatoryAreaIntrusion, RegulatoryAreaNetwork},
+	CLUSIncidContainerTunnel:              {RegulatoryAreaIntrusion, RegulatoryAreaNetwork},
+	CLUSIncidHostProcessViolation:         {RegulatoryAreaIntrusion, RegulatoryAreaHardening},
+	CLUSIncidContainerProcessViolation:    {RegulatoryAreaIntrusion, RegulatoryAreaHardening},
+	CLUSIncidHostFileContentDrift:         {RegulatoryAreaIntegrity},
+	CLUSIncidContainerFileContentDrift:    {RegulatoryAreaIntegrity},
+	CLUSIncidHostKernelLoad:               {RegulatoryAreaIntrusion, RegulatoryAreaIntegrity},
+	CLUSIncidContainerKernelLoad:          {RegulatoryAreaIntrusion, RegulatoryAreaIntegrity},
+	CLUSIncidContainerEscape:              {RegulatoryAreaIntrusion, RegulatoryAreaAccess},
+	CLUSIncidContainerCryptoMining:        {RegulatoryAreaIntrusion},
+}
+
+// The admission control deny rules prevent the deployments of the criteria. Every deny rule controls the changes
+// of the cluster resources.
+var CLUSAdmCriteriaRegulatoryMap = map[string][]string{
+	CriteriaKeyCVENames:            {RegulatoryAreaVulnerability},
+	CriteriaKeyCVEHighCount:        {RegulatoryAreaVulnerability},
+	CriteriaKeyCVEMediumCount:      {RegulatoryAreaVulnerability},
+	CriteriaKeyCVEHighWithFixCount: {RegulatoryAreaVulnerability},
+	CriteriaKeyCVEScore:            {RegulatoryAreaVulnerability},
+	CriteriaKeyCVEScoreCount:       {RegulatoryAreaVulnerability},
+	CriteriaKeyImageScanned:        {RegulatoryAreaVulnerability},
+	CriteriaKeyImageSigned:         {RegulatoryAreaIntegrity},
+	CriteriaKeyImageVerifiers:      {RegulatoryAreaIntegrity},
+	CriteriaKeyRunAsRoot:           {RegulatoryAreaAccess},
+	CriteriaKeyRunAsPrivileged:     {RegulatoryAreaAccess},
+	CriteriaKeyAllowPrivEscalation: {RegulatoryAreaAccess},
+	CriteriaKeyPrivilegedSCC:       {RegulatoryAreaAccess},
+	CriteriaKeySaBindRiskyRole:     {RegulatoryAreaAccess},
+	CriteriaKeyUser:                {RegulatoryAreaAccess},
+	CriteriaKeyK8sGroups:           {RegulatoryAreaAccess},
+	CriteriaKeyImageCompliance:     {RegulatoryAreaHardening, RegulatoryAreaEncryption},
+	CriteriaKeyEnvVarSecrets:       {RegulatoryAreaEncryption},
+	CriteriaKeySharePidWithHost:    {RegulatoryAreaHardening},
+	CriteriaKeyShareIpcWithHost:    {RegulatoryAreaHardening},
+	CriteriaKeyShareNetWithHost:    {RegulatoryAreaHardening, RegulatoryAreaNetwork},
+	CriteriaKeyPspCompliance:       {RegulatoryAreaHardening},
+	CriteriaKeyHasPssViolation:     {RegulatoryAreaHardening},
+	CriteriaKeyMountVolumes:        {RegulatoryAreaHardening},
+	CriteriaKeyRequestLimit:        {RegulatoryAreaHardening},
+	CriteriaKeyImageRegistry:       {RegulatoryAreaIntegrity},
+	CriteriaKeyBaseImage:           {RegulatoryAreaIntegrity},
+}
+
+// The areas of an admission control deny rule
+func AdmissionRuleRegulatoryAreas(rule *CLUSAdmissionRule) []string {
+	areas := []string{RegulatoryAreaChange}
+	for _, crit := range rule.Criteria {
+		for _, area := range CLUSAdmCriteriaRegulatoryMap[crit.Name] {
+			areas = appendUniq(areas, area)
+		}
+	}
+	return areas
+}
@@ -4,6 +4,7 @@ import (
 	"sort"
 
 	"github.com/neuvector/neuvector/controller/api"
+	"github.com/neuvector/neuvector/share"
 	"github.com/neuvector/neuvector/share/utils"
 )
 
@@ -40,6 +41,7 @@ func GetComplianceMeta() ([]api.RESTBenchMeta, map[string]api.RESTBenchMeta) {
 			}
 
 			sort.Strings(item.Tags)
+			item.Controls = share.RegulatoryControls(complianceRegulatoryAreas(item.TestNum))
 			complianceMetaMap[item.TestNum] = *item
 		}
 
@@ -50,6 +52,72 @@ func GetComplianceMeta() ([]api.RESTBenchMeta, map[string]api.RESTBenchMeta) {
 	return complianceMetas, complianceMetaMap
 }
 
+// Every benchmark check is a secure configuration check. The other areas are of the check numbers.
+func complianceRegulatoryAreas(testNum string) []string {
+	areas := []string{share.RegulatoryAreaHardening}
+	for _, a := range complianceAreas {
+		if a.checks.Contains(testNum) {
+			areas = append(areas, a.area)
+		}
+	}
+	return areas
+}
+
+var complianceAreas = []struct {
+	area   string
+	checks utils.Set
+}{
+	{share.RegulatoryAreaAudit, utils.NewSet(
+		"D.1.1.3", "D.1.1.4", "D.1.1.5", "D.1.1.6", "D.1.1.7", "D.1.1.8", "D.1.1.9", "D.1.1.10", "D.1.1.11", "D.1.1.12",
+		"D.1.1.13", "D.1.1.14", "D.1.1.15", "D.1.1.16", "D.1.1.17", "D.1.1.18",
+		"K.1.2.22", "K.1.2.23", "K.1.2.24", "K.1.2.25",
+		"K.3.2.1", "K.3.2.2",
+	)},
+	{share.RegulatoryAreaAccess, utils.NewSet(
+		// trusted user, no new privileges
+		"D.1.1.2", "D.2.14",
+		// file mode, owner
+		"D.3.1", "D.3.2", "D.3.3", "D.3.4", "D.3.5", "D.3.6", "D.3.7", "D.3.8", "D.3.9", "D.3.10",
+		"D.3.11", "D.3.12", "D.3.13", "D.3.14", "D.3.15", "D.3.16", "D.3.17", "D.3.18", "D.3.19", "D.3.20",
+		"D.3.21", "D.3.22",
+		// root user, setuid
+		"D.4.1", "D.4.8", "I.4.1", "I.4.8",
+		// privilege, mount
+		"D.5.4", "D.5.5", "D.5.7", "D.5.17", "D.5.25", "D.5.31",
+		// master file mode, owner
+		"K.1.1.1", "K.1.1.2", "K.1.1.3", "K.1.1.4", "K.1.1.5", "K.1.1.6", "K.1.1.7", "K.1.1.8", "K.1.1.9", "K.1.1.10",
+		"K.1.1.11", "K.1.1.12", "K.1.1.13", "K.1.1.14", "K.1.1.15", "K.1.1.16", "K.1.1.17", "K.1.1.18", "K.1.1.19", "K.1.1.20",
+		"K.1.1.21",
+		// worker: file mode owner
+		"K.4.1.1", "K.4.1.2", "K.4.1.3", "K.4.1.4", "K.4.1.5", "K.4.1.6", "K.4.1.7", "K.4.1.8", "K.4.1.9", "K.4.1.10",
+	)},
+	{share.RegulatoryAreaAuth, utils.NewSet(
+		// token, cert, auth
+		"K.1.2.1", "K.1.2.2", "K.1.2.3", "K.1.2.4", "K.1.2.5", "K.1.2.6", "K.1.2.7", "K.1.2.8", "K.1.2.9",
+		// service account
+		"K.1.3.3", "K.1.3.4", "K.1.3.5", "K.1.3.6",
+		// worker: auth
+		"K.4.2.1", "K.4.2.2", "K.4.2.3", "K.4.2.4", "K.4.2.6",
+	)},
+	{share.RegulatoryAreaEncryption, utils.NewSet(
+		// insecure registry, tls, secrets
+		"D.2.5", "D.2.7", "D.4.10", "I.4.10",
+		// service account, tls, encrypt
+		"K.1.2.27", "K.1.2.28", "K.1.2.29", "K.1.2.30", "K.1.2.31", "K.1.2.32", "K.1.2.33", "K.1.2.34", "K.1.2.35",
+		// cert
+		"K.2.1", "K.2.2", "K.2.3", "K.2.4", "K.2.5", "K.2.6", "K.2.7",
+		"K.4.2.10", "K.4.2.11", "K.4.2.12", "K.4.2.13",
+	)},
+	{share.RegulatoryAreaChange, utils.NewSet(
+		// adm. ctrl.
+		"K.1.2.10", "K.1.2.11", "K.1.2.12", "K.1.2.13", "K.1.2.14", "K.1.2.15", "K.1.2.16", "K.1.2.17",
+	)},
+	{share.RegulatoryAreaNetwork, utils.NewSet(
+		// traffic between containers, host network ns
+		"D.2.1", "D.5.9",
+	)},
+}
+
 var complianceHIPAA utils.Set = utils.NewSet(
 	// trusted user
 	"D.1.1.2",
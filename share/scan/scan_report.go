@@ -33,6 +33,13 @@ const (
 	vulnSeverityCritical
 )
 
+// Vulnerability names and keys are repeated in the reports of many images. They come from the cve database,
+// so the interned strings are bounded by its size; the file and package strings are not interned as they are
+// only released with the reports.
+const vulStringInternMax = 500000
+
+var vulStrings *utils.Interner = utils.NewInterner(vulStringInternMax)
+
 var serverityString2ID = map[string]int8{
 	share.VulnSeverityLow:      vulnSeverityLow,
 	share.VulnSeverityMedium:   vulnSeverityMedium,
@@ -566,12 +573,14 @@ func ExtractVulnerability(vuls []*share.ScanVulnerability) []*VulTrait {
 		}
 
 		traits[i] = &VulTrait{
-			Name:     v.Name,
+			Name:     vulStrings.Intern(v.Name),
 			severity: s,
-			dbKey:    v.DBKey,
+			dbKey:    vulStrings.Intern(v.DBKey),
 			pubTS:    pubTS,
 			fileName: v.FileName,
-			pkgName:  v.PackageName, pkgVer: v.PackageVersion, fixVer: v.FixedVersion,
+			pkgName:  v.PackageName,
+			pkgVer:   v.PackageVersion,
+			fixVer:   v.FixedVersion,
 		}
 	}
 	return traits
@@ -27,6 +27,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/vishvananda/netns"
 
+	"github.com/neuvector/neuvector/share"
 	namespace "github.com/neuvector/neuvector/share/system/ns"
 	sk "github.com/neuvector/neuvector/share/system/sidekick"
 	"github.com/neuvector/neuvector/share/system/sysinfo"
@@ -712,7 +713,121 @@ func (s *SystemTools) IsOpenshift() (bool, error) {
 	return false, nil
 }
 
+// K3s and MicroK8s run the kubernetes components in a single process. Their datastore is kine, an etcd shim
+// over sqlite or dqlite, unless an etcd is configured.
+func (s *SystemTools) GetEmbeddedKube() (string, bool, error) {
+	fd, err := os.Open(s.procDir)
+	if err != nil {
+		log.Error("Read process directory fail")
+		return "", false, err
+	}
+	defer fd.Close()
+
+	files, err := fd.Readdir(-1)
+	if err != nil {
+		return "", false, err
+	}
+
+	var flavor string
+	var kine, dqlite bool
+	for _, file := range files {
+		if file.IsDir() {
+			pid, err := strconv.Atoi(file.Name())
+			if err != nil {
+				continue
+			}
+
+			if cmds, err := s.ReadCmdLine(pid); err == nil && len(cmds) > 0 {
+				if f, k := parseEmbeddedKubeCmd(cmds); f != "" {
+					flavor = f
+					kine = kine || k
+				} else if filepath.Base(cmds[0]) == "k8s-dqlite" {
+					dqlite = true
+				}
+			}
+		}
+	}
+
+	if flavor == share.FlavorMicroK8s {
+		kine = dqlite
+	}
+	return flavor, kine, nil
+}
+
+// k3s with the embedded etcd is initialized by --cluster-init, or joins the cluster of the embedded etcd.
+// The external datastore other than etcd is also served by kine.
+func parseEmbeddedKubeCmd(cmds []string) (string, bool) {
+	switch filepath.Base(cmds[0]) {
+	case "k3s":
+		if len(cmds) < 2 {
+			return "", false
+		} else if cmds[1] == "agent" {
+			return share.FlavorK3s, false
+		} else if cmds[1] != "server" {
+			return "", false
+		}
+		kine := true
+		for i, arg := range cmds[2:] {
+			if arg == "--cluster-init" || arg == "--server" || strings.HasPrefix(arg, "--server=") {
+				kine = false
+			} else if v := strings.TrimPrefix(arg, "--datastore-endpoint="); v != arg {
+				kine = !strings.HasPrefix(v, "http")
+			} else if arg == "--datastore-endpoint" && i+3 < len(cmds) {
+				kine = !strings.HasPrefix(cmds[i+3], "http")
+			}
+		}
+		return share.FlavorK3s, kine
+	case "kubelite":
+		// the datastore of MicroK8s is decided by the k8s-dqlite process
+		return share.FlavorMicroK8s, false
+	}
+	return "", false
+}
+
 //return true if file size over limit
+// The capabilities to intercept the container traffic and to monitor the processes and files of other containers.
+var privilegedCaps = []struct {
+	bit  uint
+	name string
+}{
+	{12, "NET_ADMIN"},
+	{19, "SYS_PTRACE"},
+	{21, "SYS_ADMIN"},
+}
+
+// Return the privileged capabilities that are not in the effective set of the calling process. The enforcer
+// is not granted them in the restricted environments, such as GKE Autopilot, where privileged pods are disallowed.
+func (s *SystemTools) GetMissingCapabilities() ([]string, error) {
+	dat, err := ioutil.ReadFile("/proc/self/status")
+	if err != nil {
+		return nil, err
+	}
+	return parseMissingCapabilities(dat)
+}
+
+func parseMissingCapabilities(status []byte) ([]string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(status))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "CapEff:\t") {
+			continue
+		}
+
+		caps, err := strconv.ParseUint(strings.TrimSpace(line[7:]), 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		missing := make([]string, 0)
+		for _, c := range privilegedCaps {
+			if caps&(1<<c.bit) == 0 {
+				missing = append(missing, c.name)
+			}
+		}
+		return missing, nil
+	}
+	return nil, fmt.Errorf("Capabilities not found in status")
+}
+
 func (s *SystemTools) NsGetFile(filePath string, pid int, binary bool, start, len int) ([]byte, error) {
 	var errb bytes.Buffer
 	args := []string{
@@ -1,7 +1,10 @@
 package system
 
 import (
+	"reflect"
 	"testing"
+
+	"github.com/neuvector/neuvector/share"
 )
 
 func TestParseSharedNetNS(t *testing.T) {
@@ -11,3 +14,45 @@ func TestParseSharedNetNS(t *testing.T) {
 		t.Errorf("Incorrect pid: %v\n", pid)
 	}
 }
+
+func TestParseEmbeddedKubeCmd(t *testing.T) {
+	tests := []struct {
+		cmds   []string
+		flavor string
+		kine   bool
+	}{
+		{[]string{"/usr/local/bin/k3s", "server"}, share.FlavorK3s, true},
+		{[]string{"/usr/local/bin/k3s", "server", "--cluster-init"}, share.FlavorK3s, false},
+		{[]string{"/usr/local/bin/k3s", "server", "--server", "https://10.1.1.1:6443"}, share.FlavorK3s, false},
+		{[]string{"/usr/local/bin/k3s", "server", "--datastore-endpoint=mysql://k3s@tcp(db:3306)/k3s"}, share.FlavorK3s, true},
+		{[]string{"/usr/local/bin/k3s", "server", "--datastore-endpoint", "https://etcd:2379"}, share.FlavorK3s, false},
+		{[]string{"/usr/local/bin/k3s", "agent"}, share.FlavorK3s, false},
+		{[]string{"/usr/local/bin/k3s", "kubectl", "get", "pods"}, "", false},
+		{[]string{"/snap/microk8s/4916/kubelite", "--scheduler-args-file=/var/snap/microk8s/4916/args/kube-scheduler"}, share.FlavorMicroK8s, false},
+		{[]string{"/usr/bin/kubelet"}, "", false},
+	}
+
+	for _, test := range tests {
+		if flavor, kine := parseEmbeddedKubeCmd(test.cmds); flavor != test.flavor || kine != test.kine {
+			t.Errorf("Unexpected result: %v, expect=%v:%v actual=%v:%v", test.cmds, test.flavor, test.kine, flavor, kine)
+		}
+	}
+}
+
+func TestParseMissingCapabilities(t *testing.T) {
+	status := "Name:\tmonitor\nCapInh:\t0000000000000000\nCapPrm:\t000001ffffffffff\nCapEff:\t000001ffffffffff\n"
+	if missing, err := parseMissingCapabilities([]byte(status)); err != nil || len(missing) != 0 {
+		t.Errorf("Unexpected missing capabilities: %v, %v", missing, err)
+	}
+
+	// default capabilities of a container plus NET_ADMIN
+	status = "Name:\tmonitor\nCapEff:\t00000000a80435fb\n"
+	missing, err := parseMissingCapabilities([]byte(status))
+	if err != nil || !reflect.DeepEqual(missing, []string{"SYS_PTRACE", "SYS_ADMIN"}) {
+		t.Errorf("Unexpected missing capabilities: %v, %v", missing, err)
+	}
+
+	if _, err = parseMissingCapabilities([]byte("Name:\tmonitor\n")); err == nil {
+		t.Errorf("Error expected")
+	}
+}
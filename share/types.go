@@ -55,6 +55,19 @@ const (
 	PolicyActionCheckVh  string = "check_vh"
 )
 
+// Units of the rate limit of an allow rule
+const (
+	PolicyRateUnitBytes       string = "bytes"       // bytes per second
+	PolicyRateUnitConnections string = "connections" // connections per second
+)
+
+// How the command-line arguments of a process profile rule are matched
+const (
+	ProcArgsMatchExact  string = "exact"
+	ProcArgsMatchPrefix string = "prefix"
+	ProcArgsMatchRegex  string = "regex"
+)
+
 const (
 	VulnSeverityCritical string = "Critical"
 	VulnSeverityHigh     string = "High"
@@ -83,6 +96,7 @@ const (
 	PlatformKubernetes = "Kubernetes"
 	PlatformRancher    = "Rancher"
 	PlatformAliyun     = "Aliyun"
+	PlatformNomad      = "Nomad"
 
 	FlavorSwarm     = "Swarm"
 	FlavorUCP       = "UCP"
@@ -90,6 +104,8 @@ const (
 	FlavorRancher   = "Rancher"
 	FlavorIKE       = "IKE"
 	FlavorGKE       = "GKE"
+	FlavorK3s       = "K3s"
+	FlavorMicroK8s  = "MicroK8s"
 
 	NetworkFlannel   = "Flannel"
 	NetworkCalico    = "Calico"
@@ -100,6 +116,7 @@ const (
 const (
 	ENV_PLATFORM_INFO = "NV_PLATFORM_INFO"
 	ENV_SYSTEM_GROUPS = "NV_SYSTEM_GROUPS"
+	ENV_HOST_LABELS   = "NV_HOST_LABELS"
 	ENV_DISABLE_PCAP  = "DISABLE_PACKET_CAPTURE"
 )
 
@@ -171,6 +188,10 @@ const (
 	EventActionQuarantine  string = "quarantine"
 	EventActionSuppressLog string = "suppress-log"
 	EventActionWebhook     string = "webhook"
+	// quarantine the source workload of a network threat, such as port scan
+	EventActionQuarantineSource string = "quarantine-source"
+	// quarantine the workload and add its image to the federal quarantine list, for fed response rules only
+	EventActionFedQuarantine string = "fed-quarantine"
 )
 
 const (
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Interner keeps one copy of the strings that are repeated in many objects, such as image names, label
+// values and vulnerability names, so the copies decoded from each object can be released. The strings of
+// the removed objects are kept until Rebuild is called with the strings of the live objects.
+type Interner struct {
+	mutex sync.Mutex
+	strs  map[string]string
+	max   int
+	full  bool
+	marks map[string]string // strings interned during a rebuild
+}
+
+// NewInterner creates an interner that keeps at most max strings; 0 means no limit.
+func NewInterner(max int) *Interner {
+	return &Interner{strs: make(map[string]string), max: max}
+}
+
+// Intern returns the kept copy of s. When the interner is full, s is returned as is.
+func (i *Interner) Intern(s string) string {
+	if s == "" {
+		return s
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	v, ok := i.strs[s]
+	if !ok {
+		if i.max > 0 && len(i.strs) >= i.max {
+			if !i.full {
+				i.full = true
+				log.WithFields(log.Fields{"max": i.max}).Warn("Interner is full")
+			}
+			return s
+		}
+		i.strs[s] = s
+		v = s
+	}
+	if i.marks != nil {
+		i.marks[v] = v
+	}
+	return v
+}
+
+// Rebuild releases the strings that are no longer used and returns how many are released. walk reports
+// the strings of the live objects with keep; the strings interned during the walk are kept as well, so
+// the caller doesn't have to stop the updates. Rebuilds must not run concurrently.
+func (i *Interner) Rebuild(walk func(keep func(s string))) int {
+	i.mutex.Lock()
+	i.marks = make(map[string]string)
+	i.mutex.Unlock()
+
+	live := make(map[string]struct{})
+	walk(func(s string) {
+		if s != "" {
+			live[s] = struct{}{}
+		}
+	})
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	strs := i.marks
+	for s := range live {
+		if v, ok := i.strs[s]; ok {
+			strs[v] = v
+		}
+	}
+	released := len(i.strs) - len(strs)
+	i.strs = strs
+	i.marks = nil
+	i.full = false
+	return released
+}
+
+func (i *Interner) Size() int {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return len(i.strs)
+}
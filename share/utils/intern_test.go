@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestInterner(t *testing.T) {
+	in := NewInterner(2)
+	a := in.Intern(string([]byte("abc")))
+	b := in.Intern(string([]byte("abc")))
+	if a != b || in.Size() != 1 {
+		t.Errorf("Unexpected interned string: %s %s size=%d", a, b, in.Size())
+	}
+	in.Intern("")
+	if in.Size() != 1 {
+		t.Errorf("Empty string should not be kept: size=%d", in.Size())
+	}
+	in.Intern("def")
+	if s := in.Intern("xyz"); s != "xyz" || in.Size() != 2 {
+		t.Errorf("String should not be kept when full: %s size=%d", s, in.Size())
+	}
+}
+
+func TestInternerRebuild(t *testing.T) {
+	in := NewInterner(3)
+	in.Intern("abc")
+	in.Intern("def")
+	in.Intern("xyz")
+	if s := in.Intern("ghi"); in.Size() != 3 {
+		t.Errorf("String should not be kept when full: %s size=%d", s, in.Size())
+	}
+
+	// Only the strings of the live objects are kept
+	if released := in.Rebuild(func(keep func(s string)) {
+		keep("abc")
+		keep("ghi") // not interned
+		keep("")
+	}); released != 2 || in.Size() != 1 {
+		t.Errorf("Unused strings should be released: released=%d size=%d", released, in.Size())
+	}
+
+	// Strings interned during the rebuild are kept
+	in.Rebuild(func(keep func(s string)) {
+		in.Intern("new")
+		keep("abc")
+	})
+	if in.Size() != 2 {
+		t.Errorf("String interned during rebuild should be kept: size=%d", in.Size())
+	}
+
+	// Released strings are interned again
+	def := string([]byte("def"))
+	if s := in.Intern(def); s != "def" || in.Size() != 3 {
+		t.Errorf("Released string should be interned again: %s size=%d", s, in.Size())
+	}
+
+	if released := in.Rebuild(func(keep func(s string)) {}); released != 3 || in.Size() != 0 {
+		t.Errorf("All strings should be released: released=%d size=%d", released, in.Size())
+	}
+}
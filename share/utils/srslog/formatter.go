@@ -56,3 +56,14 @@ func RFC5424Formatter(p Priority, hostname, tag, content string) string {
 		p, 1, timestamp, hostname, appName, pid, tag, content)
 	return msg
 }
+
+// RFC5424StructuredDataFormatter provides an RFC 5424 compliant message, in which the content starts with the
+// structured data, "-" or SD elements, followed by a space and the message.
+func RFC5424StructuredDataFormatter(p Priority, hostname, tag, content string) string {
+	timestamp := time.Now().Format(time.RFC3339)
+	pid := os.Getpid()
+	appName := truncateStartStr(os.Args[0], appNameMaxLength)
+	msg := fmt.Sprintf("<%d>%d %s %s %s %d %s %s",
+		p, 1, timestamp, hostname, appName, pid, tag, content)
+	return msg
+}
@@ -68,6 +68,14 @@ func NormalizeForURL(name string) string {
 	return name
 }
 
+// Image ids/digests are reported with or without the hash algorithm prefix
+func NormalizeImageDigest(digest string) string {
+	if i := strings.Index(digest, ":"); i >= 0 {
+		digest = digest[i+1:]
+	}
+	return strings.ToLower(digest)
+}
+
 func MakeServiceName(namespace, name string) string {
 	if namespace == "" {
 		return name
@@ -230,6 +238,14 @@ func IsIPv6(ip net.IP) bool {
 	return ip.To16() != nil
 }
 
+// IPHostMask returns the full-length mask of the ip's address family
+func IPHostMask(ip net.IP) net.IPMask {
+	if IsIPv4(ip) {
+		return net.CIDRMask(32, 32)
+	}
+	return net.CIDRMask(128, 128)
+}
+
 func IPv42Int(ip net.IP) uint32 {
 	if len(ip) == 16 {
 		return binary.BigEndian.Uint32(ip[12:16])
@@ -255,6 +271,7 @@ type EnvironParser struct {
 	kvPairs     map[string]string
 	platformEnv map[string][]string
 	sysGroups   []*regexp.Regexp
+	hostLabels  map[string]string
 }
 
 func NewEnvironParser(envs []string) *EnvironParser {
@@ -287,6 +304,14 @@ func NewEnvironParser(envs []string) *EnvironParser {
 							p.sysGroups = append(p.sysGroups, r)
 						}
 					}
+				case share.ENV_HOST_LABELS:
+					// NV_HOST_LABELS=env=prod;tier=db
+					p.hostLabels = make(map[string]string)
+					for _, t := range strings.Split(v, ";") {
+						if kv := strings.SplitN(strings.TrimSpace(t), "=", 2); len(kv) == 2 && kv[0] != "" {
+							p.hostLabels[kv[0]] = kv[1]
+						}
+					}
 				}
 			}
 		}
@@ -298,6 +323,10 @@ func (p EnvironParser) GetPlatformEnv() map[string][]string {
 	return p.platformEnv
 }
 
+func (p EnvironParser) GetHostLabels() map[string]string {
+	return p.hostLabels
+}
+
 func (p EnvironParser) GetPlatformName() (string, string) {
 	if p.platformEnv != nil {
 		if plts, ok := p.platformEnv[share.ENV_PLT_PLATFORM]; ok && len(plts) > 0 {
@@ -184,6 +184,20 @@ func TestPlatformEnv(t *testing.T) {
 	}
 }
 
+func TestHostLabelsEnv(t *testing.T) {
+	envs := []string{"NV_HOST_LABELS=env=prod; tier=db;invalid;zone="}
+	p := NewEnvironParser(envs)
+
+	labels := p.GetHostLabels()
+	if len(labels) != 3 || labels["env"] != "prod" || labels["tier"] != "db" || labels["zone"] != "" {
+		t.Errorf("Error: labels=%+v\n", labels)
+	}
+
+	if labels = NewEnvironParser([]string{}).GetHostLabels(); labels != nil {
+		t.Errorf("Error: labels=%+v\n", labels)
+	}
+}
+
 func TestBase64Encrypt(t *testing.T) {
 	token := "123456"
 	encrypt := EncryptUserToken(token, nil)
@@ -275,3 +289,12 @@ func TestBytesDisplay(t *testing.T) {
 		t.Errorf("(%v) and (%v) is not equal\n", num, str)
 	}
 }
+
+func TestIPHostMask(t *testing.T) {
+	if ones, bits := IPHostMask(net.ParseIP("10.1.2.3")).Size(); ones != 32 || bits != 32 {
+		t.Errorf("Unexpected ipv4 host mask: %v/%v\n", ones, bits)
+	}
+	if ones, bits := IPHostMask(net.ParseIP("fd00::1")).Size(); ones != 128 || bits != 128 {
+		t.Errorf("Unexpected ipv6 host mask: %v/%v\n", ones, bits)
+	}
+}
@@ -0,0 +1,579 @@
+// openapigen generates the OpenAPI 3 document of the controller REST API.
+//
+// Routes are read from the route table in StartRESTServer(). Request and response bodies are derived from
+// the variables passed to json.Unmarshal() and restRespSuccess() in each route handler, and the schemas are
+// derived from the structs in the api & share packages. Routes marked with "Skip API document" are ignored.
+//
+//	go run ./tools/openapigen -o controller/api/openapi.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	openapiVersion = "3.0.3"
+	routeFuncName  = "StartRESTServer"
+	skipDocComment = "Skip API document"
+	schemaRefBase  = "#/components/schemas/"
+)
+
+var routeMethods = map[string]string{
+	"GET":    "get",
+	"POST":   "post",
+	"PUT":    "put",
+	"PATCH":  "patch",
+	"DELETE": "delete",
+}
+
+type route struct {
+	method  string
+	path    string
+	handler string
+	comment string
+}
+
+type typeInfo struct {
+	pkg  string
+	spec *ast.TypeSpec
+}
+
+type generator struct {
+	fset     *token.FileSet
+	handlers map[string]*ast.FuncDecl
+	types    map[string]*typeInfo // key is "{pkg}.{type}"
+	schemas  map[string]interface{}
+	pending  []string
+}
+
+func newGenerator() *generator {
+	return &generator{
+		fset:     token.NewFileSet(),
+		handlers: make(map[string]*ast.FuncDecl),
+		types:    make(map[string]*typeInfo),
+		schemas:  make(map[string]interface{}),
+	}
+}
+
+func (g *generator) parseDir(dir string) ([]*ast.File, error) {
+	pkgs, err := parser.ParseDir(g.fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ast.File
+	for _, pkg := range pkgs {
+		names := make([]string, 0, len(pkg.Files))
+		for name := range pkg.Files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			files = append(files, pkg.Files[name])
+		}
+	}
+	return files, nil
+}
+
+// Load the type declarations of a package. pkg is the name used by other packages to refer to it
+func (g *generator) loadTypes(pkg, dir string) error {
+	files, err := g.parseDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+				for _, spec := range gd.Specs {
+					ts := spec.(*ast.TypeSpec)
+					g.types[fmt.Sprintf("%s.%s", pkg, ts.Name.Name)] = &typeInfo{pkg: pkg, spec: ts}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Load the route table & route handlers of the rest package
+func (g *generator) loadRoutes(dir string) ([]*route, error) {
+	files, err := g.parseDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []*route
+	for _, f := range files {
+		comments := make(map[int]string)
+		for _, cg := range f.Comments {
+			comments[g.fset.Position(cg.Pos()).Line] = strings.TrimSpace(cg.Text())
+		}
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv != nil || fd.Body == nil {
+				continue
+			}
+			g.handlers[fd.Name.Name] = fd
+			if fd.Name.Name != routeFuncName {
+				continue
+			}
+			ast.Inspect(fd.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || len(call.Args) != 2 {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				if _, ok := routeMethods[sel.Sel.Name]; !ok {
+					return true
+				}
+				lit, ok := call.Args[0].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+				handler, ok := call.Args[1].(*ast.Ident)
+				if !ok {
+					return true
+				}
+				path, _ := strconv.Unquote(lit.Value)
+				comment := comments[g.fset.Position(call.Pos()).Line]
+				if strings.Contains(comment, skipDocComment) {
+					return true
+				}
+				routes = append(routes, &route{method: sel.Sel.Name, path: path, handler: handler.Name, comment: comment})
+				return true
+			})
+		}
+	}
+	return routes, nil
+}
+
+// Return the type expression of the value that expr evaluates to, as far as it can be told syntactically
+func exprType(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return exprType(e.X)
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return exprType(e.X)
+		}
+	case *ast.CompositeLit:
+		return e.Type
+	case *ast.CallExpr:
+		if id, ok := e.Fun.(*ast.Ident); ok && (id.Name == "new" || id.Name == "make") && len(e.Args) > 0 {
+			return e.Args[0]
+		}
+	case *ast.Ident:
+		if e.Obj == nil {
+			return nil
+		}
+		switch d := e.Obj.Decl.(type) {
+		case *ast.ValueSpec:
+			if d.Type != nil {
+				return d.Type
+			}
+			for i, name := range d.Names {
+				if name.Name == e.Name && i < len(d.Values) {
+					return exprType(d.Values[i])
+				}
+			}
+		case *ast.AssignStmt:
+			if len(d.Lhs) == len(d.Rhs) {
+				for i, lhs := range d.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok && id.Name == e.Name {
+						return exprType(d.Rhs[i])
+					}
+				}
+			}
+		case *ast.Field:
+			return d.Type
+		}
+	}
+	return nil
+}
+
+// Find the request & response body types of a route handler
+func (g *generator) handlerBodies(name string) (ast.Expr, ast.Expr) {
+	fd, ok := g.handlers[name]
+	if !ok {
+		return nil, nil
+	}
+
+	var reqType, respType ast.Expr
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		switch fun := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			if x, ok := fun.X.(*ast.Ident); ok && x.Name == "json" && fun.Sel.Name == "Unmarshal" && len(call.Args) == 2 {
+				if t := exprType(call.Args[1]); reqType == nil && g.isApiType(t) {
+					reqType = t
+				}
+			}
+		case *ast.Ident:
+			if fun.Name == "restRespSuccess" && len(call.Args) > 2 {
+				if t := exprType(call.Args[2]); respType == nil && g.isApiType(t) {
+					respType = t
+				}
+			}
+		}
+		return true
+	})
+	return reqType, respType
+}
+
+// Whether the type expression refers to a type in the api package, maybe thru pointer/slice/map
+func (g *generator) isApiType(t ast.Expr) bool {
+	switch e := t.(type) {
+	case *ast.StarExpr:
+		return g.isApiType(e.X)
+	case *ast.ArrayType:
+		return g.isApiType(e.Elt)
+	case *ast.MapType:
+		return g.isApiType(e.Value)
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok && x.Name == "api" {
+			_, ok = g.types["api."+e.Sel.Name]
+			return ok
+		}
+	}
+	return false
+}
+
+func schemaName(pkg, name string) string {
+	if pkg == "api" {
+		return name
+	}
+	return fmt.Sprintf("%s.%s", pkg, name)
+}
+
+func (g *generator) schemaRef(pkg, name string) map[string]interface{} {
+	key := fmt.Sprintf("%s.%s", pkg, name)
+	sname := schemaName(pkg, name)
+	if _, ok := g.schemas[sname]; !ok {
+		g.schemas[sname] = nil // placeholder until it's built
+		g.pending = append(g.pending, key)
+	}
+	return map[string]interface{}{"$ref": schemaRefBase + sname}
+}
+
+var basicSchemas = map[string]map[string]interface{}{
+	"string":  {"type": "string"},
+	"bool":    {"type": "boolean"},
+	"int":     {"type": "integer"},
+	"int8":    {"type": "integer", "format": "int32"},
+	"int16":   {"type": "integer", "format": "int32"},
+	"int32":   {"type": "integer", "format": "int32"},
+	"int64":   {"type": "integer", "format": "int64"},
+	"uint":    {"type": "integer", "minimum": 0},
+	"uint8":   {"type": "integer", "format": "int32", "minimum": 0},
+	"uint16":  {"type": "integer", "format": "int32", "minimum": 0},
+	"uint32":  {"type": "integer", "format": "int64", "minimum": 0},
+	"uint64":  {"type": "integer", "format": "int64", "minimum": 0},
+	"byte":    {"type": "integer", "format": "int32", "minimum": 0},
+	"rune":    {"type": "integer", "format": "int32"},
+	"float32": {"type": "number", "format": "float"},
+	"float64": {"type": "number", "format": "double"},
+}
+
+// Return the schema of a type expression used in package pkg. nil means the type is not serialized in json
+func (g *generator) typeSchema(pkg string, t ast.Expr) map[string]interface{} {
+	switch e := t.(type) {
+	case *ast.Ident:
+		if s, ok := basicSchemas[e.Name]; ok {
+			copied := make(map[string]interface{}, len(s))
+			for k, v := range s {
+				copied[k] = v
+			}
+			return copied
+		}
+		if _, ok := g.types[fmt.Sprintf("%s.%s", pkg, e.Name)]; ok {
+			return g.schemaRef(pkg, e.Name)
+		}
+		return map[string]interface{}{}
+	case *ast.SelectorExpr:
+		x, _ := e.X.(*ast.Ident)
+		if x == nil {
+			return map[string]interface{}{}
+		}
+		if x.Name == "time" && e.Sel.Name == "Time" {
+			return map[string]interface{}{"type": "string", "format": "date-time"}
+		}
+		if _, ok := g.types[fmt.Sprintf("%s.%s", x.Name, e.Sel.Name)]; ok {
+			return g.schemaRef(x.Name, e.Sel.Name)
+		}
+		return map[string]interface{}{"type": "object", "description": fmt.Sprintf("%s.%s", x.Name, e.Sel.Name)}
+	case *ast.StarExpr:
+		return g.typeSchema(pkg, e.X)
+	case *ast.ArrayType:
+		if id, ok := e.Elt.(*ast.Ident); ok && id.Name == "byte" {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		items := g.typeSchema(pkg, e.Elt)
+		if items == nil {
+			return nil
+		}
+		return map[string]interface{}{"type": "array", "items": items}
+	case *ast.MapType:
+		values := g.typeSchema(pkg, e.Value)
+		if values == nil {
+			return nil
+		}
+		return map[string]interface{}{"type": "object", "additionalProperties": values}
+	case *ast.InterfaceType:
+		return map[string]interface{}{}
+	case *ast.StructType:
+		return g.structSchema(pkg, e)
+	case *ast.FuncType, *ast.ChanType:
+		return nil
+	}
+	return map[string]interface{}{}
+}
+
+func (g *generator) structSchema(pkg string, st *ast.StructType) map[string]interface{} {
+	props := make(map[string]interface{})
+	var required []string
+	var embedded []interface{}
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			if s, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(s)
+			}
+		}
+		jsonTag, hasTag := tag.Lookup("json")
+		opts := strings.Split(jsonTag, ",")
+		if opts[0] == "-" && len(opts) == 1 {
+			continue
+		}
+		var omitempty, asString bool
+		for _, opt := range opts[1:] {
+			switch opt {
+			case "omitempty":
+				omitempty = true
+			case "string":
+				asString = true
+			}
+		}
+
+		names := make([]string, 0, len(field.Names))
+		for _, name := range field.Names {
+			if ast.IsExported(name.Name) {
+				names = append(names, name.Name)
+			}
+		}
+		if len(field.Names) == 0 {
+			// embedded field. its fields are promoted unless it's given a json name
+			if !hasTag || opts[0] == "" {
+				if s := g.typeSchema(pkg, field.Type); s != nil {
+					embedded = append(embedded, s)
+				}
+				continue
+			}
+			names = append(names, opts[0])
+		}
+
+		schema := g.typeSchema(pkg, field.Type)
+		if schema == nil {
+			continue
+		}
+		if asString {
+			schema = map[string]interface{}{"type": "string"}
+		}
+		for _, name := range names {
+			if hasTag && opts[0] != "" {
+				name = opts[0]
+			}
+			props[name] = schema
+			if !omitempty {
+				required = append(required, name)
+			}
+		}
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": props}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	if len(embedded) > 0 {
+		return map[string]interface{}{"allOf": append(embedded, schema)}
+	}
+	return schema
+}
+
+func (g *generator) buildSchemas() map[string]interface{} {
+	for len(g.pending) > 0 {
+		key := g.pending[0]
+		g.pending = g.pending[1:]
+		info := g.types[key]
+		schema := g.typeSchema(info.pkg, info.spec.Type)
+		if schema == nil {
+			schema = map[string]interface{}{}
+		}
+		g.schemas[schemaName(info.pkg, info.spec.Name.Name)] = schema
+	}
+	return g.schemas
+}
+
+// Convert a httprouter path to an openapi path & its path parameters
+func convertPath(path string) (string, []string) {
+	var params []string
+	segs := strings.Split(path, "/")
+	for i, seg := range segs {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			params = append(params, seg[1:])
+			segs[i] = fmt.Sprintf("{%s}", seg[1:])
+		}
+	}
+	return strings.Join(segs, "/"), params
+}
+
+// The first segment after the version is used as the tag, like "group" for /v1/group/{name}
+func pathTag(path string) string {
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segs) > 1 {
+		return segs[1]
+	}
+	return segs[0]
+}
+
+func (g *generator) generate(routes []*route, version string) map[string]interface{} {
+	paths := make(map[string]interface{})
+	opIDs := make(map[string]int)
+	for _, rt := range routes {
+		path, params := convertPath(rt.path)
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			item = make(map[string]interface{})
+			paths[path] = item
+		}
+
+		opID := strings.TrimPrefix(rt.handler, "handler")
+		if n := opIDs[opID]; n > 0 {
+			opIDs[opID] = n + 1
+			opID = fmt.Sprintf("%s%d", opID, n+1)
+		} else {
+			opIDs[opID] = 1
+		}
+		op := map[string]interface{}{
+			"operationId": opID,
+			"tags":        []string{pathTag(rt.path)},
+		}
+		if rt.comment != "" {
+			op["summary"] = rt.comment
+		}
+		if len(params) > 0 {
+			parameters := make([]interface{}, 0, len(params))
+			for _, p := range params {
+				parameters = append(parameters, map[string]interface{}{
+					"name":     p,
+					"in":       "path",
+					"required": true,
+					"schema":   map[string]interface{}{"type": "string"},
+				})
+			}
+			op["parameters"] = parameters
+		}
+
+		reqType, respType := g.handlerBodies(rt.handler)
+		if reqType != nil {
+			op["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": g.typeSchema("api", reqType)},
+				},
+			}
+		}
+		success := map[string]interface{}{"description": "Success"}
+		if respType != nil {
+			success["content"] = map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": g.typeSchema("api", respType)},
+			}
+		}
+		op["responses"] = map[string]interface{}{
+			"200": success,
+			"default": map[string]interface{}{
+				"description": "Error",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": g.schemaRef("api", "RESTError")},
+				},
+			},
+		}
+		item[routeMethods[rt.method]] = op
+	}
+
+	return map[string]interface{}{
+		"openapi": openapiVersion,
+		"info": map[string]interface{}{
+			"title":   "NeuVector API",
+			"version": version,
+		},
+		"security": []interface{}{
+			map[string]interface{}{"ApiKeyAuth": []string{}},
+			map[string]interface{}{"TokenAuth": []string{}},
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"securitySchemes": map[string]interface{}{
+				"ApiKeyAuth": map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-Auth-Apikey"},
+				"TokenAuth":  map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-Auth-Token"},
+			},
+			"schemas": g.buildSchemas(),
+		},
+	}
+}
+
+// Generate the openapi document from the source tree at root
+func generate(root, version string) (map[string]interface{}, error) {
+	g := newGenerator()
+	if err := g.loadTypes("api", filepath.Join(root, "controller", "api")); err != nil {
+		return nil, err
+	}
+	if err := g.loadTypes("share", filepath.Join(root, "share")); err != nil {
+		return nil, err
+	}
+	routes, err := g.loadRoutes(filepath.Join(root, "controller", "rest"))
+	if err != nil {
+		return nil, err
+	}
+	return g.generate(routes, version), nil
+}
+
+func main() {
+	root := flag.String("root", ".", "Root of the source tree")
+	output := flag.String("o", "", "Output file. Default is stdout")
+	version := flag.String("v", "", "API version")
+	flag.Parse()
+
+	doc, err := generate(*root, *version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to generate: %s\n", err)
+		os.Exit(1)
+	}
+	data, _ := json.MarshalIndent(doc, "", "  ")
+	data = append(data, '\n')
+	if *output == "" {
+		os.Stdout.Write(data)
+	} else if err := ioutil.WriteFile(*output, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write %s: %s\n", *output, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestConvertPath(t *testing.T) {
+	path, params := convertPath("/v1/scan/registry/:name/image/:id")
+	if path != "/v1/scan/registry/{name}/image/{id}" || len(params) != 2 || params[0] != "name" || params[1] != "id" {
+		t.Errorf("Unexpected path: %s, params=%v", path, params)
+	}
+	if tag := pathTag("/v1/group/:name"); tag != "group" {
+		t.Errorf("Unexpected tag: %s", tag)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	doc, err := generate("../..", "test")
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+
+	paths := doc["paths"].(map[string]interface{})
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	bodySchema := func(body interface{}) string {
+		content, _ := body.(map[string]interface{})["content"].(map[string]interface{})
+		if mt, ok := content["application/json"].(map[string]interface{}); ok {
+			ref, _ := mt["schema"].(map[string]interface{})["$ref"].(string)
+			return ref
+		}
+		return ""
+	}
+
+	group, ok := paths["/v1/group"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Path /v1/group not found")
+	}
+	get := group["get"].(map[string]interface{})
+	if ref := bodySchema(get["responses"].(map[string]interface{})["200"]); ref != schemaRefBase+"RESTGroupsData" {
+		t.Errorf("Unexpected response of GET /v1/group: %s", ref)
+	}
+	post := group["post"].(map[string]interface{})
+	if ref := bodySchema(post["requestBody"]); ref != schemaRefBase+"RESTGroupConfigData" {
+		t.Errorf("Unexpected request of POST /v1/group: %s", ref)
+	}
+
+	if item, ok := paths["/v1/group/{name}"].(map[string]interface{}); !ok {
+		t.Errorf("Path /v1/group/{name} not found")
+	} else if _, ok := item["get"].(map[string]interface{})["parameters"]; !ok {
+		t.Errorf("Path parameter of /v1/group/{name} not found")
+	}
+
+	// routes marked not to be documented
+	for _, p := range []string{"/v1/fed_auth", "/v1/selfuser"} {
+		if _, ok := paths[p]; ok {
+			t.Errorf("Path %s should be skipped", p)
+		}
+	}
+
+	for name, schema := range schemas {
+		if schema == nil {
+			t.Errorf("Schema %s not built", name)
+		}
+	}
+	if _, ok := schemas["RESTGroupBrief"]; !ok {
+		t.Errorf("Embedded schema RESTGroupBrief not found")
+	}
+}